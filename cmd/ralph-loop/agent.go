@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/agent"
+	"github.com/CodexForgeBR/cli-tools/internal/ai"
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/model"
+	sighandler "github.com/CodexForgeBR/cli-tools/internal/signal"
+)
+
+// newAgentCmd builds `ralph-loop agent`, the daemon that runs the AI calls
+// of a loop started elsewhere with --remote-agent.
+func newAgentCmd() *cobra.Command {
+	var listen string
+	cmd := &cobra.Command{
+		Use:   "agent [--listen <addr>]",
+		Short: "Run AI calls for a loop on another machine (--remote-agent)",
+		Long:  "Serve the AI calls of a ralph-loop started elsewhere with --remote-agent http://<this host>:7433, running each with the AI CLIs of this machine in this directory, which must hold the same checkout of the project. Clients authenticate with the token in " + agent.TokenEnv + ", which must be set. The sandbox, container, Kubernetes job, permission and timeout settings of this directory's .ralph-loop/config and the global config apply. Serve it over TLS (e.g. behind a reverse proxy) when the network is not trusted.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			token := os.Getenv(agent.TokenEnv)
+			if token == "" {
+				return fmt.Errorf("set %s to the token clients must present", agent.TokenEnv)
+			}
+			paths := configPaths("")
+			cfg, err := config.LoadWithPrecedence(paths[0], paths[1], "", nil)
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			if cfg.ContainerImage != "" && cfg.ContainerRuntime == "" {
+				cfg.ContainerRuntime = ai.DetectRuntime()
+			}
+			server := &agent.Server{
+				Token: token,
+				NewRunner: func(req agent.RunRequest) (ai.AIRunner, error) {
+					if !model.IsProvider(req.Provider) {
+						return nil, fmt.Errorf("unknown AI %q", req.Provider)
+					}
+					modelName := req.Model
+					if modelName == "" {
+						modelName = model.DefaultModelForAI(req.Provider)
+					}
+					logging.Info(fmt.Sprintf("%s call on %s (%s)", req.Phase, req.Provider, modelName))
+					return newCLIRunner(cfg, req.Provider, modelName, req.Phase, config.Sampling{ReasoningEffort: req.ReasoningEffort}), nil
+				},
+			}
+
+			ln, err := net.Listen("tcp", listen)
+			if err != nil {
+				return fmt.Errorf("listen on %s: %w", listen, err)
+			}
+			srv := &http.Server{Handler: server.Handler(), ReadHeaderTimeout: 5 * time.Second}
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+			sighandler.SetupSignalHandler(ctx, cancel, nil)
+			go func() {
+				<-ctx.Done()
+				_ = srv.Close()
+			}()
+			logging.Info(fmt.Sprintf("Agent listening on %s", ln.Addr()))
+			if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&listen, "listen", ":7433", "Address to serve the agent on")
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
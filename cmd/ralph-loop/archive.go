@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/archive"
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// stateDir is the state directory used by the orchestrator and the
+// subcommands, set by useStateDir.
+var stateDir = config.DefaultStateDir
+
+// newExportCmd builds `ralph-loop export`, which writes the current session
+// to a tar.gz archive.
+func newExportCmd() *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the current session to a tar.gz archive",
+		Long:  "Export the session state, iteration outputs and learnings in .ralph-loop to a tar.gz archive that `ralph-loop import` can restore on another machine.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := useProjectStateDir(cmd); err != nil {
+				return err
+			}
+			root, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			if output == "" {
+				s, err := state.LoadState(stateDir)
+				if err != nil {
+					return fmt.Errorf("no session to export: %w", err)
+				}
+				output = s.SessionID + ".tar.gz"
+			}
+
+			f, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("create archive: %w", err)
+			}
+			manifest, err := archive.Export(f, stateDir, root)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(output)
+				return err
+			}
+			logging.Success(fmt.Sprintf("Exported session %s (iteration %d) to %s", manifest.SessionID, manifest.Iteration, output))
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Archive path (default: <session-id>.tar.gz)")
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
+
+// newImportCmd builds `ralph-loop import`, which restores a session archive
+// into .ralph-loop so it can be continued with --resume.
+func newImportCmd() *cobra.Command {
+	var force bool
+	cmd := &cobra.Command{
+		Use:   "import <archive>",
+		Short: "Import a session archive created by export",
+		Long:  "Restore a session archive into .ralph-loop, rebasing recorded paths onto this checkout. Continue it with `ralph-loop --resume`.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := useProjectStateDir(cmd); err != nil {
+				return err
+			}
+			root, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			// Hold the lock so a running loop is not overwritten.
+			lock, err := state.AcquireLock(stateDir, false)
+			if err != nil {
+				return err
+			}
+			defer lock.Release()
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("open archive: %w", err)
+			}
+			defer f.Close()
+
+			manifest, err := archive.Import(f, stateDir, root, force)
+			if err != nil {
+				return err
+			}
+			logging.Success(fmt.Sprintf("Imported session %s (iteration %d); continue with: ralph-loop --resume", manifest.SessionID, manifest.Iteration))
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&force, "force", false, "Replace an existing session in .ralph-loop")
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
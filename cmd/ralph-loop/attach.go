@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/attach"
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	sighandler "github.com/CodexForgeBR/cli-tools/internal/signal"
+)
+
+// newAttachCmd builds `ralph-loop attach`, which follows a loop running in
+// another terminal.
+func newAttachCmd() *cobra.Command {
+	var lines int
+	var raw bool
+	cmd := &cobra.Command{
+		Use:   "attach",
+		Short: "Follow a running loop's status and AI output",
+		Long:  "Follow the loop running in this directory, for example in tmux or CI: print its iteration, phase, status and verdicts as they change and stream the AI output from the iteration's phase logs. Ctrl+C detaches without affecting the loop, which exits on its own when it is done.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := useProjectStateDir(cmd); err != nil {
+				return err
+			}
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+			sighandler.SetupSignalHandler(ctx, cancel, nil)
+			return attach.Follow(ctx, attach.Config{
+				StateDir: stateDir,
+				Out:      cmd.OutOrStdout(),
+				Lines:    lines,
+				Raw:      raw,
+			})
+		},
+	}
+	cmd.Flags().IntVarP(&lines, "lines", "n", 20, "Lines of the current iteration's output to show first")
+	cmd.Flags().BoolVar(&raw, "raw", false, "Print the AI CLIs' JSON events instead of their text")
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
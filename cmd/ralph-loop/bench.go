@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/bench"
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	sighandler "github.com/CodexForgeBR/cli-tools/internal/signal"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// newBenchCmd builds `ralph-loop bench`, which compares model pairs on the
+// same tasks file.
+func newBenchCmd() *cobra.Command {
+	var matrix, tasksFile, outputDir string
+	var runs int
+	var keep bool
+	cmd := &cobra.Command{
+		Use:   "bench --matrix <pairs> [-- <ralph-loop flags>]",
+		Short: "Compare implementation/validation model pairs on a tasks file",
+		Long:  "Run the tasks file --runs times with each IMPL/VAL model pair of --matrix, every run in a fresh git worktree of HEAD with the tasks file as it is now, and report per pair how many runs completed, the iterations they took, their mean cost (Claude only) and duration, and how the others ended. Flags after -- are passed to every run, e.g. -- --ai codex --max-iterations 10. The report, results.json and each run's output are written to --output-dir.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pairs, err := bench.ParseMatrix(matrix)
+			if err != nil {
+				return fmt.Errorf("--matrix: %w", err)
+			}
+			if runs < 1 {
+				return fmt.Errorf("--runs must be at least 1")
+			}
+			tasksPath, err := tasks.DiscoverTasksFile(tasksFile)
+			if err != nil {
+				return err
+			}
+			exe, err := os.Executable()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+			sighandler.SetupSignalHandler(ctx, cancel, nil)
+			total := len(pairs) * runs
+			done := 0
+			results, runErr := bench.Run(ctx, bench.Config{
+				Pairs:     pairs,
+				Runs:      runs,
+				Dir:       ".",
+				TasksFile: tasksPath,
+				StateDir:  config.DefaultStateDir,
+				Args:      args,
+				LogDir:    filepath.Join(outputDir, "logs"),
+				Keep:      keep,
+				Exec: func(ctx context.Context, dir string, args []string, out io.Writer) (int, error) {
+					return runLoop(ctx, exe, dir, args, out)
+				},
+				Progress: func(p bench.Pair, run int) {
+					done++
+					logging.Info(fmt.Sprintf("[%d/%d] %s, run %d", done, total, p, run))
+				},
+			})
+			if len(results) > 0 {
+				if err := writeBenchReport(cmd.OutOrStdout(), outputDir, tasksFileLabel(tasksPath), runs, results); err != nil {
+					return err
+				}
+			}
+			return runErr
+		},
+	}
+	cmd.Flags().StringVar(&matrix, "matrix", "", "Comma-separated IMPL/VAL model pairs, e.g. opus/sonnet,sonnet/sonnet")
+	cmd.Flags().IntVar(&runs, "runs", 3, "Runs per model pair")
+	cmd.Flags().StringVar(&tasksFile, "tasks-file", "", "Tasks file to benchmark (default: discovered as for a normal run)")
+	cmd.Flags().StringVarP(&outputDir, "output-dir", "o", "ralph-bench", "Directory for the report and each run's output")
+	cmd.Flags().BoolVar(&keep, "keep-worktrees", false, "Keep each run's worktree for inspection")
+	_ = cmd.MarkFlagRequired("matrix")
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
+
+// runLoop runs ralph-loop with args in dir and returns its exit code.
+// Cancelling ctx interrupts it, so it saves its state as on Ctrl+C.
+func runLoop(ctx context.Context, exe, dir string, args []string, out io.Writer) (int, error) {
+	loop := exec.CommandContext(ctx, exe, args...)
+	loop.Dir = dir
+	loop.Stdout, loop.Stderr = out, out
+	loop.Cancel = func() error { return loop.Process.Signal(os.Interrupt) }
+	loop.WaitDelay = 30 * time.Second
+	err := loop.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	return 0, err
+}
+
+// writeBenchReport writes report.md and results.json to dir and prints
+// the report.
+func writeBenchReport(out io.Writer, dir, tasksFile string, runs int, results []bench.Result) error {
+	report, err := os.Create(filepath.Join(dir, "report.md"))
+	if err != nil {
+		return err
+	}
+	defer report.Close()
+	bench.WriteMarkdown(io.MultiWriter(out, report), tasksFile, runs, results)
+
+	data, err := json.MarshalIndent(map[string]any{
+		"summaries": bench.Summarize(results),
+		"runs":      results,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "results.json"), data, 0644); err != nil {
+		return err
+	}
+	logging.Success(fmt.Sprintf("Benchmark report written to %s", filepath.Join(dir, "report.md")))
+	return nil
+}
+
+// tasksFileLabel shows the tasks file relative to the working directory
+// when it is below it.
+func tasksFileLabel(path string) string {
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	return path
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// newBlameCmd builds `ralph-loop blame`, which lists the iterations that
+// changed a file and the tasks they completed.
+func newBlameCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "blame <path>",
+		Short: "Show which iterations and tasks changed a file",
+		Long:  "List the judged iterations of the session in .ralph-loop that changed a file, or any file under a directory, oldest first, with each iteration's verdict and the tasks it checked off. Files are recorded when the project is a git repository; run ralph-loop diff --iteration <n> to see an iteration's changes.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := useProjectStateDir(cmd); err != nil {
+				return err
+			}
+			s, err := state.LoadState(stateDir)
+			if err != nil {
+				return fmt.Errorf("no session to blame: %w", err)
+			}
+			path := projectRelative(args[0])
+			found := state.FileHistory(s, path)
+			if len(found) == 0 {
+				return fmt.Errorf("no recorded iteration changed %s", path)
+			}
+			printBlame(cmd.OutOrStdout(), s, path, found)
+			return nil
+		},
+	}
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
+
+// projectRelative returns path as the iteration history records it:
+// slash-separated and relative to the current directory, the project root.
+func projectRelative(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// printBlame writes one line per iteration: its number, when it was
+// judged, its verdict and the tasks it checked off with their text.
+func printBlame(w io.Writer, s *state.SessionState, path string, found []state.IterationRecord) {
+	fmt.Fprintln(w, path)
+	for _, h := range found {
+		completed := "no task completed"
+		if len(h.Tasks) > 0 {
+			var names []string
+			for _, id := range h.Tasks {
+				if t := state.FindTask(s, id); t != nil && t.Text != "" {
+					names = append(names, t.Text)
+				} else {
+					names = append(names, id)
+				}
+			}
+			completed = strings.Join(names, "; ")
+		}
+		verdict := h.Verdict
+		if verdict == "" {
+			verdict = "-"
+		}
+		fmt.Fprintf(w, "  iteration %-3d %-20s %-16s %s\n", h.Iteration, h.At, verdict, completed)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+)
+
+// newConfigCmd builds `ralph-loop config`, which inspects the settings.
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the configuration",
+		Long:  "Inspect the settings ralph-loop runs with, from ~/.config/ralph-loop/config, .ralph-loop/config, --config, the profile and the RALPH_* environment variables.",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newConfigShowCmd())
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
+
+// newConfigShowCmd builds `ralph-loop config show`, which prints the
+// settings the config files, the profile and the environment set.
+func newConfigShowCmd() *cobra.Command {
+	var file, profile string
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print the settings and where they come from, secrets redacted",
+		Long:  "Print every setting the config files, the selected profile and the RALPH_* environment variables set, as KEY=value with where it came from, ${...} references expanded. Settings left at their defaults are not listed. The values of secret settings, such as NOTIFY_WEBHOOK, and of those read from the keyring are redacted.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := configPaths(file)
+			var overrides map[string]string
+			if profile != "" {
+				overrides = map[string]string{"PROFILE": profile}
+			}
+			settings, err := config.Resolve(paths[0], paths[1], paths[2], overrides)
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			for _, s := range settings {
+				fmt.Fprintf(w, "%s=%s\t# %s\n", s.Key, s.Shown(), s.Source)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().StringVar(&file, "config", "", "Additional config file, as for the loop's --config")
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile to apply, as for the loop's --profile")
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
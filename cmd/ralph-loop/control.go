@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// newPauseCmd builds `ralph-loop pause`, which asks the running loop to
+// wait after its current phase.
+func newPauseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pause",
+		Short: "Pause the running loop after its current phase",
+		Long:  "Ask the ralph-loop running in this directory to save its state and wait once the current phase finishes. The process keeps running; continue it with `ralph-loop resume-now`.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := useProjectStateDir(cmd); err != nil {
+				return err
+			}
+			holder, err := runningLoop()
+			if err != nil {
+				return err
+			}
+			if err := state.RequestControl(stateDir, state.ControlPause); err != nil {
+				return err
+			}
+			logging.Success(fmt.Sprintf("Pause requested; the loop (pid %d) waits after its current phase. Continue with ralph-loop resume-now", holder.PID))
+			return nil
+		},
+	}
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
+
+// newResumeNowCmd builds `ralph-loop resume-now`, which wakes the running
+// loop from a pause, a pause window or its scheduled start.
+func newResumeNowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resume-now",
+		Short: "Wake the running loop from a pause or scheduled wait",
+		Long:  "Wake the ralph-loop running in this directory: end a `ralph-loop pause`, cut a PAUSE_BETWEEN window short, or start now instead of at the --start-at time. A pause that has not taken effect yet is cancelled.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := useProjectStateDir(cmd); err != nil {
+				return err
+			}
+			if _, err := runningLoop(); err != nil {
+				return err
+			}
+			if state.PendingControl(stateDir) == state.ControlPause {
+				if err := state.ClearControl(stateDir); err != nil {
+					return err
+				}
+				logging.Success("Cancelled the pause request the loop had not reached yet")
+				return nil
+			}
+			s, err := state.LoadState(stateDir)
+			if err != nil {
+				return err
+			}
+			if s.Phase != state.PhasePaused && s.Phase != state.PhaseWaitingForSchedule {
+				return fmt.Errorf("the loop is not paused or waiting (phase: %s)", s.Phase)
+			}
+			if err := state.RequestControl(stateDir, state.ControlResume); err != nil {
+				return err
+			}
+			logging.Success("Resume requested; the loop continues within a few seconds")
+			return nil
+		},
+	}
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
+
+// runningLoop returns the loop running in stateDir, or an error when there
+// is none to control.
+func runningLoop() (*state.LockInfo, error) {
+	holder := state.RunningLoop(stateDir)
+	if holder == nil {
+		return nil, fmt.Errorf("no ralph-loop is running in %s", stateDir)
+	}
+	return holder, nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/statecrypt"
+)
+
+// newDiffCmd builds `ralph-loop diff`, which prints what an iteration
+// changed along with the verdict and feedback it was judged with.
+func newDiffCmd() *cobra.Command {
+	var iteration int
+	var statOnly bool
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show what an iteration changed and how it was judged",
+		Long:  "Print the git diff captured for an iteration in .ralph-loop/iteration-NNN, between the working tree before its implementation phase and after its validation, preceded by the iteration's verdict, feedback and --stat summary. The state directory is left out of the diff. No diff is captured when the project is not a git repository.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := useProjectStateDir(cmd); err != nil {
+				return err
+			}
+			if iteration <= 0 {
+				s, err := state.LoadState(stateDir)
+				if err != nil {
+					return fmt.Errorf("no session to show the diff of; pass --iteration: %w", err)
+				}
+				iteration = s.Iteration
+				if n := len(s.History); n > 0 {
+					iteration = s.History[n-1].Iteration
+				}
+			}
+
+			iterDir := filepath.Join(stateDir, fmt.Sprintf("iteration-%03d", iteration))
+			v, err := state.LoadIterationVerdict(iterDir)
+			if err != nil {
+				return fmt.Errorf("no verdict for iteration %d in %s; it was not judged: %w", iteration, iterDir, err)
+			}
+			patch, err := statecrypt.ReadFile(filepath.Join(iterDir, state.IterationDiffFile))
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			printIterationDiff(cmd.OutOrStdout(), v, string(patch), err == nil, statOnly)
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&iteration, "iteration", 0, "Iteration to show (default: the session's last judged iteration)")
+	cmd.Flags().BoolVar(&statOnly, "stat", false, "Show the verdict and the --stat summary without the diff")
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
+
+// printIterationDiff writes the verdict, the feedback indented, the
+// --stat summary and, unless statOnly, the patch.
+func printIterationDiff(w io.Writer, v *state.IterationVerdict, patch string, captured, statOnly bool) {
+	fmt.Fprintf(w, "Iteration %d: %s", v.Iteration, v.Verdict)
+	if v.At != "" {
+		fmt.Fprintf(w, " (%s)", v.At)
+	}
+	fmt.Fprintln(w)
+	if feedback := strings.TrimSpace(v.Feedback); feedback != "" {
+		fmt.Fprintln(w, "\nFeedback:")
+		for _, line := range strings.Split(feedback, "\n") {
+			fmt.Fprintln(w, "  "+line)
+		}
+	}
+	fmt.Fprintln(w)
+	switch {
+	case !captured:
+		fmt.Fprintln(w, "No diff was captured for this iteration (not a git repository).")
+	case patch == "":
+		fmt.Fprintln(w, "The iteration changed no files.")
+	case statOnly:
+		fmt.Fprint(w, v.DiffStat)
+	default:
+		fmt.Fprint(w, v.DiffStat)
+		fmt.Fprintln(w)
+		fmt.Fprint(w, patch)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+)
+
+// newCompletionCmd builds `ralph-loop completion <shell>`, which prints a
+// shell completion script.
+func newCompletionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:       "completion <bash|zsh|fish|powershell>",
+		Short:     "Print a shell completion script",
+		Long:      "Print the completion script for bash, zsh, fish or powershell. For example, `source <(ralph-loop completion bash)` enables completion in the current bash session; add it to ~/.bashrc to keep it. Model flags complete the known models of the chosen AI and the models set in the config files.",
+		ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			out := cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(out, true)
+			case "zsh":
+				return root.GenZshCompletion(out)
+			case "fish":
+				return root.GenFishCompletion(out, true)
+			default:
+				return root.GenPowerShellCompletionWithDesc(out)
+			}
+		},
+	}
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
+
+// newDocsCmd builds `ralph-loop docs`, which generates documentation from
+// the command tree.
+func newDocsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Generate documentation",
+		Long:  "Generate documentation from the ralph-loop command tree.",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newManCmd())
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
+
+// newManCmd builds `ralph-loop docs man`, which writes a man page for
+// ralph-loop and each of its commands.
+func newManCmd() *cobra.Command {
+	var dir string
+	cmd := &cobra.Command{
+		Use:   "man",
+		Short: "Write man pages",
+		Long:  "Write section 1 man pages for ralph-loop and each of its commands to a directory, e.g. `ralph-loop docs man -d /usr/local/share/man/man1`.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return err
+			}
+			date := time.Now().Format("2006-01-02")
+			return writeManPages(cmd.Root(), dir, version, date)
+		},
+	}
+	cmd.Flags().StringVarP(&dir, "dir", "d", ".", "Directory to write the pages to")
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
+
+// writeManPages writes the man page of cmd and of every available command
+// below it.
+func writeManPages(cmd *cobra.Command, dir, version, date string) error {
+	path := filepath.Join(dir, cli.ManPageName(cmd))
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = cli.WriteManPage(f, cmd, version, date)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	logging.Info(fmt.Sprintf("Wrote %s", path))
+
+	for _, sub := range cmd.Commands() {
+		if !sub.IsAvailableCommand() {
+			continue
+		}
+		if err := writeManPages(sub, dir, version, date); err != nil {
+			return err
+		}
+	}
+	return nil
+}
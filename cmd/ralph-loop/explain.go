@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+)
+
+// newExplainExitCmd builds `ralph-loop explain-exit`, which prints what an
+// exit code means and how to recover from it.
+func newExplainExitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain-exit [code]",
+		Short: "Explain a ralph-loop exit code",
+		Long:  "Print the meaning of a ralph-loop exit code and the suggested remediation. Without a code, every exit code is listed.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			if len(args) == 0 {
+				for i, e := range exitcode.Explanations() {
+					if i > 0 {
+						fmt.Fprintln(out)
+					}
+					printExplanation(out, e)
+				}
+				return nil
+			}
+			code, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid exit code %q", args[0])
+			}
+			e, ok := exitcode.Explain(code)
+			if !ok {
+				return fmt.Errorf("ralph-loop does not exit with code %d; run ralph-loop explain-exit to list the codes", code)
+			}
+			printExplanation(out, e)
+			return nil
+		},
+	}
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
+
+func printExplanation(w io.Writer, e exitcode.Explanation) {
+	fmt.Fprintf(w, "%d %s\n", e.Code, e.Name)
+	fmt.Fprintf(w, "  Meaning: %s\n", e.Meaning)
+	fmt.Fprintf(w, "  Remedy:  %s\n", e.Remedy)
+}
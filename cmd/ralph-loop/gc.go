@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	"github.com/CodexForgeBR/cli-tools/internal/gc"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// newGCCmd builds `ralph-loop gc`, which removes the iteration directories
+// the session no longer needs, asking first.
+func newGCCmd() *cobra.Command {
+	var yes, dryRun bool
+	var retainIterations, retainMB int
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Remove iteration directories the session no longer needs",
+		Long:  "List what .ralph-loop keeps that the session no longer needs and ask before removing each kind: the iteration directories left by earlier sessions, those of the current session beyond the retention policy (--retain-iterations and --retain-mb, by default RETAIN_ITERATIONS and RETAIN_MB), and the iterations `ralph-loop rollback` undid. The session state, session outcomes, learnings and config are kept.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := useProjectStateDir(cmd); err != nil {
+				return err
+			}
+			r, err := gcRetention(cmd, retainIterations, retainMB)
+			if err != nil {
+				return err
+			}
+			if _, err := os.Stat(stateDir); os.IsNotExist(err) {
+				logging.Info(fmt.Sprintf("Nothing to remove: %s does not exist", stateDir))
+				return nil
+			}
+			// A running loop is writing its iteration; refuse to race it.
+			lock, err := state.AcquireLock(stateDir, false)
+			if err != nil {
+				return err
+			}
+			defer lock.Release()
+
+			groups, err := gc.Find(stateDir, r)
+			if err != nil {
+				return err
+			}
+			if len(groups) == 0 {
+				logging.Info(fmt.Sprintf("Nothing to remove in %s", stateDir))
+				return nil
+			}
+			out, in := cmd.OutOrStdout(), bufio.NewReader(cmd.InOrStdin())
+			var removed int
+			var freed int64
+			for _, g := range groups {
+				fmt.Fprintf(out, "%s (%d, %s):\n", g.Title, len(g.Paths), formatMB(g.Bytes))
+				for _, p := range g.Paths {
+					fmt.Fprintf(out, "  %s\n", filepath.Base(p))
+				}
+				if dryRun || (!yes && !confirm(out, in, "Remove them?")) {
+					continue
+				}
+				if err := g.Remove(); err != nil {
+					return err
+				}
+				removed += len(g.Paths)
+				freed += g.Bytes
+			}
+			if !dryRun {
+				logging.Success(fmt.Sprintf("Removed %d director(ies), %s", removed, formatMB(freed)))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Remove everything listed without asking")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List what would be removed and remove nothing")
+	cmd.Flags().IntVar(&retainIterations, "retain-iterations", 0, "Keep the directories of the last N iterations (default: RETAIN_ITERATIONS, 0 = all)")
+	cmd.Flags().IntVar(&retainMB, "retain-mb", 0, "Keep the newest iteration directories up to this total size in MB (default: RETAIN_MB, 0 = no limit)")
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
+
+// gcRetention returns the retention policy of the gc flags, or of
+// RETAIN_ITERATIONS and RETAIN_MB for those not given.
+func gcRetention(cmd *cobra.Command, retainIterations, retainMB int) (state.Retention, error) {
+	for _, s := range []struct {
+		flag, key string
+		value     *int
+	}{
+		{"retain-iterations", "RETAIN_ITERATIONS", &retainIterations},
+		{"retain-mb", "RETAIN_MB", &retainMB},
+	} {
+		if cmd.Flags().Changed(s.flag) {
+			if *s.value < 0 {
+				return state.Retention{}, fmt.Errorf("--%s must not be negative", s.flag)
+			}
+			continue
+		}
+		settings, err := projectSettings()
+		if err != nil {
+			return state.Retention{}, err
+		}
+		value := settingValue(settings, s.key)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return state.Retention{}, fmt.Errorf("%s must be a non-negative integer, got %q", s.key, value)
+		}
+		*s.value = n
+	}
+	return state.Retention{Keep: retainIterations, MaxBytes: int64(retainMB) << 20}, nil
+}
+
+// confirm asks question on out and reports whether the answer read from
+// in is yes; no answer is no.
+func confirm(out io.Writer, in *bufio.Reader, question string) bool {
+	fmt.Fprintf(out, "%s [y/N] ", question)
+	answer, _ := in.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// formatMB formats a size in bytes in megabytes.
+func formatMB(bytes int64) string {
+	return fmt.Sprintf("%.1f MB", float64(bytes)/(1<<20))
+}
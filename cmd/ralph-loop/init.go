@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/model"
+	"github.com/CodexForgeBR/cli-tools/internal/scaffold"
+)
+
+// newInitCmd builds `ralph-loop init`, which scaffolds a project's config,
+// tasks and plan files.
+func newInitCmd() *cobra.Command {
+	var (
+		opts        scaffold.Options
+		interactive bool
+		force       bool
+	)
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Scaffold .ralph-loop/config, tasks.md and plan.md",
+		Long:  "Write a starter .ralph-loop/config (with cheap and thorough profiles), a tasks.md skeleton in the format ralph-loop expects, a plan.md template, and a .gitignore entry that keeps session state out of the repository. Existing files are kept unless --force is given. The language defaults to the one detected from the project's build files.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("language") {
+				opts.Language = scaffold.DetectLanguage(root)
+			}
+			if interactive {
+				if opts, err = scaffold.Ask(cmd.InOrStdin(), cmd.OutOrStdout(), opts); err != nil {
+					return err
+				}
+			}
+
+			res, err := scaffold.Init(root, opts, force)
+			if err != nil {
+				return err
+			}
+			for _, path := range res.Skipped {
+				logging.Info(fmt.Sprintf("Kept existing %s", path))
+			}
+			if len(res.Written) == 0 {
+				logging.Info("Nothing to do; use --force to overwrite the config, tasks and plan files")
+				return nil
+			}
+			logging.Success(fmt.Sprintf("Wrote %s; describe the work in %s and %s, then run ralph-loop --original-plan-file %s",
+				strings.Join(res.Written, ", "), scaffold.PlanPath, scaffold.TasksPath, scaffold.PlanPath))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&opts.Language, "language", "", "Project language for learnings tags and the test task (default: detect)")
+	cmd.Flags().StringVar(&opts.AI, "ai", model.Claude, "AI CLI to configure: claude, codex, amazonq or copilot")
+	cmd.Flags().StringVar(&opts.NotifyChannel, "notify-channel", "telegram", "Notification channel")
+	cmd.Flags().StringVar(&opts.NotifyChatID, "notify-chat-id", "", "Recipient chat ID (default: no notifications)")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Ask for the language, AI CLI and notification target")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing config, tasks and plan files")
+	_ = cmd.RegisterFlagCompletionFunc("ai", cobra.FixedCompletions(model.Providers, cobra.ShellCompDirectiveNoFileComp))
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
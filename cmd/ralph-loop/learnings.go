@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	"github.com/CodexForgeBR/cli-tools/internal/learnings"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+)
+
+// newLearningsCmd builds `ralph-loop learnings`, which manages the
+// user-level learnings library.
+func newLearningsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "learnings",
+		Short: "Manage the global learnings library",
+		Long:  "Manage the learnings library in ~/.config/ralph-loop/learnings/, which holds one file per language or framework tag. Entries for the project's tags are added to implementation prompts next to the project's own learnings.",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newLearningsPromoteCmd())
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
+
+// newLearningsPromoteCmd builds `ralph-loop learnings promote`, which
+// copies the project's learnings into the global library.
+func newLearningsPromoteCmd() *cobra.Command {
+	var file, tagList string
+	cmd := &cobra.Command{
+		Use:   "promote",
+		Short: "Copy this project's learnings to the global library",
+		Long:  "Append the entries of the project learnings file to the global library file of each tag. Tags default to the languages and frameworks detected from the project's build files; entries the library already holds are skipped.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			tags := learnings.ParseTags(tagList)
+			if len(tags) == 0 {
+				tags = learnings.DetectTags(root)
+			}
+			if len(tags) == 0 {
+				return fmt.Errorf("no language or framework detected; pass --tags")
+			}
+
+			if !cmd.Flags().Changed("file") {
+				if err := useProjectStateDir(cmd); err != nil {
+					return err
+				}
+				file = filepath.Join(stateDir, "learnings.md")
+			}
+			entries := learnings.Entries(learnings.ReadLearnings(file))
+			if len(entries) == 0 {
+				logging.Info(fmt.Sprintf("No learnings to promote in %s", file))
+				return nil
+			}
+			dir := learnings.GlobalDir()
+			if dir == "" {
+				return fmt.Errorf("cannot locate the home directory for the learnings library")
+			}
+			added, err := learnings.Promote(dir, tags, entries, filepath.Base(root))
+			if err != nil {
+				return err
+			}
+			for _, tag := range tags {
+				logging.Success(fmt.Sprintf("%s: %d new entries", filepath.Join(dir, tag+".md"), added[tag]))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&file, "file", ".ralph-loop/learnings.md", "Project learnings file (default: learnings.md in the state directory)")
+	cmd.Flags().StringVar(&tagList, "tags", "", "Library tags to promote to, e.g. go,react (default: detect)")
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	"github.com/CodexForgeBR/cli-tools/internal/iterlog"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// newLogsCmd builds `ralph-loop logs`, which prints the AI CLIs' raw output
+// kept for an iteration.
+func newLogsCmd() *cobra.Command {
+	var iteration int
+	var phase string
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Show the AI output logged for an iteration",
+		Long:  "Print the raw AI CLI output of an iteration's phases from .ralph-loop/iteration-NNN, including rotated and gzipped logs, oldest first. Without --phase every phase is shown under a header.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := useProjectStateDir(cmd); err != nil {
+				return err
+			}
+			phases := iterlog.Phases
+			if phase != "" {
+				if !slices.Contains(iterlog.Phases, phase) {
+					return fmt.Errorf("unknown phase %q (want %s)", phase, strings.Join(iterlog.Phases, ", "))
+				}
+				phases = []string{phase}
+			}
+			if iteration <= 0 {
+				s, err := state.LoadState(stateDir)
+				if err != nil {
+					return fmt.Errorf("no session to show logs of; pass --iteration: %w", err)
+				}
+				iteration = s.Iteration
+			}
+
+			iterDir := filepath.Join(stateDir, fmt.Sprintf("iteration-%03d", iteration))
+			out := cmd.OutOrStdout()
+			shown := false
+			for _, p := range phases {
+				files, err := iterlog.Files(iterDir, p)
+				if err != nil {
+					return err
+				}
+				if len(files) == 0 {
+					continue
+				}
+				if len(phases) > 1 {
+					fmt.Fprintf(out, "==> %s <==\n", filepath.Base(iterlog.Path(iterDir, p)))
+				}
+				if err := iterlog.Copy(out, files); err != nil {
+					return err
+				}
+				shown = true
+			}
+			if !shown {
+				return fmt.Errorf("no %s logs for iteration %d in %s", strings.Join(phases, "/"), iteration, iterDir)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&iteration, "iteration", 0, "Iteration to show (default: the session's current iteration)")
+	cmd.Flags().StringVar(&phase, "phase", "", "Phase to show: "+strings.Join(iterlog.Phases, ", ")+" (default: all)")
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
@@ -5,17 +5,32 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 
+	"github.com/CodexForgeBR/cli-tools/internal/agent"
 	"github.com/CodexForgeBR/cli-tools/internal/ai"
 	"github.com/CodexForgeBR/cli-tools/internal/cli"
 	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/gitdiff"
+	ghissue "github.com/CodexForgeBR/cli-tools/internal/github"
+	"github.com/CodexForgeBR/cli-tools/internal/learnings"
 	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/metrics"
 	"github.com/CodexForgeBR/cli-tools/internal/model"
 	"github.com/CodexForgeBR/cli-tools/internal/phases"
 	"github.com/CodexForgeBR/cli-tools/internal/ratelimit"
+	"github.com/CodexForgeBR/cli-tools/internal/secrets"
 	sighandler "github.com/CodexForgeBR/cli-tools/internal/signal"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/statecrypt"
+	"github.com/CodexForgeBR/cli-tools/internal/tracing"
+	"github.com/CodexForgeBR/cli-tools/internal/tui"
+	"github.com/CodexForgeBR/cli-tools/internal/web"
 )
 
 // version vars injected via ldflags at build time
@@ -50,12 +65,119 @@ func main() {
 	// Set custom help template
 	cli.SetCustomHelp(rootCmd)
 
+	// Session archive subcommands
+	rootCmd.AddCommand(newExportCmd(), newImportCmd())
+
+	// Shell completion and man pages; our completion command replaces
+	// cobra's default so it shows in the custom help.
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(newCompletionCmd(), newDocsCmd())
+	rootCmd.AddCommand(newSelfUpdateCmd(), newLearningsCmd(), newLogsCmd(), newAttachCmd(), newRespondCmd(), newMCPCmd(), newBenchCmd(), newInitCmd(), newExplainExitCmd(), newConfigCmd(), newReportCmd(), newAgentCmd(), newWorkspaceCmd(), newDiffCmd(), newBlameCmd(), newRollbackCmd(), newGCCmd())
+	rootCmd.AddCommand(newPauseCmd(), newResumeNowCmd())
+	cli.RegisterCompletions(rootCmd, configPaths("")...)
+
+	// Subcommands decrypt and encrypt state with the configured key,
+	// looked up only when they touch a state file; the loop sets its own.
+	statecrypt.SetKeySource(func() ([]byte, error) {
+		settings, err := projectSettings()
+		if err != nil {
+			return nil, err
+		}
+		return parseStateKey(settingValue(settings, "STATE_KEY"))
+	})
+
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
 
+// projectSettings returns the settings the config files and the
+// environment set, for the subcommands, which do not load a Config.
+var projectSettings = sync.OnceValues(func() ([]config.Setting, error) {
+	paths := configPaths("")
+	return config.Resolve(paths[0], paths[1], paths[2], nil)
+})
+
+// settingValue returns the value of key in settings, or "".
+func settingValue(settings []config.Setting, key string) string {
+	for _, s := range settings {
+		if s.Key == key {
+			return s.Value
+		}
+	}
+	return ""
+}
+
+// useStateDir points stateDir at the state directory value, --state-dir
+// or STATE_DIR, sets for the project in the current directory, moving
+// there the state of an in-repo .ralph-loop.
+func useStateDir(value string) error {
+	dir, err := config.ProjectStateDir(value, ".")
+	if err != nil {
+		return err
+	}
+	moved, err := state.MigrateStateDir(config.DefaultStateDir, dir)
+	if err != nil {
+		return err
+	}
+	if len(moved) > 0 {
+		logging.Info(fmt.Sprintf("Moved the session state from %s to %s", config.DefaultStateDir, dir))
+	}
+	stateDir = dir
+	return nil
+}
+
+// useProjectStateDir is useStateDir for a subcommand: its --state-dir,
+// else STATE_DIR from the config files and the environment.
+func useProjectStateDir(cmd *cobra.Command) error {
+	value, _ := cmd.Flags().GetString("state-dir")
+	if value == "" {
+		settings, err := projectSettings()
+		if err != nil {
+			return err
+		}
+		value = settingValue(settings, "STATE_DIR")
+	}
+	return useStateDir(value)
+}
+
+// projectExclude returns dir relative to the project in the current
+// directory, for git to leave out, or nothing when it lies outside.
+func projectExclude(dir string) []string {
+	root, err := filepath.Abs(".")
+	if err != nil {
+		return nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil
+	}
+	return []string{rel}
+}
+
+// parseStateKey decodes STATE_KEY; empty means no encryption.
+func parseStateKey(value string) ([]byte, error) {
+	if value == "" {
+		return nil, nil
+	}
+	return statecrypt.ParseKey(value)
+}
+
+// configPaths returns the global, project and explicit config file paths,
+// lowest precedence first.
+func configPaths(explicit string) []string {
+	global := ""
+	if home, err := os.UserHomeDir(); err == nil {
+		global = filepath.Join(home, ".config", "ralph-loop", "config")
+	}
+	return []string{global, filepath.Join(".ralph-loop", "config"), explicit}
+}
+
 // buildCLIOverrides creates a map of CLI flag overrides from the config.
 // Uses cmd.Flags().Changed() to only include flags explicitly set by the user,
 // ensuring config file values are not accidentally overridden by default values.
@@ -70,16 +192,66 @@ func buildCLIOverrides(cmd *cobra.Command, cfg *config.Config) map[string]string
 		"ai":                          {"AI_CLI", cfg.AIProvider},
 		"implementation-model":        {"IMPL_MODEL", cfg.ImplModel},
 		"validation-model":            {"VAL_MODEL", cfg.ValModel},
+		"validation-ai":               {"VAL_AI", cfg.ValAI},
+		"implementation-model-ladder": {"IMPL_MODEL_LADDER", cfg.ImplModelLadder},
+		"validator-pool":              {"VALIDATOR_POOL", cfg.ValidatorPool},
 		"cross-validation-ai":         {"CROSS_AI", cfg.CrossAI},
 		"cross-model":                 {"CROSS_MODEL", cfg.CrossModel},
+		"fallback-ai":                 {"FALLBACK_AI", cfg.FallbackAI},
 		"final-plan-validation-ai":    {"FINAL_PLAN_AI", cfg.FinalPlanAI},
 		"final-plan-validation-model": {"FINAL_PLAN_MODEL", cfg.FinalPlanModel},
 		"tasks-validation-ai":         {"TASKS_VAL_AI", cfg.TasksValAI},
 		"tasks-validation-model":      {"TASKS_VAL_MODEL", cfg.TasksValModel},
+		"summary-model":               {"SUMMARY_MODEL", cfg.SummaryModel},
+		"summary-ai":                  {"SUMMARY_AI", cfg.SummaryAI},
+		"triage-ai":                   {"TRIAGE_AI", cfg.TriageAI},
+		"triage-model":                {"TRIAGE_MODEL", cfg.TriageModel},
+		"auto-cross-validate":         {"AUTO_CROSS_VALIDATE", cfg.AutoCrossValidate},
 		"learnings-file":              {"LEARNINGS_FILE", cfg.LearningsFile},
+		"learnings-tags":              {"LEARNINGS_TAGS", cfg.LearningsTags},
 		"notify-webhook":              {"NOTIFY_WEBHOOK", cfg.NotifyWebhook},
 		"notify-channel":              {"NOTIFY_CHANNEL", cfg.NotifyChannel},
 		"notify-chat-id":              {"NOTIFY_CHAT_ID", cfg.NotifyChatID},
+		"notify-events":               {"NOTIFY_EVENTS", cfg.NotifyEvents},
+		"notify-digest":               {"NOTIFY_DIGEST", cfg.NotifyDigest.String()},
+		"coverage-file":               {"COVERAGE_FILE", cfg.CoverageFile},
+		"heartbeat-interval":          {"HEARTBEAT_INTERVAL", cfg.HeartbeatInterval.String()},
+		"sandbox-cmd":                 {"SANDBOX_CMD", cfg.SandboxCmd},
+		"sandbox-env":                 {"SANDBOX_ENV", cfg.SandboxEnv},
+		"container-image":             {"CONTAINER_IMAGE", cfg.ContainerImage},
+		"container-runtime":           {"CONTAINER_RUNTIME", cfg.ContainerRuntime},
+		"container-pull":              {"CONTAINER_PULL", cfg.ContainerPull},
+		"k8s-job-image":               {"K8S_JOB_IMAGE", cfg.K8sJobImage},
+		"k8s-job-namespace":           {"K8S_JOB_NAMESPACE", cfg.K8sJobNamespace},
+		"k8s-job-secrets":             {"K8S_JOB_SECRETS", cfg.K8sJobSecrets},
+		"k8s-job-pvc":                 {"K8S_JOB_PVC", cfg.K8sJobPVC},
+		"remote-agent":                {"REMOTE_AGENT", cfg.RemoteAgent},
+		"spec-drift":                  {"SPEC_DRIFT", cfg.SpecDrift},
+		"allowed-tools":               {"ALLOWED_TOOLS", cfg.AllowedTools},
+		"denied-commands":             {"DENIED_COMMANDS", cfg.DeniedCommands},
+		"metrics-addr":                {"METRICS_ADDR", cfg.MetricsAddr},
+		"metrics-file":                {"METRICS_FILE", cfg.MetricsFile},
+		"serve":                       {"SERVE", cfg.Serve},
+		"issue-provider":              {"ISSUE_PROVIDER", cfg.IssueProvider},
+		"max-duration":                {"MAX_DURATION", cfg.MaxDuration.String()},
+		"shutdown-grace":              {"SHUTDOWN_GRACE", cfg.ShutdownGrace.String()},
+		"cache-ttl":                   {"CACHE_TTL", cfg.CacheTTL.String()},
+		"pause-between":               {"PAUSE_BETWEEN", cfg.PauseBetween},
+		"custom-verdicts":             {"CUSTOM_VERDICTS", cfg.CustomVerdicts},
+		"external-validators":         {"EXTERNAL_VALIDATORS", cfg.ExternalValidators},
+		"verify-cmds":                 {"VERIFY_CMDS", cfg.VerifyCmds},
+		"verify-reports":              {"VERIFY_REPORTS", cfg.VerifyReports},
+		"policy-file":                 {"POLICY_FILE", cfg.PolicyFile},
+		"protected-paths":             {"PROTECTED_PATHS", cfg.ProtectedPaths},
+		"evidence-patterns":           {"EVIDENCE_PATTERNS", cfg.EvidencePatterns},
+		"protected-paths-action":      {"PROTECTED_PATHS_ACTION", cfg.ProtectedPathsAction},
+		"protected-branches":          {"PROTECTED_BRANCHES", cfg.ProtectedBranches},
+		"hook-pre-iteration":          {"HOOK_PRE_ITERATION", cfg.HookPreIteration},
+		"hook-post-implementation":    {"HOOK_POST_IMPLEMENTATION", cfg.HookPostImplementation},
+		"hook-post-validation":        {"HOOK_POST_VALIDATION", cfg.HookPostValidation},
+		"hook-on-exit":                {"HOOK_ON_EXIT", cfg.HookOnExit},
+		"profile":                     {"PROFILE", cfg.Profile},
+		"state-dir":                   {"STATE_DIR", cfg.StateDir},
 	}
 	for flag, mapping := range stringFlags {
 		if cmd.Flags().Changed(flag) {
@@ -92,11 +264,31 @@ func buildCLIOverrides(cmd *cobra.Command, cfg *config.Config) map[string]string
 		key string
 		val int
 	}{
-		"max-iterations":     {"MAX_ITERATIONS", cfg.MaxIterations},
-		"max-inadmissible":   {"MAX_INADMISSIBLE", cfg.MaxInadmissible},
-		"max-claude-retry":   {"MAX_CLAUDE_RETRY", cfg.MaxClaudeRetry},
-		"max-turns":          {"MAX_TURNS", cfg.MaxTurns},
-		"inactivity-timeout": {"INACTIVITY_TIMEOUT", cfg.InactivityTimeout},
+		"max-iterations":         {"MAX_ITERATIONS", cfg.MaxIterations},
+		"max-inadmissible":       {"MAX_INADMISSIBLE", cfg.MaxInadmissible},
+		"max-claude-retry":       {"MAX_CLAUDE_RETRY", cfg.MaxClaudeRetry},
+		"max-turns":              {"MAX_TURNS", cfg.MaxTurns},
+		"max-task-attempts":      {"MAX_TASK_ATTEMPTS", cfg.MaxTaskAttempts},
+		"max-format-retries":     {"MAX_FORMAT_RETRIES", cfg.MaxFormatRetries},
+		"escalate-after":         {"ESCALATE_AFTER", cfg.EscalateAfter},
+		"validators":             {"VALIDATORS", cfg.Validators},
+		"inactivity-timeout":     {"INACTIVITY_TIMEOUT", cfg.InactivityTimeout},
+		"hang-timeout":           {"HANG_TIMEOUT", cfg.HangTimeout},
+		"failover-after":         {"FAILOVER_AFTER", cfg.FailoverAfter},
+		"iteration-timeout":      {"ITERATION_TIMEOUT", cfg.IterationTimeout},
+		"impl-timeout":           {"IMPL_TIMEOUT", cfg.ImplTimeout},
+		"val-timeout":            {"VAL_TIMEOUT", cfg.ValTimeout},
+		"learnings-max-tokens":   {"LEARNINGS_MAX_TOKENS", cfg.LearningsMaxTokens},
+		"context-max-tokens":     {"CONTEXT_MAX_TOKENS", cfg.ContextMaxTokens},
+		"val-diff-max-tokens":    {"VAL_DIFF_MAX_TOKENS", cfg.ValDiffMaxTokens},
+		"impl-output-max-tokens": {"IMPL_OUTPUT_MAX_TOKENS", cfg.ImplOutputMaxTokens},
+		"hook-timeout":           {"HOOK_TIMEOUT", cfg.HookTimeout},
+		"log-max-mb":             {"LOG_MAX_MB", cfg.LogMaxMB},
+		"retain-iterations":      {"RETAIN_ITERATIONS", cfg.RetainIterations},
+		"retain-mb":              {"RETAIN_MB", cfg.RetainMB},
+		"output-gzip-kb":         {"OUTPUT_GZIP_KB", cfg.OutputGzipKB},
+		"heartbeat-iterations":   {"HEARTBEAT_ITERATIONS", cfg.HeartbeatIterations},
+		"pr":                     {"PR_NUMBER", cfg.PRNumber},
 	}
 	for flag, mapping := range intFlags {
 		if cmd.Flags().Changed(flag) {
@@ -104,12 +296,35 @@ func buildCLIOverrides(cmd *cobra.Command, cfg *config.Config) map[string]string
 		}
 	}
 
+	// Float flags
+	floatFlags := map[string]struct {
+		key string
+		val float64
+	}{
+		"screenshot-threshold": {"SCREENSHOT_THRESHOLD", cfg.ScreenshotThreshold},
+		"min-confidence":       {"MIN_CONFIDENCE", cfg.MinConfidence},
+	}
+	for flag, mapping := range floatFlags {
+		if cmd.Flags().Changed(flag) {
+			overrides[mapping.key] = fmt.Sprintf("%g", mapping.val)
+		}
+	}
+
 	// Bool flags
 	boolFlags := map[string]struct {
 		key string
 		val bool
 	}{
-		"verbose": {"VERBOSE", cfg.Verbose},
+		"verbose":        {"VERBOSE", cfg.Verbose},
+		"apply-patch":    {"APPLY_PATCH", cfg.ApplyPatch},
+		"tui":            {"TUI", cfg.TUI},
+		"log-gzip":       {"LOG_GZIP", cfg.LogGzip},
+		"dedup-outputs":  {"DEDUP_OUTPUTS", cfg.DedupOutputs},
+		"allow-dirty":    {"ALLOW_DIRTY", cfg.AllowDirty},
+		"val-rotate":     {"VAL_ROTATE", cfg.ValRotate},
+		"github-check":   {"GITHUB_CHECK", cfg.GitHubCheck},
+		"annotate-tasks": {"ANNOTATE_TASKS", cfg.AnnotateTasks},
+		"triage":         {"TRIAGE", cfg.Triage},
 	}
 	for flag, mapping := range boolFlags {
 		if cmd.Flags().Changed(flag) {
@@ -125,9 +340,21 @@ func buildCLIOverrides(cmd *cobra.Command, cfg *config.Config) map[string]string
 	if cmd.Flags().Changed("no-learnings") {
 		overrides["ENABLE_LEARNINGS"] = "false"
 	}
+	if cmd.Flags().Changed("no-global-learnings") {
+		overrides["GLOBAL_LEARNINGS"] = "false"
+	}
+	if cmd.Flags().Changed("no-project-memory") {
+		overrides["PROJECT_MEMORY"] = "false"
+	}
 	if cmd.Flags().Changed("no-cross-validate") {
 		overrides["CROSS_VALIDATE"] = "false"
 	}
+	if cmd.Flags().Changed("no-pr-comment") {
+		overrides["PR_COMMENT"] = "false"
+	}
+	if cmd.Flags().Changed("no-cache") {
+		overrides["RESPONSE_CACHE"] = "false"
+	}
 
 	return overrides
 }
@@ -135,12 +362,8 @@ func buildCLIOverrides(cmd *cobra.Command, cfg *config.Config) map[string]string
 func runOrchestrator(cmd *cobra.Command, cfg *config.Config) error {
 	// Load config with full precedence chain
 	// CLI flags are already bound to cfg, now load file-based configs
-	globalConfigPath := ""
-	if home, err := os.UserHomeDir(); err == nil {
-		globalConfigPath = filepath.Join(home, ".config", "ralph-loop", "config")
-	}
-	projectConfigPath := filepath.Join(".ralph-loop", "config")
-	explicitConfigPath := cfg.ConfigFile
+	paths := configPaths(cfg.ConfigFile)
+	globalConfigPath, projectConfigPath, explicitConfigPath := paths[0], paths[1], paths[2]
 
 	// Build CLI overrides map using Changed() for accurate detection
 	cliOverrides := buildCLIOverrides(cmd, cfg)
@@ -161,17 +384,60 @@ func runOrchestrator(cmd *cobra.Command, cfg *config.Config) error {
 	// Store CLI override keys so resume logic knows which flags to preserve
 	finalCfg.CLIOverrides = cliOverrideKeys
 
+	// Keep the secret settings out of the logs and state files
+	secrets.Register(finalCfg.Secrets...)
+
+	// Encrypt the state and the finished iterations' outputs with STATE_KEY
+	stateKey, err := parseStateKey(finalCfg.StateKey)
+	if err != nil {
+		return fmt.Errorf("STATE_KEY: %w", err)
+	}
+	statecrypt.SetKey(stateKey)
+
+	// Keep the state where --state-dir or STATE_DIR says
+	if err := useStateDir(finalCfg.StateDir); err != nil {
+		return fmt.Errorf("state directory: %w", err)
+	}
+
+	// Give every phase its AI and model. The built-in models are claude's,
+	// so models left at them count as unset and follow the phase's AI.
+	defaults := config.NewDefaultConfig()
+	implModel, valModel := finalCfg.ImplModel, finalCfg.ValModel
+	if implModel == defaults.ImplModel {
+		implModel = ""
+	}
+	if valModel == defaults.ValModel {
+		valModel = ""
+	}
+	resolved := model.Resolve(map[model.Phase]model.Assignment{
+		model.PhaseImpl:      {AI: finalCfg.AIProvider, Model: implModel},
+		model.PhaseVal:       {AI: finalCfg.ValAI, Model: valModel},
+		model.PhaseCross:     {AI: finalCfg.CrossAI, Model: finalCfg.CrossModel},
+		model.PhaseFinalPlan: {AI: finalCfg.FinalPlanAI, Model: finalCfg.FinalPlanModel},
+		model.PhaseTasksVal:  {AI: finalCfg.TasksValAI, Model: finalCfg.TasksValModel},
+		model.PhaseSummary:   {AI: finalCfg.SummaryAI, Model: finalCfg.SummaryModel},
+		model.PhaseTriage:    {AI: finalCfg.TriageAI, Model: finalCfg.TriageModel},
+	})
+	finalCfg.AIProvider, finalCfg.ImplModel = resolved[model.PhaseImpl].AI, resolved[model.PhaseImpl].Model
+	finalCfg.ValAI, finalCfg.ValModel = resolved[model.PhaseVal].AI, resolved[model.PhaseVal].Model
+	finalCfg.CrossAI, finalCfg.CrossModel = resolved[model.PhaseCross].AI, resolved[model.PhaseCross].Model
+	finalCfg.FinalPlanAI, finalCfg.FinalPlanModel = resolved[model.PhaseFinalPlan].AI, resolved[model.PhaseFinalPlan].Model
+	finalCfg.TasksValAI, finalCfg.TasksValModel = resolved[model.PhaseTasksVal].AI, resolved[model.PhaseTasksVal].Model
+	finalCfg.SummaryAI, finalCfg.SummaryModel = resolved[model.PhaseSummary].AI, resolved[model.PhaseSummary].Model
+	finalCfg.TriageAI, finalCfg.TriageModel = resolved[model.PhaseTriage].AI, resolved[model.PhaseTriage].Model
+
+	// Rotation judges each iteration with one validator from the pool
+	if finalCfg.ValRotate {
+		if len(finalCfg.ValidatorPoolSpecs()) == 0 {
+			return fmt.Errorf("--val-rotate needs a validator pool (--validator-pool or VALIDATOR_POOL) to rotate through")
+		}
+		if finalCfg.Validators > 1 {
+			return fmt.Errorf("--val-rotate cannot be combined with --validators %d", finalCfg.Validators)
+		}
+	}
+
 	// Merge CLI-only flags (not in config files)
-	finalCfg.TasksFile = cfg.TasksFile
-	finalCfg.OriginalPlanFile = cfg.OriginalPlanFile
-	finalCfg.GithubIssue = cfg.GithubIssue
-	finalCfg.ConfigFile = cfg.ConfigFile
-	finalCfg.Resume = cfg.Resume
-	finalCfg.ResumeForce = cfg.ResumeForce
-	finalCfg.Clean = cfg.Clean
-	finalCfg.Status = cfg.Status
-	finalCfg.Cancel = cfg.Cancel
-	finalCfg.StartAt = cfg.StartAt
+	finalCfg.MergeCLIOnly(cfg)
 
 	// Replace cfg reference for subsequent use
 	cfg = finalCfg
@@ -179,20 +445,96 @@ func runOrchestrator(cmd *cobra.Command, cfg *config.Config) error {
 	// Set verbose mode
 	logging.SetVerbose(cfg.Verbose)
 
+	// Run the AI CLIs in containers with the runtime found on the host; with
+	// none, the command checks report docker missing
+	if cfg.ContainerImage != "" && cfg.ContainerRuntime == "" {
+		cfg.ContainerRuntime = ai.DetectRuntime()
+		if cfg.ContainerRuntime == "" {
+			cfg.ContainerRuntime = "docker"
+		}
+	}
+
+	// Every AI call goes to the remote agent, so it must be up
+	if cfg.RemoteAgent != "" && cfg.Replay == "" {
+		token := os.Getenv(agent.TokenEnv)
+		if token == "" {
+			return fmt.Errorf("--remote-agent needs the agent's token in %s", agent.TokenEnv)
+		}
+		pingCtx, pingCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		err := agent.Ping(pingCtx, cfg.RemoteAgent, token)
+		pingCancel()
+		if err != nil {
+			return fmt.Errorf("remote agent %s: %w", cfg.RemoteAgent, err)
+		}
+		logging.Info(fmt.Sprintf("Running AI calls on the agent at %s", cfg.RemoteAgent))
+	}
+
 	// Create context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Build AI runners based on config
 	orch := phases.NewOrchestrator(cfg)
+	orch.StateDir = stateDir
+	orch.WorkDir = "."
+	orch.LearningsLibrary = learnings.GlobalDir()
+	orch.CheckWorkspace = true
+	orch.ReloadConfig = func() (*config.Config, error) {
+		fresh, err := config.LoadWithPrecedence(globalConfigPath, projectConfigPath, explicitConfigPath, cliOverrides)
+		if err == nil {
+			secrets.Register(fresh.Secrets...)
+		}
+		return fresh, err
+	}
+	reg := metrics.New()
+	orch.Metrics = reg
+
+	// Trace the run when an OTLP endpoint is configured
+	tracer, err := tracing.NewFromEnv()
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Tracing disabled: %v", err))
+	}
+	if tracer != nil {
+		tracer.SetErrorHandler(func(err error) { logging.Debug(fmt.Sprintf("Trace export failed: %v", err)) })
+		orch.Tracer = tracer
+	}
+
+	// Record every AI call, or answer them all from a recording; a replay
+	// needs no AI CLI, so every provider counts as available, as it does
+	// when the CLIs run in a container image or on a remote agent
+	var rec *ai.Recording
+	available := func(provider string) bool { return ai.CheckAvailability(provider)[provider] }
+	if cfg.ContainerImage != "" || cfg.RemoteAgent != "" {
+		available = func(string) bool { return true }
+	}
+	if cfg.Record != "" {
+		rec = ai.NewRecording(cfg.Record)
+		logging.Info(fmt.Sprintf("Recording AI calls in %s", cfg.Record))
+	} else if cfg.Replay != "" {
+		rec = ai.NewRecording(cfg.Replay)
+		available = func(string) bool { return true }
+		orch.CommandChecker = func(tools ...string) map[string]bool {
+			avail := make(map[string]bool, len(tools))
+			for _, t := range tools {
+				avail[t] = true
+			}
+			return avail
+		}
+		logging.Info(fmt.Sprintf("Replaying AI calls from %s", cfg.Replay))
+	}
 
 	retryCfg := ai.RetryConfig{
 		MaxRetries: cfg.MaxClaudeRetry,
 		BaseDelay:  5,
+		MaxDelay:   300,
+		Jitter:     0.2,
+		OnEvent:    orch.RecordRetry,
 		OnRetry: func(attempt int, delay int) {
+			reg.IncRetry()
 			logging.Warn(fmt.Sprintf("Attempt %d failed. Retrying in %ds...", attempt+1, delay))
 		},
 		OnRateLimit: func(info *ratelimit.RateLimitInfo) {
+			reg.IncRateLimit()
 			if info != nil && info.Parseable {
 				logging.Warn(fmt.Sprintf("Rate limit detected (resets at %s)", info.ResetHuman))
 			} else {
@@ -201,95 +543,435 @@ func runOrchestrator(cmd *cobra.Command, cfg *config.Config) error {
 		},
 	}
 
-	// Setup implementation and validation runners
-	var rawImpl, rawVal ai.AIRunner
-	if cfg.AIProvider == model.Claude {
-		rawImpl = &ai.ClaudeRunner{
-			Model:             cfg.ImplModel,
-			MaxTurns:          cfg.MaxTurns,
-			Verbose:           cfg.Verbose,
-			InactivityTimeout: cfg.InactivityTimeout,
+	// Setup cross-validation first: its provider is the alternate that
+	// rate-limited work is rerouted to. MIN_CONFIDENCE and
+	// AUTO_CROSS_VALIDATE need the cross runner for low-confidence and
+	// suspicious verdicts even when cross-validation is off.
+	var crossAvailable bool
+	if cfg.CrossValidate || cfg.MinConfidence > 0 || cfg.AutoCrossValidate != "" {
+		crossAvailable = available(cfg.CrossAI)
+		if !crossAvailable {
+			logging.Warn(fmt.Sprintf("Cross-validation disabled: %s is not available", cfg.CrossAI))
+			cfg.CrossValidate = false
 		}
-		rawVal = &ai.ClaudeRunner{
-			Model:             cfg.ValModel,
-			MaxTurns:          cfg.MaxTurns,
-			Verbose:           cfg.Verbose,
-			InactivityTimeout: cfg.InactivityTimeout,
+	}
+	throttle := ai.NewThrottle()
+	// throttled wraps raw so it is rerouted to alt (on altProvider) or
+	// delayed while its provider is rate limited or overloaded.
+	throttled := func(raw ai.AIRunner, provider string, alt ai.AIRunner, altProvider string) ai.AIRunner {
+		return &ai.ThrottledRunner{
+			Inner:             raw,
+			Provider:          provider,
+			Alternate:         alt,
+			AlternateProvider: altProvider,
+			Throttle:          throttle,
+			OnReroute: func(from, to string, until time.Time) {
+				logging.Warn(fmt.Sprintf("%s is rate limited until %s; rerouting to %s", from, until.Format("15:04:05"), to))
+			},
+			OnDelay: func(provider string, until time.Time) {
+				logging.Warn(fmt.Sprintf("%s is rate limited; waiting until %s", provider, until.Format("15:04:05")))
+			},
 		}
-	} else {
-		rawImpl = &ai.CodexRunner{
-			Model:             cfg.ImplModel,
-			Verbose:           cfg.Verbose,
-			InactivityTimeout: cfg.InactivityTimeout,
+	}
+	// With a fallback AI, the primary is health-checked before the first
+	// call and after repeated failed calls; once it is found down, every
+	// phase on it moves to the fallback for the rest of the session
+	var failover *ai.Failover
+	if cfg.FallbackAI != "" && cfg.Replay == "" {
+		switch {
+		case cfg.FallbackAI == cfg.AIProvider:
+			logging.Warn(fmt.Sprintf("Failover disabled: the fallback AI is %s already", cfg.AIProvider))
+		case !available(cfg.FallbackAI):
+			logging.Warn(fmt.Sprintf("Failover disabled: %s is not available", cfg.FallbackAI))
+		default:
+			probe := newRunner(cfg, nil, cfg.AIProvider, model.DefaultSummaryModel(cfg.AIProvider), "HEALTH", config.Sampling{})
+			failover = &ai.Failover{
+				Primary:    cfg.AIProvider,
+				Secondary:  cfg.FallbackAI,
+				Threshold:  cfg.FailoverAfter,
+				Probe:      (&ai.HealthCheck{Provider: cfg.AIProvider, Runner: probe, Version: cfg.SandboxCmd == "" && cfg.ContainerImage == "" && cfg.RemoteAgent == ""}).Run,
+				OnFailover: orch.RecordFailover,
+			}
+			orch.Failover = failover
 		}
-		rawVal = &ai.CodexRunner{
-			Model:             cfg.ValModel,
-			Verbose:           cfg.Verbose,
-			InactivityTimeout: cfg.InactivityTimeout,
+	}
+	// failsOver lets a phase's runner on the primary AI move to the
+	// fallback, which runs the fallback's default model.
+	failsOver := func(runner ai.AIRunner, provider, phase string, s config.Sampling) ai.AIRunner {
+		if failover == nil || provider != failover.Primary {
+			return runner
+		}
+		fallbackModel := model.DefaultModelForAI(failover.Secondary)
+		if phase == "SUMMARY" {
+			fallbackModel = model.DefaultSummaryModel(failover.Secondary)
+		}
+		return &ai.FailoverRunner{
+			Primary:   runner,
+			Secondary: throttled(newRunner(cfg, rec, failover.Secondary, fallbackModel, phase, s), failover.Secondary, nil, ""),
+			Failover:  failover,
 		}
 	}
-	orch.ImplRunner = &ai.RetryRunner{Inner: rawImpl, RetryCfg: retryCfg}
-	orch.ValRunner = &ai.RetryRunner{Inner: rawVal, RetryCfg: retryCfg}
 
-	// Setup cross-validation runner
-	if cfg.CrossValidate {
-		crossAI, crossModel := model.SetupCrossValidation(cfg.AIProvider, cfg.CrossAI, cfg.CrossModel)
-		cfg.CrossAI = crossAI
-		cfg.CrossModel = crossModel
+	var altImpl, altVal ai.AIRunner
+	if crossAvailable && cfg.CrossAI != cfg.AIProvider {
+		altImpl = newRunner(cfg, rec, cfg.CrossAI, model.DefaultImplModel(cfg.CrossAI), "IMPL", cfg.ImplSampling)
+		altVal = newRunner(cfg, rec, cfg.CrossAI, cfg.CrossModel, "VAL", cfg.ValSampling)
+	}
 
-		avail := ai.CheckAvailability(crossAI)
-		if avail[crossAI] {
-			var rawCross ai.AIRunner
-			if crossAI == model.Claude {
-				rawCross = &ai.ClaudeRunner{Model: crossModel, MaxTurns: cfg.MaxTurns, Verbose: cfg.Verbose, InactivityTimeout: cfg.InactivityTimeout}
-			} else {
-				rawCross = &ai.CodexRunner{Model: crossModel, Verbose: cfg.Verbose, InactivityTimeout: cfg.InactivityTimeout}
+	// Setup implementation and validation runners
+	rawImpl := newRunner(cfg, rec, cfg.AIProvider, cfg.ImplModel, "IMPL", cfg.ImplSampling)
+	rawVal := newRunner(cfg, rec, cfg.ValAI, cfg.ValModel, "VAL", cfg.ValSampling)
+	orch.ImplRunner = &ai.RetryRunner{Inner: failsOver(throttled(rawImpl, cfg.AIProvider, altImpl, cfg.CrossAI), cfg.AIProvider, "IMPL", cfg.ImplSampling), RetryCfg: retryCfg}
+	orch.ValRunner = &ai.RetryRunner{Inner: failsOver(throttled(rawVal, cfg.ValAI, altVal, cfg.CrossAI), cfg.ValAI, "VAL", cfg.ValSampling), RetryCfg: retryCfg}
+
+	// Setup validator rotation or quorum
+	if cfg.ValRotate {
+		for _, spec := range cfg.ValidatorPoolSpecs() {
+			valModel := spec.Model
+			if valModel == "" {
+				valModel = model.DefaultValModel(spec.AI)
 			}
-			orch.CrossRunner = &ai.RetryRunner{Inner: rawCross, RetryCfg: retryCfg}
-		} else {
-			cfg.CrossValidate = false
+			raw := newRunner(cfg, rec, spec.AI, valModel, "VAL", cfg.ValSampling)
+			orch.ValRotation = append(orch.ValRotation, phases.QuorumMember{
+				Label:  spec.AI + "/" + valModel,
+				Runner: &ai.RetryRunner{Inner: failsOver(throttled(raw, spec.AI, nil, ""), spec.AI, "VAL", cfg.ValSampling), RetryCfg: retryCfg},
+			})
+		}
+	} else if cfg.Validators > 1 {
+		for _, spec := range cfg.ValidatorSpecs() {
+			valModel := spec.Model
+			if valModel == "" {
+				valModel = model.DefaultValModel(spec.AI)
+			}
+			raw := newRunner(cfg, rec, spec.AI, valModel, "VAL", cfg.ValSampling)
+			orch.ValQuorum = append(orch.ValQuorum, phases.QuorumMember{
+				Label:  spec.AI + "/" + valModel,
+				Runner: &ai.RetryRunner{Inner: failsOver(throttled(raw, spec.AI, nil, ""), spec.AI, "VAL", cfg.ValSampling), RetryCfg: retryCfg},
+			})
 		}
 	}
 
+	// Setup cross-validation runner; while the cross provider is limited,
+	// cross-validation falls back to the main provider.
+	if crossAvailable {
+		rawCross := newRunner(cfg, rec, cfg.CrossAI, cfg.CrossModel, "CROSS", cfg.CrossSampling)
+		altCross := newRunner(cfg, rec, cfg.ValAI, cfg.ValModel, "CROSS", cfg.CrossSampling)
+		orch.CrossRunner = &ai.RetryRunner{Inner: failsOver(throttled(rawCross, cfg.CrossAI, altCross, cfg.ValAI), cfg.CrossAI, "CROSS", cfg.CrossSampling), RetryCfg: retryCfg}
+	}
+
 	// Setup final-plan validation runner
 	if cfg.CrossValidate || cfg.FinalPlanAI != "" {
-		fpAI, fpModel := model.SetupFinalPlanValidation(cfg.CrossAI, cfg.CrossModel, cfg.FinalPlanAI, cfg.FinalPlanModel)
-		cfg.FinalPlanAI = fpAI
-		cfg.FinalPlanModel = fpModel
-
-		avail := ai.CheckAvailability(fpAI)
-		if avail[fpAI] {
-			var rawFP ai.AIRunner
-			if fpAI == model.Claude {
-				rawFP = &ai.ClaudeRunner{Model: fpModel, MaxTurns: cfg.MaxTurns, Verbose: cfg.Verbose, InactivityTimeout: cfg.InactivityTimeout}
-			} else {
-				rawFP = &ai.CodexRunner{Model: fpModel, Verbose: cfg.Verbose, InactivityTimeout: cfg.InactivityTimeout}
-			}
-			orch.FinalPlanRunner = &ai.RetryRunner{Inner: rawFP, RetryCfg: retryCfg}
+		if available(cfg.FinalPlanAI) {
+			rawFP := newRunner(cfg, rec, cfg.FinalPlanAI, cfg.FinalPlanModel, "FINAL_PLAN", cfg.FinalPlanSampling)
+			orch.FinalPlanRunner = &ai.RetryRunner{Inner: failsOver(throttled(rawFP, cfg.FinalPlanAI, nil, ""), cfg.FinalPlanAI, "FINAL_PLAN", cfg.FinalPlanSampling), RetryCfg: retryCfg}
+		} else {
+			logging.Warn(fmt.Sprintf("Final-plan validation disabled: %s is not available", cfg.FinalPlanAI))
 		}
 	}
 
-	// Setup tasks validation runner
-	tvAI, tvModel := model.SetupTasksValidation(cfg.AIProvider, cfg.ImplModel, cfg.TasksValAI, cfg.TasksValModel)
-	cfg.TasksValAI = tvAI
-	cfg.TasksValModel = tvModel
-	if cfg.OriginalPlanFile != "" || cfg.GithubIssue != "" {
-		var rawTV ai.AIRunner
-		if tvAI == model.Claude {
-			rawTV = &ai.ClaudeRunner{Model: tvModel, MaxTurns: cfg.MaxTurns, Verbose: cfg.Verbose, InactivityTimeout: cfg.InactivityTimeout}
+	// Setup tasks validation runner. Always set up: a spec-kit spec.md or
+	// plan.md may be found next to the tasks file, and the phase is
+	// skipped when there is no spec.
+	rawTV := newRunner(cfg, rec, cfg.TasksValAI, cfg.TasksValModel, "TASKS_VAL", cfg.TasksValSampling)
+	orch.TasksValRunner = &ai.RetryRunner{Inner: failsOver(throttled(rawTV, cfg.TasksValAI, nil, ""), cfg.TasksValAI, "TASKS_VAL", cfg.TasksValSampling), RetryCfg: retryCfg}
+
+	// Setup the runner summarizing implementation output too long for the
+	// validator
+	rawSummary := newRunner(cfg, rec, cfg.SummaryAI, cfg.SummaryModel, "SUMMARY", config.Sampling{})
+	orch.SummaryRunner = &ai.RetryRunner{Inner: failsOver(throttled(rawSummary, cfg.SummaryAI, nil, ""), cfg.SummaryAI, "SUMMARY", config.Sampling{}), RetryCfg: retryCfg}
+
+	// Setup the triage runner checking each iteration before validation
+	if cfg.Triage {
+		if available(cfg.TriageAI) {
+			rawTriage := newRunner(cfg, rec, cfg.TriageAI, cfg.TriageModel, "TRIAGE", config.Sampling{})
+			orch.TriageRunner = &ai.RetryRunner{Inner: failsOver(throttled(rawTriage, cfg.TriageAI, nil, ""), cfg.TriageAI, "TRIAGE", config.Sampling{}), RetryCfg: retryCfg}
 		} else {
-			rawTV = &ai.CodexRunner{Model: tvModel, Verbose: cfg.Verbose, InactivityTimeout: cfg.InactivityTimeout}
+			logging.Warn(fmt.Sprintf("Triage disabled: %s is not available", cfg.TriageAI))
 		}
-		orch.TasksValRunner = &ai.RetryRunner{Inner: rawTV, RetryCfg: retryCfg}
 	}
 
-	// Setup signal handler to save state on interrupt
-	sighandler.SetupSignalHandler(ctx, cancel, func() {
-		logging.Warn("Interrupted — saving state...")
+	// Find the PR for the run summary comment: --pr, the PR a GitHub
+	// Actions run was triggered for, or the branch's open PR. In CI the
+	// comment goes through the REST API with GITHUB_TOKEN, as gh may be
+	// missing there
+	if cfg.PRComment {
+		orch.PRNumber = cfg.PRNumber
+		if orch.PRNumber == 0 {
+			orch.PRNumber = ghissue.PRFromEnv()
+		}
+		if orch.PRNumber == 0 {
+			if n, err := ghissue.FindOpenPR(); err == nil && n > 0 {
+				orch.PRNumber = n
+			}
+		}
+		if orch.PRNumber > 0 {
+			orch.PRClient = ghissue.NewClientFromEnv()
+			logging.Debug(fmt.Sprintf("Run summary will be posted to PR #%d", orch.PRNumber))
+		}
+	}
+
+	// Report the session as a GitHub check run on the commit under test
+	if cfg.GitHubCheck {
+		client := ghissue.NewClientFromEnv()
+		sha, err := ghissue.HeadSHA()
+		switch {
+		case client == nil:
+			logging.Warn("GitHub check run disabled: set GITHUB_TOKEN and GITHUB_REPOSITORY")
+		case err != nil:
+			logging.Warn(fmt.Sprintf("GitHub check run disabled: %v", err))
+		default:
+			orch.Checks, orch.CheckSHA = client, sha
+		}
+	}
+
+	// SIGINT saves the state and exits at once; SIGTERM lets the AI call in
+	// progress finish first, for up to the grace period; SIGHUP reloads the
+	// config before the next iteration
+	sighandler.Watch(ctx, cancel, sighandler.Handlers{
+		OnInterrupt: func(sig os.Signal) {
+			orch.RecordInterrupt(sighandler.Name(sig))
+			logging.Warn("Interrupted — saving state...")
+		},
+		OnTerminate: func(sig os.Signal) {
+			orch.RequestShutdown(sighandler.Name(sig))
+			logging.Warn(fmt.Sprintf("%s received — stopping once the current AI call finishes (at most %s; send it again to stop now)",
+				sighandler.Name(sig), cfg.ShutdownGrace))
+		},
+		OnReload: func() {
+			orch.RequestReload()
+			logging.Info("SIGHUP received — reloading the config before the next iteration")
+		},
+		Grace: cfg.ShutdownGrace,
 	})
 
+	// Expose metrics while the loop runs
+	stopMetrics := func() {}
+	if cfg.MetricsAddr != "" {
+		srv, err := reg.Serve(cfg.MetricsAddr)
+		if err != nil {
+			logging.Warn(fmt.Sprintf("Metrics endpoint disabled: %v", err))
+		} else {
+			logging.Info(fmt.Sprintf("Serving metrics on http://%s/metrics", cfg.MetricsAddr))
+			stopMetrics = func() { _ = srv.Close() }
+		}
+	}
+
+	// Switch to the live dashboard last, so setup warnings stay visible
+	var dash *tui.Dashboard
+	if cfg.TUI {
+		if !isatty.IsTerminal(os.Stderr.Fd()) && !isatty.IsCygwinTerminal(os.Stderr.Fd()) {
+			logging.Warn("--tui ignored: stderr is not a terminal")
+		} else if d, err := tui.Start(os.Stderr); err != nil {
+			logging.Warn(fmt.Sprintf("--tui disabled: %v", err))
+		} else {
+			dash = d
+			orch.Dashboard = d
+		}
+	}
+
+	// Start the status server after the dashboard so its log capture sees
+	// output before the dashboard swallows it
+	stopServer := func() {}
+	if cfg.Serve != "" {
+		hub := web.NewHub()
+		restore, err := web.CaptureStderr(hub)
+		if err != nil {
+			logging.Warn(fmt.Sprintf("Status server disabled: %v", err))
+		} else {
+			server := &web.Server{
+				StateDir: orch.StateDir,
+				Hub:      hub,
+				Cancel:   cancel,
+				Escalate: orch.RequestEscalation,
+				Token:    os.Getenv(web.TokenEnv),
+			}
+			srv, err := server.Start(cfg.Serve)
+			if err != nil {
+				restore()
+				logging.Warn(fmt.Sprintf("Status server disabled: %v", err))
+			} else {
+				logging.Info(fmt.Sprintf("Status server listening on http://%s/", srv.Addr))
+				stopServer = func() {
+					_ = srv.Close()
+					restore()
+				}
+			}
+		}
+	}
+
 	// Run orchestrator
 	exitCode := orch.Run(ctx)
+	stopServer()
+	dash.Stop()
+	reg.SetExitCode(exitCode)
+	stopMetrics()
+	if tracer != nil {
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := tracer.Shutdown(shutdownCtx); err != nil {
+			logging.Warn(fmt.Sprintf("Failed to flush traces: %v", err))
+		}
+		cancelShutdown()
+	}
+	if cfg.MetricsFile != "" {
+		if err := reg.WriteTextfile(cfg.MetricsFile); err != nil {
+			logging.Warn(fmt.Sprintf("Failed to write metrics file: %v", err))
+		}
+	}
 	os.Exit(exitCode)
 	return nil // unreachable
 }
+
+// runnerPermissions returns the ALLOWED_TOOLS / DENIED_COMMANDS policy for
+// a phase's runner, read-only for the implementer with --apply-patch, or
+// nil when there is none, warning about the parts the provider's CLI
+// cannot enforce.
+func runnerPermissions(cfg *config.Config, provider, phase string) *ai.Permissions {
+	perms := &ai.Permissions{AllowedTools: cfg.AllowedToolList(), DeniedCommands: cfg.DeniedCommandList()}
+	// The implementer of --apply-patch outputs a diff for ralph-loop to
+	// apply; its own edits would conflict with it.
+	perms.ReadOnly = cfg.ApplyPatch && phase == "IMPL"
+	if !perms.Restricted() {
+		return nil
+	}
+	switch provider {
+	case model.Codex:
+		if perms.ReadOnly {
+			break
+		}
+		logging.Warn(fmt.Sprintf("%s: the codex CLI cannot filter tools or commands; running it in its workspace-write sandbox instead", phase))
+	case model.AmazonQ:
+		if len(perms.DeniedCommands) > 0 {
+			logging.Warn(fmt.Sprintf("%s: DENIED_COMMANDS ignored: the amazonq CLI cannot deny single commands", phase))
+		}
+	}
+	return perms
+}
+
+// cachedPhases are the phases whose responses are cached: their agents
+// only read the workspace, so an identical prompt over unchanged files
+// deserves the same answer.
+var cachedPhases = map[string]bool{"TASKS_VAL": true, "FINAL_PLAN": true, "SUMMARY": true}
+
+// newRunner builds the AI runner for one phase. phase is the config key
+// prefix ("IMPL", "VAL", ...) used in warnings and recordings. With --record the CLI's calls are
+// saved in rec; with --replay they are answered from it instead. With
+// --remote-agent the calls run on the agent daemon. The
+// cachedPhases go through the response cache unless --no-cache is given.
+func newRunner(cfg *config.Config, rec *ai.Recording, provider, modelName, phase string, s config.Sampling) ai.AIRunner {
+	if rec != nil && cfg.Replay != "" {
+		return &ai.TracedRunner{
+			Inner: &ai.ReplayRunner{
+				Recording: rec,
+				Phase:     phase,
+				OnPromptMismatch: func(phase string, call int) {
+					logging.Warn(fmt.Sprintf("Replay: %s call %d has a different prompt than the recorded one", phase, call))
+				},
+			},
+			Provider: provider,
+			Model:    modelName,
+			Phase:    phase,
+		}
+	}
+	var runner ai.AIRunner
+	if cfg.RemoteAgent != "" {
+		runner = &agent.RemoteRunner{
+			URL:             cfg.RemoteAgent,
+			Token:           os.Getenv(agent.TokenEnv),
+			Phase:           phase,
+			Provider:        provider,
+			Model:           modelName,
+			ReasoningEffort: s.ReasoningEffort,
+		}
+	} else {
+		runner = newCLIRunner(cfg, provider, modelName, phase, s)
+	}
+	if cfg.ResponseCache && cachedPhases[phase] {
+		runner = &ai.CachedRunner{
+			Inner:    runner,
+			Dir:      ai.DefaultCacheDir(),
+			TTL:      cfg.CacheTTL,
+			Provider: provider,
+			Model:    modelName,
+			Phase:    phase,
+			Fingerprint: func() (string, error) {
+				return gitdiff.Snapshot(".", projectExclude(stateDir)...)
+			},
+			OnHit: func(phase string, age time.Duration) {
+				logging.Info(fmt.Sprintf("%s: reusing the response to an identical prompt from %s ago (--no-cache to call the AI)", phase, age.Round(time.Second)))
+			},
+		}
+	}
+	if rec != nil {
+		runner = &ai.RecordingRunner{Inner: runner, Recording: rec, Phase: phase}
+	}
+	return &ai.TracedRunner{Inner: runner, Provider: provider, Model: modelName, Phase: phase}
+}
+
+// newCLIRunner builds the runner that calls the provider's CLI on this
+// machine, for newRunner and the agent daemon. q and gh copilot accept no
+// reasoning effort, so it is reported and ignored for them.
+func newCLIRunner(cfg *config.Config, provider, modelName, phase string, s config.Sampling) ai.AIRunner {
+	if s.ReasoningEffort != "" && (provider == model.AmazonQ || provider == model.Copilot) {
+		logging.Warn(fmt.Sprintf("%s_REASONING_EFFORT=%s ignored: the %s CLI does not accept a reasoning effort", phase, s.ReasoningEffort, provider))
+	}
+	var sandbox *ai.Sandbox
+	switch {
+	case cfg.ContainerImage != "":
+		sandbox = &ai.Sandbox{Container: &ai.Container{Runtime: cfg.ContainerRuntime, Image: cfg.ContainerImage, Env: cfg.SandboxEnvVars()}}
+	case cfg.SandboxCmd != "":
+		sandbox = &ai.Sandbox{Command: cfg.SandboxCmd, Env: cfg.SandboxEnvVars()}
+	}
+	perms := runnerPermissions(cfg, provider, phase)
+	var runner ai.AIRunner
+	switch provider {
+	case model.Claude:
+		runner = &ai.ClaudeRunner{
+			Model:             modelName,
+			MaxTurns:          cfg.MaxTurns,
+			Verbose:           cfg.Verbose,
+			InactivityTimeout: cfg.InactivityTimeout,
+			HangTimeout:       cfg.HangTimeout,
+			ReasoningEffort:   s.ReasoningEffort,
+			Sandbox:           sandbox,
+			Permissions:       perms,
+		}
+	case model.AmazonQ:
+		runner = &ai.AmazonQRunner{
+			Model:             modelName,
+			InactivityTimeout: cfg.InactivityTimeout,
+			HangTimeout:       cfg.HangTimeout,
+			Sandbox:           sandbox,
+			Permissions:       perms,
+		}
+	case model.Copilot:
+		runner = &ai.CopilotRunner{
+			Model:             modelName,
+			InactivityTimeout: cfg.InactivityTimeout,
+			HangTimeout:       cfg.HangTimeout,
+			Sandbox:           sandbox,
+			Permissions:       perms,
+		}
+	default:
+		runner = &ai.CodexRunner{
+			Model:             modelName,
+			Verbose:           cfg.Verbose,
+			InactivityTimeout: cfg.InactivityTimeout,
+			HangTimeout:       cfg.HangTimeout,
+			ReasoningEffort:   s.ReasoningEffort,
+			Sandbox:           sandbox,
+			Permissions:       perms,
+		}
+	}
+	if cfg.K8sJobImage != "" && phase == "IMPL" {
+		runner = &ai.JobRunner{
+			Job: &ai.KubeJob{
+				Namespace: cfg.K8sJobNamespace,
+				Image:     cfg.K8sJobImage,
+				Volume:    cfg.K8sJobPVC,
+				Secrets:   cfg.K8sJobSecretNames(),
+			},
+			Runner: runner,
+		}
+	}
+	return runner
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	"github.com/CodexForgeBR/cli-tools/internal/mcp"
+	sighandler "github.com/CodexForgeBR/cli-tools/internal/signal"
+)
+
+// resumeLogName is the file in the state directory that receives the
+// output of loops resumed through the MCP server.
+const resumeLogName = "mcp-resume.log"
+
+// newMCPCmd builds `ralph-loop mcp`, which serves the session in this
+// directory to other AI tools over the Model Context Protocol.
+func newMCPCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "Serve the session to AI tools over MCP (stdio)",
+		Long:  "Run a Model Context Protocol server on stdin/stdout so that other AI tools and agents can query the session in this directory (status, iteration history and outputs, tasks) and cancel or resume it. Register it with your MCP client as the command `ralph-loop mcp`, run from the project directory. A resumed loop runs in the background with its output in the state directory's " + resumeLogName + ".",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := useProjectStateDir(cmd); err != nil {
+				return err
+			}
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+			sighandler.SetupSignalHandler(ctx, cancel, nil)
+			server := &mcp.Server{
+				StateDir:  stateDir,
+				Name:      "ralph-loop",
+				Version:   version,
+				Interrupt: interruptProcess,
+				Resume:    startResume,
+			}
+			return server.Serve(ctx, cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	}
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
+
+// interruptProcess stops the loop running as pid as Ctrl+C would.
+func interruptProcess(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Signal(os.Interrupt)
+}
+
+// startResume runs `ralph-loop --resume` in the background, appending its
+// output to the resume log.
+func startResume(force bool) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	logFile, err := os.OpenFile(filepath.Join(stateDir, resumeLogName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	flag := "--resume"
+	if force {
+		flag = "--resume-force"
+	}
+	loop := exec.Command(exe, flag, "--state-dir", stateDir)
+	loop.Stdout, loop.Stderr = logFile, logFile
+	if err := loop.Start(); err != nil {
+		return fmt.Errorf("run %s %s: %w", exe, flag, err)
+	}
+	// Reap the loop when it exits; the server may be gone by then.
+	go func() { _ = loop.Wait() }()
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/report"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// newReportCmd builds `ralph-loop report`, which renders the session's
+// burn-down as markdown or HTML.
+func newReportCmd() *cobra.Command {
+	var format, output string
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Render a burn-down report of the session",
+		Long:  "Render the session in .ralph-loop as a burn-down report: the tasks left after each iteration, the verdicts, the cost per iteration, the blocked tasks and the learnings. Markdown suits a pull request; HTML is a standalone page with a chart.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := useProjectStateDir(cmd); err != nil {
+				return err
+			}
+			if format != "markdown" && format != "md" && format != "html" {
+				return fmt.Errorf("unknown format %q (want markdown or html)", format)
+			}
+			s, err := state.LoadState(stateDir)
+			if err != nil {
+				return fmt.Errorf("no session to report on: %w", err)
+			}
+			r := report.Build(s, stateDir)
+
+			if output == "" {
+				return writeReport(cmd.OutOrStdout(), format, r)
+			}
+			f, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("create report: %w", err)
+			}
+			err = writeReport(f, format, r)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(output)
+				return err
+			}
+			logging.Success(fmt.Sprintf("Wrote the report of session %s to %s", r.SessionID, output))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "markdown", "Output format: markdown or html")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write (default: stdout)")
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
+
+// writeReport writes r to w in format.
+func writeReport(w io.Writer, format string, r report.Report) error {
+	if format == "html" {
+		return report.WriteHTML(w, r)
+	}
+	report.WriteMarkdown(w, r)
+	return nil
+}
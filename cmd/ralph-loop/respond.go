@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// newRespondCmd builds `ralph-loop respond`, which records a human decision
+// for an escalated session.
+func newRespondCmd() *cobra.Command {
+	var message string
+	cmd := &cobra.Command{
+		Use:   "respond",
+		Short: "Record guidance for the next implementation prompt",
+		Long:  "Answer an escalation: record a decision in the session in .ralph-loop so that `ralph-loop --resume` includes it in the next implementation prompt. Each message is given to the implementer once; respond again to add more.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := useProjectStateDir(cmd); err != nil {
+				return err
+			}
+			if _, err := state.LoadState(stateDir); err != nil {
+				return fmt.Errorf("no session to respond to in %s: %w", stateDir, err)
+			}
+			// A running loop would overwrite the state; refuse to race it.
+			lock, err := state.AcquireLock(stateDir, false)
+			if err != nil {
+				return err
+			}
+			defer lock.Release()
+
+			s, err := state.LoadState(stateDir)
+			if err != nil {
+				return err
+			}
+			if !state.AddHumanGuidance(s, message, time.Now()) {
+				return fmt.Errorf("--message must not be empty")
+			}
+			if err := state.SaveState(s, stateDir); err != nil {
+				return err
+			}
+			logging.Success(fmt.Sprintf("Recorded guidance for session %s; continue with ralph-loop --resume", s.SessionID))
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&message, "message", "m", "", "The decision or guidance for the implementer")
+	_ = cmd.MarkFlagRequired("message")
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/rollback"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// newRollbackCmd builds `ralph-loop rollback`, which undoes the iterations
+// after a given one so the loop can be resumed from there.
+func newRollbackCmd() *cobra.Command {
+	var to int
+	var keepFiles bool
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Undo the iterations after a given one",
+		Long:  "Return the session in .ralph-loop and the working tree to where they stood after an iteration: the files the later iterations changed are restored from the snapshot taken before them, and the iteration counter, task statuses and feedback are rewound. The undone iteration directories are moved to .ralph-loop/rolled-back-<time>, with the tree hash to bring their changes back. Continue with ralph-loop --resume. Files are restored only in a git repository; --keep-files rewinds the session alone.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := useProjectStateDir(cmd); err != nil {
+				return err
+			}
+			// A running loop would overwrite the state; refuse to race it.
+			lock, err := state.AcquireLock(stateDir, false)
+			if err != nil {
+				return err
+			}
+			defer lock.Release()
+
+			res, err := rollback.Run(rollback.Options{StateDir: stateDir, To: to, KeepFiles: keepFiles})
+			if err != nil {
+				return err
+			}
+			if !keepFiles {
+				logging.Info(fmt.Sprintf("Restored %d file(s); `git checkout %s -- .` brings the undone changes back", len(res.Restored), res.Tree))
+			}
+			logging.Info(fmt.Sprintf("Undone iteration directories moved to %s", res.Backup))
+			logging.Success(fmt.Sprintf("Rolled back from iteration %d to %d; continue with ralph-loop --resume", res.From, to))
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&to, "to-iteration", 0, "Iteration to return to; the ones after it are undone (0 undoes them all)")
+	cmd.Flags().BoolVar(&keepFiles, "keep-files", false, "Rewind the session only and leave the working tree as it is")
+	_ = cmd.MarkFlagRequired("to-iteration")
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/selfupdate"
+)
+
+// newSelfUpdateCmd builds `ralph-loop self-update`, which replaces this
+// binary with the latest GitHub release.
+func newSelfUpdateCmd() *cobra.Command {
+	var check, force bool
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Update ralph-loop to the latest release",
+		Long:  "Check GitHub for the latest ralph-loop release and, if it is newer than this binary, download the build for this platform, verify its SHA-256 against the release's checksums.txt and atomically replace this binary. GITHUB_TOKEN, when set, is used to avoid API rate limits.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := &selfupdate.Client{Token: os.Getenv("GITHUB_TOKEN")}
+			rel, err := client.Latest()
+			if err != nil {
+				return err
+			}
+
+			cmp, comparable := selfupdate.CompareVersions(version, rel.Version())
+			switch {
+			case comparable && cmp >= 0 && !force:
+				logging.Success(fmt.Sprintf("ralph-loop %s is up to date (latest release: %s)", version, rel.Tag))
+				return nil
+			case !comparable && !force:
+				logging.Warn(fmt.Sprintf("This is a development build (%s); latest release is %s. Use --force to replace it.", version, rel.Tag))
+				return nil
+			}
+			if check {
+				logging.Info(fmt.Sprintf("Update available: %s -> %s", version, rel.Tag))
+				return nil
+			}
+
+			exe, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("locate running binary: %w", err)
+			}
+			logging.Info(fmt.Sprintf("Downloading %s", rel.ArchiveName(runtime.GOOS, runtime.GOARCH)))
+			binary, err := client.Download(rel, runtime.GOOS, runtime.GOARCH)
+			if err != nil {
+				return err
+			}
+			if err := selfupdate.Replace(exe, binary); err != nil {
+				return err
+			}
+			logging.Success(fmt.Sprintf("Updated ralph-loop %s -> %s", version, rel.Tag))
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&check, "check", false, "Only report whether an update is available")
+	cmd.Flags().BoolVar(&force, "force", false, "Install the latest release even if it is not newer")
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
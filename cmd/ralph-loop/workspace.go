@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/cli"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	sighandler "github.com/CodexForgeBR/cli-tools/internal/signal"
+	"github.com/CodexForgeBR/cli-tools/internal/workspace"
+)
+
+// newWorkspaceCmd builds `ralph-loop workspace`, which runs a loop in every
+// repository of a workspace manifest.
+func newWorkspaceCmd() *cobra.Command {
+	var manifestPath, outputDir string
+	var parallel, keepGoing bool
+	cmd := &cobra.Command{
+		Use:   "workspace [-f <manifest>] [--parallel] [-- <ralph-loop flags>]",
+		Short: "Run loops across the repositories of a workspace manifest",
+		Long:  "Run a loop in every repository listed in the workspace manifest (" + workspace.DefaultManifest + " by default), each on its own tasks file, one after the other or with --parallel at the same time. A sequential run stops at the first repository that does not complete unless --keep-going is set. The loops share one learnings file, " + workspace.DirName + "/learnings.md next to the manifest, unless the manifest sets shared_learnings: false. Flags after -- are passed to every loop, after the manifest's. The combined summary, results.json and each repository's output are written to --output-dir, " + workspace.DirName + " next to the manifest by default.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, err := workspace.Load(manifestPath)
+			if err != nil {
+				return err
+			}
+			if outputDir == "" {
+				outputDir = filepath.Join(m.Dir, workspace.DirName)
+			}
+			exe, err := os.Executable()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+			sighandler.SetupSignalHandler(ctx, cancel, nil)
+			// Every repository keeps its state where --state-dir or STATE_DIR
+			// puts it for that repository.
+			stateDirValue, _ := cmd.Flags().GetString("state-dir")
+			if stateDirValue != "" {
+				args = append(args, "--state-dir", stateDirValue)
+			} else {
+				settings, err := projectSettings()
+				if err != nil {
+					return err
+				}
+				stateDirValue = settingValue(settings, "STATE_DIR")
+			}
+			var mu sync.Mutex
+			started := 0
+			results, runErr := workspace.Run(ctx, workspace.Config{
+				Manifest:  m,
+				Parallel:  parallel,
+				KeepGoing: keepGoing,
+				Args:      args,
+				StateDir:  stateDirValue,
+				LogDir:    filepath.Join(outputDir, "logs"),
+				Exec: func(ctx context.Context, dir string, args []string, out io.Writer) (int, error) {
+					return runLoop(ctx, exe, dir, args, out)
+				},
+				Progress: func(r workspace.Repo) {
+					mu.Lock()
+					defer mu.Unlock()
+					started++
+					logging.Info(fmt.Sprintf("[%d/%d] %s (%s)", started, len(m.Repos), r.Name, r.Path))
+				},
+			})
+			if len(results) > 0 {
+				if err := writeWorkspaceSummary(cmd.OutOrStdout(), outputDir, m, results); err != nil {
+					return err
+				}
+			}
+			if runErr != nil {
+				return runErr
+			}
+			failed := 0
+			for _, r := range results {
+				if !r.Completed() {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d repositories did not complete", failed, len(results))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&manifestPath, "file", "f", workspace.DefaultManifest, "Workspace manifest")
+	cmd.Flags().BoolVar(&parallel, "parallel", false, "Run the repositories' loops at the same time")
+	cmd.Flags().BoolVar(&keepGoing, "keep-going", false, "Keep running the remaining repositories after one does not complete")
+	cmd.Flags().StringVarP(&outputDir, "output-dir", "o", "", "Directory for the summary and each repository's output (default: "+workspace.DirName+" next to the manifest)")
+	cli.SetSubcommandHelp(cmd)
+	return cmd
+}
+
+// writeWorkspaceSummary writes summary.md and results.json to dir and
+// prints the summary.
+func writeWorkspaceSummary(out io.Writer, dir string, m *workspace.Manifest, results []workspace.Result) error {
+	summary, err := os.Create(filepath.Join(dir, "summary.md"))
+	if err != nil {
+		return err
+	}
+	defer summary.Close()
+	workspace.WriteMarkdown(io.MultiWriter(out, summary), m, results)
+
+	data, err := json.MarshalIndent(map[string]any{
+		"workspace":      m.Name,
+		"learnings_file": m.LearningsFile(),
+		"repositories":   results,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "results.json"), data, 0644); err != nil {
+		return err
+	}
+	logging.Success(fmt.Sprintf("Workspace summary written to %s", filepath.Join(dir, "summary.md")))
+	return nil
+}
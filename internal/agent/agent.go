@@ -0,0 +1,315 @@
+// Package agent runs AI CLI calls on another machine: an agent daemon
+// serves runs over HTTP, and RemoteRunner drives it from the orchestrator,
+// streaming the prompt out and the CLI output and output files back.
+//
+// The protocol is JSON over HTTP, authenticated with a bearer token:
+//
+//	POST   /v1/runs                       start a run (RunRequest) -> {"id": ...}
+//	GET    /v1/runs/{id}/events?after=N   stream the run's events after N as JSON lines
+//	DELETE /v1/runs/{id}                  cancel a run
+//	GET    /v1/health                     check the agent is up
+//
+// A run lives on the agent independently of the connection that started it,
+// so a client that loses the event stream reconnects and resumes after the
+// last event it received.
+package agent
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/ai"
+	"github.com/CodexForgeBR/cli-tools/internal/ratelimit"
+)
+
+// TokenEnv is the environment variable holding the token shared by the
+// agent daemon and its clients.
+const TokenEnv = "RALPH_AGENT_TOKEN"
+
+// DefaultRetain is how long a finished run stays on the agent for clients
+// to collect its result.
+const DefaultRetain = 10 * time.Minute
+
+// outputName is the output file of a run in its scratch directory; the
+// runner's side files (outputName + ".stream.json", ...) are returned too.
+const outputName = "output.txt"
+
+// Event types.
+const (
+	EventOutput = "output" // a chunk of the CLI's raw output
+	EventResult = "result" // the run finished; always the last event
+)
+
+// RunRequest is the body of POST /v1/runs.
+type RunRequest struct {
+	Phase           string `json:"phase"`
+	Provider        string `json:"provider"`
+	Model           string `json:"model"`
+	ReasoningEffort string `json:"reasoning_effort,omitempty"`
+	Prompt          string `json:"prompt"`
+}
+
+// Event is one line of a run's event stream. Seq numbers a run's events
+// from 1.
+type Event struct {
+	Seq    int     `json:"seq"`
+	Type   string  `json:"type"`
+	Data   string  `json:"data,omitempty"`
+	Result *Result `json:"result,omitempty"`
+}
+
+// Result is how a run ended: the output the runner wrote, its side files
+// keyed by suffix, and its error, if any, with the error's class so the
+// client can rebuild rate-limit and authentication errors.
+type Result struct {
+	Output     string                   `json:"output"`
+	Files      map[string]string        `json:"files,omitempty"`
+	Error      string                   `json:"error,omitempty"`
+	ErrorClass ai.ErrorClass            `json:"error_class,omitempty"`
+	RateLimit  *ratelimit.RateLimitInfo `json:"rate_limit,omitempty"`
+}
+
+// Server is the agent daemon's HTTP handler. It runs every requested call
+// with the runner NewRunner builds, in the agent's working directory.
+type Server struct {
+	Token     string
+	NewRunner func(req RunRequest) (ai.AIRunner, error)
+	Retain    time.Duration // 0 means DefaultRetain
+
+	mu   sync.Mutex
+	runs map[string]*run
+}
+
+// run is a call in progress or finished.
+type run struct {
+	mu       sync.Mutex
+	events   []Event
+	changed  chan struct{} // closed and replaced on every new event
+	done     bool
+	finished time.Time
+	cancel   context.CancelFunc
+}
+
+// Handler returns the HTTP routes, all behind the bearer token.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/health", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("POST /v1/runs", s.handleStart)
+	mux.HandleFunc("GET /v1/runs/{id}/events", s.handleEvents)
+	mux.HandleFunc("DELETE /v1/runs/{id}", s.handleCancel)
+	return s.authenticate(mux)
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) != 1 {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleStart(w http.ResponseWriter, r *http.Request) {
+	var req RunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	runner, err := s.NewRunner(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	id, err := newRunID()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// The run outlives this request: the client streams its events on
+	// other connections and may reconnect
+	ctx, cancel := context.WithCancel(context.Background())
+	rn := &run{changed: make(chan struct{}), cancel: cancel}
+	s.mu.Lock()
+	s.prune()
+	if s.runs == nil {
+		s.runs = map[string]*run{}
+	}
+	s.runs[id] = rn
+	s.mu.Unlock()
+	go rn.execute(ctx, runner, req.Prompt)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(map[string]string{"id": id})
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	rn := s.lookup(r.PathValue("id"))
+	if rn == nil {
+		http.NotFound(w, r)
+		return
+	}
+	after, _ := strconv.Atoi(r.URL.Query().Get("after"))
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	for {
+		events, changed, done := rn.since(after)
+		for _, ev := range events {
+			if err := enc.Encode(ev); err != nil {
+				return
+			}
+			after = ev.Seq
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if done && len(events) == 0 {
+			return
+		}
+		if len(events) > 0 {
+			continue
+		}
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request) {
+	rn := s.lookup(r.PathValue("id"))
+	if rn == nil {
+		http.NotFound(w, r)
+		return
+	}
+	rn.cancel()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) lookup(id string) *run {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.runs[id]
+}
+
+// prune forgets the runs finished more than Retain ago. s.mu must be held.
+func (s *Server) prune() {
+	retain := s.Retain
+	if retain <= 0 {
+		retain = DefaultRetain
+	}
+	for id, rn := range s.runs {
+		rn.mu.Lock()
+		expired := rn.done && time.Since(rn.finished) > retain
+		rn.mu.Unlock()
+		if expired {
+			delete(s.runs, id)
+		}
+	}
+}
+
+// execute runs runner in a scratch directory, streaming its output as
+// events, and ends with the result event.
+func (rn *run) execute(ctx context.Context, runner ai.AIRunner, prompt string) {
+	defer rn.cancel()
+	res := &Result{}
+	dir, err := os.MkdirTemp("", "ralph-agent-")
+	if err != nil {
+		res.Error, res.ErrorClass = err.Error(), ai.ClassTransient
+		rn.finish(res)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	outputPath := filepath.Join(dir, outputName)
+	err = runner.Run(ai.WithOutputLog(ctx, outputWriter{rn}), prompt, outputPath)
+	if data, readErr := os.ReadFile(outputPath); readErr == nil {
+		res.Output = string(data)
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		suffix, ok := strings.CutPrefix(e.Name(), outputName)
+		if !ok || suffix == "" || e.IsDir() {
+			continue
+		}
+		if data, readErr := os.ReadFile(filepath.Join(dir, e.Name())); readErr == nil {
+			if res.Files == nil {
+				res.Files = map[string]string{}
+			}
+			res.Files[suffix] = string(data)
+		}
+	}
+	if err != nil {
+		res.Error, res.ErrorClass = err.Error(), ai.Classify(err)
+		var rl *ai.RateLimitError
+		if errors.As(err, &rl) {
+			res.RateLimit = rl.Info
+		}
+	}
+	rn.finish(res)
+}
+
+// add appends ev, marking the run finished when ev is the result.
+func (rn *run) add(ev Event) {
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+	ev.Seq = len(rn.events) + 1
+	rn.events = append(rn.events, ev)
+	if ev.Type == EventResult {
+		rn.done = true
+		rn.finished = time.Now()
+	}
+	close(rn.changed)
+	rn.changed = make(chan struct{})
+}
+
+func (rn *run) finish(res *Result) {
+	rn.add(Event{Type: EventResult, Result: res})
+}
+
+// since returns the events after seq, the channel closed on the next
+// event, and whether the run has finished.
+func (rn *run) since(seq int) ([]Event, <-chan struct{}, bool) {
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+	var events []Event
+	if seq < len(rn.events) {
+		events = append(events, rn.events[max(seq, 0):]...)
+	}
+	return events, rn.changed, rn.done
+}
+
+// outputWriter turns the CLI output of a run into output events.
+type outputWriter struct{ rn *run }
+
+func (w outputWriter) Write(p []byte) (int, error) {
+	w.rn.add(Event{Type: EventOutput, Data: string(p)})
+	return len(p), nil
+}
+
+func newRunID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,234 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/ai"
+	"github.com/CodexForgeBR/cli-tools/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRunner streams "working" to the output log, writes output and a
+// side file, and returns err.
+type fakeRunner struct {
+	err     error
+	block   bool // wait for cancellation instead
+	stopped chan struct{}
+}
+
+func (f *fakeRunner) Run(ctx context.Context, prompt, outputPath string) error {
+	if log := ai.OutputLog(ctx); log != nil {
+		io.WriteString(log, "working on "+prompt+"\n")
+	}
+	if f.block {
+		<-ctx.Done()
+		close(f.stopped)
+		return ctx.Err()
+	}
+	os.WriteFile(outputPath+".stream.json", []byte(`{"type":"result"}`), 0644)
+	os.WriteFile(outputPath, []byte("done: "+prompt), 0644)
+	return f.err
+}
+
+func newAgent(t *testing.T, runner ai.AIRunner) (*httptest.Server, *[]RunRequest) {
+	var mu sync.Mutex
+	var requests []RunRequest
+	s := &Server{Token: "secret", NewRunner: func(req RunRequest) (ai.AIRunner, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, req)
+		if req.Provider == "unknown" {
+			return nil, fmt.Errorf("unknown AI %q", req.Provider)
+		}
+		return runner, nil
+	}}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestRemoteRunner_Run(t *testing.T) {
+	srv, requests := newAgent(t, &fakeRunner{})
+	r := &RemoteRunner{URL: srv.URL, Token: "secret", Phase: "IMPL", Provider: "claude", Model: "opus", ReasoningEffort: "high"}
+
+	var log bytes.Buffer
+	out := filepath.Join(t.TempDir(), "impl-output.txt")
+	require.NoError(t, r.Run(ai.WithOutputLog(context.Background(), &log), "task 1", out))
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "done: task 1", string(data))
+	side, err := os.ReadFile(out + ".stream.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"type":"result"}`, string(side))
+	assert.Equal(t, "working on task 1\n", log.String())
+	assert.Equal(t, []RunRequest{{Phase: "IMPL", Provider: "claude", Model: "opus", ReasoningEffort: "high", Prompt: "task 1"}}, *requests)
+}
+
+func TestRemoteRunner_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		check func(t *testing.T, err error)
+	}{
+		{"rate limit", &ai.RateLimitError{Info: &ratelimit.RateLimitInfo{Detected: true, Parseable: true, ResetHuman: "14:00"}}, func(t *testing.T, err error) {
+			var rl *ai.RateLimitError
+			require.ErrorAs(t, err, &rl)
+			assert.Equal(t, "14:00", rl.Info.ResetHuman)
+		}},
+		{"auth", &ai.AuthError{UnderlyingErr: errors.New("exit status 1")}, func(t *testing.T, err error) {
+			var auth *ai.AuthError
+			require.ErrorAs(t, err, &auth)
+		}},
+		{"other", errors.New("claude command failed: exit status 2"), func(t *testing.T, err error) {
+			assert.EqualError(t, err, "claude command failed: exit status 2")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _ := newAgent(t, &fakeRunner{err: tt.err})
+			r := &RemoteRunner{URL: srv.URL, Token: "secret", Provider: "claude"}
+			out := filepath.Join(t.TempDir(), "out.txt")
+			tt.check(t, r.Run(context.Background(), "p", out))
+			data, _ := os.ReadFile(out)
+			assert.Equal(t, "done: p", string(data), "the output comes back with the error")
+		})
+	}
+}
+
+func TestRemoteRunner_RejectedRequests(t *testing.T) {
+	srv, requests := newAgent(t, &fakeRunner{})
+
+	r := &RemoteRunner{URL: srv.URL, Token: "wrong", Provider: "claude", Delay: time.Millisecond}
+	err := r.Run(context.Background(), "p", filepath.Join(t.TempDir(), "out.txt"))
+	assert.ErrorContains(t, err, "agent rejected the token")
+
+	r = &RemoteRunner{URL: srv.URL, Token: "secret", Provider: "unknown", Delay: time.Millisecond}
+	err = r.Run(context.Background(), "p", filepath.Join(t.TempDir(), "out.txt"))
+	assert.ErrorContains(t, err, `unknown AI "unknown"`)
+	assert.Len(t, *requests, 1, "refused runs are not retried")
+}
+
+func TestRemoteRunner_ReconnectResumesStream(t *testing.T) {
+	var afters []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			fmt.Fprint(w, `{"id":"run1"}`)
+			return
+		}
+		afters = append(afters, r.URL.Query().Get("after"))
+		enc := json.NewEncoder(w)
+		switch len(afters) {
+		case 1:
+			enc.Encode(Event{Seq: 1, Type: EventOutput, Data: "part 1\n"})
+			// the connection drops before the result
+		case 2:
+			http.Error(w, "agent restarting", http.StatusServiceUnavailable)
+		default:
+			enc.Encode(Event{Seq: 1, Type: EventOutput, Data: "part 1\n"})
+			enc.Encode(Event{Seq: 2, Type: EventOutput, Data: "part 2\n"})
+			enc.Encode(Event{Seq: 3, Type: EventResult, Result: &Result{Output: "done"}})
+		}
+	}))
+	defer srv.Close()
+
+	var log bytes.Buffer
+	r := &RemoteRunner{URL: srv.URL, Token: "secret", Delay: time.Millisecond}
+	out := filepath.Join(t.TempDir(), "out.txt")
+	require.NoError(t, r.Run(ai.WithOutputLog(context.Background(), &log), "p", out))
+
+	assert.Equal(t, []string{"0", "1", "1"}, afters)
+	assert.Equal(t, "part 1\npart 2\n", log.String(), "events already received are skipped")
+}
+
+func TestRemoteRunner_GivesUpAfterReconnects(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "down", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	r := &RemoteRunner{URL: srv.URL, Token: "secret", Reconnects: 2, Delay: time.Millisecond}
+	err := r.Run(context.Background(), "p", filepath.Join(t.TempDir(), "out.txt"))
+	assert.ErrorContains(t, err, "502 Bad Gateway")
+	assert.Equal(t, 3, calls)
+}
+
+func TestRemoteRunner_CancelStopsRemoteRun(t *testing.T) {
+	runner := &fakeRunner{block: true, stopped: make(chan struct{})}
+	srv, _ := newAgent(t, runner)
+
+	var log syncBuffer
+	ctx, cancel := context.WithCancel(ai.WithOutputLog(context.Background(), &log))
+	go func() {
+		for log.Len() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+	r := &RemoteRunner{URL: srv.URL, Token: "secret", Provider: "claude"}
+	require.ErrorIs(t, r.Run(ctx, "p", filepath.Join(t.TempDir(), "out.txt")), context.Canceled)
+
+	select {
+	case <-runner.stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the run on the agent was not cancelled")
+	}
+}
+
+func TestServer_PrunesFinishedRuns(t *testing.T) {
+	s := &Server{Token: "secret", Retain: time.Minute, runs: map[string]*run{
+		"old":     {done: true, finished: time.Now().Add(-2 * time.Minute)},
+		"recent":  {done: true, finished: time.Now()},
+		"running": {},
+	}}
+	s.prune()
+	assert.Len(t, s.runs, 2)
+	assert.Nil(t, s.runs["old"])
+}
+
+func TestServer_Health(t *testing.T) {
+	srv, _ := newAgent(t, &fakeRunner{})
+	for token, want := range map[string]int{"secret": http.StatusOK, "": http.StatusUnauthorized} {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/v1/health", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, want, resp.StatusCode)
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Len()
+}
@@ -0,0 +1,268 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/ai"
+)
+
+// Reconnect defaults.
+const (
+	DefaultReconnects     = 5
+	DefaultReconnectDelay = 2 * time.Second
+)
+
+// errUnauthorized is returned when the agent rejects the token.
+var errUnauthorized = &permanentError{errors.New("agent rejected the token")}
+
+// RemoteRunner implements ai.AIRunner by running the call on an agent
+// daemon at URL. The CLI output streams into the phase log as it arrives,
+// and the output file and the runner's side files are written locally
+// when the run ends. Lost connections are retried up to Reconnects times
+// in a row, Delay apart, resuming the event stream where it broke.
+type RemoteRunner struct {
+	URL             string
+	Token           string
+	Phase           string
+	Provider        string
+	Model           string
+	ReasoningEffort string
+	HTTP            *http.Client  // nil means http.DefaultClient
+	Reconnects      int           // 0 means DefaultReconnects
+	Delay           time.Duration // 0 means DefaultReconnectDelay
+}
+
+// SetModel switches the model used by subsequent runs.
+func (r *RemoteRunner) SetModel(model string) {
+	r.Model = model
+}
+
+// Run starts the call on the agent and follows it to the end. Cancelling
+// ctx cancels the run on the agent.
+func (r *RemoteRunner) Run(ctx context.Context, prompt string, outputPath string) error {
+	var id string
+	err := r.retry(ctx, func() (bool, error) {
+		var err error
+		id, err = r.start(ctx, prompt)
+		return false, err
+	})
+	if err != nil {
+		return fmt.Errorf("agent %s: %w", r.URL, err)
+	}
+
+	var res *Result
+	after := 0
+	err = r.retry(ctx, func() (bool, error) {
+		var progressed bool
+		var err error
+		res, progressed, err = r.follow(ctx, id, &after)
+		return progressed, err
+	})
+	if ctx.Err() != nil {
+		r.cancel(id)
+		return ctx.Err()
+	}
+	if err != nil {
+		return fmt.Errorf("agent %s: %w", r.URL, err)
+	}
+
+	if err := os.WriteFile(outputPath, []byte(res.Output), 0644); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	for suffix, data := range res.Files {
+		if strings.ContainsAny(suffix, `/\`) {
+			continue
+		}
+		if err := os.WriteFile(outputPath+suffix, []byte(data), 0644); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+	}
+	return res.err()
+}
+
+// Ping checks that the agent at url is up and accepts token.
+func Ping(ctx context.Context, url, token string) error {
+	resp, err := (&RemoteRunner{URL: url, Token: token}).do(ctx, http.MethodGet, "/v1/health", nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// err rebuilds the error the runner on the agent returned.
+func (res *Result) err() error {
+	if res.Error == "" {
+		return nil
+	}
+	underlying := errors.New(res.Error)
+	switch res.ErrorClass {
+	case ai.ClassRateLimit, ai.ClassOverloaded:
+		return &ai.RateLimitError{Info: res.RateLimit, UnderlyingErr: underlying}
+	case ai.ClassAuth:
+		return &ai.AuthError{UnderlyingErr: underlying}
+	}
+	return underlying
+}
+
+// retry calls attempt until it succeeds, fails with a permanentError, or
+// fails Reconnects times in a row; an attempt that made progress resets
+// the count.
+func (r *RemoteRunner) retry(ctx context.Context, attempt func() (progressed bool, err error)) error {
+	limit := r.Reconnects
+	if limit <= 0 {
+		limit = DefaultReconnects
+	}
+	delay := r.Delay
+	if delay <= 0 {
+		delay = DefaultReconnectDelay
+	}
+	failures := 0
+	for {
+		progressed, err := attempt()
+		var perm *permanentError
+		if err == nil || errors.As(err, &perm) || ctx.Err() != nil {
+			return err
+		}
+		if progressed {
+			failures = 0
+		}
+		failures++
+		if failures > limit {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// start creates the run and returns its ID.
+func (r *RemoteRunner) start(ctx context.Context, prompt string) (string, error) {
+	body, err := json.Marshal(RunRequest{
+		Phase:           r.Phase,
+		Provider:        r.Provider,
+		Model:           r.Model,
+		ReasoningEffort: r.ReasoningEffort,
+		Prompt:          prompt,
+	})
+	if err != nil {
+		return "", err
+	}
+	resp, err := r.do(ctx, http.MethodPost, "/v1/runs", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", fmt.Errorf("start run: %w", err)
+	}
+	return created.ID, nil
+}
+
+// follow streams the events of run id after *after, copying output to the
+// phase log, until the result arrives or the stream breaks. It reports
+// whether any event was received.
+func (r *RemoteRunner) follow(ctx context.Context, id string, after *int) (*Result, bool, error) {
+	resp, err := r.do(ctx, http.MethodGet, fmt.Sprintf("/v1/runs/%s/events?after=%d", id, *after), nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	log := ai.OutputLog(ctx)
+	progressed := false
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for sc.Scan() {
+		var ev Event
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			return nil, progressed, fmt.Errorf("malformed event: %w", err)
+		}
+		if ev.Seq <= *after {
+			continue
+		}
+		*after = ev.Seq
+		progressed = true
+		switch ev.Type {
+		case EventOutput:
+			if log != nil {
+				_, _ = io.WriteString(log, ev.Data)
+			}
+		case EventResult:
+			if ev.Result == nil {
+				return nil, progressed, errors.New("result event without a result")
+			}
+			return ev.Result, progressed, nil
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, progressed, fmt.Errorf("event stream broken: %w", err)
+	}
+	return nil, progressed, errors.New("event stream ended before the result")
+}
+
+// cancel asks the agent to stop run id, without waiting long for it.
+func (r *RemoteRunner) cancel(id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if resp, err := r.do(ctx, http.MethodDelete, "/v1/runs/"+id, nil); err == nil {
+		resp.Body.Close()
+	}
+}
+
+// do sends a request to the agent and returns the response when its status
+// is 2xx.
+func (r *RemoteRunner) do(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(r.URL, "/")+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+r.Token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	client := r.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, errUnauthorized
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusNotFound {
+		// The agent refused the run or forgot it; retrying cannot help
+		return nil, &permanentError{err}
+	}
+	return nil, err
+}
+
+// permanentError is an agent response that retrying cannot change.
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
@@ -0,0 +1,56 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// processActivity returns a counter that grows while any process in the
+// process group of pid uses CPU or does I/O: the sum of their CPU ticks
+// (reaped children included) and bytes read and written. ok is false when
+// the group cannot be inspected.
+func processActivity(pid int) (total uint64, ok bool) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return 0, false
+	}
+	group := strconv.Itoa(pid)
+	for _, e := range entries {
+		if _, err := strconv.Atoi(e.Name()); err != nil {
+			continue
+		}
+		dir := filepath.Join("/proc", e.Name())
+		stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+		if err != nil {
+			continue
+		}
+		// The command name in parentheses may contain spaces; the
+		// fields after it start with the state.
+		end := strings.LastIndexByte(string(stat), ')')
+		if end < 0 {
+			continue
+		}
+		fields := strings.Fields(string(stat[end+1:]))
+		if len(fields) < 15 || fields[2] != group {
+			continue
+		}
+		ok = true
+		// utime, stime, cutime and cstime.
+		for _, f := range fields[11:15] {
+			v, _ := strconv.ParseUint(f, 10, 64)
+			total += v
+		}
+		if io, err := os.ReadFile(filepath.Join(dir, "io")); err == nil {
+			for _, line := range strings.Split(string(io), "\n") {
+				key, value, found := strings.Cut(line, ":")
+				if found && (key == "rchar" || key == "wchar") {
+					v, _ := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+					total += v
+				}
+			}
+		}
+	}
+	return total, ok
+}
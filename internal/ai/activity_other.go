@@ -0,0 +1,9 @@
+//go:build !linux
+
+package ai
+
+// processActivity is not available on this platform: the watchdog cannot
+// tell an idle process from a busy one, so only INACTIVITY_TIMEOUT applies.
+func processActivity(pid int) (total uint64, ok bool) {
+	return 0, false
+}
@@ -0,0 +1,46 @@
+package ai
+
+import (
+	"context"
+	"strings"
+)
+
+// AmazonQRunner implements AIRunner for the Amazon Q Developer CLI
+// (q chat).
+type AmazonQRunner struct {
+	Model             string
+	InactivityTimeout int          // seconds before killing inactive process
+	HangTimeout       int          // seconds without output or process activity before killing a hung process
+	Sandbox           *Sandbox     // optional isolation wrapper; nil runs q directly
+	Permissions       *Permissions // optional allowed tools; q cannot deny commands
+}
+
+// SetModel switches the model used by subsequent runs.
+func (r *AmazonQRunner) SetModel(model string) {
+	r.Model = model
+}
+
+// BuildArgs constructs the argument list for the q CLI command. q chat
+// takes a non-interactive prompt only as an argument, so it is passed
+// that way on every platform. Allowed tools replace --trust-all-tools.
+func (r *AmazonQRunner) BuildArgs(prompt string) []string {
+	args := []string{
+		"chat",
+		"--no-interactive",
+	}
+	if allowed := r.Permissions.allowed("fs_read"); len(allowed) > 0 {
+		args = append(args, "--trust-tools="+strings.Join(allowed, ","))
+	} else {
+		args = append(args, "--trust-all-tools")
+	}
+	if r.Model != "" {
+		args = append(args, "--model", r.Model)
+	}
+	return append(args, prompt)
+}
+
+// Run executes q chat with the given prompt and writes its cleaned text
+// output to outputPath (see runPlainText).
+func (r *AmazonQRunner) Run(ctx context.Context, prompt string, outputPath string) error {
+	return runPlainText(ctx, r.Sandbox, "q", r.BuildArgs(prompt), r.InactivityTimeout, r.HangTimeout, outputPath)
+}
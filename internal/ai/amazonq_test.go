@@ -0,0 +1,63 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCLI puts an executable shell script called name first in PATH.
+func fakeCLI(t *testing.T, name, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dir
+}
+
+func TestAmazonQRunner_BuildArgs(t *testing.T) {
+	r := &AmazonQRunner{Model: "claude-sonnet-4"}
+	assert.Equal(t, []string{"chat", "--no-interactive", "--trust-all-tools", "--model", "claude-sonnet-4", "do the task"}, r.BuildArgs("do the task"))
+
+	r.SetModel("")
+	assert.Equal(t, []string{"chat", "--no-interactive", "--trust-all-tools", "do the task"}, r.BuildArgs("do the task"))
+}
+
+func TestAmazonQRunnerRun_Success(t *testing.T) {
+	dir := fakeCLI(t, "q", `printf '\033[32m> \033[0m\342\240\213 Thinking...\rImplemented T001 (%s %s)\nRALPH_STATUS: success\n' "$1" "$2"`)
+
+	outputPath := filepath.Join(dir, "output.txt")
+	r := &AmazonQRunner{}
+	require.NoError(t, r.Run(context.Background(), "prompt", outputPath))
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, "Implemented T001 (chat --no-interactive)\nRALPH_STATUS: success", string(data))
+	assert.FileExists(t, outputPath+".log")
+}
+
+func TestAmazonQRunnerRun_RateLimitDetected(t *testing.T) {
+	dir := fakeCLI(t, "q", "echo 'ThrottlingException: rate limit exceeded'\nexit 1\n")
+
+	err := (&AmazonQRunner{}).Run(context.Background(), "prompt", filepath.Join(dir, "output.txt"))
+	var rlErr *RateLimitError
+	assert.True(t, errors.As(err, &rlErr), "should return a RateLimitError")
+}
+
+func TestAmazonQRunnerRun_AuthFailureDetected(t *testing.T) {
+	dir := fakeCLI(t, "q", "echo 'error: You are not logged in, please log in with q login' >&2\nexit 1\n")
+
+	err := (&AmazonQRunner{}).Run(context.Background(), "prompt", filepath.Join(dir, "output.txt"))
+	require.Error(t, err)
+	assert.Equal(t, ClassAuth, Classify(err))
+	assert.Contains(t, err.Error(), "q command failed")
+}
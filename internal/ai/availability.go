@@ -1,14 +1,47 @@
 package ai
 
-import "os/exec"
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// providerCommands maps the AI backends whose executable is not named
+// after them to that executable.
+var providerCommands = map[string]string{
+	"amazonq": "q",
+	"copilot": "gh",
+}
+
+// Command returns the executable that runs the given AI backend or tool.
+func Command(tool string) string {
+	if cmd, ok := providerCommands[tool]; ok {
+		return cmd
+	}
+	return tool
+}
+
+// ghCopilotAvailable reports whether `gh copilot` works, as gh only
+// provides it once the Copilot CLI extension is installed. A variable so
+// tests can stub it.
+var ghCopilotAvailable = func() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return exec.CommandContext(ctx, "gh", "copilot", "--help").Run() == nil
+}
 
 // CheckAvailability checks if the given tools are available in PATH.
+// AI backends are looked up by their executable (see Command); copilot
+// also needs the gh Copilot extension.
 // Returns a map of tool name to availability status.
 func CheckAvailability(tools ...string) map[string]bool {
 	result := make(map[string]bool, len(tools))
 	for _, tool := range tools {
-		_, err := exec.LookPath(tool)
+		_, err := exec.LookPath(Command(tool))
 		result[tool] = err == nil
+		if result[tool] && tool == "copilot" {
+			result[tool] = ghCopilotAvailable()
+		}
 	}
 	return result
 }
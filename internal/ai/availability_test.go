@@ -274,3 +274,21 @@ func TestCheckAvailability_CaseInsensitivity(t *testing.T) {
 		}
 	})
 }
+
+func TestCheckAvailability_ProviderCommands(t *testing.T) {
+	assert.Equal(t, "q", Command("amazonq"))
+	assert.Equal(t, "gh", Command("copilot"))
+	assert.Equal(t, "claude", Command("claude"))
+
+	fakeCLI(t, "q", "exit 0\n")
+	fakeCLI(t, "gh", "exit 0\n")
+	orig := ghCopilotAvailable
+	defer func() { ghCopilotAvailable = orig }()
+
+	ghCopilotAvailable = func() bool { return true }
+	assert.Equal(t, map[string]bool{"amazonq": true, "copilot": true}, CheckAvailability("amazonq", "copilot"))
+
+	// gh without the Copilot extension cannot run copilot.
+	ghCopilotAvailable = func() bool { return false }
+	assert.False(t, CheckAvailability("copilot")["copilot"])
+}
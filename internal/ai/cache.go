@@ -0,0 +1,134 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCacheDir returns the user-level response cache,
+// ~/.cache/ralph-loop/responses (or the platform's equivalent). It lives
+// outside the project's state directory so --clean keeps it.
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "ralph-loop", "responses")
+}
+
+// CachedRunner answers a call from an on-disk cache when the same prompt
+// was sent to the same provider, model and phase before, against the same
+// files, and runs Inner otherwise. Only the extracted output is cached, not
+// the CLI's event stream, so a cache hit costs no tokens in the reports.
+// It suits phases that only read the workspace; one whose agent edits
+// files must not be cached.
+type CachedRunner struct {
+	Inner    AIRunner
+	Dir      string
+	TTL      time.Duration // entries older than this are ignored; 0 keeps them forever
+	Provider string
+	Model    string
+	Phase    string
+	// Fingerprint returns the state of the files the prompt refers to,
+	// e.g. a snapshot of the working tree. When it fails the call is run
+	// and not cached.
+	Fingerprint func() (string, error)
+	// OnHit, if set, is called when a call is answered from the cache
+	// with the age of the cached response.
+	OnHit func(phase string, age time.Duration)
+}
+
+// Run writes the cached response to outputPath, or runs Inner and caches
+// its output when the call succeeded with some. Failing to write the cache
+// does not fail the call.
+func (r *CachedRunner) Run(ctx context.Context, prompt string, outputPath string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	path, ok := r.entryPath(prompt)
+	if !ok {
+		return r.Inner.Run(ctx, prompt, outputPath)
+	}
+
+	if info, err := os.Stat(path); err == nil {
+		age := time.Since(info.ModTime())
+		if r.TTL > 0 && age > r.TTL {
+			_ = os.Remove(path)
+		} else if data, err := os.ReadFile(path); err == nil {
+			if err := os.WriteFile(outputPath, data, 0644); err != nil {
+				return err
+			}
+			if r.OnHit != nil {
+				r.OnHit(r.Phase, age)
+			}
+			return nil
+		}
+	}
+
+	if err := r.Inner.Run(ctx, prompt, outputPath); err != nil {
+		return err
+	}
+	if data, err := os.ReadFile(outputPath); err == nil && len(data) > 0 {
+		_ = writeCacheEntry(path, data)
+	}
+	return nil
+}
+
+// SetModel switches the model of the key and forwards the switch to the
+// inner runner, if it supports it.
+func (r *CachedRunner) SetModel(model string) {
+	r.Model = model
+	if ms, ok := r.Inner.(ModelSetter); ok {
+		ms.SetModel(model)
+	}
+}
+
+// entryPath returns the cache file of prompt, keyed by a hash of the
+// provider, model, phase, prompt and file fingerprint. ok is false when
+// the fingerprint is unavailable.
+func (r *CachedRunner) entryPath(prompt string) (path string, ok bool) {
+	if r.Dir == "" || r.Fingerprint == nil {
+		return "", false
+	}
+	fingerprint, err := r.Fingerprint()
+	if err != nil {
+		return "", false
+	}
+	h := sha256.New()
+	for _, part := range []string{r.Provider, r.Model, r.Phase, fingerprint, prompt} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	key := hex.EncodeToString(h.Sum(nil))
+	return filepath.Join(r.Dir, key[:2], key+".txt"), true
+}
+
+// writeCacheEntry writes data to path through a temporary file, so a
+// concurrent reader never sees a partial entry.
+func writeCacheEntry(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".entry-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ ModelSetter = (*CachedRunner)(nil)
+
+// cachedTestRunner returns a CachedRunner over an inner runner answering
+// "answer <prompt>", and the number of inner calls made so far.
+func cachedTestRunner(t *testing.T, fingerprint *string) (*CachedRunner, *int) {
+	t.Helper()
+	calls := 0
+	inner := &mockRunner{runFunc: func(ctx context.Context, prompt, outputPath string) error {
+		calls++
+		require.NoError(t, os.WriteFile(outputPath+".stream.json", []byte(`{"type":"result"}`), 0644))
+		return os.WriteFile(outputPath, []byte("answer "+prompt), 0644)
+	}}
+	return &CachedRunner{
+		Inner:       inner,
+		Dir:         filepath.Join(t.TempDir(), "cache"),
+		Provider:    "claude",
+		Model:       "opus",
+		Phase:       "TASKS_VAL",
+		Fingerprint: func() (string, error) { return *fingerprint, nil },
+	}, &calls
+}
+
+func TestCachedRunner_ReusesIdenticalCalls(t *testing.T) {
+	fingerprint := "tree-1"
+	r, calls := cachedTestRunner(t, &fingerprint)
+	var hits []string
+	r.OnHit = func(phase string, age time.Duration) { hits = append(hits, phase) }
+
+	out := filepath.Join(t.TempDir(), "output.txt")
+	require.NoError(t, r.Run(context.Background(), "check", out))
+	assert.Equal(t, 1, *calls)
+
+	second := filepath.Join(t.TempDir(), "output.txt")
+	require.NoError(t, r.Run(context.Background(), "check", second))
+	assert.Equal(t, 1, *calls, "an identical call is answered from the cache")
+	assert.Equal(t, "answer check", readFile(t, second))
+	assert.NoFileExists(t, second+".stream.json", "a cache hit reports no token usage")
+	assert.Equal(t, []string{"TASKS_VAL"}, hits)
+
+	// A different prompt, model or set of files misses.
+	require.NoError(t, r.Run(context.Background(), "other", out))
+	r.SetModel("sonnet")
+	require.NoError(t, r.Run(context.Background(), "check", out))
+	fingerprint = "tree-2"
+	require.NoError(t, r.Run(context.Background(), "check", out))
+	assert.Equal(t, 4, *calls)
+}
+
+func TestCachedRunner_ExpiredEntry(t *testing.T) {
+	fingerprint := "tree"
+	r, calls := cachedTestRunner(t, &fingerprint)
+	r.TTL = time.Hour
+	out := filepath.Join(t.TempDir(), "output.txt")
+	require.NoError(t, r.Run(context.Background(), "check", out))
+
+	path, ok := r.entryPath("check")
+	require.True(t, ok)
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(path, old, old))
+
+	require.NoError(t, r.Run(context.Background(), "check", out))
+	assert.Equal(t, 2, *calls, "an entry older than the TTL is not reused")
+	require.NoError(t, r.Run(context.Background(), "check", out))
+	assert.Equal(t, 2, *calls, "the fresh response replaced it")
+}
+
+func TestCachedRunner_SkipsFailuresAndMissingFingerprint(t *testing.T) {
+	fingerprint := "tree"
+	r, calls := cachedTestRunner(t, &fingerprint)
+	out := filepath.Join(t.TempDir(), "output.txt")
+
+	failing := r.Inner
+	r.Inner = &mockRunner{runFunc: func(ctx context.Context, prompt, outputPath string) error {
+		return errors.New("exit status 1")
+	}}
+	assert.EqualError(t, r.Run(context.Background(), "check", out), "exit status 1")
+	r.Inner = failing
+	require.NoError(t, r.Run(context.Background(), "check", out))
+	assert.Equal(t, 1, *calls, "a failed call is not cached")
+
+	r.Fingerprint = func() (string, error) { return "", errors.New("not a git repository") }
+	require.NoError(t, r.Run(context.Background(), "check", out))
+	require.NoError(t, r.Run(context.Background(), "check", out))
+	assert.Equal(t, 3, *calls, "without a fingerprint every call runs")
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
+	"strings"
 
 	"github.com/CodexForgeBR/cli-tools/internal/parser"
 	"github.com/CodexForgeBR/cli-tools/internal/ratelimit"
@@ -14,21 +14,43 @@ import (
 type ClaudeRunner struct {
 	Model             string
 	MaxTurns          int
-	Verbose           bool // Controls Go-level logging, not CLI flag
-	InactivityTimeout int  // seconds before killing inactive process
+	Verbose           bool         // Controls Go-level logging, not CLI flag
+	InactivityTimeout int          // seconds before killing inactive process
+	HangTimeout       int          // seconds without output or process activity before killing a hung process
+	ReasoningEffort   string       // minimal, low, medium or high; empty uses the CLI default
+	Sandbox           *Sandbox     // optional isolation wrapper; nil runs claude directly
+	Permissions       *Permissions // optional tool restrictions; nil allows everything
+}
+
+// SetModel switches the model used by subsequent runs.
+func (r *ClaudeRunner) SetModel(model string) {
+	r.Model = model
 }
 
 // BuildArgs constructs the argument list for the claude CLI command.
 // Always includes --verbose and --output-format stream-json (required for monitoring).
+// Permissions are skipped unless an allowed tools list must be enforced;
+// read-only permissions allow Read, Grep and Glob alone.
 func (r *ClaudeRunner) BuildArgs(prompt string) []string {
 	args := []string{
 		"--print",
 		"--verbose",
 		"--output-format", "stream-json",
-		"--dangerously-skip-permissions",
+	}
+	if allowed := r.Permissions.allowed("Read", "Grep", "Glob"); len(allowed) > 0 {
+		args = append(args, "--allowedTools", strings.Join(allowed, ","))
+	} else {
+		args = append(args, "--dangerously-skip-permissions")
+	}
+	if denied := r.Permissions.denied("Bash(%s:*)"); len(denied) > 0 {
+		args = append(args, "--disallowedTools", strings.Join(denied, ","))
+	}
+	args = append(args,
 		"--model", r.Model,
 		"--max-turns", fmt.Sprintf("%d", r.MaxTurns),
-		"--", prompt,
+	)
+	if !promptOnStdin {
+		args = append(args, "--", prompt)
 	}
 	return args
 }
@@ -36,7 +58,8 @@ func (r *ClaudeRunner) BuildArgs(prompt string) []string {
 // Run executes the claude CLI with the given prompt and writes output to outputPath.
 // Uses cmd.Start() + MonitorProcess + cmd.Wait() for process lifecycle management.
 // Parses stream-json output to extract text content.
-// Checks for rate limits after execution and returns a RateLimitError if detected.
+// Checks for rate limits after execution and returns a RateLimitError if
+// detected, or an AuthError if the CLI failed for lack of credentials.
 func (r *ClaudeRunner) Run(ctx context.Context, prompt string, outputPath string) error {
 	args := r.BuildArgs(prompt)
 
@@ -44,7 +67,10 @@ func (r *ClaudeRunner) Run(ctx context.Context, prompt string, outputPath string
 	monCtx, monCancel := context.WithCancel(ctx)
 	defer monCancel()
 
-	cmd := exec.CommandContext(monCtx, "claude", args...)
+	cmd := newCommand(monCtx, r.Sandbox, "claude", args, claudeEnv(r.ReasoningEffort))
+	if promptOnStdin {
+		cmd.Stdin = strings.NewReader(prompt)
+	}
 
 	// Raw stream-json output file
 	rawPath := outputPath + ".stream.json"
@@ -53,9 +79,10 @@ func (r *ClaudeRunner) Run(ctx context.Context, prompt string, outputPath string
 		return fmt.Errorf("create output file: %w", err)
 	}
 
-	// Merge stdout and stderr into the raw file
-	cmd.Stdout = rawFile
-	cmd.Stderr = rawFile
+	// Merge stdout and stderr into the raw file, and the phase log if any
+	out := teeOutput(ctx, rawFile)
+	cmd.Stdout = out
+	cmd.Stderr = out
 
 	// Start the process (non-blocking)
 	if err := cmd.Start(); err != nil {
@@ -64,13 +91,18 @@ func (r *ClaudeRunner) Run(ctx context.Context, prompt string, outputPath string
 	}
 
 	// Start monitor in a goroutine
+	var hang hangWatch
 	go MonitorProcess(monCtx, monCancel, MonitorConfig{
 		InactivityTimeout: r.InactivityTimeout,
 		OutputPath:        rawPath,
+		HangTimeout:       r.HangTimeout,
+		PID:               cmd.Process.Pid,
+		SnapshotPath:      outputPath + ".hang.json",
+		OnHang:            hang.record,
 	})
 
 	// Wait for process to complete (or be killed by monitor)
-	runErr := cmd.Wait()
+	runErr := hang.err(cmd.Wait())
 	rawFile.Close()
 
 	// Parse stream-json output to extract text
@@ -97,6 +129,9 @@ func (r *ClaudeRunner) Run(ctx context.Context, prompt string, outputPath string
 	}
 
 	if runErr != nil {
+		if detectAuthFailure(outputPath, rawPath) {
+			return &AuthError{UnderlyingErr: fmt.Errorf("claude command failed: %w", runErr)}
+		}
 		return fmt.Errorf("claude command failed: %w", runErr)
 	}
 
@@ -5,7 +5,7 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
+	"strings"
 
 	"github.com/CodexForgeBR/cli-tools/internal/parser"
 	"github.com/CodexForgeBR/cli-tools/internal/ratelimit"
@@ -15,22 +15,46 @@ import (
 type CodexRunner struct {
 	Model             string
 	Verbose           bool
-	InactivityTimeout int // seconds before killing inactive process
+	InactivityTimeout int          // seconds before killing inactive process
+	HangTimeout       int          // seconds without output or process activity before killing a hung process
+	ReasoningEffort   string       // minimal, low, medium or high; empty uses the CLI default
+	Sandbox           *Sandbox     // optional isolation wrapper; nil runs codex directly
+	Permissions       *Permissions // when restricted, codex runs in its workspace-write or read-only sandbox
+}
+
+// SetModel switches the model used by subsequent runs.
+func (r *CodexRunner) SetModel(model string) {
+	r.Model = model
 }
 
 // BuildArgs constructs the argument list for the codex CLI command.
 // outputPath is the file where codex writes the extracted last message via --output-last-message.
+// Restricted permissions replace the sandbox bypass with codex's workspace-write
+// sandbox, and read-only ones with its read-only sandbox.
 func (r *CodexRunner) BuildArgs(prompt string, outputPath string) []string {
 	args := []string{
 		"exec",
 		"--json",
 		"--output-last-message", outputPath,
-		"--dangerously-bypass-approvals-and-sandbox",
+	}
+	switch {
+	case r.Permissions.readOnly():
+		args = append(args, "--sandbox", "read-only")
+	case r.Permissions.Restricted():
+		args = append(args, "--sandbox", "workspace-write")
+	default:
+		args = append(args, "--dangerously-bypass-approvals-and-sandbox")
 	}
 	if r.Model != "" {
 		args = append(args, "--model", r.Model)
 	}
-	args = append(args, prompt)
+	args = append(args, codexEffortArgs(r.ReasoningEffort)...)
+	if promptOnStdin {
+		// "-" makes codex exec read the prompt from stdin.
+		args = append(args, "-")
+	} else {
+		args = append(args, prompt)
+	}
 	return args
 }
 
@@ -38,7 +62,8 @@ func (r *CodexRunner) BuildArgs(prompt string, outputPath string) []string {
 // Uses cmd.Start() + MonitorProcess + cmd.Wait() for process lifecycle management.
 // Codex writes extracted text to outputPath via --output-last-message; raw JSONL goes to a separate file.
 // Falls back to parsing JSONL if --output-last-message produces empty output.
-// Checks for rate limits after execution and returns a RateLimitError if detected.
+// Checks for rate limits after execution and returns a RateLimitError if
+// detected, or an AuthError if the CLI failed for lack of credentials.
 func (r *CodexRunner) Run(ctx context.Context, prompt string, outputPath string) error {
 	args := r.BuildArgs(prompt, outputPath)
 
@@ -46,7 +71,10 @@ func (r *CodexRunner) Run(ctx context.Context, prompt string, outputPath string)
 	monCtx, monCancel := context.WithCancel(ctx)
 	defer monCancel()
 
-	cmd := exec.CommandContext(monCtx, "codex", args...)
+	cmd := newCommand(monCtx, r.Sandbox, "codex", args, nil)
+	if promptOnStdin {
+		cmd.Stdin = strings.NewReader(prompt)
+	}
 
 	// Raw JSONL output file (separate from the extracted text output)
 	rawPath := outputPath + ".jsonl"
@@ -55,9 +83,10 @@ func (r *CodexRunner) Run(ctx context.Context, prompt string, outputPath string)
 		return fmt.Errorf("create output file: %w", err)
 	}
 
-	// Merge stdout and stderr into the raw JSONL file
-	cmd.Stdout = rawFile
-	cmd.Stderr = rawFile
+	// Merge stdout and stderr into the raw JSONL file, and the phase log if any
+	out := teeOutput(ctx, rawFile)
+	cmd.Stdout = out
+	cmd.Stderr = out
 
 	// Start the process (non-blocking)
 	if err := cmd.Start(); err != nil {
@@ -66,13 +95,18 @@ func (r *CodexRunner) Run(ctx context.Context, prompt string, outputPath string)
 	}
 
 	// Start monitor in a goroutine
+	var hang hangWatch
 	go MonitorProcess(monCtx, monCancel, MonitorConfig{
 		InactivityTimeout: r.InactivityTimeout,
 		OutputPath:        rawPath,
+		HangTimeout:       r.HangTimeout,
+		PID:               cmd.Process.Pid,
+		SnapshotPath:      outputPath + ".hang.json",
+		OnHang:            hang.record,
 	})
 
 	// Wait for process to complete (or be killed by monitor)
-	runErr := cmd.Wait()
+	runErr := hang.err(cmd.Wait())
 	rawFile.Close()
 
 	// Check if outputPath has content from --output-last-message
@@ -103,6 +137,9 @@ func (r *CodexRunner) Run(ctx context.Context, prompt string, outputPath string)
 	}
 
 	if runErr != nil {
+		if detectAuthFailure(outputPath, rawPath) {
+			return &AuthError{UnderlyingErr: fmt.Errorf("codex command failed: %w", runErr)}
+		}
 		return fmt.Errorf("codex command failed: %w", runErr)
 	}
 
@@ -301,6 +301,34 @@ exit 1
 	assert.True(t, errors.As(err, &rlErr), "should return a RateLimitError")
 }
 
+func TestCodexRunnerRun_AuthFailureDetected(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows")
+	}
+
+	tmpDir := t.TempDir()
+
+	// Create a fake "codex" script that fails for lack of credentials
+	fakeScript := filepath.Join(tmpDir, "codex")
+	scriptContent := `#!/bin/sh
+echo 'Error: Not logged in. Run codex login' >&2
+exit 1
+`
+	err := os.WriteFile(fakeScript, []byte(scriptContent), 0755)
+	require.NoError(t, err)
+
+	origPath := os.Getenv("PATH")
+	os.Setenv("PATH", tmpDir+":"+origPath)
+	defer os.Setenv("PATH", origPath)
+
+	outputPath := filepath.Join(tmpDir, "output.json")
+	r := &CodexRunner{Model: "test-model"}
+	err = r.Run(context.Background(), "prompt", outputPath)
+	require.Error(t, err)
+	assert.Equal(t, ClassAuth, Classify(err))
+	assert.Contains(t, err.Error(), "codex command failed")
+}
+
 func TestCodexRunnerRun_Success(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("skipping on windows")
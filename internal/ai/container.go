@@ -0,0 +1,118 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+)
+
+// ContainerLabel labels the containers ralph-loop starts, so the stopped
+// ones can be pruned.
+const ContainerLabel = "ralph-loop"
+
+// ContainerRuntimes are the supported container runtimes, in the order
+// they are looked for.
+var ContainerRuntimes = []string{"docker", "podman"}
+
+// containerSeq numbers the containers this process starts, keeping their
+// names unique across runners.
+var containerSeq atomic.Int64
+
+// Container runs AI CLIs inside a Docker or Podman container of Image, for
+// a reproducible toolchain isolated from the host. The project directory
+// is bind-mounted at the same path and is the working directory, so paths
+// in prompts and output mean the same inside and out. Every call runs in
+// a fresh container that is removed when the CLI exits or is killed.
+type Container struct {
+	Runtime string // "docker" or "podman"
+	Image   string
+	Workdir string   // project directory; defaults to the current directory
+	Env     []string // names of host variables passed into the container
+}
+
+// DetectRuntime returns the first of ContainerRuntimes found in PATH, or
+// "" when there is none.
+func DetectRuntime() string {
+	for _, rt := range ContainerRuntimes {
+		if _, err := exec.LookPath(rt); err == nil {
+			return rt
+		}
+	}
+	return ""
+}
+
+// Args returns the runtime arguments that run name with args in a
+// container called containerName. extraEnv names runner-specific
+// variables passed through in addition to c.Env.
+func (c *Container) Args(containerName, name string, args []string, extraEnv []string) []string {
+	workdir := (&Sandbox{Workdir: c.Workdir}).workdir()
+	argv := []string{"run", "--rm", "-i",
+		"--name", containerName,
+		"--label", ContainerLabel,
+		"-v", workdir + ":" + workdir,
+		"-w", workdir,
+	}
+	for _, v := range append(append([]string{}, c.Env...), extraEnv...) {
+		argv = append(argv, "--env", v)
+	}
+	argv = append(argv, c.Image, name)
+	return append(argv, args...)
+}
+
+// command builds the exec.Cmd running name with args in a new container.
+// Cancelling ctx kills the runtime client and removes the container,
+// which would otherwise outlive its client.
+func (c *Container) command(ctx context.Context, name string, args []string, extraEnv []string) *exec.Cmd {
+	containerName := fmt.Sprintf("%s-%d-%d", ContainerLabel, os.Getpid(), containerSeq.Add(1))
+	cmd := exec.CommandContext(ctx, c.Runtime, c.Args(containerName, name, args, extraEnv)...)
+	cmd.Dir = (&Sandbox{Workdir: c.Workdir}).workdir()
+	setProcessTree(cmd)
+	kill := cmd.Cancel
+	cmd.Cancel = func() error {
+		c.remove(containerName)
+		return kill()
+	}
+	return cmd
+}
+
+// remove force-removes the container called containerName, if it exists.
+func (c *Container) remove(containerName string) {
+	exec.Command(c.Runtime, "rm", "-f", containerName).Run()
+}
+
+// Pull makes sure Image is present according to policy: "missing" pulls
+// it only when it is not present locally, "always" every time, and
+// "never" never.
+func (c *Container) Pull(ctx context.Context, policy string) error {
+	switch policy {
+	case "never":
+		return nil
+	case "missing", "":
+		if exec.CommandContext(ctx, c.Runtime, "image", "inspect", c.Image).Run() == nil {
+			return nil
+		}
+	}
+	if out, err := exec.CommandContext(ctx, c.Runtime, "pull", c.Image).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s pull %s: %w: %s", c.Runtime, c.Image, err, lastLine(string(out)))
+	}
+	return nil
+}
+
+// Prune removes the stopped containers ralph-loop started, such as those
+// left behind when it was killed.
+func (c *Container) Prune(ctx context.Context) error {
+	out, err := exec.CommandContext(ctx, c.Runtime, "container", "prune", "-f", "--filter", "label="+ContainerLabel).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s container prune: %w: %s", c.Runtime, err, lastLine(string(out)))
+	}
+	return nil
+}
+
+// lastLine returns the last non-empty line of s.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,129 @@
+package ai
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRuntime installs a fake container runtime called name that logs
+// every call and then runs script, and returns the log path.
+func fakeRuntime(t *testing.T, name, script string) string {
+	t.Helper()
+	log := filepath.Join(t.TempDir(), "calls.log")
+	fakeCLI(t, name, `echo "$*" >> `+log+"\n"+script)
+	return log
+}
+
+func readCalls(t *testing.T, log string) []string {
+	t.Helper()
+	data, err := os.ReadFile(log)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestContainer_Args(t *testing.T) {
+	c := &Container{Runtime: "podman", Image: "ghcr.io/acme/agent:1", Workdir: "/src/project", Env: []string{"ANTHROPIC_API_KEY"}}
+	assert.Equal(t, []string{
+		"run", "--rm", "-i",
+		"--name", "ralph-loop-1-2",
+		"--label", ContainerLabel,
+		"-v", "/src/project:/src/project", "-w", "/src/project",
+		"--env", "ANTHROPIC_API_KEY", "--env", "MAX_THINKING_TOKENS",
+		"ghcr.io/acme/agent:1",
+		"claude", "--print", "prompt",
+	}, c.Args("ralph-loop-1-2", "claude", []string{"--print", "prompt"}, []string{"MAX_THINKING_TOKENS"}))
+}
+
+func TestClaudeRunnerRun_InsideContainer(t *testing.T) {
+	log := fakeRuntime(t, "docker", `echo '{"type":"result","result":"done"}'`+"\n")
+	workdir := t.TempDir()
+
+	c := &Container{Runtime: "docker", Image: "agent", Workdir: workdir}
+	r := &ClaudeRunner{Model: "test-model", MaxTurns: 1, Sandbox: &Sandbox{Container: c}}
+	require.NoError(t, r.Run(context.Background(), "prompt", filepath.Join(t.TempDir(), "output.txt")))
+	require.NoError(t, r.Run(context.Background(), "prompt", filepath.Join(t.TempDir(), "output.txt")))
+
+	calls := readCalls(t, log)
+	require.Len(t, calls, 2)
+	assert.True(t, strings.HasPrefix(calls[0], "run --rm -i --name ralph-loop-"), calls[0])
+	assert.Contains(t, calls[0], "-v "+workdir+":"+workdir+" -w "+workdir+" agent claude --print")
+	assert.NotEqual(t, strings.Fields(calls[0])[4], strings.Fields(calls[1])[4], "every call gets its own container")
+}
+
+func TestContainer_CancelRemovesContainer(t *testing.T) {
+	log := fakeRuntime(t, "docker", `[ "$1" = run ] && sleep 30`+"\n")
+	c := &Container{Runtime: "docker", Image: "agent", Workdir: t.TempDir()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	cmd := newCommand(ctx, &Sandbox{Container: c}, "codex", []string{"exec"}, nil)
+	start := time.Now()
+	require.Error(t, cmd.Run())
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	calls := readCalls(t, log)
+	require.Len(t, calls, 2)
+	name := strings.Fields(calls[0])[4]
+	assert.Equal(t, "rm -f "+name, calls[1])
+}
+
+func TestContainer_Pull(t *testing.T) {
+	// The image is present when $PRESENT is set.
+	log := fakeRuntime(t, "docker", `[ "$1" = image ] && [ -z "$PRESENT" ] && exit 1`+"\nexit 0\n")
+	c := &Container{Runtime: "docker", Image: "agent:1"}
+
+	t.Setenv("PRESENT", "1")
+	require.NoError(t, c.Pull(context.Background(), "missing"))
+	require.NoError(t, c.Pull(context.Background(), "never"))
+	require.NoError(t, c.Pull(context.Background(), "always"))
+	t.Setenv("PRESENT", "")
+	require.NoError(t, c.Pull(context.Background(), "missing"))
+
+	assert.Equal(t, []string{
+		"image inspect agent:1",
+		"pull agent:1",
+		"image inspect agent:1",
+		"pull agent:1",
+	}, readCalls(t, log))
+}
+
+func TestContainer_PullAndPruneErrors(t *testing.T) {
+	fakeRuntime(t, "podman", "echo 'Trying to pull...'\necho 'Error: manifest unknown' >&2\nexit 125\n")
+	c := &Container{Runtime: "podman", Image: "agent:nope"}
+
+	err := c.Pull(context.Background(), "always")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "podman pull agent:nope")
+	assert.Contains(t, err.Error(), "Error: manifest unknown")
+
+	assert.ErrorContains(t, c.Prune(context.Background()), "podman container prune")
+}
+
+func TestContainer_Prune(t *testing.T) {
+	log := fakeRuntime(t, "docker", "")
+	require.NoError(t, (&Container{Runtime: "docker"}).Prune(context.Background()))
+	assert.Equal(t, []string{"container prune -f --filter label=" + ContainerLabel}, readCalls(t, log))
+}
+
+func TestDetectRuntime(t *testing.T) {
+	dir := fakeCLI(t, "podman", "")
+	t.Setenv("PATH", dir)
+	assert.Equal(t, "podman", DetectRuntime())
+
+	t.Setenv("PATH", t.TempDir())
+	assert.Equal(t, "", DetectRuntime())
+}
+
+func TestSandbox_ProgramOfContainer(t *testing.T) {
+	assert.Equal(t, "podman", (&Sandbox{Command: "firejail", Container: &Container{Runtime: "podman"}}).Program())
+}
@@ -0,0 +1,50 @@
+package ai
+
+import "context"
+
+// CopilotRunner implements AIRunner for the GitHub Copilot CLI, run as
+// gh copilot.
+type CopilotRunner struct {
+	Model             string
+	InactivityTimeout int          // seconds before killing inactive process
+	HangTimeout       int          // seconds without output or process activity before killing a hung process
+	Sandbox           *Sandbox     // optional isolation wrapper; nil runs gh directly
+	Permissions       *Permissions // optional tool restrictions; nil allows everything
+}
+
+// SetModel switches the model used by subsequent runs.
+func (r *CopilotRunner) SetModel(model string) {
+	r.Model = model
+}
+
+// BuildArgs constructs the argument list for the gh CLI command. Copilot
+// takes a non-interactive prompt only through -p, so it is passed that
+// way on every platform. Allowed tools replace --allow-all-tools.
+func (r *CopilotRunner) BuildArgs(prompt string) []string {
+	args := []string{
+		"copilot",
+		"-p", prompt,
+	}
+	if allowed := r.Permissions.allowed(); len(allowed) > 0 {
+		for _, tool := range allowed {
+			args = append(args, "--allow-tool", tool)
+		}
+	} else if r.Permissions.readOnly() {
+		args = append(args, "--deny-tool", "write", "--deny-tool", "shell")
+	} else {
+		args = append(args, "--allow-all-tools")
+	}
+	for _, rule := range r.Permissions.denied("shell(%s)") {
+		args = append(args, "--deny-tool", rule)
+	}
+	if r.Model != "" {
+		args = append(args, "--model", r.Model)
+	}
+	return args
+}
+
+// Run executes gh copilot with the given prompt and writes its cleaned
+// text output to outputPath (see runPlainText).
+func (r *CopilotRunner) Run(ctx context.Context, prompt string, outputPath string) error {
+	return runPlainText(ctx, r.Sandbox, "gh", r.BuildArgs(prompt), r.InactivityTimeout, r.HangTimeout, outputPath)
+}
@@ -0,0 +1,46 @@
+package ai
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopilotRunner_BuildArgs(t *testing.T) {
+	r := &CopilotRunner{Model: "gpt-5"}
+	assert.Equal(t, []string{"copilot", "-p", "do the task", "--allow-all-tools", "--model", "gpt-5"}, r.BuildArgs("do the task"))
+
+	r.SetModel("")
+	assert.Equal(t, []string{"copilot", "-p", "do the task", "--allow-all-tools"}, r.BuildArgs("do the task"))
+}
+
+func TestCopilotRunnerRun_Success(t *testing.T) {
+	dir := fakeCLI(t, "gh", `echo "$1 $2 $3"; echo 'RALPH_STATUS: success'`)
+
+	outputPath := filepath.Join(dir, "output.txt")
+	require.NoError(t, (&CopilotRunner{}).Run(context.Background(), "the prompt", outputPath))
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, "copilot -p the prompt\nRALPH_STATUS: success", string(data))
+}
+
+func TestCopilotRunnerRun_CommandFails(t *testing.T) {
+	dir := fakeCLI(t, "gh", "echo 'unknown command \"copilot\" for \"gh\"' >&2\nexit 1\n")
+
+	err := (&CopilotRunner{}).Run(context.Background(), "prompt", filepath.Join(dir, "output.txt"))
+	require.Error(t, err)
+	assert.Equal(t, ClassTransient, Classify(err))
+	assert.Contains(t, err.Error(), "gh command failed")
+}
+
+func TestCopilotRunnerRun_AuthFailureDetected(t *testing.T) {
+	dir := fakeCLI(t, "gh", "echo 'To get started with GitHub CLI, please run:  gh auth login' >&2\nexit 4\n")
+
+	err := (&CopilotRunner{}).Run(context.Background(), "prompt", filepath.Join(dir, "output.txt"))
+	assert.Equal(t, ClassAuth, Classify(err))
+}
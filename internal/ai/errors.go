@@ -0,0 +1,85 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"os"
+	"regexp"
+)
+
+// ErrorClass groups AI invocation failures by how they should be retried.
+type ErrorClass string
+
+const (
+	// ClassTransient covers crashes, timeouts and other failures worth
+	// retrying with backoff.
+	ClassTransient ErrorClass = "transient"
+	// ClassRateLimit is a usage limit; retries wait for the reset time.
+	ClassRateLimit ErrorClass = "rate_limit"
+	// ClassOverloaded is a temporary API capacity error; retries back off
+	// like transient errors.
+	ClassOverloaded ErrorClass = "overloaded"
+	// ClassAuth is a missing or expired credential; retrying cannot help
+	// until the user logs in again.
+	ClassAuth ErrorClass = "auth"
+	// ClassCanceled means the run was interrupted and must not be retried.
+	ClassCanceled ErrorClass = "canceled"
+	// ClassHung is a CLI the watchdog killed for showing no output and no
+	// process activity; it is retried like a transient failure.
+	ClassHung ErrorClass = "hung"
+)
+
+// AuthError is returned when an AI CLI fails because it is not
+// authenticated.
+type AuthError struct {
+	UnderlyingErr error
+}
+
+func (e *AuthError) Error() string {
+	if e.UnderlyingErr != nil {
+		return "authentication failed: " + e.UnderlyingErr.Error()
+	}
+	return "authentication failed"
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.UnderlyingErr
+}
+
+// Classify returns the class of an error returned by an AIRunner.
+func Classify(err error) ErrorClass {
+	var rateLimitErr *RateLimitError
+	var authErr *AuthError
+	var hangErr *HangError
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return ClassCanceled
+	case errors.As(err, &rateLimitErr):
+		if rateLimitErr.Overloaded() {
+			return ClassOverloaded
+		}
+		return ClassRateLimit
+	case errors.As(err, &authErr):
+		return ClassAuth
+	case errors.As(err, &hangErr):
+		return ClassHung
+	}
+	return ClassTransient
+}
+
+// authPattern matches the messages the claude, codex, q and gh CLIs print when
+// their credentials are missing, invalid or expired.
+var authPattern = regexp.MustCompile(`(?i)(invalid api key|please run /login|authentication_error|not logged in|oauth token (has )?expired|401 unauthorized|codex login|\bq login|gh auth login)`)
+
+// detectAuthFailure reports whether any of the output files contains an
+// authentication failure message. It is only consulted when the CLI exited
+// with an error, so the AI discussing authentication does not trip it.
+func detectAuthFailure(paths ...string) bool {
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err == nil && authPattern.Match(data) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/ratelimit"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{"plain failure", errors.New("exit status 1"), ClassTransient},
+		{"rate limit", &RateLimitError{}, ClassRateLimit},
+		{"wrapped rate limit", fmt.Errorf("attempt: %w", &RateLimitError{}), ClassRateLimit},
+		{"overloaded", &RateLimitError{Info: &ratelimit.RateLimitInfo{Detected: true, Overloaded: true}}, ClassOverloaded},
+		{"auth", &AuthError{UnderlyingErr: errors.New("exit status 1")}, ClassAuth},
+		{"canceled", fmt.Errorf("claude command failed: %w", context.Canceled), ClassCanceled},
+		{"deadline", context.DeadlineExceeded, ClassCanceled},
+		{"hung", fmt.Errorf("claude command failed: %w", &HangError{Err: errors.New("signal: killed")}), ClassHung},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Classify(tt.err))
+		})
+	}
+}
+
+func TestAuthError(t *testing.T) {
+	inner := errors.New("codex command failed: exit status 1")
+	err := &AuthError{UnderlyingErr: inner}
+	assert.Equal(t, "authentication failed: codex command failed: exit status 1", err.Error())
+	assert.ErrorIs(t, err, inner)
+	assert.Equal(t, "authentication failed", (&AuthError{}).Error())
+}
+
+func TestDetectAuthFailure(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+		return p
+	}
+	out := write("out.txt", "")
+	raw := write("raw.json", `{"type":"result","is_error":true,"result":"Invalid API key · Please run /login"}`)
+	other := write("other.txt", "panic: something else")
+
+	assert.True(t, detectAuthFailure(out, raw))
+	assert.False(t, detectAuthFailure(out, other))
+	assert.False(t, detectAuthFailure(filepath.Join(dir, "missing")))
+}
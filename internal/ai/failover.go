@@ -0,0 +1,187 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// probeTimeout bounds a health check.
+	probeTimeout = 2 * time.Minute
+	// probePrompt is the trivial prompt a health check sends.
+	probePrompt = "Reply with the single word OK."
+)
+
+// probeVersionArgs are the arguments printing an AI CLI's version; gh
+// copilot has no version flag, so its help is asked for instead.
+var probeVersionArgs = map[string][]string{
+	"copilot": {"copilot", "--help"},
+}
+
+// HealthCheck is a lightweight probe telling whether an AI provider works:
+// its CLI starts and Runner answers a trivial prompt.
+type HealthCheck struct {
+	Provider string
+	Runner   AIRunner
+	// Version runs the CLI's --version first. Leave it off when the CLI
+	// only exists inside a sandbox.
+	Version bool
+}
+
+// Run probes the provider and returns why it is down, or nil.
+func (h *HealthCheck) Run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	if h.Version {
+		args, ok := probeVersionArgs[h.Provider]
+		if !ok {
+			args = []string{"--version"}
+		}
+		cmd := Command(h.Provider)
+		if out, err := exec.CommandContext(ctx, cmd, args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("%s %s: %w: %s", cmd, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "ralph-loop-probe-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	outputPath := filepath.Join(dir, "output.txt")
+	if err := h.Runner.Run(ctx, probePrompt, outputPath); err != nil {
+		return fmt.Errorf("probe prompt: %w", err)
+	}
+	if data, err := os.ReadFile(outputPath); err != nil || strings.TrimSpace(string(data)) == "" {
+		return errors.New("probe prompt: no answer")
+	}
+	return nil
+}
+
+// Failover moves a session from its primary AI provider to a secondary one
+// when a health check finds the primary down, for the rest of the session.
+// The primary is checked when the session starts (Check) and after
+// Threshold consecutive failed calls. One Failover is shared by every
+// runner so all phases switch together. It is safe for concurrent use.
+type Failover struct {
+	Primary   string
+	Secondary string
+	// Threshold is the number of consecutive failed calls of the primary
+	// that trigger a health check; an authentication failure triggers one
+	// at once.
+	Threshold int
+	// Probe checks the primary, returning why it is down.
+	Probe func(ctx context.Context) error
+	// OnFailover is called once, when the session switches to the
+	// secondary.
+	OnFailover func(from, to, reason string)
+
+	mu       sync.Mutex
+	failures int
+	active   bool
+}
+
+// Active reports whether the session runs on the secondary provider.
+func (f *Failover) Active() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.active
+}
+
+// Check probes the primary and fails over when it is down. It reports
+// whether the session runs on the secondary. A probe cut short by ctx
+// does not fail over.
+func (f *Failover) Check(ctx context.Context) bool {
+	if f.Active() {
+		return true
+	}
+	err := f.Probe(ctx)
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	f.mu.Lock()
+	if f.active {
+		f.mu.Unlock()
+		return true
+	}
+	f.active = true
+	f.mu.Unlock()
+	if f.OnFailover != nil {
+		f.OnFailover(f.Primary, f.Secondary, fmt.Sprintf("health check failed: %v", err))
+	}
+	return true
+}
+
+// Restore switches to the secondary without a health check or OnFailover,
+// for a resumed session that had failed over before.
+func (f *Failover) Restore() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.active = true
+}
+
+// failed counts a failed call of the primary and reports whether it is
+// time for a health check, resetting the count if so.
+func (f *Failover) failed(auth bool) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures++
+	if !auth && f.failures < f.Threshold {
+		return false
+	}
+	f.failures = 0
+	return true
+}
+
+// succeeded resets the count of consecutive failed calls.
+func (f *Failover) succeeded() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = 0
+}
+
+// FailoverRunner runs Primary until Failover switches the session to the
+// secondary provider, and Secondary from then on. The call whose failure
+// led to the switch is run again on Secondary. Rate limits and overloads
+// are left to the ThrottledRunner and do not count as failures.
+type FailoverRunner struct {
+	Primary   AIRunner
+	Secondary AIRunner
+	Failover  *Failover
+}
+
+// Run executes the prompt on the provider the session currently uses.
+func (r *FailoverRunner) Run(ctx context.Context, prompt string, outputPath string) error {
+	if r.Failover.Active() {
+		return r.Secondary.Run(ctx, prompt, outputPath)
+	}
+	err := r.Primary.Run(ctx, prompt, outputPath)
+	if err == nil {
+		r.Failover.succeeded()
+		return nil
+	}
+	class := Classify(err)
+	if class == ClassCanceled || class == ClassRateLimit || class == ClassOverloaded || ctx.Err() != nil {
+		return err
+	}
+	if !r.Failover.failed(class == ClassAuth) || !r.Failover.Check(ctx) {
+		return err
+	}
+	return r.Secondary.Run(ctx, prompt, outputPath)
+}
+
+// SetModel forwards the model switch to the primary runner, if it supports
+// it. The secondary keeps its own model.
+func (r *FailoverRunner) SetModel(model string) {
+	if ms, ok := r.Primary.(ModelSetter); ok {
+		ms.SetModel(model)
+	}
+}
@@ -0,0 +1,123 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ ModelSetter = (*FailoverRunner)(nil)
+
+func TestHealthCheck(t *testing.T) {
+	answering := &mockRunner{runFunc: func(ctx context.Context, prompt, outputPath string) error {
+		return os.WriteFile(outputPath, []byte("OK\n"), 0644)
+	}}
+	silent := &mockRunner{runFunc: func(ctx context.Context, prompt, outputPath string) error { return nil }}
+
+	fakeCLI(t, "claude", "echo 1.0.0\n")
+	require.NoError(t, (&HealthCheck{Provider: "claude", Runner: answering, Version: true}).Run(context.Background()))
+	assert.EqualError(t, (&HealthCheck{Provider: "claude", Runner: silent}).Run(context.Background()), "probe prompt: no answer")
+
+	fakeCLI(t, "codex", "echo 'broken install' >&2\nexit 1\n")
+	err := (&HealthCheck{Provider: "codex", Runner: answering, Version: true}).Run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "codex --version")
+	assert.Contains(t, err.Error(), "broken install")
+}
+
+// failoverTestRunners returns a FailoverRunner whose primary fails with
+// primaryErr, and the names of the runners called so far.
+func failoverTestRunners(primaryErr *error, probeErr error) (*FailoverRunner, *[]string, *[]string) {
+	var calls, switches []string
+	f := &Failover{
+		Primary:   "claude",
+		Secondary: "codex",
+		Threshold: 2,
+		Probe:     func(ctx context.Context) error { return probeErr },
+		OnFailover: func(from, to, reason string) {
+			switches = append(switches, from+"->"+to+": "+reason)
+		},
+	}
+	return &FailoverRunner{
+		Primary: &mockRunner{runFunc: func(ctx context.Context, prompt, outputPath string) error {
+			calls = append(calls, "primary")
+			return *primaryErr
+		}},
+		Secondary: &mockRunner{runFunc: func(ctx context.Context, prompt, outputPath string) error {
+			calls = append(calls, "secondary")
+			return nil
+		}},
+		Failover: f,
+	}, &calls, &switches
+}
+
+func TestFailoverRunner_SwitchesAfterRepeatedFailures(t *testing.T) {
+	primaryErr := errors.New("exit status 1")
+	r, calls, switches := failoverTestRunners(&primaryErr, errors.New("connection refused"))
+	out := filepath.Join(t.TempDir(), "output.txt")
+
+	assert.Error(t, r.Run(context.Background(), "p", out))
+	require.NoError(t, r.Run(context.Background(), "p", out), "the second failure fails over and reruns the call")
+	require.NoError(t, r.Run(context.Background(), "p", out))
+	assert.Equal(t, []string{"primary", "primary", "secondary", "secondary"}, *calls)
+	assert.Equal(t, []string{"claude->codex: health check failed: connection refused"}, *switches)
+}
+
+func TestFailoverRunner_HealthyPrimaryStays(t *testing.T) {
+	primaryErr := errors.New("exit status 1")
+	r, calls, switches := failoverTestRunners(&primaryErr, nil)
+	out := filepath.Join(t.TempDir(), "output.txt")
+
+	for i := 0; i < 3; i++ {
+		assert.Error(t, r.Run(context.Background(), "p", out))
+	}
+	assert.Equal(t, []string{"primary", "primary", "primary"}, *calls)
+	assert.Empty(t, *switches, "a primary passing its health check is kept")
+	assert.False(t, r.Failover.Active())
+}
+
+func TestFailoverRunner_CountsOnlyConsecutiveProviderFailures(t *testing.T) {
+	var primaryErr error = &RateLimitError{}
+	r, calls, switches := failoverTestRunners(&primaryErr, errors.New("down"))
+	out := filepath.Join(t.TempDir(), "output.txt")
+
+	assert.Error(t, r.Run(context.Background(), "p", out))
+	assert.Error(t, r.Run(context.Background(), "p", out))
+	primaryErr = errors.New("exit status 1")
+	assert.Error(t, r.Run(context.Background(), "p", out))
+	primaryErr = nil
+	require.NoError(t, r.Run(context.Background(), "p", out))
+	primaryErr = errors.New("exit status 1")
+	assert.Error(t, r.Run(context.Background(), "p", out), "a success resets the count")
+	assert.Empty(t, *switches, "rate limits are not failures")
+	assert.Len(t, *calls, 5)
+
+	primaryErr = &AuthError{}
+	require.NoError(t, r.Run(context.Background(), "p", out), "an auth failure is checked at once")
+	assert.Len(t, *switches, 1)
+}
+
+func TestFailover_CheckAndRestore(t *testing.T) {
+	var switched int
+	f := &Failover{
+		Primary:    "claude",
+		Secondary:  "codex",
+		Probe:      func(ctx context.Context) error { return ctx.Err() },
+		OnFailover: func(from, to, reason string) { switched++ },
+	}
+	assert.False(t, f.Check(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, f.Check(ctx), "an interrupted probe does not fail over")
+
+	f.Restore()
+	assert.True(t, f.Active())
+	assert.True(t, f.Check(context.Background()))
+	assert.Zero(t, switched, "restoring a failover reports nothing")
+}
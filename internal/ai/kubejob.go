@@ -0,0 +1,261 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/ratelimit"
+)
+
+// jobLogEnv names the variable telling the job container where to write
+// the CLI output.
+const jobLogEnv = "RALPH_LOOP_JOB_LOG"
+
+// jobSeq numbers the jobs this process submits, keeping their names unique.
+var jobSeq atomic.Int64
+
+// KubeJob runs AI CLI calls as Kubernetes Jobs through kubectl, so heavy
+// loops use cluster capacity. The project directory must live on the
+// PersistentVolumeClaim Volume, mounted by the orchestrator and, at the
+// same path, by every job: the job works on the project there and writes
+// the CLI output next to the orchestrator's output file, where it is
+// collected once the job completes.
+type KubeJob struct {
+	Kubectl   string        // defaults to "kubectl"
+	Namespace string        // empty uses the current kubectl context's namespace
+	Image     string        // must provide the AI CLI and sh
+	Volume    string        // PersistentVolumeClaim holding the project
+	Secrets   []string      // secrets whose keys become environment variables, e.g. API keys
+	Workdir   string        // project directory and mount path; defaults to the current directory
+	Poll      time.Duration // interval between job status checks; defaults to 5s
+}
+
+// jobCommand is an AI CLI invocation and how to turn its output into the
+// text a runner writes to outputPath.
+type jobCommand struct {
+	Name    string
+	Args    []string
+	Env     []string // KEY=VALUE entries set in the job container
+	RawPath string   // where the raw CLI output goes, as when run locally
+	// Parse returns the output text from the raw CLI output.
+	Parse func(raw string) string
+}
+
+// jobCommander is implemented by runners whose CLI can run in a Job.
+type jobCommander interface {
+	jobCommand(prompt, outputPath string) jobCommand
+}
+
+func (r *ClaudeRunner) jobCommand(prompt, outputPath string) jobCommand {
+	args := r.BuildArgs(prompt)
+	if promptOnStdin {
+		args = append(args, "--", prompt)
+	}
+	return jobCommand{Name: "claude", Args: args, Env: claudeEnv(r.ReasoningEffort), RawPath: outputPath + ".stream.json", Parse: parser.ParseStreamJSON}
+}
+
+func (r *CodexRunner) jobCommand(prompt, outputPath string) jobCommand {
+	args := r.BuildArgs(prompt, outputPath)
+	if promptOnStdin {
+		args[len(args)-1] = prompt
+	}
+	// codex writes its last message to outputPath itself; the JSONL is the
+	// fallback when that is empty
+	parse := func(raw string) string {
+		if data, err := os.ReadFile(outputPath); err == nil && len(bytes.TrimSpace(data)) > 0 {
+			return string(data)
+		}
+		return parser.ParseCodexJSONL(raw)
+	}
+	return jobCommand{Name: "codex", Args: args, RawPath: outputPath + ".jsonl", Parse: parse}
+}
+
+func (r *AmazonQRunner) jobCommand(prompt, outputPath string) jobCommand {
+	return jobCommand{Name: "q", Args: r.BuildArgs(prompt), RawPath: outputPath + ".log", Parse: parser.ParsePlainText}
+}
+
+func (r *CopilotRunner) jobCommand(prompt, outputPath string) jobCommand {
+	return jobCommand{Name: "gh", Args: r.BuildArgs(prompt), RawPath: outputPath + ".log", Parse: parser.ParsePlainText}
+}
+
+// JobRunner implements AIRunner by running the CLI of Runner, one of the
+// CLI runners, as a Kubernetes Job. It watches the job until it completes
+// and then collects the output the way Runner would have.
+type JobRunner struct {
+	Job    *KubeJob
+	Runner AIRunner
+}
+
+// SetModel forwards the model switch to the wrapped runner.
+func (r *JobRunner) SetModel(model string) {
+	if ms, ok := r.Runner.(ModelSetter); ok {
+		ms.SetModel(model)
+	}
+}
+
+// Run submits a job running the CLI with prompt, waits for it, and writes
+// the output to outputPath. Cancelling ctx deletes the job. Like the other
+// runners it returns a RateLimitError or AuthError when the output shows
+// one.
+func (r *JobRunner) Run(ctx context.Context, prompt string, outputPath string) error {
+	jc, ok := r.Runner.(jobCommander)
+	if !ok {
+		return fmt.Errorf("%T cannot run as a Kubernetes job", r.Runner)
+	}
+	absOutput, err := filepath.Abs(outputPath)
+	if err != nil {
+		return err
+	}
+	c := jc.jobCommand(prompt, absOutput)
+	os.Remove(c.RawPath)
+
+	name := fmt.Sprintf("ralph-loop-%d-%d-%d", time.Now().Unix(), os.Getpid(), jobSeq.Add(1))
+	if err := r.Job.submit(ctx, name, c); err != nil {
+		return err
+	}
+	defer r.Job.delete(name)
+	runErr := r.Job.wait(ctx, name)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	rawData, err := os.ReadFile(c.RawPath)
+	if err != nil && runErr == nil {
+		runErr = fmt.Errorf("job output not found on the shared volume: %w", err)
+	}
+	if err := os.WriteFile(outputPath, []byte(c.Parse(string(rawData))), 0644); err != nil {
+		return fmt.Errorf("write parsed output: %w", err)
+	}
+
+	rateLimitInfo, checkErr := ratelimit.CheckRateLimit(outputPath)
+	if checkErr == nil && rateLimitInfo != nil && rateLimitInfo.Detected {
+		return &RateLimitError{
+			Info:          rateLimitInfo,
+			UnderlyingErr: runErr,
+		}
+	}
+	if runErr != nil {
+		if detectAuthFailure(outputPath, c.RawPath) {
+			return &AuthError{UnderlyingErr: fmt.Errorf("%s job failed: %w", c.Name, runErr)}
+		}
+		return fmt.Errorf("%s job failed: %w", c.Name, runErr)
+	}
+	return nil
+}
+
+// manifest returns the Job called name that runs c with the project
+// volume mounted.
+func (k *KubeJob) manifest(name string, c jobCommand) map[string]any {
+	workdir := (&Sandbox{Workdir: k.Workdir}).workdir()
+	env := []map[string]string{{"name": jobLogEnv, "value": c.RawPath}}
+	for _, e := range c.Env {
+		key, value, _ := strings.Cut(e, "=")
+		env = append(env, map[string]string{"name": key, "value": value})
+	}
+	var envFrom []map[string]any
+	for _, s := range k.Secrets {
+		envFrom = append(envFrom, map[string]any{"secretRef": map[string]string{"name": s}})
+	}
+	metadata := map[string]any{"name": name, "labels": map[string]string{"app.kubernetes.io/managed-by": ContainerLabel}}
+	if k.Namespace != "" {
+		metadata["namespace"] = k.Namespace
+	}
+	container := map[string]any{
+		"name":       "ai",
+		"image":      k.Image,
+		"command":    append([]string{"sh", "-c", `exec "$@" > "$` + jobLogEnv + `" 2>&1`, "sh", c.Name}, c.Args...),
+		"workingDir": workdir,
+		"env":        env,
+		"volumeMounts": []map[string]string{
+			{"name": "project", "mountPath": workdir},
+		},
+	}
+	if envFrom != nil {
+		container["envFrom"] = envFrom
+	}
+	return map[string]any{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata":   metadata,
+		"spec": map[string]any{
+			"backoffLimit":            0,
+			"ttlSecondsAfterFinished": 3600,
+			"template": map[string]any{
+				"metadata": map[string]any{"labels": map[string]string{"app.kubernetes.io/managed-by": ContainerLabel}},
+				"spec": map[string]any{
+					"restartPolicy": "Never",
+					"containers":    []any{container},
+					"volumes": []map[string]any{
+						{"name": "project", "persistentVolumeClaim": map[string]string{"claimName": k.Volume}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// submit creates the job called name running c.
+func (k *KubeJob) submit(ctx context.Context, name string, c jobCommand) error {
+	manifest, err := json.Marshal(k.manifest(name, c))
+	if err != nil {
+		return err
+	}
+	cmd := k.kubectl(ctx, "create", "-f", "-")
+	cmd.Stdin = bytes.NewReader(manifest)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("kubectl create job %s: %w: %s", name, err, lastLine(string(out)))
+	}
+	return nil
+}
+
+// wait polls the job called name until it succeeds, fails or ctx is
+// cancelled, and returns an error unless it succeeded.
+func (k *KubeJob) wait(ctx context.Context, name string) error {
+	poll := k.Poll
+	if poll <= 0 {
+		poll = 5 * time.Second
+	}
+	for {
+		out, err := k.kubectl(ctx, "get", "job", name, "-o", "jsonpath={.status.succeeded}/{.status.failed}").Output()
+		if err != nil && ctx.Err() == nil {
+			return fmt.Errorf("kubectl get job %s: %w", name, err)
+		}
+		succeeded, failed, _ := strings.Cut(strings.TrimSpace(string(out)), "/")
+		switch {
+		case succeeded != "" && succeeded != "0":
+			return nil
+		case failed != "" && failed != "0":
+			return fmt.Errorf("job %s failed", name)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(poll):
+		}
+	}
+}
+
+// delete removes the job called name and its pod, without waiting.
+func (k *KubeJob) delete(name string) {
+	k.kubectl(context.Background(), "delete", "job", name, "--ignore-not-found", "--wait=false", "--cascade=background").Run()
+}
+
+func (k *KubeJob) kubectl(ctx context.Context, args ...string) *exec.Cmd {
+	kubectl := k.Kubectl
+	if kubectl == "" {
+		kubectl = "kubectl"
+	}
+	if k.Namespace != "" {
+		args = append([]string{"--namespace", k.Namespace}, args...)
+	}
+	return exec.CommandContext(ctx, kubectl, args...)
+}
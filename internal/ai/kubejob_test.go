@@ -0,0 +1,157 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKubectl installs a fake kubectl that logs every call, saves the
+// manifest it is asked to create, runs onCreate, and then reports the job
+// status given by status ("1/" succeeded, "/1" failed, "/" running).
+func fakeKubectl(t *testing.T, onCreate, status string) (log, manifest string) {
+	t.Helper()
+	dir := t.TempDir()
+	manifest = filepath.Join(dir, "manifest.json")
+	log = fakeRuntime(t, "kubectl", `case "$*" in
+*create*) cat > `+manifest+"\n"+onCreate+` ;;
+*get*) printf '`+status+`' ;;
+esac
+`)
+	return log, manifest
+}
+
+func TestJobRunner_Run(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "impl-output.txt")
+	log, manifestPath := fakeKubectl(t, `echo '{"type":"result","result":"implemented"}' > `+out+".stream.json", "1/")
+
+	job := &KubeJob{Namespace: "ci", Image: "agent:1", Volume: "project-pvc", Secrets: []string{"ai-keys"}, Workdir: "/work", Poll: time.Millisecond}
+	r := &JobRunner{Job: job, Runner: &ClaudeRunner{Model: "opus", MaxTurns: 5, ReasoningEffort: "high"}}
+	require.NoError(t, r.Run(context.Background(), "do the task", out))
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "implemented", strings.TrimSpace(string(data)))
+
+	calls := readCalls(t, log)
+	require.Len(t, calls, 3)
+	assert.Equal(t, "--namespace ci create -f -", calls[0])
+	assert.True(t, strings.HasPrefix(calls[1], "--namespace ci get job ralph-loop-"), calls[1])
+	assert.True(t, strings.HasPrefix(calls[2], "--namespace ci delete job ralph-loop-"), calls[2])
+
+	raw, err := os.ReadFile(manifestPath)
+	require.NoError(t, err)
+	var m struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+		Spec struct {
+			BackoffLimit int `json:"backoffLimit"`
+			Template     struct {
+				Spec struct {
+					Containers []struct {
+						Image      string   `json:"image"`
+						Command    []string `json:"command"`
+						WorkingDir string   `json:"workingDir"`
+						Env        []struct {
+							Name  string `json:"name"`
+							Value string `json:"value"`
+						} `json:"env"`
+						EnvFrom []struct {
+							SecretRef struct {
+								Name string `json:"name"`
+							} `json:"secretRef"`
+						} `json:"envFrom"`
+						VolumeMounts []struct {
+							MountPath string `json:"mountPath"`
+						} `json:"volumeMounts"`
+					} `json:"containers"`
+					Volumes []struct {
+						PVC struct {
+							ClaimName string `json:"claimName"`
+						} `json:"persistentVolumeClaim"`
+					} `json:"volumes"`
+				} `json:"spec"`
+			} `json:"template"`
+		} `json:"spec"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &m))
+	assert.Equal(t, "Job", m.Kind)
+	assert.Equal(t, "ci", m.Metadata.Namespace)
+	assert.Equal(t, 0, m.Spec.BackoffLimit)
+	require.Len(t, m.Spec.Template.Spec.Containers, 1)
+	c := m.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "agent:1", c.Image)
+	assert.Equal(t, "/work", c.WorkingDir)
+	assert.Equal(t, []string{"sh", "-c", `exec "$@" > "$RALPH_LOOP_JOB_LOG" 2>&1`, "sh", "claude"}, c.Command[:5])
+	assert.Contains(t, c.Command, "do the task")
+	assert.Equal(t, jobLogEnv, c.Env[0].Name)
+	assert.Equal(t, out+".stream.json", c.Env[0].Value)
+	assert.Equal(t, "MAX_THINKING_TOKENS", c.Env[1].Name)
+	require.Len(t, c.EnvFrom, 1)
+	assert.Equal(t, "ai-keys", c.EnvFrom[0].SecretRef.Name)
+	assert.Equal(t, "/work", c.VolumeMounts[0].MountPath)
+	assert.Equal(t, "project-pvc", m.Spec.Template.Spec.Volumes[0].PVC.ClaimName)
+}
+
+func TestJobRunner_JobFails(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "impl-output.txt")
+	fakeKubectl(t, `echo 'Error: Invalid API key' > `+out+".log", "/1")
+
+	r := &JobRunner{Job: &KubeJob{Image: "agent:1", Volume: "pvc", Poll: time.Millisecond}, Runner: &AmazonQRunner{}}
+	err := r.Run(context.Background(), "do the task", out)
+	var authErr *AuthError
+	require.ErrorAs(t, err, &authErr)
+	assert.Contains(t, err.Error(), "job ralph-loop-")
+}
+
+func TestJobRunner_MissingOutput(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "impl-output.txt")
+	fakeKubectl(t, "", "1/")
+
+	r := &JobRunner{Job: &KubeJob{Image: "agent:1", Volume: "pvc", Poll: time.Millisecond}, Runner: &CopilotRunner{}}
+	err := r.Run(context.Background(), "do the task", out)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "job output not found on the shared volume")
+}
+
+func TestJobRunner_CancelDeletesJob(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "impl-output.txt")
+	log, _ := fakeKubectl(t, "", "/")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	r := &JobRunner{Job: &KubeJob{Image: "agent:1", Volume: "pvc", Poll: time.Millisecond}, Runner: &CodexRunner{}}
+	require.ErrorIs(t, r.Run(ctx, "do the task", out), context.DeadlineExceeded)
+
+	calls := readCalls(t, log)
+	assert.True(t, strings.HasPrefix(calls[len(calls)-1], "delete job ralph-loop-"), calls[len(calls)-1])
+}
+
+func TestJobRunner_SubmitError(t *testing.T) {
+	fakeCLI(t, "kubectl", "echo 'error: forbidden' >&2\nexit 1\n")
+
+	r := &JobRunner{Job: &KubeJob{Image: "agent:1", Volume: "pvc"}, Runner: &ClaudeRunner{}}
+	err := r.Run(context.Background(), "do the task", filepath.Join(t.TempDir(), "out.txt"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "error: forbidden")
+}
+
+func TestJobRunner_UnsupportedRunner(t *testing.T) {
+	r := &JobRunner{Job: &KubeJob{}, Runner: &ReplayRunner{}}
+	assert.Error(t, r.Run(context.Background(), "p", filepath.Join(t.TempDir(), "out.txt")))
+}
+
+func TestJobRunner_SetModel(t *testing.T) {
+	inner := &ClaudeRunner{Model: "sonnet"}
+	(&JobRunner{Runner: inner}).SetModel("opus")
+	assert.Equal(t, "opus", inner.Model)
+}
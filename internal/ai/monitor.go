@@ -13,11 +13,23 @@ type MonitorConfig struct {
 	HardCap           int           // absolute max seconds (default 7200)
 	OutputPath        string        // file to monitor for size changes
 	TickInterval      time.Duration // interval between checks (default 2s, configurable for testing)
+
+	// HangTimeout is the watchdog: seconds without output and without CPU
+	// or I/O in the process group of PID before the process is killed as
+	// hung (0 = off). Where process activity cannot be sampled only
+	// InactivityTimeout applies.
+	HangTimeout int
+	PID         int
+	// SnapshotPath is where the hang report is saved ("" = not saved).
+	SnapshotPath string
+	// OnHang, if set, receives the report before the process is killed.
+	OnHang func(HangReport)
 }
 
 // MonitorProcess monitors an AI process by watching its output file.
 // It cancels the context if:
 // - No output for InactivityTimeout seconds
+// - No output and no process activity for HangTimeout seconds
 // - Total runtime exceeds HardCap seconds
 // - A result marker (RALPH_STATUS or RALPH_VALIDATION) is detected, after a 2s grace period
 func MonitorProcess(ctx context.Context, cancel context.CancelFunc, cfg MonitorConfig) {
@@ -36,6 +48,8 @@ func MonitorProcess(ctx context.Context, cancel context.CancelFunc, cfg MonitorC
 	lastChange := time.Now()
 	resultDetected := false
 	var resultTime time.Time
+	lastActivity, lastActive := uint64(0), time.Now()
+	sampling := cfg.HangTimeout > 0 && cfg.PID > 0
 
 	for {
 		select {
@@ -50,6 +64,26 @@ func MonitorProcess(ctx context.Context, cancel context.CancelFunc, cfg MonitorC
 				return
 			}
 
+			// Watchdog: no output and no CPU or I/O means the process hung
+			if sampling {
+				if activity, ok := processActivity(cfg.PID); !ok {
+					sampling = false
+				} else if activity != lastActivity {
+					lastActivity, lastActive = activity, time.Now()
+				}
+			}
+			if sampling {
+				idle := min(time.Since(lastChange), time.Since(lastActive))
+				if idle.Seconds() >= float64(cfg.HangTimeout) {
+					report := newHangReport(cfg.PID, elapsed, idle, cfg.OutputPath, cfg.SnapshotPath)
+					if cfg.OnHang != nil {
+						cfg.OnHang(report)
+					}
+					cancel()
+					return
+				}
+			}
+
 			// Check file size
 			info, err := os.Stat(cfg.OutputPath)
 			if err != nil {
@@ -0,0 +1,30 @@
+package ai
+
+import (
+	"context"
+	"io"
+)
+
+type outputLogKey struct{}
+
+// WithOutputLog returns a context whose runs also copy the CLI's raw
+// stdout and stderr to w, so callers can keep a log per phase without each
+// runner knowing about it.
+func WithOutputLog(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, outputLogKey{}, w)
+}
+
+// OutputLog returns the writer WithOutputLog attached to ctx, or nil.
+func OutputLog(ctx context.Context) io.Writer {
+	w, _ := ctx.Value(outputLogKey{}).(io.Writer)
+	return w
+}
+
+// teeOutput returns the writer a CLI's output goes to: raw, plus the log
+// carried by ctx, if any.
+func teeOutput(ctx context.Context, raw io.Writer) io.Writer {
+	if w, ok := ctx.Value(outputLogKey{}).(io.Writer); ok && w != nil {
+		return io.MultiWriter(raw, w)
+	}
+	return raw
+}
@@ -0,0 +1,24 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeeOutput_WithoutLog(t *testing.T) {
+	var raw bytes.Buffer
+	assert.Same(t, &raw, teeOutput(context.Background(), &raw))
+}
+
+func TestTeeOutput_CopiesToLog(t *testing.T) {
+	var raw, log bytes.Buffer
+	w := teeOutput(WithOutputLog(context.Background(), &log), &raw)
+
+	_, err := w.Write([]byte(`{"type":"text"}` + "\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"text"}`+"\n", raw.String())
+	assert.Equal(t, raw.String(), log.String())
+}
@@ -0,0 +1,67 @@
+package ai
+
+import "fmt"
+
+// Permissions restricts what an AI CLI may do, from one policy shared by
+// every runner. Each runner translates it into its CLI's own flags:
+//
+//   - claude: --allowedTools (dropping --dangerously-skip-permissions so the
+//     list is enforced) and --disallowedTools "Bash(cmd:*)".
+//   - gh copilot: --allow-tool instead of --allow-all-tools, and
+//     --deny-tool "shell(cmd)".
+//   - q chat: --trust-tools instead of --trust-all-tools; it cannot deny
+//     single commands.
+//   - codex: runs in its workspace-write sandbox instead of bypassing it;
+//     it cannot filter tools or commands.
+//
+// ReadOnly limits claude to Read, Grep and Glob, runs codex in its
+// read-only sandbox, trusts q with fs_read only and denies gh copilot
+// writes and the shell.
+type Permissions struct {
+	// AllowedTools are the only tools the CLI may use, in the CLI's own
+	// syntax (e.g. "Edit" or "Bash(go test:*)" for claude). Empty allows
+	// every tool.
+	AllowedTools []string
+	// DeniedCommands are shell commands, matched by prefix, that the CLI
+	// must not run (e.g. "rm", "git push").
+	DeniedCommands []string
+	// ReadOnly lets the CLI read the workspace and nothing else, as the
+	// implementer of --apply-patch, whose diff ralph-loop applies. It
+	// replaces AllowedTools.
+	ReadOnly bool
+}
+
+// Restricted reports whether p restricts anything. A nil p does not.
+func (p *Permissions) Restricted() bool {
+	return p != nil && (p.ReadOnly || len(p.AllowedTools) > 0 || len(p.DeniedCommands) > 0)
+}
+
+// readOnly reports whether p lets the CLI read only; false when p is nil.
+func (p *Permissions) readOnly() bool {
+	return p != nil && p.ReadOnly
+}
+
+// allowed returns p's allowed tools, or the read tools of readOnlyTools
+// when p is read-only; nil when p is nil.
+func (p *Permissions) allowed(readOnlyTools ...string) []string {
+	if p == nil {
+		return nil
+	}
+	if p.ReadOnly {
+		return readOnlyTools
+	}
+	return p.AllowedTools
+}
+
+// denied returns p's denied commands, each formatted with format (e.g.
+// "Bash(%s:*)"); nil when p is nil.
+func (p *Permissions) denied(format string) []string {
+	if p == nil {
+		return nil
+	}
+	var rules []string
+	for _, cmd := range p.DeniedCommands {
+		rules = append(rules, fmt.Sprintf(format, cmd))
+	}
+	return rules
+}
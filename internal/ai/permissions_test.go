@@ -0,0 +1,75 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermissions_Restricted(t *testing.T) {
+	var p *Permissions
+	assert.False(t, p.Restricted())
+	assert.False(t, (&Permissions{}).Restricted())
+	assert.True(t, (&Permissions{DeniedCommands: []string{"rm"}}).Restricted())
+	assert.True(t, (&Permissions{AllowedTools: []string{"Edit"}}).Restricted())
+	assert.True(t, (&Permissions{ReadOnly: true}).Restricted())
+}
+
+func TestClaudeRunner_BuildArgs_Permissions(t *testing.T) {
+	r := &ClaudeRunner{Model: "opus", MaxTurns: 5, Permissions: &Permissions{
+		AllowedTools:   []string{"Edit", "Bash(go test:*)"},
+		DeniedCommands: []string{"rm", "git push"},
+	}}
+	args := r.BuildArgs("p")
+	assert.NotContains(t, args, "--dangerously-skip-permissions")
+	assert.Equal(t, "Edit,Bash(go test:*)", args[indexOf(args, "--allowedTools")+1])
+	assert.Equal(t, "Bash(rm:*),Bash(git push:*)", args[indexOf(args, "--disallowedTools")+1])
+
+	// Denied commands alone keep permissions skipped for everything else.
+	r.Permissions = &Permissions{DeniedCommands: []string{"rm"}}
+	args = r.BuildArgs("p")
+	assert.Contains(t, args, "--dangerously-skip-permissions")
+	assert.NotContains(t, args, "--allowedTools")
+	assert.Contains(t, args, "Bash(rm:*)")
+}
+
+func TestCodexRunner_BuildArgs_Permissions(t *testing.T) {
+	r := &CodexRunner{Permissions: &Permissions{DeniedCommands: []string{"rm"}}}
+	args := r.BuildArgs("p", "out.txt")
+	assert.NotContains(t, args, "--dangerously-bypass-approvals-and-sandbox")
+	assert.Equal(t, "workspace-write", args[indexOf(args, "--sandbox")+1])
+}
+
+func TestAmazonQRunner_BuildArgs_Permissions(t *testing.T) {
+	r := &AmazonQRunner{Permissions: &Permissions{AllowedTools: []string{"fs_read", "fs_write"}}}
+	assert.Equal(t, []string{"chat", "--no-interactive", "--trust-tools=fs_read,fs_write", "p"}, r.BuildArgs("p"))
+}
+
+func TestCopilotRunner_BuildArgs_Permissions(t *testing.T) {
+	r := &CopilotRunner{Permissions: &Permissions{
+		AllowedTools:   []string{"write", "shell(npm test)"},
+		DeniedCommands: []string{"rm"},
+	}}
+	assert.Equal(t, []string{"copilot", "-p", "p", "--allow-tool", "write", "--allow-tool", "shell(npm test)", "--deny-tool", "shell(rm)"}, r.BuildArgs("p"))
+
+	r.Permissions = &Permissions{DeniedCommands: []string{"git push"}}
+	assert.Equal(t, []string{"copilot", "-p", "p", "--allow-all-tools", "--deny-tool", "shell(git push)"}, r.BuildArgs("p"))
+}
+
+// TestBuildArgs_ReadOnly verifies that the implementer of --apply-patch
+// cannot edit the workspace itself.
+func TestBuildArgs_ReadOnly(t *testing.T) {
+	perms := &Permissions{ReadOnly: true, AllowedTools: []string{"Edit"}, DeniedCommands: []string{"rm"}}
+
+	claude := (&ClaudeRunner{Model: "opus", MaxTurns: 5, Permissions: perms}).BuildArgs("p")
+	assert.NotContains(t, claude, "--dangerously-skip-permissions")
+	assert.Equal(t, "Read,Grep,Glob", claude[indexOf(claude, "--allowedTools")+1])
+
+	codex := (&CodexRunner{Permissions: perms}).BuildArgs("p", "out.txt")
+	assert.NotContains(t, codex, "--dangerously-bypass-approvals-and-sandbox")
+	assert.Equal(t, "read-only", codex[indexOf(codex, "--sandbox")+1])
+
+	assert.Equal(t, []string{"chat", "--no-interactive", "--trust-tools=fs_read", "p"}, (&AmazonQRunner{Permissions: perms}).BuildArgs("p"))
+	assert.Equal(t, []string{"copilot", "-p", "p", "--deny-tool", "write", "--deny-tool", "shell", "--deny-tool", "shell(rm)"},
+		(&CopilotRunner{Permissions: &Permissions{ReadOnly: true, DeniedCommands: []string{"rm"}}}).BuildArgs("p"))
+}
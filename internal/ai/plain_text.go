@@ -0,0 +1,72 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/ratelimit"
+)
+
+// runPlainText runs an AI CLI that prints its answer as terminal text
+// rather than structured events. The raw output goes to outputPath+".log"
+// and the cleaned text (see parser.ParsePlainText) to outputPath. It
+// returns a RateLimitError or AuthError like the other runners.
+func runPlainText(ctx context.Context, sb *Sandbox, name string, args []string, inactivityTimeout, hangTimeout int, outputPath string) error {
+	// Create a cancellable context for the monitor to use
+	monCtx, monCancel := context.WithCancel(ctx)
+	defer monCancel()
+
+	cmd := newCommand(monCtx, sb, name, args, nil)
+
+	rawPath := outputPath + ".log"
+	rawFile, err := os.Create(rawPath)
+	if err != nil {
+		return fmt.Errorf("create output file: %w", err)
+	}
+
+	// Merge stdout and stderr into the raw file, and the phase log if any
+	out := teeOutput(ctx, rawFile)
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	if err := cmd.Start(); err != nil {
+		rawFile.Close()
+		return fmt.Errorf("%s command failed: %w", name, err)
+	}
+
+	var hang hangWatch
+	go MonitorProcess(monCtx, monCancel, MonitorConfig{
+		InactivityTimeout: inactivityTimeout,
+		OutputPath:        rawPath,
+		HangTimeout:       hangTimeout,
+		PID:               cmd.Process.Pid,
+		SnapshotPath:      outputPath + ".hang.json",
+		OnHang:            hang.record,
+	})
+
+	runErr := hang.err(cmd.Wait())
+	rawFile.Close()
+
+	rawData, _ := os.ReadFile(rawPath)
+	if err := os.WriteFile(outputPath, []byte(parser.ParsePlainText(string(rawData))), 0644); err != nil {
+		return fmt.Errorf("write parsed output: %w", err)
+	}
+
+	rateLimitInfo, checkErr := ratelimit.CheckRateLimit(outputPath)
+	if checkErr == nil && rateLimitInfo != nil && rateLimitInfo.Detected {
+		return &RateLimitError{
+			Info:          rateLimitInfo,
+			UnderlyingErr: runErr,
+		}
+	}
+
+	if runErr != nil {
+		if detectAuthFailure(outputPath) {
+			return &AuthError{UnderlyingErr: fmt.Errorf("%s command failed: %w", name, runErr)}
+		}
+		return fmt.Errorf("%s command failed: %w", name, runErr)
+	}
+	return nil
+}
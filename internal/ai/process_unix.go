@@ -0,0 +1,28 @@
+//go:build !windows
+
+package ai
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// promptOnStdin reports whether prompts are piped to the AI CLI rather
+// than passed as its last argument.
+const promptOnStdin = false
+
+// setProcessTree starts cmd in its own process group and makes
+// cancellation kill the whole group, so tools the CLI spawned (test
+// runners, dev servers, browsers) do not outlive it.
+func setProcessTree(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		if errors.Is(err, syscall.ESRCH) {
+			return os.ErrProcessDone
+		}
+		return err
+	}
+}
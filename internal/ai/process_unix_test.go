@@ -0,0 +1,57 @@
+//go:build !windows
+
+package ai
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewCommand_CancelKillsProcessTree verifies that cancelling a runner
+// also kills the processes its CLI started.
+func TestNewCommand_CancelKillsProcessTree(t *testing.T) {
+	tmpDir := t.TempDir()
+	pidFile := filepath.Join(tmpDir, "child.pid")
+	script := filepath.Join(tmpDir, "fakecli")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\nsleep 30 &\necho $! > "+pidFile+"\nwait\n"), 0755))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := newCommand(ctx, nil, script, nil, nil)
+	require.NoError(t, cmd.Start())
+
+	var childPid int
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(pidFile)
+		if err != nil {
+			return false
+		}
+		childPid, err = strconv.Atoi(strings.TrimSpace(string(data)))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	_ = cmd.Wait()
+
+	assert.Eventually(t, func() bool {
+		return syscall.Kill(childPid, 0) != nil
+	}, 5*time.Second, 10*time.Millisecond, "the CLI's child process should be killed with it")
+}
+
+// TestNewCommand_CancelAfterExit verifies that cancelling a finished
+// command is not reported as an error.
+func TestNewCommand_CancelAfterExit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cmd := newCommand(ctx, nil, "true", nil, nil)
+	require.NoError(t, cmd.Run())
+	assert.ErrorIs(t, cmd.Cancel(), os.ErrProcessDone)
+}
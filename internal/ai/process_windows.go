@@ -0,0 +1,30 @@
+//go:build windows
+
+package ai
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// promptOnStdin reports whether prompts are piped to the AI CLI rather
+// than passed as its last argument. On Windows a command line is limited
+// to 32767 characters, and npm installs the CLIs as .cmd shims whose
+// arguments cmd.exe re-parses, mangling quotes, '%' and newlines.
+const promptOnStdin = true
+
+// setProcessTree starts cmd in a new process group, so a Ctrl+C in the
+// console reaches only ralph-loop, and makes cancellation terminate the
+// whole process tree with taskkill.
+func setProcessTree(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+	cmd.Cancel = func() error {
+		kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid))
+		kill.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+		if err := kill.Run(); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+}
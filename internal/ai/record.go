@@ -0,0 +1,128 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Recording is a directory of recorded AI interactions. Each call of a
+// phase is numbered in order and stored as <phase>-<NNN>.prompt.txt and
+// <phase>-<NNN>.output.txt, plus .stream.json when the CLI wrote one and
+// .error.txt when the call failed. Runners of the same phase share the
+// numbering, so a replay must make the calls in the recorded order.
+type Recording struct {
+	Dir string
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+// NewRecording returns a recording in dir.
+func NewRecording(dir string) *Recording {
+	return &Recording{Dir: dir, calls: map[string]int{}}
+}
+
+// next returns the base path of the next call of phase.
+func (r *Recording) next(phase string) (string, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls[phase]++
+	n := r.calls[phase]
+	return filepath.Join(r.Dir, fmt.Sprintf("%s-%03d", strings.ToLower(phase), n)), n
+}
+
+// RecordingRunner runs Inner and saves each prompt and output in the
+// recording.
+type RecordingRunner struct {
+	Inner     AIRunner
+	Recording *Recording
+	Phase     string // IMPL, VAL, CROSS, ...
+}
+
+// Run delegates to Inner and records the call, whether or not it failed.
+func (r *RecordingRunner) Run(ctx context.Context, prompt string, outputPath string) error {
+	base, _ := r.Recording.next(r.Phase)
+	if err := os.MkdirAll(r.Recording.Dir, 0755); err != nil {
+		return fmt.Errorf("create recording dir: %w", err)
+	}
+	if err := os.WriteFile(base+".prompt.txt", []byte(prompt), 0644); err != nil {
+		return fmt.Errorf("record prompt: %w", err)
+	}
+
+	err := r.Inner.Run(ctx, prompt, outputPath)
+	if copyErr := copyIfExists(outputPath, base+".output.txt"); copyErr != nil {
+		return fmt.Errorf("record output: %w", copyErr)
+	}
+	if copyErr := copyIfExists(outputPath+".stream.json", base+".stream.json"); copyErr != nil {
+		return fmt.Errorf("record output: %w", copyErr)
+	}
+	if err != nil {
+		if writeErr := os.WriteFile(base+".error.txt", []byte(err.Error()), 0644); writeErr != nil {
+			return fmt.Errorf("record error: %w", writeErr)
+		}
+	}
+	return err
+}
+
+// SetModel forwards the model switch to the inner runner, if it supports it.
+func (r *RecordingRunner) SetModel(model string) {
+	if ms, ok := r.Inner.(ModelSetter); ok {
+		ms.SetModel(model)
+	}
+}
+
+// ReplayRunner answers calls from a recording instead of running an AI CLI.
+type ReplayRunner struct {
+	Recording *Recording
+	Phase     string
+	// OnPromptMismatch, if set, is called when a prompt differs from the
+	// recorded one; the recorded output is replayed regardless.
+	OnPromptMismatch func(phase string, call int)
+}
+
+// Run writes the recorded output of the phase's next call to outputPath
+// and returns the recorded error, if the call failed.
+func (r *ReplayRunner) Run(ctx context.Context, prompt string, outputPath string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	base, n := r.Recording.next(r.Phase)
+	recorded, err := os.ReadFile(base + ".prompt.txt")
+	if err != nil {
+		return fmt.Errorf("no recorded %s call %d in %s: %w", r.Phase, n, r.Recording.Dir, err)
+	}
+	if string(recorded) != prompt && r.OnPromptMismatch != nil {
+		r.OnPromptMismatch(r.Phase, n)
+	}
+
+	if err := copyIfExists(base+".output.txt", outputPath); err != nil {
+		return fmt.Errorf("replay output: %w", err)
+	}
+	if err := copyIfExists(base+".stream.json", outputPath+".stream.json"); err != nil {
+		return fmt.Errorf("replay output: %w", err)
+	}
+	if msg, err := os.ReadFile(base + ".error.txt"); err == nil {
+		return errors.New(string(msg))
+	}
+	return nil
+}
+
+// SetModel is a no-op: the recording decides what every call returns.
+func (r *ReplayRunner) SetModel(string) {}
+
+// copyIfExists copies src to dst; a missing src is not an error.
+func copyIfExists(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0644)
+}
@@ -0,0 +1,96 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Compile-time interface checks.
+var (
+	_ ModelSetter = (*RecordingRunner)(nil)
+	_ ModelSetter = (*ReplayRunner)(nil)
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestRecordingRunner_RecordsEachCall(t *testing.T) {
+	recDir := filepath.Join(t.TempDir(), "rec")
+	out := filepath.Join(t.TempDir(), "output.txt")
+	calls := 0
+	inner := &mockRunner{runFunc: func(ctx context.Context, prompt, outputPath string) error {
+		calls++
+		require.NoError(t, os.WriteFile(outputPath, []byte("answer "+prompt), 0644))
+		if calls == 2 {
+			require.NoError(t, os.WriteFile(outputPath+".stream.json", []byte(`{"type":"result"}`), 0644))
+			return errors.New("exit status 1")
+		}
+		return nil
+	}}
+	rec := NewRecording(recDir)
+	impl := &RecordingRunner{Inner: inner, Recording: rec, Phase: "IMPL"}
+	val := &RecordingRunner{Inner: inner, Recording: rec, Phase: "VAL"}
+
+	require.NoError(t, impl.Run(context.Background(), "one", out))
+	assert.EqualError(t, impl.Run(context.Background(), "two", out), "exit status 1")
+	require.NoError(t, val.Run(context.Background(), "three", out))
+
+	assert.Equal(t, "one", readFile(t, filepath.Join(recDir, "impl-001.prompt.txt")))
+	assert.Equal(t, "answer one", readFile(t, filepath.Join(recDir, "impl-001.output.txt")))
+	assert.NoFileExists(t, filepath.Join(recDir, "impl-001.error.txt"))
+	assert.Equal(t, "answer two", readFile(t, filepath.Join(recDir, "impl-002.output.txt")))
+	assert.Equal(t, `{"type":"result"}`, readFile(t, filepath.Join(recDir, "impl-002.stream.json")))
+	assert.Equal(t, "exit status 1", readFile(t, filepath.Join(recDir, "impl-002.error.txt")))
+	assert.Equal(t, "three", readFile(t, filepath.Join(recDir, "val-001.prompt.txt")))
+}
+
+func TestReplayRunner_ReplaysRecording(t *testing.T) {
+	recDir := t.TempDir()
+	inner := &mockRunner{runFunc: func(ctx context.Context, prompt, outputPath string) error {
+		if err := os.WriteFile(outputPath, []byte("answer "+prompt), 0644); err != nil {
+			return err
+		}
+		if prompt == "two" {
+			return errors.New("exit status 1")
+		}
+		return nil
+	}}
+	recorder := &RecordingRunner{Inner: inner, Recording: NewRecording(recDir), Phase: "IMPL"}
+	out := filepath.Join(t.TempDir(), "output.txt")
+	require.NoError(t, recorder.Run(context.Background(), "one", out))
+	require.Error(t, recorder.Run(context.Background(), "two", out))
+
+	var mismatches []int
+	replay := &ReplayRunner{
+		Recording:        NewRecording(recDir),
+		Phase:            "IMPL",
+		OnPromptMismatch: func(phase string, call int) { mismatches = append(mismatches, call) },
+	}
+	replayOut := filepath.Join(t.TempDir(), "replayed.txt")
+
+	require.NoError(t, replay.Run(context.Background(), "one", replayOut))
+	assert.Equal(t, "answer one", readFile(t, replayOut))
+	assert.EqualError(t, replay.Run(context.Background(), "changed", replayOut), "exit status 1")
+	assert.Equal(t, "answer two", readFile(t, replayOut))
+	assert.Equal(t, []int{2}, mismatches)
+
+	err := replay.Run(context.Background(), "three", replayOut)
+	assert.ErrorContains(t, err, "no recorded IMPL call 3")
+}
+
+func TestReplayRunner_HonoursCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	replay := &ReplayRunner{Recording: NewRecording(t.TempDir()), Phase: "VAL"}
+	assert.ErrorIs(t, replay.Run(ctx, "p", filepath.Join(t.TempDir(), "out")), context.Canceled)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand/v2"
 	"time"
 
 	"github.com/CodexForgeBR/cli-tools/internal/ratelimit"
@@ -11,18 +12,42 @@ import (
 
 // RetryConfig configures exponential backoff retry behavior.
 type RetryConfig struct {
-	MaxRetries        int
-	BaseDelay         int // seconds (default 5)
-	StartAttempt      int // for resume (default 0)
-	StartDelay        int // for resume (default 0, will use BaseDelay)
-	MaxRateLimitWaits int // max consecutive rate limit waits (default 3)
+	MaxRetries        int     // budget for transient errors
+	BaseDelay         int     // seconds (default 5)
+	MaxDelay          int     // cap on the backoff delay in seconds (0 = no cap)
+	Jitter            float64 // randomize each delay by up to ±Jitter of itself (0-1)
+	StartAttempt      int     // for resume (default 0)
+	StartDelay        int     // for resume (default 0, will use BaseDelay)
+	MaxRateLimitWaits int     // max consecutive rate limit waits (default 3)
+	MaxAuthRetries    int     // retries after an authentication failure (default 0)
 	OnRetry           func(attempt int, delay int)
 	OnRateLimit       func(info *ratelimit.RateLimitInfo)
+	OnEvent           func(RetryEvent)
 }
 
+// RetryEvent describes one failed attempt, or the success that ended a run
+// of failures, for retry telemetry.
+type RetryEvent struct {
+	Class   ErrorClass // "" when Err is nil
+	Err     error      // nil when the call succeeded after retrying
+	Attempt int        // failures of this class so far in the call
+	Delay   int        // seconds waited before the next attempt
+	GaveUp  bool       // the error was fatal or its budget was exhausted
+}
+
+// jitterFloat returns a number in [0, 1). It is a variable so tests can make
+// jitter deterministic.
+var jitterFloat = rand.Float64
+
 // RetryWithBackoff retries fn with exponential backoff.
-// Delays: BaseDelay, BaseDelay*2, BaseDelay*4, BaseDelay*8, ...
-// Handles rate limit errors specially: waits for reset time and retries without incrementing attempt.
+// Delays: BaseDelay, BaseDelay*2, BaseDelay*4, BaseDelay*8, ... capped at
+// MaxDelay and spread by Jitter.
+//
+// Errors are classified first (see Classify), and each class has its own
+// budget: transient, overload and hang errors use MaxRetries; rate limit errors wait for the
+// reset time without consuming an attempt, up to MaxRateLimitWaits;
+// authentication failures are retried MaxAuthRetries times; cancellation is
+// never retried.
 func RetryWithBackoff(ctx context.Context, cfg RetryConfig, fn func() error) error {
 	if cfg.BaseDelay == 0 {
 		cfg.BaseDelay = 5
@@ -38,63 +63,139 @@ func RetryWithBackoff(ctx context.Context, cfg RetryConfig, fn func() error) err
 	}
 
 	rateLimitWaits := 0
+	authRetries := 0
+	failed := false
+
+	emit := func(ev RetryEvent) {
+		if cfg.OnEvent != nil {
+			cfg.OnEvent(ev)
+		}
+	}
 
 	for {
 		err := fn()
 		if err == nil {
+			if failed {
+				emit(RetryEvent{})
+			}
 			return nil
 		}
+		failed = true
 
-		// Check if this is a rate limit error
-		var rateLimitErr *RateLimitError
-		if errors.As(err, &rateLimitErr) {
-			rateLimitWaits++
-			if rateLimitWaits >= cfg.MaxRateLimitWaits {
-				return fmt.Errorf("max rate limit waits (%d) exceeded: %w", cfg.MaxRateLimitWaits, err)
+		class := Classify(err)
+		if ctx.Err() != nil {
+			class = ClassCanceled
+		}
+		switch class {
+		case ClassCanceled:
+			emit(RetryEvent{Class: class, Err: err, Attempt: 1, GaveUp: true})
+			if ctx.Err() != nil {
+				return ctx.Err()
 			}
+			return err
 
-			// Notify caller about rate limit
-			if cfg.OnRateLimit != nil {
-				cfg.OnRateLimit(rateLimitErr.Info)
+		case ClassAuth:
+			authRetries++
+			if authRetries > cfg.MaxAuthRetries {
+				emit(RetryEvent{Class: class, Err: err, Attempt: authRetries, GaveUp: true})
+				return err
 			}
 
-			// Wait for rate limit reset if parseable
-			if rateLimitErr.Info != nil && rateLimitErr.Info.Parseable {
-				waitErr := ratelimit.WaitForReset(ctx, rateLimitErr.Info)
-				if waitErr != nil {
-					return fmt.Errorf("rate limit wait cancelled: %w", waitErr)
-				}
-			} else {
-				// Fallback: 15 minute wait if time unparseable
-				select {
-				case <-ctx.Done():
-					return ctx.Err()
-				case <-time.After(15 * time.Minute):
-				}
+		case ClassRateLimit:
+			rateLimitWaits++
+			if rateLimitWaits >= cfg.MaxRateLimitWaits {
+				emit(RetryEvent{Class: class, Err: err, Attempt: rateLimitWaits, GaveUp: true})
+				return fmt.Errorf("max rate limit waits (%d) exceeded: %w", cfg.MaxRateLimitWaits, err)
+			}
+			if err := waitForRateLimit(ctx, cfg, err, rateLimitWaits, emit); err != nil {
+				return err
 			}
-
 			// Retry same attempt without incrementing
 			continue
 		}
 
-		// Not a rate limit error - normal retry logic
-		if attempt >= cfg.MaxRetries {
+		// Transient, overloaded, hung and retried auth errors back off
+		// exponentially; all but auth share the MaxRetries budget.
+		if class != ClassAuth && attempt >= cfg.MaxRetries {
+			emit(RetryEvent{Class: class, Err: err, Attempt: attempt + 1, GaveUp: true})
 			return fmt.Errorf("max retries (%d) exceeded: %w", cfg.MaxRetries, err)
 		}
 
+		wait := jittered(delay, cfg.Jitter)
 		if cfg.OnRetry != nil {
-			cfg.OnRetry(attempt, delay)
+			cfg.OnRetry(attempt, wait)
+		}
+		n := attempt + 1
+		if class == ClassAuth {
+			n = authRetries
 		}
+		emit(RetryEvent{Class: class, Err: err, Attempt: n, Delay: wait})
 
 		// Sleep with context awareness
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-time.After(time.Duration(delay) * time.Second):
+		case <-time.After(time.Duration(wait) * time.Second):
 		}
 
-		// Double the delay for next attempt
-		delay *= 2
+		delay = nextDelay(delay, cfg.MaxDelay)
 		attempt++
 	}
 }
+
+// waitForRateLimit notifies the caller and sleeps until the rate limit in
+// err resets, or 15 minutes when the reset time is unknown.
+func waitForRateLimit(ctx context.Context, cfg RetryConfig, err error, waits int, emit func(RetryEvent)) error {
+	var info *ratelimit.RateLimitInfo
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		info = rateLimitErr.Info
+	}
+
+	// Notify caller about rate limit
+	if cfg.OnRateLimit != nil {
+		cfg.OnRateLimit(info)
+	}
+
+	// Wait for rate limit reset if parseable
+	if info != nil && info.Parseable {
+		emit(RetryEvent{Class: ClassRateLimit, Err: err, Attempt: waits, Delay: max(0, int(time.Until(time.Unix(info.ResetEpoch, 0)).Seconds()))})
+		if waitErr := ratelimit.WaitForReset(ctx, info); waitErr != nil {
+			return fmt.Errorf("rate limit wait cancelled: %w", waitErr)
+		}
+		return nil
+	}
+
+	// Fallback: 15 minute wait if time unparseable
+	fallback := 15 * time.Minute
+	emit(RetryEvent{Class: ClassRateLimit, Err: err, Attempt: waits, Delay: int(fallback.Seconds())})
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(fallback):
+	}
+	return nil
+}
+
+// nextDelay doubles delay, capped at maxDelay when it is positive.
+func nextDelay(delay, maxDelay int) int {
+	delay *= 2
+	if maxDelay > 0 && delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+// jittered spreads delay by up to ±fraction of itself, never below one
+// second.
+func jittered(delay int, fraction float64) int {
+	if fraction <= 0 || delay <= 0 {
+		return delay
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	spread := float64(delay) * fraction
+	d := int(float64(delay) - spread + 2*spread*jitterFloat() + 0.5)
+	return max(1, d)
+}
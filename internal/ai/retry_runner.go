@@ -14,3 +14,10 @@ func (r *RetryRunner) Run(ctx context.Context, prompt string, outputPath string)
 		return r.Inner.Run(ctx, prompt, outputPath)
 	})
 }
+
+// SetModel forwards the model switch to the inner runner, if it supports it.
+func (r *RetryRunner) SetModel(model string) {
+	if ms, ok := r.Inner.(ModelSetter); ok {
+		ms.SetModel(model)
+	}
+}
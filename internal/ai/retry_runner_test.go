@@ -165,3 +165,21 @@ func TestRetryRunner_ImplementsAIRunner(t *testing.T) {
 	}
 	assert.NotNil(t, runner)
 }
+
+func TestRetryRunner_SetModelForwardsToInner(t *testing.T) {
+	inner := &ClaudeRunner{Model: "sonnet"}
+	runner := &RetryRunner{Inner: inner}
+
+	var ms ModelSetter = runner
+	ms.SetModel("opus")
+	assert.Equal(t, "opus", inner.Model)
+
+	// Inner runners without SetModel are left alone.
+	(&RetryRunner{Inner: &retryMockRunner{}}).SetModel("opus")
+}
+
+func TestCodexRunner_SetModel(t *testing.T) {
+	r := &CodexRunner{Model: "gpt-5-mini"}
+	r.SetModel("gpt-5")
+	assert.Contains(t, r.BuildArgs("p", "/tmp/o"), "gpt-5")
+}
@@ -3,6 +3,7 @@ package ai
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -731,3 +732,155 @@ func TestRetryWithBackoff_DefaultMaxRateLimitWaits(t *testing.T) {
 	assert.Contains(t, err.Error(), "max rate limit waits")
 	assert.Equal(t, 3, attempts, "default MaxRateLimitWaits should be 3")
 }
+
+func TestNextDelay(t *testing.T) {
+	assert.Equal(t, 10, nextDelay(5, 0), "no cap doubles")
+	assert.Equal(t, 10, nextDelay(5, 60))
+	assert.Equal(t, 60, nextDelay(40, 60), "capped at MaxDelay")
+	assert.Equal(t, 60, nextDelay(60, 60))
+}
+
+func TestJittered(t *testing.T) {
+	orig := jitterFloat
+	defer func() { jitterFloat = orig }()
+
+	jitterFloat = func() float64 { return 0 }
+	assert.Equal(t, 8, jittered(10, 0.2), "lowest jitter")
+	jitterFloat = func() float64 { return 0.999 }
+	assert.Equal(t, 12, jittered(10, 0.2), "highest jitter")
+	jitterFloat = func() float64 { return 0.5 }
+	assert.Equal(t, 10, jittered(10, 0.2))
+
+	assert.Equal(t, 10, jittered(10, 0), "no jitter")
+	jitterFloat = func() float64 { return 0 }
+	assert.Equal(t, 1, jittered(1, 1), "never below one second")
+}
+
+func TestRetryWithBackoff_JitterReportedToOnRetry(t *testing.T) {
+	orig := jitterFloat
+	defer func() { jitterFloat = orig }()
+	jitterFloat = func() float64 { return 0 }
+
+	var delays []int
+	cfg := RetryConfig{
+		MaxRetries: 3,
+		BaseDelay:  10,
+		Jitter:     0.5,
+		OnRetry:    func(attempt int, delay int) { delays = append(delays, delay) },
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_ = RetryWithBackoff(ctx, cfg, func() error { return errors.New("fail") })
+	assert.Equal(t, []int{5}, delays)
+}
+
+func TestRetryWithBackoff_AuthFailsFast(t *testing.T) {
+	var events []RetryEvent
+	calls := 0
+	authErr := &AuthError{UnderlyingErr: errors.New("claude command failed: exit status 1")}
+	cfg := RetryConfig{
+		MaxRetries: 5,
+		BaseDelay:  1,
+		OnEvent:    func(ev RetryEvent) { events = append(events, ev) },
+	}
+
+	err := RetryWithBackoff(context.Background(), cfg, func() error {
+		calls++
+		return authErr
+	})
+
+	assert.Equal(t, 1, calls, "authentication failures are not retried by default")
+	assert.ErrorIs(t, err, authErr)
+	assert.Equal(t, []RetryEvent{{Class: ClassAuth, Err: authErr, Attempt: 1, GaveUp: true}}, events)
+}
+
+func TestRetryWithBackoff_AuthBudget(t *testing.T) {
+	calls := 0
+	cfg := RetryConfig{MaxRetries: 5, BaseDelay: 1, MaxAuthRetries: 1}
+
+	err := RetryWithBackoff(context.Background(), cfg, func() error {
+		calls++
+		return &AuthError{}
+	})
+
+	assert.Equal(t, 2, calls, "one retry after the first failure")
+	assert.Equal(t, ClassAuth, Classify(err))
+}
+
+func TestRetryWithBackoff_CanceledErrorNotRetried(t *testing.T) {
+	calls := 0
+	err := RetryWithBackoff(context.Background(), RetryConfig{MaxRetries: 5, BaseDelay: 1}, func() error {
+		calls++
+		return fmt.Errorf("claude command failed: %w", context.Canceled)
+	})
+
+	assert.Equal(t, 1, calls)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRetryWithBackoff_EventsRecordRecovery(t *testing.T) {
+	var events []RetryEvent
+	failure := errors.New("exit status 1")
+	calls := 0
+	cfg := RetryConfig{
+		MaxRetries: 3,
+		BaseDelay:  1,
+		OnEvent:    func(ev RetryEvent) { events = append(events, ev) },
+	}
+
+	err := RetryWithBackoff(context.Background(), cfg, func() error {
+		calls++
+		if calls == 1 {
+			return failure
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []RetryEvent{
+		{Class: ClassTransient, Err: failure, Attempt: 1, Delay: 1},
+		{},
+	}, events)
+}
+
+func TestRetryWithBackoff_EventsRecordGivingUp(t *testing.T) {
+	var events []RetryEvent
+	cfg := RetryConfig{
+		MaxRetries: 0,
+		OnEvent:    func(ev RetryEvent) { events = append(events, ev) },
+	}
+
+	err := RetryWithBackoff(context.Background(), cfg, func() error { return errors.New("boom") })
+
+	require.Error(t, err)
+	require.Len(t, events, 1)
+	assert.True(t, events[0].GaveUp)
+	assert.Equal(t, ClassTransient, events[0].Class)
+}
+
+func TestRetryWithBackoff_OverloadedBacksOff(t *testing.T) {
+	overloaded := &RateLimitError{Info: &ratelimit.RateLimitInfo{Detected: true, Overloaded: true}}
+	rateLimitCalls := 0
+	var delays []int
+	calls := 0
+	cfg := RetryConfig{
+		MaxRetries:  2,
+		BaseDelay:   1,
+		OnRetry:     func(attempt int, delay int) { delays = append(delays, delay) },
+		OnRateLimit: func(*ratelimit.RateLimitInfo) { rateLimitCalls++ },
+	}
+
+	err := RetryWithBackoff(context.Background(), cfg, func() error {
+		calls++
+		if calls == 1 {
+			return overloaded
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, delays, "overloads back off instead of waiting for a reset")
+	assert.Zero(t, rateLimitCalls)
+	assert.Equal(t, "API overloaded", overloaded.Error())
+}
@@ -12,19 +12,35 @@ type AIRunner interface {
 	Run(ctx context.Context, prompt string, outputPath string) error
 }
 
-// RateLimitError is returned when a rate limit is detected in AI output.
+// ModelSetter is implemented by runners whose model can be switched between
+// invocations, e.g. when the implementation model is escalated.
+type ModelSetter interface {
+	SetModel(model string)
+}
+
+// RateLimitError is returned when a rate limit or an API overload is
+// detected in AI output.
 type RateLimitError struct {
 	Info          *ratelimit.RateLimitInfo
 	UnderlyingErr error
 }
 
 func (e *RateLimitError) Error() string {
+	if e.Overloaded() {
+		return "API overloaded"
+	}
 	if e.Info != nil && e.Info.Parseable {
 		return fmt.Sprintf("rate limit detected (resets at %s)", e.Info.ResetHuman)
 	}
 	return "rate limit detected (reset time unknown)"
 }
 
+// Overloaded reports whether the error is a temporary capacity error rather
+// than a usage limit.
+func (e *RateLimitError) Overloaded() bool {
+	return e.Info != nil && e.Info.Overloaded
+}
+
 func (e *RateLimitError) Unwrap() error {
 	return e.UnderlyingErr
 }
@@ -0,0 +1,32 @@
+package ai
+
+import "fmt"
+
+// claudeThinkingTokens maps a reasoning-effort level to the extended
+// thinking budget passed to the claude CLI via MAX_THINKING_TOKENS.
+// The claude CLI has no reasoning-effort flag, so the budget stands in for it.
+var claudeThinkingTokens = map[string]int{
+	"minimal": 1024,
+	"low":     4000,
+	"medium":  10000,
+	"high":    31999,
+}
+
+// claudeEnv returns the extra environment for a claude invocation with the
+// given reasoning effort, or nil when the effort is unset or unknown.
+func claudeEnv(effort string) []string {
+	tokens, ok := claudeThinkingTokens[effort]
+	if !ok {
+		return nil
+	}
+	return []string{fmt.Sprintf("MAX_THINKING_TOKENS=%d", tokens)}
+}
+
+// codexEffortArgs returns the codex config override for the given reasoning
+// effort, or nil when the effort is unset.
+func codexEffortArgs(effort string) []string {
+	if effort == "" {
+		return nil
+	}
+	return []string{"-c", fmt.Sprintf("model_reasoning_effort=%q", effort)}
+}
@@ -0,0 +1,54 @@
+package ai
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaudeEnv(t *testing.T) {
+	assert.Equal(t, []string{"MAX_THINKING_TOKENS=4000"}, claudeEnv("low"))
+	assert.Equal(t, []string{"MAX_THINKING_TOKENS=31999"}, claudeEnv("high"))
+	assert.Nil(t, claudeEnv(""))
+	assert.Nil(t, claudeEnv("unknown"))
+}
+
+func TestCodexRunner_BuildArgs_ReasoningEffort(t *testing.T) {
+	r := CodexRunner{Model: "gpt-5", ReasoningEffort: "high"}
+	args := r.BuildArgs("prompt", "/tmp/out.txt")
+
+	idx := indexOf(args, "-c")
+	require.GreaterOrEqual(t, idx, 0)
+	assert.Equal(t, `model_reasoning_effort="high"`, args[idx+1])
+	assert.Equal(t, "prompt", args[len(args)-1], "prompt must stay last")
+
+	plain := CodexRunner{Model: "gpt-5"}
+	assert.NotContains(t, plain.BuildArgs("prompt", "/tmp/out.txt"), "-c")
+}
+
+func TestClaudeRunnerRun_PassesThinkingBudget(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows")
+	}
+
+	tmpDir := t.TempDir()
+	fakeScript := filepath.Join(tmpDir, "claude")
+	scriptContent := `#!/bin/sh
+echo "{\"type\":\"result\",\"result\":\"budget=$MAX_THINKING_TOKENS\"}"
+`
+	require.NoError(t, os.WriteFile(fakeScript, []byte(scriptContent), 0755))
+	t.Setenv("PATH", tmpDir+":"+os.Getenv("PATH"))
+
+	outputPath := filepath.Join(tmpDir, "output.txt")
+	r := &ClaudeRunner{Model: "test-model", MaxTurns: 1, ReasoningEffort: "medium"}
+	require.NoError(t, r.Run(context.Background(), "prompt", outputPath))
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "budget=10000")
+}
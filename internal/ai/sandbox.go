@@ -0,0 +1,107 @@
+package ai
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Sandbox wraps AI CLI invocations in an isolating command such as firejail,
+// bwrap or docker run, so the model cannot touch files outside the project.
+//
+// Command is split on whitespace (no shell quoting) and may contain two
+// placeholders:
+//   - {workdir} expands to Workdir, for mounting the project
+//     ("-v {workdir}:{workdir} -w {workdir}", "--bind {workdir} {workdir}").
+//   - {env} expands to "--env NAME" for each passed-through variable
+//     (docker/podman syntax); other sandboxes inherit the environment.
+//
+// The wrapped CLI and its arguments are appended after Command.
+//
+// Container, when set, runs the CLI in a container instead (see
+// Container); Command is then ignored.
+type Sandbox struct {
+	Command   string
+	Workdir   string   // project directory; defaults to the current directory
+	Env       []string // names of host variables passed into the sandbox
+	Container *Container
+}
+
+// Wrap returns the program and arguments that run name with args inside the
+// sandbox. extraEnv names runner-specific variables (e.g. MAX_THINKING_TOKENS)
+// that are passed through in addition to s.Env.
+func (s *Sandbox) Wrap(name string, args []string, extraEnv []string) (string, []string) {
+	workdir := s.workdir()
+	var envFlags []string
+	for _, v := range append(append([]string{}, s.Env...), extraEnv...) {
+		envFlags = append(envFlags, "--env", v)
+	}
+
+	var argv []string
+	for _, field := range strings.Fields(s.Command) {
+		switch field {
+		case "{env}":
+			argv = append(argv, envFlags...)
+		default:
+			argv = append(argv, strings.ReplaceAll(field, "{workdir}", workdir))
+		}
+	}
+	if len(argv) == 0 {
+		return name, args
+	}
+	argv = append(argv, name)
+	argv = append(argv, args...)
+	return argv[0], argv[1:]
+}
+
+// Program returns the sandbox executable, or "" when Command is empty.
+func (s *Sandbox) Program() string {
+	if s.Container != nil {
+		return s.Container.Runtime
+	}
+	fields := strings.Fields(s.Command)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+func (s *Sandbox) workdir() string {
+	if s.Workdir != "" {
+		return s.Workdir
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+	return wd
+}
+
+// newCommand builds the exec.Cmd for an AI CLI, wrapping it in sb when set.
+// env holds extra KEY=VALUE entries added to the inherited environment.
+// Cancelling ctx kills the CLI and every process it started.
+func newCommand(ctx context.Context, sb *Sandbox, name string, args []string, env []string) *exec.Cmd {
+	names := make([]string, 0, len(env))
+	for _, e := range env {
+		k, _, _ := strings.Cut(e, "=")
+		names = append(names, k)
+	}
+	var cmd *exec.Cmd
+	switch {
+	case sb != nil && sb.Container != nil:
+		cmd = sb.Container.command(ctx, name, args, names)
+	case sb != nil && sb.Program() != "":
+		prog, argv := sb.Wrap(name, args, names)
+		cmd = exec.CommandContext(ctx, prog, argv...)
+		cmd.Dir = sb.workdir()
+		setProcessTree(cmd)
+	default:
+		cmd = exec.CommandContext(ctx, name, args...)
+		setProcessTree(cmd)
+	}
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmd
+}
@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandbox_Wrap(t *testing.T) {
+	sb := &Sandbox{
+		Command: "docker run --rm -i {env} -v {workdir}:{workdir} -w {workdir} agent-image",
+		Workdir: "/src/project",
+		Env:     []string{"ANTHROPIC_API_KEY"},
+	}
+
+	prog, args := sb.Wrap("claude", []string{"--print", "--", "prompt"}, []string{"MAX_THINKING_TOKENS"})
+	assert.Equal(t, "docker", prog)
+	assert.Equal(t, []string{
+		"run", "--rm", "-i",
+		"--env", "ANTHROPIC_API_KEY", "--env", "MAX_THINKING_TOKENS",
+		"-v", "/src/project:/src/project", "-w", "/src/project", "agent-image",
+		"claude", "--print", "--", "prompt",
+	}, args)
+}
+
+func TestSandbox_WrapWithoutPlaceholders(t *testing.T) {
+	sb := &Sandbox{Command: "firejail --quiet"}
+	prog, args := sb.Wrap("codex", []string{"exec", "prompt"}, nil)
+	assert.Equal(t, "firejail", prog)
+	assert.Equal(t, []string{"--quiet", "codex", "exec", "prompt"}, args)
+}
+
+func TestSandbox_EmptyCommandRunsDirectly(t *testing.T) {
+	sb := &Sandbox{Command: "  "}
+	prog, args := sb.Wrap("claude", []string{"x"}, nil)
+	assert.Equal(t, "claude", prog)
+	assert.Equal(t, []string{"x"}, args)
+	assert.Equal(t, "", sb.Program())
+}
+
+func TestClaudeRunnerRun_InsideSandbox(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows")
+	}
+
+	tmpDir := t.TempDir()
+	// The fake sandbox records its arguments and working directory, then
+	// emits a claude stream-json result instead of running the wrapped CLI.
+	fakeSandbox := filepath.Join(tmpDir, "fakejail")
+	scriptContent := `#!/bin/sh
+echo "{\"type\":\"result\",\"result\":\"dir=$(pwd) args=$*\"}"
+`
+	require.NoError(t, os.WriteFile(fakeSandbox, []byte(scriptContent), 0755))
+
+	workdir := filepath.Join(tmpDir, "project")
+	require.NoError(t, os.Mkdir(workdir, 0755))
+	workdir, err := filepath.EvalSymlinks(workdir)
+	require.NoError(t, err)
+
+	outputPath := filepath.Join(tmpDir, "output.txt")
+	r := &ClaudeRunner{
+		Model:    "test-model",
+		MaxTurns: 1,
+		Sandbox:  &Sandbox{Command: fakeSandbox + " --bind {workdir}", Workdir: workdir},
+	}
+	require.NoError(t, r.Run(context.Background(), "prompt", outputPath))
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "dir="+workdir)
+	assert.Contains(t, string(data), "args=--bind "+workdir+" claude --print")
+}
@@ -0,0 +1,141 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// OverloadCooldown is how long a provider is avoided after an overload
+	// error.
+	OverloadCooldown = time.Minute
+	// UnknownResetCooldown is how long a provider is avoided after a rate
+	// limit whose reset time could not be parsed.
+	UnknownResetCooldown = 15 * time.Minute
+)
+
+// Throttle tracks which AI providers are rate limited or overloaded, and
+// until when. One Throttle is shared by every runner so a limit hit in one
+// phase is respected by the others. It is safe for concurrent use.
+type Throttle struct {
+	mu    sync.Mutex
+	until map[string]time.Time
+	now   func() time.Time
+}
+
+// NewThrottle returns a Throttle with no provider limited.
+func NewThrottle() *Throttle {
+	return &Throttle{until: map[string]time.Time{}, now: time.Now}
+}
+
+// Limit marks provider unavailable until the given time. An earlier limit
+// never shortens a later one.
+func (t *Throttle) Limit(provider string, until time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if until.After(t.until[provider]) {
+		t.until[provider] = until
+	}
+}
+
+// LimitedUntil returns when provider becomes available again, or the zero
+// time if it is available now.
+func (t *Throttle) LimitedUntil(provider string) time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	until := t.until[provider]
+	if !until.After(t.now()) {
+		return time.Time{}
+	}
+	return until
+}
+
+// record limits provider according to a rate limit or overload error and
+// reports whether err was one.
+func (t *Throttle) record(provider string, err error) bool {
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		return false
+	}
+	now := t.now()
+	switch info := rateLimitErr.Info; {
+	case rateLimitErr.Overloaded():
+		t.Limit(provider, now.Add(OverloadCooldown))
+	case info != nil && info.Parseable:
+		t.Limit(provider, time.Unix(info.ResetEpoch, 0))
+	default:
+		t.Limit(provider, now.Add(UnknownResetCooldown))
+	}
+	return true
+}
+
+// ThrottledRunner runs Inner unless its provider is throttled. While the
+// provider is rate limited or overloaded, work is rerouted to Alternate
+// (another provider) if that one is available, and otherwise delayed until
+// the limit lifts. Without an Alternate, a limit hit by Inner is returned
+// so the surrounding RetryRunner can wait it out.
+type ThrottledRunner struct {
+	Inner    AIRunner
+	Provider string
+
+	// Alternate, when set, runs the work while Provider is throttled.
+	Alternate         AIRunner
+	AlternateProvider string
+
+	Throttle *Throttle
+
+	// OnReroute is called when work is sent to the alternate provider.
+	OnReroute func(from, to string, until time.Time)
+	// OnDelay is called before waiting for a throttled provider.
+	OnDelay func(provider string, until time.Time)
+}
+
+// Run executes the prompt on the first provider that is not throttled.
+func (r *ThrottledRunner) Run(ctx context.Context, prompt string, outputPath string) error {
+	if until := r.Throttle.LimitedUntil(r.Provider); !until.IsZero() {
+		if r.alternateAvailable() {
+			return r.reroute(ctx, prompt, outputPath, until)
+		}
+		if r.OnDelay != nil {
+			r.OnDelay(r.Provider, until)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Until(until)):
+		}
+	}
+
+	err := r.Inner.Run(ctx, prompt, outputPath)
+	if err == nil || !r.Throttle.record(r.Provider, err) {
+		return err
+	}
+	if r.alternateAvailable() {
+		return r.reroute(ctx, prompt, outputPath, r.Throttle.LimitedUntil(r.Provider))
+	}
+	return err
+}
+
+func (r *ThrottledRunner) alternateAvailable() bool {
+	return r.Alternate != nil && r.AlternateProvider != r.Provider &&
+		r.Throttle.LimitedUntil(r.AlternateProvider).IsZero()
+}
+
+func (r *ThrottledRunner) reroute(ctx context.Context, prompt, outputPath string, until time.Time) error {
+	if r.OnReroute != nil {
+		r.OnReroute(r.Provider, r.AlternateProvider, until)
+	}
+	err := r.Alternate.Run(ctx, prompt, outputPath)
+	r.Throttle.record(r.AlternateProvider, err)
+	return err
+}
+
+// SetModel forwards the model switch to the primary runner, if it supports
+// it. The alternate keeps its own model.
+func (r *ThrottledRunner) SetModel(model string) {
+	if ms, ok := r.Inner.(ModelSetter); ok {
+		ms.SetModel(model)
+	}
+}
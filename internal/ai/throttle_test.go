@@ -0,0 +1,146 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/ratelimit"
+)
+
+// Compile-time interface checks.
+var (
+	_ AIRunner    = (*ThrottledRunner)(nil)
+	_ ModelSetter = (*ThrottledRunner)(nil)
+)
+
+func rateLimited(reset time.Time) error {
+	return &RateLimitError{Info: &ratelimit.RateLimitInfo{Detected: true, Parseable: true, ResetEpoch: reset.Unix()}}
+}
+
+func TestThrottle_LimitAndExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	th := NewThrottle()
+	th.now = func() time.Time { return now }
+
+	assert.True(t, th.LimitedUntil("claude").IsZero())
+
+	th.Limit("claude", now.Add(time.Hour))
+	th.Limit("claude", now.Add(time.Minute))
+	assert.Equal(t, now.Add(time.Hour), th.LimitedUntil("claude"), "a shorter limit does not override")
+	assert.True(t, th.LimitedUntil("codex").IsZero())
+
+	now = now.Add(2 * time.Hour)
+	assert.True(t, th.LimitedUntil("claude").IsZero(), "limits expire")
+}
+
+func TestThrottle_Record(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	th := NewThrottle()
+	th.now = func() time.Time { return now }
+
+	assert.False(t, th.record("claude", errors.New("exit status 1")))
+	assert.False(t, th.record("claude", nil))
+
+	assert.True(t, th.record("claude", &RateLimitError{Info: &ratelimit.RateLimitInfo{Detected: true, Overloaded: true}}))
+	assert.Equal(t, now.Add(OverloadCooldown), th.LimitedUntil("claude"))
+
+	assert.True(t, th.record("codex", &RateLimitError{}))
+	assert.Equal(t, now.Add(UnknownResetCooldown), th.LimitedUntil("codex"))
+
+	reset := now.Add(3 * time.Hour)
+	assert.True(t, th.record("claude", rateLimited(reset)))
+	assert.True(t, reset.Equal(th.LimitedUntil("claude")))
+}
+
+func TestThrottledRunner_ReroutesOnRateLimit(t *testing.T) {
+	th := NewThrottle()
+	primary := &retryMockRunner{results: []error{rateLimited(time.Now().Add(time.Hour))}}
+	alternate := &retryMockRunner{}
+	var rerouted []string
+	r := &ThrottledRunner{
+		Inner: primary, Provider: "claude",
+		Alternate: alternate, AlternateProvider: "codex",
+		Throttle:  th,
+		OnReroute: func(from, to string, until time.Time) { rerouted = append(rerouted, from+"->"+to) },
+	}
+
+	require.NoError(t, r.Run(context.Background(), "p", "out"))
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 1, alternate.calls)
+
+	// While claude is limited, work goes straight to codex.
+	require.NoError(t, r.Run(context.Background(), "p", "out"))
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 2, alternate.calls)
+	assert.Equal(t, []string{"claude->codex", "claude->codex"}, rerouted)
+}
+
+func TestThrottledRunner_NoAlternateReturnsError(t *testing.T) {
+	limit := rateLimited(time.Now().Add(time.Hour))
+	primary := &retryMockRunner{results: []error{limit}}
+	r := &ThrottledRunner{Inner: primary, Provider: "claude", Throttle: NewThrottle()}
+
+	err := r.Run(context.Background(), "p", "out")
+	assert.ErrorIs(t, err, limit, "the RetryRunner waits for the reset")
+}
+
+func TestThrottledRunner_BothLimitedDelays(t *testing.T) {
+	th := NewThrottle()
+	th.Limit("claude", time.Now().Add(50*time.Millisecond))
+	th.Limit("codex", time.Now().Add(time.Hour))
+	primary := &retryMockRunner{}
+	alternate := &retryMockRunner{}
+	var delayed string
+	r := &ThrottledRunner{
+		Inner: primary, Provider: "claude",
+		Alternate: alternate, AlternateProvider: "codex",
+		Throttle: th,
+		OnDelay:  func(provider string, until time.Time) { delayed = provider },
+	}
+
+	start := time.Now()
+	require.NoError(t, r.Run(context.Background(), "p", "out"))
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	assert.Equal(t, "claude", delayed)
+	assert.Equal(t, 1, primary.calls)
+	assert.Zero(t, alternate.calls)
+}
+
+func TestThrottledRunner_DelayCancelled(t *testing.T) {
+	th := NewThrottle()
+	th.Limit("claude", time.Now().Add(time.Hour))
+	r := &ThrottledRunner{Inner: &retryMockRunner{}, Provider: "claude", Throttle: th}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, r.Run(ctx, "p", "out"), context.Canceled)
+}
+
+func TestThrottledRunner_AlternateLimitRecorded(t *testing.T) {
+	th := NewThrottle()
+	th.Limit("claude", time.Now().Add(time.Hour))
+	overloaded := &RateLimitError{Info: &ratelimit.RateLimitInfo{Detected: true, Overloaded: true}}
+	r := &ThrottledRunner{
+		Inner: &retryMockRunner{}, Provider: "claude",
+		Alternate: &retryMockRunner{results: []error{overloaded}}, AlternateProvider: "codex",
+		Throttle: th,
+	}
+
+	assert.ErrorIs(t, r.Run(context.Background(), "p", "out"), overloaded)
+	assert.False(t, th.LimitedUntil("codex").IsZero())
+}
+
+func TestThrottledRunner_SetModelForwardsToPrimary(t *testing.T) {
+	primary := &ClaudeRunner{Model: "opus"}
+	alternate := &CodexRunner{Model: "gpt-5"}
+	r := &ThrottledRunner{Inner: primary, Provider: "claude", Alternate: alternate, AlternateProvider: "codex", Throttle: NewThrottle()}
+
+	r.SetModel("sonnet")
+	assert.Equal(t, "sonnet", primary.Model)
+	assert.Equal(t, "gpt-5", alternate.Model)
+}
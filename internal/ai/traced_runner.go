@@ -0,0 +1,60 @@
+package ai
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/tracing"
+)
+
+// TracedRunner wraps an AIRunner with a tracing span per invocation. Wrap
+// the raw CLI runner (inside any RetryRunner) so every attempt is recorded.
+// Without a span in the context it simply delegates.
+type TracedRunner struct {
+	Inner    AIRunner
+	Provider string // claude or codex
+	Model    string
+	Phase    string // IMPL, VAL, CROSS, ...
+}
+
+// Run delegates to the inner runner inside an "ai.run" span carrying the
+// provider, model, duration and, when the CLI reports them, token usage and
+// cost.
+func (r *TracedRunner) Run(ctx context.Context, prompt string, outputPath string) error {
+	ctx, span := tracing.Start(ctx, "ai.run",
+		tracing.String("gen_ai.system", r.Provider),
+		tracing.String("gen_ai.request.model", r.Model),
+		tracing.String("ralph.phase", r.Phase),
+	)
+	start := time.Now()
+	err := r.Inner.Run(ctx, prompt, outputPath)
+	if span == nil {
+		return err
+	}
+
+	span.SetAttributes(tracing.Int("ralph.duration_ms", int(time.Since(start).Milliseconds())))
+	if raw, readErr := os.ReadFile(outputPath + ".stream.json"); readErr == nil {
+		if usage, ok := parser.ParseStreamJSONUsage(string(raw)); ok {
+			span.SetAttributes(
+				tracing.Int("gen_ai.usage.input_tokens", usage.InputTokens),
+				tracing.Int("gen_ai.usage.output_tokens", usage.OutputTokens),
+			)
+		}
+		if cost, ok := parser.ParseStreamJSONCost(string(raw)); ok {
+			span.SetAttributes(tracing.Float("ralph.cost_usd", cost))
+		}
+	}
+	span.RecordError(err)
+	span.End()
+	return err
+}
+
+// SetModel forwards the model switch to the inner runner, if it supports it.
+func (r *TracedRunner) SetModel(model string) {
+	r.Model = model
+	if ms, ok := r.Inner.(ModelSetter); ok {
+		ms.SetModel(model)
+	}
+}
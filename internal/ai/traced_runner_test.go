@@ -0,0 +1,111 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/tracing"
+)
+
+// spanCollector is an in-memory tracing.Exporter.
+type spanCollector struct {
+	spans []tracing.SpanData
+}
+
+func (c *spanCollector) Export(_ context.Context, spans []tracing.SpanData) error {
+	c.spans = append(c.spans, spans...)
+	return nil
+}
+
+// Compile-time interface checks.
+var (
+	_ AIRunner    = (*TracedRunner)(nil)
+	_ ModelSetter = (*TracedRunner)(nil)
+)
+
+func attrMap(attrs []tracing.Attr) map[string]any {
+	m := make(map[string]any, len(attrs))
+	for _, a := range attrs {
+		m[a.Key] = a.Value
+	}
+	return m
+}
+
+func TestTracedRunner_RecordsSpanWithUsage(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "out.txt")
+	inner := &mockRunner{runFunc: func(ctx context.Context, prompt, out string) error {
+		stream := `{"type":"result","total_cost_usd":0.12,"usage":{"input_tokens":100,"output_tokens":20}}`
+		return os.WriteFile(out+".stream.json", []byte(stream), 0644)
+	}}
+	exp := &spanCollector{}
+	tr := tracing.NewTracer(exp)
+	ctx, root := tr.Start(context.Background(), "session")
+
+	r := &TracedRunner{Inner: inner, Provider: "claude", Model: "opus", Phase: "IMPL"}
+	require.NoError(t, r.Run(ctx, "prompt", outputPath))
+	root.End()
+	require.NoError(t, tr.Shutdown(context.Background()))
+
+	require.Len(t, exp.spans, 2)
+	span := exp.spans[0]
+	assert.Equal(t, "ai.run", span.Name)
+	assert.Equal(t, exp.spans[1].SpanID, span.ParentID)
+	attrs := attrMap(span.Attrs)
+	assert.Equal(t, "claude", attrs["gen_ai.system"])
+	assert.Equal(t, "opus", attrs["gen_ai.request.model"])
+	assert.Equal(t, "IMPL", attrs["ralph.phase"])
+	assert.Equal(t, int64(100), attrs["gen_ai.usage.input_tokens"])
+	assert.Equal(t, int64(20), attrs["gen_ai.usage.output_tokens"])
+	assert.InDelta(t, 0.12, attrs["ralph.cost_usd"], 1e-9)
+	assert.Contains(t, attrs, "ralph.duration_ms")
+	assert.False(t, span.Failed)
+}
+
+func TestTracedRunner_RecordsError(t *testing.T) {
+	inner := &mockRunner{runFunc: func(ctx context.Context, prompt, out string) error {
+		return errors.New("cli crashed")
+	}}
+	exp := &spanCollector{}
+	tr := tracing.NewTracer(exp)
+	ctx, root := tr.Start(context.Background(), "session")
+
+	r := &TracedRunner{Inner: inner, Provider: "codex", Model: "gpt-5"}
+	err := r.Run(ctx, "prompt", filepath.Join(t.TempDir(), "out.txt"))
+	root.End()
+	require.NoError(t, tr.Shutdown(context.Background()))
+
+	assert.EqualError(t, err, "cli crashed")
+	require.NotEmpty(t, exp.spans)
+	assert.True(t, exp.spans[0].Failed)
+	assert.Equal(t, "cli crashed", exp.spans[0].Error)
+	assert.NotContains(t, attrMap(exp.spans[0].Attrs), "gen_ai.usage.input_tokens")
+}
+
+func TestTracedRunner_WithoutTracing(t *testing.T) {
+	calls := 0
+	inner := &mockRunner{runFunc: func(ctx context.Context, prompt, out string) error {
+		calls++
+		assert.Nil(t, tracing.SpanFromContext(ctx))
+		return nil
+	}}
+	r := &TracedRunner{Inner: inner}
+	require.NoError(t, r.Run(context.Background(), "prompt", filepath.Join(t.TempDir(), "out.txt")))
+	assert.Equal(t, 1, calls)
+}
+
+func TestTracedRunner_SetModel(t *testing.T) {
+	inner := &CodexRunner{Model: "gpt-5"}
+	r := &TracedRunner{Inner: inner, Model: "gpt-5"}
+	r.SetModel("o3")
+	assert.Equal(t, "o3", r.Model)
+	assert.Equal(t, "o3", inner.Model)
+
+	// Inner runners without SetModel are left alone.
+	(&TracedRunner{Inner: &mockRunner{}}).SetModel("opus")
+}
@@ -0,0 +1,109 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// hangTailLines is how many of the last output lines a hang report keeps.
+const hangTailLines = 20
+
+// HangReport is the diagnostic snapshot taken when the watchdog kills an
+// AI CLI that produced no output and showed no process activity for the
+// hang timeout. It is saved as JSON next to the output.
+type HangReport struct {
+	PID            int       `json:"pid"`
+	KilledAt       time.Time `json:"killed_at"`
+	RuntimeSeconds int       `json:"runtime_seconds"`
+	IdleSeconds    int       `json:"idle_seconds"` // without output or process activity
+	LastOutput     []string  `json:"last_output"`
+	Path           string    `json:"-"` // where the report was saved, "" when it was not
+}
+
+// HangError is returned when the watchdog killed a hung AI CLI. It is
+// classified as ClassHung and retried like a transient failure.
+type HangError struct {
+	Report HangReport
+	Err    error // the CLI's exit error
+}
+
+func (e *HangError) Error() string {
+	msg := fmt.Sprintf("AI CLI hung: no output or process activity for %ds, killed after %ds",
+		e.Report.IdleSeconds, e.Report.RuntimeSeconds)
+	if e.Report.Path != "" {
+		msg += "; diagnostics in " + e.Report.Path
+	}
+	return msg
+}
+
+func (e *HangError) Unwrap() error {
+	return e.Err
+}
+
+// hangWatch receives the report of a process the watchdog killed, so the
+// runner can return a HangError once the process has exited.
+type hangWatch struct {
+	mu     sync.Mutex
+	report *HangReport
+}
+
+// record is the MonitorConfig.OnHang callback.
+func (w *hangWatch) record(r HangReport) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.report = &r
+}
+
+// err returns runErr, wrapped in a HangError when the watchdog killed the
+// process.
+func (w *hangWatch) err(runErr error) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.report == nil {
+		return runErr
+	}
+	return &HangError{Report: *w.report, Err: runErr}
+}
+
+// newHangReport builds the report of the process pid, reading the last
+// lines of its output, and saves it to snapshotPath unless that is "".
+func newHangReport(pid int, runtime, idle time.Duration, outputPath, snapshotPath string) HangReport {
+	r := HangReport{
+		PID:            pid,
+		KilledAt:       time.Now(),
+		RuntimeSeconds: int(runtime.Seconds()),
+		IdleSeconds:    int(idle.Seconds()),
+		LastOutput:     tailLines(outputPath, hangTailLines),
+	}
+	if snapshotPath == "" {
+		return r
+	}
+	if data, err := json.MarshalIndent(r, "", "  "); err == nil {
+		if os.WriteFile(snapshotPath, append(data, '\n'), 0644) == nil {
+			r.Path = snapshotPath
+		}
+	}
+	return r
+}
+
+// tailLines returns the last n non-empty lines of the file at path.
+func tailLines(path string, n int) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
@@ -0,0 +1,107 @@
+package ai
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startGroup starts a shell script in its own process group.
+func startGroup(t *testing.T, script string) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command("sh", "-c", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		_ = cmd.Wait()
+	})
+	return cmd
+}
+
+func TestProcessActivity(t *testing.T) {
+	busy := startGroup(t, "while :; do :; done")
+	first, ok := processActivity(busy.Process.Pid)
+	require.True(t, ok)
+	assert.Eventually(t, func() bool {
+		now, _ := processActivity(busy.Process.Pid)
+		return now > first
+	}, 5*time.Second, 50*time.Millisecond, "a busy loop uses CPU")
+
+	_, ok = processActivity(1 << 30)
+	assert.False(t, ok, "no such process group")
+}
+
+func TestMonitorProcess_KillsHungProcess(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "output.log")
+	require.NoError(t, os.WriteFile(output, []byte("working\n"), 0644))
+	idle := startGroup(t, "sleep 30")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var hang hangWatch
+	done := make(chan struct{})
+	go func() {
+		MonitorProcess(ctx, cancel, MonitorConfig{
+			OutputPath:   output,
+			TickInterval: 50 * time.Millisecond,
+			HangTimeout:  1,
+			PID:          idle.Process.Pid,
+			SnapshotPath: filepath.Join(dir, "output.hang.json"),
+			OnHang:       hang.record,
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the watchdog did not fire")
+	}
+	assert.Error(t, ctx.Err(), "the process context is cancelled")
+	err := hang.err(nil)
+	var hangErr *HangError
+	require.ErrorAs(t, err, &hangErr)
+	assert.Equal(t, []string{"working"}, hangErr.Report.LastOutput)
+	assert.FileExists(t, filepath.Join(dir, "output.hang.json"))
+}
+
+func TestMonitorProcess_BusyProcessIsNotHung(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "output.log")
+	require.NoError(t, os.WriteFile(output, nil, 0644))
+	busy := startGroup(t, "while :; do :; done")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+	var hang hangWatch
+	MonitorProcess(ctx, cancel, MonitorConfig{
+		OutputPath:   output,
+		TickInterval: 50 * time.Millisecond,
+		HangTimeout:  1,
+		PID:          busy.Process.Pid,
+		OnHang:       hang.record,
+	})
+	assert.NoError(t, hang.err(nil), "CPU use without output is not a hang")
+}
+
+func TestAmazonQRunnerRun_HungCLI(t *testing.T) {
+	dir := fakeCLI(t, "q", "echo 'Thinking...'\nexec sleep 30\n")
+	outputPath := filepath.Join(dir, "output.txt")
+
+	start := time.Now()
+	err := (&AmazonQRunner{HangTimeout: 1}).Run(context.Background(), "prompt", outputPath)
+	assert.Less(t, time.Since(start), 10*time.Second, "the hung CLI is killed")
+	var hangErr *HangError
+	require.ErrorAs(t, err, &hangErr)
+	assert.Equal(t, ClassHung, Classify(err))
+	assert.Equal(t, []string{"Thinking..."}, hangErr.Report.LastOutput)
+	assert.FileExists(t, outputPath+".hang.json")
+}
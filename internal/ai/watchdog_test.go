@@ -0,0 +1,54 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHangReport(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "output.txt.stream.json")
+	var lines []string
+	for i := 1; i <= 30; i++ {
+		lines = append(lines, "line "+string(rune('a'+i%26)))
+	}
+	require.NoError(t, os.WriteFile(output, []byte(strings.Join(lines, "\n")+"\n\n"), 0644))
+	snapshot := filepath.Join(dir, "output.txt.hang.json")
+
+	r := newHangReport(42, 10*time.Minute, 5*time.Minute, output, snapshot)
+	assert.Equal(t, 42, r.PID)
+	assert.Equal(t, 600, r.RuntimeSeconds)
+	assert.Equal(t, 300, r.IdleSeconds)
+	assert.Equal(t, lines[10:], r.LastOutput)
+	assert.Equal(t, snapshot, r.Path)
+
+	var saved HangReport
+	data, err := os.ReadFile(snapshot)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &saved))
+	assert.Equal(t, r.LastOutput, saved.LastOutput)
+	assert.Equal(t, 300, saved.IdleSeconds)
+
+	assert.Empty(t, newHangReport(1, 0, 0, filepath.Join(dir, "missing"), "").Path)
+}
+
+func TestHangWatch(t *testing.T) {
+	var w hangWatch
+	exitErr := errors.New("signal: killed")
+	assert.Equal(t, exitErr, w.err(exitErr))
+
+	w.record(HangReport{RuntimeSeconds: 700, IdleSeconds: 600, Path: "out.hang.json"})
+	err := w.err(exitErr)
+	var hangErr *HangError
+	require.ErrorAs(t, err, &hangErr)
+	assert.ErrorIs(t, err, exitErr)
+	assert.Equal(t, "AI CLI hung: no output or process activity for 600s, killed after 700s; diagnostics in out.hang.json", err.Error())
+}
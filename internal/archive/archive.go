@@ -0,0 +1,309 @@
+// Package archive exports a ralph-loop session to a tar.gz file and imports
+// it on another machine, so a half-finished loop can be handed off between a
+// laptop and a CI runner.
+//
+// An archive holds manifest.json followed by the state directory (session
+// state, iteration outputs, learnings and cached issue) under state/. The
+// project itself is not included: the receiving side is expected to have the
+// same checkout, typically by pushing and pulling the working branch.
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// FormatVersion is the archive layout version written to the manifest.
+const FormatVersion = 1
+
+const (
+	manifestName = "manifest.json"
+	statePrefix  = "state/"
+)
+
+// localFiles are state directory entries that belong to the machine rather
+// than the session: the state lock and the project config file. They are
+// neither exported nor replaced on import.
+var localFiles = map[string]bool{
+	"ralph-loop.lock": true,
+	"config":          true,
+}
+
+// Manifest describes an exported session.
+type Manifest struct {
+	FormatVersion int    `json:"format_version"`
+	SessionID     string `json:"session_id"`
+	Iteration     int    `json:"iteration"`
+	ExportedAt    string `json:"exported_at"`
+	// ProjectRoot is the absolute project directory on the exporting
+	// machine. Import rewrites state paths under it to the new root.
+	ProjectRoot string `json:"project_root"`
+}
+
+// Export writes the session in stateDir to w as a gzip-compressed tar.
+// projectRoot is recorded so paths can be rebased on import. The state lock
+// file is skipped.
+func Export(w io.Writer, stateDir, projectRoot string) (*Manifest, error) {
+	s, err := state.LoadState(stateDir)
+	if err != nil {
+		return nil, fmt.Errorf("no session to export: %w", err)
+	}
+	manifest := &Manifest{
+		FormatVersion: FormatVersion,
+		SessionID:     s.SessionID,
+		Iteration:     s.Iteration,
+		ExportedAt:    time.Now().Format(time.RFC3339),
+		ProjectRoot:   projectRoot,
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	data, _ := json.MarshalIndent(manifest, "", "    ")
+	if err := writeEntry(tw, manifestName, 0644, data); err != nil {
+		return nil, err
+	}
+
+	err = filepath.WalkDir(stateDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(stateDir, p)
+		if err != nil || rel == "." || localFiles[rel] {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			// Directories are implied by file paths; symlinks and other
+			// special files are not portable.
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return writeEntry(tw, statePrefix+filepath.ToSlash(rel), info.Mode().Perm(), content)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("archive state: %w", err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("finish archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("finish archive: %w", err)
+	}
+	return manifest, nil
+}
+
+func writeEntry(tw *tar.Writer, name string, mode fs.FileMode, data []byte) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    int64(mode),
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Import restores an archive written by Export into stateDir. Unless force
+// is true it refuses to overwrite an existing session. Paths recorded in the
+// session state under the exporting machine's project root are rebased onto
+// projectRoot so that --resume works on the new machine.
+func Import(r io.Reader, stateDir, projectRoot string, force bool) (*Manifest, error) {
+	if _, err := os.Stat(filepath.Join(stateDir, "current-state.json")); err == nil && !force {
+		return nil, fmt.Errorf("%s already holds a session; use --force to replace it", stateDir)
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	// Extract into a sibling staging directory first so a corrupt archive
+	// never leaves a half-written session behind.
+	if err := os.MkdirAll(filepath.Dir(filepath.Clean(stateDir)), 0755); err != nil {
+		return nil, fmt.Errorf("create state dir parent: %w", err)
+	}
+	staging, err := os.MkdirTemp(filepath.Dir(filepath.Clean(stateDir)), ".ralph-import-")
+	if err != nil {
+		return nil, fmt.Errorf("create staging dir: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	var manifest *Manifest
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if hdr.Name == manifestName {
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("parse manifest: %w", err)
+			}
+			continue
+		}
+		rel, ok := strings.CutPrefix(hdr.Name, statePrefix)
+		if !ok {
+			continue
+		}
+		clean := path.Clean(rel)
+		if clean == "." || path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+			return nil, fmt.Errorf("archive entry %q escapes the state directory", hdr.Name)
+		}
+		if err := extractFile(filepath.Join(staging, filepath.FromSlash(clean)), tr, fs.FileMode(hdr.Mode).Perm()); err != nil {
+			return nil, err
+		}
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("not a ralph-loop archive: %s missing", manifestName)
+	}
+	if manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("archive format %d is newer than supported (%d); upgrade ralph-loop", manifest.FormatVersion, FormatVersion)
+	}
+
+	s, err := state.LoadState(staging)
+	if err != nil {
+		return nil, fmt.Errorf("archive has no session state: %w", err)
+	}
+	rebaseState(s, manifest.ProjectRoot, projectRoot, stateDir)
+	if err := state.SaveState(s, staging); err != nil {
+		return nil, err
+	}
+
+	if err := replaceDir(staging, stateDir); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func extractFile(dest string, r io.Reader, mode fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Dir(dest), err)
+	}
+	f, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", dest, err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("write %s: %w", dest, err)
+	}
+	return f.Close()
+}
+
+// replaceDir moves the contents of src into dst, removing everything else in
+// dst except localFiles.
+func replaceDir(src, dst string) error {
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return fmt.Errorf("create state dir: %w", err)
+	}
+	entries, err := os.ReadDir(dst)
+	if err != nil {
+		return fmt.Errorf("read state dir: %w", err)
+	}
+	for _, e := range entries {
+		if localFiles[e.Name()] {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dst, e.Name())); err != nil {
+			return fmt.Errorf("clear state dir: %w", err)
+		}
+	}
+	entries, err = os.ReadDir(src)
+	if err != nil {
+		return fmt.Errorf("read staging dir: %w", err)
+	}
+	for _, e := range entries {
+		if localFiles[e.Name()] {
+			continue
+		}
+		if err := os.Rename(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name())); err != nil {
+			return fmt.Errorf("move %s into state dir: %w", e.Name(), err)
+		}
+	}
+	return nil
+}
+
+// rebaseState rewrites absolute paths in s from the exporting machine to the
+// importing one. Paths under oldRoot move to newRoot; iteration output paths
+// recorded in the checkpoint, kept artifacts and screenshot baselines are
+// pointed at stateDir.
+func rebaseState(s *state.SessionState, oldRoot, newRoot, stateDir string) {
+	rebase := func(p string) string {
+		if oldRoot == "" || p == "" {
+			return p
+		}
+		rel, err := filepath.Rel(oldRoot, p)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return p
+		}
+		return filepath.Join(newRoot, rel)
+	}
+
+	s.TasksFile = rebase(s.TasksFile)
+	s.Learnings.File = rebase(s.Learnings.File)
+	if s.OriginalPlanFile != nil {
+		p := rebase(*s.OriginalPlanFile)
+		s.OriginalPlanFile = &p
+	}
+	if cp := s.Checkpoint; cp != nil {
+		for _, p := range []*string{&cp.ImplOutput, &cp.ValOutput} {
+			if *p == "" {
+				continue
+			}
+			// Checkpoint outputs live in <state>/iteration-NNN/.
+			*p = filepath.Join(stateDir, filepath.Base(filepath.Dir(*p)), filepath.Base(*p))
+		}
+	}
+	// Kept artifacts and diff images live in <state>/iteration-NNN/artifacts/
+	// and frozen screenshot baselines in <state>/baselines/<task>/.
+	inState := func(p string) string {
+		if p == "" {
+			return p
+		}
+		dir := filepath.Dir(p)
+		return filepath.Join(stateDir, filepath.Base(filepath.Dir(dir)), filepath.Base(dir), filepath.Base(p))
+	}
+	for i := range s.Artifacts {
+		a := &s.Artifacts[i]
+		a.Path = inState(a.Path)
+		if d := a.Screenshot; d != nil {
+			d.Baseline = inState(d.Baseline)
+			d.Diff = inState(d.Diff)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// newSession creates a project with a saved session in <root>/.ralph-loop.
+func newSession(t *testing.T, root string) string {
+	t.Helper()
+	stateDir := filepath.Join(root, ".ralph-loop")
+	iterDir := filepath.Join(stateDir, "iteration-002")
+	require.NoError(t, os.MkdirAll(iterDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(iterDir, "implementation-output.txt"), []byte("impl"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(stateDir, "learnings.md"), []byte("# Learnings\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(stateDir, "config"), []byte("MAX_ITERATIONS=5\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(stateDir, "ralph-loop.lock"), []byte("{}"), 0644))
+
+	plan := filepath.Join(root, "specs", "plan.md")
+	require.NoError(t, state.SaveState(&state.SessionState{
+		SessionID:        "ralph-20260101-120000",
+		Iteration:        2,
+		TasksFile:        filepath.Join(root, "specs", "tasks.md"),
+		OriginalPlanFile: &plan,
+		Learnings:        state.LearningsState{Enabled: 1, File: ".ralph-loop/learnings.md"},
+		Checkpoint: &state.Checkpoint{
+			Iteration:  2,
+			Phase:      state.PhaseValidation,
+			ImplOutput: filepath.Join(iterDir, "implementation-output.txt"),
+		},
+		Artifacts: []state.Artifact{
+			{Iteration: 2, Phase: state.PhaseImplementation, Source: "junit.xml", Path: filepath.Join(iterDir, "artifacts", "junit.xml")},
+			{Iteration: 2, Phase: state.PhaseImplementation, Source: "home.png", Path: filepath.Join(iterDir, "artifacts", "home.png"),
+				Screenshot: &state.ScreenshotDiff{
+					Status:   "regression",
+					Baseline: filepath.Join(stateDir, "baselines", "T001", "home.png"),
+					Diff:     filepath.Join(iterDir, "artifacts", "home-diff.png"),
+				}},
+		},
+	}, stateDir))
+	return stateDir
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	oldRoot := t.TempDir()
+	oldState := newSession(t, oldRoot)
+
+	var buf bytes.Buffer
+	manifest, err := Export(&buf, oldState, oldRoot)
+	require.NoError(t, err)
+	assert.Equal(t, "ralph-20260101-120000", manifest.SessionID)
+	assert.Equal(t, 2, manifest.Iteration)
+
+	newRoot := t.TempDir()
+	newState := filepath.Join(newRoot, ".ralph-loop")
+	require.NoError(t, os.MkdirAll(newState, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(newState, "config"), []byte("LOCAL=1\n"), 0644))
+
+	imported, err := Import(&buf, newState, newRoot, false)
+	require.NoError(t, err)
+	assert.Equal(t, oldRoot, imported.ProjectRoot)
+
+	data, err := os.ReadFile(filepath.Join(newState, "iteration-002", "implementation-output.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "impl", string(data))
+	assert.FileExists(t, filepath.Join(newState, "learnings.md"))
+	assert.NoFileExists(t, filepath.Join(newState, "ralph-loop.lock"), "the lock is not exported")
+	config, err := os.ReadFile(filepath.Join(newState, "config"))
+	require.NoError(t, err)
+	assert.Equal(t, "LOCAL=1\n", string(config), "the local project config is kept")
+
+	s, err := state.LoadState(newState)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(newRoot, "specs", "tasks.md"), s.TasksFile)
+	assert.Equal(t, filepath.Join(newRoot, "specs", "plan.md"), *s.OriginalPlanFile)
+	assert.Equal(t, ".ralph-loop/learnings.md", s.Learnings.File, "relative paths are unchanged")
+	assert.Equal(t, filepath.Join(newState, "iteration-002", "implementation-output.txt"), s.Checkpoint.ImplOutput)
+	assert.Equal(t, filepath.Join(newState, "iteration-002", "artifacts", "junit.xml"), s.Artifacts[0].Path)
+	assert.Equal(t, filepath.Join(newState, "baselines", "T001", "home.png"), s.Artifacts[1].Screenshot.Baseline)
+	assert.Equal(t, filepath.Join(newState, "iteration-002", "artifacts", "home-diff.png"), s.Artifacts[1].Screenshot.Diff)
+}
+
+func TestImport_RefusesExistingSessionWithoutForce(t *testing.T) {
+	root := t.TempDir()
+	stateDir := newSession(t, root)
+
+	var buf bytes.Buffer
+	_, err := Export(&buf, stateDir, root)
+	require.NoError(t, err)
+	archived := buf.Bytes()
+
+	_, err = Import(bytes.NewReader(archived), stateDir, root, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already holds a session")
+
+	require.NoError(t, os.WriteFile(filepath.Join(stateDir, "stale.txt"), []byte("x"), 0644))
+	_, err = Import(bytes.NewReader(archived), stateDir, root, true)
+	require.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(stateDir, "stale.txt"), "force replaces the session")
+	assert.FileExists(t, filepath.Join(stateDir, "ralph-loop.lock"), "the importer's lock is kept")
+}
+
+func TestExport_NoSession(t *testing.T) {
+	_, err := Export(&bytes.Buffer{}, t.TempDir(), "/src")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no session to export")
+}
+
+// tarGz builds an archive from name/content pairs.
+func tarGz(t *testing.T, files map[string]string) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return &buf
+}
+
+func TestImport_InvalidArchives(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{"no manifest", map[string]string{"state/current-state.json": "{}"}, "manifest.json missing"},
+		{"no state", map[string]string{"manifest.json": `{"format_version": 1}`}, "archive has no session state"},
+		{"newer format", map[string]string{"manifest.json": `{"format_version": 99}`}, "upgrade ralph-loop"},
+		{"path traversal", map[string]string{"state/../../evil.txt": "x"}, "escapes the state directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			stateDir := filepath.Join(root, ".ralph-loop")
+			_, err := Import(tarGz(t, tt.files), stateDir, root, false)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.NoFileExists(t, filepath.Join(root, "evil.txt"))
+			assert.NoFileExists(t, filepath.Join(stateDir, "current-state.json"))
+		})
+	}
+
+	_, err := Import(bytes.NewReader([]byte("not gzip")), t.TempDir(), "/src", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "open archive")
+}
@@ -0,0 +1,166 @@
+// Package artifacts keeps the evidence files (screenshots, test logs,
+// coverage reports) that the implementer and validators declare in
+// RALPH_ARTIFACTS blocks. Each file is copied into the iteration directory
+// so later iterations cannot overwrite it and it can be reviewed after the
+// run.
+package artifacts
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// DirName is the subdirectory of an iteration directory holding artifacts.
+const DirName = "artifacts"
+
+// MaxSize caps the bytes copied for a single artifact; larger files and
+// directories are skipped with an error.
+const MaxSize = 100 << 20
+
+// Collect copies declared artifacts into iterDir/artifacts and returns a
+// record for each one kept. Relative paths are resolved against workDir
+// ("" means the current directory). Directories are copied recursively.
+// Artifacts that are missing or too large are skipped; their errors are
+// joined into the returned error.
+func Collect(declared []parser.Artifact, workDir, iterDir string, iteration int, phase string) ([]state.Artifact, error) {
+	if len(declared) == 0 {
+		return nil, nil
+	}
+	destDir := filepath.Join(iterDir, DirName)
+	var kept []state.Artifact
+	var errs []error
+	for _, a := range declared {
+		src := a.Path
+		if !filepath.IsAbs(src) && workDir != "" {
+			src = filepath.Join(workDir, src)
+		}
+		size, err := treeSize(src)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("artifact %s: %w", a.Path, err))
+			continue
+		}
+		if size > MaxSize {
+			errs = append(errs, fmt.Errorf("artifact %s: %d bytes exceeds the %d byte limit", a.Path, size, MaxSize))
+			continue
+		}
+		if err := os.MkdirAll(destDir, 0755); err != nil {
+			return kept, fmt.Errorf("create artifacts dir: %w", err)
+		}
+		dest := uniquePath(filepath.Join(destDir, filepath.Base(filepath.Clean(src))))
+		if err := copyTree(src, dest); err != nil {
+			errs = append(errs, fmt.Errorf("artifact %s: %w", a.Path, err))
+			continue
+		}
+		kept = append(kept, state.Artifact{
+			Iteration:   iteration,
+			Phase:       phase,
+			Source:      a.Path,
+			Path:        dest,
+			Kind:        a.Kind,
+			Description: a.Description,
+			Task:        a.Task,
+			Size:        size,
+		})
+	}
+	return kept, errors.Join(errs...)
+}
+
+// treeSize returns the size of a file, or the total size of the regular
+// files under a directory.
+func treeSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, fmt.Errorf("not found")
+		}
+		return 0, err
+	}
+	if !info.IsDir() {
+		return info.Size(), nil
+	}
+	var total int64
+	err = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			fi, err := d.Info()
+			if err != nil {
+				return err
+			}
+			total += fi.Size()
+		}
+		return nil
+	})
+	return total, err
+}
+
+// uniquePath appends -2, -3, ... before the extension until path is unused.
+func uniquePath(path string) string {
+	if _, err := os.Lstat(path); errors.Is(err, fs.ErrNotExist) {
+		return path
+	}
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(path, ext)
+	for n := 2; ; n++ {
+		candidate := stem + "-" + strconv.Itoa(n) + ext
+		if _, err := os.Lstat(candidate); errors.Is(err, fs.ErrNotExist) {
+			return candidate
+		}
+	}
+}
+
+// copyTree copies a file or directory. Only regular files are copied;
+// symlinks and special files inside directories are skipped.
+func copyTree(src, dest string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return copyFile(src, dest)
+	}
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, 0755)
+		case d.Type().IsRegular():
+			return copyFile(path, target)
+		}
+		return nil
+	})
+}
+
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,70 @@
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestCollect_FilesAndDirectories(t *testing.T) {
+	work := t.TempDir()
+	iterDir := filepath.Join(t.TempDir(), "iteration-002")
+	writeFile(t, filepath.Join(work, "shots", "login.png"), "png")
+	writeFile(t, filepath.Join(work, "other", "login.png"), "png2")
+	writeFile(t, filepath.Join(work, "coverage", "index.html"), "<html>")
+	writeFile(t, filepath.Join(work, "coverage", "js", "app.js"), "js")
+
+	kept, err := Collect([]parser.Artifact{
+		{Path: "shots/login.png", Kind: "screenshot", Description: "login page", Task: "T003"},
+		{Path: "other/login.png"},
+		{Path: "coverage", Kind: "coverage"},
+	}, work, iterDir, 2, state.PhaseValidation)
+	require.NoError(t, err)
+
+	dest := filepath.Join(iterDir, DirName)
+	assert.Equal(t, []state.Artifact{
+		{Iteration: 2, Phase: state.PhaseValidation, Source: "shots/login.png", Path: filepath.Join(dest, "login.png"), Kind: "screenshot", Description: "login page", Task: "T003", Size: 3},
+		{Iteration: 2, Phase: state.PhaseValidation, Source: "other/login.png", Path: filepath.Join(dest, "login-2.png"), Size: 4},
+		{Iteration: 2, Phase: state.PhaseValidation, Source: "coverage", Path: filepath.Join(dest, "coverage"), Kind: "coverage", Size: 8},
+	}, kept)
+
+	data, err := os.ReadFile(filepath.Join(dest, "coverage", "js", "app.js"))
+	require.NoError(t, err)
+	assert.Equal(t, "js", string(data))
+}
+
+func TestCollect_MissingArtifactIsSkipped(t *testing.T) {
+	work := t.TempDir()
+	iterDir := t.TempDir()
+	writeFile(t, filepath.Join(work, "junit.xml"), "<testsuite/>")
+
+	kept, err := Collect([]parser.Artifact{
+		{Path: "missing.png"},
+		{Path: filepath.Join(work, "junit.xml"), Kind: "test_log"},
+	}, "", iterDir, 1, state.PhaseImplementation)
+
+	assert.ErrorContains(t, err, "artifact missing.png: not found")
+	require.Len(t, kept, 1)
+	assert.Equal(t, "test_log", kept[0].Kind)
+	assert.FileExists(t, filepath.Join(iterDir, DirName, "junit.xml"))
+}
+
+func TestCollect_Nothing(t *testing.T) {
+	iterDir := t.TempDir()
+	kept, err := Collect(nil, "", iterDir, 1, state.PhaseImplementation)
+	assert.NoError(t, err)
+	assert.Nil(t, kept)
+	assert.NoDirExists(t, filepath.Join(iterDir, DirName))
+}
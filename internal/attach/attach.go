@@ -0,0 +1,166 @@
+// Package attach follows a ralph-loop session from another terminal. It
+// polls the state directory, reports status changes from
+// current-state.json and streams the text of the phase logs the loop
+// writes to each iteration directory (see iterlog).
+package attach
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/iterlog"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// Config controls Follow.
+type Config struct {
+	StateDir string
+	Out      io.Writer
+	// Interval is how often the state and logs are polled; 0 means 500ms.
+	Interval time.Duration
+	// Lines is how many lines of the current iteration's output are shown
+	// on attach.
+	Lines int
+	// Raw prints the logs' JSON events as written instead of their text.
+	Raw bool
+}
+
+// Follow reports the session in cfg.StateDir and streams its output until
+// the loop releases the state lock or ctx ends. A session no loop is
+// running is shown once.
+func Follow(ctx context.Context, cfg Config) error {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 500 * time.Millisecond
+	}
+	s, err := state.LoadState(cfg.StateDir)
+	if err != nil {
+		return fmt.Errorf("no session in %s: %w", cfg.StateDir, err)
+	}
+
+	f := &follower{cfg: cfg, tails: map[string]*logTail{}}
+	f.report(s)
+	f.switchIteration(s.Iteration)
+	f.backlog(cfg.Lines)
+	if !running(cfg.StateDir) {
+		fmt.Fprintf(cfg.Out, "No ralph-loop is running in %s.\n", cfg.StateDir)
+		return nil
+	}
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		// Check the lock first so output written before the loop exited
+		// is still streamed below.
+		done := !running(cfg.StateDir)
+		if next, err := state.LoadState(cfg.StateDir); err == nil {
+			// A failed read is a state file caught mid-write; retry.
+			if next.Iteration != f.iteration {
+				f.poll()
+				f.switchIteration(next.Iteration)
+			}
+			f.report(next)
+			s = next
+		}
+		f.poll()
+		if done {
+			fmt.Fprintf(cfg.Out, "Loop exited: %s at iteration %d.\n", s.Status, s.Iteration)
+			return nil
+		}
+	}
+}
+
+// running reports whether a loop holds the lock of stateDir.
+func running(stateDir string) bool {
+	_, err := state.ReadLock(stateDir)
+	return err == nil
+}
+
+type follower struct {
+	cfg       Config
+	iteration int
+	tails     map[string]*logTail // by phase
+	lastLog   string              // phase whose output was printed last
+	status    string              // last reported status line
+	verdict   string
+}
+
+// report prints a status line when the iteration, phase or status changed,
+// and the verdict when a new one was recorded.
+func (f *follower) report(s *state.SessionState) {
+	line := fmt.Sprintf("== Iteration %d/%d: %s (%s) ==", s.Iteration, s.MaxIterations, s.Phase, s.Status)
+	if line != f.status {
+		fmt.Fprintln(f.cfg.Out, line)
+		f.status = line
+	}
+	if s.Verdict != f.verdict {
+		if s.Verdict != "" {
+			fmt.Fprintf(f.cfg.Out, "== Verdict: %s ==\n", s.Verdict)
+		}
+		f.verdict = s.Verdict
+	}
+}
+
+// switchIteration starts following the logs of iteration from their
+// beginning.
+func (f *follower) switchIteration(iteration int) {
+	f.iteration = iteration
+	f.tails = map[string]*logTail{}
+	f.lastLog = ""
+	iterDir := filepath.Join(f.cfg.StateDir, fmt.Sprintf("iteration-%03d", iteration))
+	for _, phase := range iterlog.Phases {
+		f.tails[phase] = &logTail{path: iterlog.Path(iterDir, phase), raw: f.cfg.Raw}
+	}
+}
+
+// backlog skips what the logs already hold, printing only its last n
+// lines.
+func (f *follower) backlog(n int) {
+	var shown []string
+	lastLog := ""
+	for _, phase := range iterlog.Phases {
+		if lines := f.tails[phase].read(); len(lines) > 0 {
+			shown = append(shown, logHeader(phase))
+			shown = append(shown, lines...)
+			lastLog = phase
+		}
+	}
+	if n <= 0 || len(shown) == 0 {
+		return
+	}
+	if len(shown) > n {
+		shown = shown[len(shown)-n:]
+	}
+	for _, line := range shown {
+		fmt.Fprintln(f.cfg.Out, line)
+	}
+	f.lastLog = lastLog
+}
+
+// poll prints what was appended to the iteration's logs, with a header
+// whenever the output comes from another phase than before.
+func (f *follower) poll() {
+	for _, phase := range iterlog.Phases {
+		lines := f.tails[phase].read()
+		if len(lines) == 0 {
+			continue
+		}
+		if phase != f.lastLog {
+			fmt.Fprintln(f.cfg.Out, logHeader(phase))
+			f.lastLog = phase
+		}
+		fmt.Fprintln(f.cfg.Out, strings.Join(lines, "\n"))
+	}
+}
+
+func logHeader(phase string) string {
+	return fmt.Sprintf("--- %s.log ---", phase)
+}
@@ -0,0 +1,132 @@
+package attach
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe to read while Follow writes to it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func saveState(t *testing.T, dir string, iteration int, phase, status, verdict string) {
+	t.Helper()
+	require.NoError(t, state.SaveState(&state.SessionState{
+		Iteration: iteration, MaxIterations: 5, Phase: phase, Status: status, Verdict: verdict,
+	}, dir))
+}
+
+func iterDir(t *testing.T, dir string, iteration int) string {
+	t.Helper()
+	d := filepath.Join(dir, "iteration-00"+string(rune('0'+iteration)))
+	require.NoError(t, os.MkdirAll(d, 0755))
+	return d
+}
+
+func TestFollow_NoSession(t *testing.T) {
+	err := Follow(context.Background(), Config{StateDir: t.TempDir(), Out: &bytes.Buffer{}})
+	assert.ErrorContains(t, err, "no session")
+}
+
+func TestFollow_StoppedSessionShownOnce(t *testing.T) {
+	dir := t.TempDir()
+	saveState(t, dir, 2, state.PhaseValidation, state.StatusInterrupted, "NEEDS_MORE_WORK")
+	appendFile(t, filepath.Join(iterDir(t, dir, 2), "impl.log"), "a\nb\nc\n")
+
+	var out bytes.Buffer
+	require.NoError(t, Follow(context.Background(), Config{StateDir: dir, Out: &out, Lines: 2}))
+	assert.Equal(t, "== Iteration 2/5: validation (INTERRUPTED) ==\n"+
+		"== Verdict: NEEDS_MORE_WORK ==\n"+
+		"b\nc\n"+
+		"No ralph-loop is running in "+dir+".\n", out.String())
+}
+
+func TestFollow_StreamsUntilLoopExits(t *testing.T) {
+	dir := t.TempDir()
+	saveState(t, dir, 1, state.PhaseImplementation, state.StatusInProgress, "")
+	first := iterDir(t, dir, 1)
+	appendFile(t, filepath.Join(first, "impl.log"), "earlier\n")
+	lock, err := state.AcquireLock(dir, false)
+	require.NoError(t, err)
+
+	out := &syncBuffer{}
+	done := make(chan error, 1)
+	go func() {
+		done <- Follow(context.Background(), Config{StateDir: dir, Out: out, Interval: 5 * time.Millisecond})
+	}()
+	waitFor := func(text string) {
+		t.Helper()
+		require.Eventually(t, func() bool { return strings.Contains(out.String(), text) }, 2*time.Second, 5*time.Millisecond, "waiting for %q in:\n%s", text, out.String())
+	}
+
+	waitFor("== Iteration 1/5: implementation (IN_PROGRESS) ==")
+	appendFile(t, filepath.Join(first, "impl.log"), claudeLine+"\n")
+	waitFor("Editing main.go")
+
+	saveState(t, dir, 1, state.PhaseValidation, state.StatusInProgress, "")
+	appendFile(t, filepath.Join(first, "val.log"), codexLine+"\n")
+	waitFor("Running tests")
+
+	saveState(t, dir, 2, state.PhaseImplementation, state.StatusInProgress, "NEEDS_MORE_WORK")
+	appendFile(t, filepath.Join(iterDir(t, dir, 2), "impl.log"), "second\n")
+	waitFor("second")
+
+	saveState(t, dir, 2, state.PhaseValidation, state.StatusComplete, "COMPLETE")
+	require.NoError(t, lock.Release())
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Follow did not return after the loop exited")
+	}
+
+	assert.Equal(t, "== Iteration 1/5: implementation (IN_PROGRESS) ==\n"+
+		"--- impl.log ---\n"+
+		"Editing main.go\n"+
+		"== Iteration 1/5: validation (IN_PROGRESS) ==\n"+
+		"--- val.log ---\n"+
+		"Running tests\n"+
+		"== Iteration 2/5: implementation (IN_PROGRESS) ==\n"+
+		"== Verdict: NEEDS_MORE_WORK ==\n"+
+		"--- impl.log ---\n"+
+		"second\n"+
+		"== Iteration 2/5: validation (COMPLETE) ==\n"+
+		"== Verdict: COMPLETE ==\n"+
+		"Loop exited: COMPLETE at iteration 2.\n", out.String())
+}
+
+func TestFollow_StopsWithContext(t *testing.T) {
+	dir := t.TempDir()
+	saveState(t, dir, 1, state.PhaseImplementation, state.StatusInProgress, "")
+	lock, err := state.AcquireLock(dir, false)
+	require.NoError(t, err)
+	defer lock.Release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NoError(t, Follow(ctx, Config{StateDir: dir, Out: &bytes.Buffer{}, Interval: time.Millisecond}))
+}
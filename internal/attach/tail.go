@@ -0,0 +1,126 @@
+package attach
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/statecrypt"
+)
+
+// logTail incrementally reads a phase log. The logs hold the raw output of
+// whichever AI CLI ran, so each JSON line is decoded as a claude
+// stream-json or codex JSONL event and only its text is kept.
+type logTail struct {
+	path    string
+	raw     bool
+	offset  int64
+	partial string
+	// sealed is set once the log was read encrypted: its iteration is
+	// over and the log no longer grows.
+	sealed bool
+}
+
+// read returns the lines appended since the previous call.
+func (t *logTail) read() []string {
+	if t.sealed {
+		return nil
+	}
+	info, err := os.Stat(t.path)
+	if err != nil {
+		return nil
+	}
+	var data []byte
+	if plain, ok := readSealed(t.path); ok {
+		// The finished iteration's log was encrypted (see statecrypt);
+		// show what was not read of it yet.
+		t.sealed = true
+		if int64(len(plain)) > t.offset {
+			data = plain[t.offset:]
+		}
+	} else {
+		if info.Size() < t.offset {
+			// Rotated: finish the previous file, now path.1, then start over.
+			data = readFrom(t.path+".1", t.offset)
+			t.offset = 0
+		}
+		more := readFrom(t.path, t.offset)
+		t.offset += int64(len(more))
+		data = append(data, more...)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	chunk := t.partial + string(data)
+	complete := strings.LastIndexByte(chunk, '\n')
+	if complete < 0 {
+		t.partial = chunk
+		return nil
+	}
+	t.partial = chunk[complete+1:]
+
+	var out []string
+	for _, line := range strings.Split(chunk[:complete], "\n") {
+		text := line
+		if !t.raw {
+			text = eventText(line)
+		}
+		for _, l := range strings.Split(text, "\n") {
+			if strings.TrimSpace(l) != "" {
+				out = append(out, l)
+			}
+		}
+	}
+	return out
+}
+
+// readFrom returns the contents of path past offset, or nil.
+// readSealed returns the decrypted contents of path when it is encrypted,
+// nothing when it cannot be decrypted.
+func readSealed(path string) ([]byte, bool) {
+	if !statecrypt.IsEncrypted(readHead(path)) {
+		return nil, false
+	}
+	plain, _ := statecrypt.ReadFile(path)
+	return plain, true
+}
+
+func readHead(path string) []byte {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	head := make([]byte, 64)
+	n, _ := io.ReadFull(f, head)
+	return head[:n]
+}
+
+func readFrom(path string, offset int64) []byte {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil
+	}
+	data, _ := io.ReadAll(f)
+	return data
+}
+
+// eventText returns the text of a log line: the text of a claude or codex
+// JSON event, nothing for events without text, or the line itself when it
+// is not JSON (such as a CLI's stderr).
+func eventText(line string) string {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "{") {
+		return line
+	}
+	if text := parser.ParseStreamJSON(line); text != "" {
+		return text
+	}
+	return parser.ParseCodexJSONL(line)
+}
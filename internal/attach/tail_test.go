@@ -0,0 +1,88 @@
+package attach
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/statecrypt"
+)
+
+const (
+	claudeLine = `{"type":"assistant","message":{"content":[{"type":"text","text":"Editing main.go"}]}}`
+	codexLine  = `{"type":"item.completed","item":{"type":"agent_message","text":"Running tests"}}`
+	noiseLine  = `{"type":"system","subtype":"init"}`
+)
+
+func appendFile(t *testing.T, path, text string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString(text)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
+
+func TestLogTail_DecodesEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "impl.log")
+	tail := &logTail{path: path}
+	assert.Nil(t, tail.read(), "no log yet")
+
+	appendFile(t, path, claudeLine+"\n"+noiseLine+"\n"+codexLine+"\nwarning: slow network\n")
+	assert.Equal(t, []string{"Editing main.go", "Running tests", "warning: slow network"}, tail.read())
+	assert.Nil(t, tail.read())
+}
+
+func TestLogTail_WaitsForCompleteLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "val.log")
+	tail := &logTail{path: path}
+
+	appendFile(t, path, claudeLine[:20])
+	assert.Nil(t, tail.read())
+	appendFile(t, path, claudeLine[20:]+"\n")
+	assert.Equal(t, []string{"Editing main.go"}, tail.read())
+}
+
+func TestLogTail_FollowsRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "impl.log")
+	tail := &logTail{path: path, raw: true}
+
+	appendFile(t, path, "one\n")
+	assert.Equal(t, []string{"one"}, tail.read())
+
+	appendFile(t, path, "two\n")
+	require.NoError(t, os.Rename(path, path+".1"))
+	appendFile(t, path, "3\n")
+	assert.Equal(t, []string{"two", "3"}, tail.read())
+}
+
+func TestLogTail_FinishesSealedLog(t *testing.T) {
+	key, err := statecrypt.ParseKey("c3RhdGUta2V5LXN0YXRlLWtleS1zdGF0ZS1rZXktMzI=")
+	require.NoError(t, err)
+	statecrypt.SetKey(key)
+	t.Cleanup(func() { statecrypt.SetKey(nil) })
+	dir := t.TempDir()
+	path := filepath.Join(dir, "impl.log")
+	tail := &logTail{path: path, raw: true}
+
+	appendFile(t, path, "one\n")
+	assert.Equal(t, []string{"one"}, tail.read())
+	appendFile(t, path, "two\n")
+	require.NoError(t, statecrypt.SealDir(dir))
+	assert.Equal(t, []string{"two"}, tail.read(), "the rest of the log is decrypted")
+	assert.Nil(t, tail.read())
+
+	fresh := &logTail{path: path, raw: true}
+	assert.Equal(t, []string{"one", "two"}, fresh.read())
+}
+
+func TestLogTail_Raw(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cross.log")
+	tail := &logTail{path: path, raw: true}
+
+	appendFile(t, path, noiseLine+"\n")
+	assert.Equal(t, []string{noiseLine}, tail.read())
+}
@@ -8,7 +8,9 @@ package banner
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 
@@ -143,6 +145,24 @@ func PrintMaxIterationsBanner(iterations int, maxIterations int) {
 	fmt.Fprintln(os.Stderr, sep)
 }
 
+// PrintBudgetBanner displays when the --max-duration budget stops the loop.
+//
+// Example output:
+//
+//	═══════════════════════════════════════════════════
+//	  ⏱ Time budget exhausted (1h 52m 10s of 2h 0m 0s)
+//	  Iterations: 6
+//	  Use --resume to continue from this point
+//	═══════════════════════════════════════════════════
+func PrintBudgetBanner(iterations int, elapsedSecs int, budgetSecs int) {
+	sep := warnColor("═══════════════════════════════════════════════════")
+	fmt.Fprintln(os.Stderr, sep)
+	fmt.Fprintf(os.Stderr, warnColor("  ⏱ Time budget exhausted (%s of %s)\n"), logging.FormatDuration(elapsedSecs), logging.FormatDuration(budgetSecs))
+	fmt.Fprintf(os.Stderr, "  Iterations: %d\n", iterations)
+	fmt.Fprintln(os.Stderr, "  Use --resume to continue from this point")
+	fmt.Fprintln(os.Stderr, sep)
+}
+
 // PrintInadmissibleBanner displays when inadmissible threshold is exceeded.
 //
 // Parameters:
@@ -185,6 +205,102 @@ func PrintInterruptedBanner(iteration int, phase string) {
 	fmt.Fprintln(os.Stderr, sep)
 }
 
+// PrintModelEscalationBanner displays a switch to a stronger implementation model.
+//
+// Example output:
+//
+//	═══════════════════════════════════════════════════
+//	  ⬆ Escalating implementation model
+//	  Model:     sonnet -> opus
+//	  Iteration: 4
+//	  Reason:    2 consecutive NEEDS_MORE_WORK verdicts
+//	═══════════════════════════════════════════════════
+func PrintModelEscalationBanner(from, to string, iteration int, reason string) {
+	sep := warnColor("═══════════════════════════════════════════════════")
+	fmt.Fprintln(os.Stderr, sep)
+	fmt.Fprintln(os.Stderr, warnColor("  ⬆ Escalating implementation model"))
+	fmt.Fprintf(os.Stderr, "  Model:     %s -> %s\n", from, to)
+	fmt.Fprintf(os.Stderr, "  Iteration: %d\n", iteration)
+	fmt.Fprintf(os.Stderr, "  Reason:    %s\n", reason)
+	fmt.Fprintln(os.Stderr, sep)
+}
+
+// PrintFailoverBanner displays the switch to the fallback AI provider after
+// the primary failed its health check.
+//
+// Example output:
+//
+//	═══════════════════════════════════════════════════
+//	  ⇄ Failing over to the fallback AI
+//	  AI:        claude -> codex
+//	  Iteration: 4
+//	  Reason:    health check failed: probe prompt: no answer
+//	  The rest of the session runs on codex
+//	═══════════════════════════════════════════════════
+func PrintFailoverBanner(from, to string, iteration int, reason string) {
+	sep := warnColor("═══════════════════════════════════════════════════")
+	fmt.Fprintln(os.Stderr, sep)
+	fmt.Fprintln(os.Stderr, warnColor("  ⇄ Failing over to the fallback AI"))
+	fmt.Fprintf(os.Stderr, "  AI:        %s -> %s\n", from, to)
+	fmt.Fprintf(os.Stderr, "  Iteration: %d\n", iteration)
+	fmt.Fprintf(os.Stderr, "  Reason:    %s\n", reason)
+	fmt.Fprintf(os.Stderr, "  The rest of the session runs on %s\n", to)
+	fmt.Fprintln(os.Stderr, sep)
+}
+
+// SpecInfo describes one tasks file of a multi-file session.
+type SpecInfo struct {
+	TasksFile      string
+	Status         string
+	FirstIteration int
+	LastIteration  int
+	Done           int
+	Total          int
+}
+
+// PrintSpecSummary displays the combined progress of a multi-file session,
+// one row per tasks file in run order.
+//
+// Example output:
+//
+//	═══════════════════════════════════════════════════
+//	  Specs:      1 done, 1 active, 1 pending
+//	  done     iter 1-3   5/5  specs/001-core/tasks.md
+//	  active   iter 4-6   2/4  specs/002-api/tasks.md
+//	  pending  -          0/3  specs/003-ui/tasks.md
+//	═══════════════════════════════════════════════════
+func PrintSpecSummary(specs []SpecInfo) {
+	sep := headerColor("═══════════════════════════════════════════════════")
+	fmt.Fprintln(os.Stderr, sep)
+
+	counts := make(map[string]int)
+	var order []string
+	for _, s := range specs {
+		if counts[s.Status] == 0 {
+			order = append(order, s.Status)
+		}
+		counts[s.Status]++
+	}
+	parts := make([]string, 0, len(order))
+	for _, status := range order {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+	}
+	fmt.Fprintf(os.Stderr, "  Specs:      %s\n", strings.Join(parts, ", "))
+
+	for _, s := range specs {
+		iters := "-"
+		switch {
+		case s.FirstIteration == 0:
+		case s.LastIteration <= s.FirstIteration:
+			iters = fmt.Sprintf("iter %d", s.FirstIteration)
+		default:
+			iters = fmt.Sprintf("iter %d-%d", s.FirstIteration, s.LastIteration)
+		}
+		fmt.Fprintf(os.Stderr, "  %-8s %-10s %3s  %s\n", s.Status, iters, fmt.Sprintf("%d/%d", s.Done, s.Total), s.TasksFile)
+	}
+	fmt.Fprintln(os.Stderr, sep)
+}
+
 // StatusInfo contains all fields for displaying session status.
 type StatusInfo struct {
 	SessionID         string
@@ -205,7 +321,75 @@ type StatusInfo struct {
 	CrossModel        string
 	RetryAttempt      int
 	RetryDelay        int
+	Retries           RetryInfo
 	LastFeedback      string
+	Tasks             []TaskInfo
+	Escalations       []EscalationInfo
+	Failover          *FailoverInfo
+	Progress          *ProgressInfo
+	Validators        []ValidatorInfo
+	Artifacts         []ArtifactInfo
+}
+
+// RetryInfo summarizes the retries of a session's AI invocations.
+type RetryInfo struct {
+	Total     int
+	Classes   map[string]int // failed attempts by error class
+	GaveUp    int
+	LastClass string
+	LastError string
+	LastAt    string
+}
+
+// ArtifactInfo describes one kept evidence artifact.
+type ArtifactInfo struct {
+	Iteration int
+	Phase     string
+	Kind      string
+	Path      string
+	Diff      string // screenshot comparison, e.g. "regression 12.4%"; "" if none
+}
+
+// EscalationInfo describes one implementation model escalation.
+type EscalationInfo struct {
+	Iteration int
+	From      string
+	To        string
+}
+
+// ProgressInfo is the estimated progress of a session.
+type ProgressInfo struct {
+	Summary string // share of tasks done and ETA, e.g. "42% (5/12 tasks), ETA 1h20m0s (~4 iteration(s))"
+	// Average durations of an iteration and of its implementation and
+	// validation phases; 0 before an iteration was judged.
+	Iteration, Impl, Val time.Duration
+}
+
+// FailoverInfo describes the switch of a session to its fallback AI.
+type FailoverInfo struct {
+	Iteration int
+	From      string
+	To        string
+	Reason    string
+}
+
+// ValidatorInfo describes the validator that judged one iteration.
+type ValidatorInfo struct {
+	Iteration int
+	Validator string
+	Verdict   string
+}
+
+// TaskInfo describes a single tracked task for the status display.
+type TaskInfo struct {
+	ID             string
+	Text           string
+	Status         string
+	FirstIteration int
+	LastIteration  int
+	Verdicts       []string
+	Churning       bool
+	Meta           string // formatted {key: value} metadata, if any
 }
 
 // PrintStatusBanner displays current session status with all available fields.
@@ -237,9 +421,25 @@ func PrintStatusBanner(info StatusInfo) {
 	if info.AICli != "" {
 		fmt.Fprintf(os.Stderr, "  AI:         %s (impl: %s, val: %s)\n", info.AICli, info.ImplModel, info.ValModel)
 	}
+	for _, e := range info.Escalations {
+		fmt.Fprintf(os.Stderr, "  Escalated:  %s -> %s (iteration %d)\n", e.From, e.To, e.Iteration)
+	}
+	if f := info.Failover; f != nil {
+		fmt.Fprintf(os.Stderr, "  Failover:   %s -> %s (iteration %d): %s\n", f.From, f.To, f.Iteration, f.Reason)
+	}
+	for _, v := range info.Validators {
+		fmt.Fprintf(os.Stderr, "  Validated:  %s by %s (iteration %d)\n", v.Verdict, v.Validator, v.Iteration)
+	}
 	if info.CrossValEnabled {
 		fmt.Fprintf(os.Stderr, "  Cross-val:  %s / %s\n", info.CrossAI, info.CrossModel)
 	}
+	if p := info.Progress; p != nil {
+		fmt.Fprintf(os.Stderr, "  Progress:   %s\n", p.Summary)
+		if p.Iteration > 0 {
+			fmt.Fprintf(os.Stderr, "  Pace:       %s per iteration (impl %s, val %s)\n",
+				p.Iteration.Round(time.Second), p.Impl.Round(time.Second), p.Val.Round(time.Second))
+		}
+	}
 	if info.InadmissibleCount > 0 || info.MaxInadmissible > 0 {
 		fmt.Fprintf(os.Stderr, "  Inadmiss.:  %d/%d\n", info.InadmissibleCount, info.MaxInadmissible)
 	}
@@ -252,6 +452,18 @@ func PrintStatusBanner(info StatusInfo) {
 	if info.RetryAttempt > 0 {
 		fmt.Fprintf(os.Stderr, "  Retry:      attempt %d (delay %ds)\n", info.RetryAttempt, info.RetryDelay)
 	}
+	if r := info.Retries; r.Total > 0 {
+		classes := make([]string, 0, len(r.Classes))
+		for class := range r.Classes {
+			classes = append(classes, class)
+		}
+		sort.Strings(classes)
+		for i, class := range classes {
+			classes[i] = fmt.Sprintf("%s %d", class, r.Classes[class])
+		}
+		fmt.Fprintf(os.Stderr, "  Retries:    %d (%s), %d gave up\n", r.Total, strings.Join(classes, ", "), r.GaveUp)
+		fmt.Fprintf(os.Stderr, "  Last error: %s at %s: %s\n", r.LastClass, r.LastAt, r.LastError)
+	}
 	if info.LastFeedback != "" {
 		feedback := info.LastFeedback
 		if len(feedback) > 80 {
@@ -259,5 +471,68 @@ func PrintStatusBanner(info StatusInfo) {
 		}
 		fmt.Fprintf(os.Stderr, "  Feedback:   %s\n", feedback)
 	}
+	if len(info.Tasks) > 0 {
+		printTaskTable(info.Tasks)
+	}
+	if len(info.Artifacts) > 0 {
+		fmt.Fprintf(os.Stderr, "  Artifacts:  %d\n", len(info.Artifacts))
+		for _, a := range info.Artifacts {
+			kind := a.Kind
+			if kind == "" {
+				kind = "-"
+			}
+			fmt.Fprintf(os.Stderr, "    iter %-3d %-14s %-10s %s\n", a.Iteration, a.Phase, kind, a.Path)
+			if a.Diff != "" {
+				fmt.Fprintf(os.Stderr, "             %s\n", a.Diff)
+			}
+		}
+	}
 	fmt.Fprintln(os.Stderr, sep)
 }
+
+// printTaskTable prints a per-task summary line followed by one row per task.
+//
+// Example output:
+//
+//	Tasks:      2 done, 1 blocked, 1 pending
+//	  T001  done     iter 1-2  Create initial setup
+//	  T002  blocked  iter 2    Add configuration file
+//	  T003  pending  iter 1-4  Implement core logic (churning: 4 verdicts)
+func printTaskTable(tasks []TaskInfo) {
+	counts := make(map[string]int)
+	var order []string
+	for _, t := range tasks {
+		if counts[t.Status] == 0 {
+			order = append(order, t.Status)
+		}
+		counts[t.Status]++
+	}
+	parts := make([]string, 0, len(order))
+	for _, status := range order {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+	}
+	fmt.Fprintf(os.Stderr, "  Tasks:      %s\n", strings.Join(parts, ", "))
+
+	for _, t := range tasks {
+		iters := "-"
+		switch {
+		case t.FirstIteration == 0:
+		case t.FirstIteration == t.LastIteration:
+			iters = fmt.Sprintf("iter %d", t.FirstIteration)
+		default:
+			iters = fmt.Sprintf("iter %d-%d", t.FirstIteration, t.LastIteration)
+		}
+		text := t.Text
+		if len(text) > 50 {
+			text = text[:50] + "..."
+		}
+		line := fmt.Sprintf("    %-6s %-8s %-10s %s", t.ID, t.Status, iters, text)
+		if t.Meta != "" {
+			line += " " + t.Meta
+		}
+		if t.Churning {
+			line += warnColor(fmt.Sprintf(" (churning: %d verdicts)", len(t.Verdicts)))
+		}
+		fmt.Fprintln(os.Stderr, line)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -339,6 +340,17 @@ func TestPrintBlockedBanner_NilList(t *testing.T) {
 	assert.NotEmpty(t, output, "blocked banner should not be empty even with nil tasks")
 }
 
+// TestPrintBudgetBanner verifies the time budget banner
+func TestPrintBudgetBanner(t *testing.T) {
+	output := captureStderr(t, func() {
+		PrintBudgetBanner(6, 6730, 7200)
+	})
+
+	assert.Contains(t, output, "Time budget exhausted (1h 52m 10s of 2h 0m 0s)")
+	assert.Contains(t, output, "Iterations: 6")
+	assert.Contains(t, output, "--resume")
+}
+
 // TestPrintMaxIterationsBanner verifies max iterations banner shows counts
 func TestPrintMaxIterationsBanner(t *testing.T) {
 	tests := []struct {
@@ -854,3 +866,192 @@ func TestBannerOutput_NotEmpty(t *testing.T) {
 		})
 	}
 }
+
+// TestPrintStatusBanner_Tasks verifies the per-task table in the status banner
+func TestPrintStatusBanner_Tasks(t *testing.T) {
+	output := captureStderr(t, func() {
+		PrintStatusBanner(StatusInfo{
+			SessionID: "ralph-20260130-153045",
+			Status:    "IN_PROGRESS",
+			Tasks: []TaskInfo{
+				{ID: "T001", Text: "T001 Create setup", Status: "done", FirstIteration: 1, LastIteration: 2},
+				{ID: "T002", Text: "T002 Add config", Status: "blocked", FirstIteration: 2, LastIteration: 2},
+				{ID: "T003", Text: "T003 Core logic", Status: "pending", FirstIteration: 1, LastIteration: 4,
+					Verdicts: []string{"NEEDS_MORE_WORK", "NEEDS_MORE_WORK", "NEEDS_MORE_WORK", "NEEDS_MORE_WORK"}, Churning: true},
+				{ID: "T004", Text: "T004 Docs", Status: "pending", Meta: "{area: docs, est: 1h}"},
+			},
+		})
+	})
+
+	assert.Contains(t, output, "T004 Docs {area: docs, est: 1h}")
+
+	assert.Contains(t, output, "Tasks:      1 done, 1 blocked, 2 pending")
+	assert.Contains(t, output, "iter 1-2")
+	assert.Contains(t, output, "iter 2 ")
+	assert.Contains(t, output, "T002 Add config")
+	assert.Contains(t, output, "churning: 4 verdicts")
+	assert.Equal(t, 1, strings.Count(output, "churning"))
+}
+
+// TestPrintStatusBanner_NoTasks verifies the task section is omitted when empty
+func TestPrintStatusBanner_NoTasks(t *testing.T) {
+	output := captureStderr(t, func() {
+		PrintStatusBanner(StatusInfo{SessionID: "s", Status: "IN_PROGRESS"})
+	})
+	assert.NotContains(t, output, "Tasks:")
+}
+
+// TestPrintModelEscalationBanner verifies the escalation details are shown
+func TestPrintModelEscalationBanner(t *testing.T) {
+	output := captureStderr(t, func() {
+		PrintModelEscalationBanner("sonnet", "opus", 4, "2 consecutive NEEDS_MORE_WORK verdicts")
+	})
+
+	assert.Contains(t, output, "Escalating implementation model")
+	assert.Contains(t, output, "Model:     sonnet -> opus")
+	assert.Contains(t, output, "Iteration: 4")
+	assert.Contains(t, output, "Reason:    2 consecutive NEEDS_MORE_WORK verdicts")
+}
+
+// TestPrintStatusBanner_Escalations verifies recorded escalations are listed
+func TestPrintStatusBanner_Escalations(t *testing.T) {
+	output := captureStderr(t, func() {
+		PrintStatusBanner(StatusInfo{
+			SessionID: "s",
+			Status:    "IN_PROGRESS",
+			Escalations: []EscalationInfo{
+				{Iteration: 3, From: "haiku", To: "sonnet"},
+				{Iteration: 6, From: "sonnet", To: "opus"},
+			},
+		})
+	})
+
+	assert.Contains(t, output, "Escalated:  haiku -> sonnet (iteration 3)")
+	assert.Contains(t, output, "Escalated:  sonnet -> opus (iteration 6)")
+}
+
+// TestPrintFailoverBanner verifies the failover details are shown
+func TestPrintFailoverBanner(t *testing.T) {
+	output := captureStderr(t, func() {
+		PrintFailoverBanner("claude", "codex", 4, "health check failed: probe prompt: no answer")
+	})
+
+	assert.Contains(t, output, "Failing over to the fallback AI")
+	assert.Contains(t, output, "AI:        claude -> codex")
+	assert.Contains(t, output, "Iteration: 4")
+	assert.Contains(t, output, "Reason:    health check failed: probe prompt: no answer")
+	assert.Contains(t, output, "The rest of the session runs on codex")
+}
+
+// TestPrintStatusBanner_Progress verifies the estimated progress and pace
+// are shown
+func TestPrintStatusBanner_Progress(t *testing.T) {
+	output := captureStderr(t, func() {
+		PrintStatusBanner(StatusInfo{
+			SessionID: "s",
+			Status:    "IN_PROGRESS",
+			Progress: &ProgressInfo{
+				Summary:   "25% (3/12 tasks), ETA 9h0m0s (~9 iteration(s))",
+				Iteration: time.Hour,
+				Impl:      50 * time.Minute,
+				Val:       10 * time.Minute,
+			},
+		})
+	})
+
+	assert.Contains(t, output, "Progress:   25% (3/12 tasks), ETA 9h0m0s (~9 iteration(s))")
+	assert.Contains(t, output, "Pace:       1h0m0s per iteration (impl 50m0s, val 10m0s)")
+
+	output = captureStderr(t, func() {
+		PrintStatusBanner(StatusInfo{SessionID: "s", Progress: &ProgressInfo{Summary: "0% (0/4 tasks), ETA unknown"}})
+	})
+	assert.Contains(t, output, "Progress:   0% (0/4 tasks), ETA unknown")
+	assert.NotContains(t, output, "Pace:")
+}
+
+// TestPrintStatusBanner_Failover verifies a recorded failover is listed
+func TestPrintStatusBanner_Failover(t *testing.T) {
+	output := captureStderr(t, func() {
+		PrintStatusBanner(StatusInfo{
+			SessionID: "s",
+			Status:    "IN_PROGRESS",
+			Failover:  &FailoverInfo{Iteration: 2, From: "claude", To: "codex", Reason: "health check failed: timeout"},
+		})
+	})
+
+	assert.Contains(t, output, "Failover:   claude -> codex (iteration 2): health check failed: timeout")
+}
+
+// TestPrintStatusBanner_Validators verifies the rotating validators' history
+// is listed
+func TestPrintStatusBanner_Validators(t *testing.T) {
+	output := captureStderr(t, func() {
+		PrintStatusBanner(StatusInfo{
+			SessionID: "s",
+			Status:    "IN_PROGRESS",
+			Validators: []ValidatorInfo{
+				{Iteration: 1, Validator: "claude/sonnet", Verdict: "NEEDS_MORE_WORK"},
+				{Iteration: 2, Validator: "codex/gpt-5", Verdict: "COMPLETE"},
+			},
+		})
+	})
+
+	assert.Contains(t, output, "Validated:  NEEDS_MORE_WORK by claude/sonnet (iteration 1)")
+	assert.Contains(t, output, "Validated:  COMPLETE by codex/gpt-5 (iteration 2)")
+}
+
+// TestPrintStatusBanner_Retries verifies the retry telemetry summary
+func TestPrintStatusBanner_Retries(t *testing.T) {
+	output := captureStderr(t, func() {
+		PrintStatusBanner(StatusInfo{
+			SessionID: "s",
+			Status:    "IN_PROGRESS",
+			Retries: RetryInfo{
+				Total:     4,
+				Classes:   map[string]int{"transient": 3, "rate_limit": 1},
+				GaveUp:    1,
+				LastClass: "transient",
+				LastError: "claude command failed: exit status 1",
+				LastAt:    "2026-01-01T12:00:00Z",
+			},
+		})
+	})
+
+	assert.Contains(t, output, "Retries:    4 (rate_limit 1, transient 3), 1 gave up")
+	assert.Contains(t, output, "Last error: transient at 2026-01-01T12:00:00Z: claude command failed: exit status 1")
+}
+
+// TestPrintStatusBanner_Artifacts verifies kept artifacts are listed
+func TestPrintStatusBanner_Artifacts(t *testing.T) {
+	output := captureStderr(t, func() {
+		PrintStatusBanner(StatusInfo{
+			SessionID: "s",
+			Status:    "IN_PROGRESS",
+			Artifacts: []ArtifactInfo{
+				{Iteration: 2, Phase: "validation", Kind: "screenshot", Path: ".ralph-loop/iteration-002/artifacts/login.png"},
+				{Iteration: 3, Phase: "implementation", Path: ".ralph-loop/iteration-003/artifacts/junit.xml"},
+				{Iteration: 3, Phase: "implementation", Kind: "screenshot", Path: ".ralph-loop/iteration-003/artifacts/home.png", Diff: "regression 12.40%"},
+			},
+		})
+	})
+
+	assert.Contains(t, output, "Artifacts:  3")
+	assert.Contains(t, output, "home.png\n             regression 12.40%\n")
+	assert.Contains(t, output, "iter 2   validation     screenshot .ralph-loop/iteration-002/artifacts/login.png")
+	assert.Contains(t, output, "iter 3   implementation -          .ralph-loop/iteration-003/artifacts/junit.xml")
+}
+
+func TestPrintSpecSummary(t *testing.T) {
+	output := captureStderr(t, func() {
+		PrintSpecSummary([]SpecInfo{
+			{TasksFile: "specs/001-core/tasks.md", Status: "done", FirstIteration: 1, LastIteration: 3, Done: 5, Total: 5},
+			{TasksFile: "specs/002-api/tasks.md", Status: "active", FirstIteration: 4, LastIteration: 4, Done: 2, Total: 4},
+			{TasksFile: "specs/003-ui/tasks.md", Status: "pending", Total: 3},
+		})
+	})
+
+	assert.Contains(t, output, "Specs:      1 done, 1 active, 1 pending")
+	assert.Contains(t, output, "done     iter 1-3   5/5  specs/001-core/tasks.md")
+	assert.Contains(t, output, "active   iter 4     2/4  specs/002-api/tasks.md")
+	assert.Contains(t, output, "pending  -          0/3  specs/003-ui/tasks.md")
+}
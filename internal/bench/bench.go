@@ -0,0 +1,231 @@
+// Package bench runs the same tasks file under several implementation and
+// validation model pairs, each run in its own git worktree, and compares
+// how often and how cheaply each pair completes it.
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/gitdiff"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// Pair is one configuration of the matrix.
+type Pair struct {
+	Impl string `json:"implementation_model"`
+	Val  string `json:"validation_model"`
+}
+
+func (p Pair) String() string { return p.Impl + "/" + p.Val }
+
+// ParseMatrix parses comma-separated IMPL/VAL model pairs, e.g.
+// "opus/sonnet,sonnet/sonnet". A single model is used for both phases.
+func ParseMatrix(spec string) ([]Pair, error) {
+	var pairs []Pair
+	seen := map[Pair]bool{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		impl, val, ok := strings.Cut(entry, "/")
+		impl, val = strings.TrimSpace(impl), strings.TrimSpace(val)
+		if !ok {
+			val = impl
+		}
+		if impl == "" || val == "" || strings.Contains(val, "/") {
+			return nil, fmt.Errorf("%q: expected IMPL/VAL models, e.g. opus/sonnet", entry)
+		}
+		p := Pair{Impl: impl, Val: val}
+		if seen[p] {
+			return nil, fmt.Errorf("%s is listed twice", p)
+		}
+		seen[p] = true
+		pairs = append(pairs, p)
+	}
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("no model pairs given")
+	}
+	return pairs, nil
+}
+
+// Config describes a benchmark.
+type Config struct {
+	Pairs []Pair
+	// Runs is the number of runs per pair.
+	Runs int
+	// Dir is the directory ralph-loop is run from, inside a git
+	// repository; each run uses the same directory of a fresh worktree of
+	// the repository's HEAD.
+	Dir string
+	// TasksFile is the tasks file, inside the repository. Its current
+	// content is copied into every worktree.
+	TasksFile string
+	// StateDir is the state directory's name, relative to Dir. A config
+	// file in it is copied into every worktree, and every run keeps its
+	// state there, whatever STATE_DIR says.
+	StateDir string
+	// Args are extra ralph-loop flags for every run.
+	Args []string
+	// LogDir receives each run's output.
+	LogDir string
+	// Keep leaves the worktrees in place for inspection.
+	Keep bool
+	// Exec runs ralph-loop with args in dir, writing its output to out,
+	// and returns its exit code. err is only for failures to run it.
+	Exec func(ctx context.Context, dir string, args []string, out io.Writer) (code int, err error)
+	// Progress, if set, is called before each run.
+	Progress func(p Pair, run int)
+}
+
+// Result is the outcome of one run.
+type Result struct {
+	Pair
+	Run        int           `json:"run"`
+	ExitCode   int           `json:"exit_code"`
+	Iterations int           `json:"iterations"`
+	Cost       float64       `json:"cost_usd"`
+	HasCost    bool          `json:"has_cost"`
+	Duration   time.Duration `json:"duration_ns"`
+	Log        string        `json:"log"`
+	Worktree   string        `json:"worktree,omitempty"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// Run runs every pair cfg.Runs times, one run after the other. When ctx
+// is cancelled it returns the results so far with ctx's error.
+func Run(ctx context.Context, cfg Config) ([]Result, error) {
+	root, err := gitdiff.TopLevel(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("benchmarks need a git repository: %w", err)
+	}
+	root, _ = filepath.EvalSymlinks(root)
+	dir, _ := filepath.EvalSymlinks(absPath(cfg.Dir))
+	relDir, err := filepath.Rel(root, dir)
+	if err != nil {
+		return nil, err
+	}
+	tasks, _ := filepath.EvalSymlinks(absPath(cfg.TasksFile))
+	relTasks, err := filepath.Rel(root, tasks)
+	if err != nil || strings.HasPrefix(relTasks, "..") {
+		return nil, fmt.Errorf("tasks file %s is not inside the repository %s", cfg.TasksFile, root)
+	}
+	if err := os.MkdirAll(cfg.LogDir, 0755); err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, p := range cfg.Pairs {
+		for n := 1; n <= cfg.Runs; n++ {
+			if err := ctx.Err(); err != nil {
+				return results, err
+			}
+			if cfg.Progress != nil {
+				cfg.Progress(p, n)
+			}
+			results = append(results, runOnce(ctx, cfg, root, relDir, relTasks, p, n))
+		}
+	}
+	return results, ctx.Err()
+}
+
+// runOnce runs pair p in a new worktree of root.
+func runOnce(ctx context.Context, cfg Config, root, relDir, relTasks string, p Pair, n int) Result {
+	res := Result{Pair: p, Run: n}
+	name := fmt.Sprintf("%s-%s-run%d", safeName(p.Impl), safeName(p.Val), n)
+	res.Log = filepath.Join(cfg.LogDir, name+".log")
+	fail := func(err error) Result {
+		res.ExitCode, res.Error = -1, err.Error()
+		return res
+	}
+
+	parent, err := os.MkdirTemp("", "ralph-bench-")
+	if err != nil {
+		return fail(err)
+	}
+	wt := filepath.Join(parent, name)
+	if err := gitdiff.AddWorktree(root, wt); err != nil {
+		_ = os.RemoveAll(parent)
+		return fail(err)
+	}
+	if cfg.Keep {
+		res.Worktree = wt
+	} else {
+		defer func() {
+			_ = gitdiff.RemoveWorktree(root, wt)
+			_ = os.RemoveAll(parent)
+		}()
+	}
+
+	runDir := filepath.Join(wt, relDir)
+	if err := copyFile(filepath.Join(root, relTasks), filepath.Join(wt, relTasks)); err != nil {
+		return fail(err)
+	}
+	if err := copyFile(filepath.Join(cfg.Dir, cfg.StateDir, "config"), filepath.Join(runDir, cfg.StateDir, "config")); err != nil && !os.IsNotExist(err) {
+		return fail(err)
+	}
+
+	logFile, err := os.Create(res.Log)
+	if err != nil {
+		return fail(err)
+	}
+	defer logFile.Close()
+
+	args := append([]string{
+		"--tasks-file", filepath.Join(wt, relTasks),
+		"--implementation-model", p.Impl,
+		"--validation-model", p.Val,
+		"--state-dir", cfg.StateDir,
+		// The copied tasks file may differ from HEAD's.
+		"--allow-dirty",
+	}, cfg.Args...)
+	start := time.Now()
+	code, err := cfg.Exec(ctx, runDir, args, logFile)
+	res.Duration = time.Since(start)
+	if err != nil {
+		return fail(err)
+	}
+	res.ExitCode = code
+
+	stateDir := filepath.Join(runDir, cfg.StateDir)
+	if s, err := state.LoadState(stateDir); err == nil {
+		res.Iterations = s.Iteration
+	}
+	res.Cost, res.HasCost = state.SessionCost(stateDir)
+	return res
+}
+
+// safeName makes a model name usable in a file name.
+func safeName(model string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, model)
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// copyFile copies src to dst, creating dst's directory.
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0644)
+}
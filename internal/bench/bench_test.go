@@ -0,0 +1,171 @@
+package bench
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+func TestParseMatrix(t *testing.T) {
+	pairs, err := ParseMatrix(" opus/sonnet, sonnet ,gpt-5/o4-mini")
+	require.NoError(t, err)
+	assert.Equal(t, []Pair{{"opus", "sonnet"}, {"sonnet", "sonnet"}, {"gpt-5", "o4-mini"}}, pairs)
+	assert.Equal(t, "opus/sonnet", pairs[0].String())
+
+	for _, bad := range []string{"", " , ", "opus/", "/sonnet", "a/b/c", "opus/sonnet,opus/sonnet"} {
+		_, err := ParseMatrix(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+// benchRepo returns a git repository whose project directory, app, holds
+// a committed tasks file and a ralph-loop config file.
+func benchRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	app := filepath.Join(root, "app")
+	require.NoError(t, os.MkdirAll(filepath.Join(app, ".ralph-loop"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(app, "tasks.md"), []byte("- [ ] T001 Old\n"), 0644))
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.email=t@example.com", "-c", "user.name=t", "commit", "-q", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	// Uncommitted edits are benchmarked too.
+	require.NoError(t, os.WriteFile(filepath.Join(app, "tasks.md"), []byte("- [ ] T001 New\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(app, ".ralph-loop", "config"), []byte("MAX_ITERATIONS=5\n"), 0644))
+	return app
+}
+
+func TestRun(t *testing.T) {
+	app := benchRepo(t)
+	logDir := filepath.Join(t.TempDir(), "logs")
+	var dirs []string
+	var progress []string
+	cfg := Config{
+		Pairs:     []Pair{{"opus", "sonnet"}, {"sonnet", "sonnet"}},
+		Runs:      2,
+		Dir:       app,
+		TasksFile: filepath.Join(app, "tasks.md"),
+		StateDir:  ".ralph-loop",
+		Args:      []string{"--max-iterations", "3"},
+		LogDir:    logDir,
+		Progress:  func(p Pair, run int) { progress = append(progress, p.String()) },
+		Exec: func(ctx context.Context, dir string, args []string, out io.Writer) (int, error) {
+			dirs = append(dirs, dir)
+			joined := strings.Join(args, " ")
+			assert.Contains(t, joined, "--allow-dirty --max-iterations 3")
+			assert.Contains(t, joined, "--state-dir .ralph-loop", "runs keep their state in the worktree")
+
+			tasksFile := args[1]
+			data, err := os.ReadFile(tasksFile)
+			require.NoError(t, err)
+			assert.Equal(t, "- [ ] T001 New\n", string(data), "the working copy of the tasks file is used")
+			cfgData, err := os.ReadFile(filepath.Join(dir, ".ralph-loop", "config"))
+			require.NoError(t, err)
+			assert.Equal(t, "MAX_ITERATIONS=5\n", string(cfgData))
+
+			_, _ = io.WriteString(out, "ran "+joined)
+			stateDir := filepath.Join(dir, ".ralph-loop")
+			iterations, code := 2, 0
+			if strings.Contains(joined, "--implementation-model sonnet") {
+				iterations, code = 3, 2
+			}
+			require.NoError(t, state.SaveState(&state.SessionState{Iteration: iterations}, stateDir))
+			iterDir := filepath.Join(stateDir, "iteration-001")
+			require.NoError(t, os.MkdirAll(iterDir, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(iterDir, "implementation-output.txt.stream.json"),
+				[]byte(`{"type":"result","total_cost_usd":0.5}`+"\n"), 0644))
+			return code, nil
+		},
+	}
+
+	results, err := Run(context.Background(), cfg)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.Equal(t, []string{"opus/sonnet", "opus/sonnet", "sonnet/sonnet", "sonnet/sonnet"}, progress)
+
+	first := results[0]
+	assert.Equal(t, Pair{"opus", "sonnet"}, first.Pair)
+	assert.Equal(t, 1, first.Run)
+	assert.Zero(t, first.ExitCode)
+	assert.Equal(t, 2, first.Iterations)
+	assert.True(t, first.HasCost)
+	assert.InDelta(t, 0.5, first.Cost, 1e-9)
+	assert.Equal(t, filepath.Join(logDir, "opus-sonnet-run1.log"), first.Log)
+	assert.Empty(t, first.Worktree)
+	assert.Equal(t, 2, results[2].ExitCode)
+	assert.Equal(t, 3, results[2].Iterations)
+
+	log, err := os.ReadFile(first.Log)
+	require.NoError(t, err)
+	assert.Contains(t, string(log), "--implementation-model opus --validation-model sonnet")
+
+	for _, dir := range dirs {
+		assert.Equal(t, "app", filepath.Base(dir), "runs start in the same project directory")
+		assert.NoDirExists(t, dir, "worktrees are removed")
+	}
+	assert.NoFileExists(t, filepath.Join(app, ".ralph-loop", "current-state.json"), "the project is untouched")
+}
+
+func TestRun_KeepWorktrees(t *testing.T) {
+	app := benchRepo(t)
+	results, err := Run(context.Background(), Config{
+		Pairs:     []Pair{{"opus", "opus"}},
+		Runs:      1,
+		Dir:       app,
+		TasksFile: filepath.Join(app, "tasks.md"),
+		StateDir:  ".ralph-loop",
+		LogDir:    t.TempDir(),
+		Keep:      true,
+		Exec: func(ctx context.Context, dir string, args []string, out io.Writer) (int, error) {
+			return 0, nil
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NotEmpty(t, results[0].Worktree)
+	assert.DirExists(t, results[0].Worktree)
+	cmd := exec.Command("git", "worktree", "remove", "--force", results[0].Worktree)
+	cmd.Dir = app
+	require.NoError(t, cmd.Run())
+}
+
+func TestRun_Errors(t *testing.T) {
+	_, err := Run(context.Background(), Config{Dir: t.TempDir()})
+	assert.ErrorContains(t, err, "git repository")
+
+	app := benchRepo(t)
+	_, err = Run(context.Background(), Config{Dir: app, TasksFile: filepath.Join(t.TempDir(), "tasks.md")})
+	assert.ErrorContains(t, err, "not inside the repository")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := Run(ctx, Config{
+		Pairs: []Pair{{"opus", "opus"}}, Runs: 1, Dir: app, TasksFile: filepath.Join(app, "tasks.md"),
+		StateDir: ".ralph-loop", LogDir: t.TempDir(),
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, results)
+}
+
+func TestSafeName(t *testing.T) {
+	assert.Equal(t, "openai_gpt-5_high", safeName("openai/gpt-5:high"))
+}
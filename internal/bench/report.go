@@ -0,0 +1,155 @@
+package bench
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+)
+
+// Summary aggregates the runs of one pair.
+type Summary struct {
+	Pair
+	Runs      int `json:"runs"`
+	Completed int `json:"completed"`
+	// MeanIterations is over the completed runs only: the iterations it
+	// took to complete.
+	MeanIterations float64 `json:"mean_iterations_to_complete"`
+	// MeanCost is over the runs that reported a cost; HasCost is false
+	// when none did.
+	MeanCost     float64       `json:"mean_cost_usd"`
+	HasCost      bool          `json:"has_cost"`
+	MeanDuration time.Duration `json:"mean_duration_ns"`
+	// Failures counts the runs that did not complete by outcome, e.g.
+	// "MaxIterations".
+	Failures map[string]int `json:"failures,omitempty"`
+}
+
+// CompletionRate is the fraction of runs that completed.
+func (s Summary) CompletionRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return float64(s.Completed) / float64(s.Runs)
+}
+
+// Summarize aggregates results per pair, in the order pairs first appear.
+func Summarize(results []Result) []Summary {
+	var order []Pair
+	byPair := map[Pair][]Result{}
+	for _, r := range results {
+		if _, ok := byPair[r.Pair]; !ok {
+			order = append(order, r.Pair)
+		}
+		byPair[r.Pair] = append(byPair[r.Pair], r)
+	}
+
+	summaries := make([]Summary, 0, len(order))
+	for _, p := range order {
+		s := Summary{Pair: p}
+		var iterations, costRuns int
+		var cost float64
+		var duration time.Duration
+		for _, r := range byPair[p] {
+			s.Runs++
+			duration += r.Duration
+			if r.HasCost {
+				cost += r.Cost
+				costRuns++
+			}
+			if r.ExitCode == exitcode.Success && r.Error == "" {
+				s.Completed++
+				iterations += r.Iterations
+				continue
+			}
+			if s.Failures == nil {
+				s.Failures = map[string]int{}
+			}
+			s.Failures[outcome(r)]++
+		}
+		if s.Completed > 0 {
+			s.MeanIterations = float64(iterations) / float64(s.Completed)
+		}
+		if costRuns > 0 {
+			s.MeanCost, s.HasCost = cost/float64(costRuns), true
+		}
+		s.MeanDuration = duration / time.Duration(s.Runs)
+		summaries = append(summaries, s)
+	}
+	return summaries
+}
+
+// outcome names how a run ended.
+func outcome(r Result) string {
+	if r.Error != "" {
+		return "not run"
+	}
+	if name := exitcode.Name(r.ExitCode); name != "unknown" {
+		return name
+	}
+	return fmt.Sprintf("exit %d", r.ExitCode)
+}
+
+// WriteMarkdown writes the comparison report: one row per pair, then
+// every run.
+func WriteMarkdown(w io.Writer, tasksFile string, runs int, results []Result) {
+	fmt.Fprintf(w, "# ralph-loop benchmark\n\nTasks: `%s`, %d run(s) per model pair.\n\n", tasksFile, runs)
+	fmt.Fprintln(w, "| Implementation | Validation | Completed | Iterations to complete | Mean cost | Mean duration | Failures |")
+	fmt.Fprintln(w, "|---|---|---|---|---|---|---|")
+	for _, s := range Summarize(results) {
+		iterations, cost := "n/a", "n/a"
+		if s.Completed > 0 {
+			iterations = fmt.Sprintf("%.1f", s.MeanIterations)
+		}
+		if s.HasCost {
+			cost = fmt.Sprintf("$%.2f", s.MeanCost)
+		}
+		fmt.Fprintf(w, "| %s | %s | %d/%d (%.0f%%) | %s | %s | %s | %s |\n",
+			s.Impl, s.Val, s.Completed, s.Runs, 100*s.CompletionRate(), iterations, cost,
+			s.MeanDuration.Round(time.Second), failureList(s.Failures))
+	}
+
+	fmt.Fprintln(w, "\n## Runs")
+	fmt.Fprintln(w, "\n| Implementation | Validation | Run | Outcome | Iterations | Cost | Duration | Log |")
+	fmt.Fprintln(w, "|---|---|---|---|---|---|---|---|")
+	for _, r := range results {
+		cost := "n/a"
+		if r.HasCost {
+			cost = fmt.Sprintf("$%.2f", r.Cost)
+		}
+		result := outcome(r)
+		if r.ExitCode == exitcode.Success && r.Error == "" {
+			result = "Complete"
+		}
+		if r.Error != "" {
+			result += ": " + r.Error
+		}
+		fmt.Fprintf(w, "| %s | %s | %d | %s | %d | %s | %s | %s |\n",
+			r.Impl, r.Val, r.Run, result, r.Iterations, cost, r.Duration.Round(time.Second), r.Log)
+	}
+}
+
+// failureList formats failure counts as "MaxIterations ×2, Blocked ×1".
+func failureList(failures map[string]int) string {
+	if len(failures) == 0 {
+		return "-"
+	}
+	names := make([]string, 0, len(failures))
+	for name := range failures {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if failures[names[i]] != failures[names[j]] {
+			return failures[names[i]] > failures[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s ×%d", name, failures[name])
+	}
+	return strings.Join(parts, ", ")
+}
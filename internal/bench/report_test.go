@@ -0,0 +1,63 @@
+package bench
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var opusSonnet, sonnetOnly = Pair{"opus", "sonnet"}, Pair{"sonnet", "sonnet"}
+
+func testResults() []Result {
+	return []Result{
+		{Pair: opusSonnet, Run: 1, Iterations: 2, Cost: 1.0, HasCost: true, Duration: 10 * time.Minute, Log: "a.log"},
+		{Pair: opusSonnet, Run: 2, Iterations: 3, Cost: 2.0, HasCost: true, Duration: 20 * time.Minute, Log: "b.log"},
+		{Pair: sonnetOnly, Run: 1, ExitCode: 2, Iterations: 5, Duration: 30 * time.Minute, Log: "c.log"},
+		{Pair: sonnetOnly, Run: 2, ExitCode: 20, Iterations: 1, Duration: 5 * time.Minute, Log: "d.log"},
+		{Pair: sonnetOnly, Run: 3, ExitCode: -1, Error: "git worktree: locked", Log: "e.log"},
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	summaries := Summarize(testResults())
+	require.Len(t, summaries, 2)
+
+	opus := summaries[0]
+	assert.Equal(t, opusSonnet, opus.Pair)
+	assert.Equal(t, 2, opus.Runs)
+	assert.Equal(t, 2, opus.Completed)
+	assert.Equal(t, 1.0, opus.CompletionRate())
+	assert.Equal(t, 2.5, opus.MeanIterations)
+	assert.True(t, opus.HasCost)
+	assert.Equal(t, 1.5, opus.MeanCost)
+	assert.Equal(t, 15*time.Minute, opus.MeanDuration)
+	assert.Empty(t, opus.Failures)
+
+	sonnet := summaries[1]
+	assert.Equal(t, 3, sonnet.Runs)
+	assert.Zero(t, sonnet.Completed)
+	assert.Zero(t, sonnet.MeanIterations, "no run completed")
+	assert.False(t, sonnet.HasCost)
+	assert.Equal(t, map[string]int{"MaxIterations": 1, "exit 20": 1, "not run": 1}, sonnet.Failures)
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	WriteMarkdown(&buf, "specs/tasks.md", 2, testResults())
+	out := buf.String()
+
+	assert.Contains(t, out, "Tasks: `specs/tasks.md`, 2 run(s) per model pair.")
+	assert.Contains(t, out, "| opus | sonnet | 2/2 (100%) | 2.5 | $1.50 | 15m0s | - |")
+	assert.Contains(t, out, "| sonnet | sonnet | 0/3 (0%) | n/a | n/a | 11m40s | MaxIterations ×1, exit 20 ×1, not run ×1 |")
+	assert.Contains(t, out, "| opus | sonnet | 1 | Complete | 2 | $1.00 | 10m0s | a.log |")
+	assert.Contains(t, out, "| sonnet | sonnet | 3 | not run: git worktree: locked | 0 | n/a | 0s | e.log |")
+}
+
+func TestFailureList(t *testing.T) {
+	assert.Equal(t, "-", failureList(nil))
+	assert.Equal(t, "Blocked ×2, Escalate ×1, MaxIterations ×1",
+		failureList(map[string]int{"MaxIterations": 1, "Blocked": 2, "Escalate": 1}))
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/model"
+)
+
+// modelFlag ties a model flag to the flag choosing its AI and to the
+// config keys holding either. followsAI means the phase runs on --ai when
+// its own AI is not chosen.
+type modelFlag struct {
+	aiFlag    string
+	aiKey     string
+	modelKey  string
+	followsAI bool
+}
+
+var modelFlags = map[string]modelFlag{
+	"implementation-model":        {"ai", "AI_CLI", "IMPL_MODEL", false},
+	"validation-model":            {"validation-ai", "VAL_AI", "VAL_MODEL", true},
+	"cross-model":                 {"cross-validation-ai", "CROSS_AI", "CROSS_MODEL", false},
+	"final-plan-validation-model": {"final-plan-validation-ai", "FINAL_PLAN_AI", "FINAL_PLAN_MODEL", false},
+	"tasks-validation-model":      {"tasks-validation-ai", "TASKS_VAL_AI", "TASKS_VAL_MODEL", true},
+	"summary-model":               {"summary-ai", "SUMMARY_AI", "SUMMARY_MODEL", true},
+	"triage-model":                {"triage-ai", "TRIAGE_AI", "TRIAGE_MODEL", true},
+}
+
+var aiFlags = []string{"ai", "validation-ai", "cross-validation-ai", "final-plan-validation-ai", "tasks-validation-ai", "summary-ai", "triage-ai", "fallback-ai"}
+
+// RegisterCompletions adds shell completion for flag values: AI backends,
+// issue providers, the profiles defined in configFiles (and --config), and
+// for model flags the known models of the AI chosen on the command line or
+// in those files, plus any model they set.
+func RegisterCompletions(cmd *cobra.Command, configFiles ...string) {
+	fixed := func(values ...string) cobra.CompletionFunc {
+		return cobra.FixedCompletions(values, cobra.ShellCompDirectiveNoFileComp)
+	}
+	for _, name := range aiFlags {
+		_ = cmd.RegisterFlagCompletionFunc(name, fixed(model.Providers...))
+	}
+	_ = cmd.RegisterFlagCompletionFunc("issue-provider", fixed("github", "gitlab", "gitea"))
+	_ = cmd.RegisterFlagCompletionFunc("protected-paths-action", fixed(config.ProtectedRevert, config.ProtectedEscalate))
+	_ = cmd.RegisterFlagCompletionFunc("container-runtime", fixed("docker", "podman"))
+	_ = cmd.RegisterFlagCompletionFunc("container-pull", fixed(config.ContainerPullMissing, config.ContainerPullAlways, config.ContainerPullNever))
+	_ = cmd.RegisterFlagCompletionFunc("spec-drift", fixed(config.SpecDriftOff, config.SpecDriftPause, config.SpecDriftRegenerate))
+	_ = cmd.MarkFlagDirname("spec-dir")
+	_ = cmd.RegisterFlagCompletionFunc("profile", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return config.ProfileNames(withConfigFlag(cmd, configFiles)...), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	for name, mf := range modelFlags {
+		_ = cmd.RegisterFlagCompletionFunc(name, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeModels(cmd, mf, configFiles), cobra.ShellCompDirectiveNoFileComp
+		})
+	}
+}
+
+// withConfigFlag appends the --config file, when given, to configFiles.
+func withConfigFlag(cmd *cobra.Command, configFiles []string) []string {
+	if f := cmd.Flags().Lookup("config"); f != nil && f.Changed {
+		return append(configFiles[:len(configFiles):len(configFiles)], f.Value.String())
+	}
+	return configFiles
+}
+
+// completeModels lists the models for a model flag, most specific first:
+// the one set in the config files, then the known models of its AI.
+func completeModels(cmd *cobra.Command, mf modelFlag, configFiles []string) []string {
+	configFiles = withConfigFlag(cmd, configFiles)
+	settings := map[string]string{}
+	for _, path := range configFiles {
+		if m, err := config.LoadFile(path); err == nil {
+			for k, v := range m {
+				settings[k] = v
+			}
+		}
+	}
+
+	chosenAI := func(flag, key string) string {
+		if f := cmd.Flags().Lookup(flag); f != nil && f.Changed {
+			return f.Value.String()
+		}
+		return settings[key]
+	}
+	ai := chosenAI(mf.aiFlag, mf.aiKey)
+	if ai == "" && mf.followsAI {
+		ai = chosenAI("ai", "AI_CLI")
+	}
+
+	var models []string
+	seen := map[string]bool{}
+	add := func(names ...string) {
+		for _, n := range names {
+			if n != "" && !seen[n] {
+				seen[n] = true
+				models = append(models, n)
+			}
+		}
+	}
+	add(settings[mf.modelKey])
+	if model.IsProvider(ai) {
+		add(model.KnownModels(ai)...)
+	} else {
+		var known []string
+		for _, p := range model.Providers {
+			known = append(known, model.KnownModels(p)...)
+		}
+		sort.Strings(known)
+		add(known...)
+	}
+	return models
+}
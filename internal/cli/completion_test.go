@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+)
+
+// completeFlag returns the completions offered for flag after parsing args.
+func completeFlag(t *testing.T, cmd *cobra.Command, flag string, args ...string) []string {
+	t.Helper()
+	require.NoError(t, cmd.ParseFlags(args))
+	fn, ok := cmd.GetFlagCompletionFunc(flag)
+	require.True(t, ok, "no completion registered for --%s", flag)
+	values, directive := fn(cmd, nil, "")
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+	return values
+}
+
+func newCompletionTestCmd(configFiles ...string) *cobra.Command {
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, config.NewDefaultConfig())
+	RegisterCompletions(cmd, configFiles...)
+	return cmd
+}
+
+func TestRegisterCompletions_AIFlags(t *testing.T) {
+	cmd := newCompletionTestCmd()
+	for _, flag := range []string{"ai", "validation-ai", "cross-validation-ai", "final-plan-validation-ai", "tasks-validation-ai", "summary-ai"} {
+		assert.Equal(t, []string{"claude", "codex", "amazonq", "copilot"}, completeFlag(t, cmd, flag), flag)
+	}
+	assert.Equal(t, []string{"github", "gitlab", "gitea"}, completeFlag(t, cmd, "issue-provider"))
+	assert.Equal(t, []string{"revert", "escalate"}, completeFlag(t, cmd, "protected-paths-action"))
+}
+
+func TestRegisterCompletions_ModelsOfChosenAI(t *testing.T) {
+	cmd := newCompletionTestCmd()
+	models := completeFlag(t, cmd, "implementation-model", "--ai", "codex")
+	assert.Contains(t, models, "gpt-5")
+	assert.NotContains(t, models, "opus")
+
+	models = completeFlag(t, cmd, "cross-model", "--cross-validation-ai", "claude")
+	assert.Equal(t, []string{"opus", "sonnet", "haiku"}, models)
+}
+
+func TestRegisterCompletions_SummaryModelFollowsAI(t *testing.T) {
+	models := completeFlag(t, newCompletionTestCmd(), "summary-model", "--ai", "claude")
+	assert.Equal(t, []string{"opus", "sonnet", "haiku"}, models)
+
+	// The phase's own AI wins over --ai.
+	models = completeFlag(t, newCompletionTestCmd(), "summary-model", "--ai", "claude", "--summary-ai", "codex")
+	assert.Contains(t, models, "gpt-5")
+	assert.NotContains(t, models, "opus")
+}
+
+func TestRegisterCompletions_ModelsWithoutAI(t *testing.T) {
+	models := completeFlag(t, newCompletionTestCmd(), "tasks-validation-model")
+	assert.Contains(t, models, "opus")
+	assert.Contains(t, models, "gpt-5")
+}
+
+func TestRegisterCompletions_ModelsFromConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	global := filepath.Join(dir, "global")
+	project := filepath.Join(dir, "project")
+	explicit := filepath.Join(dir, "explicit")
+	require.NoError(t, os.WriteFile(global, []byte("AI_CLI=codex\nVAL_MODEL=gpt-4.1\n"), 0644))
+	require.NoError(t, os.WriteFile(project, []byte("VAL_MODEL=gpt-5-mini\n"), 0644))
+	require.NoError(t, os.WriteFile(explicit, []byte("CROSS_MODEL=claude-custom\n"), 0644))
+
+	cmd := newCompletionTestCmd(global, project, filepath.Join(dir, "missing"))
+	models := completeFlag(t, cmd, "validation-model")
+	assert.Equal(t, "gpt-5-mini", models[0], "the project setting overrides the global one")
+	assert.NotContains(t, models, "gpt-4.1")
+	assert.NotContains(t, models, "opus", "the configured AI is codex")
+
+	models = completeFlag(t, cmd, "cross-model", "--config", explicit)
+	assert.Equal(t, "claude-custom", models[0])
+}
+
+func TestRegisterCompletions_ProfilesFromConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	global := filepath.Join(dir, "global")
+	explicit := filepath.Join(dir, "explicit")
+	require.NoError(t, os.WriteFile(global, []byte("[profile.thorough]\nVALIDATORS=3\n[profile.cheap]\nIMPL_MODEL=sonnet\n"), 0644))
+	require.NoError(t, os.WriteFile(explicit, []byte("[profile.ci]\nMAX_ITERATIONS=5\n"), 0644))
+
+	cmd := newCompletionTestCmd(global, filepath.Join(dir, "missing"))
+	assert.Equal(t, []string{"cheap", "thorough"}, completeFlag(t, cmd, "profile"))
+	assert.Equal(t, []string{"cheap", "ci", "thorough"}, completeFlag(t, cmd, "profile", "--config", explicit))
+}
@@ -3,78 +3,267 @@ package cli
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/model"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
 )
 
-// BindFlags registers all 32 CLI flags on the given cobra command.
+// BindFlags registers all 124 CLI flags on the given cobra command.
 // The flags directly modify fields in the provided config pointer.
 // Call ValidateFlags after parsing to check flag combinations.
 func BindFlags(cmd *cobra.Command, cfg *config.Config) {
 	flags := cmd.Flags()
 
 	// AI Provider & Models
-	flags.StringVar(&cfg.AIProvider, "ai", "claude", "AI CLI to use: claude or codex")
+	flags.StringVar(&cfg.AIProvider, "ai", "claude", "AI CLI to use: claude, codex, amazonq (q chat) or copilot (gh copilot)")
 	flags.StringVar(&cfg.ImplModel, "implementation-model", "", "Model for implementation phase")
 	flags.StringVar(&cfg.ValModel, "validation-model", "", "Model for validation phase")
+	flags.StringVar(&cfg.ValAI, "validation-ai", "", "AI CLI for validation")
+	flags.StringVar(&cfg.ImplModelLadder, "implementation-model-ladder", "", "Comma-separated implementation models to escalate through, cheapest first")
+	flags.IntVar(&cfg.EscalateAfter, "escalate-after", 2, "Consecutive NEEDS_MORE_WORK verdicts on the same tasks before escalating the model")
+	flags.IntVar(&cfg.Validators, "validators", 1, "Number of validators that vote on each iteration")
+	flags.StringVar(&cfg.ValidatorPool, "validator-pool", "", "Comma-separated ai[:model] entries assigned to validators round-robin")
+	flags.BoolVar(&cfg.ValRotate, "val-rotate", false, "Judge each iteration with the next --validator-pool entry in turn")
 	flags.StringVar(&cfg.CrossModel, "cross-model", "", "Model for cross-validation")
 	flags.StringVar(&cfg.CrossAI, "cross-validation-ai", "", "AI CLI for cross-validation")
+	flags.StringVar(&cfg.FallbackAI, "fallback-ai", "", "AI CLI taking over for the rest of the session when --ai fails its health check")
+	flags.IntVar(&cfg.FailoverAfter, "failover-after", 3, "Consecutive failed AI calls before --ai is health-checked for failover")
 	flags.StringVar(&cfg.FinalPlanAI, "final-plan-validation-ai", "", "AI CLI for final plan validation")
 	flags.StringVar(&cfg.FinalPlanModel, "final-plan-validation-model", "", "Model for final plan validation")
 	flags.StringVar(&cfg.TasksValAI, "tasks-validation-ai", "", "AI CLI for tasks validation")
 	flags.StringVar(&cfg.TasksValModel, "tasks-validation-model", "", "Model for tasks validation")
+	flags.StringVar(&cfg.SummaryModel, "summary-model", "", "Model that summarizes long implementation output for the validator")
+	flags.StringVar(&cfg.SummaryAI, "summary-ai", "", "AI CLI that summarizes long implementation output")
+	flags.BoolVar(&cfg.Triage, "triage", false, "Check each iteration with a cheap model first and skip validation when the claimed work is obviously missing")
+	flags.StringVar(&cfg.TriageAI, "triage-ai", "", "AI CLI for triage")
+	flags.StringVar(&cfg.TriageModel, "triage-model", "", "Model for triage")
 
 	// Iteration Limits
 	flags.IntVar(&cfg.MaxIterations, "max-iterations", 20, "Maximum loop iterations")
 	flags.IntVar(&cfg.MaxInadmissible, "max-inadmissible", 5, "Max inadmissible verdicts before exit 6")
 	flags.IntVar(&cfg.MaxClaudeRetry, "max-claude-retry", 10, "Max retries per AI invocation")
 	flags.IntVar(&cfg.MaxTurns, "max-turns", 100, "Max agent turns per AI invocation")
+	flags.IntVar(&cfg.MaxTaskAttempts, "max-task-attempts", 3, "Consecutive incomplete iterations before a task is skipped (0 disables)")
+	flags.IntVar(&cfg.MaxFormatRetries, "max-format-retries", 2, "Times the validator is asked to repair unusable output before the iteration counts as inadmissible")
 	flags.IntVar(&cfg.InactivityTimeout, "inactivity-timeout", 1800, "Seconds of inactivity before kill")
+	flags.IntVar(&cfg.HangTimeout, "hang-timeout", 600, "Seconds without output or CPU and I/O activity before an AI CLI is killed as hung (0 = off)")
+	flags.IntVar(&cfg.IterationTimeout, "iteration-timeout", 0, "Seconds before an iteration's implementation and validation are cut off (0 = none)")
+	flags.IntVar(&cfg.ImplTimeout, "impl-timeout", 0, "Seconds before the implementation phase is cut off (0 = none)")
+	flags.IntVar(&cfg.ValTimeout, "val-timeout", 0, "Seconds before the validation phase is cut off (0 = none)")
+	flags.DurationVar(&cfg.MaxDuration, "max-duration", 0, "Wall-clock budget (e.g. 2h); no iteration starts that would exceed it")
+	flags.DurationVar(&cfg.ShutdownGrace, "shutdown-grace", 5*time.Minute, "How long after SIGTERM the AI call in progress may finish before it is interrupted")
 
 	// Input Files
-	flags.StringVar(&cfg.TasksFile, "tasks-file", "", "Path to tasks.md")
+	flags.Var(tasksFilesValue{cfg}, "tasks-file", "Path to tasks.md; repeat or use a glob (specs/**/tasks.md) for several")
+	flags.StringVar(&cfg.SpecDir, "spec-dir", "", "Spec-kit feature folder with tasks.md, plan.md and spec.md (e.g. specs/001-feature)")
 	flags.StringVar(&cfg.OriginalPlanFile, "original-plan-file", "", "Path to original plan (mutually exclusive with --github-issue)")
-	flags.StringVar(&cfg.GithubIssue, "github-issue", "", "GitHub issue URL or number")
+	flags.StringVar(&cfg.GithubIssue, "github-issue", "", "Issue URL, owner/repo#number or number (GitHub, GitLab or Gitea)")
+	flags.StringVar(&cfg.JiraIssue, "jira-issue", "", "Jira issue key or browse URL (e.g. PROJ-123) used as the spec")
+	flags.StringVar(&cfg.IssueProvider, "issue-provider", "", "Issue provider for --github-issue: github, gitlab or gitea (default: detect from URL)")
+	flags.BoolVar(&cfg.PlanFromIssue, "plan-from-issue", false, "Generate plan.md and tasks.md from --github-issue before the loop")
+	flags.StringVar(&cfg.SpecDrift, "spec-drift", config.SpecDriftOff, "When the spec changes mid-run, revalidate the tasks and pause or regenerate them: off, pause or regenerate")
 	flags.StringVar(&cfg.LearningsFile, "learnings-file", ".ralph-loop/learnings.md", "Path to learnings file")
+	flags.StringVar(&cfg.LearningsTags, "learnings-tags", "", "Tags of the global learnings to use, e.g. go,react (default: detect)")
+	flags.IntVar(&cfg.ContextMaxTokens, "context-max-tokens", 0, "Pack excerpts of files related to the pending tasks into prompts, up to this many tokens (0 = off)")
+	flags.IntVar(&cfg.ValDiffMaxTokens, "val-diff-max-tokens", 10000, "Show the validator the iteration's git diff, up to this many tokens (0 = off)")
+	flags.IntVar(&cfg.ImplOutputMaxTokens, "impl-output-max-tokens", 30000, "Give the validator a summary of implementation output larger than this many estimated tokens (0 = never)")
+	flags.IntVar(&cfg.LearningsMaxTokens, "learnings-max-tokens", 8000, "Summarize learnings larger than this many estimated tokens (0 = never)")
 	flags.StringVar(&cfg.ConfigFile, "config", "", "Path to additional config file")
+	flags.StringVar(&cfg.Profile, "profile", "", "Apply the [profile.NAME] section of the config files (e.g. cheap, thorough)")
+	// The subcommands read the session state too.
+	cmd.PersistentFlags().StringVar(&cfg.StateDir, "state-dir", "", "Keep the session state in this directory, or per project under $XDG_STATE_HOME with xdg (default: .ralph-loop)")
 
 	// Feature Toggles
 	flags.BoolVarP(&cfg.Verbose, "verbose", "v", false, "Pass verbose flag to AI CLI")
+	flags.BoolVar(&cfg.ApplyPatch, "apply-patch", false, "Have the implementer output a unified diff that ralph-loop applies")
+	flags.IntVar(&cfg.LogMaxMB, "log-max-mb", 10, "Rotate an iteration's phase logs (impl.log, val.log, ...) at this size in MB (0 = never)")
+	flags.BoolVar(&cfg.LogGzip, "log-gzip", false, "Gzip the phase logs of finished iterations")
+	flags.IntVar(&cfg.OutputGzipKB, "output-gzip-kb", 0, "Gzip the AI outputs and logs of finished iterations of this size in KB or more (0 = never)")
+	flags.BoolVar(&cfg.DedupOutputs, "dedup-outputs", false, "Hard-link the files of a finished iteration identical to the previous iteration's")
+	flags.IntVar(&cfg.RetainIterations, "retain-iterations", 0, "Keep the directories of the last N iterations only (0 = all)")
+	flags.IntVar(&cfg.RetainMB, "retain-mb", 0, "Keep the newest iteration directories up to this total size in MB (0 = no limit)")
+	flags.BoolVar(&cfg.TUI, "tui", false, "Show a live terminal dashboard instead of the scrolling log")
+	flags.StringVar(&cfg.SandboxCmd, "sandbox-cmd", "", "Command that wraps every AI CLI invocation (e.g. firejail, bwrap, docker run)")
+	flags.StringVar(&cfg.SandboxEnv, "sandbox-env", "", "Comma-separated environment variables passed into the sandbox")
+	flags.StringVar(&cfg.ContainerImage, "container-image", "", "Run the AI CLIs in containers of this image with the project bind-mounted")
+	flags.StringVar(&cfg.ContainerRuntime, "container-runtime", "", "Container runtime: docker or podman (default: whichever is installed)")
+	flags.StringVar(&cfg.ContainerPull, "container-pull", config.ContainerPullMissing, "When to pull --container-image: missing, always or never")
+	flags.StringVar(&cfg.K8sJobImage, "k8s-job-image", "", "Run each implementation call as a Kubernetes Job of this image")
+	flags.StringVar(&cfg.K8sJobNamespace, "k8s-job-namespace", "", "Namespace of the implementation jobs (default: the kubectl context's)")
+	flags.StringVar(&cfg.K8sJobSecrets, "k8s-job-secrets", "", "Comma-separated secrets whose keys become environment variables in the jobs (e.g. API keys)")
+	flags.StringVar(&cfg.K8sJobPVC, "k8s-job-pvc", "", "PersistentVolumeClaim holding the project, shared with the jobs")
+	flags.StringVar(&cfg.RemoteAgent, "remote-agent", "", "Run every AI call on the ralph-loop agent daemon at this URL (token in RALPH_AGENT_TOKEN)")
+	flags.StringVar(&cfg.AllowedTools, "allowed-tools", "", "Comma-separated tools the AI CLIs may use, in each CLI's syntax (e.g. Edit,Bash(go test:*))")
+	flags.StringVar(&cfg.DeniedCommands, "denied-commands", "", "Comma-separated shell commands the AI CLIs must not run (e.g. rm,git push)")
+	flags.StringVar(&cfg.MetricsAddr, "metrics-addr", "", "Serve Prometheus metrics on this address (e.g. 127.0.0.1:9464)")
+	flags.StringVar(&cfg.MetricsFile, "metrics-file", "", "Write Prometheus metrics to this textfile on exit")
+	flags.StringVar(&cfg.ProtectedPaths, "protected-paths", "", "Comma-separated paths or globs the implementer must not change (e.g. .github/workflows,infra/prod)")
+	flags.StringVar(&cfg.ProtectedPathsAction, "protected-paths-action", config.ProtectedRevert, "What a change to a protected path does: revert or escalate")
+	flags.BoolVar(&cfg.AllowDirty, "allow-dirty", false, "Start even with uncommitted changes, on a protected branch or during a rebase or merge")
+	flags.StringVar(&cfg.ProtectedBranches, "protected-branches", "main,master", "Comma-separated branches a new session refuses to start on")
+	flags.StringVar(&cfg.EvidencePatterns, "evidence-patterns", "Deploy,Run tests,Verify", "Comma-separated task text prefixes of tasks that need a RALPH_EVIDENCE entry (empty: none)")
+	flags.BoolVar(&cfg.AnnotateTasks, "annotate-tasks", false, "Note in tasks.md the iteration each task was completed in and its evidence")
+	flags.StringVar(&cfg.HookPreIteration, "hook-pre-iteration", "", "Shell command run before each iteration; failing stops the loop")
+	flags.StringVar(&cfg.HookPostImplementation, "hook-post-implementation", "", "Shell command run after implementation; failing sends its output back to the implementer")
+	flags.StringVar(&cfg.HookPostValidation, "hook-post-validation", "", "Shell command run after validation; failing turns COMPLETE into NEEDS_MORE_WORK")
+	flags.StringVar(&cfg.HookOnExit, "hook-on-exit", "", "Shell command run when ralph-loop exits")
+	flags.IntVar(&cfg.HookTimeout, "hook-timeout", 600, "Seconds each hook may run (0 = no limit)")
+	flags.StringVar(&cfg.PolicyFile, "policy-file", "", "YAML file of inadmissible practice policies, merged into the built-in ones")
+	flags.StringVar(&cfg.CustomVerdicts, "custom-verdicts", "", "Project verdicts and their actions (e.g. \"SECURITY_REVIEW_NEEDED=exit-code 20: needs a security review\")")
+	flags.StringVar(&cfg.ExternalValidators, "external-validators", "", "Commands validating each iteration next to the AI validator, as ';'-separated NAME=COMMAND entries")
+	flags.StringVar(&cfg.VerifyCmds, "verify-cmds", "", "';'-separated test commands run after each implementation phase; their results are shown to the validator")
+	flags.StringVar(&cfg.VerifyReports, "verify-reports", "", "Comma-separated globs of the TRX or JUnit XML reports the verify commands write")
+	flags.Float64Var(&cfg.MinConfidence, "min-confidence", 0, "Cross-validate COMPLETE verdicts whose confidence is below this (0-1), even with cross-validation off")
+	flags.StringVar(&cfg.AutoCrossValidate, "auto-cross-validate", "", "Suspicious completions to cross-validate even with cross-validation off: first-complete, no-changes, max-checked=N")
+	flags.Float64Var(&cfg.ScreenshotThreshold, "screenshot-threshold", 0.5, "Percent of pixels a screenshot may differ from its baseline and still count as unchanged")
+	flags.StringVar(&cfg.Serve, "serve", "", "Serve a status page with live log and cancel/escalate controls (e.g. :8080)")
 
 	// Negation flags need special handling via Changed detection
-	var noLearnings, noCrossValidate bool
+	var noLearnings, noGlobalLearnings, noProjectMemory, noCrossValidate, noPRComment, noCache bool
 	flags.BoolVar(&noLearnings, "no-learnings", false, "Disable learnings persistence")
+	flags.BoolVar(&noGlobalLearnings, "no-global-learnings", false, "Do not merge the global learnings library into prompts")
+	flags.BoolVar(&noProjectMemory, "no-project-memory", false, "Do not show the first implementation prompt what earlier sessions escalated on, blocked and learned")
 	flags.BoolVar(&noCrossValidate, "no-cross-validate", false, "Disable cross-validation phase")
+	flags.BoolVar(&noPRComment, "no-pr-comment", false, "Disable the summary comment on the branch's open PR")
+	flags.IntVar(&cfg.PRNumber, "pr", 0, "Pull request to post the summary comment on (default: from GITHUB_REF or the branch)")
+	flags.BoolVar(&cfg.GitHubCheck, "github-check", false, "Report the session as a GitHub check run, updated every iteration (needs GITHUB_TOKEN)")
+	flags.StringVar(&cfg.CoverageFile, "coverage-file", "", "Coverage report (Go profile, lcov, Cobertura) whose change the PR summary shows")
+	flags.BoolVar(&noCache, "no-cache", false, "Always call the AI, even for a prompt answered before over unchanged files")
+	flags.DurationVar(&cfg.CacheTTL, "cache-ttl", 24*time.Hour, "How long a cached response to a read-only phase's prompt is reused (0 = forever)")
 
 	// Scheduling
 	flags.StringVar(&cfg.StartAt, "start-at", "", "Schedule start time (ISO 8601, HH:MM, YYYY-MM-DD HH:MM)")
 	// Alias --at for --start-at
 	flags.StringVar(&cfg.StartAt, "at", "", "Alias for --start-at")
+	flags.StringVar(&cfg.PauseBetween, "pause-between", "", "Windows in which no iteration starts (e.g. \"09:00-18:00 weekdays\")")
 
 	// Notifications
 	flags.StringVar(&cfg.NotifyWebhook, "notify-webhook", "http://127.0.0.1:18789/webhook", "OpenClaw webhook URL")
 	flags.StringVar(&cfg.NotifyChannel, "notify-channel", "telegram", "Notification channel")
 	flags.StringVar(&cfg.NotifyChatID, "notify-chat-id", "", "Recipient chat ID")
+	flags.StringVar(&cfg.NotifyEvents, "notify-events", "exit,heartbeat", "Events to notify: names such as escalate,blocked,completed, or the groups all, exit and progress")
+	flags.DurationVar(&cfg.NotifyDigest, "notify-digest", 0, "Batch progress notifications into one digest per interval (e.g. 30m); 0 sends each at once")
+	flags.DurationVar(&cfg.HeartbeatInterval, "heartbeat-interval", 0, "Send a heartbeat with the iteration, phase and elapsed time this often (e.g. 30m; 0 = never)")
+	flags.IntVar(&cfg.HeartbeatIterations, "heartbeat-iterations", 0, "Send a heartbeat every N iterations (0 = never)")
 
 	// Session Management
 	flags.BoolVar(&cfg.Resume, "resume", false, "Resume from last interrupted session")
 	flags.BoolVar(&cfg.ResumeForce, "resume-force", false, "Resume even if tasks.md changed (implies --resume)")
 	flags.BoolVar(&cfg.Clean, "clean", false, "Delete state directory and start fresh")
+	flags.BoolVar(&cfg.RevalidatePlan, "revalidate-plan", false, "Run final-plan validation even when it confirmed the unchanged spec, tasks and plan before")
 	flags.BoolVar(&cfg.Status, "status", false, "Show session status and exit")
 	flags.BoolVar(&cfg.Cancel, "cancel", false, "Cancel active session and exit")
+	flags.BoolVar(&cfg.ForceUnlock, "force-unlock", false, "Take over the state lock held by another ralph-loop process")
+
+	// Recording
+	flags.StringVar(&cfg.Record, "record", "", "Save every AI prompt and output, per phase, in this directory")
+	flags.StringVar(&cfg.Replay, "replay", "", "Answer every AI call from a --record directory instead of running an AI CLI")
 }
 
+// tasksFilesValue backs the repeatable --tasks-file flag. Every value is
+// collected in TasksFiles and the first one is also kept in TasksFile, so
+// single-file sessions behave as before.
+type tasksFilesValue struct{ cfg *config.Config }
+
+func (v tasksFilesValue) String() string { return strings.Join(v.cfg.TasksFiles, ",") }
+
+func (v tasksFilesValue) Set(s string) error {
+	v.cfg.TasksFiles = append(v.cfg.TasksFiles, s)
+	v.cfg.TasksFile = v.cfg.TasksFiles[0]
+	return nil
+}
+
+func (v tasksFilesValue) Type() string { return "path" }
+
 // ValidateFlags checks for invalid flag combinations after parsing.
 // Must be called after cmd.Execute() or cmd.ParseFlags().
 func ValidateFlags(cmd *cobra.Command, cfg *config.Config) error {
+	if cfg.MinConfidence < 0 || cfg.MinConfidence > 1 {
+		return fmt.Errorf("--min-confidence must be between 0 and 1, got %g", cfg.MinConfidence)
+	}
+
+	// --container-image replaces --sandbox-cmd
+	if cfg.ContainerImage != "" && cfg.SandboxCmd != "" {
+		return fmt.Errorf("--container-image and --sandbox-cmd are mutually exclusive")
+	}
+	if cfg.ContainerRuntime != "" && cfg.ContainerRuntime != "docker" && cfg.ContainerRuntime != "podman" {
+		return fmt.Errorf("--container-runtime must be docker or podman, got %q", cfg.ContainerRuntime)
+	}
+	switch cfg.ContainerPull {
+	case config.ContainerPullMissing, config.ContainerPullAlways, config.ContainerPullNever:
+	default:
+		return fmt.Errorf("--container-pull must be missing, always or never, got %q", cfg.ContainerPull)
+	}
+
+	switch cfg.SpecDrift {
+	case config.SpecDriftOff, config.SpecDriftPause, config.SpecDriftRegenerate:
+	default:
+		return fmt.Errorf("--spec-drift must be off, pause or regenerate, got %q", cfg.SpecDrift)
+	}
+
+	// Jobs work on the project through the shared volume
+	if cfg.K8sJobImage != "" && cfg.K8sJobPVC == "" {
+		return fmt.Errorf("--k8s-job-image requires --k8s-job-pvc")
+	}
+
+	// The agent runs the AI CLIs with its own sandbox settings
+	if cfg.RemoteAgent != "" {
+		if u, err := url.Parse(cfg.RemoteAgent); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("--remote-agent must be an http or https URL, got %q", cfg.RemoteAgent)
+		}
+		for _, local := range []struct {
+			flag string
+			set  bool
+		}{{"--sandbox-cmd", cfg.SandboxCmd != ""}, {"--container-image", cfg.ContainerImage != ""}, {"--k8s-job-image", cfg.K8sJobImage != ""}} {
+			if local.set {
+				return fmt.Errorf("--remote-agent and %s are mutually exclusive; configure them on the agent", local.flag)
+			}
+		}
+	}
+
+	if cfg.ProtectedPathsAction != config.ProtectedRevert && cfg.ProtectedPathsAction != config.ProtectedEscalate {
+		return fmt.Errorf("--protected-paths-action must be revert or escalate, got %q", cfg.ProtectedPathsAction)
+	}
+
 	// Mutual exclusion: --original-plan-file and --github-issue
 	if cfg.OriginalPlanFile != "" && cfg.GithubIssue != "" {
 		return fmt.Errorf("--original-plan-file and --github-issue are mutually exclusive")
 	}
 
+	// --jira-issue is a third, exclusive spec source
+	if cfg.JiraIssue != "" && (cfg.OriginalPlanFile != "" || cfg.GithubIssue != "") {
+		return fmt.Errorf("--jira-issue is mutually exclusive with --original-plan-file and --github-issue")
+	}
+
+	// --plan-from-issue needs an issue to plan from
+	if cfg.PlanFromIssue && cfg.GithubIssue == "" && cfg.JiraIssue == "" {
+		return fmt.Errorf("--plan-from-issue requires --github-issue or --jira-issue")
+	}
+
+	// --plan-from-issue writes a single tasks file
+	if cfg.PlanFromIssue && (len(cfg.TasksFiles) > 1 || tasks.IsGlob(cfg.TasksFile)) {
+		return fmt.Errorf("--plan-from-issue takes a single --tasks-file, not several or a glob")
+	}
+
+	// --spec-dir names the tasks file itself
+	if cfg.SpecDir != "" {
+		if len(cfg.TasksFiles) > 0 {
+			return fmt.Errorf("--spec-dir and --tasks-file are mutually exclusive")
+		}
+		if info, err := os.Stat(cfg.SpecDir); err != nil {
+			return fmt.Errorf("--spec-dir: %w", err)
+		} else if !info.IsDir() {
+			return fmt.Errorf("--spec-dir: %s is not a directory", cfg.SpecDir)
+		}
+	}
+
 	// --original-plan-file must exist if provided
 	if cfg.OriginalPlanFile != "" {
 		if _, err := os.Stat(cfg.OriginalPlanFile); err != nil {
@@ -82,6 +271,18 @@ func ValidateFlags(cmd *cobra.Command, cfg *config.Config) error {
 		}
 	}
 
+	// --replay reads what --record wrote
+	if cfg.Record != "" && cfg.Replay != "" {
+		return fmt.Errorf("--record and --replay are mutually exclusive")
+	}
+	if cfg.Replay != "" {
+		if info, err := os.Stat(cfg.Replay); err != nil {
+			return fmt.Errorf("--replay: %w", err)
+		} else if !info.IsDir() {
+			return fmt.Errorf("--replay: %s is not a directory", cfg.Replay)
+		}
+	}
+
 	// --config must exist if provided
 	if cfg.ConfigFile != "" {
 		if _, err := os.Stat(cfg.ConfigFile); err != nil {
@@ -98,13 +299,48 @@ func ValidateFlags(cmd *cobra.Command, cfg *config.Config) error {
 	if cmd.Flags().Changed("no-learnings") {
 		cfg.EnableLearnings = false
 	}
+	if cmd.Flags().Changed("no-global-learnings") {
+		cfg.GlobalLearnings = false
+	}
+	if cmd.Flags().Changed("no-project-memory") {
+		cfg.ProjectMemory = false
+	}
 	if cmd.Flags().Changed("no-cross-validate") {
 		cfg.CrossValidate = false
 	}
+	if cmd.Flags().Changed("no-pr-comment") {
+		cfg.PRComment = false
+	}
+	if cmd.Flags().Changed("no-cache") {
+		cfg.ResponseCache = false
+	}
 
 	// Validate AI provider value
-	if cfg.AIProvider != "claude" && cfg.AIProvider != "codex" {
-		return fmt.Errorf("--ai must be 'claude' or 'codex', got: %s", cfg.AIProvider)
+	if !model.IsProvider(cfg.AIProvider) {
+		return fmt.Errorf("--ai must be one of %s, got: %s", strings.Join(model.Providers, ", "), cfg.AIProvider)
+	}
+	for _, name := range aiFlags[1:] {
+		if v := cmd.Flags().Lookup(name).Value.String(); v != "" && !model.IsProvider(v) {
+			return fmt.Errorf("--%s must be one of %s, got: %s", name, strings.Join(model.Providers, ", "), v)
+		}
+	}
+
+	if cfg.FailoverAfter < 1 {
+		return fmt.Errorf("--failover-after must be at least 1, got: %d", cfg.FailoverAfter)
+	}
+
+	if cfg.PRNumber < 0 {
+		return fmt.Errorf("--pr must be a pull request number, got: %d", cfg.PRNumber)
+	}
+
+	// Validate validator quorum
+	if cfg.Validators < 1 {
+		return fmt.Errorf("--validators must be at least 1, got: %d", cfg.Validators)
+	}
+	for _, spec := range cfg.ValidatorSpecs() {
+		if !model.IsProvider(spec.AI) {
+			return fmt.Errorf("--validator-pool entries must use one of %s, got: %s", strings.Join(model.Providers, ", "), spec.AI)
+		}
 	}
 
 	return nil
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
@@ -23,9 +24,23 @@ func TestBindFlags_DefaultValues(t *testing.T) {
 	assert.Equal(t, "claude", cfg.AIProvider)
 	assert.Equal(t, 20, cfg.MaxIterations)
 	assert.Equal(t, 5, cfg.MaxInadmissible)
+	assert.Equal(t, 3, cfg.MaxTaskAttempts)
+	assert.Equal(t, 2, cfg.MaxFormatRetries)
 	assert.Equal(t, 10, cfg.MaxClaudeRetry)
 	assert.Equal(t, 100, cfg.MaxTurns)
 	assert.Equal(t, 1800, cfg.InactivityTimeout)
+	assert.Equal(t, 600, cfg.HangTimeout)
+	assert.Empty(t, cfg.FallbackAI)
+	assert.Equal(t, 3, cfg.FailoverAfter)
+	assert.Zero(t, cfg.PRNumber)
+	assert.Empty(t, cfg.CoverageFile)
+	assert.False(t, cfg.GitHubCheck)
+	assert.Empty(t, cfg.ContainerImage)
+	assert.Equal(t, "missing", cfg.ContainerPull)
+	assert.Empty(t, cfg.K8sJobImage)
+	assert.Empty(t, cfg.K8sJobPVC)
+	assert.Empty(t, cfg.RemoteAgent)
+	assert.Equal(t, "off", cfg.SpecDrift)
 	assert.Equal(t, ".ralph-loop/learnings.md", cfg.LearningsFile)
 	assert.Equal(t, "http://127.0.0.1:18789/webhook", cfg.NotifyWebhook)
 	assert.Equal(t, "telegram", cfg.NotifyChannel)
@@ -43,6 +58,8 @@ func TestBindFlags_AIProvider(t *testing.T) {
 		{"default", []string{}, "claude"},
 		{"claude", []string{"--ai", "claude"}, "claude"},
 		{"codex", []string{"--ai", "codex"}, "codex"},
+		{"amazonq", []string{"--ai", "amazonq"}, "amazonq"},
+		{"copilot", []string{"--ai", "copilot"}, "copilot"},
 	}
 
 	for _, tt := range tests {
@@ -55,6 +72,7 @@ func TestBindFlags_AIProvider(t *testing.T) {
 			require.NoError(t, err)
 
 			assert.Equal(t, tt.expected, cfg.AIProvider)
+			assert.NoError(t, ValidateFlags(cmd, cfg))
 		})
 	}
 }
@@ -71,7 +89,17 @@ func TestValidateFlags_InvalidAI(t *testing.T) {
 	// Validation should fail
 	err = ValidateFlags(cmd, cfg)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "must be 'claude' or 'codex'")
+	assert.Contains(t, err.Error(), "must be one of claude, codex, amazonq, copilot")
+}
+
+func TestValidateFlags_InvalidPhaseAI(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, cfg)
+	require.NoError(t, cmd.ParseFlags([]string{"--ai", "codex", "--summary-ai", "gemini"}))
+
+	err := ValidateFlags(cmd, cfg)
+	assert.EqualError(t, err, "--summary-ai must be one of claude, codex, amazonq, copilot, got: gemini")
 }
 
 func TestBindFlags_VerboseFlag(t *testing.T) {
@@ -99,6 +127,96 @@ func TestBindFlags_VerboseFlag(t *testing.T) {
 	}
 }
 
+func TestBindFlags_ScreenshotThreshold(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, cfg)
+	assert.Equal(t, 0.5, cfg.ScreenshotThreshold)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--screenshot-threshold", "3"}))
+	assert.Equal(t, 3.0, cfg.ScreenshotThreshold)
+}
+
+func TestBindFlags_MinConfidence(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, cfg)
+	assert.Zero(t, cfg.MinConfidence)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--min-confidence", "0.75"}))
+	assert.Equal(t, 0.75, cfg.MinConfidence)
+	require.NoError(t, ValidateFlags(cmd, cfg))
+
+	cfg.MinConfidence = 75
+	assert.ErrorContains(t, ValidateFlags(cmd, cfg), "--min-confidence must be between 0 and 1")
+}
+
+func TestValidateFlags_ProtectedPathsAction(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, cfg)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--protected-paths-action", "escalate"}))
+	require.NoError(t, ValidateFlags(cmd, cfg))
+
+	cfg.ProtectedPathsAction = "delete"
+	assert.ErrorContains(t, ValidateFlags(cmd, cfg), `--protected-paths-action must be revert or escalate, got "delete"`)
+}
+
+func TestValidateFlags_Container(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"image", []string{"--container-image", "ghcr.io/acme/agent:1", "--container-runtime", "podman", "--container-pull", "always"}, ""},
+		{"with sandbox", []string{"--container-image", "agent", "--sandbox-cmd", "firejail"}, "--container-image and --sandbox-cmd are mutually exclusive"},
+		{"unknown runtime", []string{"--container-runtime", "lxc"}, `--container-runtime must be docker or podman, got "lxc"`},
+		{"unknown pull policy", []string{"--container-pull", "sometimes"}, `--container-pull must be missing, always or never, got "sometimes"`},
+		{"k8s job", []string{"--k8s-job-image", "agent", "--k8s-job-pvc", "project"}, ""},
+		{"k8s job without volume", []string{"--k8s-job-image", "agent"}, "--k8s-job-image requires --k8s-job-pvc"},
+		{"remote agent", []string{"--remote-agent", "https://gpu-box:7433"}, ""},
+		{"remote agent not a URL", []string{"--remote-agent", "gpu-box:7433"}, `--remote-agent must be an http or https URL, got "gpu-box:7433"`},
+		{"remote agent with container", []string{"--remote-agent", "http://gpu-box:7433", "--container-image", "agent"}, "--remote-agent and --container-image are mutually exclusive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewDefaultConfig()
+			cmd := &cobra.Command{Use: "test"}
+			BindFlags(cmd, cfg)
+			require.NoError(t, cmd.ParseFlags(tt.args))
+
+			err := ValidateFlags(cmd, cfg)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBindFlags_MaxDuration(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, cfg)
+	assert.Zero(t, cfg.MaxDuration)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--max-duration", "2h"}))
+	assert.Equal(t, 2*time.Hour, cfg.MaxDuration)
+
+	assert.Equal(t, 5*time.Minute, cfg.ShutdownGrace)
+	require.NoError(t, cmd.ParseFlags([]string{"--shutdown-grace", "30s"}))
+	assert.Equal(t, 30*time.Second, cfg.ShutdownGrace)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--notify-digest", "30m"}))
+	assert.Equal(t, 30*time.Minute, cfg.NotifyDigest)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--heartbeat-interval", "15m"}))
+	assert.Equal(t, 15*time.Minute, cfg.HeartbeatInterval)
+}
+
 func TestBindFlags_IntFlags(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -109,9 +227,28 @@ func TestBindFlags_IntFlags(t *testing.T) {
 	}{
 		{"max-iterations", "--max-iterations", "30", func(c *config.Config) int { return c.MaxIterations }, 30},
 		{"max-inadmissible", "--max-inadmissible", "10", func(c *config.Config) int { return c.MaxInadmissible }, 10},
+		{"max-task-attempts", "--max-task-attempts", "5", func(c *config.Config) int { return c.MaxTaskAttempts }, 5},
+		{"max-format-retries", "--max-format-retries", "0", func(c *config.Config) int { return c.MaxFormatRetries }, 0},
+		{"escalate-after", "--escalate-after", "4", func(c *config.Config) int { return c.EscalateAfter }, 4},
 		{"max-claude-retry", "--max-claude-retry", "15", func(c *config.Config) int { return c.MaxClaudeRetry }, 15},
 		{"max-turns", "--max-turns", "200", func(c *config.Config) int { return c.MaxTurns }, 200},
 		{"inactivity-timeout", "--inactivity-timeout", "3600", func(c *config.Config) int { return c.InactivityTimeout }, 3600},
+		{"hang-timeout", "--hang-timeout", "300", func(c *config.Config) int { return c.HangTimeout }, 300},
+		{"failover-after", "--failover-after", "5", func(c *config.Config) int { return c.FailoverAfter }, 5},
+		{"pr", "--pr", "42", func(c *config.Config) int { return c.PRNumber }, 42},
+		{"iteration-timeout", "--iteration-timeout", "2700", func(c *config.Config) int { return c.IterationTimeout }, 2700},
+		{"impl-timeout", "--impl-timeout", "1800", func(c *config.Config) int { return c.ImplTimeout }, 1800},
+		{"val-timeout", "--val-timeout", "900", func(c *config.Config) int { return c.ValTimeout }, 900},
+		{"heartbeat-iterations", "--heartbeat-iterations", "5", func(c *config.Config) int { return c.HeartbeatIterations }, 5},
+		{"context-max-tokens", "--context-max-tokens", "6000", func(c *config.Config) int { return c.ContextMaxTokens }, 6000},
+		{"val-diff-max-tokens", "--val-diff-max-tokens", "4000", func(c *config.Config) int { return c.ValDiffMaxTokens }, 4000},
+		{"impl-output-max-tokens", "--impl-output-max-tokens", "12000", func(c *config.Config) int { return c.ImplOutputMaxTokens }, 12000},
+		{"hook-timeout", "--hook-timeout", "120", func(c *config.Config) int { return c.HookTimeout }, 120},
+		{"learnings-max-tokens", "--learnings-max-tokens", "2000", func(c *config.Config) int { return c.LearningsMaxTokens }, 2000},
+		{"log-max-mb", "--log-max-mb", "0", func(c *config.Config) int { return c.LogMaxMB }, 0},
+		{"retain-iterations", "--retain-iterations", "20", func(c *config.Config) int { return c.RetainIterations }, 20},
+		{"retain-mb", "--retain-mb", "500", func(c *config.Config) int { return c.RetainMB }, 500},
+		{"output-gzip-kb", "--output-gzip-kb", "256", func(c *config.Config) int { return c.OutputGzipKB }, 256},
 	}
 
 	for _, tt := range tests {
@@ -138,19 +275,57 @@ func TestBindFlags_StringFlags(t *testing.T) {
 	}{
 		{"implementation-model", "--implementation-model", "sonnet", func(c *config.Config) string { return c.ImplModel }, "sonnet"},
 		{"validation-model", "--validation-model", "haiku", func(c *config.Config) string { return c.ValModel }, "haiku"},
+		{"validation-ai", "--validation-ai", "copilot", func(c *config.Config) string { return c.ValAI }, "copilot"},
 		{"cross-model", "--cross-model", "default", func(c *config.Config) string { return c.CrossModel }, "default"},
 		{"cross-validation-ai", "--cross-validation-ai", "codex", func(c *config.Config) string { return c.CrossAI }, "codex"},
+		{"fallback-ai", "--fallback-ai", "codex", func(c *config.Config) string { return c.FallbackAI }, "codex"},
+		{"coverage-file", "--coverage-file", "coverage.out", func(c *config.Config) string { return c.CoverageFile }, "coverage.out"},
+		{"container-image", "--container-image", "ghcr.io/acme/agent:1", func(c *config.Config) string { return c.ContainerImage }, "ghcr.io/acme/agent:1"},
+		{"container-runtime", "--container-runtime", "podman", func(c *config.Config) string { return c.ContainerRuntime }, "podman"},
+		{"container-pull", "--container-pull", "never", func(c *config.Config) string { return c.ContainerPull }, "never"},
+		{"k8s-job-image", "--k8s-job-image", "ghcr.io/acme/agent:1", func(c *config.Config) string { return c.K8sJobImage }, "ghcr.io/acme/agent:1"},
+		{"k8s-job-namespace", "--k8s-job-namespace", "ci", func(c *config.Config) string { return c.K8sJobNamespace }, "ci"},
+		{"k8s-job-secrets", "--k8s-job-secrets", "ai-keys", func(c *config.Config) string { return c.K8sJobSecrets }, "ai-keys"},
+		{"k8s-job-pvc", "--k8s-job-pvc", "project", func(c *config.Config) string { return c.K8sJobPVC }, "project"},
+		{"remote-agent", "--remote-agent", "http://gpu-box:7433", func(c *config.Config) string { return c.RemoteAgent }, "http://gpu-box:7433"},
+		{"spec-drift", "--spec-drift", "pause", func(c *config.Config) string { return c.SpecDrift }, "pause"},
 		{"final-plan-validation-ai", "--final-plan-validation-ai", "claude", func(c *config.Config) string { return c.FinalPlanAI }, "claude"},
 		{"final-plan-validation-model", "--final-plan-validation-model", "opus", func(c *config.Config) string { return c.FinalPlanModel }, "opus"},
 		{"tasks-validation-ai", "--tasks-validation-ai", "codex", func(c *config.Config) string { return c.TasksValAI }, "codex"},
 		{"tasks-validation-model", "--tasks-validation-model", "default", func(c *config.Config) string { return c.TasksValModel }, "default"},
+		{"summary-model", "--summary-model", "haiku", func(c *config.Config) string { return c.SummaryModel }, "haiku"},
+		{"summary-ai", "--summary-ai", "amazonq", func(c *config.Config) string { return c.SummaryAI }, "amazonq"},
+		{"triage-ai", "--triage-ai", "codex", func(c *config.Config) string { return c.TriageAI }, "codex"},
+		{"triage-model", "--triage-model", "o4-mini", func(c *config.Config) string { return c.TriageModel }, "o4-mini"},
+		{"auto-cross-validate", "--auto-cross-validate", "first-complete", func(c *config.Config) string { return c.AutoCrossValidate }, "first-complete"},
 		{"tasks-file", "--tasks-file", "custom-tasks.md", func(c *config.Config) string { return c.TasksFile }, "custom-tasks.md"},
 		{"learnings-file", "--learnings-file", "custom-learnings.md", func(c *config.Config) string { return c.LearningsFile }, "custom-learnings.md"},
+		{"state-dir", "--state-dir", "xdg", func(c *config.Config) string { return c.StateDir }, "xdg"},
 		{"notify-webhook", "--notify-webhook", "http://example.com", func(c *config.Config) string { return c.NotifyWebhook }, "http://example.com"},
 		{"notify-channel", "--notify-channel", "slack", func(c *config.Config) string { return c.NotifyChannel }, "slack"},
 		{"notify-chat-id", "--notify-chat-id", "12345", func(c *config.Config) string { return c.NotifyChatID }, "12345"},
+		{"notify-events", "--notify-events", "escalate,progress", func(c *config.Config) string { return c.NotifyEvents }, "escalate,progress"},
 		{"start-at", "--start-at", "14:30", func(c *config.Config) string { return c.StartAt }, "14:30"},
 		{"at alias", "--at", "15:00", func(c *config.Config) string { return c.StartAt }, "15:00"},
+		{"pause-between", "--pause-between", "09:00-18:00 weekdays", func(c *config.Config) string { return c.PauseBetween }, "09:00-18:00 weekdays"},
+		{"record", "--record", "recordings/run-1", func(c *config.Config) string { return c.Record }, "recordings/run-1"},
+		{"replay", "--replay", "recordings/run-1", func(c *config.Config) string { return c.Replay }, "recordings/run-1"},
+		{"custom-verdicts", "--custom-verdicts", "WONTFIX=exit-code 20", func(c *config.Config) string { return c.CustomVerdicts }, "WONTFIX=exit-code 20"},
+		{"external-validators", "--external-validators", "lint=./lint.sh", func(c *config.Config) string { return c.ExternalValidators }, "lint=./lint.sh"},
+		{"verify-cmds", "--verify-cmds", "go test -json ./...", func(c *config.Config) string { return c.VerifyCmds }, "go test -json ./..."},
+		{"verify-reports", "--verify-reports", "TestResults/*.trx", func(c *config.Config) string { return c.VerifyReports }, "TestResults/*.trx"},
+		{"policy-file", "--policy-file", "policies.yaml", func(c *config.Config) string { return c.PolicyFile }, "policies.yaml"},
+		{"protected-paths", "--protected-paths", ".github/workflows,infra/prod", func(c *config.Config) string { return c.ProtectedPaths }, ".github/workflows,infra/prod"},
+		{"protected-paths-action", "--protected-paths-action", "escalate", func(c *config.Config) string { return c.ProtectedPathsAction }, "escalate"},
+		{"protected-branches", "--protected-branches", "main,release", func(c *config.Config) string { return c.ProtectedBranches }, "main,release"},
+		{"evidence-patterns", "--evidence-patterns", "Deploy,Migrate", func(c *config.Config) string { return c.EvidencePatterns }, "Deploy,Migrate"},
+		{"allowed-tools", "--allowed-tools", "Edit,Read", func(c *config.Config) string { return c.AllowedTools }, "Edit,Read"},
+		{"denied-commands", "--denied-commands", "rm,curl", func(c *config.Config) string { return c.DeniedCommands }, "rm,curl"},
+		{"hook-pre-iteration", "--hook-pre-iteration", "make db-reset", func(c *config.Config) string { return c.HookPreIteration }, "make db-reset"},
+		{"hook-post-implementation", "--hook-post-implementation", "./sast.sh", func(c *config.Config) string { return c.HookPostImplementation }, "./sast.sh"},
+		{"hook-post-validation", "--hook-post-validation", "make e2e", func(c *config.Config) string { return c.HookPostValidation }, "make e2e"},
+		{"hook-on-exit", "--hook-on-exit", "notify-send done", func(c *config.Config) string { return c.HookOnExit }, "notify-send done"},
+		{"profile", "--profile", "cheap", func(c *config.Config) string { return c.Profile }, "cheap"},
 	}
 
 	for _, tt := range tests {
@@ -177,8 +352,17 @@ func TestBindFlags_BoolFlags(t *testing.T) {
 		{"resume", "--resume", func(c *config.Config) bool { return c.Resume }, true},
 		{"resume-force", "--resume-force", func(c *config.Config) bool { return c.ResumeForce }, true},
 		{"clean", "--clean", func(c *config.Config) bool { return c.Clean }, true},
+		{"revalidate-plan", "--revalidate-plan", func(c *config.Config) bool { return c.RevalidatePlan }, true},
 		{"status", "--status", func(c *config.Config) bool { return c.Status }, true},
 		{"cancel", "--cancel", func(c *config.Config) bool { return c.Cancel }, true},
+		{"force-unlock", "--force-unlock", func(c *config.Config) bool { return c.ForceUnlock }, true},
+		{"log-gzip", "--log-gzip", func(c *config.Config) bool { return c.LogGzip }, true},
+		{"dedup-outputs", "--dedup-outputs", func(c *config.Config) bool { return c.DedupOutputs }, true},
+		{"allow-dirty", "--allow-dirty", func(c *config.Config) bool { return c.AllowDirty }, true},
+		{"val-rotate", "--val-rotate", func(c *config.Config) bool { return c.ValRotate }, true},
+		{"github-check", "--github-check", func(c *config.Config) bool { return c.GitHubCheck }, true},
+		{"annotate-tasks", "--annotate-tasks", func(c *config.Config) bool { return c.AnnotateTasks }, true},
+		{"triage", "--triage", func(c *config.Config) bool { return c.Triage }, true},
 	}
 
 	for _, tt := range tests {
@@ -216,6 +400,120 @@ func TestValidateFlags_MutualExclusion(t *testing.T) {
 	assert.Contains(t, err.Error(), "mutually exclusive")
 }
 
+func TestValidateFlags_PlanFromIssueRequiresIssue(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, cfg)
+
+	err := cmd.ParseFlags([]string{"--plan-from-issue"})
+	require.NoError(t, err)
+
+	err = ValidateFlags(cmd, cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "--plan-from-issue requires --github-issue or --jira-issue")
+
+	cfg.GithubIssue = "42"
+	assert.NoError(t, ValidateFlags(cmd, cfg))
+	assert.True(t, cfg.PlanFromIssue)
+
+	cfg.GithubIssue = ""
+	cfg.JiraIssue = "PROJ-1"
+	assert.NoError(t, ValidateFlags(cmd, cfg))
+}
+
+func TestBindFlags_RepeatedTasksFile(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, cfg)
+
+	err := cmd.ParseFlags([]string{"--tasks-file", "specs/a/tasks.md", "--tasks-file", "specs/**/tasks.md"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"specs/a/tasks.md", "specs/**/tasks.md"}, cfg.TasksFiles)
+	assert.Equal(t, "specs/a/tasks.md", cfg.TasksFile)
+	assert.Equal(t, "specs/a/tasks.md,specs/**/tasks.md", cmd.Flags().Lookup("tasks-file").Value.String())
+}
+
+func TestValidateFlags_PlanFromIssueSingleTasksFile(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, cfg)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--plan-from-issue", "--github-issue", "42", "--tasks-file", "specs/**/tasks.md"}))
+	err := ValidateFlags(cmd, cfg)
+	assert.ErrorContains(t, err, "--plan-from-issue takes a single --tasks-file")
+}
+
+func TestValidateFlags_SpecDrift(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, cfg)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--spec-drift", "regenerate"}))
+	assert.NoError(t, ValidateFlags(cmd, cfg))
+
+	cfg.SpecDrift = "ask"
+	assert.EqualError(t, ValidateFlags(cmd, cfg), `--spec-drift must be off, pause or regenerate, got "ask"`)
+}
+
+func TestValidateFlags_SpecDir(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, cfg)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--spec-dir", dir}))
+	assert.Equal(t, dir, cfg.SpecDir)
+	assert.NoError(t, ValidateFlags(cmd, cfg))
+
+	cfg.TasksFiles = []string{"tasks.md"}
+	assert.ErrorContains(t, ValidateFlags(cmd, cfg), "--spec-dir and --tasks-file are mutually exclusive")
+
+	cfg.TasksFiles = nil
+	cfg.SpecDir = filepath.Join(dir, "missing")
+	assert.ErrorContains(t, ValidateFlags(cmd, cfg), "--spec-dir")
+
+	file := filepath.Join(dir, "tasks.md")
+	require.NoError(t, os.WriteFile(file, []byte("- [ ] a\n"), 0644))
+	cfg.SpecDir = file
+	assert.ErrorContains(t, ValidateFlags(cmd, cfg), "is not a directory")
+}
+
+func TestValidateFlags_Replay(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, cfg)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--replay", dir}))
+	assert.NoError(t, ValidateFlags(cmd, cfg))
+
+	cfg.Record = filepath.Join(dir, "again")
+	assert.ErrorContains(t, ValidateFlags(cmd, cfg), "--record and --replay are mutually exclusive")
+
+	cfg.Record = ""
+	cfg.Replay = filepath.Join(dir, "missing")
+	assert.ErrorContains(t, ValidateFlags(cmd, cfg), "--replay")
+
+	file := filepath.Join(dir, "impl-001.prompt.txt")
+	require.NoError(t, os.WriteFile(file, []byte("prompt"), 0644))
+	cfg.Replay = file
+	assert.ErrorContains(t, ValidateFlags(cmd, cfg), "is not a directory")
+}
+
+func TestValidateFlags_JiraIssueExclusive(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, cfg)
+
+	err := cmd.ParseFlags([]string{"--jira-issue", "PROJ-123", "--github-issue", "42"})
+	require.NoError(t, err)
+
+	err = ValidateFlags(cmd, cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "--jira-issue is mutually exclusive")
+}
+
 func TestValidateFlags_OriginalPlanFileMustExist(t *testing.T) {
 	cfg := config.NewDefaultConfig()
 	cmd := &cobra.Command{Use: "test"}
@@ -292,3 +590,136 @@ func TestValidateFlags_NoCrossValidate(t *testing.T) {
 	require.NoError(t, err)
 	assert.False(t, cfg.CrossValidate, "--no-cross-validate should disable cross-validation")
 }
+
+func TestValidateFlags_NoPRComment(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, cfg)
+
+	err := cmd.ParseFlags([]string{"--no-pr-comment"})
+	require.NoError(t, err)
+
+	assert.True(t, cfg.PRComment, "PRComment should still be true before validation")
+
+	err = ValidateFlags(cmd, cfg)
+	require.NoError(t, err)
+	assert.False(t, cfg.PRComment, "--no-pr-comment should disable the PR summary comment")
+}
+
+func TestValidateFlags_PRNumber(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, cfg)
+	require.NoError(t, cmd.ParseFlags([]string{"--pr", "-1"}))
+
+	err := ValidateFlags(cmd, cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--pr must be a pull request number")
+}
+
+func TestValidateFlags_NoCache(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, cfg)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--no-cache", "--cache-ttl", "1h"}))
+	assert.True(t, cfg.ResponseCache, "ResponseCache should still be true before validation")
+	assert.Equal(t, time.Hour, cfg.CacheTTL)
+
+	require.NoError(t, ValidateFlags(cmd, cfg))
+	assert.False(t, cfg.ResponseCache, "--no-cache should disable the response cache")
+}
+
+func TestValidateFlags_NoGlobalLearnings(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, cfg)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--no-global-learnings", "--learnings-tags", "go,react"}))
+	require.NoError(t, ValidateFlags(cmd, cfg))
+	assert.False(t, cfg.GlobalLearnings)
+	assert.True(t, cfg.EnableLearnings, "project learnings stay enabled")
+	assert.Equal(t, "go,react", cfg.LearningsTags)
+}
+
+func TestValidateFlags_NoProjectMemory(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, cfg)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--no-project-memory"}))
+	assert.True(t, cfg.ProjectMemory, "ProjectMemory should still be true before validation")
+	require.NoError(t, ValidateFlags(cmd, cfg))
+	assert.False(t, cfg.ProjectMemory)
+}
+
+func TestBindFlags_ModelLadder(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cmd := &cobra.Command{Use: "test"}
+	BindFlags(cmd, cfg)
+
+	err := cmd.ParseFlags([]string{"--implementation-model-ladder", "sonnet,opus"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"sonnet", "opus"}, cfg.ModelLadder())
+}
+
+func TestValidateFlags_Validators(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"default", []string{}, ""},
+		{"quorum with pool", []string{"--validators", "3", "--validator-pool", "claude:opus,codex"}, ""},
+		{"zero validators", []string{"--validators", "0"}, "--validators must be at least 1"},
+		{"unknown provider", []string{"--validators", "2", "--validator-pool", "gemini"}, "--validator-pool entries must use"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewDefaultConfig()
+			cmd := &cobra.Command{Use: "test"}
+			BindFlags(cmd, cfg)
+			require.NoError(t, cmd.ParseFlags(tt.args))
+
+			err := ValidateFlags(cmd, cfg)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateFlags_Failover(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"default", []string{}, ""},
+		{"fallback", []string{"--fallback-ai", "codex", "--failover-after", "1"}, ""},
+		{"unknown fallback", []string{"--fallback-ai", "gemini"}, "--fallback-ai must be one of claude, codex, amazonq, copilot, got: gemini"},
+		{"zero failures", []string{"--failover-after", "0"}, "--failover-after must be at least 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewDefaultConfig()
+			cmd := &cobra.Command{Use: "test"}
+			BindFlags(cmd, cfg)
+			require.NoError(t, cmd.ParseFlags(tt.args))
+
+			err := ValidateFlags(cmd, cfg)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// manSectionTitles renames help sections to their man page conventions.
+var manSectionTitles = map[string]string{
+	"FLAGS":      "OPTIONS",
+	"EXIT CODES": "EXIT STATUS",
+}
+
+// ManPageName returns the file name of cmd's man page, e.g.
+// "ralph-loop-export.1".
+func ManPageName(cmd *cobra.Command) string {
+	return strings.ReplaceAll(cmd.CommandPath(), " ", "-") + ".1"
+}
+
+// WriteManPage writes cmd's man page in roff to w. The root command's page
+// is built from the help text, so the two never disagree; subcommand pages
+// list their flags.
+func WriteManPage(w io.Writer, cmd *cobra.Command, version, date string) error {
+	name := strings.ReplaceAll(cmd.CommandPath(), " ", "-")
+	var b strings.Builder
+	fmt.Fprintf(&b, ".TH \"%s\" \"1\" \"%s\" \"ralph-loop %s\" \"User Commands\"\n", strings.ToUpper(name), date, roffEscape(version))
+	fmt.Fprintf(&b, ".SH NAME\n%s \\- %s\n", roffEscape(name), roffEscape(cmd.Short))
+
+	if cmd.HasParent() {
+		writeManSection(&b, "SYNOPSIS", cmd.UseLine())
+		if cmd.Long != "" {
+			fmt.Fprintf(&b, ".SH DESCRIPTION\n%s\n", roffEscape(cmd.Long))
+		}
+		if cmd.HasAvailableLocalFlags() {
+			b.WriteString(".SH OPTIONS\n")
+			cmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+				if !f.Hidden {
+					writeManFlag(&b, f)
+				}
+			})
+		}
+		fmt.Fprintf(&b, ".SH SEE ALSO\n\\fB%s\\fR(1)\n", roffEscape(cmd.Root().Name()))
+	} else {
+		sections := helpSections(helpTemplate)
+		for _, s := range sections {
+			switch s.title {
+			case "USAGE":
+				writeManSection(&b, "SYNOPSIS", s.body)
+				fmt.Fprintf(&b, ".SH DESCRIPTION\n%s\n", roffEscape(cmd.Long))
+			default:
+				title := s.title
+				if t, ok := manSectionTitles[title]; ok {
+					title = t
+				}
+				writeManSection(&b, title, s.body)
+			}
+		}
+		var also []string
+		for _, sub := range cmd.Commands() {
+			if sub.IsAvailableCommand() {
+				also = append(also, fmt.Sprintf("\\fB%s\\fR(1)", roffEscape(strings.TrimSuffix(ManPageName(sub), ".1"))))
+			}
+		}
+		if len(also) > 0 {
+			fmt.Fprintf(&b, ".SH SEE ALSO\n%s\n", strings.Join(also, ", "))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// helpSection is a block of the help text under an all-caps heading.
+type helpSection struct {
+	title string
+	body  string
+}
+
+// helpSections splits help text into its headed sections. Text before the
+// first heading (the one-line summary) is dropped.
+func helpSections(text string) []helpSection {
+	var sections []helpSection
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" && line == strings.ToUpper(line) && !strings.HasPrefix(line, " ") {
+			sections = append(sections, helpSection{title: line})
+			continue
+		}
+		if len(sections) > 0 {
+			sections[len(sections)-1].body += line + "\n"
+		}
+	}
+	for i := range sections {
+		sections[i].body = strings.Trim(sections[i].body, "\n")
+	}
+	return sections
+}
+
+// writeManSection writes body as a preformatted section.
+func writeManSection(b *strings.Builder, title, body string) {
+	fmt.Fprintf(b, ".SH %s\n.nf\n", title)
+	for _, line := range strings.Split(body, "\n") {
+		b.WriteString(roffEscape(line) + "\n")
+	}
+	b.WriteString(".fi\n")
+}
+
+func writeManFlag(b *strings.Builder, f *pflag.Flag) {
+	b.WriteString(".TP\n")
+	if f.Shorthand != "" {
+		fmt.Fprintf(b, "\\fB\\-%s\\fR, ", f.Shorthand)
+	}
+	fmt.Fprintf(b, "\\fB\\-\\-%s\\fR", roffEscape(f.Name))
+	if typ, _ := pflag.UnquoteUsage(f); typ != "" {
+		fmt.Fprintf(b, " \\fI%s\\fR", typ)
+	}
+	b.WriteString("\n" + roffEscape(f.Usage) + "\n")
+}
+
+// roffEscape escapes backslashes and dashes, and keeps lines starting with
+// a period or quote from being read as requests.
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newManTestTree() (*cobra.Command, *cobra.Command) {
+	root := &cobra.Command{Use: "ralph-loop", Short: "Loop orchestrator", Long: "Ralph Loop orchestrates AI loops.", Run: func(*cobra.Command, []string) {}}
+	export := &cobra.Command{Use: "export", Short: "Export the session", Long: "Export the session to an archive.", Run: func(*cobra.Command, []string) {}}
+	export.Flags().StringP("output", "o", "", "Archive path")
+	export.Flags().Bool("secret", false, "Hidden flag")
+	_ = export.Flags().MarkHidden("secret")
+	root.AddCommand(export)
+	return root, export
+}
+
+func TestManPageName(t *testing.T) {
+	root, export := newManTestTree()
+	assert.Equal(t, "ralph-loop.1", ManPageName(root))
+	assert.Equal(t, "ralph-loop-export.1", ManPageName(export))
+}
+
+func TestWriteManPage_Root(t *testing.T) {
+	root, _ := newManTestTree()
+	var b strings.Builder
+	require.NoError(t, WriteManPage(&b, root, "1.2.3", "2026-03-01"))
+	page := b.String()
+
+	assert.True(t, strings.HasPrefix(page, `.TH "RALPH-LOOP" "1" "2026-03-01" "ralph-loop 1.2.3" "User Commands"`))
+	assert.Contains(t, page, ".SH NAME\nralph\\-loop \\- Loop orchestrator\n")
+	assert.Contains(t, page, ".SH SYNOPSIS\n.nf\n  ralph\\-loop [flags]\n")
+	assert.Contains(t, page, ".SH DESCRIPTION\nRalph Loop orchestrates AI loops.\n")
+	assert.Contains(t, page, ".SH OPTIONS\n")
+	assert.Contains(t, page, `\-\-max\-duration`)
+	assert.Contains(t, page, ".SH EXIT STATUS\n")
+	assert.Contains(t, page, ".SH ENVIRONMENT\n")
+	assert.Contains(t, page, ".SH EXAMPLES\n")
+	assert.Contains(t, page, ".SH SEE ALSO\n\\fBralph\\-loop\\-export\\fR(1)\n")
+	assert.NotContains(t, page, ".SH FLAGS")
+}
+
+func TestWriteManPage_Subcommand(t *testing.T) {
+	_, export := newManTestTree()
+	var b strings.Builder
+	require.NoError(t, WriteManPage(&b, export, "1.2.3", "2026-03-01"))
+	page := b.String()
+
+	assert.Contains(t, page, `.TH "RALPH-LOOP-EXPORT"`)
+	assert.Contains(t, page, ".SH SYNOPSIS\n.nf\nralph\\-loop export [flags]\n.fi\n")
+	assert.Contains(t, page, ".TP\n\\fB\\-o\\fR, \\fB\\-\\-output\\fR \\fIstring\\fR\nArchive path\n")
+	assert.NotContains(t, page, "secret")
+	assert.Contains(t, page, ".SH SEE ALSO\n\\fBralph\\-loop\\fR(1)\n")
+}
+
+func TestHelpSections(t *testing.T) {
+	sections := helpSections("tool - summary\n\nUSAGE\n  tool [flags]\n\nEXIT CODES\n  0   Success\n")
+	require.Len(t, sections, 2)
+	assert.Equal(t, helpSection{title: "USAGE", body: "  tool [flags]"}, sections[0])
+	assert.Equal(t, helpSection{title: "EXIT CODES", body: "  0   Success"}, sections[1])
+}
+
+func TestRoffEscape(t *testing.T) {
+	assert.Equal(t, `\-\-flag`, roffEscape("--flag"))
+	assert.Equal(t, `C:\eUsers`, roffEscape(`C:\Users`))
+	assert.Equal(t, `\&.ralph\-loop`, roffEscape(".ralph-loop"))
+	assert.Equal(t, `\&'quoted'`, roffEscape("'quoted'"))
+}
@@ -9,58 +9,265 @@ const helpTemplate = `ralph-loop - Dual-model AI implementation-validation loop
 
 USAGE
   ralph-loop [flags]
+  ralph-loop <command> [flags]
+
+COMMANDS
+  init [-i] [--ai <ai>] [--force]          Scaffold .ralph-loop/config, tasks.md, plan.md and a .gitignore entry
+  export [-o <file>]                       Export the session in .ralph-loop to a tar.gz archive
+  import <file> [--force]                  Restore an exported session, then continue with --resume
+  completion <bash|zsh|fish|powershell>    Print a shell completion script
+  docs man [-d <dir>]                      Write man pages for ralph-loop and its commands
+  self-update [--check] [--force]          Replace this binary with the latest verified GitHub release
+  config show [--config <file>]            Print the settings and where they come from, secrets redacted
+  learnings promote [--tags <list>]        Copy this project's learnings to the global library
+  logs [--iteration <n>] [--phase <name>]  Print an iteration's AI output (impl, triage, val, cross, final-plan)
+  diff [--iteration <n>] [--stat]          Print an iteration's git diff with its verdict and feedback
+  blame <path>                             List the iterations and tasks that changed a file
+  rollback --to-iteration <n>              Undo the iterations after n, files and session, for --resume
+  gc [--yes] [--dry-run]                   Remove iteration directories the session no longer needs, asking first
+  attach [-n <lines>] [--raw]              Follow a running loop's status and AI output from another terminal
+  respond -m <message>                     Record a decision for an escalated session's next --resume
+  report [--format <fmt>] [-o <file>]      Render the session's burn-down as markdown or html
+  mcp                                      Serve the session to AI tools over MCP on stdin/stdout
+  bench --matrix <pairs> [--runs <n>]      Compare IMPL/VAL model pairs on the tasks file in git worktrees
+  agent [--listen <addr>]                  Run the AI calls of a loop started elsewhere with --remote-agent
+  workspace [-f <manifest>] [--parallel]   Run loops across the repositories of a workspace manifest
 
 FLAGS
   AI Provider & Models:
-    --ai <claude|codex>                    AI CLI to use (default: claude)
-    --implementation-model <model>         Model for implementation phase (default: opus/default)
-    --validation-model <model>             Model for validation phase (default: opus/default)
-    --cross-validation-ai <claude|codex>   AI CLI for cross-validation (default: auto-opposite)
+    --ai <ai>                              AI CLI to use: claude, codex, amazonq (q chat) or copilot
+                                           (gh copilot) (default: claude)
+    --implementation-model <model>         Model for implementation phase (default: opus, default for codex,
+                                           claude-sonnet-4 for amazonq, claude-sonnet-4.5 for copilot)
+    --validation-model <model>             Model for validation phase (default: as for implementation)
+    --validation-ai <ai>                   AI CLI for validation (default: same as --ai)
+    --implementation-model-ladder <list>   Models to escalate through, cheapest first (e.g. sonnet,opus)
+    --escalate-after <int>                 NEEDS_MORE_WORK verdicts on the same tasks before escalating (default: 2)
+    --validators <int>                     Validators voting on each iteration, majority wins (default: 1)
+    --validator-pool <list>                ai[:model] entries for the validators, round-robin (e.g. claude:opus,codex)
+    --val-rotate                           Judge each iteration with the next --validator-pool entry in turn
+    --cross-validation-ai <ai>             AI CLI for cross-validation (default: claude for codex, else codex)
     --cross-model <model>                  Model for cross-validation (default: auto)
+    --fallback-ai <ai>                     AI CLI taking over for the rest of the session when --ai fails
+                                           its health check, run at startup and after failed calls (default: none)
+    --failover-after <int>                 Consecutive failed AI calls before --ai is health-checked (default: 3)
     --final-plan-validation-ai <ai>        AI CLI for final plan validation (default: same as cross-val)
     --final-plan-validation-model <model>  Model for final plan validation (default: same as cross-val)
     --tasks-validation-ai <ai>             AI CLI for tasks validation (default: same as --ai)
     --tasks-validation-model <model>       Model for tasks validation (default: same as impl)
+    --summary-model <model>                Model summarizing long implementation output (default: haiku for
+                                           claude, o4-mini for codex, claude-haiku-4.5 for copilot)
+    --summary-ai <ai>                      AI CLI summarizing long implementation output (default: same as --ai)
+    --triage                               Check each iteration with a cheap model before validation; claimed
+                                           work with no changes or test runs behind it skips validation
+    --triage-ai <ai>                       AI CLI for triage (default: same as validation)
+    --triage-model <model>                 Model for triage (default: the triage AI's small model, as --summary-model)
 
   Iteration Limits:
     --max-iterations <int>                 Maximum loop iterations (default: 20)
     --max-inadmissible <int>               Max inadmissible verdicts before exit 6 (default: 5)
     --max-claude-retry <int>               Max retries per AI invocation (default: 10)
     --max-turns <int>                      Max agent turns per AI invocation (default: 100)
+    --max-task-attempts <int>              Skip a task after N incomplete iterations, 0 disables (default: 3)
+    --max-format-retries <int>             Repair calls for unparseable validator output before it counts
+                                           as inadmissible (default: 2)
     --inactivity-timeout <int>             Seconds of inactivity before kill (default: 1800)
+    --hang-timeout <int>                   Seconds without output and without CPU or I/O in the AI CLI's
+                                           processes before it is killed as hung, its last output saved
+                                           to <output>.hang.json and the call retried (default: 600, 0 off)
+    --iteration-timeout <int>              Seconds before an iteration's implementation and validation are
+                                           cut off and the next iteration starts (default: 0, none)
+    --impl-timeout <int>                   Seconds before the implementation phase is cut off (default: 0, none)
+    --val-timeout <int>                    Seconds before the validation phase is cut off (default: 0, none)
+    --max-duration <duration>              Wall-clock budget (e.g. 2h, 90m); exit 7 instead of starting an
+                                           iteration that would exceed it (default: unlimited)
+    --shutdown-grace <duration>            After SIGTERM, how long the AI call in progress may finish
+                                           before it is interrupted and the state saved (default: 5m)
 
   Input Files:
-    --tasks-file <path>                    Path to tasks.md (default: auto-detect)
+    --tasks-file <path>                    Path to tasks.md (default: auto-detect); repeat or use a glob
+                                           (specs/**/tasks.md) to run several specs in one session
+    --spec-dir <dir>                       Spec-kit folder (specs/NNN-slug); uses its tasks.md, plan.md and spec.md
     --original-plan-file <path>            Path to original plan (mutually exclusive with --github-issue)
-    --github-issue <url|number>            GitHub issue URL or number (mutually exclusive with --original-plan-file)
+    --github-issue <url|number>            Issue URL, owner/repo#number or number (mutually exclusive with --original-plan-file)
+    --jira-issue <key|url>                 Jira issue (e.g. PROJ-123) used as the spec; needs JIRA_URL, JIRA_TOKEN
+    --issue-provider <name>                github, gitlab or gitea (default: detect from URL, else github)
+    --plan-from-issue                      Generate plan.md and tasks.md from --github-issue before the loop
+                                           (written to --tasks-file or specs/issue-<n>/; validated against the issue)
+    --spec-drift <mode>                    Re-read the spec (re-fetch the issue) every iteration; when it changed,
+                                           revalidate the tasks and pause or regenerate them: off, pause or
+                                           regenerate (default: off)
     --learnings-file <path>                Path to learnings file (default: .ralph-loop/learnings.md)
+    --learnings-tags <list>                Global learnings to use, e.g. go,react (default: detect from project files)
+    --context-max-tokens <int>             Add excerpts of files related to the pending tasks (mentioned, changed
+                                           or matching task keywords) to prompts, up to this size (default: 0, off)
+    --val-diff-max-tokens <int>            Show the validator the iteration's git diff, up to this size
+                                           (default: 10000, 0 off)
+    --impl-output-max-tokens <int>         Summarize implementation output above this size for the validator,
+                                           which also gets the full file's path (default: 30000, 0 never)
+    --learnings-max-tokens <int>           Summarize learnings above this size with the validation model (default: 8000, 0 never)
     --config <path>                        Path to additional config file
+    --state-dir <path|xdg>                 Keep the session state there, for every command, instead of
+                                           .ralph-loop; xdg keeps it per project in $XDG_STATE_HOME/ralph-loop.
+                                           An existing .ralph-loop is moved there, but for its config file
+    --profile <name>                       Apply the [profile.<name>] section of the config files
 
   Feature Toggles:
     -v, --verbose                          Pass verbose flag to AI CLI
+    --apply-patch                          Implementer returns a RALPH_PATCH diff; ralph-loop applies it
+                                           (the implementer may only read the workspace)
+    --log-max-mb <int>                     Rotate the per-iteration phase logs (impl.log, val.log, cross.log)
+                                           at this size (default: 10, 0 never)
+    --log-gzip                             Gzip the phase logs of finished iterations
+    --output-gzip-kb <int>                 Gzip the AI outputs and logs of finished iterations of this
+                                           size in KB or more (default: 0, never)
+    --dedup-outputs                        Hard-link the files of a finished iteration identical to the
+                                           previous iteration's
+    --retain-iterations <int>              Keep the directories of the last N iterations only, removing
+                                           older ones as each iteration ends (default: 0, all)
+    --retain-mb <int>                      Keep the newest iteration directories up to this total size
+                                           in MB (default: 0, no limit)
+    --tui                                  Live dashboard: phase, progress, streaming output, verdict, log
+    --sandbox-cmd <cmd>                    Wrap AI CLIs in a sandbox; {workdir} and {env} are expanded
+    --sandbox-env <list>                   Environment variables passed into the sandbox (e.g. ANTHROPIC_API_KEY)
+    --container-image <image>              Run each AI CLI call in a fresh container of this image, with the
+                                           project bind-mounted and --sandbox-env passed in
+    --container-runtime <name>             docker or podman (default: whichever is installed)
+    --container-pull <policy>              Pull the image: missing, always or never (default: missing)
+    --k8s-job-image <image>                Run each implementation call as a Kubernetes Job of this image
+    --k8s-job-namespace <ns>               Namespace of the jobs (default: the kubectl context's)
+    --k8s-job-secrets <list>               Secrets whose keys become environment variables in the jobs
+    --k8s-job-pvc <claim>                  PersistentVolumeClaim holding the project, shared with the jobs
+    --remote-agent <url>                   Run every AI call on a ralph-loop agent daemon (RALPH_AGENT_TOKEN)
+    --allowed-tools <list>                 Only tools the AI CLIs may use, in each CLI's syntax (e.g. Edit,Bash(go test:*))
+    --denied-commands <list>               Shell commands the AI CLIs must not run (e.g. rm,git push)
+    --metrics-addr <addr>                  Serve Prometheus metrics on <addr>/metrics (e.g. 127.0.0.1:9464)
+    --metrics-file <path>                  Write Prometheus metrics to a textfile on exit
+    --screenshot-threshold <pct>           Max % of pixels a screenshot may differ from its baseline (default: 0.5)
+    --serve <addr>                         Status page with live log and cancel/escalate buttons (e.g. :8080)
+                                           (only on 127.0.0.1 unless RALPH_SERVE_TOKEN is set)
     --no-learnings                         Disable learnings persistence
+    --no-global-learnings                  Don't merge ~/.config/ralph-loop/learnings/ into prompts
+    --no-project-memory                    Don't show the first prompt what earlier sessions escalated on,
+                                           blocked and learned (.ralph-loop/sessions/)
     --no-cross-validate                    Disable cross-validation phase
+    --min-confidence <0-1>                 Cross-validate COMPLETE verdicts the validator is less sure of,
+                                           even with --no-cross-validate (default: 0, off)
+    --auto-cross-validate <list>           Cross-validate suspicious COMPLETE verdicts even with --no-cross-validate:
+                                           first-complete (on iteration 1), no-changes (tasks checked but no
+                                           file changed), max-checked=N (more than N tasks checked at once)
+    --custom-verdicts <spec>               Extra verdicts the validator may return: "NAME=ACTION[: description]",
+                                           ';'-separated; ACTION: continue, escalate or exit-code N
+    --external-validators <spec>           Commands validating each iteration next to the AI validator:
+                                           "NAME=COMMAND", ';'-separated. They read the iteration as JSON on
+                                           stdin and print {"verdict": ..., "feedback": ..., "findings": [...]};
+                                           the most severe verdict wins
+    --verify-cmds <cmds>                   Test commands run after each implementation phase, ';'-separated
+                                           (e.g. "go test -json ./..."). Their pass/fail/skip counts and
+                                           failing tests go to the validator, compared with the last iteration
+    --verify-reports <globs>               TRX or JUnit XML reports the verify commands write (e.g.
+                                           TestResults/*.trx,junit.xml)
+    --policy-file <file>                   YAML inadmissible practice policies, merged into the built-in ones
+    --protected-paths <list>               Paths or globs the implementer must not change (e.g. .github/workflows,infra/prod)
+    --protected-paths-action <action>      revert the changes and tell the model, or escalate (default: revert)
+    --allow-dirty                          Start even with uncommitted changes, on a protected branch or
+                                           during a rebase or merge
+    --protected-branches <list>            Branches a new session refuses to start on (default: main,master)
+    --evidence-patterns <list>             Tasks starting with these need a RALPH_EVIDENCE entry with the command
+                                           run and its output, or are unchecked (default: Deploy,Run tests,Verify)
+    --annotate-tasks                       Write a <!-- ralph: completed in iter N, evidence: ... --> note on each
+                                           task the loop completes
+    --hook-pre-iteration <cmd>             Shell command run before each iteration; a failure stops the loop
+    --hook-post-implementation <cmd>       Shell command run after implementation; a failure sends its output
+                                           back to the implementer instead of validating
+    --hook-post-validation <cmd>           Shell command run after validation; a failure turns COMPLETE into
+                                           NEEDS_MORE_WORK with its output as feedback
+    --hook-on-exit <cmd>                   Shell command run when ralph-loop exits
+    --hook-timeout <seconds>               Time limit for each hook run (default: 600, 0 none). Hooks read a
+                                           JSON event on stdin; RALPH_HOOK_EVENT names the hook point
+    --no-pr-comment                        Disable the summary comment on the branch's open PR
+    --pr <int>                             PR to post the summary comment on (default: from GITHUB_REF in
+                                           GitHub Actions, else the branch's open PR)
+    --github-check                         Report the session as a GitHub check run on the commit, updated
+                                           every iteration with validator annotations (needs GITHUB_TOKEN)
+    --coverage-file <file>                 Coverage report (Go profile, lcov, Cobertura XML) whose change
+                                           over the session the PR summary shows
+    --no-cache                             Always call the AI; by default tasks validation, final-plan
+                                           validation and summaries reuse the response to an identical
+                                           prompt over unchanged files (cached in ~/.cache/ralph-loop)
+    --cache-ttl <duration>                 How long a cached response is reused (default: 24h, 0 forever)
 
   Scheduling:
     --start-at <time>                      Schedule start time (ISO 8601, HH:MM, YYYY-MM-DD HH:MM)
     --at <time>                            Alias for --start-at
+    --pause-between <windows>              Don't start iterations during these windows; state is saved and
+                                           the loop sleeps until the window ends. "HH:MM-HH:MM [days]",
+                                           ';'-separated; days: daily, weekdays, weekends, mon-fri, sat,sun
 
   Notifications:
     --notify-webhook <url>                 OpenClaw webhook URL (default: http://127.0.0.1:18789/webhook)
     --notify-channel <channel>             Notification channel (default: telegram)
     --notify-chat-id <id>                  Recipient chat ID (required to enable notifications)
+    --notify-events <list>                 Events to notify (default: exit,heartbeat: the one that ends the
+                                           run, and heartbeats when enabled); event names, or all, exit
+                                           and progress (iteration, rate_limited, heartbeat)
+    --notify-digest <duration>             Send progress events as one digest per interval (e.g. 30m)
+                                           instead of one message each (default: 0, each at once)
+    --heartbeat-interval <duration>        Send a heartbeat with the iteration, phase and elapsed time
+                                           this often, to tell a working run from a hung one (e.g. 30m)
+    --heartbeat-iterations <int>           Send a heartbeat every N iterations (default: 0, never)
 
   Session Management:
     --resume                               Resume from last interrupted session
     --resume-force                         Resume even if tasks.md changed (implies --resume)
     --clean                                Delete state directory and start fresh
+    --revalidate-plan                      Run final-plan validation even when the spec, tasks and plan
+                                           are unchanged since it confirmed them
     --status                               Show session status and exit
     --cancel                               Cancel active session and exit
+    --force-unlock                         Take over a state lock left by another ralph-loop process
+
+  Recording:
+    --record <dir>                         Save every AI prompt and output, per phase and call, in <dir>
+    --replay <dir>                         Run from a --record directory instead of the AI CLIs (same flags and
+                                           tasks; calls are answered in recorded order)
 
   Help & Version:
     -h, --help                             Show this help text
     --version                              Show version, commit, build date
 
+ENVIRONMENT
+  Every config file key can also be set as an environment variable with the
+  RALPH_ prefix (e.g. RALPH_MAX_ITERATIONS=30, RALPH_AI_CLI=codex).
+  Precedence: defaults < ~/.config/ralph-loop/config < .ralph-loop/config
+  < --config file < profile < RALPH_* environment < CLI flags.
+
+  Config files may define named presets in [profile.<name>] sections, each
+  running until the next section header:
+    [profile.cheap]
+    IMPL_MODEL=sonnet
+    MAX_ITERATIONS=10
+  --profile (or PROFILE / RALPH_PROFILE) picks one; the same profile in a
+  later file overrides keys of an earlier one.
+
+  Per-phase sampling (config file or environment only), where PHASE is one of
+  IMPL, VAL, CROSS, FINAL_PLAN, TASKS_VAL:
+    PHASE_REASONING_EFFORT                 minimal, low, medium or high (not supported by q and gh copilot)
+    PHASE_TEMPERATURE                      rejected: none of the AI CLIs accepts a temperature
+
+  OpenTelemetry tracing is enabled when OTEL_EXPORTER_OTLP_ENDPOINT (or
+  OTEL_EXPORTER_OTLP_TRACES_ENDPOINT) is set. Spans are sent over OTLP/HTTP
+  as JSON; OTEL_EXPORTER_OTLP_HEADERS, OTEL_SERVICE_NAME and
+  OTEL_RESOURCE_ATTRIBUTES are honoured.
+
+  GitLab issues are read with the glab CLI, or from the API when GITLAB_TOKEN
+  is set (GITLAB_HOST defaults to gitlab.com). Gitea issues are read from the
+  API at the issue URL's host or GITEA_URL, authenticated with GITEA_TOKEN.
+  Jira issues are read from JIRA_URL (or the browse URL's host) with
+  JIRA_TOKEN, as basic auth when JIRA_EMAIL is set (Jira Cloud) and as a
+  bearer token otherwise; JIRA_ACCEPTANCE_FIELD names the acceptance
+  criteria field (default: the field called "Acceptance Criteria").
+
 EXIT CODES
   0   Success              All tasks complete and validated
   1   Error                Invalid arguments, file not found, misconfiguration
@@ -69,7 +276,9 @@ EXIT CODES
   4   Blocked              All tasks blocked on external dependencies
   5   TasksInvalid         Tasks don't properly implement original plan
   6   Inadmissible         Inadmissible violation threshold exceeded
+  7   Budget               --max-duration reached before completion (resume with --resume)
   130 Interrupted          SIGINT or SIGTERM received
+  Run ralph-loop explain-exit <code> for what a code means and how to recover.
 
 EXAMPLES
   # Start a new loop with default settings
@@ -87,10 +296,31 @@ EXAMPLES
   # Check session status
   ralph-loop --status
 
+  # Hand a session over to another machine
+  ralph-loop export -o session.tar.gz
+  ralph-loop import session.tar.gz && ralph-loop --resume
+
+  # Enable shell completion for the current bash session
+  source <(ralph-loop completion bash)
+
 For more information, see: https://github.com/CodexForgeBR/cli-tools
 `
 
+const subcommandHelpTemplate = `{{.Long}}
+
+USAGE
+  {{.UseLine}}
+
+FLAGS
+{{.LocalFlags.FlagUsages}}`
+
 // SetCustomHelp configures the cobra command to use our custom help template.
 func SetCustomHelp(cmd *cobra.Command) {
 	cmd.SetHelpTemplate(helpTemplate)
 }
+
+// SetSubcommandHelp gives a subcommand its own help text instead of
+// inheriting the root command's template.
+func SetSubcommandHelp(cmd *cobra.Command) {
+	cmd.SetHelpTemplate(subcommandHelpTemplate)
+}
@@ -22,20 +22,93 @@ func TestHelpTemplate_ContainsKeyFlags(t *testing.T) {
 		"--final-plan-validation-model",
 		"--tasks-validation-ai",
 		"--tasks-validation-model",
+		"--summary-model",
+		"--summary-ai",
+		"--triage",
+		"--triage-ai",
+		"--triage-model",
+		"--validation-ai",
+		"--impl-output-max-tokens",
+		"--max-format-retries",
 		"--max-iterations",
 		"--max-inadmissible",
 		"--max-claude-retry",
 		"--max-turns",
 		"--inactivity-timeout",
+		"--hang-timeout",
+		"--fallback-ai",
+		"--failover-after",
+		"--pr",
+		"--coverage-file",
+		"--github-check",
+		"--container-image",
+		"--container-runtime",
+		"--container-pull",
+		"--k8s-job-image",
+		"--k8s-job-namespace",
+		"--k8s-job-secrets",
+		"--k8s-job-pvc",
+		"--remote-agent",
+		"--spec-drift",
+		"--iteration-timeout",
+		"--impl-timeout",
+		"--val-timeout",
 		"--tasks-file",
+		"--spec-dir",
 		"--original-plan-file",
 		"--github-issue",
+		"--plan-from-issue",
+		"--issue-provider",
+		"--screenshot-threshold",
+		"--max-duration",
+		"--shutdown-grace",
+		"--no-cache",
+		"--notify-events",
+		"--notify-digest",
+		"--heartbeat-interval",
+		"--heartbeat-iterations",
+		"--cache-ttl",
+		"--jira-issue",
 		"--learnings-file",
 		"--config",
 		"--verbose",
+		"--log-max-mb",
+		"--retain-iterations",
+		"--retain-mb",
+		"--log-gzip",
+		"--output-gzip-kb",
+		"--dedup-outputs",
 		"--no-learnings",
 		"--no-cross-validate",
 		"--start-at",
+		"--learnings-tags",
+		"--no-global-learnings",
+		"--no-project-memory",
+		"--learnings-max-tokens",
+		"--context-max-tokens",
+		"--val-diff-max-tokens",
+		"--min-confidence",
+		"--custom-verdicts",
+		"--external-validators",
+		"--verify-cmds",
+		"--verify-reports",
+		"--policy-file",
+		"--protected-paths",
+		"--protected-paths-action",
+		"--allow-dirty",
+		"--val-rotate",
+		"--auto-cross-validate",
+		"--evidence-patterns",
+		"--annotate-tasks",
+		"--allowed-tools",
+		"--denied-commands",
+		"--protected-branches",
+		"--hook-pre-iteration",
+		"--hook-post-implementation",
+		"--hook-post-validation",
+		"--hook-on-exit",
+		"--hook-timeout",
+		"--pause-between",
 		"--at",
 		"--notify-webhook",
 		"--notify-channel",
@@ -43,8 +116,13 @@ func TestHelpTemplate_ContainsKeyFlags(t *testing.T) {
 		"--resume",
 		"--resume-force",
 		"--clean",
+		"--revalidate-plan",
 		"--status",
 		"--cancel",
+		"--record",
+		"--replay",
+		"--profile",
+		"--state-dir",
 		"--help",
 		"--version",
 	}
@@ -63,12 +141,14 @@ func TestHelpTemplate_ContainsExitCodes(t *testing.T) {
 		"Blocked",
 		"TasksInvalid",
 		"Inadmissible",
+		"Budget",
 		"Interrupted",
 	}
 
 	for _, code := range exitCodes {
 		assert.Contains(t, helpTemplate, code, "Help template should contain exit code: %s", code)
 	}
+	assert.Contains(t, helpTemplate, "ralph-loop explain-exit <code>")
 }
 
 func TestHelpTemplate_ContainsSections(t *testing.T) {
@@ -2,16 +2,22 @@
 //
 // Configuration is assembled from multiple sources with a strict precedence
 // chain: built-in defaults < global config file < project config file <
-// explicit config file < CLI flag overrides.
+// explicit config file < RALPH_* environment variables < CLI flag overrides.
 package config
 
+import (
+	"strings"
+	"time"
+)
+
 // WhitelistedVars lists every configuration variable name that may appear in
-// config files. Variables not in this list are silently ignored during loading.
-// The list contains exactly 21 entries matching the data model specification.
+// config files or, prefixed with RALPH_, in the environment. Variables not in
+// this list are silently ignored during loading.
+// The list contains exactly 77 entries matching the data model specification.
 //
 // Note: TASKS_FILE, ORIGINAL_PLAN_FILE, and GITHUB_ISSUE are session-specific
 // CLI-only flags and are intentionally excluded from config file loading.
-var WhitelistedVars = [21]string{
+var WhitelistedVars = [117]string{
 	"AI_CLI",
 	"IMPL_MODEL",
 	"VAL_MODEL",
@@ -33,21 +39,140 @@ var WhitelistedVars = [21]string{
 	"NOTIFY_WEBHOOK",
 	"NOTIFY_CHANNEL",
 	"NOTIFY_CHAT_ID",
+	"PR_COMMENT",
+	"PR_NUMBER",
+	"COVERAGE_FILE",
+	"GITHUB_CHECK",
+	"MAX_TASK_ATTEMPTS",
+	"IMPL_TEMPERATURE",
+	"IMPL_REASONING_EFFORT",
+	"VAL_TEMPERATURE",
+	"VAL_REASONING_EFFORT",
+	"CROSS_TEMPERATURE",
+	"CROSS_REASONING_EFFORT",
+	"FINAL_PLAN_TEMPERATURE",
+	"FINAL_PLAN_REASONING_EFFORT",
+	"TASKS_VAL_TEMPERATURE",
+	"TASKS_VAL_REASONING_EFFORT",
+	"IMPL_MODEL_LADDER",
+	"ESCALATE_AFTER",
+	"VALIDATORS",
+	"VALIDATOR_POOL",
+	"SANDBOX_CMD",
+	"SANDBOX_ENV",
+	"CONTAINER_IMAGE",
+	"CONTAINER_RUNTIME",
+	"CONTAINER_PULL",
+	"K8S_JOB_IMAGE",
+	"K8S_JOB_NAMESPACE",
+	"K8S_JOB_SECRETS",
+	"K8S_JOB_PVC",
+	"REMOTE_AGENT",
+	"APPLY_PATCH",
+	"METRICS_ADDR",
+	"METRICS_FILE",
+	"TUI",
+	"SERVE",
+	"ISSUE_PROVIDER",
+	"SCREENSHOT_THRESHOLD",
+	"MAX_DURATION",
+	"ITERATION_TIMEOUT",
+	"IMPL_TIMEOUT",
+	"VAL_TIMEOUT",
+	"PAUSE_BETWEEN",
+	"GLOBAL_LEARNINGS",
+	"LEARNINGS_TAGS",
+	"LEARNINGS_MAX_TOKENS",
+	"CONTEXT_MAX_TOKENS",
+	"VAL_DIFF_MAX_TOKENS",
+	"MIN_CONFIDENCE",
+	"CUSTOM_VERDICTS",
+	"MAX_FORMAT_RETRIES",
+	"LOG_MAX_MB",
+	"LOG_GZIP",
+	"OUTPUT_GZIP_KB",
+	"DEDUP_OUTPUTS",
+	"RETAIN_ITERATIONS",
+	"RETAIN_MB",
+	"POLICY_FILE",
+	"PROTECTED_PATHS",
+	"PROTECTED_PATHS_ACTION",
+	"ALLOW_DIRTY",
+	"PROTECTED_BRANCHES",
+	"SUMMARY_MODEL",
+	"IMPL_OUTPUT_MAX_TOKENS",
+	"HOOK_PRE_ITERATION",
+	"HOOK_POST_IMPLEMENTATION",
+	"HOOK_POST_VALIDATION",
+	"HOOK_ON_EXIT",
+	"HOOK_TIMEOUT",
+	"EXTERNAL_VALIDATORS",
+	"PROFILE",
+	"VAL_AI",
+	"SUMMARY_AI",
+	"VAL_ROTATE",
+	"AUTO_CROSS_VALIDATE",
+	"EVIDENCE_PATTERNS",
+	"ALLOWED_TOOLS",
+	"DENIED_COMMANDS",
+	"SHUTDOWN_GRACE",
+	"RESPONSE_CACHE",
+	"CACHE_TTL",
+	"NOTIFY_EVENTS",
+	"NOTIFY_DIGEST",
+	"HEARTBEAT_INTERVAL",
+	"HEARTBEAT_ITERATIONS",
+	"HANG_TIMEOUT",
+	"FALLBACK_AI",
+	"FAILOVER_AFTER",
+	"SPEC_DRIFT",
+	"ANNOTATE_TASKS",
+	"TRIAGE",
+	"TRIAGE_AI",
+	"TRIAGE_MODEL",
+	"VERIFY_CMDS",
+	"VERIFY_REPORTS",
+	"PROJECT_MEMORY",
+	"STATE_KEY",
+	"STATE_DIR",
 }
 
 // Config holds every configuration field for the ralph-loop CLI.
 // See the data model specification for field semantics.
 type Config struct {
-	// AI provider and model selection.
+	// AI provider and model selection. ValAI is the provider of the
+	// validation phase; empty uses AIProvider.
 	AIProvider string
+	ValAI      string
 	ImplModel  string
 	ValModel   string
 
+	// Implementation model escalation: a comma-separated ladder of models,
+	// cheapest first, climbed after EscalateAfter consecutive NEEDS_MORE_WORK
+	// verdicts on the same tasks. An empty ladder disables escalation.
+	ImplModelLadder string
+	EscalateAfter   int
+
+	// Validator quorum: Validators runners vote on each iteration. The
+	// optional ValidatorPool ("claude:opus,codex") assigns providers and
+	// models round-robin; otherwise every validator uses ValAI/ValModel.
+	// With ValRotate a single validator judges each iteration, taken from
+	// the pool in turn.
+	Validators    int
+	ValidatorPool string
+	ValRotate     bool
+
 	// Cross-validation settings.
 	CrossValidate bool
 	CrossAI       string
 	CrossModel    string
 
+	// Failover settings. FallbackAI takes over the calls of AIProvider
+	// for the rest of the session once a health check, run at startup
+	// and after FailoverAfter consecutive failed calls, finds it down.
+	FallbackAI    string
+	FailoverAfter int
+
 	// Final plan validation settings.
 	FinalPlanAI    string
 	FinalPlanModel string
@@ -56,60 +181,559 @@ type Config struct {
 	TasksValAI    string
 	TasksValModel string
 
+	// SummaryAI and SummaryModel summarize implementation output above
+	// ImplOutputMaxTokens. Empty uses AIProvider and its small model (see
+	// model.DefaultSummaryModel).
+	SummaryAI    string
+	SummaryModel string
+
+	// Triage checks each iteration with TriageAI and TriageModel, a cheap
+	// model, before validation: an iteration claiming work it obviously
+	// did not do is sent back without the full validation. Empty uses
+	// ValAI and its small model (see model.DefaultSummaryModel).
+	Triage      bool
+	TriageAI    string
+	TriageModel string
+
+	// Per-phase sampling parameters (<PHASE>_REASONING_EFFORT). Zero values
+	// leave the CLI defaults in place. <PHASE>_TEMPERATURE is whitelisted
+	// only to be rejected: none of the AI CLIs accepts a temperature.
+	ImplSampling      Sampling
+	ValSampling       Sampling
+	CrossSampling     Sampling
+	FinalPlanSampling Sampling
+	TasksValSampling  Sampling
+
 	// Iteration limits.
 	MaxIterations   int
 	MaxInadmissible int
 	MaxClaudeRetry  int
 	MaxTurns        int
+	MaxTaskAttempts int // consecutive incomplete iterations before a task is skipped; 0 disables
+	// MaxFormatRetries is how many times the validator is asked to re-emit
+	// a RALPH_VALIDATION block it got wrong before the iteration is judged
+	// INADMISSIBLE.
+	MaxFormatRetries int
 
 	// Timeouts.
 	InactivityTimeout int
+	// HangTimeout is the watchdog's limit in seconds on an AI CLI showing
+	// neither output nor CPU or I/O activity; 0 disables it.
+	HangTimeout int
+	// Hard limits in seconds on an iteration's implementation and validation
+	// phases, together and individually. 0 disables a limit.
+	IterationTimeout int
+	ImplTimeout      int
+	ValTimeout       int
+	// MaxDuration is the wall-clock budget of a run; no iteration is started
+	// that would end past it. 0 means unlimited.
+	MaxDuration time.Duration
+	// ShutdownGrace is how long, after SIGTERM, the AI CLI call in progress
+	// may run before it is interrupted; no new call is started meanwhile.
+	ShutdownGrace time.Duration
+	// PauseBetween lists the windows, e.g. "09:00-18:00 weekdays", during
+	// which no iteration starts (see schedule.ParsePauseWindows).
+	PauseBetween string
+	// SpecDrift says what happens when the spec the tasks are validated
+	// against changes mid-run (see SpecDriftOff).
+	SpecDrift string
 
 	// File paths.
 	LearningsFile   string
 	EnableLearnings bool
+	// GlobalLearnings merges the user-level learnings library for
+	// LearningsTags (detected from the project when empty) into prompts.
+	GlobalLearnings bool
+	LearningsTags   string
+	// ProjectMemory shows the first implementation prompt of a session
+	// what earlier sessions in the project escalated on, blocked and
+	// learned (see state.SessionOutcome).
+	ProjectMemory bool
+	// LearningsMaxTokens is the estimated size above which the prompt
+	// learnings are replaced by a digest from the validation model.
+	// 0 disables summarization.
+	LearningsMaxTokens int
+	// ContextMaxTokens bounds the excerpts of code related to the pending
+	// tasks that are packed into implementation prompts. 0 disables them.
+	ContextMaxTokens int
+	// ValDiffMaxTokens caps the git diff of the iteration shown to the
+	// validator. 0 leaves the diff out of the validation prompt.
+	ValDiffMaxTokens int
+	// ImplOutputMaxTokens is the estimated size above which the validator
+	// gets a summary of the implementation output with the path of the full
+	// file. 0 disables summarization.
+	ImplOutputMaxTokens int
 
 	// Runtime flags.
 	Verbose bool
 
+	// Per-iteration phase logs (iteration-NNN/impl.log, val.log, ...).
+	// LogMaxMB is the size at which a log is rotated, 0 never rotates;
+	// LogGzip compresses the logs of finished iterations.
+	LogMaxMB int
+	LogGzip  bool
+
+	// Compaction of finished iterations: OutputGzipKB gzips the AI outputs
+	// and logs of this size in KB or more, 0 never; DedupOutputs makes the
+	// files identical to the previous iteration's hard links to them.
+	OutputGzipKB int
+	DedupOutputs bool
+
+	// Retention of the iteration directories: at the end of every
+	// iteration the oldest are removed beyond the last RetainIterations,
+	// or beyond RetainMB in total. 0 keeps them all.
+	RetainIterations int
+	RetainMB         int
+
+	// StateDir is where the session state is kept (see ProjectStateDir);
+	// empty keeps it in DefaultStateDir, inside the project.
+	StateDir string
+	// StateKey, base64 of 32 bytes, encrypts current-state.json, the
+	// session outcomes and the outputs of finished iterations at rest
+	// (see statecrypt). Empty keeps them plain.
+	StateKey string
+
+	// ApplyPatch asks the implementation model for a RALPH_PATCH unified
+	// diff and applies it with internal/patch instead of letting the CLI
+	// agent edit files directly.
+	ApplyPatch bool
+
 	// Notification settings.
 	NotifyWebhook string
 	NotifyChannel string
 	NotifyChatID  string
+	// NotifyEvents selects the events notified, as event names and the
+	// groups all, exit and progress (see notification.ParseEvents).
+	NotifyEvents string
+	// NotifyDigest batches progress events into one message per interval;
+	// 0 sends each at once.
+	NotifyDigest time.Duration
+	// HeartbeatInterval and HeartbeatIterations send a heartbeat with the
+	// iteration, phase and elapsed time every interval and every that many
+	// iterations; 0 disables each.
+	HeartbeatInterval   time.Duration
+	HeartbeatIterations int
+
+	// PR integration settings. PRNumber is the pull request to comment on;
+	// 0 detects it from GITHUB_REF or the current branch. CoverageFile is
+	// the coverage report whose change over the session the comment shows.
+	PRComment    bool
+	PRNumber     int
+	CoverageFile string
+	// GitHubCheck reports the session as a GitHub check run on the commit
+	// under test, updated every iteration, so its result can gate merges.
+	GitHubCheck bool
+
+	// Response cache settings. With ResponseCache on, the read-only phases
+	// (tasks validation, final-plan validation, summaries) reuse the
+	// response to an identical prompt over unchanged files for CacheTTL
+	// (0 = forever).
+	ResponseCache bool
+	CacheTTL      time.Duration
+
+	// Sandbox settings. SandboxCmd wraps every AI CLI invocation (e.g.
+	// "firejail --quiet --whitelist={workdir}"); SandboxEnv lists host
+	// variables passed into the sandbox. See ai.Sandbox for placeholders.
+	SandboxCmd string
+	SandboxEnv string
+
+	// Container settings. With ContainerImage set, every AI CLI runs in a
+	// fresh container of that image with the project bind-mounted, using
+	// ContainerRuntime ("docker" or "podman"; "" finds one) and pulling
+	// the image per ContainerPull (see ContainerPullMissing).
+	// SandboxEnv lists the host variables passed in.
+	ContainerImage   string
+	ContainerRuntime string
+	ContainerPull    string
+
+	// Kubernetes Job dispatch. With K8sJobImage set, every implementation
+	// call runs as a Job of that image in K8sJobNamespace ("" is the
+	// kubectl context's), with the project on the PersistentVolumeClaim
+	// K8sJobPVC and the keys of the comma-separated K8sJobSecrets as
+	// environment variables (see ai.KubeJob).
+	K8sJobImage     string
+	K8sJobNamespace string
+	K8sJobSecrets   string
+	K8sJobPVC       string
+
+	// RemoteAgent is the URL of a ralph-loop agent daemon that runs every
+	// AI call instead of this machine (see agent.RemoteRunner); the token
+	// comes from RALPH_AGENT_TOKEN.
+	RemoteAgent string
+
+	// Tool permissions passed to every AI CLI (see ai.Permissions).
+	// AllowedTools lists, comma-separated, the only tools the CLI may use,
+	// in the CLI's own syntax (e.g. "Edit,Bash(go test:*)"); DeniedCommands
+	// lists shell commands it must not run (e.g. "rm,git push"). Empty
+	// keeps the CLI's unrestricted defaults.
+	AllowedTools   string
+	DeniedCommands string
+
+	// Metrics settings. MetricsAddr serves Prometheus metrics on /metrics
+	// while the loop runs; MetricsFile writes them as a textfile on exit.
+	MetricsAddr string
+	MetricsFile string
+
+	// TUI replaces the scrolling log with a live terminal dashboard.
+	TUI bool
+
+	// Serve is the listen address of the web status server (e.g. ":8080");
+	// empty disables it.
+	Serve string
+
+	// IssueProvider selects where --github-issue is fetched from: github,
+	// gitlab or gitea. Empty detects it from the issue URL, defaulting to
+	// GitHub for bare references.
+	IssueProvider string
+
+	// ScreenshotThreshold is the percentage of differing pixels (0-100)
+	// below which a screenshot counts as unchanged from its baseline.
+	ScreenshotThreshold float64
+
+	// MinConfidence (0-1) is the validator confidence below which a
+	// COMPLETE verdict is cross-validated even when CrossValidate is off.
+	// 0 disables the check.
+	MinConfidence float64
+
+	// AutoCrossValidate lists the suspicious completions that are
+	// cross-validated even when CrossValidate is off, e.g.
+	// "first-complete,no-changes,max-checked=5" (see
+	// phases.ParseCrossTriggers). Empty disables them.
+	AutoCrossValidate string
+
+	// CustomVerdicts defines project verdicts and their actions, e.g.
+	// "SECURITY_REVIEW_NEEDED=exit-code 20: needs a security review"
+	// (see phases.ParseCustomVerdicts).
+	CustomVerdicts string
+
+	// ExternalValidators are commands judging each iteration next to the
+	// AI validator, as ';'-separated NAME=COMMAND entries (see
+	// extval.Parse). The most severe verdict wins.
+	ExternalValidators string
+
+	// VerifyCmds are shell commands, ';'-separated, run in the project
+	// root after each implementation phase, such as "go test -json ./...".
+	// The test results they print, and those in the VerifyReports files,
+	// are shown to the validator and compared with the previous
+	// iteration's.
+	VerifyCmds string
+
+	// VerifyReports are comma-separated globs of the TRX or JUnit XML
+	// reports the VerifyCmds write, relative to the project root. Only
+	// reports written during the run are read.
+	VerifyReports string
+
+	// PolicyFile is a YAML file of inadmissible practice policies merged
+	// into the built-in ones (see policy.Load). Empty uses the built-ins.
+	PolicyFile string
+
+	// ProtectedPaths lists, comma-separated, the paths the implementer must
+	// not change: files, directories or globs relative to the project root
+	// (e.g. ".github/workflows,infra/prod").
+	ProtectedPaths string
+	// ProtectedPathsAction is what happens when an iteration changes a
+	// protected path: ProtectedRevert puts the files back, ProtectedEscalate
+	// stops the loop for a human.
+	ProtectedPathsAction string
+
+	// EvidencePatterns lists, comma-separated, the task text prefixes
+	// (case-insensitive, after the task ID) of tasks that are only done
+	// with a RALPH_EVIDENCE entry, e.g. "Deploy,Run tests,Verify". Empty
+	// requires no evidence.
+	EvidencePatterns string
+	// AnnotateTasks writes a `<!-- ralph: ... -->` note onto each task the
+	// loop saw completed: the iteration and the artifacts kept for it.
+	AnnotateTasks bool
+
+	// AllowDirty lets a new session start on a working tree with
+	// uncommitted changes, on a protected branch or during an unfinished
+	// rebase or merge.
+	AllowDirty bool
+	// ProtectedBranches lists, comma-separated, the branches a new session
+	// refuses to start on unless AllowDirty is set.
+	ProtectedBranches string
+
+	// Lifecycle hooks: shell commands run with a JSON event on stdin (see
+	// package hooks). Empty disables a hook.
+	HookPreIteration       string
+	HookPostImplementation string
+	HookPostValidation     string
+	HookOnExit             string
+	// HookTimeout bounds each hook run, in seconds. 0 means no limit.
+	HookTimeout int
+
+	// Profile names the [profile.NAME] section of the config files whose
+	// settings are applied over the files (see LoadWithPrecedence).
+	Profile string
 
 	// CLI-only flags (not loaded from config files).
 	TasksFile        string
+	SpecDir          string
 	OriginalPlanFile string
 	GithubIssue      string
+	JiraIssue        string
+	PlanFromIssue    bool
 	ConfigFile       string
 	Resume           bool
 	ResumeForce      bool
 	Clean            bool
+	RevalidatePlan   bool
 	Status           bool
 	Cancel           bool
+	ForceUnlock      bool
 	StartAt          string
 
+	// Record and Replay are directories of recorded AI interactions (see
+	// ai.Recording): Record saves every prompt and output, Replay answers
+	// every call from a recording instead of running an AI CLI.
+	Record string
+	Replay string
+
+	// SpecFile is the feature spec (spec.md) of a spec-kit folder, found
+	// from --spec-dir or next to the tasks file. It takes precedence over
+	// OriginalPlanFile as the document tasks are validated against.
+	SpecFile string
+
+	// TasksFiles holds every --tasks-file value when the flag is repeated
+	// or given a glob; TasksFile is then the first of them.
+	TasksFiles []string
+
 	// CLIOverrides records which config keys were explicitly set via CLI
 	// flags. During resume, saved-state values are only restored for keys
 	// that are NOT present in this map, so explicit CLI flags always win.
 	CLIOverrides map[string]bool
+
+	// Secrets are the values of the secret settings (see Setting.Secret),
+	// which logs and state files redact.
+	Secrets []string
 }
 
 // NewDefaultConfig returns a Config populated with all built-in default values.
 func NewDefaultConfig() *Config {
 	return &Config{
-		AIProvider:        "claude",
-		ImplModel:         "opus",
-		ValModel:          "opus",
-		CrossValidate:     true,
-		MaxIterations:     20,
-		MaxInadmissible:   5,
-		MaxClaudeRetry:    10,
-		MaxTurns:          100,
-		InactivityTimeout: 1800,
-		LearningsFile:     ".ralph-loop/learnings.md",
-		EnableLearnings:   true,
-		NotifyWebhook:     "http://127.0.0.1:18789/webhook",
-		NotifyChannel:     "telegram",
+		AIProvider:         "claude",
+		ImplModel:          "opus",
+		ValModel:           "opus",
+		EscalateAfter:      2,
+		Validators:         1,
+		CrossValidate:      true,
+		MaxIterations:      20,
+		MaxInadmissible:    5,
+		MaxClaudeRetry:     10,
+		MaxTurns:           100,
+		MaxTaskAttempts:    3,
+		MaxFormatRetries:   2,
+		InactivityTimeout:  1800,
+		HangTimeout:        600,
+		FailoverAfter:      3,
+		LearningsFile:      ".ralph-loop/learnings.md",
+		EnableLearnings:    true,
+		GlobalLearnings:    true,
+		ProjectMemory:      true,
+		LearningsMaxTokens: 8000,
+		ValDiffMaxTokens:   10000,
+		LogMaxMB:           10,
+		NotifyWebhook:      "http://127.0.0.1:18789/webhook",
+		NotifyChannel:      "telegram",
+		NotifyEvents:       "exit,heartbeat",
+		ContainerPull:      ContainerPullMissing,
+		SpecDrift:          SpecDriftOff,
+		PRComment:          true,
+
+		ImplOutputMaxTokens:  30000,
+		ProtectedPathsAction: ProtectedRevert,
+		ProtectedBranches:    "main,master",
+		EvidencePatterns:     "Deploy,Run tests,Verify",
+		HookTimeout:          600,
+		ShutdownGrace:        5 * time.Minute,
+		ResponseCache:        true,
+		CacheTTL:             24 * time.Hour,
+
+		ScreenshotThreshold: 0.5,
+	}
+}
+
+// ContainerPull values.
+const (
+	ContainerPullMissing = "missing"
+	ContainerPullAlways  = "always"
+	ContainerPullNever   = "never"
+)
+
+// SpecDrift values. With SpecDriftOff the spec is read once; otherwise it
+// is re-read, and an issue re-fetched, before every iteration, and when it
+// changed the tasks are validated against it again. Tasks that no longer
+// match pause the loop for the user to update them (SpecDriftPause) or are
+// regenerated from the new spec (SpecDriftRegenerate).
+const (
+	SpecDriftOff        = "off"
+	SpecDriftPause      = "pause"
+	SpecDriftRegenerate = "regenerate"
+)
+
+// ProtectedPathsAction values.
+const (
+	ProtectedRevert   = "revert"
+	ProtectedEscalate = "escalate"
+)
+
+// ReasoningEfforts lists the accepted <PHASE>_REASONING_EFFORT values.
+var ReasoningEfforts = []string{"minimal", "low", "medium", "high"}
+
+// Sampling holds the sampling parameters for one AI phase.
+type Sampling struct {
+	// ReasoningEffort is one of ReasoningEfforts, or "" for the CLI default.
+	ReasoningEffort string
+}
+
+// MergeCLIOnly copies the CLI-only flags of from, which no config file
+// sets, into c.
+func (c *Config) MergeCLIOnly(from *Config) {
+	c.TasksFile = from.TasksFile
+	c.TasksFiles = from.TasksFiles
+	c.SpecDir = from.SpecDir
+	c.OriginalPlanFile = from.OriginalPlanFile
+	c.GithubIssue = from.GithubIssue
+	c.JiraIssue = from.JiraIssue
+	c.PlanFromIssue = from.PlanFromIssue
+	c.ConfigFile = from.ConfigFile
+	c.Resume = from.Resume
+	c.ResumeForce = from.ResumeForce
+	c.Clean = from.Clean
+	c.RevalidatePlan = from.RevalidatePlan
+	c.Status = from.Status
+	c.Cancel = from.Cancel
+	c.ForceUnlock = from.ForceUnlock
+	c.StartAt = from.StartAt
+	c.Record = from.Record
+	c.Replay = from.Replay
+}
+
+// ModelLadder returns the models in ImplModelLadder, in order, with blank
+// entries removed.
+func (c *Config) ModelLadder() []string {
+	var ladder []string
+	for _, m := range strings.Split(c.ImplModelLadder, ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			ladder = append(ladder, m)
+		}
+	}
+	return ladder
+}
+
+// ProtectedBranchList returns the branches in ProtectedBranches, with
+// blank entries removed.
+func (c *Config) ProtectedBranchList() []string {
+	var branches []string
+	for _, b := range strings.Split(c.ProtectedBranches, ",") {
+		if b = strings.TrimSpace(b); b != "" {
+			branches = append(branches, b)
+		}
+	}
+	return branches
+}
+
+// EvidencePatternList returns the entries of EvidencePatterns, with blank
+// entries removed.
+func (c *Config) EvidencePatternList() []string {
+	var patterns []string
+	for _, p := range strings.Split(c.EvidencePatterns, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// AllowedToolList returns the entries of AllowedTools, with blank entries
+// removed.
+func (c *Config) AllowedToolList() []string {
+	var tools []string
+	for _, t := range strings.Split(c.AllowedTools, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tools = append(tools, t)
+		}
+	}
+	return tools
+}
+
+// DeniedCommandList returns the entries of DeniedCommands, with blank
+// entries removed.
+func (c *Config) DeniedCommandList() []string {
+	var commands []string
+	for _, cmd := range strings.Split(c.DeniedCommands, ",") {
+		if cmd = strings.TrimSpace(cmd); cmd != "" {
+			commands = append(commands, cmd)
+		}
+	}
+	return commands
+}
+
+// SandboxEnvVars returns the variable names in SandboxEnv, with blank
+// entries removed.
+func (c *Config) SandboxEnvVars() []string {
+	var vars []string
+	for _, v := range strings.Split(c.SandboxEnv, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			vars = append(vars, v)
+		}
+	}
+	return vars
+}
+
+// K8sJobSecretNames returns the secret names in K8sJobSecrets, with blank
+// entries removed.
+func (c *Config) K8sJobSecretNames() []string {
+	var names []string
+	for _, s := range strings.Split(c.K8sJobSecrets, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			names = append(names, s)
+		}
+	}
+	return names
+}
+
+// ValidatorSpec identifies the provider and model of one quorum validator.
+// An empty Model means the provider's default validation model.
+type ValidatorSpec struct {
+	AI    string
+	Model string
+}
+
+// ValidatorSpecs returns one spec per validator. Pool entries ("ai" or
+// "ai:model") are assigned round-robin; without a pool every validator uses
+// ValAI (or AIProvider when unset) and ValModel.
+func (c *Config) ValidatorSpecs() []ValidatorSpec {
+	pool := c.ValidatorPoolSpecs()
+	if len(pool) == 0 {
+		ai := c.ValAI
+		if ai == "" {
+			ai = c.AIProvider
+		}
+		pool = []ValidatorSpec{{AI: ai, Model: c.ValModel}}
+	}
+
+	n := c.Validators
+	if n < 1 {
+		n = 1
+	}
+	specs := make([]ValidatorSpec, n)
+	for i := range specs {
+		specs[i] = pool[i%len(pool)]
+	}
+	return specs
+}
+
+// ValidatorPoolSpecs returns the entries of ValidatorPool, in order.
+func (c *Config) ValidatorPoolSpecs() []ValidatorSpec {
+	var pool []ValidatorSpec
+	for _, entry := range strings.Split(c.ValidatorPool, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		ai, model, _ := strings.Cut(entry, ":")
+		pool = append(pool, ValidatorSpec{AI: strings.TrimSpace(ai), Model: strings.TrimSpace(model)})
 	}
+	return pool
 }
@@ -2,6 +2,7 @@ package config_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,6 +24,10 @@ func TestNewDefaultConfigValues(t *testing.T) {
 	assert.Empty(t, cfg.CrossAI)
 	assert.Empty(t, cfg.CrossModel)
 
+	// Failover.
+	assert.Empty(t, cfg.FallbackAI)
+	assert.Equal(t, 3, cfg.FailoverAfter)
+
 	// Final plan validation.
 	assert.Empty(t, cfg.FinalPlanAI)
 	assert.Empty(t, cfg.FinalPlanModel)
@@ -30,15 +35,24 @@ func TestNewDefaultConfigValues(t *testing.T) {
 	// Tasks validation.
 	assert.Empty(t, cfg.TasksValAI)
 	assert.Empty(t, cfg.TasksValModel)
+	assert.Empty(t, cfg.SummaryModel)
+	assert.Empty(t, cfg.ValAI)
+	assert.Empty(t, cfg.SummaryAI)
+	assert.False(t, cfg.Triage)
+	assert.Empty(t, cfg.TriageAI)
+	assert.Empty(t, cfg.TriageModel)
 
 	// Iteration limits.
 	assert.Equal(t, 20, cfg.MaxIterations)
 	assert.Equal(t, 5, cfg.MaxInadmissible)
+	assert.Equal(t, 3, cfg.MaxTaskAttempts)
+	assert.Equal(t, 2, cfg.MaxFormatRetries)
 	assert.Equal(t, 10, cfg.MaxClaudeRetry)
 	assert.Equal(t, 100, cfg.MaxTurns)
 
 	// Timeouts.
 	assert.Equal(t, 1800, cfg.InactivityTimeout)
+	assert.Equal(t, 600, cfg.HangTimeout)
 
 	// File paths.
 	assert.Empty(t, cfg.TasksFile)
@@ -49,11 +63,74 @@ func TestNewDefaultConfigValues(t *testing.T) {
 
 	// Runtime flags.
 	assert.False(t, cfg.Verbose)
+	assert.Equal(t, 10, cfg.LogMaxMB)
+	assert.Equal(t, 0, cfg.RetainIterations)
+	assert.Equal(t, 0, cfg.RetainMB)
+	assert.False(t, cfg.LogGzip)
+	assert.Equal(t, 0, cfg.OutputGzipKB)
+	assert.False(t, cfg.DedupOutputs)
 
 	// Notification settings.
 	assert.Equal(t, "http://127.0.0.1:18789/webhook", cfg.NotifyWebhook)
 	assert.Equal(t, "telegram", cfg.NotifyChannel)
 	assert.Empty(t, cfg.NotifyChatID)
+	assert.Equal(t, "exit,heartbeat", cfg.NotifyEvents)
+	assert.Zero(t, cfg.NotifyDigest)
+	assert.Zero(t, cfg.HeartbeatInterval)
+	assert.Zero(t, cfg.HeartbeatIterations)
+
+	// PR integration settings.
+	assert.True(t, cfg.PRComment)
+	assert.Zero(t, cfg.PRNumber)
+	assert.Empty(t, cfg.CoverageFile)
+	assert.False(t, cfg.GitHubCheck)
+
+	// Container settings.
+	assert.Empty(t, cfg.ContainerImage)
+	assert.Empty(t, cfg.ContainerRuntime)
+	assert.Equal(t, "missing", cfg.ContainerPull)
+
+	// Kubernetes Job settings.
+	assert.Empty(t, cfg.K8sJobImage)
+	assert.Empty(t, cfg.K8sJobNamespace)
+	assert.Empty(t, cfg.K8sJobSecrets)
+	assert.Empty(t, cfg.K8sJobPVC)
+	assert.Empty(t, cfg.RemoteAgent)
+	assert.Equal(t, "off", cfg.SpecDrift)
+
+	// Learnings library settings.
+	assert.True(t, cfg.GlobalLearnings)
+	assert.True(t, cfg.ProjectMemory)
+	assert.Empty(t, cfg.StateKey)
+	assert.Empty(t, cfg.StateDir)
+	assert.Empty(t, cfg.LearningsTags)
+	assert.Equal(t, 8000, cfg.LearningsMaxTokens)
+	assert.Zero(t, cfg.ContextMaxTokens)
+	assert.Equal(t, 10000, cfg.ValDiffMaxTokens)
+	assert.Equal(t, 30000, cfg.ImplOutputMaxTokens)
+	assert.Zero(t, cfg.MinConfidence)
+	assert.Empty(t, cfg.AutoCrossValidate)
+	assert.Empty(t, cfg.CustomVerdicts)
+	assert.Empty(t, cfg.ExternalValidators)
+	assert.Empty(t, cfg.VerifyCmds)
+	assert.Empty(t, cfg.VerifyReports)
+	assert.Empty(t, cfg.PolicyFile)
+	assert.Empty(t, cfg.ProtectedPaths)
+	assert.Equal(t, config.ProtectedRevert, cfg.ProtectedPathsAction)
+	assert.False(t, cfg.AllowDirty)
+	assert.Equal(t, "main,master", cfg.ProtectedBranches)
+	assert.Equal(t, "Deploy,Run tests,Verify", cfg.EvidencePatterns)
+	assert.False(t, cfg.AnnotateTasks)
+	assert.Empty(t, cfg.AllowedTools)
+	assert.Empty(t, cfg.DeniedCommands)
+	assert.Equal(t, 5*time.Minute, cfg.ShutdownGrace)
+	assert.True(t, cfg.ResponseCache)
+	assert.Equal(t, 24*time.Hour, cfg.CacheTTL)
+	assert.Empty(t, cfg.HookPreIteration)
+	assert.Empty(t, cfg.HookPostImplementation)
+	assert.Empty(t, cfg.HookPostValidation)
+	assert.Empty(t, cfg.HookOnExit)
+	assert.Equal(t, 600, cfg.HookTimeout)
 
 	// CLI-only flags default to zero values.
 	assert.Empty(t, cfg.ConfigFile)
@@ -63,10 +140,12 @@ func TestNewDefaultConfigValues(t *testing.T) {
 	assert.False(t, cfg.Status)
 	assert.False(t, cfg.Cancel)
 	assert.Empty(t, cfg.StartAt)
+	assert.Empty(t, cfg.Record)
+	assert.Empty(t, cfg.Replay)
 }
 
-func TestWhitelistedVarsContains21Entries(t *testing.T) {
-	assert.Len(t, config.WhitelistedVars, 21)
+func TestWhitelistedVarsContains117Entries(t *testing.T) {
+	assert.Len(t, config.WhitelistedVars, 117)
 }
 
 func TestWhitelistedVarsContainsAllExpectedNames(t *testing.T) {
@@ -92,6 +171,102 @@ func TestWhitelistedVarsContainsAllExpectedNames(t *testing.T) {
 		"NOTIFY_WEBHOOK",
 		"NOTIFY_CHANNEL",
 		"NOTIFY_CHAT_ID",
+		"PR_COMMENT",
+		"PR_NUMBER",
+		"COVERAGE_FILE",
+		"GITHUB_CHECK",
+		"MAX_TASK_ATTEMPTS",
+		"IMPL_TEMPERATURE",
+		"IMPL_REASONING_EFFORT",
+		"VAL_TEMPERATURE",
+		"VAL_REASONING_EFFORT",
+		"CROSS_TEMPERATURE",
+		"CROSS_REASONING_EFFORT",
+		"FINAL_PLAN_TEMPERATURE",
+		"FINAL_PLAN_REASONING_EFFORT",
+		"TASKS_VAL_TEMPERATURE",
+		"TASKS_VAL_REASONING_EFFORT",
+		"IMPL_MODEL_LADDER",
+		"ESCALATE_AFTER",
+		"VALIDATORS",
+		"VALIDATOR_POOL",
+		"SANDBOX_CMD",
+		"SANDBOX_ENV",
+		"CONTAINER_IMAGE",
+		"CONTAINER_RUNTIME",
+		"CONTAINER_PULL",
+		"K8S_JOB_IMAGE",
+		"K8S_JOB_NAMESPACE",
+		"K8S_JOB_SECRETS",
+		"K8S_JOB_PVC",
+		"REMOTE_AGENT",
+		"APPLY_PATCH",
+		"METRICS_ADDR",
+		"METRICS_FILE",
+		"TUI",
+		"SERVE",
+		"ISSUE_PROVIDER",
+		"SCREENSHOT_THRESHOLD",
+		"MAX_DURATION",
+		"ITERATION_TIMEOUT",
+		"IMPL_TIMEOUT",
+		"VAL_TIMEOUT",
+		"PAUSE_BETWEEN",
+		"GLOBAL_LEARNINGS",
+		"LEARNINGS_TAGS",
+		"LEARNINGS_MAX_TOKENS",
+		"CONTEXT_MAX_TOKENS",
+		"VAL_DIFF_MAX_TOKENS",
+		"MIN_CONFIDENCE",
+		"CUSTOM_VERDICTS",
+		"MAX_FORMAT_RETRIES",
+		"LOG_MAX_MB",
+		"LOG_GZIP",
+		"OUTPUT_GZIP_KB",
+		"DEDUP_OUTPUTS",
+		"RETAIN_ITERATIONS",
+		"RETAIN_MB",
+		"POLICY_FILE",
+		"PROTECTED_PATHS",
+		"PROTECTED_PATHS_ACTION",
+		"ALLOW_DIRTY",
+		"PROTECTED_BRANCHES",
+		"SUMMARY_MODEL",
+		"IMPL_OUTPUT_MAX_TOKENS",
+		"HOOK_PRE_ITERATION",
+		"HOOK_POST_IMPLEMENTATION",
+		"HOOK_POST_VALIDATION",
+		"HOOK_ON_EXIT",
+		"HOOK_TIMEOUT",
+		"EXTERNAL_VALIDATORS",
+		"PROFILE",
+		"VAL_AI",
+		"SUMMARY_AI",
+		"VAL_ROTATE",
+		"AUTO_CROSS_VALIDATE",
+		"EVIDENCE_PATTERNS",
+		"ALLOWED_TOOLS",
+		"DENIED_COMMANDS",
+		"SHUTDOWN_GRACE",
+		"RESPONSE_CACHE",
+		"CACHE_TTL",
+		"NOTIFY_EVENTS",
+		"NOTIFY_DIGEST",
+		"HEARTBEAT_INTERVAL",
+		"HEARTBEAT_ITERATIONS",
+		"HANG_TIMEOUT",
+		"FALLBACK_AI",
+		"FAILOVER_AFTER",
+		"SPEC_DRIFT",
+		"ANNOTATE_TASKS",
+		"TRIAGE",
+		"TRIAGE_AI",
+		"TRIAGE_MODEL",
+		"VERIFY_CMDS",
+		"VERIFY_REPORTS",
+		"PROJECT_MEMORY",
+		"STATE_KEY",
+		"STATE_DIR",
 	}
 
 	// Convert array to slice for comparison.
@@ -114,3 +289,115 @@ func TestWhitelistedVarsHasNoDuplicates(t *testing.T) {
 		seen[v] = true
 	}
 }
+
+func TestMergeCLIOnly(t *testing.T) {
+	flags := config.NewDefaultConfig()
+	flags.TasksFile = "tasks.md"
+	flags.TasksFiles = []string{"tasks.md", "more.md"}
+	flags.StartAt = "validation"
+	flags.Resume = true
+	flags.RevalidatePlan = true
+	flags.Record = "rec"
+	flags.Replay = "rep"
+	flags.MaxIterations = 99
+
+	cfg := config.NewDefaultConfig()
+	cfg.MergeCLIOnly(flags)
+
+	assert.Equal(t, "tasks.md", cfg.TasksFile)
+	assert.Equal(t, []string{"tasks.md", "more.md"}, cfg.TasksFiles)
+	assert.Equal(t, "validation", cfg.StartAt)
+	assert.True(t, cfg.Resume)
+	assert.True(t, cfg.RevalidatePlan)
+	assert.Equal(t, "rec", cfg.Record)
+	assert.Equal(t, "rep", cfg.Replay)
+	// Settings a config file can give are left alone.
+	assert.Equal(t, config.NewDefaultConfig().MaxIterations, cfg.MaxIterations)
+}
+
+func TestModelLadder(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	assert.Nil(t, cfg.ModelLadder())
+	assert.Equal(t, 2, cfg.EscalateAfter)
+
+	cfg.ImplModelLadder = " haiku, sonnet,,opus "
+	assert.Equal(t, []string{"haiku", "sonnet", "opus"}, cfg.ModelLadder())
+}
+
+func TestValidatorSpecs(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	assert.Equal(t, 1, cfg.Validators)
+	assert.Equal(t, []config.ValidatorSpec{{AI: "claude", Model: "opus"}}, cfg.ValidatorSpecs())
+
+	cfg.Validators = 3
+	cfg.ValidatorPool = "claude:sonnet, codex"
+	assert.Equal(t, []config.ValidatorSpec{
+		{AI: "claude", Model: "sonnet"},
+		{AI: "codex", Model: ""},
+		{AI: "claude", Model: "sonnet"},
+	}, cfg.ValidatorSpecs())
+
+	// Without a pool, validators run on the validation AI.
+	cfg.ValidatorPool = ""
+	cfg.ValAI = "codex"
+	cfg.ValModel = "gpt-5"
+	assert.Equal(t, []config.ValidatorSpec{{AI: "codex", Model: "gpt-5"}, {AI: "codex", Model: "gpt-5"}, {AI: "codex", Model: "gpt-5"}}, cfg.ValidatorSpecs())
+}
+
+func TestValidatorPoolSpecs(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	assert.Empty(t, cfg.ValidatorPoolSpecs())
+
+	cfg.Validators = 5
+	cfg.ValidatorPool = "claude:sonnet, codex:gpt-5"
+	assert.Equal(t, []config.ValidatorSpec{{AI: "claude", Model: "sonnet"}, {AI: "codex", Model: "gpt-5"}}, cfg.ValidatorPoolSpecs())
+}
+
+func TestProtectedBranchList(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	assert.Equal(t, []string{"main", "master"}, cfg.ProtectedBranchList())
+
+	cfg.ProtectedBranches = " main, ,release "
+	assert.Equal(t, []string{"main", "release"}, cfg.ProtectedBranchList())
+
+	cfg.ProtectedBranches = ""
+	assert.Empty(t, cfg.ProtectedBranchList())
+}
+
+func TestEvidencePatternList(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	assert.Equal(t, []string{"Deploy", "Run tests", "Verify"}, cfg.EvidencePatternList())
+
+	cfg.EvidencePatterns = " Migrate, ,Smoke test "
+	assert.Equal(t, []string{"Migrate", "Smoke test"}, cfg.EvidencePatternList())
+
+	cfg.EvidencePatterns = ""
+	assert.Empty(t, cfg.EvidencePatternList())
+}
+
+func TestToolPermissionLists(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	assert.Empty(t, cfg.AllowedToolList())
+	assert.Empty(t, cfg.DeniedCommandList())
+
+	cfg.AllowedTools = "Edit, ,Bash(go test:*)"
+	cfg.DeniedCommands = " rm ,git push"
+	assert.Equal(t, []string{"Edit", "Bash(go test:*)"}, cfg.AllowedToolList())
+	assert.Equal(t, []string{"rm", "git push"}, cfg.DeniedCommandList())
+}
+
+func TestK8sJobSecretNames(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	assert.Empty(t, cfg.K8sJobSecretNames())
+
+	cfg.K8sJobSecrets = "anthropic-key, ,openai-key"
+	assert.Equal(t, []string{"anthropic-key", "openai-key"}, cfg.K8sJobSecretNames())
+}
+
+func TestSandboxEnvVars(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	assert.Empty(t, cfg.SandboxEnvVars())
+
+	cfg.SandboxEnv = "ANTHROPIC_API_KEY, ,OPENAI_API_KEY"
+	assert.Equal(t, []string{"ANTHROPIC_API_KEY", "OPENAI_API_KEY"}, cfg.SandboxEnvVars())
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/secrets"
+)
+
+// keyringPrefix marks a reference to a keyring secret: ${keyring:NAME}.
+const keyringPrefix = "keyring:"
+
+// expandValue replaces the ${NAME} and ${keyring:NAME} references of a
+// config file value (see Resolve). secret reports that a reference read
+// the keyring or an environment variable whose name marks a secret.
+func expandValue(value string) (expanded string, secret bool, err error) {
+	if !strings.Contains(value, "${") {
+		return value, false, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); {
+		rest := value[i:]
+		switch {
+		case strings.HasPrefix(rest, "$${"):
+			b.WriteString("${")
+			i += 3
+		case strings.HasPrefix(rest, "${"):
+			end := strings.IndexByte(rest, '}')
+			if end < 0 {
+				return "", false, fmt.Errorf("unclosed ${ in value")
+			}
+			ref := rest[2:end]
+			v, isSecret, err := lookupRef(ref)
+			if err != nil {
+				return "", false, err
+			}
+			b.WriteString(v)
+			secret = secret || isSecret
+			i += end + 1
+		default:
+			b.WriteByte(value[i])
+			i++
+		}
+	}
+	return b.String(), secret, nil
+}
+
+// lookupRef returns the value of one ${...} reference.
+func lookupRef(ref string) (string, bool, error) {
+	if name, ok := strings.CutPrefix(ref, keyringPrefix); ok {
+		if name == "" {
+			return "", false, fmt.Errorf("${keyring:} names no secret")
+		}
+		v, err := secrets.Lookup(name)
+		if err != nil {
+			return "", false, fmt.Errorf("keyring secret %s: %w", name, err)
+		}
+		return v, true, nil
+	}
+	if ref == "" {
+		return "", false, fmt.Errorf("${} names no environment variable")
+	}
+	v, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", false, fmt.Errorf("environment variable %s is not set", ref)
+	}
+	return v, secrets.IsSecretKey(ref), nil
+}
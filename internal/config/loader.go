@@ -5,8 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/secrets"
 )
 
 // whitelistSet is a precomputed lookup table for fast whitelist membership checks.
@@ -26,17 +30,35 @@ func init() {
 //   - Lines without an = sign are skipped.
 //   - Leading and trailing whitespace is trimmed from both key and value.
 //   - Keys not present in WhitelistedVars are silently ignored.
+//   - Lines under a [profile.NAME] or other section header are not
+//     returned; see ParseFile.
 //
 // Returns a map of whitelisted key-value pairs, or an error if the file
 // cannot be opened.
 func LoadFile(path string) (map[string]string, error) {
+	settings, _, err := ParseFile(path)
+	return settings, err
+}
+
+// profileSection is the section header prefix of a named profile.
+const profileSection = "profile."
+
+// ParseFile parses a config file like LoadFile, also returning the
+// settings of each [profile.NAME] section, keyed by NAME. A profile
+// section runs until the next section header; other sections are
+// ignored. PROFILE is not honoured inside a profile.
+func ParseFile(path string) (settings map[string]string, profiles map[string]map[string]string, err error) {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("open config file: %w", err)
+		return nil, nil, fmt.Errorf("open config file: %w", err)
 	}
 	defer f.Close()
 
-	result := make(map[string]string)
+	settings = make(map[string]string)
+	profiles = make(map[string]map[string]string)
+	// current receives the lines of the section being read; nil skips them.
+	current := settings
+	inProfile := false
 	scanner := bufio.NewScanner(f)
 
 	for scanner.Scan() {
@@ -47,6 +69,23 @@ func LoadFile(path string) (map[string]string, error) {
 			continue
 		}
 
+		// Section headers switch where the following lines go.
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current, inProfile = nil, false
+			section := strings.TrimSpace(line[1 : len(line)-1])
+			if name, ok := strings.CutPrefix(section, profileSection); ok && strings.TrimSpace(name) != "" {
+				name = strings.TrimSpace(name)
+				if profiles[name] == nil {
+					profiles[name] = make(map[string]string)
+				}
+				current, inProfile = profiles[name], true
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
 		// Split on first '=' only.
 		idx := strings.Index(line, "=")
 		if idx < 0 {
@@ -60,79 +99,283 @@ func LoadFile(path string) (map[string]string, error) {
 		if !whitelistSet[key] {
 			continue
 		}
+		if key == "PROFILE" && inProfile {
+			continue
+		}
 
-		result[key] = value
+		current[key] = value
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("read config file: %w", err)
+		return nil, nil, fmt.Errorf("read config file: %w", err)
 	}
 
-	return result, nil
+	return settings, profiles, nil
+}
+
+// EnvPrefix is the prefix that maps environment variables onto config keys:
+// RALPH_MAX_ITERATIONS sets MAX_ITERATIONS.
+const EnvPrefix = "RALPH_"
+
+// LoadEnv extracts config values from environment entries in "KEY=VALUE"
+// form (as returned by os.Environ). Only variables named EnvPrefix followed
+// by a whitelisted key are returned, keyed without the prefix. Values are
+// trimmed; empty values are ignored so an unset-but-exported variable does
+// not clobber file-based settings.
+func LoadEnv(environ []string) map[string]string {
+	result := make(map[string]string)
+	for _, entry := range environ {
+		idx := strings.Index(entry, "=")
+		if idx < 0 {
+			continue
+		}
+		name := entry[:idx]
+		if !strings.HasPrefix(name, EnvPrefix) {
+			continue
+		}
+		key := strings.TrimPrefix(name, EnvPrefix)
+		value := strings.TrimSpace(entry[idx+1:])
+		if !whitelistSet[key] || value == "" {
+			continue
+		}
+		result[key] = value
+	}
+	return result
 }
 
-// LoadWithPrecedence assembles a Config by merging sources in order of
-// increasing priority:
+// LoadWithPrecedence assembles a Config from the built-in defaults and the
+// settings Resolve finds, and records the values of the secret ones in
+// Secrets.
+func LoadWithPrecedence(globalPath, projectPath, explicitPath string, cliOverrides map[string]string) (*Config, error) {
+	settings, err := Resolve(globalPath, projectPath, explicitPath, cliOverrides)
+	if err != nil {
+		return nil, err
+	}
+	cfg := NewDefaultConfig()
+	m := make(map[string]string, len(settings))
+	for _, s := range settings {
+		m[s.Key] = s.Value
+		if s.Secret {
+			cfg.Secrets = append(cfg.Secrets, s.Value)
+		}
+	}
+	ApplyMapToConfig(cfg, m)
+	return cfg, nil
+}
+
+// Setting is the value a setting resolved to and where it came from.
+type Setting struct {
+	Key   string
+	Value string
+	// Source is the config file, "profile NAME", "environment" or
+	// "command line" the value came from.
+	Source string
+	// Secret is set for the settings whose name marks a secret, such as
+	// NOTIFY_WEBHOOK, and those that took a value from the keyring or from
+	// an environment variable whose name marks a secret.
+	Secret bool
+}
+
+// Shown returns the value to print: secrets.Redacted for a secret.
+func (s Setting) Shown() string {
+	if s.Secret && s.Value != "" {
+		return secrets.Redacted
+	}
+	return s.Value
+}
+
+// Resolve merges the settings of these sources, in order of increasing
+// priority:
+//
+//  1. Global config file (globalPath)
+//  2. Project config file (projectPath)
+//  3. Explicit config file (explicitPath)
+//  4. The selected profile (see below)
+//  5. RALPH_* environment variables (see LoadEnv)
+//  6. CLI overrides (cliOverrides map)
 //
-//  1. Built-in defaults
-//  2. Global config file (globalPath)
-//  3. Project config file (projectPath)
-//  4. Explicit config file (explicitPath)
-//  5. CLI overrides (cliOverrides map)
+// The profile is the one named by PROFILE, taken from the CLI overrides,
+// the environment or the files, in that order. Its settings are those of
+// its [profile.NAME] sections, later files overriding earlier ones; an
+// unknown name is an error.
+//
+// In the config files, ${NAME} in a value is replaced by the environment
+// variable NAME and ${keyring:NAME} by the secret NAME of the OS keyring
+// (see secrets.Lookup); $${ is a literal ${.
 //
 // Any path that is empty is silently skipped. If a non-empty path cannot be
-// loaded, an error is returned.
-func LoadWithPrecedence(globalPath, projectPath, explicitPath string, cliOverrides map[string]string) (*Config, error) {
-	cfg := NewDefaultConfig()
+// loaded, an error is returned. So is a value a setting does not accept,
+// such as MAX_ITERATIONS=0 or an unknown AI_CLI, or that cannot be
+// expanded: the error lists every one of them, with where it was set. The
+// settings are returned in WhitelistedVars order.
+func Resolve(globalPath, projectPath, explicitPath string, cliOverrides map[string]string) ([]Setting, error) {
+	resolved := make(map[string]Setting)
+	profiles := make(map[string]map[string]string)
+	var problems []string
+	// set records the values of m, from source, expanding them when they
+	// come from a config file. prefix is how the keys are named there.
+	set := func(source string, m map[string]string, expand bool, prefix string) {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			s := Setting{Key: key, Value: m[key], Source: source, Secret: secrets.IsSecretKey(key)}
+			if expand {
+				value, secret, err := expandValue(s.Value)
+				if err != nil {
+					problems = append(problems, fmt.Sprintf("%s: %s%s: %v", source, prefix, key, err))
+					continue
+				}
+				s.Value, s.Secret = value, s.Secret || secret
+			}
+			if problem := checkValue(key, s.Value); problem != "" {
+				shown := strconv.Quote(s.Value)
+				if s.Secret {
+					shown = secrets.Redacted
+				}
+				problems = append(problems, fmt.Sprintf("%s: %s%s=%s: %s", source, prefix, key, shown, problem))
+				continue
+			}
+			resolved[key] = s
+		}
+	}
+	file := func(path string, m map[string]string, p map[string]map[string]string) {
+		set(path, m, true, "")
+		for name, settings := range p {
+			if profiles[name] == nil {
+				profiles[name] = make(map[string]string)
+			}
+			for k, v := range settings {
+				profiles[name][k] = v
+			}
+		}
+	}
 
-	// Layer 2: global config file.
+	// Layer 1: global config file.
 	if globalPath != "" {
-		m, err := LoadFile(globalPath)
+		m, p, err := ParseFile(globalPath)
 		if err != nil {
 			if !errors.Is(err, os.ErrNotExist) {
 				return nil, fmt.Errorf("global config: %w", err)
 			}
 			// Missing global config is not an error.
 		} else {
-			ApplyMapToConfig(cfg, m)
+			file(globalPath, m, p)
 		}
 	}
 
-	// Layer 3: project config file.
+	// Layer 2: project config file.
 	if projectPath != "" {
-		m, err := LoadFile(projectPath)
+		m, p, err := ParseFile(projectPath)
 		if err != nil {
 			if !errors.Is(err, os.ErrNotExist) {
 				return nil, fmt.Errorf("project config: %w", err)
 			}
 		} else {
-			ApplyMapToConfig(cfg, m)
+			file(projectPath, m, p)
 		}
 	}
 
-	// Layer 4: explicit config file (must exist if specified).
+	// Layer 3: explicit config file (must exist if specified).
 	if explicitPath != "" {
-		m, err := LoadFile(explicitPath)
+		m, p, err := ParseFile(explicitPath)
 		if err != nil {
 			return nil, fmt.Errorf("explicit config: %w", err)
 		}
-		ApplyMapToConfig(cfg, m)
+		file(explicitPath, m, p)
 	}
 
-	// Layer 5: CLI overrides (highest priority).
-	if len(cliOverrides) > 0 {
-		ApplyMapToConfig(cfg, cliOverrides)
+	// Layer 4: the selected profile.
+	env := LoadEnv(os.Environ())
+	name := resolved["PROFILE"].Value
+	if v := env["PROFILE"]; v != "" {
+		name = v
+	}
+	if v := cliOverrides["PROFILE"]; v != "" {
+		name = v
+	}
+	if name != "" {
+		settings, ok := profiles[name]
+		if !ok {
+			return nil, unknownProfileError(name, profiles)
+		}
+		set("profile "+name, settings, true, "")
 	}
 
-	return cfg, nil
+	// Layer 5: environment variables.
+	set("environment", env, false, EnvPrefix)
+
+	// Layer 6: CLI overrides (highest priority).
+	set("command line", cliOverrides, false, "")
+
+	if len(problems) > 0 {
+		return nil, invalidConfigError(problems)
+	}
+	settings := make([]Setting, 0, len(resolved))
+	for _, key := range WhitelistedVars {
+		if s, ok := resolved[key]; ok {
+			settings = append(settings, s)
+			delete(resolved, key)
+		}
+	}
+	rest := make([]string, 0, len(resolved))
+	for key := range resolved {
+		rest = append(rest, key)
+	}
+	sort.Strings(rest)
+	for _, key := range rest {
+		settings = append(settings, resolved[key])
+	}
+	return settings, nil
+}
+
+// unknownProfileError reports a profile that no config file defines.
+func unknownProfileError(name string, profiles map[string]map[string]string) error {
+	if len(profiles) == 0 {
+		return fmt.Errorf("unknown profile %q: no config file defines a [profile.NAME] section", name)
+	}
+	return fmt.Errorf("unknown profile %q (defined: %s)", name, strings.Join(sortedKeys(profiles), ", "))
+}
+
+// ProfileNames returns the profiles defined in the config files at paths,
+// sorted. Missing or unreadable files are skipped.
+func ProfileNames(paths ...string) []string {
+	profiles := make(map[string]map[string]string)
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if _, p, err := ParseFile(path); err == nil {
+			for name, settings := range p {
+				profiles[name] = settings
+			}
+		}
+	}
+	return sortedKeys(profiles)
+}
+
+func sortedKeys(m map[string]map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // ApplyMapToConfig sets fields on cfg from the key-value pairs in m.
 // Keys must use the WhitelistedVars naming convention (e.g., "AI_CLI").
 // Unknown keys are silently ignored. Integer fields that fail to parse
-// are silently ignored (the previous value is preserved).
+// are silently ignored (the previous value is preserved), as are
+// <PHASE>_TEMPERATURE keys and unknown reasoning efforts;
+// LoadWithPrecedence rejects them before they get here.
 func ApplyMapToConfig(cfg *Config, m map[string]string) {
 	for key, value := range m {
+		if applySampling(cfg, key, value) {
+			continue
+		}
 		switch key {
 		case "AI_CLI":
 			cfg.AIProvider = value
@@ -140,12 +383,32 @@ func ApplyMapToConfig(cfg *Config, m map[string]string) {
 			cfg.ImplModel = value
 		case "VAL_MODEL":
 			cfg.ValModel = value
+		case "IMPL_MODEL_LADDER":
+			cfg.ImplModelLadder = value
+		case "VALIDATORS":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.Validators = v
+			}
+		case "VALIDATOR_POOL":
+			cfg.ValidatorPool = value
+		case "VAL_ROTATE":
+			cfg.ValRotate = parseBool(value)
+		case "ESCALATE_AFTER":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.EscalateAfter = v
+			}
 		case "CROSS_VALIDATE":
 			cfg.CrossValidate = parseBool(value)
 		case "CROSS_AI":
 			cfg.CrossAI = value
 		case "CROSS_MODEL":
 			cfg.CrossModel = value
+		case "FALLBACK_AI":
+			cfg.FallbackAI = value
+		case "FAILOVER_AFTER":
+			if v, err := strconv.Atoi(value); err == nil && v > 0 {
+				cfg.FailoverAfter = v
+			}
 		case "FINAL_PLAN_AI":
 			cfg.FinalPlanAI = value
 		case "FINAL_PLAN_MODEL":
@@ -154,6 +417,18 @@ func ApplyMapToConfig(cfg *Config, m map[string]string) {
 			cfg.TasksValAI = value
 		case "TASKS_VAL_MODEL":
 			cfg.TasksValModel = value
+		case "SUMMARY_MODEL":
+			cfg.SummaryModel = value
+		case "VAL_AI":
+			cfg.ValAI = value
+		case "SUMMARY_AI":
+			cfg.SummaryAI = value
+		case "TRIAGE":
+			cfg.Triage = parseBool(value)
+		case "TRIAGE_AI":
+			cfg.TriageAI = value
+		case "TRIAGE_MODEL":
+			cfg.TriageModel = value
 		case "MAX_ITERATIONS":
 			if v, err := strconv.Atoi(value); err == nil {
 				cfg.MaxIterations = v
@@ -162,6 +437,38 @@ func ApplyMapToConfig(cfg *Config, m map[string]string) {
 			if v, err := strconv.Atoi(value); err == nil {
 				cfg.MaxInadmissible = v
 			}
+		case "MAX_TASK_ATTEMPTS":
+			if v, err := strconv.Atoi(value); err == nil {
+				cfg.MaxTaskAttempts = v
+			}
+		case "MAX_FORMAT_RETRIES":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.MaxFormatRetries = v
+			}
+		case "LOG_MAX_MB":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.LogMaxMB = v
+			}
+		case "LOG_GZIP":
+			cfg.LogGzip = parseBool(value)
+		case "OUTPUT_GZIP_KB":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.OutputGzipKB = v
+			}
+		case "DEDUP_OUTPUTS":
+			cfg.DedupOutputs = parseBool(value)
+		case "RETAIN_ITERATIONS":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.RetainIterations = v
+			}
+		case "RETAIN_MB":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.RetainMB = v
+			}
+		case "STATE_KEY":
+			cfg.StateKey = value
+		case "STATE_DIR":
+			cfg.StateDir = value
 		case "MAX_CLAUDE_RETRY":
 			if v, err := strconv.Atoi(value); err == nil {
 				cfg.MaxClaudeRetry = v
@@ -174,18 +481,182 @@ func ApplyMapToConfig(cfg *Config, m map[string]string) {
 			if v, err := strconv.Atoi(value); err == nil {
 				cfg.InactivityTimeout = v
 			}
+		case "HANG_TIMEOUT":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.HangTimeout = v
+			}
+		case "ITERATION_TIMEOUT":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.IterationTimeout = v
+			}
+		case "IMPL_TIMEOUT":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.ImplTimeout = v
+			}
+		case "VAL_TIMEOUT":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.ValTimeout = v
+			}
 		case "LEARNINGS_FILE":
 			cfg.LearningsFile = value
 		case "ENABLE_LEARNINGS":
 			cfg.EnableLearnings = parseBool(value)
+		case "GLOBAL_LEARNINGS":
+			cfg.GlobalLearnings = parseBool(value)
+		case "PROJECT_MEMORY":
+			cfg.ProjectMemory = parseBool(value)
+		case "LEARNINGS_TAGS":
+			cfg.LearningsTags = value
+		case "LEARNINGS_MAX_TOKENS":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.LearningsMaxTokens = v
+			}
+		case "CONTEXT_MAX_TOKENS":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.ContextMaxTokens = v
+			}
+		case "VAL_DIFF_MAX_TOKENS":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.ValDiffMaxTokens = v
+			}
+		case "IMPL_OUTPUT_MAX_TOKENS":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.ImplOutputMaxTokens = v
+			}
 		case "VERBOSE":
 			cfg.Verbose = parseBool(value)
+		case "APPLY_PATCH":
+			cfg.ApplyPatch = parseBool(value)
 		case "NOTIFY_WEBHOOK":
 			cfg.NotifyWebhook = value
 		case "NOTIFY_CHANNEL":
 			cfg.NotifyChannel = value
 		case "NOTIFY_CHAT_ID":
 			cfg.NotifyChatID = value
+		case "NOTIFY_EVENTS":
+			cfg.NotifyEvents = value
+		case "NOTIFY_DIGEST":
+			if v, err := time.ParseDuration(value); err == nil && v >= 0 {
+				cfg.NotifyDigest = v
+			}
+		case "HEARTBEAT_INTERVAL":
+			if v, err := time.ParseDuration(value); err == nil && v >= 0 {
+				cfg.HeartbeatInterval = v
+			}
+		case "HEARTBEAT_ITERATIONS":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.HeartbeatIterations = v
+			}
+		case "PR_COMMENT":
+			cfg.PRComment = parseBool(value)
+		case "PR_NUMBER":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.PRNumber = v
+			}
+		case "COVERAGE_FILE":
+			cfg.CoverageFile = value
+		case "GITHUB_CHECK":
+			cfg.GitHubCheck = parseBool(value)
+		case "SANDBOX_CMD":
+			cfg.SandboxCmd = value
+		case "SANDBOX_ENV":
+			cfg.SandboxEnv = value
+		case "CONTAINER_IMAGE":
+			cfg.ContainerImage = value
+		case "CONTAINER_RUNTIME":
+			cfg.ContainerRuntime = value
+		case "CONTAINER_PULL":
+			cfg.ContainerPull = value
+		case "K8S_JOB_IMAGE":
+			cfg.K8sJobImage = value
+		case "K8S_JOB_NAMESPACE":
+			cfg.K8sJobNamespace = value
+		case "K8S_JOB_SECRETS":
+			cfg.K8sJobSecrets = value
+		case "K8S_JOB_PVC":
+			cfg.K8sJobPVC = value
+		case "REMOTE_AGENT":
+			cfg.RemoteAgent = value
+		case "SPEC_DRIFT":
+			cfg.SpecDrift = value
+		case "ALLOWED_TOOLS":
+			cfg.AllowedTools = value
+		case "DENIED_COMMANDS":
+			cfg.DeniedCommands = value
+		case "METRICS_ADDR":
+			cfg.MetricsAddr = value
+		case "METRICS_FILE":
+			cfg.MetricsFile = value
+		case "TUI":
+			cfg.TUI = parseBool(value)
+		case "SERVE":
+			cfg.Serve = value
+		case "ISSUE_PROVIDER":
+			cfg.IssueProvider = value
+		case "PAUSE_BETWEEN":
+			cfg.PauseBetween = value
+		case "MAX_DURATION":
+			if v, err := time.ParseDuration(value); err == nil && v >= 0 {
+				cfg.MaxDuration = v
+			}
+		case "SHUTDOWN_GRACE":
+			if v, err := time.ParseDuration(value); err == nil && v >= 0 {
+				cfg.ShutdownGrace = v
+			}
+		case "RESPONSE_CACHE":
+			cfg.ResponseCache = parseBool(value)
+		case "CACHE_TTL":
+			if v, err := time.ParseDuration(value); err == nil && v >= 0 {
+				cfg.CacheTTL = v
+			}
+		case "SCREENSHOT_THRESHOLD":
+			if v, err := strconv.ParseFloat(value, 64); err == nil && v >= 0 && v <= 100 {
+				cfg.ScreenshotThreshold = v
+			}
+		case "MIN_CONFIDENCE":
+			if v, err := strconv.ParseFloat(value, 64); err == nil && v >= 0 && v <= 1 {
+				cfg.MinConfidence = v
+			}
+		case "AUTO_CROSS_VALIDATE":
+			cfg.AutoCrossValidate = value
+		case "CUSTOM_VERDICTS":
+			cfg.CustomVerdicts = value
+		case "EXTERNAL_VALIDATORS":
+			cfg.ExternalValidators = value
+		case "VERIFY_CMDS":
+			cfg.VerifyCmds = value
+		case "VERIFY_REPORTS":
+			cfg.VerifyReports = value
+		case "POLICY_FILE":
+			cfg.PolicyFile = value
+		case "EVIDENCE_PATTERNS":
+			cfg.EvidencePatterns = value
+		case "ANNOTATE_TASKS":
+			cfg.AnnotateTasks = parseBool(value)
+		case "PROTECTED_PATHS":
+			cfg.ProtectedPaths = value
+		case "PROTECTED_PATHS_ACTION":
+			if value == ProtectedRevert || value == ProtectedEscalate {
+				cfg.ProtectedPathsAction = value
+			}
+		case "ALLOW_DIRTY":
+			cfg.AllowDirty = parseBool(value)
+		case "PROTECTED_BRANCHES":
+			cfg.ProtectedBranches = value
+		case "HOOK_PRE_ITERATION":
+			cfg.HookPreIteration = value
+		case "HOOK_POST_IMPLEMENTATION":
+			cfg.HookPostImplementation = value
+		case "HOOK_POST_VALIDATION":
+			cfg.HookPostValidation = value
+		case "HOOK_ON_EXIT":
+			cfg.HookOnExit = value
+		case "HOOK_TIMEOUT":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				cfg.HookTimeout = v
+			}
+		case "PROFILE":
+			cfg.Profile = value
 		}
 	}
 }
@@ -200,3 +671,43 @@ func parseBool(s string) bool {
 		return false
 	}
 }
+
+// samplingPhases maps each phase key prefix to its sampling settings.
+var samplingPhases = map[string]func(*Config) *Sampling{
+	"IMPL":       func(c *Config) *Sampling { return &c.ImplSampling },
+	"VAL":        func(c *Config) *Sampling { return &c.ValSampling },
+	"CROSS":      func(c *Config) *Sampling { return &c.CrossSampling },
+	"FINAL_PLAN": func(c *Config) *Sampling { return &c.FinalPlanSampling },
+	"TASKS_VAL":  func(c *Config) *Sampling { return &c.TasksValSampling },
+}
+
+// applySampling handles <PHASE>_TEMPERATURE and <PHASE>_REASONING_EFFORT
+// keys. It reports whether key was a sampling key, even if the value was
+// rejected; temperatures always are.
+func applySampling(cfg *Config, key, value string) bool {
+	var phase, param string
+	switch {
+	case strings.HasSuffix(key, "_TEMPERATURE"):
+		phase, param = strings.TrimSuffix(key, "_TEMPERATURE"), "temperature"
+	case strings.HasSuffix(key, "_REASONING_EFFORT"):
+		phase, param = strings.TrimSuffix(key, "_REASONING_EFFORT"), "effort"
+	default:
+		return false
+	}
+	field, ok := samplingPhases[phase]
+	if !ok {
+		return false
+	}
+	s := field(cfg)
+
+	if param == "temperature" {
+		return true
+	}
+	effort := strings.ToLower(value)
+	for _, e := range ReasoningEfforts {
+		if effort == e {
+			s.ReasoningEffort = effort
+		}
+	}
+	return true
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -108,6 +109,34 @@ func TestLoadFileReturnsErrorForMissingFile(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestParseFileSplitsProfileSections(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "config", `AI_CLI=claude
+PROFILE=cheap
+
+[profile.cheap]
+IMPL_MODEL=sonnet
+PROFILE=thorough
+[ profile.thorough ]
+VALIDATORS=3
+[other]
+MAX_ITERATIONS=99
+`)
+
+	settings, profiles, err := config.ParseFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"AI_CLI": "claude", "PROFILE": "cheap"}, settings)
+	assert.Equal(t, map[string]map[string]string{
+		"cheap":    {"IMPL_MODEL": "sonnet"},
+		"thorough": {"VALIDATORS": "3"},
+	}, profiles)
+
+	m, err := config.LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, settings, m)
+}
+
 // ---------------------------------------------------------------------------
 // Precedence tests
 // ---------------------------------------------------------------------------
@@ -186,6 +215,39 @@ func TestLoadWithPrecedenceCLIOverridesAll(t *testing.T) {
 	assert.True(t, cfg.Verbose)
 }
 
+func TestLoadEnvFiltersPrefixAndWhitelist(t *testing.T) {
+	m := config.LoadEnv([]string{
+		"RALPH_MAX_ITERATIONS=30",
+		"RALPH_AI_CLI= codex ",
+		"RALPH_UNKNOWN=x",
+		"RALPH_VERBOSE=",
+		"MAX_TURNS=7",
+		"PATH=/usr/bin",
+		"RALPH_TASKS_FILE=tasks.md",
+		"malformed",
+	})
+
+	assert.Equal(t, map[string]string{
+		"MAX_ITERATIONS": "30",
+		"AI_CLI":         "codex",
+	}, m)
+}
+
+func TestLoadWithPrecedenceEnvOverridesFiles(t *testing.T) {
+	dir := t.TempDir()
+	explicitPath := writeFile(t, dir, "explicit", "MAX_ITERATIONS=10\nAI_CLI=claude\n")
+	t.Setenv("RALPH_MAX_ITERATIONS", "40")
+	t.Setenv("RALPH_AI_CLI", "codex")
+
+	cfg, err := config.LoadWithPrecedence("", "", explicitPath, map[string]string{"AI_CLI": "claude"})
+	require.NoError(t, err)
+
+	// Environment wins over the config file.
+	assert.Equal(t, 40, cfg.MaxIterations)
+	// CLI wins over the environment.
+	assert.Equal(t, "claude", cfg.AIProvider)
+}
+
 func TestLoadWithPrecedenceFullChain(t *testing.T) {
 	dir := t.TempDir()
 
@@ -210,6 +272,62 @@ func TestLoadWithPrecedenceFullChain(t *testing.T) {
 	assert.True(t, cfg.Verbose)
 }
 
+func TestLoadWithPrecedenceProfile(t *testing.T) {
+	dir := t.TempDir()
+	globalPath := writeFile(t, dir, "global", "MAX_ITERATIONS=20\n[profile.cheap]\nIMPL_MODEL=sonnet\nMAX_ITERATIONS=5\n")
+	projectPath := writeFile(t, dir, "project", "IMPL_MODEL=opus\n[profile.cheap]\nVAL_MODEL=haiku\n")
+
+	cfg, err := config.LoadWithPrecedence(globalPath, projectPath, "", map[string]string{"PROFILE": "cheap", "MAX_ITERATIONS": "7"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "cheap", cfg.Profile)
+	// The profile overrides the files, the same profile in later files adds to it.
+	assert.Equal(t, "sonnet", cfg.ImplModel)
+	assert.Equal(t, "haiku", cfg.ValModel)
+	// CLI flags override the profile.
+	assert.Equal(t, 7, cfg.MaxIterations)
+}
+
+func TestLoadWithPrecedenceProfileFromFileAndEnv(t *testing.T) {
+	dir := t.TempDir()
+	projectPath := writeFile(t, dir, "project", "PROFILE=cheap\n[profile.cheap]\nVALIDATORS=1\n[profile.thorough]\nVALIDATORS=3\nMAX_ITERATIONS=40\n")
+
+	cfg, err := config.LoadWithPrecedence("", projectPath, "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cfg.Validators)
+
+	t.Setenv("RALPH_PROFILE", "thorough")
+	t.Setenv("RALPH_MAX_ITERATIONS", "50")
+	cfg, err = config.LoadWithPrecedence("", projectPath, "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "thorough", cfg.Profile)
+	assert.Equal(t, 3, cfg.Validators)
+	// The environment overrides the profile.
+	assert.Equal(t, 50, cfg.MaxIterations)
+}
+
+func TestLoadWithPrecedenceUnknownProfile(t *testing.T) {
+	dir := t.TempDir()
+	projectPath := writeFile(t, dir, "project", "[profile.thorough]\nVALIDATORS=3\n[profile.cheap]\nVALIDATORS=1\n")
+
+	_, err := config.LoadWithPrecedence("", projectPath, "", map[string]string{"PROFILE": "fast"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown profile "fast" (defined: cheap, thorough)`)
+
+	_, err = config.LoadWithPrecedence("", "", "", map[string]string{"PROFILE": "fast"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no config file defines")
+}
+
+func TestProfileNames(t *testing.T) {
+	dir := t.TempDir()
+	globalPath := writeFile(t, dir, "global", "[profile.thorough]\nVALIDATORS=3\n")
+	projectPath := writeFile(t, dir, "project", "[profile.cheap]\nVALIDATORS=1\n[profile.thorough]\nVALIDATORS=5\n")
+
+	assert.Equal(t, []string{"cheap", "thorough"}, config.ProfileNames(globalPath, "", filepath.Join(dir, "missing"), projectPath))
+	assert.Empty(t, config.ProfileNames())
+}
+
 func TestLoadWithPrecedenceMissingGlobalIsNotError(t *testing.T) {
 	cfg, err := config.LoadWithPrecedence("/nonexistent/global/config", "", "", nil)
 	require.NoError(t, err)
@@ -271,19 +389,69 @@ func TestLoadWithPrecedenceInvalidProjectPath(t *testing.T) {
 func TestApplyMapToConfigSetsAllStringFields(t *testing.T) {
 	cfg := config.NewDefaultConfig()
 	m := map[string]string{
-		"AI_CLI":           "codex",
-		"IMPL_MODEL":       "gpt-4",
-		"VAL_MODEL":        "gpt-3.5",
-		"CROSS_AI":         "claude",
-		"CROSS_MODEL":      "sonnet",
-		"FINAL_PLAN_AI":    "codex",
-		"FINAL_PLAN_MODEL": "gpt-4",
-		"TASKS_VAL_AI":     "claude",
-		"TASKS_VAL_MODEL":  "opus",
-		"LEARNINGS_FILE":   "/tmp/learnings.md",
-		"NOTIFY_WEBHOOK":   "https://example.com/hook",
-		"NOTIFY_CHANNEL":   "slack",
-		"NOTIFY_CHAT_ID":   "99999",
+		"AI_CLI":                 "codex",
+		"IMPL_MODEL":             "gpt-4",
+		"VAL_MODEL":              "gpt-3.5",
+		"CROSS_AI":               "claude",
+		"CROSS_MODEL":            "sonnet",
+		"FALLBACK_AI":            "codex",
+		"FINAL_PLAN_AI":          "codex",
+		"FINAL_PLAN_MODEL":       "gpt-4",
+		"TASKS_VAL_AI":           "claude",
+		"TASKS_VAL_MODEL":        "opus",
+		"SUMMARY_MODEL":          "haiku",
+		"VAL_AI":                 "copilot",
+		"VAL_ROTATE":             "true",
+		"AUTO_CROSS_VALIDATE":    "first-complete,max-checked=5",
+		"EVIDENCE_PATTERNS":      "Deploy,Migrate",
+		"SUMMARY_AI":             "amazonq",
+		"TRIAGE_AI":              "codex",
+		"TRIAGE_MODEL":           "o4-mini",
+		"LEARNINGS_FILE":         "/tmp/learnings.md",
+		"NOTIFY_WEBHOOK":         "https://example.com/hook",
+		"NOTIFY_CHANNEL":         "slack",
+		"NOTIFY_CHAT_ID":         "99999",
+		"STATE_KEY":              "c3RhdGUta2V5LXN0YXRlLWtleS1zdGF0ZS1rZXktMzI=",
+		"STATE_DIR":              "xdg",
+		"VALIDATOR_POOL":         "claude:opus,codex",
+		"SANDBOX_CMD":            "firejail --quiet",
+		"SANDBOX_ENV":            "ANTHROPIC_API_KEY",
+		"CONTAINER_IMAGE":        "ghcr.io/acme/agent:1",
+		"CONTAINER_RUNTIME":      "podman",
+		"CONTAINER_PULL":         "always",
+		"K8S_JOB_IMAGE":          "ghcr.io/acme/agent:1",
+		"K8S_JOB_NAMESPACE":      "ci",
+		"K8S_JOB_SECRETS":        "ai-keys",
+		"K8S_JOB_PVC":            "project",
+		"REMOTE_AGENT":           "http://gpu-box:7433",
+		"SPEC_DRIFT":             "regenerate",
+		"ALLOWED_TOOLS":          "Edit,Bash(go test:*)",
+		"DENIED_COMMANDS":        "rm,git push",
+		"METRICS_ADDR":           "127.0.0.1:9464",
+		"METRICS_FILE":           "/var/lib/node_exporter/ralph.prom",
+		"TUI":                    "true",
+		"SERVE":                  "127.0.0.1:8080",
+		"ISSUE_PROVIDER":         "gitlab",
+		"SCREENSHOT_THRESHOLD":   "2.5",
+		"MAX_DURATION":           "1h30m",
+		"SHUTDOWN_GRACE":         "45s",
+		"RESPONSE_CACHE":         "false",
+		"CACHE_TTL":              "2h",
+		"NOTIFY_EVENTS":          "escalate,blocked,completed",
+		"NOTIFY_DIGEST":          "30m",
+		"HEARTBEAT_INTERVAL":     "15m",
+		"HEARTBEAT_ITERATIONS":   "5",
+		"PAUSE_BETWEEN":          "09:00-18:00 weekdays",
+		"LEARNINGS_TAGS":         "go,react",
+		"MIN_CONFIDENCE":         "0.8",
+		"CUSTOM_VERDICTS":        "SECURITY_REVIEW_NEEDED=escalate",
+		"POLICY_FILE":            "ralph-policies.yaml",
+		"PROTECTED_PATHS":        ".github/workflows,infra/prod",
+		"PROTECTED_PATHS_ACTION": "escalate",
+		"ALLOW_DIRTY":            "true",
+		"GITHUB_CHECK":           "true",
+		"PROTECTED_BRANCHES":     "main,release",
+		"COVERAGE_FILE":          "coverage.out",
 	}
 
 	config.ApplyMapToConfig(cfg, m)
@@ -293,14 +461,64 @@ func TestApplyMapToConfigSetsAllStringFields(t *testing.T) {
 	assert.Equal(t, "gpt-3.5", cfg.ValModel)
 	assert.Equal(t, "claude", cfg.CrossAI)
 	assert.Equal(t, "sonnet", cfg.CrossModel)
+	assert.Equal(t, "codex", cfg.FallbackAI)
+	assert.Equal(t, "coverage.out", cfg.CoverageFile)
 	assert.Equal(t, "codex", cfg.FinalPlanAI)
 	assert.Equal(t, "gpt-4", cfg.FinalPlanModel)
 	assert.Equal(t, "claude", cfg.TasksValAI)
 	assert.Equal(t, "opus", cfg.TasksValModel)
+	assert.Equal(t, "haiku", cfg.SummaryModel)
+	assert.Equal(t, "copilot", cfg.ValAI)
+	assert.True(t, cfg.ValRotate)
+	assert.Equal(t, "first-complete,max-checked=5", cfg.AutoCrossValidate)
+	assert.Equal(t, "Deploy,Migrate", cfg.EvidencePatterns)
+	assert.Equal(t, "amazonq", cfg.SummaryAI)
+	assert.Equal(t, "codex", cfg.TriageAI)
+	assert.Equal(t, "o4-mini", cfg.TriageModel)
 	assert.Equal(t, "/tmp/learnings.md", cfg.LearningsFile)
 	assert.Equal(t, "https://example.com/hook", cfg.NotifyWebhook)
 	assert.Equal(t, "slack", cfg.NotifyChannel)
 	assert.Equal(t, "99999", cfg.NotifyChatID)
+	assert.Equal(t, "c3RhdGUta2V5LXN0YXRlLWtleS1zdGF0ZS1rZXktMzI=", cfg.StateKey)
+	assert.Equal(t, "xdg", cfg.StateDir)
+	assert.Equal(t, "claude:opus,codex", cfg.ValidatorPool)
+	assert.Equal(t, "firejail --quiet", cfg.SandboxCmd)
+	assert.Equal(t, "ANTHROPIC_API_KEY", cfg.SandboxEnv)
+	assert.Equal(t, "ghcr.io/acme/agent:1", cfg.ContainerImage)
+	assert.Equal(t, "podman", cfg.ContainerRuntime)
+	assert.Equal(t, "always", cfg.ContainerPull)
+	assert.Equal(t, "ghcr.io/acme/agent:1", cfg.K8sJobImage)
+	assert.Equal(t, "ci", cfg.K8sJobNamespace)
+	assert.Equal(t, "ai-keys", cfg.K8sJobSecrets)
+	assert.Equal(t, "project", cfg.K8sJobPVC)
+	assert.Equal(t, "http://gpu-box:7433", cfg.RemoteAgent)
+	assert.Equal(t, "regenerate", cfg.SpecDrift)
+	assert.Equal(t, "Edit,Bash(go test:*)", cfg.AllowedTools)
+	assert.Equal(t, "rm,git push", cfg.DeniedCommands)
+	assert.Equal(t, "127.0.0.1:9464", cfg.MetricsAddr)
+	assert.Equal(t, "/var/lib/node_exporter/ralph.prom", cfg.MetricsFile)
+	assert.True(t, cfg.TUI)
+	assert.Equal(t, "127.0.0.1:8080", cfg.Serve)
+	assert.Equal(t, "gitlab", cfg.IssueProvider)
+	assert.Equal(t, 2.5, cfg.ScreenshotThreshold)
+	assert.Equal(t, 90*time.Minute, cfg.MaxDuration)
+	assert.Equal(t, 45*time.Second, cfg.ShutdownGrace)
+	assert.False(t, cfg.ResponseCache)
+	assert.Equal(t, 2*time.Hour, cfg.CacheTTL)
+	assert.Equal(t, "escalate,blocked,completed", cfg.NotifyEvents)
+	assert.Equal(t, 30*time.Minute, cfg.NotifyDigest)
+	assert.Equal(t, 15*time.Minute, cfg.HeartbeatInterval)
+	assert.Equal(t, 5, cfg.HeartbeatIterations)
+	assert.Equal(t, "09:00-18:00 weekdays", cfg.PauseBetween)
+	assert.Equal(t, "go,react", cfg.LearningsTags)
+	assert.Equal(t, 0.8, cfg.MinConfidence)
+	assert.Equal(t, "SECURITY_REVIEW_NEEDED=escalate", cfg.CustomVerdicts)
+	assert.Equal(t, "ralph-policies.yaml", cfg.PolicyFile)
+	assert.Equal(t, ".github/workflows,infra/prod", cfg.ProtectedPaths)
+	assert.Equal(t, config.ProtectedEscalate, cfg.ProtectedPathsAction)
+	assert.True(t, cfg.AllowDirty)
+	assert.True(t, cfg.GitHubCheck)
+	assert.Equal(t, "main,release", cfg.ProtectedBranches)
 }
 
 func TestApplyMapToConfigIgnoresSessionVars(t *testing.T) {
@@ -321,11 +539,28 @@ func TestApplyMapToConfigIgnoresSessionVars(t *testing.T) {
 func TestApplyMapToConfigSetsIntegerFields(t *testing.T) {
 	cfg := config.NewDefaultConfig()
 	m := map[string]string{
-		"MAX_ITERATIONS":     "50",
-		"MAX_INADMISSIBLE":   "10",
-		"MAX_CLAUDE_RETRY":   "25",
-		"MAX_TURNS":          "200",
-		"INACTIVITY_TIMEOUT": "3600",
+		"MAX_ITERATIONS":         "50",
+		"MAX_INADMISSIBLE":       "10",
+		"MAX_CLAUDE_RETRY":       "25",
+		"MAX_TURNS":              "200",
+		"MAX_TASK_ATTEMPTS":      "4",
+		"MAX_FORMAT_RETRIES":     "1",
+		"INACTIVITY_TIMEOUT":     "3600",
+		"HANG_TIMEOUT":           "300",
+		"FAILOVER_AFTER":         "5",
+		"ITERATION_TIMEOUT":      "2700",
+		"IMPL_TIMEOUT":           "1800",
+		"VAL_TIMEOUT":            "900",
+		"VALIDATORS":             "3",
+		"LEARNINGS_MAX_TOKENS":   "2000",
+		"CONTEXT_MAX_TOKENS":     "6000",
+		"VAL_DIFF_MAX_TOKENS":    "4000",
+		"IMPL_OUTPUT_MAX_TOKENS": "12000",
+		"LOG_MAX_MB":             "0",
+		"RETAIN_ITERATIONS":      "20",
+		"RETAIN_MB":              "500",
+		"OUTPUT_GZIP_KB":         "256",
+		"PR_NUMBER":              "42",
 	}
 
 	config.ApplyMapToConfig(cfg, m)
@@ -334,7 +569,24 @@ func TestApplyMapToConfigSetsIntegerFields(t *testing.T) {
 	assert.Equal(t, 10, cfg.MaxInadmissible)
 	assert.Equal(t, 25, cfg.MaxClaudeRetry)
 	assert.Equal(t, 200, cfg.MaxTurns)
+	assert.Equal(t, 4, cfg.MaxTaskAttempts)
+	assert.Equal(t, 1, cfg.MaxFormatRetries)
 	assert.Equal(t, 3600, cfg.InactivityTimeout)
+	assert.Equal(t, 300, cfg.HangTimeout)
+	assert.Equal(t, 5, cfg.FailoverAfter)
+	assert.Equal(t, 2700, cfg.IterationTimeout)
+	assert.Equal(t, 1800, cfg.ImplTimeout)
+	assert.Equal(t, 900, cfg.ValTimeout)
+	assert.Equal(t, 3, cfg.Validators)
+	assert.Equal(t, 2000, cfg.LearningsMaxTokens)
+	assert.Equal(t, 6000, cfg.ContextMaxTokens)
+	assert.Equal(t, 4000, cfg.ValDiffMaxTokens)
+	assert.Equal(t, 12000, cfg.ImplOutputMaxTokens)
+	assert.Equal(t, 0, cfg.LogMaxMB)
+	assert.Equal(t, 20, cfg.RetainIterations)
+	assert.Equal(t, 500, cfg.RetainMB)
+	assert.Equal(t, 256, cfg.OutputGzipKB)
+	assert.Equal(t, 42, cfg.PRNumber)
 }
 
 func TestApplyMapToConfigSetsBooleanFields(t *testing.T) {
@@ -344,13 +596,27 @@ func TestApplyMapToConfigSetsBooleanFields(t *testing.T) {
 	m := map[string]string{
 		"CROSS_VALIDATE":   "false",
 		"ENABLE_LEARNINGS": "false",
+		"GLOBAL_LEARNINGS": "false",
+		"PROJECT_MEMORY":   "false",
 		"VERBOSE":          "true",
+		"APPLY_PATCH":      "true",
+		"LOG_GZIP":         "true",
+		"DEDUP_OUTPUTS":    "true",
+		"ANNOTATE_TASKS":   "true",
+		"TRIAGE":           "true",
 	}
 	config.ApplyMapToConfig(cfg, m)
 
 	assert.False(t, cfg.CrossValidate)
 	assert.False(t, cfg.EnableLearnings)
+	assert.False(t, cfg.GlobalLearnings)
+	assert.False(t, cfg.ProjectMemory)
 	assert.True(t, cfg.Verbose)
+	assert.True(t, cfg.ApplyPatch)
+	assert.True(t, cfg.LogGzip)
+	assert.True(t, cfg.DedupOutputs)
+	assert.True(t, cfg.AnnotateTasks)
+	assert.True(t, cfg.Triage)
 }
 
 func TestApplyMapToConfigBooleanVariations(t *testing.T) {
@@ -395,6 +661,49 @@ func TestApplyMapToConfigIgnoresInvalidIntegers(t *testing.T) {
 	assert.Equal(t, original, cfg.MaxIterations, "invalid integer should preserve previous value")
 }
 
+func TestApplyMapToConfigSetsExternalValidators(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	config.ApplyMapToConfig(cfg, map[string]string{"EXTERNAL_VALIDATORS": "semgrep=./scripts/semgrep.sh;qodana=qodana-validate"})
+
+	assert.Equal(t, "semgrep=./scripts/semgrep.sh;qodana=qodana-validate", cfg.ExternalValidators)
+}
+
+func TestApplyMapToConfigSetsVerifyCommands(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	config.ApplyMapToConfig(cfg, map[string]string{
+		"VERIFY_CMDS":    "go test -json ./...;dotnet test --logger trx",
+		"VERIFY_REPORTS": "TestResults/*.trx,junit.xml",
+	})
+
+	assert.Equal(t, "go test -json ./...;dotnet test --logger trx", cfg.VerifyCmds)
+	assert.Equal(t, "TestResults/*.trx,junit.xml", cfg.VerifyReports)
+}
+
+func TestApplyMapToConfigSetsHooks(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	config.ApplyMapToConfig(cfg, map[string]string{
+		"HOOK_PRE_ITERATION":       "make db-reset",
+		"HOOK_POST_IMPLEMENTATION": "./scripts/sast.sh",
+		"HOOK_POST_VALIDATION":     "make e2e",
+		"HOOK_ON_EXIT":             "notify-send done",
+		"HOOK_TIMEOUT":             "120",
+	})
+
+	assert.Equal(t, "make db-reset", cfg.HookPreIteration)
+	assert.Equal(t, "./scripts/sast.sh", cfg.HookPostImplementation)
+	assert.Equal(t, "make e2e", cfg.HookPostValidation)
+	assert.Equal(t, "notify-send done", cfg.HookOnExit)
+	assert.Equal(t, 120, cfg.HookTimeout)
+}
+
+func TestApplyMapToConfigIgnoresInvalidProtectedPathsAction(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+
+	config.ApplyMapToConfig(cfg, map[string]string{"PROTECTED_PATHS_ACTION": "delete"})
+
+	assert.Equal(t, config.ProtectedRevert, cfg.ProtectedPathsAction)
+}
+
 func TestApplyMapToConfigIgnoresUnknownKeys(t *testing.T) {
 	cfg := config.NewDefaultConfig()
 	expected := config.NewDefaultConfig()
@@ -443,7 +752,7 @@ VERBOSE=false
 	explicitPath := writeFile(t, dir, "explicit.config", `
 MAX_ITERATIONS=15
 VERBOSE=true
-CROSS_AI=openai
+CROSS_AI=copilot
 CROSS_MODEL=gpt-3.5
 TASKS_VAL_AI=claude
 TASKS_VAL_MODEL=opus
@@ -468,7 +777,7 @@ TASKS_VAL_MODEL=opus
 
 	// From explicit config
 	assert.True(t, cfg.Verbose, "Explicit config should override project for Verbose")
-	assert.Equal(t, "openai", cfg.CrossAI, "Explicit config should set CrossAI")
+	assert.Equal(t, "copilot", cfg.CrossAI, "Explicit config should set CrossAI")
 	assert.Equal(t, "gpt-3.5", cfg.CrossModel, "Explicit config should set CrossModel")
 	assert.Equal(t, "claude", cfg.TasksValAI, "Explicit config should set TasksValAI")
 	assert.Equal(t, "opus", cfg.TasksValModel, "Explicit config should set TasksValModel")
@@ -503,7 +812,7 @@ IMPL_MODEL=gpt-4
 VAL_MODEL=gpt-3.5
 CROSS_AI=claude
 CROSS_MODEL=opus
-FINAL_PLAN_AI=openai
+FINAL_PLAN_AI=copilot
 FINAL_PLAN_MODEL=gpt-4
 TASKS_VAL_AI=claude
 TASKS_VAL_MODEL=sonnet
@@ -536,7 +845,7 @@ VERBOSE=true
 	assert.Equal(t, "gpt-3.5", cfg.ValModel)
 	assert.Equal(t, "claude", cfg.CrossAI)
 	assert.Equal(t, "opus", cfg.CrossModel)
-	assert.Equal(t, "openai", cfg.FinalPlanAI)
+	assert.Equal(t, "copilot", cfg.FinalPlanAI)
 	assert.Equal(t, "gpt-4", cfg.FinalPlanModel)
 	assert.Equal(t, "claude", cfg.TasksValAI)
 	assert.Equal(t, "sonnet", cfg.TasksValModel)
@@ -572,7 +881,7 @@ CROSS_VALIDATE=true
 `)
 
 	projectPath := writeFile(t, dir, "project.config", `
-AI_CLI=openai
+AI_CLI=amazonq
 MAX_ITERATIONS=50
 VERBOSE=false
 CROSS_VALIDATE=true
@@ -768,3 +1077,57 @@ MAX_ITERATIONS=30
 	assert.Equal(t, defaults.NotifyWebhook, cfg.NotifyWebhook)
 	assert.Equal(t, defaults.NotifyChannel, cfg.NotifyChannel)
 }
+
+func TestApplyMapToConfigSetsSampling(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	config.ApplyMapToConfig(cfg, map[string]string{
+		"IMPL_TEMPERATURE":            "0.7",
+		"VAL_REASONING_EFFORT":        "LOW",
+		"CROSS_REASONING_EFFORT":      "high",
+		"FINAL_PLAN_TEMPERATURE":      "0",
+		"TASKS_VAL_REASONING_EFFORT":  "minimal",
+		"VAL_TEMPERATURE":             "2.5",
+		"IMPL_REASONING_EFFORT":       "extreme",
+		"TASKS_VAL_TEMPERATURE":       "warm",
+		"FINAL_PLAN_REASONING_EFFORT": "medium",
+	})
+
+	assert.Equal(t, config.Sampling{}, cfg.ImplSampling, "temperature and invalid effort are ignored")
+	assert.Equal(t, config.Sampling{ReasoningEffort: "low"}, cfg.ValSampling)
+	assert.Equal(t, config.Sampling{ReasoningEffort: "high"}, cfg.CrossSampling)
+	assert.Equal(t, config.Sampling{ReasoningEffort: "medium"}, cfg.FinalPlanSampling)
+	assert.Equal(t, config.Sampling{ReasoningEffort: "minimal"}, cfg.TasksValSampling)
+}
+
+func TestLoadFileAcceptsSamplingKeys(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "config", "VAL_REASONING_EFFORT=low\nVAL_TEMPERATURE=0.2\nOTHER_TEMPERATURE=1\n")
+
+	m, err := config.LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"VAL_REASONING_EFFORT": "low", "VAL_TEMPERATURE": "0.2"}, m)
+}
+
+func TestApplyMapToConfig_ScreenshotThresholdOutOfRange(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	config.ApplyMapToConfig(cfg, map[string]string{"SCREENSHOT_THRESHOLD": "150"})
+	assert.Equal(t, 0.5, cfg.ScreenshotThreshold)
+	config.ApplyMapToConfig(cfg, map[string]string{"SCREENSHOT_THRESHOLD": "abc"})
+	assert.Equal(t, 0.5, cfg.ScreenshotThreshold)
+}
+
+func TestApplyMapToConfig_MinConfidenceOutOfRange(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	config.ApplyMapToConfig(cfg, map[string]string{"MIN_CONFIDENCE": "80"})
+	assert.Zero(t, cfg.MinConfidence)
+	config.ApplyMapToConfig(cfg, map[string]string{"MIN_CONFIDENCE": "high"})
+	assert.Zero(t, cfg.MinConfidence)
+}
+
+func TestApplyMapToConfig_MaxDurationInvalid(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	config.ApplyMapToConfig(cfg, map[string]string{"MAX_DURATION": "two hours"})
+	assert.Zero(t, cfg.MaxDuration)
+	config.ApplyMapToConfig(cfg, map[string]string{"MAX_DURATION": "-1h"})
+	assert.Zero(t, cfg.MaxDuration)
+}
@@ -0,0 +1,41 @@
+package config
+
+// Reload copies from fresh, a configuration loaded again from the config
+// files, the settings a running loop reads afresh every iteration, and
+// returns the names of those that changed. Settings fixed at startup, such
+// as the AI CLIs, models, policies, pause windows and the shutdown grace
+// period, keep their values.
+func (c *Config) Reload(fresh *Config) []string {
+	var changed []string
+	reload(&changed, "NOTIFY_WEBHOOK", &c.NotifyWebhook, fresh.NotifyWebhook)
+	reload(&changed, "NOTIFY_CHANNEL", &c.NotifyChannel, fresh.NotifyChannel)
+	reload(&changed, "NOTIFY_CHAT_ID", &c.NotifyChatID, fresh.NotifyChatID)
+	reload(&changed, "NOTIFY_EVENTS", &c.NotifyEvents, fresh.NotifyEvents)
+	reload(&changed, "NOTIFY_DIGEST", &c.NotifyDigest, fresh.NotifyDigest)
+	reload(&changed, "MAX_TASK_ATTEMPTS", &c.MaxTaskAttempts, fresh.MaxTaskAttempts)
+	reload(&changed, "MAX_FORMAT_RETRIES", &c.MaxFormatRetries, fresh.MaxFormatRetries)
+	reload(&changed, "MAX_DURATION", &c.MaxDuration, fresh.MaxDuration)
+	reload(&changed, "ITERATION_TIMEOUT", &c.IterationTimeout, fresh.IterationTimeout)
+	reload(&changed, "IMPL_TIMEOUT", &c.ImplTimeout, fresh.ImplTimeout)
+	reload(&changed, "VAL_TIMEOUT", &c.ValTimeout, fresh.ValTimeout)
+	reload(&changed, "LEARNINGS_MAX_TOKENS", &c.LearningsMaxTokens, fresh.LearningsMaxTokens)
+	reload(&changed, "CONTEXT_MAX_TOKENS", &c.ContextMaxTokens, fresh.ContextMaxTokens)
+	reload(&changed, "VAL_DIFF_MAX_TOKENS", &c.ValDiffMaxTokens, fresh.ValDiffMaxTokens)
+	reload(&changed, "IMPL_OUTPUT_MAX_TOKENS", &c.ImplOutputMaxTokens, fresh.ImplOutputMaxTokens)
+	reload(&changed, "SCREENSHOT_THRESHOLD", &c.ScreenshotThreshold, fresh.ScreenshotThreshold)
+	reload(&changed, "EVIDENCE_PATTERNS", &c.EvidencePatterns, fresh.EvidencePatterns)
+	reload(&changed, "HOOK_PRE_ITERATION", &c.HookPreIteration, fresh.HookPreIteration)
+	reload(&changed, "HOOK_POST_IMPLEMENTATION", &c.HookPostImplementation, fresh.HookPostImplementation)
+	reload(&changed, "HOOK_POST_VALIDATION", &c.HookPostValidation, fresh.HookPostValidation)
+	reload(&changed, "HOOK_ON_EXIT", &c.HookOnExit, fresh.HookOnExit)
+	reload(&changed, "HOOK_TIMEOUT", &c.HookTimeout, fresh.HookTimeout)
+	return changed
+}
+
+// reload sets *dst to v, recording key in changed when that changes it.
+func reload[T comparable](changed *[]string, key string, dst *T, v T) {
+	if *dst != v {
+		*dst = v
+		*changed = append(*changed, key)
+	}
+}
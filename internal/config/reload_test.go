@@ -0,0 +1,30 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+)
+
+func TestReload(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	fresh := config.NewDefaultConfig()
+	assert.Empty(t, cfg.Reload(fresh))
+
+	fresh.HookTimeout = 60
+	fresh.MaxDuration = 2 * time.Hour
+	fresh.NotifyChatID = "C42"
+	fresh.NotifyDigest = time.Hour
+	fresh.ImplModel = "sonnet"
+	fresh.PauseBetween = "09:00-18:00"
+
+	assert.Equal(t, []string{"NOTIFY_CHAT_ID", "NOTIFY_DIGEST", "MAX_DURATION", "HOOK_TIMEOUT"}, cfg.Reload(fresh))
+	assert.Equal(t, 60, cfg.HookTimeout)
+	assert.Equal(t, 2*time.Hour, cfg.MaxDuration)
+	assert.Equal(t, "C42", cfg.NotifyChatID)
+	assert.Equal(t, "opus", cfg.ImplModel, "models are fixed at startup")
+	assert.Empty(t, cfg.PauseBetween, "pause windows are parsed at startup")
+}
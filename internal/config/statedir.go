@@ -0,0 +1,63 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultStateDir is the state directory, relative to the project, when
+// STATE_DIR is not set. The project config file stays in it wherever the
+// state is kept.
+const DefaultStateDir = ".ralph-loop"
+
+// StateDirXDG as STATE_DIR keeps the state of each project in
+// $XDG_STATE_HOME/ralph-loop/<project>-<hash>, out of the repository.
+const StateDirXDG = "xdg"
+
+// ProjectStateDir returns the state directory STATE_DIR value sets for
+// the project in root: DefaultStateDir when empty, a per-project directory
+// under $XDG_STATE_HOME (~/.local/state by default) for StateDirXDG, and
+// otherwise value, relative to root unless absolute or under ~/.
+func ProjectStateDir(value, root string) (string, error) {
+	switch {
+	case value == "":
+		return filepath.Join(root, DefaultStateDir), nil
+	case value == StateDirXDG:
+		base, err := xdgStateHome()
+		if err != nil {
+			return "", err
+		}
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			return "", err
+		}
+		sum := sha256.Sum256([]byte(abs))
+		return filepath.Join(base, "ralph-loop", filepath.Base(abs)+"-"+hex.EncodeToString(sum[:6])), nil
+	case value == "~" || strings.HasPrefix(value, "~/"):
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("STATE_DIR %s: %w", value, err)
+		}
+		return filepath.Join(home, strings.TrimPrefix(value, "~")), nil
+	case filepath.IsAbs(value):
+		return filepath.Clean(value), nil
+	}
+	return filepath.Join(root, value), nil
+}
+
+// xdgStateHome returns $XDG_STATE_HOME, or ~/.local/state when it is
+// unset or, as the specification requires ignoring, relative.
+func xdgStateHome() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("STATE_DIR xdg: %w", err)
+	}
+	return filepath.Join(home, ".local", "state"), nil
+}
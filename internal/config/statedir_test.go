@@ -0,0 +1,48 @@
+package config_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+)
+
+func TestProjectStateDir(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "shop-api")
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+	sum := sha256.Sum256([]byte(root))
+	project := "shop-api-" + hex.EncodeToString(sum[:6])
+
+	for value, want := range map[string]string{
+		"":                  filepath.Join(root, ".ralph-loop"),
+		"build/ralph":       filepath.Join(root, "build", "ralph"),
+		"/var/lib/ralph/x/": "/var/lib/ralph/x",
+		"~/ralph-state":     filepath.Join(home, "ralph-state"),
+		"xdg":               filepath.Join(home, "state", "ralph-loop", project),
+	} {
+		got, err := config.ProjectStateDir(value, root)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, got, value)
+	}
+
+	other, err := config.ProjectStateDir("xdg", filepath.Join(t.TempDir(), "shop-api"))
+	require.NoError(t, err)
+	assert.NotEqual(t, filepath.Join(home, "state", "ralph-loop", project), other, "projects of the same name get their own directory")
+
+	t.Setenv("XDG_STATE_HOME", "relative/state")
+	got, err := config.ProjectStateDir("xdg", root)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".local", "state", "ralph-loop", project), got, "a relative XDG_STATE_HOME is ignored")
+	require.NoError(t, os.Unsetenv("XDG_STATE_HOME"))
+	got, err = config.ProjectStateDir("xdg", root)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".local", "state", "ralph-loop", project), got)
+}
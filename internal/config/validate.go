@@ -0,0 +1,208 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/model"
+	"github.com/CodexForgeBR/cli-tools/internal/statecrypt"
+)
+
+// MaxIterationsLimit is the most iterations MAX_ITERATIONS accepts.
+const MaxIterationsLimit = 1000
+
+// check returns what is wrong with the value of a setting, or "" when the
+// value is accepted.
+type check func(value string) string
+
+// checks are the values the settings accept. Settings without a check
+// accept any value.
+var checks = map[string]check{
+	"AI_CLI":        provider,
+	"VAL_AI":        provider,
+	"CROSS_AI":      provider,
+	"FALLBACK_AI":   provider,
+	"FINAL_PLAN_AI": provider,
+	"TASKS_VAL_AI":  provider,
+	"SUMMARY_AI":    provider,
+	"TRIAGE_AI":     provider,
+
+	"MAX_ITERATIONS":         intRange(1, MaxIterationsLimit),
+	"VALIDATORS":             intRange(1, -1),
+	"FAILOVER_AFTER":         intRange(1, -1),
+	"ESCALATE_AFTER":         intRange(0, -1),
+	"MAX_INADMISSIBLE":       intRange(0, -1),
+	"MAX_TASK_ATTEMPTS":      intRange(0, -1),
+	"MAX_FORMAT_RETRIES":     intRange(0, -1),
+	"MAX_CLAUDE_RETRY":       intRange(0, -1),
+	"MAX_TURNS":              intRange(0, -1),
+	"LOG_MAX_MB":             intRange(0, -1),
+	"RETAIN_ITERATIONS":      intRange(0, -1),
+	"RETAIN_MB":              intRange(0, -1),
+	"OUTPUT_GZIP_KB":         intRange(0, -1),
+	"INACTIVITY_TIMEOUT":     intRange(0, -1),
+	"HANG_TIMEOUT":           intRange(0, -1),
+	"ITERATION_TIMEOUT":      intRange(0, -1),
+	"IMPL_TIMEOUT":           intRange(0, -1),
+	"VAL_TIMEOUT":            intRange(0, -1),
+	"HOOK_TIMEOUT":           intRange(0, -1),
+	"LEARNINGS_MAX_TOKENS":   intRange(0, -1),
+	"CONTEXT_MAX_TOKENS":     intRange(0, -1),
+	"VAL_DIFF_MAX_TOKENS":    intRange(0, -1),
+	"IMPL_OUTPUT_MAX_TOKENS": intRange(0, -1),
+	"HEARTBEAT_ITERATIONS":   intRange(0, -1),
+	"PR_NUMBER":              intRange(0, -1),
+
+	"NOTIFY_DIGEST":      duration,
+	"HEARTBEAT_INTERVAL": duration,
+	"MAX_DURATION":       duration,
+	"SHUTDOWN_GRACE":     duration,
+	"CACHE_TTL":          duration,
+
+	"SCREENSHOT_THRESHOLD": floatRange(0, 100),
+	"MIN_CONFIDENCE":       floatRange(0, 1),
+
+	"VAL_ROTATE":       boolean,
+	"CROSS_VALIDATE":   boolean,
+	"TRIAGE":           boolean,
+	"LOG_GZIP":         boolean,
+	"DEDUP_OUTPUTS":    boolean,
+	"ENABLE_LEARNINGS": boolean,
+	"GLOBAL_LEARNINGS": boolean,
+	"PROJECT_MEMORY":   boolean,
+	"VERBOSE":          boolean,
+	"APPLY_PATCH":      boolean,
+	"PR_COMMENT":       boolean,
+	"GITHUB_CHECK":     boolean,
+	"TUI":              boolean,
+	"RESPONSE_CACHE":   boolean,
+	"ANNOTATE_TASKS":   boolean,
+	"ALLOW_DIRTY":      boolean,
+
+	"CONTAINER_RUNTIME":      oneOf("docker", "podman"),
+	"CONTAINER_PULL":         oneOf(ContainerPullMissing, ContainerPullAlways, ContainerPullNever),
+	"SPEC_DRIFT":             oneOf(SpecDriftOff, SpecDriftPause, SpecDriftRegenerate),
+	"PROTECTED_PATHS_ACTION": oneOf(ProtectedRevert, ProtectedEscalate),
+	"ISSUE_PROVIDER":         oneOf("github", "gitlab", "gitea"),
+
+	"NOTIFY_WEBHOOK": httpURL,
+	"REMOTE_AGENT":   httpURL,
+	"METRICS_ADDR":   listenAddr,
+	"SERVE":          listenAddr,
+	"STATE_KEY":      stateKey,
+}
+
+// checkFor returns the check of key, including the <PHASE>_TEMPERATURE
+// and <PHASE>_REASONING_EFFORT keys, or nil. No AI CLI accepts a
+// temperature, so every <PHASE>_TEMPERATURE value is rejected.
+func checkFor(key string) check {
+	if c, ok := checks[key]; ok {
+		return c
+	}
+	if phase, ok := strings.CutSuffix(key, "_TEMPERATURE"); ok && samplingPhases[phase] != nil {
+		return func(string) string {
+			return "not supported: no AI CLI accepts a temperature; use " + phase + "_REASONING_EFFORT"
+		}
+	}
+	if phase, ok := strings.CutSuffix(key, "_REASONING_EFFORT"); ok && samplingPhases[phase] != nil {
+		return func(value string) string { return oneOf(ReasoningEfforts...)(strings.ToLower(value)) }
+	}
+	return nil
+}
+
+// checkValue returns what is wrong with value for the setting key, or ""
+// when it is accepted. Empty values are not checked: they reset a setting.
+func checkValue(key, value string) string {
+	c := checkFor(key)
+	if c == nil || value == "" {
+		return ""
+	}
+	return c(value)
+}
+
+// invalidConfigError reports every rejected setting at once, each line
+// naming where it came from.
+func invalidConfigError(problems []string) error {
+	return fmt.Errorf("invalid configuration:\n  %s", strings.Join(problems, "\n  "))
+}
+
+func provider(value string) string {
+	if model.IsProvider(value) {
+		return ""
+	}
+	return "must be one of " + strings.Join(model.Providers, ", ")
+}
+
+// intRange accepts integers from min to max; a negative max means no
+// upper bound.
+func intRange(min, max int) check {
+	return func(value string) string {
+		if v, err := strconv.Atoi(value); err == nil && v >= min && (max < 0 || v <= max) {
+			return ""
+		}
+		if max < 0 {
+			return fmt.Sprintf("must be an integer of at least %d", min)
+		}
+		return fmt.Sprintf("must be an integer from %d to %d", min, max)
+	}
+}
+
+func floatRange(min, max float64) check {
+	return func(value string) string {
+		if v, err := strconv.ParseFloat(value, 64); err == nil && v >= min && v <= max {
+			return ""
+		}
+		return fmt.Sprintf("must be a number from %g to %g", min, max)
+	}
+}
+
+func duration(value string) string {
+	if v, err := time.ParseDuration(value); err == nil && v >= 0 {
+		return ""
+	}
+	return "must be a duration such as 90s, 30m or 2h"
+}
+
+func boolean(value string) string {
+	switch strings.ToLower(value) {
+	case "true", "1", "yes", "false", "0", "no":
+		return ""
+	}
+	return "must be true or false"
+}
+
+func oneOf(values ...string) check {
+	return func(value string) string {
+		for _, v := range values {
+			if value == v {
+				return ""
+			}
+		}
+		return "must be one of " + strings.Join(values, ", ")
+	}
+}
+
+func httpURL(value string) string {
+	if u, err := url.Parse(value); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+		return ""
+	}
+	return "must be an http or https URL"
+}
+
+func listenAddr(value string) string {
+	if _, port, err := net.SplitHostPort(value); err == nil && port != "" {
+		return ""
+	}
+	return "must be a host:port address such as :8080 or 127.0.0.1:9464"
+}
+
+func stateKey(value string) string {
+	if _, err := statecrypt.ParseKey(value); err != nil {
+		return fmt.Sprintf("must be %d bytes in base64, e.g. from openssl rand -base64 32", statecrypt.KeySize)
+	}
+	return ""
+}
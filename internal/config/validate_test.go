@@ -0,0 +1,107 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/secrets"
+)
+
+func TestLoadWithPrecedenceRejectsInvalidValues(t *testing.T) {
+	dir := t.TempDir()
+	globalPath := writeFile(t, dir, "global", "MAX_ITERATIONS=0\nAI_CLI=gpt\n")
+	projectPath := writeFile(t, dir, "project", "REMOTE_AGENT=agent.local:7000\nSTATE_KEY=hunter2hunter2\nVAL_TEMPERATURE=3\n[profile.fast]\nCACHE_TTL=soon\n")
+	t.Setenv("RALPH_VERBOSE", "ture")
+
+	_, err := config.LoadWithPrecedence(globalPath, projectPath, "", map[string]string{"PROFILE": "fast", "MAX_ITERATIONS": "5000"})
+	require.Error(t, err)
+	assert.Equal(t, "invalid configuration:\n"+
+		"  "+globalPath+`: AI_CLI="gpt": must be one of claude, codex, amazonq, copilot`+"\n"+
+		"  "+globalPath+`: MAX_ITERATIONS="0": must be an integer from 1 to 1000`+"\n"+
+		"  "+projectPath+`: REMOTE_AGENT="agent.local:7000": must be an http or https URL`+"\n"+
+		"  "+projectPath+`: STATE_KEY=[REDACTED]: must be 32 bytes in base64, e.g. from openssl rand -base64 32`+"\n"+
+		"  "+projectPath+`: VAL_TEMPERATURE="3": not supported: no AI CLI accepts a temperature; use VAL_REASONING_EFFORT`+"\n"+
+		`  profile fast: CACHE_TTL="soon": must be a duration such as 90s, 30m or 2h`+"\n"+
+		`  environment: RALPH_VERBOSE="ture": must be true or false`+"\n"+
+		`  command line: MAX_ITERATIONS="5000": must be an integer from 1 to 1000`, err.Error())
+}
+
+func TestLoadWithPrecedenceAcceptsValidValues(t *testing.T) {
+	dir := t.TempDir()
+	projectPath := writeFile(t, dir, "project", `AI_CLI=codex
+MAX_ITERATIONS=1000
+VALIDATORS=3
+MIN_CONFIDENCE=0.8
+MAX_DURATION=2h
+VERBOSE=yes
+CONTAINER_PULL=never
+IMPL_REASONING_EFFORT=HIGH
+NOTIFY_WEBHOOK=https://hooks.example.com/ralph
+SERVE=:8080
+METRICS_ADDR=127.0.0.1:9464
+STATE_KEY=c3RhdGUta2V5LXN0YXRlLWtleS1zdGF0ZS1rZXktMzI=
+IMPL_MODEL=anything-goes
+VAL_AI=
+`)
+	cfg, err := config.LoadWithPrecedence("", projectPath, "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1000, cfg.MaxIterations)
+	assert.Equal(t, "high", cfg.ImplSampling.ReasoningEffort)
+	assert.Equal(t, ":8080", cfg.Serve)
+	assert.Equal(t, []string{"https://hooks.example.com/ralph", "c3RhdGUta2V5LXN0YXRlLWtleS1zdGF0ZS1rZXktMzI="}, cfg.Secrets, "STATE_KEY is secret")
+}
+
+func TestResolveExpandsReferences(t *testing.T) {
+	prev := secrets.SetKeyring(secrets.MapKeyring{"telegram": "7000123:telegram-secret"})
+	t.Cleanup(func() { secrets.SetKeyring(prev) })
+	t.Setenv("SLACK_WEBHOOK", "https://hooks.slack.com/T0/B0/xyz")
+	t.Setenv("TEAM_MODEL", "sonnet")
+
+	dir := t.TempDir()
+	projectPath := writeFile(t, dir, "project", `NOTIFY_WEBHOOK=${SLACK_WEBHOOK}
+IMPL_MODEL=${TEAM_MODEL}
+NOTIFY_CHAT_ID=${keyring:telegram}
+HOOK_ON_EXIT=echo $${HOME} > exit.log
+`)
+	t.Setenv("RALPH_VAL_MODEL", "${TEAM_MODEL}")
+
+	settings, err := config.Resolve("", projectPath, "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []config.Setting{
+		{Key: "IMPL_MODEL", Value: "sonnet", Source: projectPath},
+		{Key: "VAL_MODEL", Value: "${TEAM_MODEL}", Source: "environment"},
+		{Key: "NOTIFY_WEBHOOK", Value: "https://hooks.slack.com/T0/B0/xyz", Source: projectPath, Secret: true},
+		{Key: "NOTIFY_CHAT_ID", Value: "7000123:telegram-secret", Source: projectPath, Secret: true},
+		{Key: "HOOK_ON_EXIT", Value: "echo ${HOME} > exit.log", Source: projectPath},
+	}, settings, "only config file values are expanded")
+	assert.Equal(t, secrets.Redacted, settings[2].Shown())
+	assert.Equal(t, "sonnet", settings[0].Shown())
+
+	cfg, err := config.LoadWithPrecedence("", projectPath, "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://hooks.slack.com/T0/B0/xyz", cfg.NotifyWebhook)
+	assert.Equal(t, []string{"https://hooks.slack.com/T0/B0/xyz", "7000123:telegram-secret"}, cfg.Secrets)
+}
+
+func TestResolveReportsUnexpandableReferences(t *testing.T) {
+	prev := secrets.SetKeyring(secrets.MapKeyring{})
+	t.Cleanup(func() { secrets.SetKeyring(prev) })
+	t.Setenv("BAD_WEBHOOK", "hooks.example.com/secret-path")
+
+	dir := t.TempDir()
+	projectPath := writeFile(t, dir, "project", `NOTIFY_WEBHOOK=${BAD_WEBHOOK}
+IMPL_MODEL=${RALPH_TEST_UNSET_VARIABLE}
+NOTIFY_CHAT_ID=${keyring:telegram}
+VAL_MODEL=${unclosed
+`)
+	_, err := config.Resolve("", projectPath, "", nil)
+	require.Error(t, err)
+	assert.Equal(t, "invalid configuration:\n"+
+		"  "+projectPath+": IMPL_MODEL: environment variable RALPH_TEST_UNSET_VARIABLE is not set\n"+
+		"  "+projectPath+": NOTIFY_CHAT_ID: keyring secret telegram: not found in the keyring\n"+
+		"  "+projectPath+": NOTIFY_WEBHOOK=[REDACTED]: must be an http or https URL\n"+
+		"  "+projectPath+": VAL_MODEL: unclosed ${ in value", err.Error())
+}
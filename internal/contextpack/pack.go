@@ -0,0 +1,85 @@
+package contextpack
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// excerptLines is the most lines taken from one file.
+	excerptLines = 60
+	// leadLines is how many lines before the first keyword match an
+	// excerpt starts.
+	leadLines = 10
+	// minExcerptBytes is the smallest remaining budget worth another file.
+	minExcerptBytes = 200
+)
+
+// Pack renders excerpts of files (relative to root) as markdown, in order,
+// until maxTokens (estimated at four bytes per token) is used up. Each
+// excerpt starts a little before the first line mentioning one of
+// keywords, or at the top of the file. Unreadable and binary files are
+// skipped. It also returns the files that were included.
+func Pack(root string, files, keywords []string, maxTokens int) (string, []string) {
+	remaining := maxTokens * 4
+	var b strings.Builder
+	var packed []string
+	for _, f := range files {
+		if remaining < minExcerptBytes {
+			break
+		}
+		data, err := os.ReadFile(filepath.Join(root, f))
+		if err != nil || len(data) == 0 || bytes.IndexByte(data, 0) >= 0 {
+			continue
+		}
+		excerpt := renderExcerpt(f, string(data), keywords, remaining)
+		if excerpt == "" {
+			continue
+		}
+		b.WriteString(excerpt)
+		packed = append(packed, f)
+		remaining -= len(excerpt)
+	}
+	return b.String(), packed
+}
+
+// renderExcerpt formats the excerpt of one file in at most limit bytes.
+func renderExcerpt(name, content string, keywords []string, limit int) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	start := 0
+	if i := firstMatch(lines, keywords); i > leadLines {
+		start = i - leadLines
+	}
+	end := min(start+excerptLines, len(lines))
+
+	fence := "```"
+	if strings.Contains(content, "```") {
+		fence = "````"
+	}
+	for end > start {
+		body := strings.Join(lines[start:end], "\n")
+		excerpt := fmt.Sprintf("### %s (lines %d-%d of %d)\n\n%s\n%s\n%s\n\n", name, start+1, end, len(lines), fence, body, fence)
+		if len(excerpt) <= limit {
+			return excerpt
+		}
+		// Drop lines in proportion to the overshoot and retry.
+		end -= max(1, (end-start)*(len(excerpt)-limit)/len(excerpt))
+	}
+	return ""
+}
+
+// firstMatch returns the index of the first line containing a keyword,
+// or -1.
+func firstMatch(lines, keywords []string) int {
+	for i, line := range lines {
+		for _, kw := range keywords {
+			if strings.Contains(line, kw) {
+				return i
+			}
+		}
+	}
+	return -1
+}
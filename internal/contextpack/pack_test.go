@@ -0,0 +1,57 @@
+package contextpack
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func numberedLines(n int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	return b.String()
+}
+
+func TestPack_StartsNearFirstKeyword(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "big.go", strings.Replace(numberedLines(200), "line 100\n", "func ValidateToken() {}\n", 1))
+
+	text, packed := Pack(root, []string{"big.go"}, []string{"ValidateToken"}, 10000)
+	assert.Equal(t, []string{"big.go"}, packed)
+	assert.Contains(t, text, "### big.go (lines 90-149 of 200)")
+	assert.Contains(t, text, "func ValidateToken() {}")
+	assert.NotContains(t, text, "line 89\n")
+}
+
+func TestPack_TopOfFileWithoutKeyword(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "small.md", "# Title\n\n```go\ncode\n```\n")
+
+	text, _ := Pack(root, []string{"small.md"}, nil, 10000)
+	assert.Contains(t, text, "### small.md (lines 1-5 of 5)\n\n````\n# Title")
+}
+
+func TestPack_RespectsBudget(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "a.go", numberedLines(60))
+	writeFile(t, root, "b.go", numberedLines(60))
+
+	text, packed := Pack(root, []string{"a.go", "b.go"}, nil, 150)
+	assert.LessOrEqual(t, len(text), 600)
+	assert.Equal(t, []string{"a.go"}, packed, "the second file no longer fits")
+	assert.Contains(t, text, "### a.go (lines 1-")
+}
+
+func TestPack_SkipsBinaryAndMissingFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "logo.png", "\x89PNG\x00\x01")
+	writeFile(t, root, "ok.go", "package ok\n")
+
+	text, packed := Pack(root, []string{"missing.go", "logo.png", "ok.go"}, nil, 1000)
+	assert.Equal(t, []string{"ok.go"}, packed)
+	assert.NotContains(t, text, "PNG")
+}
@@ -0,0 +1,178 @@
+// Package contextpack picks the files related to the pending tasks and packs
+// bounded excerpts of them into the implementation prompt, so the model
+// starts from the relevant code instead of rediscovering it.
+package contextpack
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxCandidates caps the files considered for a prompt; only the best
+// ranked ones fit in any realistic budget.
+const maxCandidates = 20
+
+var (
+	// pathRE matches path-like words in task text: a name with an
+	// extension, optionally preceded by directories.
+	pathRE = regexp.MustCompile("[A-Za-z0-9_.\\-/]*[A-Za-z0-9_\\-]\\.[A-Za-z0-9]{1,8}\\b")
+	// backtickRE matches `code` spans.
+	backtickRE = regexp.MustCompile("`([^`\\s]{3,})`")
+	// identRE matches identifiers that look like code: camelCase,
+	// PascalCase with an inner capital, or snake_case.
+	identRE = regexp.MustCompile(`\b([a-z]+[A-Z][A-Za-z0-9]*|[A-Z][a-z0-9]+[A-Z][A-Za-z0-9]*|[a-z0-9]+_[a-z0-9_]+)\b`)
+)
+
+// Mentions returns the files named in the task texts that exist under root,
+// in order of first mention.
+func Mentions(root string, texts []string) []string {
+	var files []string
+	seen := map[string]bool{}
+	for _, text := range texts {
+		for _, m := range pathRE.FindAllString(text, -1) {
+			rel := filepath.ToSlash(filepath.Clean(strings.TrimPrefix(m, "./")))
+			if seen[rel] || strings.HasPrefix(rel, "..") {
+				continue
+			}
+			if info, err := os.Stat(filepath.Join(root, rel)); err == nil && !info.IsDir() {
+				seen[rel] = true
+				files = append(files, rel)
+			}
+		}
+	}
+	return files
+}
+
+// Keywords returns the code-like terms of the task texts: `quoted` spans and
+// camelCase or snake_case identifiers.
+func Keywords(texts []string) []string {
+	var words []string
+	seen := map[string]bool{}
+	add := func(w string) {
+		if !seen[w] {
+			seen[w] = true
+			words = append(words, w)
+		}
+	}
+	for _, text := range texts {
+		for _, m := range backtickRE.FindAllStringSubmatch(text, -1) {
+			if !pathRE.MatchString(m[1]) {
+				add(m[1])
+			}
+		}
+		for _, m := range identRE.FindAllString(text, -1) {
+			add(m)
+		}
+	}
+	return words
+}
+
+// Changed returns the files modified or added in root's git working tree,
+// so later iterations see the work in progress. It returns nil outside a
+// git repository.
+func Changed(root string) []string {
+	var files []string
+	for _, args := range [][]string{
+		{"diff", "--name-only", "HEAD"},
+		{"ls-files", "--others", "--exclude-standard"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		out, err := cmd.Output()
+		if err != nil {
+			return nil
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				files = append(files, line)
+			}
+		}
+	}
+	return files
+}
+
+// Search returns the files under root containing any of keywords, those
+// matching the most keywords first. It uses ripgrep when installed and git
+// grep otherwise, and returns nil when neither can search root.
+func Search(root string, keywords []string) []string {
+	hits := map[string]int{}
+	for _, kw := range keywords {
+		for _, f := range searchFiles(root, kw) {
+			hits[filepath.ToSlash(f)]++
+		}
+	}
+	files := make([]string, 0, len(hits))
+	for f := range hits {
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if hits[files[i]] != hits[files[j]] {
+			return hits[files[i]] > hits[files[j]]
+		}
+		return files[i] < files[j]
+	})
+	return files
+}
+
+func searchFiles(root, keyword string) []string {
+	var cmd *exec.Cmd
+	if _, err := exec.LookPath("rg"); err == nil {
+		cmd = exec.Command("rg", "--files-with-matches", "--fixed-strings", "--", keyword)
+	} else {
+		cmd = exec.Command("git", "grep", "--untracked", "--files-with-matches", "--fixed-strings", "-e", keyword)
+	}
+	cmd.Dir = root
+	// Both tools exit 1 when nothing matches; any output is still usable.
+	out, _ := cmd.Output()
+	var files []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files
+}
+
+// Select returns the files related to the task texts, best first: files the
+// tasks mention, then files changed in the working tree, then files
+// containing the tasks' keywords. ignore lists files never to include,
+// such as the tasks file itself.
+func Select(root string, texts []string, ignore ...string) []string {
+	skip := map[string]bool{}
+	for _, f := range ignore {
+		skip[relPath(root, f)] = true
+	}
+
+	var files []string
+	seen := map[string]bool{}
+	for _, group := range [][]string{Mentions(root, texts), Changed(root), Search(root, Keywords(texts))} {
+		for _, f := range group {
+			if len(files) == maxCandidates {
+				return files
+			}
+			if seen[f] || skip[f] || strings.HasPrefix(f, ".ralph-loop/") {
+				continue
+			}
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// relPath returns path relative to root in slash form; path may be
+// absolute or already relative to root.
+func relPath(root, path string) string {
+	if filepath.IsAbs(path) {
+		if absRoot, err := filepath.Abs(root); err == nil {
+			if rel, err := filepath.Rel(absRoot, path); err == nil {
+				return filepath.ToSlash(rel)
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
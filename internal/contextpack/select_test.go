@@ -0,0 +1,88 @@
+package contextpack
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+// gitRepo creates a repository in a temp dir with files committed.
+func gitRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	for name, content := range files {
+		writeFile(t, root, name, content)
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.email=t@example.com", "-c", "user.name=t", "commit", "-q", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	return root
+}
+
+func TestMentions(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "internal/auth/login.go", "package auth\n")
+	writeFile(t, root, "README.md", "# x\n")
+
+	texts := []string{
+		"T001 Add rate limiting to internal/auth/login.go",
+		"T002 Document it in ./README.md and docs/missing.md, see ../outside.go",
+	}
+	assert.Equal(t, []string{"internal/auth/login.go", "README.md"}, Mentions(root, texts))
+}
+
+func TestKeywords(t *testing.T) {
+	texts := []string{"T001 Make `ValidateToken` reject expired tokens in parseClaims", "T002 Rename max_retries and update `config.go`"}
+	assert.Equal(t, []string{"ValidateToken", "parseClaims", "max_retries"}, Keywords(texts))
+}
+
+func TestChangedAndSearch(t *testing.T) {
+	root := gitRepo(t, map[string]string{
+		"auth.go":  "package auth\n\nfunc ValidateToken() {}\nfunc parseClaims() {}\n",
+		"user.go":  "package auth\n\nfunc parseClaims2() {}\n",
+		"other.go": "package auth\n",
+	})
+	writeFile(t, root, "other.go", "package auth\n\n// edited\n")
+	writeFile(t, root, "new.go", "package auth\n")
+
+	assert.Equal(t, []string{"other.go", "new.go"}, Changed(root))
+	assert.Equal(t, []string{"auth.go", "user.go"}, Search(root, []string{"ValidateToken", "parseClaims"}))
+}
+
+func TestChanged_NotARepository(t *testing.T) {
+	assert.Nil(t, Changed(t.TempDir()))
+}
+
+func TestSelect_RanksMentionsChangesThenSearchHits(t *testing.T) {
+	root := gitRepo(t, map[string]string{
+		"auth.go":   "func ValidateToken() {}\n",
+		"routes.go": "package main\n",
+		"tasks.md":  "- [ ] T001 Fix ValidateToken in routes.go\n",
+	})
+	writeFile(t, root, "auth_test.go", "func TestValidateToken() {}\n")
+	writeFile(t, root, ".ralph-loop/learnings.md", "ValidateToken\n")
+
+	files := Select(root, []string{"T001 Fix ValidateToken in routes.go"}, filepath.Join(root, "tasks.md"))
+	assert.Equal(t, []string{"routes.go", "auth_test.go", "auth.go"}, files)
+}
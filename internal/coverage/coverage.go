@@ -0,0 +1,117 @@
+// Package coverage reads the line coverage of a test coverage report, so
+// the run summary can show how much a session moved it.
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Read returns the coverage percentage, 0 to 100, of the report at path.
+// It understands Go cover profiles (go test -coverprofile), lcov
+// tracefiles, Cobertura XML, and a file holding just a percentage such as
+// "81.5%".
+func Read(path string) (float64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		return goProfile(trimmed)
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return cobertura(trimmed)
+	case bytes.Contains(trimmed, []byte("\nLF:")) || bytes.HasPrefix(trimmed, []byte("TN:")) || bytes.HasPrefix(trimmed, []byte("SF:")):
+		return lcov(trimmed)
+	}
+	v, err := strconv.ParseFloat(strings.TrimSuffix(string(trimmed), "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: unrecognized coverage report", path)
+	}
+	return v, nil
+}
+
+// goProfile computes the statement coverage of a Go cover profile, whose
+// lines read "file:start,end statements count". Blocks listed more than
+// once (from several packages' tests) count as covered if any run hit them.
+func goProfile(data []byte) (float64, error) {
+	type block struct{ stmts, hit int }
+	blocks := map[string]*block{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) != 3 || strings.HasPrefix(fields[0], "mode:") {
+			continue
+		}
+		stmts, err1 := strconv.Atoi(fields[1])
+		count, err2 := strconv.Atoi(fields[2])
+		if err1 != nil || err2 != nil {
+			return 0, fmt.Errorf("malformed cover profile line %q", sc.Text())
+		}
+		b, ok := blocks[fields[0]]
+		if !ok {
+			b = &block{stmts: stmts}
+			blocks[fields[0]] = b
+		}
+		b.hit = max(b.hit, count)
+	}
+	var total, covered int
+	for _, b := range blocks {
+		total += b.stmts
+		if b.hit > 0 {
+			covered += b.stmts
+		}
+	}
+	return percent(covered, total)
+}
+
+// lcov sums the LH (lines hit) and LF (lines found) records of an lcov
+// tracefile.
+func lcov(data []byte) (float64, error) {
+	var found, hit int
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || (key != "LF" && key != "LH") {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("malformed lcov line %q", line)
+		}
+		if key == "LF" {
+			found += n
+		} else {
+			hit += n
+		}
+	}
+	return percent(hit, found)
+}
+
+// cobertura reads the line-rate of a Cobertura XML report's root element.
+func cobertura(data []byte) (float64, error) {
+	var report struct {
+		XMLName  xml.Name
+		LineRate *float64 `xml:"line-rate,attr"`
+	}
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return 0, fmt.Errorf("parse Cobertura report: %w", err)
+	}
+	if report.LineRate == nil {
+		return 0, errors.New("Cobertura report has no line-rate")
+	}
+	return *report.LineRate * 100, nil
+}
+
+func percent(n, total int) (float64, error) {
+	if total == 0 {
+		return 0, errors.New("coverage report covers no lines")
+	}
+	return float64(n) * 100 / float64(total), nil
+}
@@ -0,0 +1,51 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeReport(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "coverage")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    float64
+	}{
+		{"go profile", "mode: set\na.go:1.1,3.2 3 1\na.go:5.1,6.2 1 0\nb.go:1.1,2.2 4 0\nb.go:1.1,2.2 4 2\n", 87.5},
+		{"lcov", "TN:\nSF:a.js\nLF:10\nLH:5\nend_of_record\nSF:b.js\nLF:10\nLH:10\nend_of_record\n", 75},
+		{"cobertura", `<?xml version="1.0"?>` + "\n" + `<coverage line-rate="0.625" branch-rate="0.5"><packages/></coverage>`, 62.5},
+		{"percentage", "81.5%\n", 81.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read(writeReport(t, tt.content))
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 0.001)
+		})
+	}
+}
+
+func TestRead_Errors(t *testing.T) {
+	_, err := Read(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+
+	_, err = Read(writeReport(t, "all good\n"))
+	assert.ErrorContains(t, err, "unrecognized coverage report")
+
+	_, err = Read(writeReport(t, "mode: set\n"))
+	assert.ErrorContains(t, err, "covers no lines")
+
+	_, err = Read(writeReport(t, "<coverage/>"))
+	assert.ErrorContains(t, err, "no line-rate")
+}
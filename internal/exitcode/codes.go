@@ -4,6 +4,8 @@
 // recognized by shell scripts and CI pipelines.
 package exitcode
 
+import "fmt"
+
 // Exit code constants matching the ralph-loop data model.
 const (
 	Success       = 0   // All tasks complete and validated
@@ -13,30 +15,90 @@ const (
 	Blocked       = 4   // All tasks blocked on external dependencies
 	TasksInvalid  = 5   // Tasks don't implement original plan
 	Inadmissible  = 6   // Inadmissible violation threshold exceeded
+	Budget        = 7   // --max-duration wall-clock budget exhausted
 	Interrupted   = 130 // SIGINT/SIGTERM received
 )
 
+// Explanation describes an exit code for `ralph-loop explain-exit`.
+type Explanation struct {
+	Code    int
+	Name    string
+	Meaning string
+	Remedy  string
+}
+
+// explanations lists every exit code in numeric order.
+var explanations = []Explanation{
+	{Success, "Success",
+		"All tasks are complete and the validator (and any cross-validation and final plan check) accepted them.",
+		"Nothing to do. Review the changes and open or merge the pull request."},
+	{Error, "Error",
+		"ralph-loop could not run: invalid arguments or configuration, a missing tasks file or AI CLI, a failed hook, or an unexpected failure.",
+		"Read the error printed above the exit, fix the configuration or environment, and run again (with --resume to keep the session)."},
+	{MaxIterations, "MaxIterations",
+		"The iteration limit was reached before the validator accepted the work.",
+		"Read the last feedback with --status, split or clarify the remaining tasks, then --resume with a higher --max-iterations."},
+	{Escalate, "Escalate",
+		"The validator, a protected-path change or `ralph-loop respond` asked for a human decision.",
+		"Read the escalation report in .ralph-loop, answer with `ralph-loop respond`, then --resume."},
+	{Blocked, "Blocked",
+		"Every remaining task is blocked on something outside the project, such as credentials or an external service.",
+		"Resolve the blockers named in the tasks file's ralph:blocked comments, remove the comments, then --resume."},
+	{TasksInvalid, "TasksInvalid",
+		"The tasks do not implement the original plan (tasks validation, or the final plan check).",
+		"Read the validator's findings, fix the tasks file or the plan, then start again."},
+	{Inadmissible, "Inadmissible",
+		"The implementer took inadmissible shortcuts, such as faked tests or removed checks, more often than MAX_INADMISSIBLE allows.",
+		"Review the flagged changes, revert them, tighten the tasks or policies, then --resume."},
+	{Budget, "Budget",
+		"The --max-duration wall-clock budget ran out before the work was done.",
+		"--resume, optionally with a larger --max-duration."},
+	{Interrupted, "Interrupted",
+		"ralph-loop received SIGINT or SIGTERM and saved its state.",
+		"--resume to continue where it stopped."},
+}
+
+// Explanations returns the explanation of every exit code in numeric order.
+func Explanations() []Explanation {
+	return append([]Explanation(nil), explanations...)
+}
+
+// Explain returns the explanation of code, reporting false for codes
+// ralph-loop does not use.
+func Explain(code int) (Explanation, bool) {
+	for _, e := range explanations {
+		if e.Code == code {
+			return e, true
+		}
+	}
+	return Explanation{}, false
+}
+
 // Name returns the human-readable name for the given exit code.
 // Unknown codes return "unknown".
 func Name(code int) string {
-	switch code {
-	case Success:
-		return "Success"
-	case Error:
-		return "Error"
-	case MaxIterations:
-		return "MaxIterations"
-	case Escalate:
-		return "Escalate"
-	case Blocked:
-		return "Blocked"
-	case TasksInvalid:
-		return "TasksInvalid"
-	case Inadmissible:
-		return "Inadmissible"
-	case Interrupted:
-		return "Interrupted"
-	default:
-		return "unknown"
+	if e, ok := Explain(code); ok {
+		return e.Name
+	}
+	return "unknown"
+}
+
+// Result is how a run ended: its exit code and why.
+type Result struct {
+	Code   int
+	Reason string
+}
+
+// Name returns the name of r's exit code.
+func (r Result) Name() string {
+	return Name(r.Code)
+}
+
+// String formats r as "Name (code): reason", or "Name (code)" without a
+// reason.
+func (r Result) String() string {
+	if r.Reason == "" {
+		return fmt.Sprintf("%s (%d)", r.Name(), r.Code)
 	}
+	return fmt.Sprintf("%s (%d): %s", r.Name(), r.Code, r.Reason)
 }
@@ -21,6 +21,7 @@ func TestExitCodeValues(t *testing.T) {
 		{"Blocked", exitcode.Blocked, 4},
 		{"TasksInvalid", exitcode.TasksInvalid, 5},
 		{"Inadmissible", exitcode.Inadmissible, 6},
+		{"Budget", exitcode.Budget, 7},
 		{"Interrupted", exitcode.Interrupted, 130},
 	}
 
@@ -43,6 +44,7 @@ func TestExitCodeNames(t *testing.T) {
 		{exitcode.Blocked, "Blocked"},
 		{exitcode.TasksInvalid, "TasksInvalid"},
 		{exitcode.Inadmissible, "Inadmissible"},
+		{exitcode.Budget, "Budget"},
 		{exitcode.Interrupted, "Interrupted"},
 	}
 
@@ -56,11 +58,11 @@ func TestExitCodeNames(t *testing.T) {
 func TestExitCodeNameUnknown(t *testing.T) {
 	assert.Equal(t, "unknown", exitcode.Name(99))
 	assert.Equal(t, "unknown", exitcode.Name(-1))
-	assert.Equal(t, "unknown", exitcode.Name(7))
+	assert.Equal(t, "unknown", exitcode.Name(8))
 }
 
-func TestAllEightCodesAreDefined(t *testing.T) {
-	// Verify all 8 codes are distinct values.
+func TestAllNineCodesAreDefined(t *testing.T) {
+	// Verify all 9 codes are distinct values.
 	codes := []int{
 		exitcode.Success,
 		exitcode.Error,
@@ -69,9 +71,10 @@ func TestAllEightCodesAreDefined(t *testing.T) {
 		exitcode.Blocked,
 		exitcode.TasksInvalid,
 		exitcode.Inadmissible,
+		exitcode.Budget,
 		exitcode.Interrupted,
 	}
-	assert.Len(t, codes, 8, "expected exactly 8 exit codes")
+	assert.Len(t, codes, 9, "expected exactly 9 exit codes")
 
 	seen := make(map[int]bool)
 	for _, c := range codes {
@@ -79,3 +82,29 @@ func TestAllEightCodesAreDefined(t *testing.T) {
 		seen[c] = true
 	}
 }
+
+func TestExplain(t *testing.T) {
+	e, ok := exitcode.Explain(exitcode.Inadmissible)
+	assert.True(t, ok)
+	assert.Equal(t, "Inadmissible", e.Name)
+	assert.Contains(t, e.Meaning, "MAX_INADMISSIBLE")
+	assert.NotEmpty(t, e.Remedy)
+
+	_, ok = exitcode.Explain(8)
+	assert.False(t, ok)
+}
+
+func TestExplanationsCoverEveryCode(t *testing.T) {
+	var codes []int
+	for _, e := range exitcode.Explanations() {
+		assert.NotEmpty(t, e.Meaning, e.Name)
+		assert.NotEmpty(t, e.Remedy, e.Name)
+		codes = append(codes, e.Code)
+	}
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 130}, codes)
+}
+
+func TestResultString(t *testing.T) {
+	assert.Equal(t, "Blocked (4)", exitcode.Result{Code: exitcode.Blocked}.String())
+	assert.Equal(t, "MaxIterations (2): reached 20 iterations", exitcode.Result{Code: exitcode.MaxIterations, Reason: "reached 20 iterations"}.String())
+}
@@ -0,0 +1,195 @@
+// Package extval implements the external validator protocol. An external
+// validator is a command (a script wrapping a linter, CodeRabbit, Qodana
+// or anything else) that judges an iteration next to the AI validator:
+// ralph-loop writes a Request as JSON to its stdin and reads a Response
+// as JSON from its stdout. Whatever it writes to stderr is only logged.
+package extval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/hooks"
+)
+
+// ProtocolVersion is the version of Request and Response.
+const ProtocolVersion = 1
+
+// Verdicts an external validator may return, least severe first.
+var Verdicts = []string{"COMPLETE", "NEEDS_MORE_WORK", "BLOCKED", "INADMISSIBLE", "ESCALATE"}
+
+var nameRE = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Validator is a configured external validator.
+type Validator struct {
+	Name    string
+	Command string
+}
+
+// Request is the iteration context sent to a validator on stdin.
+type Request struct {
+	Protocol  int    `json:"protocol"`
+	SessionID string `json:"session_id"`
+	Iteration int    `json:"iteration"`
+	TasksFile string `json:"tasks_file"`
+	WorkDir   string `json:"work_dir"`
+	// ImplementationOutput is the path of the implementer's output.
+	ImplementationOutput string `json:"implementation_output"`
+	// DiffBase is a git tree of the working tree before the iteration, so
+	// `git diff <diff_base>` shows its changes; "" outside a git
+	// repository.
+	DiffBase string `json:"diff_base,omitempty"`
+	// ChangedFiles are the files the iteration changed, relative to
+	// WorkDir, when DiffBase is set.
+	ChangedFiles []string `json:"changed_files,omitempty"`
+}
+
+// Response is a validator's judgement, read from its stdout.
+type Response struct {
+	Verdict  string    `json:"verdict"`
+	Feedback string    `json:"feedback,omitempty"`
+	Findings []Finding `json:"findings,omitempty"`
+}
+
+// Finding is one problem a validator reports.
+type Finding struct {
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (f Finding) String() string {
+	var b strings.Builder
+	if f.File != "" {
+		b.WriteString(f.File)
+		if f.Line > 0 {
+			fmt.Fprintf(&b, ":%d", f.Line)
+		}
+		b.WriteString(": ")
+	}
+	if f.Severity != "" {
+		fmt.Fprintf(&b, "[%s] ", f.Severity)
+	}
+	b.WriteString(f.Message)
+	return b.String()
+}
+
+// Parse parses EXTERNAL_VALIDATORS: ';'-separated NAME=COMMAND entries,
+// e.g. "semgrep=./scripts/semgrep-validate.sh;qodana=qodana-validate".
+func Parse(spec string) ([]Validator, error) {
+	var validators []Validator
+	seen := map[string]bool{}
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, command, ok := strings.Cut(entry, "=")
+		name, command = strings.TrimSpace(name), strings.TrimSpace(command)
+		if !ok || command == "" {
+			return nil, fmt.Errorf("%q: expected NAME=COMMAND", entry)
+		}
+		if !nameRE.MatchString(name) {
+			return nil, fmt.Errorf("%q: use lower case letters, digits, dashes and underscores", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%s is defined twice", name)
+		}
+		seen[name] = true
+		validators = append(validators, Validator{Name: name, Command: command})
+	}
+	return validators, nil
+}
+
+// Result is the outcome of running a validator.
+type Result struct {
+	Response Response
+	// Stdout and Stderr are what the validator wrote.
+	Stdout, Stderr string
+	Duration       time.Duration
+}
+
+// Run runs v in dir with req on stdin and returns its response. The
+// validator may exit nonzero as long as it writes a response; an error is
+// returned when it writes none, an invalid one, or does not finish before
+// ctx ends.
+func Run(ctx context.Context, v Validator, dir string, req Request) (Result, error) {
+	req.Protocol = ProtocolVersion
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("encode request: %w", err)
+	}
+
+	cmd := hooks.ShellCommand(ctx, v.Command)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), fmt.Sprintf("RALPH_VALIDATOR_PROTOCOL=%d", ProtocolVersion))
+	cmd.Stdin = bytes.NewReader(payload)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = 5 * time.Second
+
+	start := time.Now()
+	runErr := cmd.Run()
+	result := Result{Stdout: stdout.String(), Stderr: stderr.String(), Duration: time.Since(start)}
+	if ctx.Err() != nil {
+		return result, fmt.Errorf("%s: %w", v.Name, ctx.Err())
+	}
+	var exitErr *exec.ExitError
+	if runErr != nil && !errors.As(runErr, &exitErr) {
+		return result, fmt.Errorf("%s: %w", v.Name, runErr)
+	}
+
+	resp, err := ParseResponse(result.Stdout)
+	if err != nil {
+		if exitErr != nil {
+			return result, fmt.Errorf("%s exited with code %d: %w", v.Name, exitErr.ExitCode(), err)
+		}
+		return result, fmt.Errorf("%s: %w", v.Name, err)
+	}
+	result.Response = resp
+	return result, nil
+}
+
+// ParseResponse decodes a response: the last JSON object on stdout,
+// compact or indented, so validators may log progress before it.
+func ParseResponse(stdout string) (Response, error) {
+	lines := strings.Split(stdout, "\n")
+	err := errors.New("no JSON response on stdout")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if !strings.HasPrefix(strings.TrimSpace(lines[i]), "{") {
+			continue
+		}
+		var resp Response
+		if jerr := json.Unmarshal([]byte(strings.Join(lines[i:], "\n")), &resp); jerr != nil {
+			err = fmt.Errorf("invalid JSON response: %w", jerr)
+			continue
+		}
+		resp.Verdict = strings.ToUpper(strings.TrimSpace(resp.Verdict))
+		if Severity(resp.Verdict) < 0 {
+			return Response{}, fmt.Errorf("verdict %q is not one of %s", resp.Verdict, strings.Join(Verdicts, ", "))
+		}
+		return resp, nil
+	}
+	return Response{}, err
+}
+
+// Severity ranks a built-in verdict, higher being more severe; -1 for
+// any other verdict.
+func Severity(verdict string) int {
+	for i, v := range Verdicts {
+		if v == verdict {
+			return i
+		}
+	}
+	return -1
+}
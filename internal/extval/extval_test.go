@@ -0,0 +1,100 @@
+package extval
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	validators, err := Parse(" semgrep=./scripts/semgrep.sh --json ; qodana = qodana-validate ;")
+	require.NoError(t, err)
+	assert.Equal(t, []Validator{
+		{Name: "semgrep", Command: "./scripts/semgrep.sh --json"},
+		{Name: "qodana", Command: "qodana-validate"},
+	}, validators)
+
+	validators, err = Parse("")
+	require.NoError(t, err)
+	assert.Empty(t, validators)
+}
+
+func TestParse_Errors(t *testing.T) {
+	for spec, want := range map[string]string{
+		"semgrep":                 "expected NAME=COMMAND",
+		"semgrep=":                "expected NAME=COMMAND",
+		"Sem Grep=semgrep":        "use lower case letters",
+		"lint=golint;lint=vet ./": "lint is defined twice",
+	} {
+		_, err := Parse(spec)
+		assert.ErrorContains(t, err, want, spec)
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	resp, err := ParseResponse("scanning 12 files\n{\"verdict\":\"needs_more_work\",\"feedback\":\"2 findings\",\"findings\":[{\"file\":\"db.go\",\"line\":12,\"severity\":\"high\",\"message\":\"SQL injection\"}]}\n")
+	require.NoError(t, err)
+	assert.Equal(t, "NEEDS_MORE_WORK", resp.Verdict)
+	assert.Equal(t, "2 findings", resp.Feedback)
+	require.Len(t, resp.Findings, 1)
+	assert.Equal(t, "db.go:12: [high] SQL injection", resp.Findings[0].String())
+}
+
+func TestParseResponse_Indented(t *testing.T) {
+	resp, err := ParseResponse("{\n  \"verdict\": \"COMPLETE\",\n  \"findings\": [\n    {\"message\": \"style\"}\n  ]\n}\n")
+	require.NoError(t, err)
+	assert.Equal(t, "COMPLETE", resp.Verdict)
+	assert.Equal(t, "style", resp.Findings[0].String())
+}
+
+func TestParseResponse_Errors(t *testing.T) {
+	_, err := ParseResponse("all good\n")
+	assert.ErrorContains(t, err, "no JSON response on stdout")
+
+	_, err = ParseResponse("{\"verdict\": \"COMPLETE\"")
+	assert.ErrorContains(t, err, "invalid JSON response")
+
+	_, err = ParseResponse(`{"verdict": "LGTM"}`)
+	assert.ErrorContains(t, err, `verdict "LGTM" is not one of COMPLETE, NEEDS_MORE_WORK, BLOCKED, INADMISSIBLE, ESCALATE`)
+}
+
+func TestSeverity(t *testing.T) {
+	assert.Less(t, Severity("COMPLETE"), Severity("NEEDS_MORE_WORK"))
+	assert.Less(t, Severity("INADMISSIBLE"), Severity("ESCALATE"))
+	assert.Equal(t, -1, Severity("SECURITY_REVIEW_NEEDED"))
+}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	dir := t.TempDir()
+	v := Validator{Name: "lint", Command: `cat > request.json; echo "linting" >&2; echo '{"verdict":"NEEDS_MORE_WORK","feedback":"unused import"}'; exit 1`}
+
+	result, err := Run(context.Background(), v, dir, Request{Iteration: 2, DiffBase: "abc123", ChangedFiles: []string{"main.go"}})
+
+	require.NoError(t, err, "a nonzero exit with a response is not an error")
+	assert.Equal(t, "NEEDS_MORE_WORK", result.Response.Verdict)
+	assert.Equal(t, "linting\n", result.Stderr)
+	data, err := os.ReadFile(filepath.Join(dir, "request.json"))
+	require.NoError(t, err)
+	var req Request
+	require.NoError(t, json.Unmarshal(data, &req))
+	assert.Equal(t, ProtocolVersion, req.Protocol)
+	assert.Equal(t, 2, req.Iteration)
+	assert.Equal(t, []string{"main.go"}, req.ChangedFiles)
+}
+
+func TestRun_NoResponse(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	_, err := Run(context.Background(), Validator{Name: "lint", Command: "echo oops; exit 2"}, t.TempDir(), Request{})
+	assert.ErrorContains(t, err, "lint exited with code 2: no JSON response on stdout")
+}
@@ -0,0 +1,110 @@
+// Package gc finds what a project's state directory keeps that the session
+// no longer needs, for `ralph-loop gc` to remove: the iteration directories
+// left by earlier sessions, those beyond the retention policy
+// (--retain-iterations, --retain-mb) and the iterations rollback undid.
+//
+// The session state, the session outcomes, learnings and the project config
+// file are never offered.
+package gc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// Group is a kind of leftover, removed or kept as a whole.
+type Group struct {
+	// Title says what the paths are.
+	Title string
+	Paths []string
+	Bytes int64
+}
+
+// Find returns the leftovers in stateDir, the groups with nothing left
+// out. The retention policy r applies to the current session's
+// iterations. The caller holds the state lock.
+func Find(stateDir string, r state.Retention) ([]Group, error) {
+	iterations, err := state.IterationDirs(stateDir)
+	if err != nil {
+		return nil, err
+	}
+	s, err := state.LoadState(stateDir)
+	if err != nil {
+		if _, statErr := os.Stat(filepath.Join(stateDir, "current-state.json")); statErr == nil {
+			return nil, fmt.Errorf("read the session: %w", err)
+		}
+		s = nil
+	}
+
+	stale := Group{Title: "Iteration directories of earlier sessions"}
+	isStale := map[int]bool{}
+	for _, n := range iterations {
+		if s != nil && !fromEarlierSession(stateDir, n, s) {
+			continue
+		}
+		isStale[n] = true
+		stale.add(state.IterationDir(stateDir, n))
+	}
+
+	expired := Group{Title: "Iteration directories beyond the retention policy"}
+	if s != nil {
+		numbers, _, err := state.ExpiredIterations(stateDir, r, s.Iteration)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range numbers {
+			if !isStale[n] {
+				expired.add(state.IterationDir(stateDir, n))
+			}
+		}
+	}
+
+	undone := Group{Title: "Iterations undone by rollback"}
+	entries, err := os.ReadDir(stateDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), "rolled-back-") {
+			undone.add(filepath.Join(stateDir, e.Name()))
+		}
+	}
+
+	var groups []Group
+	for _, g := range []Group{stale, expired, undone} {
+		if len(g.Paths) > 0 {
+			groups = append(groups, g)
+		}
+	}
+	return groups, nil
+}
+
+// fromEarlierSession reports whether the directory of iteration n was
+// left by a session before s: its number is past s's iteration, or its
+// rollback point names another session.
+func fromEarlierSession(stateDir string, n int, s *state.SessionState) bool {
+	if n > s.Iteration {
+		return true
+	}
+	start, err := state.LoadIterationStart(state.IterationDir(stateDir, n))
+	return err == nil && start.Session.SessionID != "" && start.Session.SessionID != s.SessionID
+}
+
+func (g *Group) add(path string) {
+	g.Paths = append(g.Paths, path)
+	g.Bytes += state.DirSize(path)
+}
+
+// Remove deletes the paths of g.
+func (g Group) Remove() error {
+	for _, p := range g.Paths {
+		if err := os.RemoveAll(p); err != nil {
+			return fmt.Errorf("remove %s: %w", p, err)
+		}
+	}
+	return nil
+}
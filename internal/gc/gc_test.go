@@ -0,0 +1,94 @@
+package gc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// newStateDir creates a state directory whose session is at iteration 4,
+// with the directories of iterations 1 to 6: 1 and 6 left by an earlier
+// session, and the iterations an earlier rollback undid.
+func newStateDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, state.SaveState(&state.SessionState{SessionID: "ralph-2", Iteration: 4}, dir))
+	for n := 1; n <= 6; n++ {
+		iterDir := state.IterationDir(dir, n)
+		require.NoError(t, os.MkdirAll(iterDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(iterDir, "implementation-output.txt"), []byte("done"), 0644))
+		session := "ralph-2"
+		if n == 1 || n > 4 {
+			session = "ralph-1"
+		}
+		require.NoError(t, state.SaveIterationStart(iterDir, state.IterationStart{Session: &state.SessionState{SessionID: session, Iteration: n - 1}}))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "rolled-back-20261016-100000", "iteration-005"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, state.SessionsDir), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config"), []byte("RETAIN_ITERATIONS=2\n"), 0644))
+	return dir
+}
+
+// names returns the base names of the paths of g.
+func names(g Group) []string {
+	var names []string
+	for _, p := range g.Paths {
+		names = append(names, filepath.Base(p))
+	}
+	return names
+}
+
+func TestFind(t *testing.T) {
+	dir := newStateDir(t)
+
+	groups, err := Find(dir, state.Retention{Keep: 2})
+	require.NoError(t, err)
+	require.Len(t, groups, 3)
+	assert.Equal(t, "Iteration directories of earlier sessions", groups[0].Title)
+	assert.Equal(t, []string{"iteration-001", "iteration-005", "iteration-006"}, names(groups[0]))
+	assert.Positive(t, groups[0].Bytes)
+	assert.Equal(t, "Iteration directories beyond the retention policy", groups[1].Title)
+	assert.Equal(t, []string{"iteration-002"}, names(groups[1]))
+	assert.Equal(t, []string{"rolled-back-20261016-100000"}, names(groups[2]))
+
+	for _, g := range groups {
+		require.NoError(t, g.Remove())
+	}
+	iterations, err := state.IterationDirs(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4}, iterations)
+	for _, name := range []string{"current-state.json", state.SessionsDir, "config"} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		assert.NoError(t, err, "the session is kept")
+	}
+
+	groups, err = Find(dir, state.Retention{Keep: 2})
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+}
+
+func TestFind_WithoutPolicy(t *testing.T) {
+	groups, err := Find(newStateDir(t), state.Retention{})
+	require.NoError(t, err)
+	require.Len(t, groups, 2, "without a policy the session keeps every iteration")
+	assert.Equal(t, "Iterations undone by rollback", groups[1].Title)
+}
+
+func TestFind_WithoutSession(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(state.IterationDir(dir, 1), 0755))
+
+	groups, err := Find(dir, state.Retention{Keep: 5})
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, []string{"iteration-001"}, names(groups[0]))
+
+	groups, err = Find(filepath.Join(dir, "missing"), state.Retention{})
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+}
@@ -0,0 +1,210 @@
+// Package gitdiff snapshots a git working tree and diffs snapshots, so the
+// changes made during one iteration can be shown to the validator whether
+// or not they were committed.
+package gitdiff
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Snapshot records the working tree of the repository at dir, including
+// untracked files that are not ignored, as a git tree object and returns
+// its hash. Changes under the exclude directories (relative to dir) are left
+// out. The repository's index and HEAD are left untouched.
+func Snapshot(dir string, exclude ...string) (string, error) {
+	indexPath, err := git(dir, nil, "rev-parse", "--git-path", "index")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(indexPath) {
+		indexPath = filepath.Join(dir, indexPath)
+	}
+
+	tmp, err := os.CreateTemp("", "ralph-loop-index-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+	// Starting from a copy of the real index reuses its stat cache, so only
+	// modified files are hashed.
+	if data, err := os.ReadFile(indexPath); err == nil {
+		_, err = tmp.Write(data)
+		if err != nil {
+			tmp.Close()
+			return "", fmt.Errorf("failed to copy index: %w", err)
+		}
+	}
+	tmp.Close()
+
+	env := []string{"GIT_INDEX_FILE=" + tmpPath}
+	args := []string{"add", "--all"}
+	if len(exclude) > 0 {
+		args = append(append(args, "--", ":/"), excludeSpecs(exclude)...)
+	}
+	if _, err := git(dir, env, args...); err != nil {
+		return "", err
+	}
+	return git(dir, env, "write-tree")
+}
+
+// Diff returns the unified diff and the --stat summary between two
+// snapshots, ignoring paths under the excluded directories (relative to
+// dir).
+func Diff(dir, from, to string, exclude ...string) (patch, stat string, err error) {
+	args := append([]string{from, to, "--", "."}, excludeSpecs(exclude)...)
+	if patch, err = git(dir, nil, append([]string{"diff", "--no-color", "--no-ext-diff"}, args...)...); err != nil {
+		return "", "", err
+	}
+	if stat, err = git(dir, nil, append([]string{"diff", "--no-color", "--stat"}, args...)...); err != nil {
+		return "", "", err
+	}
+	return patch, stat, nil
+}
+
+// Changed returns the paths (relative to dir) that differ between two
+// snapshots, ignoring paths under the excluded directories.
+func Changed(dir, from, to string, exclude ...string) ([]string, error) {
+	args := append([]string{"diff", "--name-only", "-z", "--relative", "--no-renames", from, to, "--", "."}, excludeSpecs(exclude)...)
+	out, err := git(dir, nil, args...)
+	if err != nil {
+		return nil, err
+	}
+	return splitNUL(out), nil
+}
+
+// FileStat is the number of lines one file gained and lost between two
+// snapshots. Binary files have no line counts.
+type FileStat struct {
+	Path    string
+	Added   int
+	Deleted int
+	Binary  bool
+}
+
+// NumStat returns the lines added and deleted in each path (relative to
+// dir) that differs between two snapshots, ignoring paths under the
+// excluded directories.
+func NumStat(dir, from, to string, exclude ...string) ([]FileStat, error) {
+	args := append([]string{"diff", "--numstat", "-z", "--relative", "--no-renames", from, to, "--", "."}, excludeSpecs(exclude)...)
+	out, err := git(dir, nil, args...)
+	if err != nil {
+		return nil, err
+	}
+	var stats []FileStat
+	for _, record := range splitNUL(out) {
+		fields := strings.SplitN(record, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		st := FileStat{Path: fields[2]}
+		if fields[0] == "-" && fields[1] == "-" {
+			st.Binary = true
+		} else {
+			st.Added, _ = strconv.Atoi(fields[0])
+			st.Deleted, _ = strconv.Atoi(fields[1])
+		}
+		stats = append(stats, st)
+	}
+	return stats, nil
+}
+
+// Restore puts files (relative to dir) back to their content in the
+// snapshot tree: files the snapshot holds are checked out from it, the
+// others are deleted. The repository's index and HEAD are left untouched.
+func Restore(dir, tree string, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+	tmpDir, err := os.MkdirTemp("", "ralph-loop-restore-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(tmpDir, "index")}
+	if _, err := git(dir, env, "read-tree", tree); err != nil {
+		return err
+	}
+
+	args := []string{"ls-files", "-z", "--"}
+	for _, f := range files {
+		args = append(args, ":(literal)"+f)
+	}
+	out, err := git(dir, env, args...)
+	if err != nil {
+		return err
+	}
+	restore := splitNUL(out)
+	inTree := map[string]bool{}
+	for _, f := range restore {
+		inTree[f] = true
+	}
+	for _, f := range files {
+		if inTree[f] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, f)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if len(restore) == 0 {
+		return nil
+	}
+	_, err = git(dir, env, append([]string{"checkout-index", "--force", "--"}, restore...)...)
+	return err
+}
+
+// Truncate cuts diff to at most maxBytes at a line boundary and returns the
+// kept part and the number of bytes omitted.
+func Truncate(diff string, maxBytes int) (string, int) {
+	if len(diff) <= maxBytes {
+		return diff, 0
+	}
+	kept := diff[:maxBytes]
+	if i := strings.LastIndex(kept, "\n"); i >= 0 {
+		kept = kept[:i+1]
+	}
+	return kept, len(diff) - len(kept)
+}
+
+// excludeSpecs returns the pathspecs excluding dirs.
+func excludeSpecs(dirs []string) []string {
+	var specs []string
+	for _, d := range dirs {
+		specs = append(specs, ":(exclude)"+filepath.ToSlash(d))
+	}
+	return specs
+}
+
+// splitNUL splits the output of a -z git command.
+func splitNUL(out string) []string {
+	var paths []string
+	for _, p := range strings.Split(out, "\x00") {
+		if p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// git runs a git command in dir and returns its trimmed output.
+func git(dir string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
@@ -0,0 +1,173 @@
+package gitdiff
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644))
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.email=t@example.com", "-c", "user.name=t", "commit", "-q", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	return dir
+}
+
+func TestSnapshotAndDiff(t *testing.T) {
+	dir := gitRepo(t)
+	before, err := Snapshot(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.go"), []byte("package main\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".ralph-loop"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".ralph-loop", "state.json"), []byte("{}"), 0644))
+
+	after, err := Snapshot(dir)
+	require.NoError(t, err)
+	assert.NotEqual(t, before, after)
+
+	patch, stat, err := Diff(dir, before, after, ".ralph-loop")
+	require.NoError(t, err)
+	assert.Contains(t, patch, "+func main() {}")
+	assert.Contains(t, patch, "new file mode")
+	assert.NotContains(t, patch, "state.json")
+	assert.Contains(t, stat, "2 files changed")
+
+	out, err := exec.Command("git", "-C", dir, "status", "--porcelain").Output()
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "?? new.go", "the real index is untouched")
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	dir := gitRepo(t)
+	before, err := Snapshot(dir)
+	require.NoError(t, err)
+	after, err := Snapshot(dir)
+	require.NoError(t, err)
+
+	patch, stat, err := Diff(dir, before, after)
+	require.NoError(t, err)
+	assert.Empty(t, patch)
+	assert.Empty(t, stat)
+}
+
+func TestSnapshot_Exclude(t *testing.T) {
+	dir := gitRepo(t)
+	before, err := Snapshot(dir, ".ralph-loop")
+	require.NoError(t, err)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".ralph-loop"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".ralph-loop", "state.json"), []byte("{}"), 0644))
+	after, err := Snapshot(dir, ".ralph-loop")
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.go"), []byte("package main\n"), 0644))
+	after, err = Snapshot(dir, ".ralph-loop")
+	require.NoError(t, err)
+	assert.NotEqual(t, before, after)
+}
+
+func TestSnapshot_NotARepository(t *testing.T) {
+	_, err := Snapshot(t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestTruncate(t *testing.T) {
+	diff := strings.Repeat("+line\n", 10)
+	kept, omitted := Truncate(diff, 100)
+	assert.Equal(t, diff, kept)
+	assert.Zero(t, omitted)
+
+	kept, omitted = Truncate(diff, 20)
+	assert.Equal(t, "+line\n+line\n+line\n", kept)
+	assert.Equal(t, len(diff)-18, omitted)
+}
+
+func TestChangedAndRestore(t *testing.T) {
+	dir := gitRepo(t)
+	write := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	write(".github/workflows/ci.yml", "on: push\n")
+	write("deploy.sh", "echo deploy\n")
+	before, err := Snapshot(dir)
+	require.NoError(t, err)
+
+	write(".github/workflows/ci.yml", "on: [push, pull_request]\n")
+	write(".github/workflows/release.yml", "on: tag\n")
+	require.NoError(t, os.Remove(filepath.Join(dir, "deploy.sh")))
+	write("main.go", "package main\n\nfunc main() {}\n")
+	write(".ralph-loop/state.json", "{}")
+	after, err := Snapshot(dir)
+	require.NoError(t, err)
+
+	changed, err := Changed(dir, before, after, ".ralph-loop")
+	require.NoError(t, err)
+	assert.Equal(t, []string{".github/workflows/ci.yml", ".github/workflows/release.yml", "deploy.sh", "main.go"}, changed)
+
+	require.NoError(t, Restore(dir, before, []string{".github/workflows/ci.yml", ".github/workflows/release.yml", "deploy.sh"}))
+	data, err := os.ReadFile(filepath.Join(dir, ".github/workflows/ci.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, "on: push\n", string(data))
+	assert.NoFileExists(t, filepath.Join(dir, ".github/workflows/release.yml"))
+	assert.FileExists(t, filepath.Join(dir, "deploy.sh"))
+
+	now, err := Snapshot(dir)
+	require.NoError(t, err)
+	changed, err = Changed(dir, before, now, ".ralph-loop")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main.go"}, changed, "only the unprotected change is left")
+
+	out, err := exec.Command("git", "-C", dir, "status", "--porcelain", "--untracked-files=no").Output()
+	require.NoError(t, err)
+	assert.Equal(t, " M main.go\n", string(out), "the real index is untouched")
+
+	assert.NoError(t, Restore(dir, before, nil))
+}
+
+func TestNumStat(t *testing.T) {
+	dir := gitRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.go"), []byte("one\ntwo\nthree\n"), 0644))
+	before, err := Snapshot(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.go"), []byte("one\n2\n3\nfour\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "logo.png"), []byte{0x89, 'P', 'N', 'G', 0, 0, 1}, 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".ralph-loop"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".ralph-loop", "state.json"), []byte("{}\n"), 0644))
+	after, err := Snapshot(dir)
+	require.NoError(t, err)
+
+	stats, err := NumStat(dir, before, after, ".ralph-loop")
+	require.NoError(t, err)
+	assert.Equal(t, []FileStat{
+		{Path: "a.go", Added: 3, Deleted: 2},
+		{Path: "logo.png", Binary: true},
+	}, stats)
+
+	stats, err = NumStat(dir, after, after)
+	require.NoError(t, err)
+	assert.Empty(t, stats)
+}
@@ -0,0 +1,72 @@
+package gitdiff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Workspace is the state of a git working tree that matters before
+// letting an agent edit it.
+type Workspace struct {
+	// Branch is the checked out branch, "" when HEAD is detached.
+	Branch string
+	// Changes are the uncommitted changes as "XY path" lines of
+	// git status --porcelain, untracked files included.
+	Changes []string
+	// Operation is the unfinished operation the repository is in:
+	// "rebase", "merge", "cherry-pick", "revert", "bisect" or "".
+	Operation string
+}
+
+// operations maps the files git keeps during an unfinished operation to
+// the operation, in the order they are checked.
+var operations = []struct{ file, name string }{
+	{"rebase-merge", "rebase"},
+	{"rebase-apply", "rebase"},
+	{"MERGE_HEAD", "merge"},
+	{"CHERRY_PICK_HEAD", "cherry-pick"},
+	{"REVERT_HEAD", "revert"},
+	{"BISECT_LOG", "bisect"},
+}
+
+// Inspect returns the state of the working tree at dir, ignoring changes
+// under the exclude directories (relative to dir). It fails when dir is
+// not in a git repository.
+func Inspect(dir string, exclude ...string) (Workspace, error) {
+	var ws Workspace
+	if _, err := git(dir, nil, "rev-parse", "--git-dir"); err != nil {
+		return ws, err
+	}
+	// symbolic-ref fails on a detached HEAD, which has no branch.
+	ws.Branch, _ = git(dir, nil, "symbolic-ref", "--quiet", "--short", "HEAD")
+
+	args := append([]string{"status", "--porcelain", "-z", "--no-renames", "--untracked-files=all", "--", "."}, excludeSpecs(exclude)...)
+	out, err := git(dir, nil, args...)
+	if err != nil {
+		return ws, err
+	}
+	ws.Changes = splitNUL(out)
+
+	args = []string{"rev-parse"}
+	for _, op := range operations {
+		args = append(args, "--git-path", op.file)
+	}
+	out, err = git(dir, nil, args...)
+	if err != nil {
+		return ws, err
+	}
+	for i, p := range strings.Split(out, "\n") {
+		if i >= len(operations) {
+			break
+		}
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, p)
+		}
+		if _, err := os.Stat(p); err == nil {
+			ws.Operation = operations[i].name
+			break
+		}
+	}
+	return ws, nil
+}
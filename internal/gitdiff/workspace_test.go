@@ -0,0 +1,78 @@
+package gitdiff
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.email=t@example.com", "-c", "user.name=t"}, args...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestInspect_Clean(t *testing.T) {
+	dir := gitRepo(t)
+	runGit(t, dir, "checkout", "-q", "-b", "feature")
+
+	ws, err := Inspect(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "feature", ws.Branch)
+	assert.Empty(t, ws.Changes)
+	assert.Empty(t, ws.Operation)
+}
+
+func TestInspect_Changes(t *testing.T) {
+	dir := gitRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("todo\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".ralph-loop"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".ralph-loop", "state.json"), []byte("{}"), 0644))
+
+	ws, err := Inspect(dir, ".ralph-loop")
+	require.NoError(t, err)
+	assert.Equal(t, []string{" M main.go", "?? notes.txt"}, ws.Changes)
+}
+
+func TestInspect_DetachedHead(t *testing.T) {
+	dir := gitRepo(t)
+	runGit(t, dir, "checkout", "-q", "--detach")
+
+	ws, err := Inspect(dir)
+	require.NoError(t, err)
+	assert.Empty(t, ws.Branch)
+}
+
+func TestInspect_Merge(t *testing.T) {
+	dir := gitRepo(t)
+	runGit(t, dir, "checkout", "-q", "-b", "other")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package other\n"), 0644))
+	runGit(t, dir, "commit", "-q", "-am", "other")
+	runGit(t, dir, "checkout", "-q", "-")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package mine\n"), 0644))
+	runGit(t, dir, "commit", "-q", "-am", "mine")
+
+	cmd := exec.Command("git", "-c", "user.email=t@example.com", "-c", "user.name=t", "merge", "-q", "other")
+	cmd.Dir = dir
+	require.Error(t, cmd.Run(), "the merge must conflict")
+
+	ws, err := Inspect(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "merge", ws.Operation)
+	assert.NotEmpty(t, ws.Changes)
+}
+
+func TestInspect_NotARepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	_, err := Inspect(t.TempDir())
+	assert.Error(t, err)
+}
@@ -0,0 +1,20 @@
+package gitdiff
+
+// AddWorktree checks the commit at HEAD of the repository in dir out into
+// a new detached worktree at path, so a run there cannot touch dir.
+func AddWorktree(dir, path string) error {
+	_, err := git(dir, nil, "worktree", "add", "--detach", "--quiet", path, "HEAD")
+	return err
+}
+
+// RemoveWorktree deletes the worktree at path, with any changes made in
+// it, and forgets it in the repository in dir.
+func RemoveWorktree(dir, path string) error {
+	_, err := git(dir, nil, "worktree", "remove", "--force", path)
+	return err
+}
+
+// TopLevel returns the root of the working tree containing dir.
+func TopLevel(dir string) (string, error) {
+	return git(dir, nil, "rev-parse", "--show-toplevel")
+}
@@ -0,0 +1,38 @@
+package gitdiff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorktree(t *testing.T) {
+	dir := gitRepo(t)
+	wt := filepath.Join(t.TempDir(), "wt")
+
+	require.NoError(t, AddWorktree(dir, wt))
+	data, err := os.ReadFile(filepath.Join(wt, "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n", string(data))
+
+	top, err := TopLevel(filepath.Join(wt))
+	require.NoError(t, err)
+	want, _ := filepath.EvalSymlinks(wt)
+	got, _ := filepath.EvalSymlinks(top)
+	assert.Equal(t, want, got)
+
+	require.NoError(t, os.WriteFile(filepath.Join(wt, "main.go"), []byte("package changed\n"), 0644))
+	require.NoError(t, RemoveWorktree(dir, wt))
+	assert.NoDirExists(t, wt)
+	data, err = os.ReadFile(filepath.Join(dir, "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n", string(data), "the repository is untouched")
+}
+
+func TestTopLevel_NotARepository(t *testing.T) {
+	_, err := TopLevel(t.TempDir())
+	assert.Error(t, err)
+}
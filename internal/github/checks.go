@@ -0,0 +1,178 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Check run statuses and conclusions.
+const (
+	CheckInProgress = "in_progress"
+	CheckCompleted  = "completed"
+
+	ConclusionSuccess   = "success"
+	ConclusionFailure   = "failure"
+	ConclusionCancelled = "cancelled"
+	ConclusionTimedOut  = "timed_out"
+)
+
+// MaxAnnotations is the number of annotations GitHub accepts in one check
+// run update.
+const MaxAnnotations = 50
+
+// maxCheckText caps, in bytes, the summary of a check run output.
+const maxCheckText = 65535
+
+// Annotation levels.
+const (
+	AnnotationNotice  = "notice"
+	AnnotationWarning = "warning"
+	AnnotationFailure = "failure"
+)
+
+// Annotation marks a line of a file in a check run.
+type Annotation struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Level     string `json:"annotation_level"`
+	Message   string `json:"message"`
+}
+
+// CheckOutput is the title, markdown summary and annotations shown on a
+// check run.
+type CheckOutput struct {
+	Title       string       `json:"title"`
+	Summary     string       `json:"summary"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+// CheckRunUpdate is a change to a check run. Conclusion is set together
+// with Status CheckCompleted.
+type CheckRunUpdate struct {
+	Status     string       `json:"status,omitempty"`
+	Conclusion string       `json:"conclusion,omitempty"`
+	Output     *CheckOutput `json:"output,omitempty"`
+}
+
+// checkRun is the part of a check run the client reads.
+type checkRun struct {
+	ID int64 `json:"id"`
+}
+
+// CreateCheckRun creates an in-progress check run called name on the
+// commit headSHA and returns its ID.
+func (c *Client) CreateCheckRun(name, headSHA string) (int64, error) {
+	var run checkRun
+	err := c.do(http.MethodPost, "check-runs", map[string]string{
+		"name":       name,
+		"head_sha":   headSHA,
+		"status":     CheckInProgress,
+		"started_at": time.Now().UTC().Format(time.RFC3339),
+	}, &run)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create check run: %w", err)
+	}
+	return run.ID, nil
+}
+
+// UpdateCheckRun applies u to the check run id. Annotations beyond
+// MaxAnnotations and text beyond GitHub's limit are dropped.
+func (c *Client) UpdateCheckRun(id int64, u CheckRunUpdate) error {
+	if u.Output != nil {
+		out := *u.Output
+		if len(out.Annotations) > MaxAnnotations {
+			out.Annotations = out.Annotations[:MaxAnnotations]
+		}
+		out.Summary = truncate(out.Summary, maxCheckText)
+		u.Output = &out
+	}
+	var payload any = u
+	if u.Status == CheckCompleted {
+		payload = struct {
+			CheckRunUpdate
+			CompletedAt string `json:"completed_at"`
+		}{u, time.Now().UTC().Format(time.RFC3339)}
+	}
+	if err := c.do(http.MethodPatch, fmt.Sprintf("check-runs/%d", id), payload, nil); err != nil {
+		return fmt.Errorf("failed to update check run %d: %w", id, err)
+	}
+	return nil
+}
+
+// truncate cuts s to at most n bytes on a line boundary.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[:n]
+	if i := strings.LastIndexByte(s, '\n'); i > 0 {
+		s = s[:i]
+	}
+	return strings.ToValidUTF8(s, "")
+}
+
+// HeadSHA returns the commit a check run belongs on: the head of the pull
+// request a GitHub Actions run was triggered for (GITHUB_SHA is the
+// merge commit there), else GITHUB_SHA, else HEAD of the repository in
+// the current directory.
+func HeadSHA() (string, error) {
+	if path := os.Getenv("GITHUB_EVENT_PATH"); path != "" {
+		var event struct {
+			PullRequest struct {
+				Head struct {
+					SHA string `json:"sha"`
+				} `json:"head"`
+			} `json:"pull_request"`
+		}
+		if data, err := os.ReadFile(path); err == nil && json.Unmarshal(data, &event) == nil && event.PullRequest.Head.SHA != "" {
+			return event.PullRequest.Head.SHA, nil
+		}
+	}
+	if sha := os.Getenv("GITHUB_SHA"); sha != "" {
+		return sha, nil
+	}
+	out, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse HEAD: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// fileLinePattern matches a "path/to/file.ext:line" reference, optionally
+// followed by ":column".
+var fileLinePattern = regexp.MustCompile(`(?:^|[\s(\[` + "`" + `'"])((?:\./)?(?:[\w.-]+/)*[\w-][\w.-]*\.[A-Za-z0-9]+):(\d+)(?::\d+)?`)
+
+// ParseAnnotations turns the lines of text that reference a file and line,
+// such as "internal/api/handler.go:42: error is ignored", into annotations
+// at level. Each line yields at most one annotation, for its first
+// reference; the message is the whole line.
+func ParseAnnotations(text, level string) []Annotation {
+	var annotations []Annotation
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		m := fileLinePattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		n, err := strconv.Atoi(m[2])
+		if err != nil || n <= 0 {
+			continue
+		}
+		annotations = append(annotations, Annotation{
+			Path:      strings.TrimPrefix(m[1], "./"),
+			StartLine: n,
+			EndLine:   n,
+			Level:     level,
+			Message:   strings.TrimLeft(line, "-*• "),
+		})
+	}
+	return annotations
+}
@@ -0,0 +1,107 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRun_CreateAndUpdate(t *testing.T) {
+	var calls []string
+	var bodies []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		bodies = append(bodies, body)
+		fmt.Fprint(w, `{"id": 77}`)
+	}))
+	defer srv.Close()
+	c := &Client{BaseURL: srv.URL, Token: "tok", Owner: "acme", Repo: "app"}
+
+	id, err := c.CreateCheckRun("ralph-loop", "abc123")
+	require.NoError(t, err)
+	assert.Equal(t, int64(77), id)
+
+	annotations := make([]Annotation, MaxAnnotations+5)
+	require.NoError(t, c.UpdateCheckRun(id, CheckRunUpdate{
+		Status: CheckInProgress,
+		Output: &CheckOutput{Title: "Iteration 1: NEEDS_MORE_WORK", Summary: "s", Annotations: annotations},
+	}))
+	require.NoError(t, c.UpdateCheckRun(id, CheckRunUpdate{Status: CheckCompleted, Conclusion: ConclusionSuccess}))
+
+	assert.Equal(t, []string{
+		"POST /repos/acme/app/check-runs",
+		"PATCH /repos/acme/app/check-runs/77",
+		"PATCH /repos/acme/app/check-runs/77",
+	}, calls)
+	assert.Equal(t, "abc123", bodies[0]["head_sha"])
+	assert.Equal(t, CheckInProgress, bodies[0]["status"])
+	assert.Len(t, bodies[1]["output"].(map[string]any)["annotations"], MaxAnnotations)
+	assert.NotContains(t, bodies[1], "completed_at")
+	assert.Equal(t, ConclusionSuccess, bodies[2]["conclusion"])
+	assert.Contains(t, bodies[2], "completed_at")
+	assert.NotContains(t, bodies[2], "output")
+}
+
+func TestCheckRun_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Resource not accessible by integration", http.StatusForbidden)
+	}))
+	defer srv.Close()
+	c := &Client{BaseURL: srv.URL, Token: "tok", Owner: "acme", Repo: "app"}
+
+	_, err := c.CreateCheckRun("ralph-loop", "abc123")
+	assert.ErrorContains(t, err, "failed to create check run")
+	assert.ErrorContains(t, err, "403 Forbidden")
+	assert.ErrorContains(t, c.UpdateCheckRun(5, CheckRunUpdate{Status: CheckInProgress}), "failed to update check run 5")
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", truncate("short", 10))
+	assert.Equal(t, "line one", truncate("line one\nline two", 12))
+	assert.Equal(t, "a", truncate("aé", 2), "a rune is not split")
+}
+
+func TestHeadSHA(t *testing.T) {
+	event := filepath.Join(t.TempDir(), "event.json")
+	require.NoError(t, os.WriteFile(event, []byte(`{"pull_request": {"head": {"sha": "headsha"}}}`), 0644))
+	t.Setenv("GITHUB_EVENT_PATH", event)
+	t.Setenv("GITHUB_SHA", "mergesha")
+	sha, err := HeadSHA()
+	require.NoError(t, err)
+	assert.Equal(t, "headsha", sha)
+
+	require.NoError(t, os.WriteFile(event, []byte(`{"ref": "refs/heads/main"}`), 0644))
+	sha, err = HeadSHA()
+	require.NoError(t, err)
+	assert.Equal(t, "mergesha", sha)
+}
+
+func TestParseAnnotations(t *testing.T) {
+	feedback := strings.Join([]string{
+		"The implementation is incomplete:",
+		"- internal/api/handler.go:42: the error from Decode is ignored",
+		"- `./web/app.ts:7:3` still logs the token",
+		"See https://example.com/docs for details.",
+		"Tests fail in pkg/store_test.go:0",
+		"README.md:12 and docs/guide.md:3 disagree",
+	}, "\n")
+
+	assert.Equal(t, []Annotation{
+		{Path: "internal/api/handler.go", StartLine: 42, EndLine: 42, Level: AnnotationWarning,
+			Message: "internal/api/handler.go:42: the error from Decode is ignored"},
+		{Path: "web/app.ts", StartLine: 7, EndLine: 7, Level: AnnotationWarning,
+			Message: "`./web/app.ts:7:3` still logs the token"},
+		{Path: "README.md", StartLine: 12, EndLine: 12, Level: AnnotationWarning,
+			Message: "README.md:12 and docs/guide.md:3 disagree"},
+	}, ParseAnnotations(feedback, AnnotationWarning))
+}
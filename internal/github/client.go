@@ -0,0 +1,162 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAPIURL is the REST API of github.com.
+const DefaultAPIURL = "https://api.github.com"
+
+// commentsPerPage is the page size used when listing comments.
+const commentsPerPage = 100
+
+// pullRefPattern matches the GITHUB_REF of a pull_request workflow run,
+// e.g. "refs/pull/42/merge".
+var pullRefPattern = regexp.MustCompile(`^refs/pull/(\d+)/`)
+
+// Client is a minimal GitHub REST API client for one repository. It is
+// used in CI, where GITHUB_TOKEN is set but the gh CLI may be missing or
+// unauthenticated.
+type Client struct {
+	BaseURL string // API root, e.g. DefaultAPIURL
+	Token   string
+	Owner   string
+	Repo    string
+	HTTP    *http.Client // nil means a client with a 30s timeout
+}
+
+// NewClientFromEnv returns a client for the repository of the current
+// GitHub Actions run, configured from GITHUB_TOKEN (or GH_TOKEN),
+// GITHUB_REPOSITORY and GITHUB_API_URL. It returns nil when the token or
+// the repository is not set.
+func NewClientFromEnv() *Client {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		token = os.Getenv("GH_TOKEN")
+	}
+	owner, repo, ok := strings.Cut(os.Getenv("GITHUB_REPOSITORY"), "/")
+	if token == "" || !ok || owner == "" || repo == "" {
+		return nil
+	}
+	baseURL := os.Getenv("GITHUB_API_URL")
+	if baseURL == "" {
+		baseURL = DefaultAPIURL
+	}
+	return &Client{BaseURL: baseURL, Token: token, Owner: owner, Repo: repo}
+}
+
+// PRFromEnv returns the number of the pull request a GitHub Actions run
+// was triggered for, read from GITHUB_REF, or 0 outside such a run.
+func PRFromEnv() int {
+	m := pullRefPattern.FindStringSubmatch(os.Getenv("GITHUB_REF"))
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
+
+// comment is the part of an issue comment the client reads.
+type comment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// UpsertPRComment creates or updates the ralph-loop summary comment on the
+// given pull request, like the package-level UpsertPRComment but over the
+// REST API.
+func (c *Client) UpsertPRComment(number int, body string) error {
+	if number <= 0 {
+		return fmt.Errorf("pull request number must be positive, got %d", number)
+	}
+	if !strings.Contains(body, SummaryMarker) {
+		body = SummaryMarker + "\n" + body
+	}
+
+	id, err := c.findSummaryComment(number)
+	if err != nil {
+		return fmt.Errorf("failed to list comments on PR #%d: %w", number, err)
+	}
+	payload := map[string]string{"body": body}
+	if id != 0 {
+		err = c.do(http.MethodPatch, fmt.Sprintf("issues/comments/%d", id), payload, nil)
+	} else {
+		err = c.do(http.MethodPost, fmt.Sprintf("issues/%d/comments", number), payload, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to post summary comment on PR #%d: %w", number, err)
+	}
+	return nil
+}
+
+// findSummaryComment returns the ID of the most recent comment on the pull
+// request that contains SummaryMarker, or 0 if there is none.
+func (c *Client) findSummaryComment(number int) (int64, error) {
+	var found int64
+	for page := 1; ; page++ {
+		var comments []comment
+		path := fmt.Sprintf("issues/%d/comments?per_page=%d&page=%d", number, commentsPerPage, page)
+		if err := c.do(http.MethodGet, path, nil, &comments); err != nil {
+			return 0, err
+		}
+		for _, cm := range comments {
+			if strings.Contains(cm.Body, SummaryMarker) {
+				found = cm.ID
+			}
+		}
+		if len(comments) < commentsPerPage {
+			return found, nil
+		}
+	}
+}
+
+// do sends a request to the repository endpoint path, with payload as the
+// JSON body unless nil, and decodes the JSON response into v unless nil.
+func (c *Client) do(method, path string, payload, v any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	url := fmt.Sprintf("%s/repos/%s/%s/%s", strings.TrimSuffix(c.BaseURL, "/"), c.Owner, c.Repo, path)
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := c.HTTP
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,113 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPI serves comments as the first page of any comment listing and
+// records every other request.
+type fakeAPI struct {
+	mu       sync.Mutex
+	comments []comment
+	calls    []string
+	bodies   []string
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.Header.Get("Authorization") != "Bearer tok" {
+		http.Error(w, "bad credentials", http.StatusUnauthorized)
+		return
+	}
+	if r.Method == http.MethodGet {
+		page := f.comments
+		if r.URL.Query().Get("page") != "1" {
+			page = nil
+		}
+		json.NewEncoder(w).Encode(page)
+		return
+	}
+	var payload map[string]string
+	json.NewDecoder(r.Body).Decode(&payload)
+	f.calls = append(f.calls, r.Method+" "+r.URL.Path)
+	f.bodies = append(f.bodies, payload["body"])
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprint(w, "{}")
+}
+
+func newTestClient(t *testing.T, api *fakeAPI) *Client {
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	return &Client{BaseURL: srv.URL, Token: "tok", Owner: "acme", Repo: "app"}
+}
+
+func TestClientUpsertPRComment_CreatesComment(t *testing.T) {
+	api := &fakeAPI{comments: []comment{{ID: 1, Body: "LGTM"}}}
+	c := newTestClient(t, api)
+
+	require.NoError(t, c.UpsertPRComment(7, "summary"))
+	assert.Equal(t, []string{"POST /repos/acme/app/issues/7/comments"}, api.calls)
+	assert.Equal(t, SummaryMarker+"\nsummary", api.bodies[0])
+}
+
+func TestClientUpsertPRComment_UpdatesLatestComment(t *testing.T) {
+	api := &fakeAPI{comments: []comment{
+		{ID: 101, Body: SummaryMarker + "\nold"},
+		{ID: 150, Body: "LGTM"},
+		{ID: 202, Body: SummaryMarker + "\nnewer"},
+	}}
+	c := newTestClient(t, api)
+
+	require.NoError(t, c.UpsertPRComment(7, SummaryMarker+"\nsummary"))
+	assert.Equal(t, []string{"PATCH /repos/acme/app/issues/comments/202"}, api.calls)
+	assert.Equal(t, SummaryMarker+"\nsummary", api.bodies[0])
+}
+
+func TestClientUpsertPRComment_Errors(t *testing.T) {
+	c := newTestClient(t, &fakeAPI{})
+	assert.ErrorContains(t, c.UpsertPRComment(0, "body"), "must be positive")
+
+	c.Token = "wrong"
+	err := c.UpsertPRComment(7, "body")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to list comments on PR #7")
+	assert.Contains(t, err.Error(), "401 Unauthorized")
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_REPOSITORY", "acme/app")
+	t.Setenv("GITHUB_API_URL", "")
+	assert.Nil(t, NewClientFromEnv(), "no token")
+
+	t.Setenv("GH_TOKEN", "tok")
+	assert.Equal(t, &Client{BaseURL: DefaultAPIURL, Token: "tok", Owner: "acme", Repo: "app"}, NewClientFromEnv())
+
+	t.Setenv("GITHUB_API_URL", "https://ghe.example.com/api/v3")
+	assert.Equal(t, "https://ghe.example.com/api/v3", NewClientFromEnv().BaseURL)
+
+	t.Setenv("GITHUB_REPOSITORY", "acme")
+	assert.Nil(t, NewClientFromEnv(), "no repository")
+}
+
+func TestPRFromEnv(t *testing.T) {
+	for ref, want := range map[string]int{
+		"refs/pull/42/merge": 42,
+		"refs/pull/7/head":   7,
+		"refs/heads/main":    0,
+		"":                   0,
+	} {
+		t.Setenv("GITHUB_REF", ref)
+		assert.Equal(t, want, PRFromEnv(), ref)
+	}
+}
@@ -1,4 +1,5 @@
-// Package github provides utilities for interacting with GitHub issues.
+// Package github provides utilities for interacting with GitHub issues and
+// pull requests.
 package github
 
 import (
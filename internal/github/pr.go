@@ -0,0 +1,99 @@
+package github
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// SummaryMarker is the hidden HTML comment that identifies the ralph-loop
+// summary comment on a pull request, so subsequent runs update it in place
+// instead of posting a new comment each time.
+const SummaryMarker = "<!-- ralph-loop-summary -->"
+
+// FindOpenPR returns the number of the open pull request for the current
+// branch, using `gh pr view`. Returns 0 with a nil error when the branch has
+// a pull request that is not open.
+//
+// Returns an error when gh is unavailable, unauthenticated, or the branch
+// has no pull request at all.
+func FindOpenPR() (int, error) {
+	cmd := exec.Command("gh", "pr", "view",
+		"--json", "number,state",
+		"--jq", `select(.state == "OPEN") | .number`)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return 0, fmt.Errorf("failed to detect pull request: %w\nOutput: %s", err, string(output))
+	}
+
+	trimmed := strings.TrimSpace(string(output))
+	if trimmed == "" {
+		return 0, nil
+	}
+
+	number, err := strconv.Atoi(trimmed)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected pull request number %q: %w", trimmed, err)
+	}
+	return number, nil
+}
+
+// UpsertPRComment creates or updates the ralph-loop summary comment on the
+// given pull request. The body is prefixed with SummaryMarker if it does not
+// already contain it. When a comment carrying the marker exists, the most
+// recent one is edited; otherwise a new comment is posted.
+//
+// The repository is inferred by gh from the current directory.
+func UpsertPRComment(number int, body string) error {
+	if number <= 0 {
+		return fmt.Errorf("pull request number must be positive, got %d", number)
+	}
+	if !strings.Contains(body, SummaryMarker) {
+		body = SummaryMarker + "\n" + body
+	}
+
+	commentID, err := findSummaryComment(number)
+	if err != nil {
+		return err
+	}
+
+	var args []string
+	if commentID != "" {
+		args = []string{"api", "--method", "PATCH",
+			fmt.Sprintf("repos/{owner}/{repo}/issues/comments/%s", commentID),
+			"-f", "body=" + body}
+	} else {
+		args = []string{"api", "--method", "POST",
+			fmt.Sprintf("repos/{owner}/{repo}/issues/%d/comments", number),
+			"-f", "body=" + body}
+	}
+
+	output, err := exec.Command("gh", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to post summary comment on PR #%d: %w\nOutput: %s",
+			number, err, string(output))
+	}
+	return nil
+}
+
+// findSummaryComment returns the ID of the most recent comment on the pull
+// request that contains SummaryMarker, or "" if there is none.
+func findSummaryComment(number int) (string, error) {
+	cmd := exec.Command("gh", "api", "--paginate",
+		fmt.Sprintf("repos/{owner}/{repo}/issues/%d/comments", number),
+		"--jq", fmt.Sprintf(`.[] | select(.body | contains(%q)) | .id`, SummaryMarker))
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to list comments on PR #%d: %w\nOutput: %s",
+			number, err, string(output))
+	}
+
+	lines := strings.Fields(string(output))
+	if len(lines) == 0 {
+		return "", nil
+	}
+	return lines[len(lines)-1], nil
+}
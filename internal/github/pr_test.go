@@ -0,0 +1,114 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// installFakeGh writes a fake gh script to a temp dir and puts it first on
+// PATH. It returns the path of a log file the script may append to.
+func installFakeGh(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script approach does not work on Windows")
+	}
+
+	tmpDir := t.TempDir()
+	logFile := filepath.Join(tmpDir, "calls.log")
+	fakeGh := filepath.Join(tmpDir, "gh")
+	content := "#!/bin/sh\nLOG=" + logFile + "\n" + script
+	require.NoError(t, os.WriteFile(fakeGh, []byte(content), 0755))
+
+	t.Setenv("PATH", tmpDir+":"+os.Getenv("PATH"))
+	return logFile
+}
+
+// fakeCommentsScript answers comment listings with listOutput and logs the
+// method and endpoint of every other gh api call.
+func fakeCommentsScript(listOutput string) string {
+	return "if [ \"$2\" = \"--paginate\" ]; then\n" +
+		"  printf '" + listOutput + "'\n" +
+		"  exit 0\n" +
+		"fi\n" +
+		"echo \"$3 $4\" >> \"$LOG\"\n"
+}
+
+func TestFindOpenPR_ReturnsNumber(t *testing.T) {
+	installFakeGh(t, "echo 42\n")
+
+	n, err := FindOpenPR()
+	require.NoError(t, err)
+	assert.Equal(t, 42, n)
+}
+
+func TestFindOpenPR_NotOpen(t *testing.T) {
+	installFakeGh(t, "exit 0\n")
+
+	n, err := FindOpenPR()
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestFindOpenPR_NoPullRequest(t *testing.T) {
+	installFakeGh(t, "echo 'no pull requests found for branch' >&2\nexit 1\n")
+
+	n, err := FindOpenPR()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to detect pull request")
+	assert.Equal(t, 0, n)
+}
+
+func TestFindOpenPR_UnexpectedOutput(t *testing.T) {
+	installFakeGh(t, "echo not-a-number\n")
+
+	_, err := FindOpenPR()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected pull request number")
+}
+
+func TestUpsertPRComment_InvalidNumber(t *testing.T) {
+	err := UpsertPRComment(0, "body")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be positive")
+}
+
+func TestUpsertPRComment_CreatesComment(t *testing.T) {
+	logFile := installFakeGh(t, fakeCommentsScript(""))
+
+	require.NoError(t, UpsertPRComment(7, "summary"))
+
+	data, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.Equal(t, "POST repos/{owner}/{repo}/issues/7/comments\n", string(data))
+}
+
+func TestUpsertPRComment_UpdatesLatestComment(t *testing.T) {
+	logFile := installFakeGh(t, fakeCommentsScript(`101\n202\n`))
+
+	require.NoError(t, UpsertPRComment(7, "summary"))
+
+	data, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.Equal(t, "PATCH repos/{owner}/{repo}/issues/comments/202\n", string(data))
+}
+
+func TestUpsertPRComment_ListFails(t *testing.T) {
+	installFakeGh(t, "echo 'HTTP 404' >&2\nexit 1\n")
+
+	err := UpsertPRComment(7, "summary")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to list comments on PR #7")
+}
+
+func TestUpsertPRComment_PostFails(t *testing.T) {
+	installFakeGh(t, "if [ \"$2\" = \"--paginate\" ]; then exit 0; fi\necho 'HTTP 403' >&2\nexit 1\n")
+
+	err := UpsertPRComment(7, "summary")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to post summary comment on PR #7")
+}
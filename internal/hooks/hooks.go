@@ -0,0 +1,107 @@
+// Package hooks runs the user's lifecycle hook commands: shell commands
+// or executables the loop starts at fixed points of an iteration, handing
+// them a JSON event on stdin. A hook that exits nonzero fails, which lets
+// custom gates (security scans, database resets) stop or redirect the
+// loop without changes to the orchestrator.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// Hook points.
+const (
+	PreIteration       = "pre-iteration"
+	PostImplementation = "post-implementation"
+	PostValidation     = "post-validation"
+	OnExit             = "on-exit"
+)
+
+// Event is the JSON document a hook reads on stdin.
+type Event struct {
+	Event     string `json:"event"`
+	Timestamp string `json:"timestamp"`
+	SessionID string `json:"session_id,omitempty"`
+	Iteration int    `json:"iteration,omitempty"`
+	TasksFile string `json:"tasks_file,omitempty"`
+	StateDir  string `json:"state_dir"`
+	WorkDir   string `json:"work_dir"`
+	// ImplementationOutput and ValidationOutput are the paths of the
+	// iteration's phase outputs, once written.
+	ImplementationOutput string `json:"implementation_output,omitempty"`
+	ValidationOutput     string `json:"validation_output,omitempty"`
+	// Verdict and Feedback are the validation result (post-validation).
+	Verdict  string `json:"verdict,omitempty"`
+	Feedback string `json:"feedback,omitempty"`
+	// ExitCode is the code ralph-loop exits with (on-exit).
+	ExitCode *int `json:"exit_code,omitempty"`
+	// ExitReason says why ralph-loop exits (on-exit).
+	ExitReason string `json:"exit_reason,omitempty"`
+}
+
+// Result is the outcome of a hook that ran.
+type Result struct {
+	// ExitCode is the hook's exit status; -1 when it was killed.
+	ExitCode int
+	// Output is the hook's stdout and stderr, interleaved.
+	Output   string
+	Duration time.Duration
+}
+
+// Failed reports whether the hook exited nonzero.
+func (r Result) Failed() bool {
+	return r.ExitCode != 0
+}
+
+// Run runs command through the shell in dir with event as JSON on stdin
+// and RALPH_HOOK_EVENT set to the hook point. A timeout above zero bounds
+// the run; the hook and everything it started are then killed. A nonzero
+// exit is reported in the Result; the error is for a hook that could not
+// be started or timed out.
+func Run(ctx context.Context, command, dir string, event Event, timeout time.Duration) (Result, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return Result{}, fmt.Errorf("encode %s event: %w", event.Event, err)
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd := ShellCommand(ctx, command)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "RALPH_HOOK_EVENT="+event.Event)
+	cmd.Stdin = bytes.NewReader(payload)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	cmd.WaitDelay = 5 * time.Second
+
+	start := time.Now()
+	err = cmd.Run()
+	result := Result{Output: out.String(), Duration: time.Since(start)}
+	if ctx.Err() != nil {
+		result.ExitCode = -1
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return result, fmt.Errorf("%s hook timed out after %s", event.Event, timeout)
+		}
+		return result, ctx.Err()
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		result.ExitCode = exitErr.ExitCode()
+		return result, nil
+	}
+	if err != nil {
+		return result, fmt.Errorf("start %s hook: %w", event.Event, err)
+	}
+	return result, nil
+}
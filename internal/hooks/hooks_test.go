@@ -0,0 +1,60 @@
+//go:build !windows
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_PassesEventOnStdin(t *testing.T) {
+	dir := t.TempDir()
+	event := Event{Event: PostValidation, Iteration: 3, Verdict: "COMPLETE", StateDir: ".ralph-loop", WorkDir: dir}
+
+	result, err := Run(context.Background(), `cat > event.json; echo "hook $RALPH_HOOK_EVENT"`, dir, event, time.Minute)
+
+	require.NoError(t, err)
+	assert.False(t, result.Failed())
+	assert.Equal(t, "hook post-validation\n", result.Output)
+	data, err := os.ReadFile(filepath.Join(dir, "event.json"))
+	require.NoError(t, err)
+	var got Event
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, event, got)
+}
+
+func TestRun_NonzeroExit(t *testing.T) {
+	result, err := Run(context.Background(), "echo 'found 2 issues' >&2; exit 3", t.TempDir(), Event{Event: PostImplementation}, time.Minute)
+
+	require.NoError(t, err)
+	assert.True(t, result.Failed())
+	assert.Equal(t, 3, result.ExitCode)
+	assert.Equal(t, "found 2 issues\n", result.Output)
+}
+
+func TestRun_Timeout(t *testing.T) {
+	start := time.Now()
+	result, err := Run(context.Background(), "sleep 30 & wait", t.TempDir(), Event{Event: PreIteration}, 200*time.Millisecond)
+
+	assert.ErrorContains(t, err, "pre-iteration hook timed out after 200ms")
+	assert.True(t, result.Failed())
+	assert.Less(t, time.Since(start), 10*time.Second, "the hook's children must be killed too")
+}
+
+func TestRun_ExitCodeInEvent(t *testing.T) {
+	dir := t.TempDir()
+	code := 2
+	_, err := Run(context.Background(), "cat > event.json", dir, Event{Event: OnExit, ExitCode: &code}, time.Minute)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "event.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"exit_code":2`)
+}
@@ -0,0 +1,39 @@
+package issues
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// GiteaProvider fetches issues from the Gitea (or Forgejo) REST API.
+type GiteaProvider struct {
+	BaseURL string // e.g. https://codeberg.org
+	Token   string // optional, needed for private repositories
+	Client  *http.Client
+}
+
+type giteaIssue struct {
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+// Fetch implements Provider.
+func (p *GiteaProvider) Fetch(ref Ref) (*Issue, error) {
+	if p.BaseURL == "" {
+		return nil, fmt.Errorf("Gitea issue %s: no server, pass the issue URL or set GITEA_URL", ref)
+	}
+	if ref.Project == "" {
+		return nil, fmt.Errorf("Gitea issue #%d: use owner/repo#%d or the issue URL", ref.Number, ref.Number)
+	}
+	headers := map[string]string{}
+	if p.Token != "" {
+		headers["Authorization"] = "token " + p.Token
+	}
+	var gi giteaIssue
+	endpoint := fmt.Sprintf("%s/api/v1/repos/%s/issues/%d", p.BaseURL, ref.Project, ref.Number)
+	if err := getJSON(p.Client, endpoint, headers, &gi); err != nil {
+		return nil, fmt.Errorf("failed to fetch Gitea issue %s: %w", ref, err)
+	}
+	return &Issue{Ref: ref, Title: gi.Title, Body: gi.Body, URL: gi.HTMLURL}, nil
+}
@@ -0,0 +1,45 @@
+package issues
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGiteaProvider_Fetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/repos/owner/repo/issues/3", r.URL.Path)
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"title":"Fix login","body":"Login fails.","html_url":"https://codeberg.org/owner/repo/issues/3"}`))
+	}))
+	defer srv.Close()
+
+	p := &GiteaProvider{BaseURL: srv.URL, Token: "secret"}
+	issue, err := p.Fetch(Ref{Provider: Gitea, Project: "owner/repo", Number: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "Fix login", issue.Title)
+	assert.Equal(t, "Login fails.", issue.Body)
+	assert.Equal(t, "https://codeberg.org/owner/repo/issues/3", issue.URL)
+}
+
+func TestGiteaProvider_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	_, err := (&GiteaProvider{BaseURL: srv.URL}).Fetch(Ref{Provider: Gitea, Project: "owner/repo", Number: 3})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+
+	_, err = (&GiteaProvider{}).Fetch(Ref{Provider: Gitea, Project: "owner/repo", Number: 3})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "GITEA_URL")
+
+	_, err = (&GiteaProvider{BaseURL: srv.URL}).Fetch(Ref{Provider: Gitea, Number: 3})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "owner/repo#3")
+}
@@ -0,0 +1,37 @@
+package issues
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/github"
+)
+
+// GitHubProvider fetches issues with the gh CLI.
+type GitHubProvider struct{}
+
+// Fetch implements Provider.
+func (p *GitHubProvider) Fetch(ref Ref) (*Issue, error) {
+	var owner, repo string
+	if ref.Project != "" {
+		owner, repo, _ = strings.Cut(ref.Project, "/")
+		if host := strings.TrimPrefix(strings.TrimPrefix(ref.Host, "https://"), "http://"); host != "" && host != "github.com" {
+			// gh accepts HOST/OWNER/REPO for GitHub Enterprise
+			owner = host + "/" + owner
+		}
+	}
+	content, err := github.FetchIssue(owner, repo, ref.Number)
+	if err != nil {
+		return nil, err
+	}
+	title, body, _ := strings.Cut(content, "\n")
+	issue := &Issue{Ref: ref, Title: strings.TrimSpace(title), Body: strings.TrimSpace(body)}
+	if ref.Project != "" {
+		host := ref.Host
+		if host == "" {
+			host = "https://github.com"
+		}
+		issue.URL = fmt.Sprintf("%s/%s/issues/%d", host, ref.Project, ref.Number)
+	}
+	return issue, nil
+}
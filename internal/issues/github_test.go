@@ -0,0 +1,34 @@
+package issues
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubProvider_Fetch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script approach does not work on Windows")
+	}
+	binDir := t.TempDir()
+	argsFile := filepath.Join(binDir, "args")
+	script := "#!/bin/sh\necho \"$@\" > " + argsFile + "\nprintf 'Issue Title\\n\\nIssue body'\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "gh"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+":"+os.Getenv("PATH"))
+
+	issue, err := (&GitHubProvider{}).Fetch(Ref{Provider: GitHub, Project: "owner/repo", Number: 12})
+	require.NoError(t, err)
+	assert.Equal(t, "Issue Title", issue.Title)
+	assert.Equal(t, "Issue body", issue.Body)
+	assert.Equal(t, "https://github.com/owner/repo/issues/12", issue.URL)
+
+	_, err = (&GitHubProvider{}).Fetch(Ref{Provider: GitHub, Host: "https://ghe.example.com", Project: "owner/repo", Number: 12})
+	require.NoError(t, err)
+	args, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(args), "--repo ghe.example.com/owner/repo")
+}
@@ -0,0 +1,62 @@
+package issues
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strconv"
+)
+
+// GitLabProvider fetches issues from the GitLab REST API when Token is set,
+// and with the glab CLI otherwise.
+type GitLabProvider struct {
+	BaseURL string // e.g. https://gitlab.com
+	Token   string
+	Client  *http.Client
+}
+
+type gitlabIssue struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	WebURL      string `json:"web_url"`
+}
+
+// Fetch implements Provider.
+func (p *GitLabProvider) Fetch(ref Ref) (*Issue, error) {
+	var gi gitlabIssue
+	if p.Token == "" {
+		if err := p.fetchWithGlab(ref, &gi); err != nil {
+			return nil, err
+		}
+	} else {
+		if ref.Project == "" {
+			return nil, fmt.Errorf("GitLab issue #%d: the API needs a project, use group/project#%d or the issue URL", ref.Number, ref.Number)
+		}
+		endpoint := fmt.Sprintf("%s/api/v4/projects/%s/issues/%d", p.BaseURL, url.PathEscape(ref.Project), ref.Number)
+		if err := getJSON(p.Client, endpoint, map[string]string{"PRIVATE-TOKEN": p.Token}, &gi); err != nil {
+			return nil, fmt.Errorf("failed to fetch GitLab issue %s: %w", ref, err)
+		}
+	}
+	return &Issue{Ref: ref, Title: gi.Title, Body: gi.Description, URL: gi.WebURL}, nil
+}
+
+func (p *GitLabProvider) fetchWithGlab(ref Ref, gi *gitlabIssue) error {
+	args := []string{"issue", "view", strconv.Itoa(ref.Number), "--output", "json"}
+	if ref.Project != "" {
+		repo := ref.Project
+		if ref.Host != "" {
+			repo = ref.Host + "/" + ref.Project
+		}
+		args = append(args, "--repo", repo)
+	}
+	output, err := exec.Command("glab", args...).Output()
+	if err != nil {
+		return fmt.Errorf("failed to fetch GitLab issue %s with glab: %w", ref, err)
+	}
+	if err := json.Unmarshal(output, gi); err != nil {
+		return fmt.Errorf("failed to parse glab output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package issues
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitLabProvider_FetchAPI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/projects/group%2Fsub%2Fproject/issues/7", r.URL.EscapedPath())
+		assert.Equal(t, "glpat", r.Header.Get("PRIVATE-TOKEN"))
+		_, _ = w.Write([]byte(`{"title":"Add a greeting","description":"Print hello.","web_url":"https://gitlab.com/group/sub/project/-/issues/7"}`))
+	}))
+	defer srv.Close()
+
+	p := &GitLabProvider{BaseURL: srv.URL, Token: "glpat"}
+	issue, err := p.Fetch(Ref{Provider: GitLab, Project: "group/sub/project", Number: 7})
+	require.NoError(t, err)
+	assert.Equal(t, "Add a greeting", issue.Title)
+	assert.Equal(t, "Print hello.", issue.Body)
+	assert.Equal(t, "https://gitlab.com/group/sub/project/-/issues/7", issue.URL)
+}
+
+func TestGitLabProvider_APINeedsProject(t *testing.T) {
+	_, err := (&GitLabProvider{BaseURL: "http://unused", Token: "glpat"}).Fetch(Ref{Provider: GitLab, Number: 7})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "needs a project")
+}
+
+func TestGitLabProvider_FetchGlab(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script approach does not work on Windows")
+	}
+	binDir := t.TempDir()
+	argsFile := filepath.Join(binDir, "args")
+	script := "#!/bin/sh\necho \"$@\" > " + argsFile + "\necho '{\"title\":\"From glab\",\"description\":\"Body\",\"web_url\":\"https://gitlab.com/g/p/-/issues/2\"}'\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "glab"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+":"+os.Getenv("PATH"))
+
+	issue, err := (&GitLabProvider{}).Fetch(Ref{Provider: GitLab, Host: "https://gitlab.com", Project: "g/p", Number: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "From glab", issue.Title)
+
+	args, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	assert.Equal(t, "issue view 2 --output json --repo https://gitlab.com/g/p\n", string(args))
+}
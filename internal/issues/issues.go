@@ -0,0 +1,203 @@
+// Package issues fetches the issue given to --github-issue (from GitHub,
+// GitLab or Gitea) or --jira-issue and normalizes it into a single markdown
+// document, which is cached in the state dir and used as the spec for tasks
+// validation and the post-validation gates.
+package issues
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Supported providers.
+const (
+	GitHub = "github"
+	GitLab = "gitlab"
+	Gitea  = "gitea"
+)
+
+// Ref identifies an issue. Host and Project are empty when the reference
+// did not name them, e.g. a bare issue number.
+type Ref struct {
+	Provider string
+	Host     string
+	Project  string // owner/repo, or group/subgroup/project on GitLab
+	Number   int
+}
+
+// String formats the reference as project#number, or PROJ-123 for Jira.
+func (r Ref) String() string {
+	if r.Provider == Jira {
+		return jiraKey(r)
+	}
+	return fmt.Sprintf("%s#%d", r.Project, r.Number)
+}
+
+// Issue is an issue fetched from any provider.
+type Issue struct {
+	Ref   Ref
+	Title string
+	Body  string
+	URL   string
+}
+
+// Document renders the issue as the markdown document cached for the loop.
+func (i *Issue) Document() string {
+	source := i.URL
+	if source == "" {
+		source = fmt.Sprintf("%s issue %s", i.Ref.Provider, i.Ref)
+	}
+	return fmt.Sprintf("# %s\n\nSource: %s\n\n%s\n", i.Title, source, strings.TrimSpace(i.Body))
+}
+
+// Provider fetches issues from one issue tracker.
+type Provider interface {
+	Fetch(ref Ref) (*Issue, error)
+}
+
+// ParseRef parses an issue reference: an issue URL, "project#number" or a
+// bare number. provider forces the provider; when empty it is detected from
+// the URL's host, and bare references default to GitHub.
+//
+// Examples:
+//   - "136" → GitHub issue 136 of the current directory's repository
+//   - "CodexForgeBR/cli-tools#42"
+//   - "https://gitlab.com/group/sub/project/-/issues/7"
+//   - "https://codeberg.org/owner/repo/issues/3"
+func ParseRef(ref, provider string) (Ref, error) {
+	switch provider {
+	case "", GitHub, GitLab, Gitea:
+	default:
+		return Ref{}, fmt.Errorf("unknown issue provider %q: must be github, gitlab or gitea", provider)
+	}
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return Ref{}, fmt.Errorf("empty issue reference")
+	}
+
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		return parseURL(ref, provider)
+	}
+
+	if provider == "" {
+		provider = GitHub
+	}
+	project, numStr, found := cutLast(ref, "#")
+	if !found {
+		project, numStr = "", ref
+	} else if project == "" || strings.Count(project, "/") < 1 {
+		return Ref{}, fmt.Errorf("invalid issue reference %q: expected number, 'owner/repo#number' or an issue URL", ref)
+	}
+	n, err := parseNumber(numStr)
+	if err != nil {
+		return Ref{}, err
+	}
+	if provider != GitLab && strings.Count(project, "/") > 1 {
+		return Ref{}, fmt.Errorf("invalid repo path: expected 'owner/repo', got %q", project)
+	}
+	return Ref{Provider: provider, Project: project, Number: n}, nil
+}
+
+func parseURL(ref, provider string) (Ref, error) {
+	u, err := url.Parse(ref)
+	if err != nil || u.Host == "" {
+		return Ref{}, fmt.Errorf("invalid issue URL %q", ref)
+	}
+	path := strings.Trim(u.Path, "/")
+
+	project, numStr, found := cutLast(path, "/-/issues/")
+	if found {
+		if provider == "" {
+			provider = GitLab
+		}
+	} else if project, numStr, found = cutLast(path, "/issues/"); !found {
+		return Ref{}, fmt.Errorf("invalid issue URL %q: no /issues/ in path", ref)
+	}
+	if provider == "" {
+		provider = detectProvider(u.Hostname())
+		if provider == "" {
+			return Ref{}, fmt.Errorf("cannot tell whether %s is GitHub, GitLab or Gitea; set --issue-provider", u.Host)
+		}
+	}
+	if strings.Count(project, "/") < 1 || (provider != GitLab && strings.Count(project, "/") > 1) {
+		return Ref{}, fmt.Errorf("invalid issue URL %q: unexpected project path %q", ref, project)
+	}
+	n, err := parseNumber(numStr)
+	if err != nil {
+		return Ref{}, err
+	}
+	return Ref{Provider: provider, Host: u.Scheme + "://" + u.Host, Project: project, Number: n}, nil
+}
+
+// detectProvider guesses the provider from well-known host names.
+func detectProvider(host string) string {
+	switch {
+	case host == "github.com" || strings.Contains(host, "github"):
+		return GitHub
+	case strings.Contains(host, "gitlab"):
+		return GitLab
+	case host == "codeberg.org" || strings.Contains(host, "gitea"):
+		return Gitea
+	}
+	return ""
+}
+
+func parseNumber(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid issue number %q: %w", s, err)
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("issue number must be positive, got %d", n)
+	}
+	return n, nil
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
+
+// Fetch parses ref and fetches the issue from its provider, configured from
+// the environment: GitLab uses the GitLab API when GITLAB_TOKEN is set and
+// the glab CLI otherwise, with GITLAB_HOST as the default host; Gitea uses
+// GITEA_URL and GITEA_TOKEN; GitHub uses the gh CLI.
+func Fetch(ref, provider string) (*Issue, error) {
+	r, err := ParseRef(ref, provider)
+	if err != nil {
+		return nil, err
+	}
+	return ProviderFor(r, os.Getenv).Fetch(r)
+}
+
+// ProviderFor returns the provider for r, configured from getenv.
+func ProviderFor(r Ref, getenv func(string) string) Provider {
+	switch r.Provider {
+	case GitLab:
+		return &GitLabProvider{BaseURL: baseURL(r.Host, getenv("GITLAB_HOST"), "https://gitlab.com"), Token: getenv("GITLAB_TOKEN")}
+	case Gitea:
+		return &GiteaProvider{BaseURL: baseURL(r.Host, getenv("GITEA_URL"), ""), Token: getenv("GITEA_TOKEN")}
+	default:
+		return &GitHubProvider{}
+	}
+}
+
+// baseURL picks the first non-empty host, adding https:// to bare host names.
+func baseURL(hosts ...string) string {
+	for _, h := range hosts {
+		if h == "" {
+			continue
+		}
+		if !strings.Contains(h, "://") {
+			h = "https://" + h
+		}
+		return strings.TrimRight(h, "/")
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package issues
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRef(t *testing.T) {
+	tests := []struct {
+		ref, provider string
+		want          Ref
+	}{
+		{"136", "", Ref{Provider: GitHub, Number: 136}},
+		{"136", "gitlab", Ref{Provider: GitLab, Number: 136}},
+		{"CodexForgeBR/cli-tools#42", "", Ref{Provider: GitHub, Project: "CodexForgeBR/cli-tools", Number: 42}},
+		{"group/sub/project#7", "gitlab", Ref{Provider: GitLab, Project: "group/sub/project", Number: 7}},
+		{"https://github.com/owner/repo/issues/5", "", Ref{Provider: GitHub, Host: "https://github.com", Project: "owner/repo", Number: 5}},
+		{"https://gitlab.com/group/sub/project/-/issues/7", "", Ref{Provider: GitLab, Host: "https://gitlab.com", Project: "group/sub/project", Number: 7}},
+		{"https://git.example.com/group/project/-/issues/8", "", Ref{Provider: GitLab, Host: "https://git.example.com", Project: "group/project", Number: 8}},
+		{"https://codeberg.org/owner/repo/issues/3", "", Ref{Provider: Gitea, Host: "https://codeberg.org", Project: "owner/repo", Number: 3}},
+		{"https://git.example.com/owner/repo/issues/9", "gitea", Ref{Provider: Gitea, Host: "https://git.example.com", Project: "owner/repo", Number: 9}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := ParseRef(tt.ref, tt.provider)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseRef_Errors(t *testing.T) {
+	tests := []struct {
+		ref, provider, want string
+	}{
+		{"", "", "empty issue reference"},
+		{"0", "", "must be positive"},
+		{"abc", "", "invalid issue number"},
+		{"#42", "", "invalid issue reference"},
+		{"a/b/c#1", "", "expected 'owner/repo'"},
+		{"1", "jira", "unknown issue provider"},
+		{"https://git.example.com/owner/repo/issues/9", "", "set --issue-provider"},
+		{"https://github.com/owner/repo/pull/9", "", "no /issues/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			_, err := ParseRef(tt.ref, tt.provider)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestIssueDocument(t *testing.T) {
+	issue := &Issue{
+		Ref:   Ref{Provider: GitLab, Project: "group/project", Number: 7},
+		Title: "Add a greeting",
+		Body:  "Print hello.\n",
+		URL:   "https://gitlab.com/group/project/-/issues/7",
+	}
+	assert.Equal(t, "# Add a greeting\n\nSource: https://gitlab.com/group/project/-/issues/7\n\nPrint hello.\n", issue.Document())
+
+	issue.URL = ""
+	assert.Contains(t, issue.Document(), "Source: gitlab issue group/project#7")
+}
+
+func TestProviderFor(t *testing.T) {
+	env := map[string]string{"GITLAB_HOST": "gitlab.example.com", "GITLAB_TOKEN": "glpat", "GITEA_URL": "https://gitea.example.com/"}
+	getenv := func(k string) string { return env[k] }
+
+	gl := ProviderFor(Ref{Provider: GitLab}, getenv).(*GitLabProvider)
+	assert.Equal(t, "https://gitlab.example.com", gl.BaseURL)
+	assert.Equal(t, "glpat", gl.Token)
+
+	gl = ProviderFor(Ref{Provider: GitLab, Host: "https://other.example.com"}, getenv).(*GitLabProvider)
+	assert.Equal(t, "https://other.example.com", gl.BaseURL, "the URL's host wins")
+
+	gt := ProviderFor(Ref{Provider: Gitea}, getenv).(*GiteaProvider)
+	assert.Equal(t, "https://gitea.example.com", gt.BaseURL)
+
+	assert.IsType(t, &GitHubProvider{}, ProviderFor(Ref{Provider: GitHub}, getenv))
+}
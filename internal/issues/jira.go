@@ -0,0 +1,144 @@
+package issues
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Jira identifies Jira tickets, which are fetched with --jira-issue rather
+// than through ParseRef.
+const Jira = "jira"
+
+// jiraKeyPattern matches issue keys such as PROJ-123.
+var jiraKeyPattern = regexp.MustCompile(`^([A-Z][A-Z0-9_]*)-([0-9]+)$`)
+
+// ParseJiraKey parses a Jira issue key ("PROJ-123") or browse URL
+// ("https://example.atlassian.net/browse/PROJ-123").
+func ParseJiraKey(ref string) (Ref, error) {
+	ref = strings.TrimSpace(ref)
+	key, host := ref, ""
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		u, err := url.Parse(ref)
+		if err != nil || u.Host == "" {
+			return Ref{}, fmt.Errorf("invalid Jira URL %q", ref)
+		}
+		prefix, k, found := cutLast(strings.TrimRight(u.Path, "/"), "/browse/")
+		if !found {
+			return Ref{}, fmt.Errorf("invalid Jira URL %q: expected .../browse/PROJ-123", ref)
+		}
+		key, host = k, u.Scheme+"://"+u.Host+prefix
+	}
+	m := jiraKeyPattern.FindStringSubmatch(key)
+	if m == nil {
+		return Ref{}, fmt.Errorf("invalid Jira issue key %q: expected PROJ-123", key)
+	}
+	n, err := parseNumber(m[2])
+	if err != nil {
+		return Ref{}, err
+	}
+	return Ref{Provider: Jira, Host: host, Project: m[1], Number: n}, nil
+}
+
+// JiraProvider fetches tickets from the Jira REST API (v2, so descriptions
+// arrive as wiki markup rather than Atlassian document format). With Email
+// set it authenticates Jira Cloud style (basic auth with an API token);
+// otherwise Token is sent as a bearer personal access token.
+type JiraProvider struct {
+	BaseURL string // e.g. https://example.atlassian.net
+	Email   string
+	Token   string
+	// AcceptanceField is the custom field holding acceptance criteria, e.g.
+	// customfield_10035. Empty looks for a field named "Acceptance Criteria".
+	AcceptanceField string
+	Client          *http.Client
+}
+
+type jiraIssue struct {
+	Fields map[string]json.RawMessage `json:"fields"`
+	Names  map[string]string          `json:"names"`
+}
+
+// Fetch implements Provider.
+func (p *JiraProvider) Fetch(ref Ref) (*Issue, error) {
+	if p.BaseURL == "" {
+		return nil, fmt.Errorf("Jira issue %s: no server, pass the browse URL or set JIRA_URL", ref)
+	}
+	headers := map[string]string{}
+	switch {
+	case p.Email != "":
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(p.Email+":"+p.Token))
+	case p.Token != "":
+		headers["Authorization"] = "Bearer " + p.Token
+	}
+
+	endpoint := fmt.Sprintf("%s/rest/api/2/issue/%s?expand=names", p.BaseURL, url.PathEscape(ref.String()))
+	var ji jiraIssue
+	if err := getJSON(p.Client, endpoint, headers, &ji); err != nil {
+		return nil, fmt.Errorf("failed to fetch Jira issue %s: %w", ref, err)
+	}
+
+	body := jiraText(ji.Fields["description"])
+	if criteria := jiraText(ji.Fields[p.acceptanceField(ji.Names)]); criteria != "" {
+		body = strings.TrimSpace(body + "\n\n## Acceptance Criteria\n\n" + criteria)
+	}
+	return &Issue{
+		Ref:   ref,
+		Title: jiraText(ji.Fields["summary"]),
+		Body:  body,
+		URL:   fmt.Sprintf("%s/browse/%s", p.BaseURL, ref),
+	}, nil
+}
+
+func (p *JiraProvider) acceptanceField(names map[string]string) string {
+	if p.AcceptanceField != "" {
+		return p.AcceptanceField
+	}
+	for id, name := range names {
+		if strings.EqualFold(strings.TrimSpace(name), "acceptance criteria") {
+			return id
+		}
+	}
+	return ""
+}
+
+// jiraText returns a string field's value; other field types (null,
+// objects) yield "".
+func jiraText(raw json.RawMessage) string {
+	var s string
+	if len(raw) == 0 || json.Unmarshal(raw, &s) != nil {
+		return ""
+	}
+	return strings.TrimSpace(s)
+}
+
+// FetchJira parses a Jira key or browse URL and fetches the ticket,
+// configured from JIRA_URL, JIRA_EMAIL, JIRA_TOKEN and
+// JIRA_ACCEPTANCE_FIELD.
+func FetchJira(ref string) (*Issue, error) {
+	r, err := ParseJiraKey(ref)
+	if err != nil {
+		return nil, err
+	}
+	return jiraFromEnv(r, os.Getenv).Fetch(r)
+}
+
+func jiraFromEnv(r Ref, getenv func(string) string) *JiraProvider {
+	return &JiraProvider{
+		BaseURL:         baseURL(r.Host, getenv("JIRA_URL")),
+		Email:           getenv("JIRA_EMAIL"),
+		Token:           getenv("JIRA_TOKEN"),
+		AcceptanceField: getenv("JIRA_ACCEPTANCE_FIELD"),
+	}
+}
+
+// jiraKey formats a Jira reference as PROJ-123.
+func jiraKey(r Ref) string {
+	return r.Project + "-" + strconv.Itoa(r.Number)
+}
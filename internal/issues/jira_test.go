@@ -0,0 +1,91 @@
+package issues
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJiraKey(t *testing.T) {
+	r, err := ParseJiraKey("PROJ-123")
+	require.NoError(t, err)
+	assert.Equal(t, Ref{Provider: Jira, Project: "PROJ", Number: 123}, r)
+	assert.Equal(t, "PROJ-123", r.String())
+
+	r, err = ParseJiraKey("https://example.atlassian.net/browse/AB2-7")
+	require.NoError(t, err)
+	assert.Equal(t, Ref{Provider: Jira, Host: "https://example.atlassian.net", Project: "AB2", Number: 7}, r)
+
+	r, err = ParseJiraKey("https://jira.example.com/jira/browse/OPS-1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://jira.example.com/jira", r.Host, "context path is kept")
+
+	for _, bad := range []string{"", "proj-1", "PROJ", "PROJ-0", "https://example.com/issues/PROJ-1"} {
+		_, err := ParseJiraKey(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestJiraProvider_Fetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/2/issue/PROJ-123", r.URL.Path)
+		assert.Equal(t, "names", r.URL.Query().Get("expand"))
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "me@example.com", user)
+		assert.Equal(t, "api-token", pass)
+		_, _ = w.Write([]byte(`{
+			"fields": {
+				"summary": "Export reports as CSV",
+				"description": "Users need CSV exports.",
+				"customfield_10035": "* Download button on the report page\n* UTF-8 with a header row",
+				"customfield_10036": null
+			},
+			"names": {"customfield_10035": "Acceptance Criteria", "customfield_10036": "Story Points"}
+		}`))
+	}))
+	defer srv.Close()
+
+	p := &JiraProvider{BaseURL: srv.URL, Email: "me@example.com", Token: "api-token"}
+	issue, err := p.Fetch(Ref{Provider: Jira, Project: "PROJ", Number: 123})
+	require.NoError(t, err)
+	assert.Equal(t, "Export reports as CSV", issue.Title)
+	assert.Equal(t, "Users need CSV exports.\n\n## Acceptance Criteria\n\n* Download button on the report page\n* UTF-8 with a header row", issue.Body)
+	assert.Equal(t, srv.URL+"/browse/PROJ-123", issue.URL)
+}
+
+func TestJiraProvider_BearerTokenAndExplicitField(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer pat", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"fields": {"summary": "S", "description": null, "customfield_1": "AC"}, "names": {}}`))
+	}))
+	defer srv.Close()
+
+	p := &JiraProvider{BaseURL: srv.URL, Token: "pat", AcceptanceField: "customfield_1"}
+	issue, err := p.Fetch(Ref{Provider: Jira, Project: "P", Number: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "## Acceptance Criteria\n\nAC", issue.Body)
+}
+
+func TestJiraProvider_Errors(t *testing.T) {
+	_, err := (&JiraProvider{}).Fetch(Ref{Provider: Jira, Project: "P", Number: 1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JIRA_URL")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Issue does not exist", http.StatusNotFound)
+	}))
+	defer srv.Close()
+	_, err = (&JiraProvider{BaseURL: srv.URL}).Fetch(Ref{Provider: Jira, Project: "P", Number: 1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to fetch Jira issue P-1")
+}
+
+func TestJiraFromEnv(t *testing.T) {
+	env := map[string]string{"JIRA_URL": "jira.example.com", "JIRA_TOKEN": "t", "JIRA_EMAIL": "e", "JIRA_ACCEPTANCE_FIELD": "customfield_9"}
+	p := jiraFromEnv(Ref{Provider: Jira}, func(k string) string { return env[k] })
+	assert.Equal(t, &JiraProvider{BaseURL: "https://jira.example.com", Email: "e", Token: "t", AcceptanceField: "customfield_9"}, p)
+}
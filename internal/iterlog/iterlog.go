@@ -0,0 +1,365 @@
+// Package iterlog keeps per-iteration logs of the AI CLIs' raw output
+// (impl.log, val.log, cross.log, ...) in each iteration directory, rotating
+// them by size and optionally compressing those of finished iterations.
+// It also compacts the outputs of finished iterations: large ones are
+// gzipped, and files identical to the previous iteration's are hard links
+// to them. ReadFile reads an output either way.
+package iterlog
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/CodexForgeBR/cli-tools/internal/secrets"
+	"github.com/CodexForgeBR/cli-tools/internal/statecrypt"
+)
+
+// Phases are the log names, in the order the phases run.
+var Phases = []string{"impl", "triage", "val", "cross", "final-plan"}
+
+// keepRotated is how many rotated files (impl.log.1 ... impl.log.N) are
+// kept besides the live log.
+const keepRotated = 5
+
+// Path returns the log file of phase in iterDir.
+func Path(iterDir, phase string) string {
+	return filepath.Join(iterDir, phase+".log")
+}
+
+// Writer appends to a log file, rotating it to path.1, path.2, ... once it
+// would grow past maxBytes. It is safe for concurrent use, since a CLI's
+// stdout and stderr are written from different goroutines.
+type Writer struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	f        *os.File
+	size     int64
+}
+
+// Open opens path for appending. maxBytes <= 0 disables rotation.
+func Open(path string, maxBytes int64) (*Writer, error) {
+	w := &Writer{path: path, maxBytes: maxBytes}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Writer) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.f, w.size = f, info.Size()
+	return nil
+}
+
+// Write implements io.Writer. The registered secrets (see
+// secrets.Register) are redacted from what each call writes.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.f == nil {
+		return 0, os.ErrClosed
+	}
+	data := secrets.RedactBytes(p)
+	if w.maxBytes > 0 && w.size > 0 && w.size+int64(len(data)) > w.maxBytes {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.f.Write(data)
+	w.size += int64(n)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// rotate shifts path.N-1 to path.N, ..., path to path.1, dropping the
+// oldest, and starts a new empty log.
+func (w *Writer) rotate() error {
+	if err := w.f.Close(); err != nil {
+		return err
+	}
+	w.f = nil
+	os.Remove(fmt.Sprintf("%s.%d", w.path, keepRotated))
+	for i := keepRotated - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", w.path, i), fmt.Sprintf("%s.%d", w.path, i+1))
+	}
+	if err := os.Rename(w.path, w.path+".1"); err != nil {
+		return err
+	}
+	return w.open()
+}
+
+// Close closes the log.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.f == nil {
+		return nil
+	}
+	err := w.f.Close()
+	w.f = nil
+	return err
+}
+
+// Compress gzips the logs in iterDir, replacing impl.log with impl.log.gz
+// and so on. Logs that are already compressed are left alone.
+func Compress(iterDir string) error {
+	logs, err := filepath.Glob(filepath.Join(iterDir, "*.log*"))
+	if err != nil {
+		return err
+	}
+	for _, path := range logs {
+		if strings.HasSuffix(path, ".gz") {
+			continue
+		}
+		if err := gzipFile(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func gzipFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	// Encrypted logs do not compress; leave them as they are.
+	br := bufio.NewReader(in)
+	if head, _ := br.Peek(64); statecrypt.IsEncrypted(head) {
+		return nil
+	}
+	out, err := os.Create(path + ".gz")
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	_, err = io.Copy(zw, br)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Files returns the log files of phase in iterDir, compressed or not,
+// oldest first: impl.log.5 ... impl.log.1, impl.log.
+func Files(iterDir, phase string) ([]string, error) {
+	base := Path(iterDir, phase)
+	matches, err := filepath.Glob(base + "*")
+	if err != nil {
+		return nil, err
+	}
+	type logFile struct {
+		path string
+		age  int // rotation number; 0 for the live log
+	}
+	var files []logFile
+	for _, path := range matches {
+		suffix := strings.TrimSuffix(strings.TrimPrefix(path, base), ".gz")
+		switch {
+		case suffix == "":
+			files = append(files, logFile{path, 0})
+		case strings.HasPrefix(suffix, "."):
+			if n, err := strconv.Atoi(suffix[1:]); err == nil && n > 0 {
+				files = append(files, logFile{path, n})
+			}
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].age > files[j].age })
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.path
+	}
+	return paths, nil
+}
+
+// Copy writes the contents of files to w in order, decrypting the
+// encrypted ones and decompressing the gzipped ones.
+func Copy(w io.Writer, files []string) error {
+	for _, path := range files {
+		if err := copyFile(w, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	// A sealed iteration's logs are encrypted whole (see statecrypt).
+	br := bufio.NewReader(f)
+	var r io.Reader = br
+	if head, _ := br.Peek(64); statecrypt.IsEncrypted(head) {
+		data, err := statecrypt.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
+	if strings.HasSuffix(path, ".gz") {
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// ReadFile returns the contents of path, an iteration output, or of
+// path.gz when CompressOutputs compressed it, decrypted and decompressed.
+func ReadFile(path string) ([]byte, error) {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		if _, gzErr := os.Stat(path + ".gz"); gzErr == nil {
+			path += ".gz"
+		}
+	}
+	var buf bytes.Buffer
+	if err := copyFile(&buf, path); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// isOutput reports whether name is an AI output or log in an iteration
+// directory, which CompressOutputs may compress.
+func isOutput(name string) bool {
+	return strings.HasSuffix(name, "-output.txt") || strings.HasSuffix(name, ".stream.json") ||
+		strings.HasSuffix(name, ".log") || strings.Contains(name, ".log.")
+}
+
+// CompressOutputs gzips the AI outputs and logs in iterDir of minBytes or
+// more, replacing validation-output.txt with validation-output.txt.gz and
+// so on. Outputs already compressed or encrypted are left alone.
+func CompressOutputs(iterDir string, minBytes int64) error {
+	entries, err := os.ReadDir(iterDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, e := range entries {
+		if !e.Type().IsRegular() || !isOutput(e.Name()) || strings.HasSuffix(e.Name(), ".gz") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || info.Size() < minBytes {
+			continue
+		}
+		if err := gzipFile(filepath.Join(iterDir, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Dedup replaces the files under iterDir that are identical to a file
+// under prevDir, the previous iteration's directory, with hard links to
+// it, and returns the bytes saved. Where links are not supported the
+// files are left as they are.
+func Dedup(iterDir, prevDir string) (int64, error) {
+	bySize := map[int64][]string{}
+	_ = filepath.WalkDir(prevDir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil && info.Size() > 0 {
+				bySize[info.Size()] = append(bySize[info.Size()], path)
+			}
+		}
+		return nil
+	})
+	if len(bySize) == 0 {
+		return 0, nil
+	}
+
+	sums := map[string][sha256.Size]byte{}
+	sum := func(path string) ([sha256.Size]byte, bool) {
+		if s, ok := sums[path]; ok {
+			return s, true
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return [sha256.Size]byte{}, false
+		}
+		sums[path] = sha256.Sum256(data)
+		return sums[path], true
+	}
+
+	var saved int64
+	err := filepath.WalkDir(iterDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		for _, candidate := range bySize[info.Size()] {
+			prevInfo, err := os.Stat(candidate)
+			if err != nil {
+				continue
+			}
+			if os.SameFile(info, prevInfo) {
+				return nil
+			}
+			s, ok := sum(path)
+			if prev, prevOK := sum(candidate); !ok || !prevOK || s != prev {
+				continue
+			}
+			tmp := path + ".link"
+			if os.Link(candidate, tmp) != nil {
+				return nil
+			}
+			if err := os.Rename(tmp, path); err != nil {
+				os.Remove(tmp)
+				return err
+			}
+			saved += info.Size()
+			return nil
+		}
+		return nil
+	})
+	return saved, err
+}
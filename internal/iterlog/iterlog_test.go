@@ -0,0 +1,249 @@
+package iterlog
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/secrets"
+	"github.com/CodexForgeBR/cli-tools/internal/statecrypt"
+)
+
+func readAll(t *testing.T, iterDir, phase string) string {
+	t.Helper()
+	files, err := Files(iterDir, phase)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, Copy(&buf, files))
+	return buf.String()
+}
+
+func TestWriter_AppendsWithoutRotation(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Open(Path(dir, "impl"), 0)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("first\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	// Reopening appends, e.g. after a format-fix call in the same phase.
+	w, err = Open(Path(dir, "impl"), 0)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("second\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	data, err := os.ReadFile(filepath.Join(dir, "impl.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n", string(data))
+
+	_, err = w.Write([]byte("late"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestWriter_RotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Open(Path(dir, "val"), 10)
+	require.NoError(t, err)
+	for _, line := range []string{"aaaaaa\n", "bbbbbb\n", "cccccc\n"} {
+		_, err := w.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	files, err := Files(dir, "val")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "val.log.2"),
+		filepath.Join(dir, "val.log.1"),
+		filepath.Join(dir, "val.log"),
+	}, files)
+	assert.Equal(t, "aaaaaa\nbbbbbb\ncccccc\n", readAll(t, dir, "val"))
+}
+
+func TestWriter_KeepsLimitedRotations(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Open(Path(dir, "impl"), 2)
+	require.NoError(t, err)
+	for i := 0; i < keepRotated+3; i++ {
+		_, err := w.Write([]byte{byte('a' + i), '\n'})
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	files, err := Files(dir, "impl")
+	require.NoError(t, err)
+	assert.Len(t, files, keepRotated+1)
+	assert.Equal(t, "c\nd\ne\nf\ng\nh\n", readAll(t, dir, "impl"), "the oldest output is dropped")
+}
+
+func TestCompress(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Open(Path(dir, "cross"), 8)
+	require.NoError(t, err)
+	_, _ = w.Write([]byte("one\ntwo\n"))
+	_, _ = w.Write([]byte("three\n"))
+	require.NoError(t, w.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "validation-output.txt"), []byte("kept"), 0644))
+
+	require.NoError(t, Compress(dir))
+	require.NoError(t, Compress(dir), "compressing twice is harmless")
+
+	files, err := Files(dir, "cross")
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "cross.log.1.gz"), filepath.Join(dir, "cross.log.gz")}, files)
+	assert.Equal(t, "one\ntwo\nthree\n", readAll(t, dir, "cross"))
+	assert.FileExists(t, filepath.Join(dir, "validation-output.txt"))
+}
+
+func TestCopy_Encrypted(t *testing.T) {
+	key, err := statecrypt.ParseKey("c3RhdGUta2V5LXN0YXRlLWtleS1zdGF0ZS1rZXktMzI=")
+	require.NoError(t, err)
+	statecrypt.SetKey(key)
+	t.Cleanup(func() { statecrypt.SetKey(nil) })
+	dir := t.TempDir()
+	w, err := Open(Path(dir, "impl"), 8)
+	require.NoError(t, err)
+	_, _ = w.Write([]byte("one\ntwo\n"))
+	_, _ = w.Write([]byte("three\n"))
+	require.NoError(t, w.Close())
+
+	require.NoError(t, Compress(dir))
+	require.NoError(t, statecrypt.SealDir(dir))
+	require.NoError(t, Compress(dir), "encrypted logs are left as they are")
+
+	files, err := Files(dir, "impl")
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "impl.log.1.gz"), filepath.Join(dir, "impl.log.gz")}, files)
+	assert.Equal(t, "one\ntwo\nthree\n", readAll(t, dir, "impl"))
+
+	statecrypt.SetKey(nil)
+	err = Copy(&bytes.Buffer{}, files)
+	assert.ErrorIs(t, err, statecrypt.ErrNoKey)
+}
+
+func TestFiles_IgnoresOtherLogs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"val.log", "val.log.1", "val.log.bak", "val.logger", "impl.log"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name+"\n"), 0644))
+	}
+
+	files, err := Files(dir, "val")
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "val.log.1"), filepath.Join(dir, "val.log")}, files)
+
+	files, err = Files(dir, "cross")
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestCopy_CorruptGzip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "val.log.gz")
+	require.NoError(t, os.WriteFile(path, []byte("not gzip"), 0644))
+
+	err := Copy(&bytes.Buffer{}, []string{path})
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "val.log.gz"))
+}
+
+func TestWriter_RedactsSecrets(t *testing.T) {
+	secrets.Register("sk-test-0123456789")
+	t.Cleanup(secrets.Reset)
+	dir := t.TempDir()
+	w, err := Open(Path(dir, "impl"), 0)
+	require.NoError(t, err)
+	n, err := w.Write([]byte("using key sk-test-0123456789\n"))
+	require.NoError(t, err)
+	assert.Equal(t, len("using key sk-test-0123456789\n"), n, "the caller's bytes count as written")
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, "using key [REDACTED]\n", readAll(t, dir, "impl"))
+}
+
+func TestCompressOutputs(t *testing.T) {
+	dir := t.TempDir()
+	big := strings.Repeat("The implementation is done.\n", 100)
+	files := map[string]string{
+		"validation-output.txt":                 big,
+		"implementation-output.txt.stream.json": big,
+		"triage-output.txt":                     "small",
+		"verdict.json":                          big,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	w, err := Open(Path(dir, "impl"), 0)
+	require.NoError(t, err)
+	_, _ = w.Write([]byte(big))
+	require.NoError(t, w.Close())
+
+	require.NoError(t, CompressOutputs(dir, 1024))
+	require.NoError(t, CompressOutputs(dir, 1024), "compressing twice is harmless")
+
+	for _, name := range []string{"validation-output.txt", "implementation-output.txt.stream.json", "impl.log"} {
+		assert.NoFileExists(t, filepath.Join(dir, name))
+		assert.FileExists(t, filepath.Join(dir, name+".gz"))
+	}
+	assert.FileExists(t, filepath.Join(dir, "triage-output.txt"), "small outputs are kept as they are")
+	assert.FileExists(t, filepath.Join(dir, "verdict.json"), "only AI outputs and logs are compressed")
+
+	for name, content := range files {
+		data, err := ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err, name)
+		assert.Equal(t, content, string(data), name)
+	}
+	assert.Equal(t, big, readAll(t, dir, "impl"), "the logs command reads them as before")
+
+	_, err = ReadFile(filepath.Join(dir, "missing-output.txt"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.NoError(t, CompressOutputs(filepath.Join(dir, "missing"), 1024))
+}
+
+func TestDedup(t *testing.T) {
+	root := t.TempDir()
+	prev, cur := filepath.Join(root, "iteration-001"), filepath.Join(root, "iteration-002")
+	write := func(path, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	write(filepath.Join(prev, "artifacts", "junit.xml"), "<testsuite tests=\"3\"/>")
+	write(filepath.Join(prev, "validation-output.txt"), "NEEDS_MORE_WORK")
+	write(filepath.Join(prev, "empty.txt"), "")
+	write(filepath.Join(cur, "artifacts", "junit.xml"), "<testsuite tests=\"3\"/>")
+	write(filepath.Join(cur, "verify-output.txt"), "NEEDS_MORE_WORK")
+	write(filepath.Join(cur, "validation-output.txt"), "COMPLETE_AT_LAST")
+	write(filepath.Join(cur, "empty.txt"), "")
+
+	saved, err := Dedup(cur, prev)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("<testsuite tests=\"3\"/>")+len("NEEDS_MORE_WORK")), saved)
+
+	same := func(a, b string) bool {
+		ia, err := os.Stat(a)
+		require.NoError(t, err)
+		ib, err := os.Stat(b)
+		require.NoError(t, err)
+		return os.SameFile(ia, ib)
+	}
+	assert.True(t, same(filepath.Join(cur, "artifacts", "junit.xml"), filepath.Join(prev, "artifacts", "junit.xml")))
+	assert.True(t, same(filepath.Join(cur, "verify-output.txt"), filepath.Join(prev, "validation-output.txt")), "identical content is linked whatever its name")
+	assert.False(t, same(filepath.Join(cur, "validation-output.txt"), filepath.Join(prev, "validation-output.txt")))
+	assert.False(t, same(filepath.Join(cur, "empty.txt"), filepath.Join(prev, "empty.txt")))
+
+	data, err := ReadFile(filepath.Join(cur, "verify-output.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "NEEDS_MORE_WORK", string(data))
+
+	saved, err = Dedup(cur, prev)
+	require.NoError(t, err)
+	assert.Zero(t, saved, "linked files are left alone")
+	saved, err = Dedup(cur, filepath.Join(root, "missing"))
+	require.NoError(t, err)
+	assert.Zero(t, saved)
+}
@@ -0,0 +1,74 @@
+package learnings
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// digestHeader starts every digest file and records the hash of the
+// learnings it summarizes, so a digest is reused only while they are
+// unchanged.
+const digestHeader = "<!-- ralph-loop learnings digest, source sha256: %s -->\n"
+
+// EstimateTokens approximates the token count of text at four bytes per
+// token, which is close enough for deciding when to summarize.
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// DigestPath returns where the digest of a learnings file is stored:
+// beside it, with ".digest" before the extension.
+func DigestPath(learningsFile string) string {
+	ext := filepath.Ext(learningsFile)
+	return strings.TrimSuffix(learningsFile, ext) + ".digest" + ext
+}
+
+// ReadDigest returns the digest stored at path if it was made from source.
+// It reports false when the file is missing or summarizes other content.
+func ReadDigest(path, source string) (string, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	header := fmt.Sprintf(digestHeader, sourceHash(source))
+	content := string(data)
+	if !strings.HasPrefix(content, header) {
+		return "", false
+	}
+	return strings.TrimPrefix(content, header), true
+}
+
+// WriteDigest stores digest at path, tagged with the hash of source.
+func WriteDigest(path, source, digest string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create digest directory: %w", err)
+	}
+	content := fmt.Sprintf(digestHeader, sourceHash(source)) + strings.TrimSpace(digest) + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write learnings digest: %w", err)
+	}
+	return nil
+}
+
+// Truncate keeps the most recent learnings that fit in maxTokens, cutting
+// at a line boundary. It is the fallback when no digest can be made.
+func Truncate(text string, maxTokens int) string {
+	limit := maxTokens * 4
+	if len(text) <= limit {
+		return text
+	}
+	tail := text[len(text)-limit:]
+	if i := strings.Index(tail, "\n"); i >= 0 {
+		tail = tail[i+1:]
+	}
+	return "(older learnings omitted)\n\n" + tail
+}
+
+func sourceHash(source string) string {
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:])
+}
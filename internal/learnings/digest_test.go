@@ -0,0 +1,56 @@
+package learnings
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	assert.Equal(t, 0, EstimateTokens(""))
+	assert.Equal(t, 1, EstimateTokens("abc"))
+	assert.Equal(t, 3, EstimateTokens("twelve bytes"))
+}
+
+func TestDigestPath(t *testing.T) {
+	assert.Equal(t, ".ralph-loop/learnings.digest.md", DigestPath(".ralph-loop/learnings.md"))
+	assert.Equal(t, "notes.digest", DigestPath("notes"))
+}
+
+func TestWriteAndReadDigest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "learnings.digest.md")
+
+	require.NoError(t, WriteDigest(path, "source v1", "- Use table tests\n\n"))
+	digest, ok := ReadDigest(path, "source v1")
+	assert.True(t, ok)
+	assert.Equal(t, "- Use table tests\n", digest)
+
+	_, ok = ReadDigest(path, "source v2")
+	assert.False(t, ok, "a digest of other learnings is stale")
+}
+
+func TestReadDigest_Missing(t *testing.T) {
+	_, ok := ReadDigest(filepath.Join(t.TempDir(), "none.md"), "source")
+	assert.False(t, ok)
+}
+
+func TestReadDigest_WithoutHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "learnings.digest.md")
+	require.NoError(t, os.WriteFile(path, []byte("- hand written\n"), 0644))
+	_, ok := ReadDigest(path, "source")
+	assert.False(t, ok)
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", Truncate("short", 10))
+
+	text := "- first learning\n- second learning\n- third learning\n"
+	got := Truncate(text, 6)
+	assert.True(t, strings.HasPrefix(got, "(older learnings omitted)"))
+	assert.True(t, strings.HasSuffix(got, "- third learning\n"))
+	assert.NotContains(t, got, "first")
+}
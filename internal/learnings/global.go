@@ -0,0 +1,206 @@
+package learnings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GlobalDir returns the user-level learnings library,
+// ~/.config/ralph-loop/learnings. It holds one markdown file per
+// language or framework tag, e.g. go.md or react.md.
+func GlobalDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "ralph-loop", "learnings")
+}
+
+// ParseTags splits a comma-separated tag list, normalising case and
+// dropping empty and duplicate entries.
+func ParseTags(list string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, t := range strings.Split(list, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" && !seen[t] {
+			seen[t] = true
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// tagFiles maps project marker files to the tags they imply.
+var tagFiles = []struct {
+	file string
+	tags []string
+}{
+	{"go.mod", []string{"go"}},
+	{"package.json", []string{"javascript"}},
+	{"tsconfig.json", []string{"typescript"}},
+	{"pyproject.toml", []string{"python"}},
+	{"requirements.txt", []string{"python"}},
+	{"setup.py", []string{"python"}},
+	{"Cargo.toml", []string{"rust"}},
+	{"pom.xml", []string{"java"}},
+	{"build.gradle", []string{"java"}},
+	{"build.gradle.kts", []string{"kotlin"}},
+	{"Gemfile", []string{"ruby"}},
+	{"composer.json", []string{"php"}},
+	{"mix.exs", []string{"elixir"}},
+	{"Package.swift", []string{"swift"}},
+}
+
+// packageFrameworks maps npm dependencies to framework tags.
+var packageFrameworks = map[string]string{
+	"react": "react", "next": "nextjs", "vue": "vue", "nuxt": "nuxt",
+	"@angular/core": "angular", "svelte": "svelte", "express": "express",
+	"@nestjs/core": "nestjs", "@playwright/test": "playwright",
+}
+
+// pythonFrameworks matches framework names in Python dependency files.
+var pythonFrameworks = regexp.MustCompile(`(?im)^\s*["']?(django|flask|fastapi)\b`)
+
+// DetectTags returns the language and framework tags of the project in
+// root, judged by its build files, sorted.
+func DetectTags(root string) []string {
+	set := map[string]bool{}
+	for _, tf := range tagFiles {
+		if _, err := os.Stat(filepath.Join(root, tf.file)); err == nil {
+			for _, t := range tf.tags {
+				set[t] = true
+			}
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(root, "package.json")); err == nil {
+		var pkg struct {
+			Dependencies    map[string]string `json:"dependencies"`
+			DevDependencies map[string]string `json:"devDependencies"`
+		}
+		if json.Unmarshal(data, &pkg) == nil {
+			for dep, tag := range packageFrameworks {
+				if _, ok := pkg.Dependencies[dep]; ok {
+					set[tag] = true
+				}
+				if _, ok := pkg.DevDependencies[dep]; ok {
+					set[tag] = true
+				}
+			}
+		}
+	}
+	for _, name := range []string{"requirements.txt", "pyproject.toml"} {
+		if data, err := os.ReadFile(filepath.Join(root, name)); err == nil {
+			for _, m := range pythonFrameworks.FindAllStringSubmatch(string(data), -1) {
+				set[strings.ToLower(m[1])] = true
+			}
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "Gemfile")); err == nil && strings.Contains(string(data), "rails") {
+		set["rails"] = true
+	}
+
+	tags := make([]string, 0, len(set))
+	for t := range set {
+		tags = append(tags, t)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// ReadGlobal returns the library entries for tags, each under a
+// "### <tag>" heading. Tags without a file are skipped.
+func ReadGlobal(dir string, tags []string) string {
+	if dir == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, tag := range tags {
+		content := strings.TrimSpace(ReadLearnings(filepath.Join(dir, tag+".md")))
+		if content == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "### %s\n\n%s\n\n", tag, content)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Merge combines project learnings with global ones for a prompt.
+func Merge(project, global string) string {
+	if global == "" {
+		return project
+	}
+	merged := "## Global Learnings (other projects using the same stack)\n\n" + global
+	if strings.TrimSpace(project) == "" {
+		return merged
+	}
+	return strings.TrimRight(project, "\n") + "\n\n" + merged
+}
+
+// templateLine matches the boilerplate lines of a project learnings file.
+var templateLine = regexp.MustCompile(`^(# Ralph Loop Learnings|## Codebase Patterns|## Iteration Log|## Iteration \d+ \(.*\)|---|<!--.*-->)$`)
+
+// Entries returns the learnings recorded in a project learnings file,
+// without the template headings and iteration markers.
+func Entries(content string) []string {
+	var entries []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if strings.TrimSpace(line) == "" || templateLine.MatchString(strings.TrimSpace(line)) {
+			continue
+		}
+		entries = append(entries, line)
+	}
+	return entries
+}
+
+// Promote appends entries to the library file of every tag, under a
+// heading naming source. Entries a tag file already holds are skipped.
+// It returns how many entries were added per tag.
+func Promote(dir string, tags []string, entries []string, source string) (map[string]int, error) {
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("no tags to promote learnings to")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create learnings library: %w", err)
+	}
+	added := map[string]int{}
+	for _, tag := range tags {
+		path := filepath.Join(dir, tag+".md")
+		existing := map[string]bool{}
+		for _, line := range strings.Split(ReadLearnings(path), "\n") {
+			existing[strings.TrimSpace(line)] = true
+		}
+		var fresh []string
+		for _, e := range entries {
+			if !existing[strings.TrimSpace(e)] {
+				existing[strings.TrimSpace(e)] = true
+				fresh = append(fresh, e)
+			}
+		}
+		if len(fresh) == 0 {
+			continue
+		}
+		entry := fmt.Sprintf("\n## From %s (%s)\n\n%s\n", source, time.Now().Local().Format("2006-01-02"), strings.Join(fresh, "\n"))
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return added, fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		_, err = f.WriteString(entry)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return added, fmt.Errorf("failed to append to %s: %w", path, err)
+		}
+		added[tag] = len(fresh)
+	}
+	return added, nil
+}
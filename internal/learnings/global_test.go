@@ -0,0 +1,84 @@
+package learnings
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTags(t *testing.T) {
+	assert.Equal(t, []string{"go", "react"}, ParseTags(" Go, react,,go "))
+	assert.Empty(t, ParseTags(""))
+}
+
+func TestGlobalDir(t *testing.T) {
+	t.Setenv("HOME", "/home/dev")
+	assert.Equal(t, filepath.Join("/home/dev", ".config", "ralph-loop", "learnings"), GlobalDir())
+}
+
+func TestDetectTags(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0644))
+	}
+	write("go.mod", "module example.com/x\n")
+	write("package.json", `{"dependencies": {"react": "^18"}, "devDependencies": {"@playwright/test": "^1"}}`)
+	write("tsconfig.json", "{}")
+	write("requirements.txt", "Django==5.0\nrequests\n")
+
+	assert.Equal(t, []string{"django", "go", "javascript", "playwright", "python", "react", "typescript"}, DetectTags(root))
+}
+
+func TestDetectTags_EmptyProject(t *testing.T) {
+	assert.Empty(t, DetectTags(t.TempDir()))
+}
+
+func TestReadGlobalAndMerge(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.md"), []byte("- Run go vet before tests\n"), 0644))
+
+	global := ReadGlobal(dir, []string{"go", "react"})
+	assert.Equal(t, "### go\n\n- Run go vet before tests", global)
+	assert.Empty(t, ReadGlobal("", []string{"go"}))
+
+	merged := Merge("# Ralph Loop Learnings\n- project note\n", global)
+	assert.True(t, strings.HasPrefix(merged, "# Ralph Loop Learnings\n- project note\n\n## Global Learnings"))
+	assert.Contains(t, merged, "- Run go vet before tests")
+
+	assert.Equal(t, "project", Merge("project", ""))
+	assert.True(t, strings.HasPrefix(Merge("", global), "## Global Learnings"))
+}
+
+func TestEntries(t *testing.T) {
+	content := learningsTemplate + "\n## Iteration 1 (2026-01-02 10:00:00)\n\n- Use table tests\n  - nested detail\n\n## Iteration 2 (2026-01-02 11:00:00)\n\n- Mock the clock\n"
+	assert.Equal(t, []string{"- Use table tests", "  - nested detail", "- Mock the clock"}, Entries(content))
+	assert.Empty(t, Entries(learningsTemplate))
+}
+
+func TestPromote(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "learnings")
+
+	added, err := Promote(dir, []string{"go", "react"}, []string{"- Use table tests", "- Mock the clock"}, "shop")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"go": 2, "react": 2}, added)
+
+	added, err = Promote(dir, []string{"go"}, []string{"- Mock the clock", "- Keep handlers thin"}, "blog")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"go": 1}, added, "entries already in the library are skipped")
+
+	data, err := os.ReadFile(filepath.Join(dir, "go.md"))
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, "## From shop (")
+	assert.Contains(t, content, "## From blog (")
+	assert.Equal(t, 1, strings.Count(content, "- Mock the clock"))
+}
+
+func TestPromote_NoTags(t *testing.T) {
+	_, err := Promote(t.TempDir(), nil, []string{"- x"}, "p")
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 // Package logging provides colored, leveled log output for the ralph-loop CLI.
 //
-// All output functions write a prefixed, color-coded line. Debug output is
+// All output functions write a prefixed, color-coded line, with the
+// registered secrets redacted (see secrets.Register). Debug output is
 // suppressed unless verbose mode is enabled via SetVerbose(true).
 package logging
 
@@ -9,6 +10,8 @@ import (
 	"os"
 
 	"github.com/fatih/color"
+
+	"github.com/CodexForgeBR/cli-tools/internal/secrets"
 )
 
 // verbose controls whether Debug() produces output.
@@ -31,29 +34,29 @@ func SetVerbose(v bool) {
 
 // Info prints an informational message to stderr in blue.
 func Info(msg string) {
-	fmt.Fprintln(os.Stderr, infoPrefix("[INFO]")+" "+msg)
+	fmt.Fprintln(os.Stderr, infoPrefix("[INFO]")+" "+secrets.Redact(msg))
 }
 
 // Success prints a success message to stderr in green.
 func Success(msg string) {
-	fmt.Fprintln(os.Stderr, successPrefix("[SUCCESS]")+" "+msg)
+	fmt.Fprintln(os.Stderr, successPrefix("[SUCCESS]")+" "+secrets.Redact(msg))
 }
 
 // Warn prints a warning message to stderr in yellow.
 func Warn(msg string) {
-	fmt.Fprintln(os.Stderr, warnPrefix("[WARN]")+" "+msg)
+	fmt.Fprintln(os.Stderr, warnPrefix("[WARN]")+" "+secrets.Redact(msg))
 }
 
 // Error prints an error message to stderr in red.
 func Error(msg string) {
-	fmt.Fprintln(os.Stderr, errorPrefix("[ERROR]")+" "+msg)
+	fmt.Fprintln(os.Stderr, errorPrefix("[ERROR]")+" "+secrets.Redact(msg))
 }
 
 // Phase prints a phase header to stderr in cyan, surrounded by separator lines.
 func Phase(msg string) {
 	sep := phasePrefix("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Fprintln(os.Stderr, sep)
-	fmt.Fprintln(os.Stderr, phasePrefix("[PHASE]")+" "+msg)
+	fmt.Fprintln(os.Stderr, phasePrefix("[PHASE]")+" "+secrets.Redact(msg))
 	fmt.Fprintln(os.Stderr, sep)
 }
 
@@ -62,7 +65,7 @@ func Debug(msg string) {
 	if !verbose {
 		return
 	}
-	fmt.Fprintln(os.Stderr, debugPrefix("[DEBUG]")+" "+msg)
+	fmt.Fprintln(os.Stderr, debugPrefix("[DEBUG]")+" "+secrets.Redact(msg))
 }
 
 // FormatDuration converts a duration in seconds to a human-readable string.
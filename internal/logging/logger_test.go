@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/secrets"
 )
 
 func init() {
@@ -78,6 +79,16 @@ func TestInfoWritesToStderr(t *testing.T) {
 	assert.Contains(t, out, "test message")
 }
 
+func TestLogRedactsSecrets(t *testing.T) {
+	secrets.Register("https://hooks.example.com/secret")
+	t.Cleanup(secrets.Reset)
+	out := captureStderr(t, func() {
+		logging.Warn("webhook https://hooks.example.com/secret failed")
+	})
+	assert.Contains(t, out, "webhook [REDACTED] failed")
+	assert.NotContains(t, out, "hooks.example.com")
+}
+
 func TestSuccessWritesToStderr(t *testing.T) {
 	out := captureStderr(t, func() {
 		logging.Success("done")
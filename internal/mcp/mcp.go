@@ -0,0 +1,176 @@
+// Package mcp serves a ralph-loop session over the Model Context Protocol
+// so that other AI tools can read its status, history and tasks and cancel
+// or resume it. It speaks JSON-RPC 2.0, one message per line, on stdio.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// protocolVersions are the MCP revisions the server supports, newest first.
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// maxMessageSize bounds a single incoming message.
+const maxMessageSize = 10 << 20
+
+// Server answers MCP requests about the session in StateDir.
+type Server struct {
+	StateDir string
+	// Name and Version identify the server to clients.
+	Name, Version string
+	// Interrupt stops the loop running as pid, as Ctrl+C would. A nil
+	// Interrupt makes cancel_session unable to stop a running loop.
+	Interrupt func(pid int) error
+	// Resume starts `ralph-loop --resume` (--resume-force when force is
+	// set) in the background. A nil Resume disables resume_session.
+	Resume func(force bool) error
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Serve reads requests from in and writes responses to out until in is
+// exhausted or ctx is cancelled.
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	lines := make(chan []byte)
+	errCh := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(in)
+		scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		errCh <- scanner.Err()
+	}()
+
+	enc := json.NewEncoder(out)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errCh:
+			return err
+		case line := <-lines:
+			if len(line) == 0 {
+				continue
+			}
+			if resp := s.handle(line); resp != nil {
+				if err := enc.Encode(resp); err != nil {
+					return fmt.Errorf("write response: %w", err)
+				}
+			}
+		}
+	}
+}
+
+// handle answers one message; notifications get no response.
+func (s *Server) handle(line []byte) *response {
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return errorResponse(json.RawMessage("null"), codeParseError, "parse error: "+err.Error())
+	}
+	if req.ID == nil {
+		return nil
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, codeInvalidRequest, "invalid request")
+	}
+
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		return result(req.ID, map[string]any{
+			"protocolVersion": negotiateVersion(params.ProtocolVersion),
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]string{"name": s.Name, "version": s.Version},
+			"instructions":    "Query and control the ralph-loop session in " + s.StateDir + ".",
+		})
+	case "ping":
+		return result(req.ID, map[string]any{})
+	case "tools/list":
+		return result(req.ID, map[string]any{"tools": toolList()})
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
+			return errorResponse(req.ID, codeInvalidParams, "tools/call needs a tool name")
+		}
+		text, err := s.callTool(params.Name, params.Arguments)
+		if errors.Is(err, errUnknownTool) {
+			return errorResponse(req.ID, codeInvalidParams, "unknown tool "+params.Name)
+		}
+		return result(req.ID, toolResult(text, err))
+	default:
+		return errorResponse(req.ID, codeMethodNotFound, "method not found: "+req.Method)
+	}
+}
+
+// negotiateVersion returns the client's protocol version when supported,
+// otherwise the newest one the server speaks.
+func negotiateVersion(requested string) string {
+	for _, v := range protocolVersions {
+		if v == requested {
+			return v
+		}
+	}
+	return protocolVersions[0]
+}
+
+// toolResult wraps a tool's text, or its error, in a tools/call result.
+// Tool failures are results with isError set so the model can see them.
+func toolResult(text string, err error) map[string]any {
+	if err != nil {
+		return map[string]any{
+			"content": []map[string]string{{"type": "text", "text": err.Error()}},
+			"isError": true,
+		}
+	}
+	return map[string]any{"content": []map[string]string{{"type": "text", "text": text}}}
+}
+
+func result(id json.RawMessage, v any) *response {
+	return &response{JSONRPC: "2.0", ID: id, Result: v}
+}
+
+func errorResponse(id json.RawMessage, code int, msg string) *response {
+	return &response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: msg}}
+}
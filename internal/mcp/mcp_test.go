@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exchange sends messages to s and returns the decoded responses.
+func exchange(t *testing.T, s *Server, messages ...string) []map[string]any {
+	t.Helper()
+	var out bytes.Buffer
+	require.NoError(t, s.Serve(context.Background(), strings.NewReader(strings.Join(messages, "\n")+"\n"), &out))
+	var responses []map[string]any
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var resp map[string]any
+		require.NoError(t, dec.Decode(&resp))
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func TestServe_Initialize(t *testing.T) {
+	s := &Server{StateDir: ".ralph-loop", Name: "ralph-loop", Version: "1.2.3"}
+	responses := exchange(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":"two","method":"ping"}`,
+	)
+
+	require.Len(t, responses, 2, "notifications are not answered")
+	init := responses[0]["result"].(map[string]any)
+	assert.Equal(t, float64(1), responses[0]["id"])
+	assert.Equal(t, "2024-11-05", init["protocolVersion"])
+	assert.Equal(t, map[string]any{"name": "ralph-loop", "version": "1.2.3"}, init["serverInfo"])
+	assert.Contains(t, init["capabilities"], "tools")
+	assert.Equal(t, "two", responses[1]["id"])
+	assert.Equal(t, map[string]any{}, responses[1]["result"])
+}
+
+func TestNegotiateVersion(t *testing.T) {
+	assert.Equal(t, "2025-03-26", negotiateVersion("2025-03-26"))
+	assert.Equal(t, protocolVersions[0], negotiateVersion("1999-01-01"))
+	assert.Equal(t, protocolVersions[0], negotiateVersion(""))
+}
+
+func TestServe_Errors(t *testing.T) {
+	s := &Server{StateDir: t.TempDir()}
+	responses := exchange(t, s,
+		`not json`,
+		`{"jsonrpc":"1.0","id":1,"method":"ping"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"resources/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"format_disk"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{}}`,
+	)
+
+	require.Len(t, responses, 5)
+	codes := []float64{codeParseError, codeInvalidRequest, codeMethodNotFound, codeInvalidParams, codeInvalidParams}
+	for i, resp := range responses {
+		require.Contains(t, resp, "error", "response %d", i)
+		assert.Equal(t, codes[i], resp["error"].(map[string]any)["code"], "response %d", i)
+	}
+	assert.Nil(t, responses[0]["id"])
+}
+
+func TestServe_ToolsList(t *testing.T) {
+	responses := exchange(t, &Server{}, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+
+	require.Len(t, responses, 1)
+	var names []string
+	for _, tl := range responses[0]["result"].(map[string]any)["tools"].([]any) {
+		entry := tl.(map[string]any)
+		names = append(names, entry["name"].(string))
+		assert.NotEmpty(t, entry["description"])
+		assert.Equal(t, "object", entry["inputSchema"].(map[string]any)["type"])
+	}
+	assert.Equal(t, []string{"get_status", "get_history", "get_iteration_output", "get_tasks", "cancel_session", "resume_session"}, names)
+}
+
+func TestServe_StopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r, w := io.Pipe()
+	defer w.Close()
+	assert.NoError(t, (&Server{}).Serve(ctx, r, &bytes.Buffer{}))
+}
@@ -0,0 +1,221 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+	"github.com/CodexForgeBR/cli-tools/internal/web"
+)
+
+var errUnknownTool = errors.New("unknown tool")
+
+// tool describes one entry of tools/list.
+type tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+func noArguments() map[string]any {
+	return map[string]any{"type": "object", "properties": map[string]any{}}
+}
+
+func toolList() []tool {
+	return []tool{
+		{
+			Name:        "get_status",
+			Description: "The session's status, phase, iteration, last verdict and feedback, task counts, and whether a loop is running.",
+			InputSchema: noArguments(),
+		},
+		{
+			Name:        "get_history",
+			Description: "One entry per iteration with the validator's verdict, confidence and feedback, and the shell commands the implementer ran with their exit codes.",
+			InputSchema: noArguments(),
+		},
+		{
+			Name:        "get_iteration_output",
+			Description: "The implementation or validation output of an iteration, or the JSON transcript of the commands the implementer ran.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"iteration": map[string]any{"type": "integer", "minimum": 1},
+					"kind":      map[string]any{"type": "string", "enum": []string{"implementation", "validation", "commands"}},
+				},
+				"required": []string{"iteration", "kind"},
+			},
+		},
+		{
+			Name:        "get_tasks",
+			Description: "The tasks in the session's tasks file and whether each is checked or blocked.",
+			InputSchema: noArguments(),
+		},
+		{
+			Name:        "cancel_session",
+			Description: "Interrupt the running loop, which saves its state so it can be resumed, or mark a stopped session as cancelled.",
+			InputSchema: noArguments(),
+		},
+		{
+			Name:        "resume_session",
+			Description: "Start `ralph-loop --resume` in the background to continue a stopped session.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"force": map[string]any{"type": "boolean", "description": "Resume even if the session completed or was cancelled (--resume-force)"},
+				},
+			},
+		},
+	}
+}
+
+// statusResult is the get_status result.
+type statusResult struct {
+	web.Status
+	Running bool            `json:"running"`
+	Lock    *state.LockInfo `json:"lock,omitempty"`
+}
+
+// taskResult is one get_tasks entry.
+type taskResult struct {
+	ID            string   `json:"id"`
+	Text          string   `json:"text"`
+	Checked       bool     `json:"checked"`
+	Line          int      `json:"line"`
+	BlockedReason string   `json:"blocked_reason,omitempty"`
+	Depends       []string `json:"depends,omitempty"`
+}
+
+// callTool runs the named tool and returns its text result.
+func (s *Server) callTool(name string, args json.RawMessage) (string, error) {
+	switch name {
+	case "get_status":
+		st, err := web.ReadStatus(s.StateDir)
+		if err != nil {
+			return "", fmt.Errorf("no session in %s: %w", s.StateDir, err)
+		}
+		holder := state.RunningLoop(s.StateDir)
+		return marshal(statusResult{Status: st, Running: holder != nil, Lock: holder})
+	case "get_history":
+		return marshal(web.ReadIterations(s.StateDir))
+	case "get_iteration_output":
+		var params struct {
+			Iteration int    `json:"iteration"`
+			Kind      string `json:"kind"`
+		}
+		if err := json.Unmarshal(orEmpty(args), &params); err != nil {
+			return "", fmt.Errorf("invalid arguments: %w", err)
+		}
+		data, err := web.ReadIterationOutput(s.StateDir, params.Iteration, params.Kind)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case "get_tasks":
+		return s.listTasks()
+	case "cancel_session":
+		return s.cancel()
+	case "resume_session":
+		var params struct {
+			Force bool `json:"force"`
+		}
+		if err := json.Unmarshal(orEmpty(args), &params); err != nil {
+			return "", fmt.Errorf("invalid arguments: %w", err)
+		}
+		return s.resume(params.Force)
+	default:
+		return "", errUnknownTool
+	}
+}
+
+func (s *Server) listTasks() (string, error) {
+	session, err := state.LoadState(s.StateDir)
+	if err != nil {
+		return "", fmt.Errorf("no session in %s: %w", s.StateDir, err)
+	}
+	list, err := tasks.ListTasks(session.TasksFile)
+	if err != nil {
+		return "", err
+	}
+	results := make([]taskResult, 0, len(list))
+	for _, t := range list {
+		results = append(results, taskResult{
+			ID:            t.ID,
+			Text:          t.Text,
+			Checked:       t.Checked,
+			Line:          t.Line,
+			BlockedReason: t.BlockedReason,
+			Depends:       t.Depends,
+		})
+	}
+	return marshal(results)
+}
+
+// cancel interrupts the running loop, or marks a stopped session
+// cancelled as --cancel does.
+func (s *Server) cancel() (string, error) {
+	if holder := state.RunningLoop(s.StateDir); holder != nil {
+		hostname, _ := os.Hostname()
+		if holder.Hostname != hostname {
+			return "", fmt.Errorf("the loop is running on %s (pid %d) and cannot be interrupted from here", holder.Hostname, holder.PID)
+		}
+		if s.Interrupt == nil {
+			return "", fmt.Errorf("interrupting the running loop (pid %d) is not available", holder.PID)
+		}
+		if err := s.Interrupt(holder.PID); err != nil {
+			return "", fmt.Errorf("interrupt pid %d: %w", holder.PID, err)
+		}
+		return fmt.Sprintf("Interrupted the loop (pid %d); it saves its state and can be resumed.", holder.PID), nil
+	}
+
+	lock, err := state.AcquireLock(s.StateDir, false)
+	if err != nil {
+		return "", err
+	}
+	defer lock.Release()
+	session, err := state.LoadState(s.StateDir)
+	if err != nil {
+		return "", fmt.Errorf("no session in %s: %w", s.StateDir, err)
+	}
+	session.Status = state.StatusCancelled
+	if err := state.SaveState(session, s.StateDir); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Session %s cancelled.", session.SessionID), nil
+}
+
+// resume starts a loop continuing the stopped session.
+func (s *Server) resume(force bool) (string, error) {
+	if s.Resume == nil {
+		return "", errors.New("resuming is not available")
+	}
+	if holder := state.RunningLoop(s.StateDir); holder != nil {
+		return "", fmt.Errorf("a loop is already running (pid %d on %s)", holder.PID, holder.Hostname)
+	}
+	session, err := state.LoadState(s.StateDir)
+	if err != nil {
+		return "", fmt.Errorf("no session to resume in %s: %w", s.StateDir, err)
+	}
+	if err := s.Resume(force); err != nil {
+		return "", fmt.Errorf("start the loop: %w", err)
+	}
+	return fmt.Sprintf("Resuming session %s from iteration %d; follow it with get_status.", session.SessionID, session.Iteration), nil
+}
+
+// orEmpty treats missing arguments as an empty object.
+func orEmpty(args json.RawMessage) json.RawMessage {
+	if len(args) == 0 || string(args) == "null" {
+		return json.RawMessage("{}")
+	}
+	return args
+}
+
+func marshal(v any) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,156 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+func newTestSession(t *testing.T) *Server {
+	t.Helper()
+	dir := t.TempDir()
+	tasksFile := filepath.Join(dir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [x] T001 Parser\n- [ ] T002 Printer {depends: T001}\n"), 0644))
+	require.NoError(t, state.SaveState(&state.SessionState{
+		SessionID:    "s-1",
+		Iteration:    2,
+		Status:       state.StatusInterrupted,
+		Verdict:      "NEEDS_MORE_WORK",
+		TasksFile:    tasksFile,
+		LastFeedback: base64.StdEncoding.EncodeToString([]byte("print the AST")),
+	}, dir))
+	iterDir := filepath.Join(dir, "iteration-001")
+	require.NoError(t, os.MkdirAll(iterDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(iterDir, "implementation-output.txt"), []byte("wrote parser.go"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(iterDir, "validation-output.txt"),
+		[]byte(`{"RALPH_VALIDATION":{"verdict":"NEEDS_MORE_WORK","feedback":"print the AST"}}`), 0644))
+	return &Server{StateDir: dir}
+}
+
+func TestCallTool_Status(t *testing.T) {
+	s := newTestSession(t)
+
+	text, err := s.callTool("get_status", nil)
+	require.NoError(t, err)
+	var st statusResult
+	require.NoError(t, json.Unmarshal([]byte(text), &st))
+	assert.Equal(t, "s-1", st.Session.SessionID)
+	assert.Equal(t, "print the AST", st.Feedback)
+	assert.Equal(t, 1, st.TasksDone)
+	assert.Equal(t, 2, st.TasksTotal)
+	assert.False(t, st.Running)
+
+	_, err = (&Server{StateDir: t.TempDir()}).callTool("get_status", nil)
+	assert.ErrorContains(t, err, "no session")
+}
+
+func TestCallTool_HistoryAndOutput(t *testing.T) {
+	s := newTestSession(t)
+
+	text, err := s.callTool("get_history", nil)
+	require.NoError(t, err)
+	assert.Contains(t, text, `"verdict": "NEEDS_MORE_WORK"`)
+
+	text, err = s.callTool("get_iteration_output", json.RawMessage(`{"iteration":1,"kind":"implementation"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "wrote parser.go", text)
+
+	_, err = s.callTool("get_iteration_output", json.RawMessage(`{"iteration":1,"kind":"diff"}`))
+	assert.ErrorContains(t, err, "unknown output")
+	_, err = s.callTool("get_iteration_output", json.RawMessage(`{"iteration":7,"kind":"validation"}`))
+	assert.Error(t, err)
+}
+
+func TestCallTool_Tasks(t *testing.T) {
+	s := newTestSession(t)
+
+	text, err := s.callTool("get_tasks", nil)
+	require.NoError(t, err)
+	var list []taskResult
+	require.NoError(t, json.Unmarshal([]byte(text), &list))
+	require.Len(t, list, 2)
+	assert.Equal(t, taskResult{ID: "T001", Text: "T001 Parser", Checked: true, Line: 1}, list[0])
+	assert.Equal(t, []string{"T001"}, list[1].Depends)
+}
+
+func TestCallTool_CancelStoppedSession(t *testing.T) {
+	s := newTestSession(t)
+
+	text, err := s.callTool("cancel_session", nil)
+	require.NoError(t, err)
+	assert.Contains(t, text, "s-1 cancelled")
+	session, err := state.LoadState(s.StateDir)
+	require.NoError(t, err)
+	assert.Equal(t, state.StatusCancelled, session.Status)
+	assert.Nil(t, state.RunningLoop(s.StateDir), "the lock is released")
+}
+
+func TestCallTool_CancelRunningLoop(t *testing.T) {
+	s := newTestSession(t)
+	lock, err := state.AcquireLock(s.StateDir, false)
+	require.NoError(t, err)
+	defer lock.Release()
+
+	_, err = s.callTool("cancel_session", nil)
+	assert.ErrorContains(t, err, "not available")
+
+	var interrupted int
+	s.Interrupt = func(pid int) error {
+		interrupted = pid
+		return nil
+	}
+	text, err := s.callTool("cancel_session", nil)
+	require.NoError(t, err)
+	assert.Equal(t, os.Getpid(), interrupted)
+	assert.Contains(t, text, "Interrupted the loop")
+
+	session, err := state.LoadState(s.StateDir)
+	require.NoError(t, err)
+	assert.Equal(t, state.StatusInterrupted, session.Status, "the loop records its own state")
+}
+
+func TestCallTool_Resume(t *testing.T) {
+	s := newTestSession(t)
+	_, err := s.callTool("resume_session", nil)
+	assert.ErrorContains(t, err, "not available")
+
+	var forced []bool
+	s.Resume = func(force bool) error {
+		forced = append(forced, force)
+		return nil
+	}
+	text, err := s.callTool("resume_session", nil)
+	require.NoError(t, err)
+	assert.Contains(t, text, "Resuming session s-1 from iteration 2")
+	_, err = s.callTool("resume_session", json.RawMessage(`{"force":true}`))
+	require.NoError(t, err)
+	assert.Equal(t, []bool{false, true}, forced)
+
+	s.Resume = func(bool) error { return errors.New("exec failed") }
+	_, err = s.callTool("resume_session", nil)
+	assert.ErrorContains(t, err, "exec failed")
+
+	lock, err := state.AcquireLock(s.StateDir, false)
+	require.NoError(t, err)
+	defer lock.Release()
+	_, err = s.callTool("resume_session", nil)
+	assert.ErrorContains(t, err, "already running")
+}
+
+func TestServe_ToolErrorsAreResults(t *testing.T) {
+	responses := exchange(t, &Server{StateDir: t.TempDir()},
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_status"}}`)
+
+	require.Len(t, responses, 1)
+	res := responses[0]["result"].(map[string]any)
+	assert.Equal(t, true, res["isError"])
+	assert.Contains(t, res["content"].([]any)[0].(map[string]any)["text"], "no session")
+}
@@ -0,0 +1,242 @@
+// Package metrics tracks ralph-loop run statistics and exposes them in the
+// Prometheus text exposition format, either on a localhost /metrics endpoint
+// or as a textfile for the node_exporter textfile collector.
+//
+// All Registry methods are safe for concurrent use and do nothing on a nil
+// *Registry, so callers need not check whether metrics are enabled.
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Registry holds the metrics of one run.
+type Registry struct {
+	mu           sync.Mutex
+	startTime    time.Time
+	iterations   int
+	retries      int
+	rateLimits   int
+	inadmissible int
+	exitCode     *int
+	verdicts     map[[2]string]int // {phase, verdict} -> count
+	phases       map[string]*duration
+	promptTokens map[string]int // phase -> estimated tokens of its last prompt
+}
+
+type duration struct {
+	count int
+	sum   float64
+}
+
+// New returns an empty registry whose start time is now.
+func New() *Registry {
+	return &Registry{
+		startTime:    time.Now(),
+		verdicts:     make(map[[2]string]int),
+		phases:       make(map[string]*duration),
+		promptTokens: make(map[string]int),
+	}
+}
+
+// IncIteration counts a started loop iteration.
+func (r *Registry) IncIteration() {
+	r.update(func() { r.iterations++ })
+}
+
+// IncRetry counts a retried AI invocation.
+func (r *Registry) IncRetry() {
+	r.update(func() { r.retries++ })
+}
+
+// IncRateLimit counts a detected provider rate limit.
+func (r *Registry) IncRateLimit() {
+	r.update(func() { r.rateLimits++ })
+}
+
+// SetInadmissible records the current inadmissible verdict count.
+func (r *Registry) SetInadmissible(n int) {
+	r.update(func() { r.inadmissible = n })
+}
+
+// SetExitCode records the exit code of a finished run.
+func (r *Registry) SetExitCode(code int) {
+	r.update(func() { r.exitCode = &code })
+}
+
+// ObserveVerdict counts a verdict returned by the given phase.
+func (r *Registry) ObserveVerdict(phase, verdict string) {
+	r.update(func() { r.verdicts[[2]string{phase, verdict}]++ })
+}
+
+// ObservePhase records how long one run of a phase took.
+func (r *Registry) ObservePhase(phase string, d time.Duration) {
+	r.update(func() {
+		p := r.phases[phase]
+		if p == nil {
+			p = &duration{}
+			r.phases[phase] = p
+		}
+		p.count++
+		p.sum += d.Seconds()
+	})
+}
+
+// SetPromptTokens records the estimated size of the last prompt built for
+// the given phase.
+func (r *Registry) SetPromptTokens(phase string, tokens int) {
+	r.update(func() { r.promptTokens[phase] = tokens })
+}
+
+func (r *Registry) update(f func()) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f()
+}
+
+// WriteTo writes every metric in the Prometheus text format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	if r == nil {
+		return 0, nil
+	}
+	r.mu.Lock()
+	var buf bytes.Buffer
+	writeMetric(&buf, "ralph_loop_start_time_seconds", "gauge", "Unix time the run started.",
+		sample{value: float64(r.startTime.Unix())})
+	writeMetric(&buf, "ralph_loop_iterations_total", "counter", "Loop iterations started.",
+		sample{value: float64(r.iterations)})
+	writeMetric(&buf, "ralph_loop_retries_total", "counter", "AI invocations retried after a failure.",
+		sample{value: float64(r.retries)})
+	writeMetric(&buf, "ralph_loop_rate_limits_total", "counter", "Provider rate limits detected.",
+		sample{value: float64(r.rateLimits)})
+	writeMetric(&buf, "ralph_loop_inadmissible", "gauge", "Current inadmissible verdict count.",
+		sample{value: float64(r.inadmissible)})
+
+	verdicts := make([]sample, 0, len(r.verdicts))
+	for k, n := range r.verdicts {
+		verdicts = append(verdicts, sample{labels: fmt.Sprintf(`phase=%q,verdict=%q`, k[0], k[1]), value: float64(n)})
+	}
+	writeMetric(&buf, "ralph_loop_verdicts_total", "counter", "Verdicts returned, by phase.", verdicts...)
+
+	if len(r.phases) > 0 {
+		var sums, counts []sample
+		for name, p := range r.phases {
+			labels := fmt.Sprintf("phase=%q", name)
+			sums = append(sums, sample{suffix: "_sum", labels: labels, value: p.sum})
+			counts = append(counts, sample{suffix: "_count", labels: labels, value: float64(p.count)})
+		}
+		writeMetric(&buf, "ralph_loop_phase_duration_seconds", "summary", "Time spent in each phase.", append(sums, counts...)...)
+	}
+
+	prompts := make([]sample, 0, len(r.promptTokens))
+	for phase, n := range r.promptTokens {
+		prompts = append(prompts, sample{labels: fmt.Sprintf("phase=%q", phase), value: float64(n)})
+	}
+	writeMetric(&buf, "ralph_loop_prompt_tokens", "gauge", "Estimated tokens of the last prompt, by phase.", prompts...)
+
+	if r.exitCode != nil {
+		writeMetric(&buf, "ralph_loop_exit_code", "gauge", "Exit code of the finished run.",
+			sample{value: float64(*r.exitCode)})
+	}
+	r.mu.Unlock()
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+type sample struct {
+	suffix string
+	labels string
+	value  float64
+}
+
+// writeMetric writes the HELP and TYPE lines followed by the samples sorted
+// by suffix and labels, so output is stable across scrapes.
+func writeMetric(buf *bytes.Buffer, name, typ, help string, samples ...sample) {
+	if len(samples) == 0 {
+		return
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		if samples[i].suffix != samples[j].suffix {
+			return samples[i].suffix > samples[j].suffix // _sum before _count
+		}
+		return samples[i].labels < samples[j].labels
+	})
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	for _, s := range samples {
+		if s.labels != "" {
+			fmt.Fprintf(buf, "%s%s{%s} %g\n", name, s.suffix, s.labels, s.value)
+		} else {
+			fmt.Fprintf(buf, "%s%s %g\n", name, s.suffix, s.value)
+		}
+	}
+}
+
+// Handler serves the metrics at any path.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = r.WriteTo(w)
+	})
+}
+
+// Serve starts an HTTP server exposing /metrics on addr (e.g.
+// "127.0.0.1:9464") in the background. The listener is opened before Serve
+// returns, so address errors are reported immediately, and the returned
+// server's Addr holds the bound address (useful with port 0). Close the
+// server to stop it.
+func (r *Registry) Serve(addr string) (*http.Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %w", addr, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", r.Handler())
+	srv := &http.Server{Addr: ln.Addr().String(), Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "metrics server stopped: %v\n", err)
+		}
+	}()
+	return srv, nil
+}
+
+// WriteTextfile writes the metrics to path atomically (via a temporary file
+// and rename), as the node_exporter textfile collector requires.
+func (r *Registry) WriteTextfile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create metrics dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".ralph-loop-metrics-*")
+	if err != nil {
+		return fmt.Errorf("create metrics file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := r.WriteTo(tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write metrics file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write metrics file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("write metrics file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write metrics file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func render(t *testing.T, r *Registry) string {
+	t.Helper()
+	var buf bytes.Buffer
+	_, err := r.WriteTo(&buf)
+	require.NoError(t, err)
+	return buf.String()
+}
+
+func TestWriteTo_Counters(t *testing.T) {
+	r := New()
+	r.IncIteration()
+	r.IncIteration()
+	r.IncRetry()
+	r.IncRateLimit()
+	r.SetInadmissible(3)
+
+	out := render(t, r)
+	assert.Contains(t, out, "# TYPE ralph_loop_iterations_total counter\nralph_loop_iterations_total 2\n")
+	assert.Contains(t, out, "ralph_loop_retries_total 1\n")
+	assert.Contains(t, out, "ralph_loop_rate_limits_total 1\n")
+	assert.Contains(t, out, "ralph_loop_inadmissible 3\n")
+	assert.Contains(t, out, "ralph_loop_start_time_seconds ")
+	assert.NotContains(t, out, "ralph_loop_exit_code", "exit code is only reported once set")
+	assert.NotContains(t, out, "ralph_loop_verdicts_total", "empty label sets are omitted")
+}
+
+func TestWriteTo_VerdictsAndPhases(t *testing.T) {
+	r := New()
+	r.ObserveVerdict("validation", "NEEDS_MORE_WORK")
+	r.ObserveVerdict("validation", "NEEDS_MORE_WORK")
+	r.ObserveVerdict("validation", "COMPLETE")
+	r.ObservePhase("implementation", 1500*time.Millisecond)
+	r.ObservePhase("implementation", 500*time.Millisecond)
+	r.SetPromptTokens("implementation", 12000)
+	r.SetExitCode(0)
+
+	out := render(t, r)
+	assert.Contains(t, out, `ralph_loop_verdicts_total{phase="validation",verdict="COMPLETE"} 1`)
+	assert.Contains(t, out, `ralph_loop_verdicts_total{phase="validation",verdict="NEEDS_MORE_WORK"} 2`)
+	assert.Contains(t, out, "# TYPE ralph_loop_phase_duration_seconds summary\n"+
+		`ralph_loop_phase_duration_seconds_sum{phase="implementation"} 2`+"\n"+
+		`ralph_loop_phase_duration_seconds_count{phase="implementation"} 2`+"\n")
+	assert.Contains(t, out, `ralph_loop_prompt_tokens{phase="implementation"} 12000`)
+	assert.Contains(t, out, "ralph_loop_exit_code 0\n")
+}
+
+func TestWriteTo_StableOrder(t *testing.T) {
+	r := New()
+	for _, v := range []string{"C", "A", "B"} {
+		r.ObserveVerdict("validation", v)
+	}
+	assert.Equal(t, render(t, r), render(t, r))
+}
+
+func TestNilRegistry(t *testing.T) {
+	var r *Registry
+	assert.NotPanics(t, func() {
+		r.IncIteration()
+		r.IncRetry()
+		r.IncRateLimit()
+		r.SetInadmissible(1)
+		r.SetExitCode(1)
+		r.ObserveVerdict("validation", "COMPLETE")
+		r.ObservePhase("validation", time.Second)
+		r.SetPromptTokens("validation", 1)
+	})
+	assert.Empty(t, render(t, r))
+}
+
+func TestWriteTextfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "textfile", "ralph-loop.prom")
+	r := New()
+	r.IncIteration()
+
+	require.NoError(t, r.WriteTextfile(path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "ralph_loop_iterations_total 1\n")
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary file should be renamed away")
+}
+
+func TestHandler(t *testing.T) {
+	r := New()
+	r.IncRetry()
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain; version=0.0.4")
+	assert.Contains(t, rec.Body.String(), "ralph_loop_retries_total 1\n")
+}
+
+func TestServe(t *testing.T) {
+	r := New()
+	r.IncIteration()
+	srv, err := r.Serve("127.0.0.1:0")
+	require.NoError(t, err)
+	defer srv.Close()
+
+	resp, err := http.Get("http://" + srv.Addr + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), "ralph_loop_iterations_total 1\n")
+
+	resp2, err := http.Get("http://" + srv.Addr + "/other")
+	require.NoError(t, err)
+	resp2.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp2.StatusCode)
+}
+
+func TestServe_InvalidAddr(t *testing.T) {
+	_, err := New().Serve("not-an-address")
+	assert.Error(t, err)
+}
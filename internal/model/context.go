@@ -0,0 +1,42 @@
+package model
+
+import "strings"
+
+// contextWindows maps model name prefixes to their context window in
+// tokens. Longer prefixes are listed before shorter ones they extend.
+var contextWindows = []struct {
+	prefix string
+	tokens int
+}{
+	{"opus", 200000},
+	{"sonnet", 200000},
+	{"haiku", 200000},
+	{"claude", 200000},
+	{"gpt-5", 400000},
+	{"gpt-4.1", 1000000},
+	{"o3", 200000},
+	{"o4", 200000},
+}
+
+// copilotMaxWindow is the context window gh copilot allows whatever the
+// model's own.
+const copilotMaxWindow = 128000
+
+// ContextWindow returns the context window, in tokens, of model on the
+// given AI backend. Unknown models get the backend's usual window.
+func ContextWindow(ai, model string) int {
+	tokens := 200000
+	if ai == Codex {
+		tokens = 400000 // "default" is a GPT-5 model
+	}
+	for _, w := range contextWindows {
+		if strings.HasPrefix(model, w.prefix) {
+			tokens = w.tokens
+			break
+		}
+	}
+	if ai == Copilot {
+		tokens = min(tokens, copilotMaxWindow)
+	}
+	return tokens
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextWindow(t *testing.T) {
+	assert.Equal(t, 200000, ContextWindow(Claude, "opus"))
+	assert.Equal(t, 200000, ContextWindow(AmazonQ, "claude-sonnet-4"))
+	assert.Equal(t, 400000, ContextWindow(Codex, "gpt-5-codex"))
+	assert.Equal(t, 200000, ContextWindow(Codex, "o4-mini"))
+	assert.Equal(t, 400000, ContextWindow(Codex, "default"))
+	assert.Equal(t, 128000, ContextWindow(Copilot, "claude-sonnet-4.5"))
+	assert.Equal(t, 128000, ContextWindow(Copilot, "gpt-5"))
+	assert.Equal(t, 200000, ContextWindow(Claude, "claude-opus-4-1-20250805"))
+}
@@ -2,47 +2,90 @@
 //
 // It centralises default model names, opposite-AI resolution, and
 // validation that a requested model is compatible with the chosen
-// AI backend (claude or codex).
+// AI backend (claude, codex, amazonq or copilot).
 package model
 
 // AI backend identifiers used throughout the CLI.
 const (
-	Claude = "claude"
-	Codex  = "codex"
+	Claude  = "claude"
+	Codex   = "codex"
+	AmazonQ = "amazonq" // Amazon Q Developer CLI (q chat)
+	Copilot = "copilot" // GitHub Copilot CLI (gh copilot)
 )
 
+// Providers lists every supported AI backend.
+var Providers = []string{Claude, Codex, AmazonQ, Copilot}
+
+// IsProvider reports whether ai is a supported AI backend.
+func IsProvider(ai string) bool {
+	for _, p := range Providers {
+		if ai == p {
+			return true
+		}
+	}
+	return false
+}
+
 // DefaultImplModel returns the default implementation-phase model
 // for the given AI backend.
 func DefaultImplModel(ai string) string {
-	if ai == Claude {
-		return "opus"
-	}
-	return "default"
+	return DefaultModelForAI(ai)
 }
 
 // DefaultValModel returns the default validation-phase model
 // for the given AI backend.
 func DefaultValModel(ai string) string {
-	if ai == Claude {
-		return "opus"
+	return DefaultModelForAI(ai)
+}
+
+// DefaultSummaryModel returns the small, cheap model used to summarize
+// long implementation output for the given AI backend.
+func DefaultSummaryModel(ai string) string {
+	switch ai {
+	case Claude:
+		return "haiku"
+	case AmazonQ:
+		// q chat offers no small model.
+		return DefaultModelForAI(ai)
+	case Copilot:
+		return "claude-haiku-4.5"
 	}
-	return "default"
+	return "o4-mini"
 }
 
-// OppositeAI returns the counterpart AI backend:
-// claude -> codex, codex -> claude.
+// OppositeAI returns the counterpart AI backend: codex -> claude, and
+// codex for the others, whose default models are Claude models.
 func OppositeAI(ai string) string {
-	if ai == Claude {
-		return Codex
+	if ai == Codex {
+		return Claude
 	}
-	return Claude
+	return Codex
 }
 
 // DefaultModelForAI returns the general-purpose default model
 // for the given AI backend.
 func DefaultModelForAI(ai string) string {
-	if ai == Claude {
+	switch ai {
+	case Claude:
 		return "opus"
+	case AmazonQ:
+		return "claude-sonnet-4"
+	case Copilot:
+		return "claude-sonnet-4.5"
 	}
 	return "default"
 }
+
+// KnownModels returns the model names offered by shell completion for the
+// given AI backend. Other names are still accepted.
+func KnownModels(ai string) []string {
+	switch ai {
+	case Claude:
+		return []string{"opus", "sonnet", "haiku"}
+	case AmazonQ:
+		return []string{"claude-sonnet-4", "claude-3.7-sonnet", "claude-3.5-sonnet"}
+	case Copilot:
+		return []string{"claude-sonnet-4.5", "claude-sonnet-4", "claude-haiku-4.5", "gpt-5"}
+	}
+	return []string{"default", "gpt-5", "gpt-5-codex", "o3", "o4-mini"}
+}
@@ -9,6 +9,8 @@ import (
 func TestDefaultImplModel(t *testing.T) {
 	assert.Equal(t, "opus", DefaultImplModel(Claude), "claude impl default should be opus")
 	assert.Equal(t, "default", DefaultImplModel(Codex), "codex impl default should be default")
+	assert.Equal(t, "claude-sonnet-4", DefaultImplModel(AmazonQ))
+	assert.Equal(t, "claude-sonnet-4.5", DefaultImplModel(Copilot))
 }
 
 func TestDefaultValModel(t *testing.T) {
@@ -16,9 +18,18 @@ func TestDefaultValModel(t *testing.T) {
 	assert.Equal(t, "default", DefaultValModel(Codex), "codex val default should be default")
 }
 
+func TestDefaultSummaryModel(t *testing.T) {
+	assert.Equal(t, "haiku", DefaultSummaryModel(Claude))
+	assert.Equal(t, "o4-mini", DefaultSummaryModel(Codex))
+	assert.Equal(t, "claude-sonnet-4", DefaultSummaryModel(AmazonQ))
+	assert.Equal(t, "claude-haiku-4.5", DefaultSummaryModel(Copilot))
+}
+
 func TestOppositeAI(t *testing.T) {
 	assert.Equal(t, Codex, OppositeAI(Claude), "opposite of claude is codex")
 	assert.Equal(t, Claude, OppositeAI(Codex), "opposite of codex is claude")
+	assert.Equal(t, Codex, OppositeAI(AmazonQ), "amazonq serves claude models")
+	assert.Equal(t, Codex, OppositeAI(Copilot), "copilot defaults to a claude model")
 }
 
 func TestDefaultModelForAI(t *testing.T) {
@@ -37,3 +48,20 @@ func TestAutoOppositeModelSelection(t *testing.T) {
 	assert.Equal(t, "opus", DefaultModelForAI(opposite),
 		"switching from codex should yield claude default model")
 }
+
+func TestKnownModels(t *testing.T) {
+	assert.Contains(t, KnownModels(Claude), DefaultModelForAI(Claude))
+	assert.Contains(t, KnownModels(Codex), DefaultModelForAI(Codex))
+	assert.NotContains(t, KnownModels(Codex), "opus")
+	for _, ai := range Providers {
+		assert.Contains(t, KnownModels(ai), DefaultModelForAI(ai), ai)
+	}
+}
+
+func TestIsProvider(t *testing.T) {
+	for _, ai := range []string{Claude, Codex, AmazonQ, Copilot} {
+		assert.True(t, IsProvider(ai), ai)
+	}
+	assert.False(t, IsProvider("q"))
+	assert.False(t, IsProvider(""))
+}
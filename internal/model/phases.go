@@ -0,0 +1,90 @@
+package model
+
+// Phase names a phase that runs an AI, by its config key prefix.
+type Phase string
+
+// The phases that run an AI.
+const (
+	PhaseImpl      Phase = "IMPL"
+	PhaseVal       Phase = "VAL"
+	PhaseCross     Phase = "CROSS"
+	PhaseFinalPlan Phase = "FINAL_PLAN"
+	PhaseTasksVal  Phase = "TASKS_VAL"
+	PhaseSummary   Phase = "SUMMARY"
+	PhaseTriage    Phase = "TRIAGE"
+)
+
+// Assignment is the AI backend and model a phase runs on. Empty fields
+// are filled in by Resolve.
+type Assignment struct {
+	AI    string
+	Model string
+}
+
+// phaseRule says how a phase fills in the AI and model it was not given.
+type phaseRule struct {
+	phase Phase
+	// from is the phase whose AI this one runs on by default; empty
+	// means Claude.
+	from Phase
+	// opposite runs on the counterpart of from's AI instead (see
+	// OppositeAI), so the phase gets a second opinion.
+	opposite bool
+	// inheritModel takes from's model when both run on the same AI.
+	inheritModel bool
+	// defaultModel gives the model otherwise.
+	defaultModel func(ai string) string
+}
+
+// phaseTable lists every phase after the one it defaults from. A new
+// phase only needs a row here.
+var phaseTable = []phaseRule{
+	{phase: PhaseImpl, defaultModel: DefaultImplModel},
+	{phase: PhaseVal, from: PhaseImpl, defaultModel: DefaultValModel},
+	{phase: PhaseCross, from: PhaseImpl, opposite: true, defaultModel: DefaultModelForAI},
+	{phase: PhaseFinalPlan, from: PhaseCross, inheritModel: true, defaultModel: DefaultModelForAI},
+	{phase: PhaseTasksVal, from: PhaseImpl, inheritModel: true, defaultModel: DefaultImplModel},
+	{phase: PhaseSummary, from: PhaseImpl, defaultModel: DefaultSummaryModel},
+	{phase: PhaseTriage, from: PhaseVal, defaultModel: DefaultSummaryModel},
+}
+
+// Phases returns every phase, in the order Resolve fills them in.
+func Phases() []Phase {
+	phases := make([]Phase, len(phaseTable))
+	for i, r := range phaseTable {
+		phases[i] = r.phase
+	}
+	return phases
+}
+
+// Resolve returns the AI and model of every phase. Each phase keeps what
+// set gives it; an unset AI follows the phase it defaults from (the
+// implementation AI for most, its counterpart for cross-validation, and
+// the cross-validation AI for final-plan validation), and an unset model
+// is that phase's model when the AIs match, else the AI's default.
+func Resolve(set map[Phase]Assignment) map[Phase]Assignment {
+	resolved := make(map[Phase]Assignment, len(phaseTable))
+	for _, r := range phaseTable {
+		a := set[r.phase]
+		from, hasFrom := resolved[r.from]
+		if a.AI == "" {
+			switch {
+			case !hasFrom:
+				a.AI = Claude
+			case r.opposite:
+				a.AI = OppositeAI(from.AI)
+			default:
+				a.AI = from.AI
+			}
+		}
+		if a.Model == "" {
+			if r.inheritModel && hasFrom && from.AI == a.AI {
+				a.Model = from.Model
+			} else {
+				a.Model = r.defaultModel(a.AI)
+			}
+		}
+		resolved[r.phase] = a
+	}
+	return resolved
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve_Defaults(t *testing.T) {
+	got := Resolve(nil)
+	assert.Equal(t, map[Phase]Assignment{
+		PhaseImpl:      {Claude, "opus"},
+		PhaseVal:       {Claude, "opus"},
+		PhaseCross:     {Codex, "default"},
+		PhaseFinalPlan: {Codex, "default"},
+		PhaseTasksVal:  {Claude, "opus"},
+		PhaseSummary:   {Claude, "haiku"},
+		PhaseTriage:    {Claude, "haiku"},
+	}, got)
+}
+
+func TestResolve_FollowsImplementationAI(t *testing.T) {
+	got := Resolve(map[Phase]Assignment{PhaseImpl: {AI: Codex, Model: "gpt-5"}})
+	assert.Equal(t, Assignment{Codex, "default"}, got[PhaseVal])
+	assert.Equal(t, Assignment{Claude, "opus"}, got[PhaseCross], "cross-validation uses the opposite AI")
+	assert.Equal(t, Assignment{Claude, "opus"}, got[PhaseFinalPlan])
+	assert.Equal(t, Assignment{Codex, "gpt-5"}, got[PhaseTasksVal], "tasks validation uses the implementation model")
+	assert.Equal(t, Assignment{Codex, "o4-mini"}, got[PhaseSummary])
+}
+
+func TestResolve_CrossValidation(t *testing.T) {
+	got := Resolve(map[Phase]Assignment{PhaseCross: {Model: "custom-model"}})
+	assert.Equal(t, Assignment{Codex, "custom-model"}, got[PhaseCross], "the AI defaults to the opposite one")
+
+	got = Resolve(map[Phase]Assignment{PhaseCross: {AI: Codex}})
+	assert.Equal(t, Assignment{Codex, "default"}, got[PhaseCross])
+
+	got = Resolve(map[Phase]Assignment{PhaseCross: {AI: Codex, Model: "custom-model"}})
+	assert.Equal(t, Assignment{Codex, "custom-model"}, got[PhaseCross])
+
+	got = Resolve(map[Phase]Assignment{PhaseImpl: {AI: AmazonQ}})
+	assert.Equal(t, Assignment{Codex, "default"}, got[PhaseCross])
+}
+
+func TestResolve_FinalPlanFollowsCrossValidation(t *testing.T) {
+	got := Resolve(map[Phase]Assignment{PhaseCross: {AI: Codex, Model: "gpt-5"}})
+	assert.Equal(t, Assignment{Codex, "gpt-5"}, got[PhaseFinalPlan])
+
+	got = Resolve(map[Phase]Assignment{PhaseFinalPlan: {Model: "custom-model"}})
+	assert.Equal(t, Assignment{Codex, "custom-model"}, got[PhaseFinalPlan])
+
+	// Another AI does not inherit the cross-validation model.
+	got = Resolve(map[Phase]Assignment{PhaseCross: {AI: Codex, Model: "gpt-5"}, PhaseFinalPlan: {AI: Claude}})
+	assert.Equal(t, Assignment{Claude, "opus"}, got[PhaseFinalPlan])
+
+	got = Resolve(map[Phase]Assignment{PhaseFinalPlan: {AI: Claude, Model: "sonnet"}})
+	assert.Equal(t, Assignment{Claude, "sonnet"}, got[PhaseFinalPlan])
+}
+
+func TestResolve_TasksValidation(t *testing.T) {
+	got := Resolve(map[Phase]Assignment{PhaseImpl: {AI: Claude, Model: "sonnet"}, PhaseTasksVal: {Model: "haiku"}})
+	assert.Equal(t, Assignment{Claude, "haiku"}, got[PhaseTasksVal])
+
+	got = Resolve(map[Phase]Assignment{PhaseImpl: {AI: Claude, Model: "sonnet"}, PhaseTasksVal: {AI: Codex}})
+	assert.Equal(t, Assignment{Codex, "default"}, got[PhaseTasksVal], "a claude model is not inherited by codex")
+
+	got = Resolve(map[Phase]Assignment{PhaseTasksVal: {AI: Codex, Model: "o3"}})
+	assert.Equal(t, Assignment{Codex, "o3"}, got[PhaseTasksVal])
+}
+
+func TestResolve_TriageFollowsValidationAI(t *testing.T) {
+	got := Resolve(map[Phase]Assignment{PhaseImpl: {AI: Claude}, PhaseVal: {AI: Codex}})
+	assert.Equal(t, Assignment{Codex, "o4-mini"}, got[PhaseTriage])
+
+	got = Resolve(map[Phase]Assignment{PhaseTriage: {Model: "sonnet"}})
+	assert.Equal(t, Assignment{Claude, "sonnet"}, got[PhaseTriage])
+}
+
+func TestResolve_EveryPhaseIndependent(t *testing.T) {
+	set := map[Phase]Assignment{
+		PhaseImpl:      {Copilot, "gpt-5"},
+		PhaseVal:       {AmazonQ, ""},
+		PhaseCross:     {Claude, "sonnet"},
+		PhaseFinalPlan: {Codex, "o3"},
+		PhaseTasksVal:  {Claude, ""},
+		PhaseSummary:   {Codex, ""},
+	}
+	got := Resolve(set)
+	assert.Equal(t, Assignment{AmazonQ, "claude-sonnet-4"}, got[PhaseVal])
+	assert.Equal(t, Assignment{Claude, "sonnet"}, got[PhaseCross])
+	assert.Equal(t, Assignment{Codex, "o3"}, got[PhaseFinalPlan])
+	assert.Equal(t, Assignment{Claude, "opus"}, got[PhaseTasksVal])
+	assert.Equal(t, Assignment{Codex, "o4-mini"}, got[PhaseSummary])
+}
+
+func TestPhases(t *testing.T) {
+	assert.Equal(t, []Phase{PhaseImpl, PhaseVal, PhaseCross, PhaseFinalPlan, PhaseTasksVal, PhaseSummary, PhaseTriage}, Phases())
+}
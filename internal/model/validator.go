@@ -23,8 +23,10 @@ var claudeModelHints = []string{"opus", "sonnet", "haiku", "claude-"}
 //   - Claude-style hints (opus, sonnet, haiku, claude-*) are invalid
 //     with codex.
 //   - Codex-style hints (default, o[0-9]*, gpt*, chatgpt*, text*,
-//     ft*, gpt4*) are invalid with claude.
-//   - Anything else is accepted without opinion.
+//     ft*, gpt4*) are invalid with claude and amazonq, which serves
+//     only Claude models.
+//   - Anything else, including any model for copilot, is accepted
+//     without opinion.
 func ValidateModelAI(ai, model, label string) error {
 	if model == "" {
 		return nil
@@ -34,7 +36,7 @@ func ValidateModelAI(ai, model, label string) error {
 
 	// "default" is codex-only.
 	if lower == "default" {
-		if ai == Claude {
+		if ai != Codex {
 			return fmt.Errorf("%s %q is not compatible with ai=%s (\"default\" is a codex model)", label, model, ai)
 		}
 		return nil
@@ -45,7 +47,7 @@ func ValidateModelAI(ai, model, label string) error {
 		return fmt.Errorf("%s %q looks like a claude model but ai=%s", label, model, ai)
 	}
 
-	if ai == Claude && IsCodexModelHint(model) {
+	if (ai == Claude || ai == AmazonQ) && IsCodexModelHint(model) {
 		return fmt.Errorf("%s %q looks like a codex/openai model but ai=%s", label, model, ai)
 	}
 
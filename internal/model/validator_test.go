@@ -51,6 +51,18 @@ func TestValidateModelAI_ClaudeWithCodexModel_Error(t *testing.T) {
 	}
 }
 
+func TestValidateModelAI_AmazonQ(t *testing.T) {
+	assert.NoError(t, ValidateModelAI(AmazonQ, "claude-sonnet-4", "impl-model"))
+	assert.Error(t, ValidateModelAI(AmazonQ, "gpt-5", "impl-model"), "q chat serves only claude models")
+	assert.Error(t, ValidateModelAI(AmazonQ, "default", "impl-model"))
+}
+
+func TestValidateModelAI_CopilotAcceptsBothFamilies(t *testing.T) {
+	assert.NoError(t, ValidateModelAI(Copilot, "claude-sonnet-4.5", "impl-model"))
+	assert.NoError(t, ValidateModelAI(Copilot, "gpt-5", "impl-model"))
+	assert.Error(t, ValidateModelAI(Copilot, "default", "impl-model"))
+}
+
 func TestValidateModelAI_UnknownModelAccepted(t *testing.T) {
 	// Models that don't match any known pattern are accepted for both.
 	assert.NoError(t, ValidateModelAI(Claude, "my-custom-model", "impl-model"))
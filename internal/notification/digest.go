@@ -0,0 +1,180 @@
+package notification
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Progress events, sent while the run goes on.
+const (
+	// EventIteration is sent after each iteration that leaves work to do.
+	EventIteration = "iteration"
+	// EventHeartbeat is sent every HEARTBEAT_INTERVAL or
+	// HEARTBEAT_ITERATIONS to show the run is alive.
+	EventHeartbeat = "heartbeat"
+)
+
+// Groups of events usable in NOTIFY_EVENTS next to single event names.
+const (
+	GroupAll      = "all"
+	GroupExit     = "exit"     // the events that end the run
+	GroupProgress = "progress" // the events sent while the run goes on
+)
+
+// DefaultEvents is the NOTIFY_EVENTS default: one notification per run,
+// when it ends, and the heartbeats, which are only sent when enabled.
+const DefaultEvents = GroupExit + "," + EventHeartbeat
+
+var (
+	exitEvents = []string{
+		EventCompleted, EventMaxIterations, EventEscalate, EventBlocked, EventTasksInvalid,
+		EventInadmissible, EventInterrupted, EventBudget, EventCustomVerdict,
+	}
+	progressEvents = []string{EventIteration, EventRateLimited, EventHeartbeat}
+)
+
+// IsProgress reports whether event is sent while the run goes on, rather
+// than when it ends.
+func IsProgress(event string) bool {
+	for _, e := range progressEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseEvents parses a comma-separated NOTIFY_EVENTS list of event names
+// and groups (all, exit, progress) into the set of events to send. An
+// empty list selects the DefaultEvents.
+func ParseEvents(list string) (map[string]bool, error) {
+	if strings.TrimSpace(list) == "" {
+		list = DefaultEvents
+	}
+	known := map[string]bool{}
+	for _, e := range append(append([]string{}, exitEvents...), progressEvents...) {
+		known[e] = true
+	}
+
+	events := map[string]bool{}
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case name == "":
+		case name == GroupAll:
+			for e := range known {
+				events[e] = true
+			}
+		case name == GroupExit:
+			for _, e := range exitEvents {
+				events[e] = true
+			}
+		case name == GroupProgress:
+			for _, e := range progressEvents {
+				events[e] = true
+			}
+		case known[name]:
+			events[name] = true
+		default:
+			names := make([]string, 0, len(known))
+			for e := range known {
+				names = append(names, e)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("unknown event %q: use %s, %s, %s or one of %s",
+				name, GroupAll, GroupExit, GroupProgress, strings.Join(names, ", "))
+		}
+	}
+	return events, nil
+}
+
+// FormatProgress creates the message of a progress event; detail says
+// what happened, e.g. the iteration's verdict.
+func FormatProgress(event, projectName, sessionID string, iteration int, detail string) string {
+	switch event {
+	case EventIteration:
+		return fmt.Sprintf("🔁 %s [%s] iteration %d: %s", projectName, sessionID, iteration, detail)
+	case EventRateLimited:
+		return fmt.Sprintf("⏳ %s [%s] rate limit hit at iteration %d - %s", projectName, sessionID, iteration, detail)
+	case EventHeartbeat:
+		return fmt.Sprintf("💓 %s [%s] still running at iteration %d: %s", projectName, sessionID, iteration, detail)
+	default:
+		return fmt.Sprintf("ℹ️ %s [%s] %s at iteration %d: %s", projectName, sessionID, event, iteration, detail)
+	}
+}
+
+// FormatDigest joins the messages of batched progress events into one.
+func FormatDigest(messages []string) string {
+	return fmt.Sprintf("🗞️ %d update(s):\n%s", len(messages), strings.Join(messages, "\n"))
+}
+
+// Notifier sends only the selected events and, with a digest interval,
+// batches progress events into one message per interval. An event that
+// ends the run flushes the batch first, so nothing is left behind.
+type Notifier struct {
+	send func(message string)
+
+	// mu guards the policy and the batch.
+	mu      sync.Mutex
+	events  map[string]bool
+	digest  time.Duration
+	pending []string
+	timer   *time.Timer
+}
+
+// NewNotifier returns a notifier sending the given events through send,
+// batching progress events every digest (0 sends each at once).
+func NewNotifier(send func(message string), events map[string]bool, digest time.Duration) *Notifier {
+	return &Notifier{send: send, events: events, digest: digest}
+}
+
+// SetPolicy changes the selected events and the digest interval, e.g.
+// after the config was reloaded. Batched events stay batched.
+func (n *Notifier) SetPolicy(events map[string]bool, digest time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.events, n.digest = events, digest
+}
+
+// Notify sends message for event, batches it, or drops it when the event
+// is not selected.
+func (n *Notifier) Notify(event, message string) {
+	n.mu.Lock()
+	if !n.events[event] {
+		n.mu.Unlock()
+		return
+	}
+	if IsProgress(event) && n.digest > 0 {
+		n.pending = append(n.pending, message)
+		if n.timer == nil {
+			n.timer = time.AfterFunc(n.digest, n.Flush)
+		}
+		n.mu.Unlock()
+		return
+	}
+	n.mu.Unlock()
+
+	if !IsProgress(event) {
+		n.Flush()
+	}
+	n.send(message)
+}
+
+// Flush sends the batched progress events as one digest, if any.
+func (n *Notifier) Flush() {
+	n.mu.Lock()
+	pending := n.pending
+	n.pending = nil
+	if n.timer != nil {
+		n.timer.Stop()
+		n.timer = nil
+	}
+	n.mu.Unlock()
+
+	if len(pending) > 0 {
+		n.send(FormatDigest(pending))
+	}
+}
@@ -0,0 +1,108 @@
+package notification
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEvents(t *testing.T) {
+	events, err := ParseEvents("")
+	require.NoError(t, err)
+	assert.True(t, events[EventCompleted])
+	assert.True(t, events[EventEscalate])
+	assert.False(t, events[EventIteration], "progress events are off by default")
+	assert.True(t, events[EventHeartbeat], "heartbeats are on once enabled")
+
+	events, err = ParseEvents(" Escalate, blocked ,completed")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{EventEscalate: true, EventBlocked: true, EventCompleted: true}, events)
+
+	events, err = ParseEvents("progress,completed")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{EventIteration: true, EventRateLimited: true, EventHeartbeat: true, EventCompleted: true}, events)
+
+	events, err = ParseEvents("all")
+	require.NoError(t, err)
+	assert.Len(t, events, len(exitEvents)+len(progressEvents))
+
+	_, err = ParseEvents("escalate,finished")
+	assert.ErrorContains(t, err, `unknown event "finished"`)
+}
+
+func TestFormatProgressAndDigest(t *testing.T) {
+	msg := FormatProgress(EventIteration, "proj", "s1", 3, "NEEDS_MORE_WORK, 2/5 tasks done")
+	assert.Equal(t, "🔁 proj [s1] iteration 3: NEEDS_MORE_WORK, 2/5 tasks done", msg)
+	assert.Contains(t, FormatProgress(EventRateLimited, "proj", "s1", 3, "waiting 60s"), "rate limit hit at iteration 3 - waiting 60s")
+	assert.Equal(t, "💓 proj [s1] still running at iteration 3: validation, 1h0m0s elapsed", FormatProgress(EventHeartbeat, "proj", "s1", 3, "validation, 1h0m0s elapsed"))
+	assert.Equal(t, "🗞️ 2 update(s):\na\nb", FormatDigest([]string{"a", "b"}))
+}
+
+// recorder collects the messages a Notifier sends.
+type recorder struct {
+	mu   sync.Mutex
+	sent []string
+}
+
+func (r *recorder) send(msg string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, msg)
+}
+
+func (r *recorder) messages() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.sent...)
+}
+
+func TestNotifier_FiltersEvents(t *testing.T) {
+	var rec recorder
+	events, err := ParseEvents("escalate,iteration")
+	require.NoError(t, err)
+	n := NewNotifier(rec.send, events, 0)
+
+	n.Notify(EventIteration, "it 1")
+	n.Notify(EventRateLimited, "limited")
+	n.Notify(EventCompleted, "done")
+	n.Notify(EventEscalate, "help")
+	assert.Equal(t, []string{"it 1", "help"}, rec.messages())
+}
+
+func TestNotifier_BatchesProgressIntoDigest(t *testing.T) {
+	var rec recorder
+	events, err := ParseEvents("all")
+	require.NoError(t, err)
+	n := NewNotifier(rec.send, events, time.Hour)
+
+	n.Notify(EventIteration, "it 1")
+	n.Notify(EventRateLimited, "limited")
+	assert.Empty(t, rec.messages(), "progress events wait for the digest")
+
+	n.Notify(EventCompleted, "done")
+	assert.Equal(t, []string{FormatDigest([]string{"it 1", "limited"}), "done"}, rec.messages(),
+		"the end of the run flushes the digest first")
+
+	n.Flush()
+	assert.Len(t, rec.messages(), 2, "an empty batch sends nothing")
+}
+
+func TestNotifier_DigestTimer(t *testing.T) {
+	var rec recorder
+	events, err := ParseEvents("progress")
+	require.NoError(t, err)
+	n := NewNotifier(rec.send, events, 10*time.Millisecond)
+
+	n.Notify(EventIteration, "it 1")
+	n.Notify(EventIteration, "it 2")
+	assert.Eventually(t, func() bool { return len(rec.messages()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, FormatDigest([]string{"it 1", "it 2"}), rec.messages()[0])
+
+	// A policy without a digest sends progress events at once.
+	n.SetPolicy(events, 0)
+	n.Notify(EventIteration, "it 3")
+	assert.Equal(t, "it 3", rec.messages()[1])
+}
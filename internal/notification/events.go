@@ -1,6 +1,13 @@
 package notification
 
-import "fmt"
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// maxListedArtifacts caps how many artifact names a message lists.
+const maxListedArtifacts = 5
 
 // Event types matching the notification events.
 const (
@@ -12,6 +19,8 @@ const (
 	EventInadmissible  = "inadmissible"
 	EventInterrupted   = "interrupted"
 	EventRateLimited   = "rate_limited"
+	EventBudget        = "budget"
+	EventCustomVerdict = "custom_verdict"
 )
 
 // FormatEvent creates a notification message for the given event.
@@ -31,9 +40,49 @@ func FormatEvent(event string, projectName string, sessionID string, iteration i
 		return fmt.Sprintf("🚫 %s [%s] inadmissible threshold exceeded at iteration %d (exit %d)", projectName, sessionID, iteration, exitCode)
 	case EventInterrupted:
 		return fmt.Sprintf("⏸️ %s [%s] interrupted at iteration %d. Use --resume (exit %d)", projectName, sessionID, iteration, exitCode)
+	case EventBudget:
+		return fmt.Sprintf("⏱️ %s [%s] time budget exhausted after %d iterations. Use --resume (exit %d)", projectName, sessionID, iteration, exitCode)
+	case EventCustomVerdict:
+		return fmt.Sprintf("🏷️ %s [%s] stopped by a project verdict at iteration %d (exit %d)", projectName, sessionID, iteration, exitCode)
 	case EventRateLimited:
 		return fmt.Sprintf("⏳ %s [%s] rate limit hit at iteration %d - waiting for reset", projectName, sessionID, iteration)
 	default:
 		return fmt.Sprintf("ℹ️ %s [%s] event: %s (exit %d)", projectName, sessionID, event, exitCode)
 	}
 }
+
+// FormatArtifacts returns a line listing the kept artifacts by file name,
+// to append to an event message. It returns "" when there are none.
+func FormatArtifacts(paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	names := make([]string, 0, maxListedArtifacts)
+	for i, p := range paths {
+		if i == maxListedArtifacts {
+			names = append(names, fmt.Sprintf("and %d more", len(paths)-maxListedArtifacts))
+			break
+		}
+		names = append(names, filepath.Base(p))
+	}
+	return fmt.Sprintf("\n📎 %d artifact(s): %s", len(paths), strings.Join(names, ", "))
+}
+
+// FormatReport returns a line pointing at a report written for the event,
+// such as the escalation report, to append to an event message. It returns
+// "" when there is none.
+func FormatReport(path string) string {
+	if path == "" {
+		return ""
+	}
+	return fmt.Sprintf("\n📋 Report: %s", path)
+}
+
+// FormatReason returns a line giving why the run stopped, to append to an
+// event message. It returns "" when there is no reason.
+func FormatReason(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	return fmt.Sprintf("\n💬 Reason: %s", reason)
+}
@@ -80,6 +80,24 @@ func TestFormatEvent(t *testing.T) {
 			exitCode:    130,
 			wantContain: []string{"⏸️", "paused-proj", "[session-jkl]", "interrupted", "iteration 8", "--resume", "exit 130"},
 		},
+		{
+			name:        "budget event",
+			event:       EventBudget,
+			projectName: "nightly",
+			sessionID:   "session-mno",
+			iteration:   6,
+			exitCode:    7,
+			wantContain: []string{"⏱️", "nightly", "[session-mno]", "time budget exhausted", "6 iterations", "--resume", "exit 7"},
+		},
+		{
+			name:        "custom verdict event",
+			event:       EventCustomVerdict,
+			projectName: "api",
+			sessionID:   "session-pqr",
+			iteration:   3,
+			exitCode:    20,
+			wantContain: []string{"🏷️", "api", "[session-pqr]", "project verdict", "iteration 3", "exit 20"},
+		},
 		{
 			name:        "unknown event",
 			event:       "unknown_event",
@@ -125,6 +143,8 @@ func TestFormatEvent_AllEventsIncludeRequiredFields(t *testing.T) {
 		EventTasksInvalid,
 		EventInadmissible,
 		EventInterrupted,
+		EventBudget,
+		EventCustomVerdict,
 	}
 
 	projectName := "test-project"
@@ -158,4 +178,25 @@ func TestEventConstants(t *testing.T) {
 	assert.Equal(t, "tasks_invalid", EventTasksInvalid)
 	assert.Equal(t, "inadmissible", EventInadmissible)
 	assert.Equal(t, "interrupted", EventInterrupted)
+	assert.Equal(t, "budget", EventBudget)
+	assert.Equal(t, "custom_verdict", EventCustomVerdict)
+}
+
+func TestFormatArtifacts(t *testing.T) {
+	assert.Equal(t, "", FormatArtifacts(nil))
+	assert.Equal(t, "\n📎 2 artifact(s): login.png, junit.xml",
+		FormatArtifacts([]string{".ralph-loop/iteration-001/artifacts/login.png", "/tmp/junit.xml"}))
+
+	many := []string{"a", "b", "c", "d", "e", "f", "g"}
+	assert.Equal(t, "\n📎 7 artifact(s): a, b, c, d, e, and 2 more", FormatArtifacts(many))
+}
+
+func TestFormatReport(t *testing.T) {
+	assert.Equal(t, "", FormatReport(""))
+	assert.Equal(t, "\n📋 Report: /work/.ralph-loop/escalation-004.md", FormatReport("/work/.ralph-loop/escalation-004.md"))
+}
+
+func TestFormatReason(t *testing.T) {
+	assert.Equal(t, "", FormatReason(""))
+	assert.Equal(t, "\n💬 Reason: 2 tasks blocked: T003, T004", FormatReason("2 tasks blocked: T003, T004"))
 }
@@ -0,0 +1,66 @@
+package parser
+
+import "fmt"
+
+// Artifact is one evidence file declared in a RALPH_ARTIFACTS block.
+type Artifact struct {
+	// Path is the file or directory to keep, relative to the project root
+	// or absolute.
+	Path string
+
+	// Kind is a free-form category such as "screenshot", "test_log" or
+	// "coverage".
+	Kind string
+
+	// Description says what the artifact shows.
+	Description string
+
+	// Task is the ID of the task the artifact is evidence for (e.g. "T003").
+	// Screenshots tagged with a task are compared with its baseline.
+	Task string
+}
+
+// ParseArtifacts extracts the artifacts declared in a RALPH_ARTIFACTS block.
+// Entries are either path strings or objects with path, kind, description
+// and task fields; entries without a path are skipped.
+//
+// Returns (nil, nil) if no RALPH_ARTIFACTS block is found.
+// Returns (nil, error) if the JSON is malformed or the block is not a list.
+func ParseArtifacts(text string) ([]Artifact, error) {
+	raw, err := ExtractJSON(text, "RALPH_ARTIFACTS")
+	if raw == nil || err != nil {
+		return nil, err
+	}
+
+	block, ok := raw["RALPH_ARTIFACTS"]
+	if !ok {
+		// Bracket matching landed inside the list: treat the object found
+		// as a single entry.
+		if _, hasPath := raw["path"]; !hasPath {
+			return nil, nil
+		}
+		block = []interface{}{raw}
+	}
+	items, ok := block.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("RALPH_ARTIFACTS must be a list, got %T", block)
+	}
+
+	artifacts := make([]Artifact, 0, len(items))
+	for _, item := range items {
+		var a Artifact
+		switch v := item.(type) {
+		case string:
+			a.Path = v
+		case map[string]interface{}:
+			a.Path, _ = v["path"].(string)
+			a.Kind, _ = v["kind"].(string)
+			a.Description, _ = v["description"].(string)
+			a.Task, _ = v["task"].(string)
+		}
+		if a.Path != "" {
+			artifacts = append(artifacts, a)
+		}
+	}
+	return artifacts, nil
+}
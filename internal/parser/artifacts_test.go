@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArtifacts_FencedBlock(t *testing.T) {
+	input := "Done.\n\n```json\n" + `{
+  "RALPH_STATUS": {"completed_tasks": ["T001"]},
+  "RALPH_ARTIFACTS": [
+    {"path": "screenshots/login.png", "kind": "screenshot", "task": "T003", "description": "login page"},
+    "coverage/index.html",
+    {"kind": "test_log"}
+  ]
+}` + "\n```"
+
+	artifacts, err := ParseArtifacts(input)
+	require.NoError(t, err)
+	assert.Equal(t, []Artifact{
+		{Path: "screenshots/login.png", Kind: "screenshot", Description: "login page", Task: "T003"},
+		{Path: "coverage/index.html"},
+	}, artifacts)
+}
+
+func TestParseArtifacts_NoBlock(t *testing.T) {
+	artifacts, err := ParseArtifacts("nothing to keep")
+	assert.NoError(t, err)
+	assert.Nil(t, artifacts)
+}
+
+func TestParseArtifacts_BracketMatchSingleEntry(t *testing.T) {
+	artifacts, err := ParseArtifacts(`RALPH_ARTIFACTS: [{"path": "out/junit.xml", "kind": "test_log"}]`)
+	require.NoError(t, err)
+	assert.Equal(t, []Artifact{{Path: "out/junit.xml", Kind: "test_log"}}, artifacts)
+}
+
+func TestParseArtifacts_NotAList(t *testing.T) {
+	_, err := ParseArtifacts("```json\n{\"RALPH_ARTIFACTS\": \"shot.png\"}\n```")
+	assert.ErrorContains(t, err, "must be a list")
+}
@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Command is a shell command an AI CLI ran, as recorded in its own event
+// stream rather than claimed in its answer.
+type Command struct {
+	Command string `json:"command"`
+	// ExitCode is the command's exit status, or -1 when the stream ends
+	// before the command's result.
+	ExitCode int `json:"exit_code"`
+}
+
+// claudeExitCodeRE finds the exit status in the result of a failed Bash
+// tool call, e.g. "Exit code 2".
+var claudeExitCodeRE = regexp.MustCompile(`Exit code (\d+)`)
+
+// ParseCommands extracts the shell commands from the raw event stream of a
+// Claude (stream-json) or Codex (exec --json) run, in the order they were
+// issued. Malformed lines are skipped.
+//
+// Supported events:
+//   - type:assistant → message.content[] items with type="tool_use" and name="Bash"
+//   - type:user → message.content[] items with type="tool_result" for those calls
+//   - type:item.started / item.completed → items with type="command_execution"
+func ParseCommands(input string) []Command {
+	var commands []Command
+	index := map[string]int{} // tool call or item ID → position in commands
+
+	for _, line := range strings.Split(input, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var event map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			continue
+		}
+
+		switch event["type"] {
+		case "assistant", "user":
+			message, _ := event["message"].(map[string]interface{})
+			content, _ := message["content"].([]interface{})
+			for _, item := range content {
+				block, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				switch block["type"] {
+				case "tool_use":
+					input, _ := block["input"].(map[string]interface{})
+					command, _ := input["command"].(string)
+					if block["name"] != "Bash" || command == "" {
+						continue
+					}
+					id, _ := block["id"].(string)
+					index[id] = len(commands)
+					commands = append(commands, Command{Command: command, ExitCode: -1})
+				case "tool_result":
+					id, _ := block["tool_use_id"].(string)
+					if i, ok := index[id]; ok {
+						commands[i].ExitCode = claudeExitCode(block)
+					}
+				}
+			}
+		case "item.started", "item.completed":
+			item, _ := event["item"].(map[string]interface{})
+			command, _ := item["command"].(string)
+			if item["type"] != "command_execution" || command == "" {
+				continue
+			}
+			id, _ := item["id"].(string)
+			i, ok := index[id]
+			if !ok {
+				i = len(commands)
+				index[id] = i
+				commands = append(commands, Command{Command: command, ExitCode: -1})
+			}
+			if code, ok := item["exit_code"].(float64); ok {
+				commands[i].ExitCode = int(code)
+			}
+		}
+	}
+	return commands
+}
+
+// claudeExitCode derives the exit status of a Bash tool result: 0 unless
+// it is an error, in which case the code it reports, or 1.
+func claudeExitCode(result map[string]interface{}) int {
+	if isError, _ := result["is_error"].(bool); !isError {
+		return 0
+	}
+	var text string
+	switch content := result["content"].(type) {
+	case string:
+		text = content
+	case []interface{}:
+		for _, item := range content {
+			if block, ok := item.(map[string]interface{}); ok {
+				s, _ := block["text"].(string)
+				text += s
+			}
+		}
+	}
+	if m := claudeExitCodeRE.FindStringSubmatch(text); m != nil {
+		if code, err := strconv.Atoi(m[1]); err == nil {
+			return code
+		}
+	}
+	return 1
+}
@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommands_Claude(t *testing.T) {
+	input := `{"type":"system","subtype":"init"}
+{"type":"assistant","message":{"content":[{"type":"text","text":"Running tests"},{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"Exit code 2\nFAIL","is_error":true}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t2","name":"Read","input":{"file_path":"main.go"}}]}}
+not json
+{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t3","name":"Bash","input":{"command":"go vet ./..."}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t3","content":[{"type":"text","text":""}]}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t4","name":"Bash","input":{"command":"make deploy"}}]}}`
+
+	assert.Equal(t, []Command{
+		{Command: "go test ./...", ExitCode: 2},
+		{Command: "go vet ./...", ExitCode: 0},
+		{Command: "make deploy", ExitCode: -1},
+	}, ParseCommands(input))
+}
+
+func TestParseCommands_Codex(t *testing.T) {
+	input := `{"type":"thread.started","thread_id":"x"}
+{"type":"item.started","item":{"id":"item_1","type":"command_execution","command":"bash -lc 'npm test'","exit_code":null,"status":"in_progress"}}
+{"type":"item.completed","item":{"id":"item_1","type":"command_execution","command":"bash -lc 'npm test'","aggregated_output":"ok","exit_code":1,"status":"failed"}}
+{"type":"item.completed","item":{"id":"item_2","type":"agent_message","text":"done"}}
+{"type":"item.started","item":{"id":"item_3","type":"command_execution","command":"bash -lc 'npm run build'","status":"in_progress"}}`
+
+	assert.Equal(t, []Command{
+		{Command: "bash -lc 'npm test'", ExitCode: 1},
+		{Command: "bash -lc 'npm run build'", ExitCode: -1},
+	}, ParseCommands(input))
+}
+
+func TestParseCommands_Empty(t *testing.T) {
+	assert.Empty(t, ParseCommands(""))
+	assert.Empty(t, ParseCommands("plain text output"))
+}
@@ -0,0 +1,56 @@
+package parser
+
+import "fmt"
+
+// Evidence is one entry of a RALPH_EVIDENCE block: the command the
+// implementer ran for a task and an excerpt of its output.
+type Evidence struct {
+	// Task is the ID of the task the entry is evidence for (e.g. "T005").
+	Task string
+
+	// Command is the command that was run.
+	Command string
+
+	// Output is an excerpt of the command's output.
+	Output string
+}
+
+// ParseEvidence extracts the entries of a RALPH_EVIDENCE block. Entries
+// that are not objects are skipped.
+//
+// Returns (nil, nil) if no RALPH_EVIDENCE block is found.
+// Returns (nil, error) if the JSON is malformed or the block is not a list.
+func ParseEvidence(text string) ([]Evidence, error) {
+	raw, err := ExtractJSON(text, "RALPH_EVIDENCE")
+	if raw == nil || err != nil {
+		return nil, err
+	}
+
+	block, ok := raw["RALPH_EVIDENCE"]
+	if !ok {
+		// Bracket matching landed inside the list: treat the object found
+		// as a single entry.
+		if _, hasCommand := raw["command"]; !hasCommand {
+			return nil, nil
+		}
+		block = []interface{}{raw}
+	}
+	items, ok := block.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("RALPH_EVIDENCE must be a list, got %T", block)
+	}
+
+	evidence := make([]Evidence, 0, len(items))
+	for _, item := range items {
+		v, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var e Evidence
+		e.Task, _ = v["task"].(string)
+		e.Command, _ = v["command"].(string)
+		e.Output, _ = v["output"].(string)
+		evidence = append(evidence, e)
+	}
+	return evidence, nil
+}
@@ -0,0 +1,33 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEvidence_FencedBlock(t *testing.T) {
+	input := "Deployed.\n\n```json\n" + `{
+  "RALPH_STATUS": {"completed_tasks": ["T005"]},
+  "RALPH_EVIDENCE": [
+    {"task": "T005", "command": "make deploy", "output": "deployed 1.4.2"},
+    "not an object"
+  ]
+}` + "\n```"
+
+	evidence, err := ParseEvidence(input)
+	require.NoError(t, err)
+	assert.Equal(t, []Evidence{{Task: "T005", Command: "make deploy", Output: "deployed 1.4.2"}}, evidence)
+}
+
+func TestParseEvidence_NoBlock(t *testing.T) {
+	evidence, err := ParseEvidence("nothing run")
+	assert.NoError(t, err)
+	assert.Nil(t, evidence)
+}
+
+func TestParseEvidence_NotAList(t *testing.T) {
+	_, err := ParseEvidence(`{"RALPH_EVIDENCE": "ran the tests"}`)
+	assert.ErrorContains(t, err, "RALPH_EVIDENCE must be a list")
+}
@@ -183,3 +183,22 @@ func matchBraces(s string) (int, bool) {
 
 	return 0, false
 }
+
+// ExtractBlock returns the value of the key block in text, e.g. the object
+// under "RALPH_VALIDATION", as decoded by encoding/json, for checking
+// against its schema. When the JSON found after key does not nest the
+// block under key, the object itself is returned and nested is false; the
+// caller decides whether it is the block or a false positive.
+//
+// Returns (nil, false, nil) when key does not occur in text, and an error
+// when the JSON found is malformed.
+func ExtractBlock(text string, key string) (value interface{}, nested bool, err error) {
+	raw, err := ExtractJSON(text, key)
+	if raw == nil || err != nil {
+		return nil, false, err
+	}
+	if v, ok := raw[key]; ok {
+		return v, true, nil
+	}
+	return raw, false, nil
+}
@@ -302,3 +302,27 @@ func TestExtractByBracketMatch_KeyNotFoundGuardIsDeadCode(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Nil(t, result, "ExtractJSON returns nil before extractByBracketMatch is called")
 }
+
+func TestExtractBlock(t *testing.T) {
+	value, nested, err := ExtractBlock("```json\n{\"RALPH_STATUS\": {\"notes\": \"done\"}}\n```", "RALPH_STATUS")
+	require.NoError(t, err)
+	assert.True(t, nested)
+	assert.Equal(t, map[string]interface{}{"notes": "done"}, value)
+
+	value, nested, err = ExtractBlock(`{"RALPH_STATUS": "done"}`, "RALPH_STATUS")
+	require.NoError(t, err)
+	assert.True(t, nested)
+	assert.Equal(t, "done", value)
+
+	value, nested, err = ExtractBlock("RALPH_STATUS:\n{\"notes\": \"done\"}", "RALPH_STATUS")
+	require.NoError(t, err)
+	assert.False(t, nested)
+	assert.Equal(t, map[string]interface{}{"notes": "done"}, value)
+
+	value, _, err = ExtractBlock("no block here", "RALPH_STATUS")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	_, _, err = ExtractBlock("```json\n{\"RALPH_STATUS\": {\"notes\": }\n```", "RALPH_STATUS")
+	assert.Error(t, err)
+}
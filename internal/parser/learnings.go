@@ -0,0 +1,8 @@
+package parser
+
+// ExtractLearningsDigest returns the fenced block following the
+// RALPH_LEARNINGS_DIGEST marker of a summarization answer, or "" when it
+// is missing.
+func ExtractLearningsDigest(text string) string {
+	return extractMarkedBlock(text, "RALPH_LEARNINGS_DIGEST")
+}
@@ -0,0 +1,16 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractLearningsDigest(t *testing.T) {
+	text := "Compressed.\n\nRALPH_LEARNINGS_DIGEST\n```markdown\n## Codebase Patterns\n- Use table tests\n```\n"
+	assert.Equal(t, "## Codebase Patterns\n- Use table tests\n", ExtractLearningsDigest(text))
+}
+
+func TestExtractLearningsDigest_Missing(t *testing.T) {
+	assert.Equal(t, "", ExtractLearningsDigest("## Codebase Patterns\n- Use table tests\n"))
+}
@@ -0,0 +1,45 @@
+package parser
+
+import "strings"
+
+// ExtractPatch returns the unified diff from a RALPH_PATCH block: the first
+// fenced code block (```diff, ```patch or a bare fence) that follows the
+// RALPH_PATCH marker. The last RALPH_PATCH marker wins, so a model that
+// revises its patch mid-answer is judged on its final version.
+//
+// Returns "" if no marker or no fenced block after it is found.
+func ExtractPatch(text string) string {
+	return extractMarkedBlock(text, "RALPH_PATCH")
+}
+
+// extractMarkedBlock returns the contents of the first fenced code block
+// after the last occurrence of marker, with a trailing newline. The block
+// ends at a line repeating the opening fence exactly, so a ```` fence may
+// contain ``` fences.
+func extractMarkedBlock(text, marker string) string {
+	idx := strings.LastIndex(text, marker)
+	if idx < 0 {
+		return ""
+	}
+	lines := strings.Split(strings.ReplaceAll(text[idx:], "\r\n", "\n"), "\n")
+
+	start := -1
+	fence := ""
+	for i, line := range lines {
+		if i == 0 {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if start < 0 {
+			if strings.HasPrefix(trimmed, "```") {
+				fence = trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, "`"))]
+				start = i + 1
+			}
+			continue
+		}
+		if trimmed == fence {
+			return strings.Join(lines[start:i], "\n") + "\n"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,23 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractPatch(t *testing.T) {
+	text := "I changed the greeting.\n\nRALPH_PATCH\n```diff\n--- a/hello.txt\n+++ b/hello.txt\n@@ -1 +1 @@\n-hello\n+hi\n```\n\nDone."
+	assert.Equal(t, "--- a/hello.txt\n+++ b/hello.txt\n@@ -1 +1 @@\n-hello\n+hi\n", ExtractPatch(text))
+}
+
+func TestExtractPatch_LastBlockWins(t *testing.T) {
+	text := "RALPH_PATCH\n```diff\nfirst\n```\nOops, corrected:\nRALPH_PATCH:\n```patch\nsecond\n```\n"
+	assert.Equal(t, "second\n", ExtractPatch(text))
+}
+
+func TestExtractPatch_Missing(t *testing.T) {
+	assert.Equal(t, "", ExtractPatch("no patch here"))
+	assert.Equal(t, "", ExtractPatch("RALPH_PATCH but no fence"))
+	assert.Equal(t, "", ExtractPatch("RALPH_PATCH\n```diff\nunterminated"))
+}
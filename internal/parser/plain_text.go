@@ -0,0 +1,24 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ansiRE matches ANSI CSI and OSC escape sequences.
+var ansiRE = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)`)
+
+// ParsePlainText cleans the terminal output of AI CLIs that have no
+// structured output mode (q chat, gh copilot): ANSI escape sequences are
+// removed, and of a line redrawn with carriage returns (spinners, progress)
+// only the last version is kept.
+func ParsePlainText(input string) string {
+	input = ansiRE.ReplaceAllString(input, "")
+	lines := strings.Split(strings.ReplaceAll(input, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if idx := strings.LastIndex(line, "\r"); idx >= 0 {
+			lines[i] = line[idx+1:]
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
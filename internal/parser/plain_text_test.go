@@ -0,0 +1,26 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePlainText(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "", ""},
+		{"plain", "Done.\nRALPH_STATUS\n", "Done.\nRALPH_STATUS"},
+		{"ansi colors", "\x1b[1m\x1b[32m> \x1b[0mAll tests pass", "> All tests pass"},
+		{"spinner", "⠋ Thinking...\r⠙ Thinking...\r\x1b[2K\rImplemented T001\n", "Implemented T001"},
+		{"osc title", "\x1b]0;q chat\x07Hello\r\nWorld", "Hello\nWorld"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParsePlainText(tt.input))
+		})
+	}
+}
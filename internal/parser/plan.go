@@ -0,0 +1,8 @@
+package parser
+
+// ExtractPlan returns the plan.md and tasks.md documents from a planning
+// answer: the fenced blocks following the RALPH_PLAN and RALPH_TASKS
+// markers. Either result is "" when its block is missing.
+func ExtractPlan(text string) (plan, tasks string) {
+	return extractMarkedBlock(text, "RALPH_PLAN"), extractMarkedBlock(text, "RALPH_TASKS")
+}
@@ -0,0 +1,20 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractPlan(t *testing.T) {
+	text := "Here is the plan.\n\nRALPH_PLAN\n````markdown\n# Plan\n\n```go\nfunc main() {}\n```\n````\n\nRALPH_TASKS\n````markdown\n- [ ] Add main\n````\n"
+	plan, tasks := ExtractPlan(text)
+	assert.Equal(t, "# Plan\n\n```go\nfunc main() {}\n```\n", plan)
+	assert.Equal(t, "- [ ] Add main\n", tasks)
+}
+
+func TestExtractPlan_Missing(t *testing.T) {
+	plan, tasks := ExtractPlan("RALPH_PLAN\n```\n# Plan\n```\n")
+	assert.Equal(t, "# Plan\n", plan)
+	assert.Equal(t, "", tasks)
+}
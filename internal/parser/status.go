@@ -0,0 +1,83 @@
+// Package parser provides text-parsing utilities for the ralph-loop CLI.
+package parser
+
+// StatusResult holds the parsed fields from a RALPH_STATUS JSON block.
+// This structure represents the implementer's own report of which tasks it
+// completed or found blocked during an iteration.
+type StatusResult struct {
+	// CompletedTasks lists the task identifiers the implementer claims to
+	// have completed, typically in the format "T###" or "T###: description".
+	CompletedTasks []string
+
+	// BlockedTasks lists the task identifiers the implementer reports as
+	// blocked on external dependencies.
+	BlockedTasks []string
+
+	// Notes is the free-form summary of what the implementer did.
+	Notes string
+}
+
+// ParseStatus extracts RALPH_STATUS fields from AI output text.
+// Uses ExtractJSON to locate the JSON block, then maps fields to the result struct.
+//
+// Returns (nil, nil) if no RALPH_STATUS block is found.
+// Returns (nil, error) if the JSON is malformed.
+// Returns (*StatusResult, nil) if successfully parsed.
+func ParseStatus(text string) (*StatusResult, error) {
+	raw, err := ExtractJSON(text, "RALPH_STATUS")
+	if raw == nil || err != nil {
+		return nil, err
+	}
+
+	// ExtractJSON returns the outer object containing RALPH_STATUS.
+	// Extract the nested RALPH_STATUS object.
+	status, ok := raw["RALPH_STATUS"].(map[string]interface{})
+	hasRalphStatusKey := ok
+	if !ok {
+		// If RALPH_STATUS is not a nested object, treat raw as the status data
+		status = raw
+	}
+
+	result := &StatusResult{
+		CompletedTasks: []string{},
+		BlockedTasks:   []string{},
+	}
+
+	// Track if we found any actual status fields
+	hasStatusFields := false
+
+	if v, ok := status["completed_tasks"].([]interface{}); ok {
+		result.CompletedTasks = stringSlice(v)
+		hasStatusFields = true
+	}
+
+	if v, ok := status["blocked_tasks"].([]interface{}); ok {
+		result.BlockedTasks = stringSlice(v)
+		hasStatusFields = true
+	}
+
+	if v, ok := status["notes"].(string); ok {
+		result.Notes = v
+		hasStatusFields = true
+	}
+
+	// If no status fields were found AND there was no explicit RALPH_STATUS key,
+	// this was probably a false positive match (e.g., "RALPH_STATUS" in prose)
+	if !hasStatusFields && !hasRalphStatusKey {
+		return nil, nil
+	}
+
+	return result, nil
+}
+
+// stringSlice converts a decoded JSON array into a string slice, skipping
+// any non-string elements. Always returns a non-nil slice.
+func stringSlice(arr []interface{}) []string {
+	out := make([]string, 0, len(arr))
+	for _, item := range arr {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatus_FencedBlock(t *testing.T) {
+	input := "Implementation done.\n\n```json\n" + `{
+  "RALPH_STATUS": {
+    "completed_tasks": ["T001", "T002: Add config"],
+    "blocked_tasks": ["T005: needs API key"],
+    "notes": "Implemented T001 and T002"
+  }
+}` + "\n```"
+
+	result, err := ParseStatus(input)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	assert.Equal(t, []string{"T001", "T002: Add config"}, result.CompletedTasks)
+	assert.Equal(t, []string{"T005: needs API key"}, result.BlockedTasks)
+	assert.Equal(t, "Implemented T001 and T002", result.Notes)
+}
+
+func TestParseStatus_NoBlock(t *testing.T) {
+	result, err := ParseStatus("no status here")
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestParseStatus_MarkerInProseOnly(t *testing.T) {
+	result, err := ParseStatus(`RALPH_STATUS will follow {"other": 1}`)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestParseStatus_EmptyArrays(t *testing.T) {
+	result, err := ParseStatus(`{"RALPH_STATUS": {"completed_tasks": [], "blocked_tasks": []}}`)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.NotNil(t, result.CompletedTasks)
+	assert.Empty(t, result.CompletedTasks)
+	assert.Empty(t, result.BlockedTasks)
+}
+
+func TestParseStatus_SkipsNonStringEntries(t *testing.T) {
+	result, err := ParseStatus(`{"RALPH_STATUS": {"completed_tasks": ["T001", 2, null, "T003"]}}`)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, []string{"T001", "T003"}, result.CompletedTasks)
+}
+
+func TestParseStatus_MalformedJSON(t *testing.T) {
+	input := "```json\n{\"RALPH_STATUS\": {\"completed_tasks\": [\"T001\"\n```"
+	result, err := ParseStatus(input)
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
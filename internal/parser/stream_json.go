@@ -94,3 +94,77 @@ func extractResultText(event map[string]interface{}, result *strings.Builder) {
 		result.WriteString(resultText)
 	}
 }
+
+// ParseStreamJSONCost sums the total_cost_usd field of every result event in
+// Claude CLI stream-json output. Malformed lines and events without a cost
+// are skipped. The boolean reports whether any cost was found.
+func ParseStreamJSONCost(input string) (float64, bool) {
+	total := 0.0
+	found := false
+
+	for _, line := range strings.Split(input, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		var event map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			continue
+		}
+		if eventType, _ := event["type"].(string); eventType != "result" {
+			continue
+		}
+		if cost, ok := event["total_cost_usd"].(float64); ok {
+			total += cost
+			found = true
+		}
+	}
+
+	return total, found
+}
+
+// TokenUsage is the token count reported by an AI CLI run.
+type TokenUsage struct {
+	InputTokens  int
+	OutputTokens int
+}
+
+// ParseStreamJSONUsage sums the usage.input_tokens and usage.output_tokens
+// fields of every result event in Claude CLI stream-json output. Cache
+// creation and cache read tokens are counted as input. The boolean reports
+// whether any usage was found.
+func ParseStreamJSONUsage(input string) (TokenUsage, bool) {
+	var total TokenUsage
+	found := false
+
+	for _, line := range strings.Split(input, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		var event map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			continue
+		}
+		if eventType, _ := event["type"].(string); eventType != "result" {
+			continue
+		}
+		usage, ok := event["usage"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, key := range []string{"input_tokens", "cache_creation_input_tokens", "cache_read_input_tokens"} {
+			if n, ok := usage[key].(float64); ok {
+				total.InputTokens += int(n)
+			}
+		}
+		if n, ok := usage["output_tokens"].(float64); ok {
+			total.OutputTokens += int(n)
+		}
+		found = true
+	}
+
+	return total, found
+}
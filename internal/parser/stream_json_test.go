@@ -377,3 +377,42 @@ func TestExtractResultText_EmptyResult(t *testing.T) {
 	result := ParseStreamJSON(input)
 	assert.Equal(t, "", result, "Should return empty when result is empty")
 }
+
+func TestParseStreamJSONCost(t *testing.T) {
+	input := `{"type":"assistant","message":{"content":[{"type":"text","text":"hi"}]}}
+{"type":"result","result":"done","total_cost_usd":0.25}
+not json
+{"type":"result","result":"again","total_cost_usd":1.5}
+{"type":"result","result":"no cost"}`
+
+	cost, found := ParseStreamJSONCost(input)
+	assert.True(t, found)
+	assert.InDelta(t, 1.75, cost, 1e-9)
+}
+
+func TestParseStreamJSONCost_NoCost(t *testing.T) {
+	cost, found := ParseStreamJSONCost(`{"type":"result","result":"done"}`)
+	assert.False(t, found)
+	assert.Zero(t, cost)
+
+	cost, found = ParseStreamJSONCost("")
+	assert.False(t, found)
+	assert.Zero(t, cost)
+}
+
+func TestParseStreamJSONUsage(t *testing.T) {
+	input := `{"type":"assistant","message":{"usage":{"input_tokens":999}}}
+{"type":"result","usage":{"input_tokens":10,"cache_read_input_tokens":5,"cache_creation_input_tokens":2,"output_tokens":7}}
+not json
+{"type":"result","usage":{"input_tokens":3,"output_tokens":4}}`
+
+	usage, found := ParseStreamJSONUsage(input)
+	assert.True(t, found)
+	assert.Equal(t, TokenUsage{InputTokens: 20, OutputTokens: 11}, usage)
+}
+
+func TestParseStreamJSONUsage_NoUsage(t *testing.T) {
+	usage, found := ParseStreamJSONUsage(`{"type":"result","result":"done"}`)
+	assert.False(t, found)
+	assert.Zero(t, usage)
+}
@@ -0,0 +1,8 @@
+package parser
+
+// ExtractOutputSummary returns the fenced block following the
+// RALPH_OUTPUT_SUMMARY marker of an implementation output summary, or ""
+// when it is missing.
+func ExtractOutputSummary(text string) string {
+	return extractMarkedBlock(text, "RALPH_OUTPUT_SUMMARY")
+}
@@ -0,0 +1,16 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractOutputSummary(t *testing.T) {
+	text := "Done.\n\nRALPH_OUTPUT_SUMMARY\n```markdown\n- Edited main.go\n- go test ./... passed\n```\n"
+	assert.Equal(t, "- Edited main.go\n- go test ./... passed\n", ExtractOutputSummary(text))
+}
+
+func TestExtractOutputSummary_Missing(t *testing.T) {
+	assert.Equal(t, "", ExtractOutputSummary("- Edited main.go\n"))
+}
@@ -0,0 +1,55 @@
+// Package parser provides text-parsing utilities for the ralph-loop CLI.
+package parser
+
+// TriageResult holds the parsed fields from a RALPH_TRIAGE JSON block, the
+// quick check of an iteration's claims run before validation.
+type TriageResult struct {
+	// Verdict indicates the triage outcome.
+	// Valid values: PASS, FAIL
+	Verdict string
+
+	// Feedback lists, for FAIL, the claims the iteration's facts contradict.
+	Feedback string
+}
+
+// ParseTriage extracts RALPH_TRIAGE fields from AI output text.
+// Uses ExtractJSON to locate the JSON block, then maps fields to the result struct.
+//
+// Returns (nil, nil) if no RALPH_TRIAGE block is found.
+// Returns (nil, error) if the JSON is malformed.
+// Returns (*TriageResult, nil) if successfully parsed.
+func ParseTriage(text string) (*TriageResult, error) {
+	raw, err := ExtractJSON(text, "RALPH_TRIAGE")
+	if raw == nil || err != nil {
+		return nil, err
+	}
+
+	// ExtractJSON returns the outer object containing RALPH_TRIAGE.
+	// Extract the nested RALPH_TRIAGE object.
+	triage, ok := raw["RALPH_TRIAGE"].(map[string]interface{})
+	hasRalphTriageKey := ok
+	if !ok {
+		// If RALPH_TRIAGE is not a nested object, treat raw as the data
+		triage = raw
+	}
+
+	result := &TriageResult{}
+	hasTriageFields := false
+
+	if v, ok := triage["verdict"].(string); ok {
+		result.Verdict = v
+		hasTriageFields = true
+	}
+	if v, ok := triage["feedback"].(string); ok {
+		result.Feedback = v
+		hasTriageFields = true
+	}
+
+	// Without triage fields or an explicit RALPH_TRIAGE key, the match was
+	// probably the name in prose rather than a JSON block.
+	if !hasTriageFields && !hasRalphTriageKey {
+		return nil, nil
+	}
+
+	return result, nil
+}
@@ -0,0 +1,37 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTriage_Fail(t *testing.T) {
+	input := "Triage:\n\n```json\n" + `{
+  "RALPH_TRIAGE": {
+    "verdict": "FAIL",
+    "feedback": "Claims T003 tests pass, but no test command ran"
+  }
+}
+` + "```"
+
+	result, err := ParseTriage(input)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "FAIL", result.Verdict)
+	assert.Contains(t, result.Feedback, "no test command ran")
+}
+
+func TestParseTriage_Pass(t *testing.T) {
+	result, err := ParseTriage(`{"RALPH_TRIAGE": {"verdict": "PASS", "feedback": ""}}`)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "PASS", result.Verdict)
+}
+
+func TestParseTriage_NoBlock(t *testing.T) {
+	result, err := ParseTriage("RALPH_TRIAGE is mentioned but there is no JSON here")
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
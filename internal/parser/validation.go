@@ -21,6 +21,22 @@ type ValidationResult struct {
 	// BlockedTasks is a list of task identifiers that are blocked,
 	// typically in the format "T###: description".
 	BlockedTasks []string
+
+	// CompletedTasks lists the task identifiers the validator confirmed
+	// as actually done.
+	CompletedTasks []string
+
+	// IncompleteTasks lists the task identifiers the validator found
+	// not done or done wrong.
+	IncompleteTasks []string
+
+	// InadmissiblePractices lists the violations the validator found, as
+	// "<policy name>: <detail>" entries.
+	InadmissiblePractices []string
+
+	// Confidence is how sure the validator is of its verdict, from 0 to 1.
+	// Percentages (1-100) are scaled down. Nil when not reported.
+	Confidence *float64
 }
 
 // ParseValidation extracts RALPH_VALIDATION fields from AI output text.
@@ -46,7 +62,9 @@ func ParseValidation(text string) (*ValidationResult, error) {
 
 	result := &ValidationResult{
 		// Initialize with empty slice instead of nil for blocked_tasks
-		BlockedTasks: []string{},
+		BlockedTasks:    []string{},
+		CompletedTasks:  []string{},
+		IncompleteTasks: []string{},
 	}
 
 	// Track if we found any actual validation fields
@@ -91,6 +109,28 @@ func ParseValidation(text string) (*ValidationResult, error) {
 		}
 	}
 
+	// Extract completed_tasks and incomplete_tasks arrays
+	if v, ok := validation["completed_tasks"].([]interface{}); ok {
+		result.CompletedTasks = stringSlice(v)
+		hasValidationFields = true
+	}
+	if v, ok := validation["incomplete_tasks"].([]interface{}); ok {
+		result.IncompleteTasks = stringSlice(v)
+		hasValidationFields = true
+	}
+	if v, ok := validation["inadmissible_practices"].([]interface{}); ok {
+		result.InadmissiblePractices = stringSlice(v)
+		hasValidationFields = true
+	}
+
+	// Extract confidence (0-1, or a percentage)
+	if v, ok := validation["confidence"].(float64); ok {
+		if c, ok := normalizeConfidence(v); ok {
+			result.Confidence = &c
+		}
+		hasValidationFields = true
+	}
+
 	// If no validation fields were found AND there was no explicit RALPH_VALIDATION key,
 	// this was probably a false positive match (e.g., "RALPH_VALIDATION" in text but not in JSON)
 	if !hasValidationFields && !hasRalphValidationKey {
@@ -99,3 +139,16 @@ func ParseValidation(text string) (*ValidationResult, error) {
 
 	return result, nil
 }
+
+// normalizeConfidence maps a reported confidence to 0-1, treating values
+// above 1 as percentages. It reports false for values outside 0-100.
+func normalizeConfidence(v float64) (float64, bool) {
+	switch {
+	case v < 0 || v > 100:
+		return 0, false
+	case v > 1:
+		return v / 100, true
+	default:
+		return v, true
+	}
+}
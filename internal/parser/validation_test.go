@@ -637,3 +637,82 @@ func TestParseValidation_AllVerdictTypes(t *testing.T) {
 		})
 	}
 }
+
+// TestParseValidation_CompletedAndIncompleteTasks tests extracting the
+// per-task lists used for task tracking.
+func TestParseValidation_CompletedAndIncompleteTasks(t *testing.T) {
+	input := `{"RALPH_VALIDATION": {
+  "verdict": "NEEDS_MORE_WORK",
+  "feedback": "T003 not done",
+  "completed_tasks": ["T001", "T002"],
+  "incomplete_tasks": ["T003: tests missing"]
+}}`
+
+	result, err := ParseValidation(input)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	assert.Equal(t, []string{"T001", "T002"}, result.CompletedTasks)
+	assert.Equal(t, []string{"T003: tests missing"}, result.IncompleteTasks)
+}
+
+// TestParseValidation_InadmissiblePractices tests extracting the policy
+// violations the validator reports.
+func TestParseValidation_InadmissiblePractices(t *testing.T) {
+	input := `{"RALPH_VALIDATION": {
+  "verdict": "INADMISSIBLE",
+  "feedback": "Trivial test",
+  "inadmissible_practices": ["trivial-tests: src/a.test.ts asserts true"]
+}}`
+
+	result, err := ParseValidation(input)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, []string{"trivial-tests: src/a.test.ts asserts true"}, result.InadmissiblePractices)
+
+	result, err = ParseValidation(`{"RALPH_VALIDATION": {"verdict": "COMPLETE"}}`)
+	require.NoError(t, err)
+	assert.Empty(t, result.InadmissiblePractices)
+}
+
+// TestParseValidation_TaskListsDefaultEmpty tests that missing task lists
+// are returned as empty (non-nil) slices.
+func TestParseValidation_TaskListsDefaultEmpty(t *testing.T) {
+	result, err := ParseValidation(`{"RALPH_VALIDATION": {"verdict": "COMPLETE"}}`)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	assert.NotNil(t, result.CompletedTasks)
+	assert.Empty(t, result.CompletedTasks)
+	assert.NotNil(t, result.IncompleteTasks)
+	assert.Empty(t, result.IncompleteTasks)
+}
+
+func TestParseValidation_Confidence(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected *float64
+	}{
+		{"fraction", `{"RALPH_VALIDATION": {"verdict": "COMPLETE", "confidence": 0.85}}`, ptrFloat(0.85)},
+		{"percentage", `{"RALPH_VALIDATION": {"verdict": "COMPLETE", "confidence": 60}}`, ptrFloat(0.6)},
+		{"missing", `{"RALPH_VALIDATION": {"verdict": "COMPLETE"}}`, nil},
+		{"out of range", `{"RALPH_VALIDATION": {"verdict": "COMPLETE", "confidence": 250}}`, nil},
+		{"not a number", `{"RALPH_VALIDATION": {"verdict": "COMPLETE", "confidence": "high"}}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseValidation(tt.input)
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			if tt.expected == nil {
+				assert.Nil(t, result.Confidence)
+				return
+			}
+			require.NotNil(t, result.Confidence)
+			assert.InDelta(t, *tt.expected, *result.Confidence, 1e-9)
+		})
+	}
+}
+
+func ptrFloat(v float64) *float64 { return &v }
@@ -0,0 +1,206 @@
+package patch
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConflictError reports a hunk whose expected lines were not found in the
+// target file.
+type ConflictError struct {
+	Path   string
+	Hunk   int // 1-based hunk index within the file diff
+	Reason string
+}
+
+func (e *ConflictError) Error() string {
+	if e.Hunk == 0 {
+		return fmt.Sprintf("%s: %s", e.Path, e.Reason)
+	}
+	return fmt.Sprintf("%s: hunk %d: %s", e.Path, e.Hunk, e.Reason)
+}
+
+// Apply applies files to the tree rooted at dir. Every diff is checked
+// before anything is written, so a conflict in any file leaves the tree
+// untouched. Hunks whose header line numbers are stale are located by
+// searching for their context, as patch(1) does without fuzz.
+//
+// Paths must be relative, stay inside dir and appear once: a diff that
+// patches the same file twice is rejected rather than applied over itself.
+func Apply(dir string, files []FileDiff) error {
+	type result struct {
+		path    string
+		content string
+		remove  bool
+	}
+	results := make([]result, 0, len(files))
+	seen := make(map[string]bool, len(files))
+
+	for _, fd := range files {
+		path, err := resolve(dir, fd.Path())
+		if err != nil {
+			return err
+		}
+		if seen[path] {
+			return &ConflictError{Path: fd.Path(), Reason: "file patched more than once in the diff"}
+		}
+		seen[path] = true
+
+		var original string
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil && fd.IsCreate():
+			return &ConflictError{Path: fd.Path(), Reason: "file already exists"}
+		case err == nil:
+			original = string(data)
+		case errors.Is(err, os.ErrNotExist) && fd.IsCreate():
+		case errors.Is(err, os.ErrNotExist):
+			return &ConflictError{Path: fd.Path(), Reason: "file does not exist"}
+		default:
+			return fmt.Errorf("read %s: %w", fd.Path(), err)
+		}
+
+		content, err := applyFile(original, fd)
+		if err != nil {
+			return err
+		}
+		if fd.IsDelete() && content != "" {
+			return &ConflictError{Path: fd.Path(), Reason: "file not empty after deletion"}
+		}
+		results = append(results, result{path: path, content: content, remove: fd.IsDelete()})
+	}
+
+	for _, r := range results {
+		if r.remove {
+			if err := os.Remove(r.path); err != nil {
+				return fmt.Errorf("delete %s: %w", r.path, err)
+			}
+			continue
+		}
+		if err := writePreservingMode(r.path, r.content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve joins rel onto dir, rejecting absolute paths and paths that
+// escape dir.
+func resolve(dir, rel string) (string, error) {
+	if rel == "" || filepath.IsAbs(rel) {
+		return "", fmt.Errorf("invalid path %q in patch", rel)
+	}
+	clean := filepath.Clean(filepath.FromSlash(rel))
+	if clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q escapes the project directory", rel)
+	}
+	return filepath.Join(dir, clean), nil
+}
+
+func writePreservingMode(path, content string) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	} else if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+// applyFile applies the hunks of fd to original and returns the new content.
+func applyFile(original string, fd FileDiff) (string, error) {
+	crlf := strings.Contains(original, "\r\n")
+	if crlf {
+		original = strings.ReplaceAll(original, "\r\n", "\n")
+	}
+	lines := strings.Split(original, "\n")
+	trailingNewline := true
+	if original == "" {
+		lines = nil
+	} else if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	} else {
+		trailingNewline = false
+	}
+
+	var out []string
+	pos := 0 // first line of the original not yet copied
+	for i, h := range fd.Hunks {
+		old := h.Old()
+		want := h.OldStart - 1
+		if len(old) == 0 {
+			// A pure insertion's start is the line it follows.
+			want = h.OldStart
+		}
+		at, ok := locate(lines, old, want, pos)
+		if !ok {
+			return "", &ConflictError{Path: fd.Path(), Hunk: i + 1, Reason: "context does not match"}
+		}
+		out = append(out, lines[pos:at]...)
+		out = append(out, h.New()...)
+		pos = at + len(old)
+		// Models often omit the "\ No newline" marker, so the original
+		// end-of-file newline is kept unless the hunk changes it explicitly.
+		if pos == len(lines) {
+			switch {
+			case h.NoNewlineNew:
+				trailingNewline = false
+			case h.NoNewlineOld:
+				trailingNewline = true
+			}
+		}
+	}
+	out = append(out, lines[pos:]...)
+
+	if len(out) == 0 {
+		return "", nil
+	}
+	content := strings.Join(out, "\n")
+	if trailingNewline {
+		content += "\n"
+	}
+	if crlf {
+		content = strings.ReplaceAll(content, "\n", "\r\n")
+	}
+	return content, nil
+}
+
+// locate finds old in lines at or after from, preferring the position closest
+// to want.
+func locate(lines, old []string, want, from int) (int, bool) {
+	if want < from {
+		want = from
+	}
+	if len(old) == 0 {
+		// Pure insertion: trust the header position.
+		if want > len(lines) {
+			want = len(lines)
+		}
+		return want, true
+	}
+	last := len(lines) - len(old)
+	for delta := 0; want-delta >= from || want+delta <= last; delta++ {
+		if at := want - delta; at >= from && at <= last && matches(lines[at:], old) {
+			return at, true
+		}
+		if at := want + delta; delta > 0 && at >= from && at <= last && matches(lines[at:], old) {
+			return at, true
+		}
+	}
+	return 0, false
+}
+
+func matches(lines, old []string) bool {
+	for i, l := range old {
+		if lines[i] != l {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,153 @@
+package patch
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func applyDiff(t *testing.T, dir, diff string) error {
+	t.Helper()
+	files, err := Parse(diff)
+	require.NoError(t, err)
+	return Apply(dir, files)
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestApply_ModifyCreateDelete(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\ntwo\nthree\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "gone.txt"), []byte("bye\n"), 0644))
+
+	err := applyDiff(t, dir, `--- a/a.txt
++++ b/a.txt
+@@ -1,3 +1,3 @@
+ one
+-two
++TWO
+ three
+--- /dev/null
++++ b/sub/new.txt
+@@ -0,0 +1 @@
++fresh
+--- a/gone.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+`)
+	require.NoError(t, err)
+
+	assert.Equal(t, "one\nTWO\nthree\n", readFile(t, filepath.Join(dir, "a.txt")))
+	assert.Equal(t, "fresh\n", readFile(t, filepath.Join(dir, "sub", "new.txt")))
+	assert.NoFileExists(t, filepath.Join(dir, "gone.txt"))
+
+	info, err := os.Stat(filepath.Join(dir, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "mode is preserved")
+}
+
+func TestApply_StaleLineNumbers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "f.txt")
+	require.NoError(t, os.WriteFile(path, []byte("x\ny\nz\na\nb\nc\n"), 0644))
+
+	// The header claims line 1 but the context lives at line 4.
+	err := applyDiff(t, dir, "--- a/f.txt\n+++ b/f.txt\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n")
+	require.NoError(t, err)
+	assert.Equal(t, "x\ny\nz\na\nB\nc\n", readFile(t, path))
+}
+
+func TestApply_ConflictLeavesTreeUntouched(t *testing.T) {
+	dir := t.TempDir()
+	okPath := filepath.Join(dir, "ok.txt")
+	badPath := filepath.Join(dir, "bad.txt")
+	require.NoError(t, os.WriteFile(okPath, []byte("keep\n"), 0644))
+	require.NoError(t, os.WriteFile(badPath, []byte("actual\n"), 0644))
+
+	err := applyDiff(t, dir, `--- a/ok.txt
++++ b/ok.txt
+@@ -1 +1 @@
+-keep
++changed
+--- a/bad.txt
++++ b/bad.txt
+@@ -1 +1 @@
+-expected
++new
+`)
+	var conflict *ConflictError
+	require.True(t, errors.As(err, &conflict))
+	assert.Equal(t, "bad.txt", conflict.Path)
+	assert.Equal(t, 1, conflict.Hunk)
+	assert.Equal(t, "bad.txt: hunk 1: context does not match", err.Error())
+	assert.Equal(t, "keep\n", readFile(t, okPath), "no file is written when any hunk conflicts")
+}
+
+func TestApply_FileExistenceConflicts(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "exists.txt"), []byte("x\n"), 0644))
+
+	err := applyDiff(t, dir, "--- /dev/null\n+++ b/exists.txt\n@@ -0,0 +1 @@\n+y\n")
+	assert.EqualError(t, err, "exists.txt: file already exists")
+
+	err = applyDiff(t, dir, "--- a/missing.txt\n+++ b/missing.txt\n@@ -1 +1 @@\n-a\n+b\n")
+	assert.EqualError(t, err, "missing.txt: file does not exist")
+}
+
+func TestApply_RejectsDuplicatePaths(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.txt")
+	require.NoError(t, os.WriteFile(path, []byte("one\ntwo\n"), 0644))
+
+	err := applyDiff(t, dir, "--- a/a.txt\n+++ b/a.txt\n@@ -1 +1 @@\n-one\n+uno\n"+
+		"--- a/./a.txt\n+++ b/./a.txt\n@@ -2 +2 @@\n-two\n+dos\n")
+	assert.EqualError(t, err, "./a.txt: file patched more than once in the diff")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "one\ntwo\n", string(data), "nothing is written")
+}
+
+func TestApply_RejectsEscapingPaths(t *testing.T) {
+	dir := t.TempDir()
+	err := applyDiff(t, dir, "--- /dev/null\n+++ b/../outside.txt\n@@ -0,0 +1 @@\n+x\n")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "escapes the project directory")
+
+	err = applyDiff(t, dir, "--- /dev/null\n+++ /etc/passwd\n@@ -0,0 +1 @@\n+x\n")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid path")
+}
+
+func TestApply_NewlineHandling(t *testing.T) {
+	dir := t.TempDir()
+	crlf := filepath.Join(dir, "crlf.txt")
+	require.NoError(t, os.WriteFile(crlf, []byte("a\r\nb\r\n"), 0644))
+	require.NoError(t, applyDiff(t, dir, "--- a/crlf.txt\n+++ b/crlf.txt\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n"))
+	assert.Equal(t, "a\r\nc\r\n", readFile(t, crlf))
+
+	noEOL := filepath.Join(dir, "noeol.txt")
+	require.NoError(t, os.WriteFile(noEOL, []byte("a\nb"), 0644))
+	require.NoError(t, applyDiff(t, dir, "--- a/noeol.txt\n+++ b/noeol.txt\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n"))
+	assert.Equal(t, "a\nc", readFile(t, noEOL), "missing marker keeps the original EOF")
+
+	require.NoError(t, applyDiff(t, dir, "--- a/noeol.txt\n+++ b/noeol.txt\n@@ -1,2 +1,2 @@\n a\n-c\n\\ No newline at end of file\n+c\n"))
+	assert.Equal(t, "a\nc\n", readFile(t, noEOL))
+}
+
+func TestApply_InsertAfterLine(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "f.txt")
+	require.NoError(t, os.WriteFile(path, []byte("1\n2\n3\n"), 0644))
+	require.NoError(t, applyDiff(t, dir, "--- a/f.txt\n+++ b/f.txt\n@@ -2,0 +3 @@\n+2.5\n"))
+	assert.Equal(t, "1\n2\n2.5\n3\n", readFile(t, path))
+}
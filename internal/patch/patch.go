@@ -0,0 +1,225 @@
+// Package patch parses unified diffs and applies them to a directory tree.
+//
+// It backs the apply-patch execution mode, where the implementation model
+// returns its changes as a diff instead of editing files itself. Only the
+// subset of the unified diff format that models produce is supported: file
+// headers (---/+++), hunks (@@), context, additions, removals and the
+// "\ No newline at end of file" marker. Git extended headers (diff --git,
+// index, mode lines) are skipped.
+package patch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DevNull is the path used in diff headers for created and deleted files.
+const DevNull = "/dev/null"
+
+// FileDiff holds the changes to a single file.
+type FileDiff struct {
+	OldPath string // DevNull when the file is created
+	NewPath string // DevNull when the file is deleted
+	Hunks   []Hunk
+}
+
+// Path returns the path the diff applies to.
+func (f FileDiff) Path() string {
+	if f.NewPath == DevNull {
+		return f.OldPath
+	}
+	return f.NewPath
+}
+
+// IsCreate reports whether the diff creates a new file.
+func (f FileDiff) IsCreate() bool { return f.OldPath == DevNull }
+
+// IsDelete reports whether the diff deletes the file.
+func (f FileDiff) IsDelete() bool { return f.NewPath == DevNull }
+
+// Hunk is one @@ section of a file diff.
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	// Lines holds the hunk body with its ' ', '+' or '-' prefix.
+	Lines []string
+	// NoNewlineOld and NoNewlineNew record a "\ No newline at end of file"
+	// marker after the last old or new line.
+	NoNewlineOld, NoNewlineNew bool
+}
+
+// Old returns the lines the hunk expects to find (context and removals).
+func (h Hunk) Old() []string { return h.side('-') }
+
+// New returns the lines the hunk leaves behind (context and additions).
+func (h Hunk) New() []string { return h.side('+') }
+
+func (h Hunk) side(keep byte) []string {
+	out := make([]string, 0, len(h.Lines))
+	for _, l := range h.Lines {
+		if l[0] == ' ' || l[0] == keep {
+			out = append(out, l[1:])
+		}
+	}
+	return out
+}
+
+// Parse parses a unified diff. Text before the first file header is ignored.
+func Parse(diff string) ([]FileDiff, error) {
+	lines := strings.Split(strings.ReplaceAll(diff, "\r\n", "\n"), "\n")
+	var files []FileDiff
+
+	for i := 0; i < len(lines); {
+		if !strings.HasPrefix(lines[i], "--- ") || i+1 >= len(lines) || !strings.HasPrefix(lines[i+1], "+++ ") {
+			i++
+			continue
+		}
+		fd := FileDiff{
+			OldPath: headerPath(lines[i][4:]),
+			NewPath: headerPath(lines[i+1][4:]),
+		}
+		if fd.OldPath == DevNull && fd.NewPath == DevNull {
+			return nil, fmt.Errorf("line %d: both sides are %s", i+1, DevNull)
+		}
+		i += 2
+
+		for i < len(lines) && strings.HasPrefix(lines[i], "@@") {
+			h, next, err := parseHunk(lines, i)
+			if err != nil {
+				return nil, err
+			}
+			fd.Hunks = append(fd.Hunks, h)
+			i = next
+		}
+		if len(fd.Hunks) == 0 {
+			return nil, fmt.Errorf("%s: no hunks", fd.Path())
+		}
+		files = append(files, fd)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no file diffs found")
+	}
+	return files, nil
+}
+
+// headerPath strips the timestamp and a/ or b/ prefix from a ---/+++ header.
+func headerPath(s string) string {
+	if idx := strings.IndexByte(s, '\t'); idx >= 0 {
+		s = s[:idx]
+	}
+	s = strings.TrimSpace(s)
+	if s == DevNull {
+		return s
+	}
+	if strings.HasPrefix(s, "a/") || strings.HasPrefix(s, "b/") {
+		s = s[2:]
+	}
+	return s
+}
+
+// parseHunk parses the hunk starting at lines[start] and returns it with the
+// index of the first line after it.
+func parseHunk(lines []string, start int) (Hunk, int, error) {
+	var h Hunk
+	if err := parseHunkHeader(lines[start], &h); err != nil {
+		return h, 0, fmt.Errorf("line %d: %w", start+1, err)
+	}
+
+	oldLeft, newLeft := h.OldLines, h.NewLines
+	i := start + 1
+	for ; i < len(lines) && (oldLeft > 0 || newLeft > 0); i++ {
+		l := lines[i]
+		if l == "" {
+			// Some generators drop the space on blank context lines.
+			l = " "
+		}
+		switch l[0] {
+		case ' ':
+			oldLeft--
+			newLeft--
+		case '-':
+			oldLeft--
+		case '+':
+			newLeft--
+		case '\\':
+			markNoNewline(&h)
+			continue
+		default:
+			return h, 0, fmt.Errorf("line %d: unexpected %q in hunk", i+1, l)
+		}
+		if oldLeft < 0 || newLeft < 0 {
+			return h, 0, fmt.Errorf("line %d: hunk longer than its header", i+1)
+		}
+		h.Lines = append(h.Lines, l)
+	}
+	if oldLeft > 0 || newLeft > 0 {
+		return h, 0, fmt.Errorf("line %d: hunk truncated", i+1)
+	}
+	if i < len(lines) && strings.HasPrefix(lines[i], "\\") {
+		markNoNewline(&h)
+		i++
+	}
+	return h, i, nil
+}
+
+// markNoNewline applies a "\ No newline at end of file" marker to the side
+// of the most recent hunk line.
+func markNoNewline(h *Hunk) {
+	if len(h.Lines) == 0 {
+		return
+	}
+	switch h.Lines[len(h.Lines)-1][0] {
+	case '-':
+		h.NoNewlineOld = true
+	case '+':
+		h.NoNewlineNew = true
+	default:
+		h.NoNewlineOld = true
+		h.NoNewlineNew = true
+	}
+}
+
+// parseHunkHeader parses "@@ -l,s +l,s @@ ..." into h.
+func parseHunkHeader(line string, h *Hunk) error {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || fields[0] != "@@" || fields[3] != "@@" ||
+		!strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return fmt.Errorf("malformed hunk header %q", line)
+	}
+	var err error
+	if h.OldStart, h.OldLines, err = parseRange(fields[1][1:]); err != nil {
+		return fmt.Errorf("malformed hunk header %q: %w", line, err)
+	}
+	if h.NewStart, h.NewLines, err = parseRange(fields[2][1:]); err != nil {
+		return fmt.Errorf("malformed hunk header %q: %w", line, err)
+	}
+	return nil
+}
+
+// parseRange parses "start,count" or "start" (count 1). Both must be
+// unsigned decimal numbers.
+func parseRange(s string) (int, int, error) {
+	startStr, countStr, hasCount := strings.Cut(s, ",")
+	start, err := parseLineNumber(startStr)
+	if err != nil {
+		return 0, 0, err
+	}
+	count := 1
+	if hasCount {
+		if count, err = parseLineNumber(countStr); err != nil {
+			return 0, 0, err
+		}
+	}
+	return start, count, nil
+}
+
+// parseLineNumber parses a non-negative line number or count. Unlike
+// strconv.Atoi it rejects a sign, so "-1" and "+1" are errors.
+func parseLineNumber(s string) (int, error) {
+	if s == "" || s[0] == '-' || s[0] == '+' {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return strconv.Atoi(s)
+}
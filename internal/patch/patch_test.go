@@ -0,0 +1,82 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_MultipleFiles(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+index 83db48f..bf269f4 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,4 @@
+ package main
++
+ func main() {
+ }
+--- /dev/null
++++ b/docs/new.md	2024-01-01 00:00:00
+@@ -0,0 +1,2 @@
++# New
++text
+\ No newline at end of file
+`
+	files, err := Parse(diff)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	assert.Equal(t, "main.go", files[0].Path())
+	require.Len(t, files[0].Hunks, 1)
+	h := files[0].Hunks[0]
+	assert.Equal(t, []int{1, 3, 1, 4}, []int{h.OldStart, h.OldLines, h.NewStart, h.NewLines})
+	assert.Equal(t, []string{"package main", "func main() {", "}"}, h.Old())
+	assert.Equal(t, []string{"package main", "", "func main() {", "}"}, h.New())
+
+	assert.True(t, files[1].IsCreate())
+	assert.Equal(t, "docs/new.md", files[1].Path())
+	assert.True(t, files[1].Hunks[0].NoNewlineNew)
+	assert.False(t, files[1].Hunks[0].NoNewlineOld)
+}
+
+func TestParse_SingleLineRange(t *testing.T) {
+	files, err := Parse("--- a/x\n+++ b/x\n@@ -1 +1 @@\n-a\n+b\n")
+	require.NoError(t, err)
+	h := files[0].Hunks[0]
+	assert.Equal(t, 1, h.OldLines)
+	assert.Equal(t, 1, h.NewLines)
+}
+
+func TestParse_Delete(t *testing.T) {
+	files, err := Parse("--- a/old.txt\n+++ /dev/null\n@@ -1,2 +0,0 @@\n-a\n-b\n")
+	require.NoError(t, err)
+	assert.True(t, files[0].IsDelete())
+	assert.Equal(t, "old.txt", files[0].Path())
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		diff    string
+		wantErr string
+	}{
+		{"empty", "just prose", "no file diffs found"},
+		{"no hunks", "--- a/x\n+++ b/x\n", "no hunks"},
+		{"bad header", "--- a/x\n+++ b/x\n@@ -a +1 @@\n", "malformed hunk header"},
+		{"negative start", "--- a/x\n+++ b/x\n@@ --1,1 +1 @@\n-a\n+b\n", "malformed hunk header"},
+		{"negative count", "--- a/x\n+++ b/x\n@@ -1,-1 +1 @@\n+b\n", "malformed hunk header"},
+		{"signed count", "--- a/x\n+++ b/x\n@@ -1 +1,+1 @@\n-a\n+b\n", "malformed hunk header"},
+		{"truncated", "--- a/x\n+++ b/x\n@@ -1,3 +1,3 @@\n a\n", "hunk truncated"},
+		{"bad line", "--- a/x\n+++ b/x\n@@ -1,2 +1,2 @@\n a\n*b\n", "unexpected"},
+		{"both dev null", "--- /dev/null\n+++ /dev/null\n@@ -0,0 +0,0 @@\n", "both sides"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.diff)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package phases
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/artifacts"
+	"github.com/CodexForgeBR/cli-tools/internal/banner"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/screenshot"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// baselinesDir is the state subdirectory holding per-task baseline
+// screenshots.
+const baselinesDir = "baselines"
+
+// collectArtifacts copies the artifacts declared in a phase's output into
+// the iteration directory and records them on the session. Failures are
+// logged and otherwise ignored: artifacts are evidence, not a gate.
+func (o *Orchestrator) collectArtifacts(outputPath, iterDir, phase string) {
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		return
+	}
+	declared, err := parser.ParseArtifacts(string(data))
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Ignoring malformed RALPH_ARTIFACTS block: %v", err))
+		return
+	}
+	kept, err := artifacts.Collect(declared, o.WorkDir, iterDir, o.session.Iteration, phase)
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Some artifacts were not kept: %v", err))
+	}
+	if phase == state.PhaseImplementation {
+		o.compareScreenshots(kept)
+	}
+	if len(kept) > 0 {
+		o.session.Artifacts = append(o.session.Artifacts, kept...)
+		logging.Info(fmt.Sprintf("Kept %d %s artifact(s) in %s", len(kept), phase, iterDir))
+	}
+}
+
+// compareScreenshots checks the implementer's screenshots against their
+// task's baseline and records the outcome on each artifact. A screenshot of
+// a task that was already done is compared with the frozen baseline it was
+// accepted with, so later changes show up as regressions.
+func (o *Orchestrator) compareScreenshots(kept []state.Artifact) {
+	store := &screenshot.Store{
+		Dir:       filepath.Join(o.StateDir, baselinesDir),
+		Threshold: o.Config.ScreenshotThreshold,
+	}
+	for i := range kept {
+		a := &kept[i]
+		if !screenshot.IsImage(a.Path) || (a.Kind != "" && a.Kind != "screenshot") {
+			continue
+		}
+		id := tasks.ExtractTaskID(a.Task)
+		if id == "" {
+			id = a.Task
+		}
+		done := false
+		if ts := state.FindTask(o.session, id); ts != nil {
+			done = ts.Status == state.TaskDone
+		}
+		diffPath := strings.TrimSuffix(a.Path, filepath.Ext(a.Path)) + "-diff.png"
+		cmp, err := store.Check(id, a.Path, diffPath, done)
+		if err != nil {
+			logging.Warn(fmt.Sprintf("Could not compare screenshot %s: %v", a.Source, err))
+			continue
+		}
+		a.Screenshot = &state.ScreenshotDiff{
+			Status:   cmp.Status,
+			Percent:  cmp.Percent,
+			Baseline: cmp.Baseline,
+			Diff:     cmp.Diff,
+		}
+		if cmp.Status == screenshot.StatusRegression {
+			logging.Warn(fmt.Sprintf("Screenshot regression for %s: %s differs %.2f%% from its baseline", id, a.Source, cmp.Percent))
+		}
+	}
+}
+
+// screenshotDiffLines describes the current iteration's screenshot
+// comparisons for the validation prompt.
+func (o *Orchestrator) screenshotDiffLines() []string {
+	var lines []string
+	for _, a := range o.session.Artifacts {
+		d := a.Screenshot
+		if d == nil || a.Iteration != o.session.Iteration {
+			continue
+		}
+		task := a.Task
+		if task == "" {
+			task = "(no task)"
+		}
+		var line string
+		switch d.Status {
+		case screenshot.StatusNew:
+			line = fmt.Sprintf("%s %s: new — no earlier screenshot to compare with (%s)", task, a.Source, a.Path)
+		case screenshot.StatusRegression:
+			line = fmt.Sprintf("%s %s: regression — %.2f%% of pixels differ from the screenshot accepted when the task was done (%s)", task, a.Source, d.Percent, d.Baseline)
+		default:
+			line = fmt.Sprintf("%s %s: %s — %.2f%% of pixels differ from the previous screenshot (%s)", task, a.Source, d.Status, d.Percent, d.Baseline)
+		}
+		if d.Diff != "" {
+			line += fmt.Sprintf(", diff image: %s", d.Diff)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// artifactPaths returns the kept copies of every collected artifact.
+func artifactPaths(list []state.Artifact) []string {
+	paths := make([]string, len(list))
+	for i, a := range list {
+		paths[i] = a.Path
+	}
+	return paths
+}
+
+// artifactInfos converts collected artifacts into status display rows.
+func artifactInfos(list []state.Artifact) []banner.ArtifactInfo {
+	infos := make([]banner.ArtifactInfo, 0, len(list))
+	for _, a := range list {
+		info := banner.ArtifactInfo{
+			Iteration: a.Iteration,
+			Phase:     a.Phase,
+			Kind:      a.Kind,
+			Path:      a.Path,
+		}
+		if d := a.Screenshot; d != nil {
+			info.Diff = d.Status
+			if d.Status != screenshot.StatusNew {
+				info.Diff += fmt.Sprintf(" %.2f%%", d.Percent)
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
@@ -0,0 +1,109 @@
+package phases
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/screenshot"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// writeScreenshot writes a 10x10 white PNG whose first `marked` pixels are
+// black.
+func writeScreenshot(t *testing.T, path string, marked int) {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for i := 0; i < 100; i++ {
+		c := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+		if i < marked {
+			c = color.RGBA{A: 255}
+		}
+		img.SetRGBA(i%10, i/10, c)
+	}
+	require.NoError(t, screenshot.WriteImage(path, img))
+}
+
+func TestOrchestrator_ScreenshotDiffsReachValidator(t *testing.T) {
+	tmpDir := t.TempDir()
+	workDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [x] T001 Login page\n- [ ] T002 Settings page\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 2
+	cfg.CrossValidate = false
+	cfg.FinalPlanAI = ""
+	cfg.TasksValAI = ""
+
+	// Iteration 1 captures both screens; iteration 2 breaks the login page
+	// (done) and leaves the settings page (in progress) untouched.
+	implRunner := &MockOrchestratorAIRunner{}
+	implRunner.RunFunc = func(ctx context.Context, prompt string, outputPath string) error {
+		marked := 0
+		if implRunner.CallCount == 2 {
+			marked = 20
+		}
+		writeScreenshot(t, filepath.Join(workDir, "shots", "login.png"), marked)
+		writeScreenshot(t, filepath.Join(workDir, "shots", "settings.png"), 0)
+		out := "Done.\n```json\n{\"RALPH_ARTIFACTS\": [" +
+			`{"path": "shots/login.png", "kind": "screenshot", "task": "T001"},` +
+			`{"path": "shots/settings.png", "kind": "screenshot", "task": "T002"}` +
+			"]}\n```\n"
+		return os.WriteFile(outputPath, []byte(out), 0644)
+	}
+	valRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("NEEDS_MORE_WORK", "Keep going")), 0644)
+		},
+	}
+
+	orchestrator := NewOrchestrator(cfg)
+	orchestrator.CommandChecker = alwaysAvailable
+	orchestrator.StateDir = tmpDir
+	orchestrator.WorkDir = workDir
+	orchestrator.ImplRunner = implRunner
+	orchestrator.ValRunner = valRunner
+
+	assert.Equal(t, exitcode.MaxIterations, orchestrator.Run(context.Background()))
+
+	require.Len(t, valRunner.PromptLog, 2)
+	assert.Contains(t, valRunner.PromptLog[0], "SCREENSHOT DIFFS")
+	assert.Contains(t, valRunner.PromptLog[0], "T001 shots/login.png: new")
+
+	second := valRunner.PromptLog[1]
+	iter2 := filepath.Join(tmpDir, "iteration-002", "artifacts")
+	assert.Contains(t, second, fmt.Sprintf("T001 shots/login.png: regression — 20.00%% of pixels differ from the screenshot accepted when the task was done (%s), diff image: %s",
+		filepath.Join(tmpDir, "baselines", "T001", "login.png"), filepath.Join(iter2, "login-diff.png")))
+	assert.Contains(t, second, "T002 shots/settings.png: unchanged — 0.00% of pixels differ from the previous screenshot")
+	assert.NotContains(t, second, "shots/login.png: new", "only the current iteration's screenshots are listed")
+	assert.FileExists(t, filepath.Join(iter2, "login-diff.png"))
+
+	saved, err := state.LoadState(tmpDir)
+	require.NoError(t, err)
+	require.Len(t, saved.Artifacts, 4)
+	require.NotNil(t, saved.Artifacts[2].Screenshot)
+	assert.Equal(t, screenshot.StatusRegression, saved.Artifacts[2].Screenshot.Status)
+	assert.Equal(t, "T001", saved.Artifacts[2].Task)
+}
+
+func TestArtifactInfos_ScreenshotDiff(t *testing.T) {
+	infos := artifactInfos([]state.Artifact{
+		{Iteration: 1, Path: "a.png", Screenshot: &state.ScreenshotDiff{Status: screenshot.StatusNew}},
+		{Iteration: 2, Path: "a.png", Screenshot: &state.ScreenshotDiff{Status: screenshot.StatusChanged, Percent: 3.5}},
+		{Iteration: 2, Path: "junit.xml"},
+	})
+	assert.Equal(t, "new", infos[0].Diff)
+	assert.Equal(t, "changed 3.50%", infos[1].Diff)
+	assert.Empty(t, infos[2].Diff)
+}
@@ -0,0 +1,42 @@
+package phases
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/banner"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/notification"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// checkBudget stops the loop before an iteration that would run past
+// Config.MaxDuration. The next iteration is expected to take as long as
+// the average of the `started` iterations run since loopStart. Returns -1
+// when there is time left.
+func (o *Orchestrator) checkBudget(loopStart time.Time, started int) int {
+	budget := o.Config.MaxDuration
+	if budget <= 0 {
+		return -1
+	}
+	elapsed := time.Since(o.startTime)
+	var expected time.Duration
+	if started > 0 {
+		expected = time.Since(loopStart) / time.Duration(started)
+	}
+	if elapsed+expected <= budget {
+		return -1
+	}
+
+	logging.Warn(fmt.Sprintf("Not starting iteration %d: %s elapsed, an iteration takes about %s, budget is %s",
+		o.session.Iteration+1, elapsed.Round(time.Second), expected.Round(time.Second), budget))
+	banner.PrintBudgetBanner(o.session.Iteration, int(elapsed.Seconds()), int(budget.Seconds()))
+	o.session.Status = state.StatusBudgetExceeded
+	o.notify(notification.EventBudget, exitcode.Result{Code: exitcode.Budget,
+		Reason: fmt.Sprintf("%s of the %s budget spent after %d iterations", elapsed.Round(time.Second), budget, o.session.Iteration)})
+	if err := state.SaveState(o.session, o.StateDir); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save budget state: %v", err))
+	}
+	return exitcode.Budget
+}
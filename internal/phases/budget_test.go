@@ -0,0 +1,117 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func budgetTestConfig(t *testing.T, tmpDir string) *config.Config {
+	t.Helper()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] T001 Task\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 10
+	cfg.CrossValidate = false
+	cfg.FinalPlanAI = ""
+	cfg.TasksValAI = ""
+	return cfg
+}
+
+// TestOrchestrator_MaxDurationStopsBeforeOverrun verifies that the loop does
+// not start an iteration that is expected to run past the budget.
+func TestOrchestrator_MaxDurationStopsBeforeOverrun(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := budgetTestConfig(t, tmpDir)
+	cfg.MaxDuration = 250 * time.Millisecond
+
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			time.Sleep(100 * time.Millisecond)
+			return os.WriteFile(outputPath, []byte("Working."), 0644)
+		},
+	}
+	valRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("NEEDS_MORE_WORK", "Keep going")), 0644)
+		},
+	}
+
+	orchestrator := NewOrchestrator(cfg)
+	orchestrator.CommandChecker = alwaysAvailable
+	orchestrator.StateDir = tmpDir
+	orchestrator.ImplRunner = implRunner
+	orchestrator.ValRunner = valRunner
+
+	assert.Equal(t, exitcode.Budget, orchestrator.Run(context.Background()))
+	assert.Equal(t, 2, implRunner.CallCount, "a third ~100ms iteration would overrun 250ms")
+
+	saved, err := state.LoadState(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, state.StatusBudgetExceeded, saved.Status)
+	assert.Equal(t, 2, saved.Iteration)
+}
+
+// TestOrchestrator_MaxDurationZeroIsUnlimited verifies the default budget
+// never interrupts the loop.
+func TestOrchestrator_MaxDurationZeroIsUnlimited(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := budgetTestConfig(t, tmpDir)
+
+	orchestrator := NewOrchestrator(cfg)
+	orchestrator.StateDir = tmpDir
+	assert.Equal(t, -1, orchestrator.checkBudget(time.Now().Add(-time.Hour), 1))
+}
+
+// TestOrchestrator_ResumeAfterBudget verifies that a session stopped by the
+// budget resumes at the next iteration.
+func TestOrchestrator_ResumeAfterBudget(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := budgetTestConfig(t, tmpDir)
+	cfg.MaxDuration = time.Nanosecond
+
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte("Working."), 0644)
+		},
+	}
+	valRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("NEEDS_MORE_WORK", "Keep going")), 0644)
+		},
+	}
+
+	first := NewOrchestrator(cfg)
+	first.CommandChecker = alwaysAvailable
+	first.StateDir = tmpDir
+	first.ImplRunner = implRunner
+	first.ValRunner = valRunner
+	assert.Equal(t, exitcode.Budget, first.Run(context.Background()))
+	assert.Equal(t, 0, implRunner.CallCount)
+
+	resumeCfg := budgetTestConfig(t, tmpDir)
+	resumeCfg.Resume = true
+	resumeCfg.MaxIterations = 1
+	valRunner.RunFunc = func(ctx context.Context, prompt string, outputPath string) error {
+		_ = os.WriteFile(resumeCfg.TasksFile, []byte("- [x] T001 Task\n"), 0644)
+		return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("COMPLETE", "")), 0644)
+	}
+
+	resumed := NewOrchestrator(resumeCfg)
+	resumed.CommandChecker = alwaysAvailable
+	resumed.StateDir = tmpDir
+	resumed.ImplRunner = implRunner
+	resumed.ValRunner = valRunner
+	assert.Equal(t, exitcode.Success, resumed.Run(context.Background()))
+	assert.Equal(t, 1, implRunner.CallCount)
+}
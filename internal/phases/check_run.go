@@ -0,0 +1,112 @@
+package phases
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	ghissue "github.com/CodexForgeBR/cli-tools/internal/github"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+)
+
+// checkRunName is the name of the GitHub check run reporting the session.
+const checkRunName = "ralph-loop"
+
+// startCheckRun creates the in-progress check run reporting this run of
+// the session (GITHUB_CHECK). Failures are logged and leave the run
+// unreported.
+func (o *Orchestrator) startCheckRun() {
+	if o.Checks == nil {
+		return
+	}
+	id, err := o.Checks.CreateCheckRun(checkRunName, o.CheckSHA)
+	if err != nil {
+		logging.Warn(fmt.Sprintf("GitHub check run disabled: %v", err))
+		return
+	}
+	o.checkRunID = id
+	logging.Debug(fmt.Sprintf("Reporting to GitHub check run %d on %s", id, o.CheckSHA))
+}
+
+// updateCheckRun shows the verdict of the iteration just judged on the
+// check run, with the validator feedback and its file references as
+// annotations.
+func (o *Orchestrator) updateCheckRun(verdict, feedback string) {
+	if o.checkRunID == 0 {
+		return
+	}
+	var summary string
+	if eta, ok := sessionETA(o.session); ok {
+		summary = fmt.Sprintf("**Progress:** %s\n\n", eta)
+	}
+	if strings.TrimSpace(feedback) != "" {
+		summary += "### Validator feedback\n\n" + feedback
+	}
+	if summary == "" {
+		summary = verdict
+	}
+	o.sendCheckRun(ghissue.CheckRunUpdate{
+		Status: ghissue.CheckInProgress,
+		Output: &ghissue.CheckOutput{
+			Title:       fmt.Sprintf("Iteration %d: %s", o.session.Iteration, verdict),
+			Summary:     summary,
+			Annotations: o.checkAnnotations(feedback, ghissue.AnnotationWarning),
+		},
+	})
+}
+
+// finishCheckRun completes the check run with the conclusion matching how
+// the run ended and the run summary. A run that did not succeed keeps the
+// last validator feedback's annotations.
+func (o *Orchestrator) finishCheckRun(res exitcode.Result) {
+	if o.checkRunID == 0 {
+		return
+	}
+	out := &ghissue.CheckOutput{Title: res.String(), Summary: res.String()}
+	if o.session != nil {
+		out.Summary = buildPRSummary(o.summaryInput("", res))
+		if res.Code != exitcode.Success {
+			out.Annotations = o.checkAnnotations(o.lastFeedback(), ghissue.AnnotationFailure)
+		}
+	}
+	o.sendCheckRun(ghissue.CheckRunUpdate{
+		Status:     ghissue.CheckCompleted,
+		Conclusion: checkConclusion(res.Code),
+		Output:     out,
+	})
+}
+
+func (o *Orchestrator) sendCheckRun(u ghissue.CheckRunUpdate) {
+	if err := o.Checks.UpdateCheckRun(o.checkRunID, u); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to update GitHub check run: %v", err))
+	}
+}
+
+// checkAnnotations returns the annotations of the file references in
+// feedback, dropping those to files that do not exist, which GitHub
+// rejects.
+func (o *Orchestrator) checkAnnotations(feedback, level string) []ghissue.Annotation {
+	var kept []ghissue.Annotation
+	for _, a := range ghissue.ParseAnnotations(feedback, level) {
+		if _, err := os.Stat(filepath.Join(o.WorkDir, a.Path)); err == nil {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
+
+// checkConclusion maps an exit code to a check run conclusion.
+func checkConclusion(code int) string {
+	switch code {
+	case exitcode.Success:
+		return ghissue.ConclusionSuccess
+	case exitcode.Interrupted:
+		return ghissue.ConclusionCancelled
+	case exitcode.Budget:
+		return ghissue.ConclusionTimedOut
+	default:
+		return ghissue.ConclusionFailure
+	}
+}
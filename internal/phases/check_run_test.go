@@ -0,0 +1,103 @@
+package phases
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	ghissue "github.com/CodexForgeBR/cli-tools/internal/github"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkRunRequest is a request the fake Checks API received.
+type checkRunRequest struct {
+	Call string
+	Body map[string]any
+}
+
+func newCheckRunTest(t *testing.T) (*Orchestrator, *[]checkRunRequest) {
+	var requests []checkRunRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, checkRunRequest{Call: r.Method + " " + r.URL.Path, Body: body})
+		fmt.Fprint(w, `{"id": 12}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	tasksFile := filepath.Join(dir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [x] T001 First\n- [ ] T002 Second\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644))
+
+	o := NewOrchestrator(config.NewDefaultConfig())
+	o.StateDir = t.TempDir()
+	o.WorkDir = dir
+	o.session = &state.SessionState{SessionID: "sess-1", Iteration: 2, TasksFile: tasksFile}
+	o.Checks = &ghissue.Client{BaseURL: srv.URL, Token: "tok", Owner: "acme", Repo: "app"}
+	o.CheckSHA = "abc123"
+	return o, &requests
+}
+
+func TestCheckRun_Lifecycle(t *testing.T) {
+	o, requests := newCheckRunTest(t)
+	feedback := "- main.go:3: the handler ignores errors\n- gone.go:9: no longer exists"
+
+	o.startCheckRun()
+	o.updateCheckRun("NEEDS_MORE_WORK", feedback)
+	o.session.LastFeedback = base64.StdEncoding.EncodeToString([]byte(feedback))
+	o.finishCheckRun(exitcode.Result{Code: exitcode.MaxIterations, Reason: "reached the limit"})
+
+	require.Len(t, *requests, 3)
+	assert.Equal(t, "POST /repos/acme/app/check-runs", (*requests)[0].Call)
+	assert.Equal(t, "abc123", (*requests)[0].Body["head_sha"])
+
+	update := (*requests)[1]
+	assert.Equal(t, "PATCH /repos/acme/app/check-runs/12", update.Call)
+	assert.Equal(t, ghissue.CheckInProgress, update.Body["status"])
+	output := update.Body["output"].(map[string]any)
+	assert.Equal(t, "Iteration 2: NEEDS_MORE_WORK", output["title"])
+	assert.Contains(t, output["summary"], "**Progress:** 50% (1/2 tasks)")
+	assert.Contains(t, output["summary"], "### Validator feedback")
+	annotations := output["annotations"].([]any)
+	require.Len(t, annotations, 1, "annotations on missing files are dropped")
+	assert.Equal(t, "main.go", annotations[0].(map[string]any)["path"])
+	assert.Equal(t, ghissue.AnnotationWarning, annotations[0].(map[string]any)["annotation_level"])
+
+	final := (*requests)[2]
+	assert.Equal(t, ghissue.CheckCompleted, final.Body["status"])
+	assert.Equal(t, ghissue.ConclusionFailure, final.Body["conclusion"])
+	output = final.Body["output"].(map[string]any)
+	assert.Equal(t, "MaxIterations (2): reached the limit", output["title"])
+	assert.Contains(t, output["summary"], "**Result:** ❌ MaxIterations (exit 2)\n")
+	assert.Len(t, output["annotations"], 1)
+}
+
+func TestCheckRun_DisabledOrFailedStart(t *testing.T) {
+	o, requests := newCheckRunTest(t)
+	o.Checks = nil
+	o.startCheckRun()
+	o.updateCheckRun("COMPLETE", "")
+	o.finishCheckRun(exitcode.Result{Code: exitcode.Success})
+	assert.Empty(t, *requests)
+
+	o.Checks = &ghissue.Client{BaseURL: "http://127.0.0.1:1", Token: "tok", Owner: "acme", Repo: "app"}
+	o.startCheckRun()
+	assert.Zero(t, o.checkRunID, "a failed start leaves the run unreported")
+}
+
+func TestCheckConclusion(t *testing.T) {
+	assert.Equal(t, ghissue.ConclusionSuccess, checkConclusion(exitcode.Success))
+	assert.Equal(t, ghissue.ConclusionCancelled, checkConclusion(exitcode.Interrupted))
+	assert.Equal(t, ghissue.ConclusionTimedOut, checkConclusion(exitcode.Budget))
+	assert.Equal(t, ghissue.ConclusionFailure, checkConclusion(exitcode.Escalate))
+}
@@ -0,0 +1,92 @@
+package phases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// checkedTaskIDs returns the IDs of the checked tasks in the tasks file, or
+// nil when it cannot be read.
+func (o *Orchestrator) checkedTaskIDs() map[string]bool {
+	list, err := tasks.ListTasks(o.session.TasksFile)
+	if err != nil {
+		return nil
+	}
+	ids := map[string]bool{}
+	for _, t := range list {
+		if t.Checked {
+			ids[t.ID] = true
+		}
+	}
+	return ids
+}
+
+// recordImplTasks records the tasks file as the implementation phase left
+// it, so revertUnconfirmedChecks can tell the implementer's checks from the
+// validator's.
+func (o *Orchestrator) recordImplTasks() {
+	o.implChecked = o.checkedTaskIDs()
+	o.implTasksHash, _ = tasks.HashFile(o.session.TasksFile)
+}
+
+// revertUnconfirmedChecks unchecks the tasks the implementer checked in
+// this iteration that the validator did not confirm, and tells the
+// implementer so through the feedback. The validator confirms a task by
+// listing it in completed_tasks, or every task with a COMPLETE verdict
+// that lists none; tasks the validator checked itself need no
+// confirmation. It does nothing when the tasks file from before the
+// iteration is unknown, as after a resume.
+func (o *Orchestrator) revertUnconfirmedChecks(result ValidationPhaseResult) ValidationPhaseResult {
+	if o.checkedAtStart == nil || o.implChecked == nil {
+		return result
+	}
+	if result.Verdict == "COMPLETE" && len(result.CompletedTasks) == 0 {
+		return result
+	}
+	if hash, err := tasks.HashFile(o.session.TasksFile); err == nil && hash != o.implTasksHash {
+		logging.Info("The validator edited the tasks file; the tasks it checked count as confirmed")
+	}
+	confirmed := map[string]bool{}
+	for _, id := range result.CompletedTasks {
+		confirmed[id] = true
+	}
+	for _, id := range result.IncompleteTasks {
+		delete(confirmed, id)
+	}
+
+	list, err := tasks.ListTasks(o.session.TasksFile)
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Failed to list tasks: %v", err))
+		return result
+	}
+	var reverted []string
+	for _, t := range list {
+		if !t.Checked || o.checkedAtStart[t.ID] || !o.implChecked[t.ID] || confirmed[t.ID] {
+			continue
+		}
+		if err := tasks.Uncheck(o.session.TasksFile, t.Line); err != nil {
+			logging.Warn(fmt.Sprintf("Failed to uncheck task %s: %v", t.ID, err))
+			continue
+		}
+		reverted = append(reverted, t.ID)
+	}
+	if len(reverted) == 0 {
+		return result
+	}
+	o.snapshotTasks(false)
+
+	logging.Warn(fmt.Sprintf("Unchecked %s: checked by the implementer without validator confirmation", strings.Join(reverted, ", ")))
+	note := fmt.Sprintf("You checked boxes without validator confirmation; ralph-loop unchecked them: %s. "+
+		"Only check a task once its work is done and can be verified.", strings.Join(reverted, ", "))
+	if result.Feedback != "" {
+		note += "\n\n" + result.Feedback
+	}
+	result.Feedback = note
+	if result.Verdict == "COMPLETE" {
+		result.Verdict = "NEEDS_MORE_WORK"
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// TestOrchestrator_RevertsUnconfirmedChecks verifies that boxes the
+// implementer checked without the validator confirming them are unchecked
+// and that the next implementation prompt says so.
+func TestOrchestrator_RevertsUnconfirmedChecks(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [x] T001 Done before\n- [ ] T002 Real\n- [ ] T003 Claimed\n- [ ] T004 Checked by validator\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 2
+	cfg.MaxTaskAttempts = 0
+	cfg.CrossValidate = false
+	cfg.EnableLearnings = false
+
+	var prompts []string
+	impl := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			prompts = append(prompts, prompt)
+			if len(prompts) == 1 {
+				require.NoError(t, os.WriteFile(tasksFile, []byte("- [x] T001 Done before\n- [x] T002 Real\n- [x] T003 Claimed\n- [ ] T004 Checked by validator\n"), 0644))
+			}
+			return os.WriteFile(outputPath, []byte("done"), 0644)
+		},
+	}
+	var tasksAtSecondValidation string
+	validations := 0
+	val := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			validations++
+			if validations == 1 {
+				require.NoError(t, os.WriteFile(tasksFile, []byte("- [x] T001 Done before\n- [x] T002 Real\n- [x] T003 Claimed\n- [x] T004 Checked by validator\n"), 0644))
+			} else {
+				data, err := os.ReadFile(tasksFile)
+				require.NoError(t, err)
+				tasksAtSecondValidation = string(data)
+			}
+			out := `{"RALPH_VALIDATION": {"verdict": "NEEDS_MORE_WORK", "feedback": "T003 is not done", "completed_tasks": ["T002"], "incomplete_tasks": ["T003"]}}`
+			return os.WriteFile(outputPath, []byte(out), 0644)
+		},
+	}
+
+	orch := NewOrchestrator(cfg)
+	orch.CommandChecker = alwaysAvailable
+	orch.StateDir = tmpDir
+	orch.ImplRunner = impl
+	orch.ValRunner = val
+	orch.Run(context.Background())
+
+	require.Len(t, prompts, 2)
+	assert.Contains(t, prompts[1], "You checked boxes without validator confirmation; ralph-loop unchecked them: T003.")
+	assert.Contains(t, prompts[1], "T003 is not done")
+	assert.Equal(t, "- [x] T001 Done before\n- [x] T002 Real\n- [ ] T003 Claimed\n- [x] T004 Checked by validator\n", tasksAtSecondValidation)
+}
+
+// TestRevertUnconfirmedChecks_CompleteConfirmsAll verifies that a COMPLETE
+// verdict listing no tasks confirms every check, and that nothing is
+// reverted when the tasks file before the iteration is unknown.
+func TestRevertUnconfirmedChecks_CompleteConfirmsAll(t *testing.T) {
+	tasksFile := filepath.Join(t.TempDir(), "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [x] T001 Task\n"), 0644))
+	o := NewOrchestrator(config.NewDefaultConfig())
+	o.StateDir = filepath.Join(filepath.Dir(tasksFile), ".ralph-loop")
+	o.session = &state.SessionState{TasksFile: tasksFile}
+
+	result := ValidationPhaseResult{Verdict: "NEEDS_MORE_WORK", Feedback: "no"}
+	assert.Equal(t, result, o.revertUnconfirmedChecks(result))
+
+	o.checkedAtStart = map[string]bool{}
+	o.recordImplTasks()
+	result = ValidationPhaseResult{Verdict: "COMPLETE"}
+	assert.Equal(t, result, o.revertUnconfirmedChecks(result))
+
+	// A COMPLETE verdict that leaves a check out is downgraded.
+	result = o.revertUnconfirmedChecks(ValidationPhaseResult{Verdict: "COMPLETE", CompletedTasks: []string{"T002"}})
+	assert.Equal(t, "NEEDS_MORE_WORK", result.Verdict)
+	data, err := os.ReadFile(tasksFile)
+	require.NoError(t, err)
+	assert.Equal(t, "- [ ] T001 Task\n", string(data))
+}
@@ -0,0 +1,145 @@
+package phases
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/banner"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/notification"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/tracing"
+)
+
+// checkpoint records that the current iteration has finished every phase
+// before next and saves the session. The tasks file hash is refreshed
+// because implementation legitimately edits it; without this a resumed
+// session would be rejected as modified.
+func (o *Orchestrator) checkpoint(next, implOutput, valOutput string) {
+	o.session.Phase = next
+	o.session.Checkpoint = &state.Checkpoint{
+		Iteration:  o.session.Iteration,
+		Phase:      next,
+		ImplOutput: implOutput,
+		ValOutput:  valOutput,
+		DiffBase:   o.diffBase,
+	}
+	o.snapshotTasks(false)
+	if err := state.SaveState(o.session, o.StateDir); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save %s checkpoint: %v", next, err))
+	}
+}
+
+// resumeCheckpoint returns the checkpoint a resumed session continues from,
+// or nil when there is none or its output files are gone, in which case the
+// next iteration starts from implementation as before.
+func (o *Orchestrator) resumeCheckpoint() *state.Checkpoint {
+	cp := o.session.Checkpoint
+	if !o.resumed || cp == nil || cp.Iteration != o.session.Iteration {
+		return nil
+	}
+	required := []string{cp.ImplOutput}
+	switch cp.Phase {
+	case state.PhaseValidation:
+	case state.PhaseCrossValidation:
+		required = append(required, cp.ValOutput)
+	default:
+		return nil
+	}
+	for _, path := range required {
+		if _, err := os.Stat(path); err != nil {
+			logging.Warn(fmt.Sprintf("Checkpoint output %s missing; restarting iteration", path))
+			return nil
+		}
+	}
+	return cp
+}
+
+// runPostValidation runs cross-validation and final plan validation after a
+// COMPLETE verdict. It returns exitcode.Success when every gate passes, or
+// -1 after storing the rejection feedback so the loop continues. In a
+// multi-file session it also returns -1 after switching to the next spec.
+func (o *Orchestrator) runPostValidation(ctx context.Context, implOutputPath, valOutputPath string) int {
+	// Compute specFile for post-validation chain
+	specFile := o.specFile()
+
+	// Final-plan validation judges only the spec, tasks and plan; skip it
+	// when it already confirmed them unchanged.
+	finalPlanEnabled := o.FinalPlanRunner != nil
+	var finalPlanInput map[string]string
+	if finalPlanEnabled {
+		finalPlanInput = finalPlanInputs(specFile, o.session.TasksFile, o.Config.OriginalPlanFile)
+		if !o.Config.RevalidatePlan && o.finalPlanConfirmed(finalPlanInput) {
+			logging.Info("Final-plan validation skipped: the spec, tasks and plan are unchanged since it confirmed them (--revalidate-plan to run it)")
+			finalPlanEnabled = false
+		}
+	}
+
+	o.Dashboard.SetPhase(state.PhaseCrossValidation)
+	postStart := time.Now()
+	postCtx, postSpan := tracing.Start(ctx, "post_validation")
+	postResult := RunPostValidationChain(postCtx, PostValidationConfig{
+		CrossValRunner:   o.CrossRunner,
+		FinalPlanRunner:  o.FinalPlanRunner,
+		CrossValEnabled:  o.crossValidationEnabled(),
+		FinalPlanEnabled: finalPlanEnabled,
+		TasksFile:        o.session.TasksFile,
+		ImplOutputFile:   implOutputPath,
+		ValOutputFile:    valOutputPath,
+		SpecFile:         specFile,
+		PlanFile:         o.Config.OriginalPlanFile,
+		CrossAI:          o.Config.CrossAI,
+		CrossModel:       o.Config.CrossModel,
+		FinalPlanAI:      o.Config.FinalPlanAI,
+		FinalPlanModel:   o.Config.FinalPlanModel,
+		LogDir:           filepath.Dir(valOutputPath),
+		LogMaxBytes:      o.logMaxBytes(),
+	})
+	postSpan.SetAttributes(
+		tracing.String("ralph.gate", postResult.Gate),
+		tracing.String("ralph.verdict", postResult.Verdict),
+	)
+	postSpan.End()
+	if postResult.Gate != "" {
+		o.Dashboard.SetVerdict(postResult.Verdict, postResult.Feedback)
+		o.Metrics.ObservePhase(postResult.Gate, time.Since(postStart))
+		o.recordGate(postResult.Gate, postResult.Verdict)
+	}
+	if postResult.Gate == state.PhaseFinalPlanValidation {
+		o.saveFinalPlanVerdict(finalPlanInput, postResult.Verdict)
+	}
+
+	if postResult.Action == "continue" {
+		// Cross-val or final-plan rejected, continue loop
+		o.session.LastFeedback = base64.StdEncoding.EncodeToString([]byte(postResult.Feedback))
+		o.recordFeedback(postResult.Feedback)
+		o.session.Checkpoint = nil
+		return -1
+	}
+
+	// In a multi-file session, completing one spec moves on to the next
+	if switched, err := o.nextSpec(); err != nil {
+		logging.Error(err.Error())
+		return exitcode.Error
+	} else if switched {
+		if err := state.SaveState(o.session, o.StateDir); err != nil {
+			logging.Warn(fmt.Sprintf("Failed to save state: %v", err))
+		}
+		return -1
+	}
+
+	o.session.Status = state.StatusComplete
+	o.session.Checkpoint = nil
+	banner.PrintCompletionBanner(o.session.Iteration, int(time.Since(o.startTime).Seconds()))
+	o.notify(notification.EventCompleted, exitcode.Result{Code: exitcode.Success,
+		Reason: fmt.Sprintf("all tasks complete and validated after %d iteration(s)", o.session.Iteration)})
+	if err := state.SaveState(o.session, o.StateDir); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save complete state: %v", err))
+	}
+	return exitcode.Success
+}
@@ -0,0 +1,193 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// saveCheckpointedSession writes an interrupted session for iteration 1 with
+// the given checkpoint and returns the tasks file path.
+func saveCheckpointedSession(t *testing.T, dir string, cp *state.Checkpoint) string {
+	t.Helper()
+	tasksFile := filepath.Join(dir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("# Tasks\n- [x] T001 Done\n"), 0644))
+	hash, err := tasks.HashFile(tasksFile)
+	require.NoError(t, err)
+
+	require.NoError(t, state.SaveState(&state.SessionState{
+		SchemaVersion:   2,
+		SessionID:       "checkpoint-session",
+		Iteration:       1,
+		Status:          state.StatusInterrupted,
+		Phase:           cp.Phase,
+		TasksFile:       tasksFile,
+		TasksFileHash:   hash,
+		AICli:           "claude",
+		ImplModel:       "opus",
+		ValModel:        "opus",
+		MaxIterations:   3,
+		MaxInadmissible: 5,
+		CrossValidation: state.CrossValState{Enabled: 1, AI: "codex", Model: "default"},
+		Checkpoint:      cp,
+	}, dir))
+	return tasksFile
+}
+
+func resumeConfig(tasksFile string) *config.Config {
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.Resume = true
+	cfg.CrossValidate = false
+	cfg.EnableLearnings = false
+	return cfg
+}
+
+func TestOrchestrator_ResumeFromValidationCheckpoint(t *testing.T) {
+	tmpDir := t.TempDir()
+	implOutput := filepath.Join(tmpDir, "iteration-001", "implementation-output.txt")
+	require.NoError(t, os.MkdirAll(filepath.Dir(implOutput), 0755))
+	require.NoError(t, os.WriteFile(implOutput, []byte("impl done"), 0644))
+	tasksFile := saveCheckpointedSession(t, tmpDir, &state.Checkpoint{
+		Iteration: 1, Phase: state.PhaseValidation, ImplOutput: implOutput,
+	})
+
+	implRunner := &MockOrchestratorAIRunner{}
+	var valPrompt string
+	valRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			valPrompt = prompt
+			return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("COMPLETE", "ok")), 0644)
+		},
+	}
+
+	orch := NewOrchestrator(resumeConfig(tasksFile))
+	orch.CommandChecker = alwaysAvailable
+	orch.StateDir = tmpDir
+	orch.ImplRunner = implRunner
+	orch.ValRunner = valRunner
+
+	assert.Equal(t, exitcode.Success, orch.Run(context.Background()))
+	assert.Equal(t, 0, implRunner.CallCount, "implementation is not re-run")
+	assert.Contains(t, valPrompt, implOutput)
+
+	saved, err := state.LoadState(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, saved.Iteration, "the interrupted iteration is re-entered, not skipped")
+	assert.Nil(t, saved.Checkpoint)
+}
+
+func TestOrchestrator_ResumeFromCrossValidationCheckpoint(t *testing.T) {
+	tmpDir := t.TempDir()
+	iterDir := filepath.Join(tmpDir, "iteration-001")
+	require.NoError(t, os.MkdirAll(iterDir, 0755))
+	implOutput := filepath.Join(iterDir, "implementation-output.txt")
+	valOutput := filepath.Join(iterDir, "validation-output.txt")
+	require.NoError(t, os.WriteFile(implOutput, []byte("impl done"), 0644))
+	require.NoError(t, os.WriteFile(valOutput, []byte(makeOrchestratorValidationJSON("COMPLETE", "ok")), 0644))
+	tasksFile := saveCheckpointedSession(t, tmpDir, &state.Checkpoint{
+		Iteration: 1, Phase: state.PhaseCrossValidation, ImplOutput: implOutput, ValOutput: valOutput,
+	})
+
+	implRunner := &MockOrchestratorAIRunner{}
+	valRunner := &MockOrchestratorAIRunner{}
+	crossRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte(`{"RALPH_CROSS_VALIDATION": {"verdict": "CONFIRMED", "feedback": "agreed"}}`), 0644)
+		},
+	}
+
+	orch := NewOrchestrator(resumeConfig(tasksFile))
+	orch.CommandChecker = alwaysAvailable
+	orch.StateDir = tmpDir
+	orch.ImplRunner = implRunner
+	orch.ValRunner = valRunner
+	orch.CrossRunner = crossRunner
+
+	assert.Equal(t, exitcode.Success, orch.Run(context.Background()))
+	assert.Equal(t, 0, implRunner.CallCount)
+	assert.Equal(t, 0, valRunner.CallCount)
+	assert.Equal(t, 1, crossRunner.CallCount)
+}
+
+func TestOrchestrator_ResumeCheckpointMissingOutputRestarts(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := saveCheckpointedSession(t, tmpDir, &state.Checkpoint{
+		Iteration: 1, Phase: state.PhaseValidation, ImplOutput: filepath.Join(tmpDir, "gone.txt"),
+	})
+
+	implRunner := &MockOrchestratorAIRunner{}
+	valRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("COMPLETE", "ok")), 0644)
+		},
+	}
+
+	orch := NewOrchestrator(resumeConfig(tasksFile))
+	orch.CommandChecker = alwaysAvailable
+	orch.StateDir = tmpDir
+	orch.ImplRunner = implRunner
+	orch.ValRunner = valRunner
+
+	assert.Equal(t, exitcode.Success, orch.Run(context.Background()))
+	assert.Equal(t, 1, implRunner.CallCount)
+}
+
+// TestOrchestrator_CheckpointsAfterImplementation verifies that the
+// checkpoint is saved once implementation finishes, with a refreshed tasks
+// hash so a resume is not rejected for the implementer's own edits.
+func TestOrchestrator_CheckpointsAfterImplementation(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("# Tasks\n- [ ] T001 Work\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 1
+	cfg.CrossValidate = false
+	cfg.EnableLearnings = false
+
+	var atValidation *state.SessionState
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			require.NoError(t, os.WriteFile(tasksFile, []byte("# Tasks\n- [x] T001 Work\n"), 0644))
+			return os.WriteFile(outputPath, []byte("done"), 0644)
+		},
+	}
+	valRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			var err error
+			atValidation, err = state.LoadState(tmpDir)
+			require.NoError(t, err)
+			out := `{"RALPH_VALIDATION": {"verdict": "NEEDS_MORE_WORK", "feedback": "more", "completed_tasks": ["T001"]}}`
+			return os.WriteFile(outputPath, []byte(out), 0644)
+		},
+	}
+
+	orch := NewOrchestrator(cfg)
+	orch.CommandChecker = alwaysAvailable
+	orch.StateDir = tmpDir
+	orch.ImplRunner = implRunner
+	orch.ValRunner = valRunner
+	orch.Run(context.Background())
+
+	require.NotNil(t, atValidation)
+	require.NotNil(t, atValidation.Checkpoint)
+	assert.Equal(t, state.PhaseValidation, atValidation.Checkpoint.Phase)
+	assert.Equal(t, 1, atValidation.Checkpoint.Iteration)
+	assert.Equal(t, filepath.Join(tmpDir, "iteration-001", "implementation-output.txt"), atValidation.Checkpoint.ImplOutput)
+
+	hash, err := tasks.HashFile(tasksFile)
+	require.NoError(t, err)
+	assert.Equal(t, hash, atValidation.TasksFileHash)
+	assert.NoError(t, state.ValidateState(atValidation, tasksFile))
+}
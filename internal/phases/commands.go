@@ -0,0 +1,96 @@
+package phases
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/prompt"
+)
+
+// CommandsFile is the per-iteration file holding the shell commands the
+// implementer ran, as a JSON list of parser.Command.
+const CommandsFile = "commands.json"
+
+const (
+	// maxPromptCommands caps the commands listed in the validation prompt;
+	// the earliest are dropped first.
+	maxPromptCommands = 100
+	// maxPromptCommandLen caps the length of one listed command.
+	maxPromptCommandLen = 300
+)
+
+// captureCommands extracts the shell commands from the raw event stream
+// the AI CLI wrote next to implOutputPath and stores them in the iteration
+// directory. CLIs without an event stream leave no transcript.
+func (o *Orchestrator) captureCommands(implOutputPath, iterDir string) {
+	var raw []byte
+	for _, suffix := range []string{".stream.json", ".jsonl"} {
+		if data, err := os.ReadFile(implOutputPath + suffix); err == nil {
+			raw = data
+			break
+		}
+	}
+	if raw == nil {
+		return
+	}
+	commands := parser.ParseCommands(string(raw))
+	if commands == nil {
+		commands = []parser.Command{}
+	}
+	data, err := json.MarshalIndent(commands, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.WriteFile(filepath.Join(iterDir, CommandsFile), append(data, '\n'), 0644); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save the command transcript: %v", err))
+		return
+	}
+	failed := 0
+	for _, c := range commands {
+		if c.ExitCode != 0 {
+			failed++
+		}
+	}
+	logging.Info(fmt.Sprintf("Implementer ran %d command(s), %d failed or unfinished", len(commands), failed))
+}
+
+// commandsRunSection lists the iteration's command transcript for the
+// validation prompt, or returns "" when there is none.
+func commandsRunSection(iterDir string) string {
+	return prompt.BuildCommandsRunSection(commandLines(iterDir))
+}
+
+// commandLines returns the iteration's command transcript as one
+// "exit N: command" line per command, or nil when there is none.
+func commandLines(iterDir string) []string {
+	data, err := os.ReadFile(filepath.Join(iterDir, CommandsFile))
+	if err != nil {
+		return nil
+	}
+	var commands []parser.Command
+	if err := json.Unmarshal(data, &commands); err != nil {
+		return nil
+	}
+	lines := []string{}
+	if len(commands) > maxPromptCommands {
+		lines = append(lines, fmt.Sprintf("(%d earlier commands not shown)", len(commands)-maxPromptCommands))
+		commands = commands[len(commands)-maxPromptCommands:]
+	}
+	for _, c := range commands {
+		code := "?"
+		if c.ExitCode >= 0 {
+			code = fmt.Sprint(c.ExitCode)
+		}
+		command := strings.Join(strings.Fields(c.Command), " ")
+		if r := []rune(command); len(r) > maxPromptCommandLen {
+			command = string(r[:maxPromptCommandLen]) + "…"
+		}
+		lines = append(lines, fmt.Sprintf("exit %s: %s", code, command))
+	}
+	return lines
+}
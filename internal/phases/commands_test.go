@@ -0,0 +1,72 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+)
+
+// TestOrchestrator_CapturesCommands verifies that the commands in the
+// implementer's event stream are stored with the iteration and listed in
+// the validation prompt.
+func TestOrchestrator_CapturesCommands(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] T001 Run tests\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 1
+	cfg.CrossValidate = false
+	cfg.EnableLearnings = false
+
+	stream := `{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"go test\n  ./..."}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"Exit code 1","is_error":true}]}}`
+	impl := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			require.NoError(t, os.WriteFile(outputPath+".stream.json", []byte(stream), 0644))
+			return os.WriteFile(outputPath, []byte("tests pass"), 0644)
+		},
+	}
+	var valPrompt string
+	val := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			valPrompt = prompt
+			return os.WriteFile(outputPath, []byte(`{"RALPH_VALIDATION": {"verdict": "NEEDS_MORE_WORK", "feedback": "tests fail"}}`), 0644)
+		},
+	}
+
+	orch := NewOrchestrator(cfg)
+	orch.CommandChecker = alwaysAvailable
+	orch.StateDir = tmpDir
+	orch.ImplRunner = impl
+	orch.ValRunner = val
+	orch.Run(context.Background())
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "iteration-001", CommandsFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"exit_code": 1`)
+	assert.Contains(t, valPrompt, "COMMANDS THE IMPLEMENTER RAN")
+	assert.Contains(t, valPrompt, "- exit 1: go test ./...")
+}
+
+func TestCommandsRunSection(t *testing.T) {
+	dir := t.TempDir()
+	assert.Empty(t, commandsRunSection(dir), "no transcript")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, CommandsFile), []byte("[]"), 0644))
+	assert.Contains(t, commandsRunSection(dir), "(no shell commands were run)")
+
+	long := `[` + strings.Repeat(`{"command":"true","exit_code":0},`, maxPromptCommands) + `{"command":"make deploy","exit_code":-1}]`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, CommandsFile), []byte(long), 0644))
+	section := commandsRunSection(dir)
+	assert.Contains(t, section, "(1 earlier commands not shown)")
+	assert.Contains(t, section, "- exit ?: make deploy")
+}
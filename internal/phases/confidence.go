@@ -0,0 +1,49 @@
+package phases
+
+import (
+	"fmt"
+
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// lowConfidence reports whether the latest verdict falls below
+// MIN_CONFIDENCE. A verdict without a confidence counts as low, since the
+// validator was asked for one.
+func (o *Orchestrator) lowConfidence() bool {
+	if o.Config.MinConfidence <= 0 {
+		return false
+	}
+	c := o.session.Confidence
+	return c == nil || *c < o.Config.MinConfidence
+}
+
+// crossValidationEnabled reports whether the post-validation chain runs
+// cross-validation: always when CROSS_VALIDATE is on, and otherwise for a
+// COMPLETE verdict given with less than MIN_CONFIDENCE or looking
+// suspicious (see suspiciousCompletion).
+func (o *Orchestrator) crossValidationEnabled() bool {
+	if o.CrossRunner == nil {
+		return false
+	}
+	if o.Config.CrossValidate {
+		return true
+	}
+	if !o.lowConfidence() {
+		if reason := o.suspiciousCompletion(); reason != "" {
+			logging.Warn(fmt.Sprintf("Suspicious completion: %s; cross-validating", reason))
+			o.session.AutoCrossValidations = append(o.session.AutoCrossValidations, state.AutoCrossValidation{
+				Iteration: o.session.Iteration,
+				Reason:    reason,
+			})
+			return true
+		}
+		return false
+	}
+	reported := "no confidence"
+	if c := o.session.Confidence; c != nil {
+		reported = fmt.Sprintf("confidence %.2f", *c)
+	}
+	logging.Info(fmt.Sprintf("Validator reported %s (minimum %.2f); cross-validating", reported, o.Config.MinConfidence))
+	return true
+}
@@ -0,0 +1,87 @@
+package phases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// confidenceTestRun runs one iteration with cross-validation disabled in
+// which the validator says COMPLETE with the given confidence JSON value
+// ("" for none). It returns the exit code, the cross runner and the state
+// directory.
+func confidenceTestRun(t *testing.T, minConfidence float64, confidence string) (int, *MockOrchestratorAIRunner, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	cfg.MinConfidence = minConfidence
+
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte("Done."), 0644)
+		},
+	}
+	valRunner := &MockOrchestratorAIRunner{}
+	o := newTestOrchestrator(t, cfg, tmpDir, implRunner, valRunner)
+	valRunner.RunFunc = func(ctx context.Context, prompt string, outputPath string) error {
+		_ = os.WriteFile(cfg.TasksFile, []byte("- [x] T001 Task\n"), 0644)
+		field := ""
+		if confidence != "" {
+			field = `, "confidence": ` + confidence
+		}
+		return os.WriteFile(outputPath, []byte(fmt.Sprintf(`{"RALPH_VALIDATION": {"verdict": "COMPLETE"%s}}`, field)), 0644)
+	}
+	crossRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte(makeOrchestratorCrossValidationJSON("CONFIRMED", "Looks right")), 0644)
+		},
+	}
+	o.CrossRunner = crossRunner
+	return o.Run(context.Background()), crossRunner, tmpDir
+}
+
+func TestOrchestrator_LowConfidenceForcesCrossValidation(t *testing.T) {
+	code, crossRunner, stateDir := confidenceTestRun(t, 0.8, "0.55")
+	assert.Equal(t, exitcode.Success, code)
+	assert.Equal(t, 1, crossRunner.CallCount, "a COMPLETE verdict below MIN_CONFIDENCE is cross-validated")
+
+	saved, err := state.LoadState(stateDir)
+	require.NoError(t, err)
+	require.NotNil(t, saved.Confidence)
+	assert.Equal(t, 0.55, *saved.Confidence)
+}
+
+func TestOrchestrator_ConfidentVerdictSkipsCrossValidation(t *testing.T) {
+	code, crossRunner, _ := confidenceTestRun(t, 0.8, "0.95")
+	assert.Equal(t, exitcode.Success, code)
+	assert.Equal(t, 0, crossRunner.CallCount)
+}
+
+func TestOrchestrator_MissingConfidenceCountsAsLow(t *testing.T) {
+	_, crossRunner, _ := confidenceTestRun(t, 0.8, "")
+	assert.Equal(t, 1, crossRunner.CallCount)
+}
+
+func TestOrchestrator_NoMinConfidenceKeepsCrossValidationOff(t *testing.T) {
+	_, crossRunner, _ := confidenceTestRun(t, 0, "0.1")
+	assert.Equal(t, 0, crossRunner.CallCount)
+}
+
+func TestAggregateVotes_TakesLowestConfidence(t *testing.T) {
+	high, low := 0.9, 0.6
+	result := aggregateVotes([]memberVote{
+		{label: "a", result: ValidationPhaseResult{Verdict: "COMPLETE", Confidence: &high}},
+		{label: "b", result: ValidationPhaseResult{Verdict: "COMPLETE", Confidence: &low}},
+		{label: "c", result: ValidationPhaseResult{Verdict: "NEEDS_MORE_WORK"}},
+	})
+	require.NotNil(t, result.Confidence)
+	assert.Equal(t, 0.6, *result.Confidence)
+}
@@ -0,0 +1,44 @@
+package phases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CodexForgeBR/cli-tools/internal/ai"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+)
+
+// container returns the container the AI CLIs run in (CONTAINER_IMAGE),
+// or nil when they run on the host.
+func (o *Orchestrator) container() *ai.Container {
+	if o.Config.ContainerImage == "" {
+		return nil
+	}
+	return &ai.Container{Runtime: o.Config.ContainerRuntime, Image: o.Config.ContainerImage}
+}
+
+// phaseContainer prepares CONTAINER_IMAGE: it prunes the stopped
+// containers earlier runs left behind and pulls the image per
+// CONTAINER_PULL. A failed pull stops the run, as no AI CLI could start.
+func (o *Orchestrator) phaseContainer(ctx context.Context) int {
+	c := o.container()
+	if c == nil || o.Config.Replay != "" {
+		return -1
+	}
+	o.pruneContainers(ctx)
+	logging.Info(fmt.Sprintf("Preparing container image %s (%s, pull %s)", c.Image, c.Runtime, o.Config.ContainerPull))
+	if err := c.Pull(ctx, o.Config.ContainerPull); err != nil {
+		logging.Error(fmt.Sprintf("Container image unavailable: %v", err))
+		return exitcode.Error
+	}
+	return -1
+}
+
+// pruneContainers removes the stopped containers ralph-loop started.
+// Failures are only logged.
+func (o *Orchestrator) pruneContainers(ctx context.Context) {
+	if err := o.container().Prune(ctx); err != nil {
+		logging.Debug(fmt.Sprintf("Failed to prune stopped containers: %v", err))
+	}
+}
@@ -0,0 +1,63 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeContainerRuntime writes a runtime script that logs its arguments and
+// fails "pull", and returns its path and the log path.
+func fakeContainerRuntime(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	log := filepath.Join(dir, "calls.log")
+	rt := filepath.Join(dir, "docker")
+	script := "#!/bin/sh\necho \"$*\" >> " + log + "\n[ \"$1\" = pull ] && { echo 'manifest unknown' >&2; exit 1; }\nexit 0\n"
+	require.NoError(t, os.WriteFile(rt, []byte(script), 0755))
+	return rt, log
+}
+
+func TestPhaseContainer(t *testing.T) {
+	if os.PathSeparator != '/' {
+		t.Skip("fake runtime is a shell script")
+	}
+	rt, log := fakeContainerRuntime(t)
+	newOrch := func(pull string) *Orchestrator {
+		cfg := config.NewDefaultConfig()
+		cfg.ContainerImage = "agent:1"
+		cfg.ContainerRuntime = rt
+		cfg.ContainerPull = pull
+		return NewOrchestrator(cfg)
+	}
+
+	assert.Equal(t, -1, newOrch(config.ContainerPullMissing).phaseContainer(context.Background()), "present image is not pulled")
+	assert.Equal(t, exitcode.Error, newOrch(config.ContainerPullAlways).phaseContainer(context.Background()))
+
+	data, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"container prune -f --filter label=ralph-loop",
+		"image inspect agent:1",
+		"container prune -f --filter label=ralph-loop",
+		"pull agent:1",
+	}, strings.Split(strings.TrimSpace(string(data)), "\n"))
+}
+
+func TestPhaseContainer_Skipped(t *testing.T) {
+	o := NewOrchestrator(config.NewDefaultConfig())
+	assert.Nil(t, o.container())
+	assert.Equal(t, -1, o.phaseContainer(context.Background()))
+
+	o.Config.ContainerImage = "agent:1"
+	o.Config.ContainerRuntime = filepath.Join(t.TempDir(), "missing")
+	o.Config.Replay = "fixtures"
+	assert.Equal(t, -1, o.phaseContainer(context.Background()), "replay runs no container")
+}
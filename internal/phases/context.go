@@ -0,0 +1,40 @@
+package phases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/contextpack"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/prompt"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// promptContext returns the {{CONTEXT}} section of the implementation
+// prompt: excerpts of the files related to the tasks that are ready to be
+// worked on, bounded by ContextMaxTokens. It is "" when disabled or when
+// nothing related is found.
+func (o *Orchestrator) promptContext() string {
+	if o.Config.ContextMaxTokens <= 0 {
+		return ""
+	}
+	list, err := tasks.ListTasks(o.session.TasksFile)
+	if err != nil {
+		return ""
+	}
+	var texts []string
+	for _, t := range tasks.Unblocked(list) {
+		texts = append(texts, t.Text)
+	}
+	if len(texts) == 0 {
+		return ""
+	}
+
+	root := o.workDir()
+	files := contextpack.Select(root, texts, o.session.TasksFile, o.Config.LearningsFile)
+	excerpts, packed := contextpack.Pack(root, files, contextpack.Keywords(texts), o.Config.ContextMaxTokens)
+	if len(packed) > 0 {
+		logging.Info(fmt.Sprintf("Prompt context: %s", strings.Join(packed, ", ")))
+	}
+	return prompt.BuildContextSection(excerpts)
+}
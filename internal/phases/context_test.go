@@ -0,0 +1,52 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOrchestrator_PacksTaskContext verifies that files mentioned by the
+// pending tasks are excerpted into the implementation prompt.
+func TestOrchestrator_PacksTaskContext(t *testing.T) {
+	tmpDir := t.TempDir()
+	workDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "server.go"), []byte("package main\n\nfunc serve() {}\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	cfg.ContextMaxTokens = 1000
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte("Done."), 0644)
+		},
+	}
+	valRunner := &MockOrchestratorAIRunner{}
+	o := newTestOrchestrator(t, cfg, tmpDir, implRunner, valRunner)
+	require.NoError(t, os.WriteFile(cfg.TasksFile, []byte("- [ ] T001 Add graceful shutdown to server.go\n"), 0644))
+	o.WorkDir = workDir
+	valRunner.RunFunc = func(ctx context.Context, prompt string, outputPath string) error {
+		_ = os.WriteFile(cfg.TasksFile, []byte("- [x] T001 Add graceful shutdown to server.go\n"), 0644)
+		return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("COMPLETE", "")), 0644)
+	}
+
+	o.Run(context.Background())
+	require.NotEmpty(t, implRunner.PromptLog)
+	assert.Contains(t, implRunner.PromptLog[0], "RELEVANT CODE")
+	assert.Contains(t, implRunner.PromptLog[0], "### server.go (lines 1-3 of 3)")
+	assert.Contains(t, implRunner.PromptLog[0], "func serve() {}")
+}
+
+func TestPromptContext_Disabled(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	o := newTestOrchestrator(t, cfg, tmpDir, &MockOrchestratorAIRunner{}, &MockOrchestratorAIRunner{})
+	o.session = &state.SessionState{TasksFile: cfg.TasksFile}
+	assert.Empty(t, o.promptContext())
+}
@@ -0,0 +1,107 @@
+package phases
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/banner"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/notification"
+	"github.com/CodexForgeBR/cli-tools/internal/schedule"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// controlPollInterval is how often a waiting loop checks for
+// `ralph-loop resume-now` and for a graceful shutdown.
+var controlPollInterval = time.Second
+
+// pauseIfRequested waits, after `ralph-loop pause`, at a phase boundary
+// until `ralph-loop resume-now`. The state is saved first, so the pause
+// survives an interruption. It returns an exit code other than -1 when the
+// loop stops instead of resuming.
+func (o *Orchestrator) pauseIfRequested(ctx context.Context, detail string) int {
+	if !state.TakeControl(o.StateDir, state.ControlPause) {
+		return -1
+	}
+
+	logging.Phase(fmt.Sprintf("Paused %s; run ralph-loop resume-now to continue", detail))
+	return o.pause(ctx, detail)
+}
+
+// pause saves the session as paused and waits for ralph-loop resume-now. It
+// returns -1 once resumed, or the exit code when the pause was interrupted
+// or a graceful shutdown was requested.
+func (o *Orchestrator) pause(ctx context.Context, detail string) int {
+	phase := o.session.Phase
+	o.session.Phase = state.PhasePaused
+	o.Dashboard.SetPhase(state.PhasePaused)
+	if err := state.SaveState(o.session, o.StateDir); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save paused state: %v", err))
+	}
+
+	start := time.Now()
+	woken, err := o.waitUntil(ctx, time.Time{})
+	o.pausedFor += time.Since(start)
+	o.session.Phase = phase
+	o.Dashboard.SetPhase(phase)
+	if err != nil {
+		banner.PrintInterruptedBanner(o.session.Iteration, state.PhasePaused)
+		o.notify(notification.EventInterrupted, exitcode.Result{Code: exitcode.Interrupted, Reason: o.interruptReason("interrupted while paused " + detail)})
+		if saveErr := state.SaveState(o.session, o.StateDir); saveErr != nil {
+			logging.Warn(fmt.Sprintf("Failed to save interrupted state: %v", saveErr))
+		}
+		return exitcode.Interrupted
+	}
+	if !woken {
+		return o.stopForShutdown(detail)
+	}
+	if err := state.SaveState(o.session, o.StateDir); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save resumed state: %v", err))
+	}
+	logging.Success("Resumed by ralph-loop resume-now")
+	return -1
+}
+
+// waitUntil waits until target, or indefinitely when target is zero. The
+// wait ends early, reporting true, on `ralph-loop resume-now`, and,
+// reporting false, when a graceful shutdown is requested. err is the
+// context's error when it was canceled.
+func (o *Orchestrator) waitUntil(ctx context.Context, target time.Time) (woken bool, err error) {
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var resumed atomic.Bool
+	go func() {
+		ticker := time.NewTicker(controlPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-waitCtx.Done():
+				return
+			case <-ticker.C:
+				if state.TakeControl(o.StateDir, state.ControlResume) {
+					resumed.Store(true)
+					cancel()
+					return
+				}
+				if _, graceful := o.receivedSignal(); graceful {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	if target.IsZero() {
+		<-waitCtx.Done()
+	} else {
+		_ = schedule.WaitUntil(waitCtx, target)
+	}
+	cancel()
+	if resumed.Load() {
+		return true, nil
+	}
+	return false, ctx.Err()
+}
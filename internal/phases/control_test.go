@@ -0,0 +1,122 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/schedule"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// fastControlPoll makes waiting loops notice control requests quickly.
+func fastControlPoll(t *testing.T) {
+	t.Helper()
+	saved := controlPollInterval
+	controlPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { controlPollInterval = saved })
+}
+
+// resumeWhenPaused requests a resume once the loop in dir saved its state
+// as paused, and reports on the returned channel that it did.
+func resumeWhenPaused(t *testing.T, dir string) <-chan struct{} {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 500; i++ {
+			if s, err := state.LoadState(dir); err == nil && s.Phase == state.PhasePaused && state.PendingControl(dir) == "" {
+				_ = state.RequestControl(dir, state.ControlResume)
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+	return done
+}
+
+func TestOrchestrator_PauseAfterImplementationAndResume(t *testing.T) {
+	fastControlPoll(t)
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	dir := t.TempDir()
+	impl := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			require.NoError(t, state.RequestControl(dir, state.ControlPause))
+			return os.WriteFile(outputPath, []byte("Implemented."), 0644)
+		},
+	}
+	val := completeValidator(cfg)
+	o := newTestOrchestrator(t, cfg, dir, impl, val)
+	resumed := resumeWhenPaused(t, dir)
+
+	assert.Equal(t, exitcode.Success, o.Run(context.Background()))
+	<-resumed
+	assert.Equal(t, 1, val.CallCount, "validation runs after the resume")
+	assert.Equal(t, "", state.PendingControl(dir))
+}
+
+func TestPauseIfRequested_ShutdownWhilePaused(t *testing.T) {
+	fastControlPoll(t)
+	dir := t.TempDir()
+	o := &Orchestrator{Config: config.NewDefaultConfig(), StateDir: dir, session: &state.SessionState{Iteration: 1, Phase: state.PhaseValidation}}
+	o.Config.NotifyChatID = ""
+	assert.Equal(t, -1, o.pauseIfRequested(context.Background(), "before iteration 1"))
+
+	require.NoError(t, state.RequestControl(dir, state.ControlPause))
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		o.RequestShutdown("SIGTERM")
+	}()
+	assert.Equal(t, exitcode.Interrupted, o.pauseIfRequested(context.Background(), "before iteration 1"))
+	assert.Equal(t, state.PhaseValidation, o.session.Phase)
+	assert.Equal(t, "SIGTERM: stopped before iteration 1", o.exit.Reason)
+}
+
+func TestPauseIfRequested_Interrupted(t *testing.T) {
+	fastControlPoll(t)
+	dir := t.TempDir()
+	o := &Orchestrator{Config: config.NewDefaultConfig(), StateDir: dir, session: &state.SessionState{Iteration: 1}}
+	o.Config.NotifyChatID = ""
+	require.NoError(t, state.RequestControl(dir, state.ControlPause))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, exitcode.Interrupted, o.pauseIfRequested(ctx, "before iteration 1"))
+}
+
+func TestWaitUntil_ResumeNowWakesScheduledWait(t *testing.T) {
+	fastControlPoll(t)
+	dir := t.TempDir()
+	o := &Orchestrator{StateDir: dir}
+	require.NoError(t, state.RequestControl(dir, state.ControlResume))
+
+	start := time.Now()
+	woken, err := o.waitUntil(context.Background(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, woken)
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestWaitPauseWindow_ResumeNowSkipsTheWindow(t *testing.T) {
+	fastControlPoll(t)
+	dir := t.TempDir()
+	windows, err := schedule.ParsePauseWindows(currentWindow())
+	require.NoError(t, err)
+	o := &Orchestrator{StateDir: dir, session: &state.SessionState{Phase: state.PhaseValidation}}
+	require.NoError(t, state.RequestControl(dir, state.ControlResume))
+
+	_, code := o.waitPauseWindow(context.Background(), windows)
+	assert.Equal(t, -1, code)
+	assert.Equal(t, state.PhaseValidation, o.session.Phase)
+
+	paused, code := o.waitPauseWindow(context.Background(), windows)
+	assert.Equal(t, -1, code)
+	assert.Zero(t, paused, "the rest of the window is skipped")
+}
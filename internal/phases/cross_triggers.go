@@ -0,0 +1,99 @@
+package phases
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// CrossTriggers are the suspicious completions that are cross-validated
+// even when cross-validation is off.
+type CrossTriggers struct {
+	// FirstComplete triggers on a COMPLETE verdict in the first iteration.
+	FirstComplete bool
+	// NoChanges triggers when the iteration checked tasks without
+	// changing any file but the tasks file.
+	NoChanges bool
+	// MaxChecked triggers when the iteration checked more than this many
+	// tasks; 0 disables it.
+	MaxChecked int
+}
+
+// ParseCrossTriggers parses AUTO_CROSS_VALIDATE: comma-separated
+// first-complete, no-changes and max-checked=N entries.
+func ParseCrossTriggers(spec string) (CrossTriggers, error) {
+	var t CrossTriggers
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		name, value, hasValue := strings.Cut(entry, "=")
+		switch {
+		case entry == "":
+		case entry == "first-complete":
+			t.FirstComplete = true
+		case entry == "no-changes":
+			t.NoChanges = true
+		case name == "max-checked" && hasValue:
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 1 {
+				return CrossTriggers{}, fmt.Errorf("max-checked must be a positive number, got %q", value)
+			}
+			t.MaxChecked = n
+		default:
+			return CrossTriggers{}, fmt.Errorf("unknown trigger %q (want first-complete, no-changes or max-checked=N)", entry)
+		}
+	}
+	return t, nil
+}
+
+// suspiciousCompletion returns why the current iteration's COMPLETE verdict
+// looks suspicious under the configured triggers, or "" when it does not.
+// The checks that need the task count from before the iteration are
+// skipped when it is unknown, as after a resume.
+func (o *Orchestrator) suspiciousCompletion() string {
+	t := o.crossTriggers
+	if t.FirstComplete && o.session.Iteration == 1 {
+		return "COMPLETE on the first iteration"
+	}
+	if o.checkedBefore < 0 || (!t.NoChanges && t.MaxChecked == 0) {
+		return ""
+	}
+	after, err := tasks.CountChecked(o.session.TasksFile)
+	if err != nil {
+		return ""
+	}
+	checked := after - o.checkedBefore
+	if t.MaxChecked > 0 && checked > t.MaxChecked {
+		return fmt.Sprintf("%d tasks checked in one iteration (limit %d)", checked, t.MaxChecked)
+	}
+	if t.NoChanges && checked > 0 && o.diffBase != "" {
+		changed, err := o.iterationChanges()
+		if err != nil {
+			logging.Debug(fmt.Sprintf("No iteration changes for the no-changes trigger: %v", err))
+			return ""
+		}
+		if len(withoutFile(o.workDir(), changed, o.session.TasksFile)) == 0 {
+			return fmt.Sprintf("%d task(s) checked without changing any file", checked)
+		}
+	}
+	return ""
+}
+
+// withoutFile returns changed, paths relative to root, without file.
+func withoutFile(root string, changed []string, file string) []string {
+	rel, ok := insideDir(root, file)
+	if !ok {
+		return changed
+	}
+	rel = filepath.ToSlash(rel)
+	var kept []string
+	for _, path := range changed {
+		if filepath.ToSlash(path) != rel {
+			kept = append(kept, path)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,97 @@
+package phases
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/gitdiff"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+func TestParseCrossTriggers(t *testing.T) {
+	tr, err := ParseCrossTriggers(" first-complete, no-changes ,max-checked=5")
+	require.NoError(t, err)
+	assert.Equal(t, CrossTriggers{FirstComplete: true, NoChanges: true, MaxChecked: 5}, tr)
+
+	tr, err = ParseCrossTriggers("")
+	require.NoError(t, err)
+	assert.Equal(t, CrossTriggers{}, tr)
+
+	_, err = ParseCrossTriggers("max-checked=0")
+	assert.ErrorContains(t, err, "max-checked must be a positive number")
+	_, err = ParseCrossTriggers("always")
+	assert.ErrorContains(t, err, `unknown trigger "always"`)
+}
+
+func TestSuspiciousCompletion_FirstComplete(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	o := newTestOrchestrator(t, cfg, t.TempDir(), nil, nil)
+	o.crossTriggers = CrossTriggers{FirstComplete: true}
+	o.session = &state.SessionState{Iteration: 2, TasksFile: cfg.TasksFile}
+	assert.Empty(t, o.suspiciousCompletion())
+
+	o.session.Iteration = 1
+	assert.Equal(t, "COMPLETE on the first iteration", o.suspiciousCompletion())
+}
+
+func TestSuspiciousCompletion_MaxChecked(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	o := newTestOrchestrator(t, cfg, t.TempDir(), nil, nil)
+	require.NoError(t, os.WriteFile(cfg.TasksFile, []byte("- [x] T001 A\n- [x] T002 B\n- [x] T003 C\n"), 0644))
+	o.crossTriggers = CrossTriggers{MaxChecked: 2}
+	o.checkedBefore = 0
+	o.session = &state.SessionState{Iteration: 2, TasksFile: cfg.TasksFile}
+	assert.Equal(t, "3 tasks checked in one iteration (limit 2)", o.suspiciousCompletion())
+
+	o.checkedBefore = 1
+	assert.Empty(t, o.suspiciousCompletion())
+
+	// Unknown after a resume.
+	o.checkedBefore = -1
+	assert.Empty(t, o.suspiciousCompletion())
+}
+
+func TestSuspiciousCompletion_NoChanges(t *testing.T) {
+	dir := diffTestRepo(t)
+	cfg := config.NewDefaultConfig()
+	o := newTestOrchestrator(t, cfg, filepath.Join(dir, ".ralph-loop"), nil, nil)
+	o.WorkDir = dir
+	o.crossTriggers = CrossTriggers{NoChanges: true}
+	o.checkedBefore = 0
+	o.session = &state.SessionState{Iteration: 2, TasksFile: cfg.TasksFile}
+	base, err := gitdiff.Snapshot(dir)
+	require.NoError(t, err)
+	o.diffBase = base
+
+	// Only the tasks file changed.
+	require.NoError(t, os.WriteFile(cfg.TasksFile, []byte("- [x] T001 Task\n"), 0644))
+	assert.Equal(t, "1 task(s) checked without changing any file", o.suspiciousCompletion())
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644))
+	assert.Empty(t, o.suspiciousCompletion())
+}
+
+// TestCrossValidationEnabled_RecordsSuspiciousCompletion verifies that a
+// suspicious completion turns cross-validation on and is recorded in the
+// session.
+func TestCrossValidationEnabled_RecordsSuspiciousCompletion(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	o := newTestOrchestrator(t, cfg, t.TempDir(), nil, nil)
+	o.CrossRunner = &MockOrchestratorAIRunner{}
+	o.crossTriggers = CrossTriggers{FirstComplete: true}
+	o.session = &state.SessionState{Iteration: 2, TasksFile: cfg.TasksFile}
+	assert.False(t, o.crossValidationEnabled())
+
+	o.session.Iteration = 1
+	assert.True(t, o.crossValidationEnabled())
+	assert.Equal(t, []state.AutoCrossValidation{{Iteration: 1, Reason: "COMPLETE on the first iteration"}}, o.session.AutoCrossValidations)
+
+	// Without a cross runner there is nothing to run.
+	o.CrossRunner = nil
+	assert.False(t, o.crossValidationEnabled())
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
 	"github.com/CodexForgeBR/cli-tools/internal/parser"
 	"github.com/CodexForgeBR/cli-tools/internal/prompt"
+	"github.com/CodexForgeBR/cli-tools/internal/schema"
 )
 
 // CrossValidationConfig configures the cross-validation phase.
@@ -57,7 +58,15 @@ func RunCrossValidation(ctx context.Context, cfg CrossValidationConfig) CrossVal
 		}
 	}
 
-	// Parse cross-validation result
+	// Parse cross-validation result, once more formatted if need be
+	outputPath, schemaErrs := conformOutput(ctx, cfg.Runner, "RALPH_CROSS_VALIDATION", outputPath, schema.MustLookup("RALPH_CROSS_VALIDATION"))
+	if len(schemaErrs) > 0 {
+		return CrossValidationResult{
+			Action:   "exit",
+			ExitCode: exitcode.Error,
+			Feedback: formatFeedback("RALPH_CROSS_VALIDATION", schemaErrs),
+		}
+	}
 	output, err := os.ReadFile(outputPath)
 	if err != nil {
 		return CrossValidationResult{
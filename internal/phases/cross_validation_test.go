@@ -147,7 +147,7 @@ func TestRunCrossValidation_UnknownVerdict(t *testing.T) {
 
 	assert.Equal(t, "exit", result.Action)
 	assert.Equal(t, exitcode.Error, result.ExitCode)
-	assert.Contains(t, result.Feedback, "unknown cross-validation verdict")
+	assert.Contains(t, result.Feedback, `RALPH_CROSS_VALIDATION.verdict: "UNKNOWN" is not one of CONFIRMED, REJECTED`)
 }
 
 // TestRunCrossValidation_NoVerdictFound tests handling when no verdict is parsed.
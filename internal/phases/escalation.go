@@ -0,0 +1,68 @@
+package phases
+
+import (
+	"fmt"
+
+	"github.com/CodexForgeBR/cli-tools/internal/ai"
+	"github.com/CodexForgeBR/cli-tools/internal/banner"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// applyImplModel points the implementation runner at the current
+// implementation model. The runner is built before a resumed session
+// restores its (possibly escalated) model, so this runs at loop start and
+// after every escalation.
+func (o *Orchestrator) applyImplModel() {
+	if ms, ok := o.ImplRunner.(ai.ModelSetter); ok {
+		ms.SetModel(o.Config.ImplModel)
+	}
+}
+
+// trackModelEscalation feeds the validation verdict into the model ladder
+// and switches the implementation model to the next rung once
+// EscalateAfter consecutive NEEDS_MORE_WORK verdicts hit the same tasks.
+func (o *Orchestrator) trackModelEscalation(valResult ValidationPhaseResult) {
+	ladder := o.Config.ModelLadder()
+	if len(ladder) == 0 || o.Config.EscalateAfter <= 0 {
+		return
+	}
+
+	esc := &o.session.ModelEscalation
+	streak := state.UpdateEscalationStreak(esc, valResult.Verdict, valResult.IncompleteTasks)
+	if streak < o.Config.EscalateAfter || esc.Level >= len(ladder)-1 {
+		return
+	}
+
+	from := o.Config.ImplModel
+	esc.Level++
+	to := ladder[esc.Level]
+	reason := fmt.Sprintf("%d consecutive NEEDS_MORE_WORK verdicts", streak)
+	if len(esc.StreakTasks) > 0 {
+		reason += fmt.Sprintf(" on %v", esc.StreakTasks)
+	}
+	esc.Events = append(esc.Events, state.EscalationEvent{
+		Iteration: o.session.Iteration,
+		From:      from,
+		To:        to,
+		Reason:    reason,
+	})
+	esc.Streak = 0
+	esc.StreakTasks = nil
+
+	o.Config.ImplModel = to
+	o.session.ImplModel = to
+	o.applyImplModel()
+
+	logging.Warn(fmt.Sprintf("Escalating implementation model: %s -> %s (%s)", from, to, reason))
+	banner.PrintModelEscalationBanner(from, to, o.session.Iteration, reason)
+}
+
+// escalationInfos converts recorded escalations into banner display rows.
+func escalationInfos(events []state.EscalationEvent) []banner.EscalationInfo {
+	infos := make([]banner.EscalationInfo, 0, len(events))
+	for _, e := range events {
+		infos = append(infos, banner.EscalationInfo{Iteration: e.Iteration, From: e.From, To: e.To})
+	}
+	return infos
+}
@@ -0,0 +1,211 @@
+package phases
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/gitdiff"
+	"github.com/CodexForgeBR/cli-tools/internal/iterlog"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// Limits on what the escalation report quotes.
+const (
+	reportDiffMaxBytes        = 40000
+	reportValidationTailLines = 80
+)
+
+// escalationReportInput holds everything rendered into the escalation
+// report.
+type escalationReportInput struct {
+	Session *state.SessionState
+	Reason  string
+	// Feedback is the validator's last feedback, when not already the
+	// reason.
+	Feedback string
+	// DiffLabel says what Diff covers; Diff is empty when there is none.
+	DiffLabel   string
+	Diff        string
+	DiffStat    string
+	DiffOmitted int
+	// ValidationOutput is the end of the last validator output, which holds
+	// the test runs and failures it reported.
+	ValidationOutput string
+	// StrongerModel is false when the implementation model ladder has no
+	// rung left to escalate to.
+	StrongerModel bool
+	Now           time.Time
+}
+
+// writeEscalationReport writes the escalation handoff report to the state
+// directory and remembers its path for the escalation notification. It is
+// best effort: failures are logged and the loop exits as it would without.
+func (o *Orchestrator) writeEscalationReport(reason, feedback, valOutputPath string) {
+	in := escalationReportInput{
+		Session:       o.session,
+		Reason:        reason,
+		Feedback:      feedback,
+		StrongerModel: o.session.ModelEscalation.Level < len(o.Config.ModelLadder())-1,
+		Now:           time.Now(),
+	}
+	if in.Feedback == in.Reason {
+		in.Feedback = ""
+	}
+	if data, err := iterlog.ReadFile(valOutputPath); err == nil {
+		in.ValidationOutput = lastLines(string(data), reportValidationTailLines)
+	}
+	in.DiffLabel, in.Diff, in.DiffStat, in.DiffOmitted = o.escalationDiff()
+
+	path := filepath.Join(o.StateDir, fmt.Sprintf("escalation-%03d.md", o.session.Iteration))
+	if err := os.WriteFile(path, []byte(buildEscalationReport(in)), 0644); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to write escalation report: %v", err))
+		return
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	o.escalationReport = path
+	logging.Info(fmt.Sprintf("Escalation report written to %s", path))
+}
+
+// escalationDiff returns the changes of the last iteration when a snapshot
+// was taken before it, otherwise the uncommitted changes. It is empty when
+// WorkDir is unset.
+func (o *Orchestrator) escalationDiff() (label, patch, stat string, omitted int) {
+	if o.WorkDir == "" {
+		return "", "", "", 0
+	}
+	root := o.workDir()
+	head, err := gitdiff.Snapshot(root)
+	if err != nil {
+		return "", "", "", 0
+	}
+	label, from := "Changes in the last iteration", o.diffBase
+	if from == "" {
+		label, from = "Uncommitted changes", "HEAD"
+	}
+	var exclude []string
+	if rel, ok := insideDir(root, o.StateDir); ok {
+		exclude = append(exclude, rel)
+	}
+	patch, stat, err = gitdiff.Diff(root, from, head, exclude...)
+	if err != nil {
+		logging.Debug(fmt.Sprintf("No diff for the escalation report: %v", err))
+		return "", "", "", 0
+	}
+	patch, omitted = gitdiff.Truncate(patch, reportDiffMaxBytes)
+	return label, patch, stat, omitted
+}
+
+// buildEscalationReport renders the Markdown escalation report.
+func buildEscalationReport(in escalationReportInput) string {
+	var b strings.Builder
+	s := in.Session
+
+	b.WriteString("# Ralph Loop escalation\n\n")
+	fmt.Fprintf(&b, "Session `%s` escalated at iteration %d/%d on %s.\n\n",
+		s.SessionID, s.Iteration, s.MaxIterations, in.Now.Format("2006-01-02 15:04"))
+	fmt.Fprintf(&b, "- Tasks file: `%s`\n", s.TasksFile)
+	fmt.Fprintf(&b, "- Models: %s (implementation), %s (validation)\n", s.ImplModel, s.ValModel)
+	if s.Verdict != "" {
+		fmt.Fprintf(&b, "- Last verdict: %s\n", s.Verdict)
+	}
+
+	b.WriteString("\n## Reason\n\n")
+	b.WriteString(orNone(in.Reason) + "\n")
+	if in.Feedback != "" {
+		b.WriteString("\n## Validator feedback\n\n" + in.Feedback + "\n")
+	}
+
+	if open := openTasks(s); len(open) > 0 {
+		b.WriteString("\n## Open tasks\n\n")
+		for _, t := range open {
+			line := fmt.Sprintf("- %s", t.Text)
+			switch {
+			case t.Status == state.TaskBlocked && t.BlockedReason != "":
+				line += fmt.Sprintf(" _(blocked: %s)_", t.BlockedReason)
+			case t.Status == state.TaskBlocked:
+				line += " _(blocked)_"
+			case len(t.Verdicts) > 0:
+				line += fmt.Sprintf(" _(%d iterations: %s)_", len(t.Verdicts), strings.Join(t.Verdicts, ", "))
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	if in.Diff != "" {
+		fmt.Fprintf(&b, "\n## %s\n\n```\n%s\n```\n\n```diff\n%s\n```\n", in.DiffLabel, in.DiffStat, strings.TrimRight(in.Diff, "\n"))
+		if in.DiffOmitted > 0 {
+			fmt.Fprintf(&b, "\n_%d bytes of diff omitted._\n", in.DiffOmitted)
+		}
+	}
+
+	if in.ValidationOutput != "" {
+		fmt.Fprintf(&b, "\n## Validator output (last %d lines)\n\n```\n%s\n```\n", reportValidationTailLines, strings.TrimRight(in.ValidationOutput, "\n"))
+	}
+
+	b.WriteString("\n## Suggested next steps\n\n")
+	for i, step := range escalationNextSteps(in) {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, step)
+	}
+	return b.String()
+}
+
+// escalationNextSteps suggests what a human could do, from the task
+// history of the session.
+func escalationNextSteps(in escalationReportInput) []string {
+	s := in.Session
+	var blocked, churning []string
+	for _, t := range openTasks(s) {
+		switch {
+		case t.Status == state.TaskBlocked:
+			blocked = append(blocked, t.ID)
+		case t.Churning():
+			churning = append(churning, t.ID)
+		}
+	}
+
+	steps := []string{"Read the reason and feedback above and decide whether the spec or the implementation is wrong."}
+	if len(blocked) > 0 {
+		steps = append(steps, fmt.Sprintf("Resolve the blockers of %s, or remove those tasks from `%s`.", strings.Join(blocked, ", "), s.TasksFile))
+	}
+	if len(churning) > 0 {
+		steps = append(steps, fmt.Sprintf("Split or clarify %s: they stayed incomplete for %d or more iterations.", strings.Join(churning, ", "), state.ChurnThreshold))
+	}
+	if !in.StrongerModel {
+		steps = append(steps, fmt.Sprintf("Consider a stronger implementation model than %s (--implementation-model or IMPL_MODEL_LADDER).", s.ImplModel))
+	}
+	steps = append(steps, "Record your decision with `ralph-loop respond --message \"...\"` (or update the tasks or code), then continue with `ralph-loop --resume`.")
+	return steps
+}
+
+// openTasks returns the tracked tasks that are not done.
+func openTasks(s *state.SessionState) []state.TaskState {
+	var open []state.TaskState
+	for _, t := range s.Tasks {
+		if t.Status != state.TaskDone {
+			open = append(open, t)
+		}
+	}
+	return open
+}
+
+// lastLines returns the last n lines of text.
+func lastLines(text string, n int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func orNone(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "_None given._"
+	}
+	return s
+}
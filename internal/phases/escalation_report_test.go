@@ -0,0 +1,121 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reportSession() *state.SessionState {
+	return &state.SessionState{
+		SessionID:     "ralph-1",
+		Iteration:     4,
+		MaxIterations: 20,
+		TasksFile:     "specs/001/tasks.md",
+		ImplModel:     "opus",
+		ValModel:      "opus",
+		Verdict:       "ESCALATE",
+		Tasks: []state.TaskState{
+			{ID: "T001", Text: "T001 Add login", Status: state.TaskDone},
+			{ID: "T002", Text: "T002 Add logout", Status: state.TaskPending, Verdicts: []string{"NEEDS_MORE_WORK", "NEEDS_MORE_WORK", "ESCALATE"}},
+			{ID: "T003", Text: "T003 Add SSO", Status: state.TaskBlocked, BlockedReason: "needs IdP credentials"},
+		},
+	}
+}
+
+func TestBuildEscalationReport(t *testing.T) {
+	report := buildEscalationReport(escalationReportInput{
+		Session:          reportSession(),
+		Reason:           "The validator returned ESCALATE.",
+		Feedback:         "The spec contradicts itself about session expiry.",
+		DiffLabel:        "Changes in the last iteration",
+		Diff:             "--- a/auth.go\n+++ b/auth.go\n@@ -1 +1 @@\n-a\n+b\n",
+		DiffStat:         " auth.go | 2 +-",
+		DiffOmitted:      120,
+		ValidationOutput: "--- FAIL: TestLogout (0.00s)",
+		StrongerModel:    true,
+		Now:              time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC),
+	})
+
+	assert.Contains(t, report, "Session `ralph-1` escalated at iteration 4/20 on 2026-05-01 09:30.")
+	assert.Contains(t, report, "## Reason\n\nThe validator returned ESCALATE.\n")
+	assert.Contains(t, report, "## Validator feedback\n\nThe spec contradicts itself about session expiry.\n")
+	assert.Contains(t, report, "## Open tasks\n\n- T002 Add logout _(3 iterations: NEEDS_MORE_WORK, NEEDS_MORE_WORK, ESCALATE)_\n- T003 Add SSO _(blocked: needs IdP credentials)_\n")
+	assert.NotContains(t, report, "T001 Add login")
+	assert.Contains(t, report, "## Changes in the last iteration\n\n```\n auth.go | 2 +-\n```\n\n```diff\n--- a/auth.go")
+	assert.Contains(t, report, "_120 bytes of diff omitted._")
+	assert.Contains(t, report, "## Validator output (last 80 lines)\n\n```\n--- FAIL: TestLogout (0.00s)\n```\n")
+	assert.Contains(t, report, "2. Resolve the blockers of T003, or remove those tasks from `specs/001/tasks.md`.\n")
+	assert.Contains(t, report, "3. Split or clarify T002: they stayed incomplete for 3 or more iterations.\n")
+	assert.NotContains(t, report, "stronger implementation model")
+}
+
+func TestBuildEscalationReport_Minimal(t *testing.T) {
+	report := buildEscalationReport(escalationReportInput{Session: &state.SessionState{ImplModel: "sonnet"}})
+
+	assert.Contains(t, report, "## Reason\n\n_None given._\n")
+	for _, section := range []string{"## Validator feedback", "## Open tasks", "```diff", "## Validator output"} {
+		assert.NotContains(t, report, section)
+	}
+	assert.Contains(t, report, "2. Consider a stronger implementation model than sonnet")
+	assert.True(t, strings.HasSuffix(report, "3. Record your decision with `ralph-loop respond --message \"...\"` (or update the tasks or code), then continue with `ralph-loop --resume`.\n"))
+}
+
+func TestLastLines(t *testing.T) {
+	assert.Equal(t, "c\nd", lastLines("a\nb\nc\nd\n", 2))
+	assert.Equal(t, "a", lastLines("a", 5))
+}
+
+// TestOrchestrator_EscalationWritesReport verifies that an ESCALATE verdict
+// leaves a report with the feedback and the iteration's diff.
+func TestOrchestrator_EscalationWritesReport(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("# auth\n"), 0644))
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.email=t@example.com", "-c", "user.name=t", "commit", "-q", "--allow-empty", "-m", "init"},
+		{"add", "README.md"},
+	} {
+		require.NoError(t, exec.Command("git", append([]string{"-C", tmpDir}, args...)...).Run())
+	}
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 3
+
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			_ = os.WriteFile(filepath.Join(tmpDir, "auth.go"), []byte("package auth\n"), 0644)
+			return os.WriteFile(outputPath, []byte("Done."), 0644)
+		},
+	}
+	valRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("ESCALATE", "Session expiry is specified twice")), 0644)
+		},
+	}
+	o := newTestOrchestrator(t, cfg, tmpDir, implRunner, valRunner)
+	o.StateDir = filepath.Join(tmpDir, ".ralph-loop")
+	o.WorkDir = tmpDir
+
+	require.Equal(t, exitcode.Escalate, o.Run(context.Background()))
+
+	path := filepath.Join(o.StateDir, "escalation-001.md")
+	assert.Equal(t, path, o.escalationReport)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	report := string(data)
+	assert.Contains(t, report, "## Reason\n\nThe validator returned ESCALATE.\n")
+	assert.Contains(t, report, "## Validator feedback\n\nSession expiry is specified twice\n")
+	assert.Contains(t, report, "## Changes in the last iteration")
+	assert.Contains(t, report, "+package auth")
+	assert.NotContains(t, report, "iteration-001/", "the diff leaves out the state dir")
+}
@@ -0,0 +1,109 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// modelRecordingRunner records the model in effect for every run.
+type modelRecordingRunner struct {
+	model  string
+	models []string
+}
+
+func (r *modelRecordingRunner) SetModel(model string) { r.model = model }
+
+func (r *modelRecordingRunner) Run(ctx context.Context, prompt string, outputPath string) error {
+	r.models = append(r.models, r.model)
+	return os.WriteFile(outputPath, []byte(`{"RALPH_STATUS": {"completed_tasks": [], "blocked_tasks": [], "notes": "working"}}`), 0644)
+}
+
+// TestOrchestrator_EscalatesModelLadder verifies that the implementation
+// model climbs the ladder after EscalateAfter consecutive NEEDS_MORE_WORK
+// verdicts on the same tasks, and that escalations are recorded in state.
+func TestOrchestrator_EscalatesModelLadder(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] T001 Hard task\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 5
+	cfg.MaxTaskAttempts = 0
+	cfg.CrossValidate = false
+	cfg.EnableLearnings = false
+	cfg.ImplModelLadder = "haiku,sonnet,opus"
+	cfg.EscalateAfter = 2
+
+	impl := &modelRecordingRunner{model: "opus"}
+	val := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			out := `{"RALPH_VALIDATION": {"verdict": "NEEDS_MORE_WORK", "feedback": "T001 incomplete", "incomplete_tasks": ["T001"]}}`
+			return os.WriteFile(outputPath, []byte(out), 0644)
+		},
+	}
+
+	orch := NewOrchestrator(cfg)
+	orch.CommandChecker = alwaysAvailable
+	orch.StateDir = tmpDir
+	orch.ImplRunner = impl
+	orch.ValRunner = val
+
+	exitCode := orch.Run(context.Background())
+	assert.Equal(t, exitcode.MaxIterations, exitCode)
+	assert.Equal(t, []string{"haiku", "haiku", "sonnet", "sonnet", "opus"}, impl.models)
+
+	saved, err := state.LoadState(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, "opus", saved.ImplModel)
+	assert.Equal(t, 2, saved.ModelEscalation.Level)
+	require.Len(t, saved.ModelEscalation.Events, 2)
+	assert.Equal(t, state.EscalationEvent{
+		Iteration: 2,
+		From:      "haiku",
+		To:        "sonnet",
+		Reason:    "2 consecutive NEEDS_MORE_WORK verdicts on [T001]",
+	}, saved.ModelEscalation.Events[0])
+	assert.Equal(t, 4, saved.ModelEscalation.Events[1].Iteration)
+}
+
+// TestOrchestrator_NoLadderKeepsModel verifies the model is untouched when
+// no ladder is configured.
+func TestOrchestrator_NoLadderKeepsModel(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] T001 Hard task\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 3
+	cfg.CrossValidate = false
+	cfg.EnableLearnings = false
+	cfg.ImplModel = "opus"
+
+	impl := &modelRecordingRunner{model: "opus"}
+	val := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte(`{"RALPH_VALIDATION": {"verdict": "NEEDS_MORE_WORK", "feedback": "no"}}`), 0644)
+		},
+	}
+
+	orch := NewOrchestrator(cfg)
+	orch.CommandChecker = alwaysAvailable
+	orch.StateDir = tmpDir
+	orch.ImplRunner = impl
+	orch.ValRunner = val
+
+	orch.Run(context.Background())
+	assert.Equal(t, []string{"opus", "opus", "opus"}, impl.models)
+	assert.Empty(t, orch.session.ModelEscalation.Events)
+}
@@ -0,0 +1,121 @@
+package phases
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/banner"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+	"github.com/CodexForgeBR/cli-tools/internal/testresult"
+)
+
+// recordIteration adds the judged iteration to the session's history:
+// how long it and its phases took, its verdict, the tasks it checked off
+// and the files it changed, for `ralph-loop blame`.
+func (o *Orchestrator) recordIteration(elapsed, impl, val time.Duration) {
+	done, err := tasks.CountChecked(o.session.TasksFile)
+	if err != nil {
+		return
+	}
+	remaining, _ := tasks.CountUnchecked(o.session.TasksFile)
+	history := o.session.History
+	completed := 0
+	switch {
+	case o.checkedBefore >= 0:
+		completed = done - o.checkedBefore
+	case len(history) > 0:
+		completed = done - history[len(history)-1].Done
+	}
+	o.session.History = append(history, state.IterationRecord{
+		Iteration:   o.session.Iteration,
+		Verdict:     o.session.Verdict,
+		Seconds:     int(elapsed.Seconds()),
+		ImplSeconds: int(impl.Seconds()),
+		ValSeconds:  int(val.Seconds()),
+		Completed:   max(completed, 0),
+		Done:        done,
+		Total:       done + remaining,
+		At:          time.Now().Format(time.RFC3339),
+		Tasks:       o.completedTaskIDs(),
+		Files:       o.changedFiles(),
+		Tests:       o.verifyTests(),
+	})
+}
+
+// verifyTests returns the test results the VERIFY_CMDS reported for the
+// current iteration, or nil.
+func (o *Orchestrator) verifyTests() *testresult.Summary {
+	if o.verify == nil {
+		return nil
+	}
+	return o.verify.tests
+}
+
+// completedTaskIDs returns the IDs of the tasks checked during the current
+// iteration in file order, or nil when the tasks file from before the
+// iteration is unknown, as after a resume.
+func (o *Orchestrator) completedTaskIDs() []string {
+	if o.checkedAtStart == nil {
+		return nil
+	}
+	list, err := tasks.ListTasks(o.session.TasksFile)
+	if err != nil {
+		return nil
+	}
+	var ids []string
+	for _, t := range list {
+		if t.Checked && !o.checkedAtStart[t.ID] {
+			ids = append(ids, t.ID)
+		}
+	}
+	return ids
+}
+
+// changedFiles returns the files changed since the snapshot taken before
+// implementation, or nil when there is none.
+func (o *Orchestrator) changedFiles() []string {
+	if o.diffBase == "" {
+		return nil
+	}
+	files, err := o.iterationChanges()
+	if err != nil {
+		logging.Debug(fmt.Sprintf("No changed files for the history: %v", err))
+		return nil
+	}
+	return files
+}
+
+// sessionETA estimates the progress of session s from its history and
+// its tasks file; false when the tasks file cannot be read.
+func sessionETA(s *state.SessionState) (state.ETA, bool) {
+	done, err := tasks.CountChecked(s.TasksFile)
+	if err != nil {
+		return state.ETA{}, false
+	}
+	remaining, err := tasks.CountUnchecked(s.TasksFile)
+	if err != nil {
+		return state.ETA{}, false
+	}
+	return state.EstimateETA(s.History, done, done+remaining), true
+}
+
+// withETA appends the session's estimated progress to a notification's
+// detail.
+func withETA(detail string, s *state.SessionState) string {
+	if eta, ok := sessionETA(s); ok {
+		return detail + ", " + eta.String()
+	}
+	return detail
+}
+
+// progressInfo converts the estimated progress of session s into the
+// status display's; nil when the tasks file cannot be read.
+func progressInfo(s *state.SessionState) *banner.ProgressInfo {
+	eta, ok := sessionETA(s)
+	if !ok {
+		return nil
+	}
+	return &banner.ProgressInfo{Summary: eta.String(), Iteration: eta.Iteration, Impl: eta.Impl, Val: eta.Val}
+}
@@ -0,0 +1,60 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+func TestRecordIteration(t *testing.T) {
+	tasksFile := filepath.Join(t.TempDir(), "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [x] T001 One\n- [x] T002 Two\n- [ ] T003 Three\n"), 0644))
+	o := NewOrchestrator(config.NewDefaultConfig())
+	o.session = &state.SessionState{Iteration: 4, TasksFile: tasksFile, Verdict: "NEEDS_MORE_WORK"}
+
+	o.checkedBefore = 1
+	o.recordIteration(90*time.Second, time.Minute, 20*time.Second)
+	o.session.Iteration++
+	o.checkedBefore = -1 // unknown, as on resume
+	o.recordIteration(30*time.Second, 0, 30*time.Second)
+
+	require.Len(t, o.session.History, 2)
+	for i := range o.session.History {
+		assert.NotEmpty(t, o.session.History[i].At)
+		o.session.History[i].At = ""
+	}
+	assert.Equal(t, []state.IterationRecord{
+		{Iteration: 4, Verdict: "NEEDS_MORE_WORK", Seconds: 90, ImplSeconds: 60, ValSeconds: 20, Completed: 1, Done: 2, Total: 3},
+		{Iteration: 5, Verdict: "NEEDS_MORE_WORK", Seconds: 30, ImplSeconds: 0, ValSeconds: 30, Completed: 0, Done: 2, Total: 3},
+	}, o.session.History)
+
+	info := progressInfo(o.session)
+	require.NotNil(t, info)
+	assert.Equal(t, "66% (2/3 tasks), ETA 2m0s (~2 iteration(s))", info.Summary)
+	assert.Equal(t, time.Minute, info.Iteration)
+
+	assert.Nil(t, progressInfo(&state.SessionState{TasksFile: filepath.Join(t.TempDir(), "missing.md")}))
+}
+
+func TestOrchestrator_RecordsIterationHistory(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 2
+	o := newTestOrchestrator(t, cfg, t.TempDir(), nil, needsMoreWorkValidator())
+	assert.Equal(t, exitcode.MaxIterations, o.Run(context.Background()))
+
+	saved, err := state.LoadState(o.StateDir)
+	require.NoError(t, err)
+	require.Len(t, saved.History, 2)
+	assert.Equal(t, 1, saved.History[0].Iteration)
+	assert.Equal(t, 2, saved.History[1].Iteration)
+	assert.Zero(t, saved.History[1].Completed)
+}
@@ -0,0 +1,98 @@
+package phases
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// taskLeadRE matches the task ID and the [P]/[US1] style tags that precede
+// a task's description.
+var taskLeadRE = regexp.MustCompile(`^(?:T\d+\s*:?\s*)?(?:\[[^\]]*\]\s*)*`)
+
+// needsEvidence reports whether a task's description starts with one of
+// the evidence patterns, ignoring case.
+func needsEvidence(text string, patterns []string) bool {
+	desc := strings.ToLower(taskLeadRE.ReplaceAllString(strings.TrimSpace(text), ""))
+	for _, p := range patterns {
+		if strings.HasPrefix(desc, strings.ToLower(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// enforceEvidence unchecks the tasks checked in this iteration whose
+// description matches EVIDENCE_PATTERNS but that have no RALPH_EVIDENCE
+// entry with a command and its output in the implementation output, and
+// downgrades the verdict for them. The validator prompt asks for the same
+// evidence; this makes the requirement hold whatever the validator says.
+// It does nothing when the tasks file from before the iteration is unknown,
+// as after a resume.
+func (o *Orchestrator) enforceEvidence(result ValidationPhaseResult, implOutputPath string) ValidationPhaseResult {
+	patterns := o.Config.EvidencePatternList()
+	if o.checkedAtStart == nil || len(patterns) == 0 {
+		return result
+	}
+	list, err := tasks.ListTasks(o.session.TasksFile)
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Failed to list tasks: %v", err))
+		return result
+	}
+
+	var evidence []parser.Evidence
+	if data, err := os.ReadFile(implOutputPath); err == nil {
+		if evidence, err = parser.ParseEvidence(string(data)); err != nil {
+			logging.Warn(fmt.Sprintf("Ignoring malformed RALPH_EVIDENCE: %v", err))
+		}
+	}
+	proven := map[string]bool{}
+	for _, e := range evidence {
+		if strings.TrimSpace(e.Command) != "" && strings.TrimSpace(e.Output) != "" {
+			proven[e.Task] = true
+		}
+	}
+
+	var missing []string
+	for _, t := range list {
+		if !t.Checked || o.checkedAtStart[t.ID] || proven[t.ID] || !needsEvidence(t.Text, patterns) {
+			continue
+		}
+		if err := tasks.Uncheck(o.session.TasksFile, t.Line); err != nil {
+			logging.Warn(fmt.Sprintf("Failed to uncheck task %s: %v", t.ID, err))
+			continue
+		}
+		missing = append(missing, t.ID)
+	}
+	if len(missing) == 0 {
+		return result
+	}
+	o.snapshotTasks(false)
+
+	result.CompletedTasks = slices.DeleteFunc(result.CompletedTasks, func(id string) bool {
+		return slices.Contains(missing, id)
+	})
+	for _, id := range missing {
+		if !slices.Contains(result.IncompleteTasks, id) {
+			result.IncompleteTasks = append(result.IncompleteTasks, id)
+		}
+	}
+
+	logging.Warn(fmt.Sprintf("Unchecked %s: no RALPH_EVIDENCE entry", strings.Join(missing, ", ")))
+	note := fmt.Sprintf("No evidence for %s; ralph-loop unchecked them. Run the command the task calls for and "+
+		"report it in a RALPH_EVIDENCE entry with the task ID, the command and an excerpt of its output.", strings.Join(missing, ", "))
+	if result.Feedback != "" {
+		note += "\n\n" + result.Feedback
+	}
+	result.Feedback = note
+	if result.Verdict == "COMPLETE" {
+		result.Verdict = "NEEDS_MORE_WORK"
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+package phases
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+func TestNeedsEvidence(t *testing.T) {
+	patterns := []string{"Deploy", "Run tests", "Verify"}
+	assert.True(t, needsEvidence("T005 Deploy the API to staging", patterns))
+	assert.True(t, needsEvidence("T006 [P] [US1] run tests for the billing module", patterns))
+	assert.True(t, needsEvidence("T007: Verify packages exist on BaGet", patterns))
+	assert.False(t, needsEvidence("T008 Write deploy script", patterns))
+	assert.False(t, needsEvidence("T009 Deploy", nil))
+}
+
+// TestEnforceEvidence verifies that a newly checked task needing evidence
+// is unchecked and the verdict downgraded when the implementation output
+// has no RALPH_EVIDENCE entry for it, while proven and ordinary tasks stay
+// checked.
+func TestEnforceEvidence(t *testing.T) {
+	dir := t.TempDir()
+	tasksFile := filepath.Join(dir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [x] T001 Add handler\n- [x] T002 Run tests\n- [x] T003 Deploy to staging\n- [x] T004 Verify before\n"), 0644))
+	implOutput := filepath.Join(dir, "implementation-output.txt")
+	require.NoError(t, os.WriteFile(implOutput, []byte(`{"RALPH_EVIDENCE": [
+		{"task": "T002", "command": "go test ./...", "output": "ok"},
+		{"task": "T003", "command": "make deploy", "output": ""}
+	]}`), 0644))
+
+	o := NewOrchestrator(config.NewDefaultConfig())
+	o.StateDir = filepath.Join(dir, ".ralph-loop")
+	o.session = &state.SessionState{TasksFile: tasksFile}
+	o.checkedAtStart = map[string]bool{"T004": true}
+
+	result := o.enforceEvidence(ValidationPhaseResult{Verdict: "COMPLETE", Feedback: "looks good", CompletedTasks: []string{"T001", "T002", "T003"}}, implOutput)
+	assert.Equal(t, "NEEDS_MORE_WORK", result.Verdict)
+	assert.Equal(t, []string{"T001", "T002"}, result.CompletedTasks)
+	assert.Equal(t, []string{"T003"}, result.IncompleteTasks)
+	assert.Contains(t, result.Feedback, "No evidence for T003; ralph-loop unchecked them.")
+	assert.Contains(t, result.Feedback, "looks good")
+	assert.NotEmpty(t, o.session.TasksFileHash)
+
+	data, err := os.ReadFile(tasksFile)
+	require.NoError(t, err)
+	assert.Equal(t, "- [x] T001 Add handler\n- [x] T002 Run tests\n- [ ] T003 Deploy to staging\n- [x] T004 Verify before\n", string(data))
+
+	// Nothing is enforced when the tasks file before the iteration is unknown.
+	o.checkedAtStart = nil
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [x] T003 Deploy to staging\n"), 0644))
+	result = o.enforceEvidence(ValidationPhaseResult{Verdict: "COMPLETE"}, implOutput)
+	assert.Equal(t, "COMPLETE", result.Verdict)
+}
@@ -0,0 +1,45 @@
+package phases
+
+import (
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// maxExitReason caps, in runes, an exit reason taken from AI feedback.
+const maxExitReason = 200
+
+// stopWith records res as how the loop stopped, both for Run and in the
+// session state, where the status endpoints and --status pick it up.
+func (o *Orchestrator) stopWith(res exitcode.Result) {
+	o.exit = &res
+	if o.session != nil {
+		o.session.Exit = &state.ExitState{Code: res.Code, Name: res.Name(), Reason: res.Reason}
+	}
+}
+
+// exitResult returns the result of a run that exited with code, carrying
+// the reason recorded when the loop stopped, if it stopped with that code.
+func (o *Orchestrator) exitResult(code int) exitcode.Result {
+	if o.exit != nil && o.exit.Code == code {
+		return *o.exit
+	}
+	return exitcode.Result{Code: code}
+}
+
+// feedbackReason shortens AI feedback to an exit reason: its first
+// non-empty line, capped at maxExitReason runes.
+func feedbackReason(feedback string) string {
+	for _, line := range strings.Split(feedback, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if r := []rune(line); len(r) > maxExitReason {
+			line = string(r[:maxExitReason-1]) + "…"
+		}
+		return line
+	}
+	return ""
+}
@@ -0,0 +1,34 @@
+package phases
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+func TestFeedbackReason(t *testing.T) {
+	assert.Equal(t, "", feedbackReason(" \n\n"))
+	assert.Equal(t, "Tasks skip the migration.", feedbackReason("\n  Tasks skip the migration.\nT004 has no test.\n"))
+
+	reason := feedbackReason(strings.Repeat("é", maxExitReason+10))
+	assert.Len(t, []rune(reason), maxExitReason)
+	assert.True(t, strings.HasSuffix(reason, "…"))
+}
+
+func TestStopWithAndExitResult(t *testing.T) {
+	o := &Orchestrator{session: &state.SessionState{}}
+	assert.Equal(t, exitcode.Result{Code: exitcode.Error}, o.exitResult(exitcode.Error))
+
+	o.stopWith(exitcode.Result{Code: exitcode.Blocked, Reason: "1 task(s) blocked: T002"})
+	require.NotNil(t, o.session.Exit)
+	assert.Equal(t, state.ExitState{Code: exitcode.Blocked, Name: "Blocked", Reason: "1 task(s) blocked: T002"}, *o.session.Exit)
+	assert.Equal(t, "1 task(s) blocked: T002", o.exitResult(exitcode.Blocked).Reason)
+
+	// A later failure exits with another code and carries no stale reason.
+	assert.Equal(t, exitcode.Result{Code: exitcode.Error}, o.exitResult(exitcode.Error))
+}
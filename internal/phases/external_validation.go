@@ -0,0 +1,130 @@
+package phases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/CodexForgeBR/cli-tools/internal/extval"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+)
+
+// externalOutcome is the outcome of one external validator's run.
+type externalOutcome struct {
+	name   string
+	result extval.Result
+	err    error
+}
+
+// runExternalValidators runs the external validators concurrently on the
+// iteration, saving what each wrote in the iteration directory, and
+// merges their verdicts into result (see mergeExternalVerdicts).
+func (o *Orchestrator) runExternalValidators(ctx context.Context, result ValidationPhaseResult, implOutputPath, iterDir string) ValidationPhaseResult {
+	if len(o.externalValidators) == 0 {
+		return result
+	}
+	req := o.externalRequest(implOutputPath)
+	ctx, cancel := withTimeout(ctx, o.Config.ValTimeout)
+	defer cancel()
+
+	outcomes := make([]externalOutcome, len(o.externalValidators))
+	var wg sync.WaitGroup
+	for i, v := range o.externalValidators {
+		wg.Add(1)
+		go func(i int, v extval.Validator) {
+			defer wg.Done()
+			res, err := extval.Run(ctx, v, o.workDir(), req)
+			outcomes[i] = externalOutcome{name: v.Name, result: res, err: err}
+		}(i, v)
+	}
+	wg.Wait()
+
+	for _, out := range outcomes {
+		base := filepath.Join(iterDir, "external-"+out.name)
+		if err := os.WriteFile(base+"-output.txt", []byte(out.result.Stdout), 0644); err != nil {
+			logging.Warn(fmt.Sprintf("Failed to save external validator output: %v", err))
+		}
+		if out.result.Stderr != "" {
+			_ = os.WriteFile(base+"-stderr.txt", []byte(out.result.Stderr), 0644)
+		}
+		if out.err != nil {
+			logging.Warn(fmt.Sprintf("External validator %s failed, its verdict is ignored: %v", out.name, out.err))
+			o.recordGate("external:"+out.name, "ERROR")
+			continue
+		}
+		logging.Info(fmt.Sprintf("External validator %s: %s", out.name, out.result.Response.Verdict))
+		o.recordGate("external:"+out.name, out.result.Response.Verdict)
+	}
+	return mergeExternalVerdicts(result, outcomes)
+}
+
+// externalRequest returns the iteration context sent to external
+// validators.
+func (o *Orchestrator) externalRequest(implOutputPath string) extval.Request {
+	req := extval.Request{
+		ImplementationOutput: absolutePath(implOutputPath),
+		WorkDir:              absolutePath(o.workDir()),
+		DiffBase:             o.diffBase,
+	}
+	if o.session != nil {
+		req.SessionID = o.session.SessionID
+		req.Iteration = o.session.Iteration
+		req.TasksFile = absolutePath(o.session.TasksFile)
+	}
+	if o.diffBase != "" {
+		changed, err := o.iterationChanges()
+		if err != nil {
+			logging.Warn(fmt.Sprintf("Failed to list the files the iteration changed: %v", err))
+		}
+		req.ChangedFiles = changed
+	}
+	return req
+}
+
+// mergeExternalVerdicts applies the verdicts of the external validators
+// that answered to the validator's result: the most severe verdict wins,
+// and what each reported is added to the feedback. A custom verdict of
+// the validator ranks with INADMISSIBLE, so only ESCALATE replaces it.
+func mergeExternalVerdicts(result ValidationPhaseResult, outcomes []externalOutcome) ValidationPhaseResult {
+	severity := extval.Severity(result.Verdict)
+	if severity < 0 {
+		severity = extval.Severity("INADMISSIBLE")
+	}
+	verdict, source := result.Verdict, ""
+	var notes []string
+	for _, out := range outcomes {
+		if out.err != nil {
+			continue
+		}
+		resp := out.result.Response
+		if s := extval.Severity(resp.Verdict); s > severity {
+			severity, verdict, source = s, resp.Verdict, out.name
+		}
+		if resp.Verdict == "COMPLETE" && resp.Feedback == "" && len(resp.Findings) == 0 {
+			continue
+		}
+		note := fmt.Sprintf("External validator %s (%s):", out.name, resp.Verdict)
+		if resp.Feedback != "" {
+			note += "\n" + resp.Feedback
+		}
+		for _, f := range resp.Findings {
+			note += "\n- " + f.String()
+		}
+		notes = append(notes, note)
+	}
+
+	if len(notes) > 0 {
+		if result.Feedback != "" {
+			notes = append([]string{result.Feedback}, notes...)
+		}
+		result.Feedback = strings.Join(notes, "\n\n")
+	}
+	if verdict != result.Verdict {
+		logging.Warn(fmt.Sprintf("Verdict %s overridden by external validator %s: %s", result.Verdict, source, verdict))
+		result.Verdict = verdict
+	}
+	return result
+}
@@ -0,0 +1,162 @@
+//go:build !windows
+
+package phases
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/extval"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func answered(name string, resp extval.Response) externalOutcome {
+	return externalOutcome{name: name, result: extval.Result{Response: resp}}
+}
+
+func TestMergeExternalVerdicts_MostSevereWins(t *testing.T) {
+	result := ValidationPhaseResult{Verdict: "COMPLETE", Feedback: "All tasks done."}
+
+	merged := mergeExternalVerdicts(result, []externalOutcome{
+		answered("lint", extval.Response{Verdict: "COMPLETE"}),
+		answered("sast", extval.Response{
+			Verdict:  "BLOCKED",
+			Feedback: "Secrets committed.",
+			Findings: []extval.Finding{{File: "config.go", Line: 3, Severity: "high", Message: "hard-coded token"}},
+		}),
+		answered("tests", extval.Response{Verdict: "NEEDS_MORE_WORK", Feedback: "Coverage dropped."}),
+	})
+
+	assert.Equal(t, "BLOCKED", merged.Verdict)
+	assert.Equal(t, "All tasks done.\n\n"+
+		"External validator sast (BLOCKED):\nSecrets committed.\n- config.go:3: [high] hard-coded token\n\n"+
+		"External validator tests (NEEDS_MORE_WORK):\nCoverage dropped.", merged.Feedback)
+}
+
+func TestMergeExternalVerdicts_LessSevereKeepsVerdict(t *testing.T) {
+	result := ValidationPhaseResult{Verdict: "NEEDS_MORE_WORK", Feedback: "Task 3 missing."}
+
+	merged := mergeExternalVerdicts(result, []externalOutcome{
+		answered("lint", extval.Response{Verdict: "COMPLETE"}),
+	})
+
+	assert.Equal(t, result, merged)
+}
+
+func TestMergeExternalVerdicts_FailedValidatorDoesNotVote(t *testing.T) {
+	result := ValidationPhaseResult{Verdict: "COMPLETE"}
+
+	merged := mergeExternalVerdicts(result, []externalOutcome{
+		{name: "sast", result: extval.Result{Response: extval.Response{Verdict: "ESCALATE"}}, err: assert.AnError},
+	})
+
+	assert.Equal(t, result, merged)
+}
+
+func TestMergeExternalVerdicts_CustomVerdictOnlyYieldsToEscalate(t *testing.T) {
+	result := ValidationPhaseResult{Verdict: "SECURITY_HOLD"}
+
+	merged := mergeExternalVerdicts(result, []externalOutcome{
+		answered("lint", extval.Response{Verdict: "INADMISSIBLE"}),
+	})
+	assert.Equal(t, "SECURITY_HOLD", merged.Verdict)
+
+	merged = mergeExternalVerdicts(result, []externalOutcome{
+		answered("lint", extval.Response{Verdict: "ESCALATE"}),
+	})
+	assert.Equal(t, "ESCALATE", merged.Verdict)
+}
+
+// externalValidators writes the given validator scripts in dir and
+// returns the EXTERNAL_VALIDATORS setting that runs them.
+func externalValidators(t *testing.T, dir string, scripts map[string]string) string {
+	t.Helper()
+	spec := ""
+	for name, script := range scripts {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name+".sh"), []byte(script), 0644))
+		spec += name + "=sh " + name + ".sh;"
+	}
+	return spec
+}
+
+func TestOrchestrator_ExternalValidatorOverridesComplete(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 2
+	impl := doneImplementer()
+	o := newTestOrchestrator(t, cfg, t.TempDir(), impl, nil)
+	o.WorkDir = t.TempDir()
+	cfg.ExternalValidators = externalValidators(t, o.WorkDir, map[string]string{
+		"sast": `cat > request.json
+if [ -f reviewed ]; then
+  echo '{"verdict": "COMPLETE"}'
+else
+  touch reviewed
+  echo 'scanning...'
+  echo '{"verdict": "NEEDS_MORE_WORK", "feedback": "Validate user input", "findings": [{"file": "db.go", "line": 12, "message": "SQL injection"}]}'
+fi
+`,
+	})
+
+	assert.Equal(t, exitcode.Success, o.Run(context.Background()))
+
+	assert.Equal(t, 2, impl.CallCount)
+	assert.Contains(t, impl.PromptLog[1], "Validate user input")
+	assert.Contains(t, impl.PromptLog[1], "db.go:12: SQL injection")
+	assert.FileExists(t, filepath.Join(o.StateDir, "iteration-001", "external-sast-output.txt"))
+
+	data, err := os.ReadFile(filepath.Join(o.WorkDir, "request.json"))
+	require.NoError(t, err)
+	var req extval.Request
+	require.NoError(t, json.Unmarshal(data, &req))
+	assert.Equal(t, extval.ProtocolVersion, req.Protocol)
+	assert.Equal(t, o.session.SessionID, req.SessionID)
+	assert.Equal(t, 2, req.Iteration)
+	assert.FileExists(t, req.ImplementationOutput)
+}
+
+func TestOrchestrator_ExternalValidatorFailureDoesNotBlock(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	impl := doneImplementer()
+	o := newTestOrchestrator(t, cfg, t.TempDir(), impl, nil)
+	o.WorkDir = t.TempDir()
+	cfg.ExternalValidators = externalValidators(t, o.WorkDir, map[string]string{
+		"broken": "echo 'not json'\nexit 2\n",
+	})
+
+	assert.Equal(t, exitcode.Success, o.Run(context.Background()))
+	assert.Equal(t, 1, impl.CallCount)
+	assert.FileExists(t, filepath.Join(o.StateDir, "iteration-001", "external-broken-output.txt"))
+}
+
+func TestOrchestrator_ExternalValidatorBlocks(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 3
+	impl := doneImplementer()
+	o := newTestOrchestrator(t, cfg, t.TempDir(), impl, nil)
+	o.WorkDir = t.TempDir()
+	cfg.ExternalValidators = externalValidators(t, o.WorkDir, map[string]string{
+		"lint": `echo '{"verdict": "BLOCKED", "feedback": "Build is broken"}'` + "\n",
+	})
+
+	assert.Equal(t, exitcode.Blocked, o.Run(context.Background()))
+	assert.Equal(t, 1, impl.CallCount)
+}
+
+func TestOrchestrator_InvalidExternalValidators(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	impl := doneImplementer()
+	o := newTestOrchestrator(t, cfg, t.TempDir(), impl, nil)
+	o.WorkDir = t.TempDir()
+	o.Config.ExternalValidators = "Bad Name=./check.sh"
+
+	assert.Equal(t, exitcode.Error, o.Run(context.Background()))
+	assert.Zero(t, impl.CallCount)
+}
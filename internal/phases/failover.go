@@ -0,0 +1,61 @@
+package phases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/banner"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// phaseHealthCheck probes the primary AI provider before the first AI
+// call, failing over to FALLBACK_AI when it is down. A resumed session
+// that had failed over stays on the fallback without a probe.
+func (o *Orchestrator) phaseHealthCheck(ctx context.Context) {
+	f := o.Failover
+	if f == nil {
+		return
+	}
+	if prev := o.session.Failover; prev != nil && prev.From == f.Primary && prev.To == f.Secondary {
+		f.Restore()
+		logging.Warn(fmt.Sprintf("Staying on %s: %s failed over at iteration %d (%s)", prev.To, prev.From, prev.Iteration, prev.Reason))
+		return
+	}
+	logging.Phase(fmt.Sprintf("Checking %s health", f.Primary))
+	if !f.Check(ctx) {
+		logging.Success(fmt.Sprintf("%s is healthy", f.Primary))
+	}
+}
+
+// RecordFailover shows the failover banner and records the switch in the
+// session state, persisted with the next save. It is the Failover's
+// OnFailover callback and is safe to call from any goroutine.
+func (o *Orchestrator) RecordFailover(from, to, reason string) {
+	o.retryMu.Lock()
+	defer o.retryMu.Unlock()
+	iteration := 0
+	if o.session != nil {
+		iteration = o.session.Iteration
+	}
+	banner.PrintFailoverBanner(from, to, iteration, reason)
+	if o.session == nil {
+		return
+	}
+	o.session.Failover = &state.FailoverState{
+		From:      from,
+		To:        to,
+		Reason:    reason,
+		Iteration: iteration,
+		At:        time.Now().Format(time.RFC3339),
+	}
+}
+
+// failoverInfo converts a recorded failover into the status display's.
+func failoverInfo(f *state.FailoverState) *banner.FailoverInfo {
+	if f == nil {
+		return nil
+	}
+	return &banner.FailoverInfo{Iteration: f.Iteration, From: f.From, To: f.To, Reason: f.Reason}
+}
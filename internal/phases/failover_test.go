@@ -0,0 +1,83 @@
+package phases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/ai"
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+func TestPhaseHealthCheck_FailsOver(t *testing.T) {
+	o := newTestOrchestrator(t, config.NewDefaultConfig(), t.TempDir(), nil, nil)
+	o.session = &state.SessionState{Iteration: 3}
+	probes := 0
+	o.Failover = &ai.Failover{Primary: "claude", Secondary: "codex", OnFailover: o.RecordFailover, Probe: func(ctx context.Context) error {
+		probes++
+		return errors.New("probe prompt: no answer")
+	}}
+	o.phaseHealthCheck(context.Background())
+
+	assert.Equal(t, 1, probes)
+	assert.True(t, o.Failover.Active())
+	require.NotNil(t, o.session.Failover)
+	assert.Equal(t, "claude", o.session.Failover.From)
+	assert.Equal(t, "codex", o.session.Failover.To)
+	assert.Equal(t, 3, o.session.Failover.Iteration)
+	assert.Equal(t, "health check failed: probe prompt: no answer", o.session.Failover.Reason)
+	assert.NotEmpty(t, o.session.Failover.At)
+	assert.Equal(t, "health check failed: probe prompt: no answer", failoverInfo(o.session.Failover).Reason)
+}
+
+func TestPhaseHealthCheck_HealthyPrimary(t *testing.T) {
+	o := newTestOrchestrator(t, config.NewDefaultConfig(), t.TempDir(), nil, nil)
+	o.session = &state.SessionState{}
+	probes := 0
+	o.Failover = &ai.Failover{Primary: "claude", Secondary: "codex", Probe: func(ctx context.Context) error {
+		probes++
+		return nil
+	}}
+	o.phaseHealthCheck(context.Background())
+
+	assert.Equal(t, 1, probes)
+	assert.False(t, o.Failover.Active())
+	assert.Nil(t, o.session.Failover)
+	assert.Nil(t, failoverInfo(nil))
+}
+
+func TestPhaseHealthCheck_ResumedFailover(t *testing.T) {
+	o := newTestOrchestrator(t, config.NewDefaultConfig(), t.TempDir(), nil, nil)
+	prev := &state.FailoverState{From: "claude", To: "codex", Reason: "health check failed: timeout", Iteration: 2}
+	o.session = &state.SessionState{Failover: prev}
+	probes := 0
+	o.Failover = &ai.Failover{Primary: "claude", Secondary: "codex", Probe: func(ctx context.Context) error {
+		probes++
+		return nil
+	}}
+	o.phaseHealthCheck(context.Background())
+
+	assert.Zero(t, probes, "a resumed session stays on the fallback")
+	assert.True(t, o.Failover.Active())
+	assert.Same(t, prev, o.session.Failover)
+}
+
+// TestPhaseHealthCheck_ResumedFailoverToOtherFallback verifies that a
+// failover to another fallback than the configured one is not carried over.
+func TestPhaseHealthCheck_ResumedFailoverToOtherFallback(t *testing.T) {
+	o := newTestOrchestrator(t, config.NewDefaultConfig(), t.TempDir(), nil, nil)
+	o.session = &state.SessionState{Failover: &state.FailoverState{From: "claude", To: "amazonq"}}
+	probes := 0
+	o.Failover = &ai.Failover{Primary: "claude", Secondary: "codex", Probe: func(ctx context.Context) error {
+		probes++
+		return nil
+	}}
+	o.phaseHealthCheck(context.Background())
+
+	assert.Equal(t, 1, probes)
+	assert.False(t, o.Failover.Active())
+}
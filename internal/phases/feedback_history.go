@@ -0,0 +1,37 @@
+package phases
+
+import (
+	"fmt"
+
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// recordFeedback adds the issues of this iteration's feedback to the
+// session's unresolved feedback and drops the issues about tasks that are
+// now done.
+func (o *Orchestrator) recordFeedback(feedback string) {
+	state.RecordFeedback(o.session, o.session.Iteration, feedback)
+	if list, err := tasks.ListTasks(o.session.TasksFile); err == nil {
+		state.ResolveFeedback(o.session, list)
+	}
+}
+
+// feedbackHistoryLines lists, for the implementation prompt, the
+// unresolved issues the last feedback does not already bring up for the
+// first time: recurring ones and ones earlier feedback raised.
+func (o *Orchestrator) feedbackHistoryLines() []string {
+	previous := o.session.Iteration - 1
+	var lines []string
+	for _, issue := range o.session.FeedbackIssues {
+		switch {
+		case issue.LastIteration == previous && issue.Count > 1:
+			lines = append(lines, fmt.Sprintf("still failing since iteration %d (raised %d times): %s",
+				issue.FirstIteration, issue.Count, issue.Text))
+		case issue.LastIteration < previous:
+			lines = append(lines, fmt.Sprintf("raised in iteration %d, not confirmed fixed since: %s",
+				issue.LastIteration, issue.Text))
+		}
+	}
+	return lines
+}
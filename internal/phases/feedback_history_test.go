@@ -0,0 +1,66 @@
+package phases
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+)
+
+// TestOrchestrator_FeedbackHistory verifies that issues from earlier
+// feedback reach later implementation prompts, marked as recurring or as
+// not confirmed fixed, and that issues about checked tasks are dropped.
+func TestOrchestrator_FeedbackHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] T001 Login\n- [x] T002 Logout\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 3
+	cfg.MaxTaskAttempts = 0
+	cfg.CrossValidate = false
+	cfg.EnableLearnings = false
+
+	var prompts []string
+	impl := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			prompts = append(prompts, prompt)
+			return os.WriteFile(outputPath, []byte("done"), 0644)
+		},
+	}
+	feedback := []string{
+		"- T001: the login test still fails\n- README section on login is missing\n- T002: logout flashes an error",
+		"- T001: the login test still fails on CI",
+		"- T001: nothing changed",
+	}
+	validations := 0
+	val := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			msg, err := json.Marshal(feedback[validations])
+			require.NoError(t, err)
+			out := `{"RALPH_VALIDATION": {"verdict": "NEEDS_MORE_WORK", "feedback": ` + string(msg) + `}}`
+			validations++
+			return os.WriteFile(outputPath, []byte(out), 0644)
+		},
+	}
+
+	orch := NewOrchestrator(cfg)
+	orch.CommandChecker = alwaysAvailable
+	orch.StateDir = tmpDir
+	orch.ImplRunner = impl
+	orch.ValRunner = val
+	orch.Run(context.Background())
+
+	require.Len(t, prompts, 3)
+	assert.NotContains(t, prompts[1], "EARLIER FEEDBACK STILL OPEN", "every issue is new")
+	assert.Contains(t, prompts[2], "- still failing since iteration 1 (raised 2 times): T001: the login test still fails on CI")
+	assert.Contains(t, prompts[2], "- raised in iteration 1, not confirmed fixed since: README section on login is missing")
+	assert.NotContains(t, prompts[2], "logout flashes", "T002 is checked")
+}
@@ -0,0 +1,74 @@
+package phases
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// finalPlanCacheFile holds, in the state directory, the last final-plan
+// verdict and the hashes of the files it judged.
+const finalPlanCacheFile = "final-plan-cache.json"
+
+// finalPlanCache is a final-plan verdict keyed by its inputs: the spec,
+// tasks and plan files by path, each with its content hash.
+type finalPlanCache struct {
+	Verdict string            `json:"verdict"`
+	Inputs  map[string]string `json:"inputs"`
+	At      string            `json:"at"`
+}
+
+// finalPlanInputs hashes the files final-plan validation reads. It returns
+// nil when one of them cannot be read, so the verdict is not cached.
+func finalPlanInputs(files ...string) map[string]string {
+	inputs := map[string]string{}
+	for _, f := range files {
+		if f == "" {
+			continue
+		}
+		hash, err := tasks.HashFile(f)
+		if err != nil {
+			return nil
+		}
+		inputs[f] = hash
+	}
+	return inputs
+}
+
+// finalPlanConfirmed reports whether final-plan validation already
+// confirmed the plan over exactly these inputs. Only a confirmation is
+// reused: a rejection is judged again after the loop has worked on it.
+func (o *Orchestrator) finalPlanConfirmed(inputs map[string]string) bool {
+	if inputs == nil {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(o.StateDir, finalPlanCacheFile))
+	if err != nil {
+		return false
+	}
+	var cached finalPlanCache
+	if err := json.Unmarshal(data, &cached); err != nil {
+		logging.Debug(fmt.Sprintf("Ignoring %s: %v", finalPlanCacheFile, err))
+		return false
+	}
+	return cached.Verdict == "CONFIRMED" && maps.Equal(cached.Inputs, inputs)
+}
+
+// saveFinalPlanVerdict caches the verdict final-plan validation gave over
+// inputs.
+func (o *Orchestrator) saveFinalPlanVerdict(inputs map[string]string, verdict string) {
+	if inputs == nil || verdict == "" {
+		return
+	}
+	data, err := json.MarshalIndent(finalPlanCache{Verdict: verdict, Inputs: inputs, At: time.Now().Format(time.RFC3339)}, "", "    ")
+	if err != nil {
+		return
+	}
+	o.writeStateFile(finalPlanCacheFile, data)
+}
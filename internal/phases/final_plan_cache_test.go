@@ -0,0 +1,79 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+)
+
+// finalPlanTestRun runs a one-iteration session in stateDir whose
+// validator completes the tasks and whose final-plan validator answers
+// verdict, and returns the final-plan runner.
+func finalPlanTestRun(t *testing.T, stateDir string, verdict string, revalidate bool) (*MockOrchestratorAIRunner, int) {
+	t.Helper()
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	cfg.RevalidatePlan = revalidate
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte("done"), 0644)
+		},
+	}
+	o := newTestOrchestrator(t, cfg, stateDir, implRunner, nil)
+	finalPlan := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte(`{"RALPH_FINAL_PLAN_VALIDATION": {"verdict": "`+verdict+`", "feedback": "plan feedback"}}`), 0644)
+		},
+	}
+	o.FinalPlanRunner = finalPlan
+	return finalPlan, o.Run(context.Background())
+}
+
+func TestOrchestrator_FinalPlanVerdictCached(t *testing.T) {
+	stateDir := t.TempDir()
+
+	first, code := finalPlanTestRun(t, stateDir, "APPROVE", false)
+	assert.Equal(t, exitcode.Success, code)
+	assert.Equal(t, 1, first.CallCount)
+	require.FileExists(t, filepath.Join(stateDir, finalPlanCacheFile))
+
+	second, code := finalPlanTestRun(t, stateDir, "APPROVE", false)
+	assert.Equal(t, exitcode.Success, code)
+	assert.Equal(t, 0, second.CallCount, "unchanged inputs skip final-plan validation")
+
+	forced, code := finalPlanTestRun(t, stateDir, "APPROVE", true)
+	assert.Equal(t, exitcode.Success, code)
+	assert.Equal(t, 1, forced.CallCount, "--revalidate-plan runs it anyway")
+}
+
+func TestOrchestrator_FinalPlanRejectionNotReused(t *testing.T) {
+	stateDir := t.TempDir()
+
+	finalPlanTestRun(t, stateDir, "REJECT", false)
+	again, _ := finalPlanTestRun(t, stateDir, "APPROVE", false)
+	assert.Equal(t, 1, again.CallCount)
+}
+
+func TestFinalPlanConfirmed_ChangedInput(t *testing.T) {
+	dir := t.TempDir()
+	plan := filepath.Join(dir, "plan.md")
+	require.NoError(t, os.WriteFile(plan, []byte("# Plan\n"), 0644))
+	o := NewOrchestrator(config.NewDefaultConfig())
+	o.StateDir = dir
+
+	inputs := finalPlanInputs(plan, "")
+	o.saveFinalPlanVerdict(inputs, "CONFIRMED")
+	assert.True(t, o.finalPlanConfirmed(finalPlanInputs(plan)))
+
+	require.NoError(t, os.WriteFile(plan, []byte("# Plan, revised\n"), 0644))
+	assert.False(t, o.finalPlanConfirmed(finalPlanInputs(plan)))
+	assert.Nil(t, finalPlanInputs(filepath.Join(dir, "missing.md")))
+	assert.False(t, o.finalPlanConfirmed(nil))
+}
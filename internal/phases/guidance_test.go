@@ -0,0 +1,63 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOrchestrator_ResumeAppliesHumanGuidance verifies the escalation loop:
+// guidance recorded after an ESCALATE reaches the next implementation
+// prompt on --resume, and only that one.
+func TestOrchestrator_ResumeAppliesHumanGuidance(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 5
+
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte("Done."), 0644)
+		},
+	}
+	// The resumed run validates iteration 1 again before iterating on.
+	verdicts := []string{"ESCALATE", "NEEDS_MORE_WORK", "NEEDS_MORE_WORK", "COMPLETE"}
+	valRunner := &MockOrchestratorAIRunner{}
+	o := newTestOrchestrator(t, cfg, tmpDir, implRunner, valRunner)
+	valRunner.RunFunc = func(ctx context.Context, prompt string, outputPath string) error {
+		verdict := verdicts[valRunner.CallCount-1]
+		if verdict == "COMPLETE" {
+			_ = os.WriteFile(cfg.TasksFile, []byte("- [x] T001 Task\n"), 0644)
+		}
+		return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON(verdict, "Two ways to store sessions; which one?")), 0644)
+	}
+	require.Equal(t, exitcode.Escalate, o.Run(context.Background()))
+
+	s, err := state.LoadState(tmpDir)
+	require.NoError(t, err)
+	require.True(t, state.AddHumanGuidance(s, "Use approach B: store sessions in Redis", time.Now()))
+	require.NoError(t, state.SaveState(s, tmpDir))
+
+	resumeCfg := *cfg
+	resumeCfg.Resume = true
+	resumed := newTestOrchestrator(t, &resumeCfg, tmpDir, implRunner, valRunner)
+	require.Equal(t, exitcode.Success, resumed.Run(context.Background()))
+
+	require.Len(t, implRunner.PromptLog, 3)
+	assert.NotContains(t, implRunner.PromptLog[0], "HUMAN GUIDANCE")
+	assert.Contains(t, implRunner.PromptLog[1], "HUMAN GUIDANCE")
+	assert.Contains(t, implRunner.PromptLog[1], "- Use approach B: store sessions in Redis")
+	assert.NotContains(t, implRunner.PromptLog[2], "HUMAN GUIDANCE", "guidance is given once")
+
+	s, err = state.LoadState(tmpDir)
+	require.NoError(t, err)
+	require.Len(t, s.HumanGuidance, 1)
+	assert.Equal(t, 2, s.HumanGuidance[0].AppliedIteration)
+	assert.Empty(t, state.PendingGuidance(s))
+}
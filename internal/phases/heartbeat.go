@@ -0,0 +1,61 @@
+package phases
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/notification"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// startHeartbeat sends a heartbeat every HEARTBEAT_INTERVAL until the
+// returned stop function is called. The heartbeat goroutine reads the
+// iteration, phase and progress from the saved state rather than from the
+// session the loop is updating.
+func (o *Orchestrator) startHeartbeat() (stop func()) {
+	if o.Config.HeartbeatInterval <= 0 {
+		return func() {}
+	}
+	interval, n := o.Config.HeartbeatInterval, o.notifications()
+	project, sessionID := o.projectName(), o.session.SessionID
+	iteration := o.session.Iteration
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				detail := heartbeatDetail("", time.Since(o.startTime))
+				if saved, err := state.LoadState(o.StateDir); err == nil {
+					iteration = saved.Iteration
+					detail = withETA(heartbeatDetail(saved.Phase, time.Since(o.startTime)), saved)
+				}
+				n.Notify(notification.EventHeartbeat, notification.FormatProgress(notification.EventHeartbeat,
+					project, sessionID, iteration, detail))
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// iterationHeartbeat sends a heartbeat, with the estimated progress, when
+// the iteration just started is a multiple of HEARTBEAT_ITERATIONS.
+func (o *Orchestrator) iterationHeartbeat() {
+	every := o.Config.HeartbeatIterations
+	if every <= 0 || o.session.Iteration%every != 0 {
+		return
+	}
+	o.notifyProgress(notification.EventHeartbeat, withETA(heartbeatDetail(state.PhaseImplementation, time.Since(o.startTime)), o.session))
+}
+
+// heartbeatDetail says where the run is and for how long it has run.
+func heartbeatDetail(phase string, elapsed time.Duration) string {
+	elapsed = elapsed.Round(time.Second)
+	if phase == "" {
+		return fmt.Sprintf("%s elapsed", elapsed)
+	}
+	return fmt.Sprintf("%s phase, %s elapsed", phase, elapsed)
+}
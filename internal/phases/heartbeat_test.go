@@ -0,0 +1,65 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+)
+
+func TestOrchestrator_HeartbeatEveryIterations(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.HeartbeatIterations = 2
+	cfg.MaxIterations = 2
+	o := newTestOrchestrator(t, cfg, t.TempDir(), nil, needsMoreWorkValidator())
+	var sent []string
+	o.sendMessage = func(message string) { sent = append(sent, message) }
+	assert.Equal(t, exitcode.MaxIterations, o.Run(context.Background()))
+	require.Len(t, sent, 2)
+	assert.Contains(t, sent[0], "still running at iteration 2: implementation phase,")
+	assert.Contains(t, sent[1], "reached max iterations")
+}
+
+func TestOrchestrator_HeartbeatInterval(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	cfg.HeartbeatInterval = 10 * time.Millisecond
+	impl := &MockOrchestratorAIRunner{RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+		time.Sleep(100 * time.Millisecond)
+		return os.WriteFile(outputPath, []byte("Done."), 0644)
+	}}
+	o := newTestOrchestrator(t, cfg, t.TempDir(), impl, needsMoreWorkValidator())
+	var (
+		mu   sync.Mutex
+		sent []string
+	)
+	o.sendMessage = func(message string) {
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, message)
+	}
+
+	assert.Equal(t, exitcode.MaxIterations, o.Run(context.Background()))
+	mu.Lock()
+	defer mu.Unlock()
+	var heartbeats int
+	for _, msg := range sent {
+		if strings.Contains(msg, "still running at iteration 1: implementation phase") {
+			heartbeats++
+		}
+	}
+	assert.NotZero(t, heartbeats, "heartbeats are sent while the AI call runs")
+}
+
+func TestHeartbeatDetail(t *testing.T) {
+	assert.Equal(t, "validation phase, 1m30s elapsed", heartbeatDetail("validation", 90*time.Second+200*time.Millisecond))
+	assert.Equal(t, "5s elapsed", heartbeatDetail("", 5*time.Second))
+}
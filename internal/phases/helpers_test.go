@@ -0,0 +1,71 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+)
+
+// newTestOrchestrator returns an orchestrator for cfg with its state and
+// learnings in stateDir, working on a tasks file there with the one task
+// T001, and with cross-validation, final-plan and tasks validation off. A nil impl
+// reports it is done; a nil val checks T001 and says COMPLETE.
+func newTestOrchestrator(t *testing.T, cfg *config.Config, stateDir string, impl, val *MockOrchestratorAIRunner) *Orchestrator {
+	t.Helper()
+	tasksFile := filepath.Join(stateDir, "tasks.md")
+	require.NoError(t, os.MkdirAll(stateDir, 0755))
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] T001 Task\n"), 0644))
+	cfg.TasksFile = tasksFile
+	cfg.LearningsFile = filepath.Join(stateDir, "learnings.md")
+	cfg.CrossValidate = false
+	cfg.FinalPlanAI = ""
+	cfg.TasksValAI = ""
+
+	if impl == nil {
+		impl = doneImplementer()
+	}
+	if val == nil {
+		val = completeValidator(cfg)
+	}
+	o := NewOrchestrator(cfg)
+	o.CommandChecker = alwaysAvailable
+	o.StateDir = stateDir
+	o.ImplRunner = impl
+	o.ValRunner = val
+	return o
+}
+
+// doneImplementer returns the implementer newTestOrchestrator defaults to,
+// for tests that read its prompts.
+func doneImplementer() *MockOrchestratorAIRunner {
+	return &MockOrchestratorAIRunner{RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+		return os.WriteFile(outputPath, []byte("Done."), 0644)
+	}}
+}
+
+// completeValidator returns the validator newTestOrchestrator defaults to,
+// for tests that count its calls.
+func completeValidator(cfg *config.Config) *MockOrchestratorAIRunner {
+	return &MockOrchestratorAIRunner{RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+		return completeValidation(cfg, outputPath)
+	}}
+}
+
+// needsMoreWorkValidator returns a validator that never accepts the work.
+func needsMoreWorkValidator() *MockOrchestratorAIRunner {
+	return &MockOrchestratorAIRunner{RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+		return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("NEEDS_MORE_WORK", "Not done yet")), 0644)
+	}}
+}
+
+// completeValidation checks T001 in cfg's tasks file and writes a
+// COMPLETE verdict to outputPath.
+func completeValidation(cfg *config.Config, outputPath string) error {
+	_ = os.WriteFile(cfg.TasksFile, []byte("- [x] T001 Task\n"), 0644)
+	return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("COMPLETE", "")), 0644)
+}
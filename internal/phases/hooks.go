@@ -0,0 +1,158 @@
+package phases
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/hooks"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+)
+
+// hookOutputTail caps the hook output passed on in feedback.
+const hookOutputTail = 4000
+
+// hookCommand returns the command configured for a hook point.
+func (o *Orchestrator) hookCommand(point string) string {
+	switch point {
+	case hooks.PreIteration:
+		return o.Config.HookPreIteration
+	case hooks.PostImplementation:
+		return o.Config.HookPostImplementation
+	case hooks.PostValidation:
+		return o.Config.HookPostValidation
+	case hooks.OnExit:
+		return o.Config.HookOnExit
+	}
+	return ""
+}
+
+// hookEvent returns the event for a hook point with the session fields
+// filled in.
+func (o *Orchestrator) hookEvent(point string) hooks.Event {
+	workDir, _ := filepath.Abs(o.workDir())
+	stateDir, _ := filepath.Abs(o.StateDir)
+	event := hooks.Event{
+		Event:     point,
+		Timestamp: time.Now().Format(time.RFC3339),
+		StateDir:  stateDir,
+		WorkDir:   workDir,
+	}
+	if o.session != nil {
+		event.SessionID = o.session.SessionID
+		event.Iteration = o.session.Iteration
+		event.TasksFile = o.session.TasksFile
+	}
+	return event
+}
+
+// runHook runs the hook configured for event.Event, saving its output as
+// hook-<point>.log in dir. It returns "" when there is no hook or it
+// passed, and otherwise a description of the failure with the tail of
+// the hook's output.
+func (o *Orchestrator) runHook(ctx context.Context, event hooks.Event, dir string) string {
+	command := o.hookCommand(event.Event)
+	if command == "" {
+		return ""
+	}
+	logging.Info(fmt.Sprintf("Running %s hook: %s", event.Event, command))
+	timeout := time.Duration(o.Config.HookTimeout) * time.Second
+	result, err := hooks.Run(ctx, command, o.workDir(), event, timeout)
+
+	logPath := filepath.Join(dir, fmt.Sprintf("hook-%s.log", event.Event))
+	if werr := os.WriteFile(logPath, []byte(result.Output), 0644); werr != nil {
+		logging.Warn(fmt.Sprintf("Failed to save %s hook output: %v", event.Event, werr))
+	}
+
+	var failure string
+	switch {
+	case err != nil:
+		failure = fmt.Sprintf("The %s hook `%s` failed: %v", event.Event, command, err)
+	case result.Failed():
+		failure = fmt.Sprintf("The %s hook `%s` failed with exit code %d", event.Event, command, result.ExitCode)
+	default:
+		logging.Success(fmt.Sprintf("%s hook passed (%s)", event.Event, result.Duration.Round(time.Millisecond)))
+		return ""
+	}
+	logging.Warn(failure)
+	if output := strings.TrimSpace(result.Output); output != "" {
+		if len(output) > hookOutputTail {
+			output = "..." + output[len(output)-hookOutputTail:]
+		}
+		failure += ":\n\n" + output
+	}
+	return failure
+}
+
+// runPreIterationHook runs the pre-iteration hook. A failure is returned
+// as an error: the loop cannot start an iteration on a tree the hook
+// failed to prepare.
+func (o *Orchestrator) runPreIterationHook(ctx context.Context, iterDir string) error {
+	if failure := o.runHook(ctx, o.hookEvent(hooks.PreIteration), iterDir); failure != "" {
+		return fmt.Errorf("%s", failure)
+	}
+	return nil
+}
+
+// runPostImplementationHook runs the post-implementation hook. When it
+// fails the iteration's feedback becomes the hook's output and false is
+// returned, so the implementer fixes it before the work is validated.
+func (o *Orchestrator) runPostImplementationHook(ctx context.Context, implOutputPath, iterDir string) bool {
+	event := o.hookEvent(hooks.PostImplementation)
+	event.ImplementationOutput = absolutePath(implOutputPath)
+	failure := o.runHook(ctx, event, iterDir)
+	if failure == "" {
+		return true
+	}
+	o.session.LastFeedback = base64.StdEncoding.EncodeToString([]byte(failure))
+	return false
+}
+
+// applyPostValidationHook runs the post-validation hook on the validation
+// result. A failure turns COMPLETE into NEEDS_MORE_WORK and puts the
+// hook's output at the top of the feedback; other verdicts stand.
+func (o *Orchestrator) applyPostValidationHook(ctx context.Context, result ValidationPhaseResult, implOutputPath, valOutputPath, iterDir string) ValidationPhaseResult {
+	event := o.hookEvent(hooks.PostValidation)
+	event.ImplementationOutput = absolutePath(implOutputPath)
+	event.ValidationOutput = absolutePath(valOutputPath)
+	event.Verdict = result.Verdict
+	event.Feedback = result.Feedback
+	failure := o.runHook(ctx, event, iterDir)
+	if failure == "" {
+		return result
+	}
+	if result.Feedback != "" {
+		failure += "\n\n" + result.Feedback
+	}
+	result.Feedback = failure
+	if result.Verdict == "COMPLETE" {
+		logging.Warn("Verdict COMPLETE overridden by the post-validation hook: NEEDS_MORE_WORK")
+		result.Verdict = "NEEDS_MORE_WORK"
+	}
+	return result
+}
+
+// runOnExitHook runs the on-exit hook with the exit code and reason,
+// except for --status and --cancel. Its outcome is only logged.
+func (o *Orchestrator) runOnExitHook(res exitcode.Result) {
+	if o.Config.Status || o.Config.Cancel {
+		return
+	}
+	event := o.hookEvent(hooks.OnExit)
+	event.ExitCode = &res.Code
+	event.ExitReason = res.Reason
+	o.runHook(context.Background(), event, o.StateDir)
+}
+
+// absolutePath returns path made absolute, or path itself when that fails.
+func absolutePath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
@@ -0,0 +1,143 @@
+//go:build !windows
+
+package phases
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/hooks"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readHookEvent(t *testing.T, path string) hooks.Event {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var event hooks.Event
+	require.NoError(t, json.Unmarshal(data, &event))
+	return event
+}
+
+func TestOrchestrator_PreIterationHook(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	impl := doneImplementer()
+	o := newTestOrchestrator(t, cfg, t.TempDir(), impl, nil)
+	o.WorkDir = t.TempDir()
+	o.Config.HookPreIteration = "cat > pre.json"
+
+	assert.Equal(t, exitcode.Success, o.Run(context.Background()))
+
+	event := readHookEvent(t, filepath.Join(o.WorkDir, "pre.json"))
+	assert.Equal(t, hooks.PreIteration, event.Event)
+	assert.Equal(t, 1, event.Iteration)
+	assert.Equal(t, o.session.SessionID, event.SessionID)
+	assert.Equal(t, 1, impl.CallCount)
+	assert.FileExists(t, filepath.Join(o.StateDir, "iteration-001", "hook-pre-iteration.log"))
+}
+
+func TestOrchestrator_PreIterationHookFailureStops(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	impl := doneImplementer()
+	o := newTestOrchestrator(t, cfg, t.TempDir(), impl, nil)
+	o.WorkDir = t.TempDir()
+	o.Config.HookPreIteration = "echo 'database unreachable'; exit 1"
+
+	assert.Equal(t, exitcode.Error, o.Run(context.Background()))
+	assert.Zero(t, impl.CallCount)
+}
+
+func TestOrchestrator_PostImplementationHookFailureSkipsValidation(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 2
+	impl := doneImplementer()
+	val := completeValidator(cfg)
+	o := newTestOrchestrator(t, cfg, t.TempDir(), impl, val)
+	o.WorkDir = t.TempDir()
+	o.Config.HookPostImplementation = "test -f scanned || { touch scanned; echo 'SAST: SQL injection in db.go:12'; exit 1; }"
+
+	assert.Equal(t, exitcode.Success, o.Run(context.Background()))
+
+	assert.Equal(t, 2, impl.CallCount)
+	assert.Equal(t, 1, val.CallCount, "the first iteration is not validated")
+	assert.Contains(t, impl.PromptLog[1], "SAST: SQL injection in db.go:12")
+}
+
+func TestOrchestrator_PostValidationHookGetsVerdict(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	o := newTestOrchestrator(t, cfg, t.TempDir(), nil, nil)
+	o.WorkDir = t.TempDir()
+	o.Config.HookPostValidation = "cat > post.json"
+
+	assert.Equal(t, exitcode.Success, o.Run(context.Background()))
+
+	event := readHookEvent(t, filepath.Join(o.WorkDir, "post.json"))
+	assert.Equal(t, "COMPLETE", event.Verdict)
+	assert.Equal(t, filepath.Join(o.StateDir, "iteration-001", "validation-output.txt"), event.ValidationOutput)
+	assert.Equal(t, filepath.Join(o.StateDir, "iteration-001", "implementation-output.txt"), event.ImplementationOutput)
+}
+
+func TestApplyPostValidationHook(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.HookPostValidation = "echo 'e2e: 3 failed'; exit 2"
+	dir := t.TempDir()
+	o := &Orchestrator{Config: cfg, StateDir: dir, WorkDir: dir}
+
+	result := o.applyPostValidationHook(context.Background(), ValidationPhaseResult{Verdict: "COMPLETE", Feedback: "Looks good."}, "impl.txt", "val.txt", dir)
+	assert.Equal(t, "NEEDS_MORE_WORK", result.Verdict)
+	assert.Equal(t, "The post-validation hook `echo 'e2e: 3 failed'; exit 2` failed with exit code 2:\n\ne2e: 3 failed\n\nLooks good.", result.Feedback)
+
+	result = o.applyPostValidationHook(context.Background(), ValidationPhaseResult{Verdict: "ESCALATE"}, "impl.txt", "val.txt", dir)
+	assert.Equal(t, "ESCALATE", result.Verdict)
+
+	cfg.HookPostValidation = "true"
+	result = o.applyPostValidationHook(context.Background(), ValidationPhaseResult{Verdict: "COMPLETE"}, "impl.txt", "val.txt", dir)
+	assert.Equal(t, "COMPLETE", result.Verdict)
+	assert.Empty(t, result.Feedback)
+}
+
+func TestOrchestrator_OnExitHook(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	o := newTestOrchestrator(t, cfg, t.TempDir(), nil, nil)
+	o.WorkDir = t.TempDir()
+	o.Config.HookOnExit = "cat > exit.json"
+
+	code := o.Run(context.Background())
+
+	event := readHookEvent(t, filepath.Join(o.WorkDir, "exit.json"))
+	assert.Equal(t, hooks.OnExit, event.Event)
+	require.NotNil(t, event.ExitCode)
+	assert.Equal(t, code, *event.ExitCode)
+	assert.Equal(t, exitcode.Success, code)
+	assert.Equal(t, "all tasks complete and validated after 1 iteration(s)", event.ExitReason)
+
+	saved, err := state.LoadState(o.StateDir)
+	require.NoError(t, err)
+	require.NotNil(t, saved.Exit)
+	assert.Equal(t, state.ExitState{Code: exitcode.Success, Name: "Success", Reason: event.ExitReason}, *saved.Exit)
+}
+
+func TestRunPostImplementationHook_SetsFeedback(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	o := newTestOrchestrator(t, cfg, t.TempDir(), nil, nil)
+	o.WorkDir = t.TempDir()
+	o.Config.HookPostImplementation = "echo lint failed >&2; exit 1"
+	o.session = &state.SessionState{}
+
+	assert.False(t, o.runPostImplementationHook(context.Background(), "impl.txt", t.TempDir()))
+	feedback, err := base64.StdEncoding.DecodeString(o.session.LastFeedback)
+	require.NoError(t, err)
+	assert.Contains(t, string(feedback), "lint failed")
+}
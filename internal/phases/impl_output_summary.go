@@ -0,0 +1,121 @@
+package phases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/learnings"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/prompt"
+)
+
+// implOutputSummaryFile is where the summary of an iteration's
+// implementation output is kept, in the iteration directory.
+const implOutputSummaryFile = "implementation-output-summary.md"
+
+// implOutputSummarySection returns the validation prompt section standing
+// in for an implementation output above ImplOutputMaxTokens: a summary,
+// kept beside the output so a resumed validation reuses it, and the path
+// of the full file. It is "" when the output is small enough or cannot be
+// summarized, leaving the validator to read the file.
+func (o *Orchestrator) implOutputSummarySection(ctx context.Context, implOutputPath, iterDir string) string {
+	limit := o.Config.ImplOutputMaxTokens
+	if limit <= 0 {
+		return ""
+	}
+	info, err := os.Stat(implOutputPath)
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(implOutputPath)
+	if err != nil {
+		return ""
+	}
+	output := string(data)
+	tokens := learnings.EstimateTokens(output)
+	if tokens <= limit {
+		return ""
+	}
+
+	summaryPath := filepath.Join(iterDir, implOutputSummaryFile)
+	if cached, err := os.Stat(summaryPath); err == nil && !cached.ModTime().Before(info.ModTime()) {
+		if summary, err := os.ReadFile(summaryPath); err == nil && len(summary) > 0 {
+			return prompt.BuildImplOutputSummarySection(string(summary), implOutputPath, tokens)
+		}
+	}
+
+	logging.Info(fmt.Sprintf("Implementation output is ~%d tokens (limit %d), summarizing it for the validator", tokens, limit))
+	summary, err := o.summarizeImplOutput(ctx, output, limit, iterDir)
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Implementation output summarization failed, the validator reads the full output: %v", err))
+		return ""
+	}
+	if err := os.WriteFile(summaryPath, []byte(summary), 0644); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save implementation output summary: %v", err))
+	}
+	return prompt.BuildImplOutputSummarySection(summary, implOutputPath, tokens)
+}
+
+// summarizeImplOutput summarizes output in parts of at most limit tokens,
+// each with the summary runner, into a summary of about a quarter of
+// limit.
+func (o *Orchestrator) summarizeImplOutput(ctx context.Context, output string, limit int, iterDir string) (string, error) {
+	runner := o.SummaryRunner
+	if runner == nil {
+		runner = o.ValRunner
+	}
+	if runner == nil {
+		return "", fmt.Errorf("no summary runner")
+	}
+
+	parts := splitChunks(output, limit*4)
+	budget := limit / 4 / len(parts)
+	if budget < 200 {
+		budget = 200
+	}
+	var summaries []string
+	for i, part := range parts {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		outputPath := filepath.Join(iterDir, fmt.Sprintf("implementation-summary-output-%d.txt", i+1))
+		if err := runner.Run(ctx, prompt.BuildImplOutputSummaryPrompt(part, i+1, len(parts), budget), outputPath); err != nil {
+			return "", fmt.Errorf("part %d: summarization AI error: %w", i+1, err)
+		}
+		answer, err := os.ReadFile(outputPath)
+		if err != nil {
+			return "", fmt.Errorf("part %d: failed to read summarization output: %w", i+1, err)
+		}
+		summary := strings.TrimSpace(parser.ExtractOutputSummary(string(answer)))
+		if summary == "" {
+			return "", fmt.Errorf("part %d: no RALPH_OUTPUT_SUMMARY block in %s", i+1, outputPath)
+		}
+		if len(parts) > 1 {
+			summary = fmt.Sprintf("### Part %d of %d\n\n%s", i+1, len(parts), summary)
+		}
+		summaries = append(summaries, summary)
+	}
+	return strings.Join(summaries, "\n\n") + "\n", nil
+}
+
+// splitChunks splits text into chunks of at most size bytes, at line
+// boundaries where it can.
+func splitChunks(text string, size int) []string {
+	var chunks []string
+	for len(text) > size {
+		cut := strings.LastIndexByte(text[:size], '\n') + 1
+		if cut == 0 {
+			cut = size
+		}
+		chunks = append(chunks, text[:cut])
+		text = text[cut:]
+	}
+	if text != "" || len(chunks) == 0 {
+		chunks = append(chunks, text)
+	}
+	return chunks
+}
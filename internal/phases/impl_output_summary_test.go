@@ -0,0 +1,109 @@
+package phases
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitChunks(t *testing.T) {
+	assert.Equal(t, []string{""}, splitChunks("", 10))
+	assert.Equal(t, []string{"short\n"}, splitChunks("short\n", 10))
+	assert.Equal(t, []string{"line one\n", "line two\n", "end"}, splitChunks("line one\nline two\nend", 10))
+	assert.Equal(t, []string{"abcd", "efgh", "ij"}, splitChunks("abcdefghij", 4), "lines longer than a chunk are cut")
+}
+
+// testOutputImplementer returns an implementer whose output is about size
+// bytes of test results.
+func testOutputImplementer(size int) *MockOrchestratorAIRunner {
+	return &MockOrchestratorAIRunner{RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+		return os.WriteFile(outputPath, []byte(strings.Repeat("Ran go test ./...: ok\n", size/22)), 0644)
+	}}
+}
+
+// testSummarizer returns a summary runner that sums up every part alike.
+func testSummarizer() *MockOrchestratorAIRunner {
+	return &MockOrchestratorAIRunner{RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+		return os.WriteFile(outputPath, []byte("RALPH_OUTPUT_SUMMARY\n```markdown\n- Tests pass\n```\n"), 0644)
+	}}
+}
+
+func TestOrchestrator_SummarizesLongImplOutput(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	cfg.ImplOutputMaxTokens = 200
+	val := completeValidator(cfg)
+	o := newTestOrchestrator(t, cfg, t.TempDir(), testOutputImplementer(2000), val)
+	summary := testSummarizer()
+	o.SummaryRunner = summary
+
+	o.Run(context.Background())
+
+	// ~500 tokens in parts of 200 tokens
+	assert.Equal(t, 3, summary.CallCount)
+	assert.Contains(t, summary.PromptLog[0], "part\n1 of 3")
+	require.NotEmpty(t, val.PromptLog)
+	valPrompt := val.PromptLog[0]
+	assert.Contains(t, valPrompt, "IMPLEMENTATION OUTPUT SUMMARY")
+	assert.Contains(t, valPrompt, "### Part 1 of 3\n\n- Tests pass")
+	implOutput := filepath.Join(o.StateDir, "iteration-001", "implementation-output.txt")
+	assert.Contains(t, valPrompt, implOutput)
+	assert.FileExists(t, filepath.Join(o.StateDir, "iteration-001", implOutputSummaryFile))
+}
+
+func TestOrchestrator_ShortImplOutputNotSummarized(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	cfg.ImplOutputMaxTokens = 200
+	val := completeValidator(cfg)
+	o := newTestOrchestrator(t, cfg, t.TempDir(), testOutputImplementer(400), val)
+	summary := testSummarizer()
+	o.SummaryRunner = summary
+
+	o.Run(context.Background())
+
+	assert.Zero(t, summary.CallCount)
+	require.NotEmpty(t, val.PromptLog)
+	assert.NotContains(t, val.PromptLog[0], "IMPLEMENTATION OUTPUT SUMMARY")
+}
+
+func TestOrchestrator_ImplOutputSummaryFailure(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	cfg.ImplOutputMaxTokens = 200
+	val := completeValidator(cfg)
+	o := newTestOrchestrator(t, cfg, t.TempDir(), testOutputImplementer(2000), val)
+	summary := testSummarizer()
+	o.SummaryRunner = summary
+	summary.RunFunc = func(ctx context.Context, prompt string, outputPath string) error {
+		return errors.New("rate limited")
+	}
+
+	o.Run(context.Background())
+
+	require.NotEmpty(t, val.PromptLog, "validation runs on the full output")
+	assert.NotContains(t, val.PromptLog[0], "IMPLEMENTATION OUTPUT SUMMARY")
+}
+
+func TestImplOutputSummarySection_ReusesSavedSummary(t *testing.T) {
+	iterDir := t.TempDir()
+	implOutput := filepath.Join(iterDir, "implementation-output.txt")
+	require.NoError(t, os.WriteFile(implOutput, []byte(strings.Repeat("x", 4000)), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(iterDir, implOutputSummaryFile), []byte("- Saved summary\n"), 0644))
+	cfg := config.NewDefaultConfig()
+	cfg.ImplOutputMaxTokens = 200
+	summary := &MockOrchestratorAIRunner{}
+	o := &Orchestrator{Config: cfg, SummaryRunner: summary}
+
+	section := o.implOutputSummarySection(context.Background(), implOutput, iterDir)
+
+	assert.Contains(t, section, "- Saved summary")
+	assert.Zero(t, summary.CallCount)
+}
@@ -0,0 +1,203 @@
+package phases
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/gitdiff"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/prompt"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// snapshotDiffBase records the working tree before the implementation
+// phase so the iteration's changes can be saved for `ralph-loop diff`, the
+// validator shown what the iteration changed and in which files, the
+// policy patterns checked against it, protected paths restored and
+// external validators told the changed files, and completions without
+// changes cross-validated. It clears the base when WorkDir is unset or not
+// a git repository.
+func (o *Orchestrator) snapshotDiffBase() {
+	o.diffBase = ""
+	if o.WorkDir == "" {
+		return
+	}
+	base, err := gitdiff.Snapshot(o.workDir())
+	if err != nil {
+		logging.Debug(fmt.Sprintf("No iteration diff for validation: %v", err))
+		return
+	}
+	o.diffBase = base
+}
+
+// iterationDiffSection returns the validation prompt section with the diff
+// between the snapshot taken before implementation and the working tree
+// now, capped at ValDiffMaxTokens. It is "" when no base was recorded.
+func (o *Orchestrator) iterationDiffSection() string {
+	if o.Config.ValDiffMaxTokens <= 0 || o.diffBase == "" {
+		return ""
+	}
+	patch, stat, err := o.iterationPatch()
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Failed to diff the iteration for validation: %v", err))
+		return ""
+	}
+	kept, omitted := gitdiff.Truncate(patch, o.Config.ValDiffMaxTokens*4)
+	if omitted > 0 {
+		logging.Info(fmt.Sprintf("Iteration diff truncated for validation (%d bytes omitted)", omitted))
+	}
+	return prompt.BuildIterationDiffSection(kept, stat, omitted)
+}
+
+// maxFocusFiles caps the files listed in the validation prompt's focus
+// section.
+const maxFocusFiles = 50
+
+// focusFilesSection returns the validation prompt section listing the
+// files changed since the snapshot taken before implementation with their
+// line counts, most changed first. It is "" when no base was recorded or
+// nothing changed.
+func (o *Orchestrator) focusFilesSection() string {
+	if o.diffBase == "" {
+		return ""
+	}
+	root, head, exclude, err := o.iterationHead()
+	var stats []gitdiff.FileStat
+	if err == nil {
+		stats, err = gitdiff.NumStat(root, o.diffBase, head, exclude...)
+	}
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Failed to list the iteration's changed files: %v", err))
+		return ""
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].Added+stats[i].Deleted > stats[j].Added+stats[j].Deleted
+	})
+	var lines []string
+	for i, st := range stats {
+		if i == maxFocusFiles {
+			lines = append(lines, fmt.Sprintf("(%d more files not shown)", len(stats)-maxFocusFiles))
+			break
+		}
+		if st.Binary {
+			lines = append(lines, st.Path+" (binary)")
+		} else {
+			lines = append(lines, fmt.Sprintf("%s (+%d -%d)", st.Path, st.Added, st.Deleted))
+		}
+	}
+	return prompt.BuildFocusFilesSection(lines)
+}
+
+// saveIterationStart records the session and the working tree as the
+// iteration starts, the point `ralph-loop rollback` returns to when the
+// iterations from this one on are undone.
+func (o *Orchestrator) saveIterationStart(iterDir string) {
+	if err := state.SaveIterationStart(iterDir, state.IterationStart{Tree: o.diffBase, Session: o.session}); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save the iteration's rollback point: %v", err))
+	}
+}
+
+// saveIterationDiff writes the verdict and feedback the iteration was
+// judged with to iterDir/verdict.json and, when a base was recorded, what
+// it changed to iterDir/changes.diff, for `ralph-loop diff`.
+func (o *Orchestrator) saveIterationDiff(iterDir, feedback string) {
+	v := state.IterationVerdict{
+		Iteration: o.session.Iteration,
+		Verdict:   o.session.Verdict,
+		Feedback:  feedback,
+		At:        time.Now().Format(time.RFC3339),
+	}
+	if o.diffBase != "" {
+		patch, stat, err := o.iterationPatch()
+		if err == nil {
+			err = os.WriteFile(filepath.Join(iterDir, state.IterationDiffFile), []byte(patch), 0644)
+		}
+		if err != nil {
+			logging.Warn(fmt.Sprintf("Failed to save the iteration diff: %v", err))
+		} else {
+			v.DiffStat = stat
+		}
+	}
+	if err := state.SaveIterationVerdict(iterDir, v); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save the iteration verdict: %v", err))
+	}
+}
+
+// iterationPatch returns the diff between the snapshot taken before
+// implementation and the working tree now, without the state directory.
+func (o *Orchestrator) iterationPatch() (patch, stat string, err error) {
+	root, head, exclude, err := o.iterationHead()
+	if err != nil {
+		return "", "", err
+	}
+	return gitdiff.Diff(root, o.diffBase, head, exclude...)
+}
+
+// iterationChanges returns the files changed since the snapshot taken
+// before implementation, relative to the project root and without the
+// state directory.
+func (o *Orchestrator) iterationChanges() ([]string, error) {
+	root, head, exclude, err := o.iterationHead()
+	if err != nil {
+		return nil, err
+	}
+	return gitdiff.Changed(root, o.diffBase, head, exclude...)
+}
+
+// iterationFiles returns the files changed since the snapshot taken before
+// implementation: an empty list when none changed, nil when that is
+// unknown.
+func (o *Orchestrator) iterationFiles() []string {
+	if o.diffBase == "" {
+		return nil
+	}
+	files, err := o.iterationChanges()
+	if err != nil {
+		return nil
+	}
+	return append([]string{}, files...)
+}
+
+// iterationHead snapshots the working tree now and returns the project
+// root, the snapshot, and the state directory to exclude when it lies
+// inside the root.
+func (o *Orchestrator) iterationHead() (root, head string, exclude []string, err error) {
+	root = o.workDir()
+	head, err = gitdiff.Snapshot(root)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("snapshot the working tree: %w", err)
+	}
+	if rel, ok := insideDir(root, o.StateDir); ok {
+		exclude = append(exclude, rel)
+	}
+	return root, head, exclude, nil
+}
+
+// workDir returns the project root, defaulting to the current directory.
+func (o *Orchestrator) workDir() string {
+	if o.WorkDir == "" {
+		return "."
+	}
+	return o.WorkDir
+}
+
+// insideDir returns path relative to root when it lies inside root.
+func insideDir(root, path string) (string, bool) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", false
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(absRoot, absPath)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
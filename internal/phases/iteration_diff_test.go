@@ -0,0 +1,207 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/gitdiff"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// diffTestRepo creates a git repository holding the tasks file, with the
+// state directory inside it.
+func diffTestRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644))
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.email=t@example.com", "-c", "user.name=t", "commit", "-q", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	return dir
+}
+
+// TestOrchestrator_ValidationPromptHasIterationDiff verifies that the
+// validator sees the changes the implementation phase actually made, but
+// not ralph-loop's own state files.
+func TestOrchestrator_ValidationPromptHasIterationDiff(t *testing.T) {
+	workDir := diffTestRepo(t)
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	valRunner := completeValidator(cfg)
+	impl := &MockOrchestratorAIRunner{RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+		_ = os.WriteFile(filepath.Join(workDir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+		return os.WriteFile(outputPath, []byte("Done."), 0644)
+	}}
+	o := newTestOrchestrator(t, cfg, filepath.Join(workDir, ".ralph-loop"), impl, valRunner)
+	o.WorkDir = workDir
+
+	o.Run(context.Background())
+	require.NotEmpty(t, valRunner.PromptLog)
+	valPrompt := valRunner.PromptLog[0]
+	assert.Contains(t, valPrompt, "ACTUAL CHANGES THIS ITERATION")
+	assert.Contains(t, valPrompt, "+func main() {}")
+	assert.NotContains(t, valPrompt, "state.json")
+}
+
+// TestOrchestrator_ValidationPromptHasFocusFiles verifies that the
+// validator is pointed at the files the iteration changed, with their line
+// counts, even with the diff itself disabled.
+func TestOrchestrator_ValidationPromptHasFocusFiles(t *testing.T) {
+	workDir := diffTestRepo(t)
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	valRunner := completeValidator(cfg)
+	impl := &MockOrchestratorAIRunner{RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+		_ = os.WriteFile(filepath.Join(workDir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+		_ = os.WriteFile(filepath.Join(workDir, "util.go"), []byte("package main\n\nfunc a() {}\n\nfunc b() {}\n"), 0644)
+		return os.WriteFile(outputPath, []byte("Done."), 0644)
+	}}
+	o := newTestOrchestrator(t, cfg, filepath.Join(workDir, ".ralph-loop"), impl, valRunner)
+	o.WorkDir = workDir
+	o.Config.ValDiffMaxTokens = 0
+
+	o.Run(context.Background())
+	require.NotEmpty(t, valRunner.PromptLog)
+	valPrompt := valRunner.PromptLog[0]
+	assert.Contains(t, valPrompt, "FOCUS ON THESE FILES")
+	assert.Contains(t, valPrompt, "- util.go (+5 -0)\n- main.go (+2 -0)", "most changed first")
+	assert.NotContains(t, valPrompt, "state.json")
+}
+
+func TestOrchestrator_ValidationPromptReportsNoChanges(t *testing.T) {
+	workDir := diffTestRepo(t)
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	valRunner := completeValidator(cfg)
+	o := newTestOrchestrator(t, cfg, filepath.Join(workDir, ".ralph-loop"), nil, valRunner)
+	o.WorkDir = workDir
+
+	o.Run(context.Background())
+	require.NotEmpty(t, valRunner.PromptLog)
+	assert.Contains(t, valRunner.PromptLog[0], "changed NO files")
+	assert.NotContains(t, valRunner.PromptLog[0], "FOCUS ON THESE FILES")
+}
+
+// TestOrchestrator_SavesIterationDiff verifies that a judged iteration
+// leaves its changes and verdict for `ralph-loop diff`, even with the
+// validation diff disabled.
+func TestOrchestrator_SavesIterationDiff(t *testing.T) {
+	workDir := diffTestRepo(t)
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	impl := &MockOrchestratorAIRunner{RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+		_ = os.WriteFile(filepath.Join(workDir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+		return os.WriteFile(outputPath, []byte("Done."), 0644)
+	}}
+	o := newTestOrchestrator(t, cfg, filepath.Join(workDir, ".ralph-loop"), impl, nil)
+	o.WorkDir = workDir
+	o.Config.ValDiffMaxTokens = 0
+
+	o.Run(context.Background())
+	iterDir := filepath.Join(workDir, ".ralph-loop", "iteration-001")
+	patch, err := os.ReadFile(filepath.Join(iterDir, state.IterationDiffFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(patch), "+func main() {}")
+	assert.NotContains(t, string(patch), "current-state.json")
+	v, err := state.LoadIterationVerdict(iterDir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, v.Iteration)
+	assert.Equal(t, "COMPLETE", v.Verdict)
+	assert.Contains(t, v.DiffStat, "main.go")
+
+	require.Len(t, o.session.History, 1)
+	assert.Contains(t, o.session.History[0].Files, "main.go")
+	assert.NotContains(t, o.session.History[0].Files, ".ralph-loop/current-state.json")
+	assert.Equal(t, []string{"T001"}, o.session.History[0].Tasks)
+
+	start, err := state.LoadIterationStart(iterDir)
+	require.NoError(t, err)
+	assert.NotEmpty(t, start.Tree)
+	assert.Equal(t, 1, start.Session.Iteration)
+	assert.Equal(t, state.PhaseImplementation, start.Session.Phase)
+	assert.Empty(t, start.Session.History)
+}
+
+func TestOrchestrator_IterationDiffDisabled(t *testing.T) {
+	workDir := diffTestRepo(t)
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	valRunner := completeValidator(cfg)
+	o := newTestOrchestrator(t, cfg, filepath.Join(workDir, ".ralph-loop"), nil, valRunner)
+	o.WorkDir = workDir
+	o.Config.ValDiffMaxTokens = 0
+
+	o.Run(context.Background())
+	require.NotEmpty(t, valRunner.PromptLog)
+	assert.NotContains(t, valRunner.PromptLog[0], "ACTUAL CHANGES THIS ITERATION")
+}
+
+// TestOrchestrator_ResumedValidationUsesCheckpointDiffBase verifies that a
+// session resumed at validation diffs against the snapshot saved before
+// its implementation phase.
+func TestOrchestrator_ResumedValidationUsesCheckpointDiffBase(t *testing.T) {
+	workDir := diffTestRepo(t)
+	stateDir := filepath.Join(workDir, ".ralph-loop")
+	implOutput := filepath.Join(stateDir, "iteration-001", "implementation-output.txt")
+	require.NoError(t, os.MkdirAll(filepath.Dir(implOutput), 0755))
+	require.NoError(t, os.WriteFile(implOutput, []byte("impl done"), 0644))
+	base, err := gitdiff.Snapshot(workDir)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "main.go"), []byte("package main\n\nfunc resumed() {}\n"), 0644))
+	tasksFile := saveCheckpointedSession(t, stateDir, &state.Checkpoint{
+		Iteration: 1, Phase: state.PhaseValidation, ImplOutput: implOutput, DiffBase: base,
+	})
+
+	var valPrompt string
+	orch := NewOrchestrator(resumeConfig(tasksFile))
+	orch.CommandChecker = alwaysAvailable
+	orch.StateDir = stateDir
+	orch.WorkDir = workDir
+	orch.ImplRunner = &MockOrchestratorAIRunner{}
+	orch.ValRunner = &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			valPrompt = prompt
+			return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("COMPLETE", "ok")), 0644)
+		},
+	}
+
+	assert.Equal(t, exitcode.Success, orch.Run(context.Background()))
+	assert.Contains(t, valPrompt, "+func resumed() {}")
+}
+
+func TestIterationDiffSection_NotAGitRepository(t *testing.T) {
+	o := NewOrchestrator(config.NewDefaultConfig())
+	o.WorkDir = t.TempDir()
+	o.snapshotDiffBase()
+	assert.Empty(t, o.diffBase)
+	assert.Empty(t, o.iterationDiffSection())
+	assert.Empty(t, o.focusFilesSection())
+}
+
+func TestInsideDir(t *testing.T) {
+	rel, ok := insideDir("/repo", "/repo/.ralph-loop")
+	assert.True(t, ok)
+	assert.Equal(t, ".ralph-loop", rel)
+
+	_, ok = insideDir("/repo", "/tmp/state")
+	assert.False(t, ok)
+	_, ok = insideDir("/repo", "/repo")
+	assert.False(t, ok)
+}
@@ -0,0 +1,88 @@
+package phases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/learnings"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/prompt"
+)
+
+// promptLearnings returns the learnings for the implementation prompt: the
+// project's own, followed by the library entries for the project's tags,
+// summarized when they exceed LearningsMaxTokens.
+func (o *Orchestrator) promptLearnings(ctx context.Context) string {
+	text := learnings.ReadLearnings(o.Config.LearningsFile)
+	if o.Config.EnableLearnings && o.Config.GlobalLearnings && o.LearningsLibrary != "" {
+		tags := o.learningsTags()
+		global := learnings.ReadGlobal(o.LearningsLibrary, tags)
+		if global != "" && o.session.Iteration == 1 {
+			logging.Info(fmt.Sprintf("Using global learnings for: %s", strings.Join(tags, ", ")))
+		}
+		text = learnings.Merge(text, global)
+	}
+	return o.boundLearnings(ctx, text)
+}
+
+// boundLearnings keeps text within LearningsMaxTokens. Oversized learnings
+// are replaced by a digest from the validation runner, cached beside the
+// learnings file until they change; when summarization fails the most
+// recent learnings are kept instead.
+func (o *Orchestrator) boundLearnings(ctx context.Context, text string) string {
+	limit := o.Config.LearningsMaxTokens
+	if limit <= 0 || learnings.EstimateTokens(text) <= limit {
+		return text
+	}
+
+	digestPath := learnings.DigestPath(o.Config.LearningsFile)
+	if digest, ok := learnings.ReadDigest(digestPath, text); ok {
+		return digest
+	}
+
+	logging.Info(fmt.Sprintf("Learnings are ~%d tokens (limit %d), summarizing with the validation model",
+		learnings.EstimateTokens(text), limit))
+	digest, err := o.summarizeLearnings(ctx, text, limit)
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Learnings summarization failed, keeping the most recent entries: %v", err))
+		return learnings.Truncate(text, limit)
+	}
+	if err := learnings.WriteDigest(digestPath, text, digest); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save learnings digest: %v", err))
+	}
+	return digest
+}
+
+// summarizeLearnings asks the validation runner for a digest of text of at
+// most limit tokens.
+func (o *Orchestrator) summarizeLearnings(ctx context.Context, text string, limit int) (string, error) {
+	if o.ValRunner == nil {
+		return "", fmt.Errorf("no validation runner")
+	}
+	outputPath := filepath.Join(o.StateDir, "learnings-summary-output.txt")
+	if err := o.ValRunner.Run(ctx, prompt.BuildLearningsSummaryPrompt(text, limit), outputPath); err != nil {
+		return "", fmt.Errorf("summarization AI error: %w", err)
+	}
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read summarization output: %w", err)
+	}
+	digest := parser.ExtractLearningsDigest(string(output))
+	if strings.TrimSpace(digest) == "" {
+		return "", fmt.Errorf("no RALPH_LEARNINGS_DIGEST block in %s", outputPath)
+	}
+	return digest, nil
+}
+
+// learningsTags returns the configured LEARNINGS_TAGS, or the tags
+// detected from the project's build files.
+func (o *Orchestrator) learningsTags() []string {
+	if tags := learnings.ParseTags(o.Config.LearningsTags); len(tags) > 0 {
+		return tags
+	}
+	return learnings.DetectTags(o.workDir())
+}
@@ -0,0 +1,139 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/learnings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// learningsLibrary returns a learnings library with an entry for Go.
+func learningsLibrary(t *testing.T) string {
+	t.Helper()
+	library := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(library, "go.md"), []byte("- Prefer table-driven tests\n"), 0644))
+	return library
+}
+
+// TestOrchestrator_MergesGlobalLearnings verifies that library entries for
+// the project's tags reach the implementation prompt.
+func TestOrchestrator_MergesGlobalLearnings(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	cfg.LearningsTags = "go"
+	implRunner := doneImplementer()
+	o := newTestOrchestrator(t, cfg, t.TempDir(), implRunner, nil)
+	o.LearningsLibrary = learningsLibrary(t)
+
+	o.Run(context.Background())
+	require.NotEmpty(t, implRunner.PromptLog)
+	assert.Contains(t, implRunner.PromptLog[0], "Global Learnings")
+	assert.Contains(t, implRunner.PromptLog[0], "- Prefer table-driven tests")
+}
+
+// TestOrchestrator_GlobalLearningsDisabled verifies that --no-global-learnings
+// keeps the library out of the prompt.
+func TestOrchestrator_GlobalLearningsDisabled(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	cfg.GlobalLearnings = false
+	cfg.LearningsTags = "go"
+	implRunner := doneImplementer()
+	o := newTestOrchestrator(t, cfg, t.TempDir(), implRunner, nil)
+	o.LearningsLibrary = learningsLibrary(t)
+
+	o.Run(context.Background())
+	require.NotEmpty(t, implRunner.PromptLog)
+	assert.NotContains(t, implRunner.PromptLog[0], "Prefer table-driven tests")
+}
+
+func TestLearningsTags_DetectsFromWorkDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module x\n"), 0644))
+	o := NewOrchestrator(config.NewDefaultConfig())
+	o.WorkDir = dir
+	assert.Equal(t, []string{"go"}, o.learningsTags())
+
+	o.Config.LearningsTags = "rust, wasm"
+	assert.Equal(t, []string{"rust", "wasm"}, o.learningsTags())
+}
+
+// oversizedLearnings writes a learnings file of roughly 100 tokens.
+func oversizedLearnings(t *testing.T, cfg *config.Config) string {
+	t.Helper()
+	content := "# Ralph Loop Learnings\n" + strings.Repeat("- Remember to run the linter\n", 14)
+	require.NoError(t, os.WriteFile(cfg.LearningsFile, []byte(content), 0644))
+	return content
+}
+
+// TestOrchestrator_SummarizesLargeLearnings verifies that oversized
+// learnings are replaced by the validation model's digest, which is cached
+// for later iterations.
+func TestOrchestrator_SummarizesLargeLearnings(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	cfg.LearningsMaxTokens = 50
+	implRunner := doneImplementer()
+	o := newTestOrchestrator(t, cfg, t.TempDir(), implRunner, nil)
+	oversizedLearnings(t, cfg)
+
+	summaries := 0
+	validate := o.ValRunner.(*MockOrchestratorAIRunner).RunFunc
+	o.ValRunner.(*MockOrchestratorAIRunner).RunFunc = func(ctx context.Context, p string, outputPath string) error {
+		if strings.Contains(p, "RALPH_LEARNINGS_DIGEST") {
+			summaries++
+			return os.WriteFile(outputPath, []byte("RALPH_LEARNINGS_DIGEST\n```markdown\n- Run the linter\n```\n"), 0644)
+		}
+		return validate(ctx, p, outputPath)
+	}
+
+	o.Run(context.Background())
+	require.NotEmpty(t, implRunner.PromptLog)
+	assert.Equal(t, 1, summaries)
+	assert.Contains(t, implRunner.PromptLog[0], "- Run the linter")
+	assert.NotContains(t, implRunner.PromptLog[0], "Remember to run the linter")
+
+	_, ok := learnings.ReadDigest(learnings.DigestPath(cfg.LearningsFile), learnings.ReadLearnings(cfg.LearningsFile))
+	assert.True(t, ok, "the digest is cached beside the learnings file")
+}
+
+func TestBoundLearnings_ReusesCachedDigest(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.LearningsMaxTokens = 50
+	o := newTestOrchestrator(t, cfg, t.TempDir(), nil, nil)
+	content := oversizedLearnings(t, cfg)
+	require.NoError(t, learnings.WriteDigest(learnings.DigestPath(cfg.LearningsFile), content, "- cached digest"))
+
+	valRunner := o.ValRunner.(*MockOrchestratorAIRunner)
+	assert.Equal(t, "- cached digest\n", o.boundLearnings(context.Background(), content))
+	assert.Equal(t, 0, valRunner.CallCount)
+}
+
+func TestBoundLearnings_FallsBackToRecentEntries(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.LearningsMaxTokens = 50
+	o := newTestOrchestrator(t, cfg, t.TempDir(), nil, nil)
+	content := oversizedLearnings(t, cfg)
+	o.ValRunner.(*MockOrchestratorAIRunner).RunFunc = func(ctx context.Context, p string, outputPath string) error {
+		return os.WriteFile(outputPath, []byte("I could not summarize."), 0644)
+	}
+
+	got := o.boundLearnings(context.Background(), content)
+	assert.True(t, strings.HasPrefix(got, "(older learnings omitted)"))
+	assert.LessOrEqual(t, len(got), 50*4+len("(older learnings omitted)\n\n"))
+}
+
+func TestBoundLearnings_UnderLimitOrDisabled(t *testing.T) {
+	o := NewOrchestrator(config.NewDefaultConfig())
+	assert.Equal(t, "- small", o.boundLearnings(context.Background(), "- small"))
+
+	o.Config.LearningsMaxTokens = 0
+	big := strings.Repeat("x", 100000)
+	assert.Equal(t, big, o.boundLearnings(context.Background(), big))
+}
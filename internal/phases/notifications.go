@@ -0,0 +1,71 @@
+package phases
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/notification"
+)
+
+// notifications returns the notifier of the run, set up from NOTIFY_EVENTS
+// and NOTIFY_DIGEST on first use. An invalid NOTIFY_EVENTS, rejected when
+// the iteration loop starts, falls back to the default events.
+func (o *Orchestrator) notifications() *notification.Notifier {
+	if o.notifier == nil {
+		events, err := notification.ParseEvents(o.Config.NotifyEvents)
+		if err != nil {
+			events, _ = notification.ParseEvents(notification.DefaultEvents)
+		}
+		o.notifier = notification.NewNotifier(o.send, events, o.Config.NotifyDigest)
+	}
+	return o.notifier
+}
+
+// send delivers a notification to the configured target, read at send
+// time so a reloaded config applies at once.
+func (o *Orchestrator) send(message string) {
+	if o.sendMessage != nil {
+		o.sendMessage(message)
+		return
+	}
+	notification.SendNotification(o.Config.NotifyWebhook, o.Config.NotifyChannel, o.Config.NotifyChatID, message)
+}
+
+// notifyProgress sends a progress event, which does not end the run, if
+// NOTIFY_EVENTS selects it; NOTIFY_DIGEST may batch it with others.
+func (o *Orchestrator) notifyProgress(event, detail string) {
+	if o.session == nil {
+		return
+	}
+	o.notifications().Notify(event, notification.FormatProgress(event, o.projectName(), o.session.SessionID, o.session.Iteration, detail))
+}
+
+// flushNotifications sends the progress events still batched for the
+// digest, so none is lost when the process exits.
+func (o *Orchestrator) flushNotifications() {
+	if o.notifier != nil {
+		o.notifier.Flush()
+	}
+}
+
+// applyNotifyPolicy hands a reloaded NOTIFY_EVENTS and NOTIFY_DIGEST to
+// the notifier; an invalid event list keeps the current policy.
+func (o *Orchestrator) applyNotifyPolicy() {
+	events, err := notification.ParseEvents(o.Config.NotifyEvents)
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Invalid NOTIFY_EVENTS, keeping the current notifications: %v", err))
+		return
+	}
+	o.notifications().SetPolicy(events, o.Config.NotifyDigest)
+}
+
+// projectName names the project in notifications after the directory of
+// the tasks file.
+func (o *Orchestrator) projectName() string {
+	name := filepath.Base(filepath.Dir(o.session.TasksFile))
+	if name == "." || name == "" {
+		return "ralph-loop"
+	}
+	return name
+}
@@ -0,0 +1,97 @@
+package phases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/ai"
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/notification"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+func TestOrchestrator_NotifiesOnlyExitEventsByDefault(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 2
+	o := newTestOrchestrator(t, cfg, t.TempDir(), nil, needsMoreWorkValidator())
+	var sent []string
+	o.sendMessage = func(message string) { sent = append(sent, message) }
+	assert.Equal(t, exitcode.MaxIterations, o.Run(context.Background()))
+	require.Len(t, sent, 1)
+	assert.Contains(t, sent[0], "reached max iterations")
+}
+
+func TestOrchestrator_NotifiesIterations(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.NotifyEvents = "iteration,escalate"
+	cfg.MaxIterations = 2
+	o := newTestOrchestrator(t, cfg, t.TempDir(), nil, needsMoreWorkValidator())
+	var sent []string
+	o.sendMessage = func(message string) { sent = append(sent, message) }
+	assert.Equal(t, exitcode.MaxIterations, o.Run(context.Background()))
+	require.Len(t, sent, 2, "the max_iterations event is not selected")
+	assert.Contains(t, sent[0], "iteration 1: NEEDS_MORE_WORK, 0% (0/1 tasks), ETA unknown")
+	assert.Contains(t, sent[1], "iteration 2: NEEDS_MORE_WORK")
+}
+
+func TestOrchestrator_NotifyDigest(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.NotifyEvents = "all"
+	cfg.NotifyDigest = time.Hour
+	cfg.MaxIterations = 2
+	o := newTestOrchestrator(t, cfg, t.TempDir(), nil, needsMoreWorkValidator())
+	var sent []string
+	o.sendMessage = func(message string) { sent = append(sent, message) }
+	assert.Equal(t, exitcode.MaxIterations, o.Run(context.Background()))
+	require.Len(t, sent, 2)
+	assert.Contains(t, sent[0], "2 update(s)")
+	assert.Contains(t, sent[0], "iteration 1: NEEDS_MORE_WORK")
+	assert.Contains(t, sent[1], "reached max iterations")
+}
+
+func TestOrchestrator_InvalidNotifyEvents(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.NotifyEvents = "finished"
+	o := newTestOrchestrator(t, cfg, t.TempDir(), nil, needsMoreWorkValidator())
+	assert.Equal(t, exitcode.Error, o.Run(context.Background()))
+}
+
+func TestRecordRetry_NotifiesRateLimit(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.NotifyEvents = "rate_limited"
+	o := NewOrchestrator(cfg)
+	o.session = &state.SessionState{SessionID: "s1", Iteration: 3, TasksFile: "proj/tasks.md"}
+	var sent []string
+	o.sendMessage = func(message string) { sent = append(sent, message) }
+
+	limited := errors.New("rate limit")
+	o.RecordRetry(ai.RetryEvent{Class: ai.ClassRateLimit, Err: limited, Attempt: 1, Delay: 60})
+	o.RecordRetry(ai.RetryEvent{Class: ai.ClassRateLimit, Err: limited, Attempt: 2, Delay: 60})
+	o.RecordRetry(ai.RetryEvent{Class: ai.ClassTransient, Err: errors.New("exit status 1"), Attempt: 1, Delay: 5})
+	assert.Equal(t, []string{notification.FormatProgress(notification.EventRateLimited, "proj", "s1", 3, "waiting 60s before retrying")}, sent)
+}
+
+func TestApplyReload_NotifyPolicy(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	o := NewOrchestrator(cfg)
+	o.session = &state.SessionState{SessionID: "s1", TasksFile: "tasks.md"}
+	var sent []string
+	o.sendMessage = func(message string) { sent = append(sent, message) }
+
+	o.notifyProgress(notification.EventIteration, "NEEDS_MORE_WORK")
+	assert.Empty(t, sent)
+
+	fresh := config.NewDefaultConfig()
+	fresh.NotifyEvents = "progress"
+	o.ReloadConfig = func() (*config.Config, error) { return fresh, nil }
+	o.RequestReload()
+	o.applyReload()
+	o.notifyProgress(notification.EventIteration, "NEEDS_MORE_WORK")
+	assert.Len(t, sent, 1)
+}
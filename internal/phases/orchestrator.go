@@ -3,23 +3,34 @@ package phases
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/CodexForgeBR/cli-tools/internal/ai"
 	"github.com/CodexForgeBR/cli-tools/internal/banner"
 	"github.com/CodexForgeBR/cli-tools/internal/config"
 	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/extval"
 	ghissue "github.com/CodexForgeBR/cli-tools/internal/github"
+	"github.com/CodexForgeBR/cli-tools/internal/issues"
 	"github.com/CodexForgeBR/cli-tools/internal/learnings"
 	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/metrics"
 	"github.com/CodexForgeBR/cli-tools/internal/notification"
+	"github.com/CodexForgeBR/cli-tools/internal/policy"
 	"github.com/CodexForgeBR/cli-tools/internal/prompt"
 	"github.com/CodexForgeBR/cli-tools/internal/schedule"
 	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/statecrypt"
 	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+	"github.com/CodexForgeBR/cli-tools/internal/tracing"
+	"github.com/CodexForgeBR/cli-tools/internal/tui"
 )
 
 // CommandChecker is a function type that checks tool availability.
@@ -36,36 +47,221 @@ type Orchestrator struct {
 	FinalPlanRunner ai.AIRunner
 	TasksValRunner  ai.AIRunner
 	CommandChecker  CommandChecker
-	session         *state.SessionState
-	startTime       time.Time
-	resumed         bool
+	// SummaryRunner summarizes implementation output too long for the
+	// validator (see implOutputSummarySection); ValRunner when nil.
+	SummaryRunner ai.AIRunner
+	// TriageRunner, when set, checks each iteration with a cheap model
+	// before validation, which is skipped when triage fails it (see
+	// runTriage).
+	TriageRunner ai.AIRunner
+	// ValQuorum, when it has more than one member, replaces ValRunner with
+	// a concurrent vote between validators (see RunValidationQuorum).
+	ValQuorum []QuorumMember
+	// ValRotation, when set, replaces ValRunner with one validator per
+	// iteration taken from it in turn (see rotatingValidator).
+	ValRotation []QuorumMember
+	// WorkDir is the project root RALPH_PATCH diffs are applied to in
+	// apply-patch mode; empty means the current directory. Only when it is
+	// set is the working tree snapshotted with git for the iteration diff
+	// and the escalation report.
+	WorkDir string
+	// CheckWorkspace enables the preflight that refuses to start a new
+	// session on a dirty working tree, a protected branch or an unfinished
+	// rebase or merge (see phasePreflight).
+	CheckWorkspace bool
+	// LearningsLibrary is the user-level learnings library merged into
+	// prompts (see learnings.GlobalDir); empty disables it.
+	LearningsLibrary string
+	// Metrics, when set, records iterations, verdicts and phase durations.
+	Metrics *metrics.Registry
+	// Tracer, when set, records the session as a trace with a span per
+	// iteration and phase.
+	Tracer *tracing.Tracer
+	// Dashboard, when set, is kept up to date with the loop's progress.
+	Dashboard *tui.Dashboard
+	// PRNumber is the open pull request for the current branch. When set,
+	// a summary comment is posted to it at every terminal state.
+	PRNumber int
+	// PRClient, when set, posts the summary comment over the GitHub REST
+	// API instead of the gh CLI.
+	PRClient *ghissue.Client
+	// Checks, when set, reports the session as a GitHub check run on the
+	// commit CheckSHA (GITHUB_CHECK).
+	Checks   *ghissue.Client
+	CheckSHA string
+	// ReloadConfig loads the configuration again for RequestReload; nil
+	// ignores reload requests.
+	ReloadConfig func() (*config.Config, error)
+	// Failover, when set, moves the calls of the primary AI provider to
+	// FALLBACK_AI once a health check finds the primary down (see
+	// phaseHealthCheck).
+	Failover *ai.Failover
+
+	session   *state.SessionState
+	startTime time.Time
+	resumed   bool
+	gates     []gateResult
+	lock      *state.Lock
+	// specKit records the spec-kit files wired into Config by wireSpecKit.
+	specKit *tasks.SpecDir
+	// diffBase is the working tree snapshot taken before the current
+	// iteration's implementation phase (see snapshotDiffBase).
+	diffBase string
+	// policies are the inadmissible practice policies of the run, loaded
+	// from POLICY_FILE when the iteration loop starts.
+	policies []policy.Policy
+	// externalValidators judge each iteration next to the validator,
+	// parsed from EXTERNAL_VALIDATORS when the iteration loop starts.
+	externalValidators []extval.Validator
+	// verify is what the VERIFY_CMDS reported for the current iteration;
+	// nil when none ran.
+	verify *verifyRun
+	// revertedPaths are the protected files whose changes the current
+	// iteration's implementation phase had reverted.
+	revertedPaths []string
+	// crossTriggers are the suspicious completions cross-validated even
+	// with cross-validation off, parsed from AUTO_CROSS_VALIDATE when the
+	// iteration loop starts.
+	crossTriggers CrossTriggers
+	// checkedBefore is the number of checked tasks before the current
+	// iteration's implementation phase; -1 when unknown, as on resume.
+	checkedBefore int
+	// checkedAtStart and implChecked are the IDs of the tasks checked
+	// before and after the current iteration's implementation phase, and
+	// implTasksHash the tasks file hash after it; nil and "" when unknown
+	// (see revertUnconfirmedChecks).
+	checkedAtStart map[string]bool
+	implChecked    map[string]bool
+	implTasksHash  string
+
+	// escalationMu guards escalationReason, set from other goroutines by
+	// RequestEscalation.
+	escalationMu     sync.Mutex
+	escalationReason string
+	// escalationReport is the path of the handoff report written when the
+	// loop escalates, listed in the escalation notification.
+	escalationReport string
+	// exit is how the loop stopped, recorded by notify.
+	exit *exitcode.Result
+	// checkRunID is the GitHub check run reporting this run (see
+	// startCheckRun); 0 when none.
+	checkRunID int64
+	// notifier filters and batches the notifications; see notifications.
+	notifier *notification.Notifier
+	// sendMessage, if set, replaces notification.SendNotification; for
+	// tests.
+	sendMessage func(message string)
+
+	// signalMu guards the signal state, set from the signal handler by
+	// RequestShutdown, RecordInterrupt and RequestReload.
+	signalMu        sync.Mutex
+	shutdownSignal  string
+	graceful        bool
+	reloadRequested bool
+
+	// pausedFor is the time spent paused by `ralph-loop pause` since the
+	// last iteration started, left out of the iteration average.
+	pausedFor time.Duration
+	// pauseSkippedUntil is the end of the PAUSE_BETWEEN window cut short by
+	// `ralph-loop resume-now`.
+	pauseSkippedUntil time.Time
+
+	// retryMu guards the session's RetryState and Failover, updated by
+	// RecordRetry and RecordFailover from the runners' goroutines.
+	retryMu sync.Mutex
 }
 
 // NewOrchestrator creates a new orchestrator with the given config.
 func NewOrchestrator(cfg *config.Config) *Orchestrator {
 	return &Orchestrator{
-		Config:   cfg,
-		StateDir: ".ralph-loop",
+		Config:        cfg,
+		StateDir:      ".ralph-loop",
+		checkedBefore: -1,
 	}
 }
 
+// RequestEscalation asks the loop to stop with exit code Escalate at the
+// next iteration boundary. It is safe to call from any goroutine.
+func (o *Orchestrator) RequestEscalation(reason string) {
+	o.escalationMu.Lock()
+	defer o.escalationMu.Unlock()
+	o.escalationReason = reason
+}
+
+func (o *Orchestrator) requestedEscalation() string {
+	o.escalationMu.Lock()
+	defer o.escalationMu.Unlock()
+	return o.escalationReason
+}
+
 // Run executes the 10-phase orchestration loop and returns an exit code.
 func (o *Orchestrator) Run(ctx context.Context) int {
+	ctx, span := o.Tracer.Start(ctx, "ralph-loop.session",
+		tracing.String("ralph.ai_provider", o.Config.AIProvider),
+		tracing.String("ralph.impl_model", o.Config.ImplModel),
+		tracing.String("ralph.val_model", o.Config.ValModel),
+	)
+	code := o.run(ctx)
+	res := o.exitResult(code)
+	o.saveSessionOutcome()
+	o.finishCheckRun(res)
+	o.runOnExitHook(res)
+	o.printSpecSummary()
+	if o.session != nil {
+		o.sealIteration(o.session.Iteration)
+		o.pruneIterations(o.session.Iteration)
+		span.SetAttributes(
+			tracing.String("ralph.session_id", o.session.SessionID),
+			tracing.Int("ralph.iterations", o.session.Iteration),
+			tracing.String("ralph.tasks_file", o.session.TasksFile),
+		)
+	}
+	span.SetAttributes(tracing.Int("ralph.exit_code", code))
+	if code != exitcode.Success {
+		span.RecordError(fmt.Errorf("exited with %s", res))
+	}
+	span.End()
+	return code
+}
+
+func (o *Orchestrator) run(ctx context.Context) int {
 	o.startTime = time.Now()
+	defer o.releaseLock()
+	defer o.recordShutdownSignal()
+	defer o.flushNotifications()
 
 	// Phase 1: Init
 	if code := o.phaseInit(); code >= 0 {
 		return code
 	}
+	o.startCheckRun()
 
 	// Phase 2: Command checks
 	if code := o.phaseCommandChecks(); code >= 0 {
 		return code
 	}
 
+	// Phase 2a: Container image (CONTAINER_IMAGE)
+	if code := o.phaseContainer(ctx); code >= 0 {
+		return code
+	}
+	if o.container() != nil && o.Config.Replay == "" {
+		defer o.pruneContainers(context.Background())
+	}
+
+	// Phase 2b: Workspace safety checks
+	if code := o.phasePreflight(); code >= 0 {
+		return code
+	}
+
 	// Phase 3: Banner
 	o.phaseBanner()
 
+	// Phase 3b: Plan from issue (--plan-from-issue)
+	if code := o.phasePlanFromIssue(ctx); code >= 0 {
+		return code
+	}
+
 	// Phase 4: Find tasks
 	if code := o.phaseFindTasks(); code >= 0 {
 		return code
@@ -81,6 +277,9 @@ func (o *Orchestrator) Run(ctx context.Context) int {
 		return code
 	}
 
+	// Phase 6b: Health check of the primary AI (FALLBACK_AI)
+	o.phaseHealthCheck(ctx)
+
 	// Phase 7: Fetch issue
 	o.phaseFetchIssue()
 
@@ -106,6 +305,24 @@ func (o *Orchestrator) phaseInit() int {
 		return exitcode.Error
 	}
 
+	// --status only reads the state, so it works while a loop is running
+	if !o.Config.Status {
+		lock, err := state.AcquireLock(o.StateDir, o.Config.ForceUnlock)
+		if err != nil {
+			logging.Error(fmt.Sprintf("Cannot start: %v", err))
+			var locked *state.LockedError
+			if errors.As(err, &locked) {
+				logging.Info("Stop the other loop, or pass --force-unlock if it is no longer running.")
+			}
+			return exitcode.Error
+		}
+		o.lock = lock
+		// Pause and resume requests are for the loop that was running
+		if err := state.ClearControl(o.StateDir); err != nil {
+			logging.Warn(err.Error())
+		}
+	}
+
 	// Check if we're resuming an existing session
 	// This happens early to avoid creating a new session when resuming
 	if o.Config.Resume || o.Config.ResumeForce {
@@ -114,6 +331,11 @@ func (o *Orchestrator) phaseInit() int {
 		return -1
 	}
 
+	// A model ladder starts implementation on its cheapest rung
+	if ladder := o.Config.ModelLadder(); len(ladder) > 0 {
+		o.Config.ImplModel = ladder[0]
+	}
+
 	// Create new session
 	sessionID := fmt.Sprintf("ralph-%s", time.Now().Format("20060102-150405"))
 	o.session = &state.SessionState{
@@ -139,20 +361,62 @@ func (o *Orchestrator) phaseInit() int {
 			Model:   o.Config.CrossModel,
 		},
 	}
+	// The PR summary shows the coverage change against this baseline
+	o.session.CoverageBaseline = o.readCoverage()
 
 	return -1 // continue
 }
 
+// releaseLock releases the state lock taken in phaseInit, if any.
+func (o *Orchestrator) releaseLock() {
+	if err := o.lock.Release(); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to release state lock: %v", err))
+	}
+	o.lock = nil
+}
+
 func (o *Orchestrator) phaseCommandChecks() int {
 	logging.Phase("Checking required commands")
-	// Check availability of primary AI tool
 	checker := o.CommandChecker
 	if checker == nil {
 		checker = ai.CheckAvailability
 	}
-	avail := checker(o.Config.AIProvider)
-	if !avail[o.Config.AIProvider] {
-		logging.Error(fmt.Sprintf("Required tool not found: %s", o.Config.AIProvider))
+	// Check the AI of every phase that always runs; main disables cross
+	// and final-plan validation when theirs is missing. With a sandbox or
+	// a container the AI CLIs run inside it, so only the sandbox program or
+	// container runtime has to exist on the host. Implementation jobs on
+	// Kubernetes need kubectl instead of the implementation AI. A remote
+	// agent needs nothing here.
+	cfg := o.Config
+	if cfg.RemoteAgent != "" {
+		return -1
+	}
+	var tools []string
+	if cfg.K8sJobImage != "" {
+		tools = append(tools, "kubectl")
+	}
+	if sb := (&ai.Sandbox{Command: cfg.SandboxCmd, Container: o.container()}).Program(); sb != "" {
+		tools = append(tools, sb)
+	} else {
+		phaseAIs := []string{cfg.AIProvider, cfg.ValAI, cfg.TasksValAI, cfg.SummaryAI}
+		if cfg.K8sJobImage != "" {
+			phaseAIs = phaseAIs[1:]
+		}
+		for _, tool := range phaseAIs {
+			if tool != "" && !slices.Contains(tools, tool) {
+				tools = append(tools, tool)
+			}
+		}
+	}
+	avail := checker(tools...)
+	for _, tool := range tools {
+		if avail[tool] {
+			continue
+		}
+		if cmd := ai.Command(tool); cmd != tool {
+			tool = fmt.Sprintf("%s (the %s command)", tool, cmd)
+		}
+		logging.Error(fmt.Sprintf("Required tool not found: %s", tool))
 		return exitcode.Error
 	}
 	return -1
@@ -181,7 +445,26 @@ func (o *Orchestrator) phaseFindTasks() int {
 	logging.Phase("Finding tasks file")
 
 	tasksFile := o.Config.TasksFile
-	if tasksFile == "" {
+	if tasksFile == "" && o.Config.SpecDir != "" {
+		sd, err := tasks.LoadSpecDir(o.Config.SpecDir)
+		if err != nil {
+			logging.Error(fmt.Sprintf("No tasks file found: %v", err))
+			return exitcode.Error
+		}
+		tasksFile = sd.TasksFile
+	}
+	if o.multiSpec() {
+		first, err := o.findSpecs()
+		if err != nil {
+			logging.Error(fmt.Sprintf("No tasks file found: %v", err))
+			return exitcode.Error
+		}
+		if first == "" {
+			logging.Success("All tasks already checked!")
+			return exitcode.Success
+		}
+		tasksFile = first
+	} else if tasksFile == "" {
 		discovered, err := tasks.DiscoverTasksFile("")
 		if err != nil {
 			logging.Error(fmt.Sprintf("No tasks file found: %v", err))
@@ -199,14 +482,21 @@ func (o *Orchestrator) phaseFindTasks() int {
 
 	o.Config.TasksFile = absPath
 	o.session.TasksFile = absPath
+	o.wireSpecKit(absPath)
 
-	// Compute hash
-	hash, err := tasks.HashFile(absPath)
-	if err != nil {
+	// Compute hash and snapshot the file as the base of a resume merge
+	if err := o.snapshotTasks(true); err != nil {
 		logging.Error(fmt.Sprintf("Failed to hash tasks file: %v", err))
 		return exitcode.Error
 	}
-	o.session.TasksFileHash = hash
+
+	// Seed per-task tracking from the tasks file
+	o.syncTasks()
+
+	if err := o.checkDependencies(); err != nil {
+		logging.Error(fmt.Sprintf("Invalid task dependencies in %s: %v", absPath, err))
+		return exitcode.Error
+	}
 
 	// Check unchecked count
 	unchecked, err := tasks.CountUnchecked(absPath)
@@ -246,7 +536,14 @@ func (o *Orchestrator) phaseResumeCheck() int {
 				CrossModel:        existing.CrossValidation.Model,
 				RetryAttempt:      existing.RetryState.Attempt,
 				RetryDelay:        existing.RetryState.Delay,
+				Retries:           retryInfo(existing.RetryState),
 				LastFeedback:      existing.LastFeedback,
+				Tasks:             taskInfos(existing.Tasks),
+				Escalations:       escalationInfos(existing.ModelEscalation.Events),
+				Failover:          failoverInfo(existing.Failover),
+				Progress:          progressInfo(existing),
+				Validators:        validatorInfos(existing.Validators),
+				Artifacts:         artifactInfos(existing.Artifacts),
 			})
 		} else {
 			logging.Info("No active session found.")
@@ -254,15 +551,33 @@ func (o *Orchestrator) phaseResumeCheck() int {
 		return exitcode.Success
 	}
 
-	// Handle --clean flag: remove state directory and start fresh
+	// Handle --clean flag: empty the state directory, but for the outcomes
+	// of earlier sessions, and start fresh
 	if o.Config.Clean {
 		logging.Info("Cleaning state directory...")
-		if err := os.RemoveAll(o.StateDir); err != nil {
-			logging.Warn(fmt.Sprintf("Failed to remove state directory: %v", err))
+		entries, err := os.ReadDir(o.StateDir)
+		if err != nil && !os.IsNotExist(err) {
+			logging.Warn(fmt.Sprintf("Failed to read state directory: %v", err))
+		}
+		for _, e := range entries {
+			if e.Name() == state.SessionsDir {
+				continue
+			}
+			if err := os.RemoveAll(filepath.Join(o.StateDir, e.Name())); err != nil {
+				logging.Warn(fmt.Sprintf("Failed to remove %s from the state directory: %v", e.Name(), err))
+			}
 		}
 		if err := state.InitStateDir(o.StateDir); err != nil {
 			logging.Warn(fmt.Sprintf("Failed to re-init state dir after clean: %v", err))
 		}
+		// The lock file went with the state directory; take it again.
+		if o.lock != nil {
+			lock, err := state.AcquireLock(o.StateDir, true)
+			if err != nil {
+				logging.Warn(fmt.Sprintf("Failed to re-acquire state lock after clean: %v", err))
+			}
+			o.lock = lock
+		}
 	}
 
 	// Handle --cancel flag: mark session as cancelled and exit
@@ -306,10 +621,17 @@ func (o *Orchestrator) phaseResumeCheck() int {
 		if !cli["MAX_INADMISSIBLE"] {
 			o.Config.MaxInadmissible = existing.MaxInadmissible
 		}
-		if o.Config.TasksFile == "" {
+		if o.Config.TasksFile == "" || len(existing.Specs) > 0 {
+			// A multi-file session resumes on the spec it was working on
 			o.Config.TasksFile = existing.TasksFile
 		}
 
+		// Merge edits made to the tasks file while the loop was stopped
+		// instead of rejecting the resume
+		if !o.Config.ResumeForce {
+			o.mergeTasksEdits(existing, o.Config.TasksFile)
+		}
+
 		// Resume from existing state
 		err = state.ResumeFromState(existing, o.Config.TasksFile, o.Config.ResumeForce)
 		if err != nil {
@@ -319,7 +641,10 @@ func (o *Orchestrator) phaseResumeCheck() int {
 
 		// Replace the session with the resumed one
 		o.session = existing
+		o.session.Exit = nil
+		o.session.ShutdownSignal = ""
 		o.resumed = true
+		o.wireSpecKit(existing.TasksFile)
 		o.Config.EnableLearnings = existing.Learnings.Enabled == 1
 		o.Config.LearningsFile = existing.Learnings.File
 		o.Config.CrossValidate = existing.CrossValidation.Enabled == 1
@@ -375,37 +700,173 @@ func (o *Orchestrator) phaseValidateSetup() int {
 }
 
 func (o *Orchestrator) phaseFetchIssue() {
-	if o.resumed || o.Config.GithubIssue == "" {
+	if o.resumed || (o.Config.GithubIssue == "" && o.Config.JiraIssue == "") {
+		return
+	}
+	// --plan-from-issue already fetched it
+	if o.session.GithubIssue != nil || o.session.JiraIssue != nil {
 		return
 	}
 
-	logging.Phase("Fetching GitHub issue")
+	logging.Phase("Fetching issue")
 
-	owner, repo, number, err := ghissue.ParseIssueRef(o.Config.GithubIssue)
-	if err != nil {
-		logging.Warn(fmt.Sprintf("Failed to parse issue ref: %v", err))
+	if _, err := o.fetchIssue(); err != nil {
+		logging.Warn(fmt.Sprintf("Continuing without the issue: %v", err))
+	}
+}
+
+// Cached issue documents in the state dir.
+const (
+	githubIssueFile = "github-issue.md"
+	jiraIssueFile   = "jira-issue.md"
+)
+
+// specFile returns the document tasks are checked against: a spec-kit
+// spec.md, the original plan, or the cached --github-issue or --jira-issue. Returns "" when none
+// is configured.
+func (o *Orchestrator) specFile() string {
+	switch {
+	case o.Config.SpecFile != "":
+		return o.Config.SpecFile
+	case o.Config.OriginalPlanFile != "":
+		return o.Config.OriginalPlanFile
+	case o.Config.GithubIssue != "":
+		return filepath.Join(o.StateDir, githubIssueFile)
+	case o.Config.JiraIssue != "":
+		return filepath.Join(o.StateDir, jiraIssueFile)
+	}
+	return ""
+}
+
+// wireSpecKit picks up spec.md and plan.md from the spec-kit folder holding
+// tasksFile, unless an issue is the spec. An explicit --original-plan-file
+// is kept. Files wired for a previous tasks file are dropped first, so a
+// multi-file session validates each spec against its own documents.
+func (o *Orchestrator) wireSpecKit(tasksFile string) {
+	if prev := o.specKit; prev != nil {
+		if o.Config.SpecFile == prev.SpecFile {
+			o.Config.SpecFile = ""
+		}
+		if o.Config.OriginalPlanFile == prev.PlanFile {
+			o.Config.OriginalPlanFile = ""
+		}
+		o.specKit = nil
+	}
+	if o.Config.GithubIssue != "" || o.Config.JiraIssue != "" {
 		return
 	}
+	sd := tasks.SpecDirFor(tasksFile)
+	if sd == nil {
+		return
+	}
+	wired := &tasks.SpecDir{Dir: sd.Dir, TasksFile: sd.TasksFile}
+	if o.Config.SpecFile == "" && sd.SpecFile != "" {
+		o.Config.SpecFile = sd.SpecFile
+		wired.SpecFile = sd.SpecFile
+	}
+	if o.Config.OriginalPlanFile == "" && sd.PlanFile != "" {
+		o.Config.OriginalPlanFile = sd.PlanFile
+		wired.PlanFile = sd.PlanFile
+	}
+	if wired.SpecFile != "" || wired.PlanFile != "" {
+		o.specKit = wired
+		logging.Info(fmt.Sprintf("Using spec-kit folder %s", sd.Dir))
+	}
+}
 
-	content, err := ghissue.FetchIssue(owner, repo, number)
+// fetchIssue fetches --github-issue from its provider, or --jira-issue from
+// Jira, caches the normalized document in the state dir (see specFile) and
+// records the reference on the session.
+func (o *Orchestrator) fetchIssue() (*issues.Issue, error) {
+	issue, err := o.downloadIssue()
 	if err != nil {
-		logging.Warn(fmt.Sprintf("Failed to fetch issue: %v", err))
-		return
+		return nil, err
 	}
 
 	// Cache issue content in state dir
-	if err := ghissue.CacheIssue(o.StateDir, content); err != nil {
-		logging.Warn(fmt.Sprintf("Failed to cache issue: %v", err))
-		return
+	if err := os.MkdirAll(o.StateDir, 0755); err != nil {
+		return nil, fmt.Errorf("cache issue: %w", err)
+	}
+	if err := os.WriteFile(o.specFile(), []byte(issue.Document()), 0644); err != nil {
+		return nil, fmt.Errorf("cache issue: %w", err)
 	}
 
-	issueRef := o.Config.GithubIssue
-	o.session.GithubIssue = &issueRef
-	if owner != "" {
-		logging.Info(fmt.Sprintf("Fetched and cached issue %s/%s#%d", owner, repo, number))
+	if o.Config.JiraIssue != "" {
+		issueRef := o.Config.JiraIssue
+		o.session.JiraIssue = &issueRef
 	} else {
-		logging.Info(fmt.Sprintf("Fetched and cached issue #%d", number))
+		issueRef := o.Config.GithubIssue
+		o.session.GithubIssue = &issueRef
+	}
+	logging.Info(fmt.Sprintf("Fetched and cached %s issue %s", issue.Ref.Provider, issue.Ref))
+	return issue, nil
+}
+
+// downloadIssue fetches --jira-issue from Jira, or --github-issue from its
+// provider.
+func (o *Orchestrator) downloadIssue() (*issues.Issue, error) {
+	if o.Config.JiraIssue != "" {
+		return issues.FetchJira(o.Config.JiraIssue)
 	}
+	return issues.Fetch(o.Config.GithubIssue, o.Config.IssueProvider)
+}
+
+// phasePlanFromIssue generates plan.md and tasks.md from the issue before
+// the tasks file is looked up. The tasks go to --tasks-file when given,
+// otherwise to specs/issue-<number>/tasks.md (specs/PROJ-123/tasks.md for
+// Jira); an existing tasks file is never overwritten. The generated tasks
+// are then checked against the issue by the regular tasks validation phase.
+func (o *Orchestrator) phasePlanFromIssue(ctx context.Context) int {
+	if o.session == nil || !o.Config.PlanFromIssue {
+		return -1
+	}
+
+	logging.Phase("Planning from issue")
+
+	issue, err := o.fetchIssue()
+	if err != nil {
+		logging.Error(fmt.Sprintf("Cannot plan without the issue: %v", err))
+		return exitcode.Error
+	}
+
+	tasksFile := o.Config.TasksFile
+	if tasksFile == "" && o.Config.SpecDir != "" {
+		tasksFile = filepath.Join(o.Config.SpecDir, "tasks.md")
+	}
+	if tasksFile == "" {
+		dir := fmt.Sprintf("issue-%d", issue.Ref.Number)
+		if issue.Ref.Provider == issues.Jira {
+			dir = issue.Ref.String()
+		}
+		tasksFile = filepath.Join("specs", dir, "tasks.md")
+	}
+	if _, err := os.Stat(tasksFile); err == nil {
+		logging.Error(fmt.Sprintf("Tasks file %s already exists; remove it or pass another --tasks-file", tasksFile))
+		return exitcode.Error
+	}
+	planFile := filepath.Join(filepath.Dir(tasksFile), "plan.md")
+
+	outputPath := filepath.Join(o.StateDir, "plan-from-issue-output.txt")
+	o.Dashboard.SetPhase("planning")
+	o.Dashboard.WatchOutput(outputPath)
+	planCtx, planSpan := tracing.Start(ctx, "plan_from_issue")
+	err = RunPlanFromIssue(planCtx, PlanFromIssueConfig{
+		Runner:     o.ImplRunner,
+		IssueFile:  o.specFile(),
+		OutputPath: outputPath,
+		PlanFile:   planFile,
+		TasksFile:  tasksFile,
+	})
+	planSpan.RecordError(err)
+	planSpan.End()
+	if err != nil {
+		logging.Error(fmt.Sprintf("Planning from issue failed: %v", err))
+		return exitcode.Error
+	}
+
+	logging.Success(fmt.Sprintf("Generated %s and %s", planFile, tasksFile))
+	o.Config.TasksFile = tasksFile
+	return -1
 }
 
 func (o *Orchestrator) phaseTasksValidation(ctx context.Context) int {
@@ -413,7 +874,8 @@ func (o *Orchestrator) phaseTasksValidation(ctx context.Context) int {
 		return -1
 	}
 
-	if o.Config.OriginalPlanFile == "" && o.Config.GithubIssue == "" {
+	specFile := o.specFile()
+	if specFile == "" {
 		return -1
 	}
 
@@ -424,17 +886,20 @@ func (o *Orchestrator) phaseTasksValidation(ctx context.Context) int {
 
 	logging.Phase("Validating tasks against plan")
 
-	specFile := o.Config.OriginalPlanFile
-	if specFile == "" {
-		// Use cached issue as spec
-		specFile = filepath.Join(o.StateDir, "github-issue.md")
-	}
-
-	result := RunTasksValidation(ctx, TasksValidationConfig{
+	tvCtx, tvSpan := tracing.Start(ctx, gateTasksValidation)
+	result := RunTasksValidation(tvCtx, TasksValidationConfig{
 		Runner:    o.TasksValRunner,
 		SpecFile:  specFile,
 		TasksFile: o.session.TasksFile,
 	})
+	tvSpan.SetAttributes(tracing.String("ralph.action", result.Action))
+	tvSpan.End()
+
+	if result.Action == "success" {
+		o.recordGate(gateTasksValidation, "VALID")
+	} else if result.Action == "exit" {
+		o.recordGate(gateTasksValidation, "INVALID")
+	}
 
 	switch result.Action {
 	case "success":
@@ -442,7 +907,7 @@ func (o *Orchestrator) phaseTasksValidation(ctx context.Context) int {
 		return -1
 	case "exit":
 		logging.Error(fmt.Sprintf("Tasks validation failed: %s", result.Feedback))
-		o.notify(notification.EventTasksInvalid, exitcode.TasksInvalid)
+		o.notify(notification.EventTasksInvalid, exitcode.Result{Code: exitcode.TasksInvalid, Reason: feedbackReason(result.Feedback)})
 		return exitcode.TasksInvalid
 	default:
 		return -1
@@ -488,17 +953,23 @@ func (o *Orchestrator) phaseScheduleWait(ctx context.Context) int {
 
 	logging.Phase("Waiting for scheduled start time")
 
-	if err := schedule.WaitUntil(ctx, target); err != nil {
-		if ctx.Err() != nil {
-			banner.PrintInterruptedBanner(o.session.Iteration, o.session.Phase)
-			o.notify(notification.EventInterrupted, exitcode.Interrupted)
-			if saveErr := state.SaveState(o.session, o.StateDir); saveErr != nil {
-				logging.Warn(fmt.Sprintf("Failed to save interrupted state: %v", saveErr))
-			}
-			return exitcode.Interrupted
+	woken, err := o.waitUntil(ctx, target)
+	if err != nil {
+		banner.PrintInterruptedBanner(o.session.Iteration, o.session.Phase)
+		o.notify(notification.EventInterrupted, exitcode.Result{Code: exitcode.Interrupted, Reason: o.interruptReason("interrupted while waiting for the scheduled start")})
+		if saveErr := state.SaveState(o.session, o.StateDir); saveErr != nil {
+			logging.Warn(fmt.Sprintf("Failed to save interrupted state: %v", saveErr))
 		}
-		logging.Error(fmt.Sprintf("Schedule wait failed: %v", err))
-		return exitcode.Error
+		return exitcode.Interrupted
+	}
+	if code := o.stopForShutdown("while waiting for the scheduled start"); code >= 0 {
+		return code
+	}
+	if woken {
+		// A resumed session must not wait for the original time again.
+		o.session.Schedule = state.ScheduleState{}
+		logging.Success("Woken by ralph-loop resume-now, starting iteration loop")
+		return -1
 	}
 
 	logging.Success("Schedule wait complete, starting iteration loop")
@@ -507,91 +978,303 @@ func (o *Orchestrator) phaseScheduleWait(ctx context.Context) int {
 
 func (o *Orchestrator) phaseIterationLoop(ctx context.Context) int {
 	logging.Phase("Starting iteration loop")
+	o.applyImplModel()
+
+	cp := o.resumeCheckpoint()
+	if cp != nil {
+		// Re-enter the interrupted iteration instead of starting the next one.
+		o.session.Iteration = cp.Iteration - 1
+	}
+
+	o.Dashboard.SetSession(o.session.SessionID, o.Config.AIProvider, o.Config.ImplModel, o.session.TasksFile)
+
+	// Each iteration runs under its own span; ctx is re-derived from
+	// loopCtx so iteration spans are siblings rather than nested.
+	loopCtx := ctx
+	var iterSpan *tracing.Span
+	defer func() { iterSpan.End() }()
+	// iterCtx bounds the implementation and validation phases by
+	// ITERATION_TIMEOUT; ctx itself is only cancelled by an interrupt.
+	iterCtx, cancelIter := context.WithCancel(ctx)
+	defer func() { cancelIter() }()
+
+	pauses, err := schedule.ParsePauseWindows(o.Config.PauseBetween)
+	if err != nil {
+		logging.Error(fmt.Sprintf("Invalid PAUSE_BETWEEN: %v", err))
+		return exitcode.Error
+	}
+	customVerdicts, err := ParseCustomVerdicts(o.Config.CustomVerdicts)
+	if err != nil {
+		logging.Error(fmt.Sprintf("Invalid CUSTOM_VERDICTS: %v", err))
+		return exitcode.Error
+	}
+	if o.policies, err = policy.Load(o.Config.PolicyFile); err != nil {
+		logging.Error(fmt.Sprintf("Invalid POLICY_FILE: %v", err))
+		return exitcode.Error
+	}
+	if o.externalValidators, err = extval.Parse(o.Config.ExternalValidators); err != nil {
+		logging.Error(fmt.Sprintf("Invalid EXTERNAL_VALIDATORS: %v", err))
+		return exitcode.Error
+	}
+	if o.crossTriggers, err = ParseCrossTriggers(o.Config.AutoCrossValidate); err != nil {
+		logging.Error(fmt.Sprintf("Invalid AUTO_CROSS_VALIDATE: %v", err))
+		return exitcode.Error
+	}
+	if _, err := notification.ParseEvents(o.Config.NotifyEvents); err != nil {
+		logging.Error(fmt.Sprintf("Invalid NOTIFY_EVENTS: %v", err))
+		return exitcode.Error
+	}
+	if o.specDriftEnabled() && !o.resumed {
+		o.resetSpecBaseline()
+	}
+	stopHeartbeat := o.startHeartbeat()
+	defer stopHeartbeat()
 
+	loopStart, started := time.Now(), 0
 	for o.session.Iteration < o.session.MaxIterations {
+		paused, code := o.waitPauseWindow(ctx, pauses)
+		if code >= 0 {
+			return code
+		}
+		// Time spent paused does not count towards the iteration average.
+		loopStart = loopStart.Add(paused + o.pausedFor)
+		o.pausedFor = 0
+		if code := o.checkBudget(loopStart, started); code >= 0 {
+			return code
+		}
+		started++
 		o.session.Iteration++
+		o.Metrics.IncIteration()
+		iterStart := time.Now()
+		o.iterationHeartbeat()
+		iterSpan.End()
+		ctx, iterSpan = tracing.Start(loopCtx, "iteration", tracing.Int("ralph.iteration", o.session.Iteration))
+		cancelIter()
+		iterCtx, cancelIter = withTimeout(ctx, o.Config.IterationTimeout)
+		o.Dashboard.SetIteration(o.session.Iteration, o.session.MaxIterations)
+		o.updateDashboardTasks()
+		resumeAt := ""
+		if cp != nil {
+			resumeAt = cp.Phase
+			cp = nil
+		}
 		o.session.LastUpdated = time.Now().Format(time.RFC3339)
 
 		logging.Info(fmt.Sprintf("=== Iteration %d/%d ===", o.session.Iteration, o.session.MaxIterations))
+		if eta, ok := sessionETA(o.session); ok && len(o.session.History) > 0 {
+			logging.Info("Progress: " + eta.String())
+		}
 
 		// Check for context cancellation
 		if ctx.Err() != nil {
 			banner.PrintInterruptedBanner(o.session.Iteration, o.session.Phase)
-			o.notify(notification.EventInterrupted, exitcode.Interrupted)
+			o.notify(notification.EventInterrupted, exitcode.Result{Code: exitcode.Interrupted, Reason: o.interruptReason(fmt.Sprintf("interrupted before iteration %d", o.session.Iteration))})
 			if err := state.SaveState(o.session, o.StateDir); err != nil {
 				logging.Warn(fmt.Sprintf("Failed to save interrupted state: %v", err))
 			}
 			return exitcode.Interrupted
 		}
-
-		// Save state before implementation
-		o.session.Phase = state.PhaseImplementation
-		if err := state.SaveState(o.session, o.StateDir); err != nil {
-			logging.Warn(fmt.Sprintf("Failed to save implementation state: %v", err))
+		if code := o.stopForShutdown(fmt.Sprintf("before iteration %d", o.session.Iteration)); code >= 0 {
+			return code
+		}
+		if code := o.pauseIfRequested(ctx, fmt.Sprintf("before iteration %d", o.session.Iteration)); code >= 0 {
+			return code
+		}
+		o.applyReload()
+		if code := o.checkSpecDrift(ctx); code >= 0 {
+			return code
 		}
 
-		// Run implementation
-		isFirst := o.session.Iteration == 1 && o.session.LastFeedback == ""
-		feedback := ""
-		if o.session.LastFeedback != "" {
-			decoded, err := base64.StdEncoding.DecodeString(o.session.LastFeedback)
-			if err == nil {
-				feedback = string(decoded)
-			} else {
-				feedback = o.session.LastFeedback
+		// Stop if escalation was requested from outside the loop
+		if reason := o.requestedEscalation(); reason != "" {
+			banner.PrintEscalationBanner(reason)
+			prevValOutput := filepath.Join(o.StateDir, fmt.Sprintf("iteration-%03d", o.session.Iteration-1), "validation-output.txt")
+			o.writeEscalationReport(reason, o.lastFeedback(), prevValOutput)
+			o.notify(notification.EventEscalate, exitcode.Result{Code: exitcode.Escalate, Reason: feedbackReason(reason)})
+			if err := state.SaveState(o.session, o.StateDir); err != nil {
+				logging.Warn(fmt.Sprintf("Failed to save escalate state: %v", err))
 			}
+			return exitcode.Escalate
 		}
 
-		// Build prompts
-		learningsText := learnings.ReadLearnings(o.Config.LearningsFile)
-		var implPrompt string
-		if isFirst {
-			implPrompt = prompt.BuildImplFirstPrompt(o.session.TasksFile, learningsText)
-		} else {
-			implPrompt = prompt.BuildImplContinuePrompt(o.session.TasksFile, feedback, learningsText)
-		}
+		o.compressIterationLogs(o.session.Iteration - 1)
+		o.compactIteration(o.session.Iteration - 1)
+		o.sealIteration(o.session.Iteration - 1)
+		o.pruneIterations(o.session.Iteration - 1)
 
-		// Create iteration directory
+		// Create iteration directory, decrypted when a resume reenters it
 		iterDir := filepath.Join(o.StateDir, fmt.Sprintf("iteration-%03d", o.session.Iteration))
 		if err := os.MkdirAll(iterDir, 0755); err != nil {
 			logging.Warn(fmt.Sprintf("Failed to create iteration dir: %v", err))
 		}
+		if err := statecrypt.OpenDir(iterDir); err != nil {
+			logging.Warn(fmt.Sprintf("Failed to decrypt iteration dir: %v", err))
+		}
 
-		// Run implementation phase
-		logging.Phase(fmt.Sprintf("Implementation phase - Iteration %d", o.session.Iteration))
-		logging.Info(fmt.Sprintf("AI CLI: %s", o.Config.AIProvider))
-		logging.Info(fmt.Sprintf("Model: %s", o.Config.ImplModel))
 		implOutputPath := filepath.Join(iterDir, "implementation-output.txt")
-		implConfig := ImplementationConfig{
-			Runner:           o.ImplRunner,
-			Iteration:        o.session.Iteration,
-			OutputPath:       implOutputPath,
-			FirstPrompt:      implPrompt,
-			ContinuePrompt:   implPrompt, // For consistency
-			ExtractLearnings: o.Config.EnableLearnings,
-		}
-
-		implResult, implErr := RunImplementationPhaseWithLearnings(ctx, implConfig)
-		if implErr != nil {
-			logging.Error(fmt.Sprintf("Implementation failed: %v", implErr))
-			// Check for context cancellation
-			if ctx.Err() != nil {
-				return exitcode.Interrupted
+		statusOutputPath := implOutputPath
+		o.checkedBefore = -1
+		o.checkedAtStart, o.implChecked, o.implTasksHash = nil, nil, ""
+		skippedSection := prompt.BuildSkippedTasksSection(o.skippedTaskLines())
+		var implTime, valTime time.Duration
+
+		switch resumeAt {
+		case state.PhaseCrossValidation:
+			// Implementation and validation already passed; only the
+			// post-validation gates remain.
+			logging.Info(fmt.Sprintf("Resuming iteration %d at post-validation", o.session.Iteration))
+			if code := o.runPostValidation(ctx, implOutputPath, filepath.Join(iterDir, "validation-output.txt")); code >= 0 {
+				return code
 			}
 			continue
-		}
+		case state.PhaseValidation:
+			logging.Info(fmt.Sprintf("Resuming iteration %d at validation, reusing %s", o.session.Iteration, implOutputPath))
+			o.diffBase = o.session.Checkpoint.DiffBase
+		default:
+			// Save state before implementation
+			o.session.Phase = state.PhaseImplementation
+			o.session.Checkpoint = nil
+			if err := state.SaveState(o.session, o.StateDir); err != nil {
+				logging.Warn(fmt.Sprintf("Failed to save implementation state: %v", err))
+			}
 
-		// Dump implementation output to stderr for visibility
-		if data, err := os.ReadFile(implOutputPath); err == nil && len(data) > 0 {
-			_, _ = os.Stderr.Write(data)
-		}
-		logging.Success("Implementation phase completed")
+			if err := o.runPreIterationHook(iterCtx, iterDir); err != nil {
+				if ctx.Err() != nil {
+					return exitcode.Interrupted
+				}
+				logging.Error(err.Error())
+				return exitcode.Error
+			}
+			o.snapshotDiffBase()
+			o.saveIterationStart(iterDir)
+			if n, err := tasks.CountChecked(o.session.TasksFile); err == nil {
+				o.checkedBefore = n
+			}
+			o.checkedAtStart = o.checkedTaskIDs()
+
+			// Run implementation
+			isFirst := o.session.Iteration == 1 && o.session.LastFeedback == ""
+			feedback := o.lastFeedback()
+
+			// Build prompts
+			learningsText := o.promptLearnings(ctx)
+			contextText := o.promptContext()
+			guidance := state.PendingGuidance(o.session)
+			if len(guidance) > 0 {
+				logging.Info(fmt.Sprintf("Including %d human guidance message(s) in the implementation prompt", len(guidance)))
+			}
+			guidanceText := prompt.BuildHumanGuidanceSection(guidance)
+			readyText := prompt.BuildReadyTasksSection(o.readyTaskLines())
+			historyText, memoryText := "", ""
+			if isFirst {
+				memoryText = o.projectMemorySection()
+			} else {
+				historyText = prompt.BuildFeedbackHistorySection(o.feedbackHistoryLines())
+			}
+			buildImplPrompt := func() string {
+				var implPrompt string
+				if isFirst {
+					implPrompt = prompt.BuildImplFirstPrompt(o.session.TasksFile, learningsText, contextText, guidanceText, policy.RulesText(o.policies))
+				} else {
+					implPrompt = prompt.BuildImplContinuePrompt(o.session.TasksFile, feedback, learningsText, contextText, guidanceText)
+				}
+				implPrompt += memoryText + historyText + skippedSection + readyText
+				if o.Config.ApplyPatch {
+					implPrompt += prompt.BuildPatchModeSection(o.session.TasksFile)
+				}
+				return implPrompt + prompt.BuildProtectedPathsSection(protectedPaths(o.Config.ProtectedPaths),
+					o.Config.ProtectedPathsAction == config.ProtectedEscalate)
+			}
+			implPrompt := o.fitPrompt(state.PhaseImplementation, o.Config.AIProvider, o.Config.ImplModel, buildImplPrompt,
+				trimOldest("the oldest learnings", &learningsText), dropSection("the earlier feedback", &historyText),
+				dropSection("the project memory", &memoryText), dropSection("the code context", &contextText))
+
+			// Run implementation phase
+			logging.Phase(fmt.Sprintf("Implementation phase - Iteration %d", o.session.Iteration))
+			logging.Info(fmt.Sprintf("AI CLI: %s", o.Config.AIProvider))
+			logging.Info(fmt.Sprintf("Model: %s", o.Config.ImplModel))
+			implConfig := ImplementationConfig{
+				Runner:           o.ImplRunner,
+				Iteration:        o.session.Iteration,
+				OutputPath:       implOutputPath,
+				FirstPrompt:      implPrompt,
+				ContinuePrompt:   implPrompt, // For consistency
+				ExtractLearnings: o.Config.EnableLearnings,
+			}
 
-		// Append learnings if any
-		if implResult.Learnings != "" && o.Config.EnableLearnings {
-			if err := learnings.AppendLearnings(o.Config.LearningsFile, o.session.Iteration, implResult.Learnings); err != nil {
-				logging.Warn(fmt.Sprintf("Failed to append learnings: %v", err))
+			o.Dashboard.SetPhase(state.PhaseImplementation)
+			o.Dashboard.WatchOutput(implOutputPath)
+			implStart := time.Now()
+			implTimeoutCtx, cancelImpl := withTimeout(iterCtx, o.Config.ImplTimeout)
+			implCtx, implSpan := tracing.Start(implTimeoutCtx, state.PhaseImplementation)
+			implCtx, closeImplLog := withPhaseLog(implCtx, iterDir, logImpl, o.logMaxBytes())
+			implResult, implErr := RunImplementationPhaseWithLearnings(implCtx, implConfig)
+			closeImplLog()
+			cancelImpl()
+			implSpan.RecordError(implErr)
+			implSpan.End()
+			implTime = time.Since(implStart)
+			o.Metrics.ObservePhase(state.PhaseImplementation, implTime)
+			if implErr != nil {
+				logging.Error(fmt.Sprintf("Implementation failed: %v", implErr))
+				// Check for context cancellation
+				if ctx.Err() != nil {
+					return exitcode.Interrupted
+				}
+				if o.phaseTimedOut(state.PhaseImplementation, iterCtx, implTimeoutCtx) {
+					continue
+				}
+				if code := o.checkAuthFailure(implErr); code >= 0 {
+					return code
+				}
+				continue
+			}
+
+			// Dump implementation output to stderr for visibility
+			if data, err := os.ReadFile(implOutputPath); err == nil && len(data) > 0 {
+				_, _ = os.Stderr.Write(data)
+			}
+			logging.Success("Implementation phase completed")
+			state.MarkGuidanceApplied(o.session, o.session.Iteration)
+
+			if o.Config.ApplyPatch {
+				if err := o.applyImplPatch(implOutputPath, iterDir); err != nil {
+					// Nothing was applied; hand the conflict back to the
+					// implementer instead of validating an unchanged tree.
+					logging.Error(fmt.Sprintf("Patch not applied: %v", err))
+					o.session.LastFeedback = base64.StdEncoding.EncodeToString([]byte(patchFailureFeedback(err)))
+					continue
+				}
+			}
+			if code := o.guardProtectedPaths(); code >= 0 {
+				return code
+			}
+			if !o.runPostImplementationHook(iterCtx, implOutputPath, iterDir) {
+				continue
 			}
+
+			statusCtx, closeStatusLog := withPhaseLog(iterCtx, iterDir, logImpl, o.logMaxBytes())
+			statusOutputPath = o.conformStatus(statusCtx, implOutputPath)
+			closeStatusLog()
+			o.enforceDependencies()
+			o.recordImplTasks()
+			o.collectArtifacts(implOutputPath, iterDir, state.PhaseImplementation)
+			o.captureCommands(implOutputPath, iterDir)
+
+			// Append learnings if any
+			if implResult.Learnings != "" && o.Config.EnableLearnings {
+				if err := learnings.AppendLearnings(o.Config.LearningsFile, o.session.Iteration, implResult.Learnings); err != nil {
+					logging.Warn(fmt.Sprintf("Failed to append learnings: %v", err))
+				}
+			}
+
+			o.checkpoint(state.PhaseValidation, implOutputPath, "")
+		}
+		if code := o.stopForShutdown(fmt.Sprintf("after the implementation of iteration %d", o.session.Iteration)); code >= 0 {
+			return code
+		}
+		if code := o.pauseIfRequested(ctx, fmt.Sprintf("after the implementation of iteration %d", o.session.Iteration)); code >= 0 {
+			return code
 		}
 
 		// Run validation
@@ -600,102 +1283,182 @@ func (o *Orchestrator) phaseIterationLoop(ctx context.Context) int {
 			logging.Warn(fmt.Sprintf("Failed to save validation state: %v", err))
 		}
 
-		logging.Phase(fmt.Sprintf("Validation phase - Iteration %d", o.session.Iteration))
-		logging.Info(fmt.Sprintf("AI CLI: %s", o.Config.AIProvider))
-		logging.Info(fmt.Sprintf("Model: %s", o.Config.ValModel))
-		valPrompt := prompt.BuildValidationPrompt(o.session.TasksFile, implOutputPath)
+		// Triage: an iteration that obviously did not do what it claims is
+		// sent back without the expensive validation.
+		violations := o.checkPolicies()
+		o.verify = o.runVerifyCommands(iterCtx, iterDir)
 		valOutputPath := filepath.Join(iterDir, "validation-output.txt")
-		valConfig := ValidationConfig{
-			Runner:     o.ValRunner,
-			OutputPath: valOutputPath,
-			Prompt:     valPrompt,
-		}
-
-		valResult, valErr := RunValidationPhaseWithResult(ctx, valConfig)
-		if valErr != nil {
-			logging.Error(fmt.Sprintf("Validation failed: %v", valErr))
-			// Check for context cancellation
-			if ctx.Err() != nil {
-				return exitcode.Interrupted
+		var valResult ValidationPhaseResult
+		var validator QuorumMember
+		var rotating bool
+		if feedback := o.runTriage(iterCtx, implOutputPath, iterDir); feedback != "" {
+			valResult = ValidationPhaseResult{Verdict: "NEEDS_MORE_WORK", Feedback: feedback}
+		} else {
+			logging.Phase(fmt.Sprintf("Validation phase - Iteration %d", o.session.Iteration))
+			valRunner := o.ValRunner
+			validator, rotating = o.rotatingValidator()
+			if rotating {
+				valRunner = validator.Runner
+				logging.Info(fmt.Sprintf("Validator: %s", validator.Label))
+			} else {
+				logging.Info(fmt.Sprintf("AI CLI: %s", o.Config.ValAI))
+				logging.Info(fmt.Sprintf("Model: %s", o.Config.ValModel))
+			}
+			focusText := o.focusFilesSection()
+			diffText := o.iterationDiffSection()
+			summaryText := o.implOutputSummarySection(iterCtx, implOutputPath, iterDir)
+			screenshotText := prompt.BuildScreenshotDiffSection(o.screenshotDiffLines())
+			testsText := o.testResultsSection(o.verify)
+			buildValPrompt := func() string {
+				return prompt.BuildValidationPrompt(o.session.TasksFile, implOutputPath, policy.DetectionText(o.policies)) + skippedSection +
+					screenshotText + testsText + focusText + diffText +
+					prompt.BuildPolicyViolationsSection(violationLines(violations)) +
+					prompt.BuildProtectedRevertedSection(o.revertedPaths) +
+					prompt.BuildCustomVerdictsSection(customVerdictLines(customVerdicts)) +
+					commandsRunSection(iterDir) + summaryText
+			}
+			valPrompt := o.fitPrompt(state.PhaseValidation, o.Config.ValAI, o.Config.ValModel, buildValPrompt,
+				dropSection("the iteration diff", &diffText))
+			valConfig := ValidationConfig{
+				Runner:         valRunner,
+				OutputPath:     valOutputPath,
+				Prompt:         valPrompt,
+				CustomVerdicts: customVerdictNames(customVerdicts),
+				FormatRetries:  o.Config.MaxFormatRetries,
+			}
+
+			var valErr error
+			o.Dashboard.SetPhase(state.PhaseValidation)
+			o.Dashboard.WatchOutput(valOutputPath)
+			valStart := time.Now()
+			valTimeoutCtx, cancelVal := withTimeout(iterCtx, o.Config.ValTimeout)
+			valCtx, valSpan := tracing.Start(valTimeoutCtx, state.PhaseValidation)
+			valCtx, closeValLog := withPhaseLog(valCtx, iterDir, logVal, o.logMaxBytes())
+			if len(o.ValQuorum) > 1 {
+				valResult, valErr = RunValidationQuorum(valCtx, QuorumConfig{
+					Members:        o.ValQuorum,
+					OutputPath:     valOutputPath,
+					Prompt:         valPrompt,
+					CustomVerdicts: valConfig.CustomVerdicts,
+					FormatRetries:  valConfig.FormatRetries,
+				})
+			} else {
+				valResult, valErr = RunValidationPhaseWithResult(valCtx, valConfig)
+			}
+			closeValLog()
+			cancelVal()
+			valSpan.SetAttributes(tracing.String("ralph.verdict", valResult.Verdict))
+			valSpan.RecordError(valErr)
+			valSpan.End()
+			valTime = time.Since(valStart)
+			o.Metrics.ObservePhase(state.PhaseValidation, valTime)
+			if valErr != nil {
+				logging.Error(fmt.Sprintf("Validation failed: %v", valErr))
+				// Check for context cancellation
+				if ctx.Err() != nil {
+					return exitcode.Interrupted
+				}
+				if o.phaseTimedOut(state.PhaseValidation, iterCtx, valTimeoutCtx) {
+					continue
+				}
+				if code := o.checkAuthFailure(valErr); code >= 0 {
+					return code
+				}
+				continue
 			}
-			continue
-		}
 
-		// Dump validation output to stderr for visibility
-		if data, err := os.ReadFile(valOutputPath); err == nil && len(data) > 0 {
-			_, _ = os.Stderr.Write(data)
+			// Dump validation output to stderr for visibility
+			if data, err := os.ReadFile(valOutputPath); err == nil && len(data) > 0 {
+				_, _ = os.Stderr.Write(data)
+			}
+			logging.Success("Validation phase completed")
+			o.collectArtifacts(valOutputPath, iterDir, state.PhaseValidation)
+
+			valResult = o.runExternalValidators(iterCtx, valResult, implOutputPath, iterDir)
 		}
-		logging.Success("Validation phase completed")
+		valResult = o.enforcePolicies(valResult, violations)
+		valResult = o.noteRevertedPaths(valResult)
+		valResult = o.applyPostValidationHook(iterCtx, valResult, implOutputPath, valOutputPath, iterDir)
+		valResult = o.revertUnconfirmedChecks(valResult)
+		valResult = o.enforceEvidence(valResult, implOutputPath)
+		valResult = noteFailingTests(valResult, o.verify)
 
 		// Get current task counts
 		unchecked, _ := tasks.CountUnchecked(o.session.TasksFile)
 
+		// Update per-task tracking from this iteration's outputs
+		o.trackTaskProgress(statusOutputPath, valResult)
+		o.annotateTasks()
+		skipped := o.blockStuckTasks()
+		blockedTasks := mergeBlockedTasks(valResult.BlockedTasks, skipped)
+
 		// Process verdict
 		o.session.Verdict = valResult.Verdict
+		o.session.Confidence = valResult.Confidence
+		o.recordGate(state.PhaseValidation, valResult.Verdict)
+		if rotating {
+			o.recordValidator(validator.Label, valResult.Verdict)
+		}
+		iterSpan.SetAttributes(tracing.String("ralph.verdict", valResult.Verdict))
+		o.Dashboard.SetVerdict(valResult.Verdict, valResult.Feedback)
+		o.Dashboard.SetBlocked(blockedTasks)
+		o.updateDashboardTasks()
+		o.trackModelEscalation(valResult)
 		verdictResult := ProcessVerdict(VerdictInput{
 			Verdict:           valResult.Verdict,
 			Feedback:          valResult.Feedback,
 			Remaining:         unchecked,
-			BlockedCount:      len(valResult.BlockedTasks),
-			BlockedTasks:      valResult.BlockedTasks,
+			BlockedCount:      len(blockedTasks),
+			BlockedTasks:      blockedTasks,
 			InadmissibleCount: o.session.InadmissibleCount,
 			MaxInadmissible:   o.session.MaxInadmissible,
+			Custom:            customVerdicts,
 		})
 
+		// Nothing left to work on if every remaining task was skipped
+		if verdictResult.Action == "continue" && unchecked > 0 && len(skipped) >= unchecked {
+			logging.Warn("All remaining tasks were skipped as stuck")
+			verdictResult.Action = "exit"
+			verdictResult.ExitCode = exitcode.Blocked
+		}
+
 		o.session.InadmissibleCount = verdictResult.NewInadmissibleCount
+		o.Metrics.SetInadmissible(o.session.InadmissibleCount)
+		o.recordIteration(time.Since(iterStart)-o.pausedFor, implTime, valTime)
+		o.saveIterationDiff(iterDir, valResult.Feedback)
 
 		if verdictResult.Action == "exit" {
-			duration := int(time.Since(o.startTime).Seconds())
 			switch verdictResult.ExitCode {
 			case exitcode.Success:
-				// Compute specFile for post-validation chain
-				specFile := o.Config.OriginalPlanFile
-				if specFile == "" && o.Config.GithubIssue != "" {
-					specFile = filepath.Join(o.StateDir, "github-issue.md")
+				o.checkpoint(state.PhaseCrossValidation, implOutputPath, valOutputPath)
+				if code := o.stopForShutdown(fmt.Sprintf("after the validation of iteration %d", o.session.Iteration)); code >= 0 {
+					return code
 				}
-
-				// Run post-validation chain
-				postResult := RunPostValidationChain(ctx, PostValidationConfig{
-					CrossValRunner:   o.CrossRunner,
-					FinalPlanRunner:  o.FinalPlanRunner,
-					CrossValEnabled:  o.Config.CrossValidate && o.CrossRunner != nil,
-					FinalPlanEnabled: o.FinalPlanRunner != nil,
-					TasksFile:        o.session.TasksFile,
-					ImplOutputFile:   implOutputPath,
-					ValOutputFile:    valOutputPath,
-					SpecFile:         specFile,
-					PlanFile:         o.Config.OriginalPlanFile,
-					CrossAI:          o.Config.CrossAI,
-					CrossModel:       o.Config.CrossModel,
-					FinalPlanAI:      o.Config.FinalPlanAI,
-					FinalPlanModel:   o.Config.FinalPlanModel,
-				})
-
-				if postResult.Action == "continue" {
-					// Cross-val or final-plan rejected, continue loop
-					o.session.LastFeedback = base64.StdEncoding.EncodeToString([]byte(postResult.Feedback))
-					continue
+				if code := o.pauseIfRequested(ctx, fmt.Sprintf("after the validation of iteration %d", o.session.Iteration)); code >= 0 {
+					return code
 				}
-
-				o.session.Status = state.StatusComplete
-				if err := state.SaveState(o.session, o.StateDir); err != nil {
-					logging.Warn(fmt.Sprintf("Failed to save complete state: %v", err))
+				if code := o.runPostValidation(ctx, implOutputPath, valOutputPath); code >= 0 {
+					return code
 				}
-				banner.PrintCompletionBanner(o.session.Iteration, duration)
-				o.notify(notification.EventCompleted, exitcode.Success)
-				return exitcode.Success
+				continue
 
 			case exitcode.Escalate:
 				banner.PrintEscalationBanner(verdictResult.Feedback)
-				o.notify(notification.EventEscalate, exitcode.Escalate)
+				reason := verdictResult.Feedback
+				if reason == "" {
+					reason = fmt.Sprintf("The validator returned %s.", valResult.Verdict)
+				}
+				o.writeEscalationReport(reason, valResult.Feedback, valOutputPath)
+				o.notify(notification.EventEscalate, exitcode.Result{Code: exitcode.Escalate, Reason: feedbackReason(reason)})
 				if err := state.SaveState(o.session, o.StateDir); err != nil {
 					logging.Warn(fmt.Sprintf("Failed to save escalate state: %v", err))
 				}
 				return exitcode.Escalate
 
 			case exitcode.Blocked:
-				banner.PrintBlockedBanner(valResult.BlockedTasks)
-				o.notify(notification.EventBlocked, exitcode.Blocked)
+				banner.PrintBlockedBanner(blockedTasks)
+				o.notify(notification.EventBlocked, exitcode.Result{Code: exitcode.Blocked,
+					Reason: fmt.Sprintf("%d task(s) blocked: %s", len(blockedTasks), strings.Join(blockedTasks, ", "))})
 				if err := state.SaveState(o.session, o.StateDir); err != nil {
 					logging.Warn(fmt.Sprintf("Failed to save blocked state: %v", err))
 				}
@@ -703,13 +1466,18 @@ func (o *Orchestrator) phaseIterationLoop(ctx context.Context) int {
 
 			case exitcode.Inadmissible:
 				banner.PrintInadmissibleBanner(o.session.InadmissibleCount, o.session.MaxInadmissible)
-				o.notify(notification.EventInadmissible, exitcode.Inadmissible)
+				o.notify(notification.EventInadmissible, exitcode.Result{Code: exitcode.Inadmissible,
+					Reason: fmt.Sprintf("%d inadmissible iterations, limit %d", o.session.InadmissibleCount, o.session.MaxInadmissible)})
 				if err := state.SaveState(o.session, o.StateDir); err != nil {
 					logging.Warn(fmt.Sprintf("Failed to save inadmissible state: %v", err))
 				}
 				return exitcode.Inadmissible
 
 			default:
+				if _, ok := customVerdicts[valResult.Verdict]; ok {
+					logging.Warn(fmt.Sprintf("Stopping on verdict %s", verdictResult.Feedback))
+					o.notify(notification.EventCustomVerdict, exitcode.Result{Code: verdictResult.ExitCode, Reason: feedbackReason(verdictResult.Feedback)})
+				}
 				if err := state.SaveState(o.session, o.StateDir); err != nil {
 					logging.Warn(fmt.Sprintf("Failed to save state: %v", err))
 				}
@@ -719,28 +1487,67 @@ func (o *Orchestrator) phaseIterationLoop(ctx context.Context) int {
 
 		// Continue: store feedback
 		o.session.LastFeedback = base64.StdEncoding.EncodeToString([]byte(verdictResult.Feedback))
+		o.recordFeedback(verdictResult.Feedback)
+		o.session.Checkpoint = nil
 		if err := state.SaveState(o.session, o.StateDir); err != nil {
 			logging.Warn(fmt.Sprintf("Failed to save feedback state: %v", err))
 		}
+		o.notifyProgress(notification.EventIteration, withETA(valResult.Verdict, o.session))
+		o.updateCheckRun(valResult.Verdict, verdictResult.Feedback)
 	}
 
 	// Max iterations reached
 	banner.PrintMaxIterationsBanner(o.session.Iteration, o.session.MaxIterations)
-	o.notify(notification.EventMaxIterations, exitcode.MaxIterations)
+	unchecked, _ := tasks.CountUnchecked(o.session.TasksFile)
+	o.notify(notification.EventMaxIterations, exitcode.Result{Code: exitcode.MaxIterations,
+		Reason: fmt.Sprintf("reached the limit of %d iterations with %d task(s) unchecked", o.session.MaxIterations, unchecked)})
 	if err := state.SaveState(o.session, o.StateDir); err != nil {
 		logging.Warn(fmt.Sprintf("Failed to save max iterations state: %v", err))
 	}
 	return exitcode.MaxIterations
 }
 
-// notify sends a fire-and-forget notification for the given event.
-func (o *Orchestrator) notify(event string, code int) {
-	projectName := filepath.Base(filepath.Dir(o.session.TasksFile))
-	if projectName == "." || projectName == "" {
-		projectName = "ralph-loop"
+// updateDashboardTasks refreshes the task progress shown on the dashboard.
+func (o *Orchestrator) updateDashboardTasks() {
+	if o.Dashboard == nil {
+		return
+	}
+	done, _ := tasks.CountChecked(o.session.TasksFile)
+	remaining, _ := tasks.CountUnchecked(o.session.TasksFile)
+	o.Dashboard.SetTasks(done, done+remaining)
+}
+
+// notify records res as how the loop stopped, sends a fire-and-forget
+// notification for the given event and updates the summary comment on the
+// branch's pull request, if any.
+func (o *Orchestrator) notify(event string, res exitcode.Result) {
+	o.stopWith(res)
+	msg := notification.FormatEvent(event, o.projectName(), o.session.SessionID, o.session.Iteration, res.Code)
+	msg += notification.FormatReason(res.Reason)
+	msg += notification.FormatArtifacts(artifactPaths(o.session.Artifacts))
+	if event == notification.EventEscalate {
+		msg += notification.FormatReport(o.escalationReport)
+	}
+	o.notifications().Notify(event, msg)
+	o.postPRSummary(event, res)
+}
+
+// taskInfos converts tracked task state into banner display rows.
+func taskInfos(list []state.TaskState) []banner.TaskInfo {
+	infos := make([]banner.TaskInfo, 0, len(list))
+	for _, t := range list {
+		infos = append(infos, banner.TaskInfo{
+			ID:             t.ID,
+			Text:           t.Text,
+			Status:         t.Status,
+			FirstIteration: t.FirstIteration,
+			LastIteration:  t.LastIteration,
+			Verdicts:       t.Verdicts,
+			Churning:       t.Churning(),
+			Meta:           tasks.FormatMetadata(t.Meta),
+		})
 	}
-	msg := notification.FormatEvent(event, projectName, o.session.SessionID, o.session.Iteration, code)
-	notification.SendNotification(o.Config.NotifyWebhook, o.Config.NotifyChannel, o.Config.NotifyChatID, msg)
+	return infos
 }
 
 func boolToInt(b bool) int {
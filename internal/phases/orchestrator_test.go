@@ -1,12 +1,16 @@
 package phases
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,7 +20,10 @@ import (
 	"github.com/CodexForgeBR/cli-tools/internal/ai"
 	"github.com/CodexForgeBR/cli-tools/internal/config"
 	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/metrics"
 	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/tracing"
+	"github.com/CodexForgeBR/cli-tools/internal/tui"
 )
 
 // MockOrchestratorAIRunner is a configurable mock for orchestrator tests
@@ -1505,6 +1512,75 @@ func TestOrchestrator_PhaseCommandChecksNotAvailable(t *testing.T) {
 	assert.Equal(t, exitcode.Error, exitCode, "should error when AI tool is not found")
 }
 
+// TestOrchestrator_PhaseCommandChecksSandbox verifies that with a sandbox
+// configured only the sandbox program has to be on the host.
+func TestOrchestrator_PhaseCommandChecksSandbox(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.SandboxCmd = "docker run --rm -i img"
+
+	var checked []string
+	orchestrator := NewOrchestrator(cfg)
+	orchestrator.CommandChecker = func(tools ...string) map[string]bool {
+		checked = append(checked, tools...)
+		return map[string]bool{"docker": true}
+	}
+
+	assert.Equal(t, -1, orchestrator.phaseCommandChecks())
+	assert.Equal(t, []string{"docker"}, checked)
+}
+
+// TestOrchestrator_PhaseCommandChecksPerPhaseAI verifies that the AI of
+// every always-running phase is checked once.
+func TestOrchestrator_PhaseCommandChecksPerPhaseAI(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.ValAI = "codex"
+	cfg.TasksValAI = "claude"
+	cfg.SummaryAI = "amazonq"
+
+	var checked []string
+	orchestrator := NewOrchestrator(cfg)
+	orchestrator.CommandChecker = func(tools ...string) map[string]bool {
+		checked = append(checked, tools...)
+		return map[string]bool{"claude": true, "codex": true}
+	}
+
+	assert.Equal(t, exitcode.Error, orchestrator.phaseCommandChecks())
+	assert.Equal(t, []string{"claude", "codex", "amazonq"}, checked)
+}
+
+// TestOrchestrator_PhaseCommandChecksK8sJob verifies that implementation
+// jobs need kubectl rather than the implementation AI.
+func TestOrchestrator_PhaseCommandChecksK8sJob(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.AIProvider = "codex"
+	cfg.ValAI = "claude"
+	cfg.K8sJobImage = "agent:1"
+
+	var checked []string
+	orchestrator := NewOrchestrator(cfg)
+	orchestrator.CommandChecker = func(tools ...string) map[string]bool {
+		checked = append(checked, tools...)
+		return map[string]bool{"kubectl": true, "claude": true}
+	}
+
+	assert.Equal(t, -1, orchestrator.phaseCommandChecks())
+	assert.Equal(t, []string{"kubectl", "claude"}, checked)
+}
+
+// TestOrchestrator_PhaseCommandChecksRemoteAgent verifies that nothing is
+// required locally when a remote agent runs the AI CLIs.
+func TestOrchestrator_PhaseCommandChecksRemoteAgent(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.RemoteAgent = "http://gpu-box:7433"
+
+	orchestrator := NewOrchestrator(cfg)
+	orchestrator.CommandChecker = func(tools ...string) map[string]bool {
+		t.Errorf("unexpected check of %v", tools)
+		return nil
+	}
+	assert.Equal(t, -1, orchestrator.phaseCommandChecks())
+}
+
 // TestOrchestrator_PhaseFindTasksDiscoverError tests phaseFindTasks when no tasks file exists.
 func TestOrchestrator_PhaseFindTasksDiscoverError(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -2706,7 +2782,7 @@ func TestOrchestrator_NotifyDirectCall(t *testing.T) {
 	}
 
 	// This should not panic and should use "ralph-loop" as project name
-	orchestrator.notify("completed", exitcode.Success)
+	orchestrator.notify("completed", exitcode.Result{Code: exitcode.Success})
 }
 
 // TestOrchestrator_NotifyWithEmptyDir tests notify when TasksFile dir is empty.
@@ -2723,7 +2799,7 @@ func TestOrchestrator_NotifyWithEmptyDir(t *testing.T) {
 	}
 
 	// This should not panic
-	orchestrator.notify("completed", exitcode.Success)
+	orchestrator.notify("completed", exitcode.Result{Code: exitcode.Success})
 }
 
 // TestOrchestrator_PhaseValidateSetupError tests Run when phaseValidateSetup returns error.
@@ -2980,10 +3056,11 @@ func TestOrchestrator_DirectPhaseFetchIssueCacheError(t *testing.T) {
 // The code path at line 436-437 is defensive. We'd need to mock schedule.WaitUntil
 // which is a package function, not injectable. This line is effectively unreachable.
 
-// TestOrchestrator_IterationLoopDefaultExitCode tests the default exit code path in iteration loop.
-// This happens when ProcessVerdict returns an action="exit" with an unknown exit code.
-// This is a very defensive path - let's try to exercise it.
-func TestOrchestrator_IterationLoopDefaultExitCode(t *testing.T) {
+// TestOrchestrator_IterationLoopUnknownVerdict tests that a verdict outside
+// the RALPH_VALIDATION schema is sent back for format fixes and, when the
+// validator keeps repeating it, judged INADMISSIBLE instead of ending the run.
+// (The default exit path is covered by TestOrchestrator_CustomVerdictExitCode.)
+func TestOrchestrator_IterationLoopUnknownVerdict(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	tasksFile := filepath.Join(tmpDir, "tasks.md")
@@ -3003,18 +3080,8 @@ func TestOrchestrator_IterationLoopDefaultExitCode(t *testing.T) {
 		},
 	}
 
-	// Return a verdict that results in an unknown/default exit code
-	// ProcessVerdict's default case returns action="exit", ExitCode=exitcode.Error
-	// Looking at the iteration loop, after ProcessVerdict:
-	// - Success → post-val chain
-	// - Escalate → escalation
-	// - Blocked → blocked
-	// - Inadmissible → inadmissible
-	// - default → just save state and return exit code
-	// For an unknown verdict, ProcessVerdict returns exitcode.Error and action="exit"
 	valRunner := &MockOrchestratorAIRunner{
 		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
-			// Return an unknown verdict that ProcessVerdict maps to default
 			_ = os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("TOTALLY_UNKNOWN_VERDICT", "strange")), 0644)
 			return nil
 		},
@@ -3029,8 +3096,13 @@ func TestOrchestrator_IterationLoopDefaultExitCode(t *testing.T) {
 	ctx := context.Background()
 	exitCode := orchestrator.Run(ctx)
 
-	// ProcessVerdict returns Error for unknown verdict, which hits the default case
-	assert.Equal(t, exitcode.Error, exitCode, "unknown verdict should return Error exit code")
+	assert.Equal(t, exitcode.MaxIterations, exitCode, "an unknown verdict only counts as inadmissible")
+	assert.Equal(t, 1+cfg.MaxFormatRetries, valRunner.CallCount, "validation plus MAX_FORMAT_RETRIES format fixes")
+	assert.Contains(t, valRunner.PromptLog[1], `RALPH_VALIDATION.verdict: "TOTALLY_UNKNOWN_VERDICT" is not one of`)
+
+	saved, err := state.LoadState(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, saved.InadmissibleCount)
 }
 
 // TestOrchestrator_RunFullPathValidateSetupFails tests the Run function returning from phaseValidateSetup.
@@ -3235,3 +3307,757 @@ func TestOrchestrator_PhaseTasksValidationSkipNoSpecNoIssue(t *testing.T) {
 	code := orchestrator.phaseTasksValidation(context.Background())
 	assert.Equal(t, -1, code, "should skip when no spec and no issue")
 }
+
+// TestOrchestrator_FailsWhenStateLocked verifies that a second loop against
+// the same state directory fails fast, that --status still works, and that
+// the lock is released when the run ends.
+func TestOrchestrator_FailsWhenStateLocked(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] T001 Work\n"), 0644))
+
+	held, err := state.AcquireLock(tmpDir, false)
+	require.NoError(t, err)
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.CrossValidate = false
+	implRunner := &MockOrchestratorAIRunner{}
+
+	orch := NewOrchestrator(cfg)
+	orch.CommandChecker = alwaysAvailable
+	orch.StateDir = tmpDir
+	orch.ImplRunner = implRunner
+	orch.ValRunner = &MockOrchestratorAIRunner{}
+	assert.Equal(t, exitcode.Error, orch.Run(context.Background()))
+	assert.Equal(t, 0, implRunner.CallCount)
+
+	statusCfg := config.NewDefaultConfig()
+	statusCfg.TasksFile = tasksFile
+	statusCfg.Status = true
+	statusOrch := NewOrchestrator(statusCfg)
+	statusOrch.CommandChecker = alwaysAvailable
+	statusOrch.StateDir = tmpDir
+	assert.Equal(t, exitcode.Success, statusOrch.Run(context.Background()))
+
+	require.NoError(t, held.Release())
+	cfg.MaxIterations = 1
+	orch = NewOrchestrator(cfg)
+	orch.CommandChecker = alwaysAvailable
+	orch.StateDir = tmpDir
+	orch.ImplRunner = implRunner
+	orch.ValRunner = &MockOrchestratorAIRunner{}
+	orch.Run(context.Background())
+	assert.Equal(t, 1, implRunner.CallCount)
+	_, err = state.ReadLock(tmpDir)
+	assert.Error(t, err, "lock file is removed when the run ends")
+}
+
+// TestOrchestrator_RecordsMetrics verifies iterations, verdicts and phase
+// durations are reported to the metrics registry.
+func TestOrchestrator_RecordsMetrics(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("# Tasks\n- [ ] Task 1\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 3
+	cfg.CrossValidate = false
+	cfg.FinalPlanAI = ""
+	cfg.TasksValAI = ""
+
+	calls := 0
+	valRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			calls++
+			if calls == 2 {
+				_ = os.WriteFile(tasksFile, []byte("# Tasks\n- [x] Task 1\n"), 0644)
+				_ = os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("COMPLETE", "")), 0644)
+			} else {
+				_ = os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("NEEDS_MORE_WORK", "Keep going")), 0644)
+			}
+			return nil
+		},
+	}
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			_ = os.WriteFile(outputPath, []byte("Implementation output"), 0644)
+			return nil
+		},
+	}
+
+	orchestrator := NewOrchestrator(cfg)
+	orchestrator.CommandChecker = alwaysAvailable
+	orchestrator.StateDir = tmpDir
+	orchestrator.ImplRunner = implRunner
+	orchestrator.ValRunner = valRunner
+	orchestrator.Metrics = metrics.New()
+
+	require.Equal(t, exitcode.Success, orchestrator.Run(context.Background()))
+
+	var buf bytes.Buffer
+	_, err := orchestrator.Metrics.WriteTo(&buf)
+	require.NoError(t, err)
+	out := buf.String()
+	assert.Contains(t, out, "ralph_loop_iterations_total 2\n")
+	assert.Contains(t, out, `ralph_loop_verdicts_total{phase="validation",verdict="NEEDS_MORE_WORK"} 1`)
+	assert.Contains(t, out, `ralph_loop_verdicts_total{phase="validation",verdict="COMPLETE"} 1`)
+	assert.Contains(t, out, `ralph_loop_phase_duration_seconds_count{phase="implementation"} 2`)
+	assert.Contains(t, out, `ralph_loop_phase_duration_seconds_count{phase="validation"} 2`)
+}
+
+// spanRecorder is an in-memory tracing.Exporter.
+type spanRecorder struct {
+	spans []tracing.SpanData
+}
+
+func (r *spanRecorder) Export(_ context.Context, spans []tracing.SpanData) error {
+	r.spans = append(r.spans, spans...)
+	return nil
+}
+
+// TestOrchestrator_RecordsTrace verifies the session is the trace root with
+// one child span per iteration and phase spans below each iteration.
+func TestOrchestrator_RecordsTrace(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("# Tasks\n- [ ] Task 1\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 3
+	cfg.CrossValidate = false
+	cfg.FinalPlanAI = ""
+	cfg.TasksValAI = ""
+
+	calls := 0
+	valRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			calls++
+			if calls == 2 {
+				_ = os.WriteFile(tasksFile, []byte("# Tasks\n- [x] Task 1\n"), 0644)
+				_ = os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("COMPLETE", "")), 0644)
+			} else {
+				_ = os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("NEEDS_MORE_WORK", "Keep going")), 0644)
+			}
+			return nil
+		},
+	}
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			_ = os.WriteFile(outputPath, []byte("Implementation output"), 0644)
+			return nil
+		},
+	}
+
+	rec := &spanRecorder{}
+	orchestrator := NewOrchestrator(cfg)
+	orchestrator.CommandChecker = alwaysAvailable
+	orchestrator.StateDir = tmpDir
+	orchestrator.ImplRunner = implRunner
+	orchestrator.ValRunner = valRunner
+	orchestrator.Tracer = tracing.NewTracer(rec)
+
+	require.Equal(t, exitcode.Success, orchestrator.Run(context.Background()))
+	require.NoError(t, orchestrator.Tracer.Shutdown(context.Background()))
+
+	byID := make(map[string]tracing.SpanData)
+	var root tracing.SpanData
+	var iterations []tracing.SpanData
+	for _, s := range rec.spans {
+		byID[s.SpanID] = s
+		switch s.Name {
+		case "ralph-loop.session":
+			root = s
+		case "iteration":
+			iterations = append(iterations, s)
+		}
+	}
+	require.NotEmpty(t, root.SpanID)
+	assert.Empty(t, root.ParentID)
+	assert.Contains(t, root.Attrs, tracing.Int("ralph.exit_code", exitcode.Success))
+	assert.False(t, root.Failed)
+
+	require.Len(t, iterations, 2)
+	for _, it := range iterations {
+		assert.Equal(t, root.SpanID, it.ParentID)
+		assert.Equal(t, root.TraceID, it.TraceID)
+	}
+
+	phaseParents := make(map[string]int)
+	for _, s := range rec.spans {
+		if s.Name == state.PhaseImplementation || s.Name == state.PhaseValidation || s.Name == "post_validation" {
+			assert.Equal(t, "iteration", byID[s.ParentID].Name, "%s should be a child of an iteration", s.Name)
+			phaseParents[s.Name]++
+		}
+	}
+	assert.Equal(t, map[string]int{
+		state.PhaseImplementation: 2,
+		state.PhaseValidation:     2,
+		"post_validation":         1,
+	}, phaseParents)
+}
+
+// TestOrchestrator_UpdatesDashboard verifies the dashboard reflects the
+// session, task progress and final verdict.
+func TestOrchestrator_UpdatesDashboard(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("# Tasks\n- [x] Task 1\n- [ ] Task 2\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 2
+	cfg.CrossValidate = false
+	cfg.FinalPlanAI = ""
+	cfg.TasksValAI = ""
+
+	orchestrator := NewOrchestrator(cfg)
+	orchestrator.CommandChecker = alwaysAvailable
+	orchestrator.StateDir = tmpDir
+	orchestrator.ImplRunner = &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			_ = os.WriteFile(tasksFile, []byte("# Tasks\n- [x] Task 1\n- [x] Task 2\n"), 0644)
+			_ = os.WriteFile(outputPath, []byte("Implementation output"), 0644)
+			return nil
+		},
+	}
+	orchestrator.ValRunner = &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			_ = os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("COMPLETE", "")), 0644)
+			return nil
+		},
+	}
+
+	term, err := os.Create(filepath.Join(tmpDir, "term"))
+	require.NoError(t, err)
+	defer term.Close()
+	dash, err := tui.Start(term)
+	require.NoError(t, err)
+	orchestrator.Dashboard = dash
+
+	exitCode := orchestrator.Run(context.Background())
+	dash.Stop()
+	require.Equal(t, exitcode.Success, exitCode)
+
+	data, err := os.ReadFile(term.Name())
+	require.NoError(t, err)
+	screen := string(data)
+	assert.Contains(t, screen, "Verdict: ")
+	assert.Contains(t, screen, "COMPLETE")
+	assert.Contains(t, screen, "2/2 (100%)")
+	assert.Contains(t, screen, "All tasks completed successfully", "final banner is replayed after the dashboard closes")
+}
+
+// TestOrchestrator_RequestEscalation verifies an escalation requested from
+// another goroutine stops the loop at the next iteration boundary.
+func TestOrchestrator_RequestEscalation(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("# Tasks\n- [ ] Task 1\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 5
+	cfg.CrossValidate = false
+	cfg.FinalPlanAI = ""
+	cfg.TasksValAI = ""
+
+	orchestrator := NewOrchestrator(cfg)
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			_ = os.WriteFile(outputPath, []byte("Implementation output"), 0644)
+			return nil
+		},
+	}
+	valRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			orchestrator.RequestEscalation("operator asked to stop")
+			_ = os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("NEEDS_MORE_WORK", "Keep going")), 0644)
+			return nil
+		},
+	}
+	orchestrator.CommandChecker = alwaysAvailable
+	orchestrator.StateDir = tmpDir
+	orchestrator.ImplRunner = implRunner
+	orchestrator.ValRunner = valRunner
+
+	assert.Equal(t, exitcode.Escalate, orchestrator.Run(context.Background()))
+	assert.Equal(t, 1, implRunner.CallCount, "the current iteration finishes before stopping")
+	assert.Equal(t, 1, valRunner.CallCount)
+}
+
+func TestOrchestrator_PlanFromIssue(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script approach does not work on Windows")
+	}
+	tmpDir := t.TempDir()
+	binDir := t.TempDir()
+	fakeGh := "#!/bin/sh\nprintf 'Add a greeting\\n\\nPrint hello on startup.'\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "gh"), []byte(fakeGh), 0755))
+	t.Setenv("PATH", binDir+":"+os.Getenv("PATH"))
+
+	tasksFile := filepath.Join(tmpDir, "specs", "greeting", "tasks.md")
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.GithubIssue = "42"
+	cfg.PlanFromIssue = true
+	cfg.CrossValidate = false
+	cfg.FinalPlanAI = ""
+
+	orchestrator := NewOrchestrator(cfg)
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			answer := "RALPH_PLAN\n````markdown\n# Plan\n````\nRALPH_TASKS\n````markdown\n- [ ] T001 Print hello\n````\n"
+			return os.WriteFile(outputPath, []byte(answer), 0644)
+		},
+	}
+	var tasksValPrompt string
+	tasksValRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			tasksValPrompt = prompt
+			return os.WriteFile(outputPath, []byte(`{"RALPH_TASKS_VALIDATION":{"verdict":"INVALID","feedback":"Missing tests"}}`), 0644)
+		},
+	}
+	orchestrator.CommandChecker = alwaysAvailable
+	orchestrator.StateDir = tmpDir
+	orchestrator.ImplRunner = implRunner
+	orchestrator.ValRunner = &MockOrchestratorAIRunner{}
+	orchestrator.TasksValRunner = tasksValRunner
+
+	assert.Equal(t, exitcode.TasksInvalid, orchestrator.Run(context.Background()))
+	assert.Equal(t, 1, implRunner.CallCount, "only the planning run")
+
+	plan, err := os.ReadFile(filepath.Join(tmpDir, "specs", "greeting", "plan.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Plan\n", string(plan))
+	generated, err := os.ReadFile(tasksFile)
+	require.NoError(t, err)
+	assert.Equal(t, "- [ ] T001 Print hello\n", string(generated))
+
+	assert.Contains(t, tasksValPrompt, filepath.Join(tmpDir, "github-issue.md"))
+	assert.Contains(t, tasksValPrompt, tasksFile)
+}
+
+func TestOrchestrator_PlanFromIssue_KeepsExistingTasksFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script approach does not work on Windows")
+	}
+	tmpDir := t.TempDir()
+	binDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "gh"), []byte("#!/bin/sh\necho 'Issue title'\n"), 0755))
+	t.Setenv("PATH", binDir+":"+os.Getenv("PATH"))
+
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] Hand-written task\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.GithubIssue = "42"
+	cfg.PlanFromIssue = true
+
+	orchestrator := NewOrchestrator(cfg)
+	implRunner := &MockOrchestratorAIRunner{}
+	orchestrator.CommandChecker = alwaysAvailable
+	orchestrator.StateDir = tmpDir
+	orchestrator.ImplRunner = implRunner
+
+	assert.Equal(t, exitcode.Error, orchestrator.Run(context.Background()))
+	assert.Equal(t, 0, implRunner.CallCount)
+	data, err := os.ReadFile(tasksFile)
+	require.NoError(t, err)
+	assert.Equal(t, "- [ ] Hand-written task\n", string(data))
+}
+
+func TestOrchestrator_DirectPhaseFetchIssueGitea(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"title":"Fix login","body":"Login fails.","html_url":"https://gitea.example.com/owner/repo/issues/3"}`))
+	}))
+	defer srv.Close()
+
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cfg.GithubIssue = srv.URL + "/owner/repo/issues/3"
+	cfg.IssueProvider = "gitea"
+
+	orchestrator := NewOrchestrator(cfg)
+	orchestrator.StateDir = tmpDir
+	orchestrator.session = &state.SessionState{SchemaVersion: 2, SessionID: "test-gitea"}
+
+	orchestrator.phaseFetchIssue()
+
+	require.NotNil(t, orchestrator.session.GithubIssue)
+	data, err := os.ReadFile(filepath.Join(tmpDir, "github-issue.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Fix login\n\nSource: https://gitea.example.com/owner/repo/issues/3\n\nLogin fails.\n", string(data))
+}
+
+func TestOrchestrator_JiraIssueAsSpec(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"fields":{"summary":"Export CSV","description":"Add CSV export.","customfield_1":"Has a header row"},"names":{"customfield_1":"Acceptance Criteria"}}`))
+	}))
+	defer srv.Close()
+	t.Setenv("JIRA_URL", srv.URL)
+	t.Setenv("JIRA_TOKEN", "pat")
+
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] T001 Export CSV\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.JiraIssue = "PROJ-123"
+
+	orchestrator := NewOrchestrator(cfg)
+	var tasksValPrompt string
+	orchestrator.CommandChecker = alwaysAvailable
+	orchestrator.StateDir = tmpDir
+	orchestrator.TasksValRunner = &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			tasksValPrompt = prompt
+			return os.WriteFile(outputPath, []byte(`{"RALPH_TASKS_VALIDATION":{"verdict":"INVALID","feedback":"Missing tests"}}`), 0644)
+		},
+	}
+
+	assert.Equal(t, exitcode.TasksInvalid, orchestrator.Run(context.Background()))
+
+	specFile := filepath.Join(tmpDir, "jira-issue.md")
+	assert.Contains(t, tasksValPrompt, specFile)
+	data, err := os.ReadFile(specFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# Export CSV")
+	assert.Contains(t, string(data), "## Acceptance Criteria\n\nHas a header row")
+	require.NotNil(t, orchestrator.session.JiraIssue)
+	assert.Equal(t, "PROJ-123", *orchestrator.session.JiraIssue)
+}
+
+func TestOrchestrator_TaskDependencies(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] T001 Model\n- [ ] T002 Writer {depends: T001}\n- [ ] T003 Button {depends: T002}\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 1
+	cfg.CrossValidate = false
+	cfg.FinalPlanAI = ""
+	cfg.TasksValAI = ""
+
+	orchestrator := NewOrchestrator(cfg)
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			// Check off T002 ahead of T001
+			_ = os.WriteFile(tasksFile, []byte("- [ ] T001 Model\n- [x] T002 Writer {depends: T001}\n- [ ] T003 Button {depends: T002}\n"), 0644)
+			return os.WriteFile(outputPath, []byte("Implementation output"), 0644)
+		},
+	}
+	var tasksAtValidation string
+	valRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			data, _ := os.ReadFile(tasksFile)
+			tasksAtValidation = string(data)
+			return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("NEEDS_MORE_WORK", "Keep going")), 0644)
+		},
+	}
+	orchestrator.CommandChecker = alwaysAvailable
+	orchestrator.StateDir = tmpDir
+	orchestrator.ImplRunner = implRunner
+	orchestrator.ValRunner = valRunner
+
+	assert.Equal(t, exitcode.MaxIterations, orchestrator.Run(context.Background()))
+
+	require.Len(t, implRunner.PromptLog, 1)
+	assert.Contains(t, implRunner.PromptLog[0], "TASK DEPENDENCIES")
+	assert.Contains(t, implRunner.PromptLog[0], "- T001 Model\n")
+	assert.NotContains(t, implRunner.PromptLog[0], "- T002 Writer")
+	assert.Contains(t, tasksAtValidation, "- [ ] T002 Writer", "checked ahead of T001, so unchecked again")
+}
+
+func TestOrchestrator_TaskDependencyCycle(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] T001 A {depends: T002}\n- [ ] T002 B {depends: T001}\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+
+	orchestrator := NewOrchestrator(cfg)
+	implRunner := &MockOrchestratorAIRunner{}
+	orchestrator.CommandChecker = alwaysAvailable
+	orchestrator.StateDir = tmpDir
+	orchestrator.ImplRunner = implRunner
+
+	assert.Equal(t, exitcode.Error, orchestrator.Run(context.Background()))
+	assert.Equal(t, 0, implRunner.CallCount)
+}
+
+func TestOrchestrator_NoDependenciesKeepsPrompt(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] T001 A\n"), 0644))
+
+	orchestrator := NewOrchestrator(config.NewDefaultConfig())
+	orchestrator.session = &state.SessionState{TasksFile: tasksFile}
+	assert.Nil(t, orchestrator.readyTaskLines())
+}
+
+func TestOrchestrator_MultipleTasksFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	specA := filepath.Join(tmpDir, "specs", "001-a", "tasks.md")
+	specB := filepath.Join(tmpDir, "specs", "002-b", "tasks.md")
+	specC := filepath.Join(tmpDir, "specs", "003-c", "tasks.md")
+	for path, content := range map[string]string{
+		specA: "- [ ] T001 A\n",
+		specB: "---\npriority: 1\n---\n- [ ] T001 B\n- [ ] T002 B2\n",
+		specC: "- [x] T001 C\n",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFiles = []string{filepath.Join(tmpDir, "specs", "**", "tasks.md")}
+	cfg.TasksFile = cfg.TasksFiles[0]
+	cfg.CrossValidate = false
+	cfg.FinalPlanAI = ""
+	cfg.TasksValAI = ""
+
+	orchestrator := NewOrchestrator(cfg)
+	var worked []string
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			file := orchestrator.session.TasksFile
+			worked = append(worked, file)
+			data, _ := os.ReadFile(file)
+			_ = os.WriteFile(file, []byte(strings.ReplaceAll(string(data), "- [ ]", "- [x]")), 0644)
+			return os.WriteFile(outputPath, []byte("Implementation output"), 0644)
+		},
+	}
+	valRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("COMPLETE", "Done")), 0644)
+		},
+	}
+	orchestrator.CommandChecker = alwaysAvailable
+	orchestrator.StateDir = tmpDir
+	orchestrator.ImplRunner = implRunner
+	orchestrator.ValRunner = valRunner
+
+	assert.Equal(t, exitcode.Success, orchestrator.Run(context.Background()))
+
+	assert.Equal(t, []string{specB, specA}, worked, "priority 1 runs first; the complete spec is skipped")
+	saved, err := state.LoadState(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, state.StatusComplete, saved.Status)
+	assert.Equal(t, []state.SpecProgress{
+		{TasksFile: specB, Status: state.SpecDone, FirstIteration: 1, LastIteration: 1, Done: 2, Total: 2},
+		{TasksFile: specA, Status: state.SpecDone, FirstIteration: 2, LastIteration: 2, Done: 1, Total: 1},
+		{TasksFile: specC, Status: state.SpecDone, Done: 1, Total: 1},
+	}, saved.Specs)
+}
+
+func TestOrchestrator_MultipleTasksFilesAllChecked(t *testing.T) {
+	tmpDir := t.TempDir()
+	first := filepath.Join(tmpDir, "a.md")
+	second := filepath.Join(tmpDir, "b.md")
+	require.NoError(t, os.WriteFile(first, []byte("- [x] T001 A\n"), 0644))
+	require.NoError(t, os.WriteFile(second, []byte("- [x] T001 B\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFiles = []string{first, second}
+	cfg.TasksFile = first
+
+	orchestrator := NewOrchestrator(cfg)
+	implRunner := &MockOrchestratorAIRunner{}
+	orchestrator.CommandChecker = alwaysAvailable
+	orchestrator.StateDir = tmpDir
+	orchestrator.ImplRunner = implRunner
+
+	assert.Equal(t, exitcode.Success, orchestrator.Run(context.Background()))
+	assert.Equal(t, 0, implRunner.CallCount)
+}
+
+func TestOrchestrator_MultipleTasksFilesNoMatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFiles = []string{filepath.Join(tmpDir, "specs", "**", "tasks.md")}
+	cfg.TasksFile = cfg.TasksFiles[0]
+
+	orchestrator := NewOrchestrator(cfg)
+	orchestrator.CommandChecker = alwaysAvailable
+	orchestrator.StateDir = tmpDir
+
+	assert.Equal(t, exitcode.Error, orchestrator.Run(context.Background()))
+}
+
+func TestOrchestrator_SpecDirWiresSpecAndPlan(t *testing.T) {
+	tmpDir := t.TempDir()
+	specDir := filepath.Join(tmpDir, "specs", "001-albums")
+	require.NoError(t, os.MkdirAll(specDir, 0755))
+	tasksFile := filepath.Join(specDir, "tasks.md")
+	specFile := filepath.Join(specDir, "spec.md")
+	planFile := filepath.Join(specDir, "plan.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] T001 Albums\n"), 0644))
+	require.NoError(t, os.WriteFile(specFile, []byte("# Spec\n"), 0644))
+	require.NoError(t, os.WriteFile(planFile, []byte("# Plan\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.SpecDir = specDir
+	cfg.MaxIterations = 1
+	cfg.CrossValidate = false
+	cfg.FinalPlanAI = ""
+
+	orchestrator := NewOrchestrator(cfg)
+	tasksValRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte(`{"RALPH_TASKS_VALIDATION":{"verdict":"INVALID","feedback":"Missing sharing"}}`), 0644)
+		},
+	}
+	orchestrator.CommandChecker = alwaysAvailable
+	orchestrator.StateDir = tmpDir
+	orchestrator.ImplRunner = &MockOrchestratorAIRunner{}
+	orchestrator.TasksValRunner = tasksValRunner
+
+	assert.Equal(t, exitcode.TasksInvalid, orchestrator.Run(context.Background()))
+
+	assert.Equal(t, tasksFile, cfg.TasksFile)
+	assert.Equal(t, specFile, cfg.SpecFile)
+	assert.Equal(t, planFile, cfg.OriginalPlanFile)
+	require.Len(t, tasksValRunner.PromptLog, 1)
+	assert.Contains(t, tasksValRunner.PromptLog[0], specFile)
+}
+
+func TestOrchestrator_WireSpecKitPerSpec(t *testing.T) {
+	tmpDir := t.TempDir()
+	first := filepath.Join(tmpDir, "001-a")
+	second := filepath.Join(tmpDir, "002-b")
+	require.NoError(t, os.MkdirAll(first, 0755))
+	require.NoError(t, os.MkdirAll(second, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(first, "spec.md"), []byte("# A\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(first, "plan.md"), []byte("# A\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(second, "spec.md"), []byte("# B\n"), 0644))
+	for _, dir := range []string{first, second} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "tasks.md"), []byte("- [ ] T001 x\n"), 0644))
+	}
+
+	orchestrator := NewOrchestrator(config.NewDefaultConfig())
+	orchestrator.wireSpecKit(filepath.Join(first, "tasks.md"))
+	assert.Equal(t, filepath.Join(first, "spec.md"), orchestrator.specFile())
+	assert.Equal(t, filepath.Join(first, "plan.md"), orchestrator.Config.OriginalPlanFile)
+
+	orchestrator.wireSpecKit(filepath.Join(second, "tasks.md"))
+	assert.Equal(t, filepath.Join(second, "spec.md"), orchestrator.specFile())
+	assert.Empty(t, orchestrator.Config.OriginalPlanFile, "the first spec's plan is dropped")
+
+	explicit := NewOrchestrator(config.NewDefaultConfig())
+	explicit.Config.GithubIssue = "42"
+	explicit.wireSpecKit(filepath.Join(first, "tasks.md"))
+	assert.Empty(t, explicit.Config.SpecFile, "an issue stays the spec")
+}
+
+func TestOrchestrator_CollectsArtifacts(t *testing.T) {
+	tmpDir := t.TempDir()
+	workDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] T001 Login page\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(workDir, "shots"), 0755))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 1
+	cfg.CrossValidate = false
+	cfg.FinalPlanAI = ""
+	cfg.TasksValAI = ""
+
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			require.NoError(t, os.WriteFile(filepath.Join(workDir, "junit.xml"), []byte("<testsuite/>"), 0644))
+			return os.WriteFile(outputPath, []byte("Done.\n```json\n{\"RALPH_ARTIFACTS\": [{\"path\": \"junit.xml\", \"kind\": \"test_log\"}, \"missing.log\"]}\n```\n"), 0644)
+		},
+	}
+	valRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			require.NoError(t, os.WriteFile(filepath.Join(workDir, "shots", "login.png"), []byte("png"), 0644))
+			out := makeOrchestratorValidationJSON("NEEDS_MORE_WORK", "Button misaligned") +
+				"\n```json\n{\"RALPH_ARTIFACTS\": [{\"path\": \"shots/login.png\", \"kind\": \"screenshot\"}]}\n```\n"
+			return os.WriteFile(outputPath, []byte(out), 0644)
+		},
+	}
+
+	orchestrator := NewOrchestrator(cfg)
+	orchestrator.CommandChecker = alwaysAvailable
+	orchestrator.StateDir = tmpDir
+	orchestrator.WorkDir = workDir
+	orchestrator.ImplRunner = implRunner
+	orchestrator.ValRunner = valRunner
+
+	assert.Equal(t, exitcode.MaxIterations, orchestrator.Run(context.Background()))
+
+	artifactDir := filepath.Join(tmpDir, "iteration-001", "artifacts")
+	assert.FileExists(t, filepath.Join(artifactDir, "junit.xml"))
+	assert.FileExists(t, filepath.Join(artifactDir, "login.png"))
+
+	saved, err := state.LoadState(tmpDir)
+	require.NoError(t, err)
+	require.Len(t, saved.Artifacts, 2)
+	assert.Equal(t, state.Artifact{Iteration: 1, Phase: state.PhaseImplementation, Source: "junit.xml", Path: filepath.Join(artifactDir, "junit.xml"), Kind: "test_log", Size: 12}, saved.Artifacts[0])
+	assert.Equal(t, state.PhaseValidation, saved.Artifacts[1].Phase)
+	assert.Equal(t, "screenshot", saved.Artifacts[1].Kind)
+}
+
+func TestOrchestrator_ReplaysRecordedSession(t *testing.T) {
+	recDir := filepath.Join(t.TempDir(), "recording")
+	// Both runs use the same directory, as a replay in the recorded
+	// project would, so that the prompts naming its files match.
+	workDir := t.TempDir()
+	newOrch := func(impl, val ai.AIRunner) *Orchestrator {
+		require.NoError(t, os.RemoveAll(workDir))
+		require.NoError(t, os.MkdirAll(workDir, 0755))
+		cfg := config.NewDefaultConfig()
+		cfg.MaxIterations = 2
+		o := newTestOrchestrator(t, cfg, workDir, &MockOrchestratorAIRunner{}, &MockOrchestratorAIRunner{})
+		o.ImplRunner, o.ValRunner = impl, val
+		return o
+	}
+
+	impl := &MockOrchestratorAIRunner{RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+		return os.WriteFile(outputPath, []byte("Implemented T001."), 0644)
+	}}
+	val := &MockOrchestratorAIRunner{}
+	val.RunFunc = func(ctx context.Context, prompt string, outputPath string) error {
+		feedback := []string{"Add error handling", "Add tests"}[val.CallCount-1]
+		return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("NEEDS_MORE_WORK", feedback)), 0644)
+	}
+	rec := ai.NewRecording(recDir)
+	recorded := newOrch(
+		&ai.RecordingRunner{Inner: impl, Recording: rec, Phase: "IMPL"},
+		&ai.RecordingRunner{Inner: val, Recording: rec, Phase: "VAL"},
+	)
+	require.Equal(t, exitcode.MaxIterations, recorded.Run(context.Background()))
+	iterations := []string{"iteration-001", "iteration-002"}
+	var want []string
+	for _, iter := range iterations {
+		data, err := os.ReadFile(filepath.Join(workDir, iter, "validation-output.txt"))
+		require.NoError(t, err)
+		want = append(want, string(data))
+	}
+
+	replayRec := ai.NewRecording(recDir)
+	var mismatches []string
+	onMismatch := func(phase string, call int) { mismatches = append(mismatches, phase) }
+	replayed := newOrch(
+		&ai.ReplayRunner{Recording: replayRec, Phase: "IMPL", OnPromptMismatch: onMismatch},
+		&ai.ReplayRunner{Recording: replayRec, Phase: "VAL", OnPromptMismatch: onMismatch},
+	)
+	assert.Equal(t, exitcode.MaxIterations, replayed.Run(context.Background()))
+
+	for i, iter := range iterations {
+		got, err := os.ReadFile(filepath.Join(workDir, iter, "validation-output.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, want[i], string(got), iter)
+	}
+	assert.Empty(t, mismatches, "the replay sends the recorded prompts")
+	assert.Equal(t, 2, impl.CallCount, "the replay runs no AI")
+	assert.Equal(t, 2, val.CallCount)
+}
@@ -0,0 +1,120 @@
+package phases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/ai"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/prompt"
+	"github.com/CodexForgeBR/cli-tools/internal/schema"
+)
+
+// outputCheck describes how the block of an AI answer is checked and, when
+// it is unusable, re-requested from the model that wrote it.
+type outputCheck struct {
+	Runner ai.AIRunner
+	Block  string
+	Schema *schema.Schema
+	// Attempts is how many times the model is asked to re-emit the block.
+	Attempts int
+	// Required makes a missing or malformed block a violation too.
+	// Otherwise it is left to the caller's parser.
+	Required bool
+}
+
+// formatFixPath returns where the n-th re-emitted block of outputPath is
+// written: validation-output.txt -> validation-output-format-fix-1.txt.
+func formatFixPath(outputPath string, n int) string {
+	ext := filepath.Ext(outputPath)
+	return fmt.Sprintf("%s-format-fix-%d%s", strings.TrimSuffix(outputPath, ext), n, ext)
+}
+
+// blockErrors checks the block of text against s. found is false when the
+// block is absent; problems reports why, including malformed JSON.
+func blockErrors(text, block string, s *schema.Schema) (problems []string, found bool) {
+	value, nested, err := parser.ExtractBlock(text, block)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: the JSON cannot be parsed: %v", block, err)}, false
+	}
+	missing := []string{fmt.Sprintf("%s: no %s block found", block, block)}
+	if value == nil {
+		return missing, false
+	}
+	if !nested {
+		// Not nested under the key: only a block if it has block fields,
+		// otherwise the key was merely mentioned near some other JSON.
+		obj, ok := value.(map[string]interface{})
+		if !ok || !s.Describes(obj) {
+			return missing, false
+		}
+	}
+	return s.Validate(value), true
+}
+
+// conform checks the block in outputPath and, while it is unusable, asks
+// the runner up to Attempts times to re-emit it with the problems listed,
+// each time into formatFixPath(outputPath, n).
+//
+// It returns the file to parse the block from and the problems that
+// remain. A block the re-emitted answers leave out counts as unusable even
+// when it is not Required.
+func (c outputCheck) conform(ctx context.Context, outputPath string) (string, []string) {
+	path := outputPath
+	for n := 1; ; n++ {
+		data, err := os.ReadFile(path)
+		if err != nil && path == outputPath {
+			return outputPath, nil
+		}
+		problems, found := blockErrors(string(data), c.Block, c.Schema)
+		if !found && !c.Required && path == outputPath {
+			return outputPath, nil
+		}
+		if len(problems) == 0 {
+			if path != outputPath {
+				logging.Success(fmt.Sprintf("%s block corrected", c.Block))
+			}
+			return path, nil
+		}
+		if n > c.Attempts || ctx.Err() != nil {
+			return path, problems
+		}
+
+		logging.Warn(fmt.Sprintf("%s block is unusable, asking for a corrected one (%d/%d):\n  %s",
+			c.Block, n, c.Attempts, strings.Join(problems, "\n  ")))
+		fixPath := formatFixPath(outputPath, n)
+		if err := c.Runner.Run(ctx, prompt.BuildFormatFixPrompt(c.Block, path, problems, c.Schema.String()), fixPath); err != nil {
+			logging.Warn(fmt.Sprintf("Format fix failed: %v", err))
+			return path, problems
+		}
+		path = fixPath
+	}
+}
+
+// conformOutput is conform with a single fix attempt for blocks that are
+// optional or whose absence the caller reports.
+func conformOutput(ctx context.Context, runner ai.AIRunner, block, outputPath string, s *schema.Schema) (string, []string) {
+	return outputCheck{Runner: runner, Block: block, Schema: s, Attempts: 1}.conform(ctx, outputPath)
+}
+
+// conformStatus returns the file to read the implementer's RALPH_STATUS
+// block from, or "" when the block does not match its schema even after a
+// retry.
+func (o *Orchestrator) conformStatus(ctx context.Context, implOutputPath string) string {
+	path, errs := conformOutput(ctx, o.ImplRunner, "RALPH_STATUS", implOutputPath, schema.MustLookup("RALPH_STATUS"))
+	if len(errs) > 0 {
+		logging.Warn(formatFeedback("RALPH_STATUS", errs) + "\nIgnoring it.")
+		return ""
+	}
+	return path
+}
+
+// formatFeedback describes problems with a block that survived the fix
+// retries.
+func formatFeedback(block string, errs []string) string {
+	return fmt.Sprintf("%s output was unusable, even after asking for a corrected one:\n- %s", block, strings.Join(errs, "\n- "))
+}
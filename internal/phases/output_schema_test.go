@@ -0,0 +1,258 @@
+package phases
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/schema"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// writesOutput returns a RunFunc that writes the given outputs on
+// successive calls, repeating the last one.
+func writesOutput(outputs ...string) func(ctx context.Context, prompt string, outputPath string) error {
+	call := 0
+	return func(ctx context.Context, prompt string, outputPath string) error {
+		out := outputs[min(call, len(outputs)-1)]
+		call++
+		return os.WriteFile(outputPath, []byte(out), 0644)
+	}
+}
+
+func TestFormatFixPath(t *testing.T) {
+	assert.Equal(t, "/x/validation-output-format-fix-1.txt", formatFixPath("/x/validation-output.txt", 1))
+	assert.Equal(t, "/x/out-format-fix-2", formatFixPath("/x/out", 2))
+}
+
+func TestBlockErrors(t *testing.T) {
+	s := schema.MustLookup("RALPH_STATUS")
+
+	errs, found := blockErrors(`{"RALPH_STATUS": {"completed_tasks": "T001"}}`, "RALPH_STATUS", s)
+	assert.True(t, found)
+	assert.Equal(t, []string{"RALPH_STATUS.completed_tasks: expected array, got string \"T001\""}, errs)
+
+	errs, found = blockErrors("Recorded in RALPH_STATUS.notes: {\"tests\": 12}", "RALPH_STATUS", s)
+	assert.False(t, found, "a mention of the key next to unrelated JSON is not the block")
+	assert.Equal(t, []string{"RALPH_STATUS: no RALPH_STATUS block found"}, errs)
+
+	errs, found = blockErrors("no block", "RALPH_STATUS", s)
+	assert.False(t, found)
+	assert.Equal(t, []string{"RALPH_STATUS: no RALPH_STATUS block found"}, errs)
+
+	errs, found = blockErrors("```json\n{\"RALPH_STATUS\": {\"notes\": }\n```", "RALPH_STATUS", s)
+	assert.False(t, found)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "RALPH_STATUS: the JSON cannot be parsed")
+}
+
+func TestConformOutput_ConformingOutputIsKept(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "validation-output.txt")
+	require.NoError(t, os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("COMPLETE", "")), 0644))
+	runner := &MockOrchestratorAIRunner{}
+
+	path, errs := conformOutput(context.Background(), runner, "RALPH_VALIDATION", outputPath, schema.MustLookup("RALPH_VALIDATION"))
+	assert.Equal(t, outputPath, path)
+	assert.Nil(t, errs)
+	assert.Zero(t, runner.CallCount)
+}
+
+func TestConformOutput_FixRetry(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "validation-output.txt")
+	require.NoError(t, os.WriteFile(outputPath, []byte(`{"RALPH_VALIDATION": {"verdict": "DONE", "feedback": "ok"}}`), 0644))
+	runner := &MockOrchestratorAIRunner{RunFunc: writesOutput(makeOrchestratorValidationJSON("COMPLETE", "ok"))}
+
+	path, errs := conformOutput(context.Background(), runner, "RALPH_VALIDATION", outputPath, schema.MustLookup("RALPH_VALIDATION"))
+	assert.Nil(t, errs)
+	assert.Equal(t, formatFixPath(outputPath, 1), path)
+	require.Equal(t, 1, runner.CallCount)
+	assert.Contains(t, runner.PromptLog[0], outputPath)
+	assert.Contains(t, runner.PromptLog[0], `- RALPH_VALIDATION.verdict: "DONE" is not one of`)
+	assert.Contains(t, runner.PromptLog[0], `"NEEDS_MORE_WORK"`, "the schema is shown")
+}
+
+func TestConformOutput_FixStillInvalid(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "validation-output.txt")
+	require.NoError(t, os.WriteFile(outputPath, []byte(`{"RALPH_VALIDATION": {"verdict": "DONE"}}`), 0644))
+	runner := &MockOrchestratorAIRunner{RunFunc: writesOutput(`{"RALPH_VALIDATION": {"verdict": "FINISHED"}}`)}
+
+	path, errs := conformOutput(context.Background(), runner, "RALPH_VALIDATION", outputPath, schema.MustLookup("RALPH_VALIDATION"))
+	assert.Equal(t, formatFixPath(outputPath, 1), path)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0], `"FINISHED" is not one of`)
+}
+
+func TestConformOutput_FixFails(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "validation-output.txt")
+	require.NoError(t, os.WriteFile(outputPath, []byte(`{"RALPH_VALIDATION": {"verdict": "DONE"}}`), 0644))
+	runner := &MockOrchestratorAIRunner{RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+		return errors.New("cli crashed")
+	}}
+
+	path, errs := conformOutput(context.Background(), runner, "RALPH_VALIDATION", outputPath, schema.MustLookup("RALPH_VALIDATION"))
+	assert.Equal(t, outputPath, path)
+	assert.Len(t, errs, 1)
+}
+
+func TestRunValidationPhaseWithResult_FixedBlockIsUsed(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "validation-output.txt")
+	runner := &MockOrchestratorAIRunner{RunFunc: writesOutput(
+		`{"RALPH_VALIDATION": {"verdict": "NEEDS_MORE_WORK", "feedback": "T002 untested", "incomplete_tasks": "T002"}}`,
+		`{"RALPH_VALIDATION": {"verdict": "NEEDS_MORE_WORK", "feedback": "T002 untested", "incomplete_tasks": ["T002"]}}`,
+	)}
+
+	result, err := RunValidationPhaseWithResult(context.Background(), ValidationConfig{Runner: runner, OutputPath: outputPath, Prompt: "validate", FormatRetries: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "NEEDS_MORE_WORK", result.Verdict)
+	assert.Equal(t, []string{"T002"}, result.IncompleteTasks)
+}
+
+func TestRunValidationPhaseWithResult_CustomVerdictConforms(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "validation-output.txt")
+	runner := &MockOrchestratorAIRunner{RunFunc: writesOutput(makeOrchestratorValidationJSON("SECURITY_REVIEW_NEEDED", "auth"))}
+
+	result, err := RunValidationPhaseWithResult(context.Background(), ValidationConfig{
+		Runner: runner, OutputPath: outputPath, Prompt: "validate", CustomVerdicts: []string{"SECURITY_REVIEW_NEEDED"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "SECURITY_REVIEW_NEEDED", result.Verdict)
+	assert.Equal(t, 1, runner.CallCount)
+}
+
+func TestRunValidationPhaseWithResult_StillInvalidIsInadmissible(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "validation-output.txt")
+	runner := &MockOrchestratorAIRunner{RunFunc: writesOutput(`{"RALPH_VALIDATION": {"verdict": "COMPLETE", "confidence": "high"}}`)}
+
+	result, err := RunValidationPhaseWithResult(context.Background(), ValidationConfig{Runner: runner, OutputPath: outputPath, Prompt: "validate", FormatRetries: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "INADMISSIBLE", result.Verdict)
+	assert.Contains(t, result.Feedback, `RALPH_VALIDATION.confidence: expected number, got string "high"`)
+	assert.Equal(t, 2, runner.CallCount)
+}
+
+// TestOrchestrator_InvalidStatusIsFixed verifies that the implementer is
+// asked once to correct a RALPH_STATUS block that does not match its
+// schema, and that the corrected block drives task tracking.
+func TestOrchestrator_InvalidStatusIsFixed(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+
+	impl := &MockOrchestratorAIRunner{RunFunc: writesOutput(
+		`Done. {"RALPH_STATUS": {"completed_tasks": "T001", "notes": "added it"}}`,
+		`{"RALPH_STATUS": {"completed_tasks": ["T001"], "notes": "added it"}}`,
+	)}
+	val := &MockOrchestratorAIRunner{RunFunc: writesOutput(makeOrchestratorValidationJSON("NEEDS_MORE_WORK", "tests missing"))}
+	o := newTestOrchestrator(t, cfg, tmpDir, impl, val)
+
+	assert.Equal(t, exitcode.MaxIterations, o.Run(context.Background()))
+	require.Equal(t, 2, impl.CallCount)
+	assert.Contains(t, impl.PromptLog[1], `RALPH_STATUS.completed_tasks: expected array, got string "T001"`)
+
+	saved, err := state.LoadState(tmpDir)
+	require.NoError(t, err)
+	ts := state.FindTask(saved, "T001")
+	require.NotNil(t, ts)
+	assert.Equal(t, 1, ts.LastIteration)
+	assert.Equal(t, []string{"NEEDS_MORE_WORK"}, ts.Verdicts)
+}
+
+func TestConformOutput_OptionalBlockMayBeMissing(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "implementation-output.txt")
+	require.NoError(t, os.WriteFile(outputPath, []byte("Done, no status."), 0644))
+	runner := &MockOrchestratorAIRunner{}
+
+	path, errs := conformOutput(context.Background(), runner, "RALPH_STATUS", outputPath, schema.MustLookup("RALPH_STATUS"))
+	assert.Equal(t, outputPath, path)
+	assert.Nil(t, errs)
+	assert.Zero(t, runner.CallCount)
+}
+
+func TestOutputCheck_RepairsUnparseableOutput(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "validation-output.txt")
+	require.NoError(t, os.WriteFile(outputPath, []byte("```json\n{\"RALPH_VALIDATION\": {\"verdict\": \"COMPLETE\",}}\n```"), 0644))
+	runner := &MockOrchestratorAIRunner{RunFunc: writesOutput(
+		"I already answered.",
+		makeOrchestratorValidationJSON("COMPLETE", "all done"),
+	)}
+	check := outputCheck{Runner: runner, Block: "RALPH_VALIDATION", Schema: schema.MustLookup("RALPH_VALIDATION"), Attempts: 3, Required: true}
+
+	path, errs := check.conform(context.Background(), outputPath)
+	assert.Nil(t, errs)
+	assert.Equal(t, formatFixPath(outputPath, 2), path)
+	require.Equal(t, 2, runner.CallCount)
+	assert.Contains(t, runner.PromptLog[0], "RALPH_VALIDATION: the JSON cannot be parsed")
+	assert.Contains(t, runner.PromptLog[0], outputPath)
+	assert.Contains(t, runner.PromptLog[1], "RALPH_VALIDATION: no RALPH_VALIDATION block found")
+	assert.Contains(t, runner.PromptLog[1], formatFixPath(outputPath, 1), "each repair sees the previous answer")
+}
+
+func TestOutputCheck_GivesUpAfterAttempts(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "validation-output.txt")
+	require.NoError(t, os.WriteFile(outputPath, []byte("No JSON here."), 0644))
+	runner := &MockOrchestratorAIRunner{RunFunc: writesOutput("Still no JSON.")}
+	check := outputCheck{Runner: runner, Block: "RALPH_VALIDATION", Schema: schema.MustLookup("RALPH_VALIDATION"), Attempts: 2, Required: true}
+
+	path, errs := check.conform(context.Background(), outputPath)
+	assert.Equal(t, formatFixPath(outputPath, 2), path)
+	assert.Equal(t, []string{"RALPH_VALIDATION: no RALPH_VALIDATION block found"}, errs)
+	assert.Equal(t, 2, runner.CallCount)
+
+	check.Attempts = 0
+	_, errs = check.conform(context.Background(), outputPath)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, 2, runner.CallCount, "no repair calls with 0 attempts")
+}
+
+// TestOrchestrator_UnparseableValidationIsRepaired verifies that the
+// validator is asked to re-emit an unparseable RALPH_VALIDATION block
+// and the repaired verdict is used.
+func TestOrchestrator_UnparseableValidationIsRepaired(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+
+	impl := &MockOrchestratorAIRunner{}
+	val := &MockOrchestratorAIRunner{RunFunc: writesOutput(
+		"Everything is done but I forgot the JSON.",
+		`{"RALPH_VALIDATION": {"verdict": "NEEDS_MORE_WORK", "feedback": "T001 has no tests"}}`,
+	)}
+	o := newTestOrchestrator(t, cfg, tmpDir, impl, val)
+
+	assert.Equal(t, exitcode.MaxIterations, o.Run(context.Background()))
+	assert.Equal(t, 2, val.CallCount)
+
+	saved, err := state.LoadState(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, "NEEDS_MORE_WORK", saved.Verdict)
+	assert.Zero(t, saved.InadmissibleCount)
+}
+
+// TestOrchestrator_UnrepairableValidationIsInadmissible verifies that the
+// iteration only counts as inadmissible once MAX_FORMAT_RETRIES repairs
+// have failed.
+func TestOrchestrator_UnrepairableValidationIsInadmissible(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	cfg.MaxFormatRetries = 3
+
+	impl := &MockOrchestratorAIRunner{}
+	val := &MockOrchestratorAIRunner{RunFunc: writesOutput("Looks fine to me.")}
+	o := newTestOrchestrator(t, cfg, tmpDir, impl, val)
+
+	assert.Equal(t, exitcode.MaxIterations, o.Run(context.Background()))
+	assert.Equal(t, 4, val.CallCount, "validation plus MAX_FORMAT_RETRIES repairs")
+
+	saved, err := state.LoadState(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, "INADMISSIBLE", saved.Verdict)
+	assert.Equal(t, 1, saved.InadmissibleCount)
+}
@@ -0,0 +1,52 @@
+package phases
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/patch"
+)
+
+// applyImplPatch extracts the RALPH_PATCH diff from the implementation
+// output, saves it to iterDir/implementation.patch and applies it to the
+// project. A missing block is not an error: the validator judges whether
+// work was left undone. A diff that fails to parse or apply leaves the tree
+// untouched and is returned as an error.
+func (o *Orchestrator) applyImplPatch(outputPath, iterDir string) error {
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		return fmt.Errorf("read implementation output: %w", err)
+	}
+	diff := parser.ExtractPatch(string(data))
+	if diff == "" {
+		logging.Warn("No RALPH_PATCH block in implementation output; no changes applied")
+		return nil
+	}
+	if err := os.WriteFile(filepath.Join(iterDir, "implementation.patch"), []byte(diff), 0644); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save patch: %v", err))
+	}
+
+	files, err := patch.Parse(diff)
+	if err != nil {
+		return fmt.Errorf("parse patch: %w", err)
+	}
+	dir := o.WorkDir
+	if dir == "" {
+		dir = "."
+	}
+	if err := patch.Apply(dir, files); err != nil {
+		return err
+	}
+	logging.Success(fmt.Sprintf("Applied patch to %d file(s)", len(files)))
+	return nil
+}
+
+// patchFailureFeedback is the feedback given to the implementer when its
+// patch could not be applied.
+func patchFailureFeedback(err error) string {
+	return fmt.Sprintf("Your RALPH_PATCH could not be applied, so none of your changes were made: %v\n"+
+		"Re-read the current files and produce a new diff against them.", err)
+}
@@ -0,0 +1,105 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+)
+
+const checkOffPatch = "Done.\n\nRALPH_PATCH\n```diff\n--- a/tasks.md\n+++ b/tasks.md\n@@ -1,2 +1,2 @@\n # Tasks\n-- [ ] T001 Create setup\n+- [x] T001 Create setup\n```\n"
+
+// TestOrchestrator_ApplyPatchMode verifies that the implementer is asked for
+// a diff and that ralph-loop applies it before validation.
+func TestOrchestrator_ApplyPatchMode(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("# Tasks\n- [ ] T001 Create setup\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 1
+	cfg.CrossValidate = false
+	cfg.EnableLearnings = false
+	cfg.ApplyPatch = true
+
+	var implPrompt string
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			implPrompt = prompt
+			return os.WriteFile(outputPath, []byte(checkOffPatch), 0644)
+		},
+	}
+	var tasksAtValidation string
+	valRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			data, err := os.ReadFile(tasksFile)
+			require.NoError(t, err)
+			tasksAtValidation = string(data)
+			return os.WriteFile(outputPath, []byte(`{"RALPH_VALIDATION": {"verdict": "COMPLETE", "feedback": "ok"}}`), 0644)
+		},
+	}
+
+	orch := NewOrchestrator(cfg)
+	orch.CommandChecker = alwaysAvailable
+	orch.StateDir = tmpDir
+	orch.WorkDir = tmpDir
+	orch.ImplRunner = implRunner
+	orch.ValRunner = valRunner
+
+	assert.Equal(t, exitcode.Success, orch.Run(context.Background()))
+	assert.Contains(t, implPrompt, "PATCH MODE")
+	assert.Equal(t, "# Tasks\n- [x] T001 Create setup\n", tasksAtValidation)
+	assert.FileExists(t, filepath.Join(tmpDir, "iteration-001", "implementation.patch"))
+}
+
+// TestOrchestrator_ApplyPatchConflict verifies that a patch that does not
+// apply skips validation and is reported back to the implementer.
+func TestOrchestrator_ApplyPatchConflict(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("# Tasks\n- [ ] T001 Create setup\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 2
+	cfg.CrossValidate = false
+	cfg.EnableLearnings = false
+	cfg.ApplyPatch = true
+
+	var prompts []string
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			prompts = append(prompts, prompt)
+			out := checkOffPatch
+			if len(prompts) == 1 {
+				out = "RALPH_PATCH\n```diff\n--- a/tasks.md\n+++ b/tasks.md\n@@ -1 +1 @@\n-# Wrong heading\n+# Tasks\n```\n"
+			}
+			return os.WriteFile(outputPath, []byte(out), 0644)
+		},
+	}
+	valRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte(`{"RALPH_VALIDATION": {"verdict": "COMPLETE", "feedback": "ok"}}`), 0644)
+		},
+	}
+
+	orch := NewOrchestrator(cfg)
+	orch.CommandChecker = alwaysAvailable
+	orch.StateDir = tmpDir
+	orch.WorkDir = tmpDir
+	orch.ImplRunner = implRunner
+	orch.ValRunner = valRunner
+
+	assert.Equal(t, exitcode.Success, orch.Run(context.Background()))
+	require.Len(t, prompts, 2)
+	assert.Equal(t, 1, valRunner.CallCount, "validation is skipped after a failed patch")
+	assert.Contains(t, prompts[1], "Your RALPH_PATCH could not be applied")
+	assert.Contains(t, prompts[1], "tasks.md: hunk 1: context does not match")
+}
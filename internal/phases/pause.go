@@ -0,0 +1,56 @@
+package phases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/banner"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/notification"
+	"github.com/CodexForgeBR/cli-tools/internal/schedule"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// waitPauseWindow sleeps until the current PAUSE_BETWEEN window ends, if
+// one is active. State is saved first so an interrupted pause resumes
+// where it left off. It returns how long it slept, and an exit code other
+// than -1 when the wait was interrupted.
+func (o *Orchestrator) waitPauseWindow(ctx context.Context, windows []schedule.PauseWindow) (time.Duration, int) {
+	until, paused := schedule.PausedUntil(windows, time.Now())
+	if !paused || until.Equal(o.pauseSkippedUntil) {
+		return 0, -1
+	}
+
+	logging.Phase(fmt.Sprintf("Pause window active, no iteration starts until %s", until.Format("2006-01-02 15:04")))
+	phase := o.session.Phase
+	o.session.Phase = state.PhasePaused
+	o.Dashboard.SetPhase(state.PhasePaused)
+	if err := state.SaveState(o.session, o.StateDir); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save paused state: %v", err))
+	}
+
+	start := time.Now()
+	woken, err := o.waitUntil(ctx, until)
+	if err != nil {
+		banner.PrintInterruptedBanner(o.session.Iteration, o.session.Phase)
+		o.notify(notification.EventInterrupted, exitcode.Result{Code: exitcode.Interrupted, Reason: o.interruptReason("interrupted during a pause window")})
+		if saveErr := state.SaveState(o.session, o.StateDir); saveErr != nil {
+			logging.Warn(fmt.Sprintf("Failed to save interrupted state: %v", saveErr))
+		}
+		return time.Since(start), exitcode.Interrupted
+	}
+	o.session.Phase = phase
+	if code := o.stopForShutdown("during a pause window"); code >= 0 {
+		return time.Since(start), code
+	}
+	if woken {
+		// The rest of this window is skipped, not waited for again.
+		o.pauseSkippedUntil = until
+		logging.Success("Pause window cut short by ralph-loop resume-now, resuming")
+	} else {
+		logging.Success("Pause window over, resuming")
+	}
+	return time.Since(start), -1
+}
@@ -0,0 +1,76 @@
+package phases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/schedule"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// currentWindow returns a PAUSE_BETWEEN value for a window around now.
+func currentWindow() string {
+	now := time.Now()
+	return fmt.Sprintf("%s-%s", now.Add(-time.Minute).Format("15:04"), now.Add(2*time.Minute).Format("15:04"))
+}
+
+// TestOrchestrator_PauseWindowSavesStateAndSleeps verifies that no
+// iteration starts inside a pause window and that an interrupted pause
+// leaves the state saved as paused.
+func TestOrchestrator_PauseWindowSavesStateAndSleeps(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cfg.PauseBetween = currentWindow()
+
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte("Done."), 0644)
+		},
+	}
+	o := newTestOrchestrator(t, cfg, tmpDir, implRunner, &MockOrchestratorAIRunner{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, exitcode.Interrupted, o.Run(ctx))
+	assert.Zero(t, implRunner.CallCount)
+
+	saved, err := state.LoadState(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, state.PhasePaused, saved.Phase)
+	assert.Zero(t, saved.Iteration)
+}
+
+// TestOrchestrator_InvalidPauseWindow verifies that a malformed
+// PAUSE_BETWEEN stops the run before any iteration.
+func TestOrchestrator_InvalidPauseWindow(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cfg.PauseBetween = "after lunch"
+
+	implRunner := &MockOrchestratorAIRunner{}
+	o := newTestOrchestrator(t, cfg, tmpDir, implRunner, &MockOrchestratorAIRunner{})
+
+	assert.Equal(t, exitcode.Error, o.Run(context.Background()))
+	assert.Zero(t, implRunner.CallCount)
+}
+
+// TestWaitPauseWindow_OutsideWindow verifies that nothing happens outside
+// the configured windows.
+func TestWaitPauseWindow_OutsideWindow(t *testing.T) {
+	later := time.Now().Add(3 * time.Hour)
+	windows, err := schedule.ParsePauseWindows(fmt.Sprintf("%s-%s", later.Format("15:04"), later.Add(time.Hour).Format("15:04")))
+	require.NoError(t, err)
+
+	o := &Orchestrator{Config: &config.Config{}, session: &state.SessionState{Phase: state.PhaseValidation}, StateDir: t.TempDir()}
+	slept, code := o.waitPauseWindow(context.Background(), windows)
+	assert.Equal(t, -1, code)
+	assert.Zero(t, slept)
+	assert.Equal(t, state.PhaseValidation, o.session.Phase)
+}
@@ -0,0 +1,105 @@
+package phases
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/CodexForgeBR/cli-tools/internal/ai"
+	"github.com/CodexForgeBR/cli-tools/internal/iterlog"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/statecrypt"
+)
+
+// Names of the per-iteration logs, see iterlog.Phases.
+const (
+	logImpl      = "impl"
+	logTriage    = "triage"
+	logVal       = "val"
+	logCross     = "cross"
+	logFinalPlan = "final-plan"
+)
+
+// withPhaseLog returns ctx with the AI CLIs' raw output copied to the
+// phase's log in dir, and a func that closes the log. Without a dir, or
+// when the log cannot be opened, ctx is returned unchanged: logs are a
+// convenience and never stop a phase.
+func withPhaseLog(ctx context.Context, dir, phase string, maxBytes int64) (context.Context, func()) {
+	if dir == "" {
+		return ctx, func() {}
+	}
+	w, err := iterlog.Open(iterlog.Path(dir, phase), maxBytes)
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Failed to open %s log: %v", phase, err))
+		return ctx, func() {}
+	}
+	return ai.WithOutputLog(ctx, w), func() { w.Close() }
+}
+
+// logMaxBytes is the size at which phase logs are rotated; 0 never.
+func (o *Orchestrator) logMaxBytes() int64 {
+	return int64(o.Config.LogMaxMB) << 20
+}
+
+// compressIterationLogs gzips the phase logs of a finished iteration when
+// --log-gzip is set.
+func (o *Orchestrator) compressIterationLogs(iteration int) {
+	if !o.Config.LogGzip || iteration < 1 {
+		return
+	}
+	iterDir := filepath.Join(o.StateDir, fmt.Sprintf("iteration-%03d", iteration))
+	if err := iterlog.Compress(iterDir); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to compress iteration %d logs: %v", iteration, err))
+	}
+}
+
+// compactIteration gzips the large outputs of a finished iteration when
+// --output-gzip-kb is set, and with --dedup-outputs makes its files
+// identical to the iteration before's hard links to them. It runs before
+// sealIteration, whose encryption leaves nothing to deduplicate.
+func (o *Orchestrator) compactIteration(iteration int) {
+	if iteration < 1 {
+		return
+	}
+	iterDir := filepath.Join(o.StateDir, fmt.Sprintf("iteration-%03d", iteration))
+	if o.Config.OutputGzipKB > 0 {
+		if err := iterlog.CompressOutputs(iterDir, int64(o.Config.OutputGzipKB)<<10); err != nil {
+			logging.Warn(fmt.Sprintf("Failed to compress iteration %d outputs: %v", iteration, err))
+		}
+	}
+	if o.Config.DedupOutputs && iteration > 1 {
+		prevDir := filepath.Join(o.StateDir, fmt.Sprintf("iteration-%03d", iteration-1))
+		if _, err := iterlog.Dedup(iterDir, prevDir); err != nil {
+			logging.Warn(fmt.Sprintf("Failed to deduplicate iteration %d outputs: %v", iteration, err))
+		}
+	}
+}
+
+// sealIteration encrypts the outputs of a finished iteration when a
+// state key is set (see statecrypt), after its logs are compressed.
+func (o *Orchestrator) sealIteration(iteration int) {
+	if iteration < 1 {
+		return
+	}
+	iterDir := filepath.Join(o.StateDir, fmt.Sprintf("iteration-%03d", iteration))
+	if err := statecrypt.SealDir(iterDir); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to encrypt iteration %d outputs: %v", iteration, err))
+	}
+}
+
+// pruneIterations removes the directories of the iterations before
+// iteration that --retain-iterations and --retain-mb do not keep.
+func (o *Orchestrator) pruneIterations(iteration int) {
+	r := state.Retention{Keep: o.Config.RetainIterations, MaxBytes: int64(o.Config.RetainMB) << 20}
+	if iteration < 1 || (r.Keep <= 0 && r.MaxBytes <= 0) {
+		return
+	}
+	removed, freed, err := state.PruneIterations(o.StateDir, r, iteration)
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Failed to remove old iteration directories: %v", err))
+	}
+	if len(removed) > 0 {
+		logging.Info(fmt.Sprintf("Removed the directories of %d old iteration(s), %.1f MB", len(removed), float64(freed)/(1<<20)))
+	}
+}
@@ -0,0 +1,190 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/iterlog"
+	"github.com/CodexForgeBR/cli-tools/internal/statecrypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPhaseLog_WithoutDir(t *testing.T) {
+	ctx := context.Background()
+	logCtx, closeLog := withPhaseLog(ctx, "", logCross, 0)
+	closeLog()
+	assert.Equal(t, ctx, logCtx)
+}
+
+func TestWithPhaseLog_UnwritableDir(t *testing.T) {
+	ctx := context.Background()
+	logCtx, closeLog := withPhaseLog(ctx, filepath.Join(t.TempDir(), "missing"), logVal, 0)
+	closeLog()
+	assert.Equal(t, ctx, logCtx, "a log that cannot be opened does not stop the phase")
+}
+
+// TestOrchestrator_PhaseLogsPerIteration verifies that every phase gets its
+// log in the iteration directory and that --log-gzip compresses the logs
+// of the iterations before the current one.
+func TestOrchestrator_PhaseLogsPerIteration(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 2
+	cfg.LogGzip = true
+
+	valRunner := &MockOrchestratorAIRunner{}
+	o := newTestOrchestrator(t, cfg, tmpDir, nil, valRunner)
+	cfg.CrossValidate = true
+	valRunner.RunFunc = func(ctx context.Context, prompt string, outputPath string) error {
+		if valRunner.CallCount == 1 {
+			return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("NEEDS_MORE_WORK", "T001 untested")), 0644)
+		}
+		return completeValidation(cfg, outputPath)
+	}
+	o.CrossRunner = &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte(makeOrchestratorCrossValidationJSON("CONFIRMED", "")), 0644)
+		},
+	}
+
+	require.Equal(t, exitcode.Success, o.Run(context.Background()))
+
+	first := filepath.Join(tmpDir, "iteration-001")
+	assert.FileExists(t, filepath.Join(first, "impl.log.gz"))
+	assert.FileExists(t, filepath.Join(first, "val.log.gz"))
+	assert.NoFileExists(t, filepath.Join(first, "impl.log"))
+	assert.FileExists(t, filepath.Join(first, "validation-output.txt"), "only logs are compressed")
+
+	second := filepath.Join(tmpDir, "iteration-002")
+	for _, name := range []string{"impl.log", "val.log", "cross.log"} {
+		assert.FileExists(t, filepath.Join(second, name))
+	}
+}
+
+// TestOrchestrator_SealsIterationOutputs verifies that, with a state key,
+// the outputs of every iteration are encrypted once it is over, the last
+// one when the session ends, along with the state.
+func TestOrchestrator_SealsIterationOutputs(t *testing.T) {
+	key, err := statecrypt.ParseKey("c3RhdGUta2V5LXN0YXRlLWtleS1zdGF0ZS1rZXktMzI=")
+	require.NoError(t, err)
+	statecrypt.SetKey(key)
+	t.Cleanup(func() { statecrypt.SetKey(nil) })
+
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 2
+	cfg.CrossValidate = false
+	cfg.LogGzip = true
+
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte("Implemented func proprietary()."), 0644)
+		},
+	}
+	valRunner := &MockOrchestratorAIRunner{}
+	o := newTestOrchestrator(t, cfg, tmpDir, implRunner, valRunner)
+	valRunner.RunFunc = func(ctx context.Context, prompt string, outputPath string) error {
+		if valRunner.CallCount == 1 {
+			return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("NEEDS_MORE_WORK", "T001 untested")), 0644)
+		}
+		return completeValidation(cfg, outputPath)
+	}
+
+	require.Equal(t, exitcode.Success, o.Run(context.Background()))
+
+	for _, path := range []string{
+		filepath.Join(tmpDir, "current-state.json"),
+		filepath.Join(tmpDir, "iteration-001", "implementation-output.txt"),
+		filepath.Join(tmpDir, "iteration-001", "impl.log.gz"),
+		filepath.Join(tmpDir, "iteration-002", "implementation-output.txt"),
+		filepath.Join(tmpDir, "iteration-002", "val.log"),
+	} {
+		raw, err := os.ReadFile(path)
+		require.NoError(t, err, path)
+		assert.True(t, statecrypt.IsEncrypted(raw), path)
+	}
+	data, err := statecrypt.ReadFile(filepath.Join(tmpDir, "iteration-001", "implementation-output.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "Implemented func proprietary().", string(data))
+	assert.Contains(t, implRunner.PromptLog[1], "T001 untested", "the feedback reached the next iteration")
+
+	require.NoError(t, statecrypt.OpenDir(filepath.Join(tmpDir, "iteration-002")))
+	raw, err := os.ReadFile(filepath.Join(tmpDir, "iteration-002", "implementation-output.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "Implemented func proprietary().", string(raw))
+}
+
+// TestOrchestrator_PrunesOldIterations verifies that --retain-iterations
+// removes the directories of older iterations as each one ends.
+func TestOrchestrator_PrunesOldIterations(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 3
+	cfg.CrossValidate = false
+	cfg.RetainIterations = 2
+
+	valRunner := &MockOrchestratorAIRunner{}
+	o := newTestOrchestrator(t, cfg, tmpDir, nil, valRunner)
+	valRunner.RunFunc = func(ctx context.Context, prompt string, outputPath string) error {
+		if valRunner.CallCount < 3 {
+			return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("NEEDS_MORE_WORK", "T001 untested")), 0644)
+		}
+		return completeValidation(cfg, outputPath)
+	}
+
+	require.Equal(t, exitcode.Success, o.Run(context.Background()))
+
+	assert.NoDirExists(t, filepath.Join(tmpDir, "iteration-001"))
+	assert.DirExists(t, filepath.Join(tmpDir, "iteration-002"))
+	assert.FileExists(t, filepath.Join(tmpDir, "iteration-003", "implementation-output.txt"))
+}
+
+// TestOrchestrator_CompactsFinishedIterations verifies that the large
+// outputs of finished iterations are gzipped and those identical to the
+// iteration before's are hard links to them, while the last iteration's
+// are left as they are.
+func TestOrchestrator_CompactsFinishedIterations(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 3
+	cfg.CrossValidate = false
+	cfg.OutputGzipKB = 1
+	cfg.DedupOutputs = true
+
+	output := strings.Repeat("Implemented T001.\n", 100)
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte(output), 0644)
+		},
+	}
+	valRunner := &MockOrchestratorAIRunner{}
+	o := newTestOrchestrator(t, cfg, tmpDir, implRunner, valRunner)
+	valRunner.RunFunc = func(ctx context.Context, prompt string, outputPath string) error {
+		if valRunner.CallCount < 3 {
+			return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("NEEDS_MORE_WORK", "T001 untested")), 0644)
+		}
+		return completeValidation(cfg, outputPath)
+	}
+
+	require.Equal(t, exitcode.Success, o.Run(context.Background()))
+
+	first := filepath.Join(tmpDir, "iteration-001", "implementation-output.txt")
+	second := filepath.Join(tmpDir, "iteration-002", "implementation-output.txt")
+	assert.NoFileExists(t, first)
+	firstInfo, err := os.Stat(first + ".gz")
+	require.NoError(t, err)
+	secondInfo, err := os.Stat(second + ".gz")
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(firstInfo, secondInfo), "the identical output is stored once")
+
+	data, err := iterlog.ReadFile(second)
+	require.NoError(t, err)
+	assert.Equal(t, output, string(data))
+	assert.FileExists(t, filepath.Join(tmpDir, "iteration-003", "implementation-output.txt"), "the last iteration can still be resumed")
+}
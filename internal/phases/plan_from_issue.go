@@ -0,0 +1,66 @@
+package phases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/CodexForgeBR/cli-tools/internal/ai"
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/prompt"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// PlanFromIssueConfig configures the planning run of --plan-from-issue.
+type PlanFromIssueConfig struct {
+	Runner     ai.AIRunner
+	IssueFile  string // cached issue used as the spec
+	OutputPath string // where the AI output is written
+	PlanFile   string // plan.md to create
+	TasksFile  string // tasks.md to create
+}
+
+// RunPlanFromIssue asks the runner to plan the issue in cfg.IssueFile and
+// writes the resulting plan and tasks files. It fails when the answer lacks
+// either document or the tasks document has no unchecked task.
+func RunPlanFromIssue(ctx context.Context, cfg PlanFromIssueConfig) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if err := cfg.Runner.Run(ctx, prompt.BuildPlanFromIssuePrompt(cfg.IssueFile), cfg.OutputPath); err != nil {
+		return fmt.Errorf("planning AI error: %w", err)
+	}
+	output, err := os.ReadFile(cfg.OutputPath)
+	if err != nil {
+		return fmt.Errorf("failed to read planning output: %w", err)
+	}
+
+	plan, tasksDoc := parser.ExtractPlan(string(output))
+	if plan == "" {
+		return fmt.Errorf("no RALPH_PLAN block in planning output %s", cfg.OutputPath)
+	}
+	if tasksDoc == "" {
+		return fmt.Errorf("no RALPH_TASKS block in planning output %s", cfg.OutputPath)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cfg.TasksFile), 0755); err != nil {
+		return fmt.Errorf("failed to create tasks directory: %w", err)
+	}
+	if err := os.WriteFile(cfg.PlanFile, []byte(plan), 0644); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	if err := os.WriteFile(cfg.TasksFile, []byte(tasksDoc), 0644); err != nil {
+		return fmt.Errorf("failed to write tasks: %w", err)
+	}
+
+	unchecked, err := tasks.CountUnchecked(cfg.TasksFile)
+	if err != nil {
+		return fmt.Errorf("failed to count tasks: %w", err)
+	}
+	if unchecked == 0 {
+		return fmt.Errorf("generated tasks file %s has no unchecked tasks", cfg.TasksFile)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package phases
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func planFromIssueConfig(dir string, runner *mockTasksValidationRunner) PlanFromIssueConfig {
+	return PlanFromIssueConfig{
+		Runner:     runner,
+		IssueFile:  filepath.Join(dir, "github-issue.md"),
+		OutputPath: filepath.Join(dir, "plan-from-issue-output.txt"),
+		PlanFile:   filepath.Join(dir, "specs", "issue-7", "plan.md"),
+		TasksFile:  filepath.Join(dir, "specs", "issue-7", "tasks.md"),
+	}
+}
+
+func TestRunPlanFromIssue_WritesPlanAndTasks(t *testing.T) {
+	dir := t.TempDir()
+	cfg := planFromIssueConfig(dir, &mockTasksValidationRunner{
+		output: "RALPH_PLAN\n````markdown\n# Plan\n````\n\nRALPH_TASKS\n````markdown\n- [ ] T001 Do it\n````\n",
+	})
+
+	require.NoError(t, RunPlanFromIssue(context.Background(), cfg))
+
+	plan, err := os.ReadFile(cfg.PlanFile)
+	require.NoError(t, err)
+	assert.Equal(t, "# Plan\n", string(plan))
+	tasksDoc, err := os.ReadFile(cfg.TasksFile)
+	require.NoError(t, err)
+	assert.Equal(t, "- [ ] T001 Do it\n", string(tasksDoc))
+}
+
+func TestRunPlanFromIssue_MissingBlocks(t *testing.T) {
+	dir := t.TempDir()
+	cfg := planFromIssueConfig(dir, &mockTasksValidationRunner{output: "RALPH_PLAN\n```\n# Plan\n```\n"})
+
+	err := RunPlanFromIssue(context.Background(), cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no RALPH_TASKS block")
+	assert.NoFileExists(t, cfg.TasksFile)
+}
+
+func TestRunPlanFromIssue_NoUncheckedTasks(t *testing.T) {
+	dir := t.TempDir()
+	cfg := planFromIssueConfig(dir, &mockTasksValidationRunner{
+		output: "RALPH_PLAN\n```\n# Plan\n```\nRALPH_TASKS\n```\n- [x] T001 Done\n```\n",
+	})
+
+	err := RunPlanFromIssue(context.Background(), cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no unchecked tasks")
+}
+
+func TestRunPlanFromIssue_RunnerError(t *testing.T) {
+	dir := t.TempDir()
+	cfg := planFromIssueConfig(dir, &mockTasksValidationRunner{err: errors.New("boom")})
+
+	err := RunPlanFromIssue(context.Background(), cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "planning AI error: boom")
+}
@@ -0,0 +1,100 @@
+package phases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/policy"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// checkedPolicies reports whether any policy has a pattern to check.
+func checkedPolicies(policies []policy.Policy) bool {
+	for _, p := range policies {
+		if p.Checked() {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPolicies matches the policy patterns against the lines the
+// iteration added. It finds nothing when no diff base was recorded.
+func (o *Orchestrator) checkPolicies() []policy.Violation {
+	if o.diffBase == "" || !checkedPolicies(o.policies) {
+		return nil
+	}
+	patch, _, err := o.iterationPatch()
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Failed to diff the iteration for the policy check: %v", err))
+		return nil
+	}
+	violations := policy.Check(o.policies, patch)
+	for _, v := range violations {
+		logging.Warn(fmt.Sprintf("Policy violation (%s): %s", v.Action, v))
+	}
+	return violations
+}
+
+// violationLines renders violations for the validation prompt.
+func violationLines(violations []policy.Violation) []string {
+	lines := make([]string, len(violations))
+	for i, v := range violations {
+		lines[i] = v.String()
+	}
+	return lines
+}
+
+// enforcePolicies counts the policies violated in this iteration, found by
+// checkPolicies or reported by the validator, and applies the actions of
+// the ones found by pattern: the verdict becomes ESCALATE or INADMISSIBLE
+// whatever the validator decided, and warnings are added to the feedback.
+func (o *Orchestrator) enforcePolicies(result ValidationPhaseResult, violations []policy.Violation) ValidationPhaseResult {
+	var names []string
+	for _, v := range violations {
+		names = append(names, v.Policy)
+	}
+	for _, practice := range result.InadmissiblePractices {
+		names = append(names, policy.Match(o.policies, practice))
+	}
+	state.RecordPolicyViolations(o.session, names)
+	if len(violations) == 0 {
+		return result
+	}
+
+	verdict := ""
+	var enforced, warned []string
+	for _, v := range violations {
+		switch v.Action {
+		case policy.ActionEscalate:
+			verdict = "ESCALATE"
+			enforced = append(enforced, v.String())
+		case policy.ActionInadmissible:
+			if verdict == "" {
+				verdict = "INADMISSIBLE"
+			}
+			enforced = append(enforced, v.String())
+		default:
+			warned = append(warned, v.String())
+		}
+	}
+
+	var notes []string
+	if len(enforced) > 0 {
+		notes = append(notes, "Policy violations found by ralph-loop:\n- "+strings.Join(enforced, "\n- "))
+	}
+	if len(warned) > 0 {
+		notes = append(notes, "Policy warnings:\n- "+strings.Join(warned, "\n- "))
+	}
+	if result.Feedback != "" {
+		notes = append(notes, result.Feedback)
+	}
+	result.Feedback = strings.Join(notes, "\n\n")
+
+	if verdict != "" && result.Verdict != verdict && result.Verdict != "ESCALATE" {
+		logging.Warn(fmt.Sprintf("Verdict %s overridden by policy violations: %s", result.Verdict, verdict))
+		result.Verdict = verdict
+	}
+	return result
+}
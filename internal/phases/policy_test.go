@@ -0,0 +1,106 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/policy"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOrchestrator_PolicyPatternOverridesVerdict verifies that a trivial
+// test added by the implementer is caught by the built-in pattern even
+// when the validator accepts it.
+func TestOrchestrator_PolicyPatternOverridesVerdict(t *testing.T) {
+	workDir := diffTestRepo(t)
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	valRunner := completeValidator(cfg)
+	impl := &MockOrchestratorAIRunner{RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+		_ = os.WriteFile(filepath.Join(workDir, "sum.test.js"), []byte("test('sum', () => {\n  expect(true).toBe(true)\n})\n"), 0644)
+		return os.WriteFile(outputPath, []byte("Done."), 0644)
+	}}
+	o := newTestOrchestrator(t, cfg, filepath.Join(workDir, ".ralph-loop"), impl, valRunner)
+	o.WorkDir = workDir
+
+	code := o.Run(context.Background())
+	assert.Equal(t, exitcode.MaxIterations, code, "the COMPLETE verdict does not stand")
+	require.NotEmpty(t, valRunner.PromptLog)
+	assert.Contains(t, valRunner.PromptLog[0], "POLICY VIOLATIONS FOUND BY RALPH-LOOP")
+	assert.Contains(t, valRunner.PromptLog[0], "- trivial-tests: sum.test.js:2: expect(true).toBe(true)")
+	assert.Contains(t, valRunner.PromptLog[0], "TRIVIAL/EMPTY TESTS (high severity)")
+
+	s, err := state.LoadState(filepath.Join(workDir, ".ralph-loop"))
+	require.NoError(t, err)
+	assert.Equal(t, "INADMISSIBLE", s.Verdict)
+	assert.Equal(t, 1, s.InadmissibleCount)
+	assert.Equal(t, map[string]int{"trivial-tests": 1}, s.PolicyViolations)
+}
+
+func TestOrchestrator_InvalidPolicyFile(t *testing.T) {
+	workDir := diffTestRepo(t)
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	o := newTestOrchestrator(t, cfg, filepath.Join(workDir, ".ralph-loop"), nil, nil)
+	o.WorkDir = workDir
+	o.Config.PolicyFile = filepath.Join(workDir, "policies.yaml")
+	require.NoError(t, os.WriteFile(o.Config.PolicyFile, []byte("policies:\n  - name: x\n    action: explode\n    rules: x\n"), 0644))
+
+	assert.Equal(t, exitcode.Error, o.Run(context.Background()))
+}
+
+func TestEnforcePolicies(t *testing.T) {
+	policies, err := policy.Load("")
+	require.NoError(t, err)
+	violation := func(name, action string) policy.Violation {
+		return policy.Violation{Policy: name, Action: action, File: "a_test.go", Line: 3, Text: "assert.True(t, true)"}
+	}
+
+	tests := []struct {
+		name       string
+		verdict    string
+		violations []policy.Violation
+		want       string
+	}{
+		{"none", "COMPLETE", nil, "COMPLETE"},
+		{"inadmissible", "COMPLETE", []policy.Violation{violation("trivial-tests", policy.ActionInadmissible)}, "INADMISSIBLE"},
+		{"escalate wins", "NEEDS_MORE_WORK", []policy.Violation{
+			violation("trivial-tests", policy.ActionInadmissible),
+			violation("no-todo", policy.ActionEscalate),
+		}, "ESCALATE"},
+		{"warn only", "COMPLETE", []policy.Violation{violation("no-todo", policy.ActionWarn)}, "COMPLETE"},
+		{"validator escalation stands", "ESCALATE", []policy.Violation{violation("trivial-tests", policy.ActionInadmissible)}, "ESCALATE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Orchestrator{session: &state.SessionState{}, policies: policies}
+			got := o.enforcePolicies(ValidationPhaseResult{Verdict: tt.verdict, Feedback: "looks fine"}, tt.violations)
+			assert.Equal(t, tt.want, got.Verdict)
+			assert.Contains(t, got.Feedback, "looks fine")
+			for _, v := range tt.violations {
+				assert.Contains(t, got.Feedback, v.String())
+			}
+		})
+	}
+}
+
+func TestEnforcePolicies_CountsValidatorReports(t *testing.T) {
+	o := &Orchestrator{session: &state.SessionState{}, policies: policy.Default()}
+	result := ValidationPhaseResult{
+		Verdict: "INADMISSIBLE",
+		InadmissiblePractices: []string{
+			"mock-subject: user_test.go mocks UserService",
+			"Mock-Subject: again",
+			"something unnamed",
+		},
+	}
+	got := o.enforcePolicies(result, []policy.Violation{{Policy: "trivial-tests", Action: policy.ActionInadmissible}})
+	assert.Equal(t, "INADMISSIBLE", got.Verdict)
+	assert.Equal(t, map[string]int{"mock-subject": 1, "trivial-tests": 1}, o.session.PolicyViolations)
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/CodexForgeBR/cli-tools/internal/logging"
 	"github.com/CodexForgeBR/cli-tools/internal/parser"
 	"github.com/CodexForgeBR/cli-tools/internal/prompt"
+	"github.com/CodexForgeBR/cli-tools/internal/schema"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
 )
 
 // PostValidationConfig configures the post-validation chain.
@@ -29,6 +31,10 @@ type PostValidationConfig struct {
 	CrossModel     string
 	FinalPlanAI    string
 	FinalPlanModel string
+	// LogDir receives cross.log and final-plan.log with the runners' raw
+	// output, rotated at LogMaxBytes. Empty keeps no logs.
+	LogDir      string
+	LogMaxBytes int64
 }
 
 // PostValidationResult contains the outcome of the post-validation chain.
@@ -36,6 +42,8 @@ type PostValidationResult struct {
 	Action   string // "success", "continue", "exit"
 	ExitCode int
 	Feedback string
+	Gate     string // phase that produced the result, empty when nothing ran
+	Verdict  string // verdict reported by that phase, if any
 }
 
 // RunPostValidationChain orchestrates cross-val → final-plan → success/reject flow.
@@ -50,12 +58,15 @@ func RunPostValidationChain(ctx context.Context, cfg PostValidationConfig) PostV
 
 	// If only final-plan is enabled, run it directly
 	if !cfg.CrossValEnabled && cfg.FinalPlanEnabled {
-		return runFinalPlanValidation(ctx, cfg)
+		result := runFinalPlanValidation(ctx, cfg)
+		result.Gate = state.PhaseFinalPlanValidation
+		return result
 	}
 
 	// Run cross-validation if enabled
 	if cfg.CrossValEnabled {
 		crossResult := runCrossValidation(ctx, cfg)
+		crossResult.Gate = state.PhaseCrossValidation
 		if crossResult.Action != "success" {
 			return crossResult
 		}
@@ -63,7 +74,9 @@ func RunPostValidationChain(ctx context.Context, cfg PostValidationConfig) PostV
 
 	// Cross-val passed or skipped - run final plan if enabled
 	if cfg.FinalPlanEnabled {
-		return runFinalPlanValidation(ctx, cfg)
+		result := runFinalPlanValidation(ctx, cfg)
+		result.Gate = state.PhaseFinalPlanValidation
+		return result
 	}
 
 	// Everything passed
@@ -90,6 +103,9 @@ func runCrossValidation(ctx context.Context, cfg PostValidationConfig) PostValid
 		}
 	}
 
+	ctx, closeLog := withPhaseLog(ctx, cfg.LogDir, logCross, cfg.LogMaxBytes)
+	defer closeLog()
+
 	// Build the cross-validation prompt using proper prompt builder
 	crossValPrompt := prompt.BuildCrossValidationPrompt(cfg.TasksFile, cfg.ValOutputFile, cfg.ImplOutputFile)
 
@@ -104,6 +120,7 @@ func runCrossValidation(ctx context.Context, cfg PostValidationConfig) PostValid
 	outputPath := tmpFile.Name()
 	tmpFile.Close()
 	defer os.Remove(outputPath)
+	defer os.Remove(formatFixPath(outputPath, 1))
 
 	// Run cross-validation
 	err = cfg.CrossValRunner.Run(ctx, crossValPrompt, outputPath)
@@ -114,7 +131,16 @@ func runCrossValidation(ctx context.Context, cfg PostValidationConfig) PostValid
 		}
 	}
 
-	// Read output once for both stderr dump and parsing
+	// Read output once for both stderr dump and parsing, once more
+	// formatted if need be
+	outputPath, schemaErrs := conformOutput(ctx, cfg.CrossValRunner, "RALPH_CROSS_VALIDATION", outputPath, schema.MustLookup("RALPH_CROSS_VALIDATION"))
+	if len(schemaErrs) > 0 {
+		logging.Error(formatFeedback("RALPH_CROSS_VALIDATION", schemaErrs))
+		return PostValidationResult{
+			Action:   "exit",
+			ExitCode: exitcode.Error,
+		}
+	}
 	output, err := os.ReadFile(outputPath)
 	if err != nil {
 		return PostValidationResult{
@@ -150,12 +176,14 @@ func runCrossValidation(ctx context.Context, cfg PostValidationConfig) PostValid
 		return PostValidationResult{
 			Action:   "success",
 			ExitCode: exitcode.Success,
+			Verdict:  parsed.Verdict,
 		}
 	case "REJECTED":
 		return PostValidationResult{
 			Action:   "continue",
 			ExitCode: exitcode.Success,
 			Feedback: parsed.Feedback,
+			Verdict:  parsed.Verdict,
 		}
 	default:
 		// Unknown verdict
@@ -183,6 +211,9 @@ func runFinalPlanValidation(ctx context.Context, cfg PostValidationConfig) PostV
 		}
 	}
 
+	ctx, closeLog := withPhaseLog(ctx, cfg.LogDir, logFinalPlan, cfg.LogMaxBytes)
+	defer closeLog()
+
 	// Build the final-plan prompt using proper prompt builder
 	finalPlanPrompt := prompt.BuildFinalPlanPrompt(cfg.SpecFile, cfg.TasksFile, cfg.PlanFile)
 
@@ -243,12 +274,14 @@ func runFinalPlanValidation(ctx context.Context, cfg PostValidationConfig) PostV
 		return PostValidationResult{
 			Action:   "success",
 			ExitCode: exitcode.Success,
+			Verdict:  parsed.Verdict,
 		}
 	case "NOT_IMPLEMENTED":
 		return PostValidationResult{
 			Action:   "continue",
 			ExitCode: exitcode.Success,
 			Feedback: parsed.Feedback,
+			Verdict:  parsed.Verdict,
 		}
 	default:
 		// Unknown verdict
@@ -0,0 +1,187 @@
+package phases
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/coverage"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	ghissue "github.com/CodexForgeBR/cli-tools/internal/github"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// gateTasksValidation names the tasks-validation gate in the PR summary.
+const gateTasksValidation = "tasks_validation"
+
+// gateResult is the latest outcome of a validation gate in this session.
+type gateResult struct {
+	Name   string
+	Result string
+}
+
+// recordGate stores the latest result for the named gate, replacing any
+// earlier result for the same gate.
+func (o *Orchestrator) recordGate(name, result string) {
+	o.Metrics.ObserveVerdict(name, result)
+	for i := range o.gates {
+		if o.gates[i].Name == name {
+			o.gates[i].Result = result
+			return
+		}
+	}
+	o.gates = append(o.gates, gateResult{Name: name, Result: result})
+}
+
+// postPRSummary creates or updates the summary comment on the branch's open
+// pull request. It does nothing when no pull request was detected. Failures
+// are logged and never affect the exit code.
+func (o *Orchestrator) postPRSummary(event string, res exitcode.Result) {
+	if o.PRNumber <= 0 {
+		return
+	}
+	body := buildPRSummary(o.summaryInput(event, res))
+	upsert := ghissue.UpsertPRComment
+	if o.PRClient != nil {
+		upsert = o.PRClient.UpsertPRComment
+	}
+	if err := upsert(o.PRNumber, body); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to update PR #%d summary: %v", o.PRNumber, err))
+		return
+	}
+	logging.Info(fmt.Sprintf("Updated summary comment on PR #%d", o.PRNumber))
+}
+
+// summaryInput gathers the run summary of a run that ended with res on
+// event ("" when unknown).
+func (o *Orchestrator) summaryInput(event string, res exitcode.Result) prSummaryInput {
+	cost, hasCost := state.SessionCost(o.StateDir)
+	return prSummaryInput{
+		Event:    event,
+		ExitCode: res.Code,
+		Reason:   res.Reason,
+		Session:  o.session,
+		Duration: time.Since(o.startTime),
+		Gates:    o.gates,
+		Cost:     cost,
+		HasCost:  hasCost,
+		Coverage: o.readCoverage(),
+	}
+}
+
+// readCoverage returns the percentage COVERAGE_FILE reports, or nil when it
+// is not set or cannot be read.
+func (o *Orchestrator) readCoverage() *float64 {
+	if o.Config.CoverageFile == "" {
+		return nil
+	}
+	pct, err := coverage.Read(o.Config.CoverageFile)
+	if err != nil {
+		logging.Debug(fmt.Sprintf("Coverage not read: %v", err))
+		return nil
+	}
+	return &pct
+}
+
+// prSummaryInput holds everything rendered into the PR summary comment.
+type prSummaryInput struct {
+	Event    string
+	ExitCode int
+	Reason   string
+	Session  *state.SessionState
+	Duration time.Duration
+	Gates    []gateResult
+	Cost     float64
+	HasCost  bool
+	// Coverage is the coverage when the run ended, shown against the
+	// session's CoverageBaseline; nil when unknown.
+	Coverage *float64
+}
+
+// buildPRSummary renders the Markdown body of the PR summary comment.
+func buildPRSummary(in prSummaryInput) string {
+	var b strings.Builder
+	s := in.Session
+
+	b.WriteString(ghissue.SummaryMarker + "\n")
+	b.WriteString("## Ralph Loop summary\n\n")
+
+	icon := "❌"
+	if in.ExitCode == exitcode.Success {
+		icon = "✅"
+	}
+	if in.Event != "" {
+		fmt.Fprintf(&b, "**Result:** %s %s (exit %d, `%s`)\n\n", icon, exitcode.Name(in.ExitCode), in.ExitCode, in.Event)
+	} else {
+		fmt.Fprintf(&b, "**Result:** %s %s (exit %d)\n\n", icon, exitcode.Name(in.ExitCode), in.ExitCode)
+	}
+	if in.Reason != "" {
+		fmt.Fprintf(&b, "**Reason:** %s\n\n", in.Reason)
+	}
+
+	b.WriteString("| | |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Session | `%s` |\n", s.SessionID)
+	fmt.Fprintf(&b, "| Iterations | %d / %d |\n", s.Iteration, s.MaxIterations)
+	fmt.Fprintf(&b, "| Duration | %s |\n", logging.FormatDuration(int(in.Duration.Seconds())))
+	if in.HasCost {
+		fmt.Fprintf(&b, "| Cost | $%.2f |\n", in.Cost)
+	} else {
+		b.WriteString("| Cost | n/a |\n")
+	}
+	if in.Coverage != nil {
+		line := fmt.Sprintf("| Coverage | %.1f%%", *in.Coverage)
+		if s.CoverageBaseline != nil {
+			line += fmt.Sprintf(" (%+.1f)", *in.Coverage-*s.CoverageBaseline)
+		}
+		b.WriteString(line + " |\n")
+	}
+
+	if len(in.Gates) > 0 {
+		b.WriteString("\n### Gates\n\n| Gate | Result |\n|---|---|\n")
+		for _, g := range in.Gates {
+			fmt.Fprintf(&b, "| %s | %s |\n", g.Name, g.Result)
+		}
+	}
+
+	if len(s.History) > 0 {
+		b.WriteString("\n### Verdicts\n\n| Iteration | Verdict | Tasks done | Duration |\n|---|---|---|---|\n")
+		for _, h := range s.History {
+			verdict := h.Verdict
+			if verdict == "" {
+				verdict = "-"
+			}
+			fmt.Fprintf(&b, "| %d | %s | %d/%d | %s |\n", h.Iteration, verdict, h.Done, h.Total, logging.FormatDuration(h.Seconds))
+		}
+	}
+
+	var flaky []string
+	for _, t := range s.TestTracks {
+		if t.FlakySince > 0 {
+			flaky = append(flaky, fmt.Sprintf("- `%s` (since iteration %d, %d flips)", t.Name, t.FlakySince, t.Flips))
+		}
+	}
+	if len(flaky) > 0 {
+		b.WriteString("\n### Suspected flaky tests\n\nTheir failures did not count against the iterations.\n\n")
+		b.WriteString(strings.Join(flaky, "\n") + "\n")
+	}
+
+	if len(s.Tasks) > 0 {
+		counts := state.CountTasksByStatus(s)
+		fmt.Fprintf(&b, "\n### Tasks\n\n%d done, %d blocked, %d pending\n\n",
+			counts[state.TaskDone], counts[state.TaskBlocked], counts[state.TaskPending])
+		for _, t := range s.Tasks {
+			mark := " "
+			if t.Status == state.TaskDone {
+				mark = "x"
+			}
+			line := fmt.Sprintf("- [%s] %s", mark, t.Text)
+			if t.Status == state.TaskBlocked {
+				line += " _(blocked)_"
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,153 @@
+package phases
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	ghissue "github.com/CodexForgeBR/cli-tools/internal/github"
+	"github.com/CodexForgeBR/cli-tools/internal/notification"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/testresult"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordGate_ReplacesExisting(t *testing.T) {
+	o := NewOrchestrator(config.NewDefaultConfig())
+
+	o.recordGate(state.PhaseValidation, "NEEDS_MORE_WORK")
+	o.recordGate(gateTasksValidation, "VALID")
+	o.recordGate(state.PhaseValidation, "COMPLETE")
+
+	assert.Equal(t, []gateResult{
+		{Name: state.PhaseValidation, Result: "COMPLETE"},
+		{Name: gateTasksValidation, Result: "VALID"},
+	}, o.gates)
+}
+
+func TestBuildPRSummary_Success(t *testing.T) {
+	body := buildPRSummary(prSummaryInput{
+		Event:    notification.EventCompleted,
+		ExitCode: exitcode.Success,
+		Session: &state.SessionState{
+			SessionID:     "sess-1",
+			Iteration:     3,
+			MaxIterations: 20,
+			Tasks: []state.TaskState{
+				{ID: "T001", Text: "T001 First", Status: state.TaskDone},
+				{ID: "T002", Text: "T002 Second", Status: state.TaskBlocked},
+			},
+		},
+		Duration: 90 * time.Second,
+		Gates: []gateResult{
+			{Name: state.PhaseValidation, Result: "COMPLETE"},
+			{Name: state.PhaseCrossValidation, Result: "CONFIRMED"},
+		},
+		Cost:    1.234,
+		HasCost: true,
+	})
+
+	assert.Contains(t, body, ghissue.SummaryMarker)
+	assert.Contains(t, body, "✅ Success (exit 0, `completed`)")
+	assert.Contains(t, body, "| Session | `sess-1` |")
+	assert.Contains(t, body, "| Iterations | 3 / 20 |")
+	assert.Contains(t, body, "| Duration | 1m 30s |")
+	assert.Contains(t, body, "| Cost | $1.23 |")
+	assert.Contains(t, body, "| validation | COMPLETE |")
+	assert.Contains(t, body, "| cross_validation | CONFIRMED |")
+	assert.Contains(t, body, "1 done, 1 blocked, 0 pending")
+	assert.Contains(t, body, "- [x] T001 First")
+	assert.Contains(t, body, "- [ ] T002 Second _(blocked)_")
+}
+
+func TestBuildPRSummary_FailureWithoutCostOrTasks(t *testing.T) {
+	body := buildPRSummary(prSummaryInput{
+		Event:    notification.EventMaxIterations,
+		ExitCode: exitcode.MaxIterations,
+		Reason:   "reached the limit of 20 iterations with 2 tasks unchecked",
+		Session:  &state.SessionState{SessionID: "sess-2", Iteration: 20, MaxIterations: 20},
+	})
+
+	assert.Contains(t, body, "❌ MaxIterations (exit 2, `max_iterations`)")
+	assert.Contains(t, body, "**Reason:** reached the limit of 20 iterations with 2 tasks unchecked\n")
+	assert.Contains(t, body, "| Cost | n/a |")
+	assert.NotContains(t, body, "### Gates")
+	assert.NotContains(t, body, "### Tasks")
+}
+
+func TestPostPRSummary_NoPRIsNoop(t *testing.T) {
+	o := NewOrchestrator(config.NewDefaultConfig())
+	o.session = &state.SessionState{}
+
+	// Must not invoke gh when no pull request was detected.
+	t.Setenv("PATH", t.TempDir())
+	o.postPRSummary(notification.EventCompleted, exitcode.Result{Code: exitcode.Success})
+}
+
+func TestBuildPRSummary_VerdictsAndCoverage(t *testing.T) {
+	baseline, current := 71.25, 74.5
+	body := buildPRSummary(prSummaryInput{
+		Event:    notification.EventCompleted,
+		ExitCode: exitcode.Success,
+		Session: &state.SessionState{
+			SessionID: "sess-3",
+			History: []state.IterationRecord{
+				{Iteration: 1, Verdict: "NEEDS_MORE_WORK", Seconds: 600, Done: 1, Total: 3},
+				{Iteration: 2, Verdict: "COMPLETE", Seconds: 300, Done: 3, Total: 3},
+			},
+			CoverageBaseline: &baseline,
+		},
+		Coverage: &current,
+	})
+
+	assert.Contains(t, body, "| Coverage | 74.5% (+3.2) |")
+	assert.Contains(t, body, "### Verdicts")
+	assert.Contains(t, body, "| 1 | NEEDS_MORE_WORK | 1/3 | 10m 0s |")
+	assert.Contains(t, body, "| 2 | COMPLETE | 3/3 | 5m 0s |")
+
+	body = buildPRSummary(prSummaryInput{Session: &state.SessionState{}, Coverage: &current})
+	assert.Contains(t, body, "| Coverage | 74.5% |", "no delta without a baseline")
+	assert.NotContains(t, body, "### Verdicts")
+}
+
+func TestBuildPRSummary_SuspectedFlakyTests(t *testing.T) {
+	body := buildPRSummary(prSummaryInput{Session: &state.SessionState{
+		TestTracks: []testresult.Track{
+			{Name: "TestRetry (example.com/app/net)", Passed: true, Iteration: 4, Flips: 3, FlakySince: 3},
+			{Name: "TestAdd (example.com/app)", Passed: true, Iteration: 2},
+		},
+	}})
+	assert.Contains(t, body, "### Suspected flaky tests")
+	assert.Contains(t, body, "- `TestRetry (example.com/app/net)` (since iteration 3, 3 flips)\n")
+	assert.NotContains(t, body, "TestAdd")
+
+	assert.NotContains(t, buildPRSummary(prSummaryInput{Session: &state.SessionState{}}), "flaky")
+}
+
+func TestPostPRSummary_UsesAPIClient(t *testing.T) {
+	var posted string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, "[]")
+			return
+		}
+		posted = r.Method + " " + r.URL.Path
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, "{}")
+	}))
+	defer srv.Close()
+
+	o := NewOrchestrator(config.NewDefaultConfig())
+	o.StateDir = t.TempDir()
+	o.session = &state.SessionState{SessionID: "sess-4"}
+	o.PRNumber = 9
+	o.PRClient = &ghissue.Client{BaseURL: srv.URL, Token: "tok", Owner: "acme", Repo: "app"}
+	t.Setenv("PATH", t.TempDir())
+	o.postPRSummary(notification.EventCompleted, exitcode.Result{Code: exitcode.Success})
+
+	assert.Equal(t, "POST /repos/acme/app/issues/9/comments", posted)
+}
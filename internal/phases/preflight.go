@@ -0,0 +1,77 @@
+package phases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/gitdiff"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+)
+
+// maxListedChanges caps the uncommitted changes listed when the preflight
+// refuses to start.
+const maxListedChanges = 10
+
+// phasePreflight refuses to start a new session on a working tree where
+// the agent's edits could destroy the user's work: uncommitted changes, a
+// protected branch or an unfinished rebase or merge. Resumed sessions are
+// not checked, as the tree then holds the loop's own changes; neither are
+// --status, --cancel nor directories outside a git repository.
+func (o *Orchestrator) phasePreflight() int {
+	if !o.CheckWorkspace || o.Config.Status || o.Config.Cancel || o.Config.Resume || o.Config.ResumeForce {
+		return -1
+	}
+	if o.Config.AllowDirty {
+		logging.Debug("Workspace checks skipped (--allow-dirty)")
+		return -1
+	}
+
+	logging.Phase("Checking the workspace")
+	root := o.workDir()
+	var exclude []string
+	if rel, ok := insideDir(root, o.StateDir); ok {
+		exclude = append(exclude, rel)
+	}
+	ws, err := gitdiff.Inspect(root, exclude...)
+	if err != nil {
+		logging.Debug(fmt.Sprintf("Workspace checks skipped: %v", err))
+		return -1
+	}
+
+	problems := workspaceProblems(ws, o.Config.ProtectedBranchList())
+	if len(problems) == 0 {
+		return -1
+	}
+	for _, p := range problems {
+		logging.Error(fmt.Sprintf("Cannot start: %s", p))
+	}
+	logging.Info("Commit or stash your changes and work on a feature branch, or pass --allow-dirty to start anyway.")
+	return exitcode.Error
+}
+
+// workspaceProblems describes what makes ws unsafe to start on.
+func workspaceProblems(ws gitdiff.Workspace, protectedBranches []string) []string {
+	var problems []string
+	if ws.Operation != "" {
+		problems = append(problems, fmt.Sprintf("a %s is in progress; finish or abort it first", ws.Operation))
+	}
+	for _, b := range protectedBranches {
+		if ws.Branch == b {
+			problems = append(problems, fmt.Sprintf("on protected branch %s", b))
+			break
+		}
+	}
+	if n := len(ws.Changes); n > 0 {
+		listed := ws.Changes
+		if n > maxListedChanges {
+			listed = listed[:maxListedChanges]
+		}
+		msg := fmt.Sprintf("the working tree has %d uncommitted change(s):\n  %s", n, strings.Join(listed, "\n  "))
+		if n > maxListedChanges {
+			msg += fmt.Sprintf("\n  ... and %d more", n-maxListedChanges)
+		}
+		problems = append(problems, msg)
+	}
+	return problems
+}
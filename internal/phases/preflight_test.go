@@ -0,0 +1,109 @@
+package phases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/gitdiff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaceProblems(t *testing.T) {
+	protected := []string{"main", "master"}
+
+	assert.Empty(t, workspaceProblems(gitdiff.Workspace{Branch: "feature"}, protected))
+	assert.Empty(t, workspaceProblems(gitdiff.Workspace{Branch: "main"}, nil))
+
+	problems := workspaceProblems(gitdiff.Workspace{Branch: "main", Operation: "rebase", Changes: []string{" M main.go"}}, protected)
+	require.Len(t, problems, 3)
+	assert.Equal(t, "a rebase is in progress; finish or abort it first", problems[0])
+	assert.Equal(t, "on protected branch main", problems[1])
+	assert.Equal(t, "the working tree has 1 uncommitted change(s):\n   M main.go", problems[2])
+}
+
+func TestWorkspaceProblems_ListsAtMostTenChanges(t *testing.T) {
+	var changes []string
+	for i := 0; i < 12; i++ {
+		changes = append(changes, fmt.Sprintf("?? file%02d.go", i))
+	}
+	problems := workspaceProblems(gitdiff.Workspace{Branch: "feature", Changes: changes}, nil)
+	require.Len(t, problems, 1)
+	assert.Contains(t, problems[0], "has 12 uncommitted change(s)")
+	assert.Contains(t, problems[0], "file09.go")
+	assert.NotContains(t, problems[0], "file10.go")
+	assert.Contains(t, problems[0], "... and 2 more")
+}
+
+// featureBranchRepo returns a clean git repository on branch "feature".
+func featureBranchRepo(t *testing.T) string {
+	t.Helper()
+	workDir := diffTestRepo(t)
+	cmd := exec.Command("git", "checkout", "-q", "-b", "feature")
+	cmd.Dir = workDir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return workDir
+}
+
+func TestOrchestrator_PreflightRefusesDirtyTree(t *testing.T) {
+	workDir := featureBranchRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "main.go"), []byte("package main\n\n// edited\n"), 0644))
+	cfg := config.NewDefaultConfig()
+	impl := doneImplementer()
+	o := newTestOrchestrator(t, cfg, filepath.Join(workDir, ".ralph-loop"), impl, nil)
+	o.WorkDir = workDir
+	o.CheckWorkspace = true
+
+	code := o.Run(context.Background())
+
+	assert.Equal(t, exitcode.Error, code)
+	assert.Zero(t, impl.CallCount, "the implementer must not run")
+}
+
+func TestOrchestrator_PreflightAllowDirty(t *testing.T) {
+	workDir := featureBranchRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "main.go"), []byte("package main\n\n// edited\n"), 0644))
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	cfg.AllowDirty = true
+	impl := doneImplementer()
+	o := newTestOrchestrator(t, cfg, filepath.Join(workDir, ".ralph-loop"), impl, nil)
+	o.WorkDir = workDir
+	o.CheckWorkspace = true
+
+	o.Run(context.Background())
+
+	assert.NotZero(t, impl.CallCount)
+}
+
+func TestOrchestrator_PreflightRefusesProtectedBranch(t *testing.T) {
+	workDir := featureBranchRepo(t)
+	cfg := config.NewDefaultConfig()
+	cfg.ProtectedBranches = "feature"
+	o := newTestOrchestrator(t, cfg, filepath.Join(workDir, ".ralph-loop"), nil, nil)
+	o.WorkDir = workDir
+	o.CheckWorkspace = true
+
+	assert.Equal(t, exitcode.Error, o.Run(context.Background()))
+}
+
+func TestOrchestrator_PreflightPassesCleanTree(t *testing.T) {
+	workDir := featureBranchRepo(t)
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	impl := doneImplementer()
+	o := newTestOrchestrator(t, cfg, filepath.Join(workDir, ".ralph-loop"), impl, nil)
+	o.WorkDir = workDir
+	o.CheckWorkspace = true
+
+	o.Run(context.Background())
+
+	assert.NotZero(t, impl.CallCount, "the state directory does not make the tree dirty")
+}
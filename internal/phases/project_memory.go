@@ -0,0 +1,108 @@
+package phases
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/learnings"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/prompt"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// maxMemorySessions caps the earlier sessions, the latest ones, the
+// project memory covers.
+const maxMemorySessions = 5
+
+// maxMemoryLearnings caps the learnings of earlier sessions, the latest
+// ones, the project memory lists.
+const maxMemoryLearnings = 20
+
+// saveSessionOutcome leaves how the session ended in the state
+// directory's sessions/, for the project memory of later sessions.
+func (o *Orchestrator) saveSessionOutcome() {
+	if o.session == nil || o.session.SessionID == "" || o.session.Iteration == 0 {
+		return
+	}
+	var entries []string
+	if o.Config.EnableLearnings {
+		entries = learnings.Entries(learnings.ReadLearnings(o.Config.LearningsFile))
+	}
+	outcome := state.NewSessionOutcome(o.session, time.Now().Format(time.RFC3339), entries)
+	if err := state.SaveSessionOutcome(o.StateDir, outcome); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save the session outcome: %v", err))
+	}
+}
+
+// projectMemorySection returns the section of the first implementation
+// prompt with what the latest earlier sessions escalated on, blocked,
+// were told and learned, leaving out the learnings the project's
+// learnings file still holds. It is "" when PROJECT_MEMORY is off or
+// they left nothing of note.
+func (o *Orchestrator) projectMemorySection() string {
+	if !o.Config.ProjectMemory {
+		return ""
+	}
+	outcomes, err := state.LoadSessionOutcomes(o.StateDir)
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Failed to read earlier sessions: %v", err))
+		return ""
+	}
+	var earlier []state.SessionOutcome
+	for _, s := range outcomes {
+		if s.SessionID != o.session.SessionID {
+			earlier = append(earlier, s)
+		}
+	}
+	if len(earlier) > maxMemorySessions {
+		earlier = earlier[len(earlier)-maxMemorySessions:]
+	}
+
+	known := map[string]bool{}
+	if o.Config.EnableLearnings {
+		for _, e := range learnings.Entries(learnings.ReadLearnings(o.Config.LearningsFile)) {
+			known[strings.TrimSpace(e)] = true
+		}
+	}
+	var b strings.Builder
+	var learned []string
+	for _, s := range earlier {
+		escalation := s.Escalation()
+		if escalation != "" || len(s.Blocked) > 0 || len(s.Guidance) > 0 {
+			ended := "still running"
+			if s.Exit != nil {
+				ended = "ended " + s.Exit.Name
+			}
+			fmt.Fprintf(&b, "Session %s (%s, %d iterations, %s):\n", s.SessionID, filepath.Base(s.TasksFile), s.Iterations, ended)
+			if escalation != "" {
+				fmt.Fprintf(&b, "- Escalated: %s\n", escalation)
+			}
+			for _, t := range s.Blocked {
+				fmt.Fprintf(&b, "- Blocked: %s\n", t)
+			}
+			for _, g := range s.Guidance {
+				fmt.Fprintf(&b, "- A person decided: %s\n", g)
+			}
+			b.WriteString("\n")
+		}
+		for _, e := range s.Learnings {
+			if key := strings.TrimSpace(e); !known[key] {
+				known[key] = true
+				learned = append(learned, e)
+			}
+		}
+	}
+	if len(learned) > maxMemoryLearnings {
+		learned = learned[len(learned)-maxMemoryLearnings:]
+	}
+	if len(learned) > 0 {
+		b.WriteString("Learnings they recorded:\n" + strings.Join(learned, "\n") + "\n")
+	}
+	memory := strings.TrimRight(b.String(), "\n")
+	if memory != "" {
+		logging.Info(fmt.Sprintf("Including the project memory of %d earlier session(s)", len(earlier)))
+	}
+	return prompt.BuildProjectMemorySection(memory)
+}
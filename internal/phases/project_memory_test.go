@@ -0,0 +1,102 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedSessionOutcomes leaves the outcomes of two earlier sessions in the
+// state directory: one that escalated and blocked, one uneventful.
+func seedSessionOutcomes(t *testing.T, stateDir string) {
+	t.Helper()
+	require.NoError(t, state.SaveSessionOutcome(stateDir, state.SessionOutcome{
+		SessionID:  "ralph-old",
+		EndedAt:    "2026-10-01T10:00:00Z",
+		TasksFile:  "/work/specs/refunds/tasks.md",
+		Iterations: 6,
+		Exit:       &state.ExitState{Code: 3, Name: "Escalate", Reason: "Refund rules are undecided"},
+		Blocked:    []string{"T004 Call the payment API: no sandbox credentials"},
+		Guidance:   []string{"Refunds are allowed within 30 days"},
+		Learnings:  []string{"- Run make gen before building", "- Prefer table-driven tests"},
+	}))
+	require.NoError(t, state.SaveSessionOutcome(stateDir, state.SessionOutcome{
+		SessionID:  "ralph-quiet",
+		EndedAt:    "2026-10-02T10:00:00Z",
+		Iterations: 2,
+		Exit:       &state.ExitState{Code: 0, Name: "Success"},
+	}))
+}
+
+// TestOrchestrator_FirstPromptHasProjectMemory verifies that what earlier
+// sessions escalated on, blocked, were told and learned reaches the first
+// implementation prompt, less the learnings the project still holds, and
+// that the session leaves its own outcome behind.
+func TestOrchestrator_FirstPromptHasProjectMemory(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	implRunner := doneImplementer()
+	o := newTestOrchestrator(t, cfg, t.TempDir(), implRunner, nil)
+	seedSessionOutcomes(t, o.StateDir)
+	require.NoError(t, os.WriteFile(cfg.LearningsFile, []byte("# Ralph Loop Learnings\n- Prefer table-driven tests\n"), 0644))
+
+	o.Run(context.Background())
+	require.NotEmpty(t, implRunner.PromptLog)
+	first := implRunner.PromptLog[0]
+	assert.Contains(t, first, "PROJECT MEMORY")
+	assert.Contains(t, first, "Session ralph-old (tasks.md, 6 iterations, ended Escalate):")
+	assert.Contains(t, first, "- Escalated: Refund rules are undecided")
+	assert.Contains(t, first, "- Blocked: T004 Call the payment API: no sandbox credentials")
+	assert.Contains(t, first, "- A person decided: Refunds are allowed within 30 days")
+	assert.Contains(t, first, "Learnings they recorded:\n- Run make gen before building")
+	assert.NotContains(t, first, "ralph-quiet", "sessions that left nothing of note are not listed")
+	assert.Equal(t, 1, strings.Count(first, "- Prefer table-driven tests"), "learnings still in the learnings file are not repeated")
+
+	outcomes, err := state.LoadSessionOutcomes(o.StateDir)
+	require.NoError(t, err)
+	require.Len(t, outcomes, 3)
+	assert.Equal(t, o.session.SessionID, outcomes[2].SessionID)
+	require.NotNil(t, outcomes[2].Exit)
+	assert.Equal(t, "Success", outcomes[2].Exit.Name)
+	assert.Equal(t, []string{"- Prefer table-driven tests"}, outcomes[2].Learnings)
+}
+
+// TestOrchestrator_ProjectMemoryDisabled verifies --no-project-memory.
+func TestOrchestrator_ProjectMemoryDisabled(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	cfg.ProjectMemory = false
+	implRunner := doneImplementer()
+	o := newTestOrchestrator(t, cfg, t.TempDir(), implRunner, nil)
+	seedSessionOutcomes(t, o.StateDir)
+
+	o.Run(context.Background())
+	require.NotEmpty(t, implRunner.PromptLog)
+	assert.NotContains(t, implRunner.PromptLog[0], "PROJECT MEMORY")
+}
+
+// TestOrchestrator_CleanKeepsSessionOutcomes verifies that --clean wipes
+// the session but not what earlier sessions left.
+func TestOrchestrator_CleanKeepsSessionOutcomes(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	cfg.Clean = true
+	implRunner := doneImplementer()
+	o := newTestOrchestrator(t, cfg, t.TempDir(), implRunner, nil)
+	o.StateDir = filepath.Join(o.StateDir, ".ralph-loop")
+	seedSessionOutcomes(t, o.StateDir)
+	stale := filepath.Join(o.StateDir, "iteration-009")
+	require.NoError(t, os.MkdirAll(stale, 0755))
+
+	o.Run(context.Background())
+	assert.NoDirExists(t, stale)
+	require.NotEmpty(t, implRunner.PromptLog)
+	assert.Contains(t, implRunner.PromptLog[0], "Session ralph-old")
+}
@@ -0,0 +1,88 @@
+package phases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/learnings"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/model"
+)
+
+// promptWindowShare is the part of the model's context window a prompt may
+// take; the rest is left for the files the agent reads, its tool calls and
+// its answer.
+const promptWindowShare = 0.5
+
+// promptTrim is one way of shrinking a prompt, applied in order of
+// increasing importance of what it removes.
+type promptTrim struct {
+	what string // e.g. "the oldest learnings", for the warning
+	// trim cuts about excess tokens from a section the prompt is built
+	// from, reporting false when there is nothing left to cut.
+	trim func(excess int) bool
+}
+
+// trimOldest trims *text, a learnings section, to its most recent entries,
+// emptying it when they would all have to go.
+func trimOldest(what string, text *string) promptTrim {
+	return promptTrim{what: what, trim: func(excess int) bool {
+		if *text == "" {
+			return false
+		}
+		// Leave room for the note Truncate puts in place of the cut.
+		keep := learnings.EstimateTokens(*text) - excess - 16
+		if keep <= 0 {
+			*text = ""
+		} else {
+			*text = learnings.Truncate(*text, keep)
+		}
+		return true
+	}}
+}
+
+// dropSection empties *text.
+func dropSection(what string, text *string) promptTrim {
+	return promptTrim{what: what, trim: func(int) bool {
+		if *text == "" {
+			return false
+		}
+		*text = ""
+		return true
+	}}
+}
+
+// fitPrompt builds a phase's prompt and, while it is over promptWindowShare
+// of the model's context window, applies trims in order and rebuilds it,
+// warning about what was cut. The CLI would otherwise fail on an
+// oversized prompt with an error that does not say why. The estimated
+// size of the final prompt is recorded in the metrics.
+func (o *Orchestrator) fitPrompt(phase, ai, modelName string, build func() string, trims ...promptTrim) string {
+	window := model.ContextWindow(ai, modelName)
+	budget := int(float64(window) * promptWindowShare)
+	text := build()
+	tokens := learnings.EstimateTokens(text)
+	var trimmed []string
+	for _, t := range trims {
+		if tokens <= budget {
+			break
+		}
+		if t.trim(tokens - budget) {
+			trimmed = append(trimmed, t.what)
+			text = build()
+			tokens = learnings.EstimateTokens(text)
+		}
+	}
+
+	o.Metrics.SetPromptTokens(phase, tokens)
+	logging.Debug(fmt.Sprintf("The %s prompt is ~%d tokens (%s context window: %d)", phase, tokens, modelName, window))
+	if len(trimmed) > 0 {
+		logging.Warn(fmt.Sprintf("The %s prompt was over ~%d tokens, the budget for %s; trimmed %s, leaving ~%d tokens",
+			phase, budget, modelName, strings.Join(trimmed, " and "), tokens))
+	}
+	if tokens > budget {
+		logging.Warn(fmt.Sprintf("The %s prompt is ~%d tokens, over the ~%d token budget for %s, and nothing more can be trimmed",
+			phase, tokens, budget, modelName))
+	}
+	return text
+}
@@ -0,0 +1,52 @@
+package phases
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/metrics"
+)
+
+func TestFitPrompt_WithinBudget(t *testing.T) {
+	o := NewOrchestrator(config.NewDefaultConfig())
+	o.Metrics = metrics.New()
+	learningsText := "- keep me"
+	got := o.fitPrompt("implementation", "claude", "opus", func() string { return "prompt " + learningsText },
+		trimOldest("the oldest learnings", &learningsText))
+	assert.Equal(t, "prompt - keep me", got)
+
+	var out strings.Builder
+	_, _ = o.Metrics.WriteTo(&out)
+	assert.Contains(t, out.String(), `ralph_loop_prompt_tokens{phase="implementation"} 4`)
+}
+
+// TestFitPrompt_TrimsInOrder verifies that an oversized prompt loses its
+// oldest learnings first and its context only when that is not enough.
+func TestFitPrompt_TrimsInOrder(t *testing.T) {
+	o := NewOrchestrator(config.NewDefaultConfig())
+	// Copilot allows 128000 tokens, so the budget is ~64000 tokens or
+	// 256000 bytes.
+	line := strings.Repeat("x", 99) + "\n"
+	oldest := "- oldest\n" + strings.Repeat(line, 1500)
+	recent := "- recent\n" + strings.Repeat(line, 500)
+	learningsText := oldest + recent
+	contextText := strings.Repeat(line, 1000)
+	build := func() string { return "tasks\n" + learningsText + contextText }
+
+	got := o.fitPrompt("implementation", "copilot", "gpt-5", build,
+		trimOldest("the oldest learnings", &learningsText), dropSection("the code context", &contextText))
+	assert.NotContains(t, got, "- oldest")
+	assert.Contains(t, got, "- recent")
+	assert.Contains(t, got, "(older learnings omitted)")
+	assert.NotEmpty(t, contextText, "trimming learnings was enough")
+
+	// When the learnings cannot make up for the context, both go.
+	learningsText = recent
+	contextText = strings.Repeat(line, 3000)
+	got = o.fitPrompt("implementation", "copilot", "gpt-5", build,
+		trimOldest("the oldest learnings", &learningsText), dropSection("the code context", &contextText))
+	assert.Equal(t, "tasks\n", got)
+}
@@ -0,0 +1,125 @@
+package phases
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/banner"
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/gitdiff"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/notification"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// protectedPaths parses PROTECTED_PATHS: comma-separated paths relative
+// to the project root, each a file, a directory or a glob.
+func protectedPaths(spec string) []string {
+	var paths []string
+	for _, p := range strings.Split(spec, ",") {
+		p = strings.TrimSpace(filepath.ToSlash(p))
+		p = strings.TrimSuffix(strings.TrimPrefix(p, "./"), "/")
+		if p != "" && p != "." {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// isProtected reports whether file, slash-separated and relative to the
+// project root, is one of paths, lies under one of them or matches one as
+// a glob (itself or through a parent directory).
+func isProtected(file string, paths []string) bool {
+	for _, p := range paths {
+		if file == p || strings.HasPrefix(file, p+"/") {
+			return true
+		}
+		for dir := file; dir != "." && dir != "/"; dir = path.Dir(dir) {
+			if ok, _ := path.Match(p, dir); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// protectedChanges returns the protected files the iteration changed. It
+// finds nothing when no diff base was recorded.
+func (o *Orchestrator) protectedChanges() []string {
+	paths := protectedPaths(o.Config.ProtectedPaths)
+	if len(paths) == 0 {
+		return nil
+	}
+	if o.diffBase == "" {
+		logging.Warn("Protected paths not checked: the project is not a git repository")
+		return nil
+	}
+	changed, err := o.iterationChanges()
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Failed to list the files the iteration changed: %v", err))
+		return nil
+	}
+	var touched []string
+	for _, file := range changed {
+		if isProtected(file, paths) {
+			touched = append(touched, file)
+		}
+	}
+	return touched
+}
+
+// guardProtectedPaths applies PROTECTED_PATHS_ACTION to the protected files
+// the implementation phase changed: they are restored from the snapshot
+// taken before it and listed for the validator, or the loop escalates.
+// It returns the exit code to stop with, or -1 to go on.
+func (o *Orchestrator) guardProtectedPaths() int {
+	o.revertedPaths = nil
+	touched := o.protectedChanges()
+	if len(touched) == 0 {
+		return -1
+	}
+
+	if o.Config.ProtectedPathsAction == config.ProtectedEscalate {
+		reason := fmt.Sprintf("The implementation changed protected paths: %s", strings.Join(touched, ", "))
+		return o.escalateProtected(reason)
+	}
+
+	if err := gitdiff.Restore(o.workDir(), o.diffBase, touched); err != nil {
+		reason := fmt.Sprintf("Failed to revert changes to protected paths (%s): %v", strings.Join(touched, ", "), err)
+		logging.Error(reason)
+		return o.escalateProtected(reason)
+	}
+	logging.Warn(fmt.Sprintf("Reverted changes to protected paths: %s", strings.Join(touched, ", ")))
+	o.revertedPaths = touched
+	return -1
+}
+
+// escalateProtected stops the loop for human review of a protected path
+// change.
+func (o *Orchestrator) escalateProtected(reason string) int {
+	banner.PrintEscalationBanner(reason)
+	o.writeEscalationReport(reason, "", "")
+	o.notify(notification.EventEscalate, exitcode.Result{Code: exitcode.Escalate, Reason: feedbackReason(reason)})
+	if err := state.SaveState(o.session, o.StateDir); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save escalate state: %v", err))
+	}
+	return exitcode.Escalate
+}
+
+// noteRevertedPaths tells the implementer, through the validation
+// feedback, which of its changes were reverted.
+func (o *Orchestrator) noteRevertedPaths(result ValidationPhaseResult) ValidationPhaseResult {
+	if len(o.revertedPaths) == 0 {
+		return result
+	}
+	note := "ralph-loop reverted your changes to these protected paths; do not change them:\n- " +
+		strings.Join(o.revertedPaths, "\n- ")
+	if result.Feedback != "" {
+		note += "\n\n" + result.Feedback
+	}
+	result.Feedback = note
+	return result
+}
@@ -0,0 +1,139 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtectedPaths(t *testing.T) {
+	assert.Equal(t, []string{".github/workflows", "infra/prod", "*.tf"},
+		protectedPaths(" ./.github/workflows/ , infra/prod,,*.tf"))
+	assert.Empty(t, protectedPaths(""))
+}
+
+func TestIsProtected(t *testing.T) {
+	paths := []string{".github/workflows", "infra/prod", "*.tf", "deploy/*/secrets"}
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{".github/workflows/ci.yml", true},
+		{".github/workflows", true},
+		{".github/dependabot.yml", false},
+		{"infra/prod/main.tf", true},
+		{"infra/production/main.tf", false},
+		{"main.tf", true},
+		{"modules/vpc/main.tf", false},
+		{"deploy/eu/secrets/key.pem", true},
+		{"deploy/eu/config.yml", false},
+		{"main.go", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			assert.Equal(t, tt.want, isProtected(tt.file, paths))
+		})
+	}
+}
+
+// protectedTestRepo returns a test repository with a protected
+// workflow file next to main.go.
+func protectedTestRepo(t *testing.T) (workDir, workflow string) {
+	t.Helper()
+	workDir = diffTestRepo(t)
+	workflow = filepath.Join(workDir, ".github", "workflows", "ci.yml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(workflow), 0755))
+	require.NoError(t, os.WriteFile(workflow, []byte("on: push\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(workDir, ".ralph-loop"), 0755))
+	return workDir, workflow
+}
+
+// TestOrchestrator_RevertsProtectedPaths verifies that changes to protected
+// paths are undone before validation while the rest of the iteration's
+// work is kept, and that the validator is told.
+func TestOrchestrator_RevertsProtectedPaths(t *testing.T) {
+	workDir, workflow := protectedTestRepo(t)
+	added := filepath.Join(workDir, ".github", "workflows", "deploy.yml")
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	valRunner := completeValidator(cfg)
+	impl := &MockOrchestratorAIRunner{RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+		_ = os.WriteFile(workflow, []byte("on: [push, pull_request]\n"), 0644)
+		_ = os.WriteFile(added, []byte("on: push\n"), 0644)
+		_ = os.WriteFile(filepath.Join(workDir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+		return os.WriteFile(outputPath, []byte("Done."), 0644)
+	}}
+	o := newTestOrchestrator(t, cfg, filepath.Join(workDir, ".ralph-loop"), impl, valRunner)
+	o.WorkDir = workDir
+	o.Config.ProtectedPaths = ".github/workflows"
+
+	o.Run(context.Background())
+
+	data, err := os.ReadFile(workflow)
+	require.NoError(t, err)
+	assert.Equal(t, "on: push\n", string(data))
+	assert.NoFileExists(t, added)
+	data, err = os.ReadFile(filepath.Join(workDir, "main.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "func main() {}")
+
+	require.NotEmpty(t, valRunner.PromptLog)
+	assert.Contains(t, valRunner.PromptLog[0], "REVERTED CHANGES TO PROTECTED PATHS")
+	assert.Contains(t, valRunner.PromptLog[0], "- .github/workflows/ci.yml")
+	assert.Equal(t, []string{".github/workflows/ci.yml", ".github/workflows/deploy.yml"}, o.revertedPaths)
+}
+
+func TestOrchestrator_ProtectedPathsInImplPrompt(t *testing.T) {
+	workDir, _ := protectedTestRepo(t)
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	implRunner := doneImplementer()
+	o := newTestOrchestrator(t, cfg, filepath.Join(workDir, ".ralph-loop"), implRunner, nil)
+	o.WorkDir = workDir
+	o.Config.ProtectedPaths = ".github/workflows,infra/prod"
+
+	o.Run(context.Background())
+
+	require.NotEmpty(t, implRunner.PromptLog)
+	assert.Contains(t, implRunner.PromptLog[0], "PROTECTED PATHS")
+	assert.Contains(t, implRunner.PromptLog[0], "- .github/workflows\n- infra/prod")
+}
+
+func TestOrchestrator_EscalatesOnProtectedPaths(t *testing.T) {
+	workDir, workflow := protectedTestRepo(t)
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	valRunner := completeValidator(cfg)
+	impl := &MockOrchestratorAIRunner{RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+		_ = os.WriteFile(workflow, []byte("on: [push, pull_request]\n"), 0644)
+		return os.WriteFile(outputPath, []byte("Done."), 0644)
+	}}
+	o := newTestOrchestrator(t, cfg, filepath.Join(workDir, ".ralph-loop"), impl, valRunner)
+	o.WorkDir = workDir
+	o.Config.ProtectedPaths = ".github/workflows"
+	o.Config.ProtectedPathsAction = config.ProtectedEscalate
+
+	code := o.Run(context.Background())
+
+	assert.Equal(t, exitcode.Escalate, code)
+	assert.Empty(t, valRunner.PromptLog, "validation must not run")
+	data, err := os.ReadFile(workflow)
+	require.NoError(t, err)
+	assert.Equal(t, "on: [push, pull_request]\n", string(data), "the change is left for review")
+}
+
+func TestNoteRevertedPaths(t *testing.T) {
+	o := &Orchestrator{revertedPaths: []string{"infra/prod/main.tf"}}
+	result := o.noteRevertedPaths(ValidationPhaseResult{Verdict: "NEEDS_MORE_WORK", Feedback: "Fix the tests."})
+	assert.Equal(t, "ralph-loop reverted your changes to these protected paths; do not change them:\n- infra/prod/main.tf\n\nFix the tests.", result.Feedback)
+
+	o.revertedPaths = nil
+	result = o.noteRevertedPaths(ValidationPhaseResult{Feedback: "Fix the tests."})
+	assert.Equal(t, "Fix the tests.", result.Feedback)
+}
@@ -0,0 +1,202 @@
+package phases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/CodexForgeBR/cli-tools/internal/ai"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+)
+
+// QuorumMember is one validator taking part in a validation vote.
+type QuorumMember struct {
+	Label  string // e.g. "claude/opus", used in logs and dissent feedback
+	Runner ai.AIRunner
+}
+
+// QuorumConfig configures a validation vote.
+type QuorumConfig struct {
+	Members    []QuorumMember
+	OutputPath string // aggregated output; each member writes OutputPath with a -N suffix
+	Prompt     string
+	// CustomVerdicts are accepted in addition to the built-in verdicts.
+	CustomVerdicts []string
+	FormatRetries  int
+}
+
+// verdictTieOrder breaks ties between equally voted verdicts, most
+// conservative first: a split vote never completes or exits the loop when
+// it could keep working instead.
+var verdictTieOrder = []string{"INADMISSIBLE", "NEEDS_MORE_WORK", "BLOCKED", "ESCALATE", "COMPLETE"}
+
+// memberVote is the outcome of one quorum member's validation run.
+type memberVote struct {
+	label  string
+	result ValidationPhaseResult
+	err    error
+}
+
+// memberOutputPath returns the output path for the i-th (0-based) member:
+// validation-output.txt -> validation-output-1.txt.
+func memberOutputPath(outputPath string, i int) string {
+	ext := filepath.Ext(outputPath)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(outputPath, ext), i+1, ext)
+}
+
+// RunValidationQuorum runs every member concurrently on the same prompt and
+// aggregates their verdicts by plurality, breaking ties with
+// verdictTieOrder. Feedback from the winning side is kept, and feedback from
+// dissenting validators is appended so the implementer sees every concern.
+//
+// Members that fail or return no verdict do not vote. An error is returned
+// only when no member produced a verdict. The aggregated RALPH_VALIDATION
+// block is written to OutputPath.
+func RunValidationQuorum(ctx context.Context, cfg QuorumConfig) (ValidationPhaseResult, error) {
+	votes := make([]memberVote, len(cfg.Members))
+	var wg sync.WaitGroup
+	for i, m := range cfg.Members {
+		wg.Add(1)
+		go func(i int, m QuorumMember) {
+			defer wg.Done()
+			result, err := RunValidationPhaseWithResult(ctx, ValidationConfig{
+				Runner:         m.Runner,
+				OutputPath:     memberOutputPath(cfg.OutputPath, i),
+				Prompt:         cfg.Prompt,
+				CustomVerdicts: cfg.CustomVerdicts,
+				FormatRetries:  cfg.FormatRetries,
+			})
+			votes[i] = memberVote{label: m.Label, result: result, err: err}
+		}(i, m)
+	}
+	wg.Wait()
+
+	var valid []memberVote
+	var firstErr error
+	for i, v := range votes {
+		switch {
+		case v.err != nil:
+			logging.Warn(fmt.Sprintf("Validator %d (%s) failed: %v", i+1, v.label, v.err))
+			if firstErr == nil {
+				firstErr = v.err
+			}
+		case v.result.Verdict == "":
+			logging.Warn(fmt.Sprintf("Validator %d (%s) returned no verdict", i+1, v.label))
+		default:
+			logging.Info(fmt.Sprintf("Validator %d (%s): %s", i+1, v.label, v.result.Verdict))
+			valid = append(valid, v)
+		}
+	}
+	if len(valid) == 0 {
+		if firstErr != nil {
+			return ValidationPhaseResult{}, fmt.Errorf("all validators failed: %w", firstErr)
+		}
+		return ValidationPhaseResult{}, fmt.Errorf("no validator returned a verdict")
+	}
+
+	result := aggregateVotes(valid)
+	logging.Info(fmt.Sprintf("Validator quorum: %s", result.Verdict))
+	if err := writeQuorumOutput(cfg.OutputPath, result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// aggregateVotes combines the votes of validators that returned a verdict.
+func aggregateVotes(votes []memberVote) ValidationPhaseResult {
+	counts := make(map[string]int)
+	for _, v := range votes {
+		counts[v.result.Verdict]++
+	}
+
+	winner := ""
+	for _, verdict := range verdictTieOrder {
+		if counts[verdict] > counts[winner] {
+			winner = verdict
+		}
+	}
+	// Other verdicts (custom or unknown) need strictly more votes than the
+	// built-in ones, so ties still go the conservative way.
+	for _, v := range votes {
+		if counts[v.result.Verdict] > counts[winner] {
+			winner = v.result.Verdict
+		}
+	}
+
+	result := ValidationPhaseResult{Verdict: winner}
+	var agreeing, dissenting []string
+	for _, v := range votes {
+		if v.result.Verdict != winner {
+			if v.result.Feedback != "" {
+				dissenting = append(dissenting, fmt.Sprintf("- %s voted %s: %s", v.label, v.result.Verdict, v.result.Feedback))
+			}
+			continue
+		}
+		if v.result.Feedback != "" && !slices.Contains(agreeing, v.result.Feedback) {
+			agreeing = append(agreeing, v.result.Feedback)
+		}
+		result.BlockedTasks = appendUnique(result.BlockedTasks, v.result.BlockedTasks)
+		result.CompletedTasks = appendUnique(result.CompletedTasks, v.result.CompletedTasks)
+		result.IncompleteTasks = appendUnique(result.IncompleteTasks, v.result.IncompleteTasks)
+		result.InadmissiblePractices = appendUnique(result.InadmissiblePractices, v.result.InadmissiblePractices)
+		// The quorum is only as confident as its least confident member.
+		if c := v.result.Confidence; c != nil && (result.Confidence == nil || *c < *result.Confidence) {
+			result.Confidence = c
+		}
+	}
+
+	result.Feedback = strings.Join(agreeing, "\n\n")
+	if len(dissenting) > 0 {
+		if result.Feedback != "" {
+			result.Feedback += "\n\n"
+		}
+		result.Feedback += fmt.Sprintf("Dissenting validators (%d/%d):\n%s",
+			len(votes)-counts[winner], len(votes), strings.Join(dissenting, "\n"))
+	}
+	return result
+}
+
+// writeQuorumOutput writes the aggregated verdict as a RALPH_VALIDATION block
+// so downstream phases can read it like a single validator's output.
+func writeQuorumOutput(path string, result ValidationPhaseResult) error {
+	validation := map[string]interface{}{
+		"verdict":          result.Verdict,
+		"feedback":         result.Feedback,
+		"blocked_tasks":    nonNil(result.BlockedTasks),
+		"completed_tasks":  nonNil(result.CompletedTasks),
+		"incomplete_tasks": nonNil(result.IncompleteTasks),
+	}
+	if len(result.InadmissiblePractices) > 0 {
+		validation["inadmissible_practices"] = result.InadmissiblePractices
+	}
+	if result.Confidence != nil {
+		validation["confidence"] = *result.Confidence
+	}
+	block := map[string]interface{}{"RALPH_VALIDATION": validation}
+	data, err := json.MarshalIndent(block, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte("```json\n"+string(data)+"\n```\n"), 0644)
+}
+
+func appendUnique(dst, src []string) []string {
+	for _, s := range src {
+		if !slices.Contains(dst, s) {
+			dst = append(dst, s)
+		}
+	}
+	return dst
+}
+
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
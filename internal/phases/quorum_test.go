@@ -0,0 +1,164 @@
+package phases
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+)
+
+// verdictRunner returns a mock runner that writes a RALPH_VALIDATION block.
+func verdictRunner(verdict, feedback string) *MockOrchestratorAIRunner {
+	return &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			out := `{"RALPH_VALIDATION": {"verdict": "` + verdict + `", "feedback": "` + feedback + `", "incomplete_tasks": ["T001"]}}`
+			return os.WriteFile(outputPath, []byte(out), 0644)
+		},
+	}
+}
+
+func TestMemberOutputPath(t *testing.T) {
+	assert.Equal(t, "/x/validation-output-1.txt", memberOutputPath("/x/validation-output.txt", 0))
+	assert.Equal(t, "/x/out-3", memberOutputPath("/x/out", 2))
+}
+
+func TestAggregateVotes_Majority(t *testing.T) {
+	result := aggregateVotes([]memberVote{
+		{label: "claude/opus", result: ValidationPhaseResult{Verdict: "COMPLETE", Feedback: "looks good"}},
+		{label: "codex/default", result: ValidationPhaseResult{Verdict: "NEEDS_MORE_WORK", Feedback: "T002 untested", IncompleteTasks: []string{"T002"}}},
+		{label: "claude/sonnet", result: ValidationPhaseResult{Verdict: "COMPLETE", Feedback: "looks good", CompletedTasks: []string{"T001"}}},
+	})
+
+	assert.Equal(t, "COMPLETE", result.Verdict)
+	assert.Equal(t, []string{"T001"}, result.CompletedTasks)
+	assert.Empty(t, result.IncompleteTasks, "dissenting task lists are not merged")
+	assert.Equal(t, "looks good\n\nDissenting validators (1/3):\n- codex/default voted NEEDS_MORE_WORK: T002 untested", result.Feedback)
+}
+
+func TestAggregateVotes_TieIsConservative(t *testing.T) {
+	result := aggregateVotes([]memberVote{
+		{label: "a", result: ValidationPhaseResult{Verdict: "COMPLETE"}},
+		{label: "b", result: ValidationPhaseResult{Verdict: "NEEDS_MORE_WORK", Feedback: "fix tests"}},
+	})
+
+	assert.Equal(t, "NEEDS_MORE_WORK", result.Verdict)
+	assert.Equal(t, "fix tests", result.Feedback)
+}
+
+func TestAggregateVotes_CustomVerdictNeedsMajority(t *testing.T) {
+	result := aggregateVotes([]memberVote{
+		{label: "a", result: ValidationPhaseResult{Verdict: "SECURITY_REVIEW_NEEDED"}},
+		{label: "b", result: ValidationPhaseResult{Verdict: "COMPLETE"}},
+		{label: "c", result: ValidationPhaseResult{Verdict: "SECURITY_REVIEW_NEEDED"}},
+	})
+	assert.Equal(t, "SECURITY_REVIEW_NEEDED", result.Verdict)
+
+	result = aggregateVotes([]memberVote{
+		{label: "a", result: ValidationPhaseResult{Verdict: "SECURITY_REVIEW_NEEDED"}},
+		{label: "b", result: ValidationPhaseResult{Verdict: "COMPLETE"}},
+	})
+	assert.Equal(t, "COMPLETE", result.Verdict, "ties go to the built-in verdicts")
+}
+
+func TestRunValidationQuorum_WritesAggregatedOutput(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "validation-output.txt")
+
+	result, err := RunValidationQuorum(context.Background(), QuorumConfig{
+		Members: []QuorumMember{
+			{Label: "v1", Runner: verdictRunner("NEEDS_MORE_WORK", "missing docs")},
+			{Label: "v2", Runner: verdictRunner("NEEDS_MORE_WORK", "missing docs")},
+			{Label: "v3", Runner: verdictRunner("COMPLETE", "all good")},
+		},
+		OutputPath: outputPath,
+		Prompt:     "validate",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "NEEDS_MORE_WORK", result.Verdict)
+	assert.Contains(t, result.Feedback, "- v3 voted COMPLETE: all good")
+
+	for i := 1; i <= 3; i++ {
+		assert.FileExists(t, memberOutputPath(outputPath, i-1))
+	}
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	parsed, err := parser.ParseValidation(string(data))
+	require.NoError(t, err)
+	require.NotNil(t, parsed)
+	assert.Equal(t, "NEEDS_MORE_WORK", parsed.Verdict)
+	assert.Equal(t, []string{"T001"}, parsed.IncompleteTasks)
+}
+
+func TestRunValidationQuorum_FailedMembersDoNotVote(t *testing.T) {
+	failing := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return errors.New("boom")
+		},
+	}
+
+	result, err := RunValidationQuorum(context.Background(), QuorumConfig{
+		Members: []QuorumMember{
+			{Label: "bad", Runner: failing},
+			{Label: "good", Runner: verdictRunner("COMPLETE", "")},
+		},
+		OutputPath: filepath.Join(t.TempDir(), "validation-output.txt"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "COMPLETE", result.Verdict)
+}
+
+func TestRunValidationQuorum_AllFail(t *testing.T) {
+	// Members run concurrently, so each gets its own runner.
+	failing := func() *MockOrchestratorAIRunner {
+		return &MockOrchestratorAIRunner{
+			RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+				return errors.New("boom")
+			},
+		}
+	}
+
+	_, err := RunValidationQuorum(context.Background(), QuorumConfig{
+		Members:    []QuorumMember{{Label: "a", Runner: failing()}, {Label: "b", Runner: failing()}},
+		OutputPath: filepath.Join(t.TempDir(), "validation-output.txt"),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "all validators failed")
+}
+
+// TestOrchestrator_UsesValidatorQuorum verifies that the quorum replaces the
+// single validator and that its majority verdict drives the loop.
+func TestOrchestrator_UsesValidatorQuorum(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [x] T001 Done\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 1
+	cfg.CrossValidate = false
+	cfg.EnableLearnings = false
+
+	single := verdictRunner("NEEDS_MORE_WORK", "")
+	orch := NewOrchestrator(cfg)
+	orch.CommandChecker = alwaysAvailable
+	orch.StateDir = tmpDir
+	orch.ImplRunner = verdictRunner("COMPLETE", "")
+	orch.ValRunner = single
+	orch.ValQuorum = []QuorumMember{
+		{Label: "v1", Runner: verdictRunner("COMPLETE", "")},
+		{Label: "v2", Runner: verdictRunner("COMPLETE", "")},
+		{Label: "v3", Runner: verdictRunner("NEEDS_MORE_WORK", "nit")},
+	}
+
+	exitCode := orch.Run(context.Background())
+	assert.Equal(t, exitcode.Success, exitCode)
+	assert.Equal(t, 0, single.CallCount)
+}
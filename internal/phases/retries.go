@@ -0,0 +1,87 @@
+package phases
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/ai"
+	"github.com/CodexForgeBR/cli-tools/internal/banner"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/notification"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// maxRetryErrorLen caps the last error message kept in the retry telemetry.
+const maxRetryErrorLen = 200
+
+// RecordRetry adds a retry event to the session's retry telemetry, which is
+// persisted with the state and shown by --status. It is safe to call from
+// any goroutine; events before the session exists are dropped. The first
+// wait for a rate limit in a call is sent as a rate_limited notification,
+// and a call the watchdog killed as hung is reported.
+func (o *Orchestrator) RecordRetry(ev ai.RetryEvent) {
+	var hangErr *ai.HangError
+	if errors.As(ev.Err, &hangErr) {
+		logging.Warn(hangErr.Error())
+	}
+	if ev.Class == ai.ClassRateLimit && ev.Err != nil && !ev.GaveUp && ev.Attempt == 1 {
+		// Deferred first, so it runs once retryMu is released.
+		defer o.notifyProgress(notification.EventRateLimited, fmt.Sprintf("waiting %ds before retrying", ev.Delay))
+	}
+	o.retryMu.Lock()
+	defer o.retryMu.Unlock()
+	if o.session == nil {
+		return
+	}
+	rs := &o.session.RetryState
+	if ev.Err == nil {
+		rs.Attempt, rs.Delay = 0, 0
+		return
+	}
+
+	rs.Total++
+	if rs.Classes == nil {
+		rs.Classes = map[string]int{}
+	}
+	rs.Classes[string(ev.Class)]++
+	rs.LastClass = string(ev.Class)
+	rs.LastError = ev.Err.Error()
+	if len(rs.LastError) > maxRetryErrorLen {
+		rs.LastError = rs.LastError[:maxRetryErrorLen] + "..."
+	}
+	rs.LastAt = time.Now().Format(time.RFC3339)
+	if ev.GaveUp {
+		rs.GaveUp++
+		rs.Attempt, rs.Delay = 0, 0
+		return
+	}
+	rs.Attempt, rs.Delay = ev.Attempt, ev.Delay
+}
+
+// checkAuthFailure stops the loop when an AI CLI is not authenticated:
+// every later iteration would fail the same way. The session is saved so
+// it can be resumed after logging in. Returns -1 for other errors.
+func (o *Orchestrator) checkAuthFailure(err error) int {
+	if ai.Classify(err) != ai.ClassAuth {
+		return -1
+	}
+	logging.Error("The AI CLI is not authenticated. Log in and continue with --resume.")
+	if err := state.SaveState(o.session, o.StateDir); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save state: %v", err))
+	}
+	return exitcode.Error
+}
+
+// retryInfo converts retry telemetry into the status display summary.
+func retryInfo(rs state.RetryState) banner.RetryInfo {
+	return banner.RetryInfo{
+		Total:     rs.Total,
+		Classes:   rs.Classes,
+		GaveUp:    rs.GaveUp,
+		LastClass: rs.LastClass,
+		LastError: rs.LastError,
+		LastAt:    rs.LastAt,
+	}
+}
@@ -0,0 +1,81 @@
+package phases
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/ai"
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+func TestRecordRetry(t *testing.T) {
+	o := NewOrchestrator(config.NewDefaultConfig())
+	o.RecordRetry(ai.RetryEvent{Class: ai.ClassTransient, Err: errors.New("early")})
+	o.session = &state.SessionState{}
+
+	o.RecordRetry(ai.RetryEvent{Class: ai.ClassTransient, Err: errors.New("exit status 1"), Attempt: 1, Delay: 5})
+	o.RecordRetry(ai.RetryEvent{Class: ai.ClassTransient, Err: errors.New("exit status 1"), Attempt: 2, Delay: 10})
+	rs := o.session.RetryState
+	assert.Equal(t, 2, rs.Attempt)
+	assert.Equal(t, 10, rs.Delay)
+
+	o.RecordRetry(ai.RetryEvent{})
+	o.RecordRetry(ai.RetryEvent{Class: ai.ClassAuth, Err: errors.New(strings.Repeat("x", 300)), Attempt: 1, GaveUp: true})
+
+	rs = o.session.RetryState
+	assert.Equal(t, 0, rs.Attempt, "reset once the call succeeds or gives up")
+	assert.Equal(t, 3, rs.Total, "events before the session are dropped")
+	assert.Equal(t, map[string]int{"transient": 2, "auth": 1}, rs.Classes)
+	assert.Equal(t, 1, rs.GaveUp)
+	assert.Equal(t, "auth", rs.LastClass)
+	assert.Len(t, rs.LastError, maxRetryErrorLen+3)
+	assert.NotEmpty(t, rs.LastAt)
+
+	o.RecordRetry(ai.RetryEvent{Class: ai.ClassHung, Err: &ai.HangError{Report: ai.HangReport{IdleSeconds: 600}}, Attempt: 1, Delay: 5})
+	assert.Equal(t, 1, o.session.RetryState.Classes["hung"])
+	assert.Equal(t, 5, o.session.RetryState.Delay)
+}
+
+func TestOrchestrator_StopsOnAuthFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] T001 Do it\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 5
+	cfg.CrossValidate = false
+	cfg.FinalPlanAI = ""
+	cfg.TasksValAI = ""
+
+	orchestrator := NewOrchestrator(cfg)
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return ai.RetryWithBackoff(ctx, ai.RetryConfig{MaxRetries: 3, OnEvent: orchestrator.RecordRetry}, func() error {
+				return &ai.AuthError{UnderlyingErr: errors.New("claude command failed: exit status 1")}
+			})
+		},
+	}
+	orchestrator.CommandChecker = alwaysAvailable
+	orchestrator.StateDir = tmpDir
+	orchestrator.ImplRunner = implRunner
+	orchestrator.ValRunner = &MockOrchestratorAIRunner{}
+
+	assert.Equal(t, exitcode.Error, orchestrator.Run(context.Background()))
+	assert.Equal(t, 1, implRunner.CallCount, "no further iterations are attempted")
+
+	saved, err := state.LoadState(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, state.StatusInProgress, saved.Status, "the session can be resumed")
+	assert.Equal(t, map[string]int{"auth": 1}, saved.RetryState.Classes)
+	assert.Equal(t, 1, saved.RetryState.GaveUp)
+}
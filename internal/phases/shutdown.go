@@ -0,0 +1,117 @@
+package phases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/banner"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/notification"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// RequestShutdown asks the loop to stop, saving its state, once the AI
+// call in progress returns: no further phase is started. The caller cancels
+// the run's context when the grace period ends. It is safe to call from any
+// goroutine.
+func (o *Orchestrator) RequestShutdown(signal string) {
+	o.signalMu.Lock()
+	defer o.signalMu.Unlock()
+	o.shutdownSignal = signal
+	o.graceful = true
+}
+
+// RecordInterrupt records the signal that is about to cancel the run's
+// context, so the saved state says what stopped the run. It is safe to call
+// from any goroutine.
+func (o *Orchestrator) RecordInterrupt(signal string) {
+	o.signalMu.Lock()
+	defer o.signalMu.Unlock()
+	o.shutdownSignal = signal
+}
+
+// RequestReload asks the loop to reload its configuration, through
+// ReloadConfig, before the next iteration. It is safe to call from any
+// goroutine.
+func (o *Orchestrator) RequestReload() {
+	o.signalMu.Lock()
+	defer o.signalMu.Unlock()
+	o.reloadRequested = true
+}
+
+// receivedSignal returns the signal that stopped or is stopping the run,
+// and whether it asked for a graceful stop.
+func (o *Orchestrator) receivedSignal() (string, bool) {
+	o.signalMu.Lock()
+	defer o.signalMu.Unlock()
+	return o.shutdownSignal, o.graceful
+}
+
+// interruptReason is the exit reason of an interrupted run, naming the
+// signal when one was received.
+func (o *Orchestrator) interruptReason(detail string) string {
+	if sig, _ := o.receivedSignal(); sig != "" {
+		return sig + ": " + detail
+	}
+	return detail
+}
+
+// stopForShutdown stops the loop when a graceful shutdown was requested.
+// It is called between phases, where the last checkpoint lets --resume
+// continue with the next one. It returns -1 to carry on.
+func (o *Orchestrator) stopForShutdown(detail string) int {
+	sig, graceful := o.receivedSignal()
+	if !graceful {
+		return -1
+	}
+	logging.Warn(fmt.Sprintf("%s received, stopping %s", sig, detail))
+	banner.PrintInterruptedBanner(o.session.Iteration, o.session.Phase)
+	o.notify(notification.EventInterrupted, exitcode.Result{Code: exitcode.Interrupted, Reason: o.interruptReason("stopped " + detail)})
+	if err := state.SaveState(o.session, o.StateDir); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save interrupted state: %v", err))
+	}
+	return exitcode.Interrupted
+}
+
+// recordShutdownSignal saves the signal that stopped the run in the session
+// state. It runs while the state lock is still held.
+func (o *Orchestrator) recordShutdownSignal() {
+	sig, _ := o.receivedSignal()
+	if sig == "" || o.session == nil || o.lock == nil {
+		return
+	}
+	o.session.ShutdownSignal = sig
+	if err := state.SaveState(o.session, o.StateDir); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save the shutdown signal: %v", err))
+	}
+}
+
+// applyReload reloads the configuration when a reload was requested,
+// applying the settings the loop reads every iteration.
+func (o *Orchestrator) applyReload() {
+	o.signalMu.Lock()
+	requested := o.reloadRequested
+	o.reloadRequested = false
+	o.signalMu.Unlock()
+	if !requested || o.ReloadConfig == nil {
+		return
+	}
+
+	fresh, err := o.ReloadConfig()
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Config reload failed, keeping the current settings: %v", err))
+		return
+	}
+	if changed := o.Config.Reload(fresh); len(changed) > 0 {
+		logging.Info(fmt.Sprintf("Config reloaded: %s", strings.Join(changed, ", ")))
+		for _, key := range changed {
+			if key == "NOTIFY_EVENTS" || key == "NOTIFY_DIGEST" {
+				o.applyNotifyPolicy()
+				break
+			}
+		}
+	} else {
+		logging.Info("Config reloaded: no setting changed")
+	}
+}
@@ -0,0 +1,102 @@
+package phases
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+func TestOrchestrator_GracefulShutdownAfterImplementation(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 3
+	dir := t.TempDir()
+	impl := &MockOrchestratorAIRunner{}
+	val := &MockOrchestratorAIRunner{}
+	o := newTestOrchestrator(t, cfg, dir, impl, val)
+	impl.RunFunc = func(ctx context.Context, prompt string, outputPath string) error {
+		// SIGTERM arrives while the implementer works; its call finishes.
+		o.RequestShutdown("SIGTERM")
+		return os.WriteFile(outputPath, []byte("Implemented."), 0644)
+	}
+
+	assert.Equal(t, exitcode.Interrupted, o.Run(context.Background()))
+	assert.Equal(t, 1, impl.CallCount)
+	assert.Zero(t, val.CallCount, "validation is not started")
+
+	saved, err := state.LoadState(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "SIGTERM", saved.ShutdownSignal)
+	require.NotNil(t, saved.Checkpoint)
+	assert.Equal(t, state.PhaseValidation, saved.Checkpoint.Phase, "--resume continues with validation")
+	require.NotNil(t, saved.Exit)
+	assert.Equal(t, "SIGTERM: stopped after the implementation of iteration 1", saved.Exit.Reason)
+}
+
+func TestOrchestrator_InterruptRecordsSignal(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	dir := t.TempDir()
+	impl := &MockOrchestratorAIRunner{}
+	o := newTestOrchestrator(t, cfg, dir, impl, &MockOrchestratorAIRunner{})
+	ctx, cancel := context.WithCancel(context.Background())
+	impl.RunFunc = func(ctx context.Context, prompt string, outputPath string) error {
+		o.RecordInterrupt("SIGINT")
+		cancel()
+		return ctx.Err()
+	}
+
+	assert.Equal(t, exitcode.Interrupted, o.Run(ctx))
+
+	saved, err := state.LoadState(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "SIGINT", saved.ShutdownSignal)
+}
+
+func TestStopForShutdown(t *testing.T) {
+	o := &Orchestrator{Config: config.NewDefaultConfig(), StateDir: t.TempDir(), session: &state.SessionState{Iteration: 2}}
+	o.Config.NotifyChatID = ""
+	o.RecordInterrupt("SIGINT")
+	assert.Equal(t, -1, o.stopForShutdown("before iteration 2"), "SIGINT cancels the context instead")
+
+	o.RequestShutdown("SIGTERM")
+	assert.Equal(t, exitcode.Interrupted, o.stopForShutdown("before iteration 2"))
+	assert.Equal(t, "SIGTERM: stopped before iteration 2", o.exit.Reason)
+	assert.FileExists(t, filepath.Join(o.StateDir, "current-state.json"))
+}
+
+func TestApplyReload(t *testing.T) {
+	o := &Orchestrator{Config: config.NewDefaultConfig()}
+	loads := 0
+	o.ReloadConfig = func() (*config.Config, error) {
+		loads++
+		fresh := config.NewDefaultConfig()
+		fresh.HookTimeout = 30
+		fresh.ImplModel = "sonnet"
+		return fresh, nil
+	}
+
+	o.applyReload()
+	assert.Zero(t, loads, "no reload was requested")
+
+	o.RequestReload()
+	o.applyReload()
+	assert.Equal(t, 1, loads)
+	assert.Equal(t, 30, o.Config.HookTimeout)
+	assert.Equal(t, "opus", o.Config.ImplModel)
+
+	o.applyReload()
+	assert.Equal(t, 1, loads, "a request is applied once")
+
+	o.ReloadConfig = func() (*config.Config, error) { return nil, errors.New("explicit config: bad line") }
+	o.RequestReload()
+	o.applyReload()
+	assert.Equal(t, 30, o.Config.HookTimeout, "a failed reload keeps the settings")
+}
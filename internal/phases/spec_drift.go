@@ -0,0 +1,347 @@
+package phases
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/banner"
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/notification"
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/prompt"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// specBaselineDir, in the state dir, holds a copy of every spec document as
+// the tasks were last validated against it.
+const specBaselineDir = "spec-baseline"
+
+// maxShownSpecChanges bounds the changed spec lines logged; the rest are
+// only counted.
+const maxShownSpecChanges = 20
+
+// specChange is a spec document that changed since its baseline.
+type specChange struct {
+	Doc            string
+	Added, Removed []string
+	content        []byte
+}
+
+// specDriftEnabled reports whether SPEC_DRIFT tracks the spec.
+func (o *Orchestrator) specDriftEnabled() bool {
+	return o.Config.SpecDrift != "" && o.Config.SpecDrift != config.SpecDriftOff
+}
+
+// specDocs returns the documents the tasks are validated against: the spec
+// and, when it is another document, the original plan.
+func (o *Orchestrator) specDocs() []string {
+	var docs []string
+	spec := o.specFile()
+	if spec != "" {
+		docs = append(docs, spec)
+	}
+	if plan := o.Config.OriginalPlanFile; plan != "" && plan != spec {
+		docs = append(docs, plan)
+	}
+	return docs
+}
+
+// specBaseline returns where the baseline of doc is kept.
+func (o *Orchestrator) specBaseline(doc string) string {
+	if abs, err := filepath.Abs(doc); err == nil {
+		doc = abs
+	}
+	sum := sha256.Sum256([]byte(doc))
+	return filepath.Join(o.StateDir, specBaselineDir, hex.EncodeToString(sum[:6])+"-"+filepath.Base(doc))
+}
+
+func (o *Orchestrator) saveSpecBaseline(doc string, content []byte) {
+	path := o.specBaseline(doc)
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err == nil {
+		err = os.WriteFile(path, content, 0644)
+	}
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save the spec baseline of %s: %v", doc, err))
+	}
+}
+
+// resetSpecBaseline forgets the baselines of a previous session, so a new
+// session starts from the spec as it is now.
+func (o *Orchestrator) resetSpecBaseline() {
+	if err := os.RemoveAll(filepath.Join(o.StateDir, specBaselineDir)); err != nil {
+		logging.Debug(fmt.Sprintf("Failed to reset spec baseline: %v", err))
+	}
+}
+
+// checkSpecDrift re-reads the spec documents before an iteration, after
+// re-fetching the issue when it is the spec. A document seen for the first
+// time becomes its own baseline. When a document's requirements changed,
+// the changed lines are shown and the tasks are validated against the new
+// spec; tasks that no longer match pause the loop or are regenerated, per
+// SPEC_DRIFT. It returns an exit code other than -1 when the loop stops.
+func (o *Orchestrator) checkSpecDrift(ctx context.Context) int {
+	if !o.specDriftEnabled() || o.Config.Replay != "" {
+		return -1
+	}
+	o.refreshIssue()
+
+	var changes []specChange
+	for _, doc := range o.specDocs() {
+		current, err := os.ReadFile(doc)
+		if err != nil {
+			logging.Debug(fmt.Sprintf("Spec drift: cannot read %s: %v", doc, err))
+			continue
+		}
+		baseline, err := os.ReadFile(o.specBaseline(doc))
+		if err != nil {
+			o.saveSpecBaseline(doc, current)
+			continue
+		}
+		if bytes.Equal(baseline, current) {
+			continue
+		}
+		added, removed := requirementChanges(string(baseline), string(current))
+		if len(added) == 0 && len(removed) == 0 {
+			// Only blank lines or indentation changed
+			o.saveSpecBaseline(doc, current)
+			continue
+		}
+		changes = append(changes, specChange{Doc: doc, Added: added, Removed: removed, content: current})
+	}
+	if len(changes) == 0 {
+		return -1
+	}
+
+	logging.Phase("Spec changed mid-run, revalidating the tasks before the next iteration")
+	report := formatSpecChanges(changes)
+	for _, line := range strings.Split(strings.TrimRight(limitLines(report, maxShownSpecChanges), "\n"), "\n") {
+		logging.Info(line)
+	}
+	reportPath := filepath.Join(o.StateDir, "spec-changes.md")
+	if err := os.WriteFile(reportPath, []byte(report), 0644); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to write %s: %v", reportPath, err))
+	}
+
+	if code := o.revalidateTasks(ctx, report); code >= 0 {
+		return code
+	}
+	for _, c := range changes {
+		o.saveSpecBaseline(c.Doc, c.content)
+	}
+	o.snapshotTasks(false)
+	o.syncTasks()
+	o.updateDashboardTasks()
+	if err := state.SaveState(o.session, o.StateDir); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save state after the spec change: %v", err))
+	}
+	return -1
+}
+
+// refreshIssue re-fetches the issue used as the spec into its cache file.
+// A failed fetch keeps the cached copy.
+func (o *Orchestrator) refreshIssue() {
+	if o.Config.GithubIssue == "" && o.Config.JiraIssue == "" {
+		return
+	}
+	issue, err := o.downloadIssue()
+	if err != nil {
+		logging.Debug(fmt.Sprintf("Spec drift: keeping the cached issue: %v", err))
+		return
+	}
+	if err := os.WriteFile(o.specFile(), []byte(issue.Document()), 0644); err != nil {
+		logging.Debug(fmt.Sprintf("Spec drift: cannot update the cached issue: %v", err))
+	}
+}
+
+// revalidateTasks validates the tasks against the changed spec until they
+// pass. With SPEC_DRIFT=pause, rejected tasks pause the loop for the user to
+// update them, and are validated again on ralph-loop resume-now; with
+// SPEC_DRIFT=regenerate they are regenerated once and must then pass.
+func (o *Orchestrator) revalidateTasks(ctx context.Context, changes string) int {
+	if o.TasksValRunner == nil {
+		logging.Warn("Tasks validation runner not configured, continuing with the current tasks")
+		return -1
+	}
+	regenerated := false
+	for {
+		result := RunTasksValidation(ctx, TasksValidationConfig{
+			Runner:    o.TasksValRunner,
+			SpecFile:  o.specFile(),
+			TasksFile: o.session.TasksFile,
+		})
+		if ctx.Err() != nil {
+			return o.interruptedBySpecDrift()
+		}
+		if result.Action == "success" {
+			o.recordGate(gateTasksValidation, "VALID")
+			logging.Success("Tasks still match the changed spec")
+			return -1
+		}
+		o.recordGate(gateTasksValidation, "INVALID")
+		logging.Warn(fmt.Sprintf("Tasks no longer match the spec: %s", result.Feedback))
+
+		if o.Config.SpecDrift == config.SpecDriftRegenerate {
+			if regenerated {
+				return o.tasksInvalidAfterDrift(result.Feedback)
+			}
+			if err := o.regenerateTasks(ctx, changes, result.Feedback); err != nil {
+				if ctx.Err() != nil {
+					return o.interruptedBySpecDrift()
+				}
+				logging.Error(fmt.Sprintf("Regenerating the tasks failed: %v", err))
+				return o.tasksInvalidAfterDrift(result.Feedback)
+			}
+			regenerated = true
+			logging.Success(fmt.Sprintf("Regenerated %s from the changed spec", o.session.TasksFile))
+			continue
+		}
+
+		logging.Phase(fmt.Sprintf("Paused for the spec change; update %s, then run ralph-loop resume-now", o.session.TasksFile))
+		if code := o.pause(ctx, "for the spec change"); code >= 0 {
+			return code
+		}
+	}
+}
+
+// regenerateTasks asks the implementation runner for the tasks updated to
+// the changed spec and replaces the tasks file with them. Checked tasks must
+// survive.
+func (o *Orchestrator) regenerateTasks(ctx context.Context, changes, feedback string) error {
+	before, err := tasks.ListTasks(o.session.TasksFile)
+	if err != nil {
+		return err
+	}
+	outputPath := filepath.Join(o.StateDir, "tasks-regenerate-output.txt")
+	p := prompt.BuildTasksRegeneratePrompt(o.specFile(), o.session.TasksFile, changes, feedback)
+	if err := o.ImplRunner.Run(ctx, p, outputPath); err != nil {
+		return fmt.Errorf("AI error: %w", err)
+	}
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		return fmt.Errorf("read output: %w", err)
+	}
+	_, doc := parser.ExtractPlan(string(output))
+	if doc == "" {
+		return fmt.Errorf("no RALPH_TASKS block in %s", outputPath)
+	}
+
+	tmp := o.session.TasksFile + ".regenerated"
+	if err := os.WriteFile(tmp, []byte(doc), 0644); err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	after, err := tasks.ListTasks(tmp)
+	if err != nil {
+		return err
+	}
+	if lost := lostCheckedTasks(before, after); len(lost) > 0 {
+		return fmt.Errorf("the regenerated tasks drop completed tasks: %s", strings.Join(lost, "; "))
+	}
+	return os.Rename(tmp, o.session.TasksFile)
+}
+
+func (o *Orchestrator) tasksInvalidAfterDrift(feedback string) int {
+	logging.Error(fmt.Sprintf("Tasks validation failed after the spec change: %s", feedback))
+	o.notify(notification.EventTasksInvalid, exitcode.Result{Code: exitcode.TasksInvalid, Reason: feedbackReason(feedback)})
+	if err := state.SaveState(o.session, o.StateDir); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save state: %v", err))
+	}
+	return exitcode.TasksInvalid
+}
+
+func (o *Orchestrator) interruptedBySpecDrift() int {
+	banner.PrintInterruptedBanner(o.session.Iteration, o.session.Phase)
+	o.notify(notification.EventInterrupted, exitcode.Result{Code: exitcode.Interrupted, Reason: o.interruptReason("interrupted while revalidating the tasks")})
+	if err := state.SaveState(o.session, o.StateDir); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save interrupted state: %v", err))
+	}
+	return exitcode.Interrupted
+}
+
+// lostCheckedTasks returns the checked tasks of before that are not checked
+// in after.
+func lostCheckedTasks(before, after []tasks.Task) []string {
+	kept := map[string]bool{}
+	for _, t := range after {
+		if t.Checked {
+			kept[t.Text] = true
+		}
+	}
+	var lost []string
+	for _, t := range before {
+		if t.Checked && !kept[t.Text] {
+			lost = append(lost, t.Text)
+		}
+	}
+	return lost
+}
+
+// requirementChanges compares two versions of a spec line by line,
+// ignoring blank lines and indentation, and returns the lines only in the
+// new version and those only in the old one, in document order.
+func requirementChanges(old, new string) (added, removed []string) {
+	oldLines, newLines := specLines(old), specLines(new)
+	return missingFrom(newLines, oldLines), missingFrom(oldLines, newLines)
+}
+
+// missingFrom returns the lines of a not matched by a line of b, counting
+// repeated lines.
+func missingFrom(a, b []string) []string {
+	count := map[string]int{}
+	for _, line := range b {
+		count[line]++
+	}
+	var missing []string
+	for _, line := range a {
+		if count[line] > 0 {
+			count[line]--
+			continue
+		}
+		missing = append(missing, line)
+	}
+	return missing
+}
+
+func specLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// formatSpecChanges lists the added (+) and removed (-) lines per document.
+func formatSpecChanges(changes []specChange) string {
+	var b strings.Builder
+	for _, c := range changes {
+		fmt.Fprintf(&b, "%s: %d line(s) added, %d removed\n", c.Doc, len(c.Added), len(c.Removed))
+		for _, line := range c.Added {
+			fmt.Fprintf(&b, "+ %s\n", line)
+		}
+		for _, line := range c.Removed {
+			fmt.Fprintf(&b, "- %s\n", line)
+		}
+	}
+	return b.String()
+}
+
+// limitLines keeps the first n lines of text and counts the others.
+func limitLines(text string, n int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) <= n {
+		return text
+	}
+	return strings.Join(lines[:n], "\n") + fmt.Sprintf("\n... %d more line(s) in spec-changes.md\n", len(lines)-n)
+}
@@ -0,0 +1,161 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+func TestRequirementChanges(t *testing.T) {
+	old := "# Export\n\n- Export as CSV\n- Export as XML\n  - Include headers\n"
+	new := "# Export\n- Export as CSV\n\n- Export as JSON\n- Include headers\n"
+	added, removed := requirementChanges(old, new)
+	assert.Equal(t, []string{"- Export as JSON"}, added)
+	assert.Equal(t, []string{"- Export as XML"}, removed)
+
+	added, removed = requirementChanges("a\n\nb\n", "  a\nb\n\n")
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+}
+
+// tasksValVerdicts returns a tasks validation runner answering with the
+// given verdicts in turn, repeating the last.
+func tasksValVerdicts(verdicts ...string) *MockOrchestratorAIRunner {
+	r := &MockOrchestratorAIRunner{}
+	r.RunFunc = func(ctx context.Context, prompt string, outputPath string) error {
+		v := verdicts[min(r.CallCount, len(verdicts))-1]
+		return os.WriteFile(outputPath, []byte(`{"RALPH_TASKS_VALIDATION":{"verdict":"`+v+`","feedback":"T002 still covers XML"}}`), 0644)
+	}
+	return r
+}
+
+// exportSpec writes a spec of two export requirements and returns its path.
+func exportSpec(t *testing.T) string {
+	t.Helper()
+	spec := filepath.Join(t.TempDir(), "spec.md")
+	require.NoError(t, os.WriteFile(spec, []byte("# Export\n- Export as CSV\n- Export as XML\n"), 0644))
+	return spec
+}
+
+func TestCheckSpecDrift_RevalidatesChangedSpec(t *testing.T) {
+	tasksVal := tasksValVerdicts("VALID")
+	cfg := config.NewDefaultConfig()
+	cfg.SpecDrift = config.SpecDriftPause
+	spec := exportSpec(t)
+	cfg.SpecFile = spec
+	o := newTestOrchestrator(t, cfg, t.TempDir(), nil, nil)
+	o.session = &state.SessionState{Iteration: 2, TasksFile: cfg.TasksFile}
+	o.TasksValRunner = tasksVal
+
+	assert.Equal(t, -1, o.checkSpecDrift(context.Background()))
+	assert.Equal(t, 0, tasksVal.CallCount, "the first check only records the baseline")
+
+	require.NoError(t, os.WriteFile(spec, []byte("# Export\n\n- Export as CSV\n- Export as XML\n"), 0644))
+	assert.Equal(t, -1, o.checkSpecDrift(context.Background()))
+	assert.Equal(t, 0, tasksVal.CallCount, "blank lines are not a requirement change")
+
+	require.NoError(t, os.WriteFile(spec, []byte("# Export\n- Export as CSV\n- Export as JSON\n"), 0644))
+	assert.Equal(t, -1, o.checkSpecDrift(context.Background()))
+	assert.Equal(t, 1, tasksVal.CallCount)
+	report, err := os.ReadFile(filepath.Join(o.StateDir, "spec-changes.md"))
+	require.NoError(t, err)
+	assert.Equal(t, spec+": 1 line(s) added, 1 removed\n+ - Export as JSON\n- - Export as XML\n", string(report))
+
+	assert.Equal(t, -1, o.checkSpecDrift(context.Background()))
+	assert.Equal(t, 1, tasksVal.CallCount, "the validated spec is the new baseline")
+}
+
+func TestCheckSpecDrift_Off(t *testing.T) {
+	tasksVal := tasksValVerdicts("VALID")
+	cfg := config.NewDefaultConfig()
+	cfg.SpecDrift = config.SpecDriftOff
+	spec := exportSpec(t)
+	cfg.SpecFile = spec
+	o := newTestOrchestrator(t, cfg, t.TempDir(), nil, nil)
+	o.session = &state.SessionState{Iteration: 2, TasksFile: cfg.TasksFile}
+	o.TasksValRunner = tasksVal
+	assert.Equal(t, -1, o.checkSpecDrift(context.Background()))
+	require.NoError(t, os.WriteFile(spec, []byte("# Changed\n"), 0644))
+	assert.Equal(t, -1, o.checkSpecDrift(context.Background()))
+	assert.Equal(t, 0, tasksVal.CallCount)
+	assert.NoDirExists(t, filepath.Join(o.StateDir, specBaselineDir))
+}
+
+func TestCheckSpecDrift_PausesUntilTasksMatch(t *testing.T) {
+	fastControlPoll(t)
+	tasksVal := tasksValVerdicts("INVALID", "VALID")
+	cfg := config.NewDefaultConfig()
+	cfg.SpecDrift = config.SpecDriftPause
+	spec := exportSpec(t)
+	cfg.SpecFile = spec
+	o := newTestOrchestrator(t, cfg, t.TempDir(), nil, nil)
+	o.session = &state.SessionState{Iteration: 2, TasksFile: cfg.TasksFile}
+	o.TasksValRunner = tasksVal
+	o.checkSpecDrift(context.Background())
+	require.NoError(t, os.WriteFile(spec, []byte("# Export\n- Export as CSV\n- Export as JSON\n"), 0644))
+
+	resumed := resumeWhenPaused(t, o.StateDir)
+	assert.Equal(t, -1, o.checkSpecDrift(context.Background()))
+	<-resumed
+	assert.Equal(t, 2, tasksVal.CallCount, "the tasks are validated again after resume-now")
+	assert.NotEqual(t, state.PhasePaused, o.session.Phase)
+}
+
+func TestCheckSpecDrift_Regenerate(t *testing.T) {
+	tasksVal := tasksValVerdicts("INVALID", "VALID")
+	cfg := config.NewDefaultConfig()
+	cfg.SpecDrift = config.SpecDriftRegenerate
+	spec := exportSpec(t)
+	cfg.SpecFile = spec
+	o := newTestOrchestrator(t, cfg, t.TempDir(), nil, nil)
+	require.NoError(t, os.WriteFile(cfg.TasksFile, []byte("- [x] T001 CSV export\n- [ ] T002 XML export\n"), 0644))
+	o.session = &state.SessionState{Iteration: 2, TasksFile: cfg.TasksFile}
+	o.TasksValRunner = tasksVal
+	impl := &MockOrchestratorAIRunner{RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+		return os.WriteFile(outputPath, []byte("RALPH_TASKS\n````markdown\n- [x] T001 CSV export\n- [ ] T003 JSON export\n````\n"), 0644)
+	}}
+	o.ImplRunner = impl
+	o.checkSpecDrift(context.Background())
+	require.NoError(t, os.WriteFile(spec, []byte("# Export\n- Export as CSV\n- Export as JSON\n"), 0644))
+
+	assert.Equal(t, -1, o.checkSpecDrift(context.Background()))
+	require.Len(t, impl.PromptLog, 1)
+	assert.Contains(t, impl.PromptLog[0], "+ - Export as JSON")
+	assert.Contains(t, impl.PromptLog[0], "T002 still covers XML")
+	data, err := os.ReadFile(o.session.TasksFile)
+	require.NoError(t, err)
+	assert.Equal(t, "- [x] T001 CSV export\n- [ ] T003 JSON export\n", string(data))
+	assert.NotEmpty(t, o.session.TasksFileHash)
+	assert.Equal(t, 2, tasksVal.CallCount)
+}
+
+func TestCheckSpecDrift_RegenerateKeepsCompletedTasks(t *testing.T) {
+	tasksVal := tasksValVerdicts("INVALID")
+	cfg := config.NewDefaultConfig()
+	cfg.SpecDrift = config.SpecDriftRegenerate
+	spec := exportSpec(t)
+	cfg.SpecFile = spec
+	o := newTestOrchestrator(t, cfg, t.TempDir(), nil, nil)
+	require.NoError(t, os.WriteFile(cfg.TasksFile, []byte("- [x] T001 CSV export\n- [ ] T002 XML export\n"), 0644))
+	o.session = &state.SessionState{Iteration: 2, TasksFile: cfg.TasksFile}
+	o.TasksValRunner = tasksVal
+	o.ImplRunner = &MockOrchestratorAIRunner{RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+		return os.WriteFile(outputPath, []byte("RALPH_TASKS\n````markdown\n- [ ] T003 JSON export\n````\n"), 0644)
+	}}
+	o.checkSpecDrift(context.Background())
+	require.NoError(t, os.WriteFile(spec, []byte("# Export\n- Export as JSON\n"), 0644))
+
+	assert.Equal(t, exitcode.TasksInvalid, o.checkSpecDrift(context.Background()))
+	data, err := os.ReadFile(o.session.TasksFile)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "- [x] T001 CSV export\n"), "the tasks file is left alone")
+}
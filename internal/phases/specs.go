@@ -0,0 +1,130 @@
+package phases
+
+import (
+	"fmt"
+
+	"github.com/CodexForgeBR/cli-tools/internal/banner"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// multiSpec reports whether --tasks-file named several files or a glob.
+func (o *Orchestrator) multiSpec() bool {
+	files := o.Config.TasksFiles
+	return len(files) > 1 || (len(files) == 1 && tasks.IsGlob(files[0]))
+}
+
+// findSpecs expands the --tasks-file values into the session's spec list,
+// ordered by frontmatter priority, and returns the first tasks file with
+// unchecked tasks. It returns "" when every spec is already complete.
+func (o *Orchestrator) findSpecs() (string, error) {
+	files, err := tasks.ExpandTasksFiles(o.Config.TasksFiles)
+	if err != nil {
+		return "", err
+	}
+	files = tasks.OrderByPriority(files)
+
+	o.session.Specs = make([]state.SpecProgress, len(files))
+	for i, f := range files {
+		o.session.Specs[i] = state.SpecProgress{TasksFile: f, Status: state.SpecPending}
+	}
+	o.refreshSpecCounts()
+	logging.Info(fmt.Sprintf("Found %d tasks files", len(files)))
+
+	for i := range o.session.Specs {
+		spec := &o.session.Specs[i]
+		if spec.Done == spec.Total {
+			spec.Status = state.SpecDone
+			continue
+		}
+		spec.Status = state.SpecActive
+		spec.FirstIteration = o.session.Iteration + 1
+		return spec.TasksFile, nil
+	}
+	return "", nil
+}
+
+// nextSpec marks the active spec done and switches the session to the next
+// spec with unchecked tasks. It returns false when there is none left. The
+// iteration budget is shared, so the loop simply carries on.
+func (o *Orchestrator) nextSpec() (bool, error) {
+	next := -1
+	for i := range o.session.Specs {
+		spec := &o.session.Specs[i]
+		switch {
+		case spec.TasksFile == o.session.TasksFile:
+			spec.Status = state.SpecDone
+			spec.LastIteration = o.session.Iteration
+		case spec.Status == state.SpecPending && next < 0:
+			if unchecked, err := tasks.CountUnchecked(spec.TasksFile); err == nil && unchecked == 0 {
+				spec.Status = state.SpecDone
+				continue
+			}
+			next = i
+		}
+	}
+	o.refreshSpecCounts()
+	if next < 0 {
+		return false, nil
+	}
+
+	spec := &o.session.Specs[next]
+	spec.Status = state.SpecActive
+	spec.FirstIteration = o.session.Iteration + 1
+
+	o.Config.TasksFile = spec.TasksFile
+	o.session.TasksFile = spec.TasksFile
+	o.wireSpecKit(spec.TasksFile)
+	if err := o.snapshotTasks(true); err != nil {
+		return false, fmt.Errorf("hash %s: %w", spec.TasksFile, err)
+	}
+	o.session.Tasks = nil
+	o.session.LastFeedback = ""
+	o.session.FeedbackIssues = nil
+	o.session.Checkpoint = nil
+	o.syncTasks()
+	if err := o.checkDependencies(); err != nil {
+		return false, fmt.Errorf("invalid task dependencies in %s: %w", spec.TasksFile, err)
+	}
+
+	logging.Success(fmt.Sprintf("Spec complete, moving on to %s (%d/%d)", spec.TasksFile, next+1, len(o.session.Specs)))
+	o.Dashboard.SetSession(o.session.SessionID, o.Config.AIProvider, o.Config.ImplModel, spec.TasksFile)
+	o.updateDashboardTasks()
+	return true, nil
+}
+
+// refreshSpecCounts re-reads the task counts of every spec.
+func (o *Orchestrator) refreshSpecCounts() {
+	for i := range o.session.Specs {
+		spec := &o.session.Specs[i]
+		if f, err := tasks.Parse(spec.TasksFile); err == nil {
+			done, remaining := f.Counts()
+			spec.Done, spec.Total = done, done+remaining
+		}
+	}
+}
+
+// printSpecSummary prints the combined per-spec summary of a multi-file
+// session.
+func (o *Orchestrator) printSpecSummary() {
+	if o.session == nil || len(o.session.Specs) == 0 {
+		return
+	}
+	o.refreshSpecCounts()
+	infos := make([]banner.SpecInfo, len(o.session.Specs))
+	for i, s := range o.session.Specs {
+		if s.Status == state.SpecActive {
+			s.LastIteration = o.session.Iteration
+		}
+		infos[i] = banner.SpecInfo{
+			TasksFile:      s.TasksFile,
+			Status:         s.Status,
+			FirstIteration: s.FirstIteration,
+			LastIteration:  s.LastIteration,
+			Done:           s.Done,
+			Total:          s.Total,
+		}
+	}
+	banner.PrintSpecSummary(infos)
+}
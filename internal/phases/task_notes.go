@@ -0,0 +1,77 @@
+package phases
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// completedNotePrefix starts the note annotateTasks writes on completed
+// tasks.
+const completedNotePrefix = "completed in iter "
+
+// annotateTasks writes a note on each task checked in this iteration,
+// "completed in iter 4, evidence: <artifacts kept for it>", and removes it
+// from tasks unchecked since. It does nothing without --annotate-tasks or
+// when the tasks file from before the iteration is unknown, as after a
+// resume.
+func (o *Orchestrator) annotateTasks() {
+	if !o.Config.AnnotateTasks || o.checkedAtStart == nil {
+		return
+	}
+	list, err := tasks.ListTasks(o.session.TasksFile)
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Failed to list tasks: %v", err))
+		return
+	}
+	changed := false
+	for _, t := range list {
+		var note string
+		switch {
+		case t.Checked && !o.checkedAtStart[t.ID]:
+			note = o.completedNote(t.ID)
+		case !t.Checked && strings.HasPrefix(t.Note, completedNotePrefix):
+		default:
+			continue
+		}
+		if note == t.Note {
+			continue
+		}
+		if err := tasks.SetNote(o.session.TasksFile, t.Line, note); err != nil {
+			logging.Warn(fmt.Sprintf("Failed to annotate task %s: %v", t.ID, err))
+			continue
+		}
+		changed = true
+	}
+	if changed {
+		o.snapshotTasks(false)
+	}
+}
+
+// completedNote describes the completion of task id in the current
+// iteration, naming the artifacts kept for it relative to the project root.
+func (o *Orchestrator) completedNote(id string) string {
+	note := fmt.Sprintf("%s%d", completedNotePrefix, o.session.Iteration)
+	var evidence []string
+	for _, a := range o.session.Artifacts {
+		if a.Iteration == o.session.Iteration && tasks.ExtractTaskID(a.Task) == id {
+			evidence = append(evidence, o.projectPath(a.Path))
+		}
+	}
+	if len(evidence) > 0 {
+		note += ", evidence: " + strings.Join(evidence, ", ")
+	}
+	return note
+}
+
+// projectPath returns path relative to the project root, or as given when
+// it lies outside it.
+func (o *Orchestrator) projectPath(path string) string {
+	if rel, ok := insideDir(o.workDir(), path); ok {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
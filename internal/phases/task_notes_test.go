@@ -0,0 +1,57 @@
+package phases
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+func TestAnnotateTasks(t *testing.T) {
+	dir := t.TempDir()
+	tasksFile := filepath.Join(dir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [x] T001 Done before\n- [x] T002 Run tests\n- [x] T003 Add docs\n- [ ] T004 Reopened <!-- ralph: completed in iter 2 -->\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.AnnotateTasks = true
+	o := NewOrchestrator(cfg)
+	o.StateDir = filepath.Join(dir, ".ralph-loop")
+	o.WorkDir = dir
+	o.session = &state.SessionState{Iteration: 4, TasksFile: tasksFile, Artifacts: []state.Artifact{
+		{Iteration: 4, Task: "T002", Path: filepath.Join(dir, ".ralph-loop", "iteration-004", "artifacts", "test.log")},
+		{Iteration: 3, Task: "T002", Path: filepath.Join(dir, "old.log")},
+		{Iteration: 4, Task: "T001", Path: filepath.Join(dir, "other.log")},
+	}}
+	o.checkedAtStart = map[string]bool{"T001": true, "T004": true}
+
+	o.annotateTasks()
+	data, err := os.ReadFile(tasksFile)
+	require.NoError(t, err)
+	assert.Equal(t, "- [x] T001 Done before\n"+
+		"- [x] T002 Run tests <!-- ralph: completed in iter 4, evidence: .ralph-loop/iteration-004/artifacts/test.log -->\n"+
+		"- [x] T003 Add docs <!-- ralph: completed in iter 4 -->\n"+
+		"- [ ] T004 Reopened\n", string(data))
+	hash, err := tasks.HashFile(tasksFile)
+	require.NoError(t, err)
+	assert.Equal(t, hash, o.session.TasksFileHash)
+}
+
+func TestAnnotateTasks_Off(t *testing.T) {
+	tasksFile := filepath.Join(t.TempDir(), "tasks.md")
+	content := "- [x] T001 Done\n"
+	require.NoError(t, os.WriteFile(tasksFile, []byte(content), 0644))
+	o := NewOrchestrator(config.NewDefaultConfig())
+	o.session = &state.SessionState{Iteration: 1, TasksFile: tasksFile}
+	o.checkedAtStart = map[string]bool{}
+
+	o.annotateTasks()
+	data, err := os.ReadFile(tasksFile)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+}
@@ -0,0 +1,157 @@
+package phases
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// syncTasks refreshes the per-task state from the tasks file on disk.
+// Failures are logged and otherwise ignored: task tracking is advisory.
+func (o *Orchestrator) syncTasks() {
+	list, err := tasks.ListTasks(o.session.TasksFile)
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Failed to list tasks: %v", err))
+		return
+	}
+	state.SyncTasks(o.session, list)
+}
+
+// trackTaskProgress records which tasks the current iteration touched, based
+// on the implementer's RALPH_STATUS block in statusOutputPath and the
+// validator's verdict. An empty statusOutputPath means there is no usable
+// RALPH_STATUS block.
+func (o *Orchestrator) trackTaskProgress(statusOutputPath string, valResult ValidationPhaseResult) {
+	o.syncTasks()
+
+	activity := state.TaskActivity{Verdict: valResult.Verdict}
+	if data, err := os.ReadFile(statusOutputPath); err == nil {
+		if status, err := parser.ParseStatus(string(data)); err == nil && status != nil {
+			activity.Touched = append(activity.Touched, status.CompletedTasks...)
+			activity.Blocked = append(activity.Blocked, status.BlockedTasks...)
+		}
+	}
+	activity.Touched = append(activity.Touched, valResult.CompletedTasks...)
+	activity.Touched = append(activity.Touched, valResult.IncompleteTasks...)
+	activity.Blocked = append(activity.Blocked, valResult.BlockedTasks...)
+
+	state.RecordTaskActivity(o.session, o.session.Iteration, activity)
+}
+
+// blockStuckTasks marks tasks that have made no progress for
+// MaxTaskAttempts consecutive iterations as blocked, annotates them in the
+// tasks file so the implementer skips them, and returns every task that is
+// currently skipped.
+func (o *Orchestrator) blockStuckTasks() []state.TaskState {
+	stuck := state.StuckTasks(o.session, o.Config.MaxTaskAttempts)
+	if len(stuck) > 0 {
+		list, err := tasks.ListTasks(o.session.TasksFile)
+		if err != nil {
+			logging.Warn(fmt.Sprintf("Failed to list tasks: %v", err))
+		}
+		for _, id := range stuck {
+			ts := state.FindTask(o.session, id)
+			reason := fmt.Sprintf("no progress after %d consecutive iterations", ts.Attempts)
+			ts.Status = state.TaskBlocked
+			ts.BlockedReason = reason
+			logging.Warn(fmt.Sprintf("Task %s is stuck (%s), skipping it", id, reason))
+
+			for _, t := range list {
+				if t.ID != id {
+					continue
+				}
+				if err := tasks.MarkBlocked(o.session.TasksFile, t.Line, reason); err != nil {
+					logging.Warn(fmt.Sprintf("Failed to annotate task %s: %v", id, err))
+				}
+				break
+			}
+		}
+	}
+	return state.SkippedTasks(o.session)
+}
+
+// skippedTaskLines describes the skipped tasks for the prompt section.
+func (o *Orchestrator) skippedTaskLines() []string {
+	var lines []string
+	for _, t := range state.SkippedTasks(o.session) {
+		lines = append(lines, fmt.Sprintf("%s (%s)", t.Text, t.BlockedReason))
+	}
+	return lines
+}
+
+// mergeBlockedTasks combines the validator's blocked tasks with the tasks
+// ralph-loop skipped, dropping skipped tasks the validator already listed.
+func mergeBlockedTasks(reported []string, skipped []state.TaskState) []string {
+	merged := append([]string{}, reported...)
+	listed := make(map[string]bool)
+	for _, ref := range reported {
+		if id := tasks.ExtractTaskID(ref); id != "" {
+			listed[id] = true
+		}
+	}
+	for _, t := range skipped {
+		if listed[t.ID] {
+			continue
+		}
+		merged = append(merged, strings.TrimSpace(t.Text+" (skipped: "+t.BlockedReason+")"))
+	}
+	return merged
+}
+
+// checkDependencies fails on a dependency cycle in the tasks file and warns
+// about dependencies on tasks that do not exist.
+func (o *Orchestrator) checkDependencies() error {
+	list, err := tasks.ListTasks(o.session.TasksFile)
+	if err != nil {
+		return err
+	}
+	for _, u := range tasks.UnknownDependencies(list) {
+		logging.Warn(fmt.Sprintf("Unknown task dependency: %s", u))
+	}
+	return tasks.CheckDependencies(list)
+}
+
+// readyTaskLines lists the tasks whose dependencies are met, for the
+// implementation prompt. It returns nil when the tasks file declares no
+// dependencies, so files without metadata get the prompt they always had.
+func (o *Orchestrator) readyTaskLines() []string {
+	list, err := tasks.ListTasks(o.session.TasksFile)
+	if err != nil || !tasks.HasDependencies(list) {
+		return nil
+	}
+	var lines []string
+	for _, t := range tasks.Unblocked(list) {
+		lines = append(lines, t.Text)
+	}
+	return lines
+}
+
+// enforceDependencies unchecks tasks that were checked while a task they
+// depend on is still unchecked, repeating until no such task remains.
+func (o *Orchestrator) enforceDependencies() {
+	for {
+		list, err := tasks.ListTasks(o.session.TasksFile)
+		if err != nil {
+			logging.Warn(fmt.Sprintf("Failed to list tasks: %v", err))
+			return
+		}
+		premature := tasks.PrematurelyChecked(list)
+		if len(premature) == 0 {
+			return
+		}
+		byID := tasks.IndexTasks(list)
+		for _, t := range premature {
+			pending := tasks.PendingDependencies(t, byID)
+			logging.Warn(fmt.Sprintf("Task %s was checked before %s; unchecking it", t.ID, strings.Join(pending, ", ")))
+			if err := tasks.Uncheck(o.session.TasksFile, t.Line); err != nil {
+				logging.Warn(fmt.Sprintf("Failed to uncheck task %s: %v", t.ID, err))
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// TestOrchestrator_TracksPerTaskState verifies that RALPH_STATUS and
+// RALPH_VALIDATION outputs populate the per-task state.
+func TestOrchestrator_TracksPerTaskState(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte(`# Tasks
+- [ ] T001 Create setup
+- [ ] T002 Add config
+- [ ] T003 Needs credentials
+`), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 2
+	cfg.CrossValidate = false
+	cfg.EnableLearnings = false
+
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			out := `{"RALPH_STATUS": {"completed_tasks": ["T001"], "blocked_tasks": ["T003: no API key"], "notes": "done"}}`
+			return os.WriteFile(outputPath, []byte(out), 0644)
+		},
+	}
+	valRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			require.NoError(t, os.WriteFile(tasksFile, []byte(`# Tasks
+- [x] T001 Create setup
+- [ ] T002 Add config
+- [ ] T003 Needs credentials
+`), 0644))
+			out := `{"RALPH_VALIDATION": {"verdict": "NEEDS_MORE_WORK", "feedback": "T002 missing", "incomplete_tasks": ["T002"]}}`
+			return os.WriteFile(outputPath, []byte(out), 0644)
+		},
+	}
+
+	orch := NewOrchestrator(cfg)
+	orch.CommandChecker = alwaysAvailable
+	orch.StateDir = tmpDir
+	orch.ImplRunner = implRunner
+	orch.ValRunner = valRunner
+
+	exitCode := orch.Run(context.Background())
+	assert.Equal(t, exitcode.MaxIterations, exitCode)
+
+	saved, err := state.LoadState(tmpDir)
+	require.NoError(t, err)
+	require.Len(t, saved.Tasks, 3)
+
+	t1 := state.FindTask(saved, "T001")
+	assert.Equal(t, state.TaskDone, t1.Status)
+	assert.Equal(t, 1, t1.FirstIteration)
+	assert.Equal(t, 2, t1.LastIteration)
+
+	t2 := state.FindTask(saved, "T002")
+	assert.Equal(t, state.TaskPending, t2.Status)
+	assert.Equal(t, []string{"NEEDS_MORE_WORK", "NEEDS_MORE_WORK"}, t2.Verdicts)
+
+	t3 := state.FindTask(saved, "T003")
+	assert.Equal(t, state.TaskBlocked, t3.Status)
+}
+
+// TestOrchestrator_SkipsStuckTasks verifies that a task left incomplete for
+// MaxTaskAttempts consecutive iterations is annotated in the tasks file,
+// skipped in later prompts, and that the loop exits blocked once only
+// skipped tasks remain.
+func TestOrchestrator_SkipsStuckTasks(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte(`# Tasks
+- [ ] T001 Create setup
+- [ ] T002 Flaky integration
+- [ ] T003 Write docs
+`), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 10
+	cfg.MaxTaskAttempts = 2
+	cfg.CrossValidate = false
+	cfg.EnableLearnings = false
+
+	var implPrompts, valPrompts []string
+	implRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			implPrompts = append(implPrompts, prompt)
+			data, err := os.ReadFile(tasksFile)
+			require.NoError(t, err)
+			content := string(data)
+			if len(implPrompts) == 1 {
+				content = strings.Replace(content, "- [ ] T001", "- [x] T001", 1)
+			}
+			if len(implPrompts) == 3 {
+				content = strings.Replace(content, "- [ ] T003", "- [x] T003", 1)
+			}
+			require.NoError(t, os.WriteFile(tasksFile, []byte(content), 0644))
+			return os.WriteFile(outputPath, []byte(`{"RALPH_STATUS": {"completed_tasks": [], "blocked_tasks": [], "notes": "working"}}`), 0644)
+		},
+	}
+	valRunner := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			valPrompts = append(valPrompts, prompt)
+			// Confirm the task the implementer checked, so it stays checked.
+			confirmed := map[int]string{1: `"T001"`, 3: `"T003"`}[len(valPrompts)]
+			out := `{"RALPH_VALIDATION": {"verdict": "NEEDS_MORE_WORK", "feedback": "T002 still failing", "completed_tasks": [` + confirmed + `], "incomplete_tasks": ["T002"]}}`
+			return os.WriteFile(outputPath, []byte(out), 0644)
+		},
+	}
+
+	orch := NewOrchestrator(cfg)
+	orch.CommandChecker = alwaysAvailable
+	orch.StateDir = tmpDir
+	orch.ImplRunner = implRunner
+	orch.ValRunner = valRunner
+
+	exitCode := orch.Run(context.Background())
+	assert.Equal(t, exitcode.Blocked, exitCode)
+	require.Len(t, implPrompts, 3)
+
+	assert.NotContains(t, implPrompts[1], "SKIPPED TASKS")
+	assert.Contains(t, implPrompts[2], "SKIPPED TASKS")
+	assert.Contains(t, implPrompts[2], "T002 Flaky integration (no progress after 2 consecutive iterations)")
+	assert.Contains(t, valPrompts[2], "SKIPPED TASKS")
+
+	data, err := os.ReadFile(tasksFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "- [ ] T002 Flaky integration <!-- ralph:blocked no progress after 2 consecutive iterations -->")
+
+	saved, err := state.LoadState(tmpDir)
+	require.NoError(t, err)
+	t2 := state.FindTask(saved, "T002")
+	assert.Equal(t, state.TaskBlocked, t2.Status)
+	assert.Equal(t, "no progress after 2 consecutive iterations", t2.BlockedReason)
+}
+
+func TestMergeBlockedTasks(t *testing.T) {
+	skipped := []state.TaskState{
+		{ID: "T002", Text: "T002 Flaky", BlockedReason: "stuck"},
+		{ID: "T005", Text: "T005 Deploy", BlockedReason: "stuck"},
+	}
+
+	merged := mergeBlockedTasks([]string{"T002: needs creds"}, skipped)
+	assert.Equal(t, []string{"T002: needs creds", "T005 Deploy (skipped: stuck)"}, merged)
+	assert.Empty(t, mergeBlockedTasks(nil, nil))
+}
@@ -0,0 +1,111 @@
+package phases
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// Snapshots of the tasks file kept in the state directory for the resume
+// merge: the base both sides last agreed on, and the file as the loop last
+// recorded it.
+const (
+	tasksBaseSnapshot = "tasks-base.md"
+	tasksLoopSnapshot = "tasks-loop.md"
+	tasksUserBackup   = "tasks-user.md"
+	tasksConflicts    = "tasks-conflicts.md"
+)
+
+// snapshotTasks records the hash of the tasks file and saves the file as
+// the loop's side of a later merge; with base set it also becomes the
+// common base, as when a session starts on the file.
+func (o *Orchestrator) snapshotTasks(base bool) error {
+	data, err := os.ReadFile(o.session.TasksFile)
+	if err != nil {
+		return err
+	}
+	o.session.TasksFileHash = tasks.HashBytes(data)
+	names := []string{tasksLoopSnapshot}
+	if base {
+		names = append(names, tasksBaseSnapshot)
+	}
+	for _, name := range names {
+		o.writeStateFile(name, data)
+	}
+	return nil
+}
+
+// writeStateFile writes data to name in the state directory, warning on
+// failure.
+func (o *Orchestrator) writeStateFile(name string, data []byte) {
+	path := filepath.Join(o.StateDir, name)
+	err := os.MkdirAll(o.StateDir, 0755)
+	if err == nil {
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Failed to write %s: %v", path, err))
+	}
+}
+
+// mergeTasksEdits reconciles a tasks file edited since the session stopped
+// with the file as the loop left it, so the resume hash check passes. The
+// user's tasks and edits are kept along with the boxes the agent checked;
+// tasks both sides changed keep the user's version and are listed in
+// tasks-conflicts.md. The user's file is backed up to tasks-user.md first.
+// It does nothing when the file is unchanged or the snapshots are missing
+// or stale, leaving the hash check to reject the resume as before.
+func (o *Orchestrator) mergeTasksEdits(s *state.SessionState, tasksFile string) {
+	if s.TasksFileHash == "" {
+		return
+	}
+	user, err := os.ReadFile(tasksFile)
+	if err != nil || tasks.HashBytes(user) == s.TasksFileHash {
+		return
+	}
+	base, err := os.ReadFile(filepath.Join(o.StateDir, tasksBaseSnapshot))
+	if err != nil {
+		return
+	}
+	loop, err := os.ReadFile(filepath.Join(o.StateDir, tasksLoopSnapshot))
+	if err != nil || tasks.HashBytes(loop) != s.TasksFileHash {
+		return
+	}
+
+	logging.Phase("Tasks file edited since the session stopped, merging the edits")
+	merged, conflicts := tasks.Merge(base, loop, user)
+	o.writeStateFile(tasksUserBackup, user)
+	info, err := os.Stat(tasksFile)
+	if err != nil {
+		return
+	}
+	if err := os.WriteFile(tasksFile, merged, info.Mode().Perm()); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to write the merged tasks file: %v", err))
+		return
+	}
+	s.TasksFileHash = tasks.HashBytes(merged)
+	o.writeStateFile(tasksBaseSnapshot, merged)
+	o.writeStateFile(tasksLoopSnapshot, merged)
+
+	conflictsPath := filepath.Join(o.StateDir, tasksConflicts)
+	if len(conflicts) == 0 {
+		os.Remove(conflictsPath)
+		logging.Success(fmt.Sprintf("Merged your edits to %s with the loop's", tasksFile))
+		return
+	}
+	var report strings.Builder
+	for _, c := range conflicts {
+		report.WriteString(c.String() + "\n")
+	}
+	o.writeStateFile(tasksConflicts, []byte(report.String()))
+	logging.Warn(fmt.Sprintf("Merged your edits to %s with %d conflict(s); your version was kept for:", tasksFile, len(conflicts)))
+	for _, line := range strings.Split(strings.TrimRight(report.String(), "\n"), "\n") {
+		logging.Warn(line)
+	}
+	logging.Info(fmt.Sprintf("Conflicts written to %s, your original file to %s", conflictsPath, filepath.Join(o.StateDir, tasksUserBackup)))
+}
@@ -0,0 +1,79 @@
+package phases
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// interruptedSession returns a resuming orchestrator whose session stopped
+// after the agent checked T001, with the tasks file as the loop left it.
+func interruptedSession(t *testing.T) (*Orchestrator, string) {
+	t.Helper()
+	dir := t.TempDir()
+	tasksFile := filepath.Join(dir, "tasks.md")
+	stateDir := filepath.Join(dir, ".ralph-loop")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] T001 Parse input\n- [ ] T002 Write output\n"), 0644))
+
+	o := NewOrchestrator(config.NewDefaultConfig())
+	o.StateDir = stateDir
+	o.session = &state.SessionState{SessionID: "s1", Iteration: 1, Status: state.StatusInterrupted, Phase: state.PhaseImplementation, TasksFile: tasksFile}
+	require.NoError(t, o.snapshotTasks(true))
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [x] T001 Parse input\n- [ ] T002 Write output\n"), 0644))
+	require.NoError(t, o.snapshotTasks(false))
+	require.NoError(t, state.SaveState(o.session, stateDir))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.Resume = true
+	resumed := NewOrchestrator(cfg)
+	resumed.StateDir = stateDir
+	return resumed, tasksFile
+}
+
+func TestPhaseResumeCheck_MergesUserEdits(t *testing.T) {
+	o, tasksFile := interruptedSession(t)
+	// The user saved an editor buffer opened before the agent's check.
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] T001 Parse input\n- [ ] T002 Write output\n- [ ] T003 Add docs\n"), 0644))
+
+	assert.Equal(t, -1, o.phaseResumeCheck())
+	data, err := os.ReadFile(tasksFile)
+	require.NoError(t, err)
+	assert.Equal(t, "- [x] T001 Parse input\n- [ ] T002 Write output\n- [ ] T003 Add docs\n", string(data))
+	hash, err := tasks.HashFile(tasksFile)
+	require.NoError(t, err)
+	assert.Equal(t, hash, o.session.TasksFileHash)
+	assert.FileExists(t, filepath.Join(o.StateDir, tasksUserBackup))
+	assert.NoFileExists(t, filepath.Join(o.StateDir, tasksConflicts))
+}
+
+func TestPhaseResumeCheck_ReportsConflicts(t *testing.T) {
+	o, tasksFile := interruptedSession(t)
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] T002 Write output\n"), 0644))
+
+	assert.Equal(t, -1, o.phaseResumeCheck())
+	data, err := os.ReadFile(tasksFile)
+	require.NoError(t, err)
+	assert.Equal(t, "- [ ] T002 Write output\n", string(data), "the user's removal wins")
+	report, err := os.ReadFile(filepath.Join(o.StateDir, tasksConflicts))
+	require.NoError(t, err)
+	assert.Equal(t, "T001: removed by you after the agent changed it; left out\n  agent: - [x] T001 Parse input\n", string(report))
+}
+
+func TestPhaseResumeCheck_NoSnapshotStillRejects(t *testing.T) {
+	o, tasksFile := interruptedSession(t)
+	require.NoError(t, os.Remove(filepath.Join(o.StateDir, tasksBaseSnapshot)))
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] T009 Other\n"), 0644))
+
+	assert.NotEqual(t, -1, o.phaseResumeCheck())
+	data, err := os.ReadFile(tasksFile)
+	require.NoError(t, err)
+	assert.Equal(t, "- [ ] T009 Other\n", string(data))
+}
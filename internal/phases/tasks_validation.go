@@ -10,6 +10,7 @@ import (
 	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
 	"github.com/CodexForgeBR/cli-tools/internal/parser"
 	"github.com/CodexForgeBR/cli-tools/internal/prompt"
+	"github.com/CodexForgeBR/cli-tools/internal/schema"
 )
 
 // TasksValidationConfig configures the tasks validation phase.
@@ -54,7 +55,15 @@ func RunTasksValidation(ctx context.Context, cfg TasksValidationConfig) TasksVal
 		}
 	}
 
-	// Parse tasks validation result
+	// Parse tasks validation result, once more formatted if need be
+	outputPath, schemaErrs := conformOutput(ctx, cfg.Runner, "RALPH_TASKS_VALIDATION", outputPath, schema.MustLookup("RALPH_TASKS_VALIDATION"))
+	if len(schemaErrs) > 0 {
+		return TasksValidationResult{
+			Action:   "exit",
+			ExitCode: exitcode.Error,
+			Feedback: formatFeedback("RALPH_TASKS_VALIDATION", schemaErrs),
+		}
+	}
 	output, err := os.ReadFile(outputPath)
 	if err != nil {
 		return TasksValidationResult{
@@ -130,7 +130,7 @@ func TestRunTasksValidation_UnknownVerdict(t *testing.T) {
 
 	assert.Equal(t, "exit", result.Action)
 	assert.Equal(t, exitcode.Error, result.ExitCode)
-	assert.Contains(t, result.Feedback, "unknown tasks validation verdict")
+	assert.Contains(t, result.Feedback, `RALPH_TASKS_VALIDATION.verdict: "UNKNOWN" is not one of VALID, INVALID`)
 }
 
 // TestRunTasksValidation_NoVerdictFound tests handling when no verdict is parsed.
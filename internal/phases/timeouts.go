@@ -0,0 +1,98 @@
+package phases
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// withTimeout bounds ctx to secs seconds. A non-positive limit only adds
+// a cancel func so callers can treat both cases alike.
+func withTimeout(ctx context.Context, secs int) (context.Context, context.CancelFunc) {
+	if secs <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(secs)*time.Second)
+}
+
+// lastFeedback returns the decoded feedback for the next implementation
+// prompt. Feedback saved by older versions was stored as plain text.
+func (o *Orchestrator) lastFeedback() string {
+	if o.session.LastFeedback == "" {
+		return ""
+	}
+	decoded, err := base64.StdEncoding.DecodeString(o.session.LastFeedback)
+	if err != nil {
+		return o.session.LastFeedback
+	}
+	return string(decoded)
+}
+
+// phaseTimedOut reports whether phaseCtx was cut off by ITERATION_TIMEOUT
+// or the phase's own timeout. If so the iteration is recorded with a
+// TIMEOUT verdict and the next implementation prompt explains what
+// happened, keeping the previous feedback below the note.
+func (o *Orchestrator) phaseTimedOut(phase string, iterCtx, phaseCtx context.Context) bool {
+	if !errors.Is(phaseCtx.Err(), context.DeadlineExceeded) {
+		return false
+	}
+	var limit time.Duration
+	var setting string
+	switch {
+	case iterCtx.Err() != nil:
+		limit, setting = time.Duration(o.Config.IterationTimeout)*time.Second, "ITERATION_TIMEOUT"
+	case phase == state.PhaseImplementation:
+		limit, setting = time.Duration(o.Config.ImplTimeout)*time.Second, "IMPL_TIMEOUT"
+	default:
+		limit, setting = time.Duration(o.Config.ValTimeout)*time.Second, "VAL_TIMEOUT"
+	}
+	logging.Warn(fmt.Sprintf("%s phase timed out after %s (%s)", phase, limit, setting))
+
+	feedback := timeoutFeedback(phase, limit, setting)
+	if prev := validatorFeedback(o.lastFeedback()); prev != "" {
+		feedback += prevFeedbackHeader + prev
+	}
+	o.session.Verdict = state.VerdictTimeout
+	o.session.Confidence = nil
+	o.recordGate(phase, state.VerdictTimeout)
+	o.Dashboard.SetVerdict(state.VerdictTimeout, feedback)
+	o.session.LastFeedback = base64.StdEncoding.EncodeToString([]byte(feedback))
+	o.session.Checkpoint = nil
+	if err := state.SaveState(o.session, o.StateDir); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save timeout state: %v", err))
+	}
+	return true
+}
+
+// prevFeedbackHeader separates a timeout note from the validator feedback
+// carried over from an earlier iteration.
+const prevFeedbackHeader = "\n\nFeedback from the last validation:\n"
+
+// validatorFeedback strips an earlier timeout note from feedback so that
+// consecutive timeouts do not stack their notes.
+func validatorFeedback(feedback string) string {
+	if !strings.HasPrefix(feedback, "TIMEOUT:") {
+		return feedback
+	}
+	if _, prev, ok := strings.Cut(feedback, prevFeedbackHeader); ok {
+		return prev
+	}
+	return ""
+}
+
+// timeoutFeedback is the note handed to the implementer after a phase was
+// cut off.
+func timeoutFeedback(phase string, limit time.Duration, setting string) string {
+	if phase == state.PhaseImplementation {
+		return fmt.Sprintf("TIMEOUT: your previous run was stopped after %s (%s) before it finished. "+
+			"Check which of your changes were saved, then work in smaller steps and mark each task done as soon as it is complete.", limit, setting)
+	}
+	return fmt.Sprintf("TIMEOUT: validation of your previous run was stopped after %s (%s) without a verdict. "+
+		"Keep your changes focused and make them easy to verify.", limit, setting)
+}
@@ -0,0 +1,121 @@
+package phases
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hangOnce blocks its first call until the context ends, then behaves
+// like then.
+func hangOnce(runner *MockOrchestratorAIRunner, then func(ctx context.Context, prompt string, outputPath string) error) {
+	runner.RunFunc = func(ctx context.Context, prompt string, outputPath string) error {
+		if runner.CallCount == 1 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return then(ctx, prompt, outputPath)
+	}
+}
+
+// TestOrchestrator_ImplTimeoutMovesToNextIteration verifies that a hung
+// implementation is cut off, recorded as TIMEOUT and retried with the
+// timeout explained in the next prompt.
+func TestOrchestrator_ImplTimeoutMovesToNextIteration(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 2
+	cfg.ImplTimeout = 1
+
+	implRunner := &MockOrchestratorAIRunner{}
+	hangOnce(implRunner, func(ctx context.Context, prompt string, outputPath string) error {
+		return os.WriteFile(outputPath, []byte("Done."), 0644)
+	})
+	valRunner := completeValidator(cfg)
+	o := newTestOrchestrator(t, cfg, tmpDir, implRunner, valRunner)
+
+	assert.Equal(t, exitcode.Success, o.Run(context.Background()))
+	assert.Equal(t, 2, implRunner.CallCount)
+	assert.Equal(t, 1, valRunner.CallCount, "the timed out iteration is not validated")
+	require.Len(t, implRunner.PromptLog, 2)
+	assert.Contains(t, implRunner.PromptLog[1], "TIMEOUT: your previous run was stopped after 1s (IMPL_TIMEOUT)")
+}
+
+// TestOrchestrator_ValTimeoutRecordsVerdict verifies that a hung validator
+// is cut off and the TIMEOUT verdict is saved.
+func TestOrchestrator_ValTimeoutRecordsVerdict(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	cfg.ValTimeout = 1
+
+	valRunner := &MockOrchestratorAIRunner{}
+	hangOnce(valRunner, nil)
+	o := newTestOrchestrator(t, cfg, tmpDir, nil, valRunner)
+
+	assert.Equal(t, exitcode.MaxIterations, o.Run(context.Background()))
+
+	saved, err := state.LoadState(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, state.VerdictTimeout, saved.Verdict)
+	feedback, err := base64.StdEncoding.DecodeString(saved.LastFeedback)
+	require.NoError(t, err)
+	assert.Contains(t, string(feedback), "validation of your previous run was stopped after 1s (VAL_TIMEOUT)")
+}
+
+// TestOrchestrator_IterationTimeoutCoversBothPhases verifies that the
+// iteration limit is named when it, not a phase limit, fired, and that the
+// validator's last feedback is carried over.
+func TestOrchestrator_IterationTimeoutCoversBothPhases(t *testing.T) {
+	o := &Orchestrator{Config: &config.Config{IterationTimeout: 60, ValTimeout: 600}, StateDir: t.TempDir()}
+	o.session = &state.SessionState{LastFeedback: base64.StdEncoding.EncodeToString([]byte("Fix the login test"))}
+	iterCtx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	assert.True(t, o.phaseTimedOut(state.PhaseValidation, iterCtx, iterCtx))
+	feedback, err := base64.StdEncoding.DecodeString(o.session.LastFeedback)
+	require.NoError(t, err)
+	assert.Contains(t, string(feedback), "after 1m0s (ITERATION_TIMEOUT)")
+	assert.Contains(t, string(feedback), prevFeedbackHeader+"Fix the login test")
+	assert.Equal(t, state.VerdictTimeout, o.session.Verdict)
+}
+
+// TestPhaseTimedOut_IgnoresOtherErrors verifies that cancelled or live
+// contexts are not reported as timeouts.
+func TestPhaseTimedOut_IgnoresOtherErrors(t *testing.T) {
+	o := &Orchestrator{Config: &config.Config{}, session: &state.SessionState{}, StateDir: t.TempDir()}
+	live := context.Background()
+	cancelled, cancel := context.WithCancel(live)
+	cancel()
+
+	assert.False(t, o.phaseTimedOut(state.PhaseImplementation, live, live))
+	assert.False(t, o.phaseTimedOut(state.PhaseImplementation, live, cancelled))
+	assert.Empty(t, o.session.Verdict)
+}
+
+func TestValidatorFeedback(t *testing.T) {
+	assert.Equal(t, "Fix the tests", validatorFeedback("Fix the tests"))
+	assert.Equal(t, "Fix the tests", validatorFeedback("TIMEOUT: stopped."+prevFeedbackHeader+"Fix the tests"))
+	assert.Empty(t, validatorFeedback("TIMEOUT: stopped."))
+}
+
+func TestWithTimeout(t *testing.T) {
+	ctx, cancel := withTimeout(context.Background(), 0)
+	defer cancel()
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+
+	ctx, cancel = withTimeout(context.Background(), 30)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(30*time.Second), deadline, time.Second)
+}
@@ -0,0 +1,88 @@
+package phases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/prompt"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// triageOutputFile is the triage runner's output in the iteration
+// directory.
+const triageOutputFile = "triage-output.txt"
+
+// runTriage asks TriageRunner, a cheap model, whether the iteration
+// obviously did not do the work it claims, given the tasks it checked off,
+// the files it changed and the commands it ran. It returns the feedback
+// to send back when triage fails the iteration, and "" when the full
+// validation should run: triage passed, is off, or could not decide. A
+// triage error never fails an iteration.
+func (o *Orchestrator) runTriage(ctx context.Context, implOutputPath, iterDir string) string {
+	if o.TriageRunner == nil {
+		return ""
+	}
+	logging.Phase(fmt.Sprintf("Triage phase - Iteration %d", o.session.Iteration))
+	logging.Info(fmt.Sprintf("AI CLI: %s", o.Config.TriageAI))
+	logging.Info(fmt.Sprintf("Model: %s", o.Config.TriageModel))
+	o.Dashboard.SetPhase(state.PhaseTriage)
+
+	triagePrompt := prompt.BuildTriagePrompt(implOutputPath, o.completedTaskLines(), o.iterationFiles(), commandLines(iterDir))
+	outputPath := filepath.Join(iterDir, triageOutputFile)
+	start := time.Now()
+	triageCtx, closeLog := withPhaseLog(ctx, iterDir, logTriage, o.logMaxBytes())
+	err := o.TriageRunner.Run(triageCtx, triagePrompt, outputPath)
+	closeLog()
+	o.Metrics.ObservePhase(state.PhaseTriage, time.Since(start))
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Triage failed, validating in full: %v", err))
+		return ""
+	}
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Triage left no output, validating in full: %v", err))
+		return ""
+	}
+	parsed, err := parser.ParseTriage(string(output))
+	if err != nil || parsed == nil {
+		logging.Warn("Triage gave no verdict, validating in full")
+		return ""
+	}
+	o.recordGate(state.PhaseTriage, parsed.Verdict)
+	if !strings.EqualFold(parsed.Verdict, "FAIL") {
+		logging.Success("Triage passed")
+		return ""
+	}
+	feedback := strings.TrimSpace(parsed.Feedback)
+	if feedback == "" {
+		feedback = "the implementation output claims work the iteration's changes and commands do not show"
+	}
+	logging.Warn(fmt.Sprintf("Triage failed the iteration, skipping validation: %s", feedback))
+	return "Triage: " + feedback
+}
+
+// completedTaskLines returns the tasks checked off since the iteration
+// started, as their text with the ID, or nil when that is unknown.
+func (o *Orchestrator) completedTaskLines() []string {
+	if o.checkedAtStart == nil {
+		return nil
+	}
+	list, err := tasks.ListTasks(o.session.TasksFile)
+	if err != nil {
+		return nil
+	}
+	lines := []string{}
+	for _, t := range list {
+		if t.Checked && !o.checkedAtStart[t.ID] {
+			lines = append(lines, t.Text)
+		}
+	}
+	return lines
+}
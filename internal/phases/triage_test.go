@@ -0,0 +1,74 @@
+package phases
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrchestrator_TriageFailSkipsValidation(t *testing.T) {
+	workDir := diffTestRepo(t)
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	valRunner := completeValidator(cfg)
+	o := newTestOrchestrator(t, cfg, filepath.Join(workDir, ".ralph-loop"), nil, valRunner)
+	o.WorkDir = workDir
+	triage := &MockOrchestratorAIRunner{RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+		return os.WriteFile(outputPath, []byte(`{"RALPH_TRIAGE": {"verdict": "FAIL", "feedback": "claims main() was added but no files changed"}}`), 0644)
+	}}
+	o.TriageRunner = triage
+
+	o.Run(context.Background())
+	assert.Equal(t, 1, triage.CallCount)
+	assert.Equal(t, 0, valRunner.CallCount, "validation is skipped")
+	require.NotEmpty(t, triage.PromptLog)
+	assert.Contains(t, triage.PromptLog[0], "(no files changed)")
+
+	require.Len(t, o.session.History, 1)
+	assert.Equal(t, "NEEDS_MORE_WORK", o.session.History[0].Verdict)
+	feedback, err := base64.StdEncoding.DecodeString(o.session.LastFeedback)
+	require.NoError(t, err)
+	assert.Equal(t, "Triage: claims main() was added but no files changed", string(feedback))
+	assert.FileExists(t, filepath.Join(o.StateDir, "iteration-001", "triage.log"))
+}
+
+func TestOrchestrator_TriagePassRunsValidation(t *testing.T) {
+	workDir := diffTestRepo(t)
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	valRunner := completeValidator(cfg)
+	o := newTestOrchestrator(t, cfg, filepath.Join(workDir, ".ralph-loop"), nil, valRunner)
+	o.WorkDir = workDir
+	triage := &MockOrchestratorAIRunner{RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+		return os.WriteFile(outputPath, []byte(`{"RALPH_TRIAGE": {"verdict": "PASS", "feedback": ""}}`), 0644)
+	}}
+	o.TriageRunner = triage
+
+	o.Run(context.Background())
+	assert.Equal(t, 1, triage.CallCount)
+	assert.Equal(t, 1, valRunner.CallCount)
+	require.Len(t, o.session.History, 1)
+	assert.Equal(t, "COMPLETE", o.session.History[0].Verdict)
+}
+
+func TestOrchestrator_TriageErrorRunsValidation(t *testing.T) {
+	workDir := diffTestRepo(t)
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 1
+	valRunner := completeValidator(cfg)
+	o := newTestOrchestrator(t, cfg, filepath.Join(workDir, ".ralph-loop"), nil, valRunner)
+	o.WorkDir = workDir
+	o.TriageRunner = &MockOrchestratorAIRunner{RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+		return errors.New("model unavailable")
+	}}
+
+	o.Run(context.Background())
+	assert.Equal(t, 1, valRunner.CallCount)
+}
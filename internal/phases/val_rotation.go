@@ -0,0 +1,39 @@
+package phases
+
+import (
+	"github.com/CodexForgeBR/cli-tools/internal/banner"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// rotatingValidator returns the ValRotation member judging the current
+// iteration: iteration N gets member (N-1) mod len, so a resumed session
+// keeps the order. It reports false when validation does not rotate.
+func (o *Orchestrator) rotatingValidator() (QuorumMember, bool) {
+	if len(o.ValRotation) == 0 {
+		return QuorumMember{}, false
+	}
+	i := (o.session.Iteration - 1) % len(o.ValRotation)
+	if i < 0 {
+		i = 0
+	}
+	return o.ValRotation[i], true
+}
+
+// recordValidator records in the session history that validator judged the
+// current iteration with verdict.
+func (o *Orchestrator) recordValidator(validator, verdict string) {
+	o.session.Validators = append(o.session.Validators, state.ValidatorRecord{
+		Iteration: o.session.Iteration,
+		Validator: validator,
+		Verdict:   verdict,
+	})
+}
+
+// validatorInfos converts the validator history for the status display.
+func validatorInfos(records []state.ValidatorRecord) []banner.ValidatorInfo {
+	infos := make([]banner.ValidatorInfo, 0, len(records))
+	for _, r := range records {
+		infos = append(infos, banner.ValidatorInfo{Iteration: r.Iteration, Validator: r.Validator, Verdict: r.Verdict})
+	}
+	return infos
+}
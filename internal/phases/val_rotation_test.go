@@ -0,0 +1,74 @@
+package phases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// TestOrchestrator_RotatesValidators verifies that each iteration is judged
+// by the next validator of the rotation and that the history records who
+// judged which iteration.
+func TestOrchestrator_RotatesValidators(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksFile := filepath.Join(tmpDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [ ] T001 Hard task\n"), 0644))
+
+	cfg := config.NewDefaultConfig()
+	cfg.TasksFile = tasksFile
+	cfg.MaxIterations = 3
+	cfg.MaxTaskAttempts = 0
+	cfg.CrossValidate = false
+	cfg.EnableLearnings = false
+
+	var judged []string
+	validator := func(label string) QuorumMember {
+		return QuorumMember{Label: label, Runner: &MockOrchestratorAIRunner{
+			RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+				judged = append(judged, label)
+				return os.WriteFile(outputPath, []byte(`{"RALPH_VALIDATION": {"verdict": "NEEDS_MORE_WORK", "feedback": "no"}}`), 0644)
+			},
+		}}
+	}
+
+	orch := NewOrchestrator(cfg)
+	orch.CommandChecker = alwaysAvailable
+	orch.StateDir = tmpDir
+	orch.ImplRunner = &modelRecordingRunner{model: "opus"}
+	orch.ValRunner = &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			t.Fatal("ValRunner must not run while validation rotates")
+			return nil
+		},
+	}
+	orch.ValRotation = []QuorumMember{validator("claude/sonnet"), validator("codex/gpt-5")}
+
+	assert.Equal(t, exitcode.MaxIterations, orch.Run(context.Background()))
+	assert.Equal(t, []string{"claude/sonnet", "codex/gpt-5", "claude/sonnet"}, judged)
+
+	saved, err := state.LoadState(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, []state.ValidatorRecord{
+		{Iteration: 1, Validator: "claude/sonnet", Verdict: "NEEDS_MORE_WORK"},
+		{Iteration: 2, Validator: "codex/gpt-5", Verdict: "NEEDS_MORE_WORK"},
+		{Iteration: 3, Validator: "claude/sonnet", Verdict: "NEEDS_MORE_WORK"},
+	}, saved.Validators)
+}
+
+// TestOrchestrator_NoRotationRecordsNothing verifies the validator history
+// stays empty without a rotation.
+func TestOrchestrator_NoRotationRecordsNothing(t *testing.T) {
+	orch := NewOrchestrator(config.NewDefaultConfig())
+	orch.session = &state.SessionState{Iteration: 4}
+	_, rotating := orch.rotatingValidator()
+	assert.False(t, rotating)
+	assert.Empty(t, validatorInfos(orch.session.Validators))
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/CodexForgeBR/cli-tools/internal/ai"
 	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/schema"
 )
 
 // ValidationConfig configures the validation phase.
@@ -13,13 +14,26 @@ type ValidationConfig struct {
 	Runner     ai.AIRunner
 	OutputPath string
 	Prompt     string
+	// CustomVerdicts are accepted in addition to the built-in verdicts.
+	CustomVerdicts []string
+	// FormatRetries is how many times the validator is asked to re-emit a
+	// RALPH_VALIDATION block that is missing, malformed or off-schema.
+	FormatRetries int
 }
 
 // ValidationPhaseResult contains the result of validation with parsed data.
 type ValidationPhaseResult struct {
-	Verdict      string
-	Feedback     string
-	BlockedTasks []string
+	Verdict         string
+	Feedback        string
+	BlockedTasks    []string
+	CompletedTasks  []string
+	IncompleteTasks []string
+	// InadmissiblePractices lists the policy violations the validator
+	// reported.
+	InadmissiblePractices []string
+	// Confidence is the validator's 0-1 confidence in the verdict, nil
+	// when it was not reported.
+	Confidence *float64
 }
 
 // RunValidationPhase executes the validation phase using the configured runner.
@@ -29,6 +43,10 @@ func RunValidationPhase(ctx context.Context, cfg ValidationConfig) error {
 }
 
 // RunValidationPhaseWithResult executes validation and parses the result.
+// A RALPH_VALIDATION block that is missing, cannot be parsed or does not
+// match its schema is requested again up to FormatRetries times; if it is
+// still unusable, the iteration is judged INADMISSIBLE with the problems as
+// feedback.
 func RunValidationPhaseWithResult(ctx context.Context, cfg ValidationConfig) (ValidationPhaseResult, error) {
 	// Run validation phase
 	err := RunValidationPhase(ctx, cfg)
@@ -36,8 +54,26 @@ func RunValidationPhaseWithResult(ctx context.Context, cfg ValidationConfig) (Va
 		return ValidationPhaseResult{}, err
 	}
 
+	s := schema.MustLookup("RALPH_VALIDATION").WithEnum("verdict", cfg.CustomVerdicts...)
+	outputPath, schemaErrs := outputCheck{
+		Runner:   cfg.Runner,
+		Block:    "RALPH_VALIDATION",
+		Schema:   s,
+		Attempts: cfg.FormatRetries,
+		Required: true,
+	}.conform(ctx, cfg.OutputPath)
+	if ctx.Err() != nil {
+		return ValidationPhaseResult{}, ctx.Err()
+	}
+	if len(schemaErrs) > 0 {
+		return ValidationPhaseResult{
+			Verdict:  "INADMISSIBLE",
+			Feedback: formatFeedback("RALPH_VALIDATION", schemaErrs),
+		}, nil
+	}
+
 	// Read and parse validation output
-	output, err := os.ReadFile(cfg.OutputPath)
+	output, err := os.ReadFile(outputPath)
 	if err != nil {
 		return ValidationPhaseResult{}, err
 	}
@@ -55,9 +91,13 @@ func RunValidationPhaseWithResult(ctx context.Context, cfg ValidationConfig) (Va
 
 	// Convert to result format
 	result := ValidationPhaseResult{
-		Verdict:      parsed.Verdict,
-		Feedback:     parsed.Feedback,
-		BlockedTasks: parsed.BlockedTasks,
+		Verdict:               parsed.Verdict,
+		Feedback:              parsed.Feedback,
+		BlockedTasks:          parsed.BlockedTasks,
+		CompletedTasks:        parsed.CompletedTasks,
+		IncompleteTasks:       parsed.IncompleteTasks,
+		InadmissiblePractices: parsed.InadmissiblePractices,
+		Confidence:            parsed.Confidence,
 	}
 
 	return result, nil
@@ -2,10 +2,81 @@ package phases
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
 )
 
+// Custom verdict actions.
+const (
+	VerdictActionContinue = "continue"
+	VerdictActionEscalate = "escalate"
+	VerdictActionExit     = "exit"
+)
+
+// CustomVerdict is a project-defined verdict from CUSTOM_VERDICTS and what
+// it does to the loop.
+type CustomVerdict struct {
+	Name        string
+	Action      string // VerdictActionContinue, VerdictActionEscalate or VerdictActionExit
+	ExitCode    int    // process exit code for VerdictActionExit
+	Description string // shown to the validator
+}
+
+// customVerdictNameRE matches verdict names: upper case words joined by
+// underscores, like the built-in verdicts.
+var customVerdictNameRE = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// ParseCustomVerdicts parses CUSTOM_VERDICTS: entries separated by ';' of
+// the form "NAME=ACTION[: description]", where ACTION is continue,
+// escalate or "exit-code N". Built-in verdicts cannot be redefined, and N
+// must not be one of ralph-loop's own exit codes (0-7).
+func ParseCustomVerdicts(spec string) (map[string]CustomVerdict, error) {
+	verdicts := map[string]CustomVerdict{}
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, rest, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q: expected NAME=ACTION", entry)
+		}
+		name = strings.TrimSpace(name)
+		if !customVerdictNameRE.MatchString(name) {
+			return nil, fmt.Errorf("%q: verdict names are upper case letters, digits and underscores", name)
+		}
+		if _, builtin := builtinVerdicts[name]; builtin {
+			return nil, fmt.Errorf("%s is a built-in verdict and cannot be redefined", name)
+		}
+		if _, dup := verdicts[name]; dup {
+			return nil, fmt.Errorf("%s is defined twice", name)
+		}
+		action, description, _ := strings.Cut(rest, ":")
+		v := CustomVerdict{Name: name, Description: strings.TrimSpace(description)}
+		fields := strings.Fields(strings.ToLower(action))
+		switch {
+		case len(fields) == 1 && fields[0] == VerdictActionContinue:
+			v.Action = VerdictActionContinue
+		case len(fields) == 1 && fields[0] == VerdictActionEscalate:
+			v.Action = VerdictActionEscalate
+		case len(fields) == 2 && fields[0] == "exit-code":
+			code, err := strconv.Atoi(fields[1])
+			if err != nil || code <= exitcode.Budget || code > 125 {
+				return nil, fmt.Errorf("%s: exit code must be %d-125, got %q", name, exitcode.Budget+1, fields[1])
+			}
+			v.Action, v.ExitCode = VerdictActionExit, code
+		default:
+			return nil, fmt.Errorf("%s: unknown action %q (want continue, escalate or exit-code N)", name, strings.TrimSpace(action))
+		}
+		verdicts[name] = v
+	}
+	return verdicts, nil
+}
+
 // VerdictInput contains the data needed to process a validation verdict.
 type VerdictInput struct {
 	Verdict           string
@@ -15,6 +86,8 @@ type VerdictInput struct {
 	BlockedTasks      []string
 	InadmissibleCount int
 	MaxInadmissible   int
+	// Custom holds the project's CUSTOM_VERDICTS by name.
+	Custom map[string]CustomVerdict
 }
 
 // VerdictResult contains the outcome of verdict processing.
@@ -25,39 +98,81 @@ type VerdictResult struct {
 	NewInadmissibleCount int
 }
 
-// ProcessVerdict handles all 5 primary verdicts with override logic.
+// builtinVerdicts maps the 5 primary verdicts to their handlers.
+var builtinVerdicts = map[string]func(VerdictInput) VerdictResult{
+	"COMPLETE":        processComplete,
+	"NEEDS_MORE_WORK": processNeedsMoreWork,
+	"ESCALATE":        processEscalate,
+	"INADMISSIBLE":    processInadmissible,
+	"BLOCKED":         processBlocked,
+}
+
+// ProcessVerdict handles the 5 primary verdicts with override logic and
+// the project's custom verdicts with their configured action. Unknown
+// verdicts exit with exitcode.Error.
 func ProcessVerdict(input VerdictInput) VerdictResult {
-	switch input.Verdict {
-	case "COMPLETE":
-		return processComplete(input)
-	case "NEEDS_MORE_WORK":
-		return VerdictResult{
-			Action:               "continue",
-			ExitCode:             0,
-			Feedback:             input.Feedback,
-			NewInadmissibleCount: input.InadmissibleCount,
-		}
-	case "ESCALATE":
+	if handle, ok := builtinVerdicts[input.Verdict]; ok {
+		return handle(input)
+	}
+	if custom, ok := input.Custom[input.Verdict]; ok {
+		return processCustom(custom, input)
+	}
+	return VerdictResult{
+		Action:               "exit",
+		ExitCode:             exitcode.Error,
+		Feedback:             "",
+		NewInadmissibleCount: input.InadmissibleCount,
+	}
+}
+
+func processNeedsMoreWork(input VerdictInput) VerdictResult {
+	return VerdictResult{
+		Action:               "continue",
+		ExitCode:             0,
+		Feedback:             input.Feedback,
+		NewInadmissibleCount: input.InadmissibleCount,
+	}
+}
+
+func processEscalate(input VerdictInput) VerdictResult {
+	return VerdictResult{
+		Action:               "exit",
+		ExitCode:             exitcode.Escalate,
+		Feedback:             "",
+		NewInadmissibleCount: input.InadmissibleCount,
+	}
+}
+
+// processCustom applies a custom verdict's action. Exits carry the verdict
+// name and feedback so the reason can be shown.
+func processCustom(custom CustomVerdict, input VerdictInput) VerdictResult {
+	switch custom.Action {
+	case VerdictActionContinue:
+		return processNeedsMoreWork(input)
+	case VerdictActionEscalate:
 		return VerdictResult{
 			Action:               "exit",
 			ExitCode:             exitcode.Escalate,
-			Feedback:             "",
+			Feedback:             customFeedback(custom, input.Feedback),
 			NewInadmissibleCount: input.InadmissibleCount,
 		}
-	case "INADMISSIBLE":
-		return processInadmissible(input)
-	case "BLOCKED":
-		return processBlocked(input)
 	default:
 		return VerdictResult{
 			Action:               "exit",
-			ExitCode:             exitcode.Error,
-			Feedback:             "",
+			ExitCode:             custom.ExitCode,
+			Feedback:             customFeedback(custom, input.Feedback),
 			NewInadmissibleCount: input.InadmissibleCount,
 		}
 	}
 }
 
+func customFeedback(custom CustomVerdict, feedback string) string {
+	if feedback == "" {
+		return custom.Name
+	}
+	return custom.Name + ": " + feedback
+}
+
 func processComplete(input VerdictInput) VerdictResult {
 	// Override: if unchecked doable tasks remain, treat as NEEDS_MORE_WORK
 	doable := input.Remaining - input.BlockedCount
@@ -124,3 +239,27 @@ func processBlocked(input VerdictInput) VerdictResult {
 		NewInadmissibleCount: input.InadmissibleCount,
 	}
 }
+
+// customVerdictNames returns the names of the custom verdicts, sorted.
+func customVerdictNames(verdicts map[string]CustomVerdict) []string {
+	names := make([]string, 0, len(verdicts))
+	for name := range verdicts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// customVerdictLines lists the custom verdicts for the validation prompt,
+// sorted by name, as "NAME - description".
+func customVerdictLines(verdicts map[string]CustomVerdict) []string {
+	lines := make([]string, 0, len(verdicts))
+	for name, v := range verdicts {
+		if v.Description != "" {
+			name += " - " + v.Description
+		}
+		lines = append(lines, name)
+	}
+	sort.Strings(lines)
+	return lines
+}
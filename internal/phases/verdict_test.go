@@ -1,10 +1,14 @@
 package phases
 
 import (
+	"context"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/CodexForgeBR/cli-tools/internal/config"
 	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
 )
 
@@ -484,3 +488,102 @@ func TestProcessVerdict_FeedbackPreservation(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCustomVerdicts(t *testing.T) {
+	verdicts, err := ParseCustomVerdicts("SECURITY_REVIEW_NEEDED=exit-code 20: touches auth code; FLAKY=continue ;WONTFIX = Escalate;")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]CustomVerdict{
+		"SECURITY_REVIEW_NEEDED": {Name: "SECURITY_REVIEW_NEEDED", Action: VerdictActionExit, ExitCode: 20, Description: "touches auth code"},
+		"FLAKY":                  {Name: "FLAKY", Action: VerdictActionContinue},
+		"WONTFIX":                {Name: "WONTFIX", Action: VerdictActionEscalate},
+	}, verdicts)
+
+	empty, err := ParseCustomVerdicts("")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestParseCustomVerdicts_Errors(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"SECURITY", "expected NAME=ACTION"},
+		{"security=escalate", "upper case letters"},
+		{"COMPLETE=continue", "built-in verdict"},
+		{"A=continue;A=escalate", "defined twice"},
+		{"A=retry", "unknown action"},
+		{"A=exit-code", "unknown action"},
+		{"A=exit-code 3", "exit code must be 8-125"},
+		{"A=exit-code 200", "exit code must be 8-125"},
+		{"A=exit-code x", "exit code must be 8-125"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := ParseCustomVerdicts(tt.spec)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestProcessVerdict_CustomVerdicts(t *testing.T) {
+	custom, err := ParseCustomVerdicts("FLAKY=continue;WONTFIX=escalate;SECURITY_REVIEW_NEEDED=exit-code 20")
+	require.NoError(t, err)
+	input := func(verdict string) VerdictInput {
+		return VerdictInput{Verdict: verdict, Feedback: "see T002", InadmissibleCount: 1, MaxInadmissible: 5, Custom: custom}
+	}
+
+	result := ProcessVerdict(input("FLAKY"))
+	assert.Equal(t, VerdictResult{Action: "continue", Feedback: "see T002", NewInadmissibleCount: 1}, result)
+
+	result = ProcessVerdict(input("WONTFIX"))
+	assert.Equal(t, VerdictResult{Action: "exit", ExitCode: exitcode.Escalate, Feedback: "WONTFIX: see T002", NewInadmissibleCount: 1}, result)
+
+	result = ProcessVerdict(input("SECURITY_REVIEW_NEEDED"))
+	assert.Equal(t, VerdictResult{Action: "exit", ExitCode: 20, Feedback: "SECURITY_REVIEW_NEEDED: see T002", NewInadmissibleCount: 1}, result)
+
+	result = ProcessVerdict(input("SOMETHING_ELSE"))
+	assert.Equal(t, exitcode.Error, result.ExitCode, "undefined verdicts are still errors")
+}
+
+func TestCustomVerdictLines(t *testing.T) {
+	custom, err := ParseCustomVerdicts("WONTFIX=escalate;SECURITY_REVIEW_NEEDED=exit-code 20: touches auth code")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SECURITY_REVIEW_NEEDED - touches auth code", "WONTFIX"}, customVerdictLines(custom))
+}
+
+// TestOrchestrator_CustomVerdictExitCode verifies that a custom verdict is
+// offered to the validator and exits with its configured code.
+func TestOrchestrator_CustomVerdictExitCode(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cfg.CustomVerdicts = "SECURITY_REVIEW_NEEDED=exit-code 20: the change touches authentication"
+
+	impl := &MockOrchestratorAIRunner{}
+	val := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("SECURITY_REVIEW_NEEDED", "login handler changed")), 0644)
+		},
+	}
+	o := newTestOrchestrator(t, cfg, tmpDir, impl, val)
+
+	assert.Equal(t, 20, o.Run(context.Background()))
+	require.Len(t, val.PromptLog, 1)
+	assert.Contains(t, val.PromptLog[0], "- SECURITY_REVIEW_NEEDED - the change touches authentication")
+	assert.Equal(t, 1, impl.CallCount)
+}
+
+// TestOrchestrator_InvalidCustomVerdicts verifies that a bad
+// CUSTOM_VERDICTS stops the run before any iteration.
+func TestOrchestrator_InvalidCustomVerdicts(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cfg.CustomVerdicts = "COMPLETE=continue"
+
+	impl := &MockOrchestratorAIRunner{}
+	o := newTestOrchestrator(t, cfg, tmpDir, impl, &MockOrchestratorAIRunner{})
+
+	assert.Equal(t, exitcode.Error, o.Run(context.Background()))
+	assert.Zero(t, impl.CallCount)
+}
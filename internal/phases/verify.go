@@ -0,0 +1,251 @@
+package phases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/hooks"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/prompt"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/testresult"
+)
+
+// verifyOutputFile holds, in the iteration directory, what the
+// VERIFY_CMDS printed.
+const verifyOutputFile = "verify-output.txt"
+
+// maxFailingTestsShown caps the failing tests listed in the validation
+// prompt and the feedback.
+const maxFailingTestsShown = 20
+
+// verifyRun is what the VERIFY_CMDS reported for the current iteration.
+type verifyRun struct {
+	// commands are the commands with how each ended.
+	commands []string
+	// tests are the results found in their output and reports; nil when
+	// none were recognized.
+	tests *testresult.Summary
+	// flaky are the failing tests suspected flaky, whose failures do not
+	// count against the iteration.
+	flaky []string
+}
+
+// gating returns the failing tests that count against the iteration: all
+// but the suspected flaky ones.
+func (r *verifyRun) gating() []string {
+	return excludeTests(r.tests.Failing, r.flaky)
+}
+
+// excludeTests returns tests without those in exclude.
+func excludeTests(tests, exclude []string) []string {
+	if len(exclude) == 0 {
+		return tests
+	}
+	skip := make(map[string]bool, len(exclude))
+	for _, name := range exclude {
+		skip[name] = true
+	}
+	var kept []string
+	for _, name := range tests {
+		if !skip[name] {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
+
+// runVerifyCommands runs the VERIFY_CMDS in the project root, one after
+// the other, saving what they print in the iteration directory, and
+// collects the test results in their output and in the VERIFY_REPORTS
+// files they wrote. It returns nil when no command is configured.
+func (o *Orchestrator) runVerifyCommands(ctx context.Context, iterDir string) *verifyRun {
+	var commands []string
+	for _, c := range strings.Split(o.Config.VerifyCmds, ";") {
+		if c = strings.TrimSpace(c); c != "" {
+			commands = append(commands, c)
+		}
+	}
+	if len(commands) == 0 {
+		return nil
+	}
+	logging.Phase(fmt.Sprintf("Verify commands - Iteration %d", o.session.Iteration))
+	ctx, cancel := withTimeout(ctx, o.Config.ValTimeout)
+	defer cancel()
+
+	run := &verifyRun{}
+	var tests testresult.Summary
+	found := false
+	var saved bytes.Buffer
+	start := time.Now()
+	for _, command := range commands {
+		cmd := hooks.ShellCommand(ctx, command)
+		cmd.Dir = o.workDir()
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		cmd.WaitDelay = 5 * time.Second
+		err := cmd.Run()
+
+		status := "exit code 0"
+		var exitErr *exec.ExitError
+		switch {
+		case ctx.Err() != nil:
+			status = "timed out"
+		case errors.As(err, &exitErr):
+			status = fmt.Sprintf("exit code %d", exitErr.ExitCode())
+		case err != nil:
+			status = err.Error()
+		}
+		logging.Info(fmt.Sprintf("%s: %s", command, status))
+		run.commands = append(run.commands, fmt.Sprintf("`%s`: %s", command, status))
+		fmt.Fprintf(&saved, "$ %s\n%s%s(%s)\n\n", command, stdout.String(), stderr.String(), status)
+
+		if s, ok := testresult.Parse(stdout.Bytes()); ok {
+			tests.Add(s)
+			found = true
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if err := os.WriteFile(filepath.Join(iterDir, verifyOutputFile), saved.Bytes(), 0644); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save the verify commands' output: %v", err))
+	}
+
+	for _, path := range o.verifyReports(start) {
+		s, err := testresult.ReadFile(path)
+		if err != nil {
+			logging.Warn(fmt.Sprintf("Ignoring test report: %v", err))
+			continue
+		}
+		tests.Add(s)
+		found = true
+	}
+	if found {
+		run.tests = &tests
+		logging.Info(fmt.Sprintf("Tests: %s", tests))
+		run.flaky = o.trackFlakyTests(tests)
+	} else {
+		logging.Warn("No test results recognized in the verify commands' output or reports")
+	}
+	return run
+}
+
+// verifyReports returns the files matching VERIFY_REPORTS that were
+// written since, so reports left by earlier iterations are not read again.
+func (o *Orchestrator) verifyReports(since time.Time) []string {
+	var paths []string
+	for _, pattern := range strings.Split(o.Config.VerifyReports, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(o.workDir(), pattern))
+		if err != nil {
+			logging.Warn(fmt.Sprintf("Invalid VERIFY_REPORTS pattern %q: %v", pattern, err))
+			continue
+		}
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && !info.IsDir() && !info.ModTime().Before(since) {
+				paths = append(paths, m)
+			}
+		}
+	}
+	return paths
+}
+
+// trackFlakyTests records the outcomes of the iteration's tests in the
+// session and returns its failing tests that are suspected flaky.
+func (o *Orchestrator) trackFlakyTests(tests testresult.Summary) []string {
+	var nowFlaky []string
+	o.session.TestTracks, nowFlaky = testresult.UpdateTracks(o.session.TestTracks, tests, o.session.Iteration, o.iterationFiles())
+	for _, name := range nowFlaky {
+		logging.Warn(fmt.Sprintf("Test suspected flaky, its failures no longer count: %s", name))
+	}
+	flaky := testresult.Flaky(o.session.TestTracks)
+	var failing []string
+	for _, name := range tests.Failing {
+		if flaky[name] {
+			failing = append(failing, name)
+		}
+	}
+	return failing
+}
+
+// previousTests returns the last judged iteration that recorded test
+// results, or nil.
+func (o *Orchestrator) previousTests() *state.IterationRecord {
+	for i := len(o.session.History) - 1; i >= 0; i-- {
+		if o.session.History[i].Tests != nil {
+			return &o.session.History[i]
+		}
+	}
+	return nil
+}
+
+// testResultsSection returns the validation prompt section with what the
+// verify commands reported, compared with the previous iteration's
+// results. It is "" when no verify command ran.
+func (o *Orchestrator) testResultsSection(run *verifyRun) string {
+	if run == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Commands:\n- " + strings.Join(run.commands, "\n- ") + "\n")
+	if run.tests == nil {
+		b.WriteString("\nNo test results were recognized in their output.")
+		return prompt.BuildTestResultsSection(b.String())
+	}
+	fmt.Fprintf(&b, "\nTests: %s\n", run.tests)
+	if prev := o.previousTests(); prev != nil {
+		fmt.Fprintf(&b, "Iteration %d: %s\n", prev.Iteration, prev.Tests)
+		newlyFailing, fixed := testresult.Compare(*prev.Tests, *run.tests)
+		writeTestList(&b, fmt.Sprintf("Newly failing since iteration %d", prev.Iteration), excludeTests(newlyFailing, run.flaky))
+		writeTestList(&b, fmt.Sprintf("Fixed since iteration %d", prev.Iteration), fixed)
+	}
+	writeTestList(&b, "Failing tests", run.gating())
+	writeTestList(&b, "Suspected flaky tests, failing now but flipping between passing and failing in iterations that changed nothing related to them (their failures do not count)", run.flaky)
+	return prompt.BuildTestResultsSection(strings.TrimRight(b.String(), "\n"))
+}
+
+// noteFailingTests adds the failing tests to the feedback of an iteration
+// that is sent back, so the implementer knows what to fix. Suspected flaky
+// tests are listed apart, and alone add nothing.
+func noteFailingTests(result ValidationPhaseResult, run *verifyRun) ValidationPhaseResult {
+	if run == nil || run.tests == nil || len(run.gating()) == 0 || result.Verdict == "COMPLETE" {
+		return result
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Verify commands: %s", run.tests)
+	writeTestList(&b, "\nFailing tests", run.gating())
+	writeTestList(&b, "Suspected flaky, not counted", run.flaky)
+	note := strings.TrimRight(b.String(), "\n")
+	if result.Feedback != "" {
+		note = result.Feedback + "\n\n" + note
+	}
+	result.Feedback = note
+	return result
+}
+
+// writeTestList writes a titled list of at most maxFailingTestsShown
+// tests; nothing when tests is empty.
+func writeTestList(b *strings.Builder, title string, tests []string) {
+	if len(tests) == 0 {
+		return
+	}
+	shown := tests
+	if len(shown) > maxFailingTestsShown {
+		shown = shown[:maxFailingTestsShown]
+	}
+	fmt.Fprintf(b, "%s:\n- %s\n", title, strings.Join(shown, "\n- "))
+	if more := len(tests) - len(shown); more > 0 {
+		fmt.Fprintf(b, "(%d more not shown)\n", more)
+	}
+}
@@ -0,0 +1,174 @@
+package phases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/testresult"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOrchestrator_VerifyCommandResults verifies that the test results
+// the verify commands print are recorded per iteration, shown to the
+// validator compared with the previous iteration's, and sent back with
+// the feedback.
+func TestOrchestrator_VerifyCommandResults(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("verify commands run through sh")
+	}
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 2
+	cfg.VerifyCmds = "cat results.json; exit 1"
+	runs := []string{
+		`{"Action":"fail","Package":"p","Test":"TestA"}` + "\n" + `{"Action":"pass","Package":"p","Test":"TestB"}`,
+		`{"Action":"pass","Package":"p","Test":"TestA"}` + "\n" + `{"Action":"fail","Package":"p","Test":"TestB"}`,
+	}
+	impl := &MockOrchestratorAIRunner{}
+	impl.RunFunc = func(ctx context.Context, prompt string, outputPath string) error {
+		_ = os.WriteFile(filepath.Join(tmpDir, "results.json"), []byte(runs[impl.CallCount-1]), 0644)
+		return os.WriteFile(outputPath, []byte("Implemented."), 0644)
+	}
+	val := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("NEEDS_MORE_WORK", "keep going")), 0644)
+		},
+	}
+	o := newTestOrchestrator(t, cfg, tmpDir, impl, val)
+	o.WorkDir = tmpDir
+
+	o.Run(context.Background())
+	require.Len(t, val.PromptLog, 2)
+	assert.Contains(t, val.PromptLog[0], "TEST RESULTS")
+	assert.Contains(t, val.PromptLog[0], "`cat results.json`: exit code 0")
+	assert.Contains(t, val.PromptLog[0], "`exit 1`: exit code 1")
+	assert.Contains(t, val.PromptLog[0], "Tests: 1 passed, 1 failed, 0 skipped")
+	assert.NotContains(t, val.PromptLog[0], "Newly failing")
+
+	assert.Contains(t, val.PromptLog[1], "Iteration 1: 1 passed, 1 failed, 0 skipped")
+	assert.Contains(t, val.PromptLog[1], "Newly failing since iteration 1:\n- TestB (p)")
+	assert.Contains(t, val.PromptLog[1], "Fixed since iteration 1:\n- TestA (p)")
+
+	require.Len(t, impl.PromptLog, 2)
+	assert.Contains(t, impl.PromptLog[1], "Verify commands: 1 passed, 1 failed, 0 skipped\nFailing tests:\n- TestA (p)")
+
+	require.Len(t, o.session.History, 2)
+	assert.Equal(t, &testresult.Summary{Passed: 1, Failed: 1, Failing: []string{"TestA (p)"}, Passing: []string{"TestB (p)"}}, o.session.History[0].Tests)
+	assert.Equal(t, []string{"TestB (p)"}, o.session.History[1].Tests.Failing)
+
+	out, err := os.ReadFile(filepath.Join(tmpDir, "iteration-001", verifyOutputFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "$ cat results.json\n")
+}
+
+// TestOrchestrator_FlakyTestsDoNotGate verifies that a test flipping
+// between passing and failing in iterations that change nothing related
+// to it is reported apart and no longer sent back as a failure.
+func TestOrchestrator_FlakyTestsDoNotGate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("verify commands run through sh")
+	}
+	workDir := diffTestRepo(t)
+	stateDir := filepath.Join(workDir, ".ralph-loop")
+	require.NoError(t, os.MkdirAll(stateDir, 0755))
+	cfg := config.NewDefaultConfig()
+	cfg.MaxIterations = 4
+	cfg.VerifyCmds = "cat .ralph-loop/results.json"
+	fail := `{"Action":"fail","Package":"example.com/app/net","Test":"TestRetry"}`
+	pass := `{"Action":"pass","Package":"example.com/app/net","Test":"TestRetry"}`
+	runs := []string{fail, pass, fail, pass}
+	impl := &MockOrchestratorAIRunner{}
+	impl.RunFunc = func(ctx context.Context, prompt string, outputPath string) error {
+		_ = os.WriteFile(filepath.Join(stateDir, "results.json"), []byte(runs[impl.CallCount-1]), 0644)
+		_ = os.WriteFile(filepath.Join(workDir, "NOTES.md"), []byte(fmt.Sprintf("iteration %d\n", impl.CallCount)), 0644)
+		return os.WriteFile(outputPath, []byte("Implemented."), 0644)
+	}
+	val := &MockOrchestratorAIRunner{
+		RunFunc: func(ctx context.Context, prompt string, outputPath string) error {
+			return os.WriteFile(outputPath, []byte(makeOrchestratorValidationJSON("NEEDS_MORE_WORK", "keep going")), 0644)
+		},
+	}
+	o := newTestOrchestrator(t, cfg, stateDir, impl, val)
+	o.WorkDir = workDir
+
+	o.Run(context.Background())
+	require.Len(t, impl.PromptLog, 4)
+	assert.Contains(t, impl.PromptLog[1], "Failing tests:\n- TestRetry (example.com/app/net)")
+	require.Len(t, val.PromptLog, 4)
+	assert.Contains(t, val.PromptLog[2], "Suspected flaky tests")
+	assert.NotContains(t, val.PromptLog[2], "Failing tests:")
+	assert.Contains(t, impl.PromptLog[3], "VALIDATION CAUGHT YOUR LIES:\nkeep going\n\n", "a flaky failure alone is not sent back")
+
+	require.Len(t, o.session.TestTracks, 1)
+	assert.Equal(t, 3, o.session.TestTracks[0].FlakySince)
+	assert.Equal(t, 3, o.session.TestTracks[0].Flips)
+}
+
+func TestRunVerifyCommands_ReadsFreshReports(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("verify commands run through sh")
+	}
+	tmpDir := t.TempDir()
+	junit := `<testsuite><testcase classname="cart" name="adds"/><testcase classname="cart" name="pays"><failure/></testcase></testsuite>`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "source.xml"), []byte(junit), 0644))
+	stale := filepath.Join(tmpDir, "stale.xml")
+	require.NoError(t, os.WriteFile(stale, []byte(junit), 0644))
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(stale, old, old))
+
+	cfg := config.NewDefaultConfig()
+	cfg.VerifyCmds = "cp source.xml junit.xml"
+	cfg.VerifyReports = "junit.xml, stale.xml"
+	o := NewOrchestrator(cfg)
+	o.WorkDir = tmpDir
+	o.StateDir = tmpDir
+	o.session = &state.SessionState{Iteration: 1}
+
+	run := o.runVerifyCommands(context.Background(), tmpDir)
+	require.NotNil(t, run)
+	require.NotNil(t, run.tests)
+	assert.Equal(t, testresult.Summary{Passed: 1, Failed: 1, Failing: []string{"cart pays"}, Passing: []string{"cart adds"}}, *run.tests)
+}
+
+func TestRunVerifyCommands_NoResults(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("verify commands run through sh")
+	}
+	tmpDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	o := NewOrchestrator(cfg)
+	o.WorkDir = tmpDir
+	o.session = &state.SessionState{Iteration: 1}
+	assert.Nil(t, o.runVerifyCommands(context.Background(), tmpDir))
+	assert.Empty(t, o.testResultsSection(nil))
+
+	cfg.VerifyCmds = "echo ok"
+	run := o.runVerifyCommands(context.Background(), tmpDir)
+	require.NotNil(t, run)
+	assert.Nil(t, run.tests)
+	assert.Contains(t, o.testResultsSection(run), "No test results were recognized")
+}
+
+func TestNoteFailingTests(t *testing.T) {
+	failing := make([]string, maxFailingTestsShown+2)
+	for i := range failing {
+		failing[i] = fmt.Sprintf("Test%02d", i)
+	}
+	run := &verifyRun{tests: &testresult.Summary{Failed: len(failing), Failing: failing}}
+
+	got := noteFailingTests(ValidationPhaseResult{Verdict: "NEEDS_MORE_WORK", Feedback: "fix it"}, run)
+	assert.Contains(t, got.Feedback, "fix it\n\nVerify commands: 0 passed, 22 failed, 0 skipped\nFailing tests:\n- Test00")
+	assert.Contains(t, got.Feedback, "(2 more not shown)")
+
+	complete := noteFailingTests(ValidationPhaseResult{Verdict: "COMPLETE"}, run)
+	assert.Empty(t, complete.Feedback)
+	assert.Equal(t, "fix it", noteFailingTests(ValidationPhaseResult{Verdict: "NEEDS_MORE_WORK", Feedback: "fix it"}, nil).Feedback)
+}
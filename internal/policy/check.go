@@ -0,0 +1,81 @@
+package policy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Violation is an added line matching a policy's pattern.
+type Violation struct {
+	Policy string
+	Action string
+	File   string
+	Line   int // in the new version of File
+	Text   string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s:%d: %s", v.Policy, v.File, v.Line, v.Text)
+}
+
+// Check matches the patterns of policies against the lines a unified diff
+// (as from gitdiff.Diff) adds. Removed and context lines are ignored, so
+// only what the iteration wrote can violate a policy.
+func Check(policies []Policy, patch string) []Violation {
+	var checked []Policy
+	for _, p := range policies {
+		if p.Checked() {
+			checked = append(checked, p)
+		}
+	}
+	if len(checked) == 0 {
+		return nil
+	}
+
+	var violations []Violation
+	file, line := "", 0
+	for _, text := range strings.Split(patch, "\n") {
+		switch {
+		case strings.HasPrefix(text, "+++ "):
+			file = strings.TrimPrefix(strings.TrimPrefix(text, "+++ "), "b/")
+			if file == "/dev/null" {
+				file = ""
+			}
+		case strings.HasPrefix(text, "--- "), strings.HasPrefix(text, "diff "):
+		case strings.HasPrefix(text, "@@"):
+			line = hunkStart(text)
+		case strings.HasPrefix(text, "+"):
+			if file != "" {
+				added := text[1:]
+				for _, p := range checked {
+					if p.appliesTo(file) && p.re.MatchString(added) {
+						violations = append(violations, Violation{
+							Policy: p.Name,
+							Action: p.Action,
+							File:   file,
+							Line:   line,
+							Text:   strings.TrimSpace(added),
+						})
+					}
+				}
+			}
+			line++
+		case strings.HasPrefix(text, "-"):
+		default:
+			line++
+		}
+	}
+	return violations
+}
+
+// hunkStart returns the first new-file line of a "@@ -a,b +c,d @@" header.
+func hunkStart(header string) int {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0
+	}
+	start, _, _ := strings.Cut(fields[2][1:], ",")
+	n, _ := strconv.Atoi(start)
+	return n
+}
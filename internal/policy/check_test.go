@@ -0,0 +1,84 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const checkPatch = `diff --git a/src/sum.test.ts b/src/sum.test.ts
+new file mode 100644
+--- /dev/null
++++ b/src/sum.test.ts
+@@ -0,0 +1,3 @@
++test('sum', () => {
++  expect(true).toBe(true)
++})
+diff --git a/src/sum.ts b/src/sum.ts
+--- a/src/sum.ts
++++ b/src/sum.ts
+@@ -10,3 +10,4 @@ export function sum(a, b) {
+ // expect(true).toBe(true) in a comment of a non-test file
+-  return 0
++  return a + b
++  // expect(true).toBe(true)
+diff --git a/old_test.py b/old_test.py
+deleted file mode 100644
+--- a/old_test.py
++++ /dev/null
+@@ -1 +0,0 @@
+-assert True
+diff --git a/pkg/sum_test.go b/pkg/sum_test.go
+--- a/pkg/sum_test.go
++++ b/pkg/sum_test.go
+@@ -4,2 +4,3 @@ func TestSum(t *testing.T) {
+ 	got := Sum(1, 2)
+-	assert.Equal(t, 3, got)
++	assert.True(t, true)
+ }
+`
+
+func TestCheck_DefaultPolicies(t *testing.T) {
+	violations := Check(Default(), checkPatch)
+	assert.Equal(t, []Violation{
+		{Policy: "trivial-tests", Action: ActionInadmissible, File: "src/sum.test.ts", Line: 2, Text: "expect(true).toBe(true)"},
+		{Policy: "trivial-tests", Action: ActionInadmissible, File: "pkg/sum_test.go", Line: 5, Text: "assert.True(t, true)"},
+	}, violations, "only added lines of test files are checked")
+	assert.Equal(t, "trivial-tests: pkg/sum_test.go:5: assert.True(t, true)", violations[1].String())
+}
+
+func TestCheck_AllFilesWithoutGlobs(t *testing.T) {
+	path := writePolicyFile(t, "defaults: false\npolicies:\n  - name: no-return-zero\n    action: warn\n    pattern: 'expect\\(true\\)'\n")
+	policies, err := Load(path)
+	assert.NoError(t, err)
+
+	violations := Check(policies, checkPatch)
+	assert.Len(t, violations, 2)
+	assert.Equal(t, Violation{Policy: "no-return-zero", Action: ActionWarn, File: "src/sum.ts", Line: 12, Text: "// expect(true).toBe(true)"}, violations[1])
+}
+
+func TestCheck_NothingToCheck(t *testing.T) {
+	assert.Nil(t, Check(nil, checkPatch))
+	assert.Nil(t, Check(Default(), ""))
+}
+
+func TestTrivialTestsPattern(t *testing.T) {
+	trivial, _ := Find(Default(), "trivial-tests")
+	for _, line := range []string{
+		"expect(true).toBe(true)",
+		"  expect( false ).toEqual(false);",
+		"assert.True(t, true)",
+		"assertTrue(true);",
+		"    assert True",
+	} {
+		assert.True(t, trivial.re.MatchString(line), line)
+	}
+	for _, line := range []string{
+		"expect(isValid(x)).toBe(true)",
+		"assert.True(t, ok)",
+		"assertTrue(result.isEmpty());",
+		"assert True == result",
+	} {
+		assert.False(t, trivial.re.MatchString(line), line)
+	}
+}
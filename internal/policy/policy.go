@@ -0,0 +1,241 @@
+// Package policy defines the practices an implementation must not use.
+// Policies are rendered into the implementation and validation prompts;
+// those with a pattern are also checked against the lines an iteration
+// adds, so a violation is caught even when the validator misses it.
+package policy
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed defaults.yaml
+var defaultsYAML []byte
+
+// Policy actions: what a violation does to the iteration.
+const (
+	ActionInadmissible = "inadmissible" // the verdict becomes INADMISSIBLE
+	ActionEscalate     = "escalate"     // the loop stops for a human
+	ActionWarn         = "warn"         // reported, the verdict stands
+	ActionOff          = "off"          // disables a built-in policy
+)
+
+// Severities, lowest first.
+var severities = []string{"low", "medium", "high", "critical"}
+
+var nameRE = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Policy is one inadmissible practice.
+type Policy struct {
+	// Name identifies the policy in state, feedback and POLICY_FILE.
+	Name string `yaml:"name"`
+	// Title heads the policy in prompts; it defaults to the name.
+	Title    string `yaml:"title"`
+	Severity string `yaml:"severity"`
+	Action   string `yaml:"action"`
+	// Rules tell the implementer what not to do.
+	Rules string `yaml:"rules"`
+	// Detection tells the validator how to find a violation.
+	Detection string `yaml:"detection"`
+	// Pattern is a regular expression matched against each added line of
+	// the files matching Files (base name globs; all files when empty).
+	Pattern string   `yaml:"pattern"`
+	Files   []string `yaml:"files"`
+
+	re *regexp.Regexp
+}
+
+// policyFile is the layout of defaults.yaml and POLICY_FILE.
+type policyFile struct {
+	// Defaults is false to drop the built-in policies; otherwise the
+	// file's policies are merged into them by name.
+	Defaults *bool    `yaml:"defaults"`
+	Policies []Policy `yaml:"policies"`
+}
+
+// Default returns the built-in policies.
+func Default() []Policy {
+	policies, err := parse(defaultsYAML, nil)
+	if err != nil {
+		panic(fmt.Sprintf("policy: built-in policies: %v", err))
+	}
+	return policies
+}
+
+// Load returns the policies in effect for the POLICY_FILE path: the
+// built-in policies when path is "". A policy of the file replaces the
+// fields it sets of the built-in policy with the same name; "action: off"
+// disables it.
+func Load(path string) ([]Policy, error) {
+	if path == "" {
+		return Default(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := parse(data, Default())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return policies, nil
+}
+
+// parse decodes a policy file, merges it into base and validates the
+// result.
+func parse(data []byte, base []Policy) ([]Policy, error) {
+	var f policyFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil {
+		return nil, err
+	}
+	if f.Defaults != nil && !*f.Defaults {
+		base = nil
+	}
+
+	merged := append([]Policy(nil), base...)
+	index := map[string]int{}
+	for i, p := range merged {
+		index[p.Name] = i
+	}
+	seen := map[string]bool{}
+	for _, p := range f.Policies {
+		if seen[p.Name] {
+			return nil, fmt.Errorf("policy %q is defined twice", p.Name)
+		}
+		seen[p.Name] = true
+		if i, ok := index[p.Name]; ok {
+			merged[i] = override(merged[i], p)
+			continue
+		}
+		index[p.Name] = len(merged)
+		merged = append(merged, p)
+	}
+
+	var policies []Policy
+	for _, p := range merged {
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+		if p.Action != ActionOff {
+			policies = append(policies, p)
+		}
+	}
+	return policies, nil
+}
+
+// override returns base with the fields set in p.
+func override(base, p Policy) Policy {
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&base.Title, p.Title},
+		{&base.Severity, p.Severity},
+		{&base.Action, p.Action},
+		{&base.Rules, p.Rules},
+		{&base.Detection, p.Detection},
+		{&base.Pattern, p.Pattern},
+	} {
+		if f.src != "" {
+			*f.dst = f.src
+		}
+	}
+	if p.Files != nil {
+		base.Files = p.Files
+	}
+	return base
+}
+
+// validate fills in defaults and checks the fields.
+func (p *Policy) validate() error {
+	if !nameRE.MatchString(p.Name) {
+		return fmt.Errorf("policy name %q: use lower case letters, digits and dashes", p.Name)
+	}
+	if p.Title == "" {
+		p.Title = strings.ToUpper(strings.ReplaceAll(p.Name, "-", " "))
+	}
+	if p.Severity == "" {
+		p.Severity = "medium"
+	}
+	if !slices.Contains(severities, p.Severity) {
+		return fmt.Errorf("policy %s: severity %q is not one of %s", p.Name, p.Severity, strings.Join(severities, ", "))
+	}
+	if p.Action == "" {
+		p.Action = ActionInadmissible
+	}
+	actions := []string{ActionInadmissible, ActionEscalate, ActionWarn, ActionOff}
+	if !slices.Contains(actions, p.Action) {
+		return fmt.Errorf("policy %s: action %q is not one of %s", p.Name, p.Action, strings.Join(actions, ", "))
+	}
+	if p.Rules == "" && p.Detection == "" && p.Pattern == "" {
+		return fmt.Errorf("policy %s: set rules, detection or pattern", p.Name)
+	}
+	for _, glob := range p.Files {
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("policy %s: files %q: %w", p.Name, glob, err)
+		}
+	}
+	p.re = nil
+	if p.Pattern != "" {
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return fmt.Errorf("policy %s: pattern: %w", p.Name, err)
+		}
+		p.re = re
+	}
+	return nil
+}
+
+// Checked reports whether the policy has a pattern Check applies.
+func (p Policy) Checked() bool {
+	return p.re != nil
+}
+
+// appliesTo reports whether file is one the policy's pattern checks.
+func (p Policy) appliesTo(file string) bool {
+	if len(p.Files) == 0 {
+		return true
+	}
+	base := path.Base(file)
+	for _, glob := range p.Files {
+		if ok, _ := path.Match(glob, base); ok {
+			return true
+		}
+		if ok, _ := path.Match(glob, file); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the policy named name.
+func Find(policies []Policy, name string) (Policy, bool) {
+	for _, p := range policies {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Policy{}, false
+}
+
+// Match returns the name of the policy an inadmissible practice reported
+// by the validator refers to: practices are reported as "name: detail".
+// It is "" when the practice names no policy.
+func Match(policies []Policy, practice string) string {
+	name, _, _ := strings.Cut(practice, ":")
+	name = strings.ToLower(strings.TrimSpace(name))
+	if _, ok := Find(policies, name); ok {
+		return name
+	}
+	return ""
+}
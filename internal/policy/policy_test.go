@@ -0,0 +1,126 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func names(policies []Policy) []string {
+	var out []string
+	for _, p := range policies {
+		out = append(out, p.Name)
+	}
+	return out
+}
+
+func writePolicyFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policies.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestDefault(t *testing.T) {
+	policies := Default()
+	assert.Equal(t, []string{
+		"production-code-duplication",
+		"mock-subject",
+		"trivial-tests",
+		"tests-for-missing-functionality",
+	}, names(policies))
+	for _, p := range policies {
+		assert.Equal(t, ActionInadmissible, p.Action, p.Name)
+		assert.NotEmpty(t, p.Rules, p.Name)
+		assert.NotEmpty(t, p.Detection, p.Name)
+	}
+	trivial, ok := Find(policies, "trivial-tests")
+	require.True(t, ok)
+	assert.True(t, trivial.Checked())
+}
+
+func TestLoad_EmptyPathUsesDefaults(t *testing.T) {
+	policies, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, names(Default()), names(policies))
+}
+
+func TestLoad_MergesByName(t *testing.T) {
+	path := writePolicyFile(t, `
+policies:
+  - name: trivial-tests
+    action: warn
+  - name: mock-subject
+    action: "off"
+  - name: no-skipped-tests
+    severity: high
+    rules: |
+      - DO NOT skip tests with .skip or t.Skip
+    pattern: '\.skip\(|t\.Skip\('
+    files: ["*_test.go", "*.test.ts"]
+`)
+	policies, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"production-code-duplication", "trivial-tests", "tests-for-missing-functionality", "no-skipped-tests"}, names(policies))
+
+	trivial, _ := Find(policies, "trivial-tests")
+	assert.Equal(t, ActionWarn, trivial.Action)
+	assert.Equal(t, "TRIVIAL/EMPTY TESTS", trivial.Title, "unset fields keep the built-in value")
+	assert.True(t, trivial.Checked())
+
+	custom, _ := Find(policies, "no-skipped-tests")
+	assert.Equal(t, "NO SKIPPED TESTS", custom.Title)
+	assert.Equal(t, ActionInadmissible, custom.Action)
+	assert.Equal(t, "high", custom.Severity)
+}
+
+func TestLoad_WithoutDefaults(t *testing.T) {
+	path := writePolicyFile(t, `
+defaults: false
+policies:
+  - name: no-console-log
+    action: warn
+    pattern: 'console\.log\('
+`)
+	policies, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"no-console-log"}, names(policies))
+	assert.Equal(t, "medium", policies[0].Severity)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{"bad name", "policies:\n  - name: No Spaces\n    rules: x\n", "policy name"},
+		{"bad action", "policies:\n  - name: a\n    action: explode\n    rules: x\n", `action "explode"`},
+		{"bad severity", "policies:\n  - name: a\n    severity: huge\n    rules: x\n", `severity "huge"`},
+		{"empty", "policies:\n  - name: a\n", "set rules, detection or pattern"},
+		{"bad pattern", "policies:\n  - name: a\n    pattern: '('\n", "pattern"},
+		{"bad glob", "policies:\n  - name: a\n    rules: x\n    files: ['[']\n", "files"},
+		{"duplicate", "policies:\n  - name: a\n    rules: x\n  - name: a\n    rules: y\n", "defined twice"},
+		{"unknown field", "policies:\n  - name: a\n    rule: x\n", "rule"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writePolicyFile(t, tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+			assert.Contains(t, err.Error(), "policies.yaml")
+		})
+	}
+
+	_, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestMatch(t *testing.T) {
+	policies := Default()
+	assert.Equal(t, "mock-subject", Match(policies, "mock-subject: user_test.go mocks UserService"))
+	assert.Equal(t, "trivial-tests", Match(policies, " Trivial-Tests : a.spec.ts"))
+	assert.Equal(t, "", Match(policies, "tests mock the subject"))
+	assert.Equal(t, "", Match(policies, ""))
+}
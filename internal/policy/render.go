@@ -0,0 +1,79 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RulesText renders the policies for the implementation prompt, one
+// numbered entry each.
+func RulesText(policies []Policy) string {
+	var b strings.Builder
+	for i, p := range policies {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%d. %s:\n", i+1, p.Title)
+		body := p.Rules
+		if body == "" {
+			body = p.Detection
+		}
+		writeIndented(&b, body)
+		if p.Checked() {
+			writeIndented(&b, fmt.Sprintf("(ralph-loop also checks the lines you add to %s for this.)", p.fileList()))
+		}
+		switch p.Action {
+		case ActionWarn:
+			writeIndented(&b, "(Warning: a violation is reported but does not fail the iteration.)")
+		case ActionEscalate:
+			writeIndented(&b, "(A violation stops the loop for human review.)")
+		}
+	}
+	return b.String()
+}
+
+// DetectionText renders the policies for the validation prompt: how to
+// find each violation, what verdict it calls for and how to report it in
+// inadmissible_practices.
+func DetectionText(policies []Policy) string {
+	var b strings.Builder
+	for i, p := range policies {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%d. %s (%s severity):\n", i+1, p.Title, p.Severity)
+		body := p.Detection
+		if body == "" {
+			body = p.Rules
+		}
+		writeIndented(&b, body)
+		outcome := "INADMISSIBLE"
+		switch p.Action {
+		case ActionWarn:
+			outcome = "mention it in feedback; it does not change the verdict"
+		case ActionEscalate:
+			outcome = "ESCALATE"
+		}
+		writeIndented(&b, fmt.Sprintf("If found → %s. Report it as \"%s: <file and detail>\"", outcome, p.Name))
+	}
+	return b.String()
+}
+
+// fileList describes the files a pattern applies to.
+func (p Policy) fileList() string {
+	if len(p.Files) == 0 {
+		return "all files"
+	}
+	return strings.Join(p.Files, ", ")
+}
+
+// writeIndented writes text indented under a numbered entry.
+func writeIndented(b *strings.Builder, text string) {
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString("   " + line + "\n")
+	}
+}
@@ -0,0 +1,54 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulesText(t *testing.T) {
+	path := writePolicyFile(t, `
+policies:
+  - name: trivial-tests
+    action: warn
+  - name: no-secrets
+    action: escalate
+    severity: critical
+    detection: "- Check: are credentials committed?"
+`)
+	policies, err := Load(path)
+	require.NoError(t, err)
+
+	text := RulesText(policies)
+	assert.Contains(t, text, "1. PRODUCTION CODE DUPLICATION IN TESTS:\n   - DO NOT copy production logic into test files\n")
+	assert.Contains(t, text, "3. TRIVIAL/EMPTY TESTS:\n   - DO NOT write tests that don't invoke production code\n")
+	assert.Contains(t, text, "   (ralph-loop also checks the lines you add to *_test.*, *.test.*")
+	assert.Contains(t, text, "   (Warning: a violation is reported but does not fail the iteration.)\n")
+	assert.Contains(t, text, "5. NO SECRETS:\n   - Check: are credentials committed?\n   (A violation stops the loop for human review.)\n",
+		"the detection hint stands in for missing rules")
+}
+
+func TestDetectionText(t *testing.T) {
+	path := writePolicyFile(t, `
+policies:
+  - name: trivial-tests
+    action: warn
+  - name: no-secrets
+    action: escalate
+    rules: "- DO NOT commit credentials"
+`)
+	policies, err := Load(path)
+	require.NoError(t, err)
+
+	text := DetectionText(policies)
+	assert.Contains(t, text, "2. MOCK THE SUBJECT UNDER TEST (high severity):\n   - Check: Do tests mock the exact code being tested?\n   If found → INADMISSIBLE. Report it as \"mock-subject: <file and detail>\"\n")
+	assert.Contains(t, text, "If found → mention it in feedback; it does not change the verdict. Report it as \"trivial-tests: <file and detail>\"")
+	assert.Contains(t, text, "5. NO SECRETS (medium severity):\n   - DO NOT commit credentials\n   If found → ESCALATE.")
+	assert.NotContains(t, text, "(ralph-loop also checks", "pattern notes are for the implementer")
+}
+
+func TestRender_Empty(t *testing.T) {
+	assert.Empty(t, RulesText(nil))
+	assert.Empty(t, DetectionText(nil))
+}
@@ -1,18 +1,28 @@
 package prompt
 
-import "strings"
+import (
+	"strconv"
+	"strings"
+)
 
 // BuildImplFirstPrompt constructs the first implementation iteration prompt.
-// It includes inadmissible rules, evidence capture rules, playwright rules,
-// and optionally includes learnings from previous sessions.
-func BuildImplFirstPrompt(tasksFile string, learnings string) string {
+// It includes inadmissible rules listing policies (see policy.RulesText),
+// evidence capture rules, playwright rules, and optionally includes
+// learnings from previous sessions, excerpts of the code related to the
+// tasks (see BuildContextSection) and human guidance (see
+// BuildHumanGuidanceSection).
+func BuildImplFirstPrompt(tasksFile string, learnings string, context string, guidance string, policies string) string {
 	prompt := ImplFirstTemplate
 
 	// Replace task file reference
 	prompt = strings.ReplaceAll(prompt, "{{TASKS_FILE}}", tasksFile)
 
+	// Include decisions recorded with ralph-loop respond
+	prompt = strings.ReplaceAll(prompt, "{{HUMAN_GUIDANCE}}", guidance)
+
 	// Include inadmissible rules section
-	prompt = strings.ReplaceAll(prompt, "{{INADMISSIBLE_RULES}}", InadmissibleRules)
+	rules := strings.ReplaceAll(InadmissibleRules, "{{POLICIES}}", policies)
+	prompt = strings.ReplaceAll(prompt, "{{INADMISSIBLE_RULES}}", rules)
 
 	// Include evidence capture rules
 	prompt = strings.ReplaceAll(prompt, "{{EVIDENCE_RULES}}", EvidenceRules)
@@ -31,13 +41,16 @@ func BuildImplFirstPrompt(tasksFile string, learnings string) string {
 	// Include learnings output instructions
 	prompt = strings.ReplaceAll(prompt, "{{LEARNINGS_OUTPUT}}", LearningsOutput)
 
+	// Include related code excerpts
+	prompt = strings.ReplaceAll(prompt, "{{CONTEXT}}", context)
+
 	return prompt
 }
 
 // BuildImplContinuePrompt constructs the continuation implementation prompt.
 // This is used after validation finds issues that need to be fixed.
 // It includes the validator's feedback and reminds about evidence and playwright rules.
-func BuildImplContinuePrompt(tasksFile string, feedback string, learnings string) string {
+func BuildImplContinuePrompt(tasksFile string, feedback string, learnings string, context string, guidance string) string {
 	prompt := ImplContinueTemplate
 
 	// Replace task file reference
@@ -46,6 +59,9 @@ func BuildImplContinuePrompt(tasksFile string, feedback string, learnings string
 	// Include validation feedback
 	prompt = strings.ReplaceAll(prompt, "{{FEEDBACK}}", feedback)
 
+	// Include decisions recorded with ralph-loop respond
+	prompt = strings.ReplaceAll(prompt, "{{HUMAN_GUIDANCE}}", guidance)
+
 	// Include evidence capture rules
 	prompt = strings.ReplaceAll(prompt, "{{EVIDENCE_RULES}}", EvidenceRules)
 
@@ -63,14 +79,20 @@ func BuildImplContinuePrompt(tasksFile string, feedback string, learnings string
 	// Include learnings output instructions
 	prompt = strings.ReplaceAll(prompt, "{{LEARNINGS_OUTPUT}}", LearningsOutput)
 
+	// Include related code excerpts
+	prompt = strings.ReplaceAll(prompt, "{{CONTEXT}}", context)
+
 	return prompt
 }
 
 // BuildValidationPrompt constructs the validation phase prompt.
 // The validator checks the implementer's work against the tasks file.
-func BuildValidationPrompt(tasksFile string, implOutputFile string) string {
+func BuildValidationPrompt(tasksFile string, implOutputFile string, policyChecks string) string {
 	prompt := ValidationTemplate
 
+	// Include the inadmissible practice policies (see policy.DetectionText)
+	prompt = strings.ReplaceAll(prompt, "{{POLICY_CHECKS}}", policyChecks)
+
 	// Replace task file reference
 	prompt = strings.ReplaceAll(prompt, "{{TASKS_FILE}}", tasksFile)
 
@@ -128,3 +150,263 @@ func BuildFinalPlanPrompt(specFile string, tasksFile string, planFile string) st
 
 	return prompt
 }
+
+// BuildTriagePrompt constructs the triage prompt, which checks the claims
+// in implOutputFile against the tasks checked off, the files changed and
+// the commands run. A nil changed or commands list is reported as unknown.
+func BuildTriagePrompt(implOutputFile string, completed, changed, commands []string) string {
+	list := func(items []string, none, unknown string) string {
+		switch {
+		case items == nil:
+			return unknown
+		case len(items) == 0:
+			return none
+		}
+		return "- " + strings.Join(items, "\n- ")
+	}
+	prompt := strings.ReplaceAll(TriageTemplate, "{{IMPL_OUTPUT}}", implOutputFile)
+	prompt = strings.ReplaceAll(prompt, "{{COMPLETED}}", list(completed, "(none)", "(unknown)"))
+	prompt = strings.ReplaceAll(prompt, "{{CHANGES}}", list(changed, "(no files changed)", "(unknown: not a git repository)"))
+	return strings.ReplaceAll(prompt, "{{COMMANDS}}", list(commands, "(no shell commands were run)", "(unknown: the AI CLI records no transcript)"))
+}
+
+// BuildHumanGuidanceSection wraps the pending human decisions for the
+// {{HUMAN_GUIDANCE}} placeholder of the implementation prompts. It returns
+// "" when there are none.
+func BuildHumanGuidanceSection(messages []string) string {
+	if len(messages) == 0 {
+		return ""
+	}
+	list := "- " + strings.Join(messages, "\n- ")
+	return strings.ReplaceAll(HumanGuidanceSection, "{{GUIDANCE}}", list)
+}
+
+// BuildSkippedTasksSection renders the section appended to implementation and
+// validation prompts listing tasks that ralph-loop has marked blocked.
+// Returns "" when no tasks are skipped.
+func BuildSkippedTasksSection(skipped []string) string {
+	if len(skipped) == 0 {
+		return ""
+	}
+	list := "- " + strings.Join(skipped, "\n- ")
+	return strings.ReplaceAll(SkippedTasksSection, "{{SKIPPED_TASKS}}", list)
+}
+
+// BuildFeedbackHistorySection renders the section appended to continuation
+// prompts listing the unresolved feedback of earlier iterations. Returns ""
+// when there is none.
+func BuildFeedbackHistorySection(issues []string) string {
+	if len(issues) == 0 {
+		return ""
+	}
+	list := "- " + strings.Join(issues, "\n- ")
+	return strings.ReplaceAll(FeedbackHistorySection, "{{FEEDBACK_HISTORY}}", list)
+}
+
+// BuildReadyTasksSection renders the section appended to implementation
+// prompts listing the tasks whose dependencies are met. Returns "" when
+// ready is empty.
+func BuildReadyTasksSection(ready []string) string {
+	if len(ready) == 0 {
+		return ""
+	}
+	list := "- " + strings.Join(ready, "\n- ")
+	return strings.ReplaceAll(ReadyTasksSection, "{{READY_TASKS}}", list)
+}
+
+// BuildScreenshotDiffSection renders the section appended to validation
+// prompts describing how this iteration's screenshots compare with their
+// baselines. Returns "" when diffs is empty.
+func BuildScreenshotDiffSection(diffs []string) string {
+	if len(diffs) == 0 {
+		return ""
+	}
+	list := "- " + strings.Join(diffs, "\n- ")
+	return strings.ReplaceAll(ScreenshotDiffSection, "{{SCREENSHOT_DIFFS}}", list)
+}
+
+// BuildIterationDiffSection renders the section appended to validation
+// prompts with the git diff of the iteration and its --stat summary.
+// omitted is the number of bytes cut from the end of patch; an empty patch
+// tells the validator that nothing changed.
+func BuildIterationDiffSection(patch, stat string, omitted int) string {
+	if strings.TrimSpace(patch) == "" {
+		return IterationNoDiffSection
+	}
+	truncated := ""
+	if omitted > 0 {
+		truncated = "\n(diff truncated: " + strconv.Itoa(omitted) + " more bytes not shown; inspect the files listed above directly)\n"
+	}
+	section := strings.ReplaceAll(IterationDiffSection, "{{DIFF_STAT}}", stat)
+	section = strings.ReplaceAll(section, "{{DIFF_TRUNCATED}}", truncated)
+	return strings.ReplaceAll(section, "{{DIFF}}", strings.TrimRight(patch, "\n"))
+}
+
+// BuildFocusFilesSection renders the section appended to validation
+// prompts listing the files the iteration changed, one "path (+A -D)" line
+// each. Returns "" when files is empty.
+func BuildFocusFilesSection(files []string) string {
+	if len(files) == 0 {
+		return ""
+	}
+	return strings.ReplaceAll(FocusFilesSection, "{{FOCUS_FILES}}", "- "+strings.Join(files, "\n- "))
+}
+
+// BuildTestResultsSection renders the section appended to validation
+// prompts with what the verify commands reported. Returns "" when results
+// is empty.
+func BuildTestResultsSection(results string) string {
+	if results == "" {
+		return ""
+	}
+	return strings.ReplaceAll(TestResultsSection, "{{TEST_RESULTS}}", results)
+}
+
+// BuildProjectMemorySection renders the section appended to the first
+// implementation prompt with what earlier sessions of the project
+// escalated on, blocked and learned. Returns "" when memory is empty.
+func BuildProjectMemorySection(memory string) string {
+	if memory == "" {
+		return ""
+	}
+	return strings.ReplaceAll(ProjectMemorySection, "{{PROJECT_MEMORY}}", memory)
+}
+
+// BuildProtectedPathsSection renders the section appended to implementation
+// prompts listing the paths that must not be changed; escalate says whether
+// a change stops the loop rather than being reverted. Returns "" when
+// paths is empty.
+func BuildProtectedPathsSection(paths []string, escalate bool) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	consequence := "ralph-loop reverts any change to them\nafter the iteration and tells the validator."
+	if escalate {
+		consequence = "Any change to them stops the loop\nfor human review."
+	}
+	list := "- " + strings.Join(paths, "\n- ")
+	section := strings.ReplaceAll(ProtectedPathsSection, "{{CONSEQUENCE}}", consequence)
+	return strings.ReplaceAll(section, "{{PROTECTED_PATHS}}", list)
+}
+
+// BuildProtectedRevertedSection renders the section appended to validation
+// prompts listing the protected files whose changes were reverted. Returns
+// "" when there are none.
+func BuildProtectedRevertedSection(files []string) string {
+	if len(files) == 0 {
+		return ""
+	}
+	list := "- " + strings.Join(files, "\n- ")
+	return strings.ReplaceAll(ProtectedRevertedSection, "{{REVERTED_PATHS}}", list)
+}
+
+// BuildPolicyViolationsSection renders the section appended to validation
+// prompts listing the added lines that match a policy's pattern. Returns ""
+// when there are none.
+func BuildPolicyViolationsSection(violations []string) string {
+	if len(violations) == 0 {
+		return ""
+	}
+	list := "- " + strings.Join(violations, "\n- ")
+	return strings.ReplaceAll(PolicyViolationsSection, "{{VIOLATIONS}}", list)
+}
+
+// BuildCustomVerdictsSection renders the section appended to validation
+// prompts listing the project's custom verdicts, one "NAME - description"
+// entry each. Returns "" when there are none.
+func BuildCustomVerdictsSection(verdicts []string) string {
+	if len(verdicts) == 0 {
+		return ""
+	}
+	list := "- " + strings.Join(verdicts, "\n- ")
+	return strings.ReplaceAll(CustomVerdictsSection, "{{CUSTOM_VERDICTS}}", list)
+}
+
+// BuildCommandsRunSection renders the section appended to validation
+// prompts listing the shell commands the implementer ran, one
+// "exit N: command" entry each. Returns "" when commands is nil, meaning
+// the AI CLI records no transcript; an empty list says no command ran.
+func BuildCommandsRunSection(commands []string) string {
+	if commands == nil {
+		return ""
+	}
+	list := "(no shell commands were run)"
+	if len(commands) > 0 {
+		list = "- " + strings.Join(commands, "\n- ")
+	}
+	return strings.ReplaceAll(CommandsRunSection, "{{COMMANDS}}", list)
+}
+
+// BuildPatchModeSection renders the section appended to implementation
+// prompts in apply-patch mode, asking for a RALPH_PATCH diff instead of
+// direct file edits.
+func BuildPatchModeSection(tasksFile string) string {
+	return strings.ReplaceAll(PatchModeSection, "{{TASKS_FILE}}", tasksFile)
+}
+
+// BuildPlanFromIssuePrompt constructs the planning prompt for
+// --plan-from-issue, which turns the cached issue into RALPH_PLAN and
+// RALPH_TASKS documents.
+func BuildPlanFromIssuePrompt(issueFile string) string {
+	return strings.ReplaceAll(PlanFromIssueTemplate, "{{ISSUE_FILE}}", issueFile)
+}
+
+// BuildTasksRegeneratePrompt asks for the tasks file updated to a spec that
+// changed mid-run, given the changed spec lines and the tasks validation
+// feedback; the answer is a RALPH_TASKS document.
+func BuildTasksRegeneratePrompt(specFile, tasksFile, changes, feedback string) string {
+	if strings.TrimSpace(feedback) == "" {
+		feedback = "(no feedback given)"
+	}
+	prompt := strings.ReplaceAll(TasksRegenerateTemplate, "{{SPEC_FILE}}", specFile)
+	prompt = strings.ReplaceAll(prompt, "{{TASKS_FILE}}", tasksFile)
+	prompt = strings.ReplaceAll(prompt, "{{CHANGES}}", strings.TrimRight(changes, "\n"))
+	return strings.ReplaceAll(prompt, "{{FEEDBACK}}", strings.TrimSpace(feedback))
+}
+
+// BuildContextSection wraps packed code excerpts for the {{CONTEXT}}
+// placeholder of the implementation prompts. It returns "" when there are
+// no excerpts.
+func BuildContextSection(files string) string {
+	if strings.TrimSpace(files) == "" {
+		return ""
+	}
+	return strings.ReplaceAll(ContextSection, "{{FILES}}", strings.TrimRight(files, "\n"))
+}
+
+// BuildLearningsSummaryPrompt asks for a digest of learnings of at most
+// maxTokens tokens.
+func BuildLearningsSummaryPrompt(learnings string, maxTokens int) string {
+	prompt := strings.ReplaceAll(LearningsSummaryTemplate, "{{MAX_TOKENS}}", strconv.Itoa(maxTokens))
+	return strings.ReplaceAll(prompt, "{{LEARNINGS}}", learnings)
+}
+
+// BuildImplOutputSummaryPrompt asks for a summary of at most maxTokens
+// tokens of one part of a long implementation output.
+func BuildImplOutputSummaryPrompt(output string, part, parts, maxTokens int) string {
+	prompt := strings.ReplaceAll(ImplOutputSummaryTemplate, "{{PART}}", strconv.Itoa(part))
+	prompt = strings.ReplaceAll(prompt, "{{PARTS}}", strconv.Itoa(parts))
+	prompt = strings.ReplaceAll(prompt, "{{MAX_TOKENS}}", strconv.Itoa(maxTokens))
+	return strings.ReplaceAll(prompt, "{{OUTPUT}}", output)
+}
+
+// BuildImplOutputSummarySection renders the section appended to validation
+// prompts in place of a long implementation output: its summary and the
+// path of the full file. Returns "" when summary is empty.
+func BuildImplOutputSummarySection(summary, implOutputFile string, tokens int) string {
+	if strings.TrimSpace(summary) == "" {
+		return ""
+	}
+	section := strings.ReplaceAll(ImplOutputSummarySection, "{{TOKENS}}", strconv.Itoa(tokens))
+	section = strings.ReplaceAll(section, "{{IMPL_OUTPUT_FILE}}", implOutputFile)
+	return strings.ReplaceAll(section, "{{SUMMARY}}", strings.TrimRight(summary, "\n"))
+}
+
+// BuildFormatFixPrompt asks for the block of the answer in outputFile to be
+// re-emitted so that it conforms to schema, listing the violations found.
+func BuildFormatFixPrompt(block, outputFile string, errs []string, schema string) string {
+	prompt := strings.ReplaceAll(FormatFixTemplate, "{{OUTPUT_FILE}}", outputFile)
+	prompt = strings.ReplaceAll(prompt, "{{ERRORS}}", "- "+strings.Join(errs, "\n- "))
+	prompt = strings.ReplaceAll(prompt, "{{SCHEMA}}", schema)
+	return strings.ReplaceAll(prompt, "{{BLOCK}}", block)
+}
@@ -4,17 +4,24 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/CodexForgeBR/cli-tools/internal/policy"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// The policies are rendered as the loop does, from the built-in set.
+var (
+	defaultRules  = policy.RulesText(policy.Default())
+	defaultChecks = policy.DetectionText(policy.Default())
+)
+
 // TestBuildImplFirstPrompt_IncludesInadmissibleRules verifies that the first
 // implementation prompt includes the inadmissible practices section.
 func TestBuildImplFirstPrompt_IncludesInadmissibleRules(t *testing.T) {
 	tasksFile := "/path/to/tasks.md"
 	learnings := ""
 
-	result := BuildImplFirstPrompt(tasksFile, learnings)
+	result := BuildImplFirstPrompt(tasksFile, learnings, "", "", defaultRules)
 
 	assert.Contains(t, result, "INADMISSIBLE PRACTICES", "prompt should include inadmissible practices section")
 	assert.Contains(t, result, "PRODUCTION CODE DUPLICATION IN TESTS", "prompt should include specific inadmissible rule")
@@ -29,13 +36,14 @@ func TestBuildImplFirstPrompt_IncludesEvidenceRules(t *testing.T) {
 	tasksFile := "/path/to/tasks.md"
 	learnings := ""
 
-	result := BuildImplFirstPrompt(tasksFile, learnings)
+	result := BuildImplFirstPrompt(tasksFile, learnings, "", "", defaultRules)
 
 	assert.Contains(t, result, "EVIDENCE CAPTURE FOR NON-FILE TASKS", "prompt should include evidence capture section")
 	assert.Contains(t, result, "Deploy X", "prompt should include deploy evidence example")
 	assert.Contains(t, result, "Run tests", "prompt should include test evidence example")
 	assert.Contains(t, result, "Build X", "prompt should include build evidence example")
 	assert.Contains(t, result, "Playwright MCP", "prompt should mention Playwright MCP in evidence section")
+	assert.Contains(t, result, "RALPH_EVIDENCE", "prompt should describe the required evidence block")
 }
 
 // TestBuildImplFirstPrompt_IncludesPlaywrightRules verifies that the first
@@ -44,7 +52,7 @@ func TestBuildImplFirstPrompt_IncludesPlaywrightRules(t *testing.T) {
 	tasksFile := "/path/to/tasks.md"
 	learnings := ""
 
-	result := BuildImplFirstPrompt(tasksFile, learnings)
+	result := BuildImplFirstPrompt(tasksFile, learnings, "", "", defaultRules)
 
 	assert.Contains(t, result, "PLAYWRIGHT MCP VALIDATION", "prompt should include Playwright MCP section header")
 	assert.Contains(t, result, "APP NOT RUNNING", "prompt should mention app not running rule")
@@ -58,7 +66,7 @@ func TestBuildImplFirstPrompt_IncludesTasksFile(t *testing.T) {
 	tasksFile := "/custom/path/to/tasks.md"
 	learnings := ""
 
-	result := BuildImplFirstPrompt(tasksFile, learnings)
+	result := BuildImplFirstPrompt(tasksFile, learnings, "", "", defaultRules)
 
 	assert.Contains(t, result, tasksFile, "prompt should include the tasks file path")
 	assert.Contains(t, result, "TASKS FILE:", "prompt should have tasks file label")
@@ -70,7 +78,7 @@ func TestBuildImplFirstPrompt_IncludesLearnings(t *testing.T) {
 	tasksFile := "/path/to/tasks.md"
 	learnings := "Pattern: Always use strict null checks\nGotcha: API returns null on empty"
 
-	result := BuildImplFirstPrompt(tasksFile, learnings)
+	result := BuildImplFirstPrompt(tasksFile, learnings, "", "", defaultRules)
 
 	assert.Contains(t, result, "LEARNINGS FROM PREVIOUS ITERATIONS", "prompt should include learnings header")
 	assert.Contains(t, result, learnings, "prompt should include the actual learnings content")
@@ -83,7 +91,7 @@ func TestBuildImplFirstPrompt_OmitsLearnings(t *testing.T) {
 	tasksFile := "/path/to/tasks.md"
 	learnings := ""
 
-	result := BuildImplFirstPrompt(tasksFile, learnings)
+	result := BuildImplFirstPrompt(tasksFile, learnings, "", "", defaultRules)
 
 	assert.NotContains(t, result, "LEARNINGS FROM PREVIOUS ITERATIONS", "prompt should not include learnings header when empty")
 }
@@ -94,7 +102,7 @@ func TestBuildImplFirstPrompt_IncludesRalphStatus(t *testing.T) {
 	tasksFile := "/path/to/tasks.md"
 	learnings := ""
 
-	result := BuildImplFirstPrompt(tasksFile, learnings)
+	result := BuildImplFirstPrompt(tasksFile, learnings, "", "", defaultRules)
 
 	assert.Contains(t, result, "RALPH_STATUS", "prompt should mention RALPH_STATUS")
 	assert.Contains(t, result, "completed_tasks", "prompt should mention completed_tasks field")
@@ -107,7 +115,7 @@ func TestBuildImplFirstPrompt_IncludesLearningsOutput(t *testing.T) {
 	tasksFile := "/path/to/tasks.md"
 	learnings := ""
 
-	result := BuildImplFirstPrompt(tasksFile, learnings)
+	result := BuildImplFirstPrompt(tasksFile, learnings, "", "", defaultRules)
 
 	assert.Contains(t, result, "RALPH_LEARNINGS", "prompt should mention RALPH_LEARNINGS")
 	assert.Contains(t, result, "LEARNINGS OUTPUT", "prompt should include learnings output section")
@@ -123,7 +131,7 @@ func TestBuildImplContinuePrompt_IncludesFeedback(t *testing.T) {
 	feedback := "Task T001: You said you removed X but it's still in the code."
 	learnings := ""
 
-	result := BuildImplContinuePrompt(tasksFile, feedback, learnings)
+	result := BuildImplContinuePrompt(tasksFile, feedback, learnings, "", "")
 
 	assert.Contains(t, result, "VALIDATION CAUGHT YOUR LIES", "prompt should include feedback header")
 	assert.Contains(t, result, feedback, "prompt should include the actual feedback text")
@@ -137,7 +145,7 @@ func TestBuildImplContinuePrompt_IncludesEvidenceRules(t *testing.T) {
 	feedback := "Fix task T001"
 	learnings := ""
 
-	result := BuildImplContinuePrompt(tasksFile, feedback, learnings)
+	result := BuildImplContinuePrompt(tasksFile, feedback, learnings, "", "")
 
 	assert.Contains(t, result, "EVIDENCE CAPTURE FOR NON-FILE TASKS", "prompt should include evidence capture section")
 }
@@ -149,7 +157,7 @@ func TestBuildImplContinuePrompt_IncludesPlaywrightRules(t *testing.T) {
 	feedback := "Fix task T001"
 	learnings := ""
 
-	result := BuildImplContinuePrompt(tasksFile, feedback, learnings)
+	result := BuildImplContinuePrompt(tasksFile, feedback, learnings, "", "")
 
 	assert.Contains(t, result, "PLAYWRIGHT MCP VALIDATION", "prompt should include Playwright section")
 	assert.Contains(t, result, "APP NOT RUNNING", "prompt should mention app not running rule")
@@ -162,7 +170,7 @@ func TestBuildImplContinuePrompt_IncludesRalphStatus(t *testing.T) {
 	feedback := "Fix task T001"
 	learnings := ""
 
-	result := BuildImplContinuePrompt(tasksFile, feedback, learnings)
+	result := BuildImplContinuePrompt(tasksFile, feedback, learnings, "", "")
 
 	assert.Contains(t, result, "RALPH_STATUS", "prompt should mention RALPH_STATUS")
 	assert.Contains(t, result, "completed_tasks", "prompt should mention completed_tasks field")
@@ -175,7 +183,7 @@ func TestBuildImplContinuePrompt_IncludesLearnings(t *testing.T) {
 	feedback := "Fix task T001"
 	learnings := "Pattern: Database connections must be pooled\nGotcha: Timeout is in milliseconds"
 
-	result := BuildImplContinuePrompt(tasksFile, feedback, learnings)
+	result := BuildImplContinuePrompt(tasksFile, feedback, learnings, "", "")
 
 	assert.Contains(t, result, "LEARNINGS FROM PREVIOUS ITERATIONS", "prompt should include learnings header")
 	assert.Contains(t, result, learnings, "prompt should include the actual learnings content")
@@ -188,7 +196,7 @@ func TestBuildImplContinuePrompt_OmitsLearnings(t *testing.T) {
 	feedback := "Fix task T001"
 	learnings := ""
 
-	result := BuildImplContinuePrompt(tasksFile, feedback, learnings)
+	result := BuildImplContinuePrompt(tasksFile, feedback, learnings, "", "")
 
 	assert.NotContains(t, result, "LEARNINGS FROM PREVIOUS ITERATIONS", "prompt should not include learnings header when empty")
 }
@@ -200,7 +208,7 @@ func TestBuildImplContinuePrompt_WarnsCriticalRules(t *testing.T) {
 	feedback := "Fix task T001"
 	learnings := ""
 
-	result := BuildImplContinuePrompt(tasksFile, feedback, learnings)
+	result := BuildImplContinuePrompt(tasksFile, feedback, learnings, "", "")
 
 	assert.Contains(t, result, "CRITICAL", "prompt should emphasize critical rules")
 	assert.Contains(t, result, "DO NOT WRITE TESTS FOR NON-EXISTENT FUNCTIONALITY", "prompt should warn about non-existent functionality")
@@ -213,7 +221,7 @@ func TestBuildValidationPrompt_IncludesImplOutput(t *testing.T) {
 	tasksFile := "/path/to/tasks.md"
 	implOutputFile := "/path/to/impl-output.txt"
 
-	result := BuildValidationPrompt(tasksFile, implOutputFile)
+	result := BuildValidationPrompt(tasksFile, implOutputFile, defaultChecks)
 
 	assert.Contains(t, result, "IMPLEMENTATION OUTPUT FILE", "prompt should include impl output file header")
 	assert.Contains(t, result, implOutputFile, "prompt should include the implementation output file path")
@@ -225,7 +233,7 @@ func TestBuildValidationPrompt_IncludesTasksFile(t *testing.T) {
 	tasksFile := "/custom/path/to/tasks.md"
 	implOutput := "Work completed"
 
-	result := BuildValidationPrompt(tasksFile, implOutput)
+	result := BuildValidationPrompt(tasksFile, implOutput, defaultChecks)
 
 	assert.Contains(t, result, tasksFile, "prompt should include the tasks file path")
 	assert.Contains(t, result, "TASKS FILE TO CHECK AGAINST", "prompt should have tasks file label")
@@ -237,7 +245,7 @@ func TestBuildValidationPrompt_IncludesValidatorRole(t *testing.T) {
 	tasksFile := "/path/to/tasks.md"
 	implOutput := "Work completed"
 
-	result := BuildValidationPrompt(tasksFile, implOutput)
+	result := BuildValidationPrompt(tasksFile, implOutput, defaultChecks)
 
 	assert.Contains(t, result, "VALIDATOR", "prompt should mention validator role")
 	assert.Contains(t, result, "THE IMPLEMENTER IS A LIAR", "prompt should establish adversarial stance")
@@ -250,7 +258,7 @@ func TestBuildValidationPrompt_IncludesCommonLies(t *testing.T) {
 	tasksFile := "/path/to/tasks.md"
 	implOutput := "Work completed"
 
-	result := BuildValidationPrompt(tasksFile, implOutput)
+	result := BuildValidationPrompt(tasksFile, implOutput, defaultChecks)
 
 	assert.Contains(t, result, "COMMON LIES TO CATCH", "prompt should include common lies section")
 	assert.Contains(t, result, "I removed X", "prompt should list 'removed' lie example")
@@ -264,7 +272,7 @@ func TestBuildValidationPrompt_IncludesRalphValidation(t *testing.T) {
 	tasksFile := "/path/to/tasks.md"
 	implOutput := "Work completed"
 
-	result := BuildValidationPrompt(tasksFile, implOutput)
+	result := BuildValidationPrompt(tasksFile, implOutput, defaultChecks)
 
 	assert.Contains(t, result, "RALPH_VALIDATION", "prompt should mention RALPH_VALIDATION")
 	assert.Contains(t, result, "verdict", "prompt should mention verdict field")
@@ -280,7 +288,7 @@ func TestBuildValidationPrompt_IncludesVerdictOptions(t *testing.T) {
 	tasksFile := "/path/to/tasks.md"
 	implOutput := "Work completed"
 
-	result := BuildValidationPrompt(tasksFile, implOutput)
+	result := BuildValidationPrompt(tasksFile, implOutput, defaultChecks)
 
 	assert.Contains(t, result, "COMPLETE", "prompt should list COMPLETE verdict")
 	assert.Contains(t, result, "NEEDS_MORE_WORK", "prompt should list NEEDS_MORE_WORK verdict")
@@ -295,14 +303,16 @@ func TestBuildValidationPrompt_IncludesInadmissibleChecks(t *testing.T) {
 	tasksFile := "/path/to/tasks.md"
 	implOutput := "Work completed"
 
-	result := BuildValidationPrompt(tasksFile, implOutput)
+	result := BuildValidationPrompt(tasksFile, implOutput, defaultChecks)
 
 	assert.Contains(t, result, "INADMISSIBLE PRACTICES", "prompt should include inadmissible section")
 	assert.Contains(t, result, "PRODUCTION CODE DUPLICATION IN TESTS", "prompt should check for duplication")
-	assert.Contains(t, result, "MOCKING THE SUBJECT UNDER TEST", "prompt should check for mocking")
+	assert.Contains(t, result, "MOCK THE SUBJECT UNDER TEST", "prompt should check for mocking")
 	assert.Contains(t, result, "TRIVIAL/EMPTY TESTS", "prompt should check for trivial tests")
 	assert.Contains(t, result, "TESTS FOR NON-EXISTENT FUNCTIONALITY", "prompt should check for non-existent functionality")
 	assert.Contains(t, result, "DETECTION PROCESS", "prompt should include detection process")
+	assert.Contains(t, result, `Report it as "trivial-tests: <file and detail>"`, "prompt should name policies for inadmissible_practices")
+	assert.NotContains(t, result, "{{POLICY_CHECKS}}")
 }
 
 // TestBuildValidationPrompt_NonEmpty verifies that all builder functions
@@ -314,15 +324,15 @@ func TestBuildValidationPrompt_NonEmpty(t *testing.T) {
 	}{
 		{
 			name:   "BuildImplFirstPrompt",
-			result: BuildImplFirstPrompt("/path/to/tasks.md", ""),
+			result: BuildImplFirstPrompt("/path/to/tasks.md", "", "", "", defaultRules),
 		},
 		{
 			name:   "BuildImplContinuePrompt",
-			result: BuildImplContinuePrompt("/path/to/tasks.md", "feedback", ""),
+			result: BuildImplContinuePrompt("/path/to/tasks.md", "feedback", "", "", ""),
 		},
 		{
 			name:   "BuildValidationPrompt",
-			result: BuildValidationPrompt("/path/to/tasks.md", "output"),
+			result: BuildValidationPrompt("/path/to/tasks.md", "output", defaultChecks),
 		},
 	}
 
@@ -340,7 +350,7 @@ func TestBuildImplFirstPrompt_NoPlaceholders(t *testing.T) {
 	tasksFile := "/path/to/tasks.md"
 	learnings := "Some learnings"
 
-	result := BuildImplFirstPrompt(tasksFile, learnings)
+	result := BuildImplFirstPrompt(tasksFile, learnings, "", "", defaultRules)
 
 	// Should not contain unreplaced template markers
 	assert.NotContains(t, result, "{{TASKS_FILE}}", "should not contain tasks file placeholder")
@@ -358,7 +368,7 @@ func TestBuildImplContinuePrompt_NoPlaceholders(t *testing.T) {
 	feedback := "Fix these issues"
 	learnings := "Some learnings"
 
-	result := BuildImplContinuePrompt(tasksFile, feedback, learnings)
+	result := BuildImplContinuePrompt(tasksFile, feedback, learnings, "", "")
 
 	// Should not contain unreplaced template markers
 	assert.NotContains(t, result, "{{TASKS_FILE}}", "should not contain tasks file placeholder")
@@ -375,7 +385,7 @@ func TestBuildValidationPrompt_NoPlaceholders(t *testing.T) {
 	tasksFile := "/path/to/tasks.md"
 	implOutput := "Implementation output"
 
-	result := BuildValidationPrompt(tasksFile, implOutput)
+	result := BuildValidationPrompt(tasksFile, implOutput, defaultChecks)
 
 	// Should not contain unreplaced template markers
 	assert.NotContains(t, result, "{{TASKS_FILE}}", "should not contain tasks file placeholder")
@@ -388,7 +398,7 @@ func TestBuildImplFirstPrompt_WithLearningsHasNoDoubleMarkers(t *testing.T) {
 	tasksFile := "/path/to/tasks.md"
 	learnings := "Pattern: Use dependency injection"
 
-	result := BuildImplFirstPrompt(tasksFile, learnings)
+	result := BuildImplFirstPrompt(tasksFile, learnings, "", "", defaultRules)
 
 	// The LEARNINGS placeholder inside learnings-section.txt should be replaced
 	assert.NotContains(t, result, "{{LEARNINGS}}", "should not contain nested learnings placeholder")
@@ -397,19 +407,19 @@ func TestBuildImplFirstPrompt_WithLearningsHasNoDoubleMarkers(t *testing.T) {
 // TestPromptStructure verifies that prompts have expected structural elements.
 func TestPromptStructure(t *testing.T) {
 	t.Run("ImplFirst has clear workflow", func(t *testing.T) {
-		result := BuildImplFirstPrompt("/path/to/tasks.md", "")
+		result := BuildImplFirstPrompt("/path/to/tasks.md", "", "", "", defaultRules)
 		assert.Contains(t, result, "WORKFLOW:", "should include workflow section")
 		assert.Contains(t, result, "BEGIN.", "should have clear begin instruction")
 	})
 
 	t.Run("ImplContinue emphasizes fixing", func(t *testing.T) {
-		result := BuildImplContinuePrompt("/path/to/tasks.md", "feedback", "")
+		result := BuildImplContinuePrompt("/path/to/tasks.md", "feedback", "", "", "")
 		assert.Contains(t, result, "FIX YOUR MISTAKES", "should emphasize fixing")
 		assert.Contains(t, result, "REMEMBER:", "should remind of rules")
 	})
 
 	t.Run("Validation establishes adversarial role", func(t *testing.T) {
-		result := BuildValidationPrompt("/path/to/tasks.md", "output")
+		result := BuildValidationPrompt("/path/to/tasks.md", "output", defaultChecks)
 		assert.Contains(t, result, "BE RUTHLESS", "should encourage strict validation")
 		assert.Contains(t, result, "CATCH THEIR LIES", "should emphasize catching errors")
 	})
@@ -424,17 +434,17 @@ func TestPromptLength(t *testing.T) {
 	}{
 		{
 			name:     "ImplFirst",
-			prompt:   BuildImplFirstPrompt("/path/to/tasks.md", ""),
+			prompt:   BuildImplFirstPrompt("/path/to/tasks.md", "", "", "", defaultRules),
 			minLines: 50,
 		},
 		{
 			name:     "ImplContinue",
-			prompt:   BuildImplContinuePrompt("/path/to/tasks.md", "feedback", ""),
+			prompt:   BuildImplContinuePrompt("/path/to/tasks.md", "feedback", "", "", ""),
 			minLines: 30,
 		},
 		{
 			name:     "Validation",
-			prompt:   BuildValidationPrompt("/path/to/tasks.md", "output"),
+			prompt:   BuildValidationPrompt("/path/to/tasks.md", "output", defaultChecks),
 			minLines: 100,
 		},
 	}
@@ -657,3 +667,257 @@ func TestNewPromptBuilders_NonEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildSkippedTasksSection_ListsTasks(t *testing.T) {
+	section := BuildSkippedTasksSection([]string{"T003 Flaky integration test", "T007 Deploy"})
+
+	assert.Contains(t, section, "SKIPPED TASKS")
+	assert.Contains(t, section, "- T003 Flaky integration test\n- T007 Deploy")
+	assert.NotContains(t, section, "{{SKIPPED_TASKS}}")
+}
+
+func TestBuildSkippedTasksSection_EmptyWhenNoneSkipped(t *testing.T) {
+	assert.Empty(t, BuildSkippedTasksSection(nil))
+}
+
+func TestBuildReadyTasksSection(t *testing.T) {
+	section := BuildReadyTasksSection([]string{"T002 Add writer", "T004 Docs"})
+	assert.Contains(t, section, "TASK DEPENDENCIES")
+	assert.Contains(t, section, "- T002 Add writer\n- T004 Docs")
+	assert.NotContains(t, section, "{{")
+
+	assert.Empty(t, BuildReadyTasksSection(nil))
+}
+
+func TestBuildCustomVerdictsSection(t *testing.T) {
+	section := BuildCustomVerdictsSection([]string{"SECURITY_REVIEW_NEEDED - touches auth code", "WONTFIX"})
+	assert.Contains(t, section, "PROJECT VERDICTS")
+	assert.Contains(t, section, "- SECURITY_REVIEW_NEEDED - touches auth code\n- WONTFIX")
+	assert.NotContains(t, section, "{{")
+
+	assert.Empty(t, BuildCustomVerdictsSection(nil))
+}
+
+func TestBuildFeedbackHistorySection(t *testing.T) {
+	section := BuildFeedbackHistorySection([]string{"still failing since iteration 2 (raised 3 times): T002 login test"})
+	assert.Contains(t, section, "EARLIER FEEDBACK STILL OPEN")
+	assert.Contains(t, section, "- still failing since iteration 2 (raised 3 times): T002 login test")
+	assert.NotContains(t, section, "{{")
+
+	assert.Empty(t, BuildFeedbackHistorySection(nil))
+}
+
+func TestBuildCommandsRunSection(t *testing.T) {
+	section := BuildCommandsRunSection([]string{"exit 0: go test ./...", "exit ?: make deploy"})
+	assert.Contains(t, section, "COMMANDS THE IMPLEMENTER RAN")
+	assert.Contains(t, section, "- exit 0: go test ./...\n- exit ?: make deploy")
+	assert.NotContains(t, section, "{{")
+
+	assert.Contains(t, BuildCommandsRunSection([]string{}), "(no shell commands were run)")
+	assert.Empty(t, BuildCommandsRunSection(nil))
+}
+
+func TestBuildProtectedPathsSection(t *testing.T) {
+	section := BuildProtectedPathsSection([]string{".github/workflows", "infra/prod"}, false)
+	assert.Contains(t, section, "PROTECTED PATHS")
+	assert.Contains(t, section, "- .github/workflows\n- infra/prod")
+	assert.Contains(t, section, "ralph-loop reverts any change")
+	assert.NotContains(t, section, "{{")
+
+	assert.Contains(t, BuildProtectedPathsSection([]string{"infra/prod"}, true), "stops the loop\nfor human review")
+	assert.Empty(t, BuildProtectedPathsSection(nil, false))
+}
+
+func TestBuildProtectedRevertedSection(t *testing.T) {
+	section := BuildProtectedRevertedSection([]string{".github/workflows/ci.yml"})
+	assert.Contains(t, section, "REVERTED CHANGES TO PROTECTED PATHS")
+	assert.Contains(t, section, "- .github/workflows/ci.yml")
+	assert.NotContains(t, section, "{{")
+
+	assert.Empty(t, BuildProtectedRevertedSection(nil))
+}
+
+func TestBuildPolicyViolationsSection(t *testing.T) {
+	section := BuildPolicyViolationsSection([]string{"trivial-tests: a.test.ts:2: expect(true).toBe(true)"})
+	assert.Contains(t, section, "POLICY VIOLATIONS FOUND BY RALPH-LOOP")
+	assert.Contains(t, section, "- trivial-tests: a.test.ts:2: expect(true).toBe(true)")
+	assert.NotContains(t, section, "{{")
+
+	assert.Empty(t, BuildPolicyViolationsSection(nil))
+}
+
+func TestBuildScreenshotDiffSection(t *testing.T) {
+	section := BuildScreenshotDiffSection([]string{"T003 login.png: regression", "T004 banks.png: new"})
+	assert.Contains(t, section, "SCREENSHOT DIFFS")
+	assert.Contains(t, section, "- T003 login.png: regression\n- T004 banks.png: new")
+	assert.NotContains(t, section, "{{")
+
+	assert.Empty(t, BuildScreenshotDiffSection(nil))
+}
+
+func TestBuildPatchModeSection(t *testing.T) {
+	section := BuildPatchModeSection("specs/tasks.md")
+	assert.Contains(t, section, "PATCH MODE")
+	assert.Contains(t, section, "RALPH_PATCH")
+	assert.Contains(t, section, "checkbox updates to specs/tasks.md")
+	assert.NotContains(t, section, "{{")
+}
+
+func TestBuildPlanFromIssuePrompt(t *testing.T) {
+	p := BuildPlanFromIssuePrompt("/state/github-issue.md")
+	assert.Contains(t, p, "Read the issue in /state/github-issue.md")
+	assert.Contains(t, p, "RALPH_PLAN")
+	assert.Contains(t, p, "RALPH_TASKS")
+	assert.NotContains(t, p, "{{")
+}
+
+func TestBuildTasksRegeneratePrompt(t *testing.T) {
+	p := BuildTasksRegeneratePrompt("specs/a/spec.md", "specs/a/tasks.md", "+ Export as CSV\n- Export as XML\n", "T004 covers XML export")
+	assert.Contains(t, p, "Read the updated spec in specs/a/spec.md")
+	assert.Contains(t, p, "current task list in\nspecs/a/tasks.md")
+	assert.Contains(t, p, "+ Export as CSV\n- Export as XML\n\n")
+	assert.Contains(t, p, "T004 covers XML export")
+	assert.Contains(t, p, "RALPH_TASKS")
+	assert.NotContains(t, p, "{{")
+
+	assert.Contains(t, BuildTasksRegeneratePrompt("s", "t", "+ x", ""), "(no feedback given)")
+}
+
+func TestBuildImplPrompts_IncludeContext(t *testing.T) {
+	section := BuildContextSection("### main.go (lines 1-3 of 3)\n\n```\npackage main\n```\n\n")
+	assert.Contains(t, section, "RELEVANT CODE")
+	assert.Contains(t, section, "### main.go (lines 1-3 of 3)")
+	assert.NotContains(t, section, "{{")
+
+	first := BuildImplFirstPrompt("tasks.md", "", section, "", defaultRules)
+	assert.Contains(t, first, "### main.go")
+	assert.NotContains(t, first, "{{CONTEXT}}")
+
+	cont := BuildImplContinuePrompt("tasks.md", "fix it", "", section, "")
+	assert.Contains(t, cont, "### main.go")
+	assert.NotContains(t, cont, "{{CONTEXT}}")
+
+	assert.NotContains(t, BuildImplFirstPrompt("tasks.md", "", "", "", defaultRules), "RELEVANT CODE")
+}
+
+func TestBuildIterationDiffSection(t *testing.T) {
+	section := BuildIterationDiffSection("+func main() {}\n", " main.go | 1 +", 0)
+	assert.Contains(t, section, "```diff\n+func main() {}\n```")
+	assert.Contains(t, section, " main.go | 1 +")
+	assert.NotContains(t, section, "truncated")
+	assert.NotContains(t, section, "{{")
+
+	truncated := BuildIterationDiffSection("+a\n", "stat", 512)
+	assert.Contains(t, truncated, "(diff truncated: 512 more bytes not shown")
+
+	assert.Contains(t, BuildIterationDiffSection("", "", 0), "changed NO files")
+}
+
+func TestBuildFocusFilesSection(t *testing.T) {
+	section := BuildFocusFilesSection([]string{"main.go (+12 -3)", "logo.png (binary)"})
+	assert.Contains(t, section, "FOCUS ON THESE FILES")
+	assert.Contains(t, section, "- main.go (+12 -3)\n- logo.png (binary)")
+	assert.NotContains(t, section, "{{")
+
+	assert.Empty(t, BuildFocusFilesSection(nil))
+}
+
+func TestBuildTestResultsSection(t *testing.T) {
+	section := BuildTestResultsSection("Tests: 3 passed, 1 failed, 0 skipped")
+	assert.Contains(t, section, "TEST RESULTS")
+	assert.Contains(t, section, "Tests: 3 passed, 1 failed, 0 skipped")
+	assert.NotContains(t, section, "{{")
+
+	assert.Empty(t, BuildTestResultsSection(""))
+}
+
+func TestBuildProjectMemorySection(t *testing.T) {
+	section := BuildProjectMemorySection("Session ralph-1 escalated: needs a refund policy")
+	assert.Contains(t, section, "PROJECT MEMORY")
+	assert.Contains(t, section, "Session ralph-1 escalated: needs a refund policy")
+	assert.NotContains(t, section, "{{")
+
+	assert.Empty(t, BuildProjectMemorySection(""))
+}
+
+func TestBuildImplPrompts_IncludeHumanGuidance(t *testing.T) {
+	section := BuildHumanGuidanceSection([]string{"Use approach B", "Keep the v1 API"})
+	assert.Contains(t, section, "HUMAN GUIDANCE")
+	assert.Contains(t, section, "- Use approach B\n- Keep the v1 API")
+	assert.NotContains(t, section, "{{")
+
+	first := BuildImplFirstPrompt("tasks.md", "", "", section, defaultRules)
+	assert.Contains(t, first, "- Use approach B")
+	assert.Less(t, strings.Index(first, "HUMAN GUIDANCE"), strings.Index(first, "ABSOLUTE RULES"), "guidance comes before the rules")
+
+	cont := BuildImplContinuePrompt("tasks.md", "fix it", "", "", section)
+	assert.Contains(t, cont, "- Keep the v1 API")
+	assert.Less(t, strings.Index(cont, "fix it"), strings.Index(cont, "HUMAN GUIDANCE"))
+
+	for _, p := range []string{BuildImplFirstPrompt("tasks.md", "", "", "", defaultRules), BuildImplContinuePrompt("tasks.md", "fix it", "", "", "")} {
+		assert.NotContains(t, p, "HUMAN GUIDANCE")
+		assert.NotContains(t, p, "{{HUMAN_GUIDANCE}}")
+	}
+	assert.Empty(t, BuildHumanGuidanceSection(nil))
+}
+
+func TestBuildContextSection_Empty(t *testing.T) {
+	assert.Empty(t, BuildContextSection(""))
+	assert.Empty(t, BuildContextSection("\n"))
+}
+
+func TestBuildLearningsSummaryPrompt(t *testing.T) {
+	p := BuildLearningsSummaryPrompt("- Use table tests", 2000)
+	assert.Contains(t, p, "at most 2000 tokens")
+	assert.Contains(t, p, "- Use table tests")
+	assert.Contains(t, p, "RALPH_LEARNINGS_DIGEST")
+	assert.NotContains(t, p, "{{")
+}
+
+func TestBuildImplOutputSummaryPrompt(t *testing.T) {
+	p := BuildImplOutputSummaryPrompt("Ran go test ./...: ok", 2, 3, 4000)
+	assert.Contains(t, p, "part\n2 of 3")
+	assert.Contains(t, p, "(PART 2 OF 3)")
+	assert.Contains(t, p, "at most 4000 tokens")
+	assert.Contains(t, p, "Ran go test ./...: ok")
+	assert.Contains(t, p, "RALPH_OUTPUT_SUMMARY")
+	assert.NotContains(t, p, "{{")
+}
+
+func TestBuildImplOutputSummarySection(t *testing.T) {
+	section := BuildImplOutputSummarySection("- Edited main.go\n", "/tmp/iteration-001/implementation-output.txt", 52000)
+	assert.Contains(t, section, "IMPLEMENTATION OUTPUT SUMMARY")
+	assert.Contains(t, section, "~52000 tokens")
+	assert.Contains(t, section, "/tmp/iteration-001/implementation-output.txt")
+	assert.Contains(t, section, "- Edited main.go")
+	assert.NotContains(t, section, "{{")
+
+	assert.Empty(t, BuildImplOutputSummarySection("  ", "/tmp/out.txt", 52000))
+}
+
+func TestBuildFormatFixPrompt(t *testing.T) {
+	p := BuildFormatFixPrompt("RALPH_VALIDATION", "/tmp/iter-1/validation-output.txt",
+		[]string{`RALPH_VALIDATION.verdict: "DONE" is not one of COMPLETE`, `RALPH_VALIDATION: missing required property "feedback"`},
+		`{"type": "object"}`)
+
+	assert.Contains(t, p, "/tmp/iter-1/validation-output.txt")
+	assert.Contains(t, p, "- RALPH_VALIDATION.verdict: \"DONE\" is not one of COMPLETE\n- RALPH_VALIDATION: missing required property \"feedback\"")
+	assert.Contains(t, p, "```json\n{\"type\": \"object\"}\n```")
+	assert.Contains(t, p, `"RALPH_VALIDATION": { ... }`)
+	assert.NotContains(t, p, "{{")
+}
+
+func TestBuildTriagePrompt(t *testing.T) {
+	result := BuildTriagePrompt("/iter/implementation-output.txt", []string{"T001 Add parser"}, []string{}, nil)
+
+	assert.Contains(t, result, "/iter/implementation-output.txt")
+	assert.Contains(t, result, "- T001 Add parser")
+	assert.Contains(t, result, "(no files changed)")
+	assert.Contains(t, result, "(unknown: the AI CLI records no transcript)")
+	assert.Contains(t, result, "RALPH_TRIAGE")
+	assert.NotContains(t, result, "{{")
+
+	result = BuildTriagePrompt("out.txt", nil, []string{"a.go", "a_test.go"}, []string{"exit 0: go test ./..."})
+	assert.Contains(t, result, "- a.go\n- a_test.go")
+	assert.Contains(t, result, "- exit 0: go test ./...")
+}
@@ -36,4 +36,76 @@ var (
 
 	//go:embed templates/final-plan.txt
 	FinalPlanTemplate string
+
+	//go:embed templates/triage.txt
+	TriageTemplate string
+
+	//go:embed templates/protected-paths.txt
+	ProtectedPathsSection string
+
+	//go:embed templates/protected-reverted.txt
+	ProtectedRevertedSection string
+
+	//go:embed templates/policy-violations.txt
+	PolicyViolationsSection string
+
+	//go:embed templates/human-guidance.txt
+	HumanGuidanceSection string
+
+	//go:embed templates/skipped-tasks.txt
+	SkippedTasksSection string
+
+	//go:embed templates/patch-mode.txt
+	PatchModeSection string
+
+	//go:embed templates/plan-from-issue.txt
+	PlanFromIssueTemplate string
+
+	//go:embed templates/tasks-regenerate.txt
+	TasksRegenerateTemplate string
+
+	//go:embed templates/ready-tasks.txt
+	ReadyTasksSection string
+
+	//go:embed templates/screenshot-diffs.txt
+	ScreenshotDiffSection string
+
+	//go:embed templates/learnings-summary.txt
+	LearningsSummaryTemplate string
+
+	//go:embed templates/impl-output-summary.txt
+	ImplOutputSummaryTemplate string
+
+	//go:embed templates/impl-output-summary-section.txt
+	ImplOutputSummarySection string
+
+	//go:embed templates/context-section.txt
+	ContextSection string
+
+	//go:embed templates/iteration-diff.txt
+	IterationDiffSection string
+
+	//go:embed templates/iteration-no-diff.txt
+	IterationNoDiffSection string
+
+	//go:embed templates/focus-files.txt
+	FocusFilesSection string
+
+	//go:embed templates/test-results.txt
+	TestResultsSection string
+
+	//go:embed templates/project-memory.txt
+	ProjectMemorySection string
+
+	//go:embed templates/custom-verdicts.txt
+	CustomVerdictsSection string
+
+	//go:embed templates/feedback-history.txt
+	FeedbackHistorySection string
+
+	//go:embed templates/commands-run.txt
+	CommandsRunSection string
+
+	//go:embed templates/format-fix.txt
+	FormatFixTemplate string
 )
@@ -8,6 +8,12 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// The rule templates as filled with the built-in policies.
+var (
+	inadmissibleRules = strings.ReplaceAll(InadmissibleRules, "{{POLICIES}}", defaultRules)
+	validationPrompt  = strings.ReplaceAll(ValidationTemplate, "{{POLICY_CHECKS}}", defaultChecks)
+)
+
 // TestTemplatesLoad verifies that all template files are loaded via go:embed
 // and are non-empty.
 func TestTemplatesLoad(t *testing.T) {
@@ -26,6 +32,25 @@ func TestTemplatesLoad(t *testing.T) {
 		{"CrossValidationTemplate", CrossValidationTemplate},
 		{"TasksValidationTemplate", TasksValidationTemplate},
 		{"FinalPlanTemplate", FinalPlanTemplate},
+		{"TriageTemplate", TriageTemplate},
+		{"SkippedTasksSection", SkippedTasksSection},
+		{"PatchModeSection", PatchModeSection},
+		{"PlanFromIssueTemplate", PlanFromIssueTemplate},
+		{"TasksRegenerateTemplate", TasksRegenerateTemplate},
+		{"ReadyTasksSection", ReadyTasksSection},
+		{"ScreenshotDiffSection", ScreenshotDiffSection},
+		{"LearningsSummaryTemplate", LearningsSummaryTemplate},
+		{"ImplOutputSummaryTemplate", ImplOutputSummaryTemplate},
+		{"ImplOutputSummarySection", ImplOutputSummarySection},
+		{"ContextSection", ContextSection},
+		{"IterationDiffSection", IterationDiffSection},
+		{"IterationNoDiffSection", IterationNoDiffSection},
+		{"FocusFilesSection", FocusFilesSection},
+		{"TestResultsSection", TestResultsSection},
+		{"ProjectMemorySection", ProjectMemorySection},
+		{"PolicyViolationsSection", PolicyViolationsSection},
+		{"ProtectedPathsSection", ProtectedPathsSection},
+		{"ProtectedRevertedSection", ProtectedRevertedSection},
 	}
 
 	for _, tt := range tests {
@@ -77,32 +102,36 @@ func TestImplContinueTemplate_ContainsKeyMarkers(t *testing.T) {
 }
 
 // TestInadmissibleRulesTemplate_ContainsKeyMarkers verifies that the
-// inadmissible rules template contains all required inadmissible practices.
+// inadmissible rules template, filled with the built-in policies, contains
+// all required inadmissible practices.
 func TestInadmissibleRulesTemplate_ContainsKeyMarkers(t *testing.T) {
-	assert.Contains(t, InadmissibleRules, "INADMISSIBLE PRACTICES", "should have header")
-	assert.Contains(t, InadmissibleRules, "AUTOMATIC FAILURE", "should emphasize automatic failure")
+	require.Contains(t, InadmissibleRules, "{{POLICIES}}", "should have policies marker")
+	rules := inadmissibleRules
+
+	assert.Contains(t, rules, "INADMISSIBLE PRACTICES", "should have header")
+	assert.Contains(t, rules, "AUTOMATIC FAILURE", "should emphasize automatic failure")
 
 	// Check for all four main inadmissible practices
-	assert.Contains(t, InadmissibleRules, "PRODUCTION CODE DUPLICATION IN TESTS", "should list duplication practice")
-	assert.Contains(t, InadmissibleRules, "MOCK THE SUBJECT UNDER TEST", "should list mocking practice")
-	assert.Contains(t, InadmissibleRules, "TRIVIAL/EMPTY TESTS", "should list trivial tests practice")
-	assert.Contains(t, InadmissibleRules, "TESTS FOR NON-EXISTENT FUNCTIONALITY", "should list non-existent functionality practice")
+	assert.Contains(t, rules, "PRODUCTION CODE DUPLICATION IN TESTS", "should list duplication practice")
+	assert.Contains(t, rules, "MOCK THE SUBJECT UNDER TEST", "should list mocking practice")
+	assert.Contains(t, rules, "TRIVIAL/EMPTY TESTS", "should list trivial tests practice")
+	assert.Contains(t, rules, "TESTS FOR NON-EXISTENT FUNCTIONALITY", "should list non-existent functionality practice")
 
 	// Check for examples
-	assert.Contains(t, InadmissibleRules, "WRONG:", "should provide wrong examples")
-	assert.Contains(t, InadmissibleRules, "RIGHT:", "should provide right examples")
-	assert.Contains(t, InadmissibleRules, "EXAMPLES OF INADMISSIBLE TEST-WRITING", "should have examples section")
+	assert.Contains(t, rules, "WRONG:", "should provide wrong examples")
+	assert.Contains(t, rules, "RIGHT:", "should provide right examples")
+	assert.Contains(t, rules, "EXAMPLES OF INADMISSIBLE TEST-WRITING", "should have examples section")
 
 	// Check for specific examples
-	assert.Contains(t, InadmissibleRules, "page.keyboard.press", "should have keyboard example")
-	assert.Contains(t, InadmissibleRules, "validateEmail", "should have function example")
-	assert.Contains(t, InadmissibleRules, "/api/delete-user", "should have API endpoint example")
-	assert.Contains(t, InadmissibleRules, "primary-view", "should have UI element example")
+	assert.Contains(t, rules, "page.keyboard.press", "should have keyboard example")
+	assert.Contains(t, rules, "validateEmail", "should have function example")
+	assert.Contains(t, rules, "/api/delete-user", "should have API endpoint example")
+	assert.Contains(t, rules, "primary-view", "should have UI element example")
 
 	// Check for detection and resolution guidance
-	assert.Contains(t, InadmissibleRules, "DETECTION", "should explain detection process")
-	assert.Contains(t, InadmissibleRules, "WHY THIS IS INADMISSIBLE", "should explain why it matters")
-	assert.Contains(t, InadmissibleRules, "Implementation first, then tests", "should emphasize correct order")
+	assert.Contains(t, rules, "DETECTION", "should explain detection process")
+	assert.Contains(t, rules, "WHY THIS IS INADMISSIBLE", "should explain why it matters")
+	assert.Contains(t, rules, "Implementation first, then tests", "should emphasize correct order")
 }
 
 // TestEvidenceRulesTemplate_ContainsKeyMarkers verifies that the evidence rules
@@ -117,6 +146,7 @@ func TestEvidenceRulesTemplate_ContainsKeyMarkers(t *testing.T) {
 	assert.Contains(t, EvidenceRules, "Verify X", "should mention verify tasks")
 	assert.Contains(t, EvidenceRules, "Run/Execute X", "should mention execute tasks")
 	assert.Contains(t, EvidenceRules, "Playwright MCP", "should mention Playwright MCP tasks")
+	assert.Contains(t, EvidenceRules, "RALPH_ARTIFACTS", "should explain how to declare evidence files")
 
 	// Check for examples of what to record
 	assert.Contains(t, EvidenceRules, "Version deployed", "should show deploy evidence example")
@@ -179,6 +209,7 @@ func TestLearningsOutputTemplate_ContainsKeyMarkers(t *testing.T) {
 func TestValidationTemplate_ContainsKeyMarkers(t *testing.T) {
 	// Check for placeholder markers
 	assert.Contains(t, ValidationTemplate, "{{TASKS_FILE}}", "should have tasks file marker")
+	assert.Contains(t, ValidationTemplate, "RALPH_ARTIFACTS", "should let validators keep evidence files")
 	assert.Contains(t, ValidationTemplate, "{{IMPL_OUTPUT_FILE}}", "should have impl output file marker")
 
 	// Check for role establishment
@@ -192,17 +223,17 @@ func TestValidationTemplate_ContainsKeyMarkers(t *testing.T) {
 	assert.Contains(t, ValidationTemplate, "CHECK EACH TASK", "should mention checking tasks")
 
 	// Check for inadmissible practices
-	assert.Contains(t, ValidationTemplate, "INADMISSIBLE PRACTICES", "should have inadmissible section")
-	assert.Contains(t, ValidationTemplate, "AUTO-FAIL", "should emphasize automatic failure")
-	assert.Contains(t, ValidationTemplate, "PRODUCTION CODE DUPLICATION IN TESTS", "should check for duplication")
-	assert.Contains(t, ValidationTemplate, "MOCKING THE SUBJECT UNDER TEST", "should check for mocking")
-	assert.Contains(t, ValidationTemplate, "TRIVIAL/EMPTY TESTS", "should check for trivial tests")
-	assert.Contains(t, ValidationTemplate, "TESTS FOR NON-EXISTENT FUNCTIONALITY", "should check for non-existent functionality")
+	assert.Contains(t, validationPrompt, "INADMISSIBLE PRACTICES", "should have inadmissible section")
+	assert.Contains(t, validationPrompt, "AUTO-FAIL", "should emphasize automatic failure")
+	assert.Contains(t, validationPrompt, "PRODUCTION CODE DUPLICATION IN TESTS", "should check for duplication")
+	assert.Contains(t, validationPrompt, "MOCK THE SUBJECT UNDER TEST", "should check for mocking")
+	assert.Contains(t, validationPrompt, "TRIVIAL/EMPTY TESTS", "should check for trivial tests")
+	assert.Contains(t, validationPrompt, "TESTS FOR NON-EXISTENT FUNCTIONALITY", "should check for non-existent functionality")
 
 	// Check for detection process
-	assert.Contains(t, ValidationTemplate, "DETECTION PROCESS", "should have detection process")
-	assert.Contains(t, ValidationTemplate, "Read ALL test files", "should mention reading test files")
-	assert.Contains(t, ValidationTemplate, "search the PRODUCTION code", "should mention searching production code")
+	assert.Contains(t, validationPrompt, "DETECTION PROCESS", "should have detection process")
+	assert.Contains(t, validationPrompt, "Read ALL test files", "should mention reading test files")
+	assert.Contains(t, validationPrompt, "search the PRODUCTION code", "should mention searching production code")
 
 	// Check for common lies
 	assert.Contains(t, ValidationTemplate, "COMMON LIES TO CATCH", "should have common lies section")
@@ -333,8 +364,8 @@ func TestTemplateLineBreaks(t *testing.T) {
 	}{
 		{"ImplFirstTemplate", ImplFirstTemplate, 40},
 		{"ImplContinueTemplate", ImplContinueTemplate, 25},
-		{"ValidationTemplate", ValidationTemplate, 100},
-		{"InadmissibleRules", InadmissibleRules, 60},
+		{"ValidationTemplate", validationPrompt, 100},
+		{"InadmissibleRules", inadmissibleRules, 60},
 		{"EvidenceRules", EvidenceRules, 10},
 		{"PlaywrightRules", PlaywrightRules, 20},
 	}
@@ -352,8 +383,8 @@ func TestTemplateLineBreaks(t *testing.T) {
 // template provides both positive and negative examples for each practice.
 func TestInadmissibleExamplesCompleteness(t *testing.T) {
 	// Should have examples showing both wrong and right approaches
-	wrongCount := strings.Count(InadmissibleRules, "❌")
-	rightCount := strings.Count(InadmissibleRules, "✅")
+	wrongCount := strings.Count(inadmissibleRules, "❌")
+	rightCount := strings.Count(inadmissibleRules, "✅")
 
 	assert.Greater(t, wrongCount, 0, "should have wrong examples marked with ❌")
 	assert.Greater(t, rightCount, 0, "should have right examples marked with ✅")
@@ -366,15 +397,15 @@ func TestInadmissibleExamplesCompleteness(t *testing.T) {
 // includes a detailed detection process for inadmissible practices.
 func TestValidationDetectionProcess(t *testing.T) {
 	// Should have numbered or lettered steps
-	assert.Contains(t, ValidationTemplate, "a.", "detection process should have step a")
-	assert.Contains(t, ValidationTemplate, "b.", "detection process should have step b")
-	assert.Contains(t, ValidationTemplate, "c.", "detection process should have step c")
-	assert.Contains(t, ValidationTemplate, "d.", "detection process should have step d")
+	assert.Contains(t, validationPrompt, "a.", "detection process should have step a")
+	assert.Contains(t, validationPrompt, "b.", "detection process should have step b")
+	assert.Contains(t, validationPrompt, "c.", "detection process should have step c")
+	assert.Contains(t, validationPrompt, "d.", "detection process should have step d")
 
 	// Should mention specific things to check
-	assert.Contains(t, ValidationTemplate, "Read ALL test files", "should mention reading test files")
-	assert.Contains(t, ValidationTemplate, "identify what functionality", "should mention identifying functionality")
-	assert.Contains(t, ValidationTemplate, "search the PRODUCTION code", "should mention searching production")
+	assert.Contains(t, validationPrompt, "Read ALL test files", "should mention reading test files")
+	assert.Contains(t, validationPrompt, "identify what functionality", "should mention identifying functionality")
+	assert.Contains(t, validationPrompt, "search the PRODUCTION code", "should mention searching production")
 }
 
 // TestTemplateEmphasizes verifies that templates use emphasis appropriately
@@ -35,6 +35,11 @@ type RateLimitInfo struct {
 
 	// Timezone is the IANA timezone string
 	Timezone string
+
+	// Overloaded indicates a temporary capacity error (HTTP 529/503,
+	// "overloaded") rather than a usage limit. There is no reset time;
+	// callers back off briefly instead of waiting for a reset.
+	Overloaded bool
 }
 
 var (
@@ -50,13 +55,22 @@ var (
 	// Pattern 4: "resets Jan 1, 2026, 9am (UTC)" or "resets January 15, 2026, 3:30pm (America/Bahia)"
 	pattern4 = regexp.MustCompile(`(?i)resets?\s+[A-Za-z]+\s+\d{1,2},?\s+\d{4},?\s+(\d{1,2}(?::\d{2})?\s*(?:am|pm))\s*\(([^)]+)\)`)
 
+	// Pattern 5 (codex): "try again at 3:45 PM", in the local timezone.
+	// Only checked in short content, like the bare patterns.
+	pattern5 = regexp.MustCompile(`(?i)try again at\s+(\d{1,2}(?::\d{2})?\s*(?:am|pm))`)
+
 	// Bare detection patterns (no parseable time)
 	barePatterns = []string{
 		`you'?ve hit your limit`,
+		`you'?ve hit your usage limit`,
 		`rate limit exceeded`,
 		`rate limited`,
 		`too many requests`,
 	}
+
+	// Overload patterns: the API is temporarily out of capacity. Like bare
+	// patterns they are only checked in short content.
+	overloadPattern = regexp.MustCompile(`(?i)(overloaded_error|\boverloaded\b|\b529\b|503 service unavailable|server is (?:currently )?busy)`)
 )
 
 // FindRateLimitPattern searches for rate limit patterns in content.
@@ -74,6 +88,9 @@ func FindRateLimitPattern(content string) (timeStr, tzStr string, detected bool)
 
 	// Only check bare patterns for short content to avoid false positives
 	if len(content) <= BarePatternMaxContentSize {
+		if match := pattern5.FindStringSubmatch(content); match != nil {
+			return strings.TrimSpace(match[1]), "Local", true
+		}
 		for _, barePattern := range barePatterns {
 			matched, _ := regexp.MatchString("(?i)"+barePattern, content)
 			if matched {
@@ -85,6 +102,12 @@ func FindRateLimitPattern(content string) (timeStr, tzStr string, detected bool)
 	return "", "", false
 }
 
+// FindOverloadPattern reports whether short content is an API overload
+// error. Longer content is ignored to avoid matching the AI's own text.
+func FindOverloadPattern(content string) bool {
+	return len(content) <= BarePatternMaxContentSize && overloadPattern.MatchString(content)
+}
+
 // ParseTimeWithTimezone parses a time string in the given timezone and converts to epoch.
 // Returns (epoch, human, tz, error).
 func ParseTimeWithTimezone(timeStr, tzStr string) (epoch int64, human, tz string, err error) {
@@ -180,6 +203,8 @@ func ParseTimeWithTimezone(timeStr, tzStr string) (epoch int64, human, tz string
 
 // CheckRateLimit reads a file and checks for rate limit patterns.
 // Returns nil if no rate limit detected.
+// Returns RateLimitInfo with Detected=true, Overloaded=true if the API was
+// overloaded.
 // Returns RateLimitInfo with Detected=true, Parseable=false if rate limit found but unparseable.
 // Returns RateLimitInfo with Detected=true, Parseable=true and timing info if fully parsed.
 func CheckRateLimit(filePath string) (*RateLimitInfo, error) {
@@ -191,6 +216,9 @@ func CheckRateLimit(filePath string) (*RateLimitInfo, error) {
 	timeStr, tzStr, detected := FindRateLimitPattern(string(content))
 
 	if !detected {
+		if FindOverloadPattern(string(content)) {
+			return &RateLimitInfo{Detected: true, Overloaded: true}, nil
+		}
 		return nil, nil
 	}
 
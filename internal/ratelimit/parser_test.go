@@ -66,6 +66,7 @@ func TestFindRateLimitPattern_BarePatterns(t *testing.T) {
 		{"too many requests", "too many requests, try again later"},
 		{"hit your limit", "you've hit your limit"},
 		{"hit limit no apostrophe", "youve hit your limit"},
+		{"codex usage limit", "You've hit your usage limit. Upgrade to Pro or try later."},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -84,6 +85,28 @@ func TestFindRateLimitPattern_BarePatternSkippedForLargeContent(t *testing.T) {
 	assert.False(t, detected, "should not trigger bare pattern on large content")
 }
 
+func TestFindRateLimitPattern_CodexTryAgainAt(t *testing.T) {
+	timeStr, tzStr, detected := FindRateLimitPattern("You've hit your usage limit. Upgrade to Pro or try again at 3:45 PM.")
+	assert.True(t, detected)
+	assert.Equal(t, "3:45 PM", timeStr)
+	assert.Equal(t, "Local", tzStr)
+
+	_, _, detected = FindRateLimitPattern("Please try again at 3pm." + strings.Repeat(" ", BarePatternMaxContentSize))
+	assert.False(t, detected, "ignored in long content")
+}
+
+func TestFindOverloadPattern(t *testing.T) {
+	for _, content := range []string{
+		`API Error: 529 {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`,
+		"stream error: 503 Service Unavailable",
+		"The server is currently busy, please retry",
+	} {
+		assert.True(t, FindOverloadPattern(content), content)
+	}
+	assert.False(t, FindOverloadPattern("All tests pass."))
+	assert.False(t, FindOverloadPattern("overloaded"+strings.Repeat(" ", BarePatternMaxContentSize)))
+}
+
 func TestFindRateLimitPattern_NoMatch(t *testing.T) {
 	content := "Everything is working fine, no issues here."
 	_, _, detected := FindRateLimitPattern(content)
@@ -329,6 +352,18 @@ func TestCheckRateLimit_BarePatternDetected(t *testing.T) {
 	assert.False(t, info.Parseable, "bare pattern should not be parseable")
 }
 
+func TestCheckRateLimit_Overloaded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.txt")
+	require.NoError(t, os.WriteFile(path, []byte("API Error: 529 Overloaded"), 0644))
+
+	info, err := CheckRateLimit(path)
+	require.NoError(t, err)
+	require.NotNil(t, info)
+	assert.True(t, info.Detected)
+	assert.True(t, info.Overloaded)
+	assert.False(t, info.Parseable)
+}
+
 func TestCheckRateLimit_UnparseableTimezone(t *testing.T) {
 	tmpDir := t.TempDir()
 	filePath := filepath.Join(tmpDir, "output.txt")
@@ -0,0 +1,140 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// Size and margin of the burn-down chart, in pixels.
+const (
+	chartWidth  = 640
+	chartHeight = 240
+	chartMargin = 32
+)
+
+// chart is the burn-down chart: the tasks remaining after each iteration.
+type chart struct {
+	Width, Height int
+	Left, Bottom  int    // the axes' origin
+	Points        string // SVG polyline points
+	Dots          []dot
+	MaxTasks      int
+}
+
+type dot struct {
+	X, Y      int
+	Iteration int
+	Remaining int
+}
+
+// burnDown lays out the chart of r's iterations.
+func burnDown(r Report) chart {
+	c := chart{Width: chartWidth, Height: chartHeight, Left: chartMargin, Bottom: chartHeight - chartMargin, MaxTasks: r.Total}
+	for _, it := range r.Iterations {
+		c.MaxTasks = max(c.MaxTasks, it.Total)
+	}
+	if len(r.Iterations) == 0 || c.MaxTasks == 0 {
+		return c
+	}
+	plotW, plotH := chartWidth-2*chartMargin, chartHeight-2*chartMargin
+	points := make([]string, 0, len(r.Iterations))
+	for i, it := range r.Iterations {
+		x := c.Left
+		if len(r.Iterations) > 1 {
+			x += i * plotW / (len(r.Iterations) - 1)
+		}
+		y := c.Bottom - it.Remaining()*plotH/c.MaxTasks
+		points = append(points, fmt.Sprintf("%d,%d", x, y))
+		c.Dots = append(c.Dots, dot{X: x, Y: y, Iteration: it.Number, Remaining: it.Remaining()})
+	}
+	c.Points = strings.Join(points, " ")
+	return c
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"cost":  func(c float64) string { return fmt.Sprintf("$%.2f", c) },
+	"lower": strings.ToLower,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>ralph-loop report: {{.R.SessionID}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 60em; color: #1f2328; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #d0d7de; padding: 0.3em 0.7em; text-align: left; }
+th { background: #f6f8fa; }
+.verdict { font-weight: 600; }
+.complete { color: #1a7f37; }
+.needs_more_work { color: #9a6700; }
+.inadmissible, .escalate, .blocked { color: #cf222e; }
+svg text { font-size: 11px; fill: #57606a; }
+</style>
+</head>
+<body>
+<h1>ralph-loop report: {{.R.SessionID}}</h1>
+<p>Tasks: <code>{{.R.TasksFile}}</code>, status {{.R.Status}}, started {{.R.StartedAt}}, updated {{.R.LastUpdated}}.</p>
+<p><strong>{{.R.Done}}/{{.R.Total}} tasks done ({{.R.Percent}}%)</strong> after {{len .R.Iterations}} judged iteration(s){{if .R.HasCost}}, cost {{cost .R.Cost}}{{end}}.</p>
+{{- if .R.Exit}}
+<p>Exit: {{.R.Exit}}</p>
+{{- end}}
+
+<h2>Burn-down</h2>
+{{- if .R.Iterations}}
+<svg width="{{.Chart.Width}}" height="{{.Chart.Height}}" role="img" aria-label="Tasks remaining per iteration">
+<line x1="{{.Chart.Left}}" y1="{{.Chart.Bottom}}" x2="{{.Chart.Width}}" y2="{{.Chart.Bottom}}" stroke="#8c959f"/>
+<line x1="{{.Chart.Left}}" y1="0" x2="{{.Chart.Left}}" y2="{{.Chart.Bottom}}" stroke="#8c959f"/>
+<text x="2" y="12">{{.Chart.MaxTasks}}</text>
+<text x="2" y="{{.Chart.Bottom}}">0</text>
+<polyline points="{{.Chart.Points}}" fill="none" stroke="#0969da" stroke-width="2"/>
+{{- range .Chart.Dots}}
+<circle cx="{{.X}}" cy="{{.Y}}" r="3" fill="#0969da"><title>iteration {{.Iteration}}: {{.Remaining}} left</title></circle>
+<text x="{{.X}}" y="{{$.Chart.Height}}" text-anchor="middle" dy="-12">{{.Iteration}}</text>
+{{- end}}
+</svg>
+<table>
+<tr><th>Iteration</th><th>Verdict</th><th>Done</th><th>Remaining</th><th>Completed</th><th>Duration</th><th>Cost</th></tr>
+{{- range .R.Iterations}}
+<tr><td>{{.Number}}</td><td class="verdict {{lower .Verdict}}">{{.Verdict}}</td><td>{{.Done}}/{{.Total}}</td><td>{{.Remaining}}</td><td>+{{.Completed}}</td><td>{{.Duration}}</td><td>{{if .HasCost}}{{cost .Cost}}{{else}}n/a{{end}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No iteration was judged yet.</p>
+{{- end}}
+{{- if .R.Blocked}}
+
+<h2>Blocked tasks</h2>
+<ul>
+{{- range .R.Blocked}}
+<li><strong>{{.ID}}</strong> {{.Text}}{{if .Reason}} ({{.Reason}}){{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .R.Learnings}}
+
+<h2>Learnings</h2>
+<ul>
+{{- range .R.Learnings}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`))
+
+// WriteHTML writes the report as a standalone HTML page with an SVG
+// burn-down chart.
+func WriteHTML(w io.Writer, r Report) error {
+	entries := make([]string, len(r.Learnings))
+	for i, l := range r.Learnings {
+		entries[i] = strings.TrimPrefix(strings.TrimSpace(l), "- ")
+	}
+	r.Learnings = entries
+	return htmlTemplate.Execute(w, struct {
+		R     Report
+		Chart chart
+	}{r, burnDown(r)})
+}
@@ -0,0 +1,195 @@
+// Package report renders the burn-down report of a ralph-loop session:
+// the tasks left after every iteration, the verdicts, the cost, the
+// blocked tasks and the learnings, as markdown or as a standalone HTML
+// page to attach to a pull request or share with a team.
+package report
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/learnings"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// barWidth is the width of the longest remaining-tasks bar in markdown.
+const barWidth = 20
+
+// Report is what the burn-down report shows of a session.
+type Report struct {
+	SessionID   string
+	TasksFile   string
+	Status      string
+	StartedAt   string
+	LastUpdated string
+	Exit        string // how the last run ended, e.g. "Success: all tasks done"; "" while running
+
+	Done, Total int
+	Iterations  []Iteration
+	// Cost is the reported cost of the whole session (Claude only);
+	// HasCost is false when none was reported.
+	Cost    float64
+	HasCost bool
+
+	Blocked   []BlockedTask
+	Learnings []string
+}
+
+// Iteration is one judged iteration of the burn-down.
+type Iteration struct {
+	Number      int
+	Verdict     string
+	Done, Total int
+	Completed   int
+	Duration    time.Duration
+	Cost        float64
+	HasCost     bool
+}
+
+// Remaining is the number of tasks left after the iteration.
+func (it Iteration) Remaining() int {
+	return it.Total - it.Done
+}
+
+// BlockedTask is a task the session gave up on, and why.
+type BlockedTask struct {
+	ID, Text, Reason string
+}
+
+// Build gathers the report of session s, whose state, iteration
+// directories and learnings live under stateDir.
+func Build(s *state.SessionState, stateDir string) Report {
+	r := Report{
+		SessionID:   s.SessionID,
+		TasksFile:   s.TasksFile,
+		Status:      s.Status,
+		StartedAt:   s.StartedAt,
+		LastUpdated: s.LastUpdated,
+	}
+	if s.Exit != nil {
+		r.Exit = s.Exit.Name
+		if s.Exit.Reason != "" {
+			r.Exit += ": " + s.Exit.Reason
+		}
+	}
+
+	for _, h := range s.History {
+		it := Iteration{
+			Number:    h.Iteration,
+			Verdict:   h.Verdict,
+			Done:      h.Done,
+			Total:     h.Total,
+			Completed: h.Completed,
+			Duration:  time.Duration(h.Seconds) * time.Second,
+		}
+		it.Cost, it.HasCost = state.SessionCost(filepath.Join(stateDir, fmt.Sprintf("iteration-%03d", h.Iteration)))
+		r.Iterations = append(r.Iterations, it)
+	}
+	r.Cost, r.HasCost = state.SessionCost(stateDir)
+
+	done, errDone := tasks.CountChecked(s.TasksFile)
+	remaining, errLeft := tasks.CountUnchecked(s.TasksFile)
+	switch {
+	case errDone == nil && errLeft == nil:
+		r.Done, r.Total = done, done+remaining
+	case len(r.Iterations) > 0:
+		last := r.Iterations[len(r.Iterations)-1]
+		r.Done, r.Total = last.Done, last.Total
+	}
+
+	for _, t := range s.Tasks {
+		if t.Status == state.TaskBlocked {
+			r.Blocked = append(r.Blocked, BlockedTask{ID: t.ID, Text: t.Text, Reason: t.BlockedReason})
+		}
+	}
+	if s.Learnings.File != "" {
+		r.Learnings = learnings.Entries(learnings.ReadLearnings(s.Learnings.File))
+	}
+	return r
+}
+
+// Percent is the share of the tasks that are done, 0 to 100.
+func (r Report) Percent() int {
+	if r.Total <= 0 {
+		return 0
+	}
+	return r.Done * 100 / r.Total
+}
+
+// WriteMarkdown writes the report as markdown.
+func WriteMarkdown(w io.Writer, r Report) {
+	fmt.Fprintf(w, "# ralph-loop report: %s\n\n", r.SessionID)
+	fmt.Fprintf(w, "Tasks: `%s`, status %s, started %s, updated %s.\n\n", r.TasksFile, r.Status, r.StartedAt, r.LastUpdated)
+	fmt.Fprintf(w, "**%d/%d tasks done (%d%%)** after %d judged iteration(s)", r.Done, r.Total, r.Percent(), len(r.Iterations))
+	if r.HasCost {
+		fmt.Fprintf(w, ", cost $%.2f", r.Cost)
+	}
+	fmt.Fprintln(w, ".")
+	if r.Exit != "" {
+		fmt.Fprintf(w, "\nExit: %s\n", r.Exit)
+	}
+
+	fmt.Fprintln(w, "\n## Burn-down")
+	if len(r.Iterations) == 0 {
+		fmt.Fprintln(w, "\nNo iteration was judged yet.")
+	} else {
+		maxTotal := 0
+		for _, it := range r.Iterations {
+			maxTotal = max(maxTotal, it.Total)
+		}
+		fmt.Fprintln(w, "\n| Iteration | Verdict | Done | Remaining | Completed | Duration | Cost |")
+		fmt.Fprintln(w, "|---|---|---|---|---|---|---|")
+		for _, it := range r.Iterations {
+			cost := "n/a"
+			if it.HasCost {
+				cost = fmt.Sprintf("$%.2f", it.Cost)
+			}
+			fmt.Fprintf(w, "| %d | %s | %d/%d | %s %d | +%d | %s | %s |\n",
+				it.Number, orDash(it.Verdict), it.Done, it.Total, bar(it.Remaining(), maxTotal), it.Remaining(),
+				it.Completed, it.Duration, cost)
+		}
+	}
+
+	if len(r.Blocked) > 0 {
+		fmt.Fprintln(w, "\n## Blocked tasks")
+		fmt.Fprintln(w)
+		for _, t := range r.Blocked {
+			line := fmt.Sprintf("- **%s** %s", t.ID, t.Text)
+			if t.Reason != "" {
+				line += " (" + t.Reason + ")"
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+
+	if len(r.Learnings) > 0 {
+		fmt.Fprintln(w, "\n## Learnings")
+		fmt.Fprintln(w)
+		for _, l := range r.Learnings {
+			if !strings.HasPrefix(strings.TrimSpace(l), "-") {
+				l = "- " + l
+			}
+			fmt.Fprintln(w, l)
+		}
+	}
+}
+
+// bar draws n out of max as a bar of up to barWidth blocks.
+func bar(n, max int) string {
+	if n <= 0 || max <= 0 {
+		return ""
+	}
+	width := (n*barWidth + max - 1) / max
+	return strings.Repeat("█", width)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
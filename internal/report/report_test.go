@@ -0,0 +1,109 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// testSession returns a session of two judged iterations over three
+// tasks, with costs, a blocked task and learnings, and its state dir.
+func testSession(t *testing.T) (*state.SessionState, string) {
+	t.Helper()
+	dir := t.TempDir()
+	stateDir := filepath.Join(dir, ".ralph-loop")
+	tasksFile := filepath.Join(dir, "tasks.md")
+	learningsFile := filepath.Join(stateDir, "learnings.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [x] T001 One\n- [x] T002 Two\n- [ ] T003 Three\n"), 0644))
+	for iteration, cost := range map[string]string{"iteration-001": "0.5", "iteration-002": "0.25"} {
+		iterDir := filepath.Join(stateDir, iteration)
+		require.NoError(t, os.MkdirAll(iterDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(iterDir, "implementation-output.txt.stream.json"),
+			[]byte(`{"type":"result","total_cost_usd":`+cost+"}\n"), 0644))
+	}
+	require.NoError(t, os.WriteFile(learningsFile, []byte("# Ralph Loop Learnings\n\n## Iteration 1 (2026-01-30 15:30:45)\n\n- Run go generate before building\n"), 0644))
+
+	return &state.SessionState{
+		SessionID:   "s1",
+		TasksFile:   tasksFile,
+		Status:      state.StatusInProgress,
+		StartedAt:   "2026-01-30T15:00:00Z",
+		LastUpdated: "2026-01-30T16:00:00Z",
+		Learnings:   state.LearningsState{Enabled: 1, File: learningsFile},
+		History: []state.IterationRecord{
+			{Iteration: 1, Verdict: "NEEDS_MORE_WORK", Seconds: 600, Completed: 1, Done: 1, Total: 3},
+			{Iteration: 2, Verdict: "BLOCKED", Seconds: 90, Completed: 1, Done: 2, Total: 3},
+		},
+		Tasks: []state.TaskState{
+			{ID: "T002", Text: "Two", Status: state.TaskDone},
+			{ID: "T003", Text: "Three", Status: state.TaskBlocked, BlockedReason: "needs an API key"},
+		},
+		Exit: &state.ExitState{Code: 3, Name: "Blocked", Reason: "1 task(s) blocked: T003"},
+	}, stateDir
+}
+
+func TestBuild(t *testing.T) {
+	s, stateDir := testSession(t)
+	r := Build(s, stateDir)
+
+	assert.Equal(t, 2, r.Done)
+	assert.Equal(t, 3, r.Total)
+	assert.Equal(t, 66, r.Percent())
+	assert.Equal(t, "Blocked: 1 task(s) blocked: T003", r.Exit)
+	require.Len(t, r.Iterations, 2)
+	assert.Equal(t, 2, r.Iterations[0].Remaining())
+	assert.True(t, r.Iterations[0].HasCost)
+	assert.InDelta(t, 0.5, r.Iterations[0].Cost, 1e-9)
+	assert.InDelta(t, 0.75, r.Cost, 1e-9)
+	assert.Equal(t, []BlockedTask{{ID: "T003", Text: "Three", Reason: "needs an API key"}}, r.Blocked)
+	assert.Equal(t, []string{"- Run go generate before building"}, r.Learnings)
+
+	// Without the tasks file, the last iteration's counts are used.
+	s.TasksFile = filepath.Join(t.TempDir(), "missing.md")
+	r = Build(s, stateDir)
+	assert.Equal(t, 2, r.Done)
+	assert.Equal(t, 3, r.Total)
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	s, stateDir := testSession(t)
+	var buf bytes.Buffer
+	WriteMarkdown(&buf, Build(s, stateDir))
+	out := buf.String()
+
+	assert.Contains(t, out, "# ralph-loop report: s1")
+	assert.Contains(t, out, "**2/3 tasks done (66%)** after 2 judged iteration(s), cost $0.75.")
+	assert.Contains(t, out, "Exit: Blocked: 1 task(s) blocked: T003")
+	assert.Contains(t, out, "| 1 | NEEDS_MORE_WORK | 1/3 | "+strings.Repeat("█", 14)+" 2 | +1 | 10m0s | $0.50 |")
+	assert.Contains(t, out, "| 2 | BLOCKED | 2/3 | "+strings.Repeat("█", 7)+" 1 | +1 | 1m30s | $0.25 |")
+	assert.Contains(t, out, "- **T003** Three (needs an API key)")
+	assert.Contains(t, out, "## Learnings\n\n- Run go generate before building\n")
+
+	buf.Reset()
+	WriteMarkdown(&buf, Report{SessionID: "s2"})
+	assert.Contains(t, buf.String(), "No iteration was judged yet.")
+	assert.NotContains(t, buf.String(), "## Blocked tasks")
+}
+
+func TestWriteHTML(t *testing.T) {
+	s, stateDir := testSession(t)
+	s.Tasks[1].Text = "Handle <script> tags"
+	r := Build(s, stateDir)
+	var buf bytes.Buffer
+	require.NoError(t, WriteHTML(&buf, r))
+	out := buf.String()
+
+	assert.Contains(t, out, "<title>ralph-loop report: s1</title>")
+	assert.Contains(t, out, `<polyline points="32,91 608,150"`)
+	assert.Contains(t, out, `<td class="verdict needs_more_work">NEEDS_MORE_WORK</td>`)
+	assert.Contains(t, out, "Handle &lt;script&gt; tags", "task text is escaped")
+	assert.Contains(t, out, "<li>Run go generate before building</li>")
+	assert.Equal(t, "- Run go generate before building", r.Learnings[0], "the report is left as it was")
+}
@@ -0,0 +1,166 @@
+// Package rollback returns a session and its working tree to where they
+// stood after an earlier iteration, so a run of bad iterations can be
+// undone and the loop resumed from there with --resume.
+//
+// Each iteration saves a rollback point as it starts (see
+// state.IterationStart): the session and a gitdiff snapshot of the working
+// tree. Rolling back to iteration N restores the point saved by iteration
+// N+1 and moves the directories of the undone iterations aside.
+package rollback
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/gitdiff"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// Options configures Run.
+type Options struct {
+	StateDir string
+	// WorkDir is the project root; empty means the current directory.
+	WorkDir string
+	// To is the iteration to return to; every later one is undone. 0
+	// undoes every iteration.
+	To int
+	// KeepFiles rewinds the session only and leaves the working tree as
+	// it is.
+	KeepFiles bool
+}
+
+// Result describes a rollback.
+type Result struct {
+	From    int                 // the session's iteration before the rollback
+	Session *state.SessionState // the rewound session
+	// Restored lists the files put back, relative to the project root.
+	Restored []string
+	// Tree is the snapshot of the working tree before the rollback, from
+	// which `git checkout <tree> -- .` brings the undone changes back.
+	Tree string
+	// Backup is the directory the undone iterations were moved to.
+	Backup string
+}
+
+// record is written to the backup directory to say what was undone.
+type record struct {
+	From int    `json:"from_iteration"`
+	To   int    `json:"to_iteration"`
+	Tree string `json:"tree,omitempty"`
+	At   string `json:"at"`
+}
+
+// Run rolls the session in opts.StateDir back to iteration opts.To. The
+// caller holds the state lock.
+func Run(opts Options) (*Result, error) {
+	s, err := state.LoadState(opts.StateDir)
+	if err != nil {
+		return nil, fmt.Errorf("no session to roll back: %w", err)
+	}
+	if opts.To < 0 || opts.To >= s.Iteration {
+		return nil, fmt.Errorf("cannot roll back to iteration %d: the session is at iteration %d, so pick 0 to %d", opts.To, s.Iteration, s.Iteration-1)
+	}
+	start, err := state.LoadIterationStart(iterDir(opts.StateDir, opts.To+1))
+	if err != nil {
+		return nil, fmt.Errorf("iteration %d left no rollback point: %w", opts.To+1, err)
+	}
+	res := &Result{From: s.Iteration}
+
+	if !opts.KeepFiles {
+		if start.Tree == "" {
+			return nil, fmt.Errorf("iteration %d has no snapshot of the working tree (not a git repository?); use --keep-files to rewind the session only", opts.To+1)
+		}
+		if res.Tree, res.Restored, err = restoreTree(opts, start.Tree); err != nil {
+			return nil, err
+		}
+	}
+
+	at := time.Now()
+	res.Backup = filepath.Join(opts.StateDir, "rolled-back-"+at.Format("20060102-150405"))
+	if err := os.MkdirAll(res.Backup, 0755); err != nil {
+		return nil, err
+	}
+	for n := opts.To + 1; n <= s.Iteration; n++ {
+		dir := iterDir(opts.StateDir, n)
+		if err := os.Rename(dir, filepath.Join(res.Backup, filepath.Base(dir))); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	data, err := json.MarshalIndent(record{From: s.Iteration, To: opts.To, Tree: res.Tree, At: at.Format(time.RFC3339)}, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(res.Backup, "rollback.json"), data, 0644); err != nil {
+		return nil, err
+	}
+
+	rewound := start.Session
+	rewound.Iteration = opts.To
+	rewound.Status = state.StatusInterrupted
+	rewound.Phase = state.PhaseImplementation
+	rewound.Checkpoint = nil
+	rewound.Exit = nil
+	rewound.ShutdownSignal = ""
+	rewound.LastUpdated = at.Format(time.RFC3339)
+	if hash, err := tasks.HashFile(rewound.TasksFile); err == nil {
+		rewound.TasksFileHash = hash
+	}
+	if err := state.SaveState(rewound, opts.StateDir); err != nil {
+		return nil, err
+	}
+	res.Session = rewound
+	return res, nil
+}
+
+// restoreTree puts the working tree back to the snapshot tree, leaving the
+// state directory alone, and returns a snapshot of the tree it replaced
+// and the files it changed.
+func restoreTree(opts Options, tree string) (string, []string, error) {
+	root := opts.WorkDir
+	if root == "" {
+		root = "."
+	}
+	var exclude []string
+	if rel, ok := inside(root, opts.StateDir); ok {
+		exclude = append(exclude, rel)
+	}
+	head, err := gitdiff.Snapshot(root, exclude...)
+	if err != nil {
+		return "", nil, fmt.Errorf("snapshot the working tree: %w", err)
+	}
+	changed, err := gitdiff.Changed(root, tree, head, exclude...)
+	if err != nil {
+		return "", nil, fmt.Errorf("compare with the rollback point: %w", err)
+	}
+	if err := gitdiff.Restore(root, tree, changed); err != nil {
+		return "", nil, fmt.Errorf("restore the working tree: %w", err)
+	}
+	return head, changed, nil
+}
+
+// iterDir returns the directory of iteration n.
+func iterDir(stateDir string, n int) string {
+	return filepath.Join(stateDir, fmt.Sprintf("iteration-%03d", n))
+}
+
+// inside returns path relative to root when it lies inside root.
+func inside(root, path string) (string, bool) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", false
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(absRoot, absPath)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
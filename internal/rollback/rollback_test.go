@@ -0,0 +1,130 @@
+package rollback
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/gitdiff"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// testSession builds a git repository whose session ran three iterations,
+// each of which saved a rollback point and then wrote iter<n>.go and
+// checked off task T00<n>.
+func testSession(t *testing.T) (root, stateDir string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root = t.TempDir()
+	stateDir = filepath.Join(root, ".ralph-loop")
+	tasksFile := filepath.Join(root, "tasks.md")
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0644))
+	}
+	write("main.go", "package main\n")
+	write("tasks.md", "- [ ] T001 one\n- [ ] T002 two\n- [ ] T003 three\n")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.email=t@example.com", "-c", "user.name=t", "commit", "-q", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	s := &state.SessionState{TasksFile: tasksFile, Status: state.StatusInProgress}
+	checked := []string{" ", " ", " "}
+	for n := 1; n <= 3; n++ {
+		s.Iteration = n
+		s.Phase = state.PhaseImplementation
+		s.LastFeedback = fmt.Sprintf("feedback before %d", n)
+		tree, err := gitdiff.Snapshot(root, ".ralph-loop")
+		require.NoError(t, err)
+		require.NoError(t, state.SaveIterationStart(filepath.Join(stateDir, fmt.Sprintf("iteration-%03d", n)), state.IterationStart{Tree: tree, Session: s}))
+
+		write(fmt.Sprintf("iter%d.go", n), "package main\n")
+		checked[n-1] = "x"
+		write("tasks.md", fmt.Sprintf("- [%s] T001 one\n- [%s] T002 two\n- [%s] T003 three\n", checked[0], checked[1], checked[2]))
+		s.History = append(s.History, state.IterationRecord{Iteration: n, Verdict: "NEEDS_MORE_WORK"})
+	}
+	s.Phase = state.PhaseValidation
+	s.Checkpoint = &state.Checkpoint{}
+	require.NoError(t, state.SaveState(s, stateDir))
+	return root, stateDir
+}
+
+func TestRun_RestoresFilesAndSession(t *testing.T) {
+	root, stateDir := testSession(t)
+
+	res, err := Run(Options{StateDir: stateDir, WorkDir: root, To: 1})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, res.From)
+	assert.ElementsMatch(t, []string{"iter2.go", "iter3.go", "tasks.md"}, res.Restored)
+	assert.NotEmpty(t, res.Tree)
+	assert.FileExists(t, filepath.Join(root, "iter1.go"))
+	assert.NoFileExists(t, filepath.Join(root, "iter2.go"))
+	assert.NoFileExists(t, filepath.Join(root, "iter3.go"))
+	data, err := os.ReadFile(filepath.Join(root, "tasks.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "- [x] T001 one\n- [ ] T002 two\n- [ ] T003 three\n", string(data))
+
+	s, err := state.LoadState(stateDir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, s.Iteration)
+	assert.Equal(t, state.StatusInterrupted, s.Status)
+	assert.Equal(t, state.PhaseImplementation, s.Phase)
+	assert.Equal(t, "feedback before 2", s.LastFeedback)
+	assert.Len(t, s.History, 1)
+	assert.Nil(t, s.Checkpoint)
+	assert.NotEmpty(t, s.TasksFileHash)
+
+	assert.DirExists(t, filepath.Join(stateDir, "iteration-001"))
+	assert.NoDirExists(t, filepath.Join(stateDir, "iteration-002"))
+	assert.DirExists(t, filepath.Join(res.Backup, "iteration-002"))
+	assert.DirExists(t, filepath.Join(res.Backup, "iteration-003"))
+	assert.FileExists(t, filepath.Join(res.Backup, "rollback.json"))
+}
+
+func TestRun_KeepFiles(t *testing.T) {
+	root, stateDir := testSession(t)
+
+	res, err := Run(Options{StateDir: stateDir, WorkDir: root, To: 0, KeepFiles: true})
+	require.NoError(t, err)
+
+	assert.Empty(t, res.Restored)
+	assert.FileExists(t, filepath.Join(root, "iter3.go"))
+	assert.Equal(t, 0, res.Session.Iteration)
+	assert.Empty(t, res.Session.History)
+}
+
+func TestRun_RejectsOutOfRange(t *testing.T) {
+	root, stateDir := testSession(t)
+
+	for _, to := range []int{-1, 3, 7} {
+		_, err := Run(Options{StateDir: stateDir, WorkDir: root, To: to})
+		assert.Error(t, err, "to %d", to)
+	}
+	s, err := state.LoadState(stateDir)
+	require.NoError(t, err)
+	assert.Equal(t, 3, s.Iteration)
+}
+
+func TestRun_NoRollbackPoint(t *testing.T) {
+	root, stateDir := testSession(t)
+	require.NoError(t, os.Remove(filepath.Join(stateDir, "iteration-002", state.IterationStartFile)))
+
+	_, err := Run(Options{StateDir: stateDir, WorkDir: root, To: 1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no rollback point")
+	assert.FileExists(t, filepath.Join(root, "iter3.go"))
+}
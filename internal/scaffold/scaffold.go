@@ -0,0 +1,272 @@
+// Package scaffold writes the starter files of a ralph-loop project: the
+// .ralph-loop/config file, tasks.md and plan.md skeletons, and a .gitignore
+// entry that keeps session state out of the repository.
+package scaffold
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/learnings"
+	"github.com/CodexForgeBR/cli-tools/internal/model"
+)
+
+var (
+	//go:embed templates/config.txt
+	configTemplate string
+
+	//go:embed templates/tasks.md
+	tasksTemplate string
+
+	//go:embed templates/plan.md
+	planTemplate string
+)
+
+// Paths of the scaffolded files, relative to the project root.
+const (
+	ConfigPath    = ".ralph-loop/config"
+	TasksPath     = "tasks.md"
+	PlanPath      = "plan.md"
+	GitignorePath = ".gitignore"
+)
+
+// gitignoreEntry ignores the session state but keeps the project config
+// and learnings, which are worth sharing.
+const gitignoreEntry = `# ralph-loop session state
+.ralph-loop/*
+!.ralph-loop/config
+!.ralph-loop/learnings.md
+`
+
+// testCommands maps a language tag to the command that runs its tests.
+var testCommands = map[string]string{
+	"go":         "`go test ./...`",
+	"python":     "`pytest`",
+	"javascript": "`npm test`",
+	"typescript": "`npm test`",
+	"rust":       "`cargo test`",
+}
+
+// languages lists the language tags DetectLanguage picks from, preferred
+// first: a TypeScript project also has a package.json.
+var languages = []string{"go", "typescript", "javascript", "python", "rust", "java", "kotlin", "ruby", "php", "elixir", "swift"}
+
+// Options are the answers that shape the scaffolded files.
+type Options struct {
+	// Language is the project's language tag (e.g. "go"), used for
+	// LEARNINGS_TAGS and the test task. Empty leaves both generic.
+	Language string
+	// AI is the AI CLI, one of model.Providers.
+	AI string
+	// NotifyChannel and NotifyChatID set the notification target; an empty
+	// chat ID leaves notifications off.
+	NotifyChannel string
+	NotifyChatID  string
+}
+
+// Result lists, relative to the project root, the files Init wrote and the
+// existing ones it left alone.
+type Result struct {
+	Written []string
+	Skipped []string
+}
+
+// DetectLanguage returns the language of the project at root, or "" when
+// none is detected.
+func DetectLanguage(root string) string {
+	detected := map[string]bool{}
+	for _, tag := range learnings.DetectTags(root) {
+		detected[tag] = true
+	}
+	for _, lang := range languages {
+		if detected[lang] {
+			return lang
+		}
+	}
+	return ""
+}
+
+// Init writes the starter files under root. Existing config, tasks and plan
+// files are kept unless force is set; the .gitignore entry is appended
+// only when the file does not already mention .ralph-loop.
+func Init(root string, opts Options, force bool) (Result, error) {
+	if !model.IsProvider(opts.AI) {
+		return Result{}, unknownAIError(opts.AI)
+	}
+
+	var res Result
+	files := []struct {
+		path, content string
+	}{
+		{ConfigPath, renderConfig(opts)},
+		{TasksPath, render(tasksTemplate, root, opts)},
+		{PlanPath, render(planTemplate, root, opts)},
+	}
+	for _, f := range files {
+		path := filepath.Join(root, filepath.FromSlash(f.path))
+		if _, err := os.Stat(path); err == nil && !force {
+			res.Skipped = append(res.Skipped, f.path)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return res, fmt.Errorf("create %s: %w", filepath.Dir(f.path), err)
+		}
+		if err := os.WriteFile(path, []byte(f.content), 0644); err != nil {
+			return res, fmt.Errorf("write %s: %w", f.path, err)
+		}
+		res.Written = append(res.Written, f.path)
+	}
+
+	added, err := addGitignoreEntry(filepath.Join(root, GitignorePath))
+	if err != nil {
+		return res, err
+	}
+	if added {
+		res.Written = append(res.Written, GitignorePath)
+	} else {
+		res.Skipped = append(res.Skipped, GitignorePath)
+	}
+	return res, nil
+}
+
+// render fills the placeholders shared by the tasks and plan templates.
+func render(tmpl, root string, opts Options) string {
+	test := "the test suite"
+	if cmd, ok := testCommands[opts.Language]; ok {
+		test = cmd
+	}
+	return strings.NewReplacer(
+		"{{PROJECT}}", filepath.Base(root),
+		"{{TEST_COMMAND}}", test,
+	).Replace(tmpl)
+}
+
+// renderConfig fills the config template from opts.
+func renderConfig(opts Options) string {
+	var settings strings.Builder
+	if opts.Language != "" {
+		fmt.Fprintf(&settings, "LEARNINGS_TAGS=%s\n", opts.Language)
+	}
+	if opts.NotifyChatID != "" {
+		if opts.NotifyChannel != "" {
+			fmt.Fprintf(&settings, "NOTIFY_CHANNEL=%s\n", opts.NotifyChannel)
+		}
+		fmt.Fprintf(&settings, "NOTIFY_CHAT_ID=%s\n", opts.NotifyChatID)
+	}
+
+	return strings.NewReplacer(
+		"{{AI}}", opts.AI,
+		"{{IMPL_MODEL}}", model.DefaultImplModel(opts.AI),
+		"{{VAL_MODEL}}", model.DefaultValModel(opts.AI),
+		"{{CHEAP_MODEL}}", cheapModel(opts.AI),
+		"{{SETTINGS}}", settings.String(),
+	).Replace(configTemplate)
+}
+
+// cheapModel is the model of the scaffolded "cheap" profile.
+func cheapModel(ai string) string {
+	if ai == model.Claude {
+		return "sonnet"
+	}
+	return model.DefaultSummaryModel(ai)
+}
+
+func unknownAIError(ai string) error {
+	return fmt.Errorf("unknown AI CLI %q: use one of %s", ai, strings.Join(model.Providers, ", "))
+}
+
+// addGitignoreEntry appends gitignoreEntry to the .gitignore at path,
+// creating it, unless the file already mentions .ralph-loop. It reports
+// whether the entry was added.
+func addGitignoreEntry(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("read %s: %w", GitignorePath, err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "#") && strings.Contains(line, ".ralph-loop") {
+			return false, nil
+		}
+	}
+
+	entry := gitignoreEntry
+	if len(data) > 0 {
+		entry = "\n" + entry
+		if !strings.HasSuffix(string(data), "\n") {
+			entry = "\n" + entry
+		}
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return false, fmt.Errorf("open %s: %w", GitignorePath, err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(entry); err != nil {
+		return false, fmt.Errorf("write %s: %w", GitignorePath, err)
+	}
+	return true, nil
+}
+
+// Ask asks the init questions on w, reading the answers from r, and returns
+// opts updated with them. An empty answer keeps the value in opts, shown as
+// the default; "none" clears the notification target.
+func Ask(r io.Reader, w io.Writer, opts Options) (Options, error) {
+	in := bufio.NewReader(r)
+	eof := false
+	ask := func(question, def string) (string, error) {
+		if def != "" {
+			fmt.Fprintf(w, "%s [%s]: ", question, def)
+		} else {
+			fmt.Fprintf(w, "%s: ", question)
+		}
+		line, err := in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		if err == io.EOF {
+			// Input ended without a newline; finish the prompt's line.
+			fmt.Fprintln(w)
+			eof = true
+		}
+		if answer := strings.TrimSpace(line); answer != "" {
+			return answer, nil
+		}
+		return def, nil
+	}
+
+	var err error
+	if opts.Language, err = ask("Language (go, typescript, python, ...)", opts.Language); err != nil {
+		return opts, err
+	}
+	for {
+		ai, err := ask("AI CLI ("+strings.Join(model.Providers, ", ")+")", opts.AI)
+		if err != nil {
+			return opts, err
+		}
+		if model.IsProvider(ai) {
+			opts.AI = ai
+			break
+		}
+		if eof {
+			return opts, unknownAIError(ai)
+		}
+		fmt.Fprintf(w, "Please answer one of %s.\n", strings.Join(model.Providers, ", "))
+	}
+	if opts.NotifyChannel, err = ask("Notification channel (telegram, slack, ..., none)", opts.NotifyChannel); err != nil {
+		return opts, err
+	}
+	if opts.NotifyChannel == "none" {
+		opts.NotifyChannel, opts.NotifyChatID = "", ""
+		return opts, nil
+	}
+	if opts.NotifyChatID, err = ask("Notification chat ID (empty for no notifications)", opts.NotifyChatID); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
@@ -0,0 +1,131 @@
+package scaffold
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestInit_WritesFiles(t *testing.T) {
+	root := t.TempDir()
+	res, err := Init(root, Options{Language: "go", AI: "codex", NotifyChannel: "slack", NotifyChatID: "C123"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{ConfigPath, TasksPath, PlanPath, GitignorePath}, res.Written)
+	assert.Empty(t, res.Skipped)
+
+	settings, profiles, err := config.ParseFile(filepath.Join(root, ConfigPath))
+	require.NoError(t, err)
+	assert.Equal(t, "codex", settings["AI_CLI"])
+	assert.Equal(t, "go", settings["LEARNINGS_TAGS"])
+	assert.Equal(t, "slack", settings["NOTIFY_CHANNEL"])
+	assert.Equal(t, "C123", settings["NOTIFY_CHAT_ID"])
+	assert.Equal(t, "o4-mini", profiles["cheap"]["IMPL_MODEL"])
+	assert.Equal(t, "3", profiles["thorough"]["VALIDATORS"])
+
+	tasksPath := filepath.Join(root, TasksPath)
+	violations, err := tasks.CheckCompliance(tasksPath)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+	f, err := tasks.Parse(tasksPath)
+	require.NoError(t, err)
+	require.Len(t, f.Tasks, 2)
+	assert.Equal(t, []string{"T001"}, f.Tasks[1].Depends)
+	assert.Contains(t, readFile(t, tasksPath), "`go test ./...`")
+	assert.Contains(t, readFile(t, tasksPath), "# Tasks: "+filepath.Base(root))
+
+	assert.Contains(t, readFile(t, filepath.Join(root, PlanPath)), "## Requirements")
+	assert.Equal(t, gitignoreEntry, readFile(t, filepath.Join(root, GitignorePath)))
+}
+
+func TestInit_WithoutLanguageOrNotifications(t *testing.T) {
+	root := t.TempDir()
+	_, err := Init(root, Options{AI: "claude", NotifyChannel: "telegram"}, false)
+	require.NoError(t, err)
+
+	cfg := readFile(t, filepath.Join(root, ConfigPath))
+	assert.NotContains(t, cfg, "LEARNINGS_TAGS")
+	assert.NotContains(t, cfg, "NOTIFY_")
+	assert.Contains(t, cfg, "IMPL_MODEL=sonnet")
+	assert.Contains(t, readFile(t, filepath.Join(root, TasksPath)), "the test suite passes")
+}
+
+func TestInit_KeepsExistingFiles(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, TasksPath), []byte("- [ ] T001 Mine\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, GitignorePath), []byte("bin/"), 0644))
+
+	res, err := Init(root, Options{AI: "claude"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{ConfigPath, PlanPath, GitignorePath}, res.Written)
+	assert.Equal(t, []string{TasksPath}, res.Skipped)
+	assert.Equal(t, "- [ ] T001 Mine\n", readFile(t, filepath.Join(root, TasksPath)))
+	assert.Equal(t, "bin/\n\n"+gitignoreEntry, readFile(t, filepath.Join(root, GitignorePath)))
+
+	// A second run finds the .gitignore entry and every file in place.
+	res, err = Init(root, Options{AI: "claude"}, false)
+	require.NoError(t, err)
+	assert.Empty(t, res.Written)
+	assert.Len(t, res.Skipped, 4)
+
+	res, err = Init(root, Options{AI: "claude"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{ConfigPath, TasksPath, PlanPath}, res.Written)
+	assert.NotContains(t, readFile(t, filepath.Join(root, TasksPath)), "Mine")
+}
+
+func TestInit_UnknownAI(t *testing.T) {
+	root := t.TempDir()
+	_, err := Init(root, Options{AI: "gemini"}, false)
+	assert.ErrorContains(t, err, `unknown AI CLI "gemini"`)
+	assert.NoFileExists(t, filepath.Join(root, TasksPath))
+}
+
+func TestDetectLanguage(t *testing.T) {
+	root := t.TempDir()
+	assert.Equal(t, "", DetectLanguage(root))
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "package.json"), []byte(`{}`), 0644))
+	assert.Equal(t, "javascript", DetectLanguage(root))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "tsconfig.json"), []byte(`{}`), 0644))
+	assert.Equal(t, "typescript", DetectLanguage(root))
+}
+
+func TestAsk(t *testing.T) {
+	var out bytes.Buffer
+	opts, err := Ask(strings.NewReader("python\ngemini\ncodex\n\nC9\n"), &out, Options{Language: "go", AI: "claude", NotifyChannel: "telegram"})
+	require.NoError(t, err)
+	assert.Equal(t, Options{Language: "python", AI: "codex", NotifyChannel: "telegram", NotifyChatID: "C9"}, opts)
+	assert.Contains(t, out.String(), "Language (go, typescript, python, ...) [go]: ")
+	assert.Contains(t, out.String(), "Please answer one of claude, codex, amazonq, copilot.")
+}
+
+func TestAsk_NoneAndEOF(t *testing.T) {
+	opts, err := Ask(strings.NewReader("\n\nnone\n"), &bytes.Buffer{}, Options{AI: "claude", NotifyChannel: "telegram", NotifyChatID: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, Options{AI: "claude"}, opts)
+
+	// Input that ends early keeps the remaining defaults.
+	opts, err = Ask(strings.NewReader("rust"), &bytes.Buffer{}, Options{AI: "codex", NotifyChannel: "telegram"})
+	require.NoError(t, err)
+	assert.Equal(t, Options{Language: "rust", AI: "codex", NotifyChannel: "telegram"}, opts)
+}
+
+func TestAsk_InvalidAIAtEOF(t *testing.T) {
+	_, err := Ask(strings.NewReader("go\ngemini"), &bytes.Buffer{}, Options{AI: "claude"})
+	assert.ErrorContains(t, err, `unknown AI CLI "gemini"`)
+}
@@ -0,0 +1,148 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PauseWindow is a recurring time of day during which no new iteration
+// starts. Start and End are minutes after midnight; a window with End <=
+// Start runs past midnight into the next day. Days holds the weekdays the
+// window starts on.
+type PauseWindow struct {
+	Start int
+	End   int
+	Days  [7]bool
+}
+
+var dayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParsePauseWindows parses a PAUSE_BETWEEN value: one or more windows
+// separated by ';', each "HH:MM-HH:MM" optionally followed by the days it
+// applies to. Days are "daily" (the default), "weekdays", "weekends", or a
+// comma-separated list of day names and ranges such as "mon-fri,sun".
+//
+//	09:00-18:00 weekdays; 10:00-14:00 sat
+func ParsePauseWindows(input string) ([]PauseWindow, error) {
+	var windows []PauseWindow
+	for _, entry := range strings.Split(input, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		w, err := parsePauseWindow(entry)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+func parsePauseWindow(entry string) (PauseWindow, error) {
+	var w PauseWindow
+	span, days, _ := strings.Cut(entry, " ")
+	from, to, ok := strings.Cut(span, "-")
+	if !ok {
+		return w, fmt.Errorf("invalid pause window %q (expected HH:MM-HH:MM [days])", entry)
+	}
+	var err error
+	if w.Start, err = parseClock(from); err != nil {
+		return w, fmt.Errorf("invalid pause window %q: %w", entry, err)
+	}
+	if w.End, err = parseClock(to); err != nil {
+		return w, fmt.Errorf("invalid pause window %q: %w", entry, err)
+	}
+	if w.Start == w.End {
+		return w, fmt.Errorf("invalid pause window %q: start and end are equal", entry)
+	}
+	if w.Days, err = parseDays(strings.TrimSpace(days)); err != nil {
+		return w, fmt.Errorf("invalid pause window %q: %w", entry, err)
+	}
+	return w, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func parseDays(s string) ([7]bool, error) {
+	var days [7]bool
+	switch strings.ToLower(s) {
+	case "", "daily":
+		return [7]bool{true, true, true, true, true, true, true}, nil
+	case "weekdays":
+		return [7]bool{false, true, true, true, true, true, false}, nil
+	case "weekends":
+		return [7]bool{true, false, false, false, false, false, true}, nil
+	}
+	for _, part := range strings.Split(strings.ToLower(s), ",") {
+		first, last, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		from, ok := dayNames[first]
+		if !ok {
+			return days, fmt.Errorf("unknown day %q", first)
+		}
+		to := from
+		if isRange {
+			if to, ok = dayNames[last]; !ok {
+				return days, fmt.Errorf("unknown day %q", last)
+			}
+		}
+		for d := from; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == to {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// occurrence returns the window's span that contains t, if any.
+func (w PauseWindow) occurrence(t time.Time) (start, end time.Time, ok bool) {
+	length := w.End - w.Start
+	if length < 0 {
+		length += 24 * 60
+	}
+	// A window containing t started today or, past midnight, yesterday.
+	for _, back := range []int{0, -1} {
+		day := t.AddDate(0, 0, back)
+		if !w.Days[day.Weekday()] {
+			continue
+		}
+		start = time.Date(day.Year(), day.Month(), day.Day(), w.Start/60, w.Start%60, 0, 0, t.Location())
+		end = start.Add(time.Duration(length) * time.Minute)
+		if !t.Before(start) && t.Before(end) {
+			return start, end, true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// PausedUntil reports whether now falls inside one of the windows and, if
+// so, when the pause ends. Back-to-back and overlapping windows are joined
+// into one pause, up to a week for windows that cover every hour.
+func PausedUntil(windows []PauseWindow, now time.Time) (time.Time, bool) {
+	until := now
+	for until.Sub(now) < 7*24*time.Hour {
+		extended := false
+		for _, w := range windows {
+			if _, end, ok := w.occurrence(until); ok && end.After(until) {
+				until = end
+				extended = true
+			}
+		}
+		if !extended {
+			break
+		}
+	}
+	return until, until.After(now)
+}
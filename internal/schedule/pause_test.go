@@ -0,0 +1,99 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 2026-03-16 is a Monday.
+func at(day, hour, minute int) time.Time {
+	return time.Date(2026, time.March, day, hour, minute, 0, 0, time.Local)
+}
+
+func TestParsePauseWindows(t *testing.T) {
+	windows, err := ParsePauseWindows("09:00-18:00 weekdays; 22:30-06:00; 10:00-14:00 sat,sun")
+	require.NoError(t, err)
+	require.Len(t, windows, 3)
+
+	assert.Equal(t, 9*60, windows[0].Start)
+	assert.Equal(t, 18*60, windows[0].End)
+	assert.Equal(t, [7]bool{false, true, true, true, true, true, false}, windows[0].Days)
+	assert.Equal(t, [7]bool{true, true, true, true, true, true, true}, windows[1].Days)
+	assert.Equal(t, [7]bool{true, false, false, false, false, false, true}, windows[2].Days)
+}
+
+func TestParsePauseWindows_DayRanges(t *testing.T) {
+	windows, err := ParsePauseWindows("08:00-09:00 Fri-Mon,wed")
+	require.NoError(t, err)
+	require.Len(t, windows, 1)
+	assert.Equal(t, [7]bool{true, true, false, true, false, true, true}, windows[0].Days)
+}
+
+func TestParsePauseWindows_Empty(t *testing.T) {
+	windows, err := ParsePauseWindows("")
+	require.NoError(t, err)
+	assert.Empty(t, windows)
+}
+
+func TestParsePauseWindows_Invalid(t *testing.T) {
+	for _, input := range []string{
+		"09:00",
+		"9am-5pm",
+		"09:00-25:00",
+		"09:00-09:00",
+		"09:00-18:00 someday",
+		"09:00-18:00 mon-funday",
+	} {
+		_, err := ParsePauseWindows(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestPausedUntil(t *testing.T) {
+	windows, err := ParsePauseWindows("09:00-18:00 weekdays")
+	require.NoError(t, err)
+
+	until, paused := PausedUntil(windows, at(16, 10, 30))
+	assert.True(t, paused)
+	assert.Equal(t, at(16, 18, 0), until)
+
+	_, paused = PausedUntil(windows, at(16, 18, 0))
+	assert.False(t, paused, "the end of the window is outside it")
+	_, paused = PausedUntil(windows, at(16, 8, 59))
+	assert.False(t, paused)
+	_, paused = PausedUntil(windows, at(21, 10, 0))
+	assert.False(t, paused, "Saturday is not a weekday")
+}
+
+func TestPausedUntil_Overnight(t *testing.T) {
+	windows, err := ParsePauseWindows("22:00-06:00 fri")
+	require.NoError(t, err)
+
+	until, paused := PausedUntil(windows, at(21, 3, 0))
+	assert.True(t, paused, "Friday's window runs into Saturday morning")
+	assert.Equal(t, at(21, 6, 0), until)
+
+	_, paused = PausedUntil(windows, at(17, 3, 0))
+	assert.False(t, paused, "Monday night's window does not apply")
+}
+
+func TestPausedUntil_JoinsAdjacentWindows(t *testing.T) {
+	windows, err := ParsePauseWindows("08:00-12:00; 12:00-13:30 weekdays")
+	require.NoError(t, err)
+
+	until, paused := PausedUntil(windows, at(16, 9, 0))
+	assert.True(t, paused)
+	assert.Equal(t, at(16, 13, 30), until)
+}
+
+func TestPausedUntil_AlwaysPausedIsCapped(t *testing.T) {
+	windows, err := ParsePauseWindows("00:00-12:00; 12:00-00:00")
+	require.NoError(t, err)
+
+	until, paused := PausedUntil(windows, at(16, 9, 0))
+	assert.True(t, paused)
+	assert.False(t, until.Before(at(23, 9, 0)))
+}
@@ -0,0 +1,204 @@
+// Package schema checks the JSON blocks the AI CLIs emit (RALPH_VALIDATION,
+// RALPH_STATUS, ...) against JSON Schemas, producing error messages precise
+// enough to be fed back to the model.
+//
+// Only the subset of JSON Schema the embedded schemas use is supported:
+// type, enum, required, properties, additionalProperties, items, minimum
+// and maximum.
+package schema
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+)
+
+//go:embed schemas/*.json
+var files embed.FS
+
+// Schema is a parsed JSON Schema.
+type Schema struct {
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+}
+
+// Lookup returns the schema of block, e.g. "RALPH_VALIDATION".
+func Lookup(block string) (*Schema, error) {
+	data, err := files.ReadFile("schemas/" + block + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("no schema for %s", block)
+	}
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("schema for %s: %w", block, err)
+	}
+	return &s, nil
+}
+
+// MustLookup is Lookup for the schemas embedded in this package; it panics
+// when block has none.
+func MustLookup(block string) *Schema {
+	s, err := Lookup(block)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// WithEnum returns a copy of s in which property also accepts values,
+// e.g. a project's custom verdicts.
+func (s *Schema) WithEnum(property string, values ...string) *Schema {
+	p, ok := s.Properties[property]
+	if !ok || len(values) == 0 {
+		return s
+	}
+	out := *s
+	out.Properties = make(map[string]*Schema, len(s.Properties))
+	for name, ps := range s.Properties {
+		out.Properties[name] = ps
+	}
+	extended := *p
+	extended.Enum = append(append([]string{}, p.Enum...), values...)
+	out.Properties[property] = &extended
+	return &out
+}
+
+// Describes reports whether obj has at least one of the schema's
+// properties, i.e. whether it plausibly is the block at all.
+func (s *Schema) Describes(obj map[string]interface{}) bool {
+	for name := range obj {
+		if _, ok := s.Properties[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders s as indented JSON, for showing to the model.
+func (s *Schema) String() string {
+	data, _ := json.MarshalIndent(s, "", "  ")
+	return string(data)
+}
+
+// Validate checks a decoded JSON value against s and returns one message
+// per violation, each prefixed with the path of the offending value
+// (e.g. "RALPH_VALIDATION.completed_tasks[1]"). It returns nil when v
+// conforms.
+func (s *Schema) Validate(v interface{}) []string {
+	return s.validate(s.Title, v, nil)
+}
+
+func (s *Schema) validate(path string, v interface{}, errs []string) []string {
+	if s.Type != "" && !hasType(v, s.Type) {
+		return append(errs, fmt.Sprintf("%s: expected %s, got %s", path, s.Type, typeName(v)))
+	}
+	if len(s.Enum) > 0 {
+		if str, _ := v.(string); !slices.Contains(s.Enum, str) {
+			errs = append(errs, fmt.Sprintf("%s: %s is not one of %s", path, jsonText(v), strings.Join(s.Enum, ", ")))
+		}
+	}
+	switch val := v.(type) {
+	case float64:
+		if s.Minimum != nil && val < *s.Minimum {
+			errs = append(errs, fmt.Sprintf("%s: %g is less than the minimum %g", path, val, *s.Minimum))
+		}
+		if s.Maximum != nil && val > *s.Maximum {
+			errs = append(errs, fmt.Sprintf("%s: %g is greater than the maximum %g", path, val, *s.Maximum))
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range val {
+				errs = s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				errs = append(errs, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+		for _, name := range sortedKeys(val) {
+			ps, ok := s.Properties[name]
+			switch {
+			case ok:
+				errs = ps.validate(path+"."+name, val[name], errs)
+			case s.AdditionalProperties != nil && !*s.AdditionalProperties:
+				errs = append(errs, fmt.Sprintf("%s: unexpected property %q (allowed: %s)", path, name, strings.Join(sortedKeys(s.Properties), ", ")))
+			}
+		}
+	}
+	return errs
+}
+
+// hasType reports whether v, as decoded by encoding/json, is of the JSON
+// Schema type t.
+func hasType(v interface{}, t string) bool {
+	switch t {
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "null":
+		return v == nil
+	default:
+		return true
+	}
+}
+
+func typeName(v interface{}) string {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string " + jsonText(val)
+	case float64:
+		return "number " + jsonText(val)
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func jsonText(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
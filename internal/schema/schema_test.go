@@ -0,0 +1,93 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, text string) interface{} {
+	t.Helper()
+	var v interface{}
+	require.NoError(t, json.Unmarshal([]byte(text), &v))
+	return v
+}
+
+func TestLookup_EmbeddedSchemas(t *testing.T) {
+	for _, block := range []string{"RALPH_VALIDATION", "RALPH_CROSS_VALIDATION", "RALPH_STATUS", "RALPH_TASKS_VALIDATION"} {
+		t.Run(block, func(t *testing.T) {
+			s, err := Lookup(block)
+			require.NoError(t, err)
+			assert.Equal(t, block, s.Title)
+			assert.Equal(t, "object", s.Type)
+		})
+	}
+
+	_, err := Lookup("RALPH_NOPE")
+	assert.ErrorContains(t, err, "no schema for RALPH_NOPE")
+}
+
+func TestValidate_Conforming(t *testing.T) {
+	s := MustLookup("RALPH_VALIDATION")
+	v := decode(t, `{"verdict": "NEEDS_MORE_WORK", "confidence": 0.7, "feedback": "T002 untested",
+		"completed_tasks": ["T001"], "incomplete_tasks": ["T002"], "remaining": 1}`)
+
+	assert.Nil(t, s.Validate(v))
+}
+
+func TestValidate_ReportsEachViolation(t *testing.T) {
+	s := MustLookup("RALPH_VALIDATION")
+	v := decode(t, `{"verdict": "DONE", "confidence": "high", "completed_tasks": ["T001", 2],
+		"remaining": 1.5, "blocked_count": -1, "verdcit": "COMPLETE"}`)
+
+	assert.Equal(t, []string{
+		`RALPH_VALIDATION.blocked_count: -1 is less than the minimum 0`,
+		`RALPH_VALIDATION.completed_tasks[1]: expected string, got number 2`,
+		`RALPH_VALIDATION.confidence: expected number, got string "high"`,
+		`RALPH_VALIDATION.remaining: expected integer, got number 1.5`,
+		`RALPH_VALIDATION: unexpected property "verdcit" (allowed: blocked_count, blocked_tasks, completed_tasks, confidence, feedback, inadmissible_practices, incomplete_tasks, remaining, verdict)`,
+		`RALPH_VALIDATION.verdict: "DONE" is not one of COMPLETE, NEEDS_MORE_WORK, INADMISSIBLE, ESCALATE, BLOCKED`,
+	}, s.Validate(v))
+}
+
+func TestValidate_MissingRequired(t *testing.T) {
+	s := MustLookup("RALPH_TASKS_VALIDATION")
+	assert.Equal(t, []string{`RALPH_TASKS_VALIDATION: missing required property "verdict"`}, s.Validate(decode(t, `{"feedback": "ok"}`)))
+}
+
+func TestValidate_WrongBlockType(t *testing.T) {
+	s := MustLookup("RALPH_STATUS")
+	assert.Equal(t, []string{"RALPH_STATUS: expected object, got string \"done\""}, s.Validate("done"))
+}
+
+func TestValidate_NestedItemsAllowExtraProperties(t *testing.T) {
+	s := MustLookup("RALPH_CROSS_VALIDATION")
+	v := decode(t, `{"verdict": "REJECTED", "discrepancies": [{"task_id": "T001", "claimed": "x", "actual": "y", "severity": "high"}, {"task_id": 3}]}`)
+
+	assert.Equal(t, []string{"RALPH_CROSS_VALIDATION.discrepancies[1].task_id: expected string, got number 3"}, s.Validate(v))
+}
+
+func TestWithEnum(t *testing.T) {
+	base := MustLookup("RALPH_VALIDATION")
+	s := base.WithEnum("verdict", "SECURITY_REVIEW_NEEDED")
+
+	assert.Nil(t, s.Validate(decode(t, `{"verdict": "SECURITY_REVIEW_NEEDED", "feedback": ""}`)))
+	assert.Len(t, base.Validate(decode(t, `{"verdict": "SECURITY_REVIEW_NEEDED", "feedback": ""}`)), 1, "the original schema is unchanged")
+	assert.Same(t, base, base.WithEnum("verdict"))
+	assert.Same(t, base, base.WithEnum("nope", "X"))
+}
+
+func TestDescribes(t *testing.T) {
+	s := MustLookup("RALPH_STATUS")
+	assert.True(t, s.Describes(map[string]interface{}{"notes": "x", "other": 1}))
+	assert.False(t, s.Describes(map[string]interface{}{"other": 1}))
+}
+
+func TestString(t *testing.T) {
+	out := MustLookup("RALPH_TASKS_VALIDATION").String()
+	assert.Contains(t, out, `"required": [`)
+	assert.Contains(t, out, `"VALID"`)
+	assert.True(t, json.Valid([]byte(out)))
+}
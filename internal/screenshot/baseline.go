@@ -0,0 +1,135 @@
+package screenshot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Comparison statuses.
+const (
+	// StatusNew means there was no earlier screenshot; it became the
+	// baseline.
+	StatusNew = "new"
+	// StatusUnchanged means the screenshot matches its baseline within the
+	// threshold.
+	StatusUnchanged = "unchanged"
+	// StatusChanged means an unfinished task's screen changed beyond the
+	// threshold.
+	StatusChanged = "changed"
+	// StatusRegression means a finished task's screen changed beyond the
+	// threshold.
+	StatusRegression = "regression"
+)
+
+// untagged is the baseline folder for screenshots not tied to a task.
+const untagged = "_untagged"
+
+// Comparison is the outcome of checking one screenshot against its baseline.
+type Comparison struct {
+	Status   string
+	Percent  float64
+	Baseline string // the baseline compared against; "" for StatusNew
+	Diff     string // diff image path; "" when none was written
+}
+
+// Store keeps one baseline per task and screenshot file name under Dir.
+//
+// While a task is unfinished its baseline follows the latest screenshot, so
+// each iteration is compared with the previous one. Once the task is done
+// the baseline is frozen and any later change beyond Threshold is a
+// regression.
+type Store struct {
+	Dir string
+	// Threshold is the percentage of differing pixels tolerated.
+	Threshold float64
+}
+
+// Check compares the screenshot at current with the baseline for task and
+// writes a diff image to diffPath when the two differ.
+func (s *Store) Check(task, current, diffPath string, done bool) (*Comparison, error) {
+	if task == "" {
+		task = untagged
+	}
+	baseline := filepath.Join(s.Dir, sanitize(task), filepath.Base(current))
+
+	if _, err := os.Stat(baseline); errors.Is(err, fs.ErrNotExist) {
+		if err := copyFile(current, baseline); err != nil {
+			return nil, fmt.Errorf("store baseline: %w", err)
+		}
+		return &Comparison{Status: StatusNew}, nil
+	}
+
+	diff, err := Compare(baseline, current)
+	if err != nil {
+		return nil, err
+	}
+	cmp := &Comparison{Percent: diff.Percent}
+	if done {
+		cmp.Baseline = baseline
+	} else {
+		// Keep a copy of the previous screenshot next to the diff so the
+		// comparison stays reviewable after the baseline moves on.
+		prev := strings.TrimSuffix(diffPath, filepath.Ext(diffPath)) + "-previous" + filepath.Ext(baseline)
+		if err := copyFile(baseline, prev); err != nil {
+			return nil, err
+		}
+		cmp.Baseline = prev
+	}
+	if diff.Percent > 0 && diff.Image != nil {
+		if err := WriteImage(diffPath, diff.Image); err != nil {
+			return nil, err
+		}
+		cmp.Diff = diffPath
+	}
+
+	switch {
+	case diff.Percent <= s.Threshold:
+		cmp.Status = StatusUnchanged
+	case done:
+		cmp.Status = StatusRegression
+	default:
+		cmp.Status = StatusChanged
+	}
+	if !done {
+		if err := copyFile(current, baseline); err != nil {
+			return nil, fmt.Errorf("update baseline: %w", err)
+		}
+	}
+	return cmp, nil
+}
+
+// sanitize keeps task references usable as a directory name.
+func sanitize(task string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, task)
+}
+
+func copyFile(src, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,66 @@
+package screenshot
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_Check(t *testing.T) {
+	dir := t.TempDir()
+	store := &Store{Dir: filepath.Join(dir, "baselines"), Threshold: 1}
+	shot := func(iter string, marked int) string {
+		path := filepath.Join(dir, iter, "login.png")
+		writePNG(t, path, 10, 10, marked)
+		return path
+	}
+	diffPath := func(iter string) string { return filepath.Join(dir, iter, "login-diff.png") }
+
+	// First screenshot becomes the baseline.
+	cmp, err := store.Check("T003: Login", shot("i1", 0), diffPath("i1"), false)
+	require.NoError(t, err)
+	assert.Equal(t, &Comparison{Status: StatusNew}, cmp)
+	assert.FileExists(t, filepath.Join(store.Dir, "T003__Login", "login.png"))
+
+	// Same screen while the task is pending: unchanged, no diff image.
+	cmp, err = store.Check("T003: Login", shot("i2", 0), diffPath("i2"), false)
+	require.NoError(t, err)
+	assert.Equal(t, StatusUnchanged, cmp.Status)
+	assert.Empty(t, cmp.Diff)
+	assert.FileExists(t, cmp.Baseline, "the previous screenshot is kept for review")
+
+	// The screen changes while pending: changed, and the baseline moves on.
+	cmp, err = store.Check("T003: Login", shot("i3", 30), diffPath("i3"), false)
+	require.NoError(t, err)
+	assert.Equal(t, StatusChanged, cmp.Status)
+	assert.Equal(t, 30.0, cmp.Percent)
+	assert.FileExists(t, diffPath("i3"))
+
+	// Once done, the baseline is frozen and changes are regressions.
+	cmp, err = store.Check("T003: Login", shot("i4", 30), diffPath("i4"), true)
+	require.NoError(t, err)
+	assert.Equal(t, StatusUnchanged, cmp.Status)
+
+	cmp, err = store.Check("T003: Login", shot("i5", 0), diffPath("i5"), true)
+	require.NoError(t, err)
+	assert.Equal(t, StatusRegression, cmp.Status)
+	assert.Equal(t, filepath.Join(store.Dir, "T003__Login", "login.png"), cmp.Baseline)
+
+	cmp, err = store.Check("T003: Login", shot("i6", 0), diffPath("i6"), true)
+	require.NoError(t, err)
+	assert.Equal(t, StatusRegression, cmp.Status, "a regression does not become the new baseline")
+}
+
+func TestStore_CheckUntagged(t *testing.T) {
+	dir := t.TempDir()
+	store := &Store{Dir: filepath.Join(dir, "baselines")}
+	path := filepath.Join(dir, "home.png")
+	writePNG(t, path, 2, 2, 0)
+
+	cmp, err := store.Check("", path, filepath.Join(dir, "home-diff.png"), false)
+	require.NoError(t, err)
+	assert.Equal(t, StatusNew, cmp.Status)
+	assert.FileExists(t, filepath.Join(store.Dir, "_untagged", "home.png"))
+}
@@ -0,0 +1,129 @@
+// Package screenshot compares Playwright screenshots across iterations. The
+// comparisons are handed to the validator as evidence: a screenshot that did
+// not change cannot prove a claimed visual fix, and a finished task whose
+// screen changed has regressed.
+package screenshot
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // register the JPEG decoder for image.Decode
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// pixelTolerance is the per-channel difference (0-255) ignored as
+// anti-aliasing or compression noise.
+const pixelTolerance = 16
+
+// Diff is the pixel difference between two screenshots.
+type Diff struct {
+	// Percent is the share of pixels that differ, from 0 to 100. Images of
+	// different sizes differ completely.
+	Percent float64
+
+	// Image shows the current screenshot faded, with differing pixels in
+	// red. It is nil when the sizes differ.
+	Image *image.RGBA
+}
+
+// IsImage reports whether path has an image extension Compare can decode.
+func IsImage(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png", ".jpg", ".jpeg":
+		return true
+	}
+	return false
+}
+
+// Compare decodes two PNG or JPEG screenshots and measures how much of the
+// current one differs from the baseline.
+func Compare(baselinePath, currentPath string) (*Diff, error) {
+	base, err := decode(baselinePath)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := decode(currentPath)
+	if err != nil {
+		return nil, err
+	}
+	return compareImages(base, cur), nil
+}
+
+func decode(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	return img, nil
+}
+
+func compareImages(base, cur image.Image) *Diff {
+	bb, cb := base.Bounds(), cur.Bounds()
+	if bb.Dx() != cb.Dx() || bb.Dy() != cb.Dy() {
+		return &Diff{Percent: 100}
+	}
+	out := image.NewRGBA(image.Rect(0, 0, cb.Dx(), cb.Dy()))
+	red := color.RGBA{R: 255, A: 255}
+	differing := 0
+	for y := 0; y < cb.Dy(); y++ {
+		for x := 0; x < cb.Dx(); x++ {
+			c := cur.At(cb.Min.X+x, cb.Min.Y+y)
+			if pixelsDiffer(base.At(bb.Min.X+x, bb.Min.Y+y), c) {
+				differing++
+				out.SetRGBA(x, y, red)
+				continue
+			}
+			out.SetRGBA(x, y, fade(c))
+		}
+	}
+	total := cb.Dx() * cb.Dy()
+	if total == 0 {
+		return &Diff{Image: out}
+	}
+	return &Diff{Percent: float64(differing) * 100 / float64(total), Image: out}
+}
+
+func pixelsDiffer(a, b color.Color) bool {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	for _, d := range [][2]uint32{{ar, br}, {ag, bg}, {ab, bb}, {aa, ba}} {
+		// RGBA returns 16-bit channels; compare at 8 bits.
+		x, y := int(d[0]>>8), int(d[1]>>8)
+		if x-y > pixelTolerance || y-x > pixelTolerance {
+			return true
+		}
+	}
+	return false
+}
+
+// fade blends c two thirds of the way to white so red marks stand out.
+func fade(c color.Color) color.RGBA {
+	r, g, b, _ := c.RGBA()
+	blend := func(v uint32) uint8 { return uint8((v>>8 + 2*255) / 3) }
+	return color.RGBA{R: blend(r), G: blend(g), B: blend(b), A: 255}
+}
+
+// WriteImage saves img as a PNG, creating parent directories.
+func WriteImage(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return fmt.Errorf("encode %s: %w", path, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,74 @@
+package screenshot
+
+import (
+	"image"
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePNG writes a w x h white image whose first `marked` pixels (row by
+// row) are black.
+func writePNG(t *testing.T, path string, w, h, marked int) {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < w*h; i++ {
+		c := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+		if i < marked {
+			c = color.RGBA{A: 255}
+		}
+		img.SetRGBA(i%w, i/w, c)
+	}
+	require.NoError(t, WriteImage(path, img))
+}
+
+func TestCompare(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.png")
+	same := filepath.Join(dir, "same.png")
+	changed := filepath.Join(dir, "changed.png")
+	wider := filepath.Join(dir, "wider.png")
+	writePNG(t, base, 10, 10, 0)
+	writePNG(t, same, 10, 10, 0)
+	writePNG(t, changed, 10, 10, 25)
+	writePNG(t, wider, 20, 10, 0)
+
+	d, err := Compare(base, same)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, d.Percent)
+
+	d, err = Compare(base, changed)
+	require.NoError(t, err)
+	assert.Equal(t, 25.0, d.Percent)
+	require.NotNil(t, d.Image)
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, d.Image.RGBAAt(0, 0), "differing pixels are red")
+	assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, d.Image.RGBAAt(9, 9))
+
+	d, err = Compare(base, wider)
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, d.Percent)
+	assert.Nil(t, d.Image)
+}
+
+func TestCompare_ToleratesNoise(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	b := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	a.SetRGBA(0, 0, color.RGBA{R: 100, G: 100, B: 100, A: 255})
+	b.SetRGBA(0, 0, color.RGBA{R: 110, G: 95, B: 100, A: 255})
+	assert.Equal(t, 0.0, compareImages(a, b).Percent)
+}
+
+func TestCompare_Errors(t *testing.T) {
+	dir := t.TempDir()
+	_, err := Compare(filepath.Join(dir, "missing.png"), filepath.Join(dir, "other.png"))
+	assert.Error(t, err)
+}
+
+func TestIsImage(t *testing.T) {
+	assert.True(t, IsImage("shots/login.PNG"))
+	assert.True(t, IsImage("a.jpeg"))
+	assert.False(t, IsImage("junit.xml"))
+}
@@ -0,0 +1,88 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Service is the keyring service ralph-loop's secrets are stored under.
+const Service = "ralph-loop"
+
+// ErrNotFound is returned for a secret the keyring does not hold.
+var ErrNotFound = errors.New("not found in the keyring")
+
+// Keyring reads the secrets stored under Service.
+type Keyring interface {
+	Get(name string) (string, error)
+}
+
+// MapKeyring is a Keyring holding its secrets in memory, for tests.
+type MapKeyring map[string]string
+
+// Get implements Keyring.
+func (k MapKeyring) Get(name string) (string, error) {
+	if v, ok := k[name]; ok {
+		return v, nil
+	}
+	return "", ErrNotFound
+}
+
+// commandKeyring reads the OS keyring with its command-line tool: security
+// for the macOS keychain, secret-tool for the Secret Service elsewhere.
+type commandKeyring struct{}
+
+// Get implements Keyring.
+func (commandKeyring) Get(name string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", Service, "-a", name, "-w")
+	case "windows":
+		return "", errors.New("the Windows credential manager is not supported; use an environment variable")
+	default:
+		cmd = exec.Command("secret-tool", "lookup", "service", Service, "account", name)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("%s: %w", cmd.Args[0], err)
+	}
+	v := strings.TrimRight(stdout.String(), "\r\n")
+	if v == "" {
+		return "", ErrNotFound
+	}
+	return v, nil
+}
+
+var (
+	keyringMu sync.Mutex
+	keyring   Keyring = commandKeyring{}
+)
+
+// SetKeyring makes Lookup read k and returns the keyring it read before.
+func SetKeyring(k Keyring) Keyring {
+	keyringMu.Lock()
+	defer keyringMu.Unlock()
+	prev := keyring
+	keyring = k
+	return prev
+}
+
+// Lookup returns the secret name from the keyring, stored with e.g.
+// `secret-tool store --label=ralph-loop service ralph-loop account NAME` or
+// `security add-generic-password -s ralph-loop -a NAME -w`.
+func Lookup(name string) (string, error) {
+	keyringMu.Lock()
+	k := keyring
+	keyringMu.Unlock()
+	return k.Get(name)
+}
@@ -0,0 +1,97 @@
+// Package secrets keeps secret values out of what ralph-loop writes. It
+// reads secrets from the OS keyring for the config files to reference, and
+// redacts the registered ones from logs, state files and `ralph-loop config
+// show`.
+package secrets
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces a secret value.
+const Redacted = "[REDACTED]"
+
+// minLength is the length under which values are not registered: redacting
+// them would mangle unrelated text.
+const minLength = 6
+
+// secretParts are the key name parts that mark a secret, e.g. the WEBHOOK
+// of NOTIFY_WEBHOOK or the TOKEN of GITHUB_TOKEN.
+var secretParts = map[string]bool{
+	"TOKEN": true, "SECRET": true, "PASSWORD": true, "PASSWD": true,
+	"KEY": true, "APIKEY": true, "WEBHOOK": true, "CREDENTIALS": true, "AUTH": true,
+}
+
+var (
+	mu     sync.RWMutex
+	values = map[string]bool{}
+)
+
+// IsSecretKey reports whether a setting or environment variable named key
+// holds a secret, from the parts of its name.
+func IsSecretKey(key string) bool {
+	for _, part := range strings.FieldsFunc(strings.ToUpper(key), func(r rune) bool { return r == '_' || r == '-' || r == '.' }) {
+		if secretParts[part] {
+			return true
+		}
+	}
+	return false
+}
+
+// Register adds vals to the values Redact hides. Values shorter than six
+// characters are ignored.
+func Register(vals ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, v := range vals {
+		if len(v) >= minLength {
+			values[v] = true
+		}
+	}
+}
+
+// Reset forgets the registered values.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	values = map[string]bool{}
+}
+
+// Redact replaces every registered value in s, as is or JSON-escaped, with
+// Redacted.
+func Redact(s string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if len(values) == 0 {
+		return s
+	}
+	for v := range values {
+		s = strings.ReplaceAll(s, v, Redacted)
+		if escaped := jsonEscape(v); escaped != v {
+			s = strings.ReplaceAll(s, escaped, Redacted)
+		}
+	}
+	return s
+}
+
+// RedactBytes is Redact for data about to be written.
+func RedactBytes(data []byte) []byte {
+	mu.RLock()
+	empty := len(values) == 0
+	mu.RUnlock()
+	if empty {
+		return data
+	}
+	return []byte(Redact(string(data)))
+}
+
+// jsonEscape returns v as it appears inside a JSON string.
+func jsonEscape(v string) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	return string(data[1 : len(data)-1])
+}
@@ -0,0 +1,49 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSecretKey(t *testing.T) {
+	for key, want := range map[string]bool{
+		"NOTIFY_WEBHOOK":    true,
+		"SLACK_WEBHOOK":     true,
+		"GITHUB_TOKEN":      true,
+		"OPENAI_API_KEY":    true,
+		"db-password":       true,
+		"K8S_JOB_SECRETS":   false,
+		"NOTIFY_CHAT_ID":    false,
+		"MAX_ITERATIONS":    false,
+		"KEYBOARD_SHORTCUT": false,
+	} {
+		assert.Equal(t, want, IsSecretKey(key), key)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	t.Cleanup(Reset)
+	assert.Equal(t, "nothing registered", Redact("nothing registered"))
+
+	Register("https://hooks.example.com/T0?a=1&b=2", "short", "")
+	assert.Equal(t, "posting to [REDACTED] failed, short", Redact("posting to https://hooks.example.com/T0?a=1&b=2 failed, short"))
+	assert.Equal(t, `{"reason": "[REDACTED] is down"}`, string(RedactBytes([]byte(`{"reason": "https://hooks.example.com/T0?a=1&b=2 is down"}`))),
+		"values are also redacted as JSON escapes them")
+
+	Reset()
+	assert.Equal(t, "https://hooks.example.com/T0?a=1&b=2", Redact("https://hooks.example.com/T0?a=1&b=2"))
+}
+
+func TestLookup(t *testing.T) {
+	prev := SetKeyring(MapKeyring{"slack": "https://hooks.slack.com/xyz"})
+	t.Cleanup(func() { SetKeyring(prev) })
+
+	v, err := Lookup("slack")
+	require.NoError(t, err)
+	assert.Equal(t, "https://hooks.slack.com/xyz", v)
+
+	_, err = Lookup("missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
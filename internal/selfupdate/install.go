@@ -0,0 +1,156 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// binaryName is the executable inside a release archive.
+const binaryName = "ralph-loop"
+
+// maxArchiveSize bounds downloads so a bad URL cannot fill the disk.
+const maxArchiveSize = 200 << 20
+
+// Download fetches the release archive for goos/goarch, verifies it
+// against the release's checksums.txt and returns the ralph-loop binary
+// it contains.
+func (c *Client) Download(rel *Release, goos, goarch string) ([]byte, error) {
+	name := rel.ArchiveName(goos, goarch)
+	archive, ok := rel.Asset(name)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no build for %s/%s (%s)", rel.Tag, goos, goarch, name)
+	}
+	sums, ok := rel.Asset(checksumsAsset)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no %s; refusing to install an unverified binary", rel.Tag, checksumsAsset)
+	}
+
+	sumData, err := c.fetch(sums.URL)
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", checksumsAsset, err)
+	}
+	want, err := findChecksum(sumData, name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.fetch(archive.URL)
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", name, err)
+	}
+	got := sha256.Sum256(data)
+	if hex.EncodeToString(got[:]) != want {
+		return nil, fmt.Errorf("checksum mismatch for %s: got %x, want %s", name, got, want)
+	}
+	return extractBinary(data)
+}
+
+func (c *Client) fetch(url string) ([]byte, error) {
+	body, err := c.get(url, "application/octet-stream")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	data, err := io.ReadAll(io.LimitReader(body, maxArchiveSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxArchiveSize {
+		return nil, fmt.Errorf("larger than %d bytes", maxArchiveSize)
+	}
+	return data, nil
+}
+
+// findChecksum returns the hex SHA-256 listed for name in a sha256sum-style
+// checksum file.
+func findChecksum(sums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%s does not list %s", checksumsAsset, name)
+}
+
+// extractBinary returns the ralph-loop executable from a tar.gz archive.
+func extractBinary(archive []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("archive does not contain %s", binaryName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read archive: %w", err)
+		}
+		base := path.Base(hdr.Name)
+		if hdr.Typeflag == tar.TypeReg && (base == binaryName || base == binaryName+".exe") {
+			return io.ReadAll(io.LimitReader(tr, maxArchiveSize))
+		}
+	}
+}
+
+// Replace atomically swaps the executable at exe for binary. The new file
+// is written next to exe and renamed over it, so an interrupted update
+// leaves the old binary in place. Windows cannot replace a running
+// executable, so there the old one is first moved aside to exe+".old".
+func Replace(exe string, binary []byte) error {
+	resolved, err := filepath.EvalSymlinks(exe)
+	if err == nil {
+		exe = resolved
+	}
+	mode := os.FileMode(0755)
+	if info, err := os.Stat(exe); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(exe), "."+filepath.Base(exe)+".update-*")
+	if err != nil {
+		return fmt.Errorf("create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write new binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write new binary: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+
+	old := ""
+	if runtime.GOOS == "windows" {
+		old = exe + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			return fmt.Errorf("move old binary aside: %w", err)
+		}
+	}
+	if err := os.Rename(tmp.Name(), exe); err != nil {
+		if old != "" {
+			_ = os.Rename(old, exe)
+		}
+		return fmt.Errorf("replace %s: %w", exe, err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makeArchive builds a tar.gz holding the given files.
+func makeArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+// serveRelease serves a release with the given archive and checksums file.
+func serveRelease(t *testing.T, archive []byte, sums string) *Release {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/archive":
+			_, _ = w.Write(archive)
+		case "/checksums":
+			_, _ = w.Write([]byte(sums))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return &Release{Tag: "v1.5.0", Assets: []Asset{
+		{Name: "ralph-loop_1.5.0_linux_amd64.tar.gz", URL: srv.URL + "/archive"},
+		{Name: "checksums.txt", URL: srv.URL + "/checksums"},
+	}}
+}
+
+func TestDownload_VerifiesAndExtracts(t *testing.T) {
+	archive := makeArchive(t, map[string]string{"README.md": "docs", "ralph-loop": "new binary"})
+	sums := fmt.Sprintf("%x  ralph-loop_1.5.0_darwin_arm64.tar.gz\n%x  ralph-loop_1.5.0_linux_amd64.tar.gz\n",
+		sha256.Sum256([]byte("other")), sha256.Sum256(archive))
+	rel := serveRelease(t, archive, sums)
+
+	binary, err := (&Client{}).Download(rel, "linux", "amd64")
+	require.NoError(t, err)
+	assert.Equal(t, "new binary", string(binary))
+}
+
+func TestDownload_ChecksumMismatch(t *testing.T) {
+	archive := makeArchive(t, map[string]string{"ralph-loop": "tampered"})
+	sums := fmt.Sprintf("%x  ralph-loop_1.5.0_linux_amd64.tar.gz\n", sha256.Sum256([]byte("original")))
+	rel := serveRelease(t, archive, sums)
+
+	_, err := (&Client{}).Download(rel, "linux", "amd64")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch")
+}
+
+func TestDownload_MissingAssets(t *testing.T) {
+	rel := serveRelease(t, nil, "")
+	_, err := (&Client{}).Download(rel, "windows", "amd64")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no build for windows/amd64")
+
+	rel.Assets = rel.Assets[:1]
+	_, err = (&Client{}).Download(rel, "linux", "amd64")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "refusing to install an unverified binary")
+}
+
+func TestDownload_ArchiveWithoutBinary(t *testing.T) {
+	archive := makeArchive(t, map[string]string{"README.md": "docs"})
+	rel := serveRelease(t, archive, fmt.Sprintf("%x  ralph-loop_1.5.0_linux_amd64.tar.gz\n", sha256.Sum256(archive)))
+
+	_, err := (&Client{}).Download(rel, "linux", "amd64")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not contain ralph-loop")
+}
+
+func TestFindChecksum(t *testing.T) {
+	sums := []byte("ABC123  a.tar.gz\ndef456 *b.tar.gz\n")
+	got, err := findChecksum(sums, "a.tar.gz")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", got)
+	got, err = findChecksum(sums, "b.tar.gz")
+	require.NoError(t, err)
+	assert.Equal(t, "def456", got)
+	_, err = findChecksum(sums, "c.tar.gz")
+	assert.Error(t, err)
+}
+
+func TestReplace(t *testing.T) {
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "ralph-loop")
+	require.NoError(t, os.WriteFile(exe, []byte("old"), 0750))
+	link := filepath.Join(dir, "link")
+	require.NoError(t, os.Symlink(exe, link))
+
+	require.NoError(t, Replace(link, []byte("new")))
+
+	data, err := os.ReadFile(exe)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+	info, err := os.Stat(exe)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0750), info.Mode().Perm(), "permissions are kept")
+	target, err := os.Readlink(link)
+	require.NoError(t, err)
+	assert.Equal(t, exe, target, "the symlink itself is left alone")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "no temporary files are left behind")
+}
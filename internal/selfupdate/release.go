@@ -0,0 +1,171 @@
+// Package selfupdate replaces the running ralph-loop binary with the latest
+// GitHub release.
+//
+// Releases are built by goreleaser: each platform archive is named
+// ralph-loop_<version>_<os>_<arch>.tar.gz and listed with its SHA-256 in
+// checksums.txt. A download is only installed when its checksum matches.
+package selfupdate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultRepo is the GitHub repository releases are published to.
+const DefaultRepo = "CodexForgeBR/cli-tools"
+
+// checksumsAsset is the goreleaser checksum file of a release.
+const checksumsAsset = "checksums.txt"
+
+var defaultClient = &http.Client{Timeout: 5 * time.Minute}
+
+// Release is a published GitHub release.
+type Release struct {
+	Tag    string  `json:"tag_name"`
+	Assets []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Version returns the release version without the leading "v".
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.Tag, "v")
+}
+
+// ArchiveName returns the name of the release archive for a platform.
+func (r *Release) ArchiveName(goos, goarch string) string {
+	return fmt.Sprintf("ralph-loop_%s_%s_%s.tar.gz", r.Version(), goos, goarch)
+}
+
+// Asset returns the asset with the given name.
+func (r *Release) Asset(name string) (Asset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
+
+// Client talks to the GitHub releases API.
+type Client struct {
+	APIURL string // default https://api.github.com
+	Repo   string // owner/name, default DefaultRepo
+	Token  string // optional, raises the API rate limit
+	HTTP   *http.Client
+}
+
+// Latest returns the latest published release.
+func (c *Client) Latest() (*Release, error) {
+	api := c.APIURL
+	if api == "" {
+		api = "https://api.github.com"
+	}
+	repo := c.Repo
+	if repo == "" {
+		repo = DefaultRepo
+	}
+	body, err := c.get(fmt.Sprintf("%s/repos/%s/releases/latest", strings.TrimSuffix(api, "/"), repo), "application/vnd.github+json")
+	if err != nil {
+		return nil, fmt.Errorf("fetch latest release: %w", err)
+	}
+	defer body.Close()
+	var rel Release
+	if err := json.NewDecoder(body).Decode(&rel); err != nil {
+		return nil, fmt.Errorf("decode latest release: %w", err)
+	}
+	if rel.Tag == "" {
+		return nil, fmt.Errorf("latest release has no tag")
+	}
+	return &rel, nil
+}
+
+// get GETs url and returns the response body, which the caller closes.
+func (c *Client) get(url, accept string) (io.ReadCloser, error) {
+	client := c.HTTP
+	if client == nil {
+		client = defaultClient
+	}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp.Body, nil
+}
+
+// CompareVersions compares two semantic versions such as "1.4.0" or
+// "v1.5.0-rc.1", returning -1, 0 or 1. A pre-release sorts before its
+// release. ok is false when either is not a version, e.g. "dev".
+func CompareVersions(a, b string) (cmp int, ok bool) {
+	va, okA := parseVersion(a)
+	vb, okB := parseVersion(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	for i := 0; i < 3; i++ {
+		if va.parts[i] != vb.parts[i] {
+			if va.parts[i] < vb.parts[i] {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	switch {
+	case va.pre == vb.pre:
+		return 0, true
+	case va.pre == "":
+		return 1, true
+	case vb.pre == "":
+		return -1, true
+	case va.pre < vb.pre:
+		return -1, true
+	default:
+		return 1, true
+	}
+}
+
+type version struct {
+	parts [3]int
+	pre   string
+}
+
+func parseVersion(s string) (version, bool) {
+	var v version
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	s, _, _ = strings.Cut(s, "+")
+	s, v.pre, _ = strings.Cut(s, "-")
+	fields := strings.Split(s, ".")
+	if len(fields) == 0 || len(fields) > 3 {
+		return v, false
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v.parts[i] = n
+	}
+	return v, true
+}
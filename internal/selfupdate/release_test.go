@@ -0,0 +1,74 @@
+package selfupdate
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientLatest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/owner/tool/releases/latest", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"tag_name": "v1.4.0", "assets": [{"name": "checksums.txt", "browser_download_url": "https://example.com/checksums.txt"}]}`))
+	}))
+	defer srv.Close()
+
+	c := &Client{APIURL: srv.URL, Repo: "owner/tool", Token: "secret"}
+	rel, err := c.Latest()
+	require.NoError(t, err)
+	assert.Equal(t, "v1.4.0", rel.Tag)
+	assert.Equal(t, "1.4.0", rel.Version())
+	a, ok := rel.Asset("checksums.txt")
+	require.True(t, ok)
+	assert.Equal(t, "https://example.com/checksums.txt", a.URL)
+}
+
+func TestClientLatest_HTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	_, err := (&Client{APIURL: srv.URL}).Latest()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+	assert.Contains(t, err.Error(), "rate limited")
+}
+
+func TestReleaseArchiveName(t *testing.T) {
+	rel := &Release{Tag: "v2.0.1"}
+	assert.Equal(t, "ralph-loop_2.0.1_darwin_arm64.tar.gz", rel.ArchiveName("darwin", "arm64"))
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2.3", "1.10.0", -1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.2", "1.2.0", 0},
+		{"1.3.0-rc.1", "1.3.0", -1},
+		{"1.3.0", "1.3.0-rc.1", 1},
+		{"1.3.0-rc.1", "1.3.0-rc.2", -1},
+		{"1.3.0+build.5", "1.3.0", 0},
+	}
+	for _, tt := range tests {
+		got, ok := CompareVersions(tt.a, tt.b)
+		assert.True(t, ok, "%s vs %s", tt.a, tt.b)
+		assert.Equal(t, tt.want, got, "%s vs %s", tt.a, tt.b)
+	}
+}
+
+func TestCompareVersions_NotVersions(t *testing.T) {
+	for _, v := range []string{"dev", "", "1.2.3.4", "1.x"} {
+		_, ok := CompareVersions(v, "1.0.0")
+		assert.False(t, ok, v)
+	}
+}
@@ -2,7 +2,13 @@
 //
 // The SetupSignalHandler function registers handlers for SIGINT and SIGTERM,
 // allowing the application to respond to interruptions by calling cleanup callbacks
-// and canceling the provided context.
+// and canceling the provided context. On Windows the console control events
+// (Ctrl+C, Ctrl+Break, closing the console, logoff and shutdown) are handled
+// the same way.
+//
+// Watch tells the signals apart for the main loop: SIGINT stops at once,
+// SIGTERM lets the work in progress finish within a grace period, and SIGHUP
+// asks for the configuration to be reloaded.
 package signal
 
 import (
@@ -10,6 +16,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 )
 
 // SetupSignalHandler registers SIGINT and SIGTERM handlers.
@@ -33,7 +40,7 @@ import (
 //	})
 func SetupSignalHandler(ctx context.Context, cancel context.CancelFunc, onInterrupt func()) {
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigCh, shutdownSignals...)
 
 	go func() {
 		select {
@@ -47,3 +54,78 @@ func SetupSignalHandler(ctx context.Context, cancel context.CancelFunc, onInterr
 		}
 	}()
 }
+
+// Handlers are the callbacks of Watch. Nil callbacks are skipped.
+type Handlers struct {
+	// OnInterrupt is called on SIGINT, or on a second SIGTERM, before the
+	// context is canceled.
+	OnInterrupt func(sig os.Signal)
+	// OnTerminate is called on the first SIGTERM. The context is canceled
+	// Grace later unless it is done by then.
+	OnTerminate func(sig os.Signal)
+	// OnReload is called on every SIGHUP.
+	OnReload func()
+	Grace    time.Duration
+}
+
+// Watch registers the shutdown and reload signals and dispatches them to h
+// until the context is done or canceled by a signal. On Windows, where
+// shutdown allows only a few seconds, every shutdown signal interrupts and
+// there is no reload signal.
+func Watch(ctx context.Context, cancel context.CancelFunc, h Handlers) {
+	sigCh := make(chan os.Signal, 1)
+	watched := append(append(append([]os.Signal(nil), interruptSignals...), terminateSignals...), reloadSignals...)
+	signal.Notify(sigCh, watched...)
+
+	go func() {
+		defer signal.Stop(sigCh)
+		terminating := false
+		for {
+			select {
+			case sig := <-sigCh:
+				switch {
+				case contains(reloadSignals, sig):
+					if h.OnReload != nil {
+						h.OnReload()
+					}
+				case contains(terminateSignals, sig) && !terminating:
+					terminating = true
+					if h.OnTerminate != nil {
+						h.OnTerminate(sig)
+					}
+					time.AfterFunc(h.Grace, cancel)
+				default:
+					if h.OnInterrupt != nil {
+						h.OnInterrupt(sig)
+					}
+					cancel()
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Name returns the conventional name of sig, such as "SIGTERM".
+func Name(sig os.Signal) string {
+	switch sig {
+	case os.Interrupt:
+		return "SIGINT"
+	case syscall.SIGTERM:
+		return "SIGTERM"
+	case syscall.SIGHUP:
+		return "SIGHUP"
+	}
+	return sig.String()
+}
+
+func contains(signals []os.Signal, sig os.Signal) bool {
+	for _, s := range signals {
+		if s == sig {
+			return true
+		}
+	}
+	return false
+}
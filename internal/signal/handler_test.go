@@ -1,3 +1,5 @@
+//go:build !windows
+
 package signal
 
 import (
@@ -227,3 +229,83 @@ func TestSetupSignalHandler_NilCallback(t *testing.T) {
 		t.Fatal("context was not cancelled within timeout")
 	}
 }
+
+// TestWatch_TerminateThenGrace verifies that SIGTERM calls OnTerminate and
+// cancels the context only after the grace period, and that SIGHUP reloads.
+func TestWatch_TerminateThenGrace(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	terminated := make(chan os.Signal, 1)
+	reloaded := make(chan struct{}, 1)
+	Watch(ctx, cancel, Handlers{
+		OnInterrupt: func(os.Signal) { t.Error("OnInterrupt called on SIGTERM") },
+		OnTerminate: func(sig os.Signal) { terminated <- sig },
+		OnReload:    func() { reloaded <- struct{}{} },
+		Grace:       200 * time.Millisecond,
+	})
+
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	select {
+	case <-reloaded:
+	case <-time.After(time.Second):
+		t.Fatal("OnReload was not called")
+	}
+
+	start := time.Now()
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGTERM))
+	select {
+	case sig := <-terminated:
+		assert.Equal(t, "SIGTERM", Name(sig))
+	case <-time.After(time.Second):
+		t.Fatal("OnTerminate was not called")
+	}
+	assert.NoError(t, ctx.Err(), "the context survives the grace period")
+
+	select {
+	case <-ctx.Done():
+		assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	case <-time.After(2 * time.Second):
+		t.Fatal("context was not canceled after the grace period")
+	}
+}
+
+// TestWatch_SecondTerminateInterrupts verifies that a SIGTERM received while
+// terminating, like SIGINT, interrupts at once.
+func TestWatch_SecondTerminateInterrupts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupted := make(chan os.Signal, 1)
+	terminated := make(chan struct{}, 1)
+	Watch(ctx, cancel, Handlers{
+		OnInterrupt: func(sig os.Signal) { interrupted <- sig },
+		OnTerminate: func(os.Signal) { terminated <- struct{}{} },
+		Grace:       time.Hour,
+	})
+
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGTERM))
+	select {
+	case <-terminated:
+	case <-time.After(time.Second):
+		t.Fatal("OnTerminate was not called")
+	}
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGTERM))
+	select {
+	case sig := <-interrupted:
+		assert.Equal(t, "SIGTERM", Name(sig))
+	case <-time.After(time.Second):
+		t.Fatal("OnInterrupt was not called")
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context was not canceled")
+	}
+}
+
+func TestName(t *testing.T) {
+	assert.Equal(t, "SIGINT", Name(os.Interrupt))
+	assert.Equal(t, "SIGHUP", Name(syscall.SIGHUP))
+	assert.Equal(t, "user defined signal 1", Name(syscall.SIGUSR1))
+}
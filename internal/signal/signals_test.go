@@ -0,0 +1,14 @@
+package signal
+
+import (
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdownSignals(t *testing.T) {
+	assert.Contains(t, shutdownSignals, os.Signal(syscall.SIGTERM))
+	assert.Contains(t, shutdownSignals, os.Interrupt)
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+
+package signal
+
+import (
+	"os"
+	"syscall"
+)
+
+// shutdownSignals are the signals that interrupt a run.
+var shutdownSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+
+// interruptSignals stop a run at once, terminateSignals once the work in
+// progress is done, and reloadSignals reload its configuration.
+var (
+	interruptSignals = []os.Signal{syscall.SIGINT}
+	terminateSignals = []os.Signal{syscall.SIGTERM}
+	reloadSignals    = []os.Signal{syscall.SIGHUP}
+)
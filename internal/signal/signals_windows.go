@@ -0,0 +1,23 @@
+//go:build windows
+
+package signal
+
+import (
+	"os"
+	"syscall"
+)
+
+// shutdownSignals are the signals that interrupt a run. The Go runtime
+// delivers the console's CTRL_C_EVENT and CTRL_BREAK_EVENT as os.Interrupt,
+// and CTRL_CLOSE_EVENT, CTRL_LOGOFF_EVENT and CTRL_SHUTDOWN_EVENT as
+// SIGTERM; for the latter Windows allows a few seconds for the state to be
+// saved before it ends the process.
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// interruptSignals stop a run at once. Windows leaves no time to finish the
+// work in progress on SIGTERM and has no reload signal.
+var (
+	interruptSignals = shutdownSignals
+	terminateSignals []os.Signal
+	reloadSignals    []os.Signal
+)
@@ -0,0 +1,60 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ControlFile is the file in the state directory through which
+// `ralph-loop pause` and `ralph-loop resume-now` reach a running loop.
+const ControlFile = "control"
+
+// Control requests.
+const (
+	// ControlPause asks the loop to wait at the next phase boundary.
+	ControlPause = "pause"
+	// ControlResume wakes a loop that is paused or waiting for its
+	// scheduled start or the end of a pause window.
+	ControlResume = "resume"
+)
+
+// RequestControl records request for the loop running in dir, replacing a
+// request it has not taken yet.
+func RequestControl(dir, request string) error {
+	tmp := filepath.Join(dir, ControlFile+".tmp")
+	if err := os.WriteFile(tmp, []byte(request+"\n"), 0644); err != nil {
+		return fmt.Errorf("write control file: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, ControlFile)); err != nil {
+		return fmt.Errorf("write control file: %w", err)
+	}
+	return nil
+}
+
+// PendingControl returns the request not yet taken by the loop, or "".
+func PendingControl(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, ControlFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// TakeControl removes the pending request when it is request, and reports
+// whether it was. Other requests are left for later.
+func TakeControl(dir, request string) bool {
+	if PendingControl(dir) != request {
+		return false
+	}
+	return os.Remove(filepath.Join(dir, ControlFile)) == nil
+}
+
+// ClearControl removes any pending request.
+func ClearControl(dir string) error {
+	if err := os.Remove(filepath.Join(dir, ControlFile)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove control file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestControl(t *testing.T) {
+	dir := t.TempDir()
+	assert.Equal(t, "", PendingControl(dir))
+	assert.False(t, TakeControl(dir, ControlPause))
+
+	require.NoError(t, RequestControl(dir, ControlPause))
+	assert.Equal(t, ControlPause, PendingControl(dir))
+	assert.False(t, TakeControl(dir, ControlResume), "a pause is not taken as a resume")
+
+	require.NoError(t, RequestControl(dir, ControlResume))
+	assert.True(t, TakeControl(dir, ControlResume))
+	assert.Equal(t, "", PendingControl(dir))
+
+	require.NoError(t, RequestControl(dir, ControlPause))
+	require.NoError(t, ClearControl(dir))
+	assert.Equal(t, "", PendingControl(dir))
+	require.NoError(t, ClearControl(dir))
+}
@@ -0,0 +1,33 @@
+package state
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/statecrypt"
+)
+
+// SessionCost sums the reported cost of every Claude run recorded under
+// stateDir. The second return value is false when no cost was found, e.g.
+// because only non-Claude runners were used.
+func SessionCost(stateDir string) (float64, bool) {
+	var total float64
+	found := false
+	_ = filepath.WalkDir(stateDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".stream.json") {
+			return nil
+		}
+		data, err := statecrypt.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		if cost, ok := parser.ParseStreamJSONCost(string(data)); ok {
+			total += cost
+			found = true
+		}
+		return nil
+	})
+	return total, found
+}
@@ -0,0 +1,35 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionCost(t *testing.T) {
+	dir := t.TempDir()
+	iter1 := filepath.Join(dir, "iteration-001")
+	iter2 := filepath.Join(dir, "iteration-002")
+	require.NoError(t, os.MkdirAll(iter1, 0755))
+	require.NoError(t, os.MkdirAll(iter2, 0755))
+
+	require.NoError(t, os.WriteFile(filepath.Join(iter1, "implementation-output.txt.stream.json"),
+		[]byte(`{"type":"result","total_cost_usd":0.5}`+"\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(iter2, "validation-output.txt.stream.json"),
+		[]byte(`{"type":"result","total_cost_usd":0.25}`+"\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(iter2, "validation-output.txt"),
+		[]byte(`{"type":"result","total_cost_usd":9}`+"\n"), 0644))
+
+	cost, ok := SessionCost(dir)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.75, cost, 1e-9)
+}
+
+func TestSessionCost_NoStreamFiles(t *testing.T) {
+	cost, ok := SessionCost(t.TempDir())
+	assert.False(t, ok)
+	assert.Zero(t, cost)
+}
@@ -0,0 +1,56 @@
+package state
+
+import (
+	"sort"
+
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// UpdateEscalationStreak records a validation verdict for the model ladder
+// and returns the updated streak. Consecutive NEEDS_MORE_WORK verdicts that
+// report the same incomplete tasks extend the streak; any other verdict, or
+// a different set of incomplete tasks, resets it.
+func UpdateEscalationStreak(e *EscalationState, verdict string, incomplete []string) int {
+	if verdict != "NEEDS_MORE_WORK" {
+		e.Streak = 0
+		e.StreakTasks = nil
+		return 0
+	}
+
+	ids := taskIDSet(incomplete)
+	if e.Streak > 0 && equalStrings(ids, e.StreakTasks) {
+		e.Streak++
+	} else {
+		e.Streak = 1
+		e.StreakTasks = ids
+	}
+	return e.Streak
+}
+
+// taskIDSet returns the sorted, de-duplicated task IDs referenced in refs.
+func taskIDSet(refs []string) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, ref := range refs {
+		id := tasks.ExtractTaskID(ref)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
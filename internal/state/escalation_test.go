@@ -0,0 +1,33 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateEscalationStreak_SameTasksExtendStreak(t *testing.T) {
+	e := &EscalationState{}
+
+	assert.Equal(t, 1, UpdateEscalationStreak(e, "NEEDS_MORE_WORK", []string{"T002: missing", "T001"}))
+	assert.Equal(t, 2, UpdateEscalationStreak(e, "NEEDS_MORE_WORK", []string{"T001", "T002"}))
+	assert.Equal(t, []string{"T001", "T002"}, e.StreakTasks)
+}
+
+func TestUpdateEscalationStreak_DifferentTasksRestart(t *testing.T) {
+	e := &EscalationState{}
+
+	UpdateEscalationStreak(e, "NEEDS_MORE_WORK", []string{"T001"})
+	UpdateEscalationStreak(e, "NEEDS_MORE_WORK", []string{"T001"})
+	assert.Equal(t, 1, UpdateEscalationStreak(e, "NEEDS_MORE_WORK", []string{"T003"}))
+	assert.Equal(t, []string{"T003"}, e.StreakTasks)
+}
+
+func TestUpdateEscalationStreak_OtherVerdictResets(t *testing.T) {
+	e := &EscalationState{}
+
+	UpdateEscalationStreak(e, "NEEDS_MORE_WORK", nil)
+	assert.Equal(t, 2, UpdateEscalationStreak(e, "NEEDS_MORE_WORK", nil), "unreported tasks count as the same tasks")
+	assert.Equal(t, 0, UpdateEscalationStreak(e, "INADMISSIBLE", nil))
+	assert.Nil(t, e.StreakTasks)
+}
@@ -0,0 +1,86 @@
+package state
+
+import (
+	"fmt"
+	"time"
+)
+
+// etaWindow is how many of the latest iterations the ETA is estimated
+// from, so it follows the current pace rather than the session's average.
+const etaWindow = 5
+
+// ETA is the estimated progress of a session towards its last task.
+type ETA struct {
+	Done, Total int
+	// Known is false until an iteration of the window completed a task.
+	Known bool
+	// Iterations is the estimated number of iterations left, and
+	// Remaining how long they take.
+	Iterations int
+	Remaining  time.Duration
+	// Iteration and Impl and Val are the average durations of an
+	// iteration and of its implementation and validation phases.
+	Iteration, Impl, Val time.Duration
+}
+
+// EstimateETA estimates the remaining iterations and time from the pace of
+// the latest iterations in history: the tasks they completed and how long
+// they took. done and total are the tasks checked and in all.
+func EstimateETA(history []IterationRecord, done, total int) ETA {
+	e := ETA{Done: done, Total: total}
+	if len(history) > etaWindow {
+		history = history[len(history)-etaWindow:]
+	}
+	if len(history) == 0 {
+		return e
+	}
+	var seconds, impl, val, completed int
+	for _, r := range history {
+		seconds += r.Seconds
+		impl += r.ImplSeconds
+		val += r.ValSeconds
+		completed += r.Completed
+	}
+	n := len(history)
+	e.Iteration = time.Duration(seconds) * time.Second / time.Duration(n)
+	e.Impl = time.Duration(impl) * time.Second / time.Duration(n)
+	e.Val = time.Duration(val) * time.Second / time.Duration(n)
+	left := total - done
+	if left <= 0 {
+		e.Known = true
+		return e
+	}
+	if completed <= 0 {
+		return e
+	}
+	// ceil(left / (completed / n)) iterations at the current pace
+	e.Known = true
+	e.Iterations = (left*n + completed - 1) / completed
+	e.Remaining = time.Duration(e.Iterations) * e.Iteration
+	return e
+}
+
+// Percent is the share of the tasks that are done, 0 to 100.
+func (e ETA) Percent() int {
+	if e.Total <= 0 {
+		return 0
+	}
+	return e.Done * 100 / e.Total
+}
+
+// String summarizes the estimate, e.g.
+// "42% (5/12 tasks), ETA 1h20m0s (~4 iterations)".
+func (e ETA) String() string {
+	s := fmt.Sprintf("%d%% (%d/%d tasks)", e.Percent(), e.Done, e.Total)
+	switch {
+	case e.Done >= e.Total:
+		return s
+	case !e.Known:
+		return s + ", ETA unknown"
+	}
+	remaining := e.Remaining.Round(time.Second)
+	if remaining >= time.Minute {
+		remaining = remaining.Round(time.Minute)
+	}
+	return fmt.Sprintf("%s, ETA %s (~%d iteration(s))", s, remaining, e.Iterations)
+}
@@ -0,0 +1,49 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateETA(t *testing.T) {
+	history := []IterationRecord{
+		{Iteration: 1, Seconds: 9000, ImplSeconds: 8000, ValSeconds: 1000, Completed: 0},
+		{Iteration: 2, Seconds: 600, ImplSeconds: 400, ValSeconds: 200, Completed: 2},
+		{Iteration: 3, Seconds: 1200, ImplSeconds: 900, ValSeconds: 300, Completed: 1},
+	}
+	e := EstimateETA(history, 3, 12)
+	assert.True(t, e.Known)
+	assert.Equal(t, 25, e.Percent())
+	assert.Equal(t, 9, e.Iterations, "3 tasks in 3 iterations leaves 9 iterations for 9 tasks")
+	assert.Equal(t, 3600*time.Second, e.Iteration)
+	assert.Equal(t, 3100*time.Second, e.Impl)
+	assert.Equal(t, 500*time.Second, e.Val)
+	assert.Equal(t, 9*time.Hour, e.Remaining)
+	assert.Equal(t, "25% (3/12 tasks), ETA 9h0m0s (~9 iteration(s))", e.String())
+
+	// Only the latest iterations set the pace.
+	for i := 4; i <= 8; i++ {
+		history = append(history, IterationRecord{Iteration: i, Seconds: 60, Completed: 1})
+	}
+	e = EstimateETA(history, 8, 12)
+	assert.Equal(t, 4, e.Iterations)
+	assert.Equal(t, 4*time.Minute, e.Remaining)
+}
+
+func TestEstimateETA_Unknown(t *testing.T) {
+	e := EstimateETA(nil, 0, 4)
+	assert.False(t, e.Known)
+	assert.Equal(t, "0% (0/4 tasks), ETA unknown", e.String())
+
+	e = EstimateETA([]IterationRecord{{Iteration: 1, Seconds: 60}}, 1, 4)
+	assert.False(t, e.Known, "no task completed yet")
+	assert.Equal(t, time.Minute, e.Iteration)
+
+	e = EstimateETA([]IterationRecord{{Iteration: 1, Seconds: 60, Completed: 4}}, 4, 4)
+	assert.True(t, e.Known)
+	assert.Equal(t, "100% (4/4 tasks)", e.String())
+
+	assert.Zero(t, EstimateETA(nil, 0, 0).Percent())
+}
@@ -0,0 +1,140 @@
+package state
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+const (
+	// MaxFeedbackIssues caps the tracked feedback issues; the ones raised
+	// longest ago are dropped first.
+	MaxFeedbackIssues = 20
+	// FeedbackIssueTTL is the number of iterations an issue is kept after
+	// the feedback last raised it.
+	FeedbackIssueTTL = 3
+	// minIssueLen skips feedback lines too short to be an issue, such as
+	// "Issues:" headings.
+	minIssueLen = 12
+	// sameIssueOverlap is the share of words two issues must have in
+	// common to count as the same issue reworded.
+	sameIssueOverlap = 0.7
+)
+
+// FeedbackIssue is one point of validator feedback, tracked across
+// iterations until it is resolved.
+type FeedbackIssue struct {
+	Text           string `json:"text"` // latest wording
+	FirstIteration int    `json:"first_iteration"`
+	LastIteration  int    `json:"last_iteration"`
+	Count          int    `json:"count"` // iterations whose feedback raised it
+}
+
+var (
+	bulletRE = regexp.MustCompile(`^(?:[-*•]|\d+[.)])\s+`)
+	wordRE   = regexp.MustCompile(`[\p{L}\p{N}_]{3,}`)
+)
+
+// SplitFeedback breaks feedback into issues: one per line, without list
+// markers, skipping headings and lines too short to say anything.
+func SplitFeedback(feedback string) []string {
+	var issues []string
+	seen := map[string]bool{}
+	for _, line := range strings.Split(feedback, "\n") {
+		line = strings.TrimSpace(bulletRE.ReplaceAllString(strings.TrimSpace(line), ""))
+		if len(line) < minIssueLen || strings.HasSuffix(line, ":") {
+			continue
+		}
+		key := strings.ToLower(strings.Join(strings.Fields(line), " "))
+		if !seen[key] {
+			seen[key] = true
+			issues = append(issues, line)
+		}
+	}
+	return issues
+}
+
+// sameIssue reports whether a and b share enough words to be the same
+// issue worded differently.
+func sameIssue(a, b string) bool {
+	wa, wb := words(a), words(b)
+	if len(wa) == 0 || len(wb) == 0 {
+		return false
+	}
+	common := 0
+	for w := range wa {
+		if wb[w] {
+			common++
+		}
+	}
+	return float64(common) >= sameIssueOverlap*float64(max(len(wa), len(wb)))
+}
+
+func words(s string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range wordRE.FindAllString(strings.ToLower(s), -1) {
+		set[w] = true
+	}
+	return set
+}
+
+// RecordFeedback merges the issues of the feedback given in iteration into
+// the session's tracked issues, and forgets the ones no feedback has raised
+// for FeedbackIssueTTL iterations.
+func RecordFeedback(s *SessionState, iteration int, feedback string) {
+	for _, text := range SplitFeedback(feedback) {
+		var issue *FeedbackIssue
+		for i := range s.FeedbackIssues {
+			if sameIssue(s.FeedbackIssues[i].Text, text) {
+				issue = &s.FeedbackIssues[i]
+				break
+			}
+		}
+		if issue == nil {
+			s.FeedbackIssues = append(s.FeedbackIssues, FeedbackIssue{Text: text, FirstIteration: iteration})
+			issue = &s.FeedbackIssues[len(s.FeedbackIssues)-1]
+		}
+		issue.Text = text
+		if issue.LastIteration != iteration {
+			issue.LastIteration = iteration
+			issue.Count++
+		}
+	}
+
+	kept := s.FeedbackIssues[:0]
+	for _, issue := range s.FeedbackIssues {
+		if iteration-issue.LastIteration < FeedbackIssueTTL {
+			kept = append(kept, issue)
+		}
+	}
+	for len(kept) > MaxFeedbackIssues {
+		oldest := 0
+		for i := range kept {
+			if kept[i].LastIteration < kept[oldest].LastIteration {
+				oldest = i
+			}
+		}
+		kept = slices.Delete(kept, oldest, oldest+1)
+	}
+	s.FeedbackIssues = kept
+}
+
+// ResolveFeedback forgets the tracked issues about a task that is now
+// checked in list.
+func ResolveFeedback(s *SessionState, list []tasks.Task) {
+	done := map[string]bool{}
+	for _, t := range list {
+		if t.Checked {
+			done[t.ID] = true
+		}
+	}
+	kept := s.FeedbackIssues[:0]
+	for _, issue := range s.FeedbackIssues {
+		if id := tasks.ExtractTaskID(issue.Text); id == "" || !done[id] {
+			kept = append(kept, issue)
+		}
+	}
+	s.FeedbackIssues = kept
+}
@@ -0,0 +1,52 @@
+package state
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+func TestSplitFeedback(t *testing.T) {
+	feedback := "Issues found:\n- T002: the login test still fails\n* Missing error handling in parser.go\n\n1. T002: the login test still fails\nok\n"
+	assert.Equal(t, []string{"T002: the login test still fails", "Missing error handling in parser.go"}, SplitFeedback(feedback))
+	assert.Empty(t, SplitFeedback(""))
+}
+
+func TestRecordFeedback_TracksRecurringIssues(t *testing.T) {
+	s := &SessionState{}
+	RecordFeedback(s, 1, "- T002: the login test still fails\n- README not updated")
+	RecordFeedback(s, 2, "- T002: the login test still fails with a timeout")
+	require.Len(t, s.FeedbackIssues, 2)
+	assert.Equal(t, FeedbackIssue{Text: "T002: the login test still fails with a timeout", FirstIteration: 1, LastIteration: 2, Count: 2}, s.FeedbackIssues[0])
+	assert.Equal(t, FeedbackIssue{Text: "README not updated", FirstIteration: 1, LastIteration: 1, Count: 1}, s.FeedbackIssues[1])
+
+	// An issue no feedback raises for FeedbackIssueTTL iterations is dropped.
+	RecordFeedback(s, 4, "")
+	require.Len(t, s.FeedbackIssues, 1)
+	assert.Equal(t, 2, s.FeedbackIssues[0].Count)
+}
+
+func TestRecordFeedback_Cap(t *testing.T) {
+	s := &SessionState{}
+	RecordFeedback(s, 1, "- the oldest issue of them all")
+	var feedback string
+	for i := 0; i < MaxFeedbackIssues; i++ {
+		feedback += fmt.Sprintf("- module%d breaks handler%d in case%d\n", i, i, i)
+	}
+	RecordFeedback(s, 2, feedback)
+	assert.Len(t, s.FeedbackIssues, MaxFeedbackIssues)
+	assert.Equal(t, 2, s.FeedbackIssues[0].LastIteration)
+}
+
+func TestResolveFeedback(t *testing.T) {
+	s := &SessionState{}
+	RecordFeedback(s, 1, "- T001: handler returns 500\n- T002: missing migration\n- general: lint errors remain")
+	ResolveFeedback(s, []tasks.Task{{ID: "T001", Checked: true}, {ID: "T002"}})
+	require.Len(t, s.FeedbackIssues, 2)
+	assert.Equal(t, "T002: missing migration", s.FeedbackIssues[0].Text)
+	assert.Equal(t, "general: lint errors remain", s.FeedbackIssues[1].Text)
+}
@@ -0,0 +1,43 @@
+package state
+
+import (
+	"strings"
+	"time"
+)
+
+// AddHumanGuidance records message as guidance for the next implementation
+// prompt. Blank messages are ignored.
+func AddHumanGuidance(s *SessionState, message string, now time.Time) bool {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return false
+	}
+	s.HumanGuidance = append(s.HumanGuidance, HumanGuidance{
+		Message:    message,
+		RecordedAt: now.Format(time.RFC3339),
+		Iteration:  s.Iteration,
+	})
+	return true
+}
+
+// PendingGuidance returns the messages not yet given to the implementer,
+// oldest first.
+func PendingGuidance(s *SessionState) []string {
+	var pending []string
+	for _, g := range s.HumanGuidance {
+		if g.AppliedIteration == 0 {
+			pending = append(pending, g.Message)
+		}
+	}
+	return pending
+}
+
+// MarkGuidanceApplied records that the pending guidance reached the
+// implementer in iteration.
+func MarkGuidanceApplied(s *SessionState, iteration int) {
+	for i := range s.HumanGuidance {
+		if s.HumanGuidance[i].AppliedIteration == 0 {
+			s.HumanGuidance[i].AppliedIteration = iteration
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHumanGuidance_Lifecycle(t *testing.T) {
+	now := time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)
+	s := &SessionState{Iteration: 3}
+
+	assert.False(t, AddHumanGuidance(s, "  \n", now))
+	assert.True(t, AddHumanGuidance(s, " use approach B \n", now))
+	assert.True(t, AddHumanGuidance(s, "keep the v1 API", now))
+	require.Len(t, s.HumanGuidance, 2)
+	assert.Equal(t, HumanGuidance{Message: "use approach B", RecordedAt: "2026-05-01T09:30:00Z", Iteration: 3}, s.HumanGuidance[0])
+	assert.Equal(t, []string{"use approach B", "keep the v1 API"}, PendingGuidance(s))
+
+	MarkGuidanceApplied(s, 4)
+	assert.Empty(t, PendingGuidance(s))
+	assert.True(t, AddHumanGuidance(s, "drop T007", now))
+	assert.Equal(t, []string{"drop T007"}, PendingGuidance(s))
+
+	MarkGuidanceApplied(s, 6)
+	assert.Equal(t, 4, s.HumanGuidance[0].AppliedIteration, "already applied guidance keeps its iteration")
+	assert.Equal(t, 6, s.HumanGuidance[2].AppliedIteration)
+}
@@ -0,0 +1,111 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/secrets"
+	"github.com/CodexForgeBR/cli-tools/internal/statecrypt"
+)
+
+// Files kept in an iteration directory for `ralph-loop diff` and
+// `ralph-loop rollback`.
+const (
+	IterationVerdictFile = "verdict.json"
+	IterationDiffFile    = "changes.diff"
+	IterationStartFile   = "start-state.json"
+)
+
+// IterationVerdict is how a judged iteration was judged and what it
+// changed, as shown by `ralph-loop diff`.
+type IterationVerdict struct {
+	Iteration int    `json:"iteration"`
+	Verdict   string `json:"verdict"`
+	Feedback  string `json:"feedback,omitempty"`
+	// DiffStat is the --stat summary of changes.diff; empty when no diff
+	// was captured.
+	DiffStat string `json:"diff_stat,omitempty"`
+	At       string `json:"at,omitempty"`
+}
+
+// SaveIterationVerdict writes v to iterDir/verdict.json.
+func SaveIterationVerdict(iterDir string, v IterationVerdict) error {
+	data, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return fmt.Errorf("marshal verdict: %w", err)
+	}
+	if err := os.MkdirAll(iterDir, 0755); err != nil {
+		return fmt.Errorf("create iteration dir: %w", err)
+	}
+	return statecrypt.WriteFile(filepath.Join(iterDir, IterationVerdictFile), secrets.RedactBytes(data), 0644)
+}
+
+// LoadIterationVerdict reads iterDir/verdict.json.
+func LoadIterationVerdict(iterDir string) (*IterationVerdict, error) {
+	data, err := statecrypt.ReadFile(filepath.Join(iterDir, IterationVerdictFile))
+	if err != nil {
+		return nil, err
+	}
+	var v IterationVerdict
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("unmarshal verdict: %w", err)
+	}
+	return &v, nil
+}
+
+// FileHistory returns the judged iterations of s that changed path, a
+// slash-separated path relative to the project root, or a file under it
+// when path is a directory, oldest first.
+func FileHistory(s *SessionState, path string) []IterationRecord {
+	path = strings.TrimSuffix(path, "/")
+	var found []IterationRecord
+	for _, h := range s.History {
+		for _, f := range h.Files {
+			if f == path || path == "." || strings.HasPrefix(f, path+"/") {
+				found = append(found, h)
+				break
+			}
+		}
+	}
+	return found
+}
+
+// IterationStart is the session as an iteration found it, kept in the
+// iteration directory so `ralph-loop rollback` can return to it.
+type IterationStart struct {
+	// Tree is the gitdiff snapshot of the working tree; empty when the
+	// project is not a git repository.
+	Tree    string        `json:"tree,omitempty"`
+	Session *SessionState `json:"session"`
+}
+
+// SaveIterationStart writes st to iterDir/start-state.json.
+func SaveIterationStart(iterDir string, st IterationStart) error {
+	data, err := json.MarshalIndent(st, "", "    ")
+	if err != nil {
+		return fmt.Errorf("marshal iteration start: %w", err)
+	}
+	if err := os.MkdirAll(iterDir, 0755); err != nil {
+		return fmt.Errorf("create iteration dir: %w", err)
+	}
+	return statecrypt.WriteFile(filepath.Join(iterDir, IterationStartFile), secrets.RedactBytes(data), 0644)
+}
+
+// LoadIterationStart reads iterDir/start-state.json.
+func LoadIterationStart(iterDir string) (*IterationStart, error) {
+	data, err := statecrypt.ReadFile(filepath.Join(iterDir, IterationStartFile))
+	if err != nil {
+		return nil, err
+	}
+	var st IterationStart
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("unmarshal iteration start: %w", err)
+	}
+	if st.Session == nil {
+		return nil, fmt.Errorf("%s holds no session", filepath.Join(iterDir, IterationStartFile))
+	}
+	return &st, nil
+}
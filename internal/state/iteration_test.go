@@ -0,0 +1,55 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterationVerdict_RoundTrip(t *testing.T) {
+	iterDir := filepath.Join(t.TempDir(), "iteration-005")
+	v := IterationVerdict{Iteration: 5, Verdict: "NEEDS_MORE_WORK", Feedback: "T003 has no test", DiffStat: " a.go | 2 +-\n"}
+	require.NoError(t, SaveIterationVerdict(iterDir, v))
+
+	got, err := LoadIterationVerdict(iterDir)
+	require.NoError(t, err)
+	assert.Equal(t, v, *got)
+
+	_, err = LoadIterationVerdict(t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestIterationStart_RoundTrip(t *testing.T) {
+	iterDir := filepath.Join(t.TempDir(), "iteration-004")
+	st := IterationStart{Tree: "4b825dc", Session: &SessionState{Iteration: 4, Phase: PhaseImplementation, LastFeedback: "Zml4"}}
+	require.NoError(t, SaveIterationStart(iterDir, st))
+
+	got, err := LoadIterationStart(iterDir)
+	require.NoError(t, err)
+	assert.Equal(t, st.Tree, got.Tree)
+	assert.Equal(t, 4, got.Session.Iteration)
+	assert.Equal(t, "Zml4", got.Session.LastFeedback)
+}
+
+func TestFileHistory(t *testing.T) {
+	s := &SessionState{History: []IterationRecord{
+		{Iteration: 1, Files: []string{"go.mod", "internal/export/csv.go"}, Tasks: []string{"T001"}},
+		{Iteration: 2, Files: []string{"README.md"}},
+		{Iteration: 3, Files: []string{"internal/export/csv.go", "internal/export/json.go"}, Tasks: []string{"T002", "T003"}},
+		{Iteration: 4},
+	}}
+
+	iterations := func(h []IterationRecord) []int {
+		var n []int
+		for _, r := range h {
+			n = append(n, r.Iteration)
+		}
+		return n
+	}
+	assert.Equal(t, []int{1, 3}, iterations(FileHistory(s, "internal/export/csv.go")))
+	assert.Equal(t, []int{1, 3}, iterations(FileHistory(s, "internal/export/")))
+	assert.Equal(t, []int{1, 2, 3}, iterations(FileHistory(s, ".")))
+	assert.Empty(t, FileHistory(s, "internal/exp"))
+}
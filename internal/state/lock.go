@@ -0,0 +1,186 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const lockFileName = "ralph-loop.lock"
+
+// A lock file that cannot be parsed may be one another process has just
+// created and not yet written; AcquireLock reads it again this many times,
+// this far apart, before giving up.
+var (
+	lockReadAttempts = 5
+	lockReadInterval = 20 * time.Millisecond
+)
+
+// LockInfo identifies the ralph-loop process holding the state lock.
+type LockInfo struct {
+	PID       int    `json:"pid"`
+	Hostname  string `json:"hostname"`
+	StartedAt string `json:"started_at"`
+}
+
+// LockedError is returned by AcquireLock when another live process holds
+// the lock.
+type LockedError struct {
+	Path   string
+	Holder LockInfo
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("another ralph-loop is running (pid %d on %s, started %s); lock file: %s",
+		e.Holder.PID, e.Holder.Hostname, e.Holder.StartedAt, e.Path)
+}
+
+// UnreadableLockError is returned by AcquireLock when the lock file stays
+// empty or corrupt, so its holder cannot be checked.
+type UnreadableLockError struct {
+	Path string
+	Err  error
+}
+
+func (e *UnreadableLockError) Error() string {
+	return fmt.Sprintf("lock file %s is unreadable (%v); if no ralph-loop is running, use --force-unlock", e.Path, e.Err)
+}
+
+func (e *UnreadableLockError) Unwrap() error { return e.Err }
+
+// Lock is a held state directory lock.
+type Lock struct {
+	path string
+	info LockInfo
+}
+
+// AcquireLock takes the lock file in the state directory so that two loops
+// cannot write current-state.json at the same time.
+//
+// A lock left behind by a process that no longer exists on this host is
+// reclaimed automatically. A lock held by a live process, or by a process on
+// another host, is only taken over when force is true; otherwise a
+// *LockedError is returned. A lock file that stays unreadable is only taken
+// over when force is true; otherwise an *UnreadableLockError is returned.
+func AcquireLock(dir string, force bool) (*Lock, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create state dir: %w", err)
+	}
+	path := filepath.Join(dir, lockFileName)
+	hostname, _ := os.Hostname()
+	info := LockInfo{PID: os.Getpid(), Hostname: hostname, StartedAt: time.Now().Format(time.RFC3339)}
+
+	// Two attempts: the second follows removal of a stale or forced lock.
+	for attempt := 0; attempt < 2; attempt++ {
+		err := writeLockFile(path, info)
+		if err == nil {
+			return &Lock{path: path, info: info}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("create lock file: %w", err)
+		}
+
+		if !force {
+			holder, readErr := readLockSettled(dir)
+			if errors.Is(readErr, os.ErrNotExist) {
+				continue // released meanwhile
+			}
+			if readErr != nil {
+				return nil, &UnreadableLockError{Path: path, Err: readErr}
+			}
+			if !holder.stale(hostname) {
+				return nil, &LockedError{Path: path, Holder: *holder}
+			}
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("remove stale lock file: %w", err)
+		}
+	}
+	return nil, fmt.Errorf("lock file %s was recreated by another process", path)
+}
+
+// writeLockFile creates path exclusively and writes info to it.
+func writeLockFile(path string, info LockInfo) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	data, _ := json.MarshalIndent(info, "", "    ")
+	_, writeErr := f.Write(data)
+	closeErr := f.Close()
+	if writeErr != nil {
+		return writeErr
+	}
+	return closeErr
+}
+
+// readLockSettled reads the lock in dir, reading it again while it cannot
+// be parsed in case its holder is still writing it.
+func readLockSettled(dir string) (*LockInfo, error) {
+	var err error
+	for i := 0; i < lockReadAttempts; i++ {
+		if i > 0 {
+			time.Sleep(lockReadInterval)
+		}
+		var holder *LockInfo
+		holder, err = ReadLock(dir)
+		if err == nil || errors.Is(err, os.ErrNotExist) {
+			return holder, err
+		}
+	}
+	return nil, err
+}
+
+// ReadLock returns the current holder of the lock in dir.
+func ReadLock(dir string) (*LockInfo, error) {
+	data, err := os.ReadFile(filepath.Join(dir, lockFileName))
+	if err != nil {
+		return nil, fmt.Errorf("read lock file: %w", err)
+	}
+	var info LockInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("parse lock file: %w", err)
+	}
+	return &info, nil
+}
+
+// RunningLoop returns the holder of the lock in dir when a loop is running
+// there, or nil when the lock is free or was left behind by a dead
+// process on this host.
+func RunningLoop(dir string) *LockInfo {
+	holder, err := ReadLock(dir)
+	if err != nil {
+		return nil
+	}
+	hostname, _ := os.Hostname()
+	if holder.stale(hostname) {
+		return nil
+	}
+	return holder
+}
+
+// stale reports whether the lock was left behind by a dead process on this
+// host. Locks from other hosts cannot be checked and are never stale.
+func (l LockInfo) stale(hostname string) bool {
+	return l.Hostname == hostname && !processAlive(l.PID)
+}
+
+// Release removes the lock file if it still belongs to this lock. It is safe
+// to call on a nil Lock.
+func (l *Lock) Release() error {
+	if l == nil {
+		return nil
+	}
+	current, err := ReadLock(filepath.Dir(l.path))
+	if err != nil || *current != l.info {
+		// Taken over by a forced AcquireLock; leave the new holder alone.
+		return nil
+	}
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove lock file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestLock(t *testing.T, dir string, info LockInfo) {
+	t.Helper()
+	data, err := json.Marshal(info)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, lockFileName), data, 0644))
+}
+
+func TestAcquireLock_WritesHolderAndReleases(t *testing.T) {
+	dir := t.TempDir()
+
+	lock, err := AcquireLock(dir, false)
+	require.NoError(t, err)
+
+	info, err := ReadLock(dir)
+	require.NoError(t, err)
+	hostname, _ := os.Hostname()
+	assert.Equal(t, os.Getpid(), info.PID)
+	assert.Equal(t, hostname, info.Hostname)
+	assert.NotEmpty(t, info.StartedAt)
+
+	require.NoError(t, lock.Release())
+	assert.NoFileExists(t, filepath.Join(dir, lockFileName))
+}
+
+func TestAcquireLock_HeldByLiveProcess(t *testing.T) {
+	dir := t.TempDir()
+	first, err := AcquireLock(dir, false)
+	require.NoError(t, err)
+	defer first.Release()
+
+	_, err = AcquireLock(dir, false)
+	var locked *LockedError
+	require.True(t, errors.As(err, &locked))
+	assert.Equal(t, os.Getpid(), locked.Holder.PID)
+	assert.Contains(t, err.Error(), "another ralph-loop is running")
+}
+
+func TestAcquireLock_OtherHostNeedsForce(t *testing.T) {
+	dir := t.TempDir()
+	writeTestLock(t, dir, LockInfo{PID: 1, Hostname: "elsewhere.example", StartedAt: "2026-01-01T00:00:00Z"})
+
+	_, err := AcquireLock(dir, false)
+	var locked *LockedError
+	require.True(t, errors.As(err, &locked))
+	assert.Equal(t, "elsewhere.example", locked.Holder.Hostname)
+
+	lock, err := AcquireLock(dir, true)
+	require.NoError(t, err)
+	info, err := ReadLock(dir)
+	require.NoError(t, err)
+	assert.Equal(t, os.Getpid(), info.PID)
+	require.NoError(t, lock.Release())
+}
+
+func TestAcquireLock_ReclaimsStaleLock(t *testing.T) {
+	dir := t.TempDir()
+	hostname, _ := os.Hostname()
+	// PIDs are capped well below this value on every supported platform.
+	writeTestLock(t, dir, LockInfo{PID: 1 << 30, Hostname: hostname, StartedAt: "2026-01-01T00:00:00Z"})
+
+	lock, err := AcquireLock(dir, false)
+	require.NoError(t, err)
+	require.NoError(t, lock.Release())
+}
+
+func TestAcquireLock_CorruptLockNeedsForce(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, lockFileName), []byte("not json"), 0644))
+
+	_, err := AcquireLock(dir, false)
+	var unreadable *UnreadableLockError
+	require.True(t, errors.As(err, &unreadable))
+	assert.Contains(t, err.Error(), "--force-unlock")
+	assert.FileExists(t, filepath.Join(dir, lockFileName))
+
+	lock, err := AcquireLock(dir, true)
+	require.NoError(t, err)
+	require.NoError(t, lock.Release())
+}
+
+func TestAcquireLock_EmptyLockIsNotReclaimed(t *testing.T) {
+	dir := t.TempDir()
+	// What another process leaves between creating the file and writing it.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, lockFileName), nil, 0644))
+
+	_, err := AcquireLock(dir, false)
+	var unreadable *UnreadableLockError
+	require.True(t, errors.As(err, &unreadable))
+	data, err := os.ReadFile(filepath.Join(dir, lockFileName))
+	require.NoError(t, err)
+	assert.Empty(t, data, "the other process's lock file is left alone")
+}
+
+func TestAcquireLock_WaitsForLockBeingWritten(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, lockFileName)
+	require.NoError(t, os.WriteFile(path, nil, 0644))
+	hostname, _ := os.Hostname()
+	go func() {
+		time.Sleep(lockReadInterval / 2)
+		data, _ := json.Marshal(LockInfo{PID: os.Getpid(), Hostname: hostname, StartedAt: "2026-01-01T00:00:00Z"})
+		_ = os.WriteFile(path, data, 0644)
+	}()
+
+	_, err := AcquireLock(dir, false)
+	var locked *LockedError
+	require.True(t, errors.As(err, &locked))
+	assert.Equal(t, os.Getpid(), locked.Holder.PID)
+}
+
+func TestLockRelease_LeavesForcedTakeoverAlone(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := AcquireLock(dir, false)
+	require.NoError(t, err)
+
+	other := LockInfo{PID: 42, Hostname: "elsewhere.example", StartedAt: "2026-01-01T00:00:00Z"}
+	writeTestLock(t, dir, other)
+
+	require.NoError(t, lock.Release())
+	info, err := ReadLock(dir)
+	require.NoError(t, err)
+	assert.Equal(t, other, *info)
+
+	var nilLock *Lock
+	assert.NoError(t, nilLock.Release())
+}
+
+func TestRunningLoop(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, RunningLoop(dir), "no lock")
+
+	lock, err := AcquireLock(dir, false)
+	require.NoError(t, err)
+	holder := RunningLoop(dir)
+	require.NotNil(t, holder)
+	assert.Equal(t, os.Getpid(), holder.PID)
+	require.NoError(t, lock.Release())
+
+	hostname, _ := os.Hostname()
+	writeTestLock(t, dir, LockInfo{PID: 1 << 30, Hostname: hostname})
+	assert.Nil(t, RunningLoop(dir), "stale lock")
+
+	writeTestLock(t, dir, LockInfo{PID: 1 << 30, Hostname: "elsewhere.example"})
+	assert.NotNil(t, RunningLoop(dir), "locks from other hosts cannot be checked")
+}
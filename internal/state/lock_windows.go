@@ -0,0 +1,19 @@
+//go:build windows
+
+package state
+
+import "os"
+
+// processAlive reports whether a process with the given pid exists.
+// On Windows FindProcess opens a handle and fails for unknown pids.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
@@ -6,12 +6,16 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/CodexForgeBR/cli-tools/internal/secrets"
+	"github.com/CodexForgeBR/cli-tools/internal/statecrypt"
 	"github.com/CodexForgeBR/cli-tools/internal/tasks"
 )
 
 const stateFileName = "current-state.json"
 
-// SaveState persists the session state as indented JSON.
+// SaveState persists the session state as indented JSON, with the
+// registered secrets redacted (see secrets.Register) and encrypted when a
+// state key is set (see statecrypt).
 func SaveState(s *SessionState, dir string) error {
 	// Marshal with 4-space indent
 	data, err := json.MarshalIndent(s, "", "    ")
@@ -25,17 +29,18 @@ func SaveState(s *SessionState, dir string) error {
 	}
 
 	path := filepath.Join(dir, stateFileName)
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := statecrypt.WriteFile(path, secrets.RedactBytes(data), 0644); err != nil {
 		return fmt.Errorf("write state file: %w", err)
 	}
 
 	return nil
 }
 
-// LoadState reads and parses the session state from the state directory.
+// LoadState reads and parses the session state from the state directory,
+// decrypting it when it is encrypted.
 func LoadState(dir string) (*SessionState, error) {
 	path := filepath.Join(dir, stateFileName)
-	data, err := os.ReadFile(path)
+	data, err := statecrypt.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read state file: %w", err)
 	}
@@ -10,8 +10,58 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/secrets"
+	"github.com/CodexForgeBR/cli-tools/internal/statecrypt"
 )
 
+// TestSaveState_RedactsSecrets validates that registered secrets never
+// reach the state file, even JSON-escaped.
+func TestSaveState_RedactsSecrets(t *testing.T) {
+	secrets.Register("https://hooks.example.com/T0?a=1&b=2")
+	t.Cleanup(secrets.Reset)
+	dir := t.TempDir()
+	s := &SessionState{SessionID: "ralph-1", LastFeedback: "The webhook https://hooks.example.com/T0?a=1&b=2 is hardcoded"}
+	require.NoError(t, SaveState(s, dir))
+
+	data, err := os.ReadFile(filepath.Join(dir, stateFileName))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "hooks.example.com")
+	loaded, err := LoadState(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "The webhook [REDACTED] is hardcoded", loaded.LastFeedback)
+}
+
+// TestSaveState_Encrypts validates that, with a state key, the state is
+// encrypted on disk and decrypted on load, and that a plain state written
+// before the key was set still loads.
+func TestSaveState_Encrypts(t *testing.T) {
+	key, err := statecrypt.ParseKey("c3RhdGUta2V5LXN0YXRlLWtleS1zdGF0ZS1rZXktMzI=")
+	require.NoError(t, err)
+	dir := t.TempDir()
+	require.NoError(t, SaveState(&SessionState{SessionID: "ralph-0", Iteration: 1}, dir))
+
+	statecrypt.SetKey(key)
+	t.Cleanup(func() { statecrypt.SetKey(nil) })
+	loaded, err := LoadState(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "ralph-0", loaded.SessionID)
+
+	s := &SessionState{SessionID: "ralph-1", LastFeedback: "func proprietary() {}"}
+	require.NoError(t, SaveState(s, dir))
+	data, err := os.ReadFile(filepath.Join(dir, stateFileName))
+	require.NoError(t, err)
+	assert.True(t, statecrypt.IsEncrypted(data))
+	assert.NotContains(t, string(data), "proprietary")
+	loaded, err = LoadState(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "func proprietary() {}", loaded.LastFeedback)
+
+	statecrypt.SetKey(nil)
+	_, err = LoadState(dir)
+	assert.ErrorIs(t, err, statecrypt.ErrNoKey)
+}
+
 // TestSaveState validates that SaveState writes valid JSON with proper formatting
 func TestSaveState(t *testing.T) {
 	tests := []struct {
@@ -0,0 +1,163 @@
+package state
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// projectConfigFile is the project config file, which stays in the
+// in-repo state directory when the state moves out of it.
+const projectConfigFile = "config"
+
+// MigrateStateDir moves the session state kept in from, the in-repo state
+// directory, to the state directory to that STATE_DIR now sets, merging
+// into what to already holds, and returns the names it moved. The project
+// config file stays in from. A learnings file the session recorded in
+// from is recorded at its new place. Nothing is moved while a loop runs
+// on from, or when both hold a same file, such as two sessions.
+func MigrateStateDir(from, to string) ([]string, error) {
+	absFrom, err := filepath.Abs(from)
+	if err != nil {
+		return nil, err
+	}
+	absTo, err := filepath.Abs(to)
+	if err != nil {
+		return nil, err
+	}
+	if absFrom == absTo {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(absFrom)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.Name() != projectConfigFile {
+			names = append(names, e.Name())
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	if holder := RunningLoop(absFrom); holder != nil {
+		return nil, fmt.Errorf("cannot move the state in %s to %s while a loop (pid %d) runs on it", from, to, holder.PID)
+	}
+	for _, name := range names {
+		if conflict := firstConflict(filepath.Join(absFrom, name), filepath.Join(absTo, name)); conflict != "" {
+			return nil, fmt.Errorf("cannot move the state in %s to %s: both hold %s; remove one of them", from, to, conflict)
+		}
+	}
+
+	if err := os.MkdirAll(absTo, 0755); err != nil {
+		return nil, fmt.Errorf("create state dir: %w", err)
+	}
+	for _, name := range names {
+		if err := moveEntry(filepath.Join(absFrom, name), filepath.Join(absTo, name)); err != nil {
+			return nil, fmt.Errorf("move %s to %s: %w", name, to, err)
+		}
+	}
+	_ = os.Remove(absFrom) // kept when the config file is left in it
+
+	if s, err := LoadState(absTo); err == nil && s.Learnings.File != "" {
+		if abs, err := filepath.Abs(s.Learnings.File); err == nil {
+			if rel, err := filepath.Rel(absFrom, abs); err == nil && !strings.HasPrefix(rel, "..") {
+				s.Learnings.File = filepath.Join(absTo, rel)
+				if err := SaveState(s, absTo); err != nil {
+					return names, err
+				}
+			}
+		}
+	}
+	return names, nil
+}
+
+// firstConflict returns the first file under src that also exists under
+// dst, or "". Directories in both are merged, so they do not conflict.
+func firstConflict(src, dst string) string {
+	dstInfo, err := os.Stat(dst)
+	if err != nil {
+		return ""
+	}
+	srcInfo, err := os.Stat(src)
+	if err != nil || !srcInfo.IsDir() || !dstInfo.IsDir() {
+		return dst
+	}
+	entries, _ := os.ReadDir(src)
+	for _, e := range entries {
+		if c := firstConflict(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name())); c != "" {
+			return c
+		}
+	}
+	return ""
+}
+
+// moveEntry moves src to dst, merging directories dst already has, and
+// copying when they are on different file systems.
+func moveEntry(src, dst string) error {
+	if info, err := os.Stat(dst); err == nil && info.IsDir() {
+		entries, err := os.ReadDir(src)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := moveEntry(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name())); err != nil {
+				return err
+			}
+		}
+		return os.Remove(src)
+	}
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	if err := copyTree(src, dst); err != nil {
+		_ = os.RemoveAll(dst)
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+// copyTree copies the file or directory src to dst.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		in, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, in); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	})
+}
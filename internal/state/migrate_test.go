@@ -0,0 +1,80 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeStateFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestMigrateStateDir(t *testing.T) {
+	root := t.TempDir()
+	from := filepath.Join(root, ".ralph-loop")
+	to := filepath.Join(t.TempDir(), "state", "ralph-loop", "shop-api-0123456789ab")
+	writeStateFile(t, filepath.Join(from, "config"), "MAX_ITERATIONS=5\n")
+	writeStateFile(t, filepath.Join(from, "learnings.md"), "# Learnings\n")
+	writeStateFile(t, filepath.Join(from, "iteration-001", "implementation-output.txt"), "done")
+	writeStateFile(t, filepath.Join(from, SessionsDir, "ralph-1.json"), `{"session_id": "ralph-1"}`)
+	writeStateFile(t, filepath.Join(to, SessionsDir, "ralph-0.json"), `{"session_id": "ralph-0"}`)
+	s := &SessionState{SessionID: "ralph-2", Iteration: 1}
+	s.Learnings.File = filepath.Join(from, "learnings.md")
+	require.NoError(t, SaveState(s, from))
+
+	moved, err := MigrateStateDir(from, to)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"current-state.json", "iteration-001", "learnings.md", SessionsDir}, moved)
+
+	assert.FileExists(t, filepath.Join(from, "config"), "the project config stays")
+	assert.NoFileExists(t, filepath.Join(from, "current-state.json"))
+	assert.FileExists(t, filepath.Join(to, "iteration-001", "implementation-output.txt"))
+	assert.FileExists(t, filepath.Join(to, SessionsDir, "ralph-0.json"))
+	assert.FileExists(t, filepath.Join(to, SessionsDir, "ralph-1.json"), "directories are merged")
+	loaded, err := LoadState(to)
+	require.NoError(t, err)
+	assert.Equal(t, "ralph-2", loaded.SessionID)
+	assert.Equal(t, filepath.Join(to, "learnings.md"), loaded.Learnings.File, "the learnings file is recorded at its new place")
+
+	moved, err = MigrateStateDir(from, to)
+	require.NoError(t, err)
+	assert.Empty(t, moved, "nothing is left to move")
+}
+
+func TestMigrateStateDir_NothingToMove(t *testing.T) {
+	dir := t.TempDir()
+	moved, err := MigrateStateDir(filepath.Join(dir, "missing"), filepath.Join(dir, "state"))
+	require.NoError(t, err)
+	assert.Empty(t, moved)
+	assert.NoDirExists(t, filepath.Join(dir, "state"))
+
+	moved, err = MigrateStateDir(dir, dir+string(filepath.Separator))
+	require.NoError(t, err)
+	assert.Empty(t, moved, "the same directory")
+}
+
+func TestMigrateStateDir_Refuses(t *testing.T) {
+	from := filepath.Join(t.TempDir(), ".ralph-loop")
+	to := t.TempDir()
+	require.NoError(t, SaveState(&SessionState{SessionID: "ralph-1"}, from))
+	require.NoError(t, SaveState(&SessionState{SessionID: "ralph-2"}, to))
+
+	_, err := MigrateStateDir(from, to)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "both hold "+filepath.Join(to, "current-state.json"))
+	assert.FileExists(t, filepath.Join(from, "current-state.json"), "nothing was moved")
+
+	require.NoError(t, os.Remove(filepath.Join(to, "current-state.json")))
+	lock, err := AcquireLock(from, false)
+	require.NoError(t, err)
+	defer lock.Release()
+	_, err = MigrateStateDir(from, to)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "while a loop")
+}
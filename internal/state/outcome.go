@@ -0,0 +1,124 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/secrets"
+	"github.com/CodexForgeBR/cli-tools/internal/statecrypt"
+)
+
+// SessionsDir is the subdirectory of the state directory where every
+// session that ran leaves its SessionOutcome, as <session-id>.json. It
+// survives --clean, so later sessions of the project can learn from it.
+const SessionsDir = "sessions"
+
+// SessionOutcome is what a session leaves for the later sessions of the
+// project: how it ended, what it escalated on and blocked, and what it
+// learned.
+type SessionOutcome struct {
+	SessionID  string     `json:"session_id"`
+	StartedAt  string     `json:"started_at"`
+	EndedAt    string     `json:"ended_at"`
+	TasksFile  string     `json:"tasks_file"`
+	Iterations int        `json:"iterations"`
+	Exit       *ExitState `json:"exit,omitempty"`
+	// Blocked are the tasks the session left blocked, as "ID text: reason".
+	Blocked []string `json:"blocked,omitempty"`
+	// Guidance are the decisions recorded with `ralph-loop respond`.
+	Guidance []string `json:"guidance,omitempty"`
+	// Learnings are the entries of the learnings file when it ended.
+	Learnings []string `json:"learnings,omitempty"`
+}
+
+// Escalation returns why the session escalated to a human, or "" when it
+// did not end that way.
+func (o SessionOutcome) Escalation() string {
+	if o.Exit == nil || o.Exit.Name != "Escalate" {
+		return ""
+	}
+	if o.Exit.Reason == "" {
+		return "no reason given"
+	}
+	return o.Exit.Reason
+}
+
+// NewSessionOutcome summarizes s, which ended at endedAt with learnings
+// in its learnings file.
+func NewSessionOutcome(s *SessionState, endedAt string, learnings []string) SessionOutcome {
+	o := SessionOutcome{
+		SessionID:  s.SessionID,
+		StartedAt:  s.StartedAt,
+		EndedAt:    endedAt,
+		TasksFile:  s.TasksFile,
+		Iterations: s.Iteration,
+		Exit:       s.Exit,
+		Learnings:  learnings,
+	}
+	for _, t := range s.Tasks {
+		if t.Status != TaskBlocked {
+			continue
+		}
+		line := strings.TrimSpace(t.ID + " " + t.Text)
+		if t.BlockedReason != "" {
+			line += ": " + t.BlockedReason
+		}
+		o.Blocked = append(o.Blocked, line)
+	}
+	for _, g := range s.HumanGuidance {
+		o.Guidance = append(o.Guidance, g.Message)
+	}
+	return o
+}
+
+// SaveSessionOutcome writes o to stateDir/sessions/<session-id>.json,
+// replacing what an earlier run of the same session wrote.
+func SaveSessionOutcome(stateDir string, o SessionOutcome) error {
+	if o.SessionID == "" {
+		return fmt.Errorf("session outcome has no session ID")
+	}
+	data, err := json.MarshalIndent(o, "", "    ")
+	if err != nil {
+		return fmt.Errorf("marshal session outcome: %w", err)
+	}
+	dir := filepath.Join(stateDir, SessionsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create sessions dir: %w", err)
+	}
+	return statecrypt.WriteFile(filepath.Join(dir, o.SessionID+".json"), secrets.RedactBytes(data), 0644)
+}
+
+// LoadSessionOutcomes reads the outcomes in stateDir/sessions, the one
+// that ended first first. Unreadable files are skipped; no directory
+// means no outcome.
+func LoadSessionOutcomes(stateDir string) ([]SessionOutcome, error) {
+	dir := filepath.Join(stateDir, SessionsDir)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var outcomes []SessionOutcome
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		data, err := statecrypt.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		var o SessionOutcome
+		if json.Unmarshal(data, &o) != nil || o.SessionID == "" {
+			continue
+		}
+		outcomes = append(outcomes, o)
+	}
+	sort.SliceStable(outcomes, func(i, j int) bool { return outcomes[i].EndedAt < outcomes[j].EndedAt })
+	return outcomes, nil
+}
@@ -0,0 +1,61 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSessionOutcome(t *testing.T) {
+	s := &SessionState{
+		SessionID: "ralph-1",
+		StartedAt: "2026-10-01T09:00:00Z",
+		TasksFile: "tasks.md",
+		Iteration: 7,
+		Exit:      &ExitState{Code: 3, Name: "Escalate", Reason: "Needs a product decision on refunds"},
+		Tasks: []TaskState{
+			{ID: "T001", Text: "Add refunds", Status: TaskDone},
+			{ID: "T002", Text: "Call the payment API", Status: TaskBlocked, BlockedReason: "no sandbox credentials"},
+		},
+		HumanGuidance: []HumanGuidance{{Message: "Use the v2 payment API", Iteration: 5}},
+	}
+	o := NewSessionOutcome(s, "2026-10-01T10:00:00Z", []string{"- Pattern: run make gen first"})
+	assert.Equal(t, SessionOutcome{
+		SessionID:  "ralph-1",
+		StartedAt:  "2026-10-01T09:00:00Z",
+		EndedAt:    "2026-10-01T10:00:00Z",
+		TasksFile:  "tasks.md",
+		Iterations: 7,
+		Exit:       s.Exit,
+		Blocked:    []string{"T002 Call the payment API: no sandbox credentials"},
+		Guidance:   []string{"Use the v2 payment API"},
+		Learnings:  []string{"- Pattern: run make gen first"},
+	}, o)
+	assert.Equal(t, "Needs a product decision on refunds", o.Escalation())
+
+	o.Exit = &ExitState{Code: 0, Name: "Success"}
+	assert.Empty(t, o.Escalation())
+	o.Exit = nil
+	assert.Empty(t, o.Escalation())
+}
+
+func TestSessionOutcomes_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	outcomes, err := LoadSessionOutcomes(dir)
+	require.NoError(t, err)
+	assert.Empty(t, outcomes)
+
+	later := SessionOutcome{SessionID: "ralph-2", EndedAt: "2026-10-02T10:00:00Z", Iterations: 3}
+	earlier := SessionOutcome{SessionID: "ralph-1", EndedAt: "2026-10-01T10:00:00Z", Blocked: []string{"T002"}}
+	require.NoError(t, SaveSessionOutcome(dir, later))
+	require.NoError(t, SaveSessionOutcome(dir, earlier))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, SessionsDir, "broken.json"), []byte("{"), 0644))
+	assert.Error(t, SaveSessionOutcome(dir, SessionOutcome{}))
+
+	outcomes, err = LoadSessionOutcomes(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []SessionOutcome{earlier, later}, outcomes)
+}
@@ -0,0 +1,17 @@
+package state
+
+// RecordPolicyViolations counts one violation of each named policy for the
+// current iteration; a policy named twice counts once.
+func RecordPolicyViolations(s *SessionState, names []string) {
+	seen := map[string]bool{}
+	for _, name := range names {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if s.PolicyViolations == nil {
+			s.PolicyViolations = map[string]int{}
+		}
+		s.PolicyViolations[name]++
+	}
+}
@@ -0,0 +1,17 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordPolicyViolations(t *testing.T) {
+	s := &SessionState{}
+	RecordPolicyViolations(s, nil)
+	assert.Nil(t, s.PolicyViolations)
+
+	RecordPolicyViolations(s, []string{"trivial-tests", "", "trivial-tests", "mock-subject"})
+	RecordPolicyViolations(s, []string{"trivial-tests"})
+	assert.Equal(t, map[string]int{"trivial-tests": 2, "mock-subject": 1}, s.PolicyViolations)
+}
@@ -10,8 +10,9 @@ import (
 // (unless force is true), then updates the session status to IN_PROGRESS to
 // allow the orchestrator to continue from where it left off.
 //
-// Phase-aware continuation logic:
-//   - cross_validation: Resume at cross-validation phase (skips impl+val)
+// Phase-aware continuation logic (requires a Checkpoint for the saved
+// iteration whose output files still exist):
+//   - cross_validation: Resume at post-validation (skips impl+val)
 //   - validation: Resume at validation phase (skips impl)
 //   - implementation: Resume at implementation phase (full iteration restart)
 //   - waiting_for_schedule: Resume schedule wait (checks if time has passed)
@@ -0,0 +1,114 @@
+package state
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Retention is how many iteration directories a session keeps; the zero
+// value keeps them all.
+type Retention struct {
+	// Keep is the number of most recent iterations kept; 0 keeps all.
+	Keep int
+	// MaxBytes is the total size of the iteration directories kept, the
+	// newest first; 0 sets no limit. The newest is kept however large.
+	MaxBytes int64
+}
+
+// IterationDir returns the directory of iteration n in stateDir.
+func IterationDir(stateDir string, n int) string {
+	return filepath.Join(stateDir, fmt.Sprintf("iteration-%03d", n))
+}
+
+// IterationDirs returns the iterations with a directory in stateDir,
+// oldest first.
+func IterationDirs(stateDir string) ([]int, error) {
+	entries, err := os.ReadDir(stateDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var iterations []int
+	for _, e := range entries {
+		rest, ok := strings.CutPrefix(e.Name(), "iteration-")
+		if !ok || !e.IsDir() {
+			continue
+		}
+		if n, err := strconv.Atoi(rest); err == nil && n > 0 {
+			iterations = append(iterations, n)
+		}
+	}
+	sort.Ints(iterations)
+	return iterations, nil
+}
+
+// DirSize returns the total size of the files under path; what cannot be
+// read counts as nothing.
+func DirSize(path string) int64 {
+	var size int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// ExpiredIterations returns the iterations up to last whose directories
+// in stateDir r does not keep, oldest first, and their total size.
+// Iteration last and the directories after it are always kept.
+func ExpiredIterations(stateDir string, r Retention, last int) ([]int, int64, error) {
+	if r.Keep <= 0 && r.MaxBytes <= 0 {
+		return nil, 0, nil
+	}
+	iterations, err := IterationDirs(stateDir)
+	if err != nil {
+		return nil, 0, err
+	}
+	var expired []int
+	var kept, freed int64
+	count := 0
+	for i := len(iterations) - 1; i >= 0; i-- {
+		n := iterations[i]
+		if n > last {
+			continue
+		}
+		size := DirSize(IterationDir(stateDir, n))
+		count++
+		// Once one is dropped, every older one is too.
+		if len(expired) == 0 && (n == last || (r.Keep <= 0 || count <= r.Keep) && (r.MaxBytes <= 0 || kept+size <= r.MaxBytes)) {
+			kept += size
+			continue
+		}
+		expired = append([]int{n}, expired...)
+		freed += size
+	}
+	return expired, freed, nil
+}
+
+// PruneIterations removes the iteration directories in stateDir that r
+// does not keep, up to iteration last, and returns the iterations removed
+// and the bytes freed.
+func PruneIterations(stateDir string, r Retention, last int) ([]int, int64, error) {
+	expired, freed, err := ExpiredIterations(stateDir, r, last)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i, n := range expired {
+		if err := os.RemoveAll(IterationDir(stateDir, n)); err != nil {
+			return expired[:i], 0, fmt.Errorf("remove iteration %d: %w", n, err)
+		}
+	}
+	return expired, freed, nil
+}
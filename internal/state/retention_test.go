@@ -0,0 +1,80 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeIterations creates iteration directories 1 to n in stateDir, each
+// with an output of size bytes.
+func writeIterations(t *testing.T, stateDir string, n, size int) {
+	t.Helper()
+	for i := 1; i <= n; i++ {
+		dir := IterationDir(stateDir, i)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "implementation-output.txt"), []byte(strings.Repeat("x", size)), 0644))
+	}
+}
+
+func TestIterationDirs(t *testing.T) {
+	dir := t.TempDir()
+	writeIterations(t, dir, 3, 1)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "iteration-1000"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "iteration-abc"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "iteration-004"), nil, 0644))
+
+	iterations, err := IterationDirs(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 1000}, iterations)
+
+	iterations, err = IterationDirs(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, iterations)
+}
+
+func TestExpiredIterations(t *testing.T) {
+	dir := t.TempDir()
+	writeIterations(t, dir, 6, 100)
+
+	tests := []struct {
+		name    string
+		r       Retention
+		last    int
+		expired []int
+	}{
+		{"no policy", Retention{}, 6, nil},
+		{"keep last 2", Retention{Keep: 2}, 6, []int{1, 2, 3, 4}},
+		{"keep more than there are", Retention{Keep: 10}, 6, nil},
+		{"size", Retention{MaxBytes: 350}, 6, []int{1, 2, 3}},
+		{"both, the stricter wins", Retention{Keep: 4, MaxBytes: 250}, 6, []int{1, 2, 3, 4}},
+		{"the last is kept however large", Retention{MaxBytes: 50}, 6, []int{1, 2, 3, 4, 5}},
+		{"later directories are left alone", Retention{Keep: 1}, 4, []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expired, freed, err := ExpiredIterations(dir, tt.r, tt.last)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expired, expired)
+			assert.Equal(t, int64(100*len(tt.expired)), freed)
+		})
+	}
+}
+
+func TestPruneIterations(t *testing.T) {
+	dir := t.TempDir()
+	writeIterations(t, dir, 5, 10)
+
+	removed, freed, err := PruneIterations(dir, Retention{Keep: 2}, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, removed)
+	assert.Equal(t, int64(30), freed)
+
+	iterations, err := IterationDirs(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []int{4, 5}, iterations)
+}
@@ -1,33 +1,111 @@
 package state
 
+import "github.com/CodexForgeBR/cli-tools/internal/testresult"
+
 // SessionState represents the persisted state of a ralph-loop session.
 // Written to .ralph-loop/current-state.json.
 type SessionState struct {
-	SchemaVersion       int            `json:"schema_version"`
-	SessionID           string         `json:"session_id"`
-	StartedAt           string         `json:"started_at"`
-	LastUpdated         string         `json:"last_updated"`
-	Iteration           int            `json:"iteration"`
-	Status              string         `json:"status"`
-	Phase               string         `json:"phase"`
-	Verdict             string         `json:"verdict"`
-	TasksFile           string         `json:"tasks_file"`
-	TasksFileHash       string         `json:"tasks_file_hash"`
-	AICli               string         `json:"ai_cli"`
-	ImplModel           string         `json:"implementation_model"`
-	ValModel            string         `json:"validation_model"`
-	MaxIterations       int            `json:"max_iterations"`
-	MaxInadmissible     int            `json:"max_inadmissible"`
-	OriginalPlanFile    *string        `json:"original_plan_file"`
-	GithubIssue         *string        `json:"github_issue"`
-	Learnings           LearningsState `json:"learnings"`
-	CrossValidation     CrossValState  `json:"cross_validation"`
-	FinalPlanValidation PlanValState   `json:"final_plan_validation"`
-	TasksValidation     TasksValState  `json:"tasks_validation"`
-	Schedule            ScheduleState  `json:"schedule"`
-	RetryState          RetryState     `json:"retry_state"`
-	InadmissibleCount   int            `json:"inadmissible_count"`
-	LastFeedback        string         `json:"last_feedback"`
+	SchemaVersion       int             `json:"schema_version"`
+	SessionID           string          `json:"session_id"`
+	StartedAt           string          `json:"started_at"`
+	LastUpdated         string          `json:"last_updated"`
+	Iteration           int             `json:"iteration"`
+	Status              string          `json:"status"`
+	Phase               string          `json:"phase"`
+	Verdict             string          `json:"verdict"`
+	Confidence          *float64        `json:"confidence,omitempty"`
+	TasksFile           string          `json:"tasks_file"`
+	TasksFileHash       string          `json:"tasks_file_hash"`
+	AICli               string          `json:"ai_cli"`
+	ImplModel           string          `json:"implementation_model"`
+	ValModel            string          `json:"validation_model"`
+	MaxIterations       int             `json:"max_iterations"`
+	MaxInadmissible     int             `json:"max_inadmissible"`
+	OriginalPlanFile    *string         `json:"original_plan_file"`
+	GithubIssue         *string         `json:"github_issue"`
+	JiraIssue           *string         `json:"jira_issue,omitempty"`
+	Learnings           LearningsState  `json:"learnings"`
+	CrossValidation     CrossValState   `json:"cross_validation"`
+	FinalPlanValidation PlanValState    `json:"final_plan_validation"`
+	TasksValidation     TasksValState   `json:"tasks_validation"`
+	Schedule            ScheduleState   `json:"schedule"`
+	RetryState          RetryState      `json:"retry_state"`
+	InadmissibleCount   int             `json:"inadmissible_count"`
+	LastFeedback        string          `json:"last_feedback"`
+	Tasks               []TaskState     `json:"tasks,omitempty"`
+	ModelEscalation     EscalationState `json:"model_escalation"`
+	// Validators records, with --val-rotate, the validator that judged
+	// each iteration.
+	Validators []ValidatorRecord `json:"validators,omitempty"`
+	// AutoCrossValidations records the suspicious completions that were
+	// cross-validated although cross-validation was off.
+	AutoCrossValidations []AutoCrossValidation `json:"auto_cross_validations,omitempty"`
+	Checkpoint           *Checkpoint           `json:"checkpoint,omitempty"`
+	// Specs lists every tasks file of a multi-file session in run order;
+	// TasksFile is the one currently being worked on.
+	Specs []SpecProgress `json:"specs,omitempty"`
+	// Artifacts lists the evidence files kept from every iteration.
+	Artifacts []Artifact `json:"artifacts,omitempty"`
+	// HumanGuidance holds the decisions recorded with `ralph-loop respond`.
+	HumanGuidance []HumanGuidance `json:"human_guidance,omitempty"`
+	// PolicyViolations counts, per inadmissible practice policy, the
+	// iterations that violated it.
+	PolicyViolations map[string]int `json:"policy_violations,omitempty"`
+	// FeedbackIssues tracks the points of validator feedback that are not
+	// yet resolved, so they outlive the feedback that raised them.
+	FeedbackIssues []FeedbackIssue `json:"feedback_issues,omitempty"`
+	// Exit records how the last run of the session ended; nil while a run
+	// is in progress.
+	Exit *ExitState `json:"exit,omitempty"`
+	// ShutdownSignal is the signal, such as "SIGTERM", that stopped the
+	// last run of the session.
+	ShutdownSignal string `json:"shutdown_signal,omitempty"`
+	// Failover records the switch from the primary AI provider to the
+	// FALLBACK_AI after a failed health check; the rest of the session,
+	// resumes included, runs on the fallback.
+	Failover *FailoverState `json:"failover,omitempty"`
+	// History records the duration and task progress of every judged
+	// iteration, from which the ETA is estimated (see EstimateETA).
+	History []IterationRecord `json:"history,omitempty"`
+	// CoverageBaseline is the percentage COVERAGE_FILE reported when the
+	// session started, against which the PR summary shows the change.
+	CoverageBaseline *float64 `json:"coverage_baseline,omitempty"`
+	// TestTracks follow, across iterations, the outcome of every test the
+	// VERIFY_CMDS reported failing, to find the suspected flaky ones.
+	TestTracks []testresult.Track `json:"test_tracks,omitempty"`
+}
+
+// IterationRecord is how long a judged iteration and its phases took, in
+// seconds without pauses, its verdict, the tasks it completed and the files
+// it changed.
+type IterationRecord struct {
+	Iteration   int    `json:"iteration"`
+	Verdict     string `json:"verdict,omitempty"`
+	Seconds     int    `json:"seconds"`
+	ImplSeconds int    `json:"impl_seconds"`
+	ValSeconds  int    `json:"val_seconds"`
+	Completed   int    `json:"completed"` // tasks checked off during the iteration
+	Done        int    `json:"done"`      // tasks checked at the end of the iteration
+	Total       int    `json:"total"`     // tasks in the tasks file then
+	// At is when the iteration was judged.
+	At string `json:"at,omitempty"`
+	// Tasks lists the IDs of the tasks checked off during the iteration.
+	Tasks []string `json:"tasks,omitempty"`
+	// Files lists the paths, relative to the project root, the iteration
+	// changed; empty when the project is not a git repository.
+	Files []string `json:"files,omitempty"`
+	// Tests are the test results the VERIFY_CMDS reported; nil when none
+	// ran or none were recognized.
+	Tests *testresult.Summary `json:"tests,omitempty"`
+}
+
+// FailoverState records when and why a session failed over.
+type FailoverState struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Reason    string `json:"reason"`
+	Iteration int    `json:"iteration"`
+	At        string `json:"at"`
 }
 
 type LearningsState struct {
@@ -60,24 +138,170 @@ type ScheduleState struct {
 	TargetHuman string `json:"target_human"`
 }
 
+// RetryState is the retry telemetry of the session's AI invocations.
+// Attempt and Delay describe the retry in flight and reset to 0 once the
+// call succeeds; the other fields accumulate over the session.
 type RetryState struct {
 	Attempt int `json:"attempt"`
 	Delay   int `json:"delay"`
+
+	Total     int            `json:"total,omitempty"`   // failed attempts
+	Classes   map[string]int `json:"classes,omitempty"` // failed attempts by error class
+	GaveUp    int            `json:"gave_up,omitempty"` // calls that failed for good
+	LastClass string         `json:"last_class,omitempty"`
+	LastError string         `json:"last_error,omitempty"`
+	LastAt    string         `json:"last_at,omitempty"`
+}
+
+// Checkpoint records the progress of an in-flight iteration so --resume can
+// continue from the next phase instead of re-running implementation. Phase is
+// the phase to run next; the output paths point at the completed phases'
+// output files inside the iteration directory.
+type Checkpoint struct {
+	Iteration  int    `json:"iteration"`
+	Phase      string `json:"phase"`
+	ImplOutput string `json:"impl_output"`
+	ValOutput  string `json:"val_output,omitempty"`
+	// DiffBase is the snapshot of the working tree taken before the
+	// implementation phase (see gitdiff.Snapshot).
+	DiffBase string `json:"diff_base,omitempty"`
+}
+
+// EscalationState tracks the implementation model ladder: the current rung,
+// the streak of NEEDS_MORE_WORK verdicts on the same tasks, and every
+// escalation performed so far.
+type EscalationState struct {
+	Level       int               `json:"level"`
+	Streak      int               `json:"streak"`
+	StreakTasks []string          `json:"streak_tasks,omitempty"`
+	Events      []EscalationEvent `json:"events,omitempty"`
+}
+
+// HumanGuidance is a decision a person recorded for the implementer,
+// typically in answer to an escalation.
+type HumanGuidance struct {
+	Message    string `json:"message"`
+	RecordedAt string `json:"recorded_at"`
+	// Iteration is the iteration the session was at when it was recorded.
+	Iteration int `json:"iteration"`
+	// AppliedIteration is the iteration whose implementation prompt
+	// carried it; 0 while it is pending.
+	AppliedIteration int `json:"applied_iteration,omitempty"`
+}
+
+// ExitState is the exit code a run ended with, its name and why.
+type ExitState struct {
+	Code   int    `json:"code"`
+	Name   string `json:"name"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// EscalationEvent records a single switch to a stronger model.
+type EscalationEvent struct {
+	Iteration int    `json:"iteration"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Reason    string `json:"reason"`
+}
+
+// ValidatorRecord is the validator ("ai/model") that judged an iteration
+// and its verdict.
+type ValidatorRecord struct {
+	Iteration int    `json:"iteration"`
+	Validator string `json:"validator"`
+	Verdict   string `json:"verdict"`
+}
+
+// AutoCrossValidation is a suspicious completion that forced a
+// cross-validation pass, and why.
+type AutoCrossValidation struct {
+	Iteration int    `json:"iteration"`
+	Reason    string `json:"reason"`
+}
+
+// TaskState tracks a single task from the tasks file across iterations.
+type TaskState struct {
+	ID             string   `json:"id"`
+	Text           string   `json:"text"`
+	Status         string   `json:"status"`
+	FirstIteration int      `json:"first_iteration"`
+	LastIteration  int      `json:"last_iteration"`
+	Verdicts       []string `json:"verdicts"`
+	Attempts       int      `json:"attempts,omitempty"`       // consecutive iterations worked on without completion
+	BlockedReason  string   `json:"blocked_reason,omitempty"` // why ralph-loop marked the task blocked
+	// Meta is the task's {key: value} metadata from the tasks file.
+	Meta map[string]string `json:"meta,omitempty"`
+}
+
+// SpecProgress tracks one tasks file of a multi-file session.
+type SpecProgress struct {
+	TasksFile      string `json:"tasks_file"`
+	Status         string `json:"status"`
+	FirstIteration int    `json:"first_iteration,omitempty"`
+	LastIteration  int    `json:"last_iteration,omitempty"`
+	Done           int    `json:"done"`
+	Total          int    `json:"total"`
+}
+
+// Artifact is an evidence file declared in a RALPH_ARTIFACTS block and
+// copied into its iteration directory.
+type Artifact struct {
+	Iteration   int    `json:"iteration"`
+	Phase       string `json:"phase"`
+	Source      string `json:"source"` // path as declared by the AI
+	Path        string `json:"path"`   // the kept copy
+	Kind        string `json:"kind,omitempty"`
+	Description string `json:"description,omitempty"`
+	Task        string `json:"task,omitempty"`
+	Size        int64  `json:"size"`
+
+	// Screenshot is the comparison with the task's baseline screenshot.
+	Screenshot *ScreenshotDiff `json:"screenshot,omitempty"`
+}
+
+// ScreenshotDiff records how a screenshot artifact compared with its
+// baseline.
+type ScreenshotDiff struct {
+	Status   string  `json:"status"` // new, unchanged, changed or regression
+	Percent  float64 `json:"percent"`
+	Baseline string  `json:"baseline,omitempty"`
+	Diff     string  `json:"diff,omitempty"` // diff image
 }
 
 // Status constants
 const (
-	StatusInProgress  = "IN_PROGRESS"
-	StatusInterrupted = "INTERRUPTED"
-	StatusComplete    = "COMPLETE"
-	StatusCancelled   = "CANCELLED"
+	StatusInProgress     = "IN_PROGRESS"
+	StatusInterrupted    = "INTERRUPTED"
+	StatusComplete       = "COMPLETE"
+	StatusCancelled      = "CANCELLED"
+	StatusBudgetExceeded = "INTERRUPTED_BY_BUDGET" // --max-duration ran out; resumable
 )
 
+// VerdictTimeout is recorded for an iteration whose implementation or
+// validation phase was cut off by a time box.
+const VerdictTimeout = "TIMEOUT"
+
 // Phase constants
 const (
 	PhaseImplementation      = "implementation"
+	PhaseTriage              = "triage"
 	PhaseValidation          = "validation"
 	PhaseCrossValidation     = "cross_validation"
 	PhaseFinalPlanValidation = "final_plan_validation"
 	PhaseWaitingForSchedule  = "waiting_for_schedule"
+	PhasePaused              = "paused" // inside a PAUSE_BETWEEN window or after ralph-loop pause
+)
+
+// Task status constants
+const (
+	TaskPending = "pending"
+	TaskDone    = "done"
+	TaskBlocked = "blocked"
+)
+
+// Spec status constants
+const (
+	SpecPending = "pending"
+	SpecActive  = "active"
+	SpecDone    = "done"
 )
@@ -0,0 +1,153 @@
+package state
+
+import (
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// ChurnThreshold is the number of recorded verdicts after which a task that
+// is still not done is reported as churning.
+const ChurnThreshold = 3
+
+// TaskActivity describes which tasks an iteration touched, as reported by
+// the RALPH_STATUS and RALPH_VALIDATION outputs. Entries may be bare IDs
+// ("T001") or IDs followed by a description ("T001: description").
+type TaskActivity struct {
+	Touched []string
+	Blocked []string
+	Verdict string
+}
+
+// Churning reports whether the task has been worked on repeatedly without
+// being completed.
+func (t TaskState) Churning() bool {
+	return t.Status != TaskDone && len(t.Verdicts) >= ChurnThreshold
+}
+
+// FindTask returns a pointer to the tracked task with the given ID, or nil.
+func FindTask(s *SessionState, id string) *TaskState {
+	for i := range s.Tasks {
+		if s.Tasks[i].ID == id {
+			return &s.Tasks[i]
+		}
+	}
+	return nil
+}
+
+// SyncTasks reconciles the tracked tasks with the current contents of the
+// tasks file. New tasks are appended as pending, task text is refreshed, and
+// the checkbox state is authoritative for completion: checked tasks become
+// done, and unchecked tasks previously marked done revert to pending.
+// Blocked tasks stay blocked until they are checked, and unchecked tasks
+// carrying a ralph:blocked annotation are marked blocked.
+func SyncTasks(s *SessionState, list []tasks.Task) {
+	for _, t := range list {
+		ts := FindTask(s, t.ID)
+		if ts == nil {
+			s.Tasks = append(s.Tasks, TaskState{
+				ID:       t.ID,
+				Status:   TaskPending,
+				Verdicts: []string{},
+			})
+			ts = &s.Tasks[len(s.Tasks)-1]
+		}
+		ts.Text = t.Text
+		ts.Meta = t.Meta
+		switch {
+		case t.Checked:
+			ts.Status = TaskDone
+			ts.Attempts = 0
+		case t.BlockedReason != "":
+			ts.Status = TaskBlocked
+			ts.BlockedReason = t.BlockedReason
+		case ts.Status == TaskDone:
+			ts.Status = TaskPending
+		}
+	}
+}
+
+// RecordTaskActivity updates the per-task history for the given iteration.
+// Touched and blocked tasks get their first/last iteration updated and the
+// iteration verdict appended to their history. Blocked tasks that are not
+// already done are marked blocked. Unknown task IDs are ignored.
+//
+// Attempts counts the consecutive iterations a task was worked on without
+// being completed; it restarts at 1 when an iteration was skipped.
+func RecordTaskActivity(s *SessionState, iteration int, activity TaskActivity) {
+	seen := make(map[string]bool)
+	touch := func(ref string, blocked bool) {
+		id := tasks.ExtractTaskID(ref)
+		if id == "" {
+			return
+		}
+		ts := FindTask(s, id)
+		if ts == nil {
+			return
+		}
+		if blocked && ts.Status != TaskDone {
+			ts.Status = TaskBlocked
+		}
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		switch {
+		case ts.Status == TaskDone:
+			ts.Attempts = 0
+		case ts.Attempts > 0 && ts.LastIteration == iteration-1:
+			ts.Attempts++
+		default:
+			ts.Attempts = 1
+		}
+		if ts.FirstIteration == 0 {
+			ts.FirstIteration = iteration
+		}
+		ts.LastIteration = iteration
+		if activity.Verdict != "" {
+			ts.Verdicts = append(ts.Verdicts, activity.Verdict)
+		}
+	}
+
+	for _, ref := range activity.Blocked {
+		touch(ref, true)
+	}
+	for _, ref := range activity.Touched {
+		touch(ref, false)
+	}
+}
+
+// CountTasksByStatus returns the number of tracked tasks in each status.
+func CountTasksByStatus(s *SessionState) map[string]int {
+	counts := make(map[string]int)
+	for _, t := range s.Tasks {
+		counts[t.Status]++
+	}
+	return counts
+}
+
+// StuckTasks returns the IDs of pending tasks that have been worked on for
+// at least maxAttempts consecutive iterations without being completed.
+// A maxAttempts of zero or less disables detection.
+func StuckTasks(s *SessionState, maxAttempts int) []string {
+	if maxAttempts <= 0 {
+		return nil
+	}
+	var ids []string
+	for _, t := range s.Tasks {
+		if t.Status == TaskPending && t.Attempts >= maxAttempts {
+			ids = append(ids, t.ID)
+		}
+	}
+	return ids
+}
+
+// SkippedTasks returns the tasks that ralph-loop itself marked blocked and
+// that the implementer should therefore skip.
+func SkippedTasks(s *SessionState) []TaskState {
+	var skipped []TaskState
+	for _, t := range s.Tasks {
+		if t.Status == TaskBlocked && t.BlockedReason != "" {
+			skipped = append(skipped, t)
+		}
+	}
+	return skipped
+}
@@ -0,0 +1,163 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+func TestSyncTasks_AddsAndUpdates(t *testing.T) {
+	s := &SessionState{}
+
+	SyncTasks(s, []tasks.Task{
+		{ID: "T001", Text: "T001 first", Checked: true},
+		{ID: "T002", Text: "T002 second"},
+	})
+	require.Len(t, s.Tasks, 2)
+	assert.Equal(t, TaskDone, s.Tasks[0].Status)
+	assert.Equal(t, TaskPending, s.Tasks[1].Status)
+	assert.NotNil(t, s.Tasks[1].Verdicts)
+
+	// Unchecking a done task reverts it to pending; text is refreshed.
+	SyncTasks(s, []tasks.Task{
+		{ID: "T001", Text: "T001 first (edited)"},
+		{ID: "T002", Text: "T002 second", Checked: true},
+		{ID: "T003", Text: "T003 third"},
+	})
+	require.Len(t, s.Tasks, 3)
+	assert.Equal(t, TaskPending, s.Tasks[0].Status)
+	assert.Equal(t, "T001 first (edited)", s.Tasks[0].Text)
+	assert.Equal(t, TaskDone, s.Tasks[1].Status)
+	assert.Equal(t, TaskPending, s.Tasks[2].Status)
+}
+
+func TestSyncTasks_CopiesMetadata(t *testing.T) {
+	s := &SessionState{}
+	SyncTasks(s, []tasks.Task{{ID: "T001", Text: "T001 first", Meta: map[string]string{"area": "api"}}})
+	assert.Equal(t, map[string]string{"area": "api"}, s.Tasks[0].Meta)
+
+	SyncTasks(s, []tasks.Task{{ID: "T001", Text: "T001 first"}})
+	assert.Nil(t, s.Tasks[0].Meta, "metadata removed from the file is dropped")
+}
+
+func TestSyncTasks_BlockedStaysBlockedUntilChecked(t *testing.T) {
+	s := &SessionState{Tasks: []TaskState{{ID: "T001", Status: TaskBlocked}}}
+
+	SyncTasks(s, []tasks.Task{{ID: "T001", Text: "T001"}})
+	assert.Equal(t, TaskBlocked, s.Tasks[0].Status)
+
+	SyncTasks(s, []tasks.Task{{ID: "T001", Text: "T001", Checked: true}})
+	assert.Equal(t, TaskDone, s.Tasks[0].Status)
+}
+
+func TestRecordTaskActivity(t *testing.T) {
+	s := &SessionState{Tasks: []TaskState{
+		{ID: "T001", Status: TaskPending, Verdicts: []string{}},
+		{ID: "T002", Status: TaskPending, Verdicts: []string{}},
+		{ID: "T003", Status: TaskDone, Verdicts: []string{}},
+	}}
+
+	RecordTaskActivity(s, 2, TaskActivity{
+		Touched: []string{"T001", "T001: duplicate reference", "T999"},
+		Blocked: []string{"T002: waiting on credentials", "T003"},
+		Verdict: "NEEDS_MORE_WORK",
+	})
+	RecordTaskActivity(s, 3, TaskActivity{
+		Touched: []string{"T001"},
+		Verdict: "COMPLETE",
+	})
+
+	t1 := FindTask(s, "T001")
+	require.NotNil(t, t1)
+	assert.Equal(t, 2, t1.FirstIteration)
+	assert.Equal(t, 3, t1.LastIteration)
+	assert.Equal(t, []string{"NEEDS_MORE_WORK", "COMPLETE"}, t1.Verdicts)
+
+	t2 := FindTask(s, "T002")
+	assert.Equal(t, TaskBlocked, t2.Status)
+	assert.Equal(t, 2, t2.FirstIteration)
+
+	// Done tasks are never downgraded to blocked.
+	assert.Equal(t, TaskDone, FindTask(s, "T003").Status)
+	assert.Nil(t, FindTask(s, "T999"))
+}
+
+func TestTaskState_Churning(t *testing.T) {
+	churning := TaskState{Status: TaskPending, Verdicts: []string{"A", "B", "C"}}
+	assert.True(t, churning.Churning())
+
+	done := TaskState{Status: TaskDone, Verdicts: []string{"A", "B", "C"}}
+	assert.False(t, done.Churning())
+
+	fresh := TaskState{Status: TaskPending, Verdicts: []string{"A"}}
+	assert.False(t, fresh.Churning())
+}
+
+func TestCountTasksByStatus(t *testing.T) {
+	s := &SessionState{Tasks: []TaskState{
+		{ID: "T001", Status: TaskDone},
+		{ID: "T002", Status: TaskDone},
+		{ID: "T003", Status: TaskBlocked},
+	}}
+	counts := CountTasksByStatus(s)
+	assert.Equal(t, 2, counts[TaskDone])
+	assert.Equal(t, 1, counts[TaskBlocked])
+	assert.Equal(t, 0, counts[TaskPending])
+}
+
+func TestSyncTasks_BlockedAnnotation(t *testing.T) {
+	s := &SessionState{}
+
+	SyncTasks(s, []tasks.Task{{ID: "T001", Text: "T001", BlockedReason: "no progress"}})
+	require.Len(t, s.Tasks, 1)
+	assert.Equal(t, TaskBlocked, s.Tasks[0].Status)
+	assert.Equal(t, "no progress", s.Tasks[0].BlockedReason)
+
+	// Checking the task wins over the annotation.
+	SyncTasks(s, []tasks.Task{{ID: "T001", Text: "T001", Checked: true, BlockedReason: "no progress"}})
+	assert.Equal(t, TaskDone, s.Tasks[0].Status)
+}
+
+func TestRecordTaskActivity_CountsConsecutiveAttempts(t *testing.T) {
+	s := &SessionState{}
+	SyncTasks(s, []tasks.Task{{ID: "T001", Text: "T001"}, {ID: "T002", Text: "T002"}})
+
+	RecordTaskActivity(s, 1, TaskActivity{Touched: []string{"T001", "T002"}, Verdict: "NEEDS_MORE_WORK"})
+	RecordTaskActivity(s, 2, TaskActivity{Touched: []string{"T001"}, Verdict: "NEEDS_MORE_WORK"})
+	RecordTaskActivity(s, 3, TaskActivity{Touched: []string{"T001", "T002"}, Verdict: "NEEDS_MORE_WORK"})
+
+	assert.Equal(t, 3, FindTask(s, "T001").Attempts)
+	// T002 skipped iteration 2, so its streak restarted.
+	assert.Equal(t, 1, FindTask(s, "T002").Attempts)
+
+	// Completing a task clears its attempts.
+	SyncTasks(s, []tasks.Task{{ID: "T001", Text: "T001", Checked: true}, {ID: "T002", Text: "T002"}})
+	assert.Equal(t, 0, FindTask(s, "T001").Attempts)
+}
+
+func TestStuckTasks(t *testing.T) {
+	s := &SessionState{Tasks: []TaskState{
+		{ID: "T001", Status: TaskPending, Attempts: 3},
+		{ID: "T002", Status: TaskPending, Attempts: 2},
+		{ID: "T003", Status: TaskBlocked, Attempts: 5},
+		{ID: "T004", Status: TaskPending, Attempts: 4},
+	}}
+
+	assert.Equal(t, []string{"T001", "T004"}, StuckTasks(s, 3))
+	assert.Nil(t, StuckTasks(s, 0))
+}
+
+func TestSkippedTasks(t *testing.T) {
+	s := &SessionState{Tasks: []TaskState{
+		{ID: "T001", Status: TaskBlocked, BlockedReason: "no progress"},
+		{ID: "T002", Status: TaskBlocked},
+		{ID: "T003", Status: TaskPending},
+	}}
+
+	skipped := SkippedTasks(s)
+	require.Len(t, skipped, 1)
+	assert.Equal(t, "T001", skipped[0].ID)
+}
@@ -0,0 +1,220 @@
+// Package statecrypt encrypts the files ralph-loop keeps about a session,
+// which quote the code and feedback of the project, when a state key is
+// set (STATE_KEY). Files are sealed with AES-256-GCM behind a header that
+// marks them, so reading is transparent and plain files written before
+// the key was set are still read as they are.
+package statecrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// KeySize is the size, in bytes, of a state key.
+const KeySize = 32
+
+// header starts every encrypted file.
+var header = []byte("RALPH-ENCRYPTED-1\n")
+
+// ErrNoKey is returned when reading an encrypted file without a key.
+var ErrNoKey = errors.New("encrypted, and no STATE_KEY is set")
+
+var (
+	mu     sync.Mutex
+	key    []byte
+	source func() ([]byte, error)
+	loaded bool
+	// loadErr is why source failed to give the key.
+	loadErr error
+)
+
+// ParseKey decodes a state key: KeySize bytes in base64, as printed by
+// `openssl rand -base64 32`.
+func ParseKey(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	k, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		k, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	}
+	if err != nil || len(k) != KeySize {
+		return nil, fmt.Errorf("a state key is %d bytes in base64, e.g. from openssl rand -base64 32", KeySize)
+	}
+	return k, nil
+}
+
+// SetKey sets the key files are encrypted with; nil leaves them plain.
+func SetKey(k []byte) {
+	mu.Lock()
+	defer mu.Unlock()
+	key, loaded, loadErr = k, true, nil
+}
+
+// SetKeySource makes the first read or write that needs the key ask f
+// for it, so commands that touch no state file never look it up. SetKey
+// takes precedence.
+func SetKeySource(f func() ([]byte, error)) {
+	mu.Lock()
+	defer mu.Unlock()
+	source, key, loaded, loadErr = f, nil, false, nil
+}
+
+// currentKey returns the key, nil when there is none.
+func currentKey() ([]byte, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if !loaded && source != nil {
+		key, loadErr = source()
+		loaded = true
+	}
+	return key, loadErr
+}
+
+// Enabled reports whether files are encrypted.
+func Enabled() bool {
+	k, _ := currentKey()
+	return k != nil
+}
+
+// IsEncrypted reports whether data is an encrypted file.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, header)
+}
+
+// Seal encrypts data with the key; without one it returns data as is.
+func Seal(data []byte) ([]byte, error) {
+	k, err := currentKey()
+	if err != nil {
+		return nil, fmt.Errorf("state key: %w", err)
+	}
+	if k == nil || IsEncrypted(data) {
+		return data, nil
+	}
+	aead, err := newAEAD(k)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append(append([]byte{}, header...), nonce...)
+	return aead.Seal(out, nonce, data, header), nil
+}
+
+// Open decrypts data sealed by Seal; other data is returned as is.
+func Open(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	k, err := currentKey()
+	if err != nil {
+		return nil, fmt.Errorf("state key: %w", err)
+	}
+	if k == nil {
+		return nil, ErrNoKey
+	}
+	aead, err := newAEAD(k)
+	if err != nil {
+		return nil, err
+	}
+	body := data[len(header):]
+	if len(body) < aead.NonceSize() {
+		return nil, errors.New("truncated encrypted file")
+	}
+	plain, err := aead.Open(nil, body[:aead.NonceSize()], body[aead.NonceSize():], header)
+	if err != nil {
+		return nil, errors.New("cannot decrypt: wrong STATE_KEY or damaged file")
+	}
+	return plain, nil
+}
+
+func newAEAD(k []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(k)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// ReadFile reads path, decrypting it when it is encrypted.
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := Open(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return plain, nil
+}
+
+// WriteFile writes data to path, encrypted when a key is set.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	sealed, err := Seal(data)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, sealed, perm)
+}
+
+// SealDir encrypts in place the files under dir that are not yet; it does
+// nothing without a key.
+func SealDir(dir string) error {
+	if !Enabled() {
+		return nil
+	}
+	return rewriteDir(dir, Seal)
+}
+
+// OpenDir decrypts in place the encrypted files under dir, so the
+// iteration they belong to can go on writing and reading them.
+func OpenDir(dir string) error {
+	return rewriteDir(dir, Open)
+}
+
+// rewriteDir replaces every regular file under dir by what convert makes
+// of it, when that differs.
+func rewriteDir(dir string, convert func([]byte) ([]byte, error)) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		out, err := convert(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if bytes.Equal(out, data) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, out, info.Mode().Perm()); err != nil {
+			return err
+		}
+		return os.Rename(tmp, path)
+	})
+}
@@ -0,0 +1,155 @@
+package statecrypt
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKey = "c3RhdGUta2V5LXN0YXRlLWtleS1zdGF0ZS1rZXktMzI="
+
+func useKey(t *testing.T, value string) {
+	t.Helper()
+	var k []byte
+	if value != "" {
+		var err error
+		k, err = ParseKey(value)
+		require.NoError(t, err)
+	}
+	SetKey(k)
+	t.Cleanup(func() { SetKey(nil) })
+}
+
+func TestParseKey(t *testing.T) {
+	k, err := ParseKey(" " + testKey + "\n")
+	require.NoError(t, err)
+	assert.Len(t, k, KeySize)
+
+	_, err = ParseKey("c3RhdGUta2V5LXN0YXRlLWtleS1zdGF0ZS1rZXktMzI")
+	assert.NoError(t, err, "unpadded URL-safe base64 is accepted")
+
+	for _, bad := range []string{"hunter2", "c2hvcnQ=", "not base64!"} {
+		_, err := ParseKey(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	useKey(t, testKey)
+	plain := []byte(`{"last_feedback": "func secret() {}"}`)
+
+	sealed, err := Seal(plain)
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(sealed))
+	assert.False(t, bytes.Contains(sealed, []byte("secret")))
+
+	again, err := Seal(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, sealed, again, "sealed data is not sealed twice")
+
+	opened, err := Open(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, plain, opened)
+
+	opened, err = Open(plain)
+	require.NoError(t, err)
+	assert.Equal(t, plain, opened, "plain data is read as it is")
+
+	sealed[len(sealed)-1] ^= 1
+	_, err = Open(sealed)
+	assert.EqualError(t, err, "cannot decrypt: wrong STATE_KEY or damaged file")
+}
+
+func TestWithoutKey(t *testing.T) {
+	useKey(t, testKey)
+	sealed, err := Seal([]byte("feedback"))
+	require.NoError(t, err)
+
+	SetKey(nil)
+	assert.False(t, Enabled())
+	plain, err := Seal([]byte("feedback"))
+	require.NoError(t, err)
+	assert.Equal(t, "feedback", string(plain))
+	_, err = Open(sealed)
+	assert.ErrorIs(t, err, ErrNoKey)
+}
+
+func TestKeySource(t *testing.T) {
+	t.Cleanup(func() { SetKey(nil) })
+	calls := 0
+	SetKeySource(func() ([]byte, error) {
+		calls++
+		return ParseKey(testKey)
+	})
+	assert.Equal(t, 0, calls, "the key is looked up when first needed")
+	assert.True(t, Enabled())
+	_, err := Seal([]byte("feedback"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	SetKeySource(func() ([]byte, error) { return nil, errors.New("invalid configuration") })
+	_, err = Seal([]byte("feedback"))
+	assert.EqualError(t, err, "state key: invalid configuration", "a key that cannot be read does not leave files plain")
+}
+
+func TestFiles(t *testing.T) {
+	useKey(t, testKey)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "current-state.json")
+	require.NoError(t, WriteFile(path, []byte(`{"iteration": 3}`), 0644))
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(raw))
+	data, err := ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{"iteration": 3}`, string(data))
+
+	SetKey(nil)
+	_, err = ReadFile(path)
+	assert.ErrorIs(t, err, ErrNoKey)
+	assert.Contains(t, err.Error(), path)
+}
+
+func TestSealDirAndOpenDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "iteration-002")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "evidence"), 0755))
+	files := map[string]string{
+		"implementation-output.txt": "implemented T001",
+		"impl.log.gz":               "\x1f\x8b compressed",
+		"evidence/shot.png":         "\x89PNG",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	require.NoError(t, SealDir(dir), "without a key")
+	raw, err := os.ReadFile(filepath.Join(dir, "implementation-output.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "implemented T001", string(raw))
+
+	useKey(t, testKey)
+	require.NoError(t, SealDir(dir))
+	require.NoError(t, SealDir(dir), "sealing twice is harmless")
+	for name, content := range files {
+		raw, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.True(t, IsEncrypted(raw), name)
+		data, err := ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data), name)
+	}
+
+	require.NoError(t, OpenDir(dir))
+	for name, content := range files {
+		raw, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, content, string(raw), name)
+	}
+	assert.NoError(t, SealDir(filepath.Join(dir, "missing")), "a missing dir is nothing to seal")
+}
@@ -0,0 +1,143 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// blockedAnnotationRE matches the HTML comment ralph-loop appends to tasks
+// it has given up on: "<!-- ralph:blocked reason -->".
+var blockedAnnotationRE = regexp.MustCompile(`\s*<!--\s*ralph:blocked\b\s*(.*?)\s*-->`)
+
+// noteAnnotationRE matches the note ralph-loop writes on tasks with
+// --annotate-tasks: "<!-- ralph: completed in iter 4 -->". The space after
+// the colon tells it apart from a ralph:blocked annotation.
+var noteAnnotationRE = regexp.MustCompile(`\s*<!--\s*ralph:\s(.*?)\s*-->`)
+
+// checkedBoxRE matches the start of a checked task line up to its "]".
+var checkedBoxRE = regexp.MustCompile(`^\s*- \[[xX]\]`)
+
+// defaultBlockedReason is reported for annotations that carry no reason.
+const defaultBlockedReason = "blocked"
+
+// splitBlockedAnnotation removes a ralph:blocked annotation from task text
+// and returns the cleaned text and the annotation reason ("" if none).
+func splitBlockedAnnotation(text string) (string, string) {
+	m := blockedAnnotationRE.FindStringSubmatch(text)
+	if m == nil {
+		return text, ""
+	}
+	reason := m[1]
+	if reason == "" {
+		reason = defaultBlockedReason
+	}
+	return strings.TrimSpace(blockedAnnotationRE.ReplaceAllString(text, "")), reason
+}
+
+// splitNote removes a ralph: note from task text and returns the cleaned
+// text and the note ("" if none).
+func splitNote(text string) (string, string) {
+	m := noteAnnotationRE.FindStringSubmatch(text)
+	if m == nil {
+		return text, ""
+	}
+	return strings.TrimSpace(noteAnnotationRE.ReplaceAllString(text, "")), m[1]
+}
+
+// SetNote writes a `<!-- ralph: note -->` annotation at the end of the
+// checkbox task on the given 1-based line of filePath, replacing the note
+// it already carries. An empty note removes it. The note is flattened to a
+// single line and must not terminate the HTML comment early.
+func SetNote(filePath string, line int, note string) error {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(string(data), "\n")
+	if line < 1 || line > len(lines) {
+		return fmt.Errorf("line %d out of range in %s", line, filePath)
+	}
+	target := strings.TrimRight(lines[line-1], "\r")
+	if !taskLineRE.MatchString(target) {
+		return fmt.Errorf("line %d in %s is not a task", line, filePath)
+	}
+	suffix := lines[line-1][len(target):]
+	target = noteAnnotationRE.ReplaceAllString(target, "")
+	if note = strings.Join(strings.Fields(strings.ReplaceAll(note, "--", "-")), " "); note != "" {
+		target = fmt.Sprintf("%s <!-- ralph: %s -->", target, note)
+	}
+	lines[line-1] = target + suffix
+
+	return os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), info.Mode().Perm())
+}
+
+// MarkBlocked appends a `<!-- ralph:blocked reason -->` annotation to the
+// checkbox task on the given 1-based line of filePath. Lines that already
+// carry an annotation are left unchanged. The reason is flattened to a
+// single line and must not terminate the HTML comment early.
+func MarkBlocked(filePath string, line int, reason string) error {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(string(data), "\n")
+	if line < 1 || line > len(lines) {
+		return fmt.Errorf("line %d out of range in %s", line, filePath)
+	}
+	target := strings.TrimRight(lines[line-1], "\r")
+	if !taskLineRE.MatchString(target) {
+		return fmt.Errorf("line %d in %s is not a task", line, filePath)
+	}
+	if blockedAnnotationRE.MatchString(target) {
+		return nil
+	}
+
+	reason = strings.Join(strings.Fields(strings.ReplaceAll(reason, "--", "-")), " ")
+	if reason == "" {
+		reason = defaultBlockedReason
+	}
+	suffix := lines[line-1][len(target):]
+	lines[line-1] = fmt.Sprintf("%s <!-- ralph:blocked %s -->%s", target, reason, suffix)
+
+	return os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), info.Mode().Perm())
+}
+
+// Uncheck clears the checkbox of the task on the given 1-based line of
+// filePath ("- [x]" becomes "- [ ]"). Unchecked tasks are left unchanged.
+func Uncheck(filePath string, line int) error {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(string(data), "\n")
+	if line < 1 || line > len(lines) {
+		return fmt.Errorf("line %d out of range in %s", line, filePath)
+	}
+	loc := checkedBoxRE.FindStringIndex(lines[line-1])
+	if loc == nil {
+		if taskLineRE.MatchString(strings.TrimRight(lines[line-1], "\r")) {
+			return nil
+		}
+		return fmt.Errorf("line %d in %s is not a task", line, filePath)
+	}
+	lines[line-1] = lines[line-1][:loc[1]-2] + " " + lines[line-1][loc[1]-1:]
+
+	return os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), info.Mode().Perm())
+}
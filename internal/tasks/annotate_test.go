@@ -0,0 +1,103 @@
+package tasks
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkBlocked_AppendsAnnotation(t *testing.T) {
+	path := writeTempFile(t, "# Tasks\n\n- [ ] T001 First\n- [ ] T002 Second\n")
+
+	require.NoError(t, MarkBlocked(path, 3, "no progress after 3 iterations"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# Tasks\n\n- [ ] T001 First <!-- ralph:blocked no progress after 3 iterations -->\n- [ ] T002 Second\n", string(data))
+}
+
+func TestMarkBlocked_IsIdempotent(t *testing.T) {
+	content := "- [ ] T001 First <!-- ralph:blocked earlier reason -->\n"
+	path := writeTempFile(t, content)
+
+	require.NoError(t, MarkBlocked(path, 1, "new reason"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+}
+
+func TestMarkBlocked_SanitizesReason(t *testing.T) {
+	path := writeTempFile(t, "- [ ] T001 First\r\n")
+
+	require.NoError(t, MarkBlocked(path, 1, "fails --> always\nsee logs"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "- [ ] T001 First <!-- ralph:blocked fails -> always see logs -->\r\n", string(data))
+}
+
+func TestMarkBlocked_RejectsNonTaskLine(t *testing.T) {
+	path := writeTempFile(t, "# Tasks\n- [ ] T001 First\n")
+
+	err := MarkBlocked(path, 1, "reason")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not a task")
+
+	err = MarkBlocked(path, 10, "reason")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "out of range")
+}
+
+func TestMarkBlocked_MissingFile(t *testing.T) {
+	err := MarkBlocked("/nonexistent/tasks.md", 1, "reason")
+	assert.Error(t, err)
+}
+
+func TestListTasks_ReadsBlockedAnnotation(t *testing.T) {
+	path := writeTempFile(t, "- [ ] T001 First <!-- ralph:blocked stuck on flaky test -->\n- [ ] T002 Second <!-- ralph:blocked -->\n- [ ] T003 Third\n")
+
+	list, err := ListTasks(path)
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+
+	assert.Equal(t, "T001 First", list[0].Text)
+	assert.Equal(t, "stuck on flaky test", list[0].BlockedReason)
+	assert.Equal(t, "blocked", list[1].BlockedReason)
+	assert.Empty(t, list[2].BlockedReason)
+}
+
+func TestSetNote_WritesAndReplacesNote(t *testing.T) {
+	path := writeTempFile(t, "- [x] T001 First {est: 2h}\r\n- [ ] T002 Second\r\n")
+
+	require.NoError(t, SetNote(path, 1, "completed in iter 3"))
+	require.NoError(t, SetNote(path, 1, "completed in iter 4, evidence: a.log --> b.log"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "- [x] T001 First {est: 2h} <!-- ralph: completed in iter 4, evidence: a.log -> b.log -->\r\n- [ ] T002 Second\r\n", string(data))
+
+	list, err := ListTasks(path)
+	require.NoError(t, err)
+	assert.Equal(t, "T001 First", list[0].Text)
+	assert.Equal(t, "2h", list[0].Meta["est"])
+	assert.Equal(t, "completed in iter 4, evidence: a.log -> b.log", list[0].Note)
+
+	require.NoError(t, SetNote(path, 1, ""))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "- [x] T001 First {est: 2h}\r\n- [ ] T002 Second\r\n", string(data))
+	assert.Error(t, SetNote(path, 3, "x"))
+}
+
+func TestListTasks_NoteAndBlockedAnnotation(t *testing.T) {
+	path := writeTempFile(t, "- [ ] T001 First <!-- ralph:blocked stuck --> <!-- ralph: completed in iter 2 -->\n")
+
+	list, err := ListTasks(path)
+	require.NoError(t, err)
+	assert.Equal(t, "T001 First", list[0].Text)
+	assert.Equal(t, "stuck", list[0].BlockedReason)
+	assert.Equal(t, "completed in iter 2", list[0].Note)
+}
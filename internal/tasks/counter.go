@@ -1,48 +1,23 @@
 package tasks
 
-import (
-	"bufio"
-	"os"
-	"regexp"
-)
-
-// uncheckedRE matches a Markdown checkbox that has NOT been ticked.
-// Allows leading whitespace: "  - [ ] some task"
-var uncheckedRE = regexp.MustCompile(`^\s*- \[ \]`)
-
-// checkedRE matches a Markdown checkbox that HAS been ticked (x or X).
-// Allows leading whitespace: "  - [x] some task" or "  - [X] some task"
-var checkedRE = regexp.MustCompile(`^\s*- \[[xX]\]`)
-
-// CountUnchecked returns the number of unchecked task lines in filePath.
-// A line is considered an unchecked task if it matches the pattern: ^\s*- \[ \]
+// CountUnchecked returns the number of unchecked tasks ("- [ ] ...") in
+// filePath, ignoring any YAML frontmatter.
 func CountUnchecked(filePath string) (int, error) {
-	return countMatches(filePath, uncheckedRE)
-}
-
-// CountChecked returns the number of checked task lines in filePath.
-// A line is considered a checked task if it matches: ^\s*- \[[xX]\]
-func CountChecked(filePath string) (int, error) {
-	return countMatches(filePath, checkedRE)
-}
-
-// countMatches counts lines in filePath that match the given regexp.
-func countMatches(filePath string, re *regexp.Regexp) (int, error) {
-	f, err := os.Open(filePath)
+	f, err := Parse(filePath)
 	if err != nil {
 		return 0, err
 	}
-	defer f.Close()
+	_, unchecked := f.Counts()
+	return unchecked, nil
+}
 
-	count := 0
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		if re.MatchString(scanner.Text()) {
-			count++
-		}
-	}
-	if err := scanner.Err(); err != nil {
+// CountChecked returns the number of checked tasks ("- [x] ...") in
+// filePath, ignoring any YAML frontmatter.
+func CountChecked(filePath string) (int, error) {
+	f, err := Parse(filePath)
+	if err != nil {
 		return 0, err
 	}
-	return count, nil
+	checked, _ := f.Counts()
+	return checked, nil
 }
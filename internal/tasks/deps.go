@@ -0,0 +1,127 @@
+package tasks
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CheckDependencies reports a dependency cycle among list, e.g.
+// "dependency cycle: T001 -> T003 -> T001". Dependencies on unknown task IDs
+// are ignored here; see UnknownDependencies.
+func CheckDependencies(list []Task) error {
+	byID := IndexTasks(list)
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	mark := make(map[string]int, len(list))
+	var path []string
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch mark[id] {
+		case done:
+			return nil
+		case visiting:
+			start := 0
+			for i, p := range path {
+				if p == id {
+					start = i
+				}
+			}
+			return fmt.Errorf("dependency cycle: %s -> %s", strings.Join(path[start:], " -> "), id)
+		}
+		mark[id] = visiting
+		path = append(path, id)
+		for _, dep := range byID[id].Depends {
+			if _, ok := byID[dep]; ok {
+				if err := visit(dep); err != nil {
+					return err
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		mark[id] = done
+		return nil
+	}
+	for _, t := range list {
+		if err := visit(t.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnknownDependencies describes dependencies naming tasks that are not in
+// list, e.g. "T012 depends on T099".
+func UnknownDependencies(list []Task) []string {
+	byID := IndexTasks(list)
+	var unknown []string
+	for _, t := range list {
+		for _, dep := range t.Depends {
+			if _, ok := byID[dep]; !ok {
+				unknown = append(unknown, fmt.Sprintf("%s depends on %s", t.ID, dep))
+			}
+		}
+	}
+	return unknown
+}
+
+// HasDependencies reports whether any task in list declares dependencies.
+func HasDependencies(list []Task) bool {
+	for _, t := range list {
+		if len(t.Depends) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Unblocked returns the unchecked, unannotated tasks whose dependencies are
+// all checked, in file order. Unknown dependencies count as met.
+func Unblocked(list []Task) []Task {
+	byID := IndexTasks(list)
+	var ready []Task
+	for _, t := range list {
+		if !t.Checked && t.BlockedReason == "" && len(PendingDependencies(t, byID)) == 0 {
+			ready = append(ready, t)
+		}
+	}
+	return ready
+}
+
+// PrematurelyChecked returns the checked tasks that still have unchecked
+// dependencies.
+func PrematurelyChecked(list []Task) []Task {
+	byID := IndexTasks(list)
+	var premature []Task
+	for _, t := range list {
+		if t.Checked && len(PendingDependencies(t, byID)) > 0 {
+			premature = append(premature, t)
+		}
+	}
+	return premature
+}
+
+// PendingDependencies returns t's dependencies that are known and not yet
+// checked. byID indexes the tasks of t's file.
+func PendingDependencies(t Task, byID map[string]Task) []string {
+	var pending []string
+	for _, dep := range t.Depends {
+		if d, ok := byID[dep]; ok && !d.Checked {
+			pending = append(pending, dep)
+		}
+	}
+	return pending
+}
+
+// IndexTasks maps task IDs to tasks. The first task wins when IDs repeat.
+func IndexTasks(list []Task) map[string]Task {
+	byID := make(map[string]Task, len(list))
+	for _, t := range list {
+		if _, ok := byID[t.ID]; !ok {
+			byID[t.ID] = t
+		}
+	}
+	return byID
+}
@@ -0,0 +1,77 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func depsTasks() []Task {
+	return []Task{
+		{ID: "T001", Checked: true},
+		{ID: "T002", Depends: []string{"T001"}},
+		{ID: "T003", Depends: []string{"T001", "T002"}},
+		{ID: "T004", Depends: []string{"T099"}},
+		{ID: "T005", Checked: true, Depends: []string{"T003"}},
+		{ID: "T006", BlockedReason: "stuck"},
+	}
+}
+
+func TestCheckDependencies(t *testing.T) {
+	assert.NoError(t, CheckDependencies(depsTasks()))
+
+	cyclic := []Task{
+		{ID: "T001"},
+		{ID: "T002", Depends: []string{"T004"}},
+		{ID: "T003", Depends: []string{"T002"}},
+		{ID: "T004", Depends: []string{"T003"}},
+	}
+	err := CheckDependencies(cyclic)
+	require.Error(t, err)
+	assert.Equal(t, "dependency cycle: T002 -> T004 -> T003 -> T002", err.Error())
+
+	err = CheckDependencies([]Task{{ID: "T001", Depends: []string{"T001"}}})
+	require.Error(t, err)
+	assert.Equal(t, "dependency cycle: T001 -> T001", err.Error())
+}
+
+func TestUnknownDependencies(t *testing.T) {
+	assert.Equal(t, []string{"T004 depends on T099"}, UnknownDependencies(depsTasks()))
+}
+
+func TestUnblocked(t *testing.T) {
+	var ids []string
+	for _, t := range Unblocked(depsTasks()) {
+		ids = append(ids, t.ID)
+	}
+	assert.Equal(t, []string{"T002", "T004"}, ids)
+}
+
+func TestPrematurelyChecked(t *testing.T) {
+	premature := PrematurelyChecked(depsTasks())
+	require.Len(t, premature, 1)
+	assert.Equal(t, "T005", premature[0].ID)
+	assert.Equal(t, []string{"T003"}, PendingDependencies(premature[0], IndexTasks(depsTasks())))
+}
+
+func TestHasDependencies(t *testing.T) {
+	assert.True(t, HasDependencies(depsTasks()))
+	assert.False(t, HasDependencies([]Task{{ID: "T001"}}))
+}
+
+func TestUncheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.md")
+	require.NoError(t, os.WriteFile(path, []byte("# Tasks\n  - [X] T001 Done {area: api}\n- [ ] T002 Open\n"), 0644))
+
+	require.NoError(t, Uncheck(path, 2))
+	require.NoError(t, Uncheck(path, 3), "already unchecked")
+	assert.Error(t, Uncheck(path, 1))
+	assert.Error(t, Uncheck(path, 10))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# Tasks\n  - [ ] T001 Done {area: api}\n- [ ] T002 Open\n", string(data))
+}
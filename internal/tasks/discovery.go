@@ -28,8 +28,11 @@ var subdirRoots = []string{
 // Search order:
 //  1. Explicit flag value (must exist)
 //  2. ./tasks.md, ./TASKS.md, ./specs/tasks.md, ./spec/tasks.md
-//  3. ./specs/*/tasks.md (first match, alphabetical)
-//  4. ./spec/*/tasks.md  (first match, alphabetical)
+//  3. ./specs/*/tasks.md
+//  4. ./spec/*/tasks.md
+//
+// Among several spec folders, the one named after the current feature
+// branch wins, then the first (alphabetical) one with unchecked tasks.
 func DiscoverTasksFile(tasksFileFlag string) (string, error) {
 	// ---------------------------------------------------------------
 	// 1. Explicit flag
@@ -72,7 +75,7 @@ func DiscoverTasksFile(tasksFileFlag string) (string, error) {
 			continue
 		}
 		if len(matches) > 0 {
-			return matches[0], nil
+			return pickSpecFolder(matches), nil
 		}
 	}
 
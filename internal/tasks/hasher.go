@@ -13,6 +13,12 @@ func HashFile(filePath string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	return HashBytes(data), nil
+}
+
+// HashBytes returns the lowercase hexadecimal SHA-256 digest of data, as
+// HashFile does for a file's contents.
+func HashBytes(data []byte) string {
 	sum := sha256.Sum256(data)
-	return hex.EncodeToString(sum[:]), nil
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,42 @@
+package tasks
+
+import "regexp"
+
+// taskLineRE matches a Markdown checkbox line and captures the checkbox
+// mark and the task text: "  - [x] T001 Do something".
+var taskLineRE = regexp.MustCompile(`^\s*- \[([ xX])\]\s*(.*)$`)
+
+// taskIDRE matches a spec-kit style task identifier such as "T001".
+var taskIDRE = regexp.MustCompile(`\bT\d+\b`)
+
+// Task is a single checkbox entry parsed from a tasks file.
+type Task struct {
+	ID            string // "T001", or "L<line>" when the task has no identifier
+	Text          string // task text without the checkbox prefix, metadata or blocked annotation
+	Checked       bool
+	Line          int    // 1-based line number in the tasks file
+	BlockedReason string // reason from a <!-- ralph:blocked ... --> annotation, if any
+	Note          string // note from a <!-- ralph: ... --> annotation, if any
+	// Meta holds the trailing {key: value, ...} block, e.g. est and area;
+	// nil when the task has none.
+	Meta map[string]string
+	// Depends lists the task IDs named by the depends metadata key.
+	Depends []string
+}
+
+// ListTasks parses every checkbox line in filePath into a Task.
+// Tasks without a T### identifier are assigned a line-based ID ("L12") so
+// they can still be tracked individually.
+func ListTasks(filePath string) ([]Task, error) {
+	f, err := Parse(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return f.Tasks, nil
+}
+
+// ExtractTaskID returns the first T### identifier found in s, or "" if none.
+// AI outputs usually reference tasks as "T001" or "T001: description".
+func ExtractTaskID(s string) string {
+	return taskIDRE.FindString(s)
+}
@@ -0,0 +1,61 @@
+package tasks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTasks_ParsesIDsAndState(t *testing.T) {
+	content := `# Tasks
+
+- [ ] T001 Create initial setup
+- [x] T002 Add configuration file
+  - [X] T003: Nested task
+- [ ] Task without identifier
+Not a task line
+`
+	path := writeTempFile(t, content)
+
+	list, err := ListTasks(path)
+	require.NoError(t, err)
+	require.Len(t, list, 4)
+
+	assert.Equal(t, Task{ID: "T001", Text: "T001 Create initial setup", Checked: false, Line: 3}, list[0])
+	assert.Equal(t, "T002", list[1].ID)
+	assert.True(t, list[1].Checked)
+	assert.Equal(t, "T003", list[2].ID)
+	assert.True(t, list[2].Checked)
+	assert.Equal(t, "L6", list[3].ID)
+	assert.Equal(t, "Task without identifier", list[3].Text)
+}
+
+func TestListTasks_FileNotFound(t *testing.T) {
+	_, err := ListTasks("/nonexistent/tasks.md")
+	assert.Error(t, err)
+}
+
+func TestListTasks_NoTasks(t *testing.T) {
+	path := writeTempFile(t, "# Just a heading\n")
+	list, err := ListTasks(path)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestExtractTaskID(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"T001", "T001"},
+		{"T042: Add validation logic", "T042"},
+		{"Blocked: T7 waiting on API", "T7"},
+		{"no identifier", ""},
+		{"ST001 is not a task id", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ExtractTaskID(tt.input), "ExtractTaskID(%q)", tt.input)
+	}
+}
@@ -0,0 +1,221 @@
+package tasks
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Conflict is a task a three-way Merge could not reconcile. The merge keeps
+// the user's version of the task; Agent holds the agent's line it set aside.
+type Conflict struct {
+	Task   string // task ID, or its text when it has none
+	Reason string
+	User   string // the user's line, empty when the user removed the task
+	Agent  string // the agent's line, empty when the agent removed the task
+}
+
+// String formats c for logs and the conflicts report.
+func (c Conflict) String() string {
+	s := fmt.Sprintf("%s: %s", c.Task, c.Reason)
+	if c.User != "" {
+		s += "\n  yours: " + strings.TrimSpace(c.User)
+	}
+	if c.Agent != "" {
+		s += "\n  agent: " + strings.TrimSpace(c.Agent)
+	}
+	return s
+}
+
+// mergeTask is a checkbox line of one side of a merge.
+type mergeTask struct {
+	index   int    // 0-based line index
+	line    string // the line as written
+	body    string // the line with its checkbox cleared
+	checked bool
+}
+
+// Merge combines two versions of a tasks file edited from a common base:
+// the agent's, as the loop last recorded it, and the user's. Tasks are
+// matched by ID, or by text when they have none. The user's version gives
+// the layout, so prose and the tasks the user added or removed come from
+// it; a task line or checkbox the agent changed and the user did not takes
+// the agent's version, and tasks the agent added are inserted after the
+// task preceding them in the agent's version. A task both sides changed
+// differently, or one side removed after the other changed it, is a
+// conflict: the user's version wins and the conflict is returned.
+func Merge(base, agent, user []byte) ([]byte, []Conflict) {
+	switch {
+	case bytes.Equal(user, base):
+		return agent, nil
+	case bytes.Equal(agent, base), bytes.Equal(agent, user):
+		return user, nil
+	}
+
+	baseTasks, _ := indexTasks(splitMergeLines(base))
+	agentTasks, agentKeys := indexTasks(splitMergeLines(agent))
+	userLines := splitMergeLines(user)
+	userTasks, userKeys := indexTasks(userLines)
+
+	var conflicts []Conflict
+	replace := map[int]string{}
+	dropped := map[int]bool{}
+	for _, key := range userKeys {
+		u := userTasks[key]
+		a, inAgent := agentTasks[key]
+		b, inBase := baseTasks[key]
+		switch {
+		case inAgent && inBase:
+			body := u.body
+			if u.body == b.body {
+				body = a.body
+			} else if a.body != b.body && a.body != u.body {
+				conflicts = append(conflicts, Conflict{Task: conflictName(key), Reason: "changed by both you and the agent; kept yours", User: u.line, Agent: a.line})
+				continue
+			}
+			checked := u.checked
+			if u.checked == b.checked {
+				checked = a.checked
+			}
+			replace[u.index] = setCheckbox(body, checked)
+		case inAgent:
+			if a.body != u.body {
+				conflicts = append(conflicts, Conflict{Task: conflictName(key), Reason: "added by both you and the agent with different text; kept yours", User: u.line, Agent: a.line})
+				continue
+			}
+			replace[u.index] = setCheckbox(u.body, u.checked || a.checked)
+		case inBase:
+			if u.body == b.body && u.checked == b.checked {
+				dropped[u.index] = true
+				continue
+			}
+			conflicts = append(conflicts, Conflict{Task: conflictName(key), Reason: "removed by the agent after you changed it; kept yours", User: u.line})
+		}
+	}
+
+	// Place what the agent added after the closest earlier task both sides
+	// still have; -1 means before the user's first task.
+	inserts := map[int][]string{}
+	anchor := -1
+	for _, key := range agentKeys {
+		a := agentTasks[key]
+		if u, ok := userTasks[key]; ok {
+			if !dropped[u.index] {
+				anchor = u.index
+			}
+			continue
+		}
+		b, inBase := baseTasks[key]
+		if !inBase {
+			inserts[anchor] = append(inserts[anchor], a.line)
+			continue
+		}
+		if a.body != b.body || a.checked != b.checked {
+			conflicts = append(conflicts, Conflict{Task: conflictName(key), Reason: "removed by you after the agent changed it; left out", Agent: a.line})
+		}
+	}
+
+	first := len(userLines)
+	if len(userKeys) > 0 {
+		first = userTasks[userKeys[0]].index
+	}
+	var out []string
+	for i, line := range userLines {
+		if i == first {
+			out = append(out, inserts[-1]...)
+		}
+		if dropped[i] {
+			continue
+		}
+		if r, ok := replace[i]; ok {
+			line = r
+		}
+		out = append(out, line)
+		out = append(out, inserts[i]...)
+	}
+	if first == len(userLines) {
+		out = append(out, inserts[-1]...)
+	}
+	return joinMergeLines(out, user), conflicts
+}
+
+// splitMergeLines splits data into lines without their terminators.
+func splitMergeLines(data []byte) []string {
+	s := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// joinMergeLines joins lines with the line ending of like, ending with a
+// newline when like does.
+func joinMergeLines(lines []string, like []byte) []byte {
+	sep := "\n"
+	if bytes.Contains(like, []byte("\r\n")) {
+		sep = "\r\n"
+	}
+	s := strings.Join(lines, sep)
+	if len(lines) > 0 && bytes.HasSuffix(like, []byte("\n")) {
+		s += sep
+	}
+	return []byte(s)
+}
+
+// indexTasks returns the checkbox lines after any frontmatter by key, and
+// the keys in file order. A key seen again gets a "#n" suffix.
+func indexTasks(lines []string) (map[string]mergeTask, []string) {
+	byKey := map[string]mergeTask{}
+	var keys []string
+	_, start := parseFrontmatter(lines)
+	for i := start; i < len(lines); i++ {
+		m := taskLineRE.FindStringSubmatchIndex(lines[i])
+		if m == nil {
+			continue
+		}
+		text, _ := splitNote(strings.TrimSpace(lines[i][m[4]:m[5]]))
+		text, _ = splitBlockedAnnotation(text)
+		key := ExtractTaskID(text)
+		if key == "" {
+			key = text
+		}
+		for n, first := 2, key; ; n++ {
+			if _, seen := byKey[key]; !seen {
+				break
+			}
+			key = fmt.Sprintf("%s#%d", first, n)
+		}
+		byKey[key] = mergeTask{
+			index:   i,
+			line:    lines[i],
+			body:    setCheckbox(lines[i], false),
+			checked: lines[i][m[2]:m[3]] != " ",
+		}
+		keys = append(keys, key)
+	}
+	return byKey, keys
+}
+
+// setCheckbox returns the task line with its checkbox set to checked.
+func setCheckbox(line string, checked bool) string {
+	m := taskLineRE.FindStringSubmatchIndex(line)
+	if m == nil {
+		return line
+	}
+	mark := " "
+	if checked {
+		mark = "x"
+		if line[m[2]:m[3]] == "X" {
+			mark = "X"
+		}
+	}
+	return line[:m[2]] + mark + line[m[3]:]
+}
+
+// conflictName shortens a text key for the conflicts report.
+func conflictName(key string) string {
+	if r := []rune(key); len(r) > 60 {
+		return string(r[:57]) + "..."
+	}
+	return key
+}
@@ -0,0 +1,74 @@
+package tasks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mergeBase = "# Tasks\n\n- [ ] T001 Parse input\n- [ ] T002 Write output\n- [ ] T003 Add docs\n"
+
+func TestMerge_KeepsUserTasksAndAgentChecks(t *testing.T) {
+	agent := "# Tasks\n\n- [x] T001 Parse input\n- [x] T002 Write output\n- [ ] T003 Add docs\n"
+	user := "# Tasks\n\nNotes from review.\n\n- [ ] T001 Parse input\n- [ ] T002 Write output\n- [ ] T003 Add docs\n- [ ] T004 Handle errors\n"
+
+	merged, conflicts := Merge([]byte(mergeBase), []byte(agent), []byte(user))
+	assert.Empty(t, conflicts)
+	assert.Equal(t, "# Tasks\n\nNotes from review.\n\n- [x] T001 Parse input\n- [x] T002 Write output\n- [ ] T003 Add docs\n- [ ] T004 Handle errors\n", string(merged))
+}
+
+func TestMerge_UserChangesWin(t *testing.T) {
+	agent := "- [x] T001 Parse input\n- [ ] T002 Write output <!-- ralph:blocked needs API key -->\n- [ ] T003 Add docs\n"
+	user := "- [x] T001 Parse input\n- [ ] T002 Write output\n- [x] T003 Add docs (done by hand)\n"
+
+	merged, conflicts := Merge([]byte(mergeBase), []byte(agent), []byte(user))
+	assert.Empty(t, conflicts)
+	assert.Equal(t, "- [x] T001 Parse input\n- [ ] T002 Write output <!-- ralph:blocked needs API key -->\n- [x] T003 Add docs (done by hand)\n", string(merged))
+}
+
+func TestMerge_AgentAddedAndRemovedTasks(t *testing.T) {
+	agent := "# Tasks\n\n- [x] T001 Parse input\n- [ ] T001a Validate input\n- [ ] T003 Add docs\n"
+	user := "# Tasks\n\n- [ ] T001 Parse input\n- [ ] T002 Write output\n- [ ] T003 Add docs\n- [ ] T004 Release\n"
+
+	merged, conflicts := Merge([]byte(mergeBase), []byte(agent), []byte(user))
+	assert.Empty(t, conflicts)
+	assert.Equal(t, "# Tasks\n\n- [x] T001 Parse input\n- [ ] T001a Validate input\n- [ ] T003 Add docs\n- [ ] T004 Release\n", string(merged))
+}
+
+func TestMerge_Conflicts(t *testing.T) {
+	agent := "- [x] T001 Parse input from stdin\n- [x] T002 Write output\n- [ ] T003 Add docs\n"
+	user := "- [ ] T001 Parse input from a file\n- [ ] T003 Add docs\n"
+
+	merged, conflicts := Merge([]byte(mergeBase), []byte(agent), []byte(user))
+	assert.Equal(t, user, string(merged))
+	require.Len(t, conflicts, 2)
+	assert.Equal(t, Conflict{
+		Task:   "T001",
+		Reason: "changed by both you and the agent; kept yours",
+		User:   "- [ ] T001 Parse input from a file",
+		Agent:  "- [x] T001 Parse input from stdin",
+	}, conflicts[0])
+	assert.Equal(t, "T002", conflicts[1].Task)
+	assert.Equal(t, "T002: removed by you after the agent changed it; left out\n  agent: - [x] T002 Write output", conflicts[1].String())
+}
+
+func TestMerge_UnchangedSides(t *testing.T) {
+	edited := "- [x] T001 Parse input\n"
+	merged, conflicts := Merge([]byte(mergeBase), []byte(edited), []byte(mergeBase))
+	assert.Equal(t, edited, string(merged))
+	assert.Empty(t, conflicts)
+
+	merged, _ = Merge([]byte(mergeBase), []byte(mergeBase), []byte(edited))
+	assert.Equal(t, edited, string(merged))
+}
+
+func TestMerge_TasksWithoutIDsAndCRLF(t *testing.T) {
+	base := "- [ ] Parse input\r\n- [ ] Write output\r\n"
+	agent := "- [x] Parse input\r\n- [ ] Write output\r\n"
+	user := "- [ ] Parse input\r\n- [ ] Write output\r\n- [ ] Add docs\r\n"
+
+	merged, conflicts := Merge([]byte(base), []byte(agent), []byte(user))
+	assert.Empty(t, conflicts)
+	assert.Equal(t, "- [x] Parse input\r\n- [ ] Write output\r\n- [ ] Add docs\r\n", string(merged))
+}
@@ -0,0 +1,185 @@
+package tasks
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// metaBlockRE matches the metadata block that may end a task line:
+// "T012 Do thing {depends: T010, est: 2h, area: api}".
+var metaBlockRE = regexp.MustCompile(`\s*\{([^{}]*:[^{}]*)\}\s*$`)
+
+// File is a parsed tasks file.
+type File struct {
+	// Frontmatter holds the YAML block between "---" lines at the top of
+	// the file, or nil when there is none.
+	Frontmatter map[string]any
+	Tasks       []Task
+}
+
+// Parse reads filePath into a File: the optional YAML frontmatter and every
+// checkbox task with its metadata. Checkbox lines inside the frontmatter are
+// not tasks. A leading "---" block that is not a closed YAML mapping is
+// treated as ordinary markdown, so files that merely start with a
+// horizontal rule parse as before.
+func Parse(filePath string) (*File, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &File{}
+	body := 0
+	if fm, n := parseFrontmatter(lines); n > 0 {
+		result.Frontmatter, body = fm, n
+	}
+
+	for i := body; i < len(lines); i++ {
+		m := taskLineRE.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+		text, note := splitNote(strings.TrimSpace(m[2]))
+		text, reason := splitBlockedAnnotation(text)
+		text, meta := splitMetadata(text)
+		id := ExtractTaskID(text)
+		if id == "" {
+			id = fmt.Sprintf("L%d", i+1)
+		}
+		result.Tasks = append(result.Tasks, Task{
+			ID:            id,
+			Text:          text,
+			Checked:       m[1] != " ",
+			Line:          i + 1,
+			BlockedReason: reason,
+			Note:          note,
+			Meta:          meta,
+			Depends:       splitList(meta["depends"]),
+		})
+	}
+	return result, nil
+}
+
+// parseFrontmatter decodes a YAML mapping between "---" lines at the top of
+// the file. It returns the mapping and the number of lines it spans, or
+// 0 when there is no valid frontmatter.
+func parseFrontmatter(lines []string) (map[string]any, int) {
+	if len(lines) == 0 || strings.TrimRight(lines[0], " ") != "---" {
+		return nil, 0
+	}
+	for i := 1; i < len(lines); i++ {
+		if end := strings.TrimRight(lines[i], " "); end != "---" && end != "..." {
+			continue
+		}
+		var fm map[string]any
+		if err := yaml.Unmarshal([]byte(strings.Join(lines[1:i], "\n")), &fm); err != nil {
+			return nil, 0
+		}
+		return fm, i + 1
+	}
+	return nil, 0
+}
+
+// Counts returns how many tasks are checked and unchecked.
+func (f *File) Counts() (checked, unchecked int) {
+	for _, t := range f.Tasks {
+		if t.Checked {
+			checked++
+		} else {
+			unchecked++
+		}
+	}
+	return checked, unchecked
+}
+
+// splitMetadata removes a trailing {key: value, ...} block from task text
+// and returns the cleaned text and the metadata (nil if none). A comma
+// separated item without a colon continues the previous value, so
+// "{depends: T010, T011, est: 2h}" gives depends "T010, T011". Values may
+// also be written as YAML-style lists: "{depends: [T010, T011]}".
+func splitMetadata(text string) (string, map[string]string) {
+	m := metaBlockRE.FindStringSubmatchIndex(text)
+	if m == nil {
+		return text, nil
+	}
+	body := text[m[2]:m[3]]
+	meta := make(map[string]string)
+	key := ""
+	for _, item := range splitTopLevel(body) {
+		k, v, ok := strings.Cut(item, ":")
+		if ok && !strings.ContainsAny(k, "[]") {
+			key = strings.ToLower(strings.TrimSpace(k))
+			meta[key] = strings.Trim(strings.TrimSpace(v), "[]")
+			continue
+		}
+		if key != "" && strings.TrimSpace(item) != "" {
+			meta[key] += ", " + strings.TrimSpace(item)
+		}
+	}
+	delete(meta, "")
+	return strings.TrimSpace(text[:m[0]]), meta
+}
+
+// splitTopLevel splits s on commas outside [...] lists and normalizes
+// list separators.
+func splitTopLevel(s string) []string {
+	var items []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(items, s[start:])
+}
+
+// splitList splits a metadata value such as "T010, T011" or "T010 T011".
+func splitList(v string) []string {
+	fields := strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' || r == '[' || r == ']' })
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// FormatMetadata renders metadata as "{key: value, ...}" with sorted keys,
+// or "" when meta is empty.
+func FormatMetadata(meta map[string]string) string {
+	if len(meta) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + ": " + meta[k]
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
@@ -0,0 +1,83 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_FrontmatterAndMetadata(t *testing.T) {
+	content := `---
+title: Reports
+priority: 2
+areas: [api, ui]
+notes: |
+  - [ ] not a task
+---
+# Tasks
+
+- [x] T010 Add report model {area: api, est: 1h}
+- [ ] T011 Add CSV writer {depends: T010}
+- [ ] T012 Do thing {depends: T010, T011, est: 2h, area: api}
+- [ ] T013 Wire button {depends: [T011, T012]} <!-- ralph:blocked needs design -->
+- [ ] Polish {styles}
+`
+	path := filepath.Join(t.TempDir(), "tasks.md")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	f, err := Parse(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Reports", f.Frontmatter["title"])
+	assert.Equal(t, 2, f.Frontmatter["priority"])
+	assert.Equal(t, []any{"api", "ui"}, f.Frontmatter["areas"])
+
+	require.Len(t, f.Tasks, 5)
+	assert.Equal(t, Task{ID: "T010", Text: "T010 Add report model", Checked: true, Line: 10,
+		Meta: map[string]string{"area": "api", "est": "1h"}}, f.Tasks[0])
+	assert.Equal(t, []string{"T010"}, f.Tasks[1].Depends)
+
+	assert.Equal(t, "T012 Do thing", f.Tasks[2].Text)
+	assert.Equal(t, map[string]string{"depends": "T010, T011", "est": "2h", "area": "api"}, f.Tasks[2].Meta)
+	assert.Equal(t, []string{"T010", "T011"}, f.Tasks[2].Depends)
+
+	assert.Equal(t, "T013 Wire button", f.Tasks[3].Text)
+	assert.Equal(t, []string{"T011", "T012"}, f.Tasks[3].Depends)
+	assert.Equal(t, "needs design", f.Tasks[3].BlockedReason)
+
+	assert.Equal(t, "Polish {styles}", f.Tasks[4].Text, "braces without key: value are task text")
+	assert.Nil(t, f.Tasks[4].Meta)
+
+	checked, unchecked := f.Counts()
+	assert.Equal(t, 1, checked)
+	assert.Equal(t, 4, unchecked)
+}
+
+func TestParse_LeadingRuleIsNotFrontmatter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.md")
+
+	for _, content := range []string{
+		"---\n- [ ] T001 Task\n---\n- [ ] T002 Task\n", // a YAML list, not a mapping
+		"---\ntitle: [unterminated\n---\n- [ ] T001\n- [ ] T002\n",
+		"---\n- [ ] T001 Task\n- [ ] T002 Task\n", // never closed
+	} {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		f, err := Parse(path)
+		require.NoError(t, err)
+		assert.Nil(t, f.Frontmatter, content)
+		assert.Len(t, f.Tasks, 2, content)
+	}
+}
+
+func TestParse_MissingFile(t *testing.T) {
+	_, err := Parse(filepath.Join(t.TempDir(), "missing.md"))
+	assert.Error(t, err)
+}
+
+func TestFormatMetadata(t *testing.T) {
+	assert.Equal(t, "{area: api, est: 2h}", FormatMetadata(map[string]string{"est": "2h", "area": "api"}))
+	assert.Equal(t, "", FormatMetadata(nil))
+}
@@ -0,0 +1,87 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// SpecDir is a spec-kit feature folder (e.g. specs/001-photo-albums) holding
+// the feature spec, its implementation plan and the task list. Files that do
+// not exist are left empty.
+type SpecDir struct {
+	Dir       string
+	SpecFile  string // spec.md
+	PlanFile  string // plan.md
+	TasksFile string // tasks.md
+}
+
+// LoadSpecDir reads the spec-kit layout of dir, which must contain a
+// tasks.md. Paths are absolute.
+func LoadSpecDir(dir string) (*SpecDir, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("resolving spec dir: %w", err)
+	}
+	tasksFile := filepath.Join(abs, "tasks.md")
+	if _, err := os.Stat(tasksFile); err != nil {
+		return nil, fmt.Errorf("no tasks.md in spec dir %s", dir)
+	}
+	sd := &SpecDir{Dir: abs, TasksFile: tasksFile}
+	sd.SpecFile = existing(filepath.Join(abs, "spec.md"))
+	sd.PlanFile = existing(filepath.Join(abs, "plan.md"))
+	return sd, nil
+}
+
+// SpecDirFor returns the spec-kit folder a tasks file belongs to, or nil
+// when its directory holds neither a spec.md nor a plan.md.
+func SpecDirFor(tasksFile string) *SpecDir {
+	sd, err := LoadSpecDir(filepath.Dir(tasksFile))
+	if err != nil || (sd.SpecFile == "" && sd.PlanFile == "") {
+		return nil
+	}
+	sd.TasksFile = tasksFile
+	return sd
+}
+
+func existing(path string) string {
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		return path
+	}
+	return ""
+}
+
+// currentFeature names the spec-kit feature being worked on: SPECIFY_FEATURE
+// when set, otherwise the current git branch. spec-kit names feature
+// branches after their folder. It is a variable so tests can replace it.
+var currentFeature = func() string {
+	if f := os.Getenv("SPECIFY_FEATURE"); f != "" {
+		return f
+	}
+	out, err := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// pickSpecFolder chooses among candidate specs/*/tasks.md files, which are
+// in alphabetical (and so spec-kit number) order: the folder named after the
+// current feature, else the first one with unchecked tasks, else the first.
+func pickSpecFolder(matches []string) string {
+	if feature := currentFeature(); feature != "" {
+		for _, m := range matches {
+			if filepath.Base(filepath.Dir(m)) == feature {
+				return m
+			}
+		}
+	}
+	for _, m := range matches {
+		if n, err := CountUnchecked(m); err == nil && n > 0 {
+			return m
+		}
+	}
+	return matches[0]
+}
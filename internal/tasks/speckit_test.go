@@ -0,0 +1,79 @@
+package tasks
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withFeature overrides the current spec-kit feature for one test.
+func withFeature(t *testing.T, feature string) {
+	t.Helper()
+	orig := currentFeature
+	currentFeature = func() string { return feature }
+	t.Cleanup(func() { currentFeature = orig })
+}
+
+func TestLoadSpecDir(t *testing.T) {
+	dir := realPath(t, t.TempDir())
+	writeFile(t, filepath.Join(dir, "tasks.md"), "- [ ] T001 a\n")
+	writeFile(t, filepath.Join(dir, "spec.md"), "# Spec\n")
+
+	sd, err := LoadSpecDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, &SpecDir{
+		Dir:       dir,
+		SpecFile:  filepath.Join(dir, "spec.md"),
+		TasksFile: filepath.Join(dir, "tasks.md"),
+	}, sd)
+}
+
+func TestLoadSpecDir_NoTasks(t *testing.T) {
+	_, err := LoadSpecDir(t.TempDir())
+	assert.ErrorContains(t, err, "no tasks.md in spec dir")
+}
+
+func TestSpecDirFor(t *testing.T) {
+	dir := t.TempDir()
+	tasksFile := filepath.Join(dir, "tasks.md")
+	writeFile(t, tasksFile, "- [ ] T001 a\n")
+	assert.Nil(t, SpecDirFor(tasksFile), "a lone tasks.md is not a spec-kit folder")
+
+	writeFile(t, filepath.Join(dir, "plan.md"), "# Plan\n")
+	sd := SpecDirFor(tasksFile)
+	require.NotNil(t, sd)
+	assert.Equal(t, filepath.Join(dir, "plan.md"), sd.PlanFile)
+	assert.Empty(t, sd.SpecFile)
+}
+
+func TestDiscoverTasksFile_SpecKitSkipsCompletedFeatures(t *testing.T) {
+	withFeature(t, "")
+	tmp := realPath(t, t.TempDir())
+	chdirTemp(t, tmp)
+	writeFile(t, filepath.Join(tmp, "specs", "001-auth", "tasks.md"), "- [x] T001 done\n")
+	writeFile(t, filepath.Join(tmp, "specs", "002-albums", "tasks.md"), "- [ ] T001 todo\n")
+	writeFile(t, filepath.Join(tmp, "specs", "003-sharing", "tasks.md"), "- [ ] T001 later\n")
+
+	got, err := DiscoverTasksFile("")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(tmp, "specs", "002-albums", "tasks.md"), got)
+}
+
+func TestDiscoverTasksFile_SpecKitPrefersFeatureBranch(t *testing.T) {
+	withFeature(t, "003-sharing")
+	tmp := realPath(t, t.TempDir())
+	chdirTemp(t, tmp)
+	writeFile(t, filepath.Join(tmp, "specs", "002-albums", "tasks.md"), "- [ ] T001 todo\n")
+	writeFile(t, filepath.Join(tmp, "specs", "003-sharing", "tasks.md"), "- [ ] T001 later\n")
+
+	got, err := DiscoverTasksFile("")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(tmp, "specs", "003-sharing", "tasks.md"), got)
+}
+
+func TestCurrentFeature_SpecifyFeatureEnv(t *testing.T) {
+	t.Setenv("SPECIFY_FEATURE", "004-search")
+	assert.Equal(t, "004-search", currentFeature())
+}
@@ -0,0 +1,173 @@
+package tasks
+
+import (
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ExpandTasksFiles resolves --tasks-file values to absolute paths, in the
+// order given. A value containing glob characters is expanded; "**" matches
+// any number of directories, so specs/**/tasks.md finds every tasks.md under
+// specs. Plain paths must exist and globs must match at least one file.
+// Files matched more than once are kept at their first position.
+func ExpandTasksFiles(patterns []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	for _, p := range patterns {
+		var matches []string
+		if IsGlob(p) {
+			m, err := globRecursive(p)
+			if err != nil {
+				return nil, fmt.Errorf("expand %s: %w", p, err)
+			}
+			if len(m) == 0 {
+				return nil, fmt.Errorf("no tasks files match %s", p)
+			}
+			matches = m
+		} else {
+			abs, err := DiscoverTasksFile(p)
+			if err != nil {
+				return nil, err
+			}
+			matches = []string{abs}
+		}
+		for _, m := range matches {
+			abs, err := filepath.Abs(m)
+			if err != nil {
+				return nil, fmt.Errorf("resolving tasks file path: %w", err)
+			}
+			if !seen[abs] {
+				seen[abs] = true
+				files = append(files, abs)
+			}
+		}
+	}
+	return files, nil
+}
+
+// IsGlob reports whether p contains glob metacharacters.
+func IsGlob(p string) bool {
+	return strings.ContainsAny(p, "*?[")
+}
+
+// globRecursive is filepath.Glob with support for "**". Matches are
+// returned in lexical order.
+func globRecursive(pattern string) ([]string, error) {
+	if !strings.Contains(pattern, "**") {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(matches)
+		return matches, nil
+	}
+
+	slashed := filepath.ToSlash(pattern)
+	root := "."
+	if i := strings.IndexAny(slashed, "*?["); i > 0 {
+		if j := strings.LastIndex(slashed[:i], "/"); j >= 0 {
+			root = slashed[:j]
+			if root == "" {
+				root = "/"
+			}
+		}
+	}
+	re, err := globRegexp(slashed)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	err = filepath.WalkDir(filepath.FromSlash(root), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == filepath.FromSlash(root) && os.IsNotExist(err) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if !d.IsDir() && re.MatchString(filepath.ToSlash(path)) {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	return matches, err
+}
+
+// globRegexp translates a slash-separated glob into an anchored regexp.
+// "**/" matches zero or more directories and a bare "**" matches anything.
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	pattern = strings.TrimPrefix(pattern, "./")
+	if !strings.HasPrefix(pattern, "/") {
+		b.WriteString(`(?:\./)?`)
+	}
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated [ in pattern")
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// OrderByPriority sorts tasks files by the "priority" key of their
+// frontmatter, lowest first. Files without a numeric priority run after
+// those with one, and ties keep their original order.
+func OrderByPriority(files []string) []string {
+	prio := make(map[string]float64, len(files))
+	for _, f := range files {
+		prio[f] = math.Inf(1)
+		if parsed, err := Parse(f); err == nil {
+			if p, ok := numericValue(parsed.Frontmatter["priority"]); ok {
+				prio[f] = p
+			}
+		}
+	}
+	ordered := append([]string(nil), files...)
+	sort.SliceStable(ordered, func(i, j int) bool { return prio[ordered[i]] < prio[ordered[j]] })
+	return ordered
+}
+
+func numericValue(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
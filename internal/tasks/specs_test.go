@@ -0,0 +1,97 @@
+package tasks
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandTasksFiles_RecursiveGlob(t *testing.T) {
+	dir := t.TempDir()
+	chdirTemp(t, dir)
+	writeFile(t, "specs/002-api/tasks.md", "- [ ] a\n")
+	writeFile(t, "specs/001-core/tasks.md", "- [ ] b\n")
+	writeFile(t, "specs/003-ui/web/tasks.md", "- [ ] c\n")
+	writeFile(t, "specs/003-ui/notes.md", "notes\n")
+	writeFile(t, "tasks.md", "- [ ] root\n")
+
+	files, err := ExpandTasksFiles([]string{"specs/**/tasks.md"})
+	require.NoError(t, err)
+
+	root := realPath(t, dir)
+	assert.Equal(t, []string{
+		filepath.Join(root, "specs/001-core/tasks.md"),
+		filepath.Join(root, "specs/002-api/tasks.md"),
+		filepath.Join(root, "specs/003-ui/web/tasks.md"),
+	}, files)
+}
+
+func TestExpandTasksFiles_RepeatedAndDeduplicated(t *testing.T) {
+	dir := t.TempDir()
+	chdirTemp(t, dir)
+	writeFile(t, "b/tasks.md", "- [ ] b\n")
+	writeFile(t, "a/tasks.md", "- [ ] a\n")
+
+	files, err := ExpandTasksFiles([]string{"b/tasks.md", "*/tasks.md"})
+	require.NoError(t, err)
+
+	root := realPath(t, dir)
+	assert.Equal(t, []string{
+		filepath.Join(root, "b/tasks.md"),
+		filepath.Join(root, "a/tasks.md"),
+	}, files)
+}
+
+func TestExpandTasksFiles_Errors(t *testing.T) {
+	dir := t.TempDir()
+	chdirTemp(t, dir)
+
+	_, err := ExpandTasksFiles([]string{"missing.md"})
+	assert.ErrorContains(t, err, "tasks file not found")
+
+	_, err = ExpandTasksFiles([]string{"specs/**/tasks.md"})
+	assert.ErrorContains(t, err, "no tasks files match specs/**/tasks.md")
+
+	_, err = ExpandTasksFiles([]string{"specs/**/[a.md"})
+	assert.ErrorContains(t, err, "unterminated")
+}
+
+func TestGlobRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"specs/**/tasks.md", "specs/tasks.md", true},
+		{"specs/**/tasks.md", "specs/a/b/tasks.md", true},
+		{"specs/**/tasks.md", "./specs/a/tasks.md", true},
+		{"specs/**/tasks.md", "other/a/tasks.md", false},
+		{"specs/*/tasks.md", "specs/a/b/tasks.md", false},
+		{"specs/[!x]?/tasks.md", "specs/ab/tasks.md", true},
+		{"specs/[!x]?/tasks.md", "specs/xb/tasks.md", false},
+		{"specs/**", "specs/a/tasks.md", true},
+	}
+	for _, tt := range tests {
+		re, err := globRegexp(tt.pattern)
+		require.NoError(t, err)
+		assert.Equal(t, tt.match, re.MatchString(tt.path), "%s ~ %s", tt.pattern, tt.path)
+	}
+}
+
+func TestOrderByPriority(t *testing.T) {
+	dir := t.TempDir()
+	none := filepath.Join(dir, "none.md")
+	low := filepath.Join(dir, "low.md")
+	high := filepath.Join(dir, "high.md")
+	alsoHigh := filepath.Join(dir, "also-high.md")
+	writeFile(t, none, "- [ ] x\n")
+	writeFile(t, low, "---\npriority: 5\n---\n- [ ] x\n")
+	writeFile(t, high, "---\npriority: 1\n---\n- [ ] x\n")
+	writeFile(t, alsoHigh, "---\npriority: \"1\"\n---\n- [ ] x\n")
+
+	ordered := OrderByPriority([]string{none, low, high, alsoHigh})
+
+	assert.Equal(t, []string{high, alsoHigh, low, none}, ordered)
+}
@@ -0,0 +1,114 @@
+package testresult
+
+import (
+	"path"
+	"strings"
+)
+
+// FlakyFlips is how many times a test must flip between passing and
+// failing, in iterations that changed nothing related to it, to be
+// suspected flaky.
+const FlakyFlips = 2
+
+// Track is what a session knows about a test that failed at least once:
+// its latest outcome and how often that outcome flipped in iterations
+// that changed nothing related to the test.
+type Track struct {
+	Name string `json:"name"`
+	// Passed is the test's outcome in Iteration, the last one that ran it.
+	Passed    bool `json:"passed"`
+	Iteration int  `json:"iteration"`
+	Flips     int  `json:"flips,omitempty"`
+	// FlakySince is the iteration from which the test is suspected
+	// flaky; 0 when it is not.
+	FlakySince int `json:"flaky_since,omitempty"`
+}
+
+// UpdateTracks records the outcomes of run, from iteration, in tracks and
+// returns them with the tests that became suspected flaky. A test is
+// tracked from its first failure. changed lists the files, relative to the
+// project root, the iteration changed; nil when unknown, in which case no
+// flip is counted.
+func UpdateTracks(tracks []Track, run Summary, iteration int, changed []string) ([]Track, []string) {
+	index := make(map[string]int, len(tracks))
+	for i, t := range tracks {
+		index[t.Name] = i
+	}
+	var nowFlaky []string
+	record := func(name string, passed bool) {
+		i, ok := index[name]
+		if !ok {
+			if passed {
+				return
+			}
+			index[name] = len(tracks)
+			tracks = append(tracks, Track{Name: name, Iteration: iteration})
+			return
+		}
+		t := &tracks[i]
+		if t.Passed != passed && changed != nil && !related(name, changed) {
+			t.Flips++
+			if t.Flips >= FlakyFlips && t.FlakySince == 0 {
+				t.FlakySince = iteration
+				nowFlaky = append(nowFlaky, name)
+			}
+		}
+		t.Passed, t.Iteration = passed, iteration
+	}
+	for _, name := range run.Failing {
+		record(name, false)
+	}
+	for _, name := range run.Passing {
+		record(name, true)
+	}
+	return tracks, nowFlaky
+}
+
+// Flaky returns the names of the tests in tracks suspected flaky.
+func Flaky(tracks []Track) map[string]bool {
+	flaky := map[string]bool{}
+	for _, t := range tracks {
+		if t.FlakySince > 0 {
+			flaky[t.Name] = true
+		}
+	}
+	return flaky
+}
+
+// related guesses whether a change to one of files could explain a change
+// in the outcome of the named test: a file in the Go package of a
+// "Test (package)" name, go.mod or go.sum, or a file whose name, without
+// extensions and test suffixes, appears in the test's name. It errs
+// towards related, so a real regression is not taken for a flaky test.
+func related(test string, files []string) bool {
+	pkg := ""
+	if open := strings.LastIndex(test, " ("); open >= 0 && strings.HasSuffix(test, ")") {
+		pkg = test[open+2 : len(test)-1]
+		if pkg == "package failed" {
+			pkg = test[:open]
+		}
+	}
+	name := strings.ToLower(test)
+	for _, f := range files {
+		base := path.Base(f)
+		if pkg != "" {
+			dir := path.Dir(f)
+			if base == "go.mod" || base == "go.sum" || (dir == "." && path.Ext(f) == ".go") ||
+				pkg == dir || strings.HasSuffix(pkg, "/"+dir) {
+				return true
+			}
+		}
+		stem := strings.ToLower(base)
+		if i := strings.Index(stem, "."); i > 0 {
+			stem = stem[:i]
+		}
+		for _, suffix := range []string{"_test", "tests", "test", "_spec", "spec"} {
+			stem = strings.TrimSuffix(stem, suffix)
+		}
+		stem = strings.TrimRight(stem, "_-.")
+		if len(stem) >= 3 && strings.Contains(name, stem) {
+			return true
+		}
+	}
+	return false
+}
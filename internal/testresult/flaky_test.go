@@ -0,0 +1,63 @@
+package testresult
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateTracks_FlipsWithoutRelatedChanges(t *testing.T) {
+	fail := Summary{Failing: []string{"TestRetry (example.com/app/net)"}, Passing: []string{"TestAdd (example.com/app)"}}
+	pass := Summary{Passing: []string{"TestRetry (example.com/app/net)", "TestAdd (example.com/app)"}}
+
+	tracks, flaky := UpdateTracks(nil, fail, 1, []string{"README.md"})
+	assert.Equal(t, []Track{{Name: "TestRetry (example.com/app/net)", Iteration: 1}}, tracks, "passing tests are tracked from their first failure")
+	assert.Empty(t, flaky)
+
+	tracks, flaky = UpdateTracks(tracks, pass, 2, []string{})
+	assert.Equal(t, Track{Name: "TestRetry (example.com/app/net)", Passed: true, Iteration: 2, Flips: 1}, tracks[0])
+	assert.Empty(t, flaky)
+
+	tracks, flaky = UpdateTracks(tracks, fail, 3, []string{"docs/usage.md"})
+	assert.Equal(t, []string{"TestRetry (example.com/app/net)"}, flaky)
+	assert.Equal(t, 3, tracks[0].FlakySince)
+	assert.Equal(t, map[string]bool{"TestRetry (example.com/app/net)": true}, Flaky(tracks))
+
+	tracks, flaky = UpdateTracks(tracks, pass, 4, []string{})
+	assert.Empty(t, flaky, "a test is reported flaky once")
+	assert.Equal(t, 3, tracks[0].FlakySince)
+}
+
+func TestUpdateTracks_RelatedOrUnknownChangesAreNoFlips(t *testing.T) {
+	fail := Summary{Failing: []string{"CartTests.RejectsEmptyCart"}}
+	pass := Summary{Passing: []string{"CartTests.RejectsEmptyCart"}}
+
+	tracks, _ := UpdateTracks(nil, fail, 1, nil)
+	tracks, _ = UpdateTracks(tracks, pass, 2, []string{"src/Cart.cs"})
+	tracks, _ = UpdateTracks(tracks, fail, 3, nil)
+	tracks, _ = UpdateTracks(tracks, pass, 4, []string{"tests/CartTests.cs"})
+	assert.Zero(t, tracks[0].Flips)
+	assert.Empty(t, Flaky(tracks))
+}
+
+func TestRelated(t *testing.T) {
+	tests := []struct {
+		test  string
+		files []string
+		want  bool
+	}{
+		{"TestRetry (example.com/app/internal/net)", []string{"internal/net/retry.go"}, true},
+		{"TestRetry (example.com/app/internal/net)", []string{"internal/netx/dial.go"}, false},
+		{"TestRetry (example.com/app/internal/net)", []string{"go.sum"}, true},
+		{"TestRetry (example.com/app/internal/net)", []string{"main.go"}, true},
+		{"example.com/app/internal/net (package failed)", []string{"internal/net/retry.go"}, true},
+		{"cart removes items", []string{"src/cart.js"}, true},
+		{"cart removes items", []string{"src/__tests__/cart.test.js"}, true},
+		{"Api.Tests.CartTests.RejectsEmptyCart", []string{"src/Api/Cart.cs"}, true},
+		{"Api.Tests.CartTests.RejectsEmptyCart", []string{"src/Api/Order.cs", "README.md"}, false},
+		{"cart removes items", []string{"ui/a.js"}, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, related(tt.test, tt.files), "%s %v", tt.test, tt.files)
+	}
+}
@@ -0,0 +1,224 @@
+// Package testresult reads the results of a test run, so the tests the
+// VERIFY_CMDS ran can be counted, shown to the validator, compared from
+// one iteration to the next and, when they flip for no reason, suspected
+// flaky.
+package testresult
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Summary counts the tests of one or more runs by outcome and names the
+// ones that failed. The passing ones are only named in memory, for
+// UpdateTracks, as there may be thousands.
+type Summary struct {
+	Passed  int      `json:"passed"`
+	Failed  int      `json:"failed"`
+	Skipped int      `json:"skipped"`
+	Failing []string `json:"failing,omitempty"`
+	Passing []string `json:"-"`
+}
+
+// String returns the counts, e.g. "40 passed, 2 failed, 1 skipped".
+func (s Summary) String() string {
+	return fmt.Sprintf("%d passed, %d failed, %d skipped", s.Passed, s.Failed, s.Skipped)
+}
+
+// Add adds the counts and failing tests of other to s.
+func (s *Summary) Add(other Summary) {
+	s.Passed += other.Passed
+	s.Failed += other.Failed
+	s.Skipped += other.Skipped
+	s.Failing = append(s.Failing, other.Failing...)
+	s.Passing = append(s.Passing, other.Passing...)
+}
+
+// Parse reads the test results in data. It understands go test -json
+// output, .NET TRX files and JUnit XML reports such as jest-junit writes,
+// and reports false when data holds none of them.
+func Parse(data []byte) (Summary, bool) {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("<")) {
+		s, err := parseXML(trimmed)
+		return s, err == nil
+	}
+	return parseGoJSON(trimmed)
+}
+
+// ReadFile parses the test report at path (see Parse).
+func ReadFile(path string) (Summary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Summary{}, err
+	}
+	s, ok := Parse(data)
+	if !ok {
+		return Summary{}, fmt.Errorf("%s: unrecognized test report", path)
+	}
+	return s, nil
+}
+
+// Compare returns the tests failing in cur that did not fail in prev, and
+// those that failed in prev and no longer fail in cur.
+func Compare(prev, cur Summary) (newlyFailing, fixed []string) {
+	before := make(map[string]bool, len(prev.Failing))
+	for _, name := range prev.Failing {
+		before[name] = true
+	}
+	now := make(map[string]bool, len(cur.Failing))
+	for _, name := range cur.Failing {
+		now[name] = true
+		if !before[name] {
+			newlyFailing = append(newlyFailing, name)
+		}
+	}
+	for _, name := range prev.Failing {
+		if !now[name] {
+			fixed = append(fixed, name)
+		}
+	}
+	return newlyFailing, fixed
+}
+
+// goTestEvent is one line of go test -json output.
+type goTestEvent struct {
+	Action  string
+	Package string
+	Test    string
+}
+
+// parseGoJSON counts the tests of go test -json output. Lines that are
+// not test events, such as build errors, are skipped. A package that fails
+// without a failing test, as when it does not build, counts as one failed
+// test named after the package.
+func parseGoJSON(data []byte) (Summary, bool) {
+	var s Summary
+	found := false
+	testFailed := map[string]bool{}
+	var failedPackages []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if !bytes.HasPrefix(line, []byte("{")) {
+			continue
+		}
+		var ev goTestEvent
+		if json.Unmarshal(line, &ev) != nil || ev.Action == "" {
+			continue
+		}
+		found = true
+		if ev.Test == "" {
+			if ev.Action == "fail" {
+				failedPackages = append(failedPackages, ev.Package)
+			}
+			continue
+		}
+		switch ev.Action {
+		case "pass":
+			s.Passed++
+			s.Passing = append(s.Passing, ev.Test+" ("+ev.Package+")")
+		case "fail":
+			s.Failed++
+			s.Failing = append(s.Failing, ev.Test+" ("+ev.Package+")")
+			testFailed[ev.Package] = true
+		case "skip":
+			s.Skipped++
+		}
+	}
+	for _, pkg := range failedPackages {
+		if !testFailed[pkg] {
+			s.Failed++
+			s.Failing = append(s.Failing, pkg+" (package failed)")
+		}
+	}
+	return s, found
+}
+
+// xmlTestCase is a JUnit <testcase> or a TRX <UnitTestResult>.
+type xmlTestCase struct {
+	// JUnit
+	Classname string    `xml:"classname,attr"`
+	Name      string    `xml:"name,attr"`
+	Failure   *struct{} `xml:"failure"`
+	Error     *struct{} `xml:"error"`
+	Skipped   *struct{} `xml:"skipped"`
+	// TRX
+	TestName string `xml:"testName,attr"`
+	Outcome  string `xml:"outcome,attr"`
+}
+
+// parseXML counts the test cases of a JUnit report, however its test
+// suites nest, or the test results of a TRX file.
+func parseXML(data []byte) (Summary, error) {
+	var s Summary
+	found := false
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Summary{}, fmt.Errorf("invalid XML: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "testcase":
+			var tc xmlTestCase
+			if err := dec.DecodeElement(&tc, &start); err != nil {
+				return Summary{}, fmt.Errorf("invalid testcase: %w", err)
+			}
+			found = true
+			switch {
+			case tc.Failure != nil || tc.Error != nil:
+				s.Failed++
+				s.Failing = append(s.Failing, junitName(tc))
+			case tc.Skipped != nil:
+				s.Skipped++
+			default:
+				s.Passed++
+				s.Passing = append(s.Passing, junitName(tc))
+			}
+		case "UnitTestResult":
+			var tc xmlTestCase
+			if err := dec.DecodeElement(&tc, &start); err != nil {
+				return Summary{}, fmt.Errorf("invalid UnitTestResult: %w", err)
+			}
+			found = true
+			switch strings.ToLower(tc.Outcome) {
+			case "passed":
+				s.Passed++
+				s.Passing = append(s.Passing, tc.TestName)
+			case "failed", "error", "timeout", "aborted":
+				s.Failed++
+				s.Failing = append(s.Failing, tc.TestName)
+			default:
+				s.Skipped++
+			}
+		}
+	}
+	if !found {
+		return Summary{}, errors.New("no test cases")
+	}
+	return s, nil
+}
+
+// junitName names a JUnit test case by its class and name.
+func junitName(tc xmlTestCase) string {
+	if tc.Classname == "" || strings.HasPrefix(tc.Name, tc.Classname) {
+		return tc.Name
+	}
+	return tc.Classname + " " + tc.Name
+}
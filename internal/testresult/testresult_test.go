@@ -0,0 +1,106 @@
+package testresult
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const goTestJSON = `# example.com/broken
+broken/x.go:3:1: syntax error
+{"Action":"run","Package":"example.com/app","Test":"TestAdd"}
+{"Action":"output","Package":"example.com/app","Test":"TestAdd","Output":"--- PASS: TestAdd\n"}
+{"Action":"pass","Package":"example.com/app","Test":"TestAdd","Elapsed":0}
+{"Action":"run","Package":"example.com/app","Test":"TestSub"}
+{"Action":"fail","Package":"example.com/app","Test":"TestSub","Elapsed":0}
+{"Action":"skip","Package":"example.com/app","Test":"TestSlow","Elapsed":0}
+{"Action":"fail","Package":"example.com/app","Elapsed":0.1}
+{"Action":"fail","Package":"example.com/broken","Elapsed":0}
+`
+
+const trx = `<?xml version="1.0" encoding="utf-8"?>
+<TestRun id="1" xmlns="http://microsoft.com/schemas/VisualStudio/TeamTest/2010">
+  <Results>
+    <UnitTestResult testName="Api.Tests.CreatesOrder" outcome="Passed" />
+    <UnitTestResult testName="Api.Tests.RejectsEmptyCart" outcome="Failed">
+      <Output><ErrorInfo><Message>Expected 400</Message></ErrorInfo></Output>
+    </UnitTestResult>
+    <UnitTestResult testName="Api.Tests.Flaky" outcome="NotExecuted" />
+  </Results>
+  <ResultSummary outcome="Failed"><Counters total="3" passed="1" failed="1" /></ResultSummary>
+</TestRun>
+`
+
+const junit = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="jest tests" tests="4" failures="1">
+  <testsuite name="cart" tests="4">
+    <testcase classname="cart adds items" name="cart adds items" time="0.01"/>
+    <testcase classname="cart" name="removes items" time="0.01">
+      <failure message="expected 0">Error: expected 0</failure>
+    </testcase>
+    <testcase classname="cart" name="checks out" time="0.01"><error/></testcase>
+    <testcase classname="cart" name="pays later"><skipped/></testcase>
+  </testsuite>
+</testsuites>
+`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Summary
+	}{
+		{"go test -json", goTestJSON, Summary{Passed: 1, Failed: 2, Skipped: 1, Failing: []string{"TestSub (example.com/app)", "example.com/broken (package failed)"}, Passing: []string{"TestAdd (example.com/app)"}}},
+		{"trx", trx, Summary{Passed: 1, Failed: 1, Skipped: 1, Failing: []string{"Api.Tests.RejectsEmptyCart"}, Passing: []string{"Api.Tests.CreatesOrder"}}},
+		{"junit", junit, Summary{Passed: 1, Failed: 2, Skipped: 1, Failing: []string{"cart removes items", "cart checks out"}, Passing: []string{"cart adds items"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Parse([]byte(tt.data))
+			require.True(t, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParse_Unrecognized(t *testing.T) {
+	for _, data := range []string{"", "ok  \texample.com/app\t0.01s\n", "<html><body/></html>", "<testsuite"} {
+		_, ok := Parse([]byte(data))
+		assert.False(t, ok, data)
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.trx")
+	require.NoError(t, os.WriteFile(path, []byte(trx), 0644))
+	got, err := ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Failed)
+
+	require.NoError(t, os.WriteFile(path, []byte("not a report"), 0644))
+	_, err = ReadFile(path)
+	assert.ErrorContains(t, err, "unrecognized test report")
+}
+
+func TestSummary_AddAndString(t *testing.T) {
+	s := Summary{Passed: 3, Failed: 1, Failing: []string{"a"}, Passing: []string{"c"}}
+	s.Add(Summary{Passed: 2, Skipped: 1, Failed: 1, Failing: []string{"b"}, Passing: []string{"d"}})
+	assert.Equal(t, Summary{Passed: 5, Failed: 2, Skipped: 1, Failing: []string{"a", "b"}, Passing: []string{"c", "d"}}, s)
+	assert.Equal(t, "5 passed, 2 failed, 1 skipped", s.String())
+}
+
+func TestCompare(t *testing.T) {
+	newlyFailing, fixed := Compare(
+		Summary{Failing: []string{"a", "b"}},
+		Summary{Failing: []string{"b", "c"}},
+	)
+	assert.Equal(t, []string{"c"}, newlyFailing)
+	assert.Equal(t, []string{"a"}, fixed)
+
+	newlyFailing, fixed = Compare(Summary{}, Summary{})
+	assert.Empty(t, newlyFailing)
+	assert.Empty(t, fixed)
+}
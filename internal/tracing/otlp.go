@@ -0,0 +1,261 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultServiceName is reported as service.name unless OTEL_SERVICE_NAME
+// is set.
+const DefaultServiceName = "ralph-loop"
+
+// OTLPExporter posts spans to an OTLP/HTTP endpoint using the JSON encoding.
+type OTLPExporter struct {
+	URL      string // full traces URL, e.g. http://localhost:4318/v1/traces
+	Headers  map[string]string
+	Resource []Attr
+	Client   *http.Client
+}
+
+// NewFromEnv builds a tracer from the standard OpenTelemetry environment
+// variables. It returns nil, nil when tracing is not configured, i.e. when
+// neither OTEL_EXPORTER_OTLP_TRACES_ENDPOINT nor OTEL_EXPORTER_OTLP_ENDPOINT
+// is set or OTEL_SDK_DISABLED is true.
+//
+// Recognised variables: the two endpoints (the generic one gets /v1/traces
+// appended), OTEL_EXPORTER_OTLP_[TRACES_]HEADERS, OTEL_SERVICE_NAME,
+// OTEL_RESOURCE_ATTRIBUTES and OTEL_EXPORTER_OTLP_[TRACES_]PROTOCOL. Only
+// HTTP is supported; the JSON body is accepted by collectors on the same
+// endpoint as http/protobuf.
+func NewFromEnv() (*Tracer, error) {
+	exp, err := exporterFromEnv(os.Getenv)
+	if exp == nil || err != nil {
+		return nil, err
+	}
+	return NewTracer(exp), nil
+}
+
+func exporterFromEnv(getenv func(string) string) (*OTLPExporter, error) {
+	if strings.EqualFold(getenv("OTEL_SDK_DISABLED"), "true") {
+		return nil, nil
+	}
+	endpoint := getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	if endpoint == "" {
+		base := getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+		if base == "" {
+			return nil, nil
+		}
+		endpoint = strings.TrimRight(base, "/") + "/v1/traces"
+	}
+	if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: must be an http(s) URL", endpoint)
+	}
+
+	protocol := getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	if protocol == "" {
+		protocol = getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+	if protocol == "grpc" {
+		return nil, fmt.Errorf("OTLP protocol grpc is not supported; use http/json or http/protobuf")
+	}
+
+	headers, err := parseKeyValues(getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	if err != nil {
+		return nil, fmt.Errorf("OTEL_EXPORTER_OTLP_HEADERS: %w", err)
+	}
+	traceHeaders, err := parseKeyValues(getenv("OTEL_EXPORTER_OTLP_TRACES_HEADERS"))
+	if err != nil {
+		return nil, fmt.Errorf("OTEL_EXPORTER_OTLP_TRACES_HEADERS: %w", err)
+	}
+	for k, v := range traceHeaders {
+		headers[k] = v
+	}
+
+	resourceKV, err := parseKeyValues(getenv("OTEL_RESOURCE_ATTRIBUTES"))
+	if err != nil {
+		return nil, fmt.Errorf("OTEL_RESOURCE_ATTRIBUTES: %w", err)
+	}
+	service := getenv("OTEL_SERVICE_NAME")
+	if service == "" {
+		service = resourceKV["service.name"]
+	}
+	if service == "" {
+		service = DefaultServiceName
+	}
+	delete(resourceKV, "service.name")
+	resource := []Attr{String("service.name", service)}
+	keys := make([]string, 0, len(resourceKV))
+	for k := range resourceKV {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		resource = append(resource, String(k, resourceKV[k]))
+	}
+
+	return &OTLPExporter{
+		URL:      endpoint,
+		Headers:  headers,
+		Resource: resource,
+		Client:   &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// parseKeyValues parses the "k1=v1,k2=v2" format of the OTEL_* variables.
+// Values are URL-decoded.
+func parseKeyValues(s string) (map[string]string, error) {
+	m := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, fmt.Errorf("invalid entry %q, want key=value", pair)
+		}
+		decoded, err := url.QueryUnescape(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %q: %w", k, err)
+		}
+		m[strings.TrimSpace(k)] = decoded
+	}
+	return m, nil
+}
+
+// Export implements Exporter.
+func (e *OTLPExporter) Export(ctx context.Context, spans []SpanData) error {
+	body, err := json.Marshal(encodeRequest(e.Resource, spans))
+	if err != nil {
+		return fmt.Errorf("encode spans: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("export spans: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.Headers {
+		req.Header.Set(k, v)
+	}
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("export spans: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("export spans: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// The types below follow the OTLP/JSON mapping of ExportTraceServiceRequest:
+// IDs are hex strings and 64-bit integers are decimal strings.
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"` // 0 unset, 2 error
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string         `json:"key"`
+	Value map[string]any `json:"value"`
+}
+
+const (
+	spanKindInternal = 1
+	statusCodeError  = 2
+)
+
+func encodeRequest(resource []Attr, spans []SpanData) otlpRequest {
+	out := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		span := otlpSpan{
+			TraceID:           s.TraceID,
+			SpanID:            s.SpanID,
+			ParentSpanID:      s.ParentID,
+			Name:              s.Name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+			Attributes:        encodeAttrs(s.Attrs),
+		}
+		if s.Failed {
+			span.Status = otlpStatus{Code: statusCodeError, Message: s.Error}
+		}
+		out = append(out, span)
+	}
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: encodeAttrs(resource)},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: DefaultServiceName}, Spans: out}},
+	}}}
+}
+
+func encodeAttrs(attrs []Attr) []otlpKeyValue {
+	out := make([]otlpKeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		var v map[string]any
+		switch val := a.Value.(type) {
+		case string:
+			v = map[string]any{"stringValue": val}
+		case int64:
+			v = map[string]any{"intValue": strconv.FormatInt(val, 10)}
+		case float64:
+			v = map[string]any{"doubleValue": val}
+		case bool:
+			v = map[string]any{"boolValue": val}
+		default:
+			v = map[string]any{"stringValue": fmt.Sprint(val)}
+		}
+		out = append(out, otlpKeyValue{Key: a.Key, Value: v})
+	}
+	return out
+}
@@ -0,0 +1,159 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func envOf(m map[string]string) func(string) string {
+	return func(k string) string { return m[k] }
+}
+
+func TestExporterFromEnv_Disabled(t *testing.T) {
+	exp, err := exporterFromEnv(envOf(nil))
+	assert.NoError(t, err)
+	assert.Nil(t, exp)
+
+	exp, err = exporterFromEnv(envOf(map[string]string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4318",
+		"OTEL_SDK_DISABLED":           "true",
+	}))
+	assert.NoError(t, err)
+	assert.Nil(t, exp)
+}
+
+func TestExporterFromEnv_Endpoints(t *testing.T) {
+	exp, err := exporterFromEnv(envOf(map[string]string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318/",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "http://collector:4318/v1/traces", exp.URL)
+	assert.Equal(t, []Attr{String("service.name", DefaultServiceName)}, exp.Resource)
+
+	exp, err = exporterFromEnv(envOf(map[string]string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT":        "http://collector:4318",
+		"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "https://traces.example.com/custom",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "https://traces.example.com/custom", exp.URL, "signal-specific endpoint is used as-is")
+}
+
+func TestExporterFromEnv_HeadersAndResource(t *testing.T) {
+	exp, err := exporterFromEnv(envOf(map[string]string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT":       "http://collector:4318",
+		"OTEL_EXPORTER_OTLP_HEADERS":        "x-api-key=abc, authorization=Bearer%20tok",
+		"OTEL_EXPORTER_OTLP_TRACES_HEADERS": "x-api-key=override",
+		"OTEL_RESOURCE_ATTRIBUTES":          "service.name=from-attrs,deployment.environment=ci,host.name=runner-1",
+		"OTEL_SERVICE_NAME":                 "my-loop",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"x-api-key": "override", "authorization": "Bearer tok"}, exp.Headers)
+	assert.Equal(t, []Attr{
+		String("service.name", "my-loop"),
+		String("deployment.environment", "ci"),
+		String("host.name", "runner-1"),
+	}, exp.Resource)
+}
+
+func TestExporterFromEnv_Errors(t *testing.T) {
+	tests := map[string]map[string]string{
+		"grpc protocol": {
+			"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4317",
+			"OTEL_EXPORTER_OTLP_PROTOCOL": "grpc",
+		},
+		"bad scheme": {
+			"OTEL_EXPORTER_OTLP_ENDPOINT": "collector:4318",
+		},
+		"bad headers": {
+			"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318",
+			"OTEL_EXPORTER_OTLP_HEADERS":  "novalue",
+		},
+	}
+	for name, env := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := exporterFromEnv(envOf(env))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestOTLPExporter_Export(t *testing.T) {
+	var body map[string]any
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+	}))
+	defer srv.Close()
+
+	exp := &OTLPExporter{
+		URL:      srv.URL + "/v1/traces",
+		Headers:  map[string]string{"x-api-key": "abc"},
+		Resource: []Attr{String("service.name", "ralph-loop")},
+	}
+	start := time.Unix(1700000000, 5)
+	err := exp.Export(context.Background(), []SpanData{{
+		Name:     "ai.run",
+		TraceID:  "0102030405060708090a0b0c0d0e0f10",
+		SpanID:   "0102030405060708",
+		ParentID: "1112131415161718",
+		Start:    start,
+		End:      start.Add(time.Second),
+		Attrs:    []Attr{String("gen_ai.system", "claude"), Int("gen_ai.usage.input_tokens", 42), Float("ralph.cost_usd", 0.5), Bool("ok", true)},
+		Failed:   true,
+		Error:    "boom",
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	assert.Equal(t, "abc", header.Get("x-api-key"))
+
+	rs := body["resourceSpans"].([]any)[0].(map[string]any)
+	resAttrs := rs["resource"].(map[string]any)["attributes"].([]any)
+	assert.Equal(t, "service.name", resAttrs[0].(map[string]any)["key"])
+	span := rs["scopeSpans"].([]any)[0].(map[string]any)["spans"].([]any)[0].(map[string]any)
+	assert.Equal(t, "ai.run", span["name"])
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", span["traceId"])
+	assert.Equal(t, "1112131415161718", span["parentSpanId"])
+	assert.Equal(t, "1700000000000000005", span["startTimeUnixNano"])
+	assert.Equal(t, "1700000001000000005", span["endTimeUnixNano"])
+	assert.Equal(t, map[string]any{"code": float64(2), "message": "boom"}, span["status"])
+	attrs := span["attributes"].([]any)
+	assert.Equal(t, map[string]any{"stringValue": "claude"}, attrs[0].(map[string]any)["value"])
+	assert.Equal(t, map[string]any{"intValue": "42"}, attrs[1].(map[string]any)["value"])
+	assert.Equal(t, map[string]any{"doubleValue": 0.5}, attrs[2].(map[string]any)["value"])
+	assert.Equal(t, map[string]any{"boolValue": true}, attrs[3].(map[string]any)["value"])
+}
+
+func TestOTLPExporter_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad payload", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	exp := &OTLPExporter{URL: srv.URL}
+	err := exp.Export(context.Background(), []SpanData{{Name: "x"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "400")
+	assert.Contains(t, err.Error(), "bad payload")
+}
+
+func TestOTLPExporter_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	err := (&OTLPExporter{URL: url}).Export(context.Background(), []SpanData{{Name: "x"}})
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, context.Canceled))
+}
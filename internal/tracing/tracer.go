@@ -0,0 +1,116 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// batchSize is the number of ended spans that triggers an export.
+	batchSize = 64
+	// flushInterval bounds how long an ended span waits to be exported.
+	flushInterval = 5 * time.Second
+)
+
+// Tracer creates spans and exports them in the background in batches.
+type Tracer struct {
+	exporter Exporter
+
+	mu      sync.Mutex
+	queue   []SpanData
+	stopped bool
+	onError func(error)
+
+	kick     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewTracer returns a tracer that sends spans to exporter.
+func NewTracer(exporter Exporter) *Tracer {
+	t := &Tracer{
+		exporter: exporter,
+		kick:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go t.loop()
+	return t
+}
+
+// SetErrorHandler sets a function called when a batch fails to export. The
+// spans of a failed batch are dropped.
+func (t *Tracer) SetErrorHandler(f func(error)) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onError = f
+}
+
+func (t *Tracer) enqueue(s SpanData) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return
+	}
+	t.queue = append(t.queue, s)
+	if len(t.queue) >= batchSize {
+		select {
+		case t.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (t *Tracer) loop() {
+	defer close(t.done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-ticker.C:
+		case <-t.kick:
+		}
+		t.flush(context.Background())
+	}
+}
+
+// flush exports every queued span.
+func (t *Tracer) flush(ctx context.Context) {
+	t.mu.Lock()
+	batch := t.queue
+	t.queue = nil
+	onError := t.onError
+	t.mu.Unlock()
+	if len(batch) == 0 {
+		return
+	}
+	if err := t.exporter.Export(ctx, batch); err != nil && onError != nil {
+		onError(err)
+	}
+}
+
+// Shutdown stops the background exporter and exports the remaining spans,
+// giving up when ctx is done. Spans ended afterwards are dropped.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	t.stopOnce.Do(func() { close(t.stop) })
+	select {
+	case <-t.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	t.mu.Lock()
+	t.stopped = true
+	t.mu.Unlock()
+	t.flush(ctx)
+	return ctx.Err()
+}
@@ -0,0 +1,174 @@
+// Package tracing records OpenTelemetry spans for a ralph-loop run: the
+// session is the trace root, each loop iteration a child span, and phases and
+// AI CLI invocations are nested below it.
+//
+// It implements only the small part of the OpenTelemetry API ralph-loop needs
+// (spans with attributes and an error status, parent/child links through
+// context.Context, and a batching exporter) rather than depending on the
+// SDK. Spans are exported over OTLP/HTTP with the JSON encoding, configured
+// from the standard OTEL_* environment variables; see NewFromEnv.
+//
+// All functions and methods are no-ops on a nil *Tracer or *Span, so callers
+// need not check whether tracing is enabled.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Attr is a span attribute. Value is a string, int64, float64 or bool.
+type Attr struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute.
+func String(key, value string) Attr { return Attr{Key: key, Value: value} }
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attr { return Attr{Key: key, Value: int64(value)} }
+
+// Float returns a floating-point attribute.
+func Float(key string, value float64) Attr { return Attr{Key: key, Value: value} }
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attr { return Attr{Key: key, Value: value} }
+
+// SpanData is the immutable record of an ended span handed to an Exporter.
+// IDs are lowercase hex; ParentID is empty for a root span.
+type SpanData struct {
+	Name     string
+	TraceID  string
+	SpanID   string
+	ParentID string
+	Start    time.Time
+	End      time.Time
+	Attrs    []Attr
+	Error    string // status message; empty when the span succeeded
+	Failed   bool
+}
+
+// Exporter sends ended spans to a tracing backend.
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+}
+
+// Span is an in-progress operation.
+type Span struct {
+	tracer   *Tracer
+	name     string
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	start    time.Time
+
+	mu     sync.Mutex
+	attrs  []Attr
+	errMsg string
+	failed bool
+	ended  bool
+}
+
+type spanKey struct{}
+
+// Start begins a span. If ctx carries a span from the same tracer the new
+// span is its child; otherwise it starts a new trace.
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+	s := &Span{tracer: t, name: name, start: time.Now(), attrs: attrs}
+	if parent := SpanFromContext(ctx); parent != nil && parent.tracer == t {
+		s.traceID = parent.traceID
+		s.parentID = parent.spanID
+	} else {
+		_, _ = rand.Read(s.traceID[:])
+	}
+	_, _ = rand.Read(s.spanID[:])
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// Start begins a child of the span carried by ctx. Without one it returns
+// ctx unchanged and a nil span, so code below the orchestrator (such as AI
+// runners) can be instrumented without access to the tracer.
+func Start(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	parent := SpanFromContext(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+	return parent.tracer.Start(ctx, name, attrs...)
+}
+
+// SpanFromContext returns the span carried by ctx, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanKey{}).(*Span)
+	return s
+}
+
+// SetAttributes adds attributes to the span, replacing earlier values for
+// the same keys.
+func (s *Span) SetAttributes(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, a := range attrs {
+		replaced := false
+		for i := range s.attrs {
+			if s.attrs[i].Key == a.Key {
+				s.attrs[i] = a
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			s.attrs = append(s.attrs, a)
+		}
+	}
+}
+
+// RecordError marks the span as failed with err's message. A nil err is
+// ignored.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failed = true
+	s.errMsg = err.Error()
+}
+
+// End finishes the span and queues it for export. Only the first call has
+// any effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	data := SpanData{
+		Name:    s.name,
+		TraceID: hex.EncodeToString(s.traceID[:]),
+		SpanID:  hex.EncodeToString(s.spanID[:]),
+		Start:   s.start,
+		End:     time.Now(),
+		Attrs:   append([]Attr(nil), s.attrs...),
+		Error:   s.errMsg,
+		Failed:  s.failed,
+	}
+	if s.parentID != ([8]byte{}) {
+		data.ParentID = hex.EncodeToString(s.parentID[:])
+	}
+	s.mu.Unlock()
+	s.tracer.enqueue(data)
+}
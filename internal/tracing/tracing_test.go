@@ -0,0 +1,146 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memExporter collects exported spans in memory.
+type memExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+	err   error
+}
+
+func (m *memExporter) Export(_ context.Context, spans []SpanData) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.spans = append(m.spans, spans...)
+	return m.err
+}
+
+func (m *memExporter) byName(name string) SpanData {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, s := range m.spans {
+		if s.Name == name {
+			return s
+		}
+	}
+	return SpanData{}
+}
+
+func TestSpanTree(t *testing.T) {
+	exp := &memExporter{}
+	tr := NewTracer(exp)
+
+	ctx, root := tr.Start(context.Background(), "session", String("ralph.session_id", "abc"))
+	iterCtx, iter := Start(ctx, "iteration", Int("ralph.iteration", 1))
+	_, leaf := Start(iterCtx, "ai.run")
+	leaf.End()
+	iter.End()
+	root.End()
+	require.NoError(t, tr.Shutdown(context.Background()))
+
+	require.Len(t, exp.spans, 3)
+	r, i, l := exp.byName("session"), exp.byName("iteration"), exp.byName("ai.run")
+	assert.Empty(t, r.ParentID)
+	assert.Len(t, r.TraceID, 32)
+	assert.Len(t, r.SpanID, 16)
+	assert.Equal(t, r.TraceID, i.TraceID)
+	assert.Equal(t, r.TraceID, l.TraceID)
+	assert.Equal(t, r.SpanID, i.ParentID)
+	assert.Equal(t, i.SpanID, l.ParentID)
+	assert.Equal(t, []Attr{{Key: "ralph.session_id", Value: "abc"}}, r.Attrs)
+	assert.Equal(t, []Attr{{Key: "ralph.iteration", Value: int64(1)}}, i.Attrs)
+	assert.False(t, r.End.Before(r.Start))
+}
+
+func TestSeparateRootsGetSeparateTraces(t *testing.T) {
+	exp := &memExporter{}
+	tr := NewTracer(exp)
+	_, a := tr.Start(context.Background(), "a")
+	_, b := tr.Start(context.Background(), "b")
+	a.End()
+	b.End()
+	require.NoError(t, tr.Shutdown(context.Background()))
+	assert.NotEqual(t, exp.byName("a").TraceID, exp.byName("b").TraceID)
+}
+
+func TestSpan_AttributesAndError(t *testing.T) {
+	exp := &memExporter{}
+	tr := NewTracer(exp)
+	_, s := tr.Start(context.Background(), "op", String("k", "v1"))
+	s.SetAttributes(String("k", "v2"), Bool("ok", true), Float("cost", 0.5))
+	s.RecordError(nil)
+	s.RecordError(errors.New("boom"))
+	s.End()
+	s.End() // second End is ignored
+	require.NoError(t, tr.Shutdown(context.Background()))
+
+	require.Len(t, exp.spans, 1)
+	got := exp.spans[0]
+	assert.Equal(t, []Attr{String("k", "v2"), Bool("ok", true), Float("cost", 0.5)}, got.Attrs)
+	assert.True(t, got.Failed)
+	assert.Equal(t, "boom", got.Error)
+}
+
+func TestStartWithoutParentIsNoop(t *testing.T) {
+	ctx := context.Background()
+	got, s := Start(ctx, "orphan")
+	assert.Nil(t, s)
+	assert.Equal(t, ctx, got)
+}
+
+func TestNilTracerAndSpan(t *testing.T) {
+	var tr *Tracer
+	ctx, s := tr.Start(context.Background(), "x")
+	assert.Nil(t, s)
+	assert.Nil(t, SpanFromContext(ctx))
+	assert.NotPanics(t, func() {
+		s.SetAttributes(String("a", "b"))
+		s.RecordError(errors.New("x"))
+		s.End()
+		tr.SetErrorHandler(func(error) {})
+		assert.NoError(t, tr.Shutdown(context.Background()))
+	})
+}
+
+func TestShutdown_DropsLateSpansAndReportsErrors(t *testing.T) {
+	exp := &memExporter{err: errors.New("collector down")}
+	tr := NewTracer(exp)
+	var reported []error
+	tr.SetErrorHandler(func(err error) { reported = append(reported, err) })
+
+	_, s := tr.Start(context.Background(), "op")
+	s.End()
+	require.NoError(t, tr.Shutdown(context.Background()))
+	require.Len(t, reported, 1)
+	assert.EqualError(t, reported[0], "collector down")
+
+	_, late := tr.Start(context.Background(), "late")
+	late.End()
+	assert.Len(t, exp.spans, 1, "spans ended after shutdown are dropped")
+}
+
+func TestBatchSizeTriggersExport(t *testing.T) {
+	exp := &memExporter{}
+	tr := NewTracer(exp)
+	defer tr.Shutdown(context.Background())
+
+	for i := 0; i < batchSize; i++ {
+		_, s := tr.Start(context.Background(), "op")
+		s.End()
+	}
+	assert.Eventually(t, func() bool {
+		exp.mu.Lock()
+		defer exp.mu.Unlock()
+		return len(exp.spans) == batchSize
+	}, flushInterval/2, 10*time.Millisecond)
+}
@@ -0,0 +1,227 @@
+// Package tui renders a live terminal dashboard for --tui mode: the current
+// phase, iteration and task progress bars, a pane streaming the running AI
+// CLI's output, the last verdict and feedback, blocked tasks, and the tail of
+// the regular log.
+//
+// While the dashboard runs it owns the terminal's alternate screen and
+// captures everything written to os.Stderr (log lines and banners) into its
+// log pane. All Dashboard methods are no-ops on a nil *Dashboard, so the
+// orchestrator can report to it unconditionally.
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	refreshInterval = 250 * time.Millisecond
+	maxLogLines     = 200
+	maxOutputLines  = 200
+	// replayLines is how much of the log is printed to the normal screen on
+	// Stop, enough to show the final banner.
+	replayLines = 15
+)
+
+// ansiPattern matches SGR color sequences in captured log output.
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// Dashboard holds the state shown on screen and redraws it periodically.
+type Dashboard struct {
+	mu    sync.Mutex
+	state snapshot
+	tail  *outputTail
+
+	term      *os.File // the real terminal
+	oldStderr *os.File
+	pipeR     *os.File
+	pipeW     *os.File
+	stop      chan struct{}
+	wg        sync.WaitGroup
+	stopOnce  sync.Once
+	now       func() time.Time
+}
+
+// snapshot is the dashboard state rendered on each refresh.
+type snapshot struct {
+	SessionID    string
+	AI           string
+	Model        string
+	TasksFile    string
+	Started      time.Time
+	Now          time.Time
+	Phase        string
+	Iteration    int
+	MaxIteration int
+	TasksDone    int
+	TasksTotal   int
+	Verdict      string
+	Feedback     string
+	Blocked      []string
+	Output       []string
+	Log          []string
+}
+
+// Start switches term to the alternate screen, redirects os.Stderr into the
+// log pane and starts redrawing. term should be the original os.Stderr and
+// must be a terminal. Call Stop before the process exits.
+func Start(term *os.File) (*Dashboard, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("capture stderr: %w", err)
+	}
+	d := &Dashboard{
+		state:     snapshot{Started: time.Now()},
+		term:      term,
+		oldStderr: os.Stderr,
+		pipeR:     r,
+		pipeW:     w,
+		stop:      make(chan struct{}),
+		now:       time.Now,
+	}
+	os.Stderr = w
+
+	fmt.Fprint(term, "\x1b[?1049h\x1b[?25l") // alternate screen, hide cursor
+	d.wg.Add(2)
+	go d.captureLog(r)
+	go d.loop()
+	return d, nil
+}
+
+// Stop restores os.Stderr and the normal screen, then prints the last log
+// lines (normally the final banner) so the outcome stays visible.
+func (d *Dashboard) Stop() {
+	if d == nil {
+		return
+	}
+	d.stopOnce.Do(func() {
+		os.Stderr = d.oldStderr
+		d.pipeW.Close()
+		close(d.stop)
+		d.wg.Wait()
+		d.pipeR.Close()
+		d.redraw() // final frame, e.g. for terminals recording the session
+
+		fmt.Fprint(d.term, "\x1b[?25h\x1b[?1049l") // show cursor, main screen
+		d.mu.Lock()
+		lines := d.state.Log
+		d.mu.Unlock()
+		if len(lines) > replayLines {
+			lines = lines[len(lines)-replayLines:]
+		}
+		for _, l := range lines {
+			fmt.Fprintln(d.term, l)
+		}
+	})
+}
+
+func (d *Dashboard) captureLog(r io.Reader) {
+	defer d.wg.Done()
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := ansiPattern.ReplaceAllString(sc.Text(), "")
+		d.mu.Lock()
+		d.state.Log = appendCapped(d.state.Log, maxLogLines, line)
+		d.mu.Unlock()
+	}
+}
+
+func (d *Dashboard) loop() {
+	defer d.wg.Done()
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		d.redraw()
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dashboard) redraw() {
+	width, height := terminalSize(d.term)
+	d.mu.Lock()
+	if d.tail != nil {
+		d.state.Output = appendCapped(d.state.Output, maxOutputLines, d.tail.poll()...)
+	}
+	st := d.state
+	st.Now = d.now()
+	d.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for _, line := range render(st, width, height) {
+		b.WriteString(line)
+		b.WriteString("\x1b[K\r\n")
+	}
+	b.WriteString("\x1b[J")
+	_, _ = io.WriteString(d.term, b.String())
+}
+
+func (d *Dashboard) update(f func(s *snapshot)) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	f(&d.state)
+}
+
+// SetSession records the session shown in the header.
+func (d *Dashboard) SetSession(id, ai, model, tasksFile string) {
+	d.update(func(s *snapshot) {
+		s.SessionID, s.AI, s.Model, s.TasksFile = id, ai, model, tasksFile
+	})
+}
+
+// SetPhase records the running phase.
+func (d *Dashboard) SetPhase(phase string) {
+	d.update(func(s *snapshot) { s.Phase = phase })
+}
+
+// SetIteration records the current iteration.
+func (d *Dashboard) SetIteration(n, max int) {
+	d.update(func(s *snapshot) { s.Iteration, s.MaxIteration = n, max })
+}
+
+// SetTasks records how many tasks are done out of the total.
+func (d *Dashboard) SetTasks(done, total int) {
+	d.update(func(s *snapshot) { s.TasksDone, s.TasksTotal = done, total })
+}
+
+// SetVerdict records the latest verdict and its feedback.
+func (d *Dashboard) SetVerdict(verdict, feedback string) {
+	d.update(func(s *snapshot) { s.Verdict, s.Feedback = verdict, feedback })
+}
+
+// SetBlocked records the currently blocked tasks.
+func (d *Dashboard) SetBlocked(tasks []string) {
+	d.update(func(s *snapshot) { s.Blocked = append([]string(nil), tasks...) })
+}
+
+// WatchOutput clears the output pane and starts streaming the AI CLI output
+// for outputPath, the path passed to the runner.
+func (d *Dashboard) WatchOutput(outputPath string) {
+	d.update(func(s *snapshot) {
+		s.Output = nil
+		d.tail = newOutputTail(outputPath)
+	})
+}
+
+// appendCapped appends lines to buf, keeping at most limit lines.
+func appendCapped(buf []string, limit int, lines ...string) []string {
+	buf = append(buf, lines...)
+	if len(buf) > limit {
+		buf = append([]string(nil), buf[len(buf)-limit:]...)
+	}
+	return buf
+}
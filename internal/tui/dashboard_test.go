@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNilDashboard(t *testing.T) {
+	var d *Dashboard
+	assert.NotPanics(t, func() {
+		d.SetSession("id", "claude", "opus", "tasks.md")
+		d.SetPhase("implementation")
+		d.SetIteration(1, 10)
+		d.SetTasks(1, 2)
+		d.SetVerdict("COMPLETE", "")
+		d.SetBlocked([]string{"x"})
+		d.WatchOutput("/nonexistent")
+		d.Stop()
+	})
+}
+
+func TestDashboard_CapturesStderrAndRestoresIt(t *testing.T) {
+	termPath := filepath.Join(t.TempDir(), "term")
+	term, err := os.Create(termPath)
+	require.NoError(t, err)
+	defer term.Close()
+	origStderr := os.Stderr
+
+	d, err := Start(term)
+	require.NoError(t, err)
+	d.SetPhase("implementation")
+	d.SetVerdict("COMPLETE", "")
+	fmt.Fprintln(os.Stderr, "\x1b[32m[SUCCESS]\x1b[0m All tasks completed")
+	d.Stop()
+	d.Stop() // idempotent
+
+	assert.Equal(t, origStderr, os.Stderr, "stderr is restored")
+	assert.Equal(t, []string{"[SUCCESS] All tasks completed"}, d.state.Log, "captured lines are stripped of color")
+
+	data, err := os.ReadFile(termPath)
+	require.NoError(t, err)
+	screen := string(data)
+	assert.True(t, strings.HasPrefix(screen, "\x1b[?1049h"), "switches to the alternate screen")
+	assert.Contains(t, screen, "Phase:     implementation")
+	assert.True(t, strings.HasSuffix(screen, "\x1b[?1049l[SUCCESS] All tasks completed\n"),
+		"leaves the alternate screen and replays the log tail")
+}
+
+func TestAppendCapped(t *testing.T) {
+	assert.Equal(t, []string{"b", "c"}, appendCapped([]string{"a"}, 2, "b", "c"))
+	assert.Equal(t, []string{"a", "b"}, appendCapped(nil, 5, "a", "b"))
+}
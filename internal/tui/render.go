@@ -0,0 +1,184 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+)
+
+const (
+	styleReset   = "\x1b[0m"
+	styleHeader  = "\x1b[1;7m"
+	styleSection = "\x1b[36m"
+	styleGreen   = "\x1b[32m"
+	styleYellow  = "\x1b[33m"
+	styleRed     = "\x1b[31m"
+
+	feedbackLines = 3
+	blockedLines  = 3
+	logLines      = 5
+	minOutput     = 3
+)
+
+// render lays out st for a width x height terminal. Each returned line is at
+// most width visible characters; styles are applied after truncation.
+func render(st snapshot, width, height int) []string {
+	if width < 20 {
+		width = 20
+	}
+	var head, foot []string
+
+	elapsed := 0
+	if !st.Started.IsZero() && !st.Now.IsZero() {
+		elapsed = int(st.Now.Sub(st.Started) / time.Second)
+	}
+	header := fmt.Sprintf(" ralph-loop │ session %s │ %s/%s │ elapsed %s",
+		orDash(st.SessionID), orDash(st.AI), orDash(st.Model), logging.FormatDuration(elapsed))
+	head = append(head, styleHeader+pad(header, width)+styleReset)
+	head = append(head, fit(" Phase:     "+orDash(st.Phase), width))
+	barWidth := width - 32
+	if barWidth > 40 {
+		barWidth = 40
+	}
+	head = append(head, fit(fmt.Sprintf(" Iteration: %s %d/%d", bar(st.Iteration, st.MaxIteration, barWidth), st.Iteration, st.MaxIteration), width))
+	head = append(head, fit(fmt.Sprintf(" Tasks:     %s %d/%d (%d%%)", bar(st.TasksDone, st.TasksTotal, barWidth), st.TasksDone, st.TasksTotal, percent(st.TasksDone, st.TasksTotal)), width))
+
+	if st.Verdict != "" {
+		foot = append(foot, section("Verdict: "+styled(verdictStyle(st.Verdict), st.Verdict), len("Verdict: "+st.Verdict), width))
+		foot = append(foot, lastN(wrap(st.Feedback, width-2), feedbackLines, true, width)...)
+	}
+	if len(st.Blocked) > 0 {
+		title := fmt.Sprintf("Blocked (%d)", len(st.Blocked))
+		foot = append(foot, section(title, len(title), width))
+		var items []string
+		for _, b := range st.Blocked {
+			items = append(items, "- "+b)
+		}
+		foot = append(foot, lastN(items, blockedLines, true, width)...)
+	}
+	foot = append(foot, section("Log", 3, width))
+	foot = append(foot, lastN(st.Log, logLines, false, width)...)
+
+	outputHeight := height - len(head) - len(foot) - 1
+	if outputHeight < minOutput {
+		outputHeight = minOutput
+	}
+	lines := append(head, section("Output", 6, width))
+	lines = append(lines, lastN(st.Output, outputHeight, false, width)...)
+	for i := len(st.Output); i < outputHeight; i++ {
+		lines = append(lines, "")
+	}
+	return append(lines, foot...)
+}
+
+// section renders a "─ title ───" divider; visible is the title's width
+// without escape sequences.
+func section(title string, visible, width int) string {
+	rest := width - visible - 3
+	if rest < 0 {
+		rest = 0
+	}
+	return styleSection + "─ " + styleReset + title + styleSection + " " + strings.Repeat("─", rest) + styleReset
+}
+
+// lastN returns the last n lines fitted to width. With head set it returns
+// the first n instead, marking truncation with an ellipsis line.
+func lastN(lines []string, n int, head bool, width int) []string {
+	if len(lines) > n {
+		if head {
+			lines = append(append([]string(nil), lines[:n-1]...), "…")
+		} else {
+			lines = lines[len(lines)-n:]
+		}
+	}
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = fit(" "+l, width)
+	}
+	return out
+}
+
+// bar renders a progress bar of the given width.
+func bar(n, total, width int) string {
+	if width < 5 {
+		width = 5
+	}
+	filled := 0
+	if total > 0 {
+		filled = n * width / total
+		if filled > width {
+			filled = width
+		}
+	}
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", width-filled) + "]"
+}
+
+func percent(n, total int) int {
+	if total <= 0 {
+		return 0
+	}
+	return n * 100 / total
+}
+
+// fit truncates s to width runes, replacing tabs with spaces.
+func fit(s string, width int) string {
+	r := []rune(strings.ReplaceAll(s, "\t", "    "))
+	if len(r) <= width {
+		return string(r)
+	}
+	return string(r[:width-1]) + "…"
+}
+
+// pad fits s to exactly width runes.
+func pad(s string, width int) string {
+	s = fit(s, width)
+	if n := len([]rune(s)); n < width {
+		s += strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// wrap splits text into lines of at most width runes, breaking at spaces.
+func wrap(text string, width int) []string {
+	var out []string
+	for _, para := range strings.Split(strings.TrimSpace(text), "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			switch {
+			case line == "":
+				line = word
+			case len([]rune(line))+1+len([]rune(word)) <= width:
+				line += " " + word
+			default:
+				out = append(out, line)
+				line = word
+			}
+		}
+		if line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+func verdictStyle(verdict string) string {
+	switch verdict {
+	case "COMPLETE", "CONFIRMED", "APPROVE", "VALID":
+		return styleGreen
+	case "NEEDS_MORE_WORK", "REJECTED":
+		return styleYellow
+	default:
+		return styleRed
+	}
+}
+
+func styled(style, s string) string { return style + s + styleReset }
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func stripStyles(lines []string) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = ansiPattern.ReplaceAllString(l, "")
+	}
+	return out
+}
+
+func TestRender_Layout(t *testing.T) {
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	st := snapshot{
+		SessionID:    "20260101-abc",
+		AI:           "claude",
+		Model:        "opus",
+		Started:      start,
+		Now:          start.Add(90 * time.Second),
+		Phase:        "validation",
+		Iteration:    2,
+		MaxIteration: 4,
+		TasksDone:    3,
+		TasksTotal:   4,
+		Verdict:      "NEEDS_MORE_WORK",
+		Feedback:     "Add tests for the parser",
+		Blocked:      []string{"T005 needs API key"},
+		Output:       []string{"line one", "line two"},
+		Log:          []string{"[INFO] started"},
+	}
+
+	lines := stripStyles(render(st, 80, 24))
+	assert.Len(t, lines, 24, "frame fills the terminal height")
+	for _, l := range lines {
+		assert.LessOrEqual(t, len([]rune(l)), 80)
+	}
+
+	text := strings.Join(lines, "\n")
+	assert.Contains(t, lines[0], "session 20260101-abc")
+	assert.Contains(t, lines[0], "claude/opus")
+	assert.Contains(t, lines[0], "elapsed 1m 30s")
+	assert.Contains(t, text, "Phase:     validation")
+	assert.Contains(t, text, "2/4")
+	assert.Contains(t, text, "3/4 (75%)")
+	assert.Contains(t, text, "─ Output ─")
+	assert.Contains(t, text, " line two")
+	assert.Contains(t, text, "─ Verdict: NEEDS_MORE_WORK ─")
+	assert.Contains(t, text, " Add tests for the parser")
+	assert.Contains(t, text, "─ Blocked (1) ─")
+	assert.Contains(t, text, " - T005 needs API key")
+	assert.Equal(t, " [INFO] started", lines[len(lines)-1])
+}
+
+func TestRender_OutputShowsTail(t *testing.T) {
+	var out []string
+	for i := 0; i < 100; i++ {
+		out = append(out, strings.Repeat("x", i%5)+"line")
+	}
+	out[99] = "last line"
+	lines := stripStyles(render(snapshot{Output: out}, 60, 20))
+	assert.Len(t, lines, 20)
+	assert.Contains(t, strings.Join(lines, "\n"), " last line")
+	assert.NotContains(t, strings.Join(lines, "\n"), "Verdict", "verdict section hidden until a verdict exists")
+}
+
+func TestRender_TruncatesLongLines(t *testing.T) {
+	lines := stripStyles(render(snapshot{Output: []string{strings.Repeat("a", 200)}}, 40, 15))
+	for _, l := range lines {
+		assert.LessOrEqual(t, len([]rune(l)), 40)
+	}
+	assert.Contains(t, strings.Join(lines, "\n"), "…")
+}
+
+func TestBar(t *testing.T) {
+	assert.Equal(t, "[░░░░░░░░░░]", bar(0, 0, 10))
+	assert.Equal(t, "[█████░░░░░]", bar(1, 2, 10))
+	assert.Equal(t, "[██████████]", bar(5, 2, 10), "overflow is clamped")
+}
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, []string{"one two", "three", "four"}, wrap("one two three\nfour", 8))
+	assert.Empty(t, wrap("  ", 8))
+}
+
+func TestLastN(t *testing.T) {
+	lines := []string{"a", "b", "c", "d"}
+	assert.Equal(t, []string{" c", " d"}, lastN(lines, 2, false, 10))
+	assert.Equal(t, []string{" a", " …"}, lastN(lines, 2, true, 10))
+}
@@ -0,0 +1,24 @@
+//go:build windows
+
+package tui
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// terminalSize returns the width and height of the console f, falling back
+// to 80x24.
+func terminalSize(f *os.File) (int, int) {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(f.Fd()), &info); err != nil {
+		return 80, 24
+	}
+	w := int(info.Window.Right-info.Window.Left) + 1
+	h := int(info.Window.Bottom-info.Window.Top) + 1
+	if w <= 0 || h <= 0 {
+		return 80, 24
+	}
+	return w, h
+}
@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+)
+
+// outputTail incrementally reads the raw output file an AI runner writes
+// next to its output path: <path>.stream.json for claude or <path>.jsonl
+// for codex. Only text content is returned; JSON event noise is dropped.
+type outputTail struct {
+	outputPath string
+	rawPath    string // chosen once the runner has created its file
+	parse      func(string) string
+	offset     int64
+	partial    string
+}
+
+func newOutputTail(outputPath string) *outputTail {
+	return &outputTail{outputPath: outputPath}
+}
+
+// poll returns the text lines written since the previous call.
+func (t *outputTail) poll() []string {
+	if t.rawPath == "" && !t.open() {
+		return nil
+	}
+	f, err := os.Open(t.rawPath)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	if _, err := f.Seek(t.offset, io.SeekStart); err != nil {
+		return nil
+	}
+	data, err := io.ReadAll(io.LimitReader(f, 1<<20))
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	t.offset += int64(len(data))
+
+	chunk := t.partial + string(data)
+	complete := strings.LastIndexByte(chunk, '\n')
+	if complete < 0 {
+		t.partial = chunk
+		return nil
+	}
+	t.partial = chunk[complete+1:]
+
+	var out []string
+	for _, line := range strings.Split(chunk[:complete], "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		text := line
+		if strings.HasPrefix(line, "{") {
+			text = t.parse(line)
+		}
+		for _, l := range strings.Split(text, "\n") {
+			if strings.TrimSpace(l) != "" {
+				out = append(out, l)
+			}
+		}
+	}
+	return out
+}
+
+// open picks whichever raw output file the runner has created.
+func (t *outputTail) open() bool {
+	candidates := []struct {
+		suffix string
+		parse  func(string) string
+	}{
+		{".stream.json", parser.ParseStreamJSON},
+		{".jsonl", parser.ParseCodexJSONL},
+	}
+	for _, c := range candidates {
+		if _, err := os.Stat(t.outputPath + c.suffix); err == nil {
+			t.rawPath = t.outputPath + c.suffix
+			t.parse = c.parse
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputTail_ClaudeStream(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "implementation-output.txt")
+	tail := newOutputTail(out)
+	assert.Nil(t, tail.poll(), "no raw file yet")
+
+	f, err := os.Create(out + ".stream.json")
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, _ = f.WriteString(`{"type":"system","subtype":"init"}` + "\n" +
+		`{"type":"assistant","message":{"content":[{"type":"text","text":"Reading tasks\nEditing main.go"}]}}` + "\n" +
+		`{"type":"assistant","message":{"content":[{"type":"te`)
+	assert.Equal(t, []string{"Reading tasks", "Editing main.go"}, tail.poll())
+
+	_, _ = f.WriteString(`xt","text":"Done"}]}}` + "\nplain stderr line\n")
+	assert.Equal(t, []string{"Done", "plain stderr line"}, tail.poll(), "partial lines are completed on the next poll")
+	assert.Nil(t, tail.poll())
+}
+
+func TestOutputTail_CodexJSONL(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "validation-output.txt")
+	line := `{"type":"item.completed","item":{"type":"agent_message","text":"All good"}}` + "\n"
+	require.NoError(t, os.WriteFile(out+".jsonl", []byte(line), 0644))
+
+	assert.Equal(t, []string{"All good"}, newOutputTail(out).poll())
+}
@@ -0,0 +1,122 @@
+// Package web serves a small status site for --serve: session status and
+// iteration history as JSON, the live log over Server-Sent Events, and
+// endpoints to cancel the loop or request escalation, so a loop running on a
+// build server can be watched and steered remotely.
+package web
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sync"
+)
+
+const (
+	// backlogLines is how much log history a new subscriber receives.
+	backlogLines = 500
+	// subscriberBuffer is how many lines a slow subscriber may fall behind
+	// before lines are dropped for it.
+	subscriberBuffer = 256
+)
+
+// Hub fans log lines out to live subscribers and keeps a short backlog.
+type Hub struct {
+	mu      sync.Mutex
+	backlog []string
+	subs    map[chan string]struct{}
+}
+
+// NewHub returns an empty hub.
+func NewHub() *Hub {
+	return &Hub{subs: make(map[chan string]struct{})}
+}
+
+// Publish sends line to every subscriber. Subscribers that are too far
+// behind miss the line rather than blocking the loop.
+func (h *Hub) Publish(line string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.backlog = append(h.backlog, line)
+	if len(h.backlog) > backlogLines {
+		h.backlog = append([]string(nil), h.backlog[len(h.backlog)-backlogLines:]...)
+	}
+	for ch := range h.subs {
+		select {
+		case ch <- line:
+		default:
+		}
+	}
+}
+
+// Subscribe returns the current backlog and a channel of subsequent lines.
+// Call cancel to unsubscribe; the channel is closed afterwards.
+func (h *Hub) Subscribe() (backlog []string, lines <-chan string, cancel func()) {
+	ch := make(chan string, subscriberBuffer)
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	backlog = append([]string(nil), h.backlog...)
+	h.mu.Unlock()
+
+	var once sync.Once
+	return backlog, ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs, ch)
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// ansiPattern matches SGR color sequences, which are stripped from
+// published lines.
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// CaptureStderr tees everything written to os.Stderr into h while still
+// passing it through to the original stderr. Call restore before exiting to
+// flush the remaining output and put os.Stderr back.
+func CaptureStderr(h *Hub) (restore func(), err error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("capture stderr: %w", err)
+	}
+	orig := os.Stderr
+	os.Stderr = w
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tee(r, orig, h)
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			os.Stderr = orig
+			w.Close()
+			<-done
+			r.Close()
+		})
+	}, nil
+}
+
+// tee copies r to out line by line, publishing each line to h.
+func tee(r io.Reader, out io.Writer, h *Hub) {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			_, _ = io.WriteString(out, line)
+			trimmed := line
+			if trimmed[len(trimmed)-1] == '\n' {
+				trimmed = trimmed[:len(trimmed)-1]
+			}
+			h.Publish(ansiPattern.ReplaceAllString(trimmed, ""))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub_BacklogAndLive(t *testing.T) {
+	h := NewHub()
+	h.Publish("one")
+	h.Publish("two")
+
+	backlog, lines, cancel := h.Subscribe()
+	defer cancel()
+	assert.Equal(t, []string{"one", "two"}, backlog)
+
+	h.Publish("three")
+	select {
+	case l := <-lines:
+		assert.Equal(t, "three", l)
+	case <-time.After(time.Second):
+		t.Fatal("live line not delivered")
+	}
+}
+
+func TestHub_BacklogIsCapped(t *testing.T) {
+	h := NewHub()
+	for i := 0; i < backlogLines+10; i++ {
+		h.Publish(fmt.Sprint(i))
+	}
+	backlog, _, cancel := h.Subscribe()
+	defer cancel()
+	assert.Len(t, backlog, backlogLines)
+	assert.Equal(t, "10", backlog[0])
+}
+
+func TestHub_SlowSubscriberDoesNotBlock(t *testing.T) {
+	h := NewHub()
+	_, lines, cancel := h.Subscribe()
+	for i := 0; i < subscriberBuffer*2; i++ {
+		h.Publish("x")
+	}
+	assert.Len(t, lines, subscriberBuffer)
+	cancel()
+	cancel() // idempotent
+	h.Publish("after cancel")
+}
+
+func TestTee(t *testing.T) {
+	h := NewHub()
+	var out bytes.Buffer
+	tee(strings.NewReader("\x1b[34m[INFO]\x1b[0m hello\npartial"), &out, h)
+
+	assert.Equal(t, "\x1b[34m[INFO]\x1b[0m hello\npartial", out.String(), "output passes through unchanged")
+	backlog, _, cancel := h.Subscribe()
+	defer cancel()
+	assert.Equal(t, []string{"[INFO] hello", "partial"}, backlog)
+}
+
+func TestCaptureStderr(t *testing.T) {
+	orig := os.Stderr
+	devnull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	require.NoError(t, err)
+	defer devnull.Close()
+	os.Stderr = devnull
+	defer func() { os.Stderr = orig }()
+
+	h := NewHub()
+	restore, err := CaptureStderr(h)
+	require.NoError(t, err)
+	fmt.Fprintln(os.Stderr, "captured line")
+	restore()
+	restore() // idempotent
+
+	assert.Equal(t, devnull, os.Stderr)
+	backlog, _, cancel := h.Subscribe()
+	defer cancel()
+	assert.Equal(t, []string{"captured line"}, backlog)
+}
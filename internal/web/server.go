@@ -0,0 +1,346 @@
+package web
+
+import (
+	"crypto/subtle"
+	"embed"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/iterlog"
+	"github.com/CodexForgeBR/cli-tools/internal/logging"
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/statecrypt"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+//go:embed static/index.html
+var static embed.FS
+
+// TokenEnv is the environment variable holding the token the status
+// server requires on every request.
+const TokenEnv = "RALPH_SERVE_TOKEN"
+
+// heartbeatInterval keeps idle SSE connections open through proxies.
+const heartbeatInterval = 15 * time.Second
+
+// Server exposes a running session over HTTP.
+type Server struct {
+	StateDir string
+	Hub      *Hub
+	// Cancel stops the loop as if it were interrupted.
+	Cancel func()
+	// Escalate asks the loop to stop with an escalation at the next
+	// iteration boundary.
+	Escalate func(reason string)
+	// Token, when set, is required on every request as a bearer token or
+	// the token query parameter. Without it the server only listens on
+	// the loopback interface.
+	Token string
+}
+
+// Status is the body of GET /api/status.
+type Status struct {
+	Session    *state.SessionState `json:"session"`
+	Feedback   string              `json:"feedback"`
+	TasksDone  int                 `json:"tasks_done"`
+	TasksTotal int                 `json:"tasks_total"`
+}
+
+// Iteration summarises one iteration directory for GET /api/iterations.
+type Iteration struct {
+	Number     int      `json:"number"`
+	Verdict    string   `json:"verdict,omitempty"`
+	Confidence *float64 `json:"confidence,omitempty"`
+	Feedback   string   `json:"feedback,omitempty"`
+	// Commands are the shell commands the implementer ran, when its AI CLI
+	// records them.
+	Commands  []parser.Command `json:"commands,omitempty"`
+	UpdatedAt string           `json:"updated_at"`
+}
+
+// outputFiles maps the kinds served by /api/iterations/{n}/{kind}.
+var outputFiles = map[string]string{
+	"implementation": "implementation-output.txt",
+	"validation":     "validation-output.txt",
+	"commands":       "commands.json",
+}
+
+// Handler returns the HTTP routes, all behind the token when one is set.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /api/status", s.handleStatus)
+	mux.HandleFunc("GET /api/iterations", s.handleIterations)
+	mux.HandleFunc("GET /api/iterations/{n}/{kind}", s.handleIterationOutput)
+	mux.HandleFunc("GET /api/events", s.handleEvents)
+	mux.HandleFunc("POST /api/cancel", s.handleCancel)
+	mux.HandleFunc("POST /api/escalate", s.handleEscalate)
+	if s.Token == "" {
+		return mux
+	}
+	return s.authenticate(mux)
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			token = r.URL.Query().Get("token")
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) != 1 {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Start listens on addr (e.g. ":8080") and serves in the background. The
+// returned server's Addr holds the bound address; Close it to stop.
+// Without a token, an address that is not on the loopback interface is
+// replaced by 127.0.0.1 on the same port.
+func (s *Server) Start(addr string) (*http.Server, error) {
+	if s.Token == "" {
+		if local, ok := loopback(addr); !ok {
+			logging.Warn(fmt.Sprintf("Status server listening on %s instead of %s: set %s to serve other hosts", local, addr, TokenEnv))
+			addr = local
+		}
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %w", addr, err)
+	}
+	srv := &http.Server{Addr: ln.Addr().String(), Handler: s.Handler(), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "status server stopped: %v\n", err)
+		}
+	}()
+	return srv, nil
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, _ *http.Request) {
+	data, _ := static.ReadFile("static/index.html")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(data)
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	st, err := ReadStatus(s.StateDir)
+	if err != nil {
+		http.Error(w, "no session state yet", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, st)
+}
+
+func (s *Server) handleIterations(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, ReadIterations(s.StateDir))
+}
+
+func (s *Server) handleIterationOutput(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.Atoi(r.PathValue("n"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	data, err := ReadIterationOutput(s.StateDir, n, r.PathValue("kind"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write(data)
+}
+
+// ReadStatus returns the session in stateDir with its feedback decoded and
+// its tasks counted.
+func ReadStatus(stateDir string) (Status, error) {
+	session, err := state.LoadState(stateDir)
+	if err != nil {
+		return Status{}, err
+	}
+	st := Status{Session: session, Feedback: session.LastFeedback}
+	if decoded, err := base64.StdEncoding.DecodeString(session.LastFeedback); err == nil {
+		st.Feedback = string(decoded)
+	}
+	if session.TasksFile != "" {
+		done, _ := tasks.CountChecked(session.TasksFile)
+		remaining, _ := tasks.CountUnchecked(session.TasksFile)
+		st.TasksDone, st.TasksTotal = done, done+remaining
+	}
+	return st, nil
+}
+
+// ReadIterations summarises the iteration directories in stateDir, oldest
+// first. It is empty, not nil, when there are none.
+func ReadIterations(stateDir string) []Iteration {
+	history := []Iteration{}
+	entries, err := os.ReadDir(stateDir)
+	if err != nil {
+		return history
+	}
+	for _, e := range entries {
+		n, ok := iterationNumber(e.Name())
+		if !e.IsDir() || !ok {
+			continue
+		}
+		it := Iteration{Number: n}
+		if info, err := e.Info(); err == nil {
+			it.UpdatedAt = info.ModTime().Format(time.RFC3339)
+		}
+		dir := filepath.Join(stateDir, e.Name())
+		if data, err := iterlog.ReadFile(filepath.Join(dir, outputFiles["validation"])); err == nil {
+			if v, err := parser.ParseValidation(string(data)); err == nil && v != nil {
+				it.Verdict, it.Confidence, it.Feedback = v.Verdict, v.Confidence, v.Feedback
+			}
+		}
+		if data, err := statecrypt.ReadFile(filepath.Join(dir, outputFiles["commands"])); err == nil {
+			_ = json.Unmarshal(data, &it.Commands)
+		}
+		history = append(history, it)
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].Number < history[j].Number })
+	return history
+}
+
+// ReadIterationOutput returns the "implementation" or "validation" output,
+// or the "commands" transcript, of iteration n.
+func ReadIterationOutput(stateDir string, n int, kind string) ([]byte, error) {
+	file, ok := outputFiles[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown output %q: use implementation, validation or commands", kind)
+	}
+	if n < 1 {
+		return nil, fmt.Errorf("invalid iteration %d", n)
+	}
+	return iterlog.ReadFile(filepath.Join(stateDir, fmt.Sprintf("iteration-%03d", n), file))
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	backlog, lines, cancel := s.Hub.Subscribe()
+	defer cancel()
+	for _, l := range backlog {
+		writeEvent(w, l)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case l, ok := <-lines:
+			if !ok {
+				return
+			}
+			writeEvent(w, l)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// writeEvent writes one SSE "log" event. Lines never contain newlines, but
+// carriage returns from progress output are dropped to keep framing valid.
+func writeEvent(w http.ResponseWriter, line string) {
+	fmt.Fprintf(w, "event: log\ndata: %s\n\n", strings.ReplaceAll(line, "\r", ""))
+}
+
+func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request) {
+	if !sameOrigin(r) {
+		http.Error(w, "cross-origin request rejected", http.StatusForbidden)
+		return
+	}
+	if s.Cancel == nil {
+		http.Error(w, "cancel not available", http.StatusNotImplemented)
+		return
+	}
+	s.Cancel()
+	writeJSON(w, map[string]string{"result": "cancelling"})
+}
+
+func (s *Server) handleEscalate(w http.ResponseWriter, r *http.Request) {
+	if !sameOrigin(r) {
+		http.Error(w, "cross-origin request rejected", http.StatusForbidden)
+		return
+	}
+	if s.Escalate == nil {
+		http.Error(w, "escalation not available", http.StatusNotImplemented)
+		return
+	}
+	reason := strings.TrimSpace(r.FormValue("reason"))
+	if reason == "" {
+		reason = "Escalation requested from the status server"
+	}
+	s.Escalate(reason)
+	writeJSON(w, map[string]string{"result": "escalation requested"})
+}
+
+// sameOrigin rejects browser requests posted from another site. Requests
+// without an Origin header (curl, scripts) are allowed.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// loopback reports whether addr is on the loopback interface and returns
+// it on 127.0.0.1 when it is not.
+func loopback(addr string) (string, bool) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr, true // left for net.Listen to report
+	}
+	if host == "localhost" {
+		return addr, true
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return addr, true
+	}
+	return net.JoinHostPort("127.0.0.1", port), false
+}
+
+// iterationNumber parses "iteration-NNN".
+func iterationNumber(name string) (int, bool) {
+	rest, ok := strings.CutPrefix(name, "iteration-")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(rest)
+	return n, err == nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
@@ -0,0 +1,301 @@
+package web
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/iterlog"
+	"github.com/CodexForgeBR/cli-tools/internal/parser"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+func newTestServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	dir := t.TempDir()
+	tasksFile := filepath.Join(dir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksFile, []byte("- [x] T001 one\n- [ ] T002 two\n- [ ] T003 three\n"), 0644))
+	require.NoError(t, state.SaveState(&state.SessionState{
+		SessionID:     "s-1",
+		Iteration:     2,
+		MaxIterations: 10,
+		Status:        state.StatusInProgress,
+		Phase:         state.PhaseImplementation,
+		Verdict:       "NEEDS_MORE_WORK",
+		TasksFile:     tasksFile,
+		LastFeedback:  base64.StdEncoding.EncodeToString([]byte("fix the tests")),
+	}, dir))
+
+	for n, verdict := range map[int]string{1: "NEEDS_MORE_WORK", 2: ""} {
+		iterDir := filepath.Join(dir, fmt.Sprintf("iteration-%03d", n))
+		require.NoError(t, os.MkdirAll(iterDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(iterDir, "implementation-output.txt"), []byte("impl output"), 0644))
+		if verdict != "" {
+			out := `{"RALPH_VALIDATION":{"verdict":"` + verdict + `","confidence":0.7,"feedback":"fix the tests"}}`
+			require.NoError(t, os.WriteFile(filepath.Join(iterDir, "validation-output.txt"), []byte(out), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(iterDir, "commands.json"), []byte(`[{"command":"go test ./...","exit_code":1}]`), 0644))
+		}
+	}
+	return &Server{StateDir: dir, Hub: NewHub()}, dir
+}
+
+func get(t *testing.T, h http.Handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestServer_Index(t *testing.T) {
+	s, _ := newTestServer(t)
+	rec := get(t, s.Handler(), "/")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, rec.Body.String(), `new EventSource(api("api/events"))`)
+
+	assert.Equal(t, http.StatusNotFound, get(t, s.Handler(), "/nope").Code)
+}
+
+func TestServer_Status(t *testing.T) {
+	s, _ := newTestServer(t)
+	rec := get(t, s.Handler(), "/api/status")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var st Status
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &st))
+	assert.Equal(t, "s-1", st.Session.SessionID)
+	assert.Equal(t, 2, st.Session.Iteration)
+	assert.Equal(t, "fix the tests", st.Feedback, "feedback is decoded")
+	assert.Equal(t, 1, st.TasksDone)
+	assert.Equal(t, 3, st.TasksTotal)
+}
+
+func TestServer_StatusWithoutSession(t *testing.T) {
+	s := &Server{StateDir: t.TempDir(), Hub: NewHub()}
+	assert.Equal(t, http.StatusNotFound, get(t, s.Handler(), "/api/status").Code)
+}
+
+func TestServer_Iterations(t *testing.T) {
+	s, _ := newTestServer(t)
+	rec := get(t, s.Handler(), "/api/iterations")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var history []Iteration
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &history))
+	require.Len(t, history, 2)
+	assert.Equal(t, 1, history[0].Number)
+	assert.Equal(t, "NEEDS_MORE_WORK", history[0].Verdict)
+	assert.Equal(t, "fix the tests", history[0].Feedback)
+	require.NotNil(t, history[0].Confidence)
+	assert.Equal(t, 0.7, *history[0].Confidence)
+	assert.Equal(t, []parser.Command{{Command: "go test ./...", ExitCode: 1}}, history[0].Commands)
+	assert.Equal(t, 2, history[1].Number)
+	assert.Empty(t, history[1].Commands)
+	assert.Empty(t, history[1].Verdict, "iteration still running")
+	assert.NotEmpty(t, history[1].UpdatedAt)
+}
+
+func TestServer_IterationOutput(t *testing.T) {
+	s, _ := newTestServer(t)
+	rec := get(t, s.Handler(), "/api/iterations/1/implementation")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "impl output", rec.Body.String())
+	assert.Contains(t, get(t, s.Handler(), "/api/iterations/1/commands").Body.String(), "go test ./...")
+
+	assert.Equal(t, http.StatusNotFound, get(t, s.Handler(), "/api/iterations/2/validation").Code)
+	assert.Equal(t, http.StatusNotFound, get(t, s.Handler(), "/api/iterations/1/secrets").Code)
+	assert.Equal(t, http.StatusNotFound, get(t, s.Handler(), "/api/iterations/x/implementation").Code)
+}
+
+func TestServer_CompressedIterationOutput(t *testing.T) {
+	s, dir := newTestServer(t)
+	require.NoError(t, iterlog.CompressOutputs(filepath.Join(dir, "iteration-001"), 1))
+
+	rec := get(t, s.Handler(), "/api/iterations/1/implementation")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "impl output", rec.Body.String())
+	assert.Equal(t, "NEEDS_MORE_WORK", ReadIterations(dir)[0].Verdict)
+}
+
+func TestServer_Events(t *testing.T) {
+	s, _ := newTestServer(t)
+	s.Hub.Publish("[INFO] before connect")
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() string {
+		var data string
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			line = strings.TrimRight(line, "\n")
+			if line == "" {
+				return data
+			}
+			if d, ok := strings.CutPrefix(line, "data: "); ok {
+				data = d
+			}
+		}
+	}
+	assert.Equal(t, "[INFO] before connect", readEvent(), "backlog is replayed")
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		s.Hub.Publish("[INFO] live")
+	}()
+	assert.Equal(t, "[INFO] live", readEvent())
+}
+
+func TestServer_Cancel(t *testing.T) {
+	s, _ := newTestServer(t)
+	cancelled := false
+	s.Cancel = func() { cancelled = true }
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/cancel", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, cancelled)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, get(t, s.Handler(), "/api/cancel").Code, "GET cannot cancel")
+}
+
+func TestServer_Escalate(t *testing.T) {
+	s, _ := newTestServer(t)
+	var reason string
+	s.Escalate = func(r string) { reason = r }
+
+	req := httptest.NewRequest(http.MethodPost, "/api/escalate", strings.NewReader(url.Values{"reason": {"needs a human"}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "needs a human", reason)
+
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/escalate", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Escalation requested from the status server", reason)
+}
+
+func TestServer_RejectsCrossOriginActions(t *testing.T) {
+	s, _ := newTestServer(t)
+	called := false
+	s.Cancel = func() { called = true }
+	s.Escalate = func(string) { called = true }
+
+	for _, path := range []string{"/api/cancel", "/api/escalate"} {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code, path)
+	}
+	assert.False(t, called)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/cancel", nil)
+	req.Header.Set("Origin", "http://"+req.Host)
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code, "same-origin requests are allowed")
+}
+
+func TestServer_ActionsUnavailable(t *testing.T) {
+	s, _ := newTestServer(t)
+	for _, path := range []string{"/api/cancel", "/api/escalate"} {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Equal(t, http.StatusNotImplemented, rec.Code, path)
+	}
+}
+
+func TestServer_Token(t *testing.T) {
+	s, _ := newTestServer(t)
+	s.Token = "secret"
+	called := false
+	s.Cancel = func() { called = true }
+
+	for _, path := range []string{"/", "/api/status", "/api/iterations/1/implementation", "/api/events"} {
+		assert.Equal(t, http.StatusUnauthorized, get(t, s.Handler(), path).Code, path)
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/cancel", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.False(t, called)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/cancel", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/cancel", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, called)
+
+	assert.Equal(t, http.StatusOK, get(t, s.Handler(), "/api/status?token=secret").Code)
+}
+
+func TestLoopback(t *testing.T) {
+	for addr, want := range map[string]string{
+		":8080":          "127.0.0.1:8080",
+		"0.0.0.0:8080":   "127.0.0.1:8080",
+		"10.0.0.5:80":    "127.0.0.1:80",
+		"example.com:80": "127.0.0.1:80",
+	} {
+		got, ok := loopback(addr)
+		assert.False(t, ok, addr)
+		assert.Equal(t, want, got, addr)
+	}
+	for _, addr := range []string{"127.0.0.1:8080", "[::1]:8080", "localhost:8080"} {
+		got, ok := loopback(addr)
+		assert.True(t, ok, addr)
+		assert.Equal(t, addr, got)
+	}
+}
+
+func TestServer_StartWithoutTokenListensOnLoopback(t *testing.T) {
+	s, _ := newTestServer(t)
+	srv, err := s.Start(":0")
+	require.NoError(t, err)
+	defer srv.Close()
+	host, _, err := net.SplitHostPort(srv.Addr)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", host)
+}
+
+func TestServer_Start(t *testing.T) {
+	s, _ := newTestServer(t)
+	srv, err := s.Start("127.0.0.1:0")
+	require.NoError(t, err)
+	defer srv.Close()
+
+	resp, err := http.Get("http://" + srv.Addr + "/api/status")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = s.Start("bad-address")
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package workspace
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/CodexForgeBR/cli-tools/internal/exitcode"
+)
+
+// outcome names how a repository's loop ended.
+func outcome(r Result) string {
+	switch {
+	case r.Skipped:
+		return "Skipped"
+	case r.Error != "":
+		return "not run: " + r.Error
+	case r.ExitCode == exitcode.Success:
+		return "Complete"
+	}
+	if name := exitcode.Name(r.ExitCode); name != "unknown" {
+		return name
+	}
+	return fmt.Sprintf("exit %d", r.ExitCode)
+}
+
+// WriteMarkdown writes the combined summary: one row per repository, then
+// the totals.
+func WriteMarkdown(w io.Writer, m *Manifest, results []Result) {
+	title := "ralph-loop workspace"
+	if m.Name != "" {
+		title += ": " + m.Name
+	}
+	fmt.Fprintf(w, "# %s\n\n", title)
+	fmt.Fprintln(w, "| Repository | Outcome | Tasks | Iterations | Cost | Duration | Log |")
+	fmt.Fprintln(w, "|---|---|---|---|---|---|---|")
+
+	var completed, done, total, iterations int
+	var cost float64
+	var hasCost bool
+	var duration time.Duration
+	for _, r := range results {
+		c := "n/a"
+		if r.HasCost {
+			c = fmt.Sprintf("$%.2f", r.Cost)
+			cost += r.Cost
+			hasCost = true
+		}
+		log := r.Log
+		if log == "" {
+			log = "-"
+		}
+		fmt.Fprintf(w, "| %s | %s | %d/%d | %d | %s | %s | %s |\n",
+			r.Repo, outcome(r), r.TasksDone, r.TasksTotal, r.Iterations, c, r.Duration.Round(time.Second), log)
+		if r.Completed() {
+			completed++
+		}
+		done += r.TasksDone
+		total += r.TasksTotal
+		iterations += r.Iterations
+		duration += r.Duration
+	}
+
+	totalCost := "n/a"
+	if hasCost {
+		totalCost = fmt.Sprintf("$%.2f", cost)
+	}
+	fmt.Fprintf(w, "\n%d/%d repositories complete, %d/%d tasks done in %d iterations, cost %s, loop time %s.\n",
+		completed, len(results), done, total, iterations, totalCost, duration.Round(time.Second))
+	if lf := m.LearningsFile(); lf != "" {
+		fmt.Fprintf(w, "\nShared learnings: `%s`\n", lf)
+	}
+}
@@ -0,0 +1,305 @@
+// Package workspace runs ralph-loop across the repositories of a workspace
+// manifest, e.g. the frontend and backend repositories of one feature, each
+// with its own tasks file, sharing one learnings file between them and
+// combining their outcomes into one summary.
+package workspace
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/CodexForgeBR/cli-tools/internal/config"
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+	"github.com/CodexForgeBR/cli-tools/internal/tasks"
+)
+
+// DefaultManifest is the manifest file looked up when none is given.
+const DefaultManifest = "ralph-workspace.yaml"
+
+// DirName is the directory, next to the manifest, holding the shared
+// learnings file, the summary and each repository's output.
+const DirName = ".ralph-workspace"
+
+// Manifest lists the repositories of a workspace. Paths are relative to
+// the manifest's directory.
+//
+//	name: checkout
+//	parallel: true
+//	args: [--max-iterations, "15"]
+//	repos:
+//	  - path: ../checkout-api
+//	    tasks_file: specs/checkout/tasks.md
+//	  - name: web
+//	    path: ../checkout-web
+//	    tasks_file: tasks.md
+//	    args: [--ai, codex]
+type Manifest struct {
+	Name string `yaml:"name"`
+	// Parallel runs the repositories' loops at the same time instead of
+	// one after the other.
+	Parallel bool `yaml:"parallel"`
+	// Args are ralph-loop flags for every repository.
+	Args []string `yaml:"args"`
+	// SharedLearnings, true unless set to false, points every loop at
+	// one learnings file, so what one repository's loop learns reaches
+	// the others.
+	SharedLearnings *bool  `yaml:"shared_learnings"`
+	Repos           []Repo `yaml:"repos"`
+
+	// Dir is the manifest's directory.
+	Dir string `yaml:"-"`
+}
+
+// Repo is one repository of the workspace.
+type Repo struct {
+	// Name labels the repository in the summary; it defaults to the base
+	// name of Path.
+	Name      string   `yaml:"name"`
+	Path      string   `yaml:"path"`
+	TasksFile string   `yaml:"tasks_file"`
+	Args      []string `yaml:"args"`
+}
+
+// Load reads and validates the manifest at path, resolving the
+// repositories' paths and tasks files to absolute paths.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	m, err := parse(data, dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// parse decodes a manifest whose relative paths are relative to dir.
+func parse(data []byte, dir string) (*Manifest, error) {
+	var m Manifest
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+	m.Dir = dir
+	if len(m.Repos) == 0 {
+		return nil, fmt.Errorf("no repos listed")
+	}
+	seen := map[string]bool{}
+	for i := range m.Repos {
+		r := &m.Repos[i]
+		if r.Path == "" {
+			return nil, fmt.Errorf("repos[%d]: path is required", i)
+		}
+		if r.TasksFile == "" {
+			return nil, fmt.Errorf("repos[%d]: tasks_file is required", i)
+		}
+		r.Path = resolve(dir, r.Path)
+		r.TasksFile = resolve(r.Path, r.TasksFile)
+		if r.Name == "" {
+			r.Name = filepath.Base(r.Path)
+		}
+		if seen[r.Name] {
+			return nil, fmt.Errorf("repository name %q is used twice; set name on one of them", r.Name)
+		}
+		seen[r.Name] = true
+		if info, err := os.Stat(r.Path); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("%s: repository directory not found: %s", r.Name, r.Path)
+		}
+		if _, err := os.Stat(r.TasksFile); err != nil {
+			return nil, fmt.Errorf("%s: tasks file not found: %s", r.Name, r.TasksFile)
+		}
+	}
+	return &m, nil
+}
+
+// LearningsFile returns the learnings file the repositories share, or ""
+// when shared_learnings is false.
+func (m *Manifest) LearningsFile() string {
+	if m.SharedLearnings != nil && !*m.SharedLearnings {
+		return ""
+	}
+	return filepath.Join(m.Dir, DirName, "learnings.md")
+}
+
+// Config describes a workspace run.
+type Config struct {
+	Manifest *Manifest
+	// Parallel overrides the manifest's parallel setting when true.
+	Parallel bool
+	// KeepGoing runs the remaining repositories after one fails; without
+	// it a sequential run stops at the first failure. Parallel runs always
+	// finish every repository.
+	KeepGoing bool
+	// Args are extra ralph-loop flags for every repository, after the
+	// manifest's.
+	Args []string
+	// StateDir is the STATE_DIR the loops run with, which places each
+	// repository's state directory (see config.ProjectStateDir).
+	StateDir string
+	// LogDir receives each repository's output.
+	LogDir string
+	// Exec runs ralph-loop with args in dir, writing its output to out,
+	// and returns its exit code. err is only for failures to run it.
+	Exec func(ctx context.Context, dir string, args []string, out io.Writer) (code int, err error)
+	// Progress, if set, is called before each repository's loop starts.
+	Progress func(r Repo)
+}
+
+// Result is the outcome of one repository's loop.
+type Result struct {
+	Repo       string        `json:"repo"`
+	Path       string        `json:"path"`
+	TasksFile  string        `json:"tasks_file"`
+	ExitCode   int           `json:"exit_code"`
+	Iterations int           `json:"iterations"`
+	TasksDone  int           `json:"tasks_done"`
+	TasksTotal int           `json:"tasks_total"`
+	Cost       float64       `json:"cost_usd"`
+	HasCost    bool          `json:"has_cost"`
+	Duration   time.Duration `json:"duration_ns"`
+	Log        string        `json:"log,omitempty"`
+	// Skipped is set for repositories not run because an earlier one
+	// failed.
+	Skipped bool   `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Completed reports whether the loop completed every task.
+func (r Result) Completed() bool {
+	return r.ExitCode == 0 && r.Error == "" && !r.Skipped
+}
+
+// Run runs a loop in every repository of the manifest and returns one
+// result per repository, in manifest order. When ctx is cancelled it
+// returns the results so far with ctx's error.
+func Run(ctx context.Context, cfg Config) ([]Result, error) {
+	m := cfg.Manifest
+	if err := os.MkdirAll(cfg.LogDir, 0755); err != nil {
+		return nil, err
+	}
+	if lf := m.LearningsFile(); lf != "" {
+		if err := os.MkdirAll(filepath.Dir(lf), 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]Result, len(m.Repos))
+	if cfg.Parallel || m.Parallel {
+		var wg sync.WaitGroup
+		for i, r := range m.Repos {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = runRepo(ctx, cfg, r)
+				countTasks(&results[i])
+			}()
+		}
+		wg.Wait()
+		return results, ctx.Err()
+	}
+
+	failed := ""
+	for i, r := range m.Repos {
+		if err := ctx.Err(); err != nil {
+			return results[:i], err
+		}
+		if failed != "" {
+			results[i] = Result{Repo: r.Name, Path: r.Path, TasksFile: r.TasksFile, ExitCode: -1, Skipped: true,
+				Error: fmt.Sprintf("skipped: %s did not complete", failed)}
+		} else {
+			results[i] = runRepo(ctx, cfg, r)
+			if !results[i].Completed() && !cfg.KeepGoing {
+				failed = r.Name
+			}
+		}
+		countTasks(&results[i])
+	}
+	return results, ctx.Err()
+}
+
+// runRepo runs the loop of repository r.
+func runRepo(ctx context.Context, cfg Config, r Repo) Result {
+	res := Result{Repo: r.Name, Path: r.Path, TasksFile: r.TasksFile}
+	res.Log = filepath.Join(cfg.LogDir, safeName(r.Name)+".log")
+	fail := func(err error) Result {
+		res.ExitCode, res.Error = -1, err.Error()
+		return res
+	}
+	if cfg.Progress != nil {
+		cfg.Progress(r)
+	}
+
+	logFile, err := os.Create(res.Log)
+	if err != nil {
+		return fail(err)
+	}
+	defer logFile.Close()
+
+	args := []string{"--tasks-file", r.TasksFile}
+	if lf := cfg.Manifest.LearningsFile(); lf != "" {
+		args = append(args, "--learnings-file", lf)
+	}
+	args = append(args, cfg.Manifest.Args...)
+	args = append(args, r.Args...)
+	args = append(args, cfg.Args...)
+	start := time.Now()
+	code, err := cfg.Exec(ctx, r.Path, args, logFile)
+	res.Duration = time.Since(start)
+	if err != nil {
+		return fail(err)
+	}
+	res.ExitCode = code
+
+	stateDir, err := config.ProjectStateDir(cfg.StateDir, r.Path)
+	if err != nil {
+		return res
+	}
+	if s, err := state.LoadState(stateDir); err == nil {
+		res.Iterations = s.Iteration
+	}
+	res.Cost, res.HasCost = state.SessionCost(stateDir)
+	return res
+}
+
+// countTasks fills in the task counts from the repository's tasks file.
+func countTasks(res *Result) {
+	f, err := tasks.Parse(res.TasksFile)
+	if err != nil {
+		return
+	}
+	checked, unchecked := f.Counts()
+	res.TasksDone, res.TasksTotal = checked, checked+unchecked
+}
+
+// resolve returns path, made absolute relative to dir.
+func resolve(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(dir, path)
+}
+
+// safeName makes a repository name usable in a file name.
+func safeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+}
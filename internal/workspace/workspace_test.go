@@ -0,0 +1,212 @@
+package workspace
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CodexForgeBR/cli-tools/internal/state"
+)
+
+// workspaceDir returns a directory holding a manifest listing the api and
+// web repositories, each with a tasks file of two tasks.
+func workspaceDir(t *testing.T, extra string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, repo := range []string{"api", "web"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "repos", repo), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "repos", repo, "tasks.md"), []byte("- [x] T001 Done\n- [ ] T002 Todo\n"), 0644))
+	}
+	manifest := extra + `args: [--max-iterations, "5"]
+repos:
+  - path: repos/api
+    tasks_file: tasks.md
+  - name: frontend
+    path: repos/web
+    tasks_file: tasks.md
+    args: [--ai, codex]
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DefaultManifest), []byte(manifest), 0644))
+	return dir
+}
+
+func TestLoad(t *testing.T) {
+	dir := workspaceDir(t, "name: checkout\n")
+	m, err := Load(filepath.Join(dir, DefaultManifest))
+	require.NoError(t, err)
+	assert.Equal(t, "checkout", m.Name)
+	require.Len(t, m.Repos, 2)
+	assert.Equal(t, Repo{Name: "api", Path: filepath.Join(dir, "repos", "api"), TasksFile: filepath.Join(dir, "repos", "api", "tasks.md")}, m.Repos[0])
+	assert.Equal(t, "frontend", m.Repos[1].Name)
+	assert.Equal(t, []string{"--ai", "codex"}, m.Repos[1].Args)
+	assert.Equal(t, filepath.Join(dir, DirName, "learnings.md"), m.LearningsFile())
+}
+
+func TestLoad_SharedLearningsOff(t *testing.T) {
+	m, err := Load(filepath.Join(workspaceDir(t, "shared_learnings: false\n"), DefaultManifest))
+	require.NoError(t, err)
+	assert.Empty(t, m.LearningsFile())
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "a"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a", "tasks.md"), nil, 0644))
+	tests := map[string]struct {
+		manifest string
+		want     string
+	}{
+		"no repos":         {"name: x\n", "no repos listed"},
+		"unknown field":    {"repos: []\nparalel: true\n", "field paralel not found"},
+		"missing path":     {"repos:\n  - tasks_file: tasks.md\n", "repos[0]: path is required"},
+		"missing tasks":    {"repos:\n  - path: a\n", "repos[0]: tasks_file is required"},
+		"duplicate name":   {"repos:\n  - path: a\n    tasks_file: tasks.md\n  - path: ./a\n    tasks_file: tasks.md\n", `repository name "a" is used twice`},
+		"missing dir":      {"repos:\n  - path: b\n    tasks_file: tasks.md\n", "repository directory not found"},
+		"missing tasks.md": {"repos:\n  - path: a\n    tasks_file: specs/tasks.md\n", "tasks file not found"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parse([]byte(tt.manifest), dir)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+// fakeLoop records every call and exits with the code given for the
+// repository's directory, after checking one more task and saving a state
+// of 3 iterations.
+type fakeLoop struct {
+	mu    sync.Mutex
+	codes map[string]int
+	calls map[string][]string
+}
+
+func (f *fakeLoop) exec(_ context.Context, dir string, args []string, out io.Writer) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.calls == nil {
+		f.calls = map[string][]string{}
+	}
+	f.calls[filepath.Base(dir)] = args
+	fmt.Fprintf(out, "loop in %s\n", filepath.Base(dir))
+	tasksFile := filepath.Join(dir, "tasks.md")
+	data, err := os.ReadFile(tasksFile)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(tasksFile, bytes.Replace(data, []byte("[ ]"), []byte("[x]"), 1), 0644); err != nil {
+		return 0, err
+	}
+	if err := state.SaveState(&state.SessionState{Iteration: 3}, filepath.Join(dir, ".ralph-loop")); err != nil {
+		return 0, err
+	}
+	return f.codes[filepath.Base(dir)], nil
+}
+
+func TestRun_Sequential(t *testing.T) {
+	dir := workspaceDir(t, "")
+	m, err := Load(filepath.Join(dir, DefaultManifest))
+	require.NoError(t, err)
+	loop := &fakeLoop{}
+	var order []string
+	logDir := filepath.Join(dir, DirName, "logs")
+
+	results, err := Run(context.Background(), Config{
+		Manifest: m,
+		Args:     []string{"--verbose"},
+		StateDir: ".ralph-loop",
+		LogDir:   logDir,
+		Exec:     loop.exec,
+		Progress: func(r Repo) { order = append(order, r.Name) },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api", "frontend"}, order)
+	require.Len(t, results, 2)
+	for _, r := range results {
+		assert.True(t, r.Completed(), r.Repo)
+		assert.Equal(t, 3, r.Iterations)
+		assert.Equal(t, 2, r.TasksDone)
+		assert.Equal(t, 2, r.TasksTotal)
+	}
+	assert.Equal(t, filepath.Join(logDir, "frontend.log"), results[1].Log)
+	log, err := os.ReadFile(results[1].Log)
+	require.NoError(t, err)
+	assert.Equal(t, "loop in web\n", string(log))
+
+	learnings := m.LearningsFile()
+	assert.DirExists(t, filepath.Dir(learnings))
+	assert.Equal(t, []string{"--tasks-file", m.Repos[0].TasksFile, "--learnings-file", learnings, "--max-iterations", "5", "--verbose"}, loop.calls["api"])
+	assert.Equal(t, []string{"--tasks-file", m.Repos[1].TasksFile, "--learnings-file", learnings, "--max-iterations", "5", "--ai", "codex", "--verbose"}, loop.calls["web"])
+}
+
+func TestRun_SequentialStopsAtFailure(t *testing.T) {
+	m, err := Load(filepath.Join(workspaceDir(t, ""), DefaultManifest))
+	require.NoError(t, err)
+	loop := &fakeLoop{codes: map[string]int{"api": 2}}
+
+	results, err := Run(context.Background(), Config{Manifest: m, StateDir: ".ralph-loop", LogDir: t.TempDir(), Exec: loop.exec})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.False(t, results[0].Completed())
+	assert.True(t, results[1].Skipped)
+	assert.Equal(t, "skipped: api did not complete", results[1].Error)
+	assert.Equal(t, 1, results[1].TasksDone, "the tasks of skipped repositories are still counted")
+	assert.NotContains(t, loop.calls, "web")
+
+	loop = &fakeLoop{codes: map[string]int{"api": 2}}
+	results, err = Run(context.Background(), Config{Manifest: m, KeepGoing: true, StateDir: ".ralph-loop", LogDir: t.TempDir(), Exec: loop.exec})
+	require.NoError(t, err)
+	assert.False(t, results[1].Skipped)
+	assert.Contains(t, loop.calls, "web")
+}
+
+func TestRun_Parallel(t *testing.T) {
+	m, err := Load(filepath.Join(workspaceDir(t, "parallel: true\nshared_learnings: false\n"), DefaultManifest))
+	require.NoError(t, err)
+	loop := &fakeLoop{codes: map[string]int{"api": 2}}
+
+	results, err := Run(context.Background(), Config{Manifest: m, StateDir: ".ralph-loop", LogDir: t.TempDir(), Exec: loop.exec})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "api", results[0].Repo)
+	assert.Equal(t, 2, results[0].ExitCode)
+	assert.True(t, results[1].Completed(), "parallel runs finish every repository")
+	assert.NotContains(t, loop.calls["web"], "--learnings-file")
+}
+
+func TestRun_Cancelled(t *testing.T) {
+	m, err := Load(filepath.Join(workspaceDir(t, ""), DefaultManifest))
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := Run(ctx, Config{Manifest: m, LogDir: t.TempDir(), Exec: (&fakeLoop{}).exec})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, results)
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	m := &Manifest{Name: "checkout", Dir: "/ws"}
+	var buf bytes.Buffer
+	WriteMarkdown(&buf, m, []Result{
+		{Repo: "api", TasksDone: 4, TasksTotal: 4, Iterations: 3, Cost: 1.5, HasCost: true, Log: "logs/api.log"},
+		{Repo: "web", ExitCode: 2, TasksDone: 1, TasksTotal: 3, Iterations: 10, Log: "logs/web.log"},
+		{Repo: "docs", ExitCode: -1, Skipped: true, Error: "skipped: web did not complete", TasksTotal: 1},
+	})
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "# ralph-loop workspace: checkout\n"), out)
+	assert.Contains(t, out, "| api | Complete | 4/4 | 3 | $1.50 | 0s | logs/api.log |")
+	assert.Contains(t, out, "| web | MaxIterations | 1/3 | 10 | n/a | 0s | logs/web.log |")
+	assert.Contains(t, out, "| docs | Skipped | 0/1 | 0 | n/a | 0s | - |")
+	assert.Contains(t, out, "1/3 repositories complete, 5/8 tasks done in 13 iterations, cost $1.50")
+	assert.Contains(t, out, "Shared learnings: `/ws/.ralph-workspace/learnings.md`")
+}